@@ -4,23 +4,67 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"time"
 
+	"nac-service-media/domain/detection"
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/video"
 	"nac-service-media/infrastructure/config"
 	infradetection "nac-service-media/infrastructure/detection"
+	"nac-service-media/infrastructure/ffmpeg"
 )
 
 // Service orchestrates start timestamp detection
 type Service struct {
-	config config.DetectionConfig
-	output io.Writer
+	config     config.DetectionConfig
+	output     io.Writer
+	framesDir  string
+	ffmpegPath string
+	history    history.Store
+}
+
+// CoarseTraceFile is the file in the frames directory that lists the coarse
+// scan's step decisions
+const CoarseTraceFile = "coarse-trace.txt"
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithFramesDir extracts video frames into dir, e.g. the run workspace, so
+// they are kept for debugging when the run fails
+func WithFramesDir(dir string) Option {
+	return func(s *Service) {
+		s.framesDir = dir
+	}
+}
+
+// WithFFmpegPath sets the ffmpeg used to extract frames
+func WithFFmpegPath(path string) Option {
+	return func(s *Service) {
+		s.ffmpegPath = path
+	}
+}
+
+// WithHistory narrows the start search to where recent services started,
+// unless detection.search_range.adaptive.disabled is set
+func WithHistory(store history.Store) Option {
+	return func(s *Service) {
+		s.history = store
+	}
 }
 
 // NewService creates a new detection service
-func NewService(cfg config.DetectionConfig, output io.Writer) *Service {
-	return &Service{
+func NewService(cfg config.DetectionConfig, output io.Writer, opts ...Option) *Service {
+	s := &Service{
 		config: cfg,
 		output: output,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // DetectInput contains input for start detection
@@ -34,14 +78,73 @@ type DetectResult struct {
 	Confidence     float64
 	CameraAngle    string
 	FramesAnalyzed int
+
+	// CoarseTrace records the adaptive coarse scan's step decisions
+	CoarseTrace []detection.CoarseDecision
+
+	// EarlyExit is set when a confident frame bracket skipped refinement
+	EarlyExit bool
 }
 
-// DetectStart attempts to detect when the cross lights up in the video
+// DetectStart attempts to detect when the cross lights up in the video. With
+// history, only the range recent services started in is searched; a start
+// not found inside it is searched for again over the configured range.
 func (s *Service) DetectStart(ctx context.Context, input DetectInput) (*DetectResult, error) {
 	fmt.Fprintf(s.output, "Analyzing video for service start...\n")
 
+	searchRange, adapted := s.adaptedSearchRange()
+	if !adapted {
+		return s.detectStart(ctx, input.VideoPath)
+	}
+
+	fmt.Fprintf(s.output, "  Searching %s, where recent services started\n", searchRange)
+	result, err := s.detectStart(ctx, input.VideoPath, infradetection.WithSearchRange(searchRange))
+	if result == nil || ctx.Err() != nil {
+		// Detection could not run at all, so a wider search would not help
+		return nil, err
+	}
+	if err == nil {
+		start, parseErr := video.ParseTimestamp(result.Timestamp)
+		// A start at 00:00:00 means the recording began after the cross lit
+		if parseErr == nil && (start.IsZero() || searchRange.Contains(start.TotalSeconds())) {
+			return result, nil
+		}
+	}
+
+	fmt.Fprintf(s.output, "  Start not found in %s; searching %s\n", searchRange, s.config.SearchRange.SearchRange())
+	full, err := s.detectStart(ctx, input.VideoPath)
+	if err != nil {
+		return nil, err
+	}
+	full.FramesAnalyzed += result.FramesAnalyzed
+	return full, nil
+}
+
+// adaptedSearchRange narrows the configured range to recent detected starts.
+// History that cannot be read leaves the configured range in use.
+func (s *Service) adaptedSearchRange() (detection.SearchRange, bool) {
+	if s.history == nil {
+		return detection.SearchRange{}, false
+	}
+	entries, err := s.history.List()
+	if err != nil {
+		fmt.Fprintf(s.output, "  Warning: could not read history to narrow the search: %v\n", err)
+		return detection.SearchRange{}, false
+	}
+	return s.config.SearchRange.AdaptedSearchRange(entries)
+}
+
+// detectStart runs the template detector once
+func (s *Service) detectStart(ctx context.Context, videoPath string, opts ...infradetection.TemplateDetectorOption) (*DetectResult, error) {
 	// Create detector
-	detector := infradetection.NewTemplateDetector(s.config)
+	detectorOpts := opts
+	if s.framesDir != "" {
+		detectorOpts = append(detectorOpts, infradetection.WithFramesDir(s.framesDir))
+	}
+	if s.ffmpegPath != "" {
+		detectorOpts = append(detectorOpts, infradetection.WithFFmpegPath(s.ffmpegPath))
+	}
+	detector := infradetection.NewTemplateDetector(s.config, detectorOpts...)
 
 	// Load templates
 	fmt.Fprintf(s.output, "  Loading templates...\n")
@@ -54,13 +157,25 @@ func (s *Service) DetectStart(ctx context.Context, input DetectInput) (*DetectRe
 	fmt.Fprintf(s.output, "  Phase 1: Coarse scan...\n")
 
 	// Run detection (phases 1-3 happen inside)
-	result, err := detector.DetectStart(ctx, input.VideoPath)
+	result, err := detector.DetectStart(ctx, videoPath)
+	s.writeCoarseTrace(result.CoarseTrace)
 	if err != nil {
+		if result.FramesAnalyzed > 0 {
+			return &DetectResult{FramesAnalyzed: result.FramesAnalyzed}, err
+		}
 		return nil, err
 	}
 
-	fmt.Fprintf(s.output, "  Phase 2: Binary search...\n")
-	fmt.Fprintf(s.output, "  Phase 3: Refining...\n")
+	if trace := result.CoarseTrace; len(trace) > 0 {
+		fmt.Fprintf(s.output, "    %d coarse frames, step %ds -> %ds\n",
+			len(trace), trace[0].NextStep, trace[len(trace)-1].NextStep)
+	}
+	if result.EarlyExit {
+		fmt.Fprintf(s.output, "  Stopped early: confident unlit/lit frames bracket the start\n")
+	} else {
+		fmt.Fprintf(s.output, "  Phase 2: Binary search...\n")
+		fmt.Fprintf(s.output, "  Phase 3: Refining...\n")
+	}
 	fmt.Fprintf(s.output, "Detected start: %s (%s angle, confidence: %.0f%%)\n",
 		result.Timestamp.String(), result.CameraAngle, result.Confidence*100)
 
@@ -69,21 +184,63 @@ func (s *Service) DetectStart(ctx context.Context, input DetectInput) (*DetectRe
 		Confidence:     result.Confidence,
 		CameraAngle:    result.CameraAngle,
 		FramesAnalyzed: result.FramesAnalyzed,
+		CoarseTrace:    result.CoarseTrace,
+		EarlyExit:      result.EarlyExit,
 	}, nil
 }
 
+// writeCoarseTrace saves the coarse scan's step decisions next to the
+// extracted frames, so they are kept with them when the run fails
+func (s *Service) writeCoarseTrace(trace []detection.CoarseDecision) {
+	if s.framesDir == "" || len(trace) == 0 {
+		return
+	}
+	err := os.MkdirAll(s.framesDir, 0755)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(s.framesDir, CoarseTraceFile), []byte(detection.FormatCoarseTrace(trace)), 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(s.output, "  Warning: could not save the coarse scan trace: %v\n", err)
+	}
+}
+
 // IsEnabled returns whether detection is enabled in config
 func (s *Service) IsEnabled() bool {
 	return s.config.Enabled
 }
 
-// DetectEnd finds the service end by detecting the three-fold amen
+// DetectEnd finds the service end with detection.end.method: the three-fold
+// amen (default), the cross going dark, or the first long silence.
 // serviceStartSeconds is the detected/provided service start time (used to calculate search window)
 func (s *Service) DetectEnd(ctx context.Context, videoPath string, serviceStartSeconds int) (*DetectResult, error) {
-	fmt.Fprintln(s.output, "Analyzing audio for service end...")
-	fmt.Fprintln(s.output, "  Searching for three-fold amen...")
-
-	detector := infradetection.NewAmenDetector(s.config)
+	method := s.config.EndMethod()
+	var (
+		detector detection.EndDetector
+		found    string
+	)
+	switch method {
+	case detection.EndByCross:
+		fmt.Fprintln(s.output, "Analyzing video for service end...")
+		fmt.Fprintln(s.output, "  Searching for the cross going dark...")
+		var opts []infradetection.TemplateDetectorOption
+		if s.ffmpegPath != "" {
+			opts = append(opts, infradetection.WithFFmpegPath(s.ffmpegPath))
+		}
+		cross := infradetection.NewTemplateDetector(s.config, opts...)
+		if err := cross.LoadTemplates(s.config.TemplatesDir); err != nil {
+			return nil, fmt.Errorf("failed to load detection templates: %w", err)
+		}
+		defer cross.Close()
+		detector, found = cross, "cross went dark"
+	case detection.EndBySilence:
+		fmt.Fprintln(s.output, "Analyzing audio for service end...")
+		fmt.Fprintln(s.output, "  Searching for a long silence...")
+		detector, found = s.silenceDetector(), "silence began"
+	default:
+		fmt.Fprintln(s.output, "Analyzing audio for service end...")
+		fmt.Fprintln(s.output, "  Searching for three-fold amen...")
+		detector, found = infradetection.NewAmenDetector(s.config), "amen detected"
+	}
 
 	result, err := detector.DetectEnd(ctx, videoPath, serviceStartSeconds)
 	if err != nil {
@@ -91,14 +248,31 @@ func (s *Service) DetectEnd(ctx context.Context, videoPath string, serviceStartS
 	}
 
 	if !result.Detected {
-		return nil, fmt.Errorf("could not detect amen: %s", result.Error)
+		return nil, fmt.Errorf("could not detect the end (%s): %s", method, result.Error)
 	}
 
-	fmt.Fprintf(s.output, "Detected end: %s (amen detected, confidence: %.0f%%)\n",
-		result.Timestamp, result.Confidence*100)
+	if result.Confidence > 0 {
+		fmt.Fprintf(s.output, "Detected end: %s (%s, confidence: %.0f%%)\n",
+			result.Timestamp, found, result.Confidence*100)
+	} else {
+		fmt.Fprintf(s.output, "Detected end: %s (%s)\n", result.Timestamp, found)
+	}
 
 	return &DetectResult{
 		Timestamp:  result.Timestamp.String(),
 		Confidence: result.Confidence,
 	}, nil
 }
+
+// silenceDetector builds the ffmpeg silence detector from config
+func (s *Service) silenceDetector() *ffmpeg.SilenceDetector {
+	end, search := s.config.End, s.config.SearchRange
+	opts := []ffmpeg.SilenceOption{
+		ffmpeg.WithSilenceThreshold(end.SilenceNoiseDB, time.Duration(end.SilenceSeconds)*time.Second),
+		ffmpeg.WithEndSearch(time.Duration(search.AmenStartOffsetMinutes)*time.Minute, time.Duration(search.AmenSearchDurationMinutes)*time.Minute),
+	}
+	if s.ffmpegPath != "" {
+		opts = append(opts, ffmpeg.WithSilenceFFmpegPath(s.ffmpegPath))
+	}
+	return ffmpeg.NewSilenceDetector(opts...)
+}
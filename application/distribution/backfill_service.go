@@ -0,0 +1,152 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/clock"
+	"nac-service-media/domain/distribution"
+)
+
+// DefaultBackfillBatchSize is how many files are tagged before pausing
+const DefaultBackfillBatchSize = 20
+
+// DefaultBackfillPause is the wait between batches, keeping the writes well
+// under Drive's per-user rate limit
+const DefaultBackfillPause = 5 * time.Second
+
+// BackfillService tags files uploaded before metadata tagging existed with
+// the app properties new uploads get
+type BackfillService struct {
+	driveClient distribution.DriveClient
+	folderID    string
+	batchSize   int
+	pause       time.Duration
+	sleep       clock.Sleeper
+	progress    func(done, total int)
+}
+
+// BackfillOption configures a BackfillService
+type BackfillOption func(*BackfillService)
+
+// WithBackfillBatchSize sets how many files are tagged between pauses; values
+// below 1 keep the default
+func WithBackfillBatchSize(n int) BackfillOption {
+	return func(s *BackfillService) {
+		if n > 0 {
+			s.batchSize = n
+		}
+	}
+}
+
+// WithBackfillPause sets the wait between batches; negative values keep the
+// default
+func WithBackfillPause(d time.Duration) BackfillOption {
+	return func(s *BackfillService) {
+		if d >= 0 {
+			s.pause = d
+		}
+	}
+}
+
+// WithBackfillProgress is called after each batch with the files tagged so far
+func WithBackfillProgress(fn func(done, total int)) BackfillOption {
+	return func(s *BackfillService) {
+		s.progress = fn
+	}
+}
+
+// NewBackfillService creates a new backfill service
+func NewBackfillService(client distribution.DriveClient, folderID string, opts ...BackfillOption) *BackfillService {
+	s := &BackfillService{
+		driveClient: client,
+		folderID:    folderID,
+		batchSize:   DefaultBackfillBatchSize,
+		pause:       DefaultBackfillPause,
+		sleep:       clock.Sleep,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Backfill scans the Services folder and writes the service date and media
+// type inferred from each file name to files missing them. Files that cannot
+// be classified are reported and left alone; a file that fails to tag is
+// recorded and the rest carry on. With dryRun nothing is written.
+func (s *BackfillService) Backfill(ctx context.Context, dryRun bool) (*distribution.BackfillResult, error) {
+	result := &distribution.BackfillResult{}
+
+	files, err := s.driveClient.ListFiles(ctx, s.folderID)
+	if err != nil {
+		return result, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var pending []distribution.TaggedFile
+	var ids []string
+	for _, f := range files {
+		if f.MimeType == distribution.MimeTypeFolder {
+			continue
+		}
+		missing := distribution.MissingProperties(f)
+		if !classified(f, missing) {
+			result.Unclassified = append(result.Unclassified, f)
+			continue
+		}
+		if len(missing) == 0 {
+			result.AlreadyTagged++
+			continue
+		}
+		pending = append(pending, distribution.TaggedFile{Name: f.Name, Properties: missing})
+		ids = append(ids, f.ID)
+	}
+
+	if dryRun || len(pending) == 0 {
+		result.Tagged = pending
+		return result, nil
+	}
+
+	tagger, ok := s.driveClient.(distribution.PropertyTagger)
+	if !ok {
+		return result, distribution.ErrTaggingUnsupported
+	}
+
+	for start := 0; start < len(pending); start += s.batchSize {
+		if start > 0 && s.pause > 0 {
+			if err := s.sleep(ctx, s.pause); err != nil {
+				return result, err
+			}
+		}
+		end := min(start+s.batchSize, len(pending))
+		for i := start; i < end; i++ {
+			if err := tagger.SetAppProperties(ctx, ids[i], pending[i].Properties); err != nil {
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				result.Failed = append(result.Failed, distribution.FailedTag{Name: pending[i].Name, Err: err})
+				continue
+			}
+			result.Tagged = append(result.Tagged, pending[i])
+		}
+		if s.progress != nil {
+			s.progress(end, len(pending))
+		}
+	}
+	return result, nil
+}
+
+// classified reports whether f has, or can be given, both a service date and
+// a media type
+func classified(f distribution.FileInfo, missing map[string]string) bool {
+	for _, key := range []string{distribution.PropertyServiceDate, distribution.PropertyMediaType} {
+		if _, ok := f.AppProperties[key]; ok {
+			continue
+		}
+		if _, ok := missing[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -3,28 +3,75 @@ package distribution
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"nac-service-media/domain/distribution"
 )
 
+// DefaultCleanupConcurrency is how many files are deleted at once
+const DefaultCleanupConcurrency = 4
+
 // CleanupService handles storage cleanup operations
 type CleanupService struct {
 	driveClient distribution.DriveClient
 	folderID    string
+	concurrency int
+	archive     distribution.Archive
+	progress    func(distribution.CleanupProgress)
+}
+
+// CleanupOption configures a CleanupService
+type CleanupOption func(*CleanupService)
+
+// WithCleanupConcurrency sets how many deletions run at once; values below 1
+// keep the default
+func WithCleanupConcurrency(n int) CleanupOption {
+	return func(s *CleanupService) {
+		if n > 0 {
+			s.concurrency = n
+		}
+	}
+}
+
+// WithArchive makes cleanup delete files the archive already holds first,
+// oldest first, before any file that exists only in storage. A nil archive
+// keeps plain oldest-first order.
+func WithArchive(a distribution.Archive) CleanupOption {
+	return func(s *CleanupService) {
+		s.archive = a
+	}
+}
+
+// WithCleanupProgress is called after each file a batch deletes, or fails to
+func WithCleanupProgress(fn func(distribution.CleanupProgress)) CleanupOption {
+	return func(s *CleanupService) {
+		s.progress = fn
+	}
 }
 
 // NewCleanupService creates a new cleanup service
-func NewCleanupService(client distribution.DriveClient, folderID string) *CleanupService {
-	return &CleanupService{
+func NewCleanupService(client distribution.DriveClient, folderID string, opts ...CleanupOption) *CleanupService {
+	s := &CleanupService{
 		driveClient: client,
 		folderID:    folderID,
+		concurrency: DefaultCleanupConcurrency,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // EnsureSpaceAvailable deletes oldest mp4 files until sufficient space is available
-// It returns the cleanup result with information about deleted files
+// It returns the cleanup result with information about deleted files. Each pass
+// deletes, concurrently, just enough of the oldest files to cover the shortfall;
+// files that fail to delete are recorded in the result and skipped. With an
+// archive, archived files are deleted before unarchived ones.
 func (s *CleanupService) EnsureSpaceAvailable(ctx context.Context, neededBytes int64) (*distribution.CleanupResult, error) {
 	result := &distribution.CleanupResult{}
+	failed := make(map[string]bool)
+	archived := make(map[string]bool)
 
 	for {
 		storage, err := s.driveClient.GetStorageQuota(ctx)
@@ -41,23 +88,200 @@ func (s *CleanupService) EnsureSpaceAvailable(ctx context.Context, neededBytes i
 			return result, fmt.Errorf("failed to list files: %w", err)
 		}
 
-		if len(files) == 0 {
+		var candidates []distribution.FileInfo
+		for _, f := range files {
+			if !failed[f.ID] {
+				candidates = append(candidates, f)
+			}
+		}
+
+		if len(candidates) == 0 {
+			if len(files) == 0 {
+				return result, s.nothingToDelete(neededBytes, storage)
+			}
+			if len(result.Failed) > 0 {
+				first := result.Failed[0]
+				return result, fmt.Errorf("need %d bytes but only %d available after %d failed deletions (first: %s: %w)",
+					neededBytes, storage.AvailableBytes, len(result.Failed), first.Name, first.Err)
+			}
 			return result, fmt.Errorf("no mp4 files to delete, need %d bytes but only %d available",
 				neededBytes, storage.AvailableBytes)
 		}
 
-		oldest := files[0] // Already sorted by name (oldest first)
+		// Already sorted by name (oldest first)
+		candidates = s.archivedFirst(ctx, candidates, archived)
+		batch := oldestCovering(candidates, neededBytes-storage.AvailableBytes)
+		for _, id := range collect(result, s.deleteAll(ctx, batch, archived), archived) {
+			failed[id] = true
+		}
+	}
+}
+
+// PlanCleanup lists the files EnsureSpaceAvailable would delete first to make
+// room for neededBytes, without deleting anything. The plan is empty when
+// there is already room.
+func (s *CleanupService) PlanCleanup(ctx context.Context, neededBytes int64) (*distribution.CleanupPlan, error) {
+	storage, err := s.driveClient.GetStorageQuota(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check storage: %w", err)
+	}
+	if storage.HasSpaceFor(neededBytes) {
+		return &distribution.CleanupPlan{}, nil
+	}
+
+	files, err := s.driveClient.ListMP4Files(ctx, s.folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, s.nothingToDelete(neededBytes, storage)
+	}
+
+	archived := make(map[string]bool)
+	shortfall := neededBytes - storage.AvailableBytes
+	return &distribution.CleanupPlan{
+		Files:     oldestCovering(s.archivedFirst(ctx, files, archived), shortfall),
+		Shortfall: shortfall,
+		Archived:  archived,
+	}, nil
+}
+
+// DeleteFiles deletes exactly the planned files, such as once the operator
+// approved them; unlike EnsureSpaceAvailable it never moves on to others
+func (s *CleanupService) DeleteFiles(ctx context.Context, plan *distribution.CleanupPlan) *distribution.CleanupResult {
+	result := &distribution.CleanupResult{}
+	collect(result, s.deleteAll(ctx, plan.Files, plan.Archived), plan.Archived)
+	return result
+}
 
-		if err := s.driveClient.DeletePermanently(ctx, oldest.ID); err != nil {
-			return result, fmt.Errorf("failed to delete %s: %w", oldest.Name, err)
+// nothingToDelete explains why there is no room when no mp4 files are left
+func (s *CleanupService) nothingToDelete(neededBytes int64, storage *distribution.StorageInfo) error {
+	if distribution.SeesAppFilesOnly(s.driveClient) {
+		return fmt.Errorf("no mp4 files uploaded by this app to delete, need %d bytes but only %d available; with google.scope_mode: file, other files must be deleted by hand",
+			neededBytes, storage.AvailableBytes)
+	}
+	return fmt.Errorf("no mp4 files to delete, need %d bytes but only %d available",
+		neededBytes, storage.AvailableBytes)
+}
+
+// collect adds deletion outcomes to result and returns the IDs that failed
+func collect(result *distribution.CleanupResult, outcomes []deleteOutcome, archived map[string]bool) []string {
+	var failed []string
+	for _, outcome := range outcomes {
+		if outcome.err != nil {
+			failed = append(failed, outcome.file.ID)
+			result.Failed = append(result.Failed, distribution.FailedDeletion{
+				Name: outcome.file.Name,
+				Size: outcome.file.Size,
+				Err:  outcome.err,
+			})
+			continue
+		}
+		result.DeletedFiles = append(result.DeletedFiles, deletedFile(outcome.file, archived))
+		result.FreedBytes += outcome.file.Size
+	}
+	return failed
+}
+
+// deletedFile describes a file cleanup deleted
+func deletedFile(f distribution.FileInfo, archived map[string]bool) distribution.DeletedFile {
+	date, _ := distribution.FileServiceDate(f)
+	return distribution.DeletedFile{
+		Name:        f.Name,
+		Size:        f.Size,
+		ServiceDate: date,
+		Archived:    archived[f.ID],
+	}
+}
+
+// archivedFirst moves files the archive holds ahead of the rest, keeping
+// each group oldest first. Lookups are remembered in archived across passes;
+// a file that cannot be checked is treated as not archived.
+func (s *CleanupService) archivedFirst(ctx context.Context, files []distribution.FileInfo, archived map[string]bool) []distribution.FileInfo {
+	if s.archive == nil {
+		return files
+	}
+	var inArchive, notInArchive []distribution.FileInfo
+	for _, f := range files {
+		held, checked := archived[f.ID]
+		if !checked {
+			held, _ = s.archive.Contains(ctx, f)
+			archived[f.ID] = held
+		}
+		if held {
+			inArchive = append(inArchive, f)
+		} else {
+			notInArchive = append(notInArchive, f)
+		}
+	}
+	return append(inArchive, notInArchive...)
+}
+
+// oldestCovering returns the shortest prefix of files whose sizes add up to shortfall
+func oldestCovering(files []distribution.FileInfo, shortfall int64) []distribution.FileInfo {
+	var total int64
+	for i, f := range files {
+		total += f.Size
+		if total >= shortfall {
+			return files[:i+1]
+		}
+	}
+	return files
+}
+
+// deleteOutcome is the result of deleting one file
+type deleteOutcome struct {
+	file distribution.FileInfo
+	err  error
+}
+
+// deleteAll deletes the files with a pool of workers and returns one outcome
+// per file, in the order given. Progress is reported as each one finishes.
+func (s *CleanupService) deleteAll(ctx context.Context, files []distribution.FileInfo, archived map[string]bool) []deleteOutcome {
+	outcomes := make([]deleteOutcome, len(files))
+	jobs := make(chan int)
+
+	var mu sync.Mutex
+	started := time.Now()
+	progress := distribution.CleanupProgress{Total: len(files)}
+	for _, f := range files {
+		progress.TotalBytes += f.Size
+	}
+	report := func(f distribution.FileInfo, err error) {
+		if s.progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		progress.Done++
+		progress.File, progress.Err = deletedFile(f, archived), err
+		if err == nil {
+			progress.FreedBytes += f.Size
 		}
+		progress.Elapsed = time.Since(started)
+		s.progress(progress)
+	}
+
+	workers := min(s.concurrency, len(files))
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				err := s.driveClient.DeletePermanently(ctx, files[i].ID)
+				outcomes[i] = deleteOutcome{file: files[i], err: err}
+				report(files[i], err)
+			}
+		}()
+	}
 
-		result.DeletedFiles = append(result.DeletedFiles, distribution.DeletedFile{
-			Name: oldest.Name,
-			Size: oldest.Size,
-		})
-		result.FreedBytes += oldest.Size
+	for i := range files {
+		jobs <- i
 	}
+	close(jobs)
+	wg.Wait()
+	return outcomes
 }
 
 // ListMP4FilesSorted lists MP4 files sorted by filename (oldest first)
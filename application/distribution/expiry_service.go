@@ -0,0 +1,91 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/distribution"
+)
+
+// ExpiryService ends access to privately shared files and deletes them once
+// the dates WithExpiry tagged them with have come
+type ExpiryService struct {
+	driveClient distribution.DriveClient
+	folderID    string
+	now         func() time.Time
+}
+
+// ExpiryOption is a functional option for configuring ExpiryService
+type ExpiryOption func(*ExpiryService)
+
+// WithExpiryClock sets the time the dates are compared with (for testing)
+func WithExpiryClock(now func() time.Time) ExpiryOption {
+	return func(s *ExpiryService) {
+		s.now = now
+	}
+}
+
+// NewExpiryService creates a new expiry service for a folder
+func NewExpiryService(client distribution.DriveClient, folderID string, opts ...ExpiryOption) *ExpiryService {
+	s := &ExpiryService{
+		driveClient: client,
+		folderID:    folderID,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RevokedFile is a file people lost access to
+type RevokedFile struct {
+	File    distribution.FileInfo
+	Readers []string
+}
+
+// ExpiryResult is what one pass over the folder did
+type ExpiryResult struct {
+	Revoked []RevokedFile
+	Deleted []distribution.FileInfo
+	Failed  []error // Files left for the next pass
+}
+
+// Expire revokes the readers of every file whose access has ended and
+// deletes every file past its retention. A file that fails is reported in
+// the result and tried again next time.
+func (s *ExpiryService) Expire(ctx context.Context) (*ExpiryResult, error) {
+	files, err := s.driveClient.ListFiles(ctx, s.folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	now := s.now()
+	result := &ExpiryResult{}
+	for _, f := range files {
+		expiry := distribution.ExpiryOf(f)
+		switch {
+		case expiry.DeleteDue(now):
+			if err := s.driveClient.DeletePermanently(ctx, f.ID); err != nil {
+				result.Failed = append(result.Failed, fmt.Errorf("failed to delete %s: %w", f.Name, err))
+				continue
+			}
+			result.Deleted = append(result.Deleted, f)
+		case expiry.RevokeDue(now):
+			revoker, ok := s.driveClient.(distribution.ReaderRevoker)
+			if !ok {
+				return result, distribution.ErrRevokeUnsupported
+			}
+			readers, err := revoker.RevokeReaders(ctx, f.ID)
+			if err != nil {
+				result.Failed = append(result.Failed, fmt.Errorf("failed to end access to %s: %w", f.Name, err))
+				continue
+			}
+			if len(readers) > 0 {
+				result.Revoked = append(result.Revoked, RevokedFile{File: f, Readers: readers})
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+
+	"nac-service-media/domain/distribution"
+)
+
+// CheckFolder looks up the folder uploads go to and checks that the account
+// can put files in it, so a mistyped folder ID fails before anything is
+// uploaded. It returns nil and no error when the client cannot look folders
+// up, as with S3 storage.
+func CheckFolder(ctx context.Context, client distribution.DriveClient, folderID string) (*distribution.FolderDetails, error) {
+	inspector, ok := client.(distribution.FolderInspector)
+	if !ok {
+		return nil, nil
+	}
+	folder, err := inspector.FolderDetails(ctx, folderID)
+	if errors.Is(err, distribution.ErrFolderLookupUnsupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return folder, folder.Check()
+}
@@ -0,0 +1,25 @@
+package distribution
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// osFS is the file system services use unless WithFS gives them another
+type osFS struct{}
+
+func (osFS) Open(name string) (io.ReadCloser, error)    { return os.Open(name) }
+func (osFS) Create(name string) (io.WriteCloser, error) { return os.Create(name) }
+func (osFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (osFS) MkdirAll(path string) error                 { return os.MkdirAll(path, 0755) }
+func (osFS) Remove(name string) error                   { return os.Remove(name) }
+
+func (osFS) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, fn)
+}
+
+var _ domainfs.FS = osFS{}
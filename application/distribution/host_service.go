@@ -0,0 +1,55 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// HostService uploads service videos to a video host such as YouTube
+type HostService struct {
+	host distribution.VideoHost
+	fs   domainfs.FS
+}
+
+// HostOption is a functional option for configuring HostService
+type HostOption func(*HostService)
+
+// WithHostFS sets the file system videos are read from
+func WithHostFS(fsys domainfs.FS) HostOption {
+	return func(s *HostService) {
+		s.fs = fsys
+	}
+}
+
+// NewHostService creates a new video host service
+func NewHostService(host distribution.VideoHost, opts ...HostOption) *HostService {
+	s := &HostService{
+		host: host,
+		fs:   osFS{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// UploadVideo uploads a local video, listed with the service's details
+func (s *HostService) UploadVideo(ctx context.Context, videoPath string, details distribution.VideoDetails) (*distribution.HostedVideo, error) {
+	if _, err := s.fs.Stat(videoPath); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("file does not exist: %s", videoPath)
+	}
+	f, err := s.fs.Open(videoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", videoPath, err)
+	}
+	defer f.Close()
+
+	upload := distribution.NewVideoUpload(filepath.Base(videoPath), details)
+	return s.host.UploadVideo(ctx, upload, f)
+}
@@ -0,0 +1,181 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/history"
+)
+
+// OrphanService looks for forgotten files in the Services folder: files in
+// its trash, public files no run in history links to, and files named unlike
+// anything this tool uploads
+type OrphanService struct {
+	driveClient distribution.DriveClient
+	folderID    string
+	history     history.Store
+}
+
+// NewOrphanService creates a new orphan report service
+func NewOrphanService(client distribution.DriveClient, folderID string, store history.Store) *OrphanService {
+	return &OrphanService{
+		driveClient: client,
+		folderID:    folderID,
+		history:     store,
+	}
+}
+
+// historyIndex is what history says about Drive files
+type historyIndex struct {
+	fileIDs  map[string]string // File ID -> service date it was recorded for
+	dates    map[string]bool   // Service dates with a successful run
+	earliest time.Time         // First run recorded; zero when there are none
+}
+
+// Report runs every check the storage supports. Checks it cannot run are
+// named in Skipped instead of failing the report.
+func (s *OrphanService) Report(ctx context.Context) (*distribution.OrphanReport, error) {
+	index, err := s.indexHistory()
+	if err != nil {
+		return nil, err
+	}
+	report := &distribution.OrphanReport{}
+
+	if lister, ok := s.driveClient.(distribution.TrashLister); ok {
+		trashed, err := lister.ListTrashed(ctx, s.folderID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range trashed {
+			report.Orphans = append(report.Orphans, trashedOrphan(f, index))
+		}
+	} else {
+		report.Skipped = append(report.Skipped, "trash: this storage has no trash to check")
+	}
+
+	public := make(map[string]bool)
+	lister, canListPublic := s.driveClient.(distribution.PublicLister)
+	if canListPublic {
+		shared, err := lister.ListPublic(ctx, s.folderID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range shared {
+			public[f.ID] = true
+			if _, ok := index.fileIDs[f.ID]; ok || !distribution.KnownFileName(f.Name) {
+				continue
+			}
+			report.Orphans = append(report.Orphans, unreferencedOrphan(f, index))
+		}
+	} else {
+		report.Skipped = append(report.Skipped, "sharing: this storage cannot list publicly shared files")
+	}
+
+	files, err := s.driveClient.ListFiles(ctx, s.folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	for _, f := range files {
+		if f.MimeType == distribution.FolderMimeType || distribution.KnownFileName(f.Name) {
+			continue
+		}
+		if _, ok := index.fileIDs[f.ID]; ok {
+			continue // Renamed by hand, but a run still links it
+		}
+		reason := "name matches no upload pattern"
+		if public[f.ID] {
+			reason += "; shared publicly but in no history entry"
+		}
+		report.Orphans = append(report.Orphans, distribution.Orphan{
+			File:           f,
+			Kind:           distribution.OrphanUnknownName,
+			Recommendation: distribution.RecommendReview,
+			Reason:         reason,
+		})
+	}
+	return report, nil
+}
+
+// indexHistory collects the file IDs and service dates of recorded runs
+func (s *OrphanService) indexHistory() (*historyIndex, error) {
+	index := &historyIndex{fileIDs: make(map[string]string), dates: make(map[string]bool)}
+	if s.history == nil {
+		return index, nil
+	}
+	entries, err := s.history.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	for _, e := range entries {
+		date := e.ServiceDate.Format("2006-01-02")
+		for _, id := range []string{e.VideoFileID, e.AudioFileID, fileIDFromURL(e.VideoURL), fileIDFromURL(e.AudioURL)} {
+			if id != "" {
+				index.fileIDs[id] = date
+			}
+		}
+		if e.Outcome == history.OutcomeSuccess {
+			index.dates[date] = true
+		}
+		if !e.ProcessedAt.IsZero() && (index.earliest.IsZero() || e.ProcessedAt.Before(index.earliest)) {
+			index.earliest = e.ProcessedAt
+		}
+	}
+	return index, nil
+}
+
+// fileIDFromURL reads the ID from a Drive link such as
+// https://drive.google.com/file/d/ID/view
+func fileIDFromURL(url string) string {
+	_, rest, ok := strings.Cut(url, "/file/d/")
+	if !ok {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	return id
+}
+
+// serviceDateOf returns a file's service date from its metadata, or from its
+// name for files uploaded before tagging
+func serviceDateOf(f distribution.FileInfo) string {
+	if date := f.AppProperties[distribution.PropertyServiceDate]; date != "" {
+		return date
+	}
+	return distribution.InferProperties(f.Name, f.MimeType)[distribution.PropertyServiceDate]
+}
+
+func trashedOrphan(f distribution.FileInfo, index *historyIndex) distribution.Orphan {
+	o := distribution.Orphan{
+		File:           f,
+		Kind:           distribution.OrphanTrashed,
+		Recommendation: distribution.RecommendDelete,
+		Reason:         "still counts against storage",
+	}
+	if date, ok := index.fileIDs[f.ID]; ok {
+		o.Recommendation = distribution.RecommendReview
+		o.Reason = fmt.Sprintf("history links it for %s; restore it if that link should still work", date)
+	}
+	return o
+}
+
+func unreferencedOrphan(f distribution.FileInfo, index *historyIndex) distribution.Orphan {
+	o := distribution.Orphan{File: f, Kind: distribution.OrphanUnreferenced}
+	date := serviceDateOf(f)
+	switch {
+	case date != "" && index.dates[date]:
+		o.Recommendation = distribution.RecommendDelete
+		o.Reason = fmt.Sprintf("history for %s links other files; likely a superseded upload", date)
+	case index.earliest.IsZero() || (!f.CreatedTime.IsZero() && f.CreatedTime.Before(index.earliest)):
+		o.Recommendation = distribution.RecommendKeep
+		o.Reason = "uploaded before history was kept"
+	case date == "":
+		o.Recommendation = distribution.RecommendReview
+		o.Reason = "no service date and no run recorded"
+	default:
+		o.Recommendation = distribution.RecommendReview
+		o.Reason = fmt.Sprintf("no run recorded for %s; uploaded by hand?", date)
+	}
+	return o
+}
@@ -0,0 +1,91 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+
+	"nac-service-media/domain/distribution"
+)
+
+// Strategies for detecting whether a service has already been uploaded
+const (
+	ProcessedCheckMetadata = distribution.ProcessedCheckMetadata
+	ProcessedCheckName     = distribution.ProcessedCheckName
+)
+
+// ProcessedStatus describes which outputs for a service date already exist in Drive
+type ProcessedStatus struct {
+	Video *distribution.FileInfo
+	Audio *distribution.FileInfo
+}
+
+// IsComplete returns true if both the video and audio have been uploaded
+func (s *ProcessedStatus) IsComplete() bool {
+	return s.Video != nil && s.Audio != nil
+}
+
+// ProcessedCheckService determines whether a service date has already been processed
+type ProcessedCheckService struct {
+	driveClient distribution.DriveClient
+	folderID    string
+	strategy    string
+}
+
+// NewProcessedCheckService creates a new processed check service.
+// An empty strategy defaults to ProcessedCheckMetadata.
+func NewProcessedCheckService(client distribution.DriveClient, folderID, strategy string) *ProcessedCheckService {
+	if strategy == "" {
+		strategy = ProcessedCheckMetadata
+	}
+	return &ProcessedCheckService{
+		driveClient: client,
+		folderID:    folderID,
+		strategy:    strategy,
+	}
+}
+
+// Check looks up the uploaded video and audio for a service date (YYYY-MM-DD)
+func (s *ProcessedCheckService) Check(ctx context.Context, serviceDate string) (*ProcessedStatus, error) {
+	status := &ProcessedStatus{}
+
+	switch s.strategy {
+	case ProcessedCheckMetadata:
+		files, err := s.driveClient.FindFilesByProperty(ctx, s.folderID, distribution.PropertyServiceDate, serviceDate)
+		if err != nil {
+			return nil, err
+		}
+		for i := range files {
+			switch files[i].MimeType {
+			case distribution.MimeTypeMP4:
+				if status.Video == nil {
+					status.Video = &files[i]
+				}
+			case distribution.MimeTypeMP3:
+				if status.Audio == nil {
+					status.Audio = &files[i]
+				}
+			}
+		}
+	case ProcessedCheckName:
+	default:
+		return nil, fmt.Errorf("unknown processed check strategy %q (use %q or %q)", s.strategy, ProcessedCheckMetadata, ProcessedCheckName)
+	}
+
+	// Fall back to name matching for anything the metadata query didn't find
+	if status.Video == nil {
+		video, err := s.driveClient.FindFileByName(ctx, s.folderID, serviceDate+".mp4")
+		if err != nil {
+			return nil, err
+		}
+		status.Video = video
+	}
+	if status.Audio == nil {
+		audio, err := s.driveClient.FindFileByName(ctx, s.folderID, serviceDate+".mp3")
+		if err != nil {
+			return nil, err
+		}
+		status.Audio = audio
+	}
+
+	return status, nil
+}
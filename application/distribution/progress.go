@@ -0,0 +1,45 @@
+package distribution
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"nac-service-media/domain/distribution"
+)
+
+// progressStep is how far, in percent, an upload gets between updates
+const progressStep = 10
+
+// uploadProgress prints how far an upload has got and its average transfer
+// rate, every progressStep percent and once when it finishes
+type uploadProgress struct {
+	output io.Writer
+	total  int64
+	start  time.Time
+	now    func() time.Time
+	next   int // Percentage the next update is printed at
+}
+
+func newUploadProgress(output io.Writer, total int64, now func() time.Time) *uploadProgress {
+	return &uploadProgress{output: output, total: total, start: now(), now: now, next: progressStep}
+}
+
+// report is the upload's progress callback
+func (p *uploadProgress) report(sent int64) {
+	if p.total <= 0 {
+		return
+	}
+	percent := int(min(sent, p.total) * 100 / p.total)
+	if percent < p.next && sent < p.total {
+		return
+	}
+	p.next = percent - percent%progressStep + progressStep
+
+	line := fmt.Sprintf("      %3d%% of %s", percent, distribution.FormatSize(p.total))
+	if elapsed := p.now().Sub(p.start); elapsed > 0 {
+		rate := float64(sent) / elapsed.Seconds()
+		line += fmt.Sprintf(" at %s/s", distribution.FormatSize(int64(rate)))
+	}
+	fmt.Fprintln(p.output, line)
+}
@@ -0,0 +1,97 @@
+package distribution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// PublishService copies outputs to an alternate download server alongside
+// a checksum file so recipients can verify what they downloaded
+type PublishService struct {
+	publisher distribution.Publisher
+	output    io.Writer
+	fs        domainfs.FS
+}
+
+// PublishOption is a functional option for configuring PublishService
+type PublishOption func(*PublishService)
+
+// WithPublishFS sets the file system published files are read from
+func WithPublishFS(fsys domainfs.FS) PublishOption {
+	return func(s *PublishService) {
+		s.fs = fsys
+	}
+}
+
+// NewPublishService creates a new publish service
+func NewPublishService(publisher distribution.Publisher, output io.Writer, opts ...PublishOption) *PublishService {
+	if output == nil {
+		output = io.Discard
+	}
+	s := &PublishService{
+		publisher: publisher,
+		output:    output,
+		fs:        osFS{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Publish copies a local file and its .sha256 checksum file to the server
+func (s *PublishService) Publish(ctx context.Context, localPath string) (*distribution.PublishedFile, error) {
+	name := filepath.Base(localPath)
+
+	sum, size, err := fileSHA256(s.fs, localPath)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := s.fs.Open(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", localPath, err)
+	}
+	defer f.Close()
+
+	fmt.Fprintf(s.output, "      Publishing %s (%.1f MB)...\n", name, float64(size)/1024/1024)
+	if err := s.publisher.Put(ctx, name, f, size); err != nil {
+		return nil, fmt.Errorf("failed to publish %s: %w", name, err)
+	}
+
+	// sha256sum format so `sha256sum -c` works on the downloaded pair
+	checksum := fmt.Sprintf("%s  %s\n", sum, name)
+	if err := s.publisher.Put(ctx, name+distribution.ChecksumSuffix, strings.NewReader(checksum), int64(len(checksum))); err != nil {
+		return nil, fmt.Errorf("failed to publish checksum for %s: %w", name, err)
+	}
+
+	return &distribution.PublishedFile{
+		Name:   name,
+		URL:    s.publisher.URL(name),
+		SHA256: sum,
+	}, nil
+}
+
+// fileSHA256 returns the hex sha256 digest and size of a file
+func fileSHA256(fsys domainfs.FS, path string) (string, int64, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
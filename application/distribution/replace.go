@@ -0,0 +1,69 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"nac-service-media/domain/distribution"
+)
+
+// ReplaceAudio uploads audioPath as the new content of the MP3 named fileName
+// in Drive. The file keeps its ID and sharing, so links already sent still work.
+// The new content is public at once, so it must pass the pre-share scan first.
+func (s *UploadService) ReplaceAudio(ctx context.Context, fileName, audioPath string) (*distribution.UploadResult, error) {
+	replacer, ok := s.driveClient.(distribution.ContentReplacer)
+	if !ok {
+		return nil, distribution.ErrReplaceUnsupported
+	}
+	if err := s.sharer.scan(ctx, audioPath); err != nil {
+		return nil, fmt.Errorf("not replacing %s: %w", fileName, err)
+	}
+
+	existing, err := s.driveClient.FindFileByName(ctx, s.folderID, fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing file: %w", err)
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("%s is not in Drive yet; upload it instead of replacing it", fileName)
+	}
+
+	result, err := replacer.ReplaceContent(ctx, existing.ID, distribution.UploadRequest{
+		LocalPath: audioPath,
+		FileName:  fileName,
+		FolderID:  s.folderID,
+		MimeType:  distribution.MimeTypeMP3,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace %s: %w", fileName, err)
+	}
+	result.FileID = existing.ID
+	result.FileName = fileName
+	result.ShareableURL = distribution.LinkFor(s.driveClient, existing.ID)
+	return result, nil
+}
+
+// DownloadFile saves the Drive file with the given name to localPath
+func (s *UploadService) DownloadFile(ctx context.Context, fileName, localPath string) error {
+	downloader, ok := s.driveClient.(distribution.Downloader)
+	if !ok {
+		return distribution.ErrDownloadUnsupported
+	}
+
+	existing, err := s.driveClient.FindFileByName(ctx, s.folderID, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to look for %s in Drive: %w", fileName, err)
+	}
+	if existing == nil {
+		return fmt.Errorf("%s was not found in Drive", fileName)
+	}
+
+	if err := s.sharer.fs.MkdirAll(filepath.Dir(localPath)); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	fmt.Fprintf(s.output, "Downloading %s from Drive (%s)...\n", fileName, distribution.FormatSize(existing.Size))
+	if err := downloader.Download(ctx, existing.ID, localPath); err != nil {
+		return fmt.Errorf("failed to download %s: %w", fileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"nac-service-media/domain/distribution"
+)
+
+// DefaultKeptRevisions is how many earlier versions of a replaced file stay pinned
+const DefaultKeptRevisions = 3
+
+// RevisionService keeps earlier versions of replaced files so a bad
+// replacement can be rolled back
+type RevisionService struct {
+	driveClient distribution.DriveClient
+	folderID    string
+	keep        int
+	output      io.Writer
+}
+
+// NewRevisionService creates a revision service that keeps the newest keep
+// earlier versions of each file pinned (DefaultKeptRevisions when zero)
+func NewRevisionService(client distribution.DriveClient, folderID string, keep int, output io.Writer) *RevisionService {
+	if output == nil {
+		output = io.Discard
+	}
+	if keep <= 0 {
+		keep = DefaultKeptRevisions
+	}
+	return &RevisionService{driveClient: client, folderID: folderID, keep: keep, output: output}
+}
+
+// FileRevisions is a Drive file and its revisions, oldest first
+type FileRevisions struct {
+	File      distribution.FileInfo
+	Revisions []distribution.Revision
+}
+
+// List returns the revisions of the file named fileName
+func (s *RevisionService) List(ctx context.Context, fileName string) (*FileRevisions, error) {
+	manager, file, err := s.find(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := manager.ListRevisions(ctx, file.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &FileRevisions{File: *file, Revisions: revisions}, nil
+}
+
+// KeepCurrent pins the file's current content before it is replaced, and
+// unpins the oldest pinned versions beyond the number to keep. A file not in
+// Drive yet has nothing to keep.
+func (s *RevisionService) KeepCurrent(ctx context.Context, fileName string) error {
+	manager, ok := s.driveClient.(distribution.RevisionManager)
+	if !ok {
+		return distribution.ErrRevisionsUnsupported
+	}
+	file, err := s.driveClient.FindFileByName(ctx, s.folderID, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to look for %s in Drive: %w", fileName, err)
+	}
+	if file == nil {
+		return nil
+	}
+	return s.keepCurrent(ctx, manager, file.ID)
+}
+
+// Restore makes an earlier revision the file's content again. The content
+// being replaced is pinned first, so the restore can be undone too.
+func (s *RevisionService) Restore(ctx context.Context, fileName, revisionID string) (*distribution.UploadResult, error) {
+	manager, file, err := s.find(ctx, fileName)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := manager.ListRevisions(ctx, file.ID)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, r := range revisions {
+		found = found || r.ID == revisionID
+	}
+	if !found {
+		return nil, fmt.Errorf("%s has no revision %q; run drive revisions list to see them", fileName, revisionID)
+	}
+	if revisions[len(revisions)-1].ID == revisionID {
+		return nil, fmt.Errorf("revision %s is already the current content of %s", revisionID, fileName)
+	}
+
+	if err := s.keepCurrent(ctx, manager, file.ID); err != nil {
+		return nil, err
+	}
+	mimeType := distribution.MimeTypeMP4
+	if filepath.Ext(fileName) == ".mp3" {
+		mimeType = distribution.MimeTypeMP3
+	}
+	result, err := manager.RestoreRevision(ctx, file.ID, revisionID, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	result.FileID = file.ID
+	result.FileName = fileName
+	result.ShareableURL = distribution.LinkFor(s.driveClient, file.ID)
+	return result, nil
+}
+
+func (s *RevisionService) keepCurrent(ctx context.Context, manager distribution.RevisionManager, fileID string) error {
+	revisions, err := manager.ListRevisions(ctx, fileID)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		return nil
+	}
+	current := &revisions[len(revisions)-1]
+	if !current.KeepForever {
+		if err := manager.KeepRevision(ctx, fileID, current.ID, true); err != nil {
+			return err
+		}
+		current.KeepForever = true
+		fmt.Fprintf(s.output, "Pinned the current revision %s (%s)\n", current.ID, current.ModifiedTime.Format("2006-01-02 15:04"))
+	}
+	for _, r := range distribution.PinsToRelease(revisions, s.keep) {
+		if err := manager.KeepRevision(ctx, fileID, r.ID, false); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.output, "Unpinned revision %s (%s); keeping the newest %d\n", r.ID, r.ModifiedTime.Format("2006-01-02 15:04"), s.keep)
+	}
+	return nil
+}
+
+// find looks up the file by name in the folder
+func (s *RevisionService) find(ctx context.Context, fileName string) (distribution.RevisionManager, *distribution.FileInfo, error) {
+	manager, ok := s.driveClient.(distribution.RevisionManager)
+	if !ok {
+		return nil, nil, distribution.ErrRevisionsUnsupported
+	}
+	file, err := s.driveClient.FindFileByName(ctx, s.folderID, fileName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to look for %s in Drive: %w", fileName, err)
+	}
+	if file == nil {
+		return nil, nil, fmt.Errorf("%s was not found in Drive", fileName)
+	}
+	return manager, file, nil
+}
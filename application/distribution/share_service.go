@@ -0,0 +1,194 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"nac-service-media/domain/clock"
+	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// Default retry policy for setting public sharing after an upload
+const (
+	DefaultShareAttempts  = 3
+	DefaultShareBaseDelay = 500 * time.Millisecond
+)
+
+// ShareService applies "anyone with the link" sharing to uploaded files, or
+// shares them with named people only
+type ShareService struct {
+	driveClient distribution.DriveClient
+	folderID    string
+	attempts    int
+	baseDelay   time.Duration
+	sleep       clock.Sleeper
+	scanner     distribution.Scanner
+	localDirs   []string
+	fs          domainfs.FS
+	readers     []string
+	expiry      distribution.Expiry
+	strategy    string // How ShareByDate finds a date's files; "" is ProcessedCheckMetadata
+}
+
+// ShareOption is a functional option for configuring ShareService
+type ShareOption func(*ShareService)
+
+// WithScanner scans each file's local copy before it is shared; a file that
+// fails the scan, or has no local copy to scan, is not shared
+func WithScanner(scanner distribution.Scanner) ShareOption {
+	return func(s *ShareService) {
+		s.scanner = scanner
+	}
+}
+
+// WithLocalCopies sets where ShareByDate looks for local copies to scan
+func WithLocalCopies(dirs ...string) ShareOption {
+	return func(s *ShareService) {
+		s.localDirs = dirs
+	}
+}
+
+// WithFS sets the file system local copies are read from and written to
+func WithFS(fsys domainfs.FS) ShareOption {
+	return func(s *ShareService) {
+		s.fs = fsys
+	}
+}
+
+// WithReaders shares each file with these addresses only, instead of with
+// anyone who has the link
+func WithReaders(addresses ...string) ShareOption {
+	return func(s *ShareService) {
+		s.readers = addresses
+	}
+}
+
+// WithExpiry tags each upload with when its readers' access ends and when it
+// is deleted, for ExpiryService to act on
+func WithExpiry(expiry distribution.Expiry) ShareOption {
+	return func(s *ShareService) {
+		s.expiry = expiry
+	}
+}
+
+// WithProcessedCheck sets how ShareByDate finds a service date's files, so it
+// agrees with process about which files belong to a service
+func WithProcessedCheck(strategy string) ShareOption {
+	return func(s *ShareService) {
+		s.strategy = strategy
+	}
+}
+
+// NewShareService creates a new share service with the default retry policy
+func NewShareService(client distribution.DriveClient, folderID string, opts ...ShareOption) *ShareService {
+	s := &ShareService{
+		driveClient: client,
+		folderID:    folderID,
+		attempts:    DefaultShareAttempts,
+		baseDelay:   DefaultShareBaseDelay,
+		sleep:       clock.Sleep,
+		fs:          osFS{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ScanAndShare scans localPath, the file's local copy, then shares the file.
+// Without a scanner it only shares.
+func (s *ShareService) ScanAndShare(ctx context.Context, fileID, localPath string) error {
+	if err := s.scan(ctx, localPath); err != nil {
+		return err
+	}
+	return s.Share(ctx, fileID)
+}
+
+// scan runs the scanner, if any, on a local copy
+func (s *ShareService) scan(ctx context.Context, localPath string) error {
+	if s.scanner == nil {
+		return nil
+	}
+	if localPath == "" {
+		return fmt.Errorf("%w: no local copy to scan", distribution.ErrScanFailed)
+	}
+	return s.scanner.Scan(ctx, localPath)
+}
+
+// Share sets public sharing on a file, or shares it with each reader set by
+// WithReaders, retrying with exponential backoff
+func (s *ShareService) Share(ctx context.Context, fileID string) error {
+	if len(s.readers) == 0 {
+		return s.retry(ctx, func() error { return s.driveClient.SetPublicSharing(ctx, fileID) })
+	}
+	sharer, ok := s.driveClient.(distribution.UserSharer)
+	if !ok {
+		return distribution.ErrUserSharingUnsupported
+	}
+	for _, address := range s.readers {
+		if err := s.retry(ctx, func() error { return sharer.ShareWithUser(ctx, fileID, address) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retry runs fn until it succeeds or the attempts run out
+func (s *ShareService) retry(ctx context.Context, fn func() error) error {
+	var err error
+	delay := s.baseDelay
+	for attempt := 1; attempt <= s.attempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt < s.attempts {
+			if sleepErr := s.sleep(ctx, delay); sleepErr != nil {
+				return sleepErr
+			}
+			delay *= 2
+		}
+	}
+	return fmt.Errorf("sharing failed after %d attempts: %w", s.attempts, err)
+}
+
+// ShareByDate finds the uploaded video and audio for a service date (YYYY-MM-DD)
+// and sets public sharing on each. It returns the files that were shared.
+func (s *ShareService) ShareByDate(ctx context.Context, serviceDate string) ([]distribution.FileInfo, error) {
+	checker := NewProcessedCheckService(s.driveClient, s.folderID, s.strategy)
+	status, err := checker.Check(ctx, serviceDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find files for %s: %w", serviceDate, err)
+	}
+
+	var files []distribution.FileInfo
+	for _, f := range []*distribution.FileInfo{status.Video, status.Audio} {
+		if f != nil {
+			files = append(files, *f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no uploaded files found in Drive for %s", serviceDate)
+	}
+
+	for _, f := range files {
+		if err := s.ScanAndShare(ctx, f.ID, s.localCopy(f.Name)); err != nil {
+			return nil, fmt.Errorf("failed to share %s: %w", f.Name, err)
+		}
+	}
+	return files, nil
+}
+
+// localCopy returns the first local file named name in the local copy
+// directories, or "" when there is none
+func (s *ShareService) localCopy(name string) string {
+	for _, dir := range s.localDirs {
+		path := filepath.Join(dir, name)
+		if _, err := s.fs.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
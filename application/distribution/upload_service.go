@@ -2,10 +2,14 @@ package distribution
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
-	"os"
+	"io/fs"
 	"path/filepath"
+	"time"
 
 	"nac-service-media/domain/distribution"
 )
@@ -15,10 +19,13 @@ type UploadService struct {
 	driveClient distribution.DriveClient
 	folderID    string
 	output      io.Writer
+	sharer      *ShareService
 }
 
-// NewUploadService creates a new upload service
-func NewUploadService(client distribution.DriveClient, folderID string, output io.Writer) *UploadService {
+// NewUploadService creates a new upload service. Share options apply to the
+// sharing that follows each upload, and WithExpiry tags each upload; WithFS also sets where local
+// copies are checked and saved.
+func NewUploadService(client distribution.DriveClient, folderID string, output io.Writer, opts ...ShareOption) *UploadService {
 	if output == nil {
 		output = io.Discard
 	}
@@ -26,6 +33,7 @@ func NewUploadService(client distribution.DriveClient, folderID string, output i
 		driveClient: client,
 		folderID:    folderID,
 		output:      output,
+		sharer:      NewShareService(client, folderID, opts...),
 	}
 }
 
@@ -37,6 +45,8 @@ type DistributionResult struct {
 	AudioURL   string
 	AudioID    string
 	AudioSize  int64
+
+	SharingPending bool // True if either file still needs public sharing
 }
 
 // UploadVideo uploads a video file to Google Drive and sets public sharing
@@ -49,24 +59,125 @@ func (s *UploadService) UploadAudio(ctx context.Context, audioPath string) (*dis
 	return s.uploadAndShare(ctx, audioPath, distribution.MimeTypeMP3)
 }
 
-// uploadAndShare uploads a file and sets public sharing permissions
+// uploadAndShare uploads a file, printing its progress, and sets public
+// sharing permissions
 func (s *UploadService) uploadAndShare(ctx context.Context, filePath, mimeType string) (*distribution.UploadResult, error) {
 	// Verify file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	info, statErr := s.sharer.fs.Stat(filePath)
+	if errors.Is(statErr, fs.ErrNotExist) {
 		return nil, fmt.Errorf("file does not exist: %s", filePath)
 	}
 
+	req, err := s.prepareUpload(ctx, filePath, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	if statErr == nil {
+		req.Progress = newUploadProgress(s.output, info.Size(), time.Now).report
+	}
+
+	result, err := s.driveClient.Upload(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload and share %s: %w", req.FileName, err)
+	}
+
+	s.share(ctx, result, filePath)
+	return result, nil
+}
+
+// UploadAudioStream uploads the MP3 that write produces while it is being
+// produced, named after audioPath. With keepLocal it is also saved to
+// audioPath for steps that need a local copy; otherwise nothing is written to
+// disk. Drive's size and checksum are compared with what was sent; a mismatch
+// removes the upload and returns an error.
+func (s *UploadService) UploadAudioStream(ctx context.Context, audioPath string, keepLocal bool, write func(io.Writer) error) (*distribution.UploadResult, error) {
+	uploader, ok := s.driveClient.(distribution.StreamUploader)
+	if !ok {
+		return nil, distribution.ErrStreamingUnsupported
+	}
+
+	req, err := s.prepareUpload(ctx, audioPath, distribution.MimeTypeMP3)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := md5.New()
+	counter := &countingWriter{}
+	sinks := []io.Writer{hash, counter}
+	var local io.WriteCloser
+	if keepLocal {
+		if err := s.sharer.fs.MkdirAll(filepath.Dir(audioPath)); err != nil {
+			return nil, fmt.Errorf("failed to create audio directory: %w", err)
+		}
+		if local, err = s.sharer.fs.Create(audioPath); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", audioPath, err)
+		}
+		defer local.Close()
+		sinks = append(sinks, local)
+	}
+
+	// Stop the encoder if the upload fails, and the upload if the encoder does
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := write(io.MultiWriter(append([]io.Writer{pw}, sinks...)...))
+		pw.CloseWithError(err)
+		written <- err
+	}()
+
+	result, err := uploader.UploadStream(ctx, req, pr)
+	if err != nil {
+		cancel()
+	}
+	pr.Close() // Unblocks the encoder if the upload stopped reading early
+	writeErr := <-written
+
+	if err == nil && writeErr != nil {
+		// The encoder failed or was cut off, so whatever Drive kept is partial
+		s.removeUpload(ctx, result)
+		err = writeErr
+	}
+	if err == nil {
+		if err = distribution.VerifyUpload(result, counter.n, hex.EncodeToString(hash.Sum(nil))); err != nil {
+			s.removeUpload(ctx, result)
+		}
+	}
+	if err != nil {
+		if local != nil {
+			local.Close()
+			s.sharer.fs.Remove(audioPath)
+		}
+		return nil, fmt.Errorf("failed to stream %s: %w", req.FileName, err)
+	}
+	if local == nil {
+		s.share(ctx, result, "")
+		return result, nil
+	}
+	if err := local.Close(); err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", audioPath, err)
+	}
+
+	s.share(ctx, result, audioPath)
+	return result, nil
+}
+
+// prepareUpload replaces any Drive file with the same name and builds the
+// upload request, tagged with the service date from the file name
+func (s *UploadService) prepareUpload(ctx context.Context, filePath, mimeType string) (distribution.UploadRequest, error) {
 	fileName := filepath.Base(filePath)
 
 	// Check for existing file with same name and delete if found
 	existing, err := s.driveClient.FindFileByName(ctx, s.folderID, fileName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check for existing file: %w", err)
+		return distribution.UploadRequest{}, fmt.Errorf("failed to check for existing file: %w", err)
 	}
 	if existing != nil {
 		fmt.Fprintf(s.output, "      Replacing existing %s (%.1f MB)\n", existing.Name, float64(existing.Size)/1024/1024)
 		if err := s.driveClient.DeletePermanently(ctx, existing.ID); err != nil {
-			return nil, fmt.Errorf("failed to delete existing file %s: %w", existing.Name, err)
+			return distribution.UploadRequest{}, fmt.Errorf("failed to delete existing file %s: %w", existing.Name, err)
 		}
 	}
 
@@ -76,13 +187,46 @@ func (s *UploadService) uploadAndShare(ctx context.Context, filePath, mimeType s
 		FolderID:  s.folderID,
 		MimeType:  mimeType,
 	}
+	req.AppProperties = distribution.InferProperties(fileName, mimeType)
+	for k, v := range s.sharer.expiry.Properties() {
+		if req.AppProperties == nil {
+			req.AppProperties = make(map[string]string)
+		}
+		req.AppProperties[k] = v
+	}
+	return req, nil
+}
 
-	result, err := s.driveClient.UploadAndShare(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to upload and share %s: %w", fileName, err)
+// share sets the shareable URL and public sharing on an upload. The file is
+// safely in Drive at this point; a sharing failure should not lose the
+// upload, so flag it for a later `drive share` instead. localPath is
+// scanned first when a scanner is configured.
+func (s *UploadService) share(ctx context.Context, result *distribution.UploadResult, localPath string) {
+	result.ShareableURL = distribution.LinkFor(s.driveClient, result.FileID)
+	if err := s.sharer.ScanAndShare(ctx, result.FileID, localPath); err != nil {
+		fmt.Fprintf(s.output, "      Warning: uploaded %s but could not share it: %v\n", result.FileName, err)
+		result.SharingPending = true
 	}
+}
 
-	return result, nil
+// removeUpload deletes an upload that turned out to be incomplete
+func (s *UploadService) removeUpload(ctx context.Context, result *distribution.UploadResult) {
+	if result == nil {
+		return
+	}
+	if err := s.driveClient.DeletePermanently(context.WithoutCancel(ctx), result.FileID); err != nil {
+		fmt.Fprintf(s.output, "      Warning: could not remove incomplete upload %s: %v\n", result.FileName, err)
+	}
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
 }
 
 // Distribute uploads both video and audio files to Google Drive with sharing
@@ -104,5 +248,7 @@ func (s *UploadService) Distribute(ctx context.Context, videoPath, audioPath str
 		AudioURL:  audioResult.ShareableURL,
 		AudioID:   audioResult.FileID,
 		AudioSize: audioResult.Size,
+
+		SharingPending: videoResult.SharingPending || audioResult.SharingPending,
 	}, nil
 }
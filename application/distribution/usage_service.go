@@ -0,0 +1,41 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+
+	"nac-service-media/domain/distribution"
+)
+
+// DefaultUsageTop is how many of the largest files the usage report lists
+const DefaultUsageTop = 10
+
+// UsageService reports Drive storage used by the Services folder
+type UsageService struct {
+	driveClient distribution.DriveClient
+	folderID    string
+}
+
+// NewUsageService creates a new usage service
+func NewUsageService(client distribution.DriveClient, folderID string) *UsageService {
+	return &UsageService{
+		driveClient: client,
+		folderID:    folderID,
+	}
+}
+
+// Report lists every file in the Services folder and aggregates it by service
+// year and month, keeping the top largest files
+func (s *UsageService) Report(ctx context.Context, top int) (*distribution.UsageReport, error) {
+	quota, err := s.driveClient.GetStorageQuota(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check storage: %w", err)
+	}
+
+	files, err := s.driveClient.ListFiles(ctx, s.folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return distribution.BuildUsageReport(*quota, files, top), nil
+}
@@ -0,0 +1,317 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
+	"nac-service-media/domain/history"
+)
+
+// YearArchiveService gathers a year's services into Archive/<year> under the
+// Services folder, writes an index of them, and shares the year folder with
+// leadership
+type YearArchiveService struct {
+	driveClient distribution.DriveClient
+	folderID    string
+	history     history.Store
+	fs          domainfs.FS
+	now         func() time.Time
+}
+
+// YearArchiveOption configures a YearArchiveService
+type YearArchiveOption func(*YearArchiveService)
+
+// WithYearArchiveHistory fills in each service's minister and title from history
+func WithYearArchiveHistory(store history.Store) YearArchiveOption {
+	return func(s *YearArchiveService) {
+		s.history = store
+	}
+}
+
+// WithYearArchiveFS sets the file system the index is written to
+func WithYearArchiveFS(fsys domainfs.FS) YearArchiveOption {
+	return func(s *YearArchiveService) {
+		s.fs = fsys
+	}
+}
+
+// WithYearArchiveClock sets the clock used for the index's generated date
+func WithYearArchiveClock(now func() time.Time) YearArchiveOption {
+	return func(s *YearArchiveService) {
+		s.now = now
+	}
+}
+
+// NewYearArchiveService creates a year archive service for the Services folder
+func NewYearArchiveService(client distribution.DriveClient, folderID string, opts ...YearArchiveOption) *YearArchiveService {
+	s := &YearArchiveService{
+		driveClient: client,
+		folderID:    folderID,
+		fs:          osFS{},
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// YearArchiveRequest says which year to archive and who may read it
+type YearArchiveRequest struct {
+	Year       int
+	FolderName string // Services subfolder holding a folder per year
+	Church     string // Heads the index
+	ShareWith  []string
+	IndexPath  string // Where the index is written before it is uploaded
+	DryRun     bool
+}
+
+// FailedMove is a file that could not be moved into the year folder
+type FailedMove struct {
+	File distribution.FileInfo
+	Err  error
+}
+
+// FailedShare is an address the year folder could not be shared with
+type FailedShare struct {
+	Address string
+	Err     error
+}
+
+// YearArchiveResult reports what archiving a year changed, or in a dry run
+// would change
+type YearArchiveResult struct {
+	YearFolderID string
+	// CreatedFolders are the folders made, such as "Archive/2025"
+	CreatedFolders []string
+	Moved          []distribution.FileInfo
+	Failed         []FailedMove
+	// AlreadyArchived counts files the year folder held before this run
+	AlreadyArchived int
+
+	Index        distribution.YearIndex
+	IndexFileID  string
+	IndexUpdated bool // An index from an earlier run was replaced
+
+	SharedWith  []string
+	ShareFailed []FailedShare
+}
+
+// Archive moves every Services folder file dated in req.Year into the year
+// folder, creating it and the archive folder when missing. It then writes the
+// index of the year's services into the year folder and shares the folder
+// with req.ShareWith. A file or address that fails is recorded and the rest
+// carry on. With DryRun nothing is changed.
+func (s *YearArchiveService) Archive(ctx context.Context, req YearArchiveRequest) (*YearArchiveResult, error) {
+	organizer, ok := s.driveClient.(distribution.FolderOrganizer)
+	if !ok {
+		return nil, distribution.ErrFoldersUnsupported
+	}
+	var sharer distribution.UserSharer
+	if len(req.ShareWith) > 0 {
+		if sharer, ok = s.driveClient.(distribution.UserSharer); !ok {
+			return nil, distribution.ErrUserSharingUnsupported
+		}
+	}
+	folderName := req.FolderName
+	if folderName == "" {
+		folderName = distribution.DefaultArchiveFolderName
+	}
+	yearName := strconv.Itoa(req.Year)
+	result := &YearArchiveResult{}
+
+	files, err := s.driveClient.ListFiles(ctx, s.folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	var toMove []distribution.FileInfo
+	for _, f := range files {
+		if date, ok := distribution.FileServiceDate(f); ok && date.Year() == req.Year && f.MimeType != distribution.FolderMimeType {
+			toMove = append(toMove, f)
+		}
+	}
+
+	archiveID, err := s.folder(ctx, organizer, s.folderID, folderName, folderName, req.DryRun, result)
+	if err != nil {
+		return nil, err
+	}
+	var archived []distribution.FileInfo
+	if archiveID != "" {
+		yearID, err := s.folder(ctx, organizer, archiveID, yearName, folderName+"/"+yearName, req.DryRun, result)
+		if err != nil {
+			return nil, err
+		}
+		result.YearFolderID = yearID
+	} else {
+		// A dry run with no archive folder yet would create the year folder too
+		result.CreatedFolders = append(result.CreatedFolders, folderName+"/"+yearName)
+	}
+	if result.YearFolderID != "" {
+		if archived, err = s.driveClient.ListFiles(ctx, result.YearFolderID); err != nil {
+			return nil, fmt.Errorf("failed to list %s/%s: %w", folderName, yearName, err)
+		}
+	}
+	indexName := distribution.YearIndexFileName(req.Year)
+	for _, f := range archived {
+		if f.Name != indexName && f.MimeType != distribution.FolderMimeType {
+			result.AlreadyArchived++
+		}
+	}
+	if len(toMove) == 0 && result.AlreadyArchived == 0 {
+		return nil, fmt.Errorf("no %d services found in the Services folder", req.Year)
+	}
+
+	for _, f := range toMove {
+		if !req.DryRun {
+			if err := organizer.MoveFile(ctx, f.ID, s.folderID, result.YearFolderID); err != nil {
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				result.Failed = append(result.Failed, FailedMove{File: f, Err: err})
+				continue
+			}
+		}
+		result.Moved = append(result.Moved, f)
+		archived = append(archived, f)
+	}
+
+	index, err := s.buildIndex(req, archived, indexName)
+	if err != nil {
+		return result, err
+	}
+	result.Index = index
+	if req.DryRun {
+		result.SharedWith = req.ShareWith
+		return result, nil
+	}
+
+	if err := s.uploadIndex(ctx, req, result); err != nil {
+		return result, err
+	}
+
+	for _, address := range req.ShareWith {
+		if err := sharer.ShareWithUser(ctx, result.YearFolderID, address); err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			result.ShareFailed = append(result.ShareFailed, FailedShare{Address: address, Err: err})
+			continue
+		}
+		result.SharedWith = append(result.SharedWith, address)
+	}
+	return result, nil
+}
+
+// folder returns the ID of the folder called name inside parentID, creating
+// it when missing. In a dry run a missing folder is only recorded and its ID
+// is empty.
+func (s *YearArchiveService) folder(ctx context.Context, organizer distribution.FolderOrganizer, parentID, name, label string, dryRun bool, result *YearArchiveResult) (string, error) {
+	existing, err := s.driveClient.FindFileByName(ctx, parentID, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to look for the %s folder: %w", label, err)
+	}
+	if existing != nil {
+		if existing.MimeType != distribution.FolderMimeType {
+			return "", fmt.Errorf("%s is a file, not a folder; rename it or set year_archive.folder_name", label)
+		}
+		return existing.ID, nil
+	}
+
+	result.CreatedFolders = append(result.CreatedFolders, label)
+	if dryRun {
+		return "", nil
+	}
+	created, err := organizer.CreateFolder(ctx, parentID, name)
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// buildIndex lists the year folder's services, with minister and title from
+// history where it has them
+func (s *YearArchiveService) buildIndex(req YearArchiveRequest, files []distribution.FileInfo, indexName string) (distribution.YearIndex, error) {
+	details := make(map[string]distribution.ServiceDetails)
+	if s.history != nil {
+		entries, err := s.history.List()
+		if err != nil {
+			return distribution.YearIndex{}, fmt.Errorf("failed to read history: %w", err)
+		}
+		for _, e := range entries {
+			if e.ServiceDate.Year() != req.Year {
+				continue
+			}
+			details[e.ServiceDate.Format("2006-01-02")] = distribution.ServiceDetails{Minister: e.Minister, Title: e.Title}
+		}
+	}
+
+	var services []distribution.FileInfo
+	for _, f := range files {
+		if f.Name != indexName {
+			services = append(services, f)
+		}
+	}
+	link := func(fileID string) string { return distribution.LinkFor(s.driveClient, fileID) }
+
+	index := distribution.YearIndex{
+		Church:    req.Church,
+		Year:      req.Year,
+		Entries:   distribution.BuildYearIndexEntries(services, details, link),
+		Generated: s.now(),
+	}
+	return index, nil
+}
+
+// uploadIndex writes the index locally and uploads it into the year folder,
+// replacing the one an earlier run left so its link keeps working
+func (s *YearArchiveService) uploadIndex(ctx context.Context, req YearArchiveRequest, result *YearArchiveResult) error {
+	result.Index.FolderURL = distribution.FolderURL(result.YearFolderID)
+	html, err := result.Index.RenderHTML()
+	if err != nil {
+		return err
+	}
+	if err := s.fs.MkdirAll(filepath.Dir(req.IndexPath)); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	w, err := s.fs.Create(req.IndexPath)
+	if err != nil {
+		return fmt.Errorf("failed to write the index: %w", err)
+	}
+	if _, err := io.WriteString(w, html); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write the index: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write the index: %w", err)
+	}
+
+	upload := distribution.UploadRequest{
+		LocalPath: req.IndexPath,
+		FileName:  distribution.YearIndexFileName(req.Year),
+		FolderID:  result.YearFolderID,
+		MimeType:  distribution.MimeTypeHTML,
+	}
+	existing, err := s.driveClient.FindFileByName(ctx, result.YearFolderID, upload.FileName)
+	if err != nil {
+		return fmt.Errorf("failed to look for an earlier index: %w", err)
+	}
+	if replacer, ok := s.driveClient.(distribution.ContentReplacer); ok && existing != nil {
+		if _, err := replacer.ReplaceContent(ctx, existing.ID, upload); err != nil {
+			return fmt.Errorf("failed to update %s: %w", upload.FileName, err)
+		}
+		result.IndexFileID, result.IndexUpdated = existing.ID, true
+		return nil
+	}
+	uploaded, err := s.driveClient.Upload(ctx, upload)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", upload.FileName, err)
+	}
+	result.IndexFileID = uploaded.FileID
+	return nil
+}
@@ -0,0 +1,70 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Status is the outcome of one doctor finding
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusWarn
+	StatusFail
+)
+
+// String returns the label printed before a finding
+func (s Status) String() string {
+	switch s {
+	case StatusWarn:
+		return "warn"
+	case StatusFail:
+		return "FAIL"
+	default:
+		return "ok"
+	}
+}
+
+// Result is one finding of a check
+type Result struct {
+	Status Status
+	Detail string
+}
+
+// Check examines one area of the environment, such as the network
+type Check interface {
+	Name() string
+	Run(ctx context.Context) []Result
+}
+
+// Service runs doctor checks and reports their findings
+type Service struct {
+	checks []Check
+	output io.Writer
+}
+
+// NewService creates a doctor service that runs the checks in order
+func NewService(output io.Writer, checks ...Check) *Service {
+	if output == nil {
+		output = io.Discard
+	}
+	return &Service{checks: checks, output: output}
+}
+
+// Run prints every check's findings and returns how many failed
+func (s *Service) Run(ctx context.Context) int {
+	failed := 0
+	for _, check := range s.checks {
+		fmt.Fprintln(s.output, check.Name())
+		for _, r := range check.Run(ctx) {
+			fmt.Fprintf(s.output, "  %-5s %s\n", r.Status, r.Detail)
+			if r.Status == StatusFail {
+				failed++
+			}
+		}
+		fmt.Fprintln(s.output)
+	}
+	return failed
+}
@@ -0,0 +1,110 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"nac-service-media/domain/history"
+)
+
+// ExportRow is one service in an export
+type ExportRow struct {
+	Date       string `json:"date"` // YYYY-MM-DD
+	Minister   string `json:"minister"`
+	Duration   string `json:"duration"` // HH:MM:SS
+	VideoSize  int64  `json:"video_size_bytes"`
+	AudioSize  int64  `json:"audio_size_bytes"`
+	Recipients int    `json:"recipients"`
+	VideoURL   string `json:"video_url"`
+	AudioURL   string `json:"audio_url"`
+	Title      string `json:"title,omitempty"`
+	Scripture  string `json:"scripture,omitempty"`
+}
+
+var csvHeader = []string{"date", "minister", "duration", "video_size_bytes", "audio_size_bytes", "recipients", "video_url", "audio_url", "title", "scripture"}
+
+// ExportService writes history for reporting
+type ExportService struct {
+	store history.Store
+}
+
+// NewExportService creates a new export service
+func NewExportService(store history.Store) *ExportService {
+	return &ExportService{store: store}
+}
+
+// Export writes the entries matching filter to w and returns how many were written
+func (s *ExportService) Export(w io.Writer, format string, filter history.Filter) (int, error) {
+	entries, err := s.store.List()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	rows := []ExportRow{}
+	for _, e := range filter.Apply(entries) {
+		rows = append(rows, toRow(e))
+	}
+
+	switch format {
+	case history.FormatCSV:
+		err = writeCSV(w, rows)
+	case history.FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rows)
+	default:
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write %s export: %w", format, err)
+	}
+	return len(rows), nil
+}
+
+func toRow(e history.Entry) ExportRow {
+	return ExportRow{
+		Date:       e.ServiceDate.Format("2006-01-02"),
+		Minister:   e.Minister,
+		Duration:   formatSeconds(e.DurationSeconds),
+		VideoSize:  e.VideoSize,
+		AudioSize:  e.AudioSize,
+		Recipients: len(e.Recipients),
+		VideoURL:   e.VideoURL,
+		AudioURL:   e.AudioURL,
+		Title:      e.Title,
+		Scripture:  e.Scripture,
+	}
+}
+
+func writeCSV(w io.Writer, rows []ExportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write([]string{
+			r.Date,
+			r.Minister,
+			r.Duration,
+			strconv.FormatInt(r.VideoSize, 10),
+			strconv.FormatInt(r.AudioSize, 10),
+			strconv.Itoa(r.Recipients),
+			r.VideoURL,
+			r.AudioURL,
+			r.Title,
+			r.Scripture,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatSeconds formats a duration as HH:MM:SS
+func formatSeconds(total int) string {
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, (total%3600)/60, total%60)
+}
@@ -0,0 +1,55 @@
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/history"
+)
+
+// ServiceNotes are the notes recorded for one processed service
+type ServiceNotes struct {
+	ServiceDate time.Time
+	Minister    string
+	Notes       []history.Note
+}
+
+// NoteService attaches operator notes to processed services
+type NoteService struct {
+	store history.Store
+	now   func() time.Time
+}
+
+// NewNoteService creates a new note service
+func NewNoteService(store history.Store) *NoteService {
+	return &NoteService{store: store, now: time.Now}
+}
+
+// Add attaches text to the most recent run for the service date
+func (s *NoteService) Add(serviceDate time.Time, text string) (history.Note, error) {
+	note, err := history.NewNote(text, s.now())
+	if err != nil {
+		return history.Note{}, err
+	}
+	if err := s.store.AddNote(serviceDate, note); err != nil {
+		return history.Note{}, fmt.Errorf("failed to add note: %w", err)
+	}
+	return note, nil
+}
+
+// List returns the services matching filter that have notes, in recorded order
+func (s *NoteService) List(filter history.Filter) ([]ServiceNotes, error) {
+	entries, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var result []ServiceNotes
+	for _, e := range filter.Apply(entries) {
+		if len(e.Notes) == 0 {
+			continue
+		}
+		result = append(result, ServiceNotes{ServiceDate: e.ServiceDate, Minister: e.Minister, Notes: e.Notes})
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/history"
+)
+
+// RunService looks up the runs recorded in history
+type RunService struct {
+	store history.Store
+}
+
+// NewRunService creates a new run service
+func NewRunService(store history.Store) *RunService {
+	return &RunService{store: store}
+}
+
+// List returns the runs matching filter, in recorded order
+func (s *RunService) List(filter history.Filter) ([]history.Entry, error) {
+	entries, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return filter.Apply(entries), nil
+}
+
+// Show returns every run recorded for the service date, oldest first, or
+// ErrNoEntry when there is none
+func (s *RunService) Show(serviceDate time.Time) ([]history.Entry, error) {
+	entries, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	runs := history.OnDate(entries, serviceDate)
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("%w on %s", history.ErrNoEntry, serviceDate.Format("2006-01-02"))
+	}
+	return runs, nil
+}
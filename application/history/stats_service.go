@@ -0,0 +1,26 @@
+package history
+
+import (
+	"fmt"
+
+	"nac-service-media/domain/history"
+)
+
+// StatsService reports on processed services for leadership
+type StatsService struct {
+	store history.Store
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(store history.Store) *StatsService {
+	return &StatsService{store: store}
+}
+
+// Ministers tallies the services matching filter per minister
+func (s *StatsService) Ministers(filter history.Filter) ([]history.MinisterStat, error) {
+	entries, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return history.MinisterStats(filter.Apply(entries)), nil
+}
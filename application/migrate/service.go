@@ -0,0 +1,148 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/history"
+)
+
+// importNote marks history entries seeded from Drive rather than recorded by a run
+const importNote = "Imported from Drive by migrate scan"
+
+// Service moves a congregation from manual uploads onto this tool: it renames
+// the Drive files to the canonical YYYY-MM-DD names and records each service
+// in history
+type Service struct {
+	driveClient distribution.DriveClient
+	folderID    string
+	history     history.Store
+}
+
+// NewService creates a migration service for the folder
+func NewService(client distribution.DriveClient, folderID string, store history.Store) *Service {
+	return &Service{driveClient: client, folderID: folderID, history: store}
+}
+
+// FailedRename is a file that could not be renamed
+type FailedRename struct {
+	File distribution.MigrationFile
+	Err  error
+}
+
+// Result reports what a migration changed, or in a dry run would change
+type Result struct {
+	Renamed []distribution.MigrationFile
+	Failed  []FailedRename
+	// Seeded are the history entries added for services history lacked
+	Seeded []history.Entry
+	// AlreadyRecorded counts service dates history already had
+	AlreadyRecorded int
+}
+
+// Scan inventories the folder and plans the renames
+func (s *Service) Scan(ctx context.Context) (*distribution.MigrationPlan, error) {
+	files, err := s.driveClient.ListFiles(ctx, s.folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	plan := distribution.PlanMigration(files)
+	return &plan, nil
+}
+
+// Apply renames the planned files and adds a history entry for each service
+// date history does not have yet. A file that fails to rename is recorded and
+// the rest carry on; it is left out of history. With dryRun nothing is changed.
+func (s *Service) Apply(ctx context.Context, plan *distribution.MigrationPlan, dryRun bool) (*Result, error) {
+	result := &Result{}
+
+	if dryRun {
+		result.Renamed = plan.Renames
+	} else if len(plan.Renames) > 0 {
+		renamer, ok := s.driveClient.(distribution.FileRenamer)
+		if !ok {
+			return result, distribution.ErrRenameUnsupported
+		}
+		for _, mf := range plan.Renames {
+			if err := renamer.RenameFile(ctx, mf.File.ID, mf.NewName); err != nil {
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				result.Failed = append(result.Failed, FailedRename{File: mf, Err: err})
+				continue
+			}
+			result.Renamed = append(result.Renamed, mf)
+		}
+	}
+
+	if s.history == nil {
+		return result, nil
+	}
+	entries, err := s.seedEntries(append(append([]distribution.MigrationFile{}, plan.Canonical...), result.Renamed...), result)
+	if err != nil {
+		return result, err
+	}
+	for _, e := range entries {
+		if !dryRun {
+			if err := s.history.Append(e); err != nil {
+				return result, fmt.Errorf("failed to record %s in history: %w", e.ServiceDate.Format("2006-01-02"), err)
+			}
+		}
+		result.Seeded = append(result.Seeded, e)
+	}
+	return result, nil
+}
+
+// seedEntries builds one history entry per service date history lacks, from
+// that date's video and audio files, oldest first
+func (s *Service) seedEntries(files []distribution.MigrationFile, result *Result) ([]history.Entry, error) {
+	existing, err := s.history.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	recorded := make(map[string]bool)
+	for _, e := range existing {
+		recorded[e.ServiceDate.Format("2006-01-02")] = true
+	}
+
+	byDate := make(map[string]*history.Entry)
+	skipped := make(map[string]bool)
+	for _, mf := range files {
+		date := mf.ServiceDate.Format("2006-01-02")
+		if recorded[date] {
+			skipped[date] = true
+			continue
+		}
+		e, ok := byDate[date]
+		if !ok {
+			e = &history.Entry{ServiceDate: mf.ServiceDate, Outcome: history.OutcomeSuccess}
+			byDate[date] = e
+		}
+		if e.ProcessedAt.IsZero() || mf.File.CreatedTime.Before(e.ProcessedAt) {
+			e.ProcessedAt = mf.File.CreatedTime
+		}
+		url := distribution.LinkFor(s.driveClient, mf.File.ID)
+		if path.Ext(mf.NewName) == ".mp4" {
+			e.VideoSize, e.VideoFileID, e.VideoURL = mf.File.Size, mf.File.ID, url
+		} else {
+			e.AudioSize, e.AudioFileID, e.AudioURL = mf.File.Size, mf.File.ID, url
+		}
+	}
+
+	result.AlreadyRecorded = len(skipped)
+
+	entries := make([]history.Entry, 0, len(byDate))
+	for _, e := range byDate {
+		if note, err := history.NewNote(importNote, e.ProcessedAt); err == nil {
+			e.Notes = []history.Note{note}
+		}
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ServiceDate.Before(entries[j].ServiceDate)
+	})
+	return entries, nil
+}
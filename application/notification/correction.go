@@ -0,0 +1,125 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/notification"
+)
+
+// ErrNoRecordedRecipients is returned when a run's history does not say who
+// the recording email went to, so there is no one to correct
+var ErrNoRecordedRecipients = errors.New("no recipients recorded")
+
+// CorrectionService sends a short correction when a recording email named the
+// wrong minister. The correction replies to the original email so it threads
+// under it, and goes only to the original recipients.
+type CorrectionService struct {
+	notifier *Service
+	store    history.Store
+	now      func() time.Time
+}
+
+// NewCorrectionService creates a correction service. The options are those
+// of the notification service; greeting, sandbox and send timeout apply.
+func NewCorrectionService(sender notification.EmailSender, store history.Store, churchName, senderName string, opts ...Option) *CorrectionService {
+	return &CorrectionService{
+		notifier: NewService(sender, churchName, senderName, opts...),
+		store:    store,
+		now:      time.Now,
+	}
+}
+
+// Find returns the latest run for the service date, which must have recorded
+// its recipients
+func (s *CorrectionService) Find(serviceDate time.Time) (history.Entry, error) {
+	entries, err := s.store.List()
+	if err != nil {
+		return history.Entry{}, fmt.Errorf("failed to read history: %w", err)
+	}
+	entry, err := history.Latest(entries, serviceDate)
+	if err != nil {
+		return history.Entry{}, err
+	}
+	if len(entry.Recipients) == 0 {
+		return history.Entry{}, fmt.Errorf("%w for %s", ErrNoRecordedRecipients, serviceDate.Format("2006-01-02"))
+	}
+	return entry, nil
+}
+
+// Build returns the correction email for the run, addressed to to. It
+// returns notification.ErrNothingToCorrect when the run already names
+// ministerName.
+func (s *CorrectionService) Build(entry history.Entry, to []notification.Recipient, ministerName string) (*notification.EmailRequest, error) {
+	if strings.TrimSpace(ministerName) == "" {
+		return nil, fmt.Errorf("minister is required")
+	}
+	if strings.EqualFold(strings.TrimSpace(ministerName), strings.TrimSpace(entry.Minister)) {
+		return nil, fmt.Errorf("%w: the email already named %s", notification.ErrNothingToCorrect, entry.Minister)
+	}
+
+	subject := entry.EmailSubject
+	if subject == "" {
+		subject = s.notifier.subject.Render(notification.SubjectVars{
+			Church:      s.notifier.churchName,
+			Date:        entry.ServiceDate.Format("01/02/2006"),
+			DateRange:   entry.ServiceDate.Format("01/02/2006"),
+			Minister:    entry.Minister,
+			ServiceType: notification.DefaultServiceType,
+			Title:       entry.Title,
+			Scripture:   entry.Scripture,
+		})
+	}
+	subject = notification.ReplySubject(strings.TrimPrefix(subject, SandboxSubjectPrefix))
+	if s.notifier.operator != nil {
+		to = []notification.Recipient{*s.notifier.operator}
+		subject = SandboxSubjectPrefix + subject
+	}
+
+	return &notification.EmailRequest{
+		To:               to,
+		ServiceDate:      entry.ServiceDate,
+		MinisterName:     ministerName,
+		PreviousMinister: entry.Minister,
+		Title:            entry.Title,
+		Scripture:        entry.Scripture,
+		AudioURL:         entry.AudioURL,
+		VideoURL:         entry.VideoURL,
+		ChurchName:       s.notifier.churchName,
+		SenderName:       s.notifier.senderName,
+		Subject:          subject,
+		Template:         &notification.CorrectionTemplate,
+		Greeting:         s.notifier.greeting,
+		InReplyTo:        entry.EmailMessageIDs,
+	}, nil
+}
+
+// Send sends the correction and records it on the run, updating its
+// minister. In sandbox mode the history is left as it was.
+func (s *CorrectionService) Send(ctx context.Context, entry history.Entry, to []notification.Recipient, ministerName string) (*notification.EmailRequest, error) {
+	email, err := s.Build(entry, to, ministerName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.notifier.send(ctx, email); err != nil {
+		return nil, err
+	}
+	if s.notifier.operator != nil {
+		return email, nil
+	}
+
+	correction := history.Correction{
+		Minister:         ministerName,
+		PreviousMinister: entry.Minister,
+		MessageID:        email.MessageID,
+		SentAt:           s.now(),
+	}
+	if err := s.store.AddCorrection(entry.ServiceDate, correction); err != nil {
+		return email, fmt.Errorf("correction sent but not recorded in history: %w", err)
+	}
+	return email, nil
+}
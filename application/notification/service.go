@@ -1,6 +1,9 @@
 package notification
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"nac-service-media/domain/notification"
@@ -11,15 +14,126 @@ type Service struct {
 	sender     notification.EmailSender
 	churchName string
 	senderName string
+	subject    *notification.SubjectTemplate
+	ccRules    notification.CCRuleSet
+	operator   *notification.Recipient // Set in sandbox mode
+	groups     notification.RecipientGroups
+	folderURL  string
+	livestream string
+	greeting   notification.GreetingRules
+	timeout    time.Duration  // Per email; zero waits as long as ctx allows
+	preview    *time.Location // Set when bodies are previewed in the terminal
+	private    bool
+	accessEnds time.Time
+}
+
+// SandboxSubjectPrefix marks the subject of emails sent in sandbox mode
+const SandboxSubjectPrefix = "[TEST] "
+
+// PrivateSubjectPrefix marks the subject of privately shared recordings
+const PrivateSubjectPrefix = "[Private] "
+
+// Option configures a notification service
+type Option func(*Service)
+
+// WithSubjectTemplate sets the template used to render email subjects
+func WithSubjectTemplate(t *notification.SubjectTemplate) Option {
+	return func(s *Service) {
+		if t != nil {
+			s.subject = t
+		}
+	}
+}
+
+// WithCCRules sets the rules that add CC recipients based on the service
+func WithCCRules(rules notification.CCRuleSet) Option {
+	return func(s *Service) {
+		s.ccRules = rules
+	}
+}
+
+// WithSandbox reroutes every email to the operator, dropping all other To and
+// CC recipients, and prefixes the subject with [TEST]
+func WithSandbox(operator notification.Recipient) Option {
+	return func(s *Service) {
+		s.operator = &operator
+	}
+}
+
+// WithRecipientGroups sends each group's members their own variant of the
+// email; recipients outside every group get the standard one
+func WithRecipientGroups(groups notification.RecipientGroups) Option {
+	return func(s *Service) {
+		s.groups = groups
+	}
+}
+
+// WithFolderLink adds a footer linking to the Drive folder of all services,
+// so recipients can browse earlier recordings
+func WithFolderLink(url string) Option {
+	return func(s *Service) {
+		s.folderURL = url
+	}
+}
+
+// WithLivestreamLink adds a line linking to the livestream recording or the
+// church's livestream channel
+func WithLivestreamLink(url string) Option {
+	return func(s *Service) {
+		s.livestream = url
+	}
+}
+
+// WithGreeting sets how the email greets its recipients
+func WithGreeting(rules notification.GreetingRules) Option {
+	return func(s *Service) {
+		s.greeting = rules
+	}
+}
+
+// WithSendTimeout bounds how long each email may take to send, so an
+// unresponsive Gmail API fails the send instead of hanging the run
+func WithSendTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.timeout = d
+	}
+}
+
+// WithTerminalPreview shows each email's body as terminal text before it is
+// sent, with "today's" or "yesterday's" worked out in loc like the sender does
+func WithTerminalPreview(loc *time.Location) Option {
+	return func(s *Service) {
+		if loc == nil {
+			loc = time.Local
+		}
+		s.preview = loc
+	}
+}
+
+// WithPrivate sends the private template, labelled [Private], without the
+// mirror, livestream and previous services links. A non-zero accessEnds
+// tells recipients when the links stop working.
+func WithPrivate(accessEnds time.Time) Option {
+	return func(s *Service) {
+		s.private = true
+		s.accessEnds = accessEnds
+	}
 }
 
 // NewService creates a new notification service
-func NewService(sender notification.EmailSender, churchName, senderName string) *Service {
-	return &Service{
+func NewService(sender notification.EmailSender, churchName, senderName string, opts ...Option) *Service {
+	// The default template always parses
+	subject, _ := notification.ParseSubjectTemplate(notification.DefaultSubjectTemplate)
+	s := &Service{
 		sender:     sender,
 		churchName: churchName,
 		senderName: senderName,
+		subject:    subject,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // SendRequest contains the parameters for sending a recording notification
@@ -30,20 +144,222 @@ type SendRequest struct {
 	MinisterName string
 	AudioURL     string
 	VideoURL     string
+	ServiceType  string // {service_type} in the subject; defaults to "Service"
+	Label        string // {label} in the subject, e.g. "Confirmation"
+	Title        string // Sermon title, in the body and {title} in the subject
+	Scripture    string // Scripture reading, in the body and {scripture} in the subject
+
+	// ServiceEndDate is the date a service that ran past midnight ended on
+	ServiceEndDate time.Time
+
+	MirrorAudioURL string // Optional alternate download links
+	MirrorVideoURL string
+
+	// AudioVersions are extra copies of the audio at other bitrates
+	AudioVersions []notification.AudioVersion
+
+	// Context is a paragraph shown after the links; a recipient group's
+	// context replaces it
+	Context string
+}
+
+// Send sends a notification email for a service recording, one per
+// recipient group when groups are configured
+func (s *Service) Send(ctx context.Context, req SendRequest) error {
+	_, err := s.SendGroups(ctx, req)
+	return err
 }
 
-// Send sends a notification email for a service recording
-func (s *Service) Send(req SendRequest) error {
-	emailReq := &notification.EmailRequest{
-		To:           req.To,
-		CC:           req.CC,
-		ServiceDate:  req.ServiceDate,
-		MinisterName: req.MinisterName,
-		AudioURL:     req.AudioURL,
-		VideoURL:     req.VideoURL,
-		ChurchName:   s.churchName,
-		SenderName:   s.senderName,
+// GroupResult reports the email sent to one recipient group
+type GroupResult struct {
+	Group     string
+	To        []notification.Recipient
+	MessageID string // Set by senders that record the Message-ID they sent with
+	Err       error
+}
+
+// SendGroups sends each group's email, continuing past failures, and reports
+// every group. With a single email its error is returned as is; otherwise the
+// error lists the groups that failed. Once ctx is cancelled, the remaining
+// groups fail without being sent.
+func (s *Service) SendGroups(ctx context.Context, req SendRequest) ([]GroupResult, error) {
+	emails := s.BuildGroupedRequests(req)
+	results := make([]GroupResult, len(emails))
+	var errs []error
+	for i, e := range emails {
+		err := s.send(ctx, e.Request)
+		results[i] = GroupResult{Group: e.Group, To: e.To, MessageID: e.Request.MessageID, Err: err}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Group, err))
+		}
+	}
+
+	switch {
+	case len(errs) == 0:
+		return results, nil
+	case len(emails) == 1:
+		return results, results[0].Err
+	default:
+		return results, fmt.Errorf("failed to send to %d of %d groups: %w", len(errs), len(emails), errors.Join(errs...))
+	}
+}
+
+// send sends one email within the configured timeout
+func (s *Service) send(ctx context.Context, req *notification.EmailRequest) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not sent: %w", err)
+	}
+	if s.timeout <= 0 {
+		return s.sender.Send(ctx, req)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	err := s.sender.Send(ctx, req)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", s.timeout, err)
+	}
+	return err
+}
+
+// GroupedEmail is the email one recipient group gets
+type GroupedEmail struct {
+	Group   string                   // Group name, or notification.DefaultGroup
+	To      []notification.Recipient // Intended recipients, before sandbox rerouting
+	Request *notification.EmailRequest
+}
+
+// BuildGroupedRequests returns the emails Send would send for req: one per
+// recipient group with members in req.To, with the group's context and
+// template. CC recipients are copied on the first email only.
+func (s *Service) BuildGroupedRequests(req SendRequest) []GroupedEmail {
+	base := s.BuildRequest(req)
+	batches := s.groups.Split(req.To)
+	if len(batches) == 0 || (len(batches) == 1 && batches[0].Group == nil) {
+		return []GroupedEmail{{Group: notification.DefaultGroup, To: req.To, Request: base}}
+	}
+
+	emails := make([]GroupedEmail, len(batches))
+	for i, b := range batches {
+		email := *base
+		if s.operator == nil {
+			email.To = b.To
+		}
+		if i > 0 {
+			email.CC = nil
+		}
+		if b.Group != nil {
+			if b.Group.Context != "" {
+				email.Context = b.Group.Context
+			}
+			if !s.private {
+				email.Template = b.Group.Template
+			}
+		}
+		emails[i] = GroupedEmail{Group: b.GroupName(), To: b.To, Request: &email}
+	}
+	return emails
+}
+
+// BuildRequest returns the email that Send would send for req, after CC rules
+// and sandbox rerouting
+func (s *Service) BuildRequest(req SendRequest) *notification.EmailRequest {
+	to, cc := s.Route(req)
+	email := &notification.EmailRequest{
+		To:             to,
+		CC:             cc,
+		ServiceDate:    req.ServiceDate,
+		ServiceEndDate: req.ServiceEndDate,
+		MinisterName:   req.MinisterName,
+		Title:          req.Title,
+		Scripture:      req.Scripture,
+		AudioURL:       req.AudioURL,
+		VideoURL:       req.VideoURL,
+		ChurchName:     s.churchName,
+		SenderName:     s.senderName,
+		Subject:        s.Subject(req),
+		Context:        req.Context,
+		Greeting:       s.greeting,
+
+		AudioVersions:  req.AudioVersions,
+		MirrorAudioURL: req.MirrorAudioURL,
+		MirrorVideoURL: req.MirrorVideoURL,
+		FolderURL:      s.folderURL,
+		LivestreamURL:  s.livestream,
+	}
+	if s.private {
+		email.Template = &notification.PrivateTemplate
+		email.AccessEnds = s.accessEnds
+		email.MirrorAudioURL, email.MirrorVideoURL = "", ""
+		email.FolderURL, email.LivestreamURL = "", ""
+	}
+	return email
+}
+
+// Sandbox returns the operator every email is rerouted to, if sandbox mode is on
+func (s *Service) Sandbox() (notification.Recipient, bool) {
+	if s.operator == nil {
+		return notification.Recipient{}, false
+	}
+	return *s.operator, true
+}
+
+// Route returns the addresses the email for req is actually sent to: the
+// request's To and resolved CC, or only the operator in sandbox mode
+func (s *Service) Route(req SendRequest) (to, cc []notification.Recipient) {
+	if s.operator != nil {
+		return []notification.Recipient{*s.operator}, nil
+	}
+	cc, _ = s.ResolveCC(req)
+	return req.To, cc
+}
+
+// ResolveCC returns the request's CC list extended by any CC rules that fire,
+// along with the rules that fired
+func (s *Service) ResolveCC(req SendRequest) ([]notification.Recipient, []notification.FiredRule) {
+	return s.ccRules.Evaluate(req.CC, notification.CCContext{
+		Minister:    req.MinisterName,
+		ServiceType: serviceTypeOrDefault(req.ServiceType),
+	})
+}
+
+// Subject renders the subject line for a request
+func (s *Service) Subject(req SendRequest) string {
+	subject := s.subject.Render(notification.SubjectVars{
+		Church:      s.churchName,
+		Date:        req.ServiceDate.Format("01/02/2006"),
+		DateRange:   notification.FormatDateRange(req.ServiceDate, req.ServiceEndDate),
+		Minister:    req.MinisterName,
+		ServiceType: serviceTypeOrDefault(req.ServiceType),
+		Label:       req.Label,
+		Title:       req.Title,
+		Scripture:   req.Scripture,
+	})
+	if s.private {
+		subject = PrivateSubjectPrefix + subject
+	}
+	if s.operator != nil {
+		subject = SandboxSubjectPrefix + subject
+	}
+	return subject
+}
+
+func serviceTypeOrDefault(serviceType string) string {
+	if serviceType == "" {
+		return notification.DefaultServiceType
 	}
+	return serviceType
+}
 
-	return s.sender.Send(emailReq)
+// Preview renders the email's body as terminal text wrapped at
+// notification.TerminalWidth. It reports false when previews are off.
+func (s *Service) Preview(email *notification.EmailRequest) (string, bool, error) {
+	if s.preview == nil {
+		return "", false, nil
+	}
+	body, err := notification.RenderHTMLBody(email, time.Now().In(s.preview))
+	if err != nil {
+		return "", true, fmt.Errorf("failed to render the email: %w", err)
+	}
+	return notification.RenderTerminal(body, notification.TerminalWidth), true, nil
 }
@@ -0,0 +1,145 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/notification"
+	"nac-service-media/infrastructure/config"
+)
+
+// WithShareExpiry ends access to, and deletes, privately shared files whose
+// time is up after each successful run
+func WithShareExpiry() Option {
+	return func(s *Service) {
+		s.expireShares = true
+	}
+}
+
+// applyPolicy looks up the service type's policy and fills in what it
+// decides for the input: an audio-only run when the video is optional and
+// not asked for, and the email's {service_type}
+func (s *Service) applyPolicy(input Input) (Input, error) {
+	p, err := s.cfg.ServicePolicy(input.Type)
+	if err != nil {
+		return input, &ValidationError{
+			Code:    CodeUnknownType,
+			Message: err.Error(),
+		}
+	}
+	s.policy, s.videoByPolicy = p, false
+	if p.IsRegular() {
+		return input, nil
+	}
+
+	fmt.Fprintf(s.output, "Type: %s\n", p.Type)
+	if p.VideoOptional && !input.WithVideo {
+		input.SkipVideo, s.videoByPolicy = true, true
+	}
+	if input.ServiceType == "" {
+		input.ServiceType = strings.ToUpper(p.Type[:1]) + p.Type[1:]
+	}
+	return input, nil
+}
+
+// sharePrivately sets up a private service's uploads to be shared with its
+// recipients only, tagged with when their access ends and the files go.
+// In sandbox mode only the operator is given access.
+func (s *Service) sharePrivately(input Input, recipients, cc []notification.Recipient) error {
+	s.readers, s.expiry = nil, distribution.Expiry{}
+	if s.policy.PublicSharing {
+		return nil
+	}
+	people := append(append([]notification.Recipient(nil), recipients...), cc...)
+	if s.sandboxed(input) {
+		operator, err := config.NewRecipientLookup(s.cfg, "").Operator()
+		if err != nil {
+			return err
+		}
+		people = []notification.Recipient{operator}
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range people {
+		if key := strings.ToLower(r.Address); !seen[key] {
+			seen[key] = true
+			s.readers = append(s.readers, r.Address)
+		}
+	}
+	now := time.Now()
+	s.expiry = distribution.Expiry{
+		RevokeAfter: s.policy.RevokeAt(now),
+		DeleteAfter: s.policy.DeleteAt(now),
+	}
+
+	fmt.Fprintf(s.output, "Sharing: only with %d %s", len(s.readers), plural(len(s.readers), "person", "people"))
+	if !s.expiry.RevokeAfter.IsZero() {
+		fmt.Fprintf(s.output, ", until %s", s.expiry.RevokeAfter.Format("2006-01-02"))
+	}
+	if !s.expiry.DeleteAfter.IsZero() {
+		fmt.Fprintf(s.output, "; deleted from Drive %s", s.expiry.DeleteAfter.Format("2006-01-02"))
+	}
+	fmt.Fprintln(s.output)
+	return nil
+}
+
+// privateShareOptions shares uploads with the readers set by sharePrivately
+func (s *Service) privateShareOptions() []appdist.ShareOption {
+	if len(s.readers) == 0 {
+		return nil
+	}
+	return []appdist.ShareOption{appdist.WithReaders(s.readers...), appdist.WithExpiry(s.expiry)}
+}
+
+// sharedPrivately reports whether this run's files are shared with named
+// people only
+func (s *Service) sharedPrivately() bool {
+	return !s.policy.PublicSharing
+}
+
+// expireSharedFiles ends access to, and deletes, privately shared files whose
+// time is up. The run already succeeded, so failures are only warnings.
+func (s *Service) expireSharedFiles(ctx context.Context) {
+	if !s.expireShares {
+		return
+	}
+	result, err := appdist.NewExpiryService(s.driveClient, s.cfg.Google.ServicesFolderID).Expire(ctx)
+	if err != nil {
+		fmt.Fprintf(s.output, "Warning: could not check for expired shares: %v\n", err)
+		if result == nil {
+			return
+		}
+	}
+	for _, r := range result.Revoked {
+		fmt.Fprintf(s.output, "Ended access to %s for %s\n", r.File.Name, strings.Join(r.Readers, ", "))
+	}
+	for _, f := range result.Deleted {
+		fmt.Fprintf(s.output, "Deleted %s from Drive (retention ended)\n", f.Name)
+	}
+	for _, err := range result.Failed {
+		fmt.Fprintf(s.output, "Warning: %v\n", err)
+	}
+}
+
+// showPrivateRecovery explains that a private service can only be finished
+// by process, since upload and send-email share publicly. It reports whether
+// it did.
+func (s *Service) showPrivateRecovery() bool {
+	if !s.sharedPrivately() {
+		return false
+	}
+	fmt.Fprintf(s.output, "A %s is shared privately, so finish it with process rather than upload and\n", s.policy.Type)
+	fmt.Fprintf(s.output, "send-email, which share files with anyone who has the link.\n\n")
+	return true
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
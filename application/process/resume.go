@@ -0,0 +1,180 @@
+package process
+
+import (
+	"fmt"
+	"time"
+
+	appvideo "nac-service-media/application/video"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/notification"
+	"nac-service-media/domain/runstate"
+)
+
+// WithRunState saves the run's progress after each step, so a failed run
+// can be resumed with Input.Resume. The state is cleared once the email is sent.
+func WithRunState(store runstate.Store) Option {
+	return func(s *Service) {
+		s.runState = store
+	}
+}
+
+// ResumeInput returns the input a saved run was started with. Options that
+// only affect how the run is supervised, such as NonInteractive, are left
+// for the caller to set.
+func ResumeInput(state *runstate.State) Input {
+	p := state.Params
+	return Input{
+		InputPath:     p.InputPath,
+		StartTime:     p.StartTime,
+		EndTime:       p.EndTime,
+		DateOverride:  p.DateOverride,
+		MinisterKey:   p.MinisterKey,
+		RecipientKeys: p.RecipientKeys,
+		ExcludeKeys:   p.ExcludeKeys,
+		CCKeys:        p.CCKeys,
+		SenderKey:     p.SenderKey,
+		ServiceType:   p.ServiceType,
+		Type:          p.Type,
+		Label:         p.Label,
+		Title:         p.Title,
+		Scripture:     p.Scripture,
+		Notes:         p.Notes,
+		SkipVideo:     p.SkipVideo,
+		WithVideo:     p.WithVideo,
+		AudioTrack:    p.AudioTrack,
+		Sandbox:       p.Sandbox,
+		Chapters:      p.Chapters,
+		Target:        distribution.Target(p.Target),
+		Resume:        true,
+	}
+}
+
+// runParams are the options saved with the run's state
+func runParams(input Input, sourcePath string) runstate.Params {
+	return runstate.Params{
+		InputPath:     sourcePath,
+		StartTime:     input.StartTime,
+		EndTime:       input.EndTime,
+		DateOverride:  input.DateOverride,
+		MinisterKey:   input.MinisterKey,
+		RecipientKeys: input.RecipientKeys,
+		ExcludeKeys:   input.ExcludeKeys,
+		CCKeys:        input.CCKeys,
+		SenderKey:     input.SenderKey,
+		ServiceType:   input.ServiceType,
+		Type:          input.Type,
+		Label:         input.Label,
+		Title:         input.Title,
+		Scripture:     input.Scripture,
+		Notes:         input.Notes,
+		SkipVideo:     input.SkipVideo,
+		WithVideo:     input.WithVideo,
+		AudioTrack:    input.AudioTrack,
+		Sandbox:       input.Sandbox,
+		Chapters:      input.Chapters,
+		Target:        string(input.Target),
+	}
+}
+
+// startRunState loads the saved run when resuming, or starts a new one that
+// replaces any saved run for the date. Without a store the new state is
+// only kept in memory.
+func (s *Service) startRunState(input Input, sourcePath string, serviceDate time.Time) (*runstate.State, error) {
+	if !input.Resume {
+		state := runstate.New(serviceDate, runParams(input, sourcePath))
+		s.saveRunState(state, "Start")
+		return state, nil
+	}
+	if s.runState == nil {
+		return nil, fmt.Errorf("cannot resume: no run state is kept")
+	}
+
+	state, err := s.runState.Load(serviceDate)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resume: %w; run process without --resume", err)
+	}
+	if state.Params.SkipVideo != input.SkipVideo {
+		return nil, fmt.Errorf("cannot resume: the saved run for %s was %s; run process without --resume to start over",
+			serviceDate.Format("2006-01-02"), workflowName(state.Params.SkipVideo))
+	}
+	fmt.Fprintf(s.output, "Resuming the run saved at %s (%s)\n", state.UpdatedAt.Format("2006-01-02 15:04"), state.Summary())
+	return state, nil
+}
+
+func workflowName(audioOnly bool) string {
+	if audioOnly {
+		return "audio-only"
+	}
+	return "video and audio"
+}
+
+// saveRunState records a completed step. Progress is a convenience, so a
+// failure to save it is only a warning.
+func (s *Service) saveRunState(state *runstate.State, step string) {
+	if s.runState == nil {
+		return
+	}
+	state.Complete(step, time.Now())
+	if err := s.runState.Save(state); err != nil {
+		fmt.Fprintf(s.output, "      Warning: could not save progress for --resume: %v\n", err)
+	}
+}
+
+// finishRunState removes the saved run once it has succeeded
+func (s *Service) finishRunState(state *runstate.State) {
+	if s.runState == nil {
+		return
+	}
+	if err := s.runState.Clear(state.ServiceDate); err != nil {
+		fmt.Fprintf(s.output, "Warning: could not clear saved progress: %v\n", err)
+	}
+}
+
+// showResumeCommand tells the operator how to pick the failed run up again
+func (s *Service) showResumeCommand(serviceDate time.Time) {
+	if s.runState == nil {
+		return
+	}
+	fmt.Fprintf(s.output, "To pick up from the last completed step:\n  nac-service-media process --resume --date %s\n\n", serviceDate.Format("2006-01-02"))
+}
+
+// resumedFile returns a file an earlier run made, if it is still there
+func (s *Service) resumedFile(path string) (string, bool) {
+	if path == "" || s.fileSizer.Size(path) <= 0 {
+		return "", false
+	}
+	return path, true
+}
+
+// resumedVariants returns the saved extra MP3s
+func resumedVariants(state *runstate.State) []appvideo.AudioVariant {
+	variants := make([]appvideo.AudioVariant, len(state.Variants))
+	for i, v := range state.Variants {
+		variants[i] = appvideo.AudioVariant{Bitrate: v.Bitrate, OutputPath: v.Path, Reused: true}
+	}
+	return variants
+}
+
+func savedVariants(variants []appvideo.AudioVariant) []runstate.Variant {
+	saved := make([]runstate.Variant, len(variants))
+	for i, v := range variants {
+		saved[i] = runstate.Variant{Bitrate: v.Bitrate, Path: v.OutputPath}
+	}
+	return saved
+}
+
+func resumedVersions(state *runstate.State) []notification.AudioVersion {
+	var versions []notification.AudioVersion
+	for _, v := range state.AudioVersions {
+		versions = append(versions, notification.AudioVersion{Label: v.Label, URL: v.URL})
+	}
+	return versions
+}
+
+func savedVersions(versions []notification.AudioVersion) []runstate.AudioVersion {
+	var saved []runstate.AudioVersion
+	for _, v := range versions {
+		saved = append(saved, runstate.AudioVersion{Label: v.Label, URL: v.URL})
+	}
+	return saved
+}
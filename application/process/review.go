@@ -0,0 +1,104 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+
+	"nac-service-media/domain/notification"
+	"nac-service-media/infrastructure/config"
+)
+
+// RecipientEdit is one change to the email recipients made at the email step.
+// The zero value means the list is right and the email can go.
+type RecipientEdit struct {
+	Add    string // Recipient key, name or group to add
+	CC     bool   // Add to CC instead of To
+	Remove string // Address to take off To or CC
+}
+
+// Done reports whether the operator has finished editing
+func (e RecipientEdit) Done() bool {
+	return e.Add == "" && e.Remove == ""
+}
+
+// RecipientReviewFunc shows the resolved To and CC lists and returns the next change
+type RecipientReviewFunc func(to, cc []notification.Recipient) (RecipientEdit, error)
+
+// WithRecipientReview lets the operator add or remove recipients just before
+// the email is sent, for last-minute requests
+func WithRecipientReview(review RecipientReviewFunc) Option {
+	return func(s *Service) {
+		s.reviewRecipients = review
+	}
+}
+
+// reviewRecipientList applies the operator's edits until they are done.
+// Additions go through the recipient lookup; one that doesn't resolve is
+// reported and the review carries on.
+func (s *Service) reviewRecipientList(to, cc []notification.Recipient) ([]notification.Recipient, []notification.Recipient, error) {
+	lookup := config.NewRecipientLookup(s.cfg, "")
+	for {
+		fmt.Fprintf(s.output, "      To: %s\n", strings.Join(formatRecipients(to), ", "))
+		if len(cc) > 0 {
+			fmt.Fprintf(s.output, "      CC: %s\n", strings.Join(formatRecipients(cc), ", "))
+		}
+		edit, err := s.reviewRecipients(to, cc)
+		if err != nil {
+			return nil, nil, err
+		}
+		if edit.Done() {
+			return to, cc, nil
+		}
+
+		if edit.Add != "" {
+			found, err := lookup.LookupRecipients([]string{edit.Add})
+			if err != nil {
+				fmt.Fprintf(s.output, "      Not added: %v\n", err)
+				continue
+			}
+			for _, rc := range found {
+				if hasRecipient(to, rc.Address) || hasRecipient(cc, rc.Address) {
+					continue
+				}
+				if edit.CC {
+					cc = append(cc, rc)
+				} else {
+					to = append(to, rc)
+				}
+				fmt.Fprintf(s.output, "      Added: %s\n", rc)
+			}
+		}
+
+		if edit.Remove != "" {
+			remaining := withoutRecipient(to, edit.Remove)
+			if len(remaining) == 0 {
+				fmt.Fprintf(s.output, "      Not removed: %s is the only recipient\n", edit.Remove)
+				continue
+			}
+			to = remaining
+			cc = withoutRecipient(cc, edit.Remove)
+			fmt.Fprintf(s.output, "      Removed: %s\n", edit.Remove)
+		}
+	}
+}
+
+// hasRecipient reports whether the list already includes the address
+func hasRecipient(list []notification.Recipient, address string) bool {
+	for _, rc := range list {
+		if strings.EqualFold(rc.Address, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutRecipient returns the list without the address
+func withoutRecipient(list []notification.Recipient, address string) []notification.Recipient {
+	var kept []notification.Recipient
+	for _, rc := range list {
+		if !strings.EqualFold(rc.Address, address) {
+			kept = append(kept, rc)
+		}
+	}
+	return kept
+}
@@ -2,10 +2,11 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
-	"regexp"
+	"strings"
 	"time"
 
 	"sort"
@@ -13,9 +14,15 @@ import (
 	appdist "nac-service-media/application/distribution"
 	appnotif "nac-service-media/application/notification"
 	appvideo "nac-service-media/application/video"
+	"nac-service-media/domain/audit"
 	"nac-service-media/domain/distribution"
 	domainfs "nac-service-media/domain/filesystem"
+	"nac-service-media/domain/history"
 	"nac-service-media/domain/notification"
+	"nac-service-media/domain/policy"
+	"nac-service-media/domain/resource"
+	"nac-service-media/domain/runstate"
+	"nac-service-media/domain/summary"
 	"nac-service-media/domain/video"
 	"nac-service-media/infrastructure/config"
 )
@@ -44,6 +51,188 @@ type Service struct {
 	output      io.Writer
 	diskChecker domainfs.DiskChecker
 	fileRemover domainfs.FileRemover
+	publisher   distribution.Publisher
+	history     history.Store
+	summaries   summary.Archive
+	prober      video.DurationProber
+	geometry    video.GeometryProber
+	calendar    video.ServiceCalendar
+	failAtStep  int
+	scanner     distribution.Scanner
+	folderID    string // Drive folder uploads go to
+	archiver    domainfs.Archiver
+	retention   domainfs.ArchivePolicy
+	confirmStep StepConfirmFunc
+	modTimes    domainfs.ModTimer
+	fs          domainfs.FS // Nil uses the real file system
+
+	reviewRecipients RecipientReviewFunc
+	confirmBudget    StepConfirmFunc
+	confirmDelete    StepConfirmFunc
+	allowDelete      bool
+	auditLog         audit.Recorder
+	runState         runstate.Store
+	videoHost        distribution.VideoHost
+	usageSampler     resource.Sampler
+	expireShares     bool
+
+	// Set by Process from the service type's policy
+	policy        policy.Policy
+	videoByPolicy bool                // The policy made the run audio-only
+	readers       []string            // People a private service is shared with
+	expiry        distribution.Expiry // When their access ends and the files go
+
+	deleted []distribution.DeletedFile // Old videos this run removed from Drive
+}
+
+// Option is a functional option for configuring Service
+type Option func(*Service)
+
+// WithPublisher mirrors outputs to an alternate download server after the
+// Drive upload and adds the mirror links to the email
+func WithPublisher(p distribution.Publisher) Option {
+	return func(s *Service) {
+		s.publisher = p
+	}
+}
+
+// WithDurationProber reads the source length, for timestamps given relative
+// to the end of the file
+func WithDurationProber(p video.DurationProber) Option {
+	return func(s *Service) {
+		s.prober = p
+	}
+}
+
+// WithGeometryProber checks the source's frame size and aspect against the
+// video config before anything is trimmed or uploaded
+func WithGeometryProber(p video.GeometryProber) Option {
+	return func(s *Service) {
+		s.geometry = p
+	}
+}
+
+// WithSummaryArchive writes a Markdown/HTML summary of each completed run
+func WithSummaryArchive(a summary.Archive) Option {
+	return func(s *Service) {
+		s.summaries = a
+	}
+}
+
+// WithCalendar sets the timezones used to infer service dates from recording
+// names (default: the system timezone)
+func WithCalendar(c video.ServiceCalendar) Option {
+	return func(s *Service) {
+		s.calendar = c
+	}
+}
+
+// WithHistory records each completed run in the history store
+func WithHistory(store history.Store) Option {
+	return func(s *Service) {
+		s.history = store
+	}
+}
+
+// WithSimulatedFailure makes the given workflow step (1-based, as numbered in
+// the output) fail before it runs, to exercise recovery output and resuming
+// without breaking a real service. Steps that cannot fail, such as sharing,
+// ignore it. Only development builds expose this.
+func WithSimulatedFailure(step int) Option {
+	return func(s *Service) {
+		s.failAtStep = step
+	}
+}
+
+// WithShareScanner scans the trimmed video and MP3 before their uploads are
+// shared publicly; a file that fails is uploaded but left unshared
+func WithShareScanner(scanner distribution.Scanner) Option {
+	return func(s *Service) {
+		s.scanner = scanner
+	}
+}
+
+// WithFolderID uploads this run to a folder other than
+// google.services_folder_id, such as one for a convention. Storage cleanup
+// still makes room in the services folder.
+func WithFolderID(id string) Option {
+	return func(s *Service) {
+		if id != "" {
+			s.folderID = id
+		}
+	}
+}
+
+// WithLocalArchive keeps a compressed copy of each old file local cleanup
+// deletes, for the kinds the policy includes, and prunes the archive after
+func WithLocalArchive(a domainfs.Archiver, policy domainfs.ArchivePolicy) Option {
+	return func(s *Service) {
+		s.archiver = a
+		s.retention = policy
+	}
+}
+
+// WithModTimes checks the date in a recording's name against when the file was
+// written, to catch a recording PC with the wrong clock
+func WithModTimes(m domainfs.ModTimer) Option {
+	return func(s *Service) {
+		s.modTimes = m
+	}
+}
+
+// WithFS sets the file system uploads and publishing read local files from
+func WithFS(fsys domainfs.FS) Option {
+	return func(s *Service) {
+		s.fs = fsys
+	}
+}
+
+// StepConfirmFunc asks whether to go ahead with an irreversible action, such
+// as deleting files or sending the email
+type StepConfirmFunc func(action string) (bool, error)
+
+// ErrStepDeclined is returned when the operator stops the run at a checkpoint
+var ErrStepDeclined = errors.New("stopped at a checkpoint")
+
+// WithStepConfirmation pauses before each irreversible action: deleting old
+// videos from Drive, deleting local files, and sending the email
+func WithStepConfirmation(confirm StepConfirmFunc) Option {
+	return func(s *Service) {
+		s.confirmStep = confirm
+	}
+}
+
+// WithBudgetConfirmation asks before a run goes over the weekly upload budget
+// when upload_budget.on_exceed is confirm. Without it such a run stops.
+func WithBudgetConfirmation(confirm StepConfirmFunc) Option {
+	return func(s *Service) {
+		s.confirmBudget = confirm
+	}
+}
+
+// WithDeleteConfirmation lists the old videos the storage step would delete
+// from Drive and asks before deleting them. With neither this nor
+// WithDeleteAllowed, a run that needs room stops instead.
+func WithDeleteConfirmation(confirm StepConfirmFunc) Option {
+	return func(s *Service) {
+		s.confirmDelete = confirm
+	}
+}
+
+// WithDeleteAllowed lets the storage step delete old videos from Drive
+// without asking, for unattended runs given --allow-delete
+func WithDeleteAllowed(allowed bool) Option {
+	return func(s *Service) {
+		s.allowDelete = allowed
+	}
+}
+
+// WithAuditLog records whether deleting old videos to make room was
+// approved, declined or refused
+func WithAuditLog(recorder audit.Recorder) Option {
+	return func(s *Service) {
+		s.auditLog = recorder
+	}
 }
 
 // NewService creates a new process service
@@ -59,8 +248,9 @@ func NewService(
 	output io.Writer,
 	diskChecker domainfs.DiskChecker,
 	fileRemover domainfs.FileRemover,
+	opts ...Option,
 ) *Service {
-	return &Service{
+	s := &Service{
 		trimmer:     trimmer,
 		extractor:   extractor,
 		fileChecker: fileChecker,
@@ -72,20 +262,62 @@ func NewService(
 		output:      output,
 		diskChecker: diskChecker,
 		fileRemover: fileRemover,
+		folderID:    cfg.Google.ServicesFolderID,
+		policy:      policy.Regular(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Input contains all input parameters for the process command
 type Input struct {
-	InputPath     string   // Source video path (optional if using newest)
-	StartTime     string   // Start timestamp HH:MM:SS
-	EndTime       string   // End timestamp HH:MM:SS
-	MinisterKey   string   // Minister config key
-	RecipientKeys []string // Recipient config keys
-	CCKeys        []string // CC config keys (optional)
-	DateOverride  string   // Override service date (YYYY-MM-DD)
-	SenderKey     string   // Sender config key (optional, uses default if empty)
-	SkipVideo     bool     // Skip video trimming and upload; extract audio from source
+	InputPath      string          // Source video path (optional if using newest)
+	StartTime      string          // Start timestamp HH:MM:SS
+	EndTime        string          // End timestamp HH:MM:SS
+	MinisterKey    string          // Minister config key
+	RecipientKeys  []string        // Recipient config keys or email.groups names
+	ExcludeKeys    []string        // People to leave out of RecipientKeys, e.g. one choir member
+	CCKeys         []string        // CC keys, names, groups or addresses (optional)
+	DateOverride   string          // Override service date (YYYY-MM-DD)
+	SenderKey      string          // Sender config key (optional, uses default if empty)
+	SkipVideo      bool            // Skip video trimming and upload; extract audio from source
+	AudioTrack     int             // 1-based audio stream to keep (optional, defaults to audio.track)
+	Chapters       []video.Chapter // Chapter marks for the trimmed video, e.g. from a cue file
+	ServiceType    string          // Email subject {service_type} (optional, defaults to email.service_type)
+	Type           string          // Service type whose policy applies, e.g. "funeral" (optional, defaults to regular)
+	WithVideo      bool            // Include the video when the type's policy makes it optional
+	Label          string          // Email subject {label} (optional)
+	Title          string          // Sermon title tagged on the files, in the email and history (optional)
+	Scripture      string          // Scripture reading, alongside Title (optional)
+	Notes          []string        // Operator notes recorded in history, e.g. A/V issues
+	Sandbox        bool            // Send the email only to the operator (also email.sandbox)
+	StreamAudio    bool            // Pipe audio-only extraction into the Drive upload (also audio.stream_upload)
+	NonInteractive bool            // Fail instead of guessing, e.g. when a CC key matches several recipients
+	StrictGeometry bool            // Stop when the source's size or aspect looks wrong (also video.strict)
+	Resume         bool            // Skip the steps the saved run for the date finished (see WithRunState)
+
+	// Target is where the video is uploaded: Drive (the default), the video
+	// host (see WithVideoHost) or both. The email links the hosted video
+	// when there is one.
+	Target distribution.Target
+
+	// Overwrite controls what happens when a trimmed video or audio file already exists
+	Overwrite appvideo.OverwriteOptions
+
+	// DetectionConfidence is the start detection's match score, zero when the
+	// start was given by hand; recorded in history with CameraAngle
+	DetectionConfidence float64
+	CameraAngle         string
+	// DetectionEarlyExit is set when detection stopped on a confident frame bracket
+	DetectionEarlyExit bool
+
+	// FFmpegVersion is the ffmpeg the run used, recorded in history and the
+	// run summary
+	FFmpegVersion string
+
+	serviceEndDate time.Time // Set by Process when the service runs past midnight
 }
 
 // Result contains the results of a successful process run
@@ -95,6 +327,8 @@ type Result struct {
 	VideoURL    string
 	AudioURL    string
 	ServiceDate time.Time
+
+	SharingPending bool // Uploaded files still need `drive share`
 }
 
 // CleanupInput captures pre-processing state needed for local file cleanup
@@ -105,10 +339,58 @@ type CleanupInput struct {
 	SkipVideo        bool
 }
 
+// Validation error codes. They are stable so wrapper scripts can branch on
+// them; each has its own exit code.
+const (
+	CodeMinisterNotFound   = "MINISTER_NOT_FOUND"
+	CodeRecipientNotFound  = "RECIPIENT_NOT_FOUND"
+	CodeCCNotFound         = "CC_NOT_FOUND"
+	CodeSenderNotFound     = "SENDER_NOT_FOUND"
+	CodeSenderAmbiguous    = "SENDER_AMBIGUOUS"
+	CodeNoDefaultSender    = "NO_DEFAULT_SENDER"
+	CodeExclusionUnmatched = "EXCLUSION_UNMATCHED"
+	CodeNoRecipients       = "NO_RECIPIENTS"
+	CodeClockDrift         = "CLOCK_DRIFT"
+	CodeAlreadyProcessed   = "ALREADY_PROCESSED"
+	CodeOverBudget         = "OVER_UPLOAD_BUDGET"
+	CodeDeleteNotAllowed   = "DELETE_NOT_ALLOWED"
+	CodeUnknownType        = "UNKNOWN_TYPE"
+	CodeDriveFolder        = "DRIVE_FOLDER_INVALID"
+)
+
+// ExitCodeValidation is the exit code for a ValidationError without a known code
+const ExitCodeValidation = 2
+
+var validationExitCodes = map[string]int{
+	CodeMinisterNotFound:   10,
+	CodeRecipientNotFound:  11,
+	CodeCCNotFound:         12,
+	CodeSenderNotFound:     13,
+	CodeSenderAmbiguous:    14,
+	CodeNoDefaultSender:    15,
+	CodeExclusionUnmatched: 16,
+	CodeNoRecipients:       17,
+	CodeClockDrift:         18,
+	CodeOverBudget:         19,
+	CodeAlreadyProcessed:   20,
+	CodeDeleteNotAllowed:   21,
+	CodeUnknownType:        22,
+	CodeDriveFolder:        23,
+}
+
 // ValidationError contains details about a validation failure with suggestions
 type ValidationError struct {
-	Message    string
-	Suggestion string
+	Code       string `json:"code"` // One of the Code constants
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// ExitCode returns the process exit code for the error's code
+func (e *ValidationError) ExitCode() int {
+	if code, ok := validationExitCodes[e.Code]; ok {
+		return code
+	}
+	return ExitCodeValidation
 }
 
 func (e *ValidationError) Error() string {
@@ -121,21 +403,60 @@ func (e *ValidationError) Error() string {
 // Process runs the complete end-to-end workflow
 func (s *Service) Process(ctx context.Context, input Input) (*Result, error) {
 	startTime := time.Now()
+	s.deleted = nil
 
 	// Step 0: Validate all inputs before starting
+	input, err := s.applyPolicy(input)
+	if err != nil {
+		return nil, err
+	}
 	sourcePath, serviceDate, recipients, ccRecipients, ministerName, senderName, err := s.validateInputs(ctx, input)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.sharePrivately(input, recipients, ccRecipients); err != nil {
+		return nil, err
+	}
 
+	if err := s.checkTarget(input); err != nil {
+		return nil, err
+	}
+	folderName, err := s.checkFolder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.failAtStep > 0 {
+		fmt.Fprintf(s.output, "Simulating a failure at step %d (developer build)\n", s.failAtStep)
+	}
 	fmt.Fprintf(s.output, "Using source: %s\n", filepath.Base(sourcePath))
 	fmt.Fprintf(s.output, "Service date: %s\n", serviceDate.Format("2006-01-02"))
 	if ministerName != "" {
 		fmt.Fprintf(s.output, "Minister: %s\n", ministerName)
 	}
-	if input.SkipVideo {
+	fmt.Fprintf(s.output, "Recipients: %s\n", strings.Join(formatRecipients(recipients), ", "))
+	if folderName != "" {
+		fmt.Fprintf(s.output, "Drive folder: %s\n", folderName)
+	}
+	if s.videoByPolicy {
+		fmt.Fprintf(s.output, "Mode: Audio-only (add --with-video to include the video)\n")
+	} else if input.SkipVideo {
 		fmt.Fprintf(s.output, "Mode: Audio-only (--skip-video)\n")
 	}
+	if input.StartTime, input.EndTime, err = s.resolveTimestamps(ctx, sourcePath, input.StartTime, input.EndTime); err != nil {
+		return nil, err
+	}
+	if input.serviceEndDate = s.overnightEndDate(sourcePath, serviceDate, input.StartTime, input.EndTime); !input.serviceEndDate.IsZero() {
+		fmt.Fprintf(s.output, "Service runs past midnight, ending %s\n", input.serviceEndDate.Format("2006-01-02"))
+	}
+	if !input.SkipVideo {
+		if err := s.checkGeometry(ctx, sourcePath, input.StrictGeometry || s.cfg.Video.Strict); err != nil {
+			return nil, err
+		}
+	}
+	state, err := s.startRunState(input, sourcePath, serviceDate)
+	if err != nil {
+		return nil, err
+	}
 	fmt.Fprintln(s.output)
 
 	// Compute cleanup state before processing creates new files
@@ -146,177 +467,741 @@ func (s *Service) Process(ctx context.Context, input Input) (*Result, error) {
 
 	// Route to appropriate workflow
 	if input.SkipVideo {
-		return s.processAudioOnly(ctx, input, sourcePath, serviceDate, recipients, ccRecipients, ministerName, senderName, startTime, cleanupInput)
+		return s.processAudioOnly(ctx, input, sourcePath, serviceDate, recipients, ccRecipients, ministerName, senderName, startTime, cleanupInput, state)
 	}
-	return s.processFullWorkflow(ctx, input, sourcePath, serviceDate, recipients, ccRecipients, ministerName, senderName, startTime, cleanupInput)
+	return s.processFullWorkflow(ctx, input, sourcePath, serviceDate, recipients, ccRecipients, ministerName, senderName, startTime, cleanupInput, state)
 }
 
 // processFullWorkflow handles the standard video+audio workflow
-func (s *Service) processFullWorkflow(ctx context.Context, input Input, sourcePath string, serviceDate time.Time, recipients, ccRecipients []notification.Recipient, ministerName, senderName string, processStartTime time.Time, cleanupInput CleanupInput) (*Result, error) {
+func (s *Service) processFullWorkflow(ctx context.Context, input Input, sourcePath string, serviceDate time.Time, recipients, ccRecipients []notification.Recipient, ministerName, senderName string, processStartTime time.Time, cleanupInput CleanupInput, state *runstate.State) (*Result, error) {
 	// Step 1: Trim video
+	steps := s.newStepClock()
+	var err error
+	steps.StartMeasured("Trim video")
 	fmt.Fprintf(s.output, "[1/7] Trimming video...\n")
-	trimResult, err := s.trimVideo(ctx, sourcePath, input.StartTime, input.EndTime)
-	if err != nil {
-		s.showRecoveryCommands(1, input, sourcePath, serviceDate)
-		return nil, fmt.Errorf("trim failed: %w", err)
+	if style, err := s.cfg.Video.Watermark.Style(); err == nil {
+		if w := style.For(serviceDate, mediaTags(input)); !w.IsZero() {
+			fmt.Fprintf(s.output, "      Watermark: %s (re-encoding, this takes longer than a plain trim)\n", w.Text)
+		}
+	}
+	if len(input.Chapters) > 0 {
+		fmt.Fprintf(s.output, "      Chapters: %s\n", chapterList(input.Chapters))
+	}
+	var trimResult *appvideo.TrimResult
+	if path, ok := s.resumedFile(state.TrimmedPath); ok {
+		trimResult = &appvideo.TrimResult{OutputPath: path, Reused: true}
+		fmt.Fprintf(s.output, "      Done in the earlier run: %s\n\n", path)
+	} else {
+		trimResult, err = runStep(steps, func() (*appvideo.TrimResult, error) {
+			return s.trimVideo(ctx, sourcePath, input.StartTime, input.EndTime, s.audioTrack(input), mediaTags(input), input.Chapters, input.Overwrite)
+		})
+		if err != nil {
+			s.showRecoveryCommands(1, input, sourcePath, serviceDate, recoveryState{MinisterName: ministerName})
+			return nil, fmt.Errorf("trim failed: %w", err)
+		}
+		fmt.Fprintf(s.output, "      %s: %s\n\n", outputLabel(trimResult.Reused), trimResult.OutputPath)
+		state.TrimmedPath = trimResult.OutputPath
+		s.saveRunState(state, "Trim video")
 	}
-	fmt.Fprintf(s.output, "      Created: %s\n\n", trimResult.OutputPath)
 
 	// Step 2: Extract audio
+	steps.StartMeasured("Extract audio")
 	fmt.Fprintf(s.output, "[2/7] Extracting audio...\n")
-	audioResult, err := s.extractAudio(ctx, trimResult.OutputPath, serviceDate)
-	if err != nil {
-		s.showRecoveryCommands(2, input, sourcePath, serviceDate)
-		return nil, fmt.Errorf("audio extraction failed: %w", err)
+	var audioResult *appvideo.ExtractResult
+	if path, ok := s.resumedFile(state.AudioPath); ok {
+		audioResult = &appvideo.ExtractResult{OutputPath: path, Reused: true, Variants: resumedVariants(state)}
+		fmt.Fprintf(s.output, "      Done in the earlier run: %s\n", path)
+	} else {
+		audioResult, err = runStep(steps, func() (*appvideo.ExtractResult, error) {
+			return s.extractAudio(ctx, trimResult.OutputPath, serviceDate, mediaTags(input), input.Overwrite)
+		})
+		if err != nil {
+			s.showRecoveryCommands(2, input, sourcePath, serviceDate, recoveryState{TrimmedPath: trimResult.OutputPath, MinisterName: ministerName})
+			return nil, fmt.Errorf("audio extraction failed: %w", err)
+		}
+		fmt.Fprintf(s.output, "      %s: %s\n", outputLabel(audioResult.Reused), audioResult.OutputPath)
+		state.AudioPath, state.Variants = audioResult.OutputPath, savedVariants(audioResult.Variants)
+		s.saveRunState(state, "Extract audio")
+	}
+	s.printAudioVariants(audioResult.Variants)
+	fmt.Fprintln(s.output)
+
+	known := recoveryState{
+		TrimmedPath:  trimResult.OutputPath,
+		AudioPath:    audioResult.OutputPath,
+		MinisterName: ministerName,
 	}
-	fmt.Fprintf(s.output, "      Created: %s\n\n", audioResult.OutputPath)
 
 	// Step 3: Ensure Drive storage
+	steps.Start("Check Drive storage")
 	fmt.Fprintf(s.output, "[3/7] Checking Drive storage...\n")
 	videoSize := s.fileSizer.Size(trimResult.OutputPath)
 	audioSize := s.fileSizer.Size(audioResult.OutputPath)
-	neededSpace := videoSize + audioSize
-	cleanupResult, err := s.ensureStorage(ctx, neededSpace)
-	if err != nil {
-		s.showRecoveryCommands(3, input, sourcePath, serviceDate)
-		return nil, fmt.Errorf("storage check failed: %w", err)
+	neededSpace := audioSize + s.variantsSize(audioResult.Variants)
+	if input.Target.UsesDrive() {
+		neededSpace += videoSize
 	}
-	for _, df := range cleanupResult.DeletedFiles {
-		fmt.Fprintf(s.output, "      Removed: %s (%.1f MB)\n", df.Name, float64(df.Size)/1024/1024)
-	}
-	if len(cleanupResult.DeletedFiles) == 0 {
-		fmt.Fprintf(s.output, "      Storage OK\n")
+	if state.StorageChecked {
+		fmt.Fprintf(s.output, "      Done in the earlier run\n")
+	} else {
+		if err := steps.Run(func() error { return s.ensureStorageFor(ctx, neededSpace) }); err != nil {
+			known.NeededBytes = neededSpace
+			s.showRecoveryCommands(3, input, sourcePath, serviceDate, known)
+			return nil, err
+		}
+		state.StorageChecked = true
+		s.saveRunState(state, "Check Drive storage")
 	}
 	fmt.Fprintln(s.output)
 
 	// Step 4: Upload video
+	steps.Start("Upload video")
 	fmt.Fprintf(s.output, "[4/7] Uploading video...\n")
-	videoUploadResult, err := s.uploadVideo(ctx, trimResult.OutputPath)
-	if err != nil {
-		s.showRecoveryCommands(4, input, sourcePath, serviceDate)
-		return nil, fmt.Errorf("video upload failed: %w", err)
+	videoUploadResult, hosted := state.Video.Result(), state.Hosted.Result()
+	if input.Target.UsesDrive() {
+		if videoUploadResult != nil {
+			fmt.Fprintf(s.output, "      Done in the earlier run: %s\n", videoUploadResult.FileName)
+		} else {
+			videoUploadResult, err = runStep(steps, func() (*distribution.UploadResult, error) {
+				return s.uploadVideo(ctx, trimResult.OutputPath)
+			})
+			if err != nil {
+				s.showRecoveryCommands(4, input, sourcePath, serviceDate, known)
+				return nil, fmt.Errorf("video upload failed: %w", err)
+			}
+			fmt.Fprintf(s.output, "      Uploaded: %s\n", filepath.Base(trimResult.OutputPath))
+			state.Video = runstate.NewUpload(videoUploadResult)
+			s.saveRunState(state, "Upload video")
+		}
+		known.Video = videoUploadResult
 	}
-	fmt.Fprintf(s.output, "      Uploaded: %s\n\n", filepath.Base(trimResult.OutputPath))
+	if input.Target.UsesVideoHost() {
+		if hosted != nil {
+			fmt.Fprintf(s.output, "      Done in the earlier run: %s\n", hosted.URL)
+		} else {
+			hosted, err = runStep(steps, func() (*distribution.HostedVideo, error) {
+				return s.hostVideo(ctx, input, trimResult.OutputPath, serviceDate, ministerName)
+			})
+			if err != nil {
+				s.showRecoveryCommands(4, input, sourcePath, serviceDate, known)
+				return nil, fmt.Errorf("%s upload failed: %w", s.videoHost.Name(), err)
+			}
+			fmt.Fprintf(s.output, "      On %s: %s (%s)\n", hosted.Host, hosted.URL, hosted.Privacy)
+			state.Hosted = runstate.NewHosted(hosted)
+			s.saveRunState(state, "Upload video")
+		}
+		known.Video = hostedUpload(videoUploadResult, hosted)
+	}
+	fmt.Fprintln(s.output)
+	sentVideo := hostedUpload(videoUploadResult, hosted)
+	videoURL := sentVideo.ShareableURL
 
 	// Step 5: Upload audio
+	steps.Start("Upload audio")
 	fmt.Fprintf(s.output, "[5/7] Uploading audio...\n")
-	audioUploadResult, err := s.uploadAudio(ctx, audioResult.OutputPath)
-	if err != nil {
-		s.showRecoveryCommands(5, input, sourcePath, serviceDate)
-		return nil, fmt.Errorf("audio upload failed: %w", err)
+	audioUploadResult := state.Audio.Result()
+	audioVersions := resumedVersions(state)
+	if audioUploadResult != nil {
+		fmt.Fprintf(s.output, "      Done in the earlier run: %s\n", audioUploadResult.FileName)
+	} else {
+		audioUploadResult, err = runStep(steps, func() (*distribution.UploadResult, error) {
+			return s.uploadAudio(ctx, audioResult.OutputPath)
+		})
+		if err != nil {
+			s.showRecoveryCommands(5, input, sourcePath, serviceDate, known)
+			return nil, fmt.Errorf("audio upload failed: %w", err)
+		}
+		fmt.Fprintf(s.output, "      Uploaded: %s\n", filepath.Base(audioResult.OutputPath))
+		audioVersions = s.uploadAudioVariants(ctx, audioResult.Variants)
+		state.Audio, state.AudioVersions = runstate.NewUpload(audioUploadResult), savedVersions(audioVersions)
+		s.saveRunState(state, "Upload audio")
 	}
-	fmt.Fprintf(s.output, "      Uploaded: %s\n\n", filepath.Base(audioResult.OutputPath))
+	fmt.Fprintln(s.output)
+	known.Audio = audioUploadResult
 
 	// Step 6: Share files
+	steps.Start("Share files")
 	fmt.Fprintf(s.output, "[6/7] Sharing files...\n")
-	fmt.Fprintf(s.output, "      Video link: %s\n", videoUploadResult.ShareableURL)
-	fmt.Fprintf(s.output, "      Audio link: %s\n\n", audioUploadResult.ShareableURL)
+	fmt.Fprintf(s.output, "      Video link: %s\n", videoURL)
+	fmt.Fprintf(s.output, "      Audio link: %s\n", audioUploadResult.ShareableURL)
+	s.printFolderLink()
+	sharingPending := sentVideo.SharingPending || audioUploadResult.SharingPending
+	s.warnSharingPending(sharingPending, serviceDate)
+	mirror := s.publishMirror(ctx, serviceDate, trimResult.OutputPath, audioResult.OutputPath)
+	fmt.Fprintln(s.output)
 
 	// Step 7: Send email
+	steps.Start("Send email")
 	fmt.Fprintf(s.output, "[7/7] Sending email...\n")
-	err = s.sendEmail(recipients, ccRecipients, serviceDate, ministerName, senderName, audioUploadResult.ShareableURL, videoUploadResult.ShareableURL)
+	email, err := runStep(steps, func() (*sentEmail, error) {
+		return s.sendEmail(ctx, input, recipients, ccRecipients, serviceDate, ministerName, senderName, audioUploadResult.ShareableURL, videoURL, audioVersions, mirror)
+	})
 	if err != nil {
-		s.showRecoveryCommands(7, input, sourcePath, serviceDate)
+		s.showRecoveryCommands(7, input, sourcePath, serviceDate, known)
 		return nil, fmt.Errorf("email failed: %w", err)
 	}
-	for _, r := range recipients {
-		fmt.Fprintf(s.output, "      Sent to: %s <%s>\n", r.Name, r.Address)
+	if !s.sandboxed(input) {
+		for _, r := range recipients {
+			fmt.Fprintf(s.output, "      Sent to: %s\n", r)
+		}
 	}
 	fmt.Fprintln(s.output)
 
+	s.finishRunState(state)
+	runSteps := steps.Steps()
+	s.recordHistory(input, sourcePath, serviceDate, ministerName, recipients, email, trimResult.OutputPath, audioSize, sentVideo, audioUploadResult, runSteps)
+
 	elapsed := time.Since(processStartTime)
+	s.archiveSummary(summary.RunSummary{
+		ServiceDate: serviceDate,
+		Minister:    ministerName,
+		SourceFile:  filepath.Base(sourcePath),
+		StartTime:   input.StartTime,
+		EndTime:     input.EndTime,
+		Steps:       runSteps,
+		Total:       elapsed,
+		Files: append([]summary.File{
+			{Kind: "Video", Path: trimResult.OutputPath, Size: videoSize},
+			{Kind: "Audio", Path: audioResult.OutputPath, Size: audioSize},
+		}, s.variantFiles(audioResult.Variants)...),
+		Links:   summaryLinks(videoURL, audioUploadResult.ShareableURL, mirror),
+		Deleted: s.deleted,
+		Notes:   input.Notes,
+		FFmpeg:  input.FFmpegVersion,
+	}, email.Request)
 	fmt.Fprintf(s.output, "Done! Completed in %s\n", formatDuration(elapsed))
+	s.printUsage(runSteps)
+	s.printNotes(input.Notes)
 
 	// Post-processing cleanup: free space if disk is getting full (>70%)
 	s.cleanupLocalFiles(cleanupInput, 70.0, "Post-processing")
+	s.expireSharedFiles(ctx)
 
 	return &Result{
 		TrimmedPath: trimResult.OutputPath,
 		AudioPath:   audioResult.OutputPath,
-		VideoURL:    videoUploadResult.ShareableURL,
+		VideoURL:    videoURL,
 		AudioURL:    audioUploadResult.ShareableURL,
 		ServiceDate: serviceDate,
+
+		SharingPending: sharingPending,
 	}, nil
 }
 
 // processAudioOnly handles the audio-only workflow (--skip-video mode)
-func (s *Service) processAudioOnly(ctx context.Context, input Input, sourcePath string, serviceDate time.Time, recipients, ccRecipients []notification.Recipient, ministerName, senderName string, processStartTime time.Time, cleanupInput CleanupInput) (*Result, error) {
+func (s *Service) processAudioOnly(ctx context.Context, input Input, sourcePath string, serviceDate time.Time, recipients, ccRecipients []notification.Recipient, ministerName, senderName string, processStartTime time.Time, cleanupInput CleanupInput, state *runstate.State) (*Result, error) {
+	steps := s.newStepClock()
+	known := recoveryState{MinisterName: ministerName}
+
+	// Steps 1-3: Extract, make room on Drive and upload, or stream the
+	// extraction straight into the upload when that is enabled
+	total := 4
+	var audio *audioOutput
+	var err error
+	if state.Audio != nil {
+		// The upload is what counts: a streamed MP3 may have no local copy
+		upload := state.Audio.Result()
+		path, _ := s.resumedFile(state.AudioPath)
+		done := path
+		if done == "" {
+			done = upload.FileName + " (in Drive)"
+		}
+		size := upload.Size
+		if size <= 0 && path != "" {
+			size = s.fileSizer.Size(path)
+		}
+		fmt.Fprintf(s.output, "[1-3/4] Extracting and uploading audio...\n")
+		fmt.Fprintf(s.output, "      Done in the earlier run: %s\n", done)
+		audio = &audioOutput{
+			Extract:  &appvideo.ExtractResult{OutputPath: path, Reused: true, Variants: resumedVariants(state)},
+			Size:     size,
+			Upload:   upload,
+			Versions: resumedVersions(state),
+		}
+	} else {
+		if streamer, ok := s.audioStreamer(input); ok {
+			total = 3
+			audio, err = s.streamAudioOnly(ctx, streamer, input, sourcePath, serviceDate, steps, known)
+		} else {
+			audio, err = s.extractAndUploadAudioOnly(ctx, input, sourcePath, serviceDate, steps, known)
+		}
+		if err != nil {
+			return nil, err
+		}
+		state.AudioPath, state.Variants = audio.Extract.OutputPath, savedVariants(audio.Extract.Variants)
+		state.Audio, state.AudioVersions = runstate.NewUpload(audio.Upload), savedVersions(audio.Versions)
+		s.saveRunState(state, "Upload audio")
+	}
+	audioResult, audioSize, audioUploadResult := audio.Extract, audio.Size, audio.Upload
+	fmt.Fprintf(s.output, "      Audio link: %s\n", audioUploadResult.ShareableURL)
+	s.printFolderLink()
+	s.warnSharingPending(audioUploadResult.SharingPending, serviceDate)
+	mirror := s.publishMirror(ctx, serviceDate, "", audioResult.OutputPath)
+	fmt.Fprintln(s.output)
+	known.AudioPath = audioResult.OutputPath
+	known.Audio = audioUploadResult
+
+	// Last step: Send email (audio only)
+	steps.Start("Send email")
+	fmt.Fprintf(s.output, "[%d/%d] Sending email...\n", total, total)
+	email, err := runStep(steps, func() (*sentEmail, error) {
+		return s.sendEmail(ctx, input, recipients, ccRecipients, serviceDate, ministerName, senderName, audioUploadResult.ShareableURL, "", audio.Versions, mirror)
+	})
+	if err != nil {
+		s.showRecoveryCommandsAudioOnly(4, input, sourcePath, serviceDate, known)
+		return nil, fmt.Errorf("email failed: %w", err)
+	}
+	if !s.sandboxed(input) {
+		for _, r := range recipients {
+			fmt.Fprintf(s.output, "      Sent to: %s\n", r)
+		}
+	}
+	fmt.Fprintln(s.output)
+
+	s.finishRunState(state)
+	runSteps := steps.Steps()
+	s.recordHistory(input, sourcePath, serviceDate, ministerName, recipients, email, "", audioSize, nil, audioUploadResult, runSteps)
+
+	elapsed := time.Since(processStartTime)
+	s.archiveSummary(summary.RunSummary{
+		ServiceDate: serviceDate,
+		Minister:    ministerName,
+		SourceFile:  filepath.Base(sourcePath),
+		StartTime:   input.StartTime,
+		EndTime:     input.EndTime,
+		AudioOnly:   true,
+		Steps:       runSteps,
+		Total:       elapsed,
+		Files:       append(audioFiles(audioResult.OutputPath, audioSize), s.variantFiles(audioResult.Variants)...),
+		Links:       summaryLinks("", audioUploadResult.ShareableURL, mirror),
+		Deleted:     s.deleted,
+		Notes:       input.Notes,
+		FFmpeg:      input.FFmpegVersion,
+	}, email.Request)
+	fmt.Fprintf(s.output, "Done! Completed in %s\n", formatDuration(elapsed))
+	s.printUsage(runSteps)
+	s.printNotes(input.Notes)
+
+	// Post-processing cleanup: free space if disk is getting full (>70%)
+	s.cleanupLocalFiles(cleanupInput, 70.0, "Post-processing")
+	s.expireSharedFiles(ctx)
+
+	return &Result{
+		TrimmedPath: "", // No trimmed video
+		AudioPath:   audioResult.OutputPath,
+		VideoURL:    "", // No video URL
+		AudioURL:    audioUploadResult.ShareableURL,
+		ServiceDate: serviceDate,
+
+		SharingPending: audioUploadResult.SharingPending,
+	}, nil
+}
+
+// audioFiles lists the local MP3 for the run summary; a streamed MP3 that was
+// not kept has none
+func audioFiles(path string, size int64) []summary.File {
+	if path == "" {
+		return nil
+	}
+	return []summary.File{{Kind: "Audio", Path: path, Size: size}}
+}
+
+// audioOutput is the MP3 produced and uploaded by an audio-only run
+type audioOutput struct {
+	Extract  *appvideo.ExtractResult
+	Size     int64
+	Upload   *distribution.UploadResult
+	Versions []notification.AudioVersion // Uploaded extra bitrates
+}
+
+// extractAndUploadAudioOnly extracts the MP3 to a file, makes room on Drive
+// for it and uploads it
+func (s *Service) extractAndUploadAudioOnly(ctx context.Context, input Input, sourcePath string, serviceDate time.Time, steps *stepClock, known recoveryState) (*audioOutput, error) {
 	// Step 1: Extract audio directly from source with timestamps
+	steps.StartMeasured("Extract audio")
 	fmt.Fprintf(s.output, "[1/4] Extracting audio...\n")
-	audioResult, err := s.extractAudioWithTimestamps(ctx, sourcePath, serviceDate, input.StartTime, input.EndTime)
+	audioResult, err := runStep(steps, func() (*appvideo.ExtractResult, error) {
+		return s.extractAudioWithTimestamps(ctx, sourcePath, serviceDate, input.StartTime, input.EndTime, s.audioTrack(input), mediaTags(input), input.Overwrite)
+	})
 	if err != nil {
-		s.showRecoveryCommandsAudioOnly(1, input, sourcePath, serviceDate)
+		s.showRecoveryCommandsAudioOnly(1, input, sourcePath, serviceDate, known)
 		return nil, fmt.Errorf("audio extraction failed: %w", err)
 	}
-	fmt.Fprintf(s.output, "      Created: %s\n\n", audioResult.OutputPath)
+	fmt.Fprintf(s.output, "      %s: %s\n", outputLabel(audioResult.Reused), audioResult.OutputPath)
+	s.printAudioVariants(audioResult.Variants)
+	fmt.Fprintln(s.output)
+	known.AudioPath = audioResult.OutputPath
 
 	// Step 2: Ensure Drive storage (still run cleanup for mp4s)
+	steps.Start("Check Drive storage")
 	fmt.Fprintf(s.output, "[2/4] Checking Drive storage...\n")
 	audioSize := s.fileSizer.Size(audioResult.OutputPath)
-	cleanupResult, err := s.ensureStorage(ctx, audioSize)
-	if err != nil {
-		s.showRecoveryCommandsAudioOnly(2, input, sourcePath, serviceDate)
-		return nil, fmt.Errorf("storage check failed: %w", err)
-	}
-	for _, df := range cleanupResult.DeletedFiles {
-		fmt.Fprintf(s.output, "      Removed: %s (%.1f MB)\n", df.Name, float64(df.Size)/1024/1024)
-	}
-	if len(cleanupResult.DeletedFiles) == 0 {
-		fmt.Fprintf(s.output, "      Storage OK\n")
+	neededSpace := audioSize + s.variantsSize(audioResult.Variants)
+	if err := steps.Run(func() error { return s.ensureStorageFor(ctx, neededSpace) }); err != nil {
+		known.NeededBytes = neededSpace
+		s.showRecoveryCommandsAudioOnly(2, input, sourcePath, serviceDate, known)
+		return nil, err
 	}
 	fmt.Fprintln(s.output)
 
 	// Step 3: Upload audio
+	steps.Start("Upload audio")
 	fmt.Fprintf(s.output, "[3/4] Uploading audio...\n")
-	audioUploadResult, err := s.uploadAudio(ctx, audioResult.OutputPath)
+	upload, err := runStep(steps, func() (*distribution.UploadResult, error) {
+		return s.uploadAudio(ctx, audioResult.OutputPath)
+	})
 	if err != nil {
-		s.showRecoveryCommandsAudioOnly(3, input, sourcePath, serviceDate)
+		s.showRecoveryCommandsAudioOnly(3, input, sourcePath, serviceDate, known)
 		return nil, fmt.Errorf("audio upload failed: %w", err)
 	}
 	fmt.Fprintf(s.output, "      Uploaded: %s\n", filepath.Base(audioResult.OutputPath))
-	fmt.Fprintf(s.output, "      Audio link: %s\n\n", audioUploadResult.ShareableURL)
+	versions := s.uploadAudioVariants(ctx, audioResult.Variants)
+	return &audioOutput{Extract: audioResult, Size: audioSize, Upload: upload, Versions: versions}, nil
+}
 
-	// Step 4: Send email (audio only)
-	fmt.Fprintf(s.output, "[4/4] Sending email...\n")
-	err = s.sendEmail(recipients, ccRecipients, serviceDate, ministerName, senderName, audioUploadResult.ShareableURL, "")
+// streamAudioOnly makes room on Drive for the estimated MP3 size, then pipes
+// ffmpeg's output into the upload so no step waits for a finished file. If
+// streaming fails, it falls back to extracting to a file and uploading that.
+func (s *Service) streamAudioOnly(ctx context.Context, streamer video.AudioStreamer, input Input, sourcePath string, serviceDate time.Time, steps *stepClock, known recoveryState) (*audioOutput, error) {
+	bitrate := s.cfg.Audio.Bitrate.String()
+	if bitrate == "" {
+		bitrate = video.DefaultAudioBitrate
+	}
+	req, err := video.NewAudioExtractionRequestWithTimestamps(sourcePath, serviceDate, bitrate, input.StartTime, input.EndTime)
 	if err != nil {
-		s.showRecoveryCommandsAudioOnly(4, input, sourcePath, serviceDate)
-		return nil, fmt.Errorf("email failed: %w", err)
+		return nil, fmt.Errorf("audio extraction failed: %w", err)
 	}
-	for _, r := range recipients {
-		fmt.Fprintf(s.output, "      Sent to: %s <%s>\n", r.Name, r.Address)
+	req.AudioTrack = s.audioTrack(input)
+	req.Tags = mediaTags(input)
+	audioPath := req.OutputPath(s.cfg.Paths.AudioDirectory)
+
+	// Step 1: Ensure Drive storage for the estimated size
+	steps.Start("Check Drive storage")
+	fmt.Fprintf(s.output, "[1/3] Checking Drive storage...\n")
+	estimate := req.EstimatedSize()
+	fmt.Fprintf(s.output, "      Estimated audio size: %s\n", distribution.FormatSize(estimate))
+	if err := steps.Run(func() error { return s.ensureStorageFor(ctx, estimate) }); err != nil {
+		known.NeededBytes = estimate
+		s.showRecoveryCommandsAudioOnly(1, input, sourcePath, serviceDate, known)
+		return nil, err
 	}
 	fmt.Fprintln(s.output)
 
-	elapsed := time.Since(processStartTime)
-	fmt.Fprintf(s.output, "Done! Completed in %s\n", formatDuration(elapsed))
+	// Step 2: Extract and upload at once
+	steps.StartMeasured("Extract and upload audio")
+	fmt.Fprintf(s.output, "[2/3] Extracting and uploading audio...\n")
+	uploadService := appdist.NewUploadService(s.driveClient, s.folderID, s.output, s.shareOptions()...)
+	keepLocal := s.keepStreamedAudio()
+	upload, err := runStep(steps, func() (*distribution.UploadResult, error) {
+		return uploadService.UploadAudioStream(ctx, audioPath, keepLocal, func(w io.Writer) error {
+			return streamer.Stream(ctx, req, w)
+		})
+	})
+	if err == nil {
+		// Nothing points at the MP3 on disk unless it was kept
+		saved := ""
+		if keepLocal {
+			saved = audioPath
+			fmt.Fprintf(s.output, "      Created: %s\n", audioPath)
+		}
+		fmt.Fprintf(s.output, "      Streamed: %s (%s, size and checksum verified)\n", filepath.Base(audioPath), distribution.FormatSize(upload.Size))
+		return &audioOutput{Extract: &appvideo.ExtractResult{OutputPath: saved}, Size: upload.Size, Upload: upload}, nil
+	}
 
-	// Post-processing cleanup: free space if disk is getting full (>70%)
-	s.cleanupLocalFiles(cleanupInput, 70.0, "Post-processing")
+	fmt.Fprintf(s.output, "      Streaming failed: %v\n", err)
+	fmt.Fprintf(s.output, "      Falling back to extracting to a file first\n")
+	audioResult, err := s.extractAudioWithTimestamps(ctx, sourcePath, serviceDate, input.StartTime, input.EndTime, s.audioTrack(input), mediaTags(input), input.Overwrite)
+	if err != nil {
+		s.showRecoveryCommandsAudioOnly(1, input, sourcePath, serviceDate, known)
+		return nil, fmt.Errorf("audio extraction failed: %w", err)
+	}
+	fmt.Fprintf(s.output, "      %s: %s\n", outputLabel(audioResult.Reused), audioResult.OutputPath)
+	known.AudioPath = audioResult.OutputPath
 
-	return &Result{
-		TrimmedPath: "", // No trimmed video
-		AudioPath:   audioResult.OutputPath,
-		VideoURL:    "", // No video URL
-		AudioURL:    audioUploadResult.ShareableURL,
-		ServiceDate: serviceDate,
-	}, nil
+	upload, err = s.uploadAudio(ctx, audioResult.OutputPath)
+	if err != nil {
+		s.showRecoveryCommandsAudioOnly(3, input, sourcePath, serviceDate, known)
+		return nil, fmt.Errorf("audio upload failed: %w", err)
+	}
+	fmt.Fprintf(s.output, "      Uploaded: %s\n", filepath.Base(audioResult.OutputPath))
+	return &audioOutput{Extract: audioResult, Size: s.fileSizer.Size(audioResult.OutputPath), Upload: upload}, nil
+}
+
+// keepStreamedAudio reports whether a streamed MP3 is also saved locally:
+// when audio.stream_keep_local asks for it, or when the mirror or the share
+// scanner needs the file
+func (s *Service) keepStreamedAudio() bool {
+	return s.cfg.Audio.StreamKeepLocal || s.publisher != nil || s.scanner != nil
+}
+
+// audioStreamer returns the extractor's streamer when audio should be streamed
+// to Drive. Only a replaced output is streamed: other overwrite policies need
+// to inspect the existing file first.
+func (s *Service) audioStreamer(input Input) (video.AudioStreamer, bool) {
+	if !input.StreamAudio && !s.cfg.Audio.StreamUpload {
+		return nil, false
+	}
+	if len(s.cfg.Audio.ExtraBitrates()) > 0 {
+		fmt.Fprintf(s.output, "Extra audio bitrates are made from one extraction; extracting to files first\n\n")
+		return nil, false
+	}
+	if input.Overwrite.Policy != "" && input.Overwrite.Policy != video.OverwriteReplace {
+		fmt.Fprintf(s.output, "Streaming upload needs --on-existing overwrite; extracting to a file first\n\n")
+		return nil, false
+	}
+	streamer, ok := s.extractor.(video.AudioStreamer)
+	if !ok {
+		fmt.Fprintf(s.output, "Audio extractor cannot stream; extracting to a file first\n\n")
+		return nil, false
+	}
+	return streamer, true
+}
+
+// checkpoint shows the next irreversible action and asks to go ahead. Without
+// step confirmation it always goes ahead.
+func (s *Service) checkpoint(action string) (bool, error) {
+	if s.confirmStep == nil {
+		return true, nil
+	}
+	fmt.Fprintf(s.output, "      Next: %s\n", action)
+	return s.confirmStep(action)
+}
+
+// ensureStorageFor checks the upload budget, makes room on Drive and reports
+// what was removed
+func (s *Service) ensureStorageFor(ctx context.Context, neededBytes int64) error {
+	if err := s.checkUploadBudget(neededBytes); err != nil {
+		return err
+	}
+	cleanup := s.cleanupService()
+	plan, err := cleanup.PlanCleanup(ctx, neededBytes)
+	if err != nil {
+		return fmt.Errorf("storage check failed: %w", err)
+	}
+	if len(plan.Files) == 0 {
+		fmt.Fprintf(s.output, "      Storage OK\n")
+		return nil
+	}
+
+	fmt.Fprintf(s.output, "      Drive needs %s more; these old videos would be deleted:\n", distribution.FormatSize(plan.Shortfall))
+	for _, f := range plan.Files {
+		note := ""
+		if plan.Archived[f.ID] {
+			note = ", still on the mirror"
+		}
+		fmt.Fprintf(s.output, "        %s (%.1f MB)%s\n", f.Name, float64(f.Size)/1024/1024, note)
+	}
+	if err := s.approveCleanup(plan); err != nil {
+		return err
+	}
+
+	cleanupResult := cleanup.DeleteFiles(ctx, plan)
+	if len(cleanupResult.DeletedFiles) > 0 {
+		s.deleted = append(s.deleted, cleanupResult.DeletedFiles...)
+		fmt.Fprintf(s.output, "      Deleted from Drive (%s freed):\n", distribution.FormatSize(cleanupResult.FreedBytes))
+		fmt.Fprint(s.output, distribution.DeletedTable(cleanupResult.DeletedFiles, "        "))
+	}
+	for _, fd := range cleanupResult.Failed {
+		fmt.Fprintf(s.output, "      Warning: could not remove %s: %v\n", fd.Name, fd.Err)
+	}
+	if len(cleanupResult.Failed) > 0 {
+		first := cleanupResult.Failed[0]
+		return fmt.Errorf("storage check failed: %d of %d approved deletion(s) failed (first: %s: %w)",
+			len(cleanupResult.Failed), len(plan.Files), first.Name, first.Err)
+	}
+	if plan.Bytes() < plan.Shortfall {
+		return fmt.Errorf("storage check failed: deleting every old video freed %s of the %s needed",
+			distribution.FormatSize(cleanupResult.FreedBytes), distribution.FormatSize(plan.Shortfall))
+	}
+	return nil
+}
+
+// approveCleanup decides whether the planned deletions go ahead: allowed by
+// --allow-delete, confirmed by the operator, or refused when nobody can be
+// asked. The decision is recorded in the audit log.
+func (s *Service) approveCleanup(plan *distribution.CleanupPlan) error {
+	names := make([]string, len(plan.Files))
+	for i, f := range plan.Files {
+		names[i] = f.Name
+	}
+	target := strings.Join(names, ", ")
+
+	confirm := s.confirmDelete
+	if s.confirmStep != nil {
+		confirm = s.confirmStep
+	}
+	switch {
+	case s.allowDelete:
+		s.recordCleanupDecision(target, "allowed by --allow-delete")
+		return nil
+	case confirm == nil:
+		s.recordCleanupDecision(target, "refused: --allow-delete not given")
+		return &ValidationError{
+			Code:       CodeDeleteNotAllowed,
+			Message:    fmt.Sprintf("Drive needs %s more, and making room means deleting %d old video(s), which needs approval", distribution.FormatSize(plan.Shortfall), len(plan.Files)),
+			Suggestion: "Re-run with --allow-delete to let process delete them, or free space first with: nac-service-media drive cleanup",
+		}
+	}
+
+	action := fmt.Sprintf("delete the oldest videos from Drive to free %s", distribution.FormatSize(plan.Shortfall))
+	fmt.Fprintf(s.output, "      Next: %s\n", action)
+	ok, err := confirm(action)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.recordCleanupDecision(target, "declined")
+		return fmt.Errorf("storage check failed: Drive cleanup: %w", ErrStepDeclined)
+	}
+	s.recordCleanupDecision(target, "approved")
+	return nil
+}
+
+// recordCleanupDecision audits a cleanup decision; a failure to record it is
+// only a warning, since each deletion is audited on its own
+func (s *Service) recordCleanupDecision(target, decision string) {
+	if err := audit.Record(s.auditLog, audit.ActionCleanupDecision, target, decision, nil); err != nil {
+		fmt.Fprintf(s.output, "      Warning: %v\n", err)
+	}
+}
+
+// checkUploadBudget warns, or asks to go ahead, when uploading neededBytes
+// would go over the weekly upload budget tracked in history
+func (s *Service) checkUploadBudget(neededBytes int64) error {
+	budget := s.cfg.Budget.UploadBudget()
+	if !budget.Enabled() || s.history == nil {
+		return nil
+	}
+	entries, err := s.history.List()
+	if err != nil {
+		fmt.Fprintf(s.output, "      Warning: could not read history for the upload budget: %v\n", err)
+		return nil
+	}
+
+	usage := budget.Usage(entries, time.Now())
+	fmt.Fprintf(s.output, "      Upload budget: %s of %s used this week\n", distribution.FormatSize(usage.Used), distribution.FormatSize(usage.Limit))
+	if !usage.Exceeds(neededBytes) {
+		return nil
+	}
+	over := fmt.Sprintf("uploading %s would go over the weekly upload budget (%s left)",
+		distribution.FormatSize(neededBytes), distribution.FormatSize(usage.Remaining()))
+	if !budget.Confirms() {
+		fmt.Fprintf(s.output, "      Warning: %s\n", over)
+		return nil
+	}
+	if s.confirmBudget == nil {
+		return &ValidationError{
+			Code:       CodeOverBudget,
+			Message:    over,
+			Suggestion: "Run interactively to confirm the upload, wait for older uploads to leave the 7-day window, or raise upload_budget.weekly_gb",
+		}
+	}
+	fmt.Fprintf(s.output, "      Warning: %s\n", over)
+	ok, err := s.confirmBudget(over)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("storage check failed: upload budget: %w", ErrStepDeclined)
+	}
+	return nil
+}
+
+// resolveTimestamps turns -HH:MM:SS and +HH:MM:SS timestamps into absolute
+// ones, so trimming, history and recovery commands all see HH:MM:SS. An end
+// past the end of the source is moved to the end of the file.
+func (s *Service) resolveTimestamps(ctx context.Context, sourcePath, start, end string) (string, string, error) {
+	if isRelativeTimestamp(start) || isRelativeTimestamp(end) {
+		startTs, endTs, err := appvideo.ResolveRange(ctx, s.prober, sourcePath, start, end)
+		if err != nil {
+			return "", "", err
+		}
+		fmt.Fprintf(s.output, "Trim range: %s to %s (from %s to %s)\n", startTs, endTs, start, end)
+		start, end = startTs.String(), endTs.String()
+	}
+
+	startTs, err := video.ParseTimestamp(start)
+	if err != nil {
+		return start, end, nil // Trimming reports the bad timestamp
+	}
+	endTs, err := video.ParseTimestamp(end)
+	if err != nil {
+		return start, end, nil
+	}
+	fitted, clamped, err := appvideo.FitToSource(ctx, s.prober, sourcePath, startTs, endTs)
+	if err != nil {
+		return "", "", err
+	}
+	if clamped {
+		fmt.Fprintf(s.output, "End %s is past the end of the recording; trimming to the end at %s\n", endTs, fitted)
+	}
+	return start, fitted.String(), nil
+}
+
+// overnightEndDate returns the date a service that runs past midnight ends
+// on, counted from serviceDate so --date still applies, or zero when it ends
+// the day it starts. The recording's name says when it started; a trimmed
+// source has no time of day, so it is never overnight.
+func (s *Service) overnightEndDate(sourcePath string, serviceDate time.Time, start, end string) time.Time {
+	startTs, err := video.ParseTimestamp(start)
+	if err != nil {
+		return time.Time{}
+	}
+	endTs, err := video.ParseTimestamp(end)
+	if err != nil {
+		return time.Time{}
+	}
+	days, ok := s.calendar.DaysCrossed(filepath.Base(sourcePath), startTs, endTs)
+	if !ok || days <= 0 {
+		return time.Time{}
+	}
+	return serviceDate.AddDate(0, 0, days)
+}
+
+func isRelativeTimestamp(s string) bool {
+	return strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+")
+}
+
+// checkClockDrift stops when the recording was written long before or after
+// the time in its name, since the date taken from the name is then likely
+// wrong. --date confirms the date and skips this check.
+func (s *Service) checkClockDrift(sourcePath string, serviceDate time.Time) error {
+	if s.modTimes == nil {
+		return nil
+	}
+	modTime, err := s.modTimes.ModTime(sourcePath)
+	if err != nil {
+		return nil
+	}
+	name := filepath.Base(sourcePath)
+	drift, ok := s.calendar.ClockDrift(name, modTime)
+	if !ok || drift <= s.cfg.Locale.MaxClockDrift() {
+		return nil
+	}
+	fmt.Fprintf(s.output, "Warning: %s was last written %s, %s from the time in its name\n",
+		name, modTime.In(s.calendar.Location()).Format("2006-01-02 15:04"), drift.Round(time.Minute))
+	return &ValidationError{
+		Code: CodeClockDrift,
+		Message: fmt.Sprintf("the recording PC's clock may be wrong: %s is named for %s but was written on %s",
+			name, serviceDate.Format("2006-01-02"), s.calendar.DateOf(modTime).Format("2006-01-02")),
+		Suggestion: fmt.Sprintf("Rerun with --date set to the correct service date, e.g. --date %s", s.calendar.DateOf(modTime).Format("2006-01-02")),
+	}
 }
 
 func (s *Service) validateInputs(ctx context.Context, input Input) (sourcePath string, serviceDate time.Time, recipients, ccRecipients []notification.Recipient, ministerName, senderName string, err error) {
 	// Resolve source path
 	sourcePath = input.InputPath
 	if sourcePath == "" {
-		// Find newest file in source directory
-		newest, findErr := s.fileFinder.FindNewestFile(s.cfg.Paths.SourceDirectory, ".mp4")
+		// Find newest file in the source directories, by priority
+		newest, findErr := domainfs.FindNewestSource(s.fileFinder, s.cfg.Paths.Sources(), ".mp4")
 		if findErr != nil {
 			err = findErr
 			return
 		}
 		sourcePath = newest
-	} else if !filepath.IsAbs(sourcePath) {
-		// Resolve relative paths against source directory
-		sourcePath = filepath.Join(s.cfg.Paths.SourceDirectory, sourcePath)
+	} else {
+		// Resolve relative paths against the source directories
+		sourcePath = domainfs.ResolveSource(s.fileChecker.Exists, s.cfg.Paths.Sources(), sourcePath)
 	}
 
 	// Verify source file exists
@@ -334,11 +1219,14 @@ func (s *Service) validateInputs(ctx context.Context, input Input) (sourcePath s
 		}
 	} else {
 		// Try to infer from filename
-		serviceDate, err = inferDateFromFilename(filepath.Base(sourcePath))
+		serviceDate, err = s.calendar.DateFromFilename(filepath.Base(sourcePath))
 		if err != nil {
 			err = fmt.Errorf("cannot infer date from filename %q. Use --date to specify: %w", filepath.Base(sourcePath), err)
 			return
 		}
+		if err = s.checkClockDrift(sourcePath, serviceDate); err != nil {
+			return
+		}
 	}
 
 	// Note: Already-processed check is now done earlier in cmd/process.go
@@ -349,6 +1237,7 @@ func (s *Service) validateInputs(ctx context.Context, input Input) (sourcePath s
 		minister, ministerErr := config.NewConfigManager(s.cfg, "").GetMinister(input.MinisterKey)
 		if ministerErr != nil {
 			err = &ValidationError{
+				Code:       CodeMinisterNotFound,
 				Message:    fmt.Sprintf("minister '%s' not found in config", input.MinisterKey),
 				Suggestion: config.SuggestAddMinisterCommand(input.MinisterKey),
 			}
@@ -359,41 +1248,73 @@ func (s *Service) validateInputs(ctx context.Context, input Input) (sourcePath s
 
 	// Lookup recipients
 	lookup := config.NewRecipientLookup(s.cfg, "")
-	recipients, err = lookup.LookupRecipients(input.RecipientKeys)
+	recipients, err = lookup.ResolveRecipients(input.RecipientKeys, input.ExcludeKeys)
+	if errors.Is(err, notification.ErrAmbiguousRecipient) {
+		return
+	}
+	if errors.Is(err, notification.ErrExclusionUnmatched) {
+		err = &ValidationError{Code: CodeExclusionUnmatched, Message: err.Error()}
+		return
+	}
+	if errors.Is(err, notification.ErrNoRecipients) {
+		err = &ValidationError{Code: CodeNoRecipients, Message: err.Error()}
+		return
+	}
 	if err != nil {
 		key := input.RecipientKeys[0]
 		if len(input.RecipientKeys) > 1 {
 			key = "recipients"
 		}
 		err = &ValidationError{
+			Code:       CodeRecipientNotFound,
 			Message:    fmt.Sprintf("recipient '%s' not found in config", key),
 			Suggestion: config.SuggestAddRecipientCommand(key),
 		}
 		return
 	}
 
-	// Get default CC recipients
-	ccRecipients = lookup.GetDefaultCC()
+	// Get default CC recipients, unless the policy keeps the email to the
+	// people named for the run
+	if s.policy.DefaultCC {
+		ccRecipients = lookup.GetDefaultCC()
+	}
 
-	// Add any additional CC recipients from flags
-	for _, ccKey := range input.CCKeys {
-		ccMatches, ccErr := lookup.LookupRecipient(ccKey)
-		if ccErr != nil {
+	// Add any additional CC recipients from flags: keys, names, groups or
+	// raw addresses, leaving out anyone already emailed
+	for _, ccKey := range config.SplitQueries(input.CCKeys) {
+		ccMatches, ambiguous, ccErr := lookup.LookupCC(ccKey)
+		if errors.Is(ccErr, notification.ErrRecipientNotFound) {
 			err = &ValidationError{
+				Code:       CodeCCNotFound,
 				Message:    fmt.Sprintf("cc recipient '%s' not found in config", ccKey),
 				Suggestion: config.SuggestAddCCCommand(ccKey),
 			}
 			return
 		}
+		if ccErr != nil {
+			err = fmt.Errorf("cc recipient %q: %w", ccKey, ccErr)
+			return
+		}
+		if ambiguous && input.NonInteractive {
+			err = fmt.Errorf("cc recipient %q: %w: it matches %d recipients - use last name to disambiguate",
+				ccKey, notification.ErrAmbiguousRecipient, len(ccMatches))
+			return
+		}
 		ccRecipients = append(ccRecipients, ccMatches...)
 	}
+	ccRecipients = config.DedupeCC(ccRecipients, recipients)
 
 	// Lookup sender
 	mgr := config.NewConfigManager(s.cfg, "")
 	if input.SenderKey != "" {
-		sender, senderErr := mgr.GetSender(input.SenderKey)
+		sender, senderErr := lookup.LookupSender(input.SenderKey)
+		if errors.Is(senderErr, notification.ErrAmbiguousRecipient) {
+			err = &ValidationError{Code: CodeSenderAmbiguous, Message: senderErr.Error()}
+			return
+		}
 		if senderErr != nil {
 			err = &ValidationError{
+				Code:       CodeSenderNotFound,
 				Message:    fmt.Sprintf("sender '%s' not found in config", input.SenderKey),
 				Suggestion: config.SuggestAddSenderCommand(input.SenderKey),
 			}
@@ -402,21 +1323,43 @@ func (s *Service) validateInputs(ctx context.Context, input Input) (sourcePath s
 		senderName = sender.Name
 	} else {
 		sender, senderErr := mgr.GetDefaultSender()
-		if senderErr != nil {
+		if errors.Is(senderErr, config.ErrNoDefaultSender) {
 			err = &ValidationError{
+				Code:       CodeNoDefaultSender,
 				Message:    "no default sender configured",
-				Suggestion: "Set senders.default_sender in config or use --sender flag",
+				Suggestion: "Set senders.default_sender in config, set this machine's sender with whoami --set-sender, or use --sender flag",
 			}
 			return
 		}
+		if senderErr != nil {
+			err = &ValidationError{Code: CodeSenderNotFound, Message: senderErr.Error()}
+			return
+		}
 		senderName = sender.Name
 	}
 
 	return
 }
 
-func (s *Service) trimVideo(ctx context.Context, sourcePath, startTime, endTime string) (*appvideo.TrimResult, error) {
-	trimService := appvideo.NewTrimService(s.trimmer, s.fileChecker, s.cfg.Paths.TrimmedDirectory)
+// audioTrack returns the audio stream to read from the source
+func (s *Service) audioTrack(input Input) int {
+	if input.AudioTrack != video.DefaultAudioTrack {
+		return input.AudioTrack
+	}
+	return s.cfg.Audio.Track
+}
+
+// mediaTags returns the title and scripture to write into the output files
+func mediaTags(input Input) video.MediaTags {
+	return video.MediaTags{Title: input.Title, Scripture: input.Scripture}
+}
+
+func (s *Service) trimVideo(ctx context.Context, sourcePath, startTime, endTime string, audioTrack int, tags video.MediaTags, chapters []video.Chapter, overwrite appvideo.OverwriteOptions) (*appvideo.TrimResult, error) {
+	watermark, err := s.cfg.Video.Watermark.Style()
+	if err != nil {
+		return nil, fmt.Errorf("invalid video.watermark: %w", err)
+	}
+	trimService := appvideo.NewTrimService(s.trimmer, s.fileChecker, s.cfg.Paths.TrimmedDirectory, appvideo.WithOverwrite(overwrite), appvideo.WithAudioTrack(audioTrack), appvideo.WithCalendar(s.calendar), appvideo.WithTags(tags), appvideo.WithWatermark(watermark), appvideo.WithChapters(chapters))
 	return trimService.Trim(ctx, appvideo.TrimInput{
 		SourcePath: sourcePath,
 		StartTime:  startTime,
@@ -424,12 +1367,21 @@ func (s *Service) trimVideo(ctx context.Context, sourcePath, startTime, endTime
 	})
 }
 
-func (s *Service) extractAudio(ctx context.Context, videoPath string, serviceDate time.Time) (*appvideo.ExtractResult, error) {
-	bitrate := s.cfg.Audio.Bitrate
+// chapterList describes chapters, e.g. "Welcome (00:05:30), Sermon (00:40:00)"
+func chapterList(chapters []video.Chapter) string {
+	names := make([]string, len(chapters))
+	for i, c := range chapters {
+		names[i] = fmt.Sprintf("%s (%s)", c.Title, c.At)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (s *Service) extractAudio(ctx context.Context, videoPath string, serviceDate time.Time, tags video.MediaTags, overwrite appvideo.OverwriteOptions) (*appvideo.ExtractResult, error) {
+	bitrate := s.cfg.Audio.Bitrate.String()
 	if bitrate == "" {
 		bitrate = video.DefaultAudioBitrate
 	}
-	extractService := appvideo.NewExtractService(s.extractor, s.fileChecker, s.cfg.Paths.AudioDirectory, bitrate)
+	extractService := appvideo.NewExtractService(s.extractor, s.fileChecker, s.cfg.Paths.AudioDirectory, bitrate, appvideo.WithOverwrite(overwrite), appvideo.WithTags(tags), appvideo.WithExtraBitrates(s.cfg.Audio.ExtraBitrates()))
 	return extractService.Extract(ctx, appvideo.ExtractInput{
 		SourcePath:  videoPath,
 		ServiceDate: serviceDate,
@@ -437,12 +1389,12 @@ func (s *Service) extractAudio(ctx context.Context, videoPath string, serviceDat
 	})
 }
 
-func (s *Service) extractAudioWithTimestamps(ctx context.Context, sourcePath string, serviceDate time.Time, startTime, endTime string) (*appvideo.ExtractResult, error) {
-	bitrate := s.cfg.Audio.Bitrate
+func (s *Service) extractAudioWithTimestamps(ctx context.Context, sourcePath string, serviceDate time.Time, startTime, endTime string, audioTrack int, tags video.MediaTags, overwrite appvideo.OverwriteOptions) (*appvideo.ExtractResult, error) {
+	bitrate := s.cfg.Audio.Bitrate.String()
 	if bitrate == "" {
 		bitrate = video.DefaultAudioBitrate
 	}
-	extractService := appvideo.NewExtractService(s.extractor, s.fileChecker, s.cfg.Paths.AudioDirectory, bitrate)
+	extractService := appvideo.NewExtractService(s.extractor, s.fileChecker, s.cfg.Paths.AudioDirectory, bitrate, appvideo.WithOverwrite(overwrite), appvideo.WithAudioTrack(audioTrack), appvideo.WithTags(tags), appvideo.WithExtraBitrates(s.cfg.Audio.ExtraBitrates()))
 	return extractService.ExtractWithTimestamps(ctx, appvideo.ExtractWithTimestampsInput{
 		SourcePath:  sourcePath,
 		ServiceDate: serviceDate,
@@ -452,44 +1404,623 @@ func (s *Service) extractAudioWithTimestamps(ctx context.Context, sourcePath str
 	})
 }
 
-func (s *Service) ensureStorage(ctx context.Context, neededBytes int64) (*distribution.CleanupResult, error) {
-	cleanupService := appdist.NewCleanupService(s.driveClient, s.cfg.Google.ServicesFolderID)
-	return cleanupService.EnsureSpaceAvailable(ctx, neededBytes)
+// outputLabel describes whether a step produced a new file or kept an existing one
+func outputLabel(reused bool) string {
+	if reused {
+		return "Reused existing"
+	}
+	return "Created"
+}
+
+// printAudioVariants lists the extra MP3s made at audio.bitrates
+func (s *Service) printAudioVariants(variants []appvideo.AudioVariant) {
+	for _, v := range variants {
+		fmt.Fprintf(s.output, "      %s: %s (%s)\n", outputLabel(v.Reused), v.OutputPath, v.Bitrate)
+	}
+}
+
+// variantsSize is the combined size of the extra MP3s
+func (s *Service) variantsSize(variants []appvideo.AudioVariant) int64 {
+	var total int64
+	for _, v := range variants {
+		total += s.fileSizer.Size(v.OutputPath)
+	}
+	return total
+}
+
+// variantFiles lists the extra MP3s for the run summary
+func (s *Service) variantFiles(variants []appvideo.AudioVariant) []summary.File {
+	var files []summary.File
+	for _, v := range variants {
+		files = append(files, summary.File{Kind: "Audio " + v.Bitrate, Path: v.OutputPath, Size: s.fileSizer.Size(v.OutputPath)})
+	}
+	return files
+}
+
+// uploadAudioVariants uploads the extra MP3s and returns their email links,
+// labeled by bitrate and size. The main MP3 is already up, so a version that
+// fails to upload is reported and left out of the email.
+func (s *Service) uploadAudioVariants(ctx context.Context, variants []appvideo.AudioVariant) []notification.AudioVersion {
+	var versions []notification.AudioVersion
+	for _, v := range variants {
+		upload, err := s.uploadAudio(ctx, v.OutputPath)
+		if err != nil {
+			fmt.Fprintf(s.output, "      Warning: %s was not uploaded and is left out of the email: %v\n", filepath.Base(v.OutputPath), err)
+			continue
+		}
+		fmt.Fprintf(s.output, "      Uploaded: %s\n", filepath.Base(v.OutputPath))
+		versions = append(versions, notification.AudioVersion{
+			Label: fmt.Sprintf("%s, %s", v.Bitrate, distribution.FormatSize(s.fileSizer.Size(v.OutputPath))),
+			URL:   upload.ShareableURL,
+		})
+	}
+	return versions
+}
+
+func (s *Service) cleanupService() *appdist.CleanupService {
+	opts := []appdist.CleanupOption{
+		appdist.WithCleanupConcurrency(s.cfg.Google.CleanupConcurrency),
+		appdist.WithCleanupProgress(func(p distribution.CleanupProgress) {
+			fmt.Fprintf(s.output, "      %s\n", p)
+		}),
+	}
+	if s.publisher != nil {
+		opts = append(opts, appdist.WithArchive(distribution.MirrorArchive(s.publisher)))
+	}
+	return appdist.NewCleanupService(s.driveClient, s.cfg.Google.ServicesFolderID, opts...)
 }
 
 func (s *Service) uploadVideo(ctx context.Context, videoPath string) (*distribution.UploadResult, error) {
-	uploadService := appdist.NewUploadService(s.driveClient, s.cfg.Google.ServicesFolderID, s.output)
+	uploadService := appdist.NewUploadService(s.driveClient, s.folderID, s.output, s.shareOptions()...)
 	return uploadService.UploadVideo(ctx, videoPath)
 }
 
 func (s *Service) uploadAudio(ctx context.Context, audioPath string) (*distribution.UploadResult, error) {
-	uploadService := appdist.NewUploadService(s.driveClient, s.cfg.Google.ServicesFolderID, s.output)
+	uploadService := appdist.NewUploadService(s.driveClient, s.folderID, s.output, s.shareOptions()...)
 	return uploadService.UploadAudio(ctx, audioPath)
 }
 
-func (s *Service) sendEmail(recipients, ccRecipients []notification.Recipient, serviceDate time.Time, ministerName, senderName, audioURL, videoURL string) error {
-	notifService := appnotif.NewService(s.emailSender, s.cfg.Email.FromName, senderName)
-	return notifService.Send(appnotif.SendRequest{
+// shareOptions applies the pre-share scan, file system and private sharing,
+// if any, to uploads
+func (s *Service) shareOptions() []appdist.ShareOption {
+	opts := s.privateShareOptions()
+	if s.scanner != nil {
+		opts = append(opts, appdist.WithScanner(s.scanner))
+	}
+	if s.fs != nil {
+		opts = append(opts, appdist.WithFS(s.fs))
+	}
+	return opts
+}
+
+// sentEmail is a notification that was sent
+type sentEmail struct {
+	CC         []notification.Recipient   // Intended CCs, including those added by CC rules
+	Request    *notification.EmailRequest // As sent, after sandbox rerouting
+	MessageIDs []string                   // One per recipient group, for threading a correction
+}
+
+// sendEmail sends the notification and returns what was sent
+func (s *Service) sendEmail(ctx context.Context, input Input, recipients, ccRecipients []notification.Recipient, serviceDate time.Time, ministerName, senderName, audioURL, videoURL string, audioVersions []notification.AudioVersion, mirror mirrorLinks) (*sentEmail, error) {
+	if s.reviewRecipients != nil {
+		var err error
+		recipients, ccRecipients, err = s.reviewRecipientList(recipients, ccRecipients)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ok, err := s.checkpoint("send the email to " + strings.Join(formatRecipients(recipients), ", "))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("not sent: %w", ErrStepDeclined)
+	}
+	subject, err := notification.ParseSubjectTemplate(s.cfg.Email.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email.subject: %w", err)
+	}
+	ccRules, err := config.NewRecipientLookup(s.cfg, "").CCRules()
+	if err != nil {
+		return nil, fmt.Errorf("invalid email.cc_rules: %w", err)
+	}
+	groups, err := config.NewRecipientLookup(s.cfg, "").Groups()
+	if err != nil {
+		return nil, fmt.Errorf("invalid email.groups: %w", err)
+	}
+	greeting, err := s.cfg.Email.Greeting.Rules()
+	if err != nil {
+		return nil, fmt.Errorf("invalid email.greeting: %w", err)
+	}
+	serviceType := input.ServiceType
+	if serviceType == "" {
+		serviceType = s.cfg.Email.ServiceType
+	}
+
+	opts := []appnotif.Option{
+		appnotif.WithSubjectTemplate(subject),
+		appnotif.WithFolderLink(s.emailFolderURL()),
+		appnotif.WithLivestreamLink(s.cfg.Email.LivestreamURL),
+		appnotif.WithGreeting(greeting),
+		appnotif.WithSendTimeout(s.cfg.Email.SendTimeout()),
+	}
+	if s.policy.CCRules {
+		opts = append(opts, appnotif.WithCCRules(ccRules))
+	}
+	if s.policy.Private {
+		opts = append(opts, appnotif.WithPrivate(s.expiry.RevokeAfter))
+	} else {
+		opts = append(opts, appnotif.WithRecipientGroups(groups))
+	}
+	if s.sandboxed(input) {
+		operator, err := config.NewRecipientLookup(s.cfg, "").Operator()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, appnotif.WithSandbox(operator))
+		fmt.Fprintf(s.output, "      Sandbox: sending only to %s <%s>\n", operator.Name, operator.Address)
+	}
+
+	notifService := appnotif.NewService(s.emailSender, s.cfg.Email.FromName, senderName, opts...)
+	req := appnotif.SendRequest{
 		To:           recipients,
 		CC:           ccRecipients,
 		ServiceDate:  serviceDate,
 		MinisterName: ministerName,
 		AudioURL:     audioURL,
 		VideoURL:     videoURL,
+		ServiceType:  serviceType,
+		Label:        input.Label,
+		Title:        input.Title,
+		Scripture:    input.Scripture,
+
+		ServiceEndDate: input.serviceEndDate,
+		AudioVersions:  audioVersions,
+		MirrorAudioURL: mirror.Audio,
+		MirrorVideoURL: mirror.Video,
+	}
+
+	cc, fired := notifService.ResolveCC(req)
+	for _, f := range fired {
+		fmt.Fprintf(s.output, "      CC rule %s: %s\n", f.Rule, strings.Join(f.Reasons, " and "))
+	}
+	results, err := notifService.SendGroups(ctx, req)
+	if len(results) > 1 {
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Fprintf(s.output, "      Group %s: failed: %v\n", r.Group, r.Err)
+			} else {
+				fmt.Fprintf(s.output, "      Group %s: sent to %d recipients\n", r.Group, len(r.To))
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	sent := &sentEmail{CC: cc, Request: notifService.BuildRequest(req)}
+	for _, r := range results {
+		if r.MessageID != "" {
+			sent.MessageIDs = append(sent.MessageIDs, r.MessageID)
+		}
+	}
+	return sent, nil
+}
+
+// archiveSummary writes the run summary, if an archive is configured. The run
+// already succeeded, so a failure is only a warning.
+func (s *Service) archiveSummary(run summary.RunSummary, email *notification.EmailRequest) {
+	if s.summaries == nil {
+		return
+	}
+
+	run.Church = s.cfg.Email.FromName
+	run.ProcessedAt = time.Now()
+	var notes []string
+	for _, n := range run.Notes {
+		if n = strings.TrimSpace(n); n != "" {
+			notes = append(notes, n)
+		}
+	}
+	run.Notes = notes
+
+	if email != nil {
+		run.Email.Subject = email.Subject
+		run.Email.To = formatRecipients(email.To)
+		run.Email.CC = formatRecipients(email.CC)
+		data := notification.NewTemplateData(email, run.ProcessedAt.In(s.calendar.Location()))
+		run.Email.PlainText, _ = notification.DefaultTemplate.RenderPlainText(data)
+		run.Email.HTML, _ = notification.DefaultTemplate.RenderHTML(data)
+	}
+
+	paths, err := s.summaries.Save(run)
+	if err != nil {
+		fmt.Fprintf(s.output, "Warning: could not write run summary: %v\n", err)
+	}
+	for _, p := range paths {
+		fmt.Fprintf(s.output, "Summary: %s\n", p)
+	}
+}
+
+// summaryLinks lists the shareable links for the run summary, skipping empty ones
+func summaryLinks(videoURL, audioURL string, mirror mirrorLinks) []summary.Link {
+	var links []summary.Link
+	for _, l := range []summary.Link{
+		{Label: "Video", URL: videoURL},
+		{Label: "Audio", URL: audioURL},
+		{Label: "Video (mirror)", URL: mirror.Video},
+		{Label: "Audio (mirror)", URL: mirror.Audio},
+	} {
+		if l.URL != "" {
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+func formatRecipients(recipients []notification.Recipient) []string {
+	formatted := make([]string, len(recipients))
+	for i, r := range recipients {
+		formatted[i] = r.String()
+	}
+	return formatted
+}
+
+// ErrSimulatedFailure is returned by a step failed with WithSimulatedFailure
+var ErrSimulatedFailure = errors.New("simulated failure")
+
+// stepClock times the workflow steps for the run summary. It also numbers
+// them, so a simulated failure can be injected into one.
+type stepClock struct {
+	steps   []summary.Step
+	current string
+	started time.Time
+	number  int
+	failAt  int
+
+	// sampler, when set, samples resource use every interval during steps
+	// started with StartMeasured; measure stops it and returns the usage
+	sampler  resource.Sampler
+	interval time.Duration
+	measure  func() resource.Usage
+}
+
+// Start ends the current step, if any, and starts timing the next one
+func (c *stepClock) Start(name string) {
+	c.stop()
+	c.current = name
+	c.started = time.Now()
+	c.number++
+}
+
+// Run runs fn as the current step's work, unless a failure is being
+// simulated for this step
+func (c *stepClock) Run(fn func() error) error {
+	if c.failAt > 0 && c.number == c.failAt {
+		return fmt.Errorf("%w at step %d (%s)", ErrSimulatedFailure, c.number, c.current)
+	}
+	return fn()
+}
+
+// runStep is stepClock.Run for work that returns a result
+func runStep[T any](c *stepClock, fn func() (T, error)) (T, error) {
+	var result T
+	err := c.Run(func() error {
+		var err error
+		result, err = fn()
+		return err
 	})
+	return result, err
+}
+
+// Steps ends the current step and returns every step timed so far
+func (c *stepClock) Steps() []summary.Step {
+	c.stop()
+	return c.steps
+}
+
+func (c *stepClock) stop() {
+	if c.current == "" {
+		return
+	}
+	step := summary.Step{Name: c.current, Duration: time.Since(c.started)}
+	if c.measure != nil {
+		step.Usage = c.measure()
+		c.measure = nil
+	}
+	c.steps = append(c.steps, step)
+	c.current = ""
+}
+
+// printFolderLink shows where this run's uploads can be browsed in Drive
+func (s *Service) printFolderLink() {
+	if url := s.folderURL(); url != "" {
+		fmt.Fprintf(s.output, "      Folder link: %s\n", url)
+	}
+}
+
+// checkFolder makes sure the folder this run uploads to is a folder the
+// account can write to, and returns its name for the operator to confirm.
+// The name is "" when the storage cannot look folders up.
+func (s *Service) checkFolder(ctx context.Context) (string, error) {
+	folder, err := appdist.CheckFolder(ctx, s.driveClient, s.folderID)
+	if err == nil {
+		if folder == nil {
+			return "", nil
+		}
+		return folder.Name, nil
+	}
+	if !errors.Is(err, distribution.ErrFolderNotFound) && !errors.Is(err, distribution.ErrNotAFolder) &&
+		!errors.Is(err, distribution.ErrFolderNotWritable) {
+		return "", fmt.Errorf("failed to check the Drive folder: %w", err)
+	}
+	suggestion := "Set google.services_folder_id to the ID at the end of the folder's Drive URL, and check it is shared with this account as an editor"
+	if s.folderOverridden() {
+		suggestion = "Pass --folder-id the ID at the end of the folder's Drive URL, and check it is shared with this account as an editor"
+	}
+	return "", &ValidationError{
+		Code:       CodeDriveFolder,
+		Message:    fmt.Sprintf("Uploads cannot go to the Drive folder: %v", err),
+		Suggestion: suggestion,
+	}
+}
+
+// folderURL links the folder this run uploads to, or "" when there is none,
+// as when outputs are stored in S3
+func (s *Service) folderURL() string {
+	if s.folderID == s.cfg.Google.ServicesFolderID {
+		return s.cfg.ServicesFolderURL()
+	}
+	if s.cfg.UsesS3() {
+		return ""
+	}
+	return distribution.FolderURL(s.folderID)
+}
+
+// emailFolderURL is folderURL when email.include_folder_link is on
+func (s *Service) emailFolderURL() string {
+	if !s.cfg.Email.IncludeFolderLink {
+		return ""
+	}
+	return s.folderURL()
+}
+
+// folderArg passes this run's folder to a recovery upload command
+func (s *Service) folderArg() string {
+	if !s.folderOverridden() {
+		return ""
+	}
+	return " --folder-id " + s.folderID
+}
+
+// folderOverridden reports whether this run uploads outside the services folder
+func (s *Service) folderOverridden() bool {
+	return s.folderID != s.cfg.Google.ServicesFolderID
+}
+
+// sandboxed reports whether emails go only to the operator
+func (s *Service) sandboxed(input Input) bool {
+	return input.Sandbox || s.cfg.Email.Sandbox
+}
+
+// recordHistory adds the finished run to the history store, if one is
+// configured. The run already succeeded, so a failure is only a warning.
+func (s *Service) recordHistory(input Input, sourcePath string, serviceDate time.Time, ministerName string, recipients []notification.Recipient, email *sentEmail, videoPath string, audioSize int64, videoUpload, audioUpload *distribution.UploadResult, steps []summary.Step) {
+	if s.history == nil {
+		return
+	}
+
+	entry := history.Entry{
+		ServiceDate:     serviceDate,
+		ProcessedAt:     time.Now(),
+		Minister:        ministerName,
+		Title:           input.Title,
+		Scripture:       input.Scripture,
+		SourceFile:      filepath.Base(sourcePath),
+		StartTime:       input.StartTime,
+		EndTime:         input.EndTime,
+		DurationSeconds: trimmedSeconds(input.StartTime, input.EndTime),
+		AudioSize:       audioSize,
+		Outcome:         history.OutcomeSuccess,
+
+		DetectionConfidence: input.DetectionConfidence,
+		CameraAngle:         input.CameraAngle,
+		DetectionEarlyExit:  input.DetectionEarlyExit,
+		FFmpegVersion:       input.FFmpegVersion,
+		Usage:               stepUsage(steps),
+	}
+	if s.folderOverridden() {
+		entry.FolderID = s.folderID
+	}
+	if videoPath != "" {
+		entry.VideoSize = s.fileSizer.Size(videoPath)
+	}
+	if videoUpload != nil {
+		entry.VideoFileID = videoUpload.FileID
+		entry.VideoURL = videoUpload.ShareableURL
+	}
+	if audioUpload != nil {
+		entry.AudioFileID = audioUpload.FileID
+		entry.AudioURL = audioUpload.ShareableURL
+	}
+	for _, r := range append(append([]notification.Recipient{}, recipients...), email.CC...) {
+		entry.Recipients = append(entry.Recipients, r.Address)
+	}
+	entry.EmailSubject = email.Request.Subject
+	entry.EmailMessageIDs = email.MessageIDs
+	for _, text := range input.Notes {
+		if note, err := history.NewNote(text, entry.ProcessedAt); err == nil {
+			entry.Notes = append(entry.Notes, note)
+		}
+	}
+
+	if err := s.history.Append(entry); err != nil {
+		fmt.Fprintf(s.output, "Warning: could not record history: %v\n\n", err)
+		return
+	}
+	if entry.DetectionConfidence > 0 {
+		s.warnDetectionDrift()
+	}
+}
+
+// checkGeometry warns when the source's frame size or aspect is not what the
+// video config expects, e.g. OBS recording a stretched 720x480 canvas. With
+// strict, a mismatch stops the run before an hour is spent uploading it.
+func (s *Service) checkGeometry(ctx context.Context, sourcePath string, strict bool) error {
+	if s.geometry == nil {
+		return nil
+	}
+	expect, err := s.cfg.Video.Expectation()
+	if err != nil {
+		return fmt.Errorf("invalid video config: %w", err)
+	}
+	g, err := s.geometry.Geometry(ctx, sourcePath)
+	if err != nil {
+		fmt.Fprintf(s.output, "Warning: could not check the video size: %v\n", err)
+		return nil
+	}
+	problems := expect.Problems(g)
+	if len(problems) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("%s looks misrecorded: %s\nCheck the OBS output resolution, or run without --strict to upload it anyway",
+			filepath.Base(sourcePath), strings.Join(problems, "; "))
+	}
+	for _, p := range problems {
+		fmt.Fprintf(s.output, "Warning: %s\n", p)
+	}
+	fmt.Fprintf(s.output, "  Check the OBS output resolution; use --strict to stop instead of uploading\n")
+	return nil
+}
+
+// warnDetectionDrift warns when start detection scores have stayed low for
+// several weeks, before detection starts failing outright
+func (s *Service) warnDetectionDrift() {
+	entries, err := s.history.List()
+	if err != nil {
+		return
+	}
+	drift := history.NewDrift(entries, s.cfg.Detection.DriftThreshold(), s.cfg.Detection.Drift.Weeks)
+	if !drift.Alert() {
+		return
+	}
+	fmt.Fprintf(s.output, "Warning: start detection scores have been below %.0f%% for %d services in a row.\n", drift.Threshold*100, drift.LowStreak())
+	fmt.Fprintf(s.output, "  Re-capture the detection templates; see 'nac-service-media detect stats'\n\n")
+}
+
+// printNotes repeats the operator's notes in the completion summary
+func (s *Service) printNotes(notes []string) {
+	var printed bool
+	for _, text := range notes {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		if !printed {
+			fmt.Fprintf(s.output, "Notes:\n")
+			printed = true
+		}
+		fmt.Fprintf(s.output, "  - %s\n", text)
+	}
+}
+
+// trimmedSeconds returns the length between two HH:MM:SS timestamps, or 0 if
+// either is missing or invalid
+func trimmedSeconds(start, end string) int {
+	st, err := video.ParseTimestamp(start)
+	if err != nil {
+		return 0
+	}
+	et, err := video.ParseTimestamp(end)
+	if err != nil || !st.Before(et) {
+		return 0
+	}
+	return et.TotalSeconds() - st.TotalSeconds()
+}
+
+// mirrorLinks are download URLs on the alternate download server
+type mirrorLinks struct {
+	Video string
+	Audio string
+}
+
+// publishMirror copies outputs to the alternate download server, if one is
+// configured and the service is not shared privately. Failures are reported but don't stop the run since the Drive
+// links already work; the email then omits the mirror section.
+func (s *Service) publishMirror(ctx context.Context, serviceDate time.Time, videoPath, audioPath string) mirrorLinks {
+	if s.publisher == nil || s.sharedPrivately() {
+		return mirrorLinks{}
+	}
+
+	var publishOpts []appdist.PublishOption
+	if s.fs != nil {
+		publishOpts = append(publishOpts, appdist.WithPublishFS(s.fs))
+	}
+	publishService := appdist.NewPublishService(s.publisher, s.output, publishOpts...)
+	var links mirrorLinks
+	for _, f := range []struct {
+		path string
+		url  *string
+	}{{videoPath, &links.Video}, {audioPath, &links.Audio}} {
+		if f.path == "" {
+			continue
+		}
+		published, err := publishService.Publish(ctx, f.path)
+		if err != nil {
+			fmt.Fprintf(s.output, "      Warning: could not publish to mirror: %v\n", err)
+			fmt.Fprintf(s.output, "        Retry with: nac-service-media publish --date %s\n", serviceDate.Format("2006-01-02"))
+			return mirrorLinks{}
+		}
+		fmt.Fprintf(s.output, "      Mirror link: %s\n", published.URL)
+		*f.url = published.URL
+	}
+	return links
+}
+
+// warnSharingPending tells the operator how to finish sharing when it failed
+// after upload. The email is still sent since the links work once shared.
+func (s *Service) warnSharingPending(pending bool, serviceDate time.Time) {
+	if !pending {
+		return
+	}
+	fmt.Fprintf(s.output, "      Warning: sharing is incomplete; recipients can't open the links until you run:\n")
+	fmt.Fprintf(s.output, "        nac-service-media drive share --date %s%s\n", serviceDate.Format("2006-01-02"), s.folderArg())
+}
+
+// recoveryState holds values already produced when a step fails, so the
+// printed recovery commands can be copied and run as-is
+type recoveryState struct {
+	TrimmedPath  string
+	AudioPath    string
+	Video        *distribution.UploadResult
+	Audio        *distribution.UploadResult
+	MinisterName string
+	// NeededBytes is the Drive space a failed storage check asked for
+	NeededBytes int64
 }
 
-func (s *Service) showRecoveryCommands(failedStep int, input Input, sourcePath string, serviceDate time.Time) {
+func (s *Service) showRecoveryCommands(failedStep int, input Input, sourcePath string, serviceDate time.Time, known recoveryState) {
 	fmt.Fprintln(s.output)
+	s.showUploadedFiles(known)
+	s.showResumeCommand(serviceDate)
+	if s.showPrivateRecovery() {
+		return
+	}
 	fmt.Fprintln(s.output, "To complete manually:")
 
 	dateStr := serviceDate.Format("2006-01-02")
-	trimmedPath := filepath.Join(s.cfg.Paths.TrimmedDirectory, dateStr+".mp4")
-	audioPath := filepath.Join(s.cfg.Paths.AudioDirectory, dateStr+".mp3")
+	trimmedPath := known.TrimmedPath
+	if trimmedPath == "" {
+		trimmedPath = filepath.Join(s.cfg.Paths.TrimmedDirectory, dateStr+".mp4")
+	}
+	audioPath := known.AudioPath
+	if audioPath == "" {
+		audioPath = filepath.Join(s.cfg.Paths.AudioDirectory, dateStr+".mp3")
+	}
 
 	step := 1
 	if failedStep <= 1 {
-		fmt.Fprintf(s.output, "  %d. Trim:       nac-service-media trim --source %q --start %s --end %s\n", step, sourcePath, input.StartTime, input.EndTime)
+		fmt.Fprintf(s.output, "  %d. Trim:       nac-service-media trim --source %q --start %s --end %s%s\n", step, sourcePath, input.StartTime, input.EndTime, audioTrackArg(input))
 		step++
 	}
 	if failedStep <= 2 {
@@ -497,73 +2028,140 @@ func (s *Service) showRecoveryCommands(failedStep int, input Input, sourcePath s
 		step++
 	}
 	if failedStep <= 3 {
-		fmt.Fprintf(s.output, "  %d. Auth:       nac-service-media auth drive\n", step)
-		step++
-		fmt.Fprintf(s.output, "  %d. Cleanup:    nac-service-media cleanup --ensure-space 2GB\n", step)
+		step = s.showStorageRecovery(step, known)
+		fmt.Fprintf(s.output, "  %d. Auth:       nac-service-media auth status --fix\n", step)
 		step++
 	}
 	if failedStep <= 4 {
-		fmt.Fprintf(s.output, "  %d. Upload:     nac-service-media upload --video %q --audio %q\n", step, trimmedPath, audioPath)
+		fmt.Fprintf(s.output, "  %d. Upload:     nac-service-media upload --video %q --audio %q%s%s\n", step, trimmedPath, audioPath, targetArg(input), s.folderArg())
+		step++
+	} else if failedStep <= 5 {
+		fmt.Fprintf(s.output, "  %d. Upload:     nac-service-media upload --audio-only --audio %q%s\n", step, audioPath, s.folderArg())
 		step++
 	}
 	if failedStep <= 7 {
-		recipientArgs := ""
-		for _, r := range input.RecipientKeys {
-			recipientArgs += fmt.Sprintf(" --to %s", r)
-		}
-		fmt.Fprintf(s.output, "  %d. Email:      nac-service-media send-email%s --date %s --minister %q --audio-url <URL> --video-url <URL>\n", step, recipientArgs, dateStr, input.MinisterKey)
+		fmt.Fprintf(s.output, "  %d. Email:      nac-service-media send-email%s\n", step, s.sendEmailArgs(input, dateStr, known, true))
 	}
 	fmt.Fprintln(s.output)
 }
 
-func (s *Service) showRecoveryCommandsAudioOnly(failedStep int, input Input, sourcePath string, serviceDate time.Time) {
+func (s *Service) showRecoveryCommandsAudioOnly(failedStep int, input Input, sourcePath string, serviceDate time.Time, known recoveryState) {
 	fmt.Fprintln(s.output)
+	s.showUploadedFiles(known)
+	s.showResumeCommand(serviceDate)
+	if s.showPrivateRecovery() {
+		return
+	}
 	fmt.Fprintln(s.output, "To complete manually:")
 
 	dateStr := serviceDate.Format("2006-01-02")
-	audioPath := filepath.Join(s.cfg.Paths.AudioDirectory, dateStr+".mp3")
+	audioPath := known.AudioPath
+	if audioPath == "" {
+		audioPath = filepath.Join(s.cfg.Paths.AudioDirectory, dateStr+".mp3")
+	}
 
 	step := 1
 	if failedStep <= 1 {
-		fmt.Fprintf(s.output, "  %d. Extract:    nac-service-media extract-audio --source %q --start %s --end %s\n", step, sourcePath, input.StartTime, input.EndTime)
+		fmt.Fprintf(s.output, "  %d. Extract:    nac-service-media extract-audio --source %q --start %s --end %s%s\n", step, sourcePath, input.StartTime, input.EndTime, audioTrackArg(input))
 		step++
 	}
 	if failedStep <= 2 {
-		fmt.Fprintf(s.output, "  %d. Auth:       nac-service-media auth drive\n", step)
-		step++
-		fmt.Fprintf(s.output, "  %d. Cleanup:    nac-service-media cleanup --ensure-space 200MB\n", step)
+		step = s.showStorageRecovery(step, known)
+		fmt.Fprintf(s.output, "  %d. Auth:       nac-service-media auth status --fix\n", step)
 		step++
 	}
 	if failedStep <= 3 {
-		fmt.Fprintf(s.output, "  %d. Upload:     nac-service-media upload --audio %q\n", step, audioPath)
+		fmt.Fprintf(s.output, "  %d. Upload:     nac-service-media upload --audio-only --audio %q%s\n", step, audioPath, s.folderArg())
 		step++
 	}
 	if failedStep <= 4 {
-		recipientArgs := ""
-		for _, r := range input.RecipientKeys {
-			recipientArgs += fmt.Sprintf(" --to %s", r)
-		}
-		fmt.Fprintf(s.output, "  %d. Email:      nac-service-media send-email%s --date %s --minister %q --audio-url <URL>\n", step, recipientArgs, dateStr, input.MinisterKey)
+		fmt.Fprintf(s.output, "  %d. Email:      nac-service-media send-email%s\n", step, s.sendEmailArgs(input, dateStr, known, false))
+	}
+	fmt.Fprintln(s.output)
+}
+
+// showStorageRecovery suggests a drive cleanup when the storage check failed
+// and returns the next step number
+func (s *Service) showStorageRecovery(step int, known recoveryState) int {
+	if known.NeededBytes <= 0 {
+		return step
+	}
+	// Round up to whole megabytes so the cleanup frees at least what was needed
+	mb := (known.NeededBytes + 1<<20 - 1) >> 20
+	fmt.Fprintf(s.output, "  %d. Storage:    nac-service-media drive cleanup --ensure-space %dMB\n", step, mb)
+	return step + 1
+}
+
+// showUploadedFiles lists uploads that completed before the failure
+func (s *Service) showUploadedFiles(known recoveryState) {
+	if known.Video == nil && known.Audio == nil {
+		return
+	}
+	fmt.Fprintln(s.output, "Already uploaded:")
+	if known.Video != nil {
+		fmt.Fprintf(s.output, "  Video: %s (file ID %s)\n", known.Video.ShareableURL, known.Video.FileID)
+	}
+	if known.Audio != nil {
+		fmt.Fprintf(s.output, "  Audio: %s (file ID %s)\n", known.Audio.ShareableURL, known.Audio.FileID)
 	}
 	fmt.Fprintln(s.output)
 }
 
-// inferDateFromFilename extracts date from OBS-style filenames
-// Supports: "2025-12-28 10-06-16.mp4" or "2025-12-28.mp4"
-func inferDateFromFilename(filename string) (time.Time, error) {
-	// Pattern for OBS format: YYYY-MM-DD HH-MM-SS.mp4
-	obsPattern := regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+\d{2}-\d{2}-\d{2}\.mp4$`)
-	if matches := obsPattern.FindStringSubmatch(filename); len(matches) > 1 {
-		return time.Parse("2006-01-02", matches[1])
+// audioTrackArg repeats an explicit --audio-track in recovery commands
+func audioTrackArg(input Input) string {
+	if input.AudioTrack == video.DefaultAudioTrack {
+		return ""
+	}
+	return fmt.Sprintf(" --audio-track %d", input.AudioTrack)
+}
+
+// sendEmailArgs builds send-email flags, filling in URLs that are already known.
+// URLs still marked <URL> come from the output of the upload step above.
+func (s *Service) sendEmailArgs(input Input, dateStr string, known recoveryState, includeVideo bool) string {
+	var args strings.Builder
+	for _, r := range input.RecipientKeys {
+		fmt.Fprintf(&args, " --to %s", r)
+	}
+	for _, x := range input.ExcludeKeys {
+		fmt.Fprintf(&args, " --exclude %s", x)
+	}
+	fmt.Fprintf(&args, " --date %s", dateStr)
+	if known.MinisterName != "" {
+		fmt.Fprintf(&args, " --minister %q", known.MinisterName)
+	}
+	if input.SenderKey != "" {
+		fmt.Fprintf(&args, " --sender %s", input.SenderKey)
+	}
+	if input.ServiceType != "" {
+		fmt.Fprintf(&args, " --service-type %q", input.ServiceType)
+	}
+	if input.Label != "" {
+		fmt.Fprintf(&args, " --label %q", input.Label)
+	}
+	if input.Title != "" {
+		fmt.Fprintf(&args, " --title %q", input.Title)
+	}
+	if input.Scripture != "" {
+		fmt.Fprintf(&args, " --scripture %q", input.Scripture)
+	}
+	if input.Sandbox {
+		args.WriteString(" --sandbox")
 	}
 
-	// Pattern for trimmed format: YYYY-MM-DD.mp4
-	trimmedPattern := regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\.mp4$`)
-	if matches := trimmedPattern.FindStringSubmatch(filename); len(matches) > 1 {
-		return time.Parse("2006-01-02", matches[1])
+	audioURL := "<URL>"
+	if known.Audio != nil {
+		audioURL = fmt.Sprintf("%q", known.Audio.ShareableURL)
 	}
+	fmt.Fprintf(&args, " --audio-url %s", audioURL)
 
-	return time.Time{}, fmt.Errorf("filename does not match expected format")
+	if includeVideo {
+		videoURL := "<URL>"
+		if known.Video != nil {
+			videoURL = fmt.Sprintf("%q", known.Video.ShareableURL)
+		}
+		fmt.Fprintf(&args, " --video-url %s", videoURL)
+	}
+	return args.String()
 }
 
 func (s *Service) computeCleanupInput(skipVideo bool, sourcePath string, serviceDate time.Time) CleanupInput {
@@ -596,7 +2194,8 @@ func (s *Service) cleanupLocalFiles(input CleanupInput, threshold float64, label
 		return
 	}
 
-	usage, err := s.diskChecker.UsagePercent(s.cfg.Paths.SourceDirectory)
+	sourceDir := domainfs.SourceDirectoryOf(s.cfg.Paths.Sources(), input.SourcePath)
+	usage, err := s.diskChecker.UsagePercent(sourceDir)
 	if err != nil {
 		fmt.Fprintf(s.output, "\nNote: %s disk check failed: %v\n", label, err)
 		return
@@ -611,7 +2210,7 @@ func (s *Service) cleanupLocalFiles(input CleanupInput, threshold float64, label
 	dateStr := input.ServiceDate.Format("2006-01-02")
 
 	// Delete oldest source recording
-	if err := s.deleteOldestFile(s.cfg.Paths.SourceDirectory, ".mp4", input.SourcePath); err != nil {
+	if err := s.deleteOldestFile(sourceDir, ".mp4", input.SourcePath); err != nil {
 		fmt.Fprintf(s.output, "  Warning: source cleanup: %v\n", err)
 	}
 
@@ -628,6 +2227,34 @@ func (s *Service) cleanupLocalFiles(input CleanupInput, threshold float64, label
 			fmt.Fprintf(s.output, "  Warning: trimmed cleanup: %v\n", err)
 		}
 	}
+
+	s.pruneArchive()
+}
+
+// pruneArchive drops archived copies past the retention window
+func (s *Service) pruneArchive() {
+	if s.archiver == nil {
+		return
+	}
+	pruned, err := s.archiver.Prune()
+	for _, path := range pruned {
+		fmt.Fprintf(s.output, "  Pruned archive: %s\n", filepath.Base(path))
+	}
+	if err != nil {
+		fmt.Fprintf(s.output, "  Warning: archive pruning: %v\n", err)
+	}
+}
+
+// archiveKind returns what local cleanup is deleting from dir
+func (s *Service) archiveKind(dir string) string {
+	switch dir {
+	case s.cfg.Paths.TrimmedDirectory:
+		return domainfs.ArchiveTrimmed
+	case s.cfg.Paths.AudioDirectory:
+		return domainfs.ArchiveAudio
+	default:
+		return domainfs.ArchiveSource
+	}
 }
 
 func (s *Service) deleteOldestFile(dir, ext, excludePath string) error {
@@ -642,6 +2269,22 @@ func (s *Service) deleteOldestFile(dir, ext, excludePath string) error {
 		if f == excludePath {
 			continue
 		}
+		ok, err := s.checkpoint("delete " + f)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintf(s.output, "  Kept: %s\n", filepath.Base(f))
+			return nil
+		}
+		// A file that cannot be archived is kept rather than lost
+		if s.archiver != nil && s.retention.Includes(s.archiveKind(dir)) {
+			archived, err := s.archiver.Archive(f)
+			if err != nil {
+				return fmt.Errorf("archive %s: %w", filepath.Base(f), err)
+			}
+			fmt.Fprintf(s.output, "  Archived: %s -> %s\n", filepath.Base(f), archived)
+		}
 		if err := s.fileRemover.Remove(f); err != nil {
 			return fmt.Errorf("delete %s: %w", filepath.Base(f), err)
 		}
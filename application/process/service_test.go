@@ -4,11 +4,18 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"nac-service-media/domain/audit"
 	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
 	"nac-service-media/domain/notification"
+	"nac-service-media/domain/resource"
+	"nac-service-media/domain/runstate"
 	"nac-service-media/domain/video"
 	"nac-service-media/infrastructure/config"
 )
@@ -69,6 +76,7 @@ type mockDriveClient struct {
 	findFileByNameErrs map[string]error                  // per-file errors for FindFileByName
 	uploadErr          error
 	storageInfo        *distribution.StorageInfo
+	deletedIDs         []string
 }
 
 func newMockDriveClient() *mockDriveClient {
@@ -107,6 +115,16 @@ func (m *mockDriveClient) FindFileByName(ctx context.Context, folderID, fileName
 	return nil, nil // Not found is not an error
 }
 
+func (m *mockDriveClient) FindFilesByProperty(ctx context.Context, folderID, key, value string) ([]distribution.FileInfo, error) {
+	result := []distribution.FileInfo{}
+	for _, f := range m.files {
+		if f.AppProperties[key] == value {
+			result = append(result, *f)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockDriveClient) GetStorageQuota(ctx context.Context) (*distribution.StorageInfo, error) {
 	return m.storageInfo, nil
 }
@@ -122,6 +140,7 @@ func (m *mockDriveClient) ListMP4Files(ctx context.Context, folderID string) ([]
 }
 
 func (m *mockDriveClient) DeletePermanently(ctx context.Context, fileID string) error {
+	m.deletedIDs = append(m.deletedIDs, fileID)
 	return nil
 }
 
@@ -164,7 +183,7 @@ type mockEmailSender struct {
 	failError  error
 }
 
-func (m *mockEmailSender) Send(req *notification.EmailRequest) error {
+func (m *mockEmailSender) Send(ctx context.Context, req *notification.EmailRequest) error {
 	if m.shouldFail {
 		return m.failError
 	}
@@ -723,3 +742,705 @@ func TestComputeCleanupInput_AudioOnlyMode(t *testing.T) {
 		t.Error("expected SkipVideo=true")
 	}
 }
+
+func TestStepClock_SimulatedFailure(t *testing.T) {
+	steps := &stepClock{failAt: 2}
+
+	ran := 0
+	work := func() (string, error) {
+		ran++
+		return "done", nil
+	}
+
+	steps.Start("Trim video")
+	if got, err := runStep(steps, work); err != nil || got != "done" {
+		t.Fatalf("step 1 = %q, %v; want done, nil", got, err)
+	}
+
+	steps.Start("Extract audio")
+	_, err := runStep(steps, work)
+	if !errors.Is(err, ErrSimulatedFailure) {
+		t.Fatalf("step 2 error = %v, want ErrSimulatedFailure", err)
+	}
+	if err.Error() != "simulated failure at step 2 (Extract audio)" {
+		t.Errorf("step 2 error = %q", err.Error())
+	}
+	if ran != 1 {
+		t.Errorf("work ran %d times, want 1 (the failed step must not run)", ran)
+	}
+
+	steps.Start("Check Drive storage")
+	if err := steps.Run(func() error { return nil }); err != nil {
+		t.Errorf("step 3 error = %v, want nil", err)
+	}
+}
+
+func TestStepClock_NoSimulatedFailure(t *testing.T) {
+	steps := &stepClock{}
+	steps.Start("Trim video")
+	want := errors.New("ffmpeg failed")
+	if err := steps.Run(func() error { return want }); err != want {
+		t.Errorf("Run() = %v, want the step's own error", err)
+	}
+}
+
+// mockArchiver implements filesystem.Archiver for testing
+type mockArchiver struct {
+	archived []string
+	pruned   []string
+	err      error
+}
+
+func (m *mockArchiver) Archive(path string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.archived = append(m.archived, path)
+	return "/test/archive/" + filepath.Base(path), nil
+}
+
+func (m *mockArchiver) Prune() ([]string, error) {
+	return m.pruned, nil
+}
+
+func TestCleanupLocalFiles_ArchivesIncludedKindsBeforeDeleting(t *testing.T) {
+	cfg := createTestConfig()
+	fileRemover := &mockFileRemover{}
+	output := &bytes.Buffer{}
+	archiver := &mockArchiver{pruned: []string{"/test/archive/2024-01-07.mp4"}}
+
+	fileFinder := &mockFileFinder{
+		files: []string{
+			"/test/any/2025-01-01.mp4",
+			"/test/any/2025-12-28.mp4",
+		},
+	}
+
+	service := NewService(
+		&mockTrimmer{}, &mockExtractor{},
+		&mockFileChecker{existingFiles: map[string]bool{}},
+		&mockFileSizer{sizes: make(map[string]int64)},
+		newMockDriveClient(), &mockEmailSender{}, fileFinder, cfg, output,
+		&mockDiskChecker{usage: 80.0}, fileRemover,
+		WithLocalArchive(archiver, domainfs.ArchivePolicy{Kinds: []string{domainfs.ArchiveTrimmed}}),
+	)
+
+	input := CleanupInput{
+		IsNewlyProcessed: true,
+		SourcePath:       "/test/source/2025-12-28 10-06-16.mp4",
+		ServiceDate:      time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+	}
+	service.cleanupLocalFiles(input, 70.0, "Post-processing")
+
+	// Only the trimmed directory's oldest file is archived; source and audio are just deleted
+	if len(archiver.archived) != 1 || archiver.archived[0] != "/test/any/2025-01-01.mp4" {
+		t.Errorf("expected only the trimmed file archived, got %v", archiver.archived)
+	}
+	if len(fileRemover.removedFiles) != 3 {
+		t.Errorf("expected 3 files removed, got %v", fileRemover.removedFiles)
+	}
+	outStr := output.String()
+	if !containsSubstring(outStr, "Archived: 2025-01-01.mp4 -> /test/archive/2025-01-01.mp4") {
+		t.Errorf("expected archive output, got: %s", outStr)
+	}
+	if !containsSubstring(outStr, "Pruned archive: 2024-01-07.mp4") {
+		t.Errorf("expected pruned archive output, got: %s", outStr)
+	}
+}
+
+func TestDeleteOldestFile_KeepsFileWhenArchivingFails(t *testing.T) {
+	cfg := createTestConfig()
+	fileRemover := &mockFileRemover{}
+	archiver := &mockArchiver{err: errors.New("ffmpeg re-encode failed")}
+
+	fileFinder := &mockFileFinder{files: []string{"/test/trimmed/2025-01-05.mp4"}}
+
+	service := NewService(
+		&mockTrimmer{}, &mockExtractor{},
+		&mockFileChecker{existingFiles: map[string]bool{}},
+		&mockFileSizer{sizes: make(map[string]int64)},
+		newMockDriveClient(), &mockEmailSender{}, fileFinder, cfg, &bytes.Buffer{},
+		&mockDiskChecker{usage: 80.0}, fileRemover,
+		WithLocalArchive(archiver, domainfs.ArchivePolicy{Kinds: domainfs.DefaultArchiveKinds}),
+	)
+
+	err := service.deleteOldestFile("/test/trimmed", ".mp4", "/test/trimmed/2025-12-28.mp4")
+	if err == nil || !containsSubstring(err.Error(), "archive 2025-01-05.mp4") {
+		t.Fatalf("expected archive error, got %v", err)
+	}
+	if len(fileRemover.removedFiles) != 0 {
+		t.Errorf("expected the file to be kept, got %v removed", fileRemover.removedFiles)
+	}
+}
+
+func TestDeleteOldestFile_KeptWhenCheckpointDeclined(t *testing.T) {
+	cfg := createTestConfig()
+	fileRemover := &mockFileRemover{}
+	output := &bytes.Buffer{}
+	var asked []string
+
+	service := NewService(
+		&mockTrimmer{}, &mockExtractor{},
+		&mockFileChecker{existingFiles: map[string]bool{}},
+		&mockFileSizer{sizes: make(map[string]int64)},
+		newMockDriveClient(), &mockEmailSender{},
+		&mockFileFinder{files: []string{"/test/trimmed/2025-01-05.mp4"}}, cfg, output,
+		&mockDiskChecker{usage: 80.0}, fileRemover,
+		WithStepConfirmation(func(action string) (bool, error) {
+			asked = append(asked, action)
+			return false, nil
+		}),
+	)
+
+	if err := service.deleteOldestFile("/test/trimmed", ".mp4", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(asked) != 1 || asked[0] != "delete /test/trimmed/2025-01-05.mp4" {
+		t.Errorf("expected one checkpoint for the delete, got %v", asked)
+	}
+	if len(fileRemover.removedFiles) != 0 {
+		t.Errorf("expected the file to be kept, got %v removed", fileRemover.removedFiles)
+	}
+	if !containsSubstring(output.String(), "Kept: 2025-01-05.mp4") {
+		t.Errorf("expected kept note, got: %s", output.String())
+	}
+}
+
+// fullDriveClient has 10 bytes free and one old 60-byte video to delete
+func fullDriveClient() *mockDriveClient {
+	driveClient := newMockDriveClient()
+	driveClient.storageInfo = &distribution.StorageInfo{TotalBytes: 100, UsedBytes: 90, AvailableBytes: 10}
+	driveClient.files["2025-01-05.mp4"] = &distribution.FileInfo{ID: "old-video", Name: "2025-01-05.mp4", Size: 60, MimeType: "video/mp4"}
+	return driveClient
+}
+
+// recordingAudit keeps audit events in memory
+type recordingAudit struct {
+	events []audit.Event
+}
+
+func (r *recordingAudit) Record(e audit.Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+func newCleanupTestService(driveClient *mockDriveClient, output *bytes.Buffer, opts ...Option) *Service {
+	return NewService(
+		&mockTrimmer{}, &mockExtractor{},
+		&mockFileChecker{existingFiles: map[string]bool{}},
+		&mockFileSizer{sizes: make(map[string]int64)},
+		driveClient, &mockEmailSender{}, &mockFileFinder{}, createTestConfig(), output,
+		&mockDiskChecker{}, &mockFileRemover{}, opts...,
+	)
+}
+
+func TestEnsureStorageFor_RefusesToDeleteWithoutApproval(t *testing.T) {
+	driveClient := fullDriveClient()
+	log := &recordingAudit{}
+	output := &bytes.Buffer{}
+	service := newCleanupTestService(driveClient, output, WithAuditLog(log))
+
+	err := service.ensureStorageFor(context.Background(), 50)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Code != CodeDeleteNotAllowed {
+		t.Fatalf("expected a %s validation error, got %v", CodeDeleteNotAllowed, err)
+	}
+	if len(driveClient.deletedIDs) != 0 {
+		t.Errorf("expected nothing deleted, got %v", driveClient.deletedIDs)
+	}
+	if !containsSubstring(output.String(), "2025-01-05.mp4") {
+		t.Errorf("expected the candidate to be listed, got: %s", output.String())
+	}
+	if len(log.events) != 1 || log.events[0].Action != audit.ActionCleanupDecision || log.events[0].Detail != "refused: --allow-delete not given" {
+		t.Errorf("expected the refusal to be audited, got %+v", log.events)
+	}
+}
+
+func TestEnsureStorageFor_DeleteAllowed(t *testing.T) {
+	driveClient := fullDriveClient()
+	log := &recordingAudit{}
+	output := &bytes.Buffer{}
+	service := newCleanupTestService(driveClient, output, WithDeleteAllowed(true), WithAuditLog(log))
+
+	if err := service.ensureStorageFor(context.Background(), 50); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(driveClient.deletedIDs) != 1 || driveClient.deletedIDs[0] != "old-video" {
+		t.Errorf("expected the old video deleted, got %v", driveClient.deletedIDs)
+	}
+	for _, want := range []string{
+		"[1/1] Removed: 2025-01-05.mp4 (60 B); 60 B of 60 B freed",
+		"Deleted from Drive (60 B freed):",
+		"2025-01-05.mp4  2025-01-05    60 B",
+	} {
+		if !containsSubstring(output.String(), want) {
+			t.Errorf("expected %q in output, got: %s", want, output.String())
+		}
+	}
+	if len(service.deleted) != 1 || service.deleted[0].Date() != "2025-01-05" {
+		t.Errorf("expected the deletion kept for the run summary, got %+v", service.deleted)
+	}
+	if len(log.events) != 1 || log.events[0].Target != "2025-01-05.mp4" || log.events[0].Detail != "allowed by --allow-delete" {
+		t.Errorf("expected the decision to be audited, got %+v", log.events)
+	}
+}
+
+func TestEnsureStorageFor_DeleteConfirmed(t *testing.T) {
+	driveClient := fullDriveClient()
+	log := &recordingAudit{}
+	var asked []string
+	service := newCleanupTestService(driveClient, &bytes.Buffer{}, WithAuditLog(log),
+		WithDeleteConfirmation(func(action string) (bool, error) {
+			asked = append(asked, action)
+			return true, nil
+		}))
+
+	if err := service.ensureStorageFor(context.Background(), 50); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(asked) != 1 || len(driveClient.deletedIDs) != 1 {
+		t.Errorf("expected one question and one deletion, got %v and %v", asked, driveClient.deletedIDs)
+	}
+	if len(log.events) != 1 || log.events[0].Detail != "approved" {
+		t.Errorf("expected the approval to be audited, got %+v", log.events)
+	}
+}
+
+func TestEnsureStorageFor_StopsWhenCleanupDeclined(t *testing.T) {
+	driveClient := fullDriveClient()
+
+	service := NewService(
+		&mockTrimmer{}, &mockExtractor{},
+		&mockFileChecker{existingFiles: map[string]bool{}},
+		&mockFileSizer{sizes: make(map[string]int64)},
+		driveClient, &mockEmailSender{}, &mockFileFinder{}, createTestConfig(), &bytes.Buffer{},
+		&mockDiskChecker{}, &mockFileRemover{},
+		WithStepConfirmation(func(string) (bool, error) { return false, nil }),
+	)
+
+	err := service.ensureStorageFor(context.Background(), 50)
+	if !errors.Is(err, ErrStepDeclined) {
+		t.Fatalf("expected ErrStepDeclined, got %v", err)
+	}
+}
+
+func TestEnsureStorageFor_NoCheckpointWhenSpaceIsFree(t *testing.T) {
+	asked := false
+	service := NewService(
+		&mockTrimmer{}, &mockExtractor{},
+		&mockFileChecker{existingFiles: map[string]bool{}},
+		&mockFileSizer{sizes: make(map[string]int64)},
+		newMockDriveClient(), &mockEmailSender{}, &mockFileFinder{}, createTestConfig(), &bytes.Buffer{},
+		&mockDiskChecker{}, &mockFileRemover{},
+		WithStepConfirmation(func(string) (bool, error) { asked = true; return false, nil }),
+	)
+
+	if err := service.ensureStorageFor(context.Background(), 50); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asked {
+		t.Error("expected no checkpoint when Drive has room")
+	}
+}
+
+func TestValidateInputs_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Input
+		wantCode string
+		wantExit int
+	}{
+		{"unknown minister", Input{MinisterKey: "nobody", RecipientKeys: []string{"jane"}}, CodeMinisterNotFound, 10},
+		{"unknown recipient", Input{RecipientKeys: []string{"nobody"}}, CodeRecipientNotFound, 11},
+		{"unknown cc", Input{RecipientKeys: []string{"jane"}, CCKeys: []string{"nobody"}}, CodeCCNotFound, 12},
+		{"unknown sender", Input{RecipientKeys: []string{"jane"}, SenderKey: "nobody"}, CodeSenderNotFound, 13},
+		{"exclusion matches nobody", Input{RecipientKeys: []string{"jane"}, ExcludeKeys: []string{"john"}}, CodeExclusionUnmatched, 16},
+		{"everyone excluded", Input{RecipientKeys: []string{"jane"}, ExcludeKeys: []string{"jane"}}, CodeNoRecipients, 17},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileChecker := &mockFileChecker{existingFiles: map[string]bool{"/test/source/2025-12-28 10-06-16.mp4": true}}
+			service := createTestService(newMockDriveClient(), fileChecker, &mockFileFinder{}, createTestConfig())
+
+			input := tt.input
+			input.InputPath = "/test/source/2025-12-28 10-06-16.mp4"
+			_, _, _, _, _, _, err := service.validateInputs(context.Background(), input)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			if validationErr.Code != tt.wantCode {
+				t.Errorf("expected code %s, got %q", tt.wantCode, validationErr.Code)
+			}
+			if got := validationErr.ExitCode(); got != tt.wantExit {
+				t.Errorf("expected exit code %d, got %d", tt.wantExit, got)
+			}
+		})
+	}
+}
+
+func TestValidationError_ExitCodeDefault(t *testing.T) {
+	if got := (&ValidationError{Message: "bad input"}).ExitCode(); got != ExitCodeValidation {
+		t.Errorf("expected exit code %d for an uncoded error, got %d", ExitCodeValidation, got)
+	}
+}
+
+func TestReviewRecipientList_AppliesEditsUntilDone(t *testing.T) {
+	edits := []RecipientEdit{
+		{Add: "john", CC: true},
+		{Add: "nobody"},
+		{Remove: "jane@example.com"},
+		{Add: "jane"},
+		{Remove: "john@example.com"},
+	}
+	output := &bytes.Buffer{}
+	service := NewService(
+		&mockTrimmer{}, &mockExtractor{},
+		&mockFileChecker{existingFiles: map[string]bool{}},
+		&mockFileSizer{sizes: make(map[string]int64)},
+		newMockDriveClient(), &mockEmailSender{}, &mockFileFinder{}, createTestConfig(), output,
+		&mockDiskChecker{}, &mockFileRemover{},
+		WithRecipientReview(func(to, cc []notification.Recipient) (RecipientEdit, error) {
+			if len(edits) == 0 {
+				return RecipientEdit{}, nil
+			}
+			edit := edits[0]
+			edits = edits[1:]
+			return edit, nil
+		}),
+	)
+
+	jane := notification.Recipient{Name: "Jane Doe", Address: "jane@example.com"}
+	to, cc, err := service.reviewRecipientList([]notification.Recipient{jane}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Jane can't be removed while she is the only recipient, and John's
+	// removal takes him off CC
+	if len(to) != 1 || to[0].Address != "jane@example.com" {
+		t.Errorf("expected To to stay Jane, got %v", to)
+	}
+	if len(cc) != 0 {
+		t.Errorf("expected CC to be empty, got %v", cc)
+	}
+	for _, want := range []string{
+		"Added: John Doe <john@example.com>",
+		"Not added:",
+		"Not removed: jane@example.com is the only recipient",
+		"Removed: john@example.com",
+		"CC: John Doe <john@example.com>",
+	} {
+		if !containsSubstring(output.String(), want) {
+			t.Errorf("expected output to include %q, got:\n%s", want, output.String())
+		}
+	}
+}
+
+// mockModTimes implements filesystem.ModTimer for testing
+type mockModTimes map[string]time.Time
+
+func (m mockModTimes) ModTime(path string) (time.Time, error) {
+	if t, ok := m[path]; ok {
+		return t, nil
+	}
+	return time.Time{}, errors.New("no such file")
+}
+
+func TestValidateInputs_ClockDrift(t *testing.T) {
+	source := "/test/source/2025-12-29 10-06-16.mp4"
+	tests := []struct {
+		name     string
+		modTime  time.Time
+		date     string
+		wantCode string
+	}{
+		{"written the same morning", time.Date(2025, 12, 29, 11, 50, 0, 0, time.Local), "", ""},
+		{"written a day earlier", time.Date(2025, 12, 28, 11, 50, 0, 0, time.Local), "", CodeClockDrift},
+		{"--date confirms the date", time.Date(2025, 12, 28, 11, 50, 0, 0, time.Local), "2025-12-28", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(
+				&mockTrimmer{}, &mockExtractor{},
+				&mockFileChecker{existingFiles: map[string]bool{source: true}},
+				&mockFileSizer{sizes: make(map[string]int64)},
+				newMockDriveClient(), &mockEmailSender{}, &mockFileFinder{}, createTestConfig(), &bytes.Buffer{},
+				&mockDiskChecker{}, &mockFileRemover{},
+				WithModTimes(mockModTimes{source: tt.modTime}),
+			)
+
+			_, _, _, _, _, _, err := service.validateInputs(context.Background(), Input{
+				InputPath:     source,
+				RecipientKeys: []string{"jane"},
+				DateOverride:  tt.date,
+			})
+			var validationErr *ValidationError
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &validationErr) || validationErr.Code != tt.wantCode {
+				t.Fatalf("expected %s, got %v", tt.wantCode, err)
+			}
+			if validationErr.ExitCode() != 18 {
+				t.Errorf("expected exit code 18, got %d", validationErr.ExitCode())
+			}
+		})
+	}
+}
+
+// memoryRunState keeps saved runs in memory
+type memoryRunState struct {
+	states map[string]*runstate.State
+}
+
+func (m *memoryRunState) Load(serviceDate time.Time) (*runstate.State, error) {
+	if s, ok := m.states[serviceDate.Format("2006-01-02")]; ok {
+		return s, nil
+	}
+	return nil, runstate.ErrNoState
+}
+
+func (m *memoryRunState) Latest() (*runstate.State, error) {
+	return nil, runstate.ErrNoState
+}
+
+func (m *memoryRunState) Save(s *runstate.State) error {
+	m.states[s.ServiceDate.Format("2006-01-02")] = s
+	return nil
+}
+
+func (m *memoryRunState) Clear(serviceDate time.Time) error {
+	delete(m.states, serviceDate.Format("2006-01-02"))
+	return nil
+}
+
+func TestRunState_ResumeLoadsSavedRun(t *testing.T) {
+	store := &memoryRunState{states: map[string]*runstate.State{}}
+	output := &bytes.Buffer{}
+	sizer := &mockFileSizer{sizes: map[string]int64{"/trimmed/2025-01-05.mp4": 100}}
+	svc := NewService(
+		&mockTrimmer{}, &mockExtractor{},
+		&mockFileChecker{existingFiles: map[string]bool{}}, sizer,
+		newMockDriveClient(), &mockEmailSender{}, &mockFileFinder{}, createTestConfig(), output,
+		&mockDiskChecker{}, &mockFileRemover{}, WithRunState(store),
+	)
+	date := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	input := Input{StartTime: "00:01:00", EndTime: "01:00:00", MinisterKey: "smith", Title: "Grace"}
+
+	state, err := svc.startRunState(input, "/source/service.mp4", date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.TrimmedPath = "/trimmed/2025-01-05.mp4"
+	svc.saveRunState(state, "Trim video")
+
+	saved, _ := store.Load(date)
+	resumeInput := ResumeInput(saved)
+	if resumeInput.InputPath != "/source/service.mp4" || resumeInput.MinisterKey != "smith" || resumeInput.Title != "Grace" || !resumeInput.Resume {
+		t.Errorf("resume input = %+v", resumeInput)
+	}
+
+	resumed, err := svc.startRunState(resumeInput, resumeInput.InputPath, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.LastStep != "Trim video" {
+		t.Errorf("last step = %q, want Trim video", resumed.LastStep)
+	}
+	if !containsSubstring(output.String(), "Resuming the run saved at") {
+		t.Errorf("expected a resume note, got: %s", output.String())
+	}
+	if _, ok := svc.resumedFile(resumed.TrimmedPath); !ok {
+		t.Error("the trimmed file is still there, so trimming should be skipped")
+	}
+	if _, ok := svc.resumedFile(resumed.AudioPath); ok {
+		t.Error("audio was never extracted, so it should not be skipped")
+	}
+
+	svc.finishRunState(resumed)
+	if _, err := store.Load(date); !errors.Is(err, runstate.ErrNoState) {
+		t.Errorf("the saved run should be cleared once finished, got %v", err)
+	}
+}
+
+func TestRunState_ResumeRejectsOtherWorkflow(t *testing.T) {
+	store := &memoryRunState{states: map[string]*runstate.State{}}
+	svc := newCleanupTestService(newMockDriveClient(), &bytes.Buffer{}, WithRunState(store))
+	date := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.startRunState(Input{Resume: true}, "", date); !errors.Is(err, runstate.ErrNoState) {
+		t.Errorf("resuming with nothing saved should fail with ErrNoState, got %v", err)
+	}
+	if _, err := svc.startRunState(Input{SkipVideo: true}, "/source/service.mp4", date); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.startRunState(Input{Resume: true}, "/source/service.mp4", date); err == nil || !containsSubstring(err.Error(), "audio-only") {
+		t.Errorf("resuming an audio-only run as a full one should fail, got %v", err)
+	}
+}
+
+// memoryVideoHost is a VideoHost that only counts uploads
+type memoryVideoHost struct{ uploads []distribution.VideoUpload }
+
+func (h *memoryVideoHost) Name() string { return "YouTube" }
+
+func (h *memoryVideoHost) UploadVideo(ctx context.Context, v distribution.VideoUpload, r io.Reader) (*distribution.HostedVideo, error) {
+	h.uploads = append(h.uploads, v)
+	return &distribution.HostedVideo{Host: "YouTube", ID: "abc", URL: "https://www.youtube.com/watch?v=abc", Privacy: v.Privacy}, nil
+}
+
+func TestCheckTarget_NeedsVideoHost(t *testing.T) {
+	output := &bytes.Buffer{}
+	svc := newCleanupTestService(newMockDriveClient(), output)
+	if err := svc.checkTarget(Input{Target: distribution.TargetYouTube}); err == nil {
+		t.Error("a YouTube target without a video host should fail")
+	}
+	if err := svc.checkTarget(Input{Target: distribution.TargetYouTube, SkipVideo: true}); err != nil {
+		t.Errorf("audio-only runs upload no video, got %v", err)
+	}
+
+	svc = newCleanupTestService(newMockDriveClient(), output, WithVideoHost(&memoryVideoHost{}))
+	if err := svc.checkTarget(Input{Target: distribution.TargetBoth}); err != nil {
+		t.Fatal(err)
+	}
+	if !containsSubstring(output.String(), "Video target: YouTube and Drive") {
+		t.Errorf("expected the target in the output, got: %s", output.String())
+	}
+}
+
+// inspectingDriveClient is a mockDriveClient that can look its folders up
+type inspectingDriveClient struct {
+	*mockDriveClient
+	folders map[string]distribution.FolderDetails
+}
+
+func (m *inspectingDriveClient) FolderDetails(ctx context.Context, folderID string) (*distribution.FolderDetails, error) {
+	folder, ok := m.folders[folderID]
+	if !ok {
+		return nil, distribution.ErrFolderNotFound
+	}
+	return &folder, nil
+}
+
+func TestCheckFolder(t *testing.T) {
+	client := &inspectingDriveClient{mockDriveClient: newMockDriveClient(), folders: map[string]distribution.FolderDetails{
+		"services":  {ID: "services", Name: "Sunday Services", MimeType: distribution.MimeTypeFolder, CanAddChildren: true},
+		"read-only": {ID: "read-only", Name: "Archive", MimeType: distribution.MimeTypeFolder},
+		"file":      {ID: "file", Name: "notes.txt", MimeType: "text/plain", CanAddChildren: true},
+	}}
+	tests := []struct {
+		folderID string
+		wantName string
+		wantErr  error
+	}{
+		{"services", "Sunday Services", nil},
+		{"read-only", "", distribution.ErrFolderNotWritable},
+		{"file", "", distribution.ErrNotAFolder},
+		{"typo", "", distribution.ErrFolderNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.folderID, func(t *testing.T) {
+			svc := newCleanupTestService(client.mockDriveClient, &bytes.Buffer{})
+			svc.driveClient, svc.folderID = client, tt.folderID
+
+			name, err := svc.checkFolder(context.Background())
+			if name != tt.wantName {
+				t.Errorf("name = %q, want %q", name, tt.wantName)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.ExitCode() != 23 {
+				t.Fatalf("expected a DRIVE_FOLDER_INVALID error, got %v", err)
+			}
+			if !containsSubstring(validationErr.Message, tt.wantErr.Error()) {
+				t.Errorf("message %q should say %q", validationErr.Message, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckFolder_SkippedWithoutLookup(t *testing.T) {
+	svc := newCleanupTestService(newMockDriveClient(), &bytes.Buffer{})
+	if name, err := svc.checkFolder(context.Background()); name != "" || err != nil {
+		t.Errorf("checkFolder() = %q, %v; want nothing checked", name, err)
+	}
+}
+
+func TestHostedUpload_LinksTheHostedVideo(t *testing.T) {
+	hosted := &distribution.HostedVideo{Host: "YouTube", ID: "abc", URL: "https://www.youtube.com/watch?v=abc"}
+	drive := &distribution.UploadResult{FileID: "drive1", FileName: "2025-01-05.mp4", ShareableURL: "https://drive.google.com/file/d/drive1/view"}
+
+	if got := hostedUpload(drive, nil); got != drive {
+		t.Errorf("without a hosted video the Drive upload is sent, got %+v", got)
+	}
+	both := hostedUpload(drive, hosted)
+	if both.FileID != "drive1" || both.ShareableURL != hosted.URL {
+		t.Errorf("both = %+v, want the Drive file with the YouTube link", both)
+	}
+	if drive.ShareableURL != "https://drive.google.com/file/d/drive1/view" {
+		t.Error("the Drive upload should not be changed")
+	}
+	if only := hostedUpload(nil, hosted); only.FileID != "" || only.ShareableURL != hosted.URL {
+		t.Errorf("youtube only = %+v, want the YouTube link and no Drive file", only)
+	}
+}
+
+// countingSampler reports a process that uses one more second of CPU with
+// every sample, taken a second apart
+type countingSampler struct {
+	mu      sync.Mutex
+	samples int
+}
+
+func (c *countingSampler) Sample() (resource.Snapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.samples++
+	at := time.Date(2025, 12, 28, 11, 0, c.samples, 0, time.UTC)
+	return resource.Snapshot{At: at, Processes: []resource.Process{
+		{PID: 1, CPU: time.Duration(c.samples) * time.Second, Memory: 1024},
+	}}, nil
+}
+
+func TestStepClock_StartMeasured(t *testing.T) {
+	sampler := &countingSampler{}
+	steps := &stepClock{sampler: sampler, interval: time.Millisecond}
+
+	steps.StartMeasured("Trim video")
+	time.Sleep(5 * time.Millisecond)
+	steps.Start("Upload video")
+	got := steps.Steps()
+
+	if len(got) != 2 {
+		t.Fatalf("steps = %+v, want 2", got)
+	}
+	if u := got[0].Usage; u.CPUPeak != 100 || u.CPUAverage != 100 || u.MemoryPeak != 1024 {
+		t.Errorf("trim usage = %+v, want one core throughout", u)
+	}
+	if !got[1].Usage.IsZero() {
+		t.Errorf("upload usage = %+v, want it unmeasured", got[1].Usage)
+	}
+	if usage := stepUsage(got); len(usage) != 1 || usage[0].Step != "Trim video" {
+		t.Errorf("stepUsage() = %+v, want only the trim", usage)
+	}
+}
+
+func TestStepClock_StartMeasuredWithoutSampler(t *testing.T) {
+	steps := &stepClock{}
+	steps.StartMeasured("Trim video")
+	if got := steps.Steps(); len(got) != 1 || !got[0].Usage.IsZero() {
+		t.Errorf("steps = %+v, want an unmeasured trim", got)
+	}
+}
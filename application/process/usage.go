@@ -0,0 +1,78 @@
+package process
+
+import (
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/resource"
+	"nac-service-media/domain/summary"
+)
+
+// WithUsageSampler samples CPU, memory and disk use while ffmpeg trims and
+// extracts, for the run summary and history
+func WithUsageSampler(sampler resource.Sampler) Option {
+	return func(s *Service) {
+		s.usageSampler = sampler
+	}
+}
+
+// newStepClock times this run's steps, sampling resource use when a sampler is set
+func (s *Service) newStepClock() *stepClock {
+	return &stepClock{failAt: s.failAtStep, sampler: s.usageSampler, interval: resource.DefaultSampleInterval}
+}
+
+// StartMeasured starts the next step like Start and samples resource use
+// until it ends
+func (c *stepClock) StartMeasured(name string) {
+	c.Start(name)
+	if c.sampler == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	measured := make(chan resource.Usage, 1)
+	go func() {
+		var meter resource.Meter
+		sample := func() {
+			// A failed reading leaves a gap; the step itself is unaffected
+			if snap, err := c.sampler.Sample(); err == nil {
+				meter.Add(snap)
+			}
+		}
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		sample()
+		for {
+			select {
+			case <-ticker.C:
+				sample()
+			case <-done:
+				sample()
+				measured <- meter.Usage()
+				return
+			}
+		}
+	}()
+	c.measure = func() resource.Usage {
+		close(done)
+		return <-measured
+	}
+}
+
+// stepUsage lists the measured steps for history
+func stepUsage(steps []summary.Step) []resource.StepUsage {
+	var usage []resource.StepUsage
+	for _, step := range steps {
+		if !step.Usage.IsZero() {
+			usage = append(usage, resource.StepUsage{Step: step.Name, Usage: step.Usage})
+		}
+	}
+	return usage
+}
+
+// printUsage shows what the measured steps cost the computer
+func (s *Service) printUsage(steps []summary.Step) {
+	for _, u := range stepUsage(steps) {
+		fmt.Fprintf(s.output, "  %s: %s\n", u.Step, u.Usage)
+	}
+}
@@ -0,0 +1,81 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/domain/distribution"
+)
+
+// WithVideoHost uploads videos to a video host such as YouTube when
+// Input.Target asks for it
+func WithVideoHost(host distribution.VideoHost) Option {
+	return func(s *Service) {
+		s.videoHost = host
+	}
+}
+
+// checkTarget fails before anything is trimmed when the target needs a
+// video host that is not set up
+func (s *Service) checkTarget(input Input) error {
+	if input.SkipVideo || !input.Target.UsesVideoHost() {
+		return nil
+	}
+	if s.videoHost == nil {
+		return fmt.Errorf("cannot upload to %s: no video host is set up", input.Target)
+	}
+	if s.sharedPrivately() {
+		return fmt.Errorf("cannot upload to %s: a %s is shared privately, so its video can only go to Drive", input.Target, s.policy.Type)
+	}
+	fmt.Fprintf(s.output, "Video target: %s\n", targetName(input.Target, s.videoHost))
+	return nil
+}
+
+// targetName describes where videos go, e.g. "YouTube and Drive"
+func targetName(target distribution.Target, host distribution.VideoHost) string {
+	if target.UsesDrive() {
+		return host.Name() + " and Drive"
+	}
+	return host.Name()
+}
+
+// hostVideo uploads the trimmed video to the video host
+func (s *Service) hostVideo(ctx context.Context, input Input, videoPath string, serviceDate time.Time, ministerName string) (*distribution.HostedVideo, error) {
+	var opts []appdist.HostOption
+	if s.fs != nil {
+		opts = append(opts, appdist.WithHostFS(s.fs))
+	}
+	return appdist.NewHostService(s.videoHost, opts...).UploadVideo(ctx, videoPath, distribution.VideoDetails{
+		ChurchName:  s.cfg.Email.FromName,
+		ServiceDate: serviceDate,
+		Minister:    ministerName,
+		Title:       input.Title,
+		Scripture:   input.Scripture,
+		Privacy:     distribution.Privacy(s.cfg.YouTube.Privacy),
+	})
+}
+
+// targetArg repeats a --target other than Drive in recovery commands
+func targetArg(input Input) string {
+	if !input.Target.UsesVideoHost() {
+		return ""
+	}
+	return fmt.Sprintf(" --target %s", input.Target)
+}
+
+// hostedUpload is the video upload as emailed: the Drive upload, with the
+// hosted video's link when there is one. The file ID stays Drive's, empty
+// when the video only went to the host.
+func hostedUpload(drive *distribution.UploadResult, hosted *distribution.HostedVideo) *distribution.UploadResult {
+	if hosted == nil {
+		return drive
+	}
+	result := distribution.UploadResult{FileName: hosted.Host, ShareableURL: hosted.URL}
+	if drive != nil {
+		result = *drive
+		result.ShareableURL = hosted.URL
+	}
+	return &result
+}
@@ -0,0 +1,54 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"nac-service-media/domain/recording"
+)
+
+// Service hands a finished recording off to the processing pipeline
+type Service struct {
+	recorder recording.Recorder
+	output   io.Writer
+}
+
+// NewService creates a new recording service
+func NewService(recorder recording.Recorder, output io.Writer) *Service {
+	return &Service{
+		recorder: recorder,
+		output:   output,
+	}
+}
+
+// Finish ends the active recording and returns the path of the saved file.
+// If wait is true it waits for the recording to be stopped elsewhere
+// (e.g. by the operator in OBS) instead of stopping it.
+func (s *Service) Finish(ctx context.Context, wait bool) (string, error) {
+	active, err := s.recorder.IsRecording(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get recording status: %w", err)
+	}
+	if !active {
+		return "", recording.ErrNotRecording
+	}
+
+	var path string
+	if wait {
+		fmt.Fprintf(s.output, "Waiting for the recording to stop...\n")
+		path, err = s.recorder.WaitForStop(ctx)
+	} else {
+		fmt.Fprintf(s.output, "Stopping recording...\n")
+		path, err = s.recorder.StopRecording(ctx)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to finish recording: %w", err)
+	}
+	if path == "" {
+		return "", fmt.Errorf("recorder did not report an output file")
+	}
+
+	fmt.Fprintf(s.output, "Recording saved: %s\n\n", path)
+	return path, nil
+}
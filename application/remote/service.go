@@ -0,0 +1,197 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/remote"
+)
+
+// Service starts commands for the remote control API one at a time and keeps
+// their state and output for status queries
+type Service struct {
+	runner  remote.CommandRunner
+	history history.Store
+	ctx     context.Context
+	now     func() time.Time
+
+	mu     sync.Mutex
+	runs   map[string]*trackedRun
+	order  []string
+	active string
+	seq    int
+	wg     sync.WaitGroup
+}
+
+var _ remote.Controller = (*Service)(nil)
+
+// trackedRun is a run with the output it has written so far
+type trackedRun struct {
+	run    remote.Run
+	output bytes.Buffer
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithClock sets the clock runs are timestamped with
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+// NewService creates a service whose runs last as long as ctx; store may be
+// nil when history is not kept
+func NewService(ctx context.Context, runner remote.CommandRunner, store history.Store, opts ...Option) *Service {
+	s := &Service{
+		runner:  runner,
+		history: store,
+		ctx:     ctx,
+		now:     time.Now,
+		runs:    make(map[string]*trackedRun),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// StartProcess starts a non-interactive process run
+func (s *Service) StartProcess(req remote.ProcessRequest) (remote.Run, error) {
+	if err := req.Validate(); err != nil {
+		return remote.Run{}, err
+	}
+	return s.start(remote.KindProcess, req.Args())
+}
+
+// Resend emails the most recent successful run for a service date again
+func (s *Service) Resend(req remote.ResendRequest) (remote.Run, error) {
+	if err := req.Validate(); err != nil {
+		return remote.Run{}, err
+	}
+	entries, err := s.History(history.Filter{})
+	if err != nil {
+		return remote.Run{}, err
+	}
+	entry, err := remote.LatestSent(entries, req.Date)
+	if err != nil {
+		return remote.Run{}, err
+	}
+	return s.start(remote.KindResend, req.Args(entry))
+}
+
+// Run returns the current state of a run
+func (s *Service) Run(id string) (remote.Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.runs[id]
+	if !ok {
+		return remote.Run{}, fmt.Errorf("%w: %s", remote.ErrRunNotFound, id)
+	}
+	return t.snapshot(), nil
+}
+
+// Runs returns every run started since the service began, oldest first
+func (s *Service) Runs() []remote.Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := make([]remote.Run, len(s.order))
+	for i, id := range s.order {
+		runs[i] = s.runs[id].snapshot()
+	}
+	return runs
+}
+
+// History returns the processed services that match filter
+func (s *Service) History(filter history.Filter) ([]history.Entry, error) {
+	if s.history == nil {
+		return nil, nil
+	}
+	entries, err := s.history.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	var matched []history.Entry
+	for _, e := range entries {
+		if filter.Matches(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched, nil
+}
+
+// Wait blocks until every started run has finished
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
+
+// start records a new run and runs args in the background, unless another
+// run is still going
+func (s *Service) start(kind string, args []string) (remote.Run, error) {
+	s.mu.Lock()
+	if s.active != "" {
+		s.mu.Unlock()
+		return remote.Run{}, fmt.Errorf("%w: %s", remote.ErrRunInProgress, s.active)
+	}
+	s.seq++
+	t := &trackedRun{run: remote.Run{
+		ID:        fmt.Sprintf("run-%d", s.seq),
+		Kind:      kind,
+		Args:      args,
+		State:     remote.RunRunning,
+		StartedAt: s.now(),
+	}}
+	s.runs[t.run.ID] = t
+	s.order = append(s.order, t.run.ID)
+	s.active = t.run.ID
+	run := t.snapshot()
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		err := s.runner.Run(s.ctx, args, &lockedWriter{mu: &s.mu, buf: &t.output})
+		s.finish(t, err)
+	}()
+	return run, nil
+}
+
+// finish records how a run ended and lets the next one start
+func (s *Service) finish(t *trackedRun, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	finished := s.now()
+	t.run.FinishedAt = &finished
+	t.run.State = remote.RunSucceeded
+	if err != nil {
+		t.run.State = remote.RunFailed
+		t.run.Error = err.Error()
+	}
+	s.active = ""
+}
+
+// snapshot copies the run with its output so far; the caller holds the lock
+func (t *trackedRun) snapshot() remote.Run {
+	run := t.run
+	run.Args = append([]string(nil), t.run.Args...)
+	run.Output = t.output.String()
+	return run
+}
+
+// lockedWriter appends to a run's output under the service lock, so status
+// queries can read it while the command writes
+type lockedWriter struct {
+	mu  *sync.Mutex
+	buf *bytes.Buffer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
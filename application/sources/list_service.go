@@ -0,0 +1,227 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/video"
+)
+
+// Where a processed service was found
+const (
+	FoundLocal   = "local"   // Trimmed MP4 or MP3 in the output directories
+	FoundHistory = "history" // A successful run in the history file
+	FoundDrive   = "drive"   // Video and audio in the services folder
+)
+
+// Source is a recording in a source directory
+type Source struct {
+	Path     string
+	Date     time.Time     // Service date; zero when the name has none
+	Duration time.Duration // Zero when it could not be read
+
+	// ProcessedIn lists where the service was found processed (Found*);
+	// empty when it was not
+	ProcessedIn []string
+}
+
+// Processed reports whether the service was found processed anywhere
+func (s Source) Processed() bool {
+	return len(s.ProcessedIn) > 0
+}
+
+// ListResult is the recordings found and the source directories that could
+// not be read
+type ListResult struct {
+	Sources    []Source
+	Unreadable []error
+}
+
+// FileLister lists the files with an extension in a directory
+type FileLister interface {
+	ListFiles(dir, ext string) ([]string, error)
+}
+
+// ProcessedChecker finds a service's uploads in Drive
+type ProcessedChecker interface {
+	Check(ctx context.Context, serviceDate string) (*appdist.ProcessedStatus, error)
+}
+
+// ListService lists source recordings with whether each has been processed
+type ListService struct {
+	lister   FileLister
+	calendar video.ServiceCalendar
+
+	fileChecker video.FileChecker
+	trimmedDir  string
+	audioDir    string
+	prober      video.DurationProber
+	history     history.Store
+	drive       ProcessedChecker
+}
+
+// ListOption is a functional option for configuring ListService
+type ListOption func(*ListService)
+
+// WithOutputs counts a service as processed when its YYYY-MM-DD.mp4 is in
+// trimmedDir or its YYYY-MM-DD.mp3 is in audioDir
+func WithOutputs(checker video.FileChecker, trimmedDir, audioDir string) ListOption {
+	return func(s *ListService) {
+		s.fileChecker = checker
+		s.trimmedDir = trimmedDir
+		s.audioDir = audioDir
+	}
+}
+
+// WithDurationProber reads each recording's length
+func WithDurationProber(p video.DurationProber) ListOption {
+	return func(s *ListService) {
+		s.prober = p
+	}
+}
+
+// WithHistory counts a service as processed when history has a successful run
+func WithHistory(store history.Store) ListOption {
+	return func(s *ListService) {
+		s.history = store
+	}
+}
+
+// WithDriveCheck counts a service as processed when its video and audio are
+// in Drive. Each date is one Drive query.
+func WithDriveCheck(checker ProcessedChecker) ListOption {
+	return func(s *ListService) {
+		s.drive = checker
+	}
+}
+
+// NewListService creates a new source list service
+func NewListService(lister FileLister, calendar video.ServiceCalendar, opts ...ListOption) *ListService {
+	s := &ListService{lister: lister, calendar: calendar}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// List returns the MP4 recordings in dirs dated within filter, oldest first.
+// Recordings without a date in their name are only listed when the filter
+// has no bounds.
+func (s *ListService) List(ctx context.Context, dirs []string, filter history.Filter) (*ListResult, error) {
+	processedRuns, err := s.successfulRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListResult{}
+	for _, dir := range dirs {
+		files, err := s.lister.ListFiles(dir, ".mp4")
+		if err != nil {
+			result.Unreadable = append(result.Unreadable, err)
+			continue
+		}
+		for _, path := range files {
+			src := Source{Path: path}
+			if date, err := s.calendar.DateFromFilename(filepath.Base(path)); err == nil {
+				src.Date = date
+			}
+			if !s.inRange(src.Date, filter) {
+				continue
+			}
+			result.Sources = append(result.Sources, src)
+		}
+	}
+
+	sort.SliceStable(result.Sources, func(i, j int) bool {
+		a, b := result.Sources[i], result.Sources[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return filepath.Base(a.Path) < filepath.Base(b.Path)
+	})
+
+	driveChecked := make(map[string][]string) // Date -> ProcessedIn from Drive
+	for i := range result.Sources {
+		src := &result.Sources[i]
+		if s.prober != nil {
+			if d, err := s.prober.Duration(ctx, src.Path); err == nil {
+				src.Duration = d
+			}
+		}
+		if src.Date.IsZero() {
+			continue
+		}
+		date := src.Date.Format("2006-01-02")
+		if s.hasLocalOutputs(date) {
+			src.ProcessedIn = append(src.ProcessedIn, FoundLocal)
+		}
+		if processedRuns[date] {
+			src.ProcessedIn = append(src.ProcessedIn, FoundHistory)
+		}
+		found, err := s.inDrive(ctx, date, driveChecked)
+		if err != nil {
+			return nil, err
+		}
+		src.ProcessedIn = append(src.ProcessedIn, found...)
+	}
+	return result, nil
+}
+
+// inRange reports whether a service date is within the filter's bounds
+func (s *ListService) inRange(date time.Time, filter history.Filter) bool {
+	if date.IsZero() {
+		return filter.From.IsZero() && filter.To.IsZero()
+	}
+	return filter.Matches(history.Entry{ServiceDate: date})
+}
+
+// successfulRuns returns the dates history records a successful run for
+func (s *ListService) successfulRuns() (map[string]bool, error) {
+	runs := make(map[string]bool)
+	if s.history == nil {
+		return runs, nil
+	}
+	entries, err := s.history.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	for _, e := range entries {
+		if e.Outcome == history.OutcomeSuccess {
+			runs[e.ServiceDate.Format("2006-01-02")] = true
+		}
+	}
+	return runs, nil
+}
+
+func (s *ListService) hasLocalOutputs(date string) bool {
+	if s.fileChecker == nil {
+		return false
+	}
+	return (s.trimmedDir != "" && s.fileChecker.Exists(filepath.Join(s.trimmedDir, date+".mp4"))) ||
+		(s.audioDir != "" && s.fileChecker.Exists(filepath.Join(s.audioDir, date+".mp3")))
+}
+
+// inDrive checks Drive once per date, since a service may have several recordings
+func (s *ListService) inDrive(ctx context.Context, date string, checked map[string][]string) ([]string, error) {
+	if s.drive == nil {
+		return nil, nil
+	}
+	if found, ok := checked[date]; ok {
+		return found, nil
+	}
+	status, err := s.drive.Check(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check Drive for %s: %w", date, err)
+	}
+	var found []string
+	if status.IsComplete() {
+		found = []string{FoundDrive}
+	}
+	checked[date] = found
+	return found, nil
+}
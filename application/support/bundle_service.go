@@ -0,0 +1,169 @@
+package support
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/audit"
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/support"
+)
+
+// auditWindow is how far back audit events are included
+const auditWindow = 7 * 24 * time.Hour
+
+// BundleResult describes a written bundle
+type BundleResult struct {
+	Path     string
+	Manifest support.Manifest
+}
+
+// BundleService collects what the last run left behind into one file to
+// share when asking for help
+type BundleService struct {
+	store   history.Store
+	audit   audit.Log
+	sources []support.Source
+	writer  support.Writer
+	limits  support.Limits
+	version string
+	now     func() time.Time
+}
+
+// Option configures a BundleService
+type Option func(*BundleService)
+
+// WithLimits bounds the bundle size (default support.DefaultLimits)
+func WithLimits(l support.Limits) Option {
+	return func(s *BundleService) {
+		s.limits = l
+	}
+}
+
+// WithVersion records the program version in the manifest
+func WithVersion(v string) Option {
+	return func(s *BundleService) {
+		s.version = v
+	}
+}
+
+// WithClock sets the time source (for tests)
+func WithClock(now func() time.Time) Option {
+	return func(s *BundleService) {
+		s.now = now
+	}
+}
+
+// NewBundleService creates a bundle service. The audit log may be nil; the
+// sources are collected in order, most useful first, so the size limit drops
+// the least useful files.
+func NewBundleService(store history.Store, auditLog audit.Log, sources []support.Source, writer support.Writer, opts ...Option) *BundleService {
+	s := &BundleService{
+		store:   store,
+		audit:   auditLog,
+		sources: sources,
+		writer:  writer,
+		limits:  support.DefaultLimits,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// LastRun writes a bundle about the most recent run to path. Every file is
+// scrubbed of secrets, and files that don't fit the limits are listed in the
+// manifest instead.
+func (s *BundleService) LastRun(path string) (*BundleResult, error) {
+	now := s.now()
+	manifest := support.Manifest{CreatedAt: now, Version: s.version}
+
+	entries, err := s.store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	var run support.Run
+	var files []support.File
+	if last, ok := latestEntry(entries); ok {
+		run = support.Run{ProcessedAt: last.ProcessedAt, ServiceDate: last.ServiceDate}
+		manifest.LastRun = last.ProcessedAt
+		manifest.FFmpeg = last.FFmpegVersion
+		data, err := json.MarshalIndent(last, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		files = append(files, support.File{Name: "history/last-run.json", Data: data})
+	} else {
+		manifest.Missing = append(manifest.Missing, "history entry")
+	}
+
+	for _, src := range s.sources {
+		found, err := src.Collect(run)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect %s: %w", src.Name(), err)
+		}
+		if len(found) == 0 {
+			manifest.Missing = append(manifest.Missing, src.Name())
+		}
+		files = append(files, found...)
+	}
+
+	if events, err := s.recentAuditEvents(now); err != nil {
+		return nil, err
+	} else if events != nil {
+		files = append(files, support.File{Name: "audit/recent.jsonl", Data: events})
+	}
+
+	for i := range files {
+		var n int
+		files[i].Data, n = support.Scrub(files[i].Data)
+		manifest.Redacted += n
+	}
+	manifest.Files, manifest.Skipped = s.limits.Fit(files)
+
+	bundle := append([]support.File{{Name: support.ManifestName, Data: []byte(manifest.Render())}}, manifest.Files...)
+	if err := s.writer.Write(path, bundle); err != nil {
+		return nil, err
+	}
+	return &BundleResult{Path: path, Manifest: manifest}, nil
+}
+
+// recentAuditEvents returns the audit events of the last week as JSON lines,
+// or nil when there are none
+func (s *BundleService) recentAuditEvents(now time.Time) ([]byte, error) {
+	if s.audit == nil {
+		return nil, nil
+	}
+	events, err := s.audit.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if e.Time.Before(now.Add(-auditWindow)) {
+			continue
+		}
+		if err := enc.Encode(e); err != nil {
+			return nil, fmt.Errorf("failed to encode audit event: %w", err)
+		}
+	}
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// latestEntry returns the most recently processed entry
+func latestEntry(entries []history.Entry) (history.Entry, bool) {
+	var latest history.Entry
+	for _, e := range entries {
+		if e.ProcessedAt.After(latest.ProcessedAt) {
+			latest = e
+		}
+	}
+	return latest, !latest.ProcessedAt.IsZero()
+}
@@ -0,0 +1,175 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"nac-service-media/domain/release"
+)
+
+// Service checks for and installs new releases of the tool
+type Service struct {
+	source    release.Source
+	installer release.Installer
+	current   string
+	channel   string
+	publicKey ed25519.PublicKey
+}
+
+// NewService creates a new update service.
+// publicKey is a base64-encoded ed25519 key; when set, checksums must be signed.
+func NewService(source release.Source, installer release.Installer, currentVersion, channel, publicKey string) (*Service, error) {
+	if channel == "" {
+		channel = release.ChannelStable
+	}
+	if err := release.ValidateChannel(channel); err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		source:    source,
+		installer: installer,
+		current:   currentVersion,
+		channel:   channel,
+	}
+
+	if publicKey != "" {
+		key, err := base64.StdEncoding.DecodeString(publicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid update public key: expected base64-encoded ed25519 key")
+		}
+		s.publicKey = ed25519.PublicKey(key)
+	}
+
+	return s, nil
+}
+
+// CheckResult describes the newest release available on the configured channel
+type CheckResult struct {
+	Current         string
+	Latest          *release.Release
+	UpdateAvailable bool
+}
+
+// Check finds the newest release on the channel and compares it to the running version
+func (s *Service) Check(ctx context.Context) (*CheckResult, error) {
+	releases, err := s.source.Releases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	var latest *release.Release
+	for i := range releases {
+		r := &releases[i]
+		if r.Prerelease && s.channel != release.ChannelBeta {
+			continue
+		}
+		if latest == nil {
+			latest = r
+			continue
+		}
+		if cmp, err := release.CompareVersions(r.Version, latest.Version); err == nil && cmp > 0 {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no releases found on the %s channel", s.channel)
+	}
+
+	result := &CheckResult{Current: s.current, Latest: latest}
+	cmp, err := release.CompareVersions(s.current, latest.Version)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compare running version: %w", err)
+	}
+	result.UpdateAvailable = cmp < 0
+
+	return result, nil
+}
+
+// Apply downloads the binary for goos/goarch from rel, verifies it against the
+// release checksums (and signature, if a public key is configured), and
+// installs it over execPath
+func (s *Service) Apply(ctx context.Context, rel *release.Release, goos, goarch, execPath string) error {
+	assetName := release.BinaryAssetName(goos, goarch)
+	binaryAsset := rel.FindAsset(assetName)
+	if binaryAsset == nil {
+		return fmt.Errorf("release %s has no build for %s/%s (expected asset %s)", rel.Version, goos, goarch, assetName)
+	}
+	checksumsAsset := rel.FindAsset(release.ChecksumsAsset)
+	if checksumsAsset == nil {
+		return fmt.Errorf("release %s is missing %s; refusing to install an unverified binary", rel.Version, release.ChecksumsAsset)
+	}
+
+	checksums, err := s.source.Download(ctx, checksumsAsset.URL)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	if s.publicKey != nil {
+		if err := s.verifySignature(ctx, rel, checksums); err != nil {
+			return err
+		}
+	}
+
+	expected, err := findChecksum(checksums, assetName)
+	if err != nil {
+		return err
+	}
+
+	binary, err := s.source.Download(ctx, binaryAsset.URL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", assetName, err)
+	}
+
+	sum := sha256.Sum256(binary)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", assetName, expected, actual)
+	}
+
+	if err := s.installer.Install(binary, execPath); err != nil {
+		return fmt.Errorf("failed to install update: %w", err)
+	}
+
+	return nil
+}
+
+// verifySignature checks the detached ed25519 signature over the checksums file
+func (s *Service) verifySignature(ctx context.Context, rel *release.Release, checksums []byte) error {
+	sigAsset := rel.FindAsset(release.SignatureAsset)
+	if sigAsset == nil {
+		return fmt.Errorf("release %s is missing %s but signature verification is configured", rel.Version, release.SignatureAsset)
+	}
+
+	sigData, err := s.source.Download(ctx, sigAsset.URL)
+	if err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sigData)))
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if !ed25519.Verify(s.publicKey, checksums, sig) {
+		return fmt.Errorf("signature verification failed for release %s", rel.Version)
+	}
+	return nil
+}
+
+// findChecksum returns the SHA-256 for name from a "<sha256>  <name>" listing
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
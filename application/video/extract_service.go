@@ -3,6 +3,8 @@ package video
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"sync"
 	"time"
 
 	"nac-service-media/domain/video"
@@ -12,6 +14,16 @@ import (
 type ExtractResult struct {
 	OutputPath  string
 	ServiceDate string
+	Reused      bool // An existing valid output was kept instead of re-extracting
+	// Variants are the extra versions at the service's extra bitrates
+	Variants []AudioVariant
+}
+
+// AudioVariant is an extra MP3 of the service at another bitrate
+type AudioVariant struct {
+	Bitrate    string
+	OutputPath string
+	Reused     bool
 }
 
 // ExtractService coordinates audio extraction operations
@@ -20,18 +32,27 @@ type ExtractService struct {
 	fileChecker video.FileChecker
 	outputDir   string
 	bitrate     string
+	overwrite   OverwriteOptions
+	audioTrack  int
+	tags        video.MediaTags
+	extra       []string
 }
 
 // NewExtractService creates a new ExtractService
-func NewExtractService(extractor video.AudioExtractor, fileChecker video.FileChecker, outputDir string, bitrate string) *ExtractService {
+func NewExtractService(extractor video.AudioExtractor, fileChecker video.FileChecker, outputDir string, bitrate string, opts ...Option) *ExtractService {
 	if bitrate == "" {
 		bitrate = video.DefaultAudioBitrate
 	}
+	o := applyOptions(opts)
 	return &ExtractService{
 		extractor:   extractor,
 		fileChecker: fileChecker,
 		outputDir:   outputDir,
 		bitrate:     bitrate,
+		overwrite:   o.overwrite,
+		audioTrack:  o.audioTrack,
+		tags:        o.tags,
+		extra:       o.bitrates,
 	}
 }
 
@@ -70,16 +91,7 @@ func (s *ExtractService) Extract(ctx context.Context, input ExtractInput) (*Extr
 		return nil, err
 	}
 
-	// Perform extraction
-	outputPath := req.OutputPath(s.outputDir)
-	if err := s.extractor.Extract(ctx, req, outputPath); err != nil {
-		return nil, err
-	}
-
-	return &ExtractResult{
-		OutputPath:  outputPath,
-		ServiceDate: req.ServiceDate.Format("2006-01-02"),
-	}, nil
+	return s.run(ctx, req)
 }
 
 // ExtractWithTimestamps extracts audio from a source video using start/end timestamps
@@ -102,14 +114,95 @@ func (s *ExtractService) ExtractWithTimestamps(ctx context.Context, input Extrac
 		return nil, err
 	}
 
-	// Perform extraction
-	outputPath := req.OutputPath(s.outputDir)
-	if err := s.extractor.Extract(ctx, req, outputPath); err != nil {
+	return s.run(ctx, req)
+}
+
+// run applies the overwrite policy and performs the extraction
+func (s *ExtractService) run(ctx context.Context, req *video.AudioExtractionRequest) (*ExtractResult, error) {
+	if err := video.ValidateAudioTrack(s.audioTrack); err != nil {
+		return nil, err
+	}
+	req.AudioTrack = s.audioTrack
+	req.Tags = s.tags
+
+	// Resolve every output first, so an overwrite prompt never races an extraction
+	jobs := make([]extractJob, 0, 1+len(s.extra))
+	for _, r := range append([]*video.AudioExtractionRequest{req}, s.variants(req)...) {
+		outputPath, reuse, err := resolveOutput(ctx, s.fileChecker, s.overwrite, r.OutputPath(s.outputDir))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, extractJob{req: r, outputPath: outputPath, reuse: reuse})
+	}
+
+	if err := s.extractAll(ctx, jobs); err != nil {
 		return nil, err
 	}
 
-	return &ExtractResult{
-		OutputPath:  outputPath,
+	result := &ExtractResult{
+		OutputPath:  jobs[0].outputPath,
 		ServiceDate: req.ServiceDate.Format("2006-01-02"),
-	}, nil
+		Reused:      jobs[0].reuse,
+	}
+	for _, j := range jobs[1:] {
+		result.Variants = append(result.Variants, AudioVariant{Bitrate: j.req.Bitrate, OutputPath: j.outputPath, Reused: j.reuse})
+	}
+	return result, nil
+}
+
+// extractJob is one MP3 a run produces
+type extractJob struct {
+	req        *video.AudioExtractionRequest
+	outputPath string
+	reuse      bool
+}
+
+// variants returns a request per extra bitrate, skipping the main one
+func (s *ExtractService) variants(req *video.AudioExtractionRequest) []*video.AudioExtractionRequest {
+	var reqs []*video.AudioExtractionRequest
+	for _, b := range s.extra {
+		if b != req.Bitrate {
+			reqs = append(reqs, req.AtBitrate(b))
+		}
+	}
+	return reqs
+}
+
+// extractAll runs the jobs that are not reused, as many at once as there are
+// CPUs. The first failure stops the rest.
+func (s *ExtractService) extractAll(ctx context.Context, jobs []extractJob) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	slots := make(chan struct{}, runtime.NumCPU())
+	for _, j := range jobs {
+		if j.reuse {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			if ctx.Err() != nil {
+				return
+			}
+			if err := s.extractor.Extract(ctx, j.req, j.outputPath); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr == nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return firstErr
 }
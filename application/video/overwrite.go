@@ -0,0 +1,160 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/video"
+)
+
+// ConfirmFunc asks whether an existing output file should be replaced
+type ConfirmFunc func(path string) (bool, error)
+
+// OverwriteOptions configures how services handle an output file that already exists
+type OverwriteOptions struct {
+	Policy    video.OverwritePolicy
+	Validator video.MediaValidator // Optional; nil treats any existing file as valid
+	Confirm   ConfirmFunc          // Required by the prompt policy
+}
+
+// Option is a functional option for configuring TrimService and ExtractService
+type Option func(*options)
+
+type options struct {
+	overwrite  OverwriteOptions
+	audioTrack int
+	prober     video.DurationProber
+	calendar   video.ServiceCalendar
+	tags       video.MediaTags
+	watermark  video.WatermarkStyle
+	date       time.Time
+	bitrates   []string
+	chapters   []video.Chapter
+}
+
+// WithOverwrite sets the policy applied when the output file already exists
+func WithOverwrite(o OverwriteOptions) Option {
+	return func(opts *options) {
+		opts.overwrite = o
+	}
+}
+
+// WithAudioTrack selects the 1-based audio stream read from the source;
+// 0 uses ffmpeg's default. A trimmed video keeps only the selected track.
+func WithAudioTrack(track int) Option {
+	return func(opts *options) {
+		opts.audioTrack = track
+	}
+}
+
+// WithDurationProber sets how the source length is read, which is needed to
+// resolve timestamps relative to the end of the file
+func WithDurationProber(p video.DurationProber) Option {
+	return func(opts *options) {
+		opts.prober = p
+	}
+}
+
+// WithTags writes a title and scripture into the output file
+func WithTags(tags video.MediaTags) Option {
+	return func(opts *options) {
+		opts.tags = tags
+	}
+}
+
+// WithWatermark burns text into trimmed videos
+func WithWatermark(style video.WatermarkStyle) Option {
+	return func(opts *options) {
+		opts.watermark = style
+	}
+}
+
+// WithServiceDate names the trimmed video for date, e.g. from --date, instead
+// of reading the date from the recording name
+func WithServiceDate(date time.Time) Option {
+	return func(opts *options) {
+		opts.date = date
+	}
+}
+
+// WithCalendar sets the timezones used to read the service date from an OBS
+// recording name (default: the system timezone)
+func WithCalendar(c video.ServiceCalendar) Option {
+	return func(opts *options) {
+		opts.calendar = c
+	}
+}
+
+// WithExtraBitrates makes ExtractService also produce an MP3 at each bitrate,
+// named YYYY-MM-DD-<bitrate>.mp3, alongside the main one
+func WithExtraBitrates(bitrates []string) Option {
+	return func(opts *options) {
+		opts.bitrates = bitrates
+	}
+}
+
+// WithChapters marks chapters in trimmed videos, e.g. from a cue file
+func WithChapters(chapters []video.Chapter) Option {
+	return func(opts *options) {
+		opts.chapters = chapters
+	}
+}
+
+func applyOptions(opts []Option) options {
+	o := options{overwrite: OverwriteOptions{Policy: video.DefaultOverwritePolicy}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.overwrite.Policy == "" {
+		o.overwrite.Policy = video.DefaultOverwritePolicy
+	}
+	return o
+}
+
+// resolveOutput decides where to write given the overwrite policy. It returns
+// the path to write and whether an existing, valid file should be reused instead.
+func resolveOutput(ctx context.Context, checker video.FileChecker, o OverwriteOptions, path string) (string, bool, error) {
+	if !checker.Exists(path) {
+		return path, false, nil
+	}
+
+	switch o.Policy {
+	case video.OverwriteSkip:
+		// An invalid leftover (e.g. from an interrupted run) is regenerated
+		return path, validOutput(ctx, o.Validator, path) == nil, nil
+
+	case video.OverwriteVersioned:
+		for n := 2; ; n++ {
+			if candidate := video.VersionedPath(path, n); !checker.Exists(candidate) {
+				return candidate, false, nil
+			}
+		}
+
+	case video.OverwritePrompt:
+		if o.Confirm == nil {
+			return "", false, fmt.Errorf("output file already exists: %s (no prompt available; use --on-existing)", path)
+		}
+		replace, err := o.Confirm(path)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to confirm overwrite: %w", err)
+		}
+		if replace {
+			return path, false, nil
+		}
+		if err := validOutput(ctx, o.Validator, path); err != nil {
+			return "", false, fmt.Errorf("existing output is not valid and was not replaced: %w", err)
+		}
+		return path, true, nil
+
+	default:
+		return path, false, nil
+	}
+}
+
+func validOutput(ctx context.Context, validator video.MediaValidator, path string) error {
+	if validator == nil {
+		return nil
+	}
+	return validator.Validate(ctx, path)
+}
@@ -3,6 +3,8 @@ package video
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"nac-service-media/domain/video"
 )
@@ -11,6 +13,10 @@ import (
 type TrimResult struct {
 	OutputPath  string
 	ServiceDate string
+	Reused      bool // An existing valid output was kept instead of re-trimming
+	Start       video.Timestamp
+	End         video.Timestamp
+	Clamped     bool // The requested end was past the end of the source
 }
 
 // TrimService coordinates video trimming operations
@@ -18,14 +24,31 @@ type TrimService struct {
 	trimmer     video.Trimmer
 	fileChecker video.FileChecker
 	outputDir   string
+	overwrite   OverwriteOptions
+	audioTrack  int
+	prober      video.DurationProber
+	calendar    video.ServiceCalendar
+	tags        video.MediaTags
+	watermark   video.WatermarkStyle
+	date        time.Time
+	chapters    []video.Chapter
 }
 
 // NewTrimService creates a new TrimService
-func NewTrimService(trimmer video.Trimmer, fileChecker video.FileChecker, outputDir string) *TrimService {
+func NewTrimService(trimmer video.Trimmer, fileChecker video.FileChecker, outputDir string, opts ...Option) *TrimService {
+	o := applyOptions(opts)
 	return &TrimService{
 		trimmer:     trimmer,
 		fileChecker: fileChecker,
 		outputDir:   outputDir,
+		overwrite:   o.overwrite,
+		audioTrack:  o.audioTrack,
+		prober:      o.prober,
+		calendar:    o.calendar,
+		tags:        o.tags,
+		watermark:   o.watermark,
+		date:        o.date,
+		chapters:    o.chapters,
 	}
 }
 
@@ -43,31 +66,59 @@ func (s *TrimService) Trim(ctx context.Context, input TrimInput) (*TrimResult, e
 		return nil, fmt.Errorf("source file does not exist: %s", input.SourcePath)
 	}
 
-	// Parse timestamps
-	start, err := video.ParseTimestamp(input.StartTime)
+	// Parse timestamps, resolving any relative to the end of the file
+	start, end, err := ResolveRange(ctx, s.prober, input.SourcePath, input.StartTime, input.EndTime)
 	if err != nil {
-		return nil, fmt.Errorf("invalid start time: %w", err)
+		return nil, err
+	}
+	end, clamped, err := FitToSource(ctx, s.prober, input.SourcePath, start, end)
+	if err != nil {
+		return nil, err
 	}
 
-	end, err := video.ParseTimestamp(input.EndTime)
+	// Create trim request, for the given date if any
+	var req *video.TrimRequest
+	if !s.date.IsZero() {
+		req, err = video.NewTrimRequestForDate(input.SourcePath, s.date, start, end)
+	} else {
+		req, err = video.NewTrimRequest(input.SourcePath, start, end)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("invalid end time: %w", err)
+		return nil, err
+	}
+	if err := video.ValidateAudioTrack(s.audioTrack); err != nil {
+		return nil, err
 	}
+	req.AudioTrack = s.audioTrack
+	req.Tags = s.tags
+	req.Chapters = s.chapters
+	// The recording clock may differ from the service timezone
+	if s.date.IsZero() {
+		if req.ServiceDate, err = s.calendar.DateFromFilename(filepath.Base(input.SourcePath)); err != nil {
+			return nil, err
+		}
+	}
+	req.Watermark = s.watermark.For(req.ServiceDate, s.tags)
 
-	// Create trim request
-	req, err := video.NewTrimRequest(input.SourcePath, start, end)
+	// Apply the overwrite policy to an existing output
+	outputPath, reuse, err := resolveOutput(ctx, s.fileChecker, s.overwrite, req.OutputPath(s.outputDir))
 	if err != nil {
 		return nil, err
 	}
 
 	// Perform trim
-	outputPath := req.OutputPath(s.outputDir)
-	if err := s.trimmer.Trim(ctx, req, outputPath); err != nil {
-		return nil, err
+	if !reuse {
+		if err := s.trimmer.Trim(ctx, req, outputPath); err != nil {
+			return nil, err
+		}
 	}
 
 	return &TrimResult{
 		OutputPath:  outputPath,
 		ServiceDate: req.ServiceDate.Format("2006-01-02"),
+		Reused:      reuse,
+		Start:       req.Start,
+		End:         req.End,
+		Clamped:     clamped,
 	}, nil
 }
@@ -0,0 +1,59 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/video"
+)
+
+// ResolveRange turns start and end specs into absolute timestamps. A
+// -HH:MM:SS spec counts back from the end of the source, probing its length;
+// +HH:MM:SS counts from the start of the file for start and from the
+// resolved start for end.
+func ResolveRange(ctx context.Context, prober video.DurationProber, sourcePath, start, end string) (video.Timestamp, video.Timestamp, error) {
+	startSpec, err := video.ParseTimeSpec(start)
+	if err != nil {
+		return video.Timestamp{}, video.Timestamp{}, fmt.Errorf("invalid start time: %w", err)
+	}
+	endSpec, err := video.ParseTimeSpec(end)
+	if err != nil {
+		return video.Timestamp{}, video.Timestamp{}, fmt.Errorf("invalid end time: %w", err)
+	}
+
+	var duration time.Duration
+	if startSpec.NeedsDuration() || endSpec.NeedsDuration() {
+		if prober == nil {
+			return video.Timestamp{}, video.Timestamp{}, fmt.Errorf("timestamps relative to the end of the file need the source duration, which cannot be probed")
+		}
+		if duration, err = prober.Duration(ctx, sourcePath); err != nil {
+			return video.Timestamp{}, video.Timestamp{}, fmt.Errorf("failed to read source duration: %w", err)
+		}
+	}
+
+	startTs, err := startSpec.Resolve(video.Timestamp{}, duration)
+	if err != nil {
+		return video.Timestamp{}, video.Timestamp{}, fmt.Errorf("invalid start time: %w", err)
+	}
+	endTs, err := endSpec.Resolve(startTs, duration)
+	if err != nil {
+		return video.Timestamp{}, video.Timestamp{}, fmt.Errorf("invalid end time: %w", err)
+	}
+	return startTs, endTs, nil
+}
+
+// FitToSource moves an end past the end of the source back to the end of the
+// file, as when a long service outlasts its recording; clamped reports the
+// move. A start past the end is an error. Without a prober, or when the
+// length cannot be read, the range is left for trimming to check.
+func FitToSource(ctx context.Context, prober video.DurationProber, sourcePath string, start, end video.Timestamp) (fitted video.Timestamp, clamped bool, err error) {
+	if prober == nil {
+		return end, false, nil
+	}
+	duration, err := prober.Duration(ctx, sourcePath)
+	if err != nil {
+		return end, false, nil
+	}
+	return video.FitToDuration(start, end, duration)
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	appdistribution "nac-service-media/application/distribution"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/history"
+	"nac-service-media/infrastructure/config"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/spf13/cobra"
+)
+
+var archiveDryRun bool
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Gather past services into archive folders on Drive",
+}
+
+var archiveYearCmd = &cobra.Command{
+	Use:   "year YEAR",
+	Short: "Move a year's services into Archive/YEAR with an index for leadership",
+	Long: `Move every service file dated in YEAR from the Services folder into an
+Archive/YEAR folder, creating it when missing. Moved files keep their links,
+so emails already sent keep working.
+
+An index listing each service's date, minister, title and links is written
+to "YEAR Services Index.html" in the year folder, and the folder is shared
+with the recipients, names or groups listed under year_archive.share_with.
+Running it again moves anything added since and refreshes the index.
+
+Examples:
+  nac-service-media archive year 2025 --dry-run
+  nac-service-media archive year 2025`,
+	Args: cobra.ExactArgs(1),
+	RunE: runArchiveYear,
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.AddCommand(archiveYearCmd)
+	archiveYearCmd.Flags().BoolVar(&archiveDryRun, "dry-run", false, "Show the folders and moves without making them")
+}
+
+func runArchiveYear(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	year, err := strconv.Atoi(args[0])
+	if err != nil || year < 1000 || year > 9999 {
+		return fmt.Errorf("invalid year %q", args[0])
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	return RunArchiveYearWithDependencies(ctx, cfg, client, infrahistory.NewJSONStore(cfg.History.File), year, archiveDryRun, os.Stdout)
+}
+
+// RunArchiveYearWithDependencies runs the archive year command with injected dependencies (for testing)
+func RunArchiveYearWithDependencies(
+	ctx context.Context,
+	cfg *config.Config,
+	driveClient distribution.DriveClient,
+	store history.Store,
+	year int,
+	dryRun bool,
+	output io.Writer,
+) error {
+	var addresses []string
+	if len(cfg.YearArchive.ShareWith) > 0 {
+		recipients, err := config.NewRecipientLookup(cfg, "").ResolveRecipients(cfg.YearArchive.ShareWith, nil)
+		if err != nil {
+			return fmt.Errorf("invalid year_archive.share_with: %w", err)
+		}
+		for _, r := range recipients {
+			addresses = append(addresses, r.Address)
+		}
+	}
+
+	service := appdistribution.NewYearArchiveService(driveClient, cfg.Google.ServicesFolderID,
+		appdistribution.WithYearArchiveHistory(store))
+	req := appdistribution.YearArchiveRequest{
+		Year:       year,
+		FolderName: cfg.YearArchive.FolderName,
+		Church:     cfg.Email.FromName,
+		ShareWith:  addresses,
+		IndexPath:  filepath.Join(os.TempDir(), distribution.YearIndexFileName(year)),
+		DryRun:     dryRun,
+	}
+
+	fmt.Fprintf(output, "Archiving %d services...\n", year)
+	result, err := service.Archive(ctx, req)
+	if errors.Is(err, distribution.ErrFoldersUnsupported) || errors.Is(err, distribution.ErrUserSharingUnsupported) {
+		return fmt.Errorf("%w; archive year needs Google Drive storage", err)
+	}
+	if result == nil {
+		return err
+	}
+
+	created, moved, shared := "Created", "Moved", "Shared with"
+	if dryRun {
+		created, moved, shared = "Would create", "Would move", "Would share with"
+	}
+	for _, name := range result.CreatedFolders {
+		fmt.Fprintf(output, "  %s folder %s\n", created, name)
+	}
+	for _, f := range result.Moved {
+		fmt.Fprintf(output, "  %s: %s\n", moved, f.Name)
+	}
+	for _, f := range result.Failed {
+		fmt.Fprintf(output, "  Warning: could not move %s: %v\n", f.File.Name, f.Err)
+	}
+	if result.AlreadyArchived > 0 {
+		fmt.Fprintf(output, "  %d files were already archived\n", result.AlreadyArchived)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output, "\nIndex lists %d services\n", len(result.Index.Entries))
+	if dryRun {
+		for _, address := range result.SharedWith {
+			fmt.Fprintf(output, "  %s %s\n", shared, address)
+		}
+		fmt.Fprintln(output, "Dry run: nothing was changed. Run without --dry-run to apply.")
+		return nil
+	}
+
+	indexVerb := "Uploaded"
+	if result.IndexUpdated {
+		indexVerb = "Updated"
+	}
+	fmt.Fprintf(output, "  %s %s: %s\n", indexVerb, distribution.YearIndexFileName(year), distribution.LinkFor(driveClient, result.IndexFileID))
+	fmt.Fprintf(output, "  Folder: %s\n", distribution.FolderURL(result.YearFolderID))
+	for _, address := range result.SharedWith {
+		fmt.Fprintf(output, "  %s %s\n", shared, address)
+	}
+	for _, f := range result.ShareFailed {
+		fmt.Fprintf(output, "  Warning: could not share with %s: %v\n", f.Address, f.Err)
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d files could not be moved", len(result.Failed))
+	}
+	if len(result.ShareFailed) > 0 {
+		return fmt.Errorf("the year folder could not be shared with %d addresses", len(result.ShareFailed))
+	}
+	return nil
+}
@@ -10,12 +10,12 @@ import (
 
 	"nac-service-media/domain/notification"
 	"nac-service-media/infrastructure/drive"
+	"nac-service-media/infrastructure/filesystem"
 	"nac-service-media/infrastructure/gmail"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
-	gdrive "google.golang.org/api/drive/v3"
 	ggmail "google.golang.org/api/gmail/v1"
 )
 
@@ -95,17 +95,21 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
 	}
 
-	ctx := cmd.Context()
-	return RunAuthStatusWithDependencies(ctx, cfg.Google.CredentialsFile, cfg.Google.TokenFile, cfg.Google.GmailTokenFile, authFixFlag, os.Stdout)
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	return RunAuthStatusWithDependencies(ctx, cfg.Google.CredentialsFile, cfg.Google.DriveTokenFile, cfg.Google.GmailTokenFile, cfg.Google.ScopeMode, authFixFlag, os.Stdout)
 }
 
-// RunAuthStatusWithDependencies checks OAuth token status with injected dependencies
-func RunAuthStatusWithDependencies(ctx context.Context, credentialsFile, driveTokenFile, gmailTokenFile string, fix bool, output io.Writer) error {
+// RunAuthStatusWithDependencies checks OAuth token status with injected dependencies.
+// driveScopeMode is google.scope_mode, which picks the Drive scope to sign in with.
+func RunAuthStatusWithDependencies(ctx context.Context, credentialsFile, driveTokenFile, gmailTokenFile, driveScopeMode string, fix bool, output io.Writer) error {
 	fmt.Fprintln(output, "Checking OAuth token status...")
 	fmt.Fprintln(output)
 
 	// Check Drive token
-	driveResult := checkToken(ctx, credentialsFile, driveTokenFile, gdrive.DriveScope)
+	driveResult := checkToken(ctx, credentialsFile, driveTokenFile, drive.Scope(driveScopeMode))
 	printTokenStatus(output, "Google Drive", driveResult)
 
 	// Check Gmail token
@@ -130,7 +134,7 @@ func RunAuthStatusWithDependencies(ctx context.Context, credentialsFile, driveTo
 
 	if !driveResult.ok() {
 		fmt.Fprintln(output, "Re-authenticating Google Drive...")
-		_, err := drive.NewClientWithOAuth(ctx, credentialsFile, driveTokenFile)
+		_, err := drive.NewClientWithOAuth(ctx, credentialsFile, driveTokenFile, drive.WithScopeMode(driveScopeMode))
 		if err != nil {
 			return fmt.Errorf("drive re-authentication failed: %w", err)
 		}
@@ -209,7 +213,7 @@ func checkToken(ctx context.Context, credentialsFile, tokenFile, scope string) t
 
 	// Save refreshed token if it changed
 	if newToken.AccessToken != token.AccessToken {
-		if sf, err := os.Create(tokenFile); err == nil {
+		if sf, err := filesystem.CreatePrivate(filesystem.OSFS{}, tokenFile); err == nil {
 			json.NewEncoder(sf).Encode(newToken)
 			sf.Close()
 		}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	appsupport "nac-service-media/application/support"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/support"
+	infraaudit "nac-service-media/infrastructure/audit"
+	"nac-service-media/infrastructure/config"
+	infrahistory "nac-service-media/infrastructure/history"
+	infrasupport "nac-service-media/infrastructure/support"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleLastRun bool
+	bundleOutput  string
+	bundleMaxSize int
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Collect the last run's files into a zip to share when asking for help",
+	Long: `Writes a zip with what is needed to look into a problem with the last run:
+
+  - MANIFEST.txt listing what is included, what was left out and why
+  - the config file
+  - the last history entry and its run summary (summary.dir)
+  - the frames and a file listing of the newest workspace a failed run kept
+  - the last week of the audit log
+
+Passwords, keys and tokens are replaced with [REDACTED] in every file.
+Files over 5 MB are left out, as is anything that would take the bundle
+past --max-size.
+
+Examples:
+  nac-service-media bundle --last-run
+  nac-service-media bundle --last-run --output ~/Desktop/help.zip`,
+	RunE: runBundle,
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+	bundleCmd.Flags().BoolVar(&bundleLastRun, "last-run", false, "Bundle the most recent run")
+	bundleCmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "Where to write the zip (default nac-service-media-bundle-<time>.zip)")
+	bundleCmd.Flags().IntVar(&bundleMaxSize, "max-size", 20, "Largest bundle to write, in MB")
+}
+
+func runBundle(cmd *cobra.Command, args []string) error {
+	// The config may be what's broken, so bundle without it using the defaults
+	c := GetConfig()
+	if c == nil {
+		c = &config.Config{
+			History: config.HistoryConfig{File: config.DefaultHistoryFile},
+			Audit:   config.AuditConfig{File: config.DefaultAuditFile},
+		}
+	}
+	return RunBundleWithDependencies(BundleInput{
+		LastRun:    bundleLastRun,
+		OutputPath: bundleOutput,
+		MaxSizeMB:  bundleMaxSize,
+		ConfigFile: cfgFile,
+		Config:     c,
+	}, time.Now(), os.Stdout)
+}
+
+// BundleInput contains the input parameters for the bundle command
+type BundleInput struct {
+	LastRun    bool
+	OutputPath string // Empty means a timestamped name in the current directory
+	MaxSizeMB  int
+	ConfigFile string
+	Config     *config.Config
+}
+
+// RunBundleWithDependencies runs the bundle command with injected dependencies (for testing)
+func RunBundleWithDependencies(input BundleInput, now time.Time, output io.Writer) error {
+	if !input.LastRun {
+		return fmt.Errorf("choose what to bundle: --last-run")
+	}
+	if input.MaxSizeMB <= 0 {
+		return fmt.Errorf("invalid --max-size: %d must be positive", input.MaxSizeMB)
+	}
+	path := input.OutputPath
+	if path == "" {
+		path = fmt.Sprintf("nac-service-media-bundle-%s.zip", now.Format("20060102-150405"))
+	}
+
+	limits := support.DefaultLimits
+	limits.MaxTotalBytes = int64(input.MaxSizeMB) * 1024 * 1024
+	sources := []support.Source{
+		infrasupport.ConfigFile{Path: input.ConfigFile},
+		infrasupport.SummaryFiles{Dir: input.Config.Summary.Dir},
+		infrasupport.LatestWorkspace{Base: input.Config.Paths.WorkspaceDirectory},
+	}
+	service := appsupport.NewBundleService(
+		infrahistory.NewJSONStore(input.Config.History.File),
+		infraaudit.NewJSONLog(input.Config.Audit.File),
+		sources,
+		infrasupport.NewZipWriter(),
+		appsupport.WithLimits(limits),
+		appsupport.WithVersion(version),
+		appsupport.WithClock(func() time.Time { return now }),
+	)
+
+	result, err := service.LastRun(path)
+	if err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	m := result.Manifest
+	var total int64
+	for _, f := range m.Files {
+		total += int64(len(f.Data))
+	}
+	fmt.Fprintf(output, "Wrote %s (%d files, %s before compression)\n", result.Path, len(m.Files)+1, distribution.FormatSize(total))
+	if m.Redacted > 0 {
+		fmt.Fprintf(output, "  Scrubbed %d secrets\n", m.Redacted)
+	}
+	for _, s := range m.Skipped {
+		fmt.Fprintf(output, "  Left out %s: %s\n", s.Name, s.Reason)
+	}
+	for _, name := range m.Missing {
+		fmt.Fprintf(output, "  No %s found\n", name)
+	}
+	fmt.Fprintln(output, "Check the bundle before sending it; it includes names and email addresses from the config.")
+	return nil
+}
@@ -23,7 +23,8 @@ Examples:
   nac-service-media config list ministers
   nac-service-media config add minister --key smith --name "Rev. John Smith"
   nac-service-media config add sender --key avteam --name "A/V Team"
-  nac-service-media config remove recipient jane`,
+  nac-service-media config remove recipient jane
+  nac-service-media config find mary`,
 }
 
 func init() {
@@ -34,6 +35,7 @@ func init() {
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configRemoveCmd)
 	configCmd.AddCommand(configUpdateCmd)
+	configCmd.AddCommand(configFindCmd)
 }
 
 // --- ADD command ---
@@ -217,6 +219,50 @@ func RunConfigListWithDependencies(cfg *config.Config, configPath, entityType st
 	return w.Flush()
 }
 
+// --- FIND command ---
+
+var configFindCmd = &cobra.Command{
+	Use:   "find <query>",
+	Short: "Show where a person is defined",
+	Long: `Search ministers, recipients, CC recipients, and senders for a key, first
+name, last name, full name, or email address.
+
+By default send-email --to only matches recipients and --sender only matches
+sender keys. Set email.lookup_all: true to let --to fall back to default CCs
+and --sender match sender names.
+
+Examples:
+  nac-service-media config find mary
+  nac-service-media config find mary@example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigFind,
+}
+
+func runConfigFind(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("config file not found. Run 'nac-service-media setup' first")
+	}
+
+	return RunConfigFindWithDependencies(cfg, cfgFile, args[0], DefaultOutput)
+}
+
+// RunConfigFindWithDependencies runs the find command with injected dependencies
+func RunConfigFindWithDependencies(cfg *config.Config, configPath, query string, out OutputWriter) error {
+	entries := config.NewRecipientLookup(cfg, configPath).Find(query)
+	if len(entries) == 0 {
+		fmt.Fprintf(out, "Nothing in the config matches %q.\n", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WHERE\tKEY\tNAME\tEMAIL")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Section, e.Key, e.Name, e.Address)
+	}
+	return w.Flush()
+}
+
 // --- REMOVE command ---
 
 var configRemoveCmd = &cobra.Command{
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/drive"
+	"nac-service-media/infrastructure/ffmpeg"
+	"nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/gmail"
+
+	"github.com/spf13/cobra"
+)
+
+// Demo run settings
+const (
+	DemoConfigFile   = "demo-config.yaml"
+	DemoSampleFile   = "sample-service.mp4"
+	DemoPreviewFile  = "email-preview.html"
+	DemoFolderID     = "demo-services"
+	demoServiceStart = "00:00:15"
+	demoServiceEnd   = "00:01:45"
+)
+
+// SampleRenderer writes the demo's sample recording
+type SampleRenderer interface {
+	Generate(ctx context.Context, outputPath string) error
+}
+
+var demoDir string
+
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "Run the whole workflow on a sample recording without a Google account",
+	Long: `Run process end to end on a 2-minute sample recording, with Google Drive
+and Gmail simulated in memory. Nothing is uploaded and no email is sent.
+
+The sample is rendered with ffmpeg the first time, then reused. The demo
+trims it, extracts the MP3, "uploads" both, and writes the email it would
+have sent to email-preview.html for opening in a browser. Use it to train
+new volunteers or to check that ffmpeg works on a new install.
+
+The demo uses its own demo-config.yaml with made-up ministers and
+recipients, not config/config.yaml.
+
+Examples:
+  # Run the demo in the system temp folder
+  nac-service-media demo
+
+  # Keep the demo files somewhere easy to find
+  nac-service-media demo --dir ~/Desktop/media-demo`,
+	RunE: runDemo,
+}
+
+func init() {
+	rootCmd.AddCommand(demoCmd)
+	demoCmd.Flags().StringVar(&demoDir, "dir", "", "Folder for the sample recording, outputs and email preview (default: nac-service-media-demo in the temp folder)")
+}
+
+func runDemo(cmd *cobra.Command, args []string) error {
+	dir := demoDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "nac-service-media-demo")
+	}
+	checker := sizedFileChecker{FileChecker: filesystem.NewChecker()}
+	// The demo runs without a config; one that is loaded still picks ffmpeg
+	cfg := GetConfig()
+	return RunDemoWithDependencies(cmd.Context(), dir, newSampleGenerator(cfg), newTrimmer(cfg), newExtractor(cfg), checker, time.Now(), os.Stdout)
+}
+
+// RunDemoWithDependencies renders the sample recording into dir if it is not
+// there yet, processes it against in-memory Drive and Gmail, and writes the
+// email preview. now picks the service date: the most recent Sunday.
+func RunDemoWithDependencies(
+	ctx context.Context,
+	dir string,
+	renderer SampleRenderer,
+	trimmer video.Trimmer,
+	extractor video.AudioExtractor,
+	fileChecker video.FileChecker,
+	now time.Time,
+	output io.Writer,
+) error {
+	cfg, err := writeDemoConfig(dir)
+	if err != nil {
+		return err
+	}
+
+	samplePath := filepath.Join(dir, DemoSampleFile)
+	if _, err := os.Stat(samplePath); err != nil {
+		fmt.Fprintf(output, "Rendering a %s sample recording...\n", ffmpeg.SampleDuration)
+		if err := renderer.Generate(ctx, samplePath); err != nil {
+			return err
+		}
+	}
+
+	// Recordings are named the way OBS names them, so the sample is filed
+	// as if it were recorded last Sunday morning
+	date := lastSunday(now)
+	source := filepath.Join(cfg.Paths.SourceDirectory, date.Format("2006-01-02")+" 10-00-00.mp4")
+	if err := placeRecording(samplePath, source); err != nil {
+		return err
+	}
+
+	driveService := drive.NewMemoryService(
+		drive.WithMemoryClock(func() time.Time { return now }),
+		drive.WithMemoryFolder(DemoFolderID, "Demo Services"),
+	)
+	outbox := gmail.NewOutbox()
+	input := ProcessInput{
+		InputPath:      source,
+		StartTime:      demoServiceStart,
+		EndTime:        demoServiceEnd,
+		MinisterKey:    "smith",
+		RecipientKeys:  []string{"congregation"},
+		DateOverride:   date.Format("2006-01-02"),
+		OnExisting:     string(video.OverwriteReplace),
+		NonInteractive: true,
+	}
+
+	fmt.Fprintf(output, "Demo: processing %s with Drive and Gmail simulated in memory\n\n", source)
+	err = RunProcessWithDependencies(ctx, cfg, trimmer, extractor, fileChecker, driveService, outbox,
+		&demoFileFinder{source: source}, input, output,
+		filesystem.NewDiskUsageChecker(), filesystem.NewRemover())
+	if err != nil {
+		return fmt.Errorf("demo failed: %w", err)
+	}
+
+	return printDemoResult(dir, driveService, outbox, output)
+}
+
+// writeDemoConfig saves the demo's config in dir and loads it back, so the
+// demo gets the same defaults and checks as a real config
+func writeDemoConfig(dir string) (*config.Config, error) {
+	cfg := &config.Config{
+		Paths: config.PathsConfig{
+			SourceDirectory:  filepath.Join(dir, "recordings"),
+			TrimmedDirectory: filepath.Join(dir, "trimmed"),
+			AudioDirectory:   filepath.Join(dir, "audio"),
+		},
+		Audio:  config.AudioConfig{Bitrate: "192k"},
+		Google: config.GoogleConfig{ServicesFolderID: DemoFolderID},
+		Email: config.EmailConfig{
+			FromName:    "Demo Church",
+			FromAddress: "media@demo-church.example.com",
+			Recipients: map[string]config.RecipientConfig{
+				"congregation": {Name: "Demo Congregation", Address: "congregation@demo-church.example.com"},
+			},
+		},
+		Ministers: map[string]config.MinisterConfig{
+			"smith": {Name: "Pr. Smith"},
+		},
+		Senders: config.SendersConfig{
+			DefaultSender: "volunteer",
+			Senders:       map[string]config.SenderConfig{"volunteer": {Name: "Demo Volunteer"}},
+		},
+	}
+	for _, d := range []string{cfg.Paths.SourceDirectory, cfg.Paths.TrimmedDirectory, cfg.Paths.AudioDirectory} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", d, err)
+		}
+	}
+
+	path := filepath.Join(dir, DemoConfigFile)
+	if err := config.Save(cfg, path); err != nil {
+		return nil, err
+	}
+	return config.Load(path)
+}
+
+// printDemoResult lists what ended up on the simulated Drive and writes the
+// email that would have been sent to the preview file
+func printDemoResult(dir string, driveService *drive.MemoryService, outbox *gmail.Outbox, output io.Writer) error {
+	fmt.Fprintf(output, "\nSimulated Drive folder %s now holds:\n", DemoFolderID)
+	for _, f := range driveService.Files() {
+		if f.MimeType == distribution.MimeTypeFolder {
+			continue
+		}
+		shared := ""
+		if driveService.IsPublic(f.Id) {
+			shared = ", shared with anyone with the link"
+		}
+		fmt.Fprintf(output, "  %s (%s%s)\n", f.Name, distribution.FormatSize(f.Size), shared)
+	}
+
+	sent, err := outbox.Sent()
+	if err != nil {
+		return err
+	}
+	if len(sent) == 0 {
+		return fmt.Errorf("demo failed: no email was sent")
+	}
+	email := sent[len(sent)-1]
+
+	preview := filepath.Join(dir, DemoPreviewFile)
+	if err := os.WriteFile(preview, []byte(email.HTML), 0644); err != nil {
+		return fmt.Errorf("failed to write the email preview: %w", err)
+	}
+
+	fmt.Fprintln(output, "\nEmail that would have been sent:")
+	fmt.Fprintf(output, "  To:      %s\n", email.To)
+	fmt.Fprintf(output, "  Subject: %s\n", email.Subject)
+	fmt.Fprintf(output, "  Preview: %s\n", preview)
+	fmt.Fprintln(output, "\nNothing was uploaded and no email was sent. Open the preview in a browser to see the email.")
+	return nil
+}
+
+// placeRecording links the sample into the recordings folder, copying it
+// where links are not supported
+func placeRecording(sample, recording string) error {
+	if _, err := os.Stat(recording); err == nil {
+		return nil
+	}
+	if err := os.Link(sample, recording); err == nil {
+		return nil
+	}
+	src, err := os.Open(sample)
+	if err != nil {
+		return fmt.Errorf("failed to read the sample recording: %w", err)
+	}
+	defer src.Close()
+	dst, err := os.Create(recording)
+	if err != nil {
+		return fmt.Errorf("failed to place the sample recording: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to place the sample recording: %w", err)
+	}
+	return dst.Close()
+}
+
+// lastSunday returns the most recent Sunday on or before t
+func lastSunday(t time.Time) time.Time {
+	return t.AddDate(0, 0, -int(t.Weekday()))
+}
+
+// sizedFileChecker reports real file sizes alongside a FileChecker, so the
+// demo's summary shows the sizes of its outputs
+type sizedFileChecker struct {
+	video.FileChecker
+	productionFileSizer
+}
+
+// demoFileFinder only ever finds the sample recording
+type demoFileFinder struct {
+	source string
+}
+
+func (f *demoFileFinder) FindNewestFile(dir, ext string) (string, error) {
+	files, _ := f.ListFiles(dir, ext)
+	if len(files) == 0 {
+		return "", fmt.Errorf("no video files found in %s", dir)
+	}
+	return files[0], nil
+}
+
+func (f *demoFileFinder) ListFiles(dir, ext string) ([]string, error) {
+	if filepath.Clean(dir) != filepath.Dir(f.source) || !strings.EqualFold(filepath.Ext(f.source), ext) {
+		return nil, nil
+	}
+	return []string{f.source}, nil
+}
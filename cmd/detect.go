@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"nac-service-media/domain/history"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/spf13/cobra"
+)
+
+var detectStatsLast int
+
+var detectCmd = &cobra.Command{
+	Use:   "detect",
+	Short: "Inspect automatic timestamp detection",
+}
+
+var detectStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how start detection match scores are trending",
+	Long: `Show the start detection match score recorded for each processed service,
+the recent trend, and whether scores have stayed below detection.drift.threshold
+for detection.drift.weeks services in a row. Falling scores mean the templates
+no longer match the room; re-capture them before detection fails.
+
+Scores come from history.file; services whose start was given with --start
+have no score.
+
+Examples:
+  nac-service-media detect stats
+  nac-service-media detect stats --last 26`,
+	RunE: runDetectStats,
+}
+
+func init() {
+	rootCmd.AddCommand(detectCmd)
+	detectCmd.AddCommand(detectStatsCmd)
+	detectStatsCmd.Flags().IntVar(&detectStatsLast, "last", 12, "Number of most recent services to list (0 for all)")
+}
+
+func runDetectStats(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	store := infrahistory.NewJSONStore(cfg.History.File)
+	return RunDetectStatsWithDependencies(store, cfg.Detection.DriftThreshold(), cfg.Detection.Drift.Weeks, detectStatsLast, os.Stdout)
+}
+
+// RunDetectStatsWithDependencies runs the detect stats command with injected dependencies (for testing)
+func RunDetectStatsWithDependencies(store history.Store, threshold float64, weeks, last int, output io.Writer) error {
+	entries, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	drift := history.NewDrift(entries, threshold, weeks)
+	if len(drift.Scores) == 0 {
+		fmt.Fprintln(output, "No detection scores recorded yet")
+		return nil
+	}
+
+	fmt.Fprintf(output, "Start detection scores (alert below %.0f%% for %d services in a row):\n", drift.Threshold*100, drift.Weeks)
+	shown := drift.Scores
+	if last > 0 && len(shown) > last {
+		shown = shown[len(shown)-last:]
+	}
+	for _, s := range shown {
+		line := fmt.Sprintf("  %s  %3.0f%%", s.ServiceDate.Format("2006-01-02"), s.Confidence*100)
+		if s.CameraAngle != "" {
+			line += "  " + s.CameraAngle
+		}
+		if s.Confidence < drift.Threshold {
+			line += "  (low)"
+		}
+		if s.EarlyExit {
+			line += "  (early exit)"
+		}
+		fmt.Fprintln(output, line)
+	}
+
+	if recent, previous, ok := drift.Change(); ok {
+		fmt.Fprintf(output, "Trend: last %d average %.0f%%, previous %d average %.0f%% (%+.0f points)\n",
+			drift.Weeks, recent*100, drift.Weeks, previous*100, (recent-previous)*100)
+	}
+
+	if n := drift.EarlyExits(); n > 0 {
+		fmt.Fprintf(output, "Early exit: %d of %d detections stopped on a confident frame bracket\n", n, len(drift.Scores))
+	}
+
+	if drift.Alert() {
+		fmt.Fprintf(output, "Warning: scores have been below %.0f%% for %d services in a row; re-capture the detection templates\n",
+			drift.Threshold*100, drift.LowStreak())
+	} else {
+		fmt.Fprintln(output, "Scores are healthy")
+	}
+	return nil
+}
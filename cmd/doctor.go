@@ -0,0 +1,480 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	appdoctor "nac-service-media/application/doctor"
+	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
+	"nac-service-media/infrastructure/config"
+	infradetection "nac-service-media/infrastructure/detection"
+	"nac-service-media/infrastructure/drive"
+	infrafs "nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/network"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	ggmail "google.golang.org/api/gmail/v1"
+)
+
+// googleEndpoints are the hosts the Drive and Gmail clients talk to
+var googleEndpoints = []string{
+	"https://oauth2.googleapis.com",
+	"https://www.googleapis.com",
+	"https://gmail.googleapis.com",
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment for common problems",
+	Long: `Run checks that catch setup problems before a service is processed.
+
+The network check reaches each Google API host through the configured proxy
+(network.proxy_url, else HTTPS_PROXY) and CA bundle, so proxy and TLS
+inspection problems show up here instead of halfway through an upload.
+
+The source check looks in every paths.source_directories folder, in priority
+order, and reports how many recordings each holds.
+
+The path check reports configured paths written for another system, such as
+C:\Users\... on plain Linux, and files or folders that do not exist. Under
+WSL, Windows drive paths are converted to /mnt/<drive> when the config loads.
+
+The token check shows where the Drive and Gmail OAuth tokens are kept, warns
+when other users can read them (they should be 0600) or when a token was left
+behind in the working directory, and asks Google which scopes each token was
+granted, failing when one lacks the scope this app needs.
+
+The Drive folder check looks up google.services_folder_id and fails when it
+is not a folder, cannot be found, or is shared with this account read-only;
+otherwise it shows the folder's name so you can confirm it is the right one.
+It is skipped until the Drive token is set up.
+
+Examples:
+  nac-service-media doctor`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	var scopes TokenScopes
+	if client, err := network.NewHTTPClient(networkSettings(cfg)); err == nil {
+		scopes = grantedScopes(cfg.Google.CredentialsFile, client)
+	}
+	return RunDoctorWithDependencies(cmd.Context(), cfg, googleEndpoints, infrafs.DetectPlatform(), scopes, doctorDriveClient(cmd.Context(), cfg), os.Stdout)
+}
+
+// doctorDriveClient signs in to Drive with the saved token, or returns nil
+// when there is none; the token check explains why
+func doctorDriveClient(ctx context.Context, cfg *config.Config) distribution.DriveClient {
+	if cfg.UsesS3() {
+		return nil
+	}
+	ctx, err := googleContext(ctx, cfg)
+	if err != nil {
+		return nil
+	}
+	client, err := newStorageClient(ctx, cfg, drive.WithNonInteractiveAuth())
+	if err != nil {
+		return nil
+	}
+	return client
+}
+
+// RunDoctorWithDependencies runs the doctor checks against the given
+// endpoints, judging configured paths for the given platform. scopes looks
+// up what each OAuth token was granted and driveClient looks up the services
+// folder; nil skips that check.
+func RunDoctorWithDependencies(ctx context.Context, cfg *config.Config, endpoints []string, platform domainfs.Platform, scopes TokenScopes, driveClient distribution.DriveClient, output io.Writer) error {
+	checks := []appdoctor.Check{
+		&sourceCheck{dirs: cfg.Paths.Sources()},
+		&pathCheck{settings: cfg.PathSettings(), platform: platform},
+		&tokenCheck{tokens: oauthTokens(cfg), platform: platform, scopes: scopes},
+		&networkCheck{settings: networkSettings(cfg), endpoints: endpoints},
+		&detectionCheck{enabled: cfg.Detection.Enabled, available: infradetection.Available},
+	}
+	if driveClient != nil {
+		checks = append(checks, &driveFolderCheck{client: driveClient, folderID: cfg.Google.ServicesFolderID})
+	}
+
+	if failed := appdoctor.NewService(output, checks...).Run(ctx); failed > 0 {
+		return fmt.Errorf("%d doctor check(s) failed", failed)
+	}
+	fmt.Fprintln(output, "All checks passed.")
+	return nil
+}
+
+// sourceCheck verifies each source directory can be read. A missing folder
+// is only a warning while another can be used, since a capture card's folder
+// may be absent when the card is unplugged.
+type sourceCheck struct {
+	dirs []string
+}
+
+func (c *sourceCheck) Name() string {
+	return "Source directories"
+}
+
+func (c *sourceCheck) Run(ctx context.Context) []appdoctor.Result {
+	if len(c.dirs) == 0 {
+		return []appdoctor.Result{{Status: appdoctor.StatusWarn, Detail: "paths.source_directory (or source_directories) is not set"}}
+	}
+
+	finder := &ProductionFileFinder{}
+	var results []appdoctor.Result
+	readable := 0
+	for i, dir := range c.dirs {
+		label := dir
+		if len(c.dirs) > 1 {
+			label = fmt.Sprintf("%d. %s", i+1, dir)
+		}
+		files, err := finder.ListFiles(dir, ".mp4")
+		if errors.Is(err, os.ErrNotExist) {
+			results = append(results, appdoctor.Result{Status: appdoctor.StatusWarn, Detail: label + ": not found"})
+			continue
+		}
+		if err != nil {
+			results = append(results, appdoctor.Result{Status: appdoctor.StatusWarn, Detail: fmt.Sprintf("%s: %v", label, err)})
+			continue
+		}
+		readable++
+		results = append(results, appdoctor.Result{Detail: fmt.Sprintf("%s: %d recording(s)", label, len(files))})
+	}
+	if readable == 0 {
+		results = append(results, appdoctor.Result{Status: appdoctor.StatusFail, Detail: "no source directory can be read"})
+	}
+	return results
+}
+
+// pathCheck fails paths written for another system and warns about ones that
+// do not exist
+type pathCheck struct {
+	settings []config.PathSetting
+	platform domainfs.Platform
+}
+
+func (c *pathCheck) Name() string {
+	return "Configured paths"
+}
+
+func (c *pathCheck) Run(ctx context.Context) []appdoctor.Result {
+	results := []appdoctor.Result{{Detail: "running on " + platformName(c.platform)}}
+	checked := 0
+	for _, setting := range c.settings {
+		path := domainfs.NormalizePath(*setting.Value, c.platform)
+		if path == "" {
+			continue
+		}
+		checked++
+		if err := domainfs.CheckPathStyle(path, c.platform); err != nil {
+			results = append(results, appdoctor.Result{Status: appdoctor.StatusFail, Detail: fmt.Sprintf("%s: %v", setting.Key, err)})
+			continue
+		}
+		// The source check reports missing source directories
+		if setting.Created || strings.HasPrefix(setting.Key, "paths.source_director") {
+			continue
+		}
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			results = append(results, appdoctor.Result{Status: appdoctor.StatusWarn, Detail: fmt.Sprintf("%s: %s not found%s", setting.Key, path, c.mountHint(path))})
+		}
+	}
+	if len(results) == 1 {
+		results = append(results, appdoctor.Result{Detail: fmt.Sprintf("%d configured path(s) look right", checked)})
+	}
+	return results
+}
+
+// mountHint explains a missing /mnt/<drive> path whose drive WSL has not mounted
+func (c *pathCheck) mountHint(path string) string {
+	if c.platform != domainfs.PlatformWSL || domainfs.DetectPathStyle(path) != domainfs.PathStyleWSL {
+		return ""
+	}
+	mount := path[:len("/mnt/x")]
+	if _, err := os.Stat(mount); err == nil {
+		return ""
+	}
+	return fmt.Sprintf("; drive %s: is not mounted in WSL (check automount in /etc/wsl.conf)", strings.ToUpper(mount[len(mount)-1:]))
+}
+
+func platformName(platform domainfs.Platform) string {
+	switch platform {
+	case domainfs.PlatformWSL:
+		return "WSL; Windows drives are under /mnt/<drive>"
+	case domainfs.PlatformWindows:
+		return "Windows"
+	}
+	return "Linux or macOS"
+}
+
+// oauthToken is a saved OAuth token and the scope this app asks it for
+type oauthToken struct {
+	key   string
+	path  string
+	scope string
+}
+
+// oauthTokens lists the tokens this config uses; S3 storage needs no Drive token
+func oauthTokens(cfg *config.Config) []oauthToken {
+	var tokens []oauthToken
+	if !cfg.UsesS3() {
+		tokens = append(tokens, oauthToken{key: "google.drive_token_file", path: cfg.Google.DriveTokenFile, scope: drive.Scope(cfg.Google.ScopeMode)})
+	}
+	return append(tokens, oauthToken{key: "google.gmail_token_file", path: cfg.Google.GmailTokenFile, scope: ggmail.GmailSendScope})
+}
+
+// TokenScopes returns the scopes the OAuth token saved in tokenFile was granted
+type TokenScopes func(ctx context.Context, tokenFile string) ([]string, error)
+
+// tokenCheck reports where each OAuth token is kept, warns when other users
+// can read it and fails when it lacks the scope this app asks for
+type tokenCheck struct {
+	tokens   []oauthToken
+	platform domainfs.Platform
+	scopes   TokenScopes
+}
+
+func (c *tokenCheck) Name() string {
+	return "OAuth tokens"
+}
+
+func (c *tokenCheck) Run(ctx context.Context) []appdoctor.Result {
+	var results []appdoctor.Result
+	for _, token := range c.tokens {
+		results = append(results, c.check(ctx, token)...)
+	}
+	return results
+}
+
+func (c *tokenCheck) check(ctx context.Context, token oauthToken) []appdoctor.Result {
+	path := domainfs.NormalizePath(token.path, c.platform)
+	if path == "" {
+		return []appdoctor.Result{{Status: appdoctor.StatusWarn, Detail: token.key + " is not set"}}
+	}
+	// The path check reports paths written for another system
+	if domainfs.CheckPathStyle(path, c.platform) != nil {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		detail := fmt.Sprintf("%s: %s not found; run 'nac-service-media auth status --fix' to sign in", token.key, path)
+		if left := leftBehind(path); left != "" {
+			detail = fmt.Sprintf("%s: %s not found, but %s is; move it there", token.key, path, left)
+		}
+		return []appdoctor.Result{{Status: appdoctor.StatusWarn, Detail: detail}}
+	}
+	if err != nil {
+		return []appdoctor.Result{{Status: appdoctor.StatusWarn, Detail: fmt.Sprintf("%s: %v", token.key, err)}}
+	}
+
+	results := []appdoctor.Result{{Detail: fmt.Sprintf("%s: %s", token.key, path)}}
+	if mode := info.Mode().Perm(); c.platform != domainfs.PlatformWindows && mode&0077 != 0 {
+		results = append(results, appdoctor.Result{Status: appdoctor.StatusWarn, Detail: fmt.Sprintf("%s: other users can read it (mode %04o); run chmod 600 %s", token.key, mode, path)})
+	}
+	if c.scopes == nil {
+		return results
+	}
+	granted, err := c.scopes(ctx, path)
+	switch {
+	case err != nil:
+		results = append(results, appdoctor.Result{Status: appdoctor.StatusWarn, Detail: fmt.Sprintf("%s: cannot check its scopes: %v", token.key, err)})
+	case !scopeGranted(granted, token.scope):
+		results = append(results, appdoctor.Result{Status: appdoctor.StatusFail, Detail: fmt.Sprintf("%s: granted %s, not %s; delete %s and run 'nac-service-media auth status --fix'", token.key, strings.Join(granted, " "), token.scope, path)})
+	default:
+		results = append(results, appdoctor.Result{Detail: fmt.Sprintf("%s: granted %s", token.key, token.scope)})
+	}
+	return results
+}
+
+// leftBehind returns a token of the same name in the working directory, where
+// tokens were kept before google.token_dir moved them
+func leftBehind(path string) string {
+	old, err := filepath.Abs(filepath.Base(path))
+	if err != nil || old == path {
+		return ""
+	}
+	if _, err := os.Stat(old); err != nil {
+		return ""
+	}
+	return old
+}
+
+// scopeGranted reports whether want, or a broader scope covering it such as
+// drive for drive.file, is among the granted scopes
+func scopeGranted(granted []string, want string) bool {
+	for _, scope := range granted {
+		if scope == want || strings.HasPrefix(want, scope+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenInfoURL is Google's endpoint describing an access token
+const tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// grantedScopes refreshes a saved token with the OAuth client in
+// credentialsFile, then asks Google which scopes it was granted. The
+// refreshed token is not saved.
+func grantedScopes(credentialsFile string, client *http.Client) TokenScopes {
+	return func(ctx context.Context, tokenFile string) ([]string, error) {
+		creds, err := os.ReadFile(credentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read OAuth credentials file: %w", err)
+		}
+		oauthCfg, err := google.ConfigFromJSON(creds)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse OAuth credentials: %w", err)
+		}
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		var saved oauth2.Token
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return nil, fmt.Errorf("corrupt token: %w", err)
+		}
+		token, err := oauthCfg.TokenSource(network.Context(ctx, client), &saved).Token()
+		if err != nil {
+			return nil, fmt.Errorf("cannot refresh: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenInfoURL+"?access_token="+url.QueryEscape(token.AccessToken), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("tokeninfo answered HTTP %d", resp.StatusCode)
+		}
+		var info struct {
+			Scope string `json:"scope"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+			return nil, fmt.Errorf("unreadable tokeninfo answer: %w", err)
+		}
+		return strings.Fields(info.Scope), nil
+	}
+}
+
+// detectionCheck warns when detection.enabled is set in a build without
+// detection, where process needs --start and --end given by hand
+type detectionCheck struct {
+	enabled   bool
+	available bool
+}
+
+func (c *detectionCheck) Name() string {
+	return "Timestamp detection"
+}
+
+func (c *detectionCheck) Run(ctx context.Context) []appdoctor.Result {
+	switch {
+	case !c.enabled:
+		return []appdoctor.Result{{Detail: "disabled in config; give --start and --end"}}
+	case !c.available:
+		return []appdoctor.Result{{Status: appdoctor.StatusWarn, Detail: "detection.enabled is set, but this build has no detection (rebuild with -tags=detection); give --start and --end"}}
+	}
+	return []appdoctor.Result{{Detail: "available"}}
+}
+
+// driveFolderCheck verifies the services folder is a folder this account can
+// upload to, and names it so a mistyped ID that happens to exist is noticed
+type driveFolderCheck struct {
+	client   distribution.DriveClient
+	folderID string
+}
+
+func (c *driveFolderCheck) Name() string {
+	return "Drive folder"
+}
+
+func (c *driveFolderCheck) Run(ctx context.Context) []appdoctor.Result {
+	if c.folderID == "" {
+		return []appdoctor.Result{{Status: appdoctor.StatusFail, Detail: "google.services_folder_id is not set"}}
+	}
+	folder, err := appdist.CheckFolder(ctx, c.client, c.folderID)
+	switch {
+	case errors.Is(err, distribution.ErrFolderNotFound), errors.Is(err, distribution.ErrNotAFolder),
+		errors.Is(err, distribution.ErrFolderNotWritable):
+		return []appdoctor.Result{{Status: appdoctor.StatusFail, Detail: fmt.Sprintf("google.services_folder_id: %v", err)}}
+	case err != nil:
+		return []appdoctor.Result{{Status: appdoctor.StatusWarn, Detail: fmt.Sprintf("google.services_folder_id: cannot look it up: %v", err)}}
+	case folder == nil:
+		return []appdoctor.Result{{Detail: "this storage cannot look folders up"}}
+	}
+	return []appdoctor.Result{{Detail: fmt.Sprintf("google.services_folder_id: %s (%s), files can be added", folder.Name, folder.ID)}}
+}
+
+// networkCheck verifies the Google API hosts can be reached the way the Drive
+// and Gmail clients reach them
+type networkCheck struct {
+	settings  network.Settings
+	endpoints []string
+}
+
+func (c *networkCheck) Name() string {
+	return "Network"
+}
+
+func (c *networkCheck) Run(ctx context.Context) []appdoctor.Result {
+	client, err := network.NewHTTPClient(c.settings)
+	if err != nil {
+		return []appdoctor.Result{{Status: appdoctor.StatusFail, Detail: err.Error()}}
+	}
+	client.Timeout = 15 * time.Second
+
+	var results []appdoctor.Result
+	if c.settings.CABundle != "" {
+		results = append(results, appdoctor.Result{Detail: "CA bundle: " + c.settings.CABundle})
+	}
+	for _, endpoint := range c.endpoints {
+		results = append(results, c.probe(ctx, client, endpoint))
+	}
+	return results
+}
+
+func (c *networkCheck) probe(ctx context.Context, client *http.Client, endpoint string) appdoctor.Result {
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	proxy, source, err := network.ProxyFor(c.settings, endpoint)
+	if err != nil {
+		return appdoctor.Result{Status: appdoctor.StatusFail, Detail: fmt.Sprintf("%s: %v", host, err)}
+	}
+	route := "directly"
+	if proxy != nil {
+		route = fmt.Sprintf("through proxy %s (%s)", proxy.Redacted(), source)
+	}
+
+	status, err := network.Probe(ctx, client, endpoint)
+	if err != nil {
+		return appdoctor.Result{Status: appdoctor.StatusFail, Detail: fmt.Sprintf("%s: cannot connect %s: %v", host, route, err)}
+	}
+	return appdoctor.Result{Detail: fmt.Sprintf("%s: reachable %s (HTTP %d)", host, route, status)}
+}
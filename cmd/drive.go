@@ -0,0 +1,542 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/history"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	driveShareDate   string
+	driveShareFolder string
+	driveUsageTop    int
+	driveUsageTarget string
+
+	driveCleanupEnsure string
+	driveCleanupTarget string
+
+	driveBackfillDryRun    bool
+	driveBackfillBatchSize int
+	driveBackfillPause     time.Duration
+)
+
+var driveCmd = &cobra.Command{
+	Use:   "drive",
+	Short: "Manage uploaded files in Google Drive",
+}
+
+var driveShareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Set public sharing on a service's uploaded files",
+	Long: `Set "anyone with the link" sharing on the video and audio uploaded for a service.
+
+Use this when an upload succeeded but sharing failed, so recipients can open the
+links that were already emailed.
+
+Examples:
+  nac-service-media drive share --date 2025-12-28`,
+	RunE: runDriveShare,
+}
+
+var driveUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show Drive storage used by the Services folder",
+	Long: `Show total Drive usage, per-year totals for files in the Services folder,
+and the largest files.
+
+With --reclaim, also recommend which months to archive (oldest first) to free
+that much space.
+
+Examples:
+  nac-service-media drive usage
+  nac-service-media drive usage --top 5 --reclaim 20GB`,
+	RunE: runDriveUsage,
+}
+
+var driveCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete the oldest recordings to free Drive space",
+	Long: `Delete the oldest mp4 files in the Services folder, the same way process does
+before an upload, until enough space is available.
+
+--ensure-space makes room for an upload of the given size; --target-free keeps
+deleting until that absolute amount of space is free. Sizes accept units such
+as 500MB, 2GB or 1.5TB (1GB = 1024MB).
+
+When publish is configured, recordings already on the alternate download
+server (same name and size, with their .sha256 file) are deleted first, oldest
+first, before any recording whose only copy is on Drive.
+
+Examples:
+  nac-service-media drive cleanup --ensure-space 2GB
+  nac-service-media drive cleanup --target-free 5GB`,
+	RunE: runDriveCleanup,
+}
+
+var driveBackfillCmd = &cobra.Command{
+	Use:   "backfill-metadata",
+	Short: "Tag older uploads with the metadata new uploads get",
+	Long: `Scan the Services folder and tag files uploaded before metadata tagging with
+the service_date and media_type app properties, inferred from each file name
+(e.g. 2024-03-10.mp4). Properties a file already has are never changed.
+
+Files are tagged in batches with a pause between them to stay under Drive's
+rate limits. Files whose date or type cannot be worked out are listed and left
+alone; rename them to YYYY-MM-DD.mp4 or .mp3 and run again.
+
+Examples:
+  nac-service-media drive backfill-metadata --dry-run
+  nac-service-media drive backfill-metadata --batch-size 50 --pause 10s`,
+	RunE: runDriveBackfill,
+}
+
+var driveOrphansCmd = &cobra.Command{
+	Use:   "report-orphans",
+	Short: "Report forgotten files in the Services folder",
+	Long: `Look for files in the Services folder that seem forgotten and recommend
+whether to delete or keep each one:
+
+  - files in the folder's trash, which still count against storage
+  - publicly shared files that no run in history links to
+  - files named unlike anything this tool uploads
+
+Nothing is changed; delete or restore files in Drive once you have checked
+the report.
+
+Examples:
+  nac-service-media drive report-orphans`,
+	RunE: runDriveOrphans,
+}
+
+func init() {
+	rootCmd.AddCommand(driveCmd)
+	driveCmd.AddCommand(driveShareCmd)
+
+	driveShareCmd.Flags().StringVar(&driveShareDate, "date", "", "Service date in YYYY-MM-DD format (required)")
+	driveShareCmd.Flags().StringVar(&driveShareFolder, "folder-id", "", "Drive folder the files were uploaded to (defaults to google.services_folder_id)")
+	driveShareCmd.MarkFlagRequired("date")
+
+	driveCmd.AddCommand(driveUsageCmd)
+	driveUsageCmd.Flags().IntVar(&driveUsageTop, "top", appdist.DefaultUsageTop, "Number of largest files to list")
+	driveUsageCmd.Flags().StringVar(&driveUsageTarget, "reclaim", "", "Space to free, e.g. 20GB, to get an archive recommendation")
+
+	driveCmd.AddCommand(driveCleanupCmd)
+	driveCleanupCmd.Flags().StringVar(&driveCleanupEnsure, "ensure-space", "", "Make room for an upload of this size, e.g. 2GB or 500MB")
+	driveCleanupCmd.Flags().StringVar(&driveCleanupTarget, "target-free", "", "Delete until this much space is free, e.g. 5GB")
+
+	driveCmd.AddCommand(driveOrphansCmd)
+
+	driveCmd.AddCommand(driveBackfillCmd)
+	driveBackfillCmd.Flags().BoolVar(&driveBackfillDryRun, "dry-run", false, "List the files that would be tagged without changing them")
+	driveBackfillCmd.Flags().IntVar(&driveBackfillBatchSize, "batch-size", appdist.DefaultBackfillBatchSize, "Files to tag between pauses")
+	driveBackfillCmd.Flags().DurationVar(&driveBackfillPause, "pause", appdist.DefaultBackfillPause, "Wait between batches")
+	driveCleanupCmd.MarkFlagsMutuallyExclusive("ensure-space", "target-free")
+	driveCleanupCmd.MarkFlagsOneRequired("ensure-space", "target-free")
+}
+
+func runDriveShare(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	scanner, err := newShareScanner(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Drive's copies cannot be scanned, so the local outputs are
+	return RunDriveShareWithDependencies(ctx, client, servicesFolder(cfg, driveShareFolder), driveShareDate, os.Stdout,
+		appdist.WithScanner(scanner), appdist.WithLocalCopies(cfg.Paths.TrimmedDirectory, cfg.Paths.AudioDirectory),
+		appdist.WithProcessedCheck(cfg.Google.ProcessedCheck))
+}
+
+// RunDriveShareWithDependencies runs the drive share command with injected dependencies (for testing)
+func RunDriveShareWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	date string,
+	output io.Writer,
+	opts ...appdist.ShareOption,
+) error {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
+	}
+
+	fmt.Fprintf(output, "Sharing files for %s...\n", date)
+	service := appdist.NewShareService(driveClient, folderID, opts...)
+	files, err := service.ShareByDate(ctx, date)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		fmt.Fprintf(output, "  Shared: %s\n", f.Name)
+		fmt.Fprintf(output, "          %s\n", distribution.LinkFor(driveClient, f.ID))
+	}
+	return nil
+}
+
+func runDriveUsage(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	return RunDriveUsageWithDependencies(ctx, client, cfg.Google.ServicesFolderID, driveUsageTop, driveUsageTarget, os.Stdout)
+}
+
+// RunDriveUsageWithDependencies runs the drive usage command with injected dependencies (for testing)
+func RunDriveUsageWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	top int,
+	reclaim string,
+	output io.Writer,
+) error {
+	if top < 0 {
+		return fmt.Errorf("--top must be 0 or more")
+	}
+	var target int64
+	if reclaim != "" {
+		var err error
+		if target, err = distribution.ParseSize(reclaim); err != nil {
+			return fmt.Errorf("invalid --reclaim: %w", err)
+		}
+	}
+
+	report, err := appdist.NewUsageService(driveClient, folderID).Report(ctx, top)
+	if err != nil {
+		return err
+	}
+
+	q := report.Quota
+	if q.Unlimited {
+		fmt.Fprintln(output, "Storage: no quota")
+	} else {
+		fmt.Fprintf(output, "Drive storage: %s used of %s (%s available)\n",
+			distribution.FormatSize(q.UsedBytes), distribution.FormatSize(q.TotalBytes), distribution.FormatSize(q.AvailableBytes))
+	}
+	fmt.Fprintf(output, "Services folder: %s in %d files\n", distribution.FormatSize(report.FolderBytes), report.FolderFiles)
+	if distribution.SeesAppFilesOnly(driveClient) {
+		fmt.Fprintln(output, "Only files uploaded by this app are counted (google.scope_mode: file)")
+	}
+
+	if len(report.Years) > 0 || report.UndatedFiles > 0 {
+		fmt.Fprintf(output, "\nBy service year:\n")
+		for _, y := range report.Years {
+			fmt.Fprintf(output, "  %d: %s (%d files)\n", y.Year, distribution.FormatSize(y.Bytes), y.Files)
+		}
+		if report.UndatedFiles > 0 {
+			fmt.Fprintf(output, "  Undated: %s (%d files)\n", distribution.FormatSize(report.UndatedBytes), report.UndatedFiles)
+		}
+	}
+
+	if len(report.Largest) > 0 {
+		fmt.Fprintf(output, "\nLargest files:\n")
+		for i, f := range report.Largest {
+			fmt.Fprintf(output, "  %2d. %-30s %10s\n", i+1, f.Name, distribution.FormatSize(f.Size))
+		}
+	}
+
+	if target > 0 {
+		months := report.ArchiveRecommendation(target)
+		var freed int64
+		fmt.Fprintf(output, "\nTo reclaim %s, archive:\n", distribution.FormatSize(target))
+		for _, m := range months {
+			fmt.Fprintf(output, "  %s: %s (%d files)\n", m.Label(), distribution.FormatSize(m.Bytes), m.Files)
+			freed += m.Bytes
+		}
+		if freed < target {
+			fmt.Fprintf(output, "Warning: archiving every dated month frees only %s\n", distribution.FormatSize(freed))
+		} else {
+			fmt.Fprintf(output, "Total: %s from %d months\n", distribution.FormatSize(freed), len(months))
+		}
+	}
+	return nil
+}
+
+func runDriveCleanup(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Files already on the alternate download server are deleted first
+	var archive distribution.Archive
+	if cfg.Publish.URL != "" {
+		publisher, err := newPublisher(cfg.Publish, cfg.Publish.Provider)
+		if err != nil {
+			return fmt.Errorf("invalid publish config: %w", err)
+		}
+		defer closePublisher(publisher)
+		archive = distribution.MirrorArchive(publisher)
+	}
+
+	return RunDriveCleanupWithDependencies(ctx, client, cfg.Google.ServicesFolderID,
+		driveCleanupEnsure, driveCleanupTarget, cfg.Google.CleanupConcurrency, archive, os.Stdout)
+}
+
+// RunDriveCleanupWithDependencies runs the drive cleanup command with injected dependencies (for testing).
+// Exactly one of ensureSpace and targetFree must be set. Files in archive, if
+// given, are deleted before the rest.
+func RunDriveCleanupWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	ensureSpace string,
+	targetFree string,
+	concurrency int,
+	archive distribution.Archive,
+	output io.Writer,
+) error {
+	var needed int64
+	var err error
+	switch {
+	case ensureSpace != "" && targetFree != "":
+		return fmt.Errorf("use either --ensure-space or --target-free, not both")
+	case ensureSpace != "":
+		if needed, err = distribution.ParseSize(ensureSpace); err != nil {
+			return fmt.Errorf("invalid --ensure-space: %w", err)
+		}
+		fmt.Fprintf(output, "Making room for a %s upload...\n", distribution.FormatSize(needed))
+	case targetFree != "":
+		if needed, err = distribution.ParseSize(targetFree); err != nil {
+			return fmt.Errorf("invalid --target-free: %w", err)
+		}
+		fmt.Fprintf(output, "Freeing space until %s is available...\n", distribution.FormatSize(needed))
+	default:
+		return fmt.Errorf("--ensure-space or --target-free is required")
+	}
+
+	service := appdist.NewCleanupService(driveClient, folderID,
+		appdist.WithCleanupConcurrency(concurrency),
+		appdist.WithArchive(archive),
+		appdist.WithCleanupProgress(func(p distribution.CleanupProgress) {
+			fmt.Fprintf(output, "  %s\n", p)
+		}),
+	)
+	result, err := service.EnsureSpaceAvailable(ctx, needed)
+	for _, fd := range result.Failed {
+		fmt.Fprintf(output, "  Warning: could not remove %s: %v\n", fd.Name, fd.Err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(result.DeletedFiles) == 0 {
+		fmt.Fprintln(output, "Storage OK, nothing removed")
+		return nil
+	}
+	fmt.Fprintln(output)
+	fmt.Fprint(output, distribution.DeletedTable(result.DeletedFiles, ""))
+	fmt.Fprintf(output, "Freed %s from %d files\n", distribution.FormatSize(result.FreedBytes), len(result.DeletedFiles))
+	return nil
+}
+
+func runDriveBackfill(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	return RunDriveBackfillWithDependencies(ctx, client, cfg.Google.ServicesFolderID,
+		driveBackfillDryRun, driveBackfillBatchSize, driveBackfillPause, os.Stdout)
+}
+
+// RunDriveBackfillWithDependencies runs the drive backfill-metadata command with injected dependencies (for testing)
+func RunDriveBackfillWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	dryRun bool,
+	batchSize int,
+	pause time.Duration,
+	output io.Writer,
+) error {
+	if batchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+	if pause < 0 {
+		return fmt.Errorf("--pause must not be negative")
+	}
+
+	service := appdist.NewBackfillService(driveClient, folderID,
+		appdist.WithBackfillBatchSize(batchSize),
+		appdist.WithBackfillPause(pause),
+		appdist.WithBackfillProgress(func(done, total int) {
+			fmt.Fprintf(output, "  Tagged %d of %d files\n", done, total)
+		}))
+
+	fmt.Fprintln(output, "Scanning the Services folder for untagged files...")
+	result, err := service.Backfill(ctx, dryRun)
+	if errors.Is(err, distribution.ErrTaggingUnsupported) {
+		return fmt.Errorf("%w; backfill-metadata needs Google Drive storage", err)
+	}
+	if dryRun {
+		for _, f := range result.Tagged {
+			fmt.Fprintf(output, "  Would tag: %s (%s)\n", f.Name, formatProperties(f.Properties))
+		}
+	}
+	for _, f := range result.Failed {
+		fmt.Fprintf(output, "  Warning: could not tag %s: %v\n", f.Name, f.Err)
+	}
+	if len(result.Unclassified) > 0 {
+		fmt.Fprintf(output, "Could not classify %d files; rename them to YYYY-MM-DD.mp4 or .mp3 and run again:\n", len(result.Unclassified))
+		for _, f := range result.Unclassified {
+			fmt.Fprintf(output, "  %s\n", f.Name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	verb := "Tagged"
+	if dryRun {
+		verb = "Would tag"
+	}
+	fmt.Fprintf(output, "%s %d files, %d already tagged, %d unclassified\n",
+		verb, len(result.Tagged), result.AlreadyTagged, len(result.Unclassified))
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d files could not be tagged", len(result.Failed))
+	}
+	return nil
+}
+
+func runDriveOrphans(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	return RunDriveOrphansWithDependencies(ctx, client, cfg.Google.ServicesFolderID, infrahistory.NewJSONStore(cfg.History.File), os.Stdout)
+}
+
+// orphanSections are the report's sections, in the order they are printed
+var orphanSections = []struct {
+	kind  string
+	title string
+}{
+	{distribution.OrphanTrashed, "In the trash"},
+	{distribution.OrphanUnreferenced, "Shared publicly but not in history"},
+	{distribution.OrphanUnknownName, "Unrecognised names"},
+}
+
+// RunDriveOrphansWithDependencies runs the drive report-orphans command with injected dependencies (for testing)
+func RunDriveOrphansWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	store history.Store,
+	output io.Writer,
+) error {
+	fmt.Fprintln(output, "Checking the Services folder for orphaned files...")
+	report, err := appdist.NewOrphanService(driveClient, folderID, store).Report(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, skipped := range report.Skipped {
+		fmt.Fprintf(output, "Skipped %s\n", skipped)
+	}
+	if len(report.Orphans) == 0 {
+		fmt.Fprintln(output, "No orphaned files found")
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, section := range orphanSections {
+		orphans := report.OfKind(section.kind)
+		if len(orphans) == 0 {
+			continue
+		}
+		fmt.Fprintf(output, "\n%s (%d):\n", section.title, len(orphans))
+		tw := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		for _, o := range orphans {
+			counts[o.Recommendation]++
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", o.File.Name, distribution.FormatSize(o.File.Size), o.Recommendation, o.Reason)
+		}
+		tw.Flush()
+	}
+
+	fmt.Fprintf(output, "\n%d orphaned files: %d to delete, %d to keep, %d to review\n",
+		len(report.Orphans), counts[distribution.RecommendDelete], counts[distribution.RecommendKeep], counts[distribution.RecommendReview])
+	if trashed := report.TrashedBytes(); trashed > 0 {
+		fmt.Fprintf(output, "The trash holds %s; emptying the Drive trash frees that space\n", distribution.FormatSize(trashed))
+	}
+	return nil
+}
+
+// formatProperties renders app properties as sorted key=value pairs
+func formatProperties(props map[string]string) string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + props[k]
+	}
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/domain/distribution"
+
+	"github.com/spf13/cobra"
+)
+
+var driveExpireCmd = &cobra.Command{
+	Use:   "expire",
+	Short: "End access to, and delete, privately shared files whose time is up",
+	Long: `Files uploaded for a private service, such as "process --type funeral", are
+shared only with the email's recipients and tagged with two dates: when the
+recipients' access ends (funeral.revoke_after_days) and when the files are
+deleted from Drive (funeral.retention_days).
+
+process does this after every successful run; run it by hand or from a
+scheduled task when no services are being processed.
+
+Examples:
+  nac-service-media drive expire`,
+	RunE: runDriveExpire,
+}
+
+func init() {
+	driveCmd.AddCommand(driveExpireCmd)
+}
+
+func runDriveExpire(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	return RunDriveExpireWithDependencies(ctx, client, cfg.Google.ServicesFolderID, time.Now(), os.Stdout)
+}
+
+// RunDriveExpireWithDependencies runs the drive expire command with injected dependencies (for testing)
+func RunDriveExpireWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	now time.Time,
+	output io.Writer,
+) error {
+	service := appdist.NewExpiryService(driveClient, folderID, appdist.WithExpiryClock(func() time.Time { return now }))
+	result, err := service.Expire(ctx)
+	if errors.Is(err, distribution.ErrRevokeUnsupported) {
+		return fmt.Errorf("%w; drive expire needs Google Drive storage", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, r := range result.Revoked {
+		fmt.Fprintf(output, "Ended access: %s (%s)\n", r.File.Name, strings.Join(r.Readers, ", "))
+	}
+	for _, f := range result.Deleted {
+		fmt.Fprintf(output, "Deleted: %s\n", f.Name)
+	}
+	for _, err := range result.Failed {
+		fmt.Fprintf(output, "Warning: %v\n", err)
+	}
+	if len(result.Revoked) == 0 && len(result.Deleted) == 0 && len(result.Failed) == 0 {
+		fmt.Fprintln(output, "No shared files have expired")
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d files could not be expired", len(result.Failed))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/domain/distribution"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	driveRevisionsDate     string
+	driveRevisionsType     string
+	driveRevisionsRevision string
+)
+
+var driveRevisionsCmd = &cobra.Command{
+	Use:   "revisions",
+	Short: "List or roll back earlier versions of an uploaded file",
+	Long: `Drive keeps earlier versions of a file when its content is replaced, for
+example by extract-audio --refresh. The replaced version is pinned so Drive
+does not purge it; only the newest google.keep_revisions (default 3) pinned
+versions are kept.
+
+Examples:
+  nac-service-media drive revisions list --date 2025-12-28
+  nac-service-media drive revisions restore --date 2025-12-28 --revision 0B7x`,
+}
+
+var driveRevisionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the versions of a service's uploaded file",
+	RunE:  runDriveRevisionsList,
+}
+
+var driveRevisionsRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Make an earlier version the file's content again",
+	Long: `Replace a service's uploaded file with one of its earlier versions. The
+sharing link stays the same. The content being replaced is pinned first, so
+the restore can itself be rolled back.
+
+Examples:
+  nac-service-media drive revisions restore --date 2025-12-28 --revision 0B7x`,
+	RunE: runDriveRevisionsRestore,
+}
+
+func init() {
+	driveCmd.AddCommand(driveRevisionsCmd)
+	driveRevisionsCmd.AddCommand(driveRevisionsListCmd, driveRevisionsRestoreCmd)
+
+	for _, c := range []*cobra.Command{driveRevisionsListCmd, driveRevisionsRestoreCmd} {
+		c.Flags().StringVar(&driveRevisionsDate, "date", "", "Service date in YYYY-MM-DD format (required)")
+		c.Flags().StringVar(&driveRevisionsType, "type", "audio", "Which upload: audio or video")
+		c.MarkFlagRequired("date")
+	}
+	driveRevisionsRestoreCmd.Flags().StringVar(&driveRevisionsRevision, "revision", "", "Revision ID from drive revisions list (required)")
+	driveRevisionsRestoreCmd.MarkFlagRequired("revision")
+}
+
+// DriveRevisionsInput holds the options for the drive revisions commands
+type DriveRevisionsInput struct {
+	Date     string
+	Type     string // "audio" (default) or "video"
+	Revision string // restore only
+	Keep     int    // pinned versions to keep; appdist.DefaultKeptRevisions when zero
+}
+
+func driveRevisionsInput(keep int) DriveRevisionsInput {
+	return DriveRevisionsInput{Date: driveRevisionsDate, Type: driveRevisionsType, Revision: driveRevisionsRevision, Keep: keep}
+}
+
+// fileName returns the uploaded file's name for the date and type
+func (in DriveRevisionsInput) fileName() (string, error) {
+	if _, err := time.Parse("2006-01-02", in.Date); err != nil {
+		return "", fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
+	}
+	switch in.Type {
+	case "", "audio":
+		return in.Date + ".mp3", nil
+	case "video":
+		return in.Date + ".mp4", nil
+	}
+	return "", fmt.Errorf("invalid --type %q: use audio or video", in.Type)
+}
+
+func runDriveRevisionsList(cmd *cobra.Command, args []string) error {
+	return runDriveRevisions(cmd, RunDriveRevisionsListWithDependencies)
+}
+
+func runDriveRevisionsRestore(cmd *cobra.Command, args []string) error {
+	return runDriveRevisions(cmd, RunDriveRevisionsRestoreWithDependencies)
+}
+
+func runDriveRevisions(cmd *cobra.Command, run func(context.Context, distribution.DriveClient, string, DriveRevisionsInput, io.Writer) error) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	if cfg.UsesS3() {
+		return fmt.Errorf("drive revisions needs Google Drive storage; storage.provider is s3")
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return run(ctx, client, cfg.Google.ServicesFolderID, driveRevisionsInput(cfg.Google.KeepRevisions), os.Stdout)
+}
+
+// RunDriveRevisionsListWithDependencies runs drive revisions list with injected dependencies (for testing)
+func RunDriveRevisionsListWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	input DriveRevisionsInput,
+	output io.Writer,
+) error {
+	fileName, err := input.fileName()
+	if err != nil {
+		return err
+	}
+
+	file, err := appdist.NewRevisionService(driveClient, folderID, input.Keep, output).List(ctx, fileName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output, "Revisions of %s (newest last):\n", file.File.Name)
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  REVISION\tMODIFIED\tSIZE\tPINNED\t")
+	for i, r := range file.Revisions {
+		pinned := ""
+		if r.KeepForever {
+			pinned = "yes"
+		}
+		current := ""
+		if i == len(file.Revisions)-1 {
+			current = "(current)"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", r.ID, r.ModifiedTime.Local().Format("2006-01-02 15:04"), distribution.FormatSize(r.Size), pinned, current)
+	}
+	return w.Flush()
+}
+
+// RunDriveRevisionsRestoreWithDependencies runs drive revisions restore with injected dependencies (for testing)
+func RunDriveRevisionsRestoreWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	input DriveRevisionsInput,
+	output io.Writer,
+) error {
+	fileName, err := input.fileName()
+	if err != nil {
+		return err
+	}
+	if input.Revision == "" {
+		return fmt.Errorf("--revision is required; run drive revisions list to see them")
+	}
+
+	fmt.Fprintf(output, "Restoring %s to revision %s...\n", fileName, input.Revision)
+	result, err := appdist.NewRevisionService(driveClient, folderID, input.Keep, output).Restore(ctx, fileName, input.Revision)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "Restored: %s\n", result.FileName)
+	fmt.Fprintf(output, "  Link (unchanged): %s\n", result.ShareableURL)
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	appprocess "nac-service-media/application/process"
+)
+
+// Formats for --error-format
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJSON = "json"
+)
+
+// ExitCodeFailure is the exit code for any error without a more specific one
+const ExitCodeFailure = 1
+
+// ErrorReport is how a failed command is reported with --error-format json
+type ErrorReport struct {
+	Error      string `json:"error"`
+	Code       string `json:"code,omitempty"` // Validation error code, e.g. RECIPIENT_NOT_FOUND
+	Message    string `json:"message,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+	ExitCode   int    `json:"exit_code"`
+}
+
+// ExitCode returns the exit code for err: a validation error's own code,
+// otherwise ExitCodeFailure
+func ExitCode(err error) int {
+	var validationErr *appprocess.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.ExitCode()
+	}
+	return ExitCodeFailure
+}
+
+// NewErrorReport describes err for machine-readable output
+func NewErrorReport(err error) ErrorReport {
+	report := ErrorReport{Error: err.Error(), ExitCode: ExitCode(err)}
+	var validationErr *appprocess.ValidationError
+	if errors.As(err, &validationErr) {
+		report.Code = validationErr.Code
+		report.Message = validationErr.Message
+		report.Suggestion = validationErr.Suggestion
+	}
+	return report
+}
+
+// WriteError writes err to w as plain text or as a JSON ErrorReport
+func WriteError(w io.Writer, err error, format string) error {
+	if format != ErrorFormatJSON {
+		_, werr := fmt.Fprintln(w, err)
+		return werr
+	}
+	return json.NewEncoder(w).Encode(NewErrorReport(err))
+}
+
+// parseErrorFormat validates --error-format
+func parseErrorFormat(format string) error {
+	switch format {
+	case ErrorFormatText, ErrorFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown --error-format %q (must be text or json)", format)
+	}
+}
@@ -2,15 +2,22 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	appdist "nac-service-media/application/distribution"
+	appnotif "nac-service-media/application/notification"
 	appvideo "nac-service-media/application/video"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/notification"
 	"nac-service-media/domain/video"
-	"nac-service-media/infrastructure/ffmpeg"
+	"nac-service-media/infrastructure/config"
 	"nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/gmail"
 
 	"github.com/spf13/cobra"
 )
@@ -19,8 +26,15 @@ var (
 	extractSourcePath string
 	extractBitrate    string
 	extractDate       string
+	extractOnExisting string
+	extractAudioTrack int
+	extractReplace    bool
+	extractNotify     []string
 )
 
+// refreshedAudioContext explains a refreshed-audio email to its recipients
+const refreshedAudioContext = "The audio recording has been updated. The link is the same as before."
+
 var extractAudioCmd = &cobra.Command{
 	Use:   "extract-audio",
 	Short: "Extract audio from a video file",
@@ -30,19 +44,39 @@ The source should be a trimmed video file. The output will be saved to the
 configured audio directory with the service date as the filename.
 
 If --source is just a filename, it will be resolved from the configured trimmed_directory.
+Without --source, --date picks <date>.mp4 from trimmed_directory.
+
+Use --audio-track n to extract the nth audio stream (1-based) from a source with
+several. Raw recordings default to audio.track in config; trimmed videos (in
+trimmed_directory or named <date>.mp4) default to the first stream, because
+trim and process keep only the selected track.
+
+Use --on-existing (prompt, overwrite, skip, or version) to choose what happens
+when the MP3 already exists. The default is overwrite.
+
+Use --replace-drive to upload the new MP3 over the one already in Drive. The
+file keeps its ID and sharing, so links already sent keep working. If the
+trimmed video is not on this machine, it is downloaded from Drive first. Its
+one audio stream is used unless --audio-track is given.
+Add --notify to email recipients that the audio was refreshed.
 
 Example:
   nac-service-media extract-audio --source "2025-12-28.mp4"
-  nac-service-media extract-audio --source "/path/to/video.mp4" --date "2025-12-28" --bitrate "128k"`,
+  nac-service-media extract-audio --source "/path/to/video.mp4" --date "2025-12-28" --bitrate "128k"
+  nac-service-media extract-audio --date 2025-12-28 --bitrate 128k --replace-drive
+  nac-service-media extract-audio --date 2025-12-28 --replace-drive --notify "John Doe"`,
 	RunE: runExtractAudio,
 }
 
 func init() {
 	rootCmd.AddCommand(extractAudioCmd)
-	extractAudioCmd.Flags().StringVar(&extractSourcePath, "source", "", "Path to source video file (required)")
+	extractAudioCmd.Flags().StringVar(&extractSourcePath, "source", "", "Path to source video file (defaults to <date>.mp4 in trimmed_directory)")
 	extractAudioCmd.Flags().StringVar(&extractBitrate, "bitrate", "", "Audio bitrate (default from config or 192k)")
 	extractAudioCmd.Flags().StringVar(&extractDate, "date", "", "Service date in YYYY-MM-DD format (defaults to parsing from filename)")
-	extractAudioCmd.MarkFlagRequired("source")
+	extractAudioCmd.Flags().IntVar(&extractAudioTrack, "audio-track", 0, "Audio stream to extract, starting at 1 (defaults to audio.track in config for raw recordings)")
+	extractAudioCmd.Flags().StringVar(&extractOnExisting, "on-existing", string(video.DefaultOverwritePolicy), "What to do if the output exists: prompt, overwrite, skip, or version")
+	extractAudioCmd.Flags().BoolVar(&extractReplace, "replace-drive", false, "Replace the MP3 already in Drive, keeping its link")
+	extractAudioCmd.Flags().StringArrayVar(&extractNotify, "notify", nil, "Recipient(s) to tell about the refreshed audio (requires --replace-drive)")
 }
 
 func runExtractAudio(cmd *cobra.Command, args []string) error {
@@ -52,16 +86,29 @@ func runExtractAudio(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
 	}
 
+	if len(extractNotify) > 0 && !extractReplace {
+		return fmt.Errorf("--notify requires --replace-drive")
+	}
+
 	// Resolve source path - if not absolute, use trimmed_directory from config
 	sourcePath := extractSourcePath
+	if sourcePath == "" {
+		if extractDate == "" {
+			return fmt.Errorf("either --source or --date is required")
+		}
+		sourcePath = extractDate + ".mp4"
+	}
 	if !filepath.IsAbs(sourcePath) {
 		sourcePath = filepath.Join(cfg.Paths.TrimmedDirectory, sourcePath)
 	}
 
 	// Determine bitrate
+	if err := config.Bitrate(extractBitrate).Validate(); err != nil {
+		return fmt.Errorf("invalid --bitrate: %w", err)
+	}
 	bitrate := extractBitrate
 	if bitrate == "" {
-		bitrate = cfg.Audio.Bitrate
+		bitrate = cfg.Audio.Bitrate.String()
 	}
 	if bitrate == "" {
 		bitrate = video.DefaultAudioBitrate
@@ -83,22 +130,163 @@ func runExtractAudio(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	overwrite, err := overwriteOptions(cfg, extractOnExisting, DefaultPrompter)
+	if err != nil {
+		return err
+	}
+
 	// Create dependencies using production implementations
-	extractor := ffmpeg.NewExtractor()
+	extractor := newExtractor(cfg)
 	fileChecker := filesystem.NewChecker()
 
-	return RunExtractAudioWithDependencies(
-		cmd.Context(),
+	opts := []appvideo.Option{
+		appvideo.WithOverwrite(overwrite),
+		appvideo.WithAudioTrack(ExtractAudioTrack(cfg, extractAudioTrack, sourcePath, extractReplace)),
+	}
+
+	if !extractReplace {
+		// An explicit --bitrate makes just that MP3
+		if extractBitrate == "" {
+			opts = append(opts, appvideo.WithExtraBitrates(cfg.Audio.ExtraBitrates()))
+		}
+		return RunExtractAudioWithDependencies(
+			cmd.Context(),
+			extractor,
+			fileChecker,
+			cfg.Paths.AudioDirectory,
+			bitrate,
+			sourcePath,
+			serviceDate,
+			os.Stdout,
+			opts...,
+		)
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	driveClient, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	scanner, err := newShareScanner(cfg)
+	if err != nil {
+		return err
+	}
+	refresh := AudioRefresh{
+		Drive:    driveClient,
+		FolderID: cfg.Google.ServicesFolderID,
+		Scanner:  scanner,
+
+		KeepRevisions: cfg.Google.KeepRevisions,
+	}
+	if len(extractNotify) > 0 {
+		refresh.Notifier, refresh.Notify, err = refreshNotifier(ctx, cfg, extractNotify)
+		if err != nil {
+			return err
+		}
+	}
+
+	return RunRefreshAudioWithDependencies(
+		ctx,
 		extractor,
 		fileChecker,
 		cfg.Paths.AudioDirectory,
 		bitrate,
 		sourcePath,
 		serviceDate,
+		refresh,
 		os.Stdout,
+		opts...,
 	)
 }
 
+// refreshNotifier builds the notification service for a refreshed-audio
+// email from the default sender, subject, groups and sandbox settings
+func refreshNotifier(ctx context.Context, cfg *config.Config, keys []string) (*appnotif.Service, []notification.Recipient, error) {
+	lookup := config.NewRecipientLookup(cfg, cfgFile)
+	recipients, err := lookup.LookupRecipients(keys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lookup recipients: %w", err)
+	}
+	groups, err := lookup.Groups()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid email.groups: %w", err)
+	}
+	sender, err := config.NewConfigManager(cfg, cfgFile).GetDefaultSender()
+	if errors.Is(err, config.ErrNoDefaultSender) {
+		return nil, nil, fmt.Errorf("no default sender configured; set senders.default_sender in config or this machine's sender with whoami --set-sender")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	subject, err := notification.ParseSubjectTemplate(cfg.Email.Subject)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid email.subject: %w", err)
+	}
+	greeting, err := cfg.Email.Greeting.Rules()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid email.greeting: %w", err)
+	}
+
+	opts := []appnotif.Option{
+		appnotif.WithSubjectTemplate(subject),
+		appnotif.WithRecipientGroups(groups),
+		appnotif.WithFolderLink(cfg.EmailFolderURL()),
+		appnotif.WithLivestreamLink(cfg.Email.LivestreamURL),
+		appnotif.WithGreeting(greeting),
+		appnotif.WithSendTimeout(cfg.Email.SendTimeout()),
+	}
+	if cfg.Email.Sandbox {
+		operator, err := lookup.Operator()
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, appnotif.WithSandbox(operator))
+	}
+
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid locale: %w", err)
+	}
+	from := notification.Recipient{
+		Name:    cfg.Email.FromName,
+		Address: cfg.Email.FromAddress,
+	}
+	gmailClient, err := gmail.NewClientWithOAuth(ctx, gmail.OAuthConfig{
+		CredentialsFile: cfg.Google.CredentialsFile,
+		TokenFile:       cfg.Google.GmailTokenFile,
+	}, from, gmail.WithLocation(calendar.Location()), gmail.WithBCCSender(cfg.Email.CopySender(false)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Gmail client: %w", err)
+	}
+
+	return appnotif.NewService(gmailClient, cfg.Email.FromName, sender.Name, opts...), recipients, nil
+}
+
+// ExtractAudioTrack returns the audio stream extract-audio reads from
+// sourcePath. An explicit --audio-track wins. Otherwise audio.track applies to
+// raw recordings only: trimmed videos hold just the stream trim kept, so they
+// use the first. --replace-drive always reads a trimmed video, local or from
+// Drive.
+func ExtractAudioTrack(cfg *config.Config, flag int, sourcePath string, replaceDrive bool) int {
+	if flag != video.DefaultAudioTrack || !(replaceDrive || isTrimmedVideo(cfg, sourcePath)) {
+		return audioTrack(flag, cfg.Audio.Track)
+	}
+	return video.DefaultAudioTrack
+}
+
+// isTrimmedVideo reports whether path is a video made by trim or process:
+// one in trimmed_directory or named <date>.mp4
+func isTrimmedVideo(cfg *config.Config, path string) bool {
+	if cfg.Paths.TrimmedDirectory != "" && filepath.Dir(filepath.Clean(path)) == filepath.Clean(cfg.Paths.TrimmedDirectory) {
+		return true
+	}
+	_, err := parseDateFromFilename(filepath.Base(path))
+	return err == nil && strings.EqualFold(filepath.Ext(path), ".mp4")
+}
+
 // parseDateFromFilename extracts the date from a filename in YYYY-MM-DD.ext format
 func parseDateFromFilename(filename string) (time.Time, error) {
 	// Remove extension
@@ -118,18 +306,39 @@ func RunExtractAudioWithDependencies(
 	sourcePath string,
 	serviceDate time.Time,
 	output OutputWriter,
+	opts ...appvideo.Option,
 ) error {
-	// Verify ffmpeg is available if extractor supports it
-	if verifiable, ok := extractor.(interface{ VerifyInstalled(context.Context) error }); ok {
-		verifyCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
-		if err := verifiable.VerifyInstalled(verifyCtx); err != nil {
-			return fmt.Errorf("ffmpeg verification failed: %w", err)
-		}
+	result, err := extractAudio(ctx, extractor, fileChecker, outputDir, bitrate, sourcePath, serviceDate, output, opts...)
+	if err != nil {
+		return err
+	}
+
+	printOutputResult(output, result.OutputPath, result.Reused)
+	for _, v := range result.Variants {
+		printOutputResult(output, v.OutputPath, v.Reused)
+	}
+	return nil
+}
+
+// extractAudio verifies ffmpeg and extracts the audio, reporting progress to output
+func extractAudio(
+	ctx context.Context,
+	extractor video.AudioExtractor,
+	fileChecker video.FileChecker,
+	outputDir string,
+	bitrate string,
+	sourcePath string,
+	serviceDate time.Time,
+	output OutputWriter,
+	opts ...appvideo.Option,
+) (*appvideo.ExtractResult, error) {
+	// Verify ffmpeg is available and new enough if extractor supports it
+	if _, err := verifyFFmpeg(ctx, extractor, output); err != nil {
+		return nil, err
 	}
 
 	// Create service with injected dependencies
-	service := appvideo.NewExtractService(extractor, fileChecker, outputDir, bitrate)
+	service := appvideo.NewExtractService(extractor, fileChecker, outputDir, bitrate, opts...)
 
 	// Perform extraction
 	input := appvideo.ExtractInput{
@@ -140,11 +349,94 @@ func RunExtractAudioWithDependencies(
 
 	fmt.Fprintf(output, "Extracting audio from %s with bitrate %s...\n", sourcePath, bitrate)
 
-	result, err := service.Extract(ctx, input)
+	return service.Extract(ctx, input)
+}
+
+// AudioRefresh says which Drive folder holds the audio to replace and who to
+// tell about it
+type AudioRefresh struct {
+	Drive    distribution.DriveClient
+	FolderID string
+	Notifier *appnotif.Service // nil skips the notification
+	Notify   []notification.Recipient
+	Scanner  distribution.Scanner // Checks the new MP3 before it replaces the public one
+
+	// KeepRevisions is how many replaced versions stay pinned in Drive for
+	// rollback (appdist.DefaultKeptRevisions when zero)
+	KeepRevisions int
+}
+
+// RunRefreshAudioWithDependencies re-extracts the audio for a service and
+// replaces the MP3 in Drive in place, downloading the trimmed video from Drive
+// when it is not available locally (for testing)
+func RunRefreshAudioWithDependencies(
+	ctx context.Context,
+	extractor video.AudioExtractor,
+	fileChecker video.FileChecker,
+	outputDir string,
+	bitrate string,
+	sourcePath string,
+	serviceDate time.Time,
+	refresh AudioRefresh,
+	output OutputWriter,
+	opts ...appvideo.Option,
+) error {
+	uploader := appdist.NewUploadService(refresh.Drive, refresh.FolderID, output, appdist.WithScanner(refresh.Scanner))
+
+	if !fileChecker.Exists(sourcePath) {
+		fmt.Fprintf(output, "Trimmed video not found locally: %s\n", sourcePath)
+		if err := uploader.DownloadFile(ctx, filepath.Base(sourcePath), sourcePath); err != nil {
+			return fmt.Errorf("could not get the trimmed video from Drive: %w", err)
+		}
+	}
+
+	result, err := extractAudio(ctx, extractor, fileChecker, outputDir, bitrate, sourcePath, serviceDate, output, opts...)
 	if err != nil {
 		return err
 	}
+	printOutputResult(output, result.OutputPath, result.Reused)
+
+	// Drive always holds the audio under the service date, whatever the
+	// local overwrite policy named the new file
+	date := serviceDate.Format("2006-01-02")
+	fileName := date + ".mp3"
+	fmt.Fprintf(output, "Replacing %s in Drive...\n", fileName)
+	revisions := appdist.NewRevisionService(refresh.Drive, refresh.FolderID, refresh.KeepRevisions, output)
+	if err := revisions.KeepCurrent(ctx, fileName); err != nil && !errors.Is(err, distribution.ErrRevisionsUnsupported) {
+		return fmt.Errorf("not replacing %s: could not pin the current version: %w", fileName, err)
+	}
+	uploaded, err := uploader.ReplaceAudio(ctx, fileName, result.OutputPath)
+	if err != nil {
+		return fmt.Errorf("audio replace failed: %w", err)
+	}
+	fmt.Fprintf(output, "Audio replaced; the link is unchanged\n")
+	fmt.Fprintf(output, "  File ID: %s\n", uploaded.FileID)
+	fmt.Fprintf(output, "  Size: %s\n", distribution.FormatSize(uploaded.Size))
+	fmt.Fprintf(output, "  Shareable URL: %s\n", uploaded.ShareableURL)
+
+	if refresh.Notifier == nil {
+		return nil
+	}
 
-	fmt.Fprintf(output, "Successfully created: %s\n", result.OutputPath)
+	req := appnotif.SendRequest{
+		To:          refresh.Notify,
+		ServiceDate: serviceDate,
+		AudioURL:    uploaded.ShareableURL,
+		Context:     refreshedAudioContext,
+	}
+	// Include the video link so the email is complete on its own
+	if v, err := refresh.Drive.FindFileByName(ctx, refresh.FolderID, date+".mp4"); err == nil && v != nil {
+		req.VideoURL = distribution.LinkFor(refresh.Drive, v.ID)
+	}
+
+	names := make([]string, len(refresh.Notify))
+	for i, r := range refresh.Notify {
+		names[i] = r.String()
+	}
+	fmt.Fprintf(output, "Notifying %s...\n", strings.Join(names, ", "))
+	if err := refresh.Notifier.Send(ctx, req); err != nil {
+		return fmt.Errorf("audio was replaced but the notification failed: %w", err)
+	}
+	fmt.Fprintf(output, "Notification sent\n")
 	return nil
 }
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/ffmpeg"
+)
+
+// ffmpegTools finds the ffmpeg and ffprobe to run: video.ffmpeg_path, then
+// the project's ffmpeg folder, then PATH. When none is found the configured
+// or bare names are kept, so verifying ffmpeg reports the problem.
+func ffmpegTools(cfg *config.Config) ffmpeg.Tools {
+	var configured string
+	if cfg != nil {
+		configured = cfg.Video.FFmpegPath
+	}
+	tools, err := ffmpeg.Locate(configured)
+	if err != nil {
+		if configured == "" {
+			configured = "ffmpeg"
+		}
+		return ffmpeg.Tools{FFmpeg: configured, FFprobe: "ffprobe"}
+	}
+	return tools
+}
+
+// ffmpegMinVersion returns video.ffmpeg_min_version, or the default without
+// a config
+func ffmpegMinVersion(cfg *config.Config) string {
+	if cfg == nil || cfg.Video.FFmpegMinVersion == "" {
+		return ffmpeg.DefaultMinVersion
+	}
+	return cfg.Video.FFmpegMinVersion
+}
+
+// ffmpegRunner runs ffmpeg at video.ffmpeg_priority
+func ffmpegRunner(cfg *config.Config) *ffmpeg.ExecCommandRunner {
+	runner := &ffmpeg.ExecCommandRunner{}
+	if cfg != nil {
+		runner.Priority = ffmpeg.Priority(cfg.Video.FFmpegPriority)
+	}
+	return runner
+}
+
+func newTrimmer(cfg *config.Config) *ffmpeg.Trimmer {
+	return ffmpeg.NewTrimmer(
+		ffmpeg.WithFFmpegPath(ffmpegTools(cfg).FFmpeg),
+		ffmpeg.WithMinVersion(ffmpegMinVersion(cfg)),
+		ffmpeg.WithCommandRunner(ffmpegRunner(cfg)),
+	)
+}
+
+func newExtractor(cfg *config.Config) *ffmpeg.Extractor {
+	return ffmpeg.NewExtractor(
+		ffmpeg.WithExtractorFFmpegPath(ffmpegTools(cfg).FFmpeg),
+		ffmpeg.WithExtractorMinVersion(ffmpegMinVersion(cfg)),
+		ffmpeg.WithExtractorCommandRunner(ffmpegRunner(cfg)),
+	)
+}
+
+func newSampleGenerator(cfg *config.Config) *ffmpeg.SampleGenerator {
+	return ffmpeg.NewSampleGenerator(ffmpeg.WithSampleFFmpegPath(ffmpegTools(cfg).FFmpeg))
+}
+
+func newValidator(cfg *config.Config) *ffmpeg.Validator {
+	return ffmpeg.NewValidator(ffmpeg.WithFFprobePath(ffmpegTools(cfg).FFprobe))
+}
+
+// ffmpegVersioner is an ffmpeg-backed tool that reports the installed version
+type ffmpegVersioner interface {
+	Version(ctx context.Context) (ffmpeg.Version, error)
+}
+
+// verifyFFmpeg checks that the tool's ffmpeg runs and is new enough, and
+// returns its version for the run record. Tools that cannot report a version,
+// such as test doubles, are only checked with VerifyInstalled when they have it.
+func verifyFFmpeg(ctx context.Context, tool any, output io.Writer) (string, error) {
+	verifyCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if versioner, ok := tool.(ffmpegVersioner); ok {
+		v, err := versioner.Version(verifyCtx)
+		if err != nil {
+			return "", fmt.Errorf("ffmpeg verification failed: %w", err)
+		}
+		fmt.Fprintf(output, "Using ffmpeg %s\n", v)
+		return v.String(), nil
+	}
+	if verifiable, ok := tool.(interface{ VerifyInstalled(context.Context) error }); ok {
+		if err := verifiable.VerifyInstalled(verifyCtx); err != nil {
+			return "", fmt.Errorf("ffmpeg verification failed: %w", err)
+		}
+	}
+	return "", nil
+}
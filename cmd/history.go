@@ -0,0 +1,468 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apphistory "nac-service-media/application/history"
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/video"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyExportFormat string
+	historyExportFrom   string
+	historyExportTo     string
+	historyExportYear   string
+	historyExportOutput string
+
+	historyNoteFrom string
+	historyNoteTo   string
+	historyNoteYear string
+
+	historyStatsFrom string
+	historyStatsTo   string
+	historyStatsYear string
+
+	historyListFrom string
+	historyListTo   string
+	historyListYear string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Work with the record of processed services",
+	Long: `Work with the local record of services completed by "process".
+
+History is kept in history.file (default history.jsonl), one entry per run.`,
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List processed services",
+	Long: `List the runs recorded in history, oldest first, with the service date,
+minister, source recording, trim times, number of recipients and outcome.
+
+Examples:
+  nac-service-media history list
+  nac-service-media history list --year 2025`,
+	RunE: runHistoryList,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show DATE",
+	Short: "Show everything recorded for a processed service",
+	Long: `Show every run recorded for a service date: the source recording and trim
+times, the uploaded files' Drive IDs and links, who was emailed, the outcome,
+and any notes or corrections.
+
+Example:
+  nac-service-media history show 2025-12-28`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryShow,
+}
+
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export processed services to CSV or JSON",
+	Long: `Export processed services for reporting, with the date, minister, duration,
+file sizes, number of recipients, Drive links, and sermon title and scripture
+for each service.
+
+Examples:
+  # Everything recorded in 2025, as CSV
+  nac-service-media history export --year 2025 --output services-2025.csv
+
+  # A date range, as JSON on stdout
+  nac-service-media history export --format json --from 2025-09-01 --to 2025-12-31`,
+	RunE: runHistoryExport,
+}
+
+var historyNoteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Attach notes such as A/V issues to processed services",
+}
+
+var historyNoteAddCmd = &cobra.Command{
+	Use:   "add DATE TEXT",
+	Short: "Add a note to a processed service",
+	Long: `Add a free-text note to the most recent run recorded for a service date.
+
+Example:
+  nac-service-media history note add 2025-12-28 "organ mic buzzing"`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runHistoryNoteAdd,
+}
+
+var historyNoteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List services that have notes",
+	RunE:  runHistoryNoteList,
+}
+
+var historyStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize processed services",
+}
+
+var historyStatsMinistersCmd = &cobra.Command{
+	Use:   "ministers",
+	Short: "Report how often each minister served",
+	Long: `Report how many services each minister appears in, the average length of
+those services, and the last date each served.
+
+Example:
+  # The yearly report for leadership
+  nac-service-media history stats ministers --year 2025`,
+	RunE: runHistoryStatsMinisters,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyExportCmd)
+	historyCmd.AddCommand(historyNoteCmd)
+	historyNoteCmd.AddCommand(historyNoteAddCmd)
+	historyNoteCmd.AddCommand(historyNoteListCmd)
+	historyCmd.AddCommand(historyStatsCmd)
+	historyStatsCmd.AddCommand(historyStatsMinistersCmd)
+
+	historyListCmd.Flags().StringVar(&historyListFrom, "from", "", "First service date to include (YYYY-MM-DD)")
+	historyListCmd.Flags().StringVar(&historyListTo, "to", "", "Last service date to include (YYYY-MM-DD)")
+	historyListCmd.Flags().StringVar(&historyListYear, "year", "", "Calendar year to include (shorthand for --from/--to)")
+
+	historyExportCmd.Flags().StringVar(&historyExportFormat, "format", history.FormatCSV, "Output format: csv or json")
+	historyExportCmd.Flags().StringVar(&historyExportFrom, "from", "", "First service date to include (YYYY-MM-DD)")
+	historyExportCmd.Flags().StringVar(&historyExportTo, "to", "", "Last service date to include (YYYY-MM-DD)")
+	historyExportCmd.Flags().StringVar(&historyExportYear, "year", "", "Calendar year to include (shorthand for --from/--to)")
+	historyExportCmd.Flags().StringVar(&historyExportOutput, "output", "", "File to write (defaults to stdout)")
+
+	historyNoteListCmd.Flags().StringVar(&historyNoteFrom, "from", "", "First service date to include (YYYY-MM-DD)")
+	historyNoteListCmd.Flags().StringVar(&historyNoteTo, "to", "", "Last service date to include (YYYY-MM-DD)")
+	historyNoteListCmd.Flags().StringVar(&historyNoteYear, "year", "", "Calendar year to include (shorthand for --from/--to)")
+
+	historyStatsMinistersCmd.Flags().StringVar(&historyStatsFrom, "from", "", "First service date to include (YYYY-MM-DD)")
+	historyStatsMinistersCmd.Flags().StringVar(&historyStatsTo, "to", "", "Last service date to include (YYYY-MM-DD)")
+	historyStatsMinistersCmd.Flags().StringVar(&historyStatsYear, "year", "", "Calendar year to include (shorthand for --from/--to)")
+}
+
+func runHistoryList(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	store := infrahistory.NewJSONStore(cfg.History.File)
+	return RunHistoryListWithDependencies(store, historyListFrom, historyListTo, historyListYear, os.Stdout)
+}
+
+// RunHistoryListWithDependencies runs the history list command with injected dependencies (for testing)
+func RunHistoryListWithDependencies(store history.Store, from, to, year string, output io.Writer) error {
+	filter, err := historyFilter(from, to, year)
+	if err != nil {
+		return err
+	}
+
+	runs, err := apphistory.NewRunService(store).List(filter)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Fprintln(output, "No processed services recorded")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tMINISTER\tSOURCE\tTRIM\tRECIPIENTS\tOUTCOME")
+	for _, e := range runs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", e.ServiceDate.Format("2006-01-02"), orDash(e.Minister),
+			orDash(e.SourceFile), trimRange(e), len(e.Recipients), orDash(e.Outcome))
+	}
+	tw.Flush()
+	fmt.Fprintf(output, "\n%d runs\n", len(runs))
+	return nil
+}
+
+func runHistoryShow(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	store := infrahistory.NewJSONStore(cfg.History.File)
+	return RunHistoryShowWithDependencies(store, args[0], os.Stdout)
+}
+
+// RunHistoryShowWithDependencies runs the history show command with injected dependencies (for testing)
+func RunHistoryShowWithDependencies(store history.Store, date string, output io.Writer) error {
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return fmt.Errorf("invalid date (use YYYY-MM-DD): %w", err)
+	}
+
+	runs, err := apphistory.NewRunService(store).Show(serviceDate)
+	if err != nil {
+		return err
+	}
+	for i, e := range runs {
+		if i > 0 {
+			fmt.Fprintln(output)
+		}
+		printHistoryRun(output, e)
+	}
+	return nil
+}
+
+// printHistoryRun writes one run's details, leaving out what was not recorded
+func printHistoryRun(output io.Writer, e history.Entry) {
+	header := e.ServiceDate.Format("2006-01-02")
+	if e.Minister != "" {
+		header += " (" + e.Minister + ")"
+	}
+	fmt.Fprintln(output, header)
+
+	tw := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(tw, "  %s:\t%s\n", label, value)
+		}
+	}
+	if !e.ProcessedAt.IsZero() {
+		line("Processed", e.ProcessedAt.Local().Format("2006-01-02 15:04"))
+	}
+	line("Outcome", e.Outcome)
+	line("Title", e.Title)
+	line("Scripture", e.Scripture)
+	line("Source", e.SourceFile)
+	if e.StartTime != "" || e.EndTime != "" {
+		line("Trim", fmt.Sprintf("%s (%s long)", trimRange(e), video.TimestampFromSeconds(e.DurationSeconds)))
+	}
+	line("Video", uploadedFile(e.VideoFileID, e.VideoURL))
+	line("Audio", uploadedFile(e.AudioFileID, e.AudioURL))
+	if e.FolderID != "" {
+		line("Folder", e.FolderID)
+	}
+	line("Recipients", strings.Join(e.Recipients, ", "))
+	line("Email", e.EmailSubject)
+	for _, n := range e.Notes {
+		line("Note", n.Text)
+	}
+	for _, c := range e.Corrections {
+		line("Corrected", fmt.Sprintf("minister %s, not %s (%s)", c.Minister, c.PreviousMinister, c.SentAt.Local().Format("2006-01-02")))
+	}
+	tw.Flush()
+}
+
+// trimRange is a run's start and end in the source, e.g. "00:05:30-01:45:00"
+func trimRange(e history.Entry) string {
+	if e.StartTime == "" && e.EndTime == "" {
+		return "-"
+	}
+	return orDash(e.StartTime) + "-" + orDash(e.EndTime)
+}
+
+// uploadedFile is a Drive file's ID and link, or "" when it was not uploaded
+func uploadedFile(id, url string) string {
+	switch {
+	case id == "":
+		return url
+	case url == "":
+		return id
+	}
+	return id + " " + url
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func runHistoryExport(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	store := infrahistory.NewJSONStore(cfg.History.File)
+
+	if historyExportOutput == "" {
+		_, err := RunHistoryExportWithDependencies(store, historyExportFormat, historyExportFrom, historyExportTo, historyExportYear, os.Stdout)
+		return err
+	}
+
+	f, err := os.Create(historyExportOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", historyExportOutput, err)
+	}
+	defer f.Close()
+
+	n, err := RunHistoryExportWithDependencies(store, historyExportFormat, historyExportFrom, historyExportTo, historyExportYear, f)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "Exported %d services to %s\n", n, historyExportOutput)
+	return nil
+}
+
+// RunHistoryExportWithDependencies runs the history export command with injected dependencies (for testing)
+func RunHistoryExportWithDependencies(
+	store history.Store,
+	format string,
+	from string,
+	to string,
+	year string,
+	output io.Writer,
+) (int, error) {
+	format, err := history.ParseFormat(format)
+	if err != nil {
+		return 0, err
+	}
+
+	filter, err := historyFilter(from, to, year)
+	if err != nil {
+		return 0, err
+	}
+
+	return apphistory.NewExportService(store).Export(output, format, filter)
+}
+
+func runHistoryNoteAdd(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	store := infrahistory.NewJSONStore(cfg.History.File)
+	return RunHistoryNoteAddWithDependencies(store, args[0], strings.Join(args[1:], " "), os.Stdout)
+}
+
+// RunHistoryNoteAddWithDependencies runs the history note add command with injected dependencies (for testing)
+func RunHistoryNoteAddWithDependencies(store history.Store, date, text string, output io.Writer) error {
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return fmt.Errorf("invalid date (use YYYY-MM-DD): %w", err)
+	}
+
+	note, err := apphistory.NewNoteService(store).Add(serviceDate, text)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "Added note to %s: %s\n", date, note.Text)
+	return nil
+}
+
+func runHistoryNoteList(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	store := infrahistory.NewJSONStore(cfg.History.File)
+	return RunHistoryNoteListWithDependencies(store, historyNoteFrom, historyNoteTo, historyNoteYear, os.Stdout)
+}
+
+// RunHistoryNoteListWithDependencies runs the history note list command with injected dependencies (for testing)
+func RunHistoryNoteListWithDependencies(store history.Store, from, to, year string, output io.Writer) error {
+	filter, err := historyFilter(from, to, year)
+	if err != nil {
+		return err
+	}
+
+	services, err := apphistory.NewNoteService(store).List(filter)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		fmt.Fprintln(output, "No notes recorded")
+		return nil
+	}
+	for _, svc := range services {
+		header := svc.ServiceDate.Format("2006-01-02")
+		if svc.Minister != "" {
+			header += " (" + svc.Minister + ")"
+		}
+		fmt.Fprintln(output, header)
+		for _, n := range svc.Notes {
+			fmt.Fprintf(output, "  - %s\n", n.Text)
+		}
+	}
+	return nil
+}
+
+func runHistoryStatsMinisters(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	store := infrahistory.NewJSONStore(cfg.History.File)
+	return RunHistoryMinisterStatsWithDependencies(store, historyStatsFrom, historyStatsTo, historyStatsYear, os.Stdout)
+}
+
+// RunHistoryMinisterStatsWithDependencies runs the history stats ministers command with injected dependencies (for testing)
+func RunHistoryMinisterStatsWithDependencies(store history.Store, from, to, year string, output io.Writer) error {
+	filter, err := historyFilter(from, to, year)
+	if err != nil {
+		return err
+	}
+
+	stats, err := apphistory.NewStatsService(store).Ministers(filter)
+	if err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		fmt.Fprintln(output, "No services with a minister recorded")
+		return nil
+	}
+
+	total := 0
+	tw := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MINISTER\tSERVICES\tAVG LENGTH\tLAST SERVED")
+	for _, st := range stats {
+		total += st.Services
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", st.Minister, st.Services, video.TimestampFromSeconds(st.AvgSeconds), st.LastServed.Format("2006-01-02"))
+	}
+	tw.Flush()
+	fmt.Fprintf(output, "\n%d services by %d ministers\n", total, len(stats))
+	return nil
+}
+
+// historyFilter builds a date filter from --from, --to and --year
+func historyFilter(from, to, year string) (history.Filter, error) {
+	var filter history.Filter
+
+	if year != "" {
+		if from != "" || to != "" {
+			return filter, fmt.Errorf("--year cannot be combined with --from or --to")
+		}
+		y, err := strconv.Atoi(year)
+		if err != nil {
+			return filter, fmt.Errorf("invalid year %q", year)
+		}
+		filter.From = time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		filter.To = time.Date(y, time.December, 31, 0, 0, 0, 0, time.UTC)
+		return filter, nil
+	}
+
+	var err error
+	if from != "" {
+		if filter.From, err = time.Parse("2006-01-02", from); err != nil {
+			return filter, fmt.Errorf("invalid --from date (use YYYY-MM-DD): %w", err)
+		}
+	}
+	if to != "" {
+		if filter.To, err = time.Parse("2006-01-02", to); err != nil {
+			return filter, fmt.Errorf("invalid --to date (use YYYY-MM-DD): %w", err)
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, fmt.Errorf("--to date %s is before --from date %s", to, from)
+	}
+	return filter, nil
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	appmigrate "nac-service-media/application/migrate"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/history"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateDryRun bool
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move a congregation from manual uploads onto this tool",
+}
+
+var migrateScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Rename hand-named Drive files and record them in history",
+	Long: `Inventory the Services folder, rename files named by hand to the
+YYYY-MM-DD.mp4 and YYYY-MM-DD.mp3 names this tool uses, and add each service
+found to history, so processed checks, cleanup and history stats see the
+services uploaded before the switch.
+
+Dates are found in names such as "2025_12_28 Service.mp4", "20251228.mp3",
+"Service 12-28-2025.mp4" (month first) and "Dec 28, 2025.mp4". Renaming keeps
+each file's sharing link. Files that would clash with another file's name, and
+formats other than MP4 and MP3, are listed and left alone.
+
+Run with --dry-run first to review the renames.
+
+Examples:
+  nac-service-media migrate scan --dry-run
+  nac-service-media migrate scan`,
+	RunE: runMigrateScan,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateScanCmd)
+	migrateScanCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the renames and history entries without making them")
+}
+
+func runMigrateScan(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	return RunMigrateScanWithDependencies(ctx, client, cfg.Google.ServicesFolderID,
+		infrahistory.NewJSONStore(cfg.History.File), migrateDryRun, os.Stdout)
+}
+
+// RunMigrateScanWithDependencies runs the migrate scan command with injected dependencies (for testing)
+func RunMigrateScanWithDependencies(
+	ctx context.Context,
+	driveClient distribution.DriveClient,
+	folderID string,
+	store history.Store,
+	dryRun bool,
+	output io.Writer,
+) error {
+	service := appmigrate.NewService(driveClient, folderID, store)
+
+	fmt.Fprintln(output, "Scanning the Services folder...")
+	plan, err := service.Scan(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "  %d already named YYYY-MM-DD, %d to rename, %d skipped, %d not recognised\n",
+		len(plan.Canonical), len(plan.Renames), len(plan.Skipped), len(plan.Unrecognized))
+
+	result, err := service.Apply(ctx, plan, dryRun)
+	if errors.Is(err, distribution.ErrRenameUnsupported) {
+		return fmt.Errorf("%w; migrate scan needs Google Drive storage", err)
+	}
+
+	verb := "Renamed"
+	if dryRun {
+		verb = "Would rename"
+	}
+	if len(result.Renamed) > 0 {
+		fmt.Fprintln(output)
+		for _, mf := range result.Renamed {
+			fmt.Fprintf(output, "  %s: %s -> %s\n", verb, mf.File.Name, mf.NewName)
+		}
+	}
+	for _, f := range result.Failed {
+		fmt.Fprintf(output, "  Warning: could not rename %s: %v\n", f.File.File.Name, f.Err)
+	}
+	if len(plan.Skipped) > 0 {
+		fmt.Fprintf(output, "\nLeft alone:\n")
+		for _, s := range plan.Skipped {
+			fmt.Fprintf(output, "  %s: %s\n", s.File.Name, s.Reason)
+		}
+	}
+	if len(plan.Unrecognized) > 0 {
+		fmt.Fprintf(output, "\nNo service date found; rename these by hand to YYYY-MM-DD.mp4 or .mp3 if they are services:\n")
+		for _, f := range plan.Unrecognized {
+			fmt.Fprintf(output, "  %s\n", f.Name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	historyVerb := "Added"
+	if dryRun {
+		historyVerb = "Would add"
+	}
+	fmt.Fprintf(output, "\n%s %d services to history (%d already recorded)\n", historyVerb, len(result.Seeded), result.AlreadyRecorded)
+	if dryRun {
+		fmt.Fprintln(output, "Dry run: nothing was changed. Run without --dry-run to apply.")
+		return nil
+	}
+	if len(result.Renamed) > 0 {
+		fmt.Fprintln(output, "Next: run drive backfill-metadata to tag the renamed files with their service dates")
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d files could not be renamed", len(result.Failed))
+	}
+	return nil
+}
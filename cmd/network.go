@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/network"
+)
+
+// networkSettings returns the outbound HTTP settings from config
+func networkSettings(cfg *config.Config) network.Settings {
+	settings := network.Settings{
+		ProxyURL:  cfg.Network.ProxyURL,
+		CABundle:  cfg.Network.CABundle,
+		RateLimit: cfg.Network.RateLimit,
+	}
+	if cfg.Network.Debug {
+		settings.RequestLog = os.Stderr
+	}
+	return settings
+}
+
+// googleContext returns ctx set up so the Google API clients created with it,
+// and their token refreshes, go through the configured proxy and CA bundle.
+// They all share one client, so connections are reused across them.
+func googleContext(ctx context.Context, cfg *config.Config) (context.Context, error) {
+	client, err := network.SharedHTTPClient(networkSettings(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid network config: %w", err)
+	}
+	return network.Context(ctx, client), nil
+}
+
+// writeHTTPStats reports the requests sent to each host with network.debug
+func writeHTTPStats() {
+	if cfg == nil || !cfg.Network.Debug {
+		return
+	}
+	network.WriteStats(os.Stderr, network.SharedStats())
+}
@@ -2,39 +2,80 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	appdetection "nac-service-media/application/detection"
+	appdist "nac-service-media/application/distribution"
 	appprocess "nac-service-media/application/process"
+	apprecording "nac-service-media/application/recording"
+	appvideo "nac-service-media/application/video"
+	"nac-service-media/domain/audit"
+	"nac-service-media/domain/detection"
 	"nac-service-media/domain/distribution"
 	domainfs "nac-service-media/domain/filesystem"
+	"nac-service-media/domain/history"
 	"nac-service-media/domain/notification"
+	"nac-service-media/domain/recording"
+	"nac-service-media/domain/runstate"
+	"nac-service-media/domain/summary"
 	"nac-service-media/domain/video"
 	"nac-service-media/infrastructure/config"
+	infradetection "nac-service-media/infrastructure/detection"
 	"nac-service-media/infrastructure/drive"
-	"nac-service-media/infrastructure/ffmpeg"
 	"nac-service-media/infrastructure/filesystem"
 	"nac-service-media/infrastructure/gmail"
+	infrahistory "nac-service-media/infrastructure/history"
+	"nac-service-media/infrastructure/obs"
+	infraresource "nac-service-media/infrastructure/resource"
+	infrarunstate "nac-service-media/infrastructure/runstate"
+	infrasummary "nac-service-media/infrastructure/summary"
+	"nac-service-media/infrastructure/ui"
+	"nac-service-media/infrastructure/workspace"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	processInputPath     string
-	processStartTime     string
-	processEndTime       string
-	processMinisterKey   string
-	processRecipientKeys []string
-	processCCKeys        []string
-	processDateOverride  string
-	processSenderKey     string
-	processSkipVideo     bool
+	processInputPath      string
+	processStartTime      string
+	processEndTime        string
+	processMinisterKey    string
+	processRecipientKeys  []string
+	processCCKeys         []string
+	processExcludeKeys    []string
+	processDateOverride   string
+	processSenderKey      string
+	processServiceType    string
+	processType           string
+	processWithVideo      bool
+	processLabel          string
+	processTitle          string
+	processScripture      string
+	processNotes          []string
+	processSandbox        bool
+	processStreamAudio    bool
+	processSummaryDir     string
+	processSkipVideo      bool
+	processAudioTrack     int
+	processOnExisting     string
+	processFromOBS        bool
+	processOBSWait        bool
+	processNonInteractive bool
+	processConfirmSteps   bool
+	processAllowDelete    bool
+	processStrict         bool
+	processFolderID       string
+	processQuick          bool
+	processResume         bool
+	processCues           string
+	processTarget         string
 )
 
 var processCmd = &cobra.Command{
@@ -51,18 +92,45 @@ var processCmd = &cobra.Command{
 8. Send email notification with links
 
 The source video can be specified with --input, or the newest file in the
-source directory will be used by default.
+source directory will be used by default. With paths.source_directories, the
+first folder holding a recording is used. With --from-obs the recording is
+stopped in OBS (via obs-websocket) and the file OBS reports is processed;
+add --obs-wait to wait for the operator to stop it instead.
 
 Timestamps can be auto-detected when detection.enabled is true in config:
   --start: Detects when the cross lights up (visual template matching)
-  --end: Detects the three-fold amen song (audio template matching)
+  --end: Detects the three-fold amen song (audio template matching), or with
+         detection.end.method the cross going dark ("cross") or the first
+         long silence ("silence", which needs only ffmpeg)
+
+Detected timestamps are shown for a yes, or a corrected timestamp, before the
+run continues; --non-interactive and unattended runs use them as detected.
+
+A cue file written by the sound desk next to the recording, with the same name
+and a .csv or .json extension (or given with --cues), supplies the start, end
+and chapter markers; --start and --end still win when given.
+
+Timestamps may also be relative: --end -00:05:00 ends five minutes before the
+end of the file, and --start +00:02:00 starts two minutes after the detected
+start. --end +01:30:00 ends 1.5 hours after the start.
 
 The service date is inferred from the filename (OBS format: YYYY-MM-DD HH-MM-SS.mp4),
 or can be specified with --date.
 
-Ministers, recipients, and CCs are looked up by their config keys.
+Ministers, recipients, and CCs are looked up by their config keys. --recipient
+also takes the name of one of email.groups, and --exclude leaves people out of
+the result; the resolved recipients are shown before anything is processed.
+
+With --quick, whatever is not given on the command line comes from the
+defaults section of config: the minister scheduled for the service date (or
+the fallback minister), the recipients, the sender, and the typical service
+length for --end when the end cannot be auto-detected. Everything is shown on
+one screen and a single yes starts the run.
 
 Example:
+  # A regular Sunday, everything from the defaults section of config
+  nac-service-media process --quick
+
   # Fully automatic - detect both start and end
   nac-service-media process --minister smith --recipient jane
 
@@ -72,6 +140,9 @@ Example:
   # Specify both timestamps manually
   nac-service-media process --start 00:05:30 --end 01:45:00 --minister smith --recipient jane
 
+  # Start 2 minutes after the detected start, end 5 minutes before the file ends
+  nac-service-media process --start +00:02:00 --end -00:05:00 --minister smith --recipient jane
+
   nac-service-media process \
     --input "2025-12-28 10-06-16.mp4" \
     --start 00:05:30 \
@@ -80,104 +151,260 @@ Example:
     --recipient jane --recipient john \
     --sender avteam
 
+  # Everyone in the choir group except Jane
+  nac-service-media process --end 01:45:00 --recipient choir --exclude jane
+
+  # A funeral: shared only with the recipients, audio-only, access ending after funeral.revoke_after_days
+  nac-service-media process --type funeral --end 01:10:00 --recipient jones-family
+
   # Audio-only mode (skip video trimming and upload)
-  nac-service-media process --skip-video --start 00:05:30 --end 01:45:00 --minister smith --recipient jane`,
+  nac-service-media process --skip-video --start 00:05:30 --end 01:45:00 --minister smith --recipient jane
+
+  # Stop the OBS recording and process it
+  nac-service-media process --from-obs --end 01:45:00 --minister smith --recipient jane
+
+  # Tag the files and email with the sermon title and scripture
+  nac-service-media process --end 01:45:00 --recipient jane --title "Walking in Faith" --scripture "Hebrews 11:1"
+
+  # Record an A/V issue alongside the service in history
+  nac-service-media process --end 01:45:00 --recipient jane --note "organ mic buzzing"
+
+  # Re-run after a failure, reusing the trimmed video and MP3 if they are valid
+  nac-service-media process --start 00:05:30 --end 01:45:00 --recipient jane --on-existing skip
+
+  # Take the start, end and chapters from the sound desk's cue file
+  nac-service-media process --cues "/path/to/markers.csv" --recipient jane
+
+  # Pick up the last failed run where it stopped, with the options it was started with
+  nac-service-media process --resume
+
+  # Unattended (cron/watch): never prompt; fail with "non-interactive: <reason>: ..." instead
+  nac-service-media process --non-interactive --recipient jane --on-existing skip
+
+  # Unattended, and let the storage step delete the oldest videos when Drive is full
+  nac-service-media process --non-interactive --allow-delete --recipient jane`,
 	RunE: runProcess,
 }
 
 func init() {
 	rootCmd.AddCommand(processCmd)
 	processCmd.Flags().StringVar(&processInputPath, "input", "", "Path to source video file (defaults to newest in source directory)")
-	processCmd.Flags().StringVar(&processStartTime, "start", "", "Start timestamp in HH:MM:SS format (auto-detected if omitted)")
-	processCmd.Flags().StringVar(&processEndTime, "end", "", "End timestamp in HH:MM:SS format (auto-detected if omitted)")
+	processCmd.Flags().StringVar(&processStartTime, "start", "", "Start timestamp in HH:MM:SS format, or +HH:MM:SS after the detected start (auto-detected if omitted)")
+	processCmd.Flags().StringVar(&processCues, "cues", "", "Cue file (.csv or .json) from the sound desk marking the start, end and chapters (found next to the source by default)")
+	processCmd.Flags().StringVar(&processEndTime, "end", "", "End timestamp in HH:MM:SS format, -HH:MM:SS before the file end, or +HH:MM:SS after start (auto-detected if omitted)")
 	processCmd.Flags().StringVar(&processMinisterKey, "minister", "", "Minister config key (optional, omit to exclude from email)")
 	processCmd.Flags().StringArrayVar(&processRecipientKeys, "recipient", nil, "Recipient config key(s) (required, can be repeated)")
-	processCmd.Flags().StringArrayVar(&processCCKeys, "cc", nil, "Additional CC config key(s) (optional)")
+	processCmd.Flags().StringArrayVar(&processCCKeys, "cc", nil, "Additional CC by name, config key, group or address, e.g. \"Jane Doe <jane@example.com>\" (can be repeated or comma-separated)")
+	processCmd.Flags().StringArrayVar(&processExcludeKeys, "exclude", nil, "Leave someone out of the recipients, e.g. one member of a --recipient group (can be repeated)")
 	processCmd.Flags().StringVar(&processDateOverride, "date", "", "Override service date (YYYY-MM-DD)")
 	processCmd.Flags().StringVar(&processSenderKey, "sender", "", "Sender config key (defaults to config default_sender)")
+	processCmd.Flags().StringVar(&processServiceType, "service-type", "", "Service type for the email subject's {service_type} (defaults to email.service_type)")
+	processCmd.Flags().StringVar(&processType, "type", "", "Service type whose policy applies: regular (default) or funeral, which shares privately with the recipients and ends their access after funeral.revoke_after_days")
+	processCmd.Flags().BoolVar(&processWithVideo, "with-video", false, "Include the video for a --type that leaves it out by default, such as funeral")
+	processCmd.Flags().StringVar(&processLabel, "label", "", "Label for the email subject's {label} (e.g., 'Confirmation')")
+	processCmd.Flags().StringVar(&processTitle, "title", "", "Sermon title written into the video and audio files, the email and history")
+	processCmd.Flags().StringVar(&processScripture, "scripture", "", "Scripture reading recorded alongside --title (e.g., 'John 3:16')")
+	processCmd.Flags().StringArrayVar(&processNotes, "note", nil, "Note to record with this service in history, e.g. 'organ mic buzzing' (can be repeated)")
+	processCmd.Flags().BoolVar(&processSandbox, "sandbox", false, "Send the email only to the operator with a [TEST] subject (defaults to email.sandbox)")
+	processCmd.Flags().BoolVar(&processStreamAudio, "stream-audio", false, "With --skip-video, upload the MP3 while ffmpeg encodes it (defaults to audio.stream_upload)")
+	processCmd.Flags().StringVar(&processSummaryDir, "summary-dir", "", "Directory to archive the run summary in (overrides summary.dir)")
 	processCmd.Flags().BoolVar(&processSkipVideo, "skip-video", false, "Skip video trimming and upload; extract audio directly from source using timestamps")
+	processCmd.Flags().IntVar(&processAudioTrack, "audio-track", 0, "Audio stream to keep from the source, starting at 1 (defaults to audio.track in config)")
+	processCmd.Flags().BoolVar(&processFromOBS, "from-obs", false, "Stop the active OBS recording and process the file it saved")
+	processCmd.Flags().BoolVar(&processOBSWait, "obs-wait", false, "With --from-obs, wait for the recording to be stopped in OBS instead of stopping it")
+	processCmd.Flags().BoolVar(&processNonInteractive, "non-interactive", false, "Never prompt or open a browser; fail with a machine-readable reason instead (for cron/watch)")
+	processCmd.Flags().BoolVar(&processConfirmSteps, "confirm-each-step", false, "Pause for a yes before deleting files or sending the email, and review the recipients first (ignored with --non-interactive)")
+	processCmd.Flags().BoolVar(&processAllowDelete, "allow-delete", false, "Let the storage step delete the oldest videos from Drive without asking (required with --non-interactive when Drive is full)")
+	processCmd.Flags().BoolVar(&processStrict, "strict", false, "Stop instead of warning when the source's size or aspect doesn't match the video config (defaults to video.strict)")
+	processCmd.Flags().StringVar(&processFolderID, "folder-id", "", "Upload to this Drive folder instead of google.services_folder_id, e.g. for a convention")
+	processCmd.Flags().BoolVar(&processQuick, "quick", false, "Take the minister, recipients, sender and typical service length from the defaults config section, confirm once, and run")
+	processCmd.Flags().StringVar(&processTarget, "target", "", "Where to upload the video: drive, youtube or both (default youtube.target, else drive); the email links YouTube when it is used")
+	processCmd.Flags().BoolVar(&processResume, "resume", false, "Pick up a failed run from its last completed step (the run for --date, or the latest)")
+	processCmd.Flags().StringVar(&processOnExisting, "on-existing", string(video.DefaultOverwritePolicy), "What to do if a trimmed video or MP3 exists: prompt, overwrite, skip, or version")
 
 	// --start and --end are now optional (auto-detected when omitted)
 	// --minister is optional (email will omit minister section if not provided)
-	processCmd.MarkFlagRequired("recipient")
+	// --recipient is required unless --quick takes it from defaults.recipients
 }
 
-func runProcess(cmd *cobra.Command, args []string) error {
+func runProcess(cmd *cobra.Command, args []string) (err error) {
 	cfg := GetConfig()
 	if cfg == nil {
 		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
 	}
+	defer func() {
+		err = nonInteractiveError(processNonInteractive, err)
+	}()
 
-	ctx := cmd.Context()
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := video.ParseOverwritePolicy(processOnExisting); err != nil {
+		return err
+	}
+	failAt, err := simulatedFailureStep()
+	if err != nil {
+		return err
+	}
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+	if processOBSWait && !processFromOBS {
+		return fmt.Errorf("--obs-wait requires --from-obs")
+	}
+	if err := checkFolderOverride(cfg, processFolderID); err != nil {
+		return err
+	}
+	if processResume {
+		return resumeProcess(ctx, cfg, failAt)
+	}
+	if len(processRecipientKeys) == 0 && !processQuick {
+		return fmt.Errorf(`required flag(s) "recipient" not set (or use --quick with defaults.recipients in config)`)
+	}
+
+	// Take the source video straight from OBS when requested
+	inputPath := processInputPath
+	if processFromOBS {
+		if inputPath != "" {
+			return fmt.Errorf("--from-obs cannot be combined with --input")
+		}
+		path, err := finishOBSRecording(ctx, cfg.OBS, processOBSWait, os.Stdout)
+		if err != nil {
+			return err
+		}
+		inputPath = path
+	}
 
 	// Create production dependencies
-	trimmer := ffmpeg.NewTrimmer()
-	extractor := ffmpeg.NewExtractor()
+	trimmer := newTrimmer(cfg)
+	extractor := newExtractor(cfg)
 	fileChecker := filesystem.NewChecker()
-	fileFinder := &ProductionFileFinder{}
+	fileFinder := newFileFinder(cfg, os.Stdout)
 
 	// Resolve video path once (used for both detection types)
-	videoPath := processInputPath
+	videoPath := inputPath
 	if videoPath == "" {
-		// Find newest file
-		newest, err := fileFinder.FindNewestFile(cfg.Paths.SourceDirectory, ".mp4")
+		// Find newest file, searching the source directories by priority
+		newest, err := domainfs.FindNewestSource(fileFinder, cfg.Paths.Sources(), ".mp4")
 		if err != nil {
 			return fmt.Errorf("failed to find video file: %w", err)
 		}
 		videoPath = newest
-	} else if !filepath.IsAbs(videoPath) {
-		videoPath = filepath.Join(cfg.Paths.SourceDirectory, videoPath)
+	} else {
+		videoPath = domainfs.ResolveSource(fileChecker.Exists, cfg.Paths.Sources(), videoPath)
 	}
 
 	// Check if file was already processed (only in auto-detect mode, before running expensive detection)
-	if processInputPath == "" {
-		// Infer date from filename to check if already processed
-		serviceDate, err := inferDateFromFilename(filepath.Base(videoPath))
-		if err == nil {
-			// Create Drive client early to check for existing files
-			driveClient, err := drive.NewClientWithOAuth(ctx, cfg.Google.CredentialsFile, cfg.Google.TokenFile)
+	if inputPath == "" {
+		if _, err := calendar.DateFromFilename(filepath.Base(videoPath)); err == nil {
+			// Create the storage client early to check for existing files
+			driveClient, err := newStorageClient(ctx, cfg, driveAuthOptions(processNonInteractive)...)
 			if err != nil {
-				return fmt.Errorf("failed to create Google Drive client: %w", err)
-			}
-
-			// Check if both mp4 and mp3 already exist on Drive
-			dateStr := serviceDate.Format("2006-01-02")
-			mp4File, mp4Err := driveClient.FindFileByName(ctx, cfg.Google.ServicesFolderID, dateStr+".mp4")
-			if mp4Err != nil {
-				return fmt.Errorf("failed to check Drive for existing files: %w", mp4Err)
+				return err
 			}
-			mp3File, mp3Err := driveClient.FindFileByName(ctx, cfg.Google.ServicesFolderID, dateStr+".mp3")
-			if mp3Err != nil {
-				return fmt.Errorf("failed to check Drive for existing files: %w", mp3Err)
-			}
-			if mp4File != nil && mp3File != nil {
-				return fmt.Errorf("Most recent file (%s) has already been processed. Use --input to specify a different file.", dateStr)
+			if err := checkAlreadyProcessed(ctx, cfg, processFolderID, driveClient, processHistory(cfg), videoPath); err != nil {
+				return err
 			}
 		}
 	}
 
-	// Detect start timestamp if not provided
-	startTime := processStartTime
-	if startTime == "" {
-		// Check if detection is enabled
-		if !cfg.Detection.Enabled {
-			return fmt.Errorf("--start flag is required (auto-detection is disabled in config)")
+	// Pass the resolved source on, so the newest file isn't looked up (and
+	// checked for an in-progress recording) a second time
+	input := ProcessInput{
+		InputPath:      videoPath,
+		StartTime:      processStartTime,
+		EndTime:        processEndTime,
+		MinisterKey:    processMinisterKey,
+		RecipientKeys:  processRecipientKeys,
+		ExcludeKeys:    processExcludeKeys,
+		CCKeys:         processCCKeys,
+		DateOverride:   processDateOverride,
+		SenderKey:      processSenderKey,
+		ServiceType:    processServiceType,
+		Type:           processType,
+		WithVideo:      processWithVideo,
+		Label:          processLabel,
+		Title:          processTitle,
+		Scripture:      processScripture,
+		Notes:          processNotes,
+		Sandbox:        processSandbox,
+		StreamAudio:    processStreamAudio,
+		SummaryDir:     processSummaryDir,
+		SkipVideo:      processSkipVideo,
+		AudioTrack:     processAudioTrack,
+		OnExisting:     processOnExisting,
+		NonInteractive: processNonInteractive,
+		Strict:         processStrict,
+		ConfirmSteps:   processConfirmSteps,
+		AllowDelete:    processAllowDelete,
+		FolderID:       processFolderID,
+		Cues:           processCues,
+		Target:         processTarget,
+		AuditLog:       newAuditLog(cfg),
+
+		SimulateFailureAt: failAt,
+	}
+	if err := applyCues(&input, fileChecker.Exists, os.ReadFile, os.Stdout); err != nil {
+		return err
+	}
+	if processQuick {
+		detectEnd := RequireEndDetection(cfg.Detection, infradetection.Available) == nil
+		if input, err = resolveQuickRun(cfg, input, detectEnd, os.Stdout); err != nil {
+			return err
+		}
+	}
+
+	// Scratch files for this run live in their own workspace, removed on
+	// success and kept after a failure so they can be inspected
+	ws, err := workspace.New(cfg.Paths.WorkspaceDirectory, time.Now())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		kept, cleanErr := ws.Finish(err)
+		if kept {
+			fmt.Fprintf(os.Stdout, "Workspace kept for debugging: %s\n", ws.Root())
+		} else if cleanErr != nil {
+			fmt.Fprintf(os.Stdout, "Warning: %v\n", cleanErr)
+		}
+	}()
+
+	// Detect start timestamp if not provided, or if given relative to the detected start
+	startTime := input.StartTime
+	var detected *appdetection.DetectResult
+	if startTime == "" || strings.HasPrefix(startTime, "+") {
+		// Check if detection is enabled and compiled in
+		if err := RequireDetection(cfg.Detection, infradetection.Available, "--start", startTime); err != nil {
+			return err
 		}
 
 		// Run detection
-		detectedTime, err := detectStartTimestamp(ctx, cfg, videoPath)
+		detected, err = detectStartTimestamp(ctx, cfg, videoPath, ws.Dir(workspace.Frames))
+		if err != nil {
+			return err
+		}
+		confirmed, err := confirmDetected(input, "start", detected.Timestamp, os.Stdout)
 		if err != nil {
 			return err
 		}
-		startTime = detectedTime
+		if startTime, err = offsetFromDetected(startTime, confirmed); err != nil {
+			return err
+		}
+		if confirmed != detected.Timestamp {
+			// A start given by hand is not recorded as a detected one
+			detected = nil
+		}
 	}
 
 	// Detect end timestamp if not provided
-	endTime := processEndTime
+	endTime := input.EndTime
 	if endTime == "" {
-		// Check if detection is enabled
-		if !cfg.Detection.Enabled {
-			return fmt.Errorf("--end flag is required (auto-detection is disabled in config)")
+		// Check if detection is enabled and, for methods that need it, compiled in
+		if err := RequireEndDetection(cfg.Detection, infradetection.Available); err != nil {
+			return err
 		}
 
 		// Parse start time to get seconds for search window calculation
@@ -191,13 +418,15 @@ func runProcess(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		endTime = detectedTime
+		if endTime, err = confirmDetected(input, "end", detectedTime, os.Stdout); err != nil {
+			return err
+		}
 	}
 
-	// Create Drive client
-	driveClient, err := drive.NewClientWithOAuth(ctx, cfg.Google.CredentialsFile, cfg.Google.TokenFile)
+	// Create the Drive (or S3) client
+	driveClient, err := newStorageClient(ctx, cfg, driveAuthOptions(processNonInteractive)...)
 	if err != nil {
-		return fmt.Errorf("failed to create Google Drive client: %w", err)
+		return err
 	}
 
 	// Create Gmail client
@@ -208,21 +437,18 @@ func runProcess(cmd *cobra.Command, args []string) error {
 	gmailClient, err := gmail.NewClientWithOAuth(ctx, gmail.OAuthConfig{
 		CredentialsFile: cfg.Google.CredentialsFile,
 		TokenFile:       cfg.Google.GmailTokenFile,
-	}, from)
+		NonInteractive:  processNonInteractive,
+	}, from, gmail.WithLocation(calendar.Location()), gmail.WithBCCSender(cfg.Email.CopySender(processSandbox)))
 	if err != nil {
 		return fmt.Errorf("failed to create Gmail client: %w", err)
 	}
 
-	input := ProcessInput{
-		InputPath:     processInputPath,
-		StartTime:     startTime,
-		EndTime:       endTime,
-		MinisterKey:   processMinisterKey,
-		RecipientKeys: processRecipientKeys,
-		CCKeys:        processCCKeys,
-		DateOverride:  processDateOverride,
-		SenderKey:     processSenderKey,
-		SkipVideo:     processSkipVideo,
+	// Run with the detected timestamps
+	input.StartTime, input.EndTime = startTime, endTime
+	if detected != nil {
+		input.DetectionConfidence = detected.Confidence
+		input.CameraAngle = detected.CameraAngle
+		input.DetectionEarlyExit = detected.EarlyExit
 	}
 
 	return runProcessWithClients(
@@ -239,28 +465,286 @@ func runProcess(cmd *cobra.Command, args []string) error {
 	)
 }
 
-// detectStartTimestamp runs the detection algorithm and returns the detected timestamp
-func detectStartTimestamp(ctx context.Context, cfg *config.Config, videoPath string) (string, error) {
+// offsetFromDetected applies a +HH:MM:SS --start to the detected start; an
+// empty spec returns the detected start unchanged
+func offsetFromDetected(spec, detected string) (string, error) {
+	if spec == "" {
+		return detected, nil
+	}
+	ts, err := video.ParseTimeSpec(spec)
+	if err != nil {
+		return "", fmt.Errorf("invalid --start: %w", err)
+	}
+	ref, err := video.ParseTimestamp(detected)
+	if err != nil {
+		return "", fmt.Errorf("invalid detected start: %w", err)
+	}
+	resolved, err := ts.Resolve(ref, 0)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(os.Stdout, "Using start %s (%s after detected start)\n\n", resolved, ts.Offset)
+	return resolved.String(), nil
+}
+
+// applyCues fills in the start, end and chapters from the cue file, or one
+// next to the source. Timestamps given by hand win; the ones the file marks
+// need no detection.
+func applyCues(input *ProcessInput, exists func(string) bool, read func(string) ([]byte, error), output io.Writer) error {
+	cueFile := input.Cues
+	if cueFile == "" && input.InputPath != "" {
+		for _, sidecar := range video.CueSidecars(input.InputPath) {
+			if exists(sidecar) {
+				cueFile = sidecar
+				break
+			}
+		}
+	}
+	if cueFile == "" {
+		return nil
+	}
+
+	data, err := read(cueFile)
+	if err != nil {
+		return fmt.Errorf("failed to read cue file: %w", err)
+	}
+	cues, err := video.ParseCues(cueFile, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cueFile, err)
+	}
+	fmt.Fprintf(output, "Using cue file: %s\n", cueFile)
+	if cues.Start != nil {
+		if input.StartTime == "" {
+			input.StartTime = cues.Start.String()
+			fmt.Fprintf(output, "  Start: %s\n", input.StartTime)
+		} else {
+			fmt.Fprintf(output, "  Start: %s (--start; the cue file has %s)\n", input.StartTime, cues.Start)
+		}
+	}
+	if cues.End != nil {
+		if input.EndTime == "" {
+			input.EndTime = cues.End.String()
+			fmt.Fprintf(output, "  End:   %s\n", input.EndTime)
+		} else {
+			fmt.Fprintf(output, "  End:   %s (--end; the cue file has %s)\n", input.EndTime, cues.End)
+		}
+	}
+	if len(cues.Chapters) > 0 {
+		fmt.Fprintf(output, "  Chapters: %d\n", len(cues.Chapters))
+	}
+	fmt.Fprintln(output)
+	input.Chapters = cues.Chapters
+	return nil
+}
+
+// readFileFrom reads files from fsys, or the local disk when it is nil
+func readFileFrom(fsys domainfs.FS) func(string) ([]byte, error) {
+	if fsys == nil {
+		return os.ReadFile
+	}
+	return func(name string) ([]byte, error) {
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+}
+
+// resumeProcess picks up the saved run for --date, or the latest one, with
+// the options it was started with. Only how the run is supervised comes from
+// this command's flags.
+func resumeProcess(ctx context.Context, cfg *config.Config, failAt int) error {
+	state, err := savedRun(infrarunstate.NewDirStore(cfg.Paths.StateDirectory), processDateOverride)
+	if err != nil {
+		return err
+	}
+
+	driveClient, err := newStorageClient(ctx, cfg, driveAuthOptions(processNonInteractive)...)
+	if err != nil {
+		return err
+	}
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+	gmailClient, err := gmail.NewClientWithOAuth(ctx, gmail.OAuthConfig{
+		CredentialsFile: cfg.Google.CredentialsFile,
+		TokenFile:       cfg.Google.GmailTokenFile,
+		NonInteractive:  processNonInteractive,
+	}, notification.Recipient{Name: cfg.Email.FromName, Address: cfg.Email.FromAddress},
+		gmail.WithLocation(calendar.Location()), gmail.WithBCCSender(cfg.Email.CopySender(state.Params.Sandbox)))
+	if err != nil {
+		return fmt.Errorf("failed to create Gmail client: %w", err)
+	}
+
+	input := ResumeProcessInput(state)
+	input.SummaryDir = processSummaryDir
+	input.OnExisting = processOnExisting
+	input.NonInteractive = processNonInteractive
+	input.ConfirmSteps = processConfirmSteps
+	input.AllowDelete = processAllowDelete
+	input.FolderID = processFolderID
+	input.AuditLog = newAuditLog(cfg)
+	input.SimulateFailureAt = failAt
+
+	return runProcessWithClients(ctx, cfg, newTrimmer(cfg), newExtractor(cfg), filesystem.NewChecker(),
+		driveClient, gmailClient, newFileFinder(cfg, os.Stdout), input, os.Stdout)
+}
+
+// savedRun returns the saved run for date (YYYY-MM-DD), or the latest one
+// when date is empty
+func savedRun(store *infrarunstate.DirStore, date string) (*runstate.State, error) {
+	if date == "" {
+		state, err := store.Latest()
+		if errors.Is(err, runstate.ErrNoState) {
+			return nil, fmt.Errorf("nothing to resume: no unfinished run is saved in %s", store.Dir())
+		}
+		return state, err
+	}
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --date: %w", err)
+	}
+	state, err := store.Load(serviceDate)
+	if errors.Is(err, runstate.ErrNoState) {
+		return nil, fmt.Errorf("nothing to resume for %s: the run finished or was never started", date)
+	}
+	return state, err
+}
+
+// ResumeProcessInput returns the options a saved run was started with, set
+// to resume it
+func ResumeProcessInput(state *runstate.State) ProcessInput {
+	in := appprocess.ResumeInput(state)
+	return ProcessInput{
+		InputPath:     in.InputPath,
+		StartTime:     in.StartTime,
+		EndTime:       in.EndTime,
+		MinisterKey:   in.MinisterKey,
+		RecipientKeys: in.RecipientKeys,
+		ExcludeKeys:   in.ExcludeKeys,
+		CCKeys:        in.CCKeys,
+		DateOverride:  in.DateOverride,
+		SenderKey:     in.SenderKey,
+		ServiceType:   in.ServiceType,
+		Type:          in.Type,
+		WithVideo:     in.WithVideo,
+		Label:         in.Label,
+		Title:         in.Title,
+		Scripture:     in.Scripture,
+		Notes:         in.Notes,
+		Sandbox:       in.Sandbox,
+		SkipVideo:     in.SkipVideo,
+		AudioTrack:    in.AudioTrack,
+		Chapters:      in.Chapters,
+		Target:        string(in.Target),
+		Resume:        in.Resume,
+	}
+}
+
+// applyTypeDefaults fills in what the --type policy decides before the
+// clients are set up: a private service's video stays off the video host
+// unless --target asks for it, and an optional video is skipped unless
+// --with-video asks for it. An unknown type is left for process to report.
+func applyTypeDefaults(cfg *config.Config, input ProcessInput) ProcessInput {
+	p, err := cfg.ServicePolicy(input.Type)
+	if err != nil {
+		return input
+	}
+	if !p.PublicSharing && input.Target == "" {
+		input.Target = string(distribution.TargetDrive)
+	}
+	if p.VideoOptional && !input.WithVideo {
+		input.SkipVideo = true
+	}
+	return input
+}
+
+// RequireDetection returns why flag must be given by hand, or nil when
+// auto-detection can fill it in. A non-empty value is a --start relative to
+// the detected start. available reports whether the build has -tags=detection.
+func RequireDetection(cfg config.DetectionConfig, available bool, flag, value string) error {
+	switch {
+	case !cfg.Enabled && value != "":
+		return fmt.Errorf("%s %s is relative to the detected start, but auto-detection is disabled in config", flag, value)
+	case !cfg.Enabled:
+		return fmt.Errorf("%s flag is required (auto-detection is disabled in config)", flag)
+	case !available && value != "":
+		return fmt.Errorf("%s %s is relative to the detected start, but %w (built without -tags=detection); give an absolute %s", flag, value, detection.ErrDetectionUnavailable, flag)
+	case !available:
+		return fmt.Errorf("%s flag is required: %w (built without -tags=detection), so detection.enabled has no effect", flag, detection.ErrDetectionUnavailable)
+	}
+	return nil
+}
+
+// RequireEndDetection returns why --end must be given by hand, or nil when
+// detection.end.method can find the end. The silence method only needs
+// ffmpeg, so it works without -tags=detection.
+func RequireEndDetection(cfg config.DetectionConfig, available bool) error {
+	return RequireDetection(cfg, available || !cfg.EndMethod().NeedsDetectionBuild(), "--end", "")
+}
+
+// confirmDetected asks whether to use a detected timestamp, taking a
+// corrected one when the answer is no. With --non-interactive, or with
+// nobody at a terminal (e.g. the scheduled task), the detected timestamp is
+// used as is.
+func confirmDetected(input ProcessInput, which, detected string, output io.Writer) (string, error) {
+	if input.unattended() {
+		return detected, nil
+	}
+	prompter := input.prompter()
+	ok, err := prompter.Confirm(fmt.Sprintf("Use the detected %s %s?", which, detected), true)
+	if err != nil || ok {
+		return detected, err
+	}
+	answer, err := prompter.Input(fmt.Sprintf("Service %s (HH:MM:SS)", which), detected)
+	if err != nil {
+		return "", err
+	}
+	ts, err := video.ParseTimestamp(strings.TrimSpace(answer))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s timestamp: %w", which, err)
+	}
+	fmt.Fprintf(output, "Using %s %s instead of the detected %s\n\n", which, ts, detected)
+	return ts.String(), nil
+}
+
+// stdinIsTerminal reports whether someone can answer prompts
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// detectStartTimestamp runs the detection algorithm and returns the detected start
+// Frames are extracted into framesDir
+func detectStartTimestamp(ctx context.Context, cfg *config.Config, videoPath, framesDir string) (*appdetection.DetectResult, error) {
 	// Create detection service
-	detectionService := appdetection.NewService(cfg.Detection, os.Stdout)
+	opts := []appdetection.Option{appdetection.WithFramesDir(framesDir), appdetection.WithFFmpegPath(ffmpegTools(cfg).FFmpeg)}
+	if cfg.History.File != "" {
+		// Recent detected starts narrow where the search looks
+		opts = append(opts, appdetection.WithHistory(infrahistory.NewJSONStore(cfg.History.File)))
+	}
+	detectionService := appdetection.NewService(cfg.Detection, os.Stdout, opts...)
 
 	// Run detection
 	result, err := detectionService.DetectStart(ctx, appdetection.DetectInput{
 		VideoPath: videoPath,
 	})
 	if err != nil {
-		return "", fmt.Errorf("auto-detection failed: %w\nUse --start to specify manually", err)
+		return nil, fmt.Errorf("auto-detection failed: %w\nUse --start to specify manually", err)
 	}
 
 	fmt.Fprintf(os.Stdout, "Using detected timestamp: %s\n\n", result.Timestamp)
-	return result.Timestamp, nil
+	return result, nil
 }
 
-// detectEndTimestamp runs the amen detection algorithm and returns the detected end timestamp
+// detectEndTimestamp runs end detection with detection.end.method and returns the detected end timestamp
 // startTimeSeconds is the service start time used to calculate where to begin searching
 func detectEndTimestamp(ctx context.Context, cfg *config.Config, videoPath string, startTimeSeconds int) (string, error) {
 	// Create detection service
-	detectionService := appdetection.NewService(cfg.Detection, os.Stdout)
+	detectionService := appdetection.NewService(cfg.Detection, os.Stdout, appdetection.WithFFmpegPath(ffmpegTools(cfg).FFmpeg))
 
 	// Run detection, passing start time so it searches from (start + offset) minutes
 	result, err := detectionService.DetectEnd(ctx, videoPath, startTimeSeconds)
@@ -274,15 +758,239 @@ func detectEndTimestamp(ctx context.Context, cfg *config.Config, videoPath strin
 
 // ProcessInput contains the input parameters for process command
 type ProcessInput struct {
-	InputPath     string
-	StartTime     string
-	EndTime       string
-	MinisterKey   string
-	RecipientKeys []string
-	CCKeys        []string
-	DateOverride  string
-	SenderKey     string
-	SkipVideo     bool
+	InputPath      string
+	StartTime      string
+	EndTime        string
+	MinisterKey    string
+	RecipientKeys  []string
+	ExcludeKeys    []string
+	CCKeys         []string
+	DateOverride   string
+	SenderKey      string
+	ServiceType    string // Email subject {service_type}
+	Type           string // Service type whose policy applies, e.g. funeral
+	WithVideo      bool   // Include the video when the type leaves it out
+	Label          string // Email subject {label}
+	Title          string // Sermon title tagged on the files and shown in the email
+	Scripture      string
+	Notes          []string
+	Sandbox        bool   // Send the email only to the operator
+	StreamAudio    bool   // Upload audio-only output while it is encoded
+	SummaryDir     string // Archive the run summary here; overrides summary.dir
+	SkipVideo      bool
+	AudioTrack     int    // 1-based audio stream to keep; 0 uses audio.track
+	OnExisting     string // Overwrite policy for trimmed video and MP3 outputs
+	NonInteractive bool   // Fail with a reason instead of prompting
+	ConfirmSteps   bool   // Pause before deleting files or sending the email
+	AllowDelete    bool   // Delete old videos to make room on Drive without asking
+	Strict         bool   // Stop when the source's size or aspect looks wrong
+	FolderID       string // Drive folder for this run; overrides google.services_folder_id
+	Quick          bool   // Fill in the rest from the defaults config section and confirm once
+	Cues           string // Cue file with the start, end and chapters; one next to the source is used otherwise
+	Target         string // Where the video goes: drive, youtube or both; empty uses youtube.target
+
+	// Chapters mark parts of the service in the trimmed video
+	Chapters []video.Chapter
+
+	// SimulateFailureAt fails this step on purpose (development builds only)
+	SimulateFailureAt int
+
+	// DetectionConfidence and CameraAngle describe the auto-detected start,
+	// recorded in history to track template match drift
+	DetectionConfidence float64
+	CameraAngle         string
+	DetectionEarlyExit  bool
+
+	// Recorder, when set, supplies the source video by finishing the active recording
+	Recorder         recording.Recorder
+	WaitForRecording bool // Wait for the recording to stop instead of stopping it
+
+	// Publisher, when set, mirrors outputs to an alternate download server
+	Publisher distribution.Publisher
+
+	// History, when set, records the completed run
+	History history.Store
+
+	// Prober, when set, reads the source length for -HH:MM:SS timestamps
+	Prober video.DurationProber
+
+	// ModTimes, when set, checks the recording's name against when it was written
+	ModTimes domainfs.ModTimer
+
+	// GeometryProber, when set, checks the source's size and aspect
+	GeometryProber video.GeometryProber
+
+	// Summary, when set, archives the run summary instead of SummaryDir
+	Summary summary.Archive
+
+	// Scanner, when set, checks outputs before they are shared publicly
+	Scanner distribution.Scanner
+
+	// Prompter, when set, answers questions instead of the terminal
+	Prompter ui.Prompter
+
+	// AuditLog, when set, records whether deleting old videos was approved
+	AuditLog audit.Recorder
+
+	// FS, when set, holds the local outputs that are uploaded and published
+	FS domainfs.FS
+
+	// VideoHost, when set, takes the uploads for a youtube or both Target
+	// instead of signing in to YouTube
+	VideoHost distribution.VideoHost
+
+	// RunState, when set, saves progress after each step; with Resume the
+	// saved run for the date is picked up where it stopped
+	RunState runstate.Store
+	Resume   bool
+}
+
+// unattended reports whether nobody can answer questions during the run:
+// with --non-interactive, or with no prompter and no terminal on stdin (e.g.
+// the scheduled task)
+func (input ProcessInput) unattended() bool {
+	return input.NonInteractive || (input.Prompter == nil && !stdinIsTerminal())
+}
+
+// prompter returns who answers questions during the run: nobody with NonInteractive
+func (input ProcessInput) prompter() ui.Prompter {
+	if input.NonInteractive {
+		return ui.NonInteractivePrompter{}
+	}
+	if input.Prompter != nil {
+		return input.Prompter
+	}
+	return DefaultPrompter
+}
+
+// stepConfirmation pauses the run at each irreversible step with
+// --confirm-each-step, before going over a confirm-only upload budget, and
+// before deleting old videos to make room unless --allow-delete is given.
+// Unattended runs (--non-interactive, or no terminal on stdin) have nobody to
+// ask, so the checkpoints are skipped and deleting needs --allow-delete.
+func stepConfirmation(input ProcessInput, output io.Writer) []appprocess.Option {
+	opts := []appprocess.Option{appprocess.WithDeleteAllowed(input.AllowDelete)}
+	if input.AuditLog != nil {
+		opts = append(opts, appprocess.WithAuditLog(input.AuditLog))
+	}
+	unattended := input.unattended()
+	if !unattended {
+		opts = append(opts,
+			appprocess.WithBudgetConfirmation(ConfirmStep(input.prompter())),
+			appprocess.WithDeleteConfirmation(ConfirmStep(input.prompter())),
+		)
+	}
+	if !input.ConfirmSteps {
+		return opts
+	}
+	if input.NonInteractive {
+		fmt.Fprintln(output, "Note: --confirm-each-step is ignored with --non-interactive")
+		return opts
+	}
+	if unattended {
+		fmt.Fprintln(output, "Note: --confirm-each-step is ignored without a terminal")
+		return opts
+	}
+	return append(opts,
+		appprocess.WithStepConfirmation(ConfirmStep(input.prompter())),
+		appprocess.WithRecipientReview(ReviewRecipients(input.prompter())),
+	)
+}
+
+const (
+	choiceSend     = "Send to these recipients"
+	choiceAddTo    = "Add a recipient"
+	choiceAddCC    = "Add a CC"
+	choiceRemoveRc = "Remove someone"
+)
+
+// ReviewRecipients asks the operator whether to change the email recipients
+// before sending, one edit at a time
+func ReviewRecipients(prompter ui.Prompter) appprocess.RecipientReviewFunc {
+	return func(to, cc []notification.Recipient) (appprocess.RecipientEdit, error) {
+		choice, err := prompter.Select("Recipients:", []string{choiceSend, choiceAddTo, choiceAddCC, choiceRemoveRc}, choiceSend)
+		if err != nil {
+			return appprocess.RecipientEdit{}, err
+		}
+		switch choice {
+		case choiceAddTo, choiceAddCC:
+			query, err := prompter.Input("Name, key or group to add:", "")
+			if err != nil {
+				return appprocess.RecipientEdit{}, err
+			}
+			return appprocess.RecipientEdit{Add: strings.TrimSpace(query), CC: choice == choiceAddCC}, nil
+		case choiceRemoveRc:
+			everyone := append(append([]notification.Recipient{}, to...), cc...)
+			options := make([]string, len(everyone))
+			for i, rc := range everyone {
+				options[i] = rc.String()
+			}
+			picked, err := prompter.Select("Remove who?", options, options[0])
+			if err != nil {
+				return appprocess.RecipientEdit{}, err
+			}
+			for i, option := range options {
+				if option == picked {
+					return appprocess.RecipientEdit{Remove: everyone[i].Address}, nil
+				}
+			}
+		}
+		return appprocess.RecipientEdit{}, nil
+	}
+}
+
+// ConfirmStep asks the operator whether to go ahead with the next irreversible action
+func ConfirmStep(prompter ui.Prompter) appprocess.StepConfirmFunc {
+	return func(action string) (bool, error) {
+		return prompter.Confirm("Continue?", false)
+	}
+}
+
+// driveAuthOptions stops the Drive client from opening a browser to sign in
+// when running non-interactively
+func driveAuthOptions(nonInteractive bool) []drive.ClientOption {
+	if nonInteractive {
+		return []drive.ClientOption{drive.WithNonInteractiveAuth()}
+	}
+	return nil
+}
+
+// nonInteractiveError surfaces failures that needed the user as an
+// InputRequiredError, so scripts get a stable reason to match on
+func nonInteractiveError(nonInteractive bool, err error) error {
+	if !nonInteractive || err == nil {
+		return err
+	}
+	var inputErr *ui.InputRequiredError
+	switch {
+	case errors.As(err, &inputErr):
+		return inputErr
+	case errors.Is(err, drive.ErrAuthRequired), errors.Is(err, gmail.ErrAuthRequired):
+		return &ui.InputRequiredError{Reason: ui.ReasonAuthRequired, Err: err}
+	case errors.Is(err, notification.ErrAmbiguousRecipient):
+		return &ui.InputRequiredError{Reason: ui.ReasonAmbiguousRecipient, Err: err}
+	}
+	return err
+}
+
+// summaryArchive returns where the run summary is archived: input.Summary,
+// else --summary-dir, else summary.dir. It returns nil when none is set.
+func summaryArchive(cfg *config.Config, input ProcessInput) (summary.Archive, error) {
+	if input.Summary != nil {
+		return input.Summary, nil
+	}
+	dir := input.SummaryDir
+	if dir == "" {
+		dir = cfg.Summary.Dir
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	formats, err := summary.ParseFormats(cfg.Summary.Formats)
+	if err != nil {
+		return nil, fmt.Errorf("invalid summary.formats: %w", err)
+	}
+	return infrasummary.NewDirArchive(dir, formats), nil
 }
 
 // FileFinder interface for finding files (allows testing)
@@ -291,8 +999,20 @@ type FileFinder interface {
 	ListFiles(dir, ext string) ([]string, error)
 }
 
-// ProductionFileFinder implements FileFinder for production use
-type ProductionFileFinder struct{}
+// DefaultInProgressWait is how long the "wait" policy waits for a recording to finish
+const DefaultInProgressWait = 30 * time.Minute
+
+// ProductionFileFinder implements FileFinder for production use. With Growth
+// set, it checks that the newest file is not still being recorded and applies
+// InProgress (error, skip, or wait) when it is.
+type ProductionFileFinder struct {
+	Growth     domainfs.GrowthDetector
+	InProgress string    // Policy from domainfs; empty means error
+	Output     io.Writer // Skip and wait messages (optional)
+
+	WaitTimeout  time.Duration // Zero means DefaultInProgressWait
+	PollInterval time.Duration // Zero means 5 seconds
+}
 
 func (f *ProductionFileFinder) FindNewestFile(dir, ext string) (string, error) {
 	files, err := f.ListFiles(dir, ext)
@@ -308,7 +1028,86 @@ func (f *ProductionFileFinder) FindNewestFile(dir, ext string) (string, error) {
 		return files[i] > files[j]
 	})
 
-	return files[0], nil
+	if f.Growth == nil {
+		return files[0], nil
+	}
+	return f.firstComplete(files)
+}
+
+// firstComplete applies the in-progress policy to files sorted newest first
+func (f *ProductionFileFinder) firstComplete(files []string) (string, error) {
+	policy, err := domainfs.ParseInProgressPolicy(f.InProgress)
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		growing, err := f.Growth.IsGrowing(file)
+		if err != nil {
+			return "", err
+		}
+		if !growing {
+			return file, nil
+		}
+
+		switch policy {
+		case domainfs.InProgressSkip:
+			f.printf("Skipping %s: it is still being recorded\n", filepath.Base(file))
+			continue
+		case domainfs.InProgressWait:
+			if err := f.waitUntilComplete(file); err != nil {
+				return "", err
+			}
+			return file, nil
+		default:
+			return "", fmt.Errorf("%s is still being recorded; stop the recording in OBS and try again, or use --input: %w",
+				filepath.Base(file), domainfs.ErrFileGrowing)
+		}
+	}
+	return "", fmt.Errorf("every video file is still being recorded: %w", domainfs.ErrFileGrowing)
+}
+
+// waitUntilComplete polls until the file stops growing or the wait times out
+func (f *ProductionFileFinder) waitUntilComplete(file string) error {
+	timeout := f.WaitTimeout
+	if timeout <= 0 {
+		timeout = DefaultInProgressWait
+	}
+	poll := f.PollInterval
+	if poll <= 0 {
+		poll = 5 * time.Second
+	}
+
+	f.printf("Waiting for %s to finish recording...\n", filepath.Base(file))
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(poll)
+		growing, err := f.Growth.IsGrowing(file)
+		if err != nil {
+			return err
+		}
+		if !growing {
+			f.printf("Recording finished: %s\n", filepath.Base(file))
+			return nil
+		}
+	}
+	return fmt.Errorf("%s was still being recorded after %s: %w", filepath.Base(file), timeout, domainfs.ErrFileGrowing)
+}
+
+func (f *ProductionFileFinder) printf(format string, args ...any) {
+	if f.Output != nil {
+		fmt.Fprintf(f.Output, format, args...)
+	}
+}
+
+// newFileFinder returns a finder that applies paths.in_progress to recordings
+// that are still being written
+func newFileFinder(cfg *config.Config, output io.Writer) *ProductionFileFinder {
+	return &ProductionFileFinder{
+		Growth:     filesystem.NewGrowthDetector(),
+		InProgress: cfg.Paths.InProgress,
+		Output:     output,
+	}
 }
 
 func (f *ProductionFileFinder) ListFiles(dir, ext string) ([]string, error) {
@@ -342,21 +1141,94 @@ func runProcessWithClients(
 	input ProcessInput,
 	output io.Writer,
 ) error {
-	// Verify ffmpeg is available
-	if verifiable, ok := trimmer.(interface{ VerifyInstalled(context.Context) error }); ok {
-		verifyCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
-		if err := verifiable.VerifyInstalled(verifyCtx); err != nil {
-			return fmt.Errorf("ffmpeg verification failed: %w", err)
+	// Verify ffmpeg is available and new enough
+	ffmpegVersion, err := verifyFFmpeg(ctx, trimmer, output)
+	if err != nil {
+		return err
+	}
+
+	overwrite, err := overwriteOptions(cfg, input.OnExisting, input.prompter())
+	if err != nil {
+		return err
+	}
+
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+	serviceOpts := []appprocess.Option{appprocess.WithCalendar(calendar)}
+
+	// Mirror to the alternate download server when configured
+	if cfg.Publish.Auto {
+		publisher, err := newPublisher(cfg.Publish, cfg.Publish.Provider)
+		if err != nil {
+			return fmt.Errorf("invalid publish config: %w", err)
+		}
+		defer closePublisher(publisher)
+		serviceOpts = append(serviceOpts, appprocess.WithPublisher(publisher))
+	}
+	if store := processHistory(cfg); store != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithHistory(store))
+	}
+	if input.SimulateFailureAt > 0 {
+		serviceOpts = append(serviceOpts, appprocess.WithSimulatedFailure(input.SimulateFailureAt))
+	}
+	if input.FolderID != "" {
+		serviceOpts = append(serviceOpts, appprocess.WithFolderID(input.FolderID))
+	}
+	scanner, err := newShareScanner(cfg)
+	if err != nil {
+		return err
+	}
+	if scanner != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithShareScanner(scanner))
+	}
+	validator := newValidator(cfg)
+	serviceOpts = append(serviceOpts, appprocess.WithDurationProber(validator), appprocess.WithGeometryProber(validator))
+	serviceOpts = append(serviceOpts, appprocess.WithModTimes(filesystem.NewChecker()))
+	serviceOpts = append(serviceOpts, appprocess.WithRunState(infrarunstate.NewDirStore(cfg.Paths.StateDirectory)))
+	serviceOpts = append(serviceOpts, appprocess.WithUsageSampler(infraresource.NewProcSampler()))
+	serviceOpts = append(serviceOpts, appprocess.WithShareExpiry())
+	input = applyTypeDefaults(cfg, input)
+	target, err := uploadTarget(cfg, input.Target)
+	if err != nil {
+		return err
+	}
+	if target.UsesVideoHost() && !input.SkipVideo {
+		host := input.VideoHost
+		if host == nil {
+			if host, err = newVideoHost(ctx, cfg, input.NonInteractive); err != nil {
+				return err
+			}
+		}
+		serviceOpts = append(serviceOpts, appprocess.WithVideoHost(host))
+	}
+	archive, err := summaryArchive(cfg, input)
+	if err != nil {
+		return err
+	}
+	if archive != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithSummaryArchive(archive))
+	}
+
+	if cfg.Archive.Directory != "" {
+		policy, err := cfg.Archive.Policy()
+		if err != nil {
+			return fmt.Errorf("invalid archive: %w", err)
 		}
+		archiver := filesystem.NewLocalArchiver(cfg.Archive.Directory, policy,
+			filesystem.WithVideoBitrate(cfg.Archive.VideoBitrate.String()), filesystem.WithArchiveFFmpegPath(ffmpegTools(cfg).FFmpeg))
+		serviceOpts = append(serviceOpts, appprocess.WithLocalArchive(archiver, policy))
 	}
 
+	serviceOpts = append(serviceOpts, stepConfirmation(input, output)...)
+
 	// Create file sizer
 	fileSizer := &productionFileSizer{}
 
 	// Create disk checker and file remover for local cleanup
 	diskChecker := filesystem.NewDiskUsageChecker()
-	fileRemover := filesystem.NewRemover()
+	fileRemover := filesystem.NewRemover(filesystem.WithAuditLog(newAuditLog(cfg)))
 
 	// Create process service
 	service := appprocess.NewService(
@@ -371,22 +1243,45 @@ func runProcessWithClients(
 		output,
 		diskChecker,
 		fileRemover,
+		serviceOpts...,
 	)
 
 	// Build input
 	processInput := appprocess.Input{
-		InputPath:     input.InputPath,
-		StartTime:     input.StartTime,
-		EndTime:       input.EndTime,
-		MinisterKey:   input.MinisterKey,
-		RecipientKeys: input.RecipientKeys,
-		CCKeys:        input.CCKeys,
-		DateOverride:  input.DateOverride,
-		SenderKey:     input.SenderKey,
-		SkipVideo:     input.SkipVideo,
-	}
-
-	_, err := service.Process(ctx, processInput)
+		InputPath:      input.InputPath,
+		StartTime:      input.StartTime,
+		EndTime:        input.EndTime,
+		MinisterKey:    input.MinisterKey,
+		RecipientKeys:  input.RecipientKeys,
+		ExcludeKeys:    input.ExcludeKeys,
+		CCKeys:         input.CCKeys,
+		DateOverride:   input.DateOverride,
+		SenderKey:      input.SenderKey,
+		ServiceType:    input.ServiceType,
+		Type:           input.Type,
+		WithVideo:      input.WithVideo,
+		Label:          input.Label,
+		Title:          input.Title,
+		Scripture:      input.Scripture,
+		Notes:          input.Notes,
+		Sandbox:        input.Sandbox,
+		StreamAudio:    input.StreamAudio,
+		SkipVideo:      input.SkipVideo,
+		AudioTrack:     input.AudioTrack,
+		Overwrite:      overwrite,
+		NonInteractive: input.NonInteractive,
+		StrictGeometry: input.Strict,
+		Resume:         input.Resume,
+		Chapters:       input.Chapters,
+		Target:         target,
+
+		DetectionConfidence: input.DetectionConfidence,
+		CameraAngle:         input.CameraAngle,
+		DetectionEarlyExit:  input.DetectionEarlyExit,
+		FFmpegVersion:       ffmpegVersion,
+	}
+
+	_, err = service.Process(ctx, processInput)
 	return err
 }
 
@@ -406,13 +1301,18 @@ func RunProcessWithDependencies(
 	diskChecker domainfs.DiskChecker,
 	fileRemover domainfs.FileRemover,
 ) error {
-	// Verify ffmpeg is available
-	if verifiable, ok := trimmer.(interface{ VerifyInstalled(context.Context) error }); ok {
-		verifyCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
-		if err := verifiable.VerifyInstalled(verifyCtx); err != nil {
-			return fmt.Errorf("ffmpeg verification failed: %w", err)
+	// Verify ffmpeg is available and new enough
+	ffmpegVersion, err := verifyFFmpeg(ctx, trimmer, output)
+	if err != nil {
+		return err
+	}
+
+	if input.Recorder != nil {
+		path, err := finishRecording(ctx, input.Recorder, input.WaitForRecording, output)
+		if err != nil {
+			return err
 		}
+		input.InputPath = path
 	}
 
 	// Create Drive client wrapper
@@ -421,12 +1321,96 @@ func RunProcessWithDependencies(
 		return fmt.Errorf("failed to create drive client: %w", err)
 	}
 
+	// Check if file was already processed (only in auto-detect mode)
+	if input.InputPath == "" {
+		if newest, err := domainfs.FindNewestSource(fileFinder, cfg.Paths.Sources(), ".mp4"); err == nil {
+			if err := checkAlreadyProcessed(ctx, cfg, input.FolderID, driveClient, input.History, newest); err != nil {
+				return err
+			}
+		}
+	}
+
+	if input.Quick {
+		if input.InputPath == "" {
+			if newest, err := domainfs.FindNewestSource(fileFinder, cfg.Paths.Sources(), ".mp4"); err == nil {
+				input.InputPath = newest
+			}
+		}
+		if input, err = resolveQuickRun(cfg, input, false, output); err != nil {
+			return nonInteractiveError(input.NonInteractive, err)
+		}
+	}
+
+	if err := applyCues(&input, fileChecker.Exists, readFileFrom(input.FS), output); err != nil {
+		return err
+	}
+
 	// Create Gmail client wrapper
 	from := notification.Recipient{
 		Name:    cfg.Email.FromName,
 		Address: cfg.Email.FromAddress,
 	}
-	gmailClient := gmail.NewClient(from, gmail.WithGmailService(gmailService))
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+	gmailClient := gmail.NewClient(from, gmail.WithGmailService(gmailService), gmail.WithLocation(calendar.Location()), gmail.WithBCCSender(cfg.Email.CopySender(input.Sandbox)))
+
+	// Existing outputs are validated by existence only; there is no ffprobe in tests
+	policy, err := video.ParseOverwritePolicy(input.OnExisting)
+	if err != nil {
+		return err
+	}
+	overwrite := appvideo.OverwriteOptions{Policy: policy, Confirm: ConfirmOverwrite(input.prompter())}
+
+	serviceOpts := []appprocess.Option{appprocess.WithCalendar(calendar)}
+	if input.Publisher != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithPublisher(input.Publisher))
+	}
+	if input.History != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithHistory(input.History))
+	}
+	if input.Prober != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithDurationProber(input.Prober))
+	}
+	if input.GeometryProber != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithGeometryProber(input.GeometryProber))
+	}
+	if input.ModTimes != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithModTimes(input.ModTimes))
+	}
+	if input.SimulateFailureAt > 0 {
+		serviceOpts = append(serviceOpts, appprocess.WithSimulatedFailure(input.SimulateFailureAt))
+	}
+	if input.FolderID != "" {
+		serviceOpts = append(serviceOpts, appprocess.WithFolderID(input.FolderID))
+	}
+	if input.Scanner != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithShareScanner(input.Scanner))
+	}
+	if input.FS != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithFS(input.FS))
+	}
+	if input.RunState != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithRunState(input.RunState))
+	}
+	input = applyTypeDefaults(cfg, input)
+	target, err := uploadTarget(cfg, input.Target)
+	if err != nil {
+		return err
+	}
+	if input.VideoHost != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithVideoHost(input.VideoHost))
+	}
+	archive, err := summaryArchive(cfg, input)
+	if err != nil {
+		return err
+	}
+	if archive != nil {
+		serviceOpts = append(serviceOpts, appprocess.WithSummaryArchive(archive))
+	}
+
+	serviceOpts = append(serviceOpts, stepConfirmation(input, output)...)
 
 	// Create file sizer that uses the mock file checker
 	fileSizer := &mockFileSizer{fileChecker: fileChecker}
@@ -444,23 +1428,73 @@ func RunProcessWithDependencies(
 		output,
 		diskChecker,
 		fileRemover,
+		serviceOpts...,
 	)
 
 	// Build input
 	processInput := appprocess.Input{
-		InputPath:     input.InputPath,
-		StartTime:     input.StartTime,
-		EndTime:       input.EndTime,
-		MinisterKey:   input.MinisterKey,
-		RecipientKeys: input.RecipientKeys,
-		CCKeys:        input.CCKeys,
-		DateOverride:  input.DateOverride,
-		SenderKey:     input.SenderKey,
-		SkipVideo:     input.SkipVideo,
+		InputPath:      input.InputPath,
+		StartTime:      input.StartTime,
+		EndTime:        input.EndTime,
+		MinisterKey:    input.MinisterKey,
+		RecipientKeys:  input.RecipientKeys,
+		ExcludeKeys:    input.ExcludeKeys,
+		CCKeys:         input.CCKeys,
+		DateOverride:   input.DateOverride,
+		SenderKey:      input.SenderKey,
+		ServiceType:    input.ServiceType,
+		Type:           input.Type,
+		WithVideo:      input.WithVideo,
+		Label:          input.Label,
+		Title:          input.Title,
+		Scripture:      input.Scripture,
+		Notes:          input.Notes,
+		Sandbox:        input.Sandbox,
+		StreamAudio:    input.StreamAudio,
+		SkipVideo:      input.SkipVideo,
+		AudioTrack:     input.AudioTrack,
+		Overwrite:      overwrite,
+		NonInteractive: input.NonInteractive,
+		StrictGeometry: input.Strict,
+		Resume:         input.Resume,
+		Chapters:       input.Chapters,
+		Target:         target,
+
+		DetectionConfidence: input.DetectionConfidence,
+		CameraAngle:         input.CameraAngle,
+		DetectionEarlyExit:  input.DetectionEarlyExit,
+		FFmpegVersion:       ffmpegVersion,
 	}
 
 	_, err = service.Process(ctx, processInput)
-	return err
+	return nonInteractiveError(input.NonInteractive, err)
+}
+
+// finishOBSRecording connects to OBS and returns the path of the finished recording
+func finishOBSRecording(ctx context.Context, cfg config.OBSConfig, wait bool, output io.Writer) (string, error) {
+	url := cfg.URL
+	if url == "" {
+		url = obs.DefaultURL
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	client, err := obs.Dial(dialCtx, url, obs.WithPassword(cfg.Password))
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	return finishRecording(ctx, client, wait, output)
+}
+
+// finishRecording stops (or waits for) the active recording and returns its file path
+func finishRecording(ctx context.Context, recorder recording.Recorder, wait bool, output io.Writer) (string, error) {
+	path, err := apprecording.NewService(recorder, output).Finish(ctx, wait)
+	if err != nil {
+		return "", fmt.Errorf("OBS hand-off failed: %w", err)
+	}
+	return path, nil
 }
 
 // productionFileSizer provides file sizes using os.Stat
@@ -501,22 +1535,73 @@ func (a *fileFinderAdapter) ListFiles(dir, ext string) ([]string, error) {
 	return a.finder.ListFiles(dir, ext)
 }
 
-// inferDateFromFilename extracts date from OBS-style filenames
-// Supports: "2025-12-28 10-06-16.mp4" or "2025-12-28.mp4"
-func inferDateFromFilename(filename string) (time.Time, error) {
-	// Pattern for OBS format: YYYY-MM-DD HH-MM-SS.mp4
-	obsPattern := regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+\d{2}-\d{2}-\d{2}\.mp4$`)
-	if matches := obsPattern.FindStringSubmatch(filename); len(matches) > 1 {
-		return time.Parse("2006-01-02", matches[1])
+// checkAlreadyProcessed returns an error if the service recorded in videoPath
+// already has both its video and audio in Drive, or history records a
+// successful run made from it; store may be nil. Files whose date cannot be
+// inferred are never treated as processed.
+func checkAlreadyProcessed(ctx context.Context, cfg *config.Config, folderID string, driveClient distribution.DriveClient, store history.Store, videoPath string) error {
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+	serviceDate, err := calendar.DateFromFilename(filepath.Base(videoPath))
+	if err != nil {
+		return nil
+	}
+
+	dateStr := serviceDate.Format("2006-01-02")
+	checker := appdist.NewProcessedCheckService(driveClient, servicesFolder(cfg, folderID), cfg.Google.ProcessedCheck)
+	status, err := checker.Check(ctx, dateStr)
+	if err != nil {
+		return fmt.Errorf("failed to check Drive for existing files: %w", err)
+	}
+	if status.IsComplete() {
+		return &appprocess.ValidationError{
+			Code:    appprocess.CodeAlreadyProcessed,
+			Message: fmt.Sprintf("Most recent file (%s) has already been processed. Use --input to specify a different file.", dateStr),
+		}
+	}
+
+	if store == nil {
+		return nil
 	}
+	entries, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+	if run, ok := history.ProcessedRun(entries, serviceDate, filepath.Base(videoPath)); ok {
+		return &appprocess.ValidationError{
+			Code: appprocess.CodeAlreadyProcessed,
+			Message: fmt.Sprintf("Most recent file (%s) was already processed on %s, according to history, though its files are not all in Drive. Use --input to process it again.",
+				dateStr, run.ProcessedAt.Local().Format("2006-01-02 15:04")),
+		}
+	}
+	return nil
+}
 
-	// Pattern for trimmed format: YYYY-MM-DD.mp4
-	trimmedPattern := regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\.mp4$`)
-	if matches := trimmedPattern.FindStringSubmatch(filename); len(matches) > 1 {
-		return time.Parse("2006-01-02", matches[1])
+// processHistory is the configured history store, or nil when history is off
+func processHistory(cfg *config.Config) history.Store {
+	if cfg.History.File == "" {
+		return nil
 	}
+	return infrahistory.NewJSONStore(cfg.History.File)
+}
 
-	return time.Time{}, fmt.Errorf("filename does not match expected format")
+// servicesFolder returns the Drive folder a run uploads to: override when
+// given, otherwise google.services_folder_id
+func servicesFolder(cfg *config.Config, override string) string {
+	if override != "" {
+		return override
+	}
+	return cfg.Google.ServicesFolderID
+}
+
+// checkFolderOverride rejects --folder-id when outputs go to S3, which has no folders
+func checkFolderOverride(cfg *config.Config, override string) error {
+	if override != "" && cfg.UsesS3() {
+		return fmt.Errorf("--folder-id only applies to Google Drive storage")
+	}
+	return nil
 }
 
 // Ensure distribution.DriveClient is implemented
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	appprocess "nac-service-media/application/process"
+	"nac-service-media/domain/notification"
+	"nac-service-media/infrastructure/config"
+)
+
+// ApplyQuickDefaults fills in what process --quick was not told from the
+// defaults config section. The minister is looked up in the schedule for
+// serviceDate. The typical service length is used for --end only when
+// detectEnd is false, since a detected end is more accurate.
+func ApplyQuickDefaults(cfg *config.Config, input ProcessInput, serviceDate time.Time, detectEnd bool) (ProcessInput, error) {
+	defaults := cfg.Defaults
+	if input.MinisterKey == "" {
+		input.MinisterKey = defaults.MinisterFor(serviceDate)
+	}
+	if len(input.RecipientKeys) == 0 {
+		input.RecipientKeys = append([]string(nil), defaults.Recipients...)
+	}
+	if len(input.RecipientKeys) == 0 {
+		return input, fmt.Errorf("--quick needs defaults.recipients in config, or --recipient")
+	}
+	if input.SenderKey == "" && cfg.User.Sender == "" {
+		input.SenderKey = defaults.Sender
+	}
+	if input.EndTime == "" && !detectEnd && defaults.ServiceLength != "" {
+		input.EndTime = "+" + defaults.ServiceLength
+	}
+	return input, nil
+}
+
+// quickServiceDate returns the service date --quick schedules the minister
+// by: --date, else the date in the recording's name. It is zero when neither
+// is known, which picks the fallback minister.
+func quickServiceDate(cfg *config.Config, input ProcessInput) time.Time {
+	if input.DateOverride != "" {
+		date, _ := time.Parse("2006-01-02", input.DateOverride)
+		return date
+	}
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return time.Time{}
+	}
+	date, _ := calendar.DateFromFilename(filepath.Base(input.InputPath))
+	return date
+}
+
+// resolveQuickRun applies the defaults to a --quick run, shows the result on
+// one screen and asks once whether to go ahead. Non-interactive runs are not
+// asked.
+func resolveQuickRun(cfg *config.Config, input ProcessInput, detectEnd bool, output io.Writer) (ProcessInput, error) {
+	date := quickServiceDate(cfg, input)
+	input, err := ApplyQuickDefaults(cfg, input, date, detectEnd)
+	if err != nil {
+		return input, err
+	}
+
+	PrintQuickSummary(output, cfg, input, date)
+	if input.NonInteractive {
+		return input, nil
+	}
+	ok, err := input.prompter().Confirm("Process this service?", true)
+	if err != nil {
+		return input, err
+	}
+	if !ok {
+		return input, fmt.Errorf("quick run: %w", appprocess.ErrStepDeclined)
+	}
+	return input, nil
+}
+
+// PrintQuickSummary shows what a --quick run is about to do
+func PrintQuickSummary(w io.Writer, cfg *config.Config, input ProcessInput, date time.Time) {
+	mgr := config.NewConfigManager(cfg, "")
+	lookup := config.NewRecipientLookup(cfg, "")
+
+	fmt.Fprintln(w, "Quick run:")
+	fmt.Fprintf(w, "  Recording:  %s\n", input.InputPath)
+	if !date.IsZero() {
+		fmt.Fprintf(w, "  Date:       %s\n", date.Format("Monday, 01/02/2006"))
+	}
+	fmt.Fprintf(w, "  Start:      %s\n", orDetected(input.StartTime))
+	end := orDetected(input.EndTime)
+	if cfg.Defaults.ServiceLength != "" && input.EndTime == "+"+cfg.Defaults.ServiceLength {
+		end += " (defaults.service_length after the start)"
+	}
+	fmt.Fprintf(w, "  End:        %s\n", end)
+
+	minister := "none"
+	if input.MinisterKey != "" {
+		minister = input.MinisterKey + " (not in config)"
+		if m, err := mgr.GetMinister(input.MinisterKey); err == nil {
+			minister = fmt.Sprintf("%s (%s)", m.Name, m.Key)
+		}
+	}
+	fmt.Fprintf(w, "  Minister:   %s\n", minister)
+
+	to := strings.Join(input.RecipientKeys, ", ")
+	if recipients, err := lookup.ResolveRecipients(input.RecipientKeys, input.ExcludeKeys); err == nil {
+		to = strings.Join(recipientNames(recipients), ", ")
+	}
+	fmt.Fprintf(w, "  To:         %s\n", to)
+
+	sender := input.SenderKey
+	if input.SenderKey != "" {
+		if s, err := lookup.LookupSender(input.SenderKey); err == nil {
+			sender = fmt.Sprintf("%s (%s)", s.Name, s.Key)
+		}
+	} else if s, from, err := mgr.DefaultSender(); err == nil {
+		sender = fmt.Sprintf("%s (%s), from %s", s.Name, s.Key, from)
+	}
+	if sender == "" {
+		sender = "not set"
+	}
+	fmt.Fprintf(w, "  Sender:     %s\n", sender)
+	fmt.Fprintln(w)
+}
+
+func orDetected(timestamp string) string {
+	if timestamp == "" {
+		return "auto-detected"
+	}
+	return timestamp
+}
+
+func recipientNames(recipients []notification.Recipient) []string {
+	names := make([]string, len(recipients))
+	for i, rc := range recipients {
+		names[i] = rc.Name
+	}
+	return names
+}
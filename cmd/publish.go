@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/sftp"
+	"nac-service-media/infrastructure/webdav"
+
+	"github.com/spf13/cobra"
+)
+
+var publishDate string
+
+var publishCmd = &cobra.Command{
+	Use:   "publish [sftp|webdav]",
+	Short: "Copy a service's video and audio to the mirror download server",
+	Long: `Copy the trimmed video and MP3 for a service to an SFTP or WebDAV server,
+for recipients who are blocked from Google Drive.
+
+Each file is published with a matching .sha256 checksum file so downloads
+can be verified with "sha256sum -c". The provider defaults to publish.provider
+in config.
+
+Set publish.auto: true to publish during "process" and include the mirror
+links in the email.
+
+Examples:
+  nac-service-media publish --date 2025-12-28
+  nac-service-media publish sftp --date 2025-12-28`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{distribution.ProviderSFTP, distribution.ProviderWebDAV},
+	RunE:      runPublish,
+}
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+	publishCmd.Flags().StringVar(&publishDate, "date", "", "Service date in YYYY-MM-DD format (required)")
+	publishCmd.MarkFlagRequired("date")
+}
+
+func runPublish(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	if _, err := time.Parse("2006-01-02", publishDate); err != nil {
+		return fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
+	}
+
+	provider := cfg.Publish.Provider
+	if len(args) == 1 {
+		provider = args[0]
+	}
+
+	// Publish whichever outputs exist (audio-only services have no video)
+	var paths []string
+	for _, p := range []string{
+		filepath.Join(cfg.Paths.TrimmedDirectory, publishDate+".mp4"),
+		filepath.Join(cfg.Paths.AudioDirectory, publishDate+".mp3"),
+	} {
+		if _, err := os.Stat(p); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no trimmed video or audio found for %s", publishDate)
+	}
+
+	publisher, err := newPublisher(cfg.Publish, provider)
+	if err != nil {
+		return err
+	}
+	defer closePublisher(publisher)
+
+	return RunPublishWithDependencies(cmd.Context(), publisher, paths, os.Stdout)
+}
+
+// RunPublishWithDependencies runs the publish command with injected dependencies (for testing)
+func RunPublishWithDependencies(
+	ctx context.Context,
+	publisher distribution.Publisher,
+	paths []string,
+	output io.Writer,
+	opts ...appdist.PublishOption,
+) error {
+	service := appdist.NewPublishService(publisher, output, opts...)
+	for _, p := range paths {
+		published, err := service.Publish(ctx, p)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(output, "  Published: %s\n", published.Name)
+		fmt.Fprintf(output, "          %s\n", published.URL)
+		fmt.Fprintf(output, "          sha256: %s\n", published.SHA256)
+	}
+	return nil
+}
+
+// newPublisher creates the alternate download provider named by provider
+func newPublisher(cfg config.PublishConfig, provider string) (distribution.Publisher, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("publish.url is not configured")
+	}
+
+	switch provider {
+	case distribution.ProviderSFTP:
+		return sftp.NewClient(sftp.Config{
+			URL:            cfg.URL,
+			Username:       cfg.Username,
+			Password:       cfg.Password,
+			PrivateKeyFile: cfg.PrivateKeyFile,
+			KnownHostsFile: cfg.KnownHostsFile,
+			PublicURL:      cfg.PublicURL,
+		})
+	case distribution.ProviderWebDAV:
+		return webdav.NewClient(cfg.URL,
+			webdav.WithBasicAuth(cfg.Username, cfg.Password),
+			webdav.WithPublicURL(cfg.PublicURL),
+		), nil
+	case "":
+		return nil, fmt.Errorf("no publish provider given; set publish.provider or pass sftp or webdav")
+	default:
+		return nil, fmt.Errorf("unknown publish provider %q (must be sftp or webdav)", provider)
+	}
+}
+
+// closePublisher releases any connection held by the publisher
+func closePublisher(p distribution.Publisher) {
+	if c, ok := p.(io.Closer); ok {
+		c.Close()
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	osuser "os/user"
 
 	"nac-service-media/infrastructure/config"
 
@@ -10,8 +11,10 @@ import (
 )
 
 var (
-	cfgFile string
-	cfg     *config.Config
+	cfgFile     string
+	userCfgFile string
+	cfg         *config.Config
+	errorFormat string
 )
 
 var rootCmd = &cobra.Command{
@@ -27,18 +30,33 @@ church service recordings:
 
 Example:
   nac-service-media process --source recording.mp4 --start 00:05:30 --end 01:15:00`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := parseErrorFormat(errorFormat); err != nil {
+			return err
+		}
+		// The JSON report replaces cobra's own error and usage output
+		if errorFormat == ErrorFormatJSON {
+			cmd.Root().SilenceErrors = true
+			cmd.Root().SilenceUsage = true
+		}
+		return nil
+	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	err := rootCmd.Execute()
+	writeHTTPStats()
+	if err != nil {
+		WriteError(os.Stderr, err, errorFormat)
+		os.Exit(ExitCode(err))
 	}
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./config/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&userCfgFile, "user-config", "", "this machine's operator identity file (default is user.yaml in the user config directory)")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", ErrorFormatText, "How a failure is reported on stderr: text, or json with a code and exit_code for scripts")
 }
 
 func initConfig() {
@@ -52,7 +70,32 @@ func initConfig() {
 		// Config file is optional for some commands (like help)
 		// Commands that need config will check and error appropriately
 		cfg = nil
+		return
+	}
+	cfg.User = loadUserConfig()
+}
+
+// userConfigPath returns --user-config, or user.yaml in the user config directory
+func userConfigPath() (string, error) {
+	if userCfgFile != "" {
+		return userCfgFile, nil
+	}
+	return config.DefaultUserConfigPath()
+}
+
+// loadUserConfig reads this machine's operator identity. A broken file is
+// reported and ignored, so commands that never sign an email still run.
+func loadUserConfig() config.UserConfig {
+	var user config.UserConfig
+	if path, err := userConfigPath(); err == nil {
+		if user, err = config.LoadUser(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring the operator identity file: %v\n", err)
+		}
+	}
+	if u, err := osuser.Current(); err == nil {
+		user.Login = config.LoginName(u.Username)
 	}
+	return user
 }
 
 // GetConfig returns the loaded configuration
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -9,20 +10,34 @@ import (
 	"time"
 
 	appnotif "nac-service-media/application/notification"
+	"nac-service-media/domain/history"
 	"nac-service-media/domain/notification"
 	"nac-service-media/infrastructure/config"
 	"nac-service-media/infrastructure/gmail"
+	infrahistory "nac-service-media/infrastructure/history"
 
 	"github.com/spf13/cobra"
 )
 
 var (
 	emailTo        []string
+	emailExclude   []string
+	emailCC        []string
 	emailDate      string
 	emailMinister  string
 	emailAudioURL  string
 	emailVideoURL  string
 	emailSenderKey string
+	emailService   string
+	emailLabel     string
+	emailTitle     string
+	emailScripture string
+	emailDryRun    bool
+	emailSandbox   bool
+	emailPreview   bool
+	emailStream    string
+	emailCorrect   bool
+	emailForDate   string
 )
 
 var sendEmailCmd = &cobra.Command{
@@ -31,31 +46,75 @@ var sendEmailCmd = &cobra.Command{
 	Long: `Send an email notification to recipients with links to the service recording.
 
 Recipients can be specified by name (first name, last name, or full name) or by
-their config key. Multiple recipients can be specified using multiple --to flags
-or comma-separated values.
+their config key, or by the name of one of email.groups to reach all its
+members. A raw address, "Jane Doe <jane@example.com>" or jane@example.com,
+reaches someone not in the config. Multiple recipients can be specified using
+multiple --to flags or comma-separated values. --cc accepts the same forms and
+adds to email.default_cc; anyone already in --to is not copied again.
 
 Examples:
   # Send to a single recipient
   nac-service-media send-email --to jonathan --date 2025-12-28 --minister "Pr. Henkel" \
     --audio-url "https://..." --video-url "https://..."
 
+  # Send to an email.groups group, leaving one member out
+  nac-service-media send-email --to choir --exclude jane --date 2025-12-28 ...
+
   # Send to multiple recipients
   nac-service-media send-email --to jonathan --to jane --date 2025-12-28 ...
-  nac-service-media send-email --to "jonathan,jane" --date 2025-12-28 ...`,
+  nac-service-media send-email --to "jonathan,jane" --date 2025-12-28 ...
+
+  # Copy a group and a one-off address
+  nac-service-media send-email --to jonathan --cc "council,Jane Doe <jane@example.com>" --date 2025-12-28 ...
+
+  # Fill {service_type} and {label} in a custom email.subject
+  nac-service-media send-email --to jonathan --date 2025-12-28 ... \
+    --service-type "Evening Service" --label "Confirmation"
+
+  # Name the sermon in the email body
+  nac-service-media send-email --to jonathan --date 2025-12-28 ... \
+    --title "Walking in Faith" --scripture "Hebrews 11:1"
+
+  # Link this service's livestream recording instead of email.livestream_url
+  nac-service-media send-email --to jonathan --date 2025-12-28 ... \
+    --livestream-url "https://www.youtube.com/watch?v=..."
+
+  # Preview recipients, including CCs added by email.cc_rules, without sending
+  nac-service-media send-email --to jonathan --date 2025-12-28 ... --dry-run
+
+  # Read the email as it will look, links inline, without sending
+  nac-service-media send-email --to jonathan --date 2025-12-28 ... --dry-run --preview-terminal
+
+  # Send only to the operator, with a [TEST] subject (also email.sandbox: true)
+  nac-service-media send-email --to jonathan --date 2025-12-28 ... --sandbox
+
+  # The email named the wrong minister: reply to it with a short correction,
+  # sent to the same recipients (minister by config key or name)
+  nac-service-media send-email --correct --for-date 2025-12-28 --minister jones`,
 	RunE: runSendEmail,
 }
 
 func init() {
 	rootCmd.AddCommand(sendEmailCmd)
 	sendEmailCmd.Flags().StringArrayVar(&emailTo, "to", nil, "Recipient(s) by name or config key (can be repeated or comma-separated)")
+	sendEmailCmd.Flags().StringArrayVar(&emailExclude, "exclude", nil, "Leave someone out of the --to recipients, e.g. one group member (can be repeated or comma-separated)")
+	sendEmailCmd.Flags().StringArrayVar(&emailCC, "cc", nil, "CC by name, config key, group or address, on top of email.default_cc (can be repeated or comma-separated)")
 	sendEmailCmd.Flags().StringVar(&emailDate, "date", "", "Service date in YYYY-MM-DD format")
 	sendEmailCmd.Flags().StringVar(&emailMinister, "minister", "", "Minister's name (e.g., 'Pr. Henkel')")
 	sendEmailCmd.Flags().StringVar(&emailAudioURL, "audio-url", "", "Google Drive URL for audio file")
 	sendEmailCmd.Flags().StringVar(&emailVideoURL, "video-url", "", "Google Drive URL for video file")
 	sendEmailCmd.Flags().StringVar(&emailSenderKey, "sender", "", "Sender config key (defaults to config default_sender)")
+	sendEmailCmd.Flags().StringVar(&emailService, "service-type", "", "Service type for the subject's {service_type} (defaults to email.service_type, then \"Service\")")
+	sendEmailCmd.Flags().StringVar(&emailLabel, "label", "", "Label for the subject's {label} (e.g., 'Confirmation')")
+	sendEmailCmd.Flags().StringVar(&emailTitle, "title", "", "Sermon title shown in the email and the subject's {title}")
+	sendEmailCmd.Flags().StringVar(&emailScripture, "scripture", "", "Scripture reading shown in the email and the subject's {scripture}")
+	sendEmailCmd.Flags().StringVar(&emailStream, "livestream-url", "", "Livestream recording to link in the email (defaults to email.livestream_url)")
+	sendEmailCmd.Flags().BoolVar(&emailDryRun, "dry-run", false, "Show the email and which CC rules fired without sending")
+	sendEmailCmd.Flags().BoolVar(&emailPreview, "preview-terminal", false, "Show the email body as text with links inline, for checking it without a browser")
+	sendEmailCmd.Flags().BoolVar(&emailSandbox, "sandbox", false, "Send only to the operator with a [TEST] subject (defaults to email.sandbox)")
+	sendEmailCmd.Flags().BoolVar(&emailCorrect, "correct", false, "Reply to the email already sent for --for-date, correcting the minister")
+	sendEmailCmd.Flags().StringVar(&emailForDate, "for-date", "", "Service date of the email to correct, in YYYY-MM-DD format (with --correct)")
 
-	sendEmailCmd.MarkFlagRequired("to")
-	sendEmailCmd.MarkFlagRequired("date")
 	sendEmailCmd.MarkFlagRequired("minister")
 }
 
@@ -64,6 +123,12 @@ func runSendEmail(cmd *cobra.Command, args []string) error {
 	if cfg == nil {
 		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
 	}
+	if emailCorrect {
+		return runSendCorrection(cmd, cfg)
+	}
+	if len(emailTo) == 0 || emailDate == "" {
+		return fmt.Errorf(`required flag(s) "to" and "date" not set`)
+	}
 
 	// Parse service date
 	serviceDate, err := time.Parse("2006-01-02", emailDate)
@@ -78,42 +143,93 @@ func runSendEmail(cmd *cobra.Command, args []string) error {
 
 	// Lookup recipients
 	lookup := config.NewRecipientLookup(cfg, cfgFile)
-	recipients, err := lookup.LookupRecipients(emailTo)
+	recipients, err := lookup.ResolveRecipients(emailTo, emailExclude)
 	if err != nil {
 		return fmt.Errorf("failed to lookup recipients: %w", err)
 	}
 
-	// Get default CC
+	// Get default CC, plus any from --cc
 	ccRecipients := lookup.GetDefaultCC()
-
-	// Lookup sender
-	mgr := config.NewConfigManager(cfg, cfgFile)
-	var senderName string
-	if emailSenderKey != "" {
-		sender, err := mgr.GetSender(emailSenderKey)
+	for _, query := range config.SplitQueries(emailCC) {
+		cc, ambiguous, err := lookup.LookupCC(query)
 		if err != nil {
-			return fmt.Errorf("sender '%s' not found in config\n\nTo fix this, run:\n  %s", emailSenderKey, config.SuggestAddSenderCommand(emailSenderKey))
+			return fmt.Errorf("failed to lookup cc %q: %w", query, err)
+		}
+		if ambiguous {
+			return fmt.Errorf("failed to lookup cc %q: %w: it matches %d recipients - use last name to disambiguate",
+				query, notification.ErrAmbiguousRecipient, len(cc))
 		}
-		senderName = sender.Name
-	} else {
-		sender, err := mgr.GetDefaultSender()
+		ccRecipients = append(ccRecipients, cc...)
+	}
+	ccRecipients = config.DedupeCC(ccRecipients, recipients)
+	ccRules, err := lookup.CCRules()
+	if err != nil {
+		return fmt.Errorf("invalid email.cc_rules: %w", err)
+	}
+	groups, err := lookup.Groups()
+	if err != nil {
+		return fmt.Errorf("invalid email.groups: %w", err)
+	}
+
+	senderName, err := emailSenderName(cfg, lookup)
+	if err != nil {
+		return err
+	}
+
+	subject, err := notification.ParseSubjectTemplate(cfg.Email.Subject)
+	if err != nil {
+		return fmt.Errorf("invalid email.subject: %w", err)
+	}
+	greeting, err := cfg.Email.Greeting.Rules()
+	if err != nil {
+		return fmt.Errorf("invalid email.greeting: %w", err)
+	}
+	serviceType := emailService
+	if serviceType == "" {
+		serviceType = cfg.Email.ServiceType
+	}
+
+	opts := []appnotif.Option{
+		appnotif.WithSubjectTemplate(subject),
+		appnotif.WithCCRules(ccRules),
+		appnotif.WithRecipientGroups(groups),
+		appnotif.WithFolderLink(cfg.EmailFolderURL()),
+		appnotif.WithLivestreamLink(cfg.Email.LivestreamURL),
+		appnotif.WithGreeting(greeting),
+		appnotif.WithSendTimeout(cfg.Email.SendTimeout()),
+	}
+	if emailStream != "" {
+		opts = append(opts, appnotif.WithLivestreamLink(emailStream))
+	}
+	if emailSandbox || cfg.Email.Sandbox {
+		operator, err := lookup.Operator()
 		if err != nil {
-			return fmt.Errorf("no default sender configured. Either specify --sender or set senders.default_sender in config")
+			return err
 		}
-		senderName = sender.Name
+		opts = append(opts, appnotif.WithSandbox(operator))
 	}
 
 	// Create Gmail client with OAuth
-	ctx := cmd.Context()
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
 	from := notification.Recipient{
 		Name:    cfg.Email.FromName,
 		Address: cfg.Email.FromAddress,
 	}
 
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+	if emailPreview {
+		opts = append(opts, appnotif.WithTerminalPreview(calendar.Location()))
+	}
 	gmailClient, err := gmail.NewClientWithOAuth(ctx, gmail.OAuthConfig{
 		CredentialsFile: cfg.Google.CredentialsFile,
 		TokenFile:       cfg.Google.GmailTokenFile,
-	}, from)
+	}, from, gmail.WithLocation(calendar.Location()), gmail.WithBCCSender(cfg.Email.CopySender(emailSandbox)))
 	if err != nil {
 		return fmt.Errorf("failed to create Gmail client: %w", err)
 	}
@@ -127,9 +243,15 @@ func runSendEmail(cmd *cobra.Command, args []string) error {
 		ccRecipients,
 		serviceDate,
 		emailMinister,
+		serviceType,
+		emailLabel,
+		emailTitle,
+		emailScripture,
 		emailAudioURL,
 		emailVideoURL,
+		emailDryRun,
 		os.Stdout,
+		opts...,
 	)
 }
 
@@ -143,28 +265,62 @@ func RunSendEmailWithDependencies(
 	ccRecipients []notification.Recipient,
 	serviceDate time.Time,
 	ministerName string,
+	serviceType string,
+	label string,
+	title string,
+	scripture string,
 	audioURL string,
 	videoURL string,
+	dryRun bool,
 	output io.Writer,
+	opts ...appnotif.Option,
 ) error {
-	service := appnotif.NewService(sender, churchName, senderName)
+	service := appnotif.NewService(sender, churchName, senderName, opts...)
+	req := appnotif.SendRequest{
+		To:           recipients,
+		CC:           ccRecipients,
+		ServiceDate:  serviceDate,
+		MinisterName: ministerName,
+		AudioURL:     audioURL,
+		VideoURL:     videoURL,
+		ServiceType:  serviceType,
+		Label:        label,
+		Title:        title,
+		Scripture:    scripture,
+	}
 
 	// Display what we're about to send
 	toNames := make([]string, len(recipients))
 	for i, r := range recipients {
-		toNames[i] = fmt.Sprintf("%s <%s>", r.Name, r.Address)
+		toNames[i] = r.String()
 	}
 	fmt.Fprintf(output, "Sending email to: %s\n", strings.Join(toNames, ", "))
 
-	if len(ccRecipients) > 0 {
-		ccNames := make([]string, len(ccRecipients))
-		for i, r := range ccRecipients {
-			ccNames[i] = fmt.Sprintf("%s <%s>", r.Name, r.Address)
+	cc, fired := service.ResolveCC(req)
+	if len(cc) > 0 {
+		ccNames := make([]string, len(cc))
+		for i, r := range cc {
+			ccNames[i] = r.String()
 		}
 		fmt.Fprintf(output, "CC: %s\n", strings.Join(ccNames, ", "))
 	}
+	writeFiredCCRules(output, fired, dryRun)
+	if operator, ok := service.Sandbox(); ok {
+		fmt.Fprintf(output, "Sandbox: rerouting to %s <%s> only\n", operator.Name, operator.Address)
+	}
 
-	fmt.Fprintf(output, "Subject: %s: Recording of Service on %s\n", churchName, serviceDate.Format("01/02/2006"))
+	emails := service.BuildGroupedRequests(req)
+	if len(emails) > 1 || emails[0].Group != notification.DefaultGroup {
+		fmt.Fprintf(output, "Groups:\n")
+		for _, e := range emails {
+			names := make([]string, len(e.To))
+			for i, r := range e.To {
+				names[i] = r.Name
+			}
+			fmt.Fprintf(output, "  %s: %s\n", e.Group, strings.Join(names, ", "))
+		}
+	}
+	fmt.Fprintf(output, "Subject: %s\n", service.Subject(req))
 	fmt.Fprintf(output, "Minister: %s\n", ministerName)
 	if audioURL != "" {
 		fmt.Fprintf(output, "Audio URL: %s\n", audioURL)
@@ -173,17 +329,27 @@ func RunSendEmailWithDependencies(
 		fmt.Fprintf(output, "Video URL: %s\n", videoURL)
 	}
 	fmt.Fprintln(output)
+	if err := writeTerminalPreviews(output, service, emails); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Fprintf(output, "Dry run: email not sent\n")
+		return nil
+	}
 
 	// Send the email
 	fmt.Fprintf(output, "Sending email...\n")
-	err := service.Send(appnotif.SendRequest{
-		To:           recipients,
-		CC:           ccRecipients,
-		ServiceDate:  serviceDate,
-		MinisterName: ministerName,
-		AudioURL:     audioURL,
-		VideoURL:     videoURL,
-	})
+	results, err := service.SendGroups(ctx, req)
+	if len(results) > 1 {
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Fprintf(output, "  %s: failed: %v\n", r.Group, r.Err)
+			} else {
+				fmt.Fprintf(output, "  %s: sent to %d recipients\n", r.Group, len(r.To))
+			}
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
@@ -191,3 +357,194 @@ func RunSendEmailWithDependencies(
 	fmt.Fprintf(output, "Email sent successfully!\n")
 	return nil
 }
+
+// emailSenderName returns the name that signs the email: --sender, else this
+// machine's or the config's default sender
+func emailSenderName(cfg *config.Config, lookup *config.RecipientLookup) (string, error) {
+	if emailSenderKey != "" {
+		sender, err := lookup.LookupSender(emailSenderKey)
+		if errors.Is(err, notification.ErrAmbiguousRecipient) {
+			return "", err
+		}
+		if err != nil {
+			return "", fmt.Errorf("sender '%s' not found in config\n\nTo fix this, run:\n  %s", emailSenderKey, config.SuggestAddSenderCommand(emailSenderKey))
+		}
+		return sender.Name, nil
+	}
+	sender, err := config.NewConfigManager(cfg, cfgFile).GetDefaultSender()
+	if errors.Is(err, config.ErrNoDefaultSender) {
+		return "", fmt.Errorf("no default sender configured. Either specify --sender, set this machine's sender with whoami --set-sender, or set senders.default_sender in config")
+	}
+	if err != nil {
+		return "", err
+	}
+	return sender.Name, nil
+}
+
+// runSendCorrection replies to the email already sent for --for-date with a
+// correction naming the right minister
+func runSendCorrection(cmd *cobra.Command, cfg *config.Config) error {
+	if emailForDate == "" {
+		return fmt.Errorf("--correct needs --for-date, the service date of the email to correct")
+	}
+	if len(emailTo) > 0 || len(emailCC) > 0 {
+		return fmt.Errorf("--correct goes to the original email's recipients; drop --to and --cc")
+	}
+	serviceDate, err := time.Parse("2006-01-02", emailForDate)
+	if err != nil {
+		return fmt.Errorf("invalid --for-date (use YYYY-MM-DD): %w", err)
+	}
+
+	// --minister is a config key here, falling back to the name as given
+	ministerName := emailMinister
+	if m, err := config.NewConfigManager(cfg, cfgFile).GetMinister(emailMinister); err == nil {
+		ministerName = m.Name
+	}
+
+	lookup := config.NewRecipientLookup(cfg, cfgFile)
+	senderName, err := emailSenderName(cfg, lookup)
+	if err != nil {
+		return err
+	}
+	greeting, err := cfg.Email.Greeting.Rules()
+	if err != nil {
+		return fmt.Errorf("invalid email.greeting: %w", err)
+	}
+	opts := []appnotif.Option{
+		appnotif.WithGreeting(greeting),
+		appnotif.WithSendTimeout(cfg.Email.SendTimeout()),
+	}
+	if emailSandbox || cfg.Email.Sandbox {
+		operator, err := lookup.Operator()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, appnotif.WithSandbox(operator))
+	}
+
+	ctx, err := googleContext(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+	from := notification.Recipient{Name: cfg.Email.FromName, Address: cfg.Email.FromAddress}
+	gmailClient, err := gmail.NewClientWithOAuth(ctx, gmail.OAuthConfig{
+		CredentialsFile: cfg.Google.CredentialsFile,
+		TokenFile:       cfg.Google.GmailTokenFile,
+	}, from, gmail.WithLocation(calendar.Location()), gmail.WithBCCSender(cfg.Email.CopySender(emailSandbox)))
+	if err != nil {
+		return fmt.Errorf("failed to create Gmail client: %w", err)
+	}
+
+	return RunSendCorrectionWithDependencies(
+		ctx,
+		gmailClient,
+		infrahistory.NewJSONStore(cfg.History.File),
+		lookup,
+		cfg.Email.FromName,
+		senderName,
+		serviceDate,
+		ministerName,
+		emailDryRun,
+		os.Stdout,
+		opts...,
+	)
+}
+
+// RunSendCorrectionWithDependencies sends a correction for the email recorded
+// in history for serviceDate, with injected dependencies (for testing)
+func RunSendCorrectionWithDependencies(
+	ctx context.Context,
+	sender notification.EmailSender,
+	store history.Store,
+	lookup *config.RecipientLookup,
+	churchName string,
+	senderName string,
+	serviceDate time.Time,
+	ministerName string,
+	dryRun bool,
+	output io.Writer,
+	opts ...appnotif.Option,
+) error {
+	service := appnotif.NewCorrectionService(sender, store, churchName, senderName, opts...)
+	entry, err := service.Find(serviceDate)
+	if err != nil {
+		return fmt.Errorf("cannot correct the email for %s: %w", serviceDate.Format("2006-01-02"), err)
+	}
+	to := lookup.NameAddresses(entry.Recipients)
+	email, err := service.Build(entry, to, ministerName)
+	if err != nil {
+		return err
+	}
+
+	previous := entry.Minister
+	if previous == "" {
+		previous = "no minister"
+	}
+	fmt.Fprintf(output, "Correcting the email for %s: %s -> %s\n", serviceDate.Format("2006-01-02"), previous, ministerName)
+	names := make([]string, len(email.To))
+	for i, r := range email.To {
+		names[i] = r.String()
+	}
+	fmt.Fprintf(output, "Sending correction to: %s\n", strings.Join(names, ", "))
+	fmt.Fprintf(output, "Subject: %s\n", email.Subject)
+	if len(entry.EmailMessageIDs) == 0 {
+		fmt.Fprintf(output, "Warning: the original email's Message-ID was not recorded, so the correction will not thread under it\n")
+	}
+
+	if dryRun {
+		fmt.Fprintf(output, "Dry run: correction not sent\n")
+		return nil
+	}
+	if _, err := service.Send(ctx, entry, to, ministerName); err != nil {
+		return fmt.Errorf("failed to send correction: %w", err)
+	}
+	fmt.Fprintf(output, "Correction sent!\n")
+	return nil
+}
+
+// writeTerminalPreviews shows each email's body when previews are on, headed
+// by its group when there are several
+func writeTerminalPreviews(output io.Writer, service *appnotif.Service, emails []appnotif.GroupedEmail) error {
+	rule := strings.Repeat("-", notification.TerminalWidth)
+	for _, e := range emails {
+		text, ok, err := service.Preview(e.Request)
+		if !ok {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(emails) > 1 {
+			fmt.Fprintf(output, "Email for %s:\n", e.Group)
+		}
+		fmt.Fprintf(output, "%s\n%s%s\n\n", rule, text, rule)
+	}
+	return nil
+}
+
+// writeFiredCCRules explains which CC rules added recipients. With explainNone,
+// it also says when no rule fired.
+func writeFiredCCRules(output io.Writer, fired []notification.FiredRule, explainNone bool) {
+	if len(fired) == 0 {
+		if explainNone {
+			fmt.Fprintf(output, "CC rules: none fired\n")
+		}
+		return
+	}
+	fmt.Fprintf(output, "CC rules:\n")
+	for _, f := range fired {
+		added := "no new recipients"
+		if len(f.Added) > 0 {
+			names := make([]string, len(f.Added))
+			for i, r := range f.Added {
+				names[i] = r.Name
+			}
+			added = "cc " + strings.Join(names, ", ")
+		}
+		fmt.Fprintf(output, "  %s: %s -> %s\n", f.Rule, strings.Join(f.Reasons, " and "), added)
+	}
+}
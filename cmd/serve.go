@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	appremote "nac-service-media/application/remote"
+	"nac-service-media/infrastructure/api"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAPI    bool
+	serveListen string
+)
+
+// serveShutdownTimeout bounds how long open requests get when the server stops
+const serveShutdownTimeout = 5 * time.Second
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a server for remote control (experimental)",
+	Long: `Run a server so another device, such as a phone at the sound desk, can
+start processing without a terminal. The API is experimental and may change.
+
+With --api, a JSON API is served on api.listen (default 127.0.0.1:8765, this
+machine only). Every request needs the header "Authorization: Bearer <token>",
+where the token is read from api.token_file (default api_token); a random one
+is written there the first time.
+
+  GET  /api/v1/runs         runs started since the server began
+  POST /api/v1/runs         start "process --non-interactive" with a JSON body of
+                            input, start, end, date, minister, recipients, title,
+                            scripture, skip_video, sandbox and allow_delete
+  GET  /api/v1/runs/{id}    a run's state (running, succeeded, failed) and output
+  GET  /api/v1/history      processed services; ?from= and ?to= take YYYY-MM-DD
+  POST /api/v1/resends      email a processed service again: {"date", "recipients"}
+
+Only one run goes at a time. Stopping the server stops a run in progress.
+
+Examples:
+  nac-service-media serve --api
+
+  curl -H "Authorization: Bearer $(cat api_token)" \
+    -d '{"start":"00:05:30","end":"01:45:00","minister":"smith","recipients":["jane"]}' \
+    http://127.0.0.1:8765/api/v1/runs`,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().BoolVar(&serveAPI, "api", false, "Serve the remote control API")
+	serveCmd.Flags().StringVar(&serveListen, "listen", "", "Address to serve on (defaults to api.listen)")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	if !serveAPI {
+		return fmt.Errorf("nothing to serve; pass --api")
+	}
+
+	token, created, err := api.LoadOrCreateToken(cfg.API.TokenFile)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Fprintf(os.Stdout, "Wrote a new API token to %s\n", cfg.API.TokenFile)
+	}
+
+	runner, err := selfRunner()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	service := appremote.NewService(ctx, runner, infrahistory.NewJSONStore(cfg.History.File))
+	defer service.Wait()
+
+	handler, err := api.NewHandler(service, token)
+	if err != nil {
+		return err
+	}
+	listen := serveListen
+	if listen == "" {
+		listen = cfg.API.Listen
+	}
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+	return RunServeWithDependencies(ctx, ln, handler, os.Stdout)
+}
+
+// RunServeWithDependencies serves handler on ln until ctx is done (for testing)
+func RunServeWithDependencies(ctx context.Context, ln net.Listener, handler http.Handler, output io.Writer) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(ln)
+	}()
+	fmt.Fprintf(output, "Remote control API (experimental) listening on http://%s\n", ln.Addr())
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("API server stopped: %w", err)
+	case <-ctx.Done():
+	}
+	fmt.Fprintln(output, "Stopping the API server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to stop the API server: %w", err)
+	}
+	return nil
+}
+
+// selfRunner runs commands with this binary and the same config file
+func selfRunner() (*api.SelfRunner, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find this program: %w", err)
+	}
+	configPath, err := filepath.Abs(cfgFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	return &api.SelfRunner{Executable: exe, BaseArgs: []string{"--config", configPath}}, nil
+}
@@ -4,48 +4,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
+	"nac-service-media/domain/notification"
 	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/ui"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/cobra"
 )
 
-// Prompter interface for interactive prompts (allows mocking in tests)
-type Prompter interface {
-	Input(message string, defaultValue string) (string, error)
-	Confirm(message string, defaultValue bool) (bool, error)
-}
-
-// SurveyPrompter implements Prompter using the survey library
-type SurveyPrompter struct{}
-
-func (p *SurveyPrompter) Input(message string, defaultValue string) (string, error) {
-	result := ""
-	prompt := &survey.Input{
-		Message: message,
-		Default: defaultValue,
-	}
-	if err := survey.AskOne(prompt, &result); err != nil {
-		return "", err
-	}
-	return result, nil
-}
-
-func (p *SurveyPrompter) Confirm(message string, defaultValue bool) (bool, error) {
-	result := defaultValue
-	prompt := &survey.Confirm{
-		Message: message,
-		Default: defaultValue,
-	}
-	if err := survey.AskOne(prompt, &result); err != nil {
-		return false, err
-	}
-	return result, nil
-}
-
 // DefaultPrompter is the prompter used in production
-var DefaultPrompter Prompter = &SurveyPrompter{}
+var DefaultPrompter ui.Prompter = ui.NewSurveyPrompter()
 
 var setupCmd = &cobra.Command{
 	Use:   "setup",
@@ -53,7 +24,9 @@ var setupCmd = &cobra.Command{
 	Long: `Prompts for configuration values and creates config.yaml.
 
 This command guides you through setting up your configuration file
-with all necessary paths, Google Drive settings, and email recipients.`,
+with all necessary paths, Google Drive settings, and email recipients, then
+optionally ministers, senders, the service type for email subjects, and
+automatic detection.`,
 	RunE: runSetup,
 }
 
@@ -66,7 +39,7 @@ func runSetup(cmd *cobra.Command, args []string) error {
 }
 
 // RunSetupWithPrompter runs the setup with a given prompter (for testing)
-func RunSetupWithPrompter(prompter Prompter, configPath string) error {
+func RunSetupWithPrompter(prompter ui.Prompter, configPath string) error {
 	// Check if config already exists
 	if _, err := os.Stat(configPath); err == nil {
 		overwrite, err := prompter.Confirm("config.yaml already exists. Overwrite?", false)
@@ -104,6 +77,20 @@ func RunSetupWithPrompter(prompter Prompter, configPath string) error {
 		return err
 	}
 
+	// Optional sections; each is skipped unless the user opts in
+	if err := promptMinisters(prompter, cfg); err != nil {
+		return err
+	}
+	if err := promptSenders(prompter, cfg); err != nil {
+		return err
+	}
+	if err := promptServiceType(prompter, cfg); err != nil {
+		return err
+	}
+	if err := promptDetection(prompter, cfg); err != nil {
+		return err
+	}
+
 	// Ensure config directory exists
 	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
@@ -119,7 +106,7 @@ func RunSetupWithPrompter(prompter Prompter, configPath string) error {
 	return nil
 }
 
-func promptPaths(prompter Prompter, cfg *config.Config) error {
+func promptPaths(prompter ui.Prompter, cfg *config.Config) error {
 	source, err := prompter.Input("Where does OBS save recordings?", "")
 	if err != nil {
 		return fmt.Errorf("prompt cancelled")
@@ -150,7 +137,7 @@ func promptPaths(prompter Prompter, cfg *config.Config) error {
 	return nil
 }
 
-func promptAudio(prompter Prompter, cfg *config.Config) error {
+func promptAudio(prompter ui.Prompter, cfg *config.Config) error {
 	bitrate, err := prompter.Input("Audio bitrate for mp3 extraction?", "192k")
 	if err != nil {
 		return fmt.Errorf("prompt cancelled")
@@ -158,11 +145,14 @@ func promptAudio(prompter Prompter, cfg *config.Config) error {
 	if bitrate == "" {
 		bitrate = "192k"
 	}
-	cfg.Audio.Bitrate = bitrate
+	cfg.Audio.Bitrate = config.Bitrate(bitrate)
+	if err := cfg.Audio.Bitrate.Validate(); err != nil {
+		return fmt.Errorf("invalid audio bitrate: %w", err)
+	}
 	return nil
 }
 
-func promptGoogle(prompter Prompter, cfg *config.Config) error {
+func promptGoogle(prompter ui.Prompter, cfg *config.Config) error {
 	credentials, err := prompter.Input("Path to Google credentials file?", "credentials.json")
 	if err != nil {
 		return fmt.Errorf("prompt cancelled")
@@ -184,7 +174,7 @@ func promptGoogle(prompter Prompter, cfg *config.Config) error {
 	return nil
 }
 
-func promptEmail(prompter Prompter, cfg *config.Config) error {
+func promptEmail(prompter ui.Prompter, cfg *config.Config) error {
 	// From details
 	fromName, err := prompter.Input("Display name for outgoing emails?", "")
 	if err != nil {
@@ -251,7 +241,7 @@ func promptEmail(prompter Prompter, cfg *config.Config) error {
 	return nil
 }
 
-func promptRecipientWithPrompter(prompter Prompter) (config.RecipientConfig, error) {
+func promptRecipientWithPrompter(prompter ui.Prompter) (config.RecipientConfig, error) {
 	name, err := prompter.Input("  Full name:", "")
 	if err != nil {
 		return config.RecipientConfig{}, fmt.Errorf("prompt cancelled")
@@ -273,3 +263,173 @@ func promptRecipientWithPrompter(prompter Prompter) (config.RecipientConfig, err
 		Address: address,
 	}, nil
 }
+
+func promptMinisters(prompter ui.Prompter, cfg *config.Config) error {
+	for {
+		add, err := prompter.Confirm("Add a minister?", false)
+		if err != nil {
+			return fmt.Errorf("prompt cancelled")
+		}
+		if !add {
+			return nil
+		}
+
+		key, name, err := promptKeyAndName(prompter, "--minister")
+		if err != nil {
+			return err
+		}
+		if cfg.Ministers == nil {
+			cfg.Ministers = make(map[string]config.MinisterConfig)
+		}
+		cfg.Ministers[key] = config.MinisterConfig{Name: name}
+	}
+}
+
+func promptSenders(prompter ui.Prompter, cfg *config.Config) error {
+	for {
+		add, err := prompter.Confirm("Add a sender (who signs the email)?", false)
+		if err != nil {
+			return fmt.Errorf("prompt cancelled")
+		}
+		if !add {
+			break
+		}
+
+		key, name, err := promptKeyAndName(prompter, "--sender")
+		if err != nil {
+			return err
+		}
+		if cfg.Senders.Senders == nil {
+			cfg.Senders.Senders = make(map[string]config.SenderConfig)
+		}
+		cfg.Senders.Senders[key] = config.SenderConfig{Name: name}
+	}
+
+	keys := make([]string, 0, len(cfg.Senders.Senders))
+	for key := range cfg.Senders.Senders {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	switch len(keys) {
+	case 0:
+		return nil
+	case 1:
+		cfg.Senders.DefaultSender = keys[0]
+		return nil
+	}
+
+	defaultSender, err := prompter.Select("Default sender?", keys, keys[0])
+	if err != nil {
+		return fmt.Errorf("prompt cancelled")
+	}
+	cfg.Senders.DefaultSender = defaultSender
+	return nil
+}
+
+// promptKeyAndName asks for the short key used with flag and the full name
+func promptKeyAndName(prompter ui.Prompter, flag string) (string, string, error) {
+	key, err := prompter.Input(fmt.Sprintf("  Key (used with %s):", flag), "")
+	if err != nil {
+		return "", "", fmt.Errorf("prompt cancelled")
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "" {
+		return "", "", fmt.Errorf("key is required")
+	}
+
+	name, err := prompter.Input("  Full name:", "")
+	if err != nil {
+		return "", "", fmt.Errorf("prompt cancelled")
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("name is required")
+	}
+	return key, name, nil
+}
+
+// serviceTypePresets are offered for email.service_type; the first is the default
+var serviceTypePresets = []string{
+	notification.DefaultServiceType,
+	"Divine Service",
+	"Sunday Service",
+	"Midweek Service",
+	"Evening Service",
+}
+
+const otherServiceType = "Other..."
+
+func promptServiceType(prompter ui.Prompter, cfg *config.Config) error {
+	options := append(append([]string(nil), serviceTypePresets...), otherServiceType)
+	serviceType, err := prompter.Select("Service type for email subjects?", options, notification.DefaultServiceType)
+	if err != nil {
+		return fmt.Errorf("prompt cancelled")
+	}
+	if serviceType == otherServiceType {
+		if serviceType, err = prompter.Input("  Service type:", ""); err != nil {
+			return fmt.Errorf("prompt cancelled")
+		}
+	}
+	// The default needs no config entry
+	if serviceType != notification.DefaultServiceType {
+		cfg.Email.ServiceType = strings.TrimSpace(serviceType)
+	}
+	return nil
+}
+
+func promptDetection(prompter ui.Prompter, cfg *config.Config) error {
+	enable, err := prompter.Confirm("Enable automatic start/end detection?", false)
+	if err != nil {
+		return fmt.Errorf("prompt cancelled")
+	}
+	if !enable {
+		return nil
+	}
+
+	templates, err := prompter.Input("  Cross template directory?", "config/detection_templates")
+	if err != nil {
+		return fmt.Errorf("prompt cancelled")
+	}
+	audioTemplates, err := prompter.Input("  Amen template directory?", "config/audio_templates")
+	if err != nil {
+		return fmt.Errorf("prompt cancelled")
+	}
+	for _, dir := range []string{templates, audioTemplates} {
+		if info, statErr := os.Stat(dir); statErr != nil || !info.IsDir() {
+			fmt.Printf("  Note: %s does not exist yet; add templates before using detection\n", dir)
+		}
+	}
+
+	start, err := promptMinutes(prompter, "  Search for the start from minute?", 0)
+	if err != nil {
+		return err
+	}
+	end, err := promptMinutes(prompter, "  ...up to minute?", 20)
+	if err != nil {
+		return err
+	}
+	if end <= start {
+		return fmt.Errorf("detection search must end after it starts (%d to %d minutes)", start, end)
+	}
+
+	cfg.Detection.Enabled = true
+	cfg.Detection.TemplatesDir = templates
+	cfg.Detection.AudioTemplatesDir = audioTemplates
+	cfg.Detection.SearchRange.StartMinutes = start
+	cfg.Detection.SearchRange.EndMinutes = end
+	return nil
+}
+
+func promptMinutes(prompter ui.Prompter, message string, defaultValue int) (int, error) {
+	answer, err := prompter.Input(message, strconv.Itoa(defaultValue))
+	if err != nil {
+		return 0, fmt.Errorf("prompt cancelled")
+	}
+	if answer == "" {
+		return defaultValue, nil
+	}
+	minutes, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || minutes < 0 {
+		return 0, fmt.Errorf("invalid minutes %q", answer)
+	}
+	return minutes, nil
+}
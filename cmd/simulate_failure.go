@@ -0,0 +1,40 @@
+//go:build devtools
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// devtoolsBuild reports whether failure simulation is compiled in
+const devtoolsBuild = true
+
+// failAtStepEnv names the step process should fail on purpose, like the
+// hidden --simulate-failure flag. Neither exists without -tags=devtools.
+const failAtStepEnv = "NAC_FAIL_AT_STEP"
+
+var processSimulateFailure string
+
+func init() {
+	processCmd.Flags().StringVar(&processSimulateFailure, "simulate-failure", "", "Fail this step on purpose to check recovery output (also "+failAtStepEnv+")")
+	processCmd.Flags().MarkHidden("simulate-failure")
+}
+
+// simulatedFailureStep returns the step to fail from --simulate-failure or
+// NAC_FAIL_AT_STEP, or 0 to fail none
+func simulatedFailureStep() (int, error) {
+	value, source := processSimulateFailure, "--simulate-failure"
+	if value == "" {
+		value, source = os.Getenv(failAtStepEnv), failAtStepEnv
+	}
+	if value == "" {
+		return 0, nil
+	}
+	step, err := strconv.Atoi(value)
+	if err != nil || step < 1 {
+		return 0, fmt.Errorf("invalid %s %q (must be a step number, 1 or more)", source, value)
+	}
+	return step, nil
+}
@@ -0,0 +1,11 @@
+//go:build !devtools
+
+package cmd
+
+// devtoolsBuild reports whether failure simulation is compiled in
+const devtoolsBuild = false
+
+// simulatedFailureStep never fails a step outside development builds
+func simulatedFailureStep() (int, error) {
+	return 0, nil
+}
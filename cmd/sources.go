@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/application/sources"
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/filesystem"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	sourcesSince       string
+	sourcesUntil       string
+	sourcesUnprocessed bool
+	sourcesCheckDrive  bool
+)
+
+var sourcesCmd = &cobra.Command{
+	Use:   "sources",
+	Short: "Work with the recordings in the source directories",
+}
+
+var sourcesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List source recordings and whether each has been processed",
+	Long: `List the MP4 recordings in the source directories with the service date
+inferred from each file name, its length, and where it was found processed:
+
+  local    the trimmed video or audio is in the output directories
+  history  a successful run is recorded in the history file
+  drive    the video and audio are in the services folder (--check-drive)
+
+Use it to spot missed Sundays.
+
+Examples:
+  # Everything since October 1st
+  nac-service-media sources list --since 2025-10-01
+
+  # Only recordings that still need processing, checking Drive as well
+  nac-service-media sources list --since 2025-10-01 --unprocessed --check-drive`,
+	RunE: runSourcesList,
+}
+
+func init() {
+	rootCmd.AddCommand(sourcesCmd)
+	sourcesCmd.AddCommand(sourcesListCmd)
+
+	sourcesListCmd.Flags().StringVar(&sourcesSince, "since", "", "First service date to include (YYYY-MM-DD)")
+	sourcesListCmd.Flags().StringVar(&sourcesUntil, "until", "", "Last service date to include (YYYY-MM-DD)")
+	sourcesListCmd.Flags().BoolVar(&sourcesUnprocessed, "unprocessed", false, "Only list recordings that have not been processed")
+	sourcesListCmd.Flags().BoolVar(&sourcesCheckDrive, "check-drive", false, "Also look for each service in Drive (one query per date)")
+}
+
+func runSourcesList(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+
+	opts := []sources.ListOption{
+		sources.WithOutputs(filesystem.NewChecker(), cfg.Paths.TrimmedDirectory, cfg.Paths.AudioDirectory),
+		sources.WithDurationProber(newValidator(cfg)),
+		sources.WithHistory(infrahistory.NewJSONStore(cfg.History.File)),
+	}
+	if sourcesCheckDrive {
+		ctx := context.Background()
+		client, err := newStorageClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, sources.WithDriveCheck(
+			appdist.NewProcessedCheckService(client, cfg.Google.ServicesFolderID, cfg.Google.ProcessedCheck)))
+	}
+
+	svc := sources.NewListService(&ProductionFileFinder{}, calendar, opts...)
+	return RunSourcesListWithDependencies(context.Background(), svc, cfg.Paths.Sources(), sourcesSince, sourcesUntil, sourcesUnprocessed, os.Stdout)
+}
+
+// RunSourcesListWithDependencies runs the sources list command with injected dependencies (for testing)
+func RunSourcesListWithDependencies(
+	ctx context.Context,
+	svc *sources.ListService,
+	dirs []string,
+	since string,
+	until string,
+	unprocessedOnly bool,
+	output io.Writer,
+) error {
+	filter, err := sourcesFilter(since, until)
+	if err != nil {
+		return err
+	}
+
+	result, err := svc.List(ctx, dirs, filter)
+	if err != nil {
+		return err
+	}
+	for _, err := range result.Unreadable {
+		fmt.Fprintf(output, "Warning: skipping source directory: %v\n", err)
+	}
+
+	var unprocessed int
+	var rows []sources.Source
+	for _, src := range result.Sources {
+		if !src.Processed() {
+			unprocessed++
+		} else if unprocessedOnly {
+			continue
+		}
+		rows = append(rows, src)
+	}
+
+	if len(result.Sources) == 0 {
+		fmt.Fprintln(output, "No recordings found")
+		return nil
+	}
+
+	if len(rows) > 0 {
+		tw := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "DATE\tFILE\tDURATION\tPROCESSED")
+		for _, src := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", sourceDate(src), filepath.Base(src.Path), sourceDuration(src), sourceProcessed(src))
+		}
+		tw.Flush()
+		fmt.Fprintln(output)
+	}
+	fmt.Fprintf(output, "%d of %d recordings have not been processed\n", unprocessed, len(result.Sources))
+	return nil
+}
+
+// sourcesFilter builds a date filter from --since and --until
+func sourcesFilter(since, until string) (history.Filter, error) {
+	var filter history.Filter
+	var err error
+	if since != "" {
+		if filter.From, err = time.Parse("2006-01-02", since); err != nil {
+			return filter, fmt.Errorf("invalid --since date (use YYYY-MM-DD): %w", err)
+		}
+	}
+	if until != "" {
+		if filter.To, err = time.Parse("2006-01-02", until); err != nil {
+			return filter, fmt.Errorf("invalid --until date (use YYYY-MM-DD): %w", err)
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, fmt.Errorf("--until date %s is before --since date %s", until, since)
+	}
+	return filter, nil
+}
+
+func sourceDate(src sources.Source) string {
+	if src.Date.IsZero() {
+		return "?"
+	}
+	return src.Date.Format("2006-01-02")
+}
+
+func sourceDuration(src sources.Source) string {
+	if src.Duration == 0 {
+		return "?"
+	}
+	return video.TimestampFromSeconds(int(src.Duration.Seconds())).String()
+}
+
+func sourceProcessed(src sources.Source) string {
+	if !src.Processed() {
+		return "no"
+	}
+	return strings.Join(src.ProcessedIn, ", ")
+}
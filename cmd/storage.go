@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/drive"
+	"nac-service-media/infrastructure/network"
+	"nac-service-media/infrastructure/s3"
+	"nac-service-media/infrastructure/scanner"
+)
+
+// newStorageClient connects to where outputs are uploaded: Google Drive, or
+// the S3-compatible bucket when storage.provider is s3. Drive options are
+// ignored for S3.
+func newStorageClient(ctx context.Context, cfg *config.Config, opts ...drive.ClientOption) (distribution.DriveClient, error) {
+	if cfg.UsesS3() {
+		return newS3Client(cfg)
+	}
+	opts = append([]drive.ClientOption{
+		drive.WithScopeMode(cfg.Google.ScopeMode),
+		drive.WithAuditLog(newAuditLog(cfg)),
+		drive.WithChunkRetries(cfg.Google.UploadChunkRetries),
+		drive.WithUploadLog(os.Stdout),
+		drive.WithUploadSessions(filepath.Join(cfg.Paths.StateDirectory, "uploads")),
+	}, opts...)
+	client, err := drive.NewClientWithOAuth(ctx, cfg.Google.CredentialsFile, cfg.Google.DriveTokenFile, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Drive client: %w", err)
+	}
+	return client, nil
+}
+
+// newS3Client creates the object storage client for storage.s3, going
+// through the configured proxy and CA bundle like the Google clients
+func newS3Client(cfg *config.Config) (*s3.Client, error) {
+	s3cfg := cfg.Storage.S3
+	quota, err := s3cfg.QuotaBytes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage.s3.quota: %w", err)
+	}
+	httpClient, err := network.SharedHTTPClient(networkSettings(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid network config: %w", err)
+	}
+	client, err := s3.NewClient(s3cfg.Endpoint, s3cfg.Bucket,
+		s3.WithHTTPClient(httpClient),
+		s3.WithCredentials(s3cfg.AccessKeyID, s3cfg.SecretAccessKey),
+		s3.WithRegion(s3cfg.Region),
+		s3.WithPrefix(s3cfg.Prefix),
+		s3.WithPublicURL(s3cfg.PublicURL),
+		s3.WithLinkExpiry(s3cfg.LinkExpiry()),
+		s3.WithQuota(quota),
+		s3.WithAuditLog(newAuditLog(cfg)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	return client, nil
+}
+
+// newShareScanner returns the scanner run on local files before they are
+// shared publicly, or nil when scan.command is not set
+func newShareScanner(cfg *config.Config) (distribution.Scanner, error) {
+	if len(cfg.Scan.Command) == 0 {
+		return nil, nil
+	}
+	s, err := scanner.NewCommand(cfg.Scan.Command,
+		scanner.WithTimeout(cfg.Scan.Timeout()),
+		scanner.WithExitCode(cfg.Scan.ExitCode),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan config: %w", err)
+	}
+	return s, nil
+}
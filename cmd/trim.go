@@ -2,15 +2,19 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"time"
 
+	appdist "nac-service-media/application/distribution"
 	appvideo "nac-service-media/application/video"
+	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
 	"nac-service-media/domain/video"
-	"nac-service-media/infrastructure/ffmpeg"
+	"nac-service-media/infrastructure/config"
 	"nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/ui"
 
 	"github.com/spf13/cobra"
 )
@@ -20,6 +24,11 @@ var (
 	trimStartTime  string
 	trimEndTime    string
 	trimWithAudio  bool
+	trimOnExisting string
+	trimAudioTrack int
+	trimDate       string
+	trimAndUpload  bool
+	trimFolderID   string
 )
 
 var trimCmd = &cobra.Command{
@@ -27,25 +36,51 @@ var trimCmd = &cobra.Command{
 	Short: "Trim a video to specified timestamps",
 	Long: `Trim a video file to the specified start and end timestamps.
 
-The source filename must be in OBS format: YYYY-MM-DD HH-MM-SS.mp4
-The output file will be named YYYY-MM-DD.mp4 in the configured trimmed directory.
+The service date is inferred from the filename (OBS format: YYYY-MM-DD HH-MM-SS.mp4),
+or can be specified with --date. The output file is named YYYY-MM-DD.mp4 in the
+configured trimmed directory, as process names it.
 
-If --source is just a filename, it will be resolved from the configured source_directory.
+If --source is just a filename, it will be resolved from the configured source
+directories, in priority order.
 
-Use --with-audio to also extract audio as MP3 after trimming.
+Timestamps may be relative: --end -00:05:00 ends five minutes before the end
+of the file (its length is read with ffprobe), and +HH:MM:SS counts from the
+start of the file for --start and from the start time for --end.
+
+Use --and-extract (or --with-audio) to also extract audio as MP3 after
+trimming, and --and-upload to upload the results to Google Drive, so a failed
+process run can be finished with one command.
+
+Use --audio-track n to keep only the nth audio stream (1-based) when the source
+has several, e.g. a board mix and room mics. The default is audio.track in
+config, or the first stream.
+
+Use --on-existing to choose what happens when the output already exists:
+  prompt     ask before replacing it
+  overwrite  replace it (default)
+  skip       keep it if it is a valid media file, otherwise regenerate it
+  version    write to the next free name, e.g. 2025-12-28-v2.mp4
 
 Example:
   nac-service-media trim --source "2025-12-28 10-06-16.mp4" --start "00:05:30" --end "01:45:00"
-  nac-service-media trim --source "2025-12-28 10-06-16.mp4" --start "00:05:30" --end "01:45:00" --with-audio`,
+  nac-service-media trim --source "2025-12-28 10-06-16.mp4" --start "00:05:30" --end "01:45:00" --with-audio
+  nac-service-media trim --source "2025-12-28 10-06-16.mp4" --start "00:05:30" --end "-00:05:00"
+  nac-service-media trim --source capture.mp4 --date 2025-12-28 --start "00:05:30" --end "01:45:00" --and-extract --and-upload`,
 	RunE: runTrim,
 }
 
 func init() {
 	rootCmd.AddCommand(trimCmd)
 	trimCmd.Flags().StringVar(&trimSourcePath, "source", "", "Path to source video file (required)")
-	trimCmd.Flags().StringVar(&trimStartTime, "start", "", "Start timestamp in HH:MM:SS format (required)")
-	trimCmd.Flags().StringVar(&trimEndTime, "end", "", "End timestamp in HH:MM:SS format (required)")
+	trimCmd.Flags().StringVar(&trimStartTime, "start", "", "Start timestamp in HH:MM:SS format, or +HH:MM:SS / -HH:MM:SS (required)")
+	trimCmd.Flags().StringVar(&trimEndTime, "end", "", "End timestamp in HH:MM:SS format, -HH:MM:SS before the file end, or +HH:MM:SS after start (required)")
 	trimCmd.Flags().BoolVar(&trimWithAudio, "with-audio", false, "Also extract audio as MP3 after trimming")
+	trimCmd.Flags().BoolVar(&trimWithAudio, "and-extract", false, "Also extract audio as MP3 after trimming (same as --with-audio)")
+	trimCmd.Flags().BoolVar(&trimAndUpload, "and-upload", false, "Upload the trimmed video, and audio with --and-extract, to Google Drive")
+	trimCmd.Flags().StringVar(&trimFolderID, "folder-id", "", "Upload to this Drive folder instead of google.services_folder_id")
+	trimCmd.Flags().StringVar(&trimDate, "date", "", "Override service date (YYYY-MM-DD)")
+	trimCmd.Flags().IntVar(&trimAudioTrack, "audio-track", 0, "Audio stream to keep, starting at 1 (defaults to audio.track in config)")
+	trimCmd.Flags().StringVar(&trimOnExisting, "on-existing", string(video.DefaultOverwritePolicy), "What to do if the output exists: prompt, overwrite, skip, or version")
 	trimCmd.MarkFlagRequired("source")
 	trimCmd.MarkFlagRequired("start")
 	trimCmd.MarkFlagRequired("end")
@@ -58,14 +93,23 @@ func runTrim(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
 	}
 
-	// Resolve source path - if not absolute, use source_directory from config
-	sourcePath := trimSourcePath
-	if !filepath.IsAbs(sourcePath) {
-		sourcePath = filepath.Join(cfg.Paths.SourceDirectory, sourcePath)
+	if trimFolderID != "" && !trimAndUpload {
+		return fmt.Errorf("--folder-id requires --and-upload")
+	}
+	if err := checkFolderOverride(cfg, trimFolderID); err != nil {
+		return err
+	}
+
+	// Resolve source path - if not absolute, look in the configured source directories
+	sourcePath := domainfs.ResolveSource(filesystem.NewChecker().Exists, cfg.Paths.Sources(), trimSourcePath)
+
+	overwrite, err := overwriteOptions(cfg, trimOnExisting, DefaultPrompter)
+	if err != nil {
+		return err
 	}
 
 	// Create dependencies using production implementations
-	trimmer := ffmpeg.NewTrimmer()
+	trimmer := newTrimmer(cfg)
 	fileChecker := filesystem.NewChecker()
 
 	// Audio extraction dependencies (only used if --with-audio)
@@ -73,36 +117,103 @@ func runTrim(cmd *cobra.Command, args []string) error {
 	var audioOutputDir string
 	var audioBitrate string
 	if trimWithAudio {
-		extractor = ffmpeg.NewExtractor()
+		extractor = newExtractor(cfg)
 		audioOutputDir = cfg.Paths.AudioDirectory
-		audioBitrate = cfg.Audio.Bitrate
+		audioBitrate = cfg.Audio.Bitrate.String()
 		if audioBitrate == "" {
 			audioBitrate = video.DefaultAudioBitrate
 		}
 	}
 
-	return RunTrimWithDependencies(
-		cmd.Context(),
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+	watermark, err := cfg.Video.Watermark.Style()
+	if err != nil {
+		return fmt.Errorf("invalid video.watermark: %w", err)
+	}
+
+	opts := []appvideo.Option{
+		appvideo.WithOverwrite(overwrite),
+		appvideo.WithAudioTrack(audioTrack(trimAudioTrack, cfg.Audio.Track)),
+		appvideo.WithDurationProber(newValidator(cfg)),
+		appvideo.WithCalendar(calendar),
+		appvideo.WithWatermark(watermark),
+	}
+	if trimDate != "" {
+		date, err := time.Parse("2006-01-02", trimDate)
+		if err != nil {
+			return fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
+		}
+		opts = append(opts, appvideo.WithServiceDate(date))
+	}
+
+	chain := TrimChain{
+		Extractor:      extractor,
+		AudioOutputDir: audioOutputDir,
+		AudioBitrate:   audioBitrate,
+	}
+	ctx := cmd.Context()
+	if trimAndUpload {
+		// Connect before trimming, so a missing login fails fast
+		if ctx, err = googleContext(ctx, cfg); err != nil {
+			return err
+		}
+		if chain.Uploader, err = newStorageClient(ctx, cfg); err != nil {
+			return err
+		}
+		scanner, err := newShareScanner(cfg)
+		if err != nil {
+			return err
+		}
+		chain.ShareOptions = []appdist.ShareOption{appdist.WithScanner(scanner)}
+		if !cfg.UsesS3() {
+			chain.FolderID = servicesFolder(cfg, trimFolderID)
+		}
+	}
+
+	return RunTrimChainWithDependencies(
+		ctx,
 		trimmer,
 		fileChecker,
 		cfg.Paths.TrimmedDirectory,
 		sourcePath,
 		trimStartTime,
 		trimEndTime,
-		extractor,
-		audioOutputDir,
-		audioBitrate,
+		chain,
 		os.Stdout,
+		opts...,
 	)
 }
 
+// TrimChain is what trim runs after trimming: audio extraction and upload
+type TrimChain struct {
+	Extractor      video.AudioExtractor // Extracts audio from the trimmed video when set
+	AudioOutputDir string
+	AudioBitrate   string
+
+	Uploader     distribution.DriveClient // Uploads the outputs when set
+	FolderID     string
+	ShareOptions []appdist.ShareOption
+}
+
+// audioTrack returns the --audio-track flag value, falling back to config
+func audioTrack(flag, configured int) int {
+	if flag != video.DefaultAudioTrack {
+		return flag
+	}
+	return configured
+}
+
 // OutputWriter allows capturing output in tests
 type OutputWriter interface {
 	Write(p []byte) (n int, err error)
 }
 
 // RunTrimWithDependencies runs the trim command with injected dependencies (for testing)
-// If extractor is non-nil, audio will also be extracted after trimming
+// If extractor is non-nil, audio will also be extracted after trimming.
+// opts are applied to both the trim and extract services.
 func RunTrimWithDependencies(
 	ctx context.Context,
 	trimmer video.Trimmer,
@@ -115,18 +226,33 @@ func RunTrimWithDependencies(
 	audioOutputDir string,
 	audioBitrate string,
 	output OutputWriter,
+	opts ...appvideo.Option,
 ) error {
-	// Verify ffmpeg is available if trimmer supports it
-	if verifiable, ok := trimmer.(interface{ VerifyInstalled(context.Context) error }); ok {
-		verifyCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
-		if err := verifiable.VerifyInstalled(verifyCtx); err != nil {
-			return fmt.Errorf("ffmpeg verification failed: %w", err)
-		}
+	chain := TrimChain{Extractor: extractor, AudioOutputDir: audioOutputDir, AudioBitrate: audioBitrate}
+	return RunTrimChainWithDependencies(ctx, trimmer, fileChecker, outputDir, sourcePath, startTime, endTime, chain, output, opts...)
+}
+
+// RunTrimChainWithDependencies trims, then runs the extraction and upload set
+// in chain (for testing). opts are applied to both the trim and extract services.
+func RunTrimChainWithDependencies(
+	ctx context.Context,
+	trimmer video.Trimmer,
+	fileChecker video.FileChecker,
+	outputDir string,
+	sourcePath string,
+	startTime string,
+	endTime string,
+	chain TrimChain,
+	output OutputWriter,
+	opts ...appvideo.Option,
+) error {
+	// Verify ffmpeg is available and new enough if trimmer supports it
+	if _, err := verifyFFmpeg(ctx, trimmer, output); err != nil {
+		return err
 	}
 
 	// Create service with injected dependencies
-	service := appvideo.NewTrimService(trimmer, fileChecker, outputDir)
+	service := appvideo.NewTrimService(trimmer, fileChecker, outputDir, opts...)
 
 	// Perform trim
 	input := appvideo.TrimInput{
@@ -142,19 +268,28 @@ func RunTrimWithDependencies(
 		return err
 	}
 
-	fmt.Fprintf(output, "Successfully created: %s\n", result.OutputPath)
+	if result.Start.String() != startTime || result.End.String() != endTime {
+		fmt.Fprintf(output, "Resolved range: %s to %s\n", result.Start, result.End)
+	}
+	if result.Clamped {
+		fmt.Fprintf(output, "The end was past the end of the recording, so the video runs to %s\n", result.End)
+	}
+	printOutputResult(output, result.OutputPath, result.Reused)
 
 	// Extract audio if extractor is provided
-	if extractor != nil {
+	var audioPath string
+	if chain.Extractor != nil {
 		serviceDate, _ := time.Parse("2006-01-02", result.ServiceDate)
 
-		fmt.Fprintf(output, "Extracting audio with bitrate %s...\n", audioBitrate)
+		fmt.Fprintf(output, "Extracting audio with bitrate %s...\n", chain.AudioBitrate)
 
-		extractService := appvideo.NewExtractService(extractor, fileChecker, audioOutputDir, audioBitrate)
+		// The trimmed video only has the selected track, so read its default stream
+		extractOpts := append(append([]appvideo.Option{}, opts...), appvideo.WithAudioTrack(video.DefaultAudioTrack))
+		extractService := appvideo.NewExtractService(chain.Extractor, fileChecker, chain.AudioOutputDir, chain.AudioBitrate, extractOpts...)
 		extractInput := appvideo.ExtractInput{
 			SourcePath:  result.OutputPath,
 			ServiceDate: serviceDate,
-			Bitrate:     audioBitrate,
+			Bitrate:     chain.AudioBitrate,
 		}
 
 		extractResult, err := extractService.Extract(ctx, extractInput)
@@ -162,8 +297,59 @@ func RunTrimWithDependencies(
 			return fmt.Errorf("audio extraction failed: %w", err)
 		}
 
-		fmt.Fprintf(output, "Successfully created: %s\n", extractResult.OutputPath)
+		printOutputResult(output, extractResult.OutputPath, extractResult.Reused)
+		audioPath = extractResult.OutputPath
 	}
 
+	if chain.Uploader != nil {
+		fmt.Fprintln(output)
+		return RunUploadWithDependencies(ctx, chain.Uploader, chain.FolderID, result.OutputPath, audioPath, false, false, output, chain.ShareOptions...)
+	}
 	return nil
 }
+
+// overwriteOptions builds the --on-existing policy for production use, prompting
+// through prompter and validating reused files with ffprobe
+func overwriteOptions(cfg *config.Config, policy string, prompter ui.Prompter) (appvideo.OverwriteOptions, error) {
+	p, err := video.ParseOverwritePolicy(policy)
+	if err != nil {
+		return appvideo.OverwriteOptions{}, err
+	}
+	return appvideo.OverwriteOptions{
+		Policy:    p,
+		Validator: newValidator(cfg),
+		Confirm:   ConfirmOverwrite(prompter),
+	}, nil
+}
+
+const (
+	choiceReuse     = "Reuse existing file"
+	choiceOverwrite = "Overwrite"
+)
+
+// ConfirmOverwrite asks the user whether to replace or reuse an existing output file
+func ConfirmOverwrite(prompter ui.Prompter) appvideo.ConfirmFunc {
+	return func(path string) (bool, error) {
+		choice, err := prompter.Select(fmt.Sprintf("%s already exists.", path),
+			[]string{choiceReuse, choiceOverwrite}, choiceReuse)
+		if errors.Is(err, ui.ErrInputRequired) {
+			return false, &ui.InputRequiredError{
+				Reason: ui.ReasonOverwriteConfirmation,
+				Err:    fmt.Errorf("%s already exists; choose --on-existing overwrite, skip or version", path),
+			}
+		}
+		if err != nil {
+			return false, err
+		}
+		return choice == choiceOverwrite, nil
+	}
+}
+
+// printOutputResult reports a created or reused output file
+func printOutputResult(output OutputWriter, path string, reused bool) {
+	if reused {
+		fmt.Fprintf(output, "Reusing existing file: %s\n", path)
+		return
+	}
+	fmt.Fprintf(output, "Successfully created: %s\n", path)
+}
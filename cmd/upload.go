@@ -10,7 +10,6 @@ import (
 
 	appdist "nac-service-media/application/distribution"
 	"nac-service-media/domain/distribution"
-	"nac-service-media/infrastructure/drive"
 
 	"github.com/spf13/cobra"
 )
@@ -20,6 +19,8 @@ var (
 	uploadAudioPath string
 	uploadVideoOnly bool
 	uploadAudioOnly bool
+	uploadFolderID  string
+	uploadTargetArg string
 )
 
 var uploadCmd = &cobra.Command{
@@ -32,12 +33,19 @@ Use --video and --audio flags to specify specific files.
 Use --video-only or --audio-only to upload only one type.
 
 The files will be uploaded to the configured Google Drive Services folder
-and made publicly accessible with "anyone with the link" permission.
+(or the one given with --folder-id) and made publicly accessible with
+"anyone with the link" permission.
+
+With --target youtube the video goes to YouTube instead, with the privacy
+set by youtube.privacy (unlisted by default); --target both uploads it to
+both. Audio always goes to Drive.
 
 Example:
   nac-service-media upload
   nac-service-media upload --video /path/to/2025-12-28.mp4 --audio /path/to/2025-12-28.mp3
-  nac-service-media upload --video-only --video /path/to/2025-12-28.mp4`,
+  nac-service-media upload --video-only --video /path/to/2025-12-28.mp4
+  nac-service-media upload --folder-id 1AbCdEfConvention2025
+  nac-service-media upload --target youtube --video /path/to/2025-12-28.mp4 --audio /path/to/2025-12-28.mp3`,
 	RunE: runUpload,
 }
 
@@ -47,6 +55,8 @@ func init() {
 	uploadCmd.Flags().StringVar(&uploadAudioPath, "audio", "", "Path to audio file (defaults to latest in audio directory)")
 	uploadCmd.Flags().BoolVar(&uploadVideoOnly, "video-only", false, "Upload only the video file")
 	uploadCmd.Flags().BoolVar(&uploadAudioOnly, "audio-only", false, "Upload only the audio file")
+	uploadCmd.Flags().StringVar(&uploadFolderID, "folder-id", "", "Upload to this Drive folder instead of google.services_folder_id")
+	uploadCmd.Flags().StringVar(&uploadTargetArg, "target", "", "Where to upload the video: drive, youtube or both (default youtube.target, else drive)")
 }
 
 func runUpload(cmd *cobra.Command, args []string) error {
@@ -55,12 +65,18 @@ func runUpload(cmd *cobra.Command, args []string) error {
 	if cfg == nil {
 		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
 	}
+	if err := checkFolderOverride(cfg, uploadFolderID); err != nil {
+		return err
+	}
+	target, err := uploadTarget(cfg, uploadTargetArg)
+	if err != nil {
+		return err
+	}
 
 	// Resolve video path
 	videoPath := uploadVideoPath
 	if videoPath == "" && !uploadAudioOnly {
 		// Find latest video in trimmed directory
-		var err error
 		videoPath, err = findLatestFile(cfg.Paths.TrimmedDirectory, ".mp4")
 		if err != nil {
 			return fmt.Errorf("no video file specified and could not find latest: %w", err)
@@ -71,29 +87,63 @@ func runUpload(cmd *cobra.Command, args []string) error {
 	audioPath := uploadAudioPath
 	if audioPath == "" && !uploadVideoOnly {
 		// Find latest audio in audio directory
-		var err error
 		audioPath, err = findLatestFile(cfg.Paths.AudioDirectory, ".mp3")
 		if err != nil {
 			return fmt.Errorf("no audio file specified and could not find latest: %w", err)
 		}
 	}
 
-	// Create drive client with OAuth
-	ctx := cmd.Context()
-	client, err := drive.NewClientWithOAuth(ctx, cfg.Google.CredentialsFile, cfg.Google.TokenFile)
+	ctx, err := googleContext(cmd.Context(), cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create Google Drive client: %w", err)
+		return err
+	}
+
+	// The video goes to YouTube first when asked; Drive then only takes what
+	// is left for it
+	audioOnly := uploadAudioOnly
+	if target.UsesVideoHost() && !uploadAudioOnly {
+		host, err := newVideoHost(ctx, cfg, false)
+		if err != nil {
+			return err
+		}
+		if err := RunHostedUploadWithDependencies(ctx, cfg, host, videoPath, os.Stdout); err != nil {
+			return err
+		}
+		if !target.UsesDrive() {
+			if uploadVideoOnly {
+				fmt.Println("Upload complete!")
+				return nil
+			}
+			audioOnly = true
+		}
+	}
+
+	// Create the Drive (or S3) client
+	client, err := newStorageClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	scanner, err := newShareScanner(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Buckets have no folder to link to
+	folderID := servicesFolder(cfg, uploadFolderID)
+	if cfg.UsesS3() {
+		folderID = ""
 	}
 
 	return RunUploadWithDependencies(
 		ctx,
 		client,
-		cfg.Google.ServicesFolderID,
+		folderID,
 		videoPath,
 		audioPath,
 		uploadVideoOnly,
-		uploadAudioOnly,
+		audioOnly,
 		os.Stdout,
+		appdist.WithScanner(scanner),
 	)
 }
 
@@ -131,6 +181,19 @@ func findLatestFile(dir, ext string) (string, error) {
 	return latestPath, nil
 }
 
+// printSharingPending tells the user how to finish sharing a file that
+// uploaded but could not be shared
+func printSharingPending(output io.Writer, result *distribution.UploadResult) {
+	if !result.SharingPending {
+		return
+	}
+	dateArg := "YYYY-MM-DD"
+	if date, err := parseDateFromFilename(result.FileName); err == nil {
+		dateArg = date.Format("2006-01-02")
+	}
+	fmt.Fprintf(output, "  Sharing: pending; run 'nac-service-media drive share --date %s'\n", dateArg)
+}
+
 // RunUploadWithDependencies runs the upload command with injected dependencies (for testing)
 func RunUploadWithDependencies(
 	ctx context.Context,
@@ -141,8 +204,16 @@ func RunUploadWithDependencies(
 	videoOnly bool,
 	audioOnly bool,
 	output io.Writer,
+	opts ...appdist.ShareOption,
 ) error {
-	service := appdist.NewUploadService(driveClient, folderID, output)
+	folder, err := appdist.CheckFolder(ctx, driveClient, folderID)
+	if err != nil {
+		return fmt.Errorf("cannot upload to Drive folder %s: %w", folderID, err)
+	}
+	if folder != nil {
+		fmt.Fprintf(output, "Drive folder: %s\n\n", folder.Name)
+	}
+	service := appdist.NewUploadService(driveClient, folderID, output, opts...)
 
 	// Upload video if not audio-only
 	if !audioOnly && videoPath != "" {
@@ -155,6 +226,7 @@ func RunUploadWithDependencies(
 		fmt.Fprintf(output, "  File ID: %s\n", result.FileID)
 		fmt.Fprintf(output, "  Size: %.2f MB\n", float64(result.Size)/1024/1024)
 		fmt.Fprintf(output, "  Shareable URL: %s\n", result.ShareableURL)
+		printSharingPending(output, result)
 		fmt.Fprintln(output)
 	}
 
@@ -169,9 +241,13 @@ func RunUploadWithDependencies(
 		fmt.Fprintf(output, "  File ID: %s\n", result.FileID)
 		fmt.Fprintf(output, "  Size: %.2f MB\n", float64(result.Size)/1024/1024)
 		fmt.Fprintf(output, "  Shareable URL: %s\n", result.ShareableURL)
+		printSharingPending(output, result)
 		fmt.Fprintln(output)
 	}
 
 	fmt.Fprintf(output, "Upload complete!\n")
+	if folderID != "" {
+		fmt.Fprintf(output, "Services folder: %s\n", distribution.FolderURL(folderID))
+	}
 	return nil
 }
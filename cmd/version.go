@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+
+	appupdate "nac-service-media/application/update"
+	"nac-service-media/domain/release"
+	"nac-service-media/infrastructure/config"
+	infradetection "nac-service-media/infrastructure/detection"
+	"nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/github"
+
+	"github.com/spf13/cobra"
+)
+
+// Build information, set at build time via -ldflags "-X nac-service-media/cmd.version=..."
+var (
+	version   = "dev"
+	commit    = ""
+	buildDate = ""
+)
+
+var versionCapabilities bool
+
+var (
+	selfUpdateCheck   bool
+	selfUpdateChannel string
+	selfUpdateForce   bool
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show build information",
+	Long: `Show build information.
+
+Use --capabilities to list the optional features compiled into this binary,
+such as timestamp detection, which needs a build with -tags=detection.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		printVersion(os.Stdout)
+		if versionCapabilities {
+			PrintCapabilities(os.Stdout, BuildCapabilities())
+		}
+	},
+}
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update to the latest release",
+	Long: `Check GitHub releases for a newer build and install it in place.
+
+The download is verified against the release checksums (and signature, when
+update.public_key is configured) before the running binary is replaced.
+
+Examples:
+  nac-service-media self-update --check
+  nac-service-media self-update
+  nac-service-media self-update --channel beta`,
+	RunE: runSelfUpdate,
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	versionCmd.Flags().BoolVar(&versionCapabilities, "capabilities", false, "List the optional features compiled into this build")
+
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "Only report whether an update is available")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateChannel, "channel", "", "Release channel: stable or beta (defaults to config update.channel)")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "Install even if the running build is current or a development build")
+}
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildDate string
+	GoVersion string
+	Platform  string
+}
+
+// GetBuildInfo returns build information, falling back to VCS stamps from
+// the Go toolchain when ldflags were not set
+func GetBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = s.Value
+				}
+			}
+		}
+	}
+
+	return info
+}
+
+func printVersion(output io.Writer) {
+	info := GetBuildInfo()
+	fmt.Fprintf(output, "nac-service-media %s\n", info.Version)
+	if info.Commit != "" {
+		fmt.Fprintf(output, "  Commit:   %s\n", info.Commit)
+	}
+	if info.BuildDate != "" {
+		fmt.Fprintf(output, "  Built:    %s\n", info.BuildDate)
+	}
+	fmt.Fprintf(output, "  Go:       %s\n", info.GoVersion)
+	fmt.Fprintf(output, "  Platform: %s\n", info.Platform)
+}
+
+// Capability is an optional feature that depends on how the binary was built
+type Capability struct {
+	Name      string
+	Available bool
+	Hint      string // How to get the feature when it is missing
+}
+
+// BuildCapabilities lists the optional features and whether this build has them
+func BuildCapabilities() []Capability {
+	return []Capability{
+		{Name: "start detection", Available: infradetection.Available, Hint: "build with -tags=detection and install OpenCV/GoCV"},
+		{Name: "end detection", Available: infradetection.Available, Hint: "build with -tags=detection; needs Python 3 with librosa, or set detection.end.method to silence"},
+		{Name: "failure simulation", Available: devtoolsBuild, Hint: "build with -tags=devtools"},
+	}
+}
+
+// PrintCapabilities writes one line per capability, with a hint for each missing one
+func PrintCapabilities(output io.Writer, caps []Capability) {
+	fmt.Fprintln(output, "Capabilities:")
+	for _, c := range caps {
+		if c.Available {
+			fmt.Fprintf(output, "  %-20s yes\n", c.Name)
+			continue
+		}
+		fmt.Fprintf(output, "  %-20s no (%s)\n", c.Name, c.Hint)
+	}
+}
+
+// SelfUpdateInput contains the options for a self-update run
+type SelfUpdateInput struct {
+	CurrentVersion string
+	Channel        string
+	CheckOnly      bool
+	Force          bool
+	GOOS           string
+	GOARCH         string
+	ExecPath       string
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		// Self-update works without a config; use defaults
+		cfg = &config.Config{}
+	}
+
+	execPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate running executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+		execPath = resolved
+	}
+
+	input := SelfUpdateInput{
+		CurrentVersion: version,
+		Channel:        selfUpdateChannel,
+		CheckOnly:      selfUpdateCheck,
+		Force:          selfUpdateForce,
+		GOOS:           runtime.GOOS,
+		GOARCH:         runtime.GOARCH,
+		ExecPath:       execPath,
+	}
+
+	source := github.NewClient(cfg.Update.Repository)
+	installer := filesystem.NewBinaryInstaller()
+
+	return RunSelfUpdateWithDependencies(cmd.Context(), cfg, source, installer, input, os.Stdout)
+}
+
+// RunSelfUpdateWithDependencies runs self-update with injected dependencies (for testing)
+func RunSelfUpdateWithDependencies(
+	ctx context.Context,
+	cfg *config.Config,
+	source release.Source,
+	installer release.Installer,
+	input SelfUpdateInput,
+	output io.Writer,
+) error {
+	if cfg.Update.Disabled {
+		return fmt.Errorf("self-update is disabled by configuration (update.disabled); contact your administrator to upgrade")
+	}
+
+	channel := input.Channel
+	if channel == "" {
+		channel = cfg.Update.Channel
+	}
+
+	// Development builds can't be compared; treat them as older than any release
+	current := input.CurrentVersion
+	isDev := release.IsDevBuild(current)
+	if isDev {
+		current = "v0.0.0-dev"
+	}
+
+	service, err := appupdate.NewService(source, installer, current, channel, cfg.Update.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(output, "Checking for updates...")
+	result, err := service.Check(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output, "  Current: %s\n", input.CurrentVersion)
+	fmt.Fprintf(output, "  Latest:  %s\n", result.Latest.Version)
+
+	// --force reinstalls the latest release, but a check only reports
+	if !result.UpdateAvailable && (input.CheckOnly || !input.Force) {
+		fmt.Fprintln(output, "Already up to date.")
+		return nil
+	}
+
+	if input.CheckOnly {
+		fmt.Fprintf(output, "Update available. Run 'nac-service-media self-update' to install %s.\n", result.Latest.Version)
+		return nil
+	}
+
+	if isDev && !input.Force {
+		return fmt.Errorf("running a development build; use --force to replace it with %s", result.Latest.Version)
+	}
+
+	fmt.Fprintf(output, "Downloading %s for %s/%s...\n", result.Latest.Version, input.GOOS, input.GOARCH)
+	if err := service.Apply(ctx, result.Latest, input.GOOS, input.GOARCH, input.ExecPath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output, "Updated to %s.\n", result.Latest.Version)
+	return nil
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	domainfs "nac-service-media/domain/filesystem"
+	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/filesystem"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	watermarkSource    string
+	watermarkAt        string
+	watermarkOutput    string
+	watermarkDate      string
+	watermarkTitle     string
+	watermarkScripture string
+)
+
+// DefaultWatermarkPreviewAt is the source position of the preview frame
+const DefaultWatermarkPreviewAt = "00:01:00"
+
+var watermarkCmd = &cobra.Command{
+	Use:   "watermark",
+	Short: "Check the text burned into trimmed videos",
+	Long: `When video.watermark.enabled is true, trim and process draw text such as the
+service date onto the video. Drawing means re-encoding, so the trim takes much
+longer than a plain copy.`,
+}
+
+var watermarkPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Save one frame with the watermark drawn to check its placement",
+	Long: `Render a single frame of a recording with video.watermark applied, so the
+position, font and size can be checked without trimming a whole service. The
+preview is drawn even while video.watermark.enabled is false.
+
+Examples:
+  nac-service-media watermark preview --source "2025-12-28 10-06-16.mp4"
+  nac-service-media watermark preview --source "2025-12-28 10-06-16.mp4" --at 00:20:00 \
+    --title "The Good Shepherd" --output shepherd.png`,
+	RunE: runWatermarkPreview,
+}
+
+func init() {
+	rootCmd.AddCommand(watermarkCmd)
+	watermarkCmd.AddCommand(watermarkPreviewCmd)
+
+	watermarkPreviewCmd.Flags().StringVar(&watermarkSource, "source", "", "Path to source video file (required)")
+	watermarkPreviewCmd.Flags().StringVar(&watermarkAt, "at", DefaultWatermarkPreviewAt, "Position of the preview frame in HH:MM:SS")
+	watermarkPreviewCmd.Flags().StringVar(&watermarkOutput, "output", "watermark-preview.png", "Image file to write")
+	watermarkPreviewCmd.Flags().StringVar(&watermarkDate, "date", "", "Service date for {date} in YYYY-MM-DD (defaults to the filename's date)")
+	watermarkPreviewCmd.Flags().StringVar(&watermarkTitle, "title", "", "Sermon title for {title}")
+	watermarkPreviewCmd.Flags().StringVar(&watermarkScripture, "scripture", "", "Scripture reading for {scripture}")
+	watermarkPreviewCmd.MarkFlagRequired("source")
+}
+
+// WatermarkPreviewInput holds the options for a watermark preview
+type WatermarkPreviewInput struct {
+	SourcePath string
+	At         string // HH:MM:SS in the source
+	OutputPath string
+	Date       string // YYYY-MM-DD; defaults to the date in the filename
+	Tags       video.MediaTags
+}
+
+func runWatermarkPreview(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+
+	sourcePath := domainfs.ResolveSource(filesystem.NewChecker().Exists, cfg.Paths.Sources(), watermarkSource)
+
+	// Preview the configured look whether or not it is switched on yet
+	settings := cfg.Video.Watermark
+	settings.Enabled = true
+	style, err := settings.Style()
+	if err != nil {
+		return fmt.Errorf("invalid video.watermark: %w", err)
+	}
+	calendar, err := cfg.Locale.Calendar()
+	if err != nil {
+		return fmt.Errorf("invalid locale: %w", err)
+	}
+
+	return RunWatermarkPreviewWithDependencies(cmd.Context(), newTrimmer(cfg), style, calendar, WatermarkPreviewInput{
+		SourcePath: sourcePath,
+		At:         watermarkAt,
+		OutputPath: watermarkOutput,
+		Date:       watermarkDate,
+		Tags:       video.MediaTags{Title: watermarkTitle, Scripture: watermarkScripture},
+	}, os.Stdout)
+}
+
+// RunWatermarkPreviewWithDependencies runs the watermark preview command with injected dependencies (for testing)
+func RunWatermarkPreviewWithDependencies(
+	ctx context.Context,
+	previewer video.WatermarkPreviewer,
+	style video.WatermarkStyle,
+	calendar video.ServiceCalendar,
+	input WatermarkPreviewInput,
+	output io.Writer,
+) error {
+	at, err := video.ParseTimestamp(input.At)
+	if err != nil {
+		return fmt.Errorf("invalid --at: %w", err)
+	}
+
+	var serviceDate time.Time
+	if input.Date != "" {
+		if serviceDate, err = time.Parse("2006-01-02", input.Date); err != nil {
+			return fmt.Errorf("invalid --date (use YYYY-MM-DD): %w", err)
+		}
+	} else if serviceDate, err = calendar.DateFromFilename(filepath.Base(input.SourcePath)); err != nil {
+		return fmt.Errorf("%w; use --date to give the service date", err)
+	}
+
+	watermark := style.For(serviceDate, input.Tags)
+	if watermark.IsZero() {
+		return fmt.Errorf("the watermark text is empty; set video.watermark.text")
+	}
+
+	fmt.Fprintf(output, "Drawing %q at the %s of the frame at %s...\n", watermark.Text, watermark.Position, at)
+	if err := previewer.PreviewWatermark(ctx, input.SourcePath, at, watermark, input.OutputPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(output, "Preview saved: %s\n", input.OutputPath)
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"nac-service-media/infrastructure/config"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	whoamiSetSender        string
+	whoamiSetOperatorEmail string
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show or set who operates this machine",
+	Long: `Show which sender signs emails and where sandbox emails go on this machine,
+and which setting chose each.
+
+Without --sender, emails are signed by the first of:
+
+  1. the sender in this machine's user.yaml (set with whoami --set-sender)
+  2. a sender whose key is your login name
+  3. senders.default_sender in config
+
+Sandbox emails go to operator_email in user.yaml, then email.operator_address,
+then email.from_address. user.yaml lives in the user config directory (see
+--user-config), so each volunteer's PC keeps its own.
+
+Examples:
+  # Who am I on this machine?
+  nac-service-media whoami
+
+  # Sign as jonathan and get sandbox emails on this machine
+  nac-service-media whoami --set-sender jonathan --set-operator-email jonathan@example.com`,
+	RunE: runWhoami,
+}
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+	whoamiCmd.Flags().StringVar(&whoamiSetSender, "set-sender", "", "Sender key to sign emails with on this machine")
+	whoamiCmd.Flags().StringVar(&whoamiSetOperatorEmail, "set-operator-email", "", "Address that receives sandbox emails on this machine")
+}
+
+func runWhoami(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("configuration not loaded; ensure config/config.yaml exists")
+	}
+	path, err := userConfigPath()
+	if err != nil {
+		return err
+	}
+	return RunWhoamiWithDependencies(cfg, path, whoamiSetSender, whoamiSetOperatorEmail, os.Stdout)
+}
+
+// RunWhoamiWithDependencies saves any new sender or operator email to the
+// user file at userPath, then prints this machine's operator identity
+func RunWhoamiWithDependencies(cfg *config.Config, userPath, setSender, setOperatorEmail string, output io.Writer) error {
+	if setSender != "" || setOperatorEmail != "" {
+		user, err := config.LoadUser(userPath)
+		if err != nil {
+			return err
+		}
+		if setSender != "" {
+			sender, err := config.NewConfigManager(cfg, "").GetSender(setSender)
+			if err != nil {
+				return fmt.Errorf("%w\n\nTo fix this, run:\n  %s", err, config.SuggestAddSenderCommand(setSender))
+			}
+			user.Sender = sender.Key
+		}
+		if setOperatorEmail != "" {
+			user.OperatorEmail = setOperatorEmail
+		}
+		if err := config.SaveUser(user, userPath); err != nil {
+			return err
+		}
+		user.Path, user.Login = userPath, cfg.User.Login
+		cfg.User = user
+		fmt.Fprintf(output, "Saved %s\n\n", userPath)
+	}
+
+	if cfg.User.Path != "" {
+		fmt.Fprintf(output, "User file:      %s\n", cfg.User.Path)
+	} else {
+		fmt.Fprintf(output, "User file:      none (%s does not exist)\n", userPath)
+	}
+	if cfg.User.Login != "" {
+		fmt.Fprintf(output, "Login:          %s\n", cfg.User.Login)
+	}
+
+	sender, from, err := config.NewConfigManager(cfg, "").DefaultSender()
+	switch {
+	case errors.Is(err, config.ErrNoDefaultSender):
+		fmt.Fprintln(output, "Sender:         not set; use --sender, whoami --set-sender, or senders.default_sender")
+	case err != nil:
+		return err
+	default:
+		fmt.Fprintf(output, "Sender:         %s (%s), from %s\n", sender.Name, sender.Key, from)
+	}
+
+	operator, from := operatorEmail(cfg)
+	if operator == "" {
+		fmt.Fprintln(output, "Operator email: not set")
+	} else {
+		fmt.Fprintf(output, "Operator email: %s, from %s\n", operator, from)
+	}
+	return nil
+}
+
+// operatorEmail returns where sandbox emails go and which setting chose it,
+// in the order RecipientLookup.Operator checks them
+func operatorEmail(cfg *config.Config) (string, string) {
+	switch {
+	case cfg.User.OperatorEmail != "":
+		return cfg.User.OperatorEmail, config.SenderFromUserFile
+	case cfg.Email.OperatorAddress != "":
+		return cfg.Email.OperatorAddress, "email.operator_address"
+	case cfg.Email.FromAddress != "":
+		return cfg.Email.FromAddress, "email.from_address"
+	}
+	return "", ""
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/workspace"
+
+	"github.com/spf13/cobra"
+)
+
+var workspaceCleanOlderThan string
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage temporary run workspaces",
+}
+
+var workspaceCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Delete workspaces left behind by failed runs",
+	Long: `Each process run extracts frames and partial outputs into its own workspace.
+Successful runs remove it; failed runs keep it for debugging. This command
+deletes kept workspaces older than --older-than.
+
+The workspace location is paths.workspace_directory in config, or the system
+temp directory when unset.
+
+Examples:
+  nac-service-media workspace clean
+  nac-service-media workspace clean --older-than 2d
+  nac-service-media workspace clean --older-than 0`,
+	RunE: runWorkspaceClean,
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceCleanCmd)
+	workspaceCleanCmd.Flags().StringVar(&workspaceCleanOlderThan, "older-than", "7d", "Only delete workspaces older than this, e.g. 7d or 12h")
+}
+
+func runWorkspaceClean(cmd *cobra.Command, args []string) error {
+	// Config is optional here: without it, clean the default location
+	base := ""
+	if cfg := GetConfig(); cfg != nil {
+		base = cfg.Paths.WorkspaceDirectory
+	}
+	return RunWorkspaceCleanWithDependencies(base, workspaceCleanOlderThan, time.Now(), os.Stdout)
+}
+
+// RunWorkspaceCleanWithDependencies runs the workspace clean command with injected dependencies (for testing).
+// An empty base means workspace.DefaultBase().
+func RunWorkspaceCleanWithDependencies(base, olderThan string, now time.Time, output io.Writer) error {
+	age, err := config.Duration(olderThan).Value()
+	if err != nil {
+		return fmt.Errorf("invalid --older-than: %w", err)
+	}
+
+	removed, err := workspace.Clean(base, now.Add(-age))
+	var freed int64
+	for _, r := range removed {
+		fmt.Fprintf(output, "  Removed: %s (%s)\n", r.Path, distribution.FormatSize(r.Bytes))
+		freed += r.Bytes
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		fmt.Fprintln(output, "No old workspaces to remove")
+		return nil
+	}
+	fmt.Fprintf(output, "Freed %s from %d workspaces\n", distribution.FormatSize(freed), len(removed))
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/youtube"
+)
+
+// uploadTarget returns where videos go: the --target flag, or youtube.target
+// when it is not given
+func uploadTarget(cfg *config.Config, flag string) (distribution.Target, error) {
+	if flag == "" {
+		flag = cfg.YouTube.Target
+	}
+	target, err := distribution.ParseTarget(flag)
+	if err != nil {
+		return "", fmt.Errorf("invalid --target: %w", err)
+	}
+	return target, nil
+}
+
+// newVideoHost signs in to YouTube with the Google OAuth client
+func newVideoHost(ctx context.Context, cfg *config.Config, nonInteractive bool) (distribution.VideoHost, error) {
+	client, err := youtube.NewClientWithOAuth(ctx, youtube.OAuthConfig{
+		CredentialsFile: cfg.Google.CredentialsFile,
+		TokenFile:       cfg.Google.YouTubeTokenFile,
+		NonInteractive:  nonInteractive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create YouTube client: %w", err)
+	}
+	return client, nil
+}
+
+// RunHostedUploadWithDependencies uploads a trimmed video, named for its
+// service date, to a video host (for testing)
+func RunHostedUploadWithDependencies(
+	ctx context.Context,
+	cfg *config.Config,
+	host distribution.VideoHost,
+	videoPath string,
+	output io.Writer,
+	opts ...appdist.HostOption,
+) error {
+	serviceDate, err := parseDateFromFilename(filepath.Base(videoPath))
+	if err != nil {
+		return fmt.Errorf("cannot list %s on %s: name it by its service date, e.g. 2025-12-28.mp4", filepath.Base(videoPath), host.Name())
+	}
+
+	fmt.Fprintf(output, "Uploading video to %s: %s...\n", host.Name(), filepath.Base(videoPath))
+	hosted, err := appdist.NewHostService(host, opts...).UploadVideo(ctx, videoPath, distribution.VideoDetails{
+		ChurchName:  cfg.Email.FromName,
+		ServiceDate: serviceDate,
+		Privacy:     distribution.Privacy(cfg.YouTube.Privacy),
+	})
+	if err != nil {
+		return fmt.Errorf("%s upload failed: %w", host.Name(), err)
+	}
+	fmt.Fprintf(output, "Video uploaded successfully!\n")
+	fmt.Fprintf(output, "  Video ID: %s\n", hosted.ID)
+	fmt.Fprintf(output, "  Privacy: %s\n", hosted.Privacy)
+	fmt.Fprintf(output, "  Watch URL: %s\n", hosted.URL)
+	fmt.Fprintln(output)
+	return nil
+}
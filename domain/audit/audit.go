@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Destructive operations recorded in the audit log
+const (
+	ActionDriveDelete = "drive_delete" // File deleted from storage, bypassing the trash
+	ActionEmptyTrash  = "empty_trash"  // Storage trash emptied
+	ActionShare       = "share"        // Permission added to a stored file
+	ActionUnshare     = "unshare"      // Permission removed from a stored file
+	ActionLocalRemove = "local_remove" // Local file removed
+	// ActionCleanupDecision records whether deleting old files to make room
+	// was approved, allowed by flag, declined or refused
+	ActionCleanupDecision = "cleanup_decision"
+)
+
+// Outcomes of a recorded operation
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailed  = "failed"
+)
+
+// Event is one destructive operation. Each event carries the hash of the one
+// before it, so editing, removing or reordering a line breaks the chain.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Target  string    `json:"target,omitempty"` // File ID, object key or local path
+	Detail  string    `json:"detail,omitempty"` // e.g. the permission granted
+	Actor   string    `json:"actor,omitempty"`  // user@host
+	Command string    `json:"command,omitempty"`
+	Outcome string    `json:"outcome"`
+	Error   string    `json:"error,omitempty"`
+
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// Recorder appends events to the audit log
+// This is a port that can be implemented by different infrastructure adapters
+type Recorder interface {
+	Record(e Event) error
+}
+
+// Log is a Recorder that can also read back what it recorded
+type Log interface {
+	Recorder
+
+	// List returns all events in the order they were recorded
+	List() ([]Event, error)
+}
+
+// NewEvent describes the outcome of an operation on target; err is the
+// operation's error, if any
+func NewEvent(action, target string, err error) Event {
+	e := Event{Action: action, Target: target, Outcome: OutcomeSuccess}
+	if err != nil {
+		e.Outcome = OutcomeFailed
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// ComputeHash returns the hash of the event's content and PrevHash
+func (e Event) ComputeHash() string {
+	e.Hash = ""
+	data, _ := json.Marshal(e) // A struct of strings and a time cannot fail
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Seal links e after the event whose hash is prev and sets its hash
+func Seal(e Event, prev string) Event {
+	e.PrevHash = prev
+	e.Hash = e.ComputeHash()
+	return e
+}
+
+// ErrTampered is returned when the log no longer matches its hash chain
+var ErrTampered = errors.New("audit log has been altered")
+
+// Verify checks the hash chain. The error names the first event (1-based)
+// that does not match.
+func Verify(events []Event) error {
+	prev := ""
+	for i, e := range events {
+		if e.PrevHash != prev && i == 0 {
+			return fmt.Errorf("%w: event 1 is not the start of the log (earlier events removed)", ErrTampered)
+		}
+		if e.PrevHash != prev {
+			return fmt.Errorf("%w: event %d does not follow event %d (removed or reordered)", ErrTampered, i+1, i)
+		}
+		if e.ComputeHash() != e.Hash {
+			return fmt.Errorf("%w: event %d was edited", ErrTampered, i+1)
+		}
+		prev = e.Hash
+	}
+	return nil
+}
+
+// Record records the outcome of an operation on recorder, if there is one.
+// The operation's error is returned unchanged; if it succeeded but could not
+// be recorded, that failure is returned instead.
+func Record(recorder Recorder, action, target, detail string, opErr error) error {
+	if recorder == nil {
+		return opErr
+	}
+	e := NewEvent(action, target, opErr)
+	e.Detail = detail
+	if err := recorder.Record(e); err != nil && opErr == nil {
+		return fmt.Errorf("%s %s succeeded but could not be audited: %w", action, target, err)
+	}
+	return opErr
+}
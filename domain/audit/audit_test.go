@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func chain(n int) []Event {
+	var events []Event
+	prev := ""
+	for i := 0; i < n; i++ {
+		e := NewEvent(ActionDriveDelete, "file"+string(rune('a'+i)), nil)
+		e.Time = time.Date(2025, 12, 28, 10, i, 0, 0, time.UTC)
+		e = Seal(e, prev)
+		prev = e.Hash
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestVerify_IntactChain(t *testing.T) {
+	if err := Verify(chain(3)); err != nil {
+		t.Fatalf("expected intact chain, got %v", err)
+	}
+	if err := Verify(nil); err != nil {
+		t.Fatalf("expected empty log to verify, got %v", err)
+	}
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		alter  func([]Event) []Event
+		expect string
+	}{
+		{
+			name:   "edited",
+			alter:  func(e []Event) []Event { e[1].Target = "other"; return e },
+			expect: "event 2 was edited",
+		},
+		{
+			name:   "removed",
+			alter:  func(e []Event) []Event { return append(e[:1], e[2:]...) },
+			expect: "event 2 does not follow event 1",
+		},
+		{
+			name:   "reordered",
+			alter:  func(e []Event) []Event { e[1], e[2] = e[2], e[1]; return e },
+			expect: "event 2 does not follow event 1",
+		},
+		{
+			name:   "head removed",
+			alter:  func(e []Event) []Event { return e[1:] },
+			expect: "event 1 is not the start of the log",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.alter(chain(3)))
+			if !errors.Is(err, ErrTampered) {
+				t.Fatalf("expected ErrTampered, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.expect) {
+				t.Errorf("expected %q in %q", tt.expect, err.Error())
+			}
+		})
+	}
+}
+
+type failingRecorder struct{ events []Event }
+
+func (r *failingRecorder) Record(e Event) error {
+	r.events = append(r.events, e)
+	return errors.New("disk full")
+}
+
+func TestRecord(t *testing.T) {
+	opErr := errors.New("not found")
+	r := &failingRecorder{}
+
+	if err := Record(r, ActionDriveDelete, "abc", "", opErr); err != opErr {
+		t.Errorf("expected the operation's error, got %v", err)
+	}
+	if got := r.events[0]; got.Outcome != OutcomeFailed || got.Error != "not found" {
+		t.Errorf("expected failed event, got %+v", got)
+	}
+
+	err := Record(r, ActionShare, "abc", "anyone:reader", nil)
+	if err == nil || !strings.Contains(err.Error(), "could not be audited") {
+		t.Errorf("expected audit failure, got %v", err)
+	}
+	if got := r.events[1]; got.Outcome != OutcomeSuccess || got.Detail != "anyone:reader" {
+		t.Errorf("expected successful share event, got %+v", got)
+	}
+
+	if err := Record(nil, ActionEmptyTrash, "", "", nil); err != nil {
+		t.Errorf("expected no error without a recorder, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Sleeper waits for d, or returns the context's error if it is cancelled
+// first. Services take one so retries and pauses can be tested without waiting.
+type Sleeper func(ctx context.Context, d time.Duration) error
+
+// Sleep is the Sleeper that really waits
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,178 @@
+package detection
+
+import (
+	"fmt"
+	"strings"
+
+	"nac-service-media/domain/video"
+)
+
+// Coarse scan step defaults, in seconds
+const (
+	DefaultCoarseMaxStepSeconds = 120
+	DefaultCoarseMinStepSeconds = 10
+)
+
+const (
+	// nearLitRatio is the fraction of the match threshold at which the lit
+	// score counts as close to lighting up
+	nearLitRatio = 0.8
+
+	// risingPerMinute is the lit-score gain per minute treated as a trend toward lit
+	risingPerMinute = 0.05
+)
+
+// Coarse step decision reasons
+const (
+	ReasonInitial     = "initial step"
+	ReasonNearLit     = "lit score near threshold"
+	ReasonRising      = "lit score rising"
+	ReasonFalling     = "lit score flat or falling"
+	ReasonSteady      = "steady"
+	ReasonUnavailable = "frame unavailable"
+)
+
+// CoarseDecision records one step of the coarse scan for the debug report
+type CoarseDecision struct {
+	// TimestampSeconds is the frame that was analyzed
+	TimestampSeconds int
+
+	// State is the frame's detected cross state (empty if the frame was unavailable)
+	State FrameState
+
+	// LitScore is the best lit-template score for the frame
+	LitScore float64
+
+	// Gradient is the change in lit score per minute since the previous frame
+	Gradient float64
+
+	// NextStep is the step, in seconds, taken after this frame
+	NextStep int
+
+	// Reason explains the step size
+	Reason string
+}
+
+// CoarseStepper picks coarse scan steps: large while the cross shows no sign
+// of lighting, shrinking as the lit score climbs so the scan doesn't jump far
+// past the transition
+type CoarseStepper struct {
+	maxStep   int
+	minStep   int
+	threshold float64
+
+	step     int
+	prevTime int
+	prevLit  float64
+	havePrev bool
+	trace    []CoarseDecision
+}
+
+// NewCoarseStepper creates a stepper. matchThreshold is the template score at
+// which a frame counts as lit. Setting minStep equal to maxStep gives a fixed step.
+func NewCoarseStepper(maxStep, minStep int, matchThreshold float64) *CoarseStepper {
+	if maxStep <= 0 {
+		maxStep = DefaultCoarseMaxStepSeconds
+	}
+	if minStep <= 0 {
+		minStep = DefaultCoarseMinStepSeconds
+	}
+	if minStep > maxStep {
+		minStep = maxStep
+	}
+	return &CoarseStepper{
+		maxStep:   maxStep,
+		minStep:   minStep,
+		threshold: matchThreshold,
+		step:      maxStep,
+	}
+}
+
+// Step returns the current step in seconds
+func (s *CoarseStepper) Step() int {
+	return s.step
+}
+
+// Next records an analyzed frame and returns the step to the next frame
+func (s *CoarseStepper) Next(a FrameAnalysis) int {
+	gradient := 0.0
+	if s.havePrev && a.TimestampSeconds > s.prevTime {
+		gradient = (a.LitScore - s.prevLit) / (float64(a.TimestampSeconds-s.prevTime) / 60)
+	}
+
+	reason := ReasonSteady
+	switch {
+	case s.threshold > 0 && a.LitScore >= s.threshold*nearLitRatio:
+		s.shrink()
+		reason = ReasonNearLit
+	case s.havePrev && gradient >= risingPerMinute:
+		s.shrink()
+		reason = ReasonRising
+	case s.havePrev && gradient <= 0 && s.step < s.maxStep:
+		s.grow()
+		reason = ReasonFalling
+	case !s.havePrev:
+		reason = ReasonInitial
+	}
+
+	s.prevTime = a.TimestampSeconds
+	s.prevLit = a.LitScore
+	s.havePrev = true
+
+	s.trace = append(s.trace, CoarseDecision{
+		TimestampSeconds: a.TimestampSeconds,
+		State:            a.State,
+		LitScore:         a.LitScore,
+		Gradient:         gradient,
+		NextStep:         s.step,
+		Reason:           reason,
+	})
+	return s.step
+}
+
+// Skip records a frame that could not be analyzed and keeps the current step
+func (s *CoarseStepper) Skip(timestampSeconds int) int {
+	s.trace = append(s.trace, CoarseDecision{
+		TimestampSeconds: timestampSeconds,
+		NextStep:         s.step,
+		Reason:           ReasonUnavailable,
+	})
+	return s.step
+}
+
+// Trace returns the decisions made so far
+func (s *CoarseStepper) Trace() []CoarseDecision {
+	return s.trace
+}
+
+// FormatCoarseTrace renders coarse scan decisions as a table, one frame per
+// line, for the detection debug output
+func FormatCoarseTrace(trace []CoarseDecision) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-8s  %-7s  %5s  %8s  %5s  %s\n", "time", "state", "lit", "lit/min", "next", "reason")
+	for _, d := range trace {
+		state, lit, gradient := "-", "-", "-"
+		if d.Reason != ReasonUnavailable {
+			state = string(d.State)
+			lit = fmt.Sprintf("%.2f", d.LitScore)
+			gradient = fmt.Sprintf("%+.3f", d.Gradient)
+		}
+		fmt.Fprintf(&b, "%-8s  %-7s  %5s  %8s  %4ds  %s\n",
+			video.TimestampFromSeconds(d.TimestampSeconds), state, lit, gradient, d.NextStep, d.Reason)
+	}
+	return b.String()
+}
+
+func (s *CoarseStepper) shrink() {
+	s.step /= 2
+	if s.step < s.minStep {
+		s.step = s.minStep
+	}
+}
+
+func (s *CoarseStepper) grow() {
+	s.step *= 2
+	if s.step > s.maxStep {
+		s.step = s.maxStep
+	}
+}
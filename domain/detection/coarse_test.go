@@ -0,0 +1,107 @@
+package detection
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCoarseStepper_ShrinksAsLitScoreRises(t *testing.T) {
+	s := NewCoarseStepper(120, 10, 0.85)
+
+	frames := []struct {
+		at       int
+		lit      float64
+		wantStep int
+		reason   string
+	}{
+		{0, 0.30, 120, ReasonInitial},
+		{120, 0.30, 120, ReasonSteady},
+		{240, 0.45, 60, ReasonRising},
+		{300, 0.62, 30, ReasonRising},
+		{330, 0.70, 15, ReasonNearLit},
+		{345, 0.75, 10, ReasonNearLit},
+	}
+
+	for _, f := range frames {
+		got := s.Next(FrameAnalysis{State: StateUnlit, LitScore: f.lit, TimestampSeconds: f.at})
+		if got != f.wantStep {
+			t.Errorf("at %ds: step = %d, want %d", f.at, got, f.wantStep)
+		}
+		trace := s.Trace()
+		if r := trace[len(trace)-1].Reason; r != f.reason {
+			t.Errorf("at %ds: reason = %q, want %q", f.at, r, f.reason)
+		}
+	}
+}
+
+func TestCoarseStepper_GrowsBackWhenTrendFades(t *testing.T) {
+	s := NewCoarseStepper(120, 10, 0.85)
+
+	s.Next(FrameAnalysis{LitScore: 0.30, TimestampSeconds: 0})
+	if got := s.Next(FrameAnalysis{LitScore: 0.50, TimestampSeconds: 120}); got != 60 {
+		t.Fatalf("expected step to shrink to 60, got %d", got)
+	}
+	if got := s.Next(FrameAnalysis{LitScore: 0.40, TimestampSeconds: 180}); got != 120 {
+		t.Errorf("expected step to grow back to 120, got %d", got)
+	}
+}
+
+func TestCoarseStepper_FixedStepWhenMinEqualsMax(t *testing.T) {
+	s := NewCoarseStepper(30, 30, 0.85)
+	for i, lit := range []float64{0.3, 0.6, 0.8, 0.84} {
+		if got := s.Next(FrameAnalysis{LitScore: lit, TimestampSeconds: i * 30}); got != 30 {
+			t.Errorf("frame %d: step = %d, want 30", i, got)
+		}
+	}
+}
+
+func TestCoarseStepper_Defaults(t *testing.T) {
+	s := NewCoarseStepper(0, 0, 0.85)
+	if s.Step() != DefaultCoarseMaxStepSeconds {
+		t.Errorf("initial step = %d, want %d", s.Step(), DefaultCoarseMaxStepSeconds)
+	}
+
+	s = NewCoarseStepper(20, 60, 0.85)
+	for i := 0; i < 5; i++ {
+		s.Next(FrameAnalysis{LitScore: 0.84, TimestampSeconds: i * 20})
+	}
+	if s.Step() != 20 {
+		t.Errorf("min step above max should clamp to max, got %d", s.Step())
+	}
+}
+
+func TestCoarseStepper_SkipKeepsStep(t *testing.T) {
+	s := NewCoarseStepper(120, 10, 0.85)
+	s.Next(FrameAnalysis{LitScore: 0.3, TimestampSeconds: 0})
+	if got := s.Skip(120); got != 120 {
+		t.Errorf("Skip() = %d, want 120", got)
+	}
+
+	trace := s.Trace()
+	if len(trace) != 2 || trace[1].Reason != ReasonUnavailable {
+		t.Errorf("expected unavailable frame in trace, got %+v", trace)
+	}
+}
+
+func TestFormatCoarseTrace(t *testing.T) {
+	s := NewCoarseStepper(120, 10, 0.85)
+	s.Next(FrameAnalysis{State: StateUnlit, LitScore: 0.30, TimestampSeconds: 600})
+	s.Skip(720)
+	s.Next(FrameAnalysis{State: StateUnlit, LitScore: 0.50, TimestampSeconds: 840})
+
+	lines := strings.Split(strings.TrimRight(FormatCoarseTrace(s.Trace()), "\n"), "\n")
+	want := []string{
+		"time      state      lit   lit/min   next  reason",
+		"00:10:00  unlit     0.30    +0.000   120s  initial step",
+		"00:12:00  -            -         -   120s  frame unavailable",
+		"00:14:00  unlit     0.50    +0.050    60s  lit score rising",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("FormatCoarseTrace() =\n%s", strings.Join(lines, "\n"))
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
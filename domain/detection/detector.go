@@ -2,10 +2,15 @@ package detection
 
 import (
 	"context"
+	"errors"
 
 	"nac-service-media/domain/video"
 )
 
+// ErrDetectionUnavailable is returned by detectors in builds without the
+// detection tag, so callers can fall back to timestamps given by hand
+var ErrDetectionUnavailable = errors.New("auto-detection is not available in this build")
+
 // StartDetector defines the interface for detecting service start timestamps
 type StartDetector interface {
 	// DetectStart analyzes a video to find when the cross lights up
@@ -31,6 +36,29 @@ type DetectionResult struct {
 
 	// FramesAnalyzed is the number of frames processed during detection
 	FramesAnalyzed int
+
+	// CoarseTrace records the coarse scan's step decisions for debugging
+	CoarseTrace []CoarseDecision
+
+	// EarlyExit is set when a confident unlit/lit bracket ended the scan
+	// before the refinement pass
+	EarlyExit bool
+}
+
+// DefaultEarlyExitScore is the match score both frames of an adjacent
+// unlit/lit bracket need for detection to stop early
+const DefaultEarlyExitScore = 0.95
+
+// ConfidentBracket reports whether unlit and lit are one second apart and both
+// matched at or above score, pinning the transition to the lit frame.
+// A score of zero or less never matches, which turns early exit off.
+func ConfidentBracket(unlit, lit FrameAnalysis, score float64) bool {
+	if score <= 0 {
+		return false
+	}
+	return unlit.State == StateUnlit && lit.State == StateLit &&
+		lit.TimestampSeconds-unlit.TimestampSeconds == 1 &&
+		unlit.Confidence >= score && lit.Confidence >= score
 }
 
 // FrameState represents the detected state of the cross in a video frame
@@ -60,24 +88,28 @@ type FrameAnalysis struct {
 
 	// TimestampSeconds is the frame's position in the video
 	TimestampSeconds int
+
+	// LitScore is the best lit-template score, even when below the match threshold
+	LitScore float64
 }
 
 // EndDetector defines the interface for detecting service end timestamps
 type EndDetector interface {
-	// DetectEnd analyzes a video's audio to find the three-fold amen
+	// DetectEnd analyzes a video to find where the service ends, e.g. the
+	// three-fold amen, the cross going dark or a long silence.
 	// serviceStartSeconds is the detected/provided service start time in the video
 	DetectEnd(ctx context.Context, videoPath string, serviceStartSeconds int) (EndDetectionResult, error)
 }
 
 // EndDetectionResult contains the outcome of end timestamp detection
 type EndDetectionResult struct {
-	// Timestamp is the detected end time (end of amen)
+	// Timestamp is the detected end time (e.g. end of amen)
 	Timestamp video.Timestamp
 
 	// Confidence is the match score (0.0-1.0)
 	Confidence float64
 
-	// Detected indicates whether the amen was found
+	// Detected indicates whether the end was found
 	Detected bool
 
 	// Error message if detection failed
@@ -78,3 +78,31 @@ func TestFrameAnalysis(t *testing.T) {
 		}
 	})
 }
+
+func TestConfidentBracket(t *testing.T) {
+	unlit := FrameAnalysis{State: StateUnlit, Confidence: 0.97, TimestampSeconds: 1444}
+	lit := FrameAnalysis{State: StateLit, Confidence: 0.96, TimestampSeconds: 1445}
+
+	tests := []struct {
+		name  string
+		unlit FrameAnalysis
+		lit   FrameAnalysis
+		score float64
+		want  bool
+	}{
+		{"adjacent and confident", unlit, lit, DefaultEarlyExitScore, true},
+		{"lit below score", unlit, FrameAnalysis{State: StateLit, Confidence: 0.9, TimestampSeconds: 1445}, DefaultEarlyExitScore, false},
+		{"unlit below score", FrameAnalysis{State: StateUnlit, Confidence: 0.9, TimestampSeconds: 1444}, lit, DefaultEarlyExitScore, false},
+		{"gap between frames", FrameAnalysis{State: StateUnlit, Confidence: 0.97, TimestampSeconds: 1440}, lit, DefaultEarlyExitScore, false},
+		{"lower frame not visible", FrameAnalysis{State: StateNotVisible, Confidence: 0.97, TimestampSeconds: 1444}, lit, DefaultEarlyExitScore, false},
+		{"states swapped", FrameAnalysis{State: StateLit, Confidence: 0.97, TimestampSeconds: 1444}, FrameAnalysis{State: StateUnlit, Confidence: 0.97, TimestampSeconds: 1445}, DefaultEarlyExitScore, false},
+		{"disabled by negative score", unlit, lit, -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConfidentBracket(tt.unlit, tt.lit, tt.score); got != tt.want {
+				t.Errorf("ConfidentBracket() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
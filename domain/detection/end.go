@@ -0,0 +1,135 @@
+package detection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"nac-service-media/domain/video"
+)
+
+// ErrEndNotFound is returned when no end of the service was found in the search range
+var ErrEndNotFound = errors.New("service end not found")
+
+// BoundaryDetector finds both ends of a service: the cross lighting up and
+// going dark again
+type BoundaryDetector interface {
+	StartDetector
+	EndDetector
+}
+
+// EndMethod is how the end of a service is detected
+type EndMethod string
+
+// End detection methods
+const (
+	EndByAmen    EndMethod = "amen"    // The three-fold amen in the audio
+	EndByCross   EndMethod = "cross"   // The cross going dark
+	EndBySilence EndMethod = "silence" // The first long silence after the service
+)
+
+// ParseEndMethod reads detection.end.method; empty is amen
+func ParseEndMethod(s string) (EndMethod, error) {
+	switch m := EndMethod(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return EndByAmen, nil
+	case EndByAmen, EndByCross, EndBySilence:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown end detection method %q: use amen, cross or silence", s)
+	}
+}
+
+// NeedsDetectionBuild reports whether the method needs a -tags=detection
+// build; silence only needs ffmpeg
+func (m EndMethod) NeedsDetectionBuild() bool {
+	return m != EndBySilence
+}
+
+// DefaultCrossOffStepSeconds is how far apart frames are checked while
+// looking for the cross to go dark
+const DefaultCrossOffStepSeconds = 30
+
+// FrameProbe analyzes the frame at seconds into the video
+type FrameProbe func(ctx context.Context, seconds int) (FrameAnalysis, error)
+
+// FindCrossOff scans r every step seconds for the cross going dark after it
+// was seen lit, then narrows the change down to the second. It returns the
+// first dark second and how many frames were analyzed. Frames that cannot be
+// read or don't show the cross are passed over during the scan.
+func FindCrossOff(ctx context.Context, probe FrameProbe, r SearchRange, step int) (int, int, error) {
+	if step <= 0 {
+		step = DefaultCrossOffStepSeconds
+	}
+
+	frames := 0
+	lit, dark := -1, -1
+	for t := r.StartSeconds; t <= r.EndSeconds; t += step {
+		if err := ctx.Err(); err != nil {
+			return 0, frames, err
+		}
+		a, err := probe(ctx, t)
+		frames++
+		if err != nil {
+			continue
+		}
+		if a.State == StateLit {
+			lit = t
+		} else if a.State == StateUnlit && lit >= 0 {
+			dark = t
+			break
+		}
+	}
+	if dark < 0 {
+		return 0, frames, fmt.Errorf("%w: the cross did not go dark between %s", ErrEndNotFound, r)
+	}
+
+	// Anything but a lit frame counts as dark while narrowing, so the end
+	// never lands after the cross was last seen lit
+	for dark-lit > 1 {
+		if err := ctx.Err(); err != nil {
+			return 0, frames, err
+		}
+		mid := (lit + dark) / 2
+		a, err := probe(ctx, mid)
+		frames++
+		if err == nil && a.State == StateLit {
+			lit = mid
+		} else {
+			dark = mid
+		}
+	}
+	return dark, frames, nil
+}
+
+// Silence is a stretch of silence, in seconds into the recording. End is
+// zero when the silence lasts to the end of what was analyzed.
+type Silence struct {
+	Start float64
+	End   float64
+}
+
+// Duration returns how long the silence lasted, or zero when it had no end
+func (s Silence) Duration() float64 {
+	if s.End <= s.Start {
+		return 0
+	}
+	return s.End - s.Start
+}
+
+// EndFromSilences returns where the first silence of at least minSeconds
+// starting inside r begins, as the service end. A silence running to the
+// end of the analyzed audio always counts.
+func EndFromSilences(silences []Silence, r SearchRange, minSeconds float64) (video.Timestamp, error) {
+	for _, s := range silences {
+		if s.Start < float64(r.StartSeconds) || s.Start > float64(r.EndSeconds) {
+			continue
+		}
+		if s.End == 0 || s.Duration() >= minSeconds {
+			return video.TimestampFromSeconds(int(math.Ceil(s.Start))), nil
+		}
+	}
+	return video.Timestamp{}, fmt.Errorf("%w: no silence of %.0fs or more between %s", ErrEndNotFound, minSeconds, r)
+}
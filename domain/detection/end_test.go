@@ -0,0 +1,116 @@
+package detection
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseEndMethod(t *testing.T) {
+	if m, err := ParseEndMethod(""); err != nil || m != EndByAmen {
+		t.Errorf("ParseEndMethod(\"\") = %q, %v; want amen", m, err)
+	}
+	if m, err := ParseEndMethod("Silence"); err != nil || m != EndBySilence || m.NeedsDetectionBuild() {
+		t.Errorf("ParseEndMethod(Silence) = %q, %v", m, err)
+	}
+	if !EndByCross.NeedsDetectionBuild() {
+		t.Error("cross detection needs the detection build")
+	}
+	if _, err := ParseEndMethod("applause"); err == nil {
+		t.Error("ParseEndMethod(applause) should fail")
+	}
+}
+
+// crossLitUntil probes a cross that is lit from litFrom until dark
+func crossLitUntil(litFrom, dark int) FrameProbe {
+	return func(ctx context.Context, seconds int) (FrameAnalysis, error) {
+		if seconds >= litFrom && seconds < dark {
+			return FrameAnalysis{State: StateLit, TimestampSeconds: seconds}, nil
+		}
+		return FrameAnalysis{State: StateUnlit, TimestampSeconds: seconds}, nil
+	}
+}
+
+func TestFindCrossOff(t *testing.T) {
+	r := SearchRange{StartSeconds: 3000, EndSeconds: 8400}
+
+	end, frames, err := FindCrossOff(context.Background(), crossLitUntil(0, 6317), r, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end != 6317 {
+		t.Errorf("end = %d, want 6317", end)
+	}
+	if frames == 0 || frames > 120 {
+		t.Errorf("frames analyzed = %d", frames)
+	}
+}
+
+func TestFindCrossOff_DarkBeforeLitIsNotTheEnd(t *testing.T) {
+	// The cross is still unlit when the search starts, lights late and goes dark
+	r := SearchRange{StartSeconds: 0, EndSeconds: 3600}
+	end, _, err := FindCrossOff(context.Background(), crossLitUntil(600, 3000), r, 60)
+	if err != nil || end != 3000 {
+		t.Errorf("FindCrossOff() = %d, %v; want 3000", end, err)
+	}
+}
+
+func TestFindCrossOff_SkipsFramesWithoutTheCross(t *testing.T) {
+	probe := func(ctx context.Context, seconds int) (FrameAnalysis, error) {
+		switch {
+		case seconds == 120:
+			return FrameAnalysis{State: StateNotVisible}, nil
+		case seconds == 150:
+			return FrameAnalysis{}, errors.New("frame unavailable")
+		case seconds < 200:
+			return FrameAnalysis{State: StateLit}, nil
+		}
+		return FrameAnalysis{State: StateUnlit}, nil
+	}
+	end, _, err := FindCrossOff(context.Background(), probe, SearchRange{EndSeconds: 600}, 30)
+	if err != nil || end != 200 {
+		t.Errorf("FindCrossOff() = %d, %v; want 200", end, err)
+	}
+}
+
+func TestFindCrossOff_NotFound(t *testing.T) {
+	r := SearchRange{StartSeconds: 0, EndSeconds: 600}
+	if _, _, err := FindCrossOff(context.Background(), crossLitUntil(0, 9999), r, 30); !errors.Is(err, ErrEndNotFound) {
+		t.Errorf("still lit: error = %v, want ErrEndNotFound", err)
+	}
+	if _, _, err := FindCrossOff(context.Background(), crossLitUntil(9000, 9999), r, 30); !errors.Is(err, ErrEndNotFound) {
+		t.Errorf("never lit: error = %v, want ErrEndNotFound", err)
+	}
+}
+
+func TestEndFromSilences(t *testing.T) {
+	r := SearchRange{StartSeconds: 3000, EndSeconds: 9000}
+	silences := []Silence{
+		{Start: 1200, End: 1300},   // Before the search range
+		{Start: 4000.2, End: 4010}, // A pause in the sermon
+		{Start: 6317.4, End: 6400}, // After the service
+		{Start: 7000, End: 0},
+	}
+
+	end, err := EndFromSilences(silences, r, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end.String() != "01:45:18" {
+		t.Errorf("end = %s, want 01:45:18", end)
+	}
+
+	end, err = EndFromSilences(silences[:2:2], r, 5)
+	if err != nil || end.String() != "01:06:41" {
+		t.Errorf("short silence: end = %s, %v; want 01:06:41", end, err)
+	}
+
+	end, err = EndFromSilences([]Silence{{Start: 7000}}, r, 30)
+	if err != nil || end.TotalSeconds() != 7000 {
+		t.Errorf("silence to the end: end = %s, %v", end, err)
+	}
+
+	if _, err := EndFromSilences(silences[:2], r, 30); !errors.Is(err, ErrEndNotFound) {
+		t.Errorf("error = %v, want ErrEndNotFound", err)
+	}
+}
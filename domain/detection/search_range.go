@@ -0,0 +1,64 @@
+package detection
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Adaptive search range defaults
+const (
+	// MinAdaptiveStarts is how many past detected starts are needed before
+	// the search range is narrowed
+	MinAdaptiveStarts = 4
+
+	// DefaultAdaptiveMarginSeconds is added either side of the past starts
+	DefaultAdaptiveMarginSeconds = 120
+)
+
+// SearchRange is the part of a recording searched for the cross lighting up
+type SearchRange struct {
+	StartSeconds int
+	EndSeconds   int
+}
+
+// String formats the range as MM:SS-MM:SS
+func (r SearchRange) String() string {
+	return fmt.Sprintf("%s-%s", minutesSeconds(r.StartSeconds), minutesSeconds(r.EndSeconds))
+}
+
+// Contains reports whether t is inside the range, excluding its start: a
+// cross already lit at the start may have lit up before it
+func (r SearchRange) Contains(t int) bool {
+	return t > r.StartSeconds && t <= r.EndSeconds
+}
+
+// AdaptSearchRange narrows configured to span past detected starts (seconds
+// into the recording) plus margin either side. It returns false, leaving the
+// configured range in use, when there are fewer than MinAdaptiveStarts starts
+// or the result would be no narrower.
+func AdaptSearchRange(configured SearchRange, starts []int, margin int) (SearchRange, bool) {
+	if len(starts) < MinAdaptiveStarts {
+		return configured, false
+	}
+	if margin <= 0 {
+		margin = DefaultAdaptiveMarginSeconds
+	}
+	sorted := append([]int(nil), starts...)
+	sort.Ints(sorted)
+
+	adapted := SearchRange{
+		StartSeconds: max(sorted[0]-margin, configured.StartSeconds),
+		EndSeconds:   min(sorted[len(sorted)-1]+margin, configured.EndSeconds),
+	}
+	if adapted.EndSeconds <= adapted.StartSeconds {
+		return configured, false
+	}
+	if adapted == configured {
+		return configured, false
+	}
+	return adapted, true
+}
+
+func minutesSeconds(seconds int) string {
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,49 @@
+package detection
+
+import "testing"
+
+func TestAdaptSearchRange(t *testing.T) {
+	configured := SearchRange{StartSeconds: 0, EndSeconds: 30 * 60}
+
+	// Starts between 7 and 12 minutes in
+	starts := []int{9 * 60, 7 * 60, 12 * 60, 10*60 + 30}
+	got, ok := AdaptSearchRange(configured, starts, 60)
+	if !ok {
+		t.Fatal("expected the range to be narrowed")
+	}
+	if want := (SearchRange{StartSeconds: 6 * 60, EndSeconds: 13 * 60}); got != want {
+		t.Errorf("AdaptSearchRange() = %v, want %v", got, want)
+	}
+	if got.String() != "06:00-13:00" {
+		t.Errorf("String() = %q", got.String())
+	}
+}
+
+func TestAdaptSearchRange_KeepsConfiguredRange(t *testing.T) {
+	configured := SearchRange{StartSeconds: 5 * 60, EndSeconds: 15 * 60}
+
+	if _, ok := AdaptSearchRange(configured, []int{600, 620, 640}, 60); ok {
+		t.Error("expected too few starts to leave the range alone")
+	}
+
+	// Clamped to the configured range, which it already fills
+	got, ok := AdaptSearchRange(configured, []int{4 * 60, 8 * 60, 10 * 60, 16 * 60}, 60)
+	if ok || got != configured {
+		t.Errorf("AdaptSearchRange() = %v, %v; want the configured range", got, ok)
+	}
+
+	// Default margin
+	got, ok = AdaptSearchRange(configured, []int{600, 600, 600, 600}, 0)
+	if !ok || got != (SearchRange{StartSeconds: 480, EndSeconds: 720}) {
+		t.Errorf("AdaptSearchRange() with the default margin = %v, %v", got, ok)
+	}
+}
+
+func TestSearchRange_Contains(t *testing.T) {
+	r := SearchRange{StartSeconds: 360, EndSeconds: 780}
+	for at, want := range map[int]bool{360: false, 361: true, 780: true, 781: false} {
+		if got := r.Contains(at); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", at, got, want)
+		}
+	}
+}
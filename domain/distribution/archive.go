@@ -0,0 +1,51 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+)
+
+// Archive is a second copy of stored files. Cleanup deletes files the archive
+// already holds before touching the only copy of anything.
+type Archive interface {
+	// Contains reports whether the archive holds a copy of f
+	Contains(ctx context.Context, f FileInfo) (bool, error)
+}
+
+// PublishedStater is a Publisher that can report what it already holds
+type PublishedStater interface {
+	// Stat returns the size of remoteName; found is false if it does not exist
+	Stat(ctx context.Context, remoteName string) (size int64, found bool, err error)
+}
+
+// MirrorArchive treats the alternate download server as an archive. A file
+// counts as archived when a published file of the same name and size exists
+// alongside its checksum file. It returns nil if the publisher cannot look
+// up published files.
+func MirrorArchive(p Publisher) Archive {
+	s, ok := p.(PublishedStater)
+	if !ok {
+		return nil
+	}
+	return mirrorArchive{stater: s}
+}
+
+type mirrorArchive struct {
+	stater PublishedStater
+}
+
+// Contains implements Archive
+func (a mirrorArchive) Contains(ctx context.Context, f FileInfo) (bool, error) {
+	size, found, err := a.stater.Stat(ctx, f.Name)
+	if err != nil {
+		return false, fmt.Errorf("failed to check mirror for %s: %w", f.Name, err)
+	}
+	if !found || size != f.Size {
+		return false, nil
+	}
+	_, found, err = a.stater.Stat(ctx, f.Name+ChecksumSuffix)
+	if err != nil {
+		return false, fmt.Errorf("failed to check mirror for %s: %w", f.Name+ChecksumSuffix, err)
+	}
+	return found, nil
+}
@@ -0,0 +1,78 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// statPublisher is a Publisher whose published files are a name-to-size map
+type statPublisher struct {
+	files map[string]int64
+	err   error
+}
+
+func (p *statPublisher) Put(ctx context.Context, remoteName string, r io.Reader, size int64) error {
+	return nil
+}
+
+func (p *statPublisher) URL(remoteName string) string { return remoteName }
+
+func (p *statPublisher) Stat(ctx context.Context, remoteName string) (int64, bool, error) {
+	if p.err != nil {
+		return 0, false, p.err
+	}
+	size, ok := p.files[remoteName]
+	return size, ok, nil
+}
+
+// putOnlyPublisher cannot look up what it published
+type putOnlyPublisher struct{}
+
+func (putOnlyPublisher) Put(ctx context.Context, remoteName string, r io.Reader, size int64) error {
+	return nil
+}
+
+func (putOnlyPublisher) URL(remoteName string) string { return remoteName }
+
+func TestMirrorArchive_Unsupported(t *testing.T) {
+	if a := MirrorArchive(putOnlyPublisher{}); a != nil {
+		t.Errorf("MirrorArchive() = %v, want nil for a publisher without Stat", a)
+	}
+}
+
+func TestMirrorArchive_Contains(t *testing.T) {
+	file := FileInfo{ID: "1", Name: "2025-12-28.mp4", Size: 100}
+
+	tests := []struct {
+		name  string
+		files map[string]int64
+		want  bool
+	}{
+		{name: "same name, size and checksum file", files: map[string]int64{"2025-12-28.mp4": 100, "2025-12-28.mp4.sha256": 82}, want: true},
+		{name: "not published", files: map[string]int64{}},
+		{name: "different size", files: map[string]int64{"2025-12-28.mp4": 99, "2025-12-28.mp4.sha256": 82}},
+		{name: "no checksum file", files: map[string]int64{"2025-12-28.mp4": 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MirrorArchive(&statPublisher{files: tt.files}).Contains(context.Background(), file)
+			if err != nil {
+				t.Fatalf("Contains() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMirrorArchive_ContainsError(t *testing.T) {
+	boom := errors.New("connection refused")
+	_, err := MirrorArchive(&statPublisher{err: boom}).Contains(context.Background(), FileInfo{Name: "a.mp4"})
+	if !errors.Is(err, boom) {
+		t.Errorf("Contains() error = %v, want %v", err, boom)
+	}
+}
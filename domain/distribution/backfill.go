@@ -0,0 +1,85 @@
+package distribution
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// videoExtensions and audioExtensions classify files whose MIME type Drive
+// did not recognise
+var (
+	videoExtensions = map[string]bool{".mp4": true, ".m4v": true, ".mov": true, ".mkv": true}
+	audioExtensions = map[string]bool{".mp3": true, ".m4a": true, ".aac": true, ".wav": true}
+)
+
+// InferProperties returns the app properties that can be worked out from a
+// file's name and MIME type: the service date from a leading YYYY-MM-DD and
+// the media type. It returns nil when neither can be inferred.
+func InferProperties(name, mimeType string) map[string]string {
+	props := make(map[string]string)
+	if len(name) >= 10 {
+		if _, err := time.Parse("2006-01-02", name[:10]); err == nil {
+			props[PropertyServiceDate] = name[:10]
+		}
+	}
+	if t := inferMediaType(name, mimeType); t != "" {
+		props[PropertyMediaType] = t
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return props
+}
+
+func inferMediaType(name, mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "video/"):
+		return MediaTypeVideo
+	case strings.HasPrefix(mimeType, "audio/"):
+		return MediaTypeAudio
+	}
+	ext := strings.ToLower(path.Ext(name))
+	switch {
+	case videoExtensions[ext]:
+		return MediaTypeVideo
+	case audioExtensions[ext]:
+		return MediaTypeAudio
+	}
+	return ""
+}
+
+// MissingProperties returns the inferred properties a file does not carry
+// yet. Properties already set are never overwritten.
+func MissingProperties(f FileInfo) map[string]string {
+	missing := make(map[string]string)
+	for k, v := range InferProperties(f.Name, f.MimeType) {
+		if _, ok := f.AppProperties[k]; !ok {
+			missing[k] = v
+		}
+	}
+	return missing
+}
+
+// BackfillResult reports a metadata backfill over the Services folder
+type BackfillResult struct {
+	Tagged        []TaggedFile
+	AlreadyTagged int
+	// Unclassified lists files whose service date or media type could not
+	// be worked out from the name; they are left untouched
+	Unclassified []FileInfo
+	Failed       []FailedTag
+}
+
+// TaggedFile is a file that was given (or, in a dry run, would be given)
+// new app properties
+type TaggedFile struct {
+	Name       string
+	Properties map[string]string
+}
+
+// FailedTag is a file whose properties could not be written
+type FailedTag struct {
+	Name string
+	Err  error
+}
@@ -0,0 +1,49 @@
+package distribution
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		mimeType string
+		want     map[string]string
+	}{
+		{name: "video", fileName: "2024-03-10.mp4", mimeType: MimeTypeMP4,
+			want: map[string]string{PropertyServiceDate: "2024-03-10", PropertyMediaType: MediaTypeVideo}},
+		{name: "audio with a suffix", fileName: "2024-03-10 - Evening.mp3", mimeType: MimeTypeMP3,
+			want: map[string]string{PropertyServiceDate: "2024-03-10", PropertyMediaType: MediaTypeAudio}},
+		{name: "type from extension", fileName: "2024-03-10.MOV", mimeType: "application/octet-stream",
+			want: map[string]string{PropertyServiceDate: "2024-03-10", PropertyMediaType: MediaTypeVideo}},
+		{name: "no date", fileName: "Christmas Eve.mp4", mimeType: MimeTypeMP4,
+			want: map[string]string{PropertyMediaType: MediaTypeVideo}},
+		{name: "impossible date", fileName: "2024-13-40.mp3",
+			want: map[string]string{PropertyMediaType: MediaTypeAudio}},
+		{name: "nothing to infer", fileName: "notes.txt", mimeType: "text/plain", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferProperties(tt.fileName, tt.mimeType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InferProperties(%q, %q) = %v, want %v", tt.fileName, tt.mimeType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingProperties(t *testing.T) {
+	f := FileInfo{
+		Name:          "2024-03-10.mp4",
+		MimeType:      MimeTypeMP4,
+		AppProperties: map[string]string{PropertyServiceDate: "2024-03-09"},
+	}
+
+	got := MissingProperties(f)
+	want := map[string]string{PropertyMediaType: MediaTypeVideo}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingProperties() = %v, want %v (existing date must not be overwritten)", got, want)
+	}
+}
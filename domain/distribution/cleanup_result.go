@@ -1,13 +1,110 @@
 package distribution
 
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
 // CleanupResult contains information about files deleted during cleanup
 type CleanupResult struct {
 	DeletedFiles []DeletedFile
 	FreedBytes   int64
+	// Failed lists files whose deletion failed; cleanup moves on to newer files
+	Failed []FailedDeletion
+}
+
+// CleanupPlan lists the files a cleanup would delete to make room, in the
+// order it would delete them, so they can be approved first
+type CleanupPlan struct {
+	Files     []FileInfo
+	Shortfall int64           // Bytes missing before anything is deleted
+	Archived  map[string]bool // File IDs the archive holds a copy of
+}
+
+// Bytes returns the total size of the planned files
+func (p CleanupPlan) Bytes() int64 {
+	var total int64
+	for _, f := range p.Files {
+		total += f.Size
+	}
+	return total
 }
 
 // DeletedFile represents a file that was deleted
 type DeletedFile struct {
+	Name        string
+	Size        int64
+	ServiceDate time.Time // Zero when the file does not say
+	Archived    bool      // A copy was confirmed in the archive before deleting
+}
+
+// Date returns the service date as YYYY-MM-DD, or "" when it is not known
+func (f DeletedFile) Date() string {
+	if f.ServiceDate.IsZero() {
+		return ""
+	}
+	return f.ServiceDate.Format("2006-01-02")
+}
+
+// DeletedTable lays out deleted files as aligned name, service date and size
+// columns under a header, each line starting with indent
+func DeletedTable(files []DeletedFile, indent string) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%sName\tService date\tSize\n", indent)
+	for _, f := range files {
+		fmt.Fprintf(w, "%s%s\t%s\t%s\n", indent, f.Name, f.Date(), FormatSize(f.Size))
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// CleanupProgress is how far a batch of deletions has got, reported after
+// each file
+type CleanupProgress struct {
+	Done, Total int
+	File        DeletedFile // The file just deleted, or that failed
+	Err         error
+	FreedBytes  int64 // Freed by the batch so far
+	TotalBytes  int64 // Freed once every file in the batch is deleted
+	Elapsed     time.Duration
+}
+
+// ETA estimates how long the rest of the batch takes at the pace so far
+func (p CleanupProgress) ETA() time.Duration {
+	if p.Done == 0 || p.Done >= p.Total {
+		return 0
+	}
+	return p.Elapsed / time.Duration(p.Done) * time.Duration(p.Total-p.Done)
+}
+
+// String reports the file and the batch's progress, e.g.
+// "[2/12] Removed: 2025-11-01.mp4 (1.0 GB); 2.0 GB of 12.0 GB freed, about 1m0s left"
+func (p CleanupProgress) String() string {
+	var line strings.Builder
+	fmt.Fprintf(&line, "[%d/%d] ", p.Done, p.Total)
+	if p.Err != nil {
+		fmt.Fprintf(&line, "Failed: %s", p.File.Name)
+	} else {
+		note := ""
+		if p.File.Archived {
+			note = ", still on the mirror"
+		}
+		fmt.Fprintf(&line, "Removed: %s (%s%s)", p.File.Name, FormatSize(p.File.Size), note)
+	}
+	fmt.Fprintf(&line, "; %s of %s freed", FormatSize(p.FreedBytes), FormatSize(p.TotalBytes))
+	if eta := p.ETA().Round(time.Second); eta > 0 {
+		fmt.Fprintf(&line, ", about %s left", eta)
+	}
+	return line.String()
+}
+
+// FailedDeletion is a file that could not be deleted during cleanup
+type FailedDeletion struct {
 	Name string
 	Size int64
+	Err  error
 }
@@ -0,0 +1,54 @@
+package distribution
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeletedTable(t *testing.T) {
+	files := []DeletedFile{
+		{Name: "2025-11-01.mp4", Size: 1 << 30, ServiceDate: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "Christmas.mp4", Size: 512 << 20},
+	}
+	want := "  Name            Service date  Size\n" +
+		"  2025-11-01.mp4  2025-11-01    1.0 GB\n" +
+		"  Christmas.mp4                 512.0 MB\n"
+	if got := DeletedTable(files, "  "); got != want {
+		t.Errorf("DeletedTable() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCleanupProgress_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress CleanupProgress
+		want     string
+	}{
+		{
+			name: "with time left",
+			progress: CleanupProgress{Done: 2, Total: 5, File: DeletedFile{Name: "2025-11-08.mp4", Size: 1 << 30},
+				FreedBytes: 2 << 30, TotalBytes: 5 << 30, Elapsed: 10 * time.Second},
+			want: "[2/5] Removed: 2025-11-08.mp4 (1.0 GB); 2.0 GB of 5.0 GB freed, about 15s left",
+		},
+		{
+			name: "on the mirror and finished",
+			progress: CleanupProgress{Done: 1, Total: 1, File: DeletedFile{Name: "2025-11-01.mp4", Size: 1 << 30, Archived: true},
+				FreedBytes: 1 << 30, TotalBytes: 1 << 30, Elapsed: 3 * time.Second},
+			want: "[1/1] Removed: 2025-11-01.mp4 (1.0 GB, still on the mirror); 1.0 GB of 1.0 GB freed",
+		},
+		{
+			name: "failed",
+			progress: CleanupProgress{Done: 1, Total: 2, File: DeletedFile{Name: "2025-11-01.mp4", Size: 1 << 30},
+				Err: errors.New("forbidden"), TotalBytes: 2 << 30},
+			want: "[1/2] Failed: 2025-11-01.mp4; 0 B of 2.0 GB freed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.progress.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
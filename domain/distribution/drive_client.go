@@ -2,6 +2,7 @@ package distribution
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -15,6 +16,10 @@ type DriveClient interface {
 	// Returns nil, nil if no file is found (not an error)
 	FindFileByName(ctx context.Context, folderID, fileName string) (*FileInfo, error)
 
+	// FindFilesByProperty finds files in a folder tagged with the given appProperty
+	// Returns an empty slice if no files match (not an error)
+	FindFilesByProperty(ctx context.Context, folderID, key, value string) ([]FileInfo, error)
+
 	// GetStorageQuota returns the current storage quota information
 	GetStorageQuota(ctx context.Context) (*StorageInfo, error)
 
@@ -37,11 +42,39 @@ type DriveClient interface {
 	UploadAndShare(ctx context.Context, req UploadRequest) (*UploadResult, error)
 }
 
+// ErrInsufficientScope is returned when the Drive access granted at sign-in
+// does not cover an operation
+var ErrInsufficientScope = errors.New("insufficient Drive access")
+
+// AppFileScoped is implemented by clients that may only see the files this
+// app uploaded, such as Drive with the narrower drive.file scope
+type AppFileScoped interface {
+	AppFilesOnly() bool
+}
+
+// SeesAppFilesOnly reports whether client only sees files this app uploaded,
+// so listings, usage and cleanup leave every other file out
+func SeesAppFilesOnly(client DriveClient) bool {
+	s, ok := client.(AppFileScoped)
+	return ok && s.AppFilesOnly()
+}
+
+// ErrTaggingUnsupported is returned when a client cannot change the app
+// properties of a file it already stores
+var ErrTaggingUnsupported = errors.New("drive client cannot tag existing files")
+
+// PropertyTagger sets app properties on an existing file. Keys not given are
+// left as they are.
+type PropertyTagger interface {
+	SetAppProperties(ctx context.Context, fileID string, props map[string]string) error
+}
+
 // FileInfo represents metadata about a file in Google Drive
 type FileInfo struct {
-	ID          string
-	Name        string
-	MimeType    string
-	Size        int64
-	CreatedTime time.Time
+	ID            string
+	Name          string
+	MimeType      string
+	Size          int64
+	CreatedTime   time.Time
+	AppProperties map[string]string // Private app metadata (e.g., service_date)
 }
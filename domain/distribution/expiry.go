@@ -0,0 +1,74 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// App properties that end the life of a privately shared file
+const (
+	PropertyRevokeAfter = "revoke_after" // YYYY-MM-DD the readers' access ends
+	PropertyDeleteAfter = "delete_after" // YYYY-MM-DD the file is deleted
+)
+
+// ErrRevokeUnsupported is returned when a client cannot take away people's
+// access to a file
+var ErrRevokeUnsupported = errors.New("drive client cannot revoke people's access")
+
+// ReaderRevoker removes the read access given to people on a file. It
+// returns the addresses that lost access; none when no one had it.
+type ReaderRevoker interface {
+	RevokeReaders(ctx context.Context, fileID string) ([]string, error)
+}
+
+// Expiry is when a privately shared file's access ends and when it is
+// deleted; a zero time never comes
+type Expiry struct {
+	RevokeAfter time.Time
+	DeleteAfter time.Time
+}
+
+// IsZero reports whether the file never expires
+func (e Expiry) IsZero() bool {
+	return e.RevokeAfter.IsZero() && e.DeleteAfter.IsZero()
+}
+
+// Properties returns the app properties that record the expiry
+func (e Expiry) Properties() map[string]string {
+	props := make(map[string]string)
+	if !e.RevokeAfter.IsZero() {
+		props[PropertyRevokeAfter] = e.RevokeAfter.Format("2006-01-02")
+	}
+	if !e.DeleteAfter.IsZero() {
+		props[PropertyDeleteAfter] = e.DeleteAfter.Format("2006-01-02")
+	}
+	return props
+}
+
+// ExpiryOf reads a file's expiry from its app properties. Dates that do not
+// parse are ignored.
+func ExpiryOf(f FileInfo) Expiry {
+	var e Expiry
+	e.RevokeAfter, _ = time.Parse("2006-01-02", f.AppProperties[PropertyRevokeAfter])
+	e.DeleteAfter, _ = time.Parse("2006-01-02", f.AppProperties[PropertyDeleteAfter])
+	return e
+}
+
+// RevokeDue reports whether the readers' access should have ended by now
+func (e Expiry) RevokeDue(now time.Time) bool {
+	return due(e.RevokeAfter, now)
+}
+
+// DeleteDue reports whether the file should have been deleted by now
+func (e Expiry) DeleteDue(now time.Time) bool {
+	return due(e.DeleteAfter, now)
+}
+
+// due compares calendar dates, so a file expiring today is due all day
+func due(date, now time.Time) bool {
+	if date.IsZero() {
+		return false
+	}
+	return now.Format("2006-01-02") >= date.Format("2006-01-02")
+}
@@ -0,0 +1,39 @@
+package distribution
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiry_RoundTrip(t *testing.T) {
+	e := Expiry{
+		RevokeAfter: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		DeleteAfter: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	props := e.Properties()
+	if props[PropertyRevokeAfter] != "2026-02-01" || props[PropertyDeleteAfter] != "2026-03-01" {
+		t.Fatalf("Properties() = %v", props)
+	}
+	if got := ExpiryOf(FileInfo{AppProperties: props}); got != e {
+		t.Errorf("ExpiryOf() = %+v, want %+v", got, e)
+	}
+	if len((Expiry{}).Properties()) != 0 || !ExpiryOf(FileInfo{}).IsZero() {
+		t.Error("a file without an expiry should have no expiry properties")
+	}
+}
+
+func TestExpiry_Due(t *testing.T) {
+	e := ExpiryOf(FileInfo{AppProperties: map[string]string{
+		PropertyRevokeAfter: "2026-02-01",
+		PropertyDeleteAfter: "not a date",
+	}})
+	if e.RevokeDue(time.Date(2026, 1, 31, 23, 59, 0, 0, time.UTC)) {
+		t.Error("access should last until the revoke date")
+	}
+	if !e.RevokeDue(time.Date(2026, 2, 1, 0, 1, 0, 0, time.UTC)) {
+		t.Error("access should end on the revoke date")
+	}
+	if e.DeleteDue(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("a date that does not parse should never be due")
+	}
+}
@@ -0,0 +1,43 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Errors from checking the folder uploads go to
+var (
+	ErrFolderNotFound    = errors.New("drive folder not found")
+	ErrNotAFolder        = errors.New("not a drive folder")
+	ErrFolderNotWritable = errors.New("drive folder is read-only for this account")
+)
+
+// ErrFolderLookupUnsupported is returned when a client cannot look up a
+// folder's details
+var ErrFolderLookupUnsupported = errors.New("drive client cannot look up folders")
+
+// FolderDetails is what Drive says about a folder ID
+type FolderDetails struct {
+	ID             string
+	Name           string
+	MimeType       string
+	CanAddChildren bool // Whether the account may put files in it
+}
+
+// Check reports why uploads cannot go into the folder, or nil if they can
+func (f FolderDetails) Check() error {
+	if f.MimeType != MimeTypeFolder {
+		return fmt.Errorf("%w: %s (%s) is a %s", ErrNotAFolder, f.Name, f.ID, f.MimeType)
+	}
+	if !f.CanAddChildren {
+		return fmt.Errorf("%w: %s (%s)", ErrFolderNotWritable, f.Name, f.ID)
+	}
+	return nil
+}
+
+// FolderInspector looks up a folder by ID. A missing or hidden folder is
+// ErrFolderNotFound.
+type FolderInspector interface {
+	FolderDetails(ctx context.Context, folderID string) (*FolderDetails, error)
+}
@@ -0,0 +1,29 @@
+package distribution
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFolderDetails_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		folder  FolderDetails
+		wantErr error
+	}{
+		{"writable folder", FolderDetails{ID: "f", Name: "Services", MimeType: MimeTypeFolder, CanAddChildren: true}, nil},
+		{"file", FolderDetails{ID: "f", Name: "notes.txt", MimeType: "text/plain", CanAddChildren: true}, ErrNotAFolder},
+		{"read-only folder", FolderDetails{ID: "f", Name: "Services", MimeType: MimeTypeFolder}, ErrFolderNotWritable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.folder.Check()
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Check() = %v, want nil", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
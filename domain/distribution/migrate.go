@@ -0,0 +1,166 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRenameUnsupported is returned when a client cannot rename a file it stores
+var ErrRenameUnsupported = errors.New("drive client cannot rename files")
+
+// FileRenamer renames an existing file
+type FileRenamer interface {
+	RenameFile(ctx context.Context, fileID, newName string) error
+}
+
+// canonicalExtensions are the outputs this tool uploads, named YYYY-MM-DD.ext
+var canonicalExtensions = map[string]bool{".mp4": true, ".mp3": true}
+
+var months = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// Date layouts found in names given by hand, tried in order
+var (
+	yearFirst  = regexp.MustCompile(`(?:^|\D)(\d{4})[-_. ](\d{1,2})[-_. ](\d{1,2})(?:\D|$)`)
+	compact    = regexp.MustCompile(`(?:^|\D)(\d{4})(\d{2})(\d{2})(?:\D|$)`)
+	monthFirst = regexp.MustCompile(`(?:^|\D)(\d{1,2})[-_. ](\d{1,2})[-_. ](\d{4}|\d{2})(?:\D|$)`)
+	monthName  = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?[ _-]*(\d{1,2})(?:st|nd|rd|th)?,?[ _-]*(\d{4})\b`)
+	dayName    = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?[ _-]*(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?,?[ _-]*(\d{4})\b`)
+)
+
+// LegacyServiceDate finds the service date in a file name given by hand
+// before this tool named uploads, e.g. "2025_12_28 Service.mp4", "20251228.mp3",
+// "Service 12-28-2025.mp4" or "Dec 28, 2025.mp4". Numeric dates that do not
+// start with the year are read month first.
+func LegacyServiceDate(name string) (time.Time, bool) {
+	base := strings.TrimSuffix(name, path.Ext(name))
+	if m := yearFirst.FindStringSubmatch(base); m != nil {
+		return dateOf(m[1], m[2], m[3])
+	}
+	if m := compact.FindStringSubmatch(base); m != nil {
+		return dateOf(m[1], m[2], m[3])
+	}
+	if m := monthName.FindStringSubmatch(base); m != nil {
+		return dateOf(m[3], monthNumber(m[1]), m[2])
+	}
+	if m := dayName.FindStringSubmatch(base); m != nil {
+		return dateOf(m[3], monthNumber(m[2]), m[1])
+	}
+	if m := monthFirst.FindStringSubmatch(base); m != nil {
+		year := m[3]
+		if len(year) == 2 {
+			year = "20" + year
+		}
+		return dateOf(year, m[1], m[2])
+	}
+	return time.Time{}, false
+}
+
+func monthNumber(name string) string {
+	return strconv.Itoa(int(months[strings.ToLower(name)]))
+}
+
+// dateOf builds a date, rejecting ones that do not exist such as 2025-02-30
+func dateOf(year, month, day string) (time.Time, bool) {
+	y, _ := strconv.Atoi(year)
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if t.Year() != y || int(t.Month()) != m || t.Day() != d {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// MigrationFile is a file the migration recognised, with its canonical name
+type MigrationFile struct {
+	File        FileInfo
+	NewName     string
+	ServiceDate time.Time
+}
+
+// Renamed reports whether the file needs a new name
+func (f MigrationFile) Renamed() bool {
+	return f.File.Name != f.NewName
+}
+
+// MigrationSkip is a dated file the migration leaves alone, and why
+type MigrationSkip struct {
+	File   FileInfo
+	Reason string
+}
+
+// MigrationPlan is how the files in a folder map onto the canonical
+// YYYY-MM-DD.mp4 and YYYY-MM-DD.mp3 names
+type MigrationPlan struct {
+	Canonical    []MigrationFile // Already named canonically
+	Renames      []MigrationFile
+	Skipped      []MigrationSkip
+	Unrecognized []FileInfo // No service date in the name
+}
+
+// PlanMigration works out the canonical name of each file. A file is skipped
+// rather than renamed when it is not an MP4 or MP3, or when its canonical name
+// is taken or wanted by another file. Folders are ignored.
+func PlanMigration(files []FileInfo) MigrationPlan {
+	var plan MigrationPlan
+	taken := make(map[string]bool)
+	wanted := make(map[string][]MigrationFile)
+	var order []string
+	for _, f := range files {
+		if f.MimeType == MimeTypeFolder {
+			continue
+		}
+		date, ok := LegacyServiceDate(f.Name)
+		if !ok {
+			plan.Unrecognized = append(plan.Unrecognized, f)
+			continue
+		}
+		ext := strings.ToLower(path.Ext(f.Name))
+		if !canonicalExtensions[ext] {
+			plan.Skipped = append(plan.Skipped, MigrationSkip{File: f, Reason: "only .mp4 and .mp3 files are renamed"})
+			continue
+		}
+		mf := MigrationFile{File: f, NewName: date.Format("2006-01-02") + ext, ServiceDate: date}
+		if !mf.Renamed() {
+			taken[mf.NewName] = true
+			plan.Canonical = append(plan.Canonical, mf)
+			continue
+		}
+		if _, ok := wanted[mf.NewName]; !ok {
+			order = append(order, mf.NewName)
+		}
+		wanted[mf.NewName] = append(wanted[mf.NewName], mf)
+	}
+
+	for _, name := range order {
+		candidates := wanted[name]
+		switch {
+		case taken[name]:
+			for _, mf := range candidates {
+				plan.Skipped = append(plan.Skipped, MigrationSkip{File: mf.File, Reason: name + " already exists"})
+			}
+		case len(candidates) > 1:
+			for _, mf := range candidates {
+				plan.Skipped = append(plan.Skipped, MigrationSkip{File: mf.File, Reason: fmt.Sprintf("%d files would be named %s", len(candidates), name)})
+			}
+		default:
+			plan.Renames = append(plan.Renames, candidates[0])
+		}
+	}
+
+	sort.SliceStable(plan.Renames, func(i, j int) bool {
+		return plan.Renames[i].NewName < plan.Renames[j].NewName
+	})
+	return plan
+}
@@ -0,0 +1,93 @@
+package distribution
+
+import (
+	"testing"
+)
+
+func TestLegacyServiceDate(t *testing.T) {
+	tests := []struct {
+		name string
+		want string // Empty when no date should be found
+	}{
+		{"2025-12-28.mp4", "2025-12-28"},
+		{"2025-12-28 10-06-16.mp4", "2025-12-28"},
+		{"2025_12_28 Sunday Service.mp4", "2025-12-28"},
+		{"Service 2025.1.5.mp3", "2025-01-05"},
+		{"20251228.mp3", "2025-12-28"},
+		{"Service 12-28-2025.mp4", "2025-12-28"},
+		{"12.28.25 Divine Service.mp3", "2025-12-28"},
+		{"Dec 28, 2025.mp4", "2025-12-28"},
+		{"December 28th 2025 - Evening.mp4", "2025-12-28"},
+		{"28 December 2025.mp3", "2025-12-28"},
+		{"2025-02-30.mp4", ""},
+		{"Sermon notes.pdf", ""},
+		{"Christmas service.mp4", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LegacyServiceDate(tt.name)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("LegacyServiceDate(%q) = %s, want no date", tt.name, got.Format("2006-01-02"))
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("LegacyServiceDate(%q) found no date, want %s", tt.name, tt.want)
+			}
+			if d := got.Format("2006-01-02"); d != tt.want {
+				t.Errorf("LegacyServiceDate(%q) = %s, want %s", tt.name, d, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanMigration(t *testing.T) {
+	files := []FileInfo{
+		{ID: "1", Name: "2025-12-21.mp4"},
+		{ID: "2", Name: "Service 12-28-2025.mp4"},
+		{ID: "3", Name: "Dec 28 2025.mp3"},
+		{ID: "4", Name: "2025_12_21 Sunday.mp4"},
+		{ID: "5", Name: "01-04-2026 AM.mp4"},
+		{ID: "6", Name: "2026-01-04 PM.mp4"},
+		{ID: "7", Name: "2025-12-14.mov"},
+		{ID: "8", Name: "Bulletin.pdf"},
+		{ID: "9", Name: "2024", MimeType: MimeTypeFolder},
+	}
+
+	plan := PlanMigration(files)
+
+	if len(plan.Canonical) != 1 || plan.Canonical[0].File.ID != "1" {
+		t.Errorf("Canonical = %+v, want only 2025-12-21.mp4", plan.Canonical)
+	}
+	var renames []string
+	for _, r := range plan.Renames {
+		renames = append(renames, r.File.Name+" -> "+r.NewName)
+	}
+	want := []string{"Dec 28 2025.mp3 -> 2025-12-28.mp3", "Service 12-28-2025.mp4 -> 2025-12-28.mp4"}
+	if len(renames) != len(want) {
+		t.Fatalf("Renames = %v, want %v", renames, want)
+	}
+	for i := range want {
+		if renames[i] != want[i] {
+			t.Errorf("Renames[%d] = %q, want %q", i, renames[i], want[i])
+		}
+	}
+
+	reasons := make(map[string]string)
+	for _, s := range plan.Skipped {
+		reasons[s.File.ID] = s.Reason
+	}
+	if reasons["4"] != "2025-12-21.mp4 already exists" {
+		t.Errorf("file 4 reason = %q", reasons["4"])
+	}
+	if reasons["5"] != "2 files would be named 2026-01-04.mp4" || reasons["6"] != reasons["5"] {
+		t.Errorf("files 5 and 6 reasons = %q, %q", reasons["5"], reasons["6"])
+	}
+	if reasons["7"] != "only .mp4 and .mp3 files are renamed" {
+		t.Errorf("file 7 reason = %q", reasons["7"])
+	}
+	if len(plan.Unrecognized) != 1 || plan.Unrecognized[0].ID != "8" {
+		t.Errorf("Unrecognized = %+v, want only Bulletin.pdf", plan.Unrecognized)
+	}
+}
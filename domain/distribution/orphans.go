@@ -0,0 +1,79 @@
+package distribution
+
+import (
+	"context"
+	"regexp"
+)
+
+// FolderMimeType is the MIME type Drive gives folders
+const FolderMimeType = "application/vnd.google-apps.folder"
+
+// knownFileName matches what this tool uploads: trimmed videos and MP3s named
+// by service date (with an optional -vN version), and untrimmed OBS recordings
+var knownFileName = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(-v\d+)?\.(mp4|mp3)$|^\d{4}-\d{2}-\d{2} \d{2}-\d{2}-\d{2}\.mp4$`)
+
+// KnownFileName reports whether name follows one of the naming patterns this
+// tool uploads with
+func KnownFileName(name string) bool {
+	return knownFileName.MatchString(name)
+}
+
+// TrashLister lists the files of a folder that are in the trash
+type TrashLister interface {
+	ListTrashed(ctx context.Context, folderID string) ([]FileInfo, error)
+}
+
+// PublicLister lists the files of a folder that anyone with the link can open
+type PublicLister interface {
+	ListPublic(ctx context.Context, folderID string) ([]FileInfo, error)
+}
+
+// Orphan kinds
+const (
+	OrphanTrashed      = "trashed"      // In the trash, still using storage
+	OrphanUnreferenced = "unreferenced" // Shared publicly but in no history entry
+	OrphanUnknownName  = "unknown-name" // Named unlike anything this tool uploads
+)
+
+// Recommendations for an orphan
+const (
+	RecommendDelete = "delete"
+	RecommendKeep   = "keep"
+	RecommendReview = "review"
+)
+
+// Orphan is a Services folder file that looks forgotten, with what to do about it
+type Orphan struct {
+	File           FileInfo
+	Kind           string
+	Recommendation string
+	Reason         string
+}
+
+// OrphanReport is the result of looking for orphans in the Services folder
+type OrphanReport struct {
+	Orphans []Orphan
+
+	// Skipped names checks the storage could not run, with why
+	Skipped []string
+}
+
+// OfKind returns the orphans of one kind, keeping their order
+func (r *OrphanReport) OfKind(kind string) []Orphan {
+	var matched []Orphan
+	for _, o := range r.Orphans {
+		if o.Kind == kind {
+			matched = append(matched, o)
+		}
+	}
+	return matched
+}
+
+// TrashedBytes is the storage the trashed orphans still use
+func (r *OrphanReport) TrashedBytes() int64 {
+	var total int64
+	for _, o := range r.OfKind(OrphanTrashed) {
+		total += o.File.Size
+	}
+	return total
+}
@@ -0,0 +1,38 @@
+package distribution
+
+import "testing"
+
+func TestKnownFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"2025-12-28.mp4", true},
+		{"2025-12-28.mp3", true},
+		{"2025-12-28-v2.mp3", true},
+		{"2025-12-28 10-06-16.mp4", true},
+		{"2025-12-28 10-06-16.mp3", false},
+		{"Copy of 2025-12-28.mp4", false},
+		{"2025-12-28.wav", false},
+		{"notes.txt", false},
+	}
+	for _, tt := range tests {
+		if got := KnownFileName(tt.name); got != tt.want {
+			t.Errorf("KnownFileName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOrphanReport_TrashedBytes(t *testing.T) {
+	r := &OrphanReport{Orphans: []Orphan{
+		{File: FileInfo{Size: 100}, Kind: OrphanTrashed},
+		{File: FileInfo{Size: 50}, Kind: OrphanUnknownName},
+		{File: FileInfo{Size: 25}, Kind: OrphanTrashed},
+	}}
+	if got := r.TrashedBytes(); got != 125 {
+		t.Errorf("TrashedBytes() = %d, want 125", got)
+	}
+	if got := len(r.OfKind(OrphanTrashed)); got != 2 {
+		t.Errorf("OfKind(trashed) returned %d orphans, want 2", got)
+	}
+}
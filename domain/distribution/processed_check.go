@@ -0,0 +1,27 @@
+package distribution
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Strategies for detecting whether a service has already been uploaded
+const (
+	// ProcessedCheckMetadata queries the service_date app property, falling back
+	// to filename matching for files uploaded before tagging was introduced
+	ProcessedCheckMetadata = "metadata"
+	// ProcessedCheckName matches only on the YYYY-MM-DD.mp4/.mp3 filenames
+	ProcessedCheckName = "name"
+)
+
+// ParseProcessedCheck normalizes a processed check strategy; "" is
+// ProcessedCheckMetadata
+func ParseProcessedCheck(s string) (string, error) {
+	switch strategy := strings.ToLower(strings.TrimSpace(s)); strategy {
+	case "":
+		return ProcessedCheckMetadata, nil
+	case ProcessedCheckMetadata, ProcessedCheckName:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown processed check strategy %q (use %q or %q)", s, ProcessedCheckMetadata, ProcessedCheckName)
+}
@@ -0,0 +1,14 @@
+package distribution
+
+import "testing"
+
+func TestParseProcessedCheck(t *testing.T) {
+	for in, want := range map[string]string{"": ProcessedCheckMetadata, "Metadata": ProcessedCheckMetadata, " name ": ProcessedCheckName} {
+		if got, err := ParseProcessedCheck(in); err != nil || got != want {
+			t.Errorf("ParseProcessedCheck(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseProcessedCheck("filename"); err == nil {
+		t.Error("expected an unknown strategy to be rejected")
+	}
+}
@@ -0,0 +1,32 @@
+package distribution
+
+import (
+	"context"
+	"io"
+)
+
+// Publisher copies files to an alternate download server for recipients who
+// cannot reach Google Drive
+type Publisher interface {
+	// Put writes size bytes from r to remoteName on the server, replacing any existing file
+	Put(ctx context.Context, remoteName string, r io.Reader, size int64) error
+
+	// URL returns the download URL recipients use for remoteName
+	URL(remoteName string) string
+}
+
+// PublishedFile describes a file copied to the alternate download server
+type PublishedFile struct {
+	Name   string // Remote filename
+	URL    string // Download URL
+	SHA256 string // Hex digest, also published as Name + ChecksumSuffix
+}
+
+// ChecksumSuffix is appended to a published filename for its sha256sum-format checksum file
+const ChecksumSuffix = ".sha256"
+
+// Alternate download providers
+const (
+	ProviderSFTP   = "sftp"
+	ProviderWebDAV = "webdav"
+)
@@ -0,0 +1,58 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Revision is one version of a Drive file's content
+type Revision struct {
+	ID           string
+	ModifiedTime time.Time
+	Size         int64
+	MD5Checksum  string
+	// KeepForever pins the revision; Drive otherwise purges old revisions
+	// after 30 days or 100 revisions
+	KeepForever bool
+}
+
+// ErrRevisionsUnsupported is returned when a Drive client cannot manage file revisions
+var ErrRevisionsUnsupported = errors.New("drive client cannot manage file revisions")
+
+// RevisionManager lists, pins and restores the revisions of a Drive file
+type RevisionManager interface {
+	// ListRevisions returns the file's revisions, oldest first
+	ListRevisions(ctx context.Context, fileID string) ([]Revision, error)
+	// KeepRevision pins or unpins a revision
+	KeepRevision(ctx context.Context, fileID, revisionID string, keep bool) error
+	// RestoreRevision makes an old revision's content the file's current content
+	RestoreRevision(ctx context.Context, fileID, revisionID, mimeType string) (*UploadResult, error)
+}
+
+// SortRevisions orders revisions oldest first
+func SortRevisions(revisions []Revision) {
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].ModifiedTime.Before(revisions[j].ModifiedTime)
+	})
+}
+
+// PinsToRelease returns the pinned revisions beyond the newest keep, oldest
+// first, so only keep revisions stay pinned
+func PinsToRelease(revisions []Revision, keep int) []Revision {
+	var pinned []Revision
+	for _, r := range revisions {
+		if r.KeepForever {
+			pinned = append(pinned, r)
+		}
+	}
+	SortRevisions(pinned)
+	if keep < 0 {
+		keep = 0
+	}
+	if len(pinned) <= keep {
+		return nil
+	}
+	return pinned[:len(pinned)-keep]
+}
@@ -0,0 +1,40 @@
+package distribution
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPinsToRelease(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 12, d, 12, 0, 0, 0, time.UTC) }
+	revisions := []Revision{
+		{ID: "r4", ModifiedTime: day(4), KeepForever: true},
+		{ID: "r1", ModifiedTime: day(1), KeepForever: true},
+		{ID: "r2", ModifiedTime: day(2)},
+		{ID: "r3", ModifiedTime: day(3), KeepForever: true},
+		{ID: "r5", ModifiedTime: day(5)},
+	}
+
+	tests := []struct {
+		keep int
+		want []string
+	}{
+		{keep: 3, want: nil},
+		{keep: 2, want: []string{"r1"}},
+		{keep: 1, want: []string{"r1", "r3"}},
+		{keep: 0, want: []string{"r1", "r3", "r4"}},
+	}
+
+	for _, tt := range tests {
+		got := PinsToRelease(revisions, tt.keep)
+		if len(got) != len(tt.want) {
+			t.Errorf("PinsToRelease(keep=%d) = %v, want %v", tt.keep, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i].ID != tt.want[i] {
+				t.Errorf("PinsToRelease(keep=%d)[%d] = %s, want %s", tt.keep, i, got[i].ID, tt.want[i])
+			}
+		}
+	}
+}
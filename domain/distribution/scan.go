@@ -0,0 +1,15 @@
+package distribution
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrScanFailed is returned when a file does not pass the pre-share scan
+var ErrScanFailed = errors.New("file did not pass the pre-share scan")
+
+// Scanner checks a local file, e.g. for viruses, before its upload is
+// shared publicly. An error blocks sharing.
+type Scanner interface {
+	Scan(ctx context.Context, path string) error
+}
@@ -0,0 +1,61 @@
+package distribution
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseSize parses a size such as "20GB", "500 MB", "1.5TB" or "2G" into bytes.
+// Units are binary (1GB = 1024MB); "GiB" and the bare "G" forms are accepted
+// too. A bare number is taken as bytes.
+func ParseSize(s string) (int64, error) {
+	v, mult := splitSizeUnit(strings.ToUpper(strings.TrimSpace(s)))
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) || n*float64(mult) > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 20GB or 500MB)", s)
+	}
+	return int64(n * float64(mult)), nil
+}
+
+// splitSizeUnit strips a unit suffix from v and returns the number and its multiplier
+func splitSizeUnit(v string) (string, int64) {
+	for _, u := range sizeUnits {
+		for _, suffix := range sizeSuffixes(strings.TrimSuffix(u.suffix, "B")) {
+			if strings.HasSuffix(v, suffix) {
+				return strings.TrimSpace(strings.TrimSuffix(v, suffix)), u.bytes
+			}
+		}
+	}
+	return v, 1
+}
+
+// sizeSuffixes lists the spellings of a unit, longest first
+func sizeSuffixes(prefix string) []string {
+	if prefix == "" {
+		return []string{"B"}
+	}
+	return []string{prefix + "IB", prefix + "B", prefix}
+}
+
+// FormatSize formats bytes with the largest unit that keeps the value at least 1
+func FormatSize(bytes int64) string {
+	for _, u := range sizeUnits[:len(sizeUnits)-1] {
+		if bytes >= u.bytes {
+			return fmt.Sprintf("%.1f %s", float64(bytes)/float64(u.bytes), u.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
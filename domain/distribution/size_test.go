@@ -0,0 +1,72 @@
+package distribution
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"512", 512},
+		{"512B", 512},
+		{"1KB", 1 << 10},
+		{"500MB", 500 << 20},
+		{"500 mb", 500 << 20},
+		{"2GB", 2 << 30},
+		{"2G", 2 << 30},
+		{"2GiB", 2 << 30},
+		{" 1.5TB ", 3 << 39},
+		{"0.5gb", 1 << 29},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if err != nil {
+			t.Errorf("ParseSize(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSize_Invalid(t *testing.T) {
+	for _, in := range []string{"", "GB", "abc", "-1GB", "2XB", "inf", "NaN", "1e30TB"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) expected error", in)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1 << 10, "1.0 KB"},
+		{143_280_000, "136.6 MB"},
+		{5 << 30, "5.0 GB"},
+		{3 << 39, "1.5 TB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.in); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSize_RoundTrip(t *testing.T) {
+	for _, s := range []string{"2GB", "500MB", "1.5TB"} {
+		n, err := ParseSize(s)
+		if err != nil {
+			t.Fatalf("ParseSize(%q): %v", s, err)
+		}
+		back, err := ParseSize(FormatSize(n))
+		if err != nil || back != n {
+			t.Errorf("round trip of %q gave %d (%v), want %d", s, back, err, n)
+		}
+	}
+}
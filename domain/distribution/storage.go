@@ -5,9 +5,19 @@ type StorageInfo struct {
 	TotalBytes     int64
 	UsedBytes      int64
 	AvailableBytes int64
+
+	// Unlimited is set when the store has no quota, such as an S3 bucket
+	// without one; every upload fits and the byte counts are not reported
+	Unlimited bool
 }
 
 // HasSpaceFor returns true if there's enough space for the given bytes
 func (s StorageInfo) HasSpaceFor(bytes int64) bool {
-	return s.AvailableBytes >= bytes
+	return s.Unlimited || s.AvailableBytes >= bytes
 }
+
+// Storage providers outputs can be uploaded to and shared from
+const (
+	StorageProviderDrive = "drive"
+	StorageProviderS3    = "s3"
+)
@@ -0,0 +1,24 @@
+package distribution
+
+import "testing"
+
+func TestStorageInfo_HasSpaceFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage StorageInfo
+		want    bool
+	}{
+		{name: "enough space", storage: StorageInfo{AvailableBytes: 100}, want: true},
+		{name: "exactly enough", storage: StorageInfo{AvailableBytes: 50}, want: true},
+		{name: "not enough", storage: StorageInfo{AvailableBytes: 49}},
+		{name: "unlimited", storage: StorageInfo{Unlimited: true}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.storage.HasSpaceFor(50); got != tt.want {
+				t.Errorf("HasSpaceFor(50) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,25 @@
 package distribution
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
 // UploadRequest contains the parameters needed to upload a file to Google Drive
 type UploadRequest struct {
 	LocalPath string // Full path to the local file
 	FileName  string // Target filename in Google Drive
 	FolderID  string // Target folder ID in Google Drive
 	MimeType  string // MIME type of the file
+
+	// AppProperties are private key/value tags stored on the Drive file
+	AppProperties map[string]string
+
+	// Progress, when set, is called with the bytes Drive has received so far
+	// as a large upload goes
+	Progress func(sent int64)
 }
 
 // UploadResult contains the result of a successful upload
@@ -14,10 +28,92 @@ type UploadResult struct {
 	FileName     string // Name of the uploaded file
 	ShareableURL string // URL for sharing the file
 	Size         int64  // Size of the uploaded file in bytes
+	MD5Checksum  string // Hex MD5 of the content as stored by Drive, if reported
+
+	// SharingPending is set when the upload succeeded but public sharing
+	// could not be applied; the URL works once sharing is retried
+	SharingPending bool
+}
+
+// ErrStreamingUnsupported is returned when a Drive client cannot upload from a stream
+var ErrStreamingUnsupported = errors.New("drive client cannot upload from a stream")
+
+// StreamUploader uploads content whose length is not known up front, such as
+// the output of an encoder that is still running
+type StreamUploader interface {
+	UploadStream(ctx context.Context, req UploadRequest, r io.Reader) (*UploadResult, error)
+}
+
+// ErrReplaceUnsupported is returned when a Drive client cannot update a file's content in place
+var ErrReplaceUnsupported = errors.New("drive client cannot replace file content")
+
+// ContentReplacer updates the content of an existing Drive file, keeping its
+// ID, sharing and link so URLs already sent out keep working
+type ContentReplacer interface {
+	ReplaceContent(ctx context.Context, fileID string, req UploadRequest) (*UploadResult, error)
+}
+
+// ErrDownloadUnsupported is returned when a Drive client cannot download files
+var ErrDownloadUnsupported = errors.New("drive client cannot download files")
+
+// Downloader saves a Drive file's content to a local path
+type Downloader interface {
+	Download(ctx context.Context, fileID, localPath string) error
+}
+
+// VerifyUpload checks that Drive stored exactly what was sent. The checksum is
+// only compared when Drive reported one.
+func VerifyUpload(result *UploadResult, size int64, md5Hex string) error {
+	if result.Size != size {
+		return fmt.Errorf("drive stored %d bytes of %s, but %d were sent", result.Size, result.FileName, size)
+	}
+	if result.MD5Checksum != "" && result.MD5Checksum != md5Hex {
+		return fmt.Errorf("drive checksum %s of %s does not match %s", result.MD5Checksum, result.FileName, md5Hex)
+	}
+	return nil
+}
+
+// ShareableURL returns the "anyone with the link" URL for a Drive file
+func ShareableURL(fileID string) string {
+	return fmt.Sprintf("https://drive.google.com/file/d/%s/view?usp=sharing", fileID)
+}
+
+// Linker builds download links for stores whose links are not Drive file
+// URLs, such as presigned S3 object links
+type Linker interface {
+	Link(fileID string) string
+}
+
+// LinkFor returns the link recipients use to download fileID from client
+func LinkFor(client DriveClient, fileID string) string {
+	if l, ok := client.(Linker); ok {
+		return l.Link(fileID)
+	}
+	return ShareableURL(fileID)
+}
+
+// FolderURL returns the browser URL for a Drive folder
+func FolderURL(folderID string) string {
+	return fmt.Sprintf("https://drive.google.com/drive/folders/%s", folderID)
 }
 
 // MIME type constants for common media formats
 const (
 	MimeTypeMP4 = "video/mp4"
 	MimeTypeMP3 = "audio/mpeg"
+
+	MimeTypeFolder = "application/vnd.google-apps.folder"
+)
+
+// App property keys written to uploaded files so they can be found by
+// metadata even after being renamed
+const (
+	PropertyServiceDate = "service_date" // YYYY-MM-DD
+	PropertyMediaType   = "media_type"   // MediaTypeVideo or MediaTypeAudio
+)
+
+// Values of the media_type app property
+const (
+	MediaTypeVideo = "video"
+	MediaTypeAudio = "audio"
 )
@@ -0,0 +1,43 @@
+package distribution
+
+import "testing"
+
+func TestVerifyUpload(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  UploadResult
+		wantErr bool
+	}{
+		{name: "size and checksum match", result: UploadResult{Size: 10, MD5Checksum: "abc"}},
+		{name: "no checksum reported", result: UploadResult{Size: 10}},
+		{name: "size mismatch", result: UploadResult{Size: 9, MD5Checksum: "abc"}, wantErr: true},
+		{name: "checksum mismatch", result: UploadResult{Size: 10, MD5Checksum: "def"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyUpload(&tt.result, 10, "abc"); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyUpload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// linkingClient is a DriveClient whose store builds its own links
+type linkingClient struct {
+	DriveClient
+}
+
+func (linkingClient) Link(fileID string) string {
+	return "https://media.example.org/" + fileID
+}
+
+func TestLinkFor(t *testing.T) {
+	var drive struct{ DriveClient }
+	if got, want := LinkFor(drive, "abc"), "https://drive.google.com/file/d/abc/view?usp=sharing"; got != want {
+		t.Errorf("LinkFor(drive) = %q, want %q", got, want)
+	}
+	if got, want := LinkFor(linkingClient{}, "services/2025-12-28.mp3"), "https://media.example.org/services/2025-12-28.mp3"; got != want {
+		t.Errorf("LinkFor(linker) = %q, want %q", got, want)
+	}
+}
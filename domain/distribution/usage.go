@@ -0,0 +1,135 @@
+package distribution
+
+import (
+	"sort"
+	"time"
+)
+
+// UsageReport summarizes how Drive storage is used by the Services folder
+type UsageReport struct {
+	// Quota is the account-wide storage quota
+	Quota StorageInfo
+
+	// FolderBytes and FolderFiles total every file in the Services folder
+	FolderBytes int64
+	FolderFiles int
+
+	// Years breaks dated files down by service year, oldest first
+	Years []YearUsage
+
+	// Months breaks dated files down by service month, oldest first
+	Months []MonthUsage
+
+	// Largest are the biggest files in the folder, largest first
+	Largest []FileInfo
+
+	// UndatedBytes and UndatedFiles cover files with no recognizable service date
+	UndatedBytes int64
+	UndatedFiles int
+}
+
+// YearUsage is the storage used by one service year
+type YearUsage struct {
+	Year  int
+	Bytes int64
+	Files int
+}
+
+// MonthUsage is the storage used by one service month
+type MonthUsage struct {
+	Month time.Time // first day of the month
+	Bytes int64
+	Files int
+}
+
+// Label returns the month as YYYY-MM
+func (m MonthUsage) Label() string {
+	return m.Month.Format("2006-01")
+}
+
+// FileServiceDate returns the service date of an uploaded file, read from the
+// service_date app property or, for older uploads, the YYYY-MM-DD filename prefix
+func FileServiceDate(f FileInfo) (time.Time, bool) {
+	if d, ok := f.AppProperties[PropertyServiceDate]; ok {
+		if t, err := time.Parse("2006-01-02", d); err == nil {
+			return t, true
+		}
+	}
+	if len(f.Name) >= 10 {
+		if t, err := time.Parse("2006-01-02", f.Name[:10]); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// BuildUsageReport aggregates files by service year and month and picks the
+// top largest files
+func BuildUsageReport(quota StorageInfo, files []FileInfo, top int) *UsageReport {
+	report := &UsageReport{Quota: quota}
+	years := map[int]*YearUsage{}
+	months := map[time.Time]*MonthUsage{}
+
+	for _, f := range files {
+		report.FolderBytes += f.Size
+		report.FolderFiles++
+
+		date, ok := FileServiceDate(f)
+		if !ok {
+			report.UndatedBytes += f.Size
+			report.UndatedFiles++
+			continue
+		}
+
+		y, ok := years[date.Year()]
+		if !ok {
+			y = &YearUsage{Year: date.Year()}
+			years[date.Year()] = y
+		}
+		y.Bytes += f.Size
+		y.Files++
+
+		month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		m, ok := months[month]
+		if !ok {
+			m = &MonthUsage{Month: month}
+			months[month] = m
+		}
+		m.Bytes += f.Size
+		m.Files++
+	}
+
+	for _, y := range years {
+		report.Years = append(report.Years, *y)
+	}
+	sort.Slice(report.Years, func(i, j int) bool { return report.Years[i].Year < report.Years[j].Year })
+
+	for _, m := range months {
+		report.Months = append(report.Months, *m)
+	}
+	sort.Slice(report.Months, func(i, j int) bool { return report.Months[i].Month.Before(report.Months[j].Month) })
+
+	largest := append([]FileInfo(nil), files...)
+	sort.SliceStable(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+	if top >= 0 && len(largest) > top {
+		largest = largest[:top]
+	}
+	report.Largest = largest
+
+	return report
+}
+
+// ArchiveRecommendation returns the oldest months whose files together free at
+// least target bytes. If the folder can't free that much, every month is returned.
+func (r *UsageReport) ArchiveRecommendation(target int64) []MonthUsage {
+	var picked []MonthUsage
+	var freed int64
+	for _, m := range r.Months {
+		if freed >= target {
+			break
+		}
+		picked = append(picked, m)
+		freed += m.Bytes
+	}
+	return picked
+}
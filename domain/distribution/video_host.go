@@ -0,0 +1,150 @@
+package distribution
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Target is where service videos are uploaded
+type Target string
+
+// Upload targets
+const (
+	TargetDrive   Target = "drive"
+	TargetYouTube Target = "youtube"
+	TargetBoth    Target = "both"
+)
+
+// ParseTarget reads a --target value; empty is Drive
+func ParseTarget(s string) (Target, error) {
+	switch t := Target(strings.ToLower(strings.TrimSpace(s))); t {
+	case "":
+		return TargetDrive, nil
+	case TargetDrive, TargetYouTube, TargetBoth:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unknown upload target %q: use drive, youtube or both", s)
+	}
+}
+
+// UsesDrive reports whether videos go to Drive; the zero Target does
+func (t Target) UsesDrive() bool {
+	return t != TargetYouTube
+}
+
+// UsesVideoHost reports whether videos go to the video host
+func (t Target) UsesVideoHost() bool {
+	return t == TargetYouTube || t == TargetBoth
+}
+
+// Privacy is who can watch a hosted video
+type Privacy string
+
+// Privacy settings
+const (
+	PrivacyPrivate  Privacy = "private"  // Only invited accounts
+	PrivacyUnlisted Privacy = "unlisted" // Anyone with the link
+	PrivacyPublic   Privacy = "public"   // Listed on the channel and in search
+)
+
+// ParsePrivacy reads a privacy setting; empty is unlisted, so emailed links
+// work without the video appearing on the channel
+func ParsePrivacy(s string) (Privacy, error) {
+	switch p := Privacy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PrivacyUnlisted, nil
+	case PrivacyPrivate, PrivacyUnlisted, PrivacyPublic:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown privacy %q: use private, unlisted or public", s)
+	}
+}
+
+// Limits on video host listings (YouTube's)
+const (
+	maxVideoTitle       = 100
+	maxVideoDescription = 5000
+)
+
+// VideoDetails describe a service for its video host listing
+type VideoDetails struct {
+	ChurchName  string
+	ServiceDate time.Time
+	Minister    string
+	Title       string // Sermon title (optional)
+	Scripture   string // Scripture reading (optional)
+	Privacy     Privacy
+}
+
+// VideoUpload is a service video to put on a video host
+type VideoUpload struct {
+	FileName    string
+	Title       string
+	Description string
+	Privacy     Privacy
+	RecordedAt  time.Time
+}
+
+// NewVideoUpload lists a service video as e.g. "Springfield Church: Service on
+// 12/28/2025 - The Good Shepherd", with the minister and scripture in the
+// description. Text is cut to the host's limits and angle brackets, which
+// YouTube rejects, are dropped.
+func NewVideoUpload(fileName string, d VideoDetails) VideoUpload {
+	title := "Service on " + d.ServiceDate.Format("01/02/2006")
+	if d.ChurchName != "" {
+		title = d.ChurchName + ": " + title
+	}
+	if d.Title != "" {
+		title += " - " + d.Title
+	}
+
+	var lines []string
+	if d.Minister != "" {
+		lines = append(lines, "Led by "+d.Minister)
+	}
+	if d.Scripture != "" {
+		lines = append(lines, "Scripture: "+d.Scripture)
+	}
+
+	privacy := d.Privacy
+	if privacy == "" {
+		privacy = PrivacyUnlisted
+	}
+	return VideoUpload{
+		FileName:    fileName,
+		Title:       listingText(title, maxVideoTitle),
+		Description: listingText(strings.Join(lines, "\n"), maxVideoDescription),
+		Privacy:     privacy,
+		RecordedAt:  d.ServiceDate,
+	}
+}
+
+// listingText drops angle brackets and cuts s to max characters
+func listingText(s string, max int) string {
+	s = strings.NewReplacer("<", "", ">", "").Replace(s)
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:max]))
+}
+
+// HostedVideo is a video on a video host
+type HostedVideo struct {
+	Host    string // The host's name, e.g. "YouTube"
+	ID      string
+	URL     string // Watch link for the email
+	Privacy Privacy
+}
+
+// VideoHost publishes service videos on a video platform such as YouTube.
+// This is a port that can be implemented by different infrastructure adapters.
+type VideoHost interface {
+	// Name is the host's name for output, e.g. "YouTube"
+	Name() string
+	// UploadVideo uploads the content read from r and returns where it can be watched
+	UploadVideo(ctx context.Context, v VideoUpload, r io.Reader) (*HostedVideo, error)
+}
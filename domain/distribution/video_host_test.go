@@ -0,0 +1,72 @@
+package distribution
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      Target
+		drive     bool
+		videoHost bool
+	}{
+		{"", TargetDrive, true, false},
+		{"drive", TargetDrive, true, false},
+		{"YouTube", TargetYouTube, false, true},
+		{"both", TargetBoth, true, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTarget(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseTarget(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+			continue
+		}
+		if got.UsesDrive() != tt.drive || got.UsesVideoHost() != tt.videoHost {
+			t.Errorf("%q: UsesDrive() = %v, UsesVideoHost() = %v", got, got.UsesDrive(), got.UsesVideoHost())
+		}
+	}
+	if _, err := ParseTarget("vimeo"); err == nil || !strings.Contains(err.Error(), "use drive, youtube or both") {
+		t.Errorf("ParseTarget(vimeo) error = %v", err)
+	}
+}
+
+func TestParsePrivacy(t *testing.T) {
+	if p, err := ParsePrivacy(""); err != nil || p != PrivacyUnlisted {
+		t.Errorf("ParsePrivacy(\"\") = %q, %v; want unlisted", p, err)
+	}
+	if p, err := ParsePrivacy("Private"); err != nil || p != PrivacyPrivate {
+		t.Errorf("ParsePrivacy(Private) = %q, %v", p, err)
+	}
+	if _, err := ParsePrivacy("friends"); err == nil {
+		t.Error("ParsePrivacy(friends) should fail")
+	}
+}
+
+func TestNewVideoUpload(t *testing.T) {
+	date := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	v := NewVideoUpload("2025-12-28.mp4", VideoDetails{
+		ChurchName:  "Springfield Church",
+		ServiceDate: date,
+		Minister:    "Pr. Smith",
+		Title:       "The <Good> Shepherd",
+		Scripture:   "John 10:11",
+	})
+	if want := "Springfield Church: Service on 12/28/2025 - The Good Shepherd"; v.Title != want {
+		t.Errorf("Title = %q, want %q", v.Title, want)
+	}
+	if want := "Led by Pr. Smith\nScripture: John 10:11"; v.Description != want {
+		t.Errorf("Description = %q, want %q", v.Description, want)
+	}
+	if v.Privacy != PrivacyUnlisted || !v.RecordedAt.Equal(date) {
+		t.Errorf("Privacy = %q, RecordedAt = %v", v.Privacy, v.RecordedAt)
+	}
+
+	long := NewVideoUpload("2025-12-28.mp4", VideoDetails{ServiceDate: date, Title: strings.Repeat("é", 200)})
+	if n := utf8.RuneCountInString(long.Title); n != maxVideoTitle {
+		t.Errorf("long title has %d characters, want %d", n, maxVideoTitle)
+	}
+}
@@ -0,0 +1,166 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+)
+
+// DefaultArchiveFolderName is the Services subfolder that holds a folder per
+// archived year
+const DefaultArchiveFolderName = "Archive"
+
+// MimeTypeHTML is the MIME type of the year index document
+const MimeTypeHTML = "text/html"
+
+// ErrFoldersUnsupported is returned when a client cannot create folders or
+// move files between them
+var ErrFoldersUnsupported = errors.New("drive client cannot create folders or move files")
+
+// FolderOrganizer creates folders and moves files between them. A moved file
+// keeps its ID and sharing, so links already sent out keep working.
+type FolderOrganizer interface {
+	CreateFolder(ctx context.Context, parentID, name string) (*FileInfo, error)
+	MoveFile(ctx context.Context, fileID, fromFolderID, toFolderID string) error
+}
+
+// ErrUserSharingUnsupported is returned when a client cannot share a file
+// with a person
+var ErrUserSharingUnsupported = errors.New("drive client cannot share with people")
+
+// UserSharer gives one email address read access to a file or folder
+type UserSharer interface {
+	ShareWithUser(ctx context.Context, fileID, address string) error
+}
+
+// YearIndexFileName returns the name of a year's index document
+func YearIndexFileName(year int) string {
+	return fmt.Sprintf("%d Services Index.html", year)
+}
+
+// YearIndexEntry is one service listed in a year index
+type YearIndexEntry struct {
+	Date     time.Time
+	Minister string
+	Title    string
+	Links    []IndexLink // Video first, then audio, then any other files
+}
+
+// IndexLink is a link to one archived file
+type IndexLink struct {
+	Label string // "Video", "Audio", or the file name
+	URL   string
+}
+
+// ServiceDetails is what history knows about a service, keyed by YYYY-MM-DD
+type ServiceDetails struct {
+	Minister string
+	Title    string
+}
+
+// BuildYearIndexEntries groups files by service date, one entry per date,
+// filling in minister and title from details. Folders and files with no
+// service date are left out.
+func BuildYearIndexEntries(files []FileInfo, details map[string]ServiceDetails, link func(fileID string) string) []YearIndexEntry {
+	byDate := make(map[string]*YearIndexEntry)
+	var dates []string
+	for _, f := range files {
+		if f.MimeType == FolderMimeType {
+			continue
+		}
+		date, ok := FileServiceDate(f)
+		if !ok {
+			continue
+		}
+		key := date.Format("2006-01-02")
+		entry, ok := byDate[key]
+		if !ok {
+			d := details[key]
+			entry = &YearIndexEntry{Date: date, Minister: d.Minister, Title: d.Title}
+			byDate[key] = entry
+			dates = append(dates, key)
+		}
+		entry.Links = append(entry.Links, IndexLink{Label: linkLabel(f, key), URL: link(f.ID)})
+	}
+
+	sort.Strings(dates)
+	entries := make([]YearIndexEntry, 0, len(dates))
+	for _, key := range dates {
+		entry := byDate[key]
+		sort.SliceStable(entry.Links, func(i, j int) bool {
+			return linkRank(entry.Links[i].Label) < linkRank(entry.Links[j].Label)
+		})
+		entries = append(entries, *entry)
+	}
+	return entries
+}
+
+// linkLabel names the main video and MP3 of a date by kind, and anything else
+// by its file name
+func linkLabel(f FileInfo, date string) string {
+	switch f.Name {
+	case date + ".mp4":
+		return "Video"
+	case date + ".mp3":
+		return "Audio"
+	}
+	return f.Name
+}
+
+func linkRank(label string) int {
+	switch label {
+	case "Video":
+		return 0
+	case "Audio":
+		return 1
+	}
+	return 2
+}
+
+// YearIndex lists a year's archived services for leadership
+type YearIndex struct {
+	Church    string
+	Year      int
+	FolderURL string
+	Entries   []YearIndexEntry
+	Generated time.Time
+}
+
+var yearIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{if .Church}}{{.Church}}: {{end}}{{.Year}} Services</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{if .Church}}{{.Church}}: {{end}}{{.Year}} Services</h1>
+<p>{{len .Entries}} services{{if .FolderURL}}, kept in <a href="{{.FolderURL}}">this folder</a>{{end}}.</p>
+<table>
+<tr><th>Date</th><th>Minister</th><th>Title</th><th>Recording</th></tr>
+{{- range .Entries}}
+<tr><td>{{.Date.Format "Monday, January 2"}}</td><td>{{.Minister}}</td><td>{{.Title}}</td><td>
+{{- range $i, $link := .Links}}{{if $i}} | {{end}}<a href="{{$link.URL}}">{{$link.Label}}</a>{{end}}</td></tr>
+{{- end}}
+</table>
+<p><small>Generated {{.Generated.Format "January 2, 2006"}}</small></p>
+</body>
+</html>
+`))
+
+// RenderHTML renders the index as a standalone HTML document
+func (idx YearIndex) RenderHTML() (string, error) {
+	var buf bytes.Buffer
+	if err := yearIndexTemplate.Execute(&buf, idx); err != nil {
+		return "", fmt.Errorf("failed to render the year index: %w", err)
+	}
+	return buf.String(), nil
+}
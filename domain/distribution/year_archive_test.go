@@ -0,0 +1,80 @@
+package distribution
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildYearIndexEntries(t *testing.T) {
+	files := []FileInfo{
+		{ID: "a2", Name: "2025-03-02.mp3"},
+		{ID: "a1", Name: "2025-03-02-64k.mp3"},
+		{ID: "v2", Name: "2025-03-02.mp4"},
+		{ID: "v1", Name: "Easter.mp4", AppProperties: map[string]string{PropertyServiceDate: "2025-04-20"}},
+		{ID: "f", Name: "2025-01-01", MimeType: FolderMimeType},
+		{ID: "x", Name: "notes.txt"},
+	}
+	details := map[string]ServiceDetails{"2025-03-02": {Minister: "Pr. Smith", Title: "The Good Shepherd"}}
+	link := func(id string) string { return "https://example.com/" + id }
+
+	entries := BuildYearIndexEntries(files, details, link)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %+v", len(entries), entries)
+	}
+
+	first := entries[0]
+	if first.Date.Format("2006-01-02") != "2025-03-02" || first.Minister != "Pr. Smith" || first.Title != "The Good Shepherd" {
+		t.Errorf("first entry = %+v", first)
+	}
+	var labels []string
+	for _, l := range first.Links {
+		labels = append(labels, l.Label)
+	}
+	if got := strings.Join(labels, ", "); got != "Video, Audio, 2025-03-02-64k.mp3" {
+		t.Errorf("links = %s, want video, audio, then the rest", got)
+	}
+	if first.Links[0].URL != "https://example.com/v2" {
+		t.Errorf("video link = %s", first.Links[0].URL)
+	}
+
+	if second := entries[1]; second.Date.Format("2006-01-02") != "2025-04-20" || second.Minister != "" || second.Links[0].Label != "Easter.mp4" {
+		t.Errorf("second entry = %+v, want the tagged file by name", second)
+	}
+}
+
+func TestYearIndex_RenderHTML(t *testing.T) {
+	idx := YearIndex{
+		Church:    "White Plains <NAC>",
+		Year:      2025,
+		FolderURL: FolderURL("year-folder"),
+		Generated: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+		Entries: []YearIndexEntry{{
+			Date:     time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+			Minister: "Pr. Smith",
+			Links:    []IndexLink{{Label: "Video", URL: "https://example.com/v"}, {Label: "Audio", URL: "https://example.com/a"}},
+		}},
+	}
+
+	html, err := idx.RenderHTML()
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	for _, want := range []string{
+		"<title>White Plains &lt;NAC&gt;: 2025 Services</title>",
+		"1 services, kept in <a href=\"https://drive.google.com/drive/folders/year-folder\">",
+		"<td>Sunday, December 28</td><td>Pr. Smith</td>",
+		`<a href="https://example.com/v">Video</a> | <a href="https://example.com/a">Audio</a>`,
+		"Generated January 5, 2026",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("index is missing %q:\n%s", want, html)
+		}
+	}
+}
+
+func TestYearIndexFileName(t *testing.T) {
+	if got := YearIndexFileName(2025); got != "2025 Services Index.html" {
+		t.Errorf("YearIndexFileName(2025) = %q", got)
+	}
+}
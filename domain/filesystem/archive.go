@@ -0,0 +1,70 @@
+package filesystem
+
+import (
+	"fmt"
+	"time"
+)
+
+// What local cleanup can keep a compressed copy of before deleting it
+const (
+	ArchiveTrimmed = "trimmed" // Trimmed service videos, re-encoded at a low bitrate
+	ArchiveAudio   = "audio"   // Service MP3s, packed into a compressed tarball
+	ArchiveSource  = "source"  // Raw recordings, re-encoded like trimmed videos
+)
+
+// DefaultArchiveKinds are archived when archive.include is not set
+var DefaultArchiveKinds = []string{ArchiveTrimmed, ArchiveAudio}
+
+// Archiver keeps a space-efficient copy of a file local cleanup is about to
+// delete
+type Archiver interface {
+	// Archive writes a compressed copy of path and returns the copy's path
+	Archive(path string) (string, error)
+	// Prune deletes copies the retention rules no longer keep and returns
+	// their paths
+	Prune() ([]string, error)
+}
+
+// ArchivePolicy holds the retention rules for the local archive
+type ArchivePolicy struct {
+	Kinds     []string
+	KeepWeeks int // 0 keeps archived copies forever
+}
+
+// ParseArchivePolicy validates archive.include and archive.keep_weeks,
+// defaulting to DefaultArchiveKinds
+func ParseArchivePolicy(include []string, keepWeeks int) (ArchivePolicy, error) {
+	if keepWeeks < 0 {
+		return ArchivePolicy{}, fmt.Errorf("keep_weeks %d must not be negative", keepWeeks)
+	}
+	if len(include) == 0 {
+		include = DefaultArchiveKinds
+	}
+	for _, kind := range include {
+		switch kind {
+		case ArchiveTrimmed, ArchiveAudio, ArchiveSource:
+		default:
+			return ArchivePolicy{}, fmt.Errorf("unknown include %q (must be trimmed, audio, or source)", kind)
+		}
+	}
+	return ArchivePolicy{Kinds: include, KeepWeeks: keepWeeks}, nil
+}
+
+// Includes reports whether files of kind are archived before deletion
+func (p ArchivePolicy) Includes(kind string) bool {
+	for _, k := range p.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether a copy archived at archivedAt is past the
+// retention window at now
+func (p ArchivePolicy) Expired(archivedAt, now time.Time) bool {
+	if p.KeepWeeks <= 0 {
+		return false
+	}
+	return now.Sub(archivedAt) > time.Duration(p.KeepWeeks)*7*24*time.Hour
+}
@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseArchivePolicy_Defaults(t *testing.T) {
+	policy, err := ParseArchivePolicy(nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !policy.Includes(ArchiveTrimmed) || !policy.Includes(ArchiveAudio) {
+		t.Errorf("expected trimmed and audio by default, got %v", policy.Kinds)
+	}
+	if policy.Includes(ArchiveSource) {
+		t.Error("source recordings should not be archived by default")
+	}
+}
+
+func TestParseArchivePolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		include   []string
+		keepWeeks int
+		want      string
+	}{
+		{"unknown kind", []string{"trimmed", "photos"}, 0, `unknown include "photos"`},
+		{"negative weeks", nil, -1, "keep_weeks -1 must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseArchivePolicy(tt.include, tt.keepWeeks)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestArchivePolicy_Expired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := ArchivePolicy{KeepWeeks: 4}
+
+	if policy.Expired(now.AddDate(0, 0, -27), now) {
+		t.Error("a copy archived 27 days ago is inside a 4-week window")
+	}
+	if !policy.Expired(now.AddDate(0, 0, -29), now) {
+		t.Error("a copy archived 29 days ago is past a 4-week window")
+	}
+	if (ArchivePolicy{}).Expired(now.AddDate(-5, 0, 0), now) {
+		t.Error("keep_weeks 0 should keep copies forever")
+	}
+}
@@ -0,0 +1,29 @@
+package filesystem
+
+import (
+	"io"
+	"io/fs"
+)
+
+// FS is the local file system services read and write outputs through, so
+// tests can keep files in memory instead of in temporary directories
+// This is a port that can be implemented by different infrastructure adapters
+type FS interface {
+	// Open opens the named file for reading
+	Open(name string) (io.ReadCloser, error)
+
+	// Create creates or truncates the named file; its directory must exist
+	Create(name string) (io.WriteCloser, error)
+
+	// Stat describes the named file or directory
+	Stat(name string) (fs.FileInfo, error)
+
+	// MkdirAll creates a directory and any missing parents
+	MkdirAll(path string) error
+
+	// Remove deletes the named file or empty directory
+	Remove(name string) error
+
+	// WalkDir calls fn for root and everything below it, in lexical order
+	WalkDir(root string, fn fs.WalkDirFunc) error
+}
@@ -1,5 +1,11 @@
 package filesystem
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
 // DiskChecker reports filesystem disk usage
 type DiskChecker interface {
 	// UsagePercent returns the percentage of disk used (0-100) for the
@@ -12,3 +18,36 @@ type FileRemover interface {
 	// Remove deletes the file at the given path
 	Remove(path string) error
 }
+
+// GrowthDetector reports whether a file is still being written, such as a
+// recording OBS has not finished
+type GrowthDetector interface {
+	IsGrowing(path string) (bool, error)
+}
+
+// ModTimer reports when a file was last written
+type ModTimer interface {
+	ModTime(path string) (time.Time, error)
+}
+
+// ErrFileGrowing is returned when the newest source file is still being written
+var ErrFileGrowing = errors.New("file is still being written")
+
+// What the file finder does when the newest source file is still being written
+const (
+	InProgressError = "error" // Fail with a clear message (default)
+	InProgressSkip  = "skip"  // Use the newest file that is complete
+	InProgressWait  = "wait"  // Wait for the file to stop growing
+)
+
+// ParseInProgressPolicy validates paths.in_progress, defaulting to InProgressError
+func ParseInProgressPolicy(s string) (string, error) {
+	switch s {
+	case "":
+		return InProgressError, nil
+	case InProgressError, InProgressSkip, InProgressWait:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown in-progress policy %q (must be error, skip, or wait)", s)
+	}
+}
@@ -0,0 +1,118 @@
+package filesystem
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Platform is the kind of system the tool runs on, as far as paths go
+type Platform string
+
+const (
+	PlatformUnix    Platform = "unix"    // Linux or macOS
+	PlatformWSL     Platform = "wsl"     // Linux under Windows Subsystem for Linux
+	PlatformWindows Platform = "windows" // Native Windows
+)
+
+// PathStyle is the way a configured path is written
+type PathStyle string
+
+const (
+	PathStylePOSIX   PathStyle = "posix"   // /home/user/Videos
+	PathStyleWindows PathStyle = "windows" // C:\Users\user\Videos or \\server\share
+	PathStyleWSL     PathStyle = "wsl"     // /mnt/c/Users/user/Videos
+)
+
+// DetectPathStyle reports how path is written
+func DetectPathStyle(path string) PathStyle {
+	if hasDriveLetter(path) || strings.HasPrefix(path, `\\`) {
+		return PathStyleWindows
+	}
+	if _, ok := wslDrive(path); ok {
+		return PathStyleWSL
+	}
+	return PathStylePOSIX
+}
+
+// WindowsToWSL maps a Windows drive path to where WSL mounts it, e.g.
+// C:\Users\me to /mnt/c/Users/me. Network shares cannot be mapped.
+func WindowsToWSL(path string) (string, bool) {
+	if !hasDriveLetter(path) {
+		return "", false
+	}
+	drive := strings.ToLower(path[:1])
+	rest := strings.Trim(strings.ReplaceAll(path[2:], `\`, "/"), "/")
+	if rest == "" {
+		return "/mnt/" + drive, true
+	}
+	return "/mnt/" + drive + "/" + rest, true
+}
+
+// WSLToWindows maps a WSL drive mount back to its Windows path, e.g.
+// /mnt/c/Users/me to C:\Users\me
+func WSLToWindows(path string) (string, bool) {
+	drive, ok := wslDrive(path)
+	if !ok {
+		return "", false
+	}
+	rest := strings.Trim(path[len("/mnt/x"):], "/")
+	return strings.ToUpper(drive) + `:\` + strings.ReplaceAll(rest, "/", `\`), true
+}
+
+// NormalizePath converts path to the style platform opens: Windows drive
+// paths become /mnt/<drive> under WSL and the reverse on Windows. Other paths
+// are returned unchanged; CheckPathStyle reports the ones that cannot work.
+func NormalizePath(path string, platform Platform) string {
+	switch platform {
+	case PlatformWSL:
+		if converted, ok := WindowsToWSL(path); ok {
+			return converted
+		}
+	case PlatformWindows:
+		if converted, ok := WSLToWindows(path); ok {
+			return converted
+		}
+	}
+	return path
+}
+
+// CheckPathStyle returns an error explaining how to fix path when platform
+// cannot open it as written
+func CheckPathStyle(path string, platform Platform) error {
+	if path == "" {
+		return nil
+	}
+	style := DetectPathStyle(path)
+	switch {
+	case platform == PlatformUnix && style == PathStyleWindows:
+		return fmt.Errorf("%s is a Windows path, but this system is not Windows or WSL; use a path like /home/you/Videos", path)
+	case platform == PlatformWSL && strings.HasPrefix(path, `\\`):
+		return fmt.Errorf("%s is a network share, which WSL cannot open directly; mount it (e.g. under /mnt/share) and use that path", path)
+	case platform == PlatformWindows && style == PathStylePOSIX && strings.HasPrefix(path, "/"):
+		return fmt.Errorf("%s is a Linux path, but this is Windows; use a path like C:\\Users\\you\\Videos, or run the tool inside WSL", path)
+	}
+	return nil
+}
+
+// hasDriveLetter reports whether path starts with a drive such as C: or C:\
+func hasDriveLetter(path string) bool {
+	if len(path) < 2 || path[1] != ':' || !isLetter(path[0]) {
+		return false
+	}
+	return len(path) == 2 || path[2] == '\\' || path[2] == '/'
+}
+
+// wslDrive returns the drive letter of a /mnt/<letter> path
+func wslDrive(path string) (string, bool) {
+	if !strings.HasPrefix(path, "/mnt/") || len(path) < len("/mnt/x") || !isLetter(path[5]) {
+		return "", false
+	}
+	if len(path) > 6 && path[6] != '/' {
+		return "", false
+	}
+	return path[5:6], true
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,119 @@
+package filesystem
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectPathStyle(t *testing.T) {
+	tests := []struct {
+		path string
+		want PathStyle
+	}{
+		{`C:\Users\me\Videos`, PathStyleWindows},
+		{`d:/Recordings`, PathStyleWindows},
+		{`E:`, PathStyleWindows},
+		{`\\server\share\obs`, PathStyleWindows},
+		{`/mnt/c/Users/me`, PathStyleWSL},
+		{`/mnt/d`, PathStyleWSL},
+		{`/mnt/share/obs`, PathStylePOSIX},
+		{`/home/me/Videos`, PathStylePOSIX},
+		{`recordings`, PathStylePOSIX},
+		{`C:recordings`, PathStylePOSIX},
+	}
+	for _, tt := range tests {
+		if got := DetectPathStyle(tt.path); got != tt.want {
+			t.Errorf("DetectPathStyle(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWindowsToWSL(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{`C:\Users\me\Videos`, "/mnt/c/Users/me/Videos", true},
+		{`D:/Recordings/`, "/mnt/d/Recordings", true},
+		{`C:\`, "/mnt/c", true},
+		{`\\server\share`, "", false},
+		{`/home/me`, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := WindowsToWSL(tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("WindowsToWSL(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWSLToWindows(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"/mnt/c/Users/me/Videos", `C:\Users\me\Videos`, true},
+		{"/mnt/d", `D:\`, true},
+		{"/mnt/share/obs", "", false},
+		{"/home/me", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := WSLToWindows(tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("WSLToWindows(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		platform Platform
+		want     string
+	}{
+		{`C:\Users\me\Videos`, PlatformWSL, "/mnt/c/Users/me/Videos"},
+		{"/mnt/c/Users/me/Videos", PlatformWSL, "/mnt/c/Users/me/Videos"},
+		{"/mnt/c/Users/me/Videos", PlatformWindows, `C:\Users\me\Videos`},
+		{`C:\Users\me\Videos`, PlatformWindows, `C:\Users\me\Videos`},
+		{`C:\Users\me\Videos`, PlatformUnix, `C:\Users\me\Videos`},
+		{"/mnt/c/Users/me/Videos", PlatformUnix, "/mnt/c/Users/me/Videos"},
+		{"", PlatformWSL, ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizePath(tt.path, tt.platform); got != tt.want {
+			t.Errorf("NormalizePath(%q, %s) = %q, want %q", tt.path, tt.platform, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPathStyle(t *testing.T) {
+	tests := []struct {
+		path     string
+		platform Platform
+		wantErr  string
+	}{
+		{`C:\Users\me`, PlatformUnix, "is a Windows path"},
+		{`\\server\share`, PlatformWSL, "mount it"},
+		{"/home/me", PlatformWindows, "is a Linux path"},
+		{"/mnt/c/Users/me", PlatformWSL, ""},
+		{`C:\Users\me`, PlatformWSL, ""},
+		{"/home/me", PlatformUnix, ""},
+		{`C:\Users\me`, PlatformWindows, ""},
+		{"recordings", PlatformWindows, ""},
+		{"", PlatformUnix, ""},
+	}
+	for _, tt := range tests {
+		err := CheckPathStyle(tt.path, tt.platform)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("CheckPathStyle(%q, %s) unexpected error: %v", tt.path, tt.platform, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("CheckPathStyle(%q, %s) = %v, want error containing %q", tt.path, tt.platform, err, tt.wantErr)
+		}
+	}
+}
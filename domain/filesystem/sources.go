@@ -0,0 +1,68 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// NewestFinder finds the newest file with an extension in a directory
+type NewestFinder interface {
+	FindNewestFile(dir, ext string) (string, error)
+}
+
+// FindNewestSource searches the source directories in priority order and
+// returns the newest file from the first one that has any. A recording still
+// in progress stops the search rather than falling back to an older one.
+func FindNewestSource(finder NewestFinder, dirs []string, ext string) (string, error) {
+	if len(dirs) == 0 {
+		return "", fmt.Errorf("no source directory configured; set paths.source_directory")
+	}
+	var firstErr error
+	for _, dir := range dirs {
+		file, err := finder.FindNewestFile(dir, ext)
+		if err == nil {
+			return file, nil
+		}
+		if errors.Is(err, ErrFileGrowing) {
+			return "", err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(dirs) == 1 {
+		return "", firstErr
+	}
+	return "", fmt.Errorf("no video files found in any source directory (%s): %w", strings.Join(dirs, ", "), firstErr)
+}
+
+// ResolveSource resolves a relative source name against the source
+// directories, picking the first one that holds it. If none does, it is
+// resolved against the first directory so the error names a sensible path.
+func ResolveSource(exists func(path string) bool, dirs []string, name string) string {
+	if filepath.IsAbs(name) || len(dirs) == 0 {
+		return name
+	}
+	for _, dir := range dirs {
+		if path := filepath.Join(dir, name); exists(path) {
+			return path
+		}
+	}
+	return filepath.Join(dirs[0], name)
+}
+
+// SourceDirectoryOf returns the source directory path is in, or the first
+// directory if it is in none of them
+func SourceDirectoryOf(dirs []string, path string) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	for _, dir := range dirs {
+		if filepath.Dir(path) == filepath.Clean(dir) {
+			return dir
+		}
+	}
+	return dirs[0]
+}
@@ -0,0 +1,115 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// mapFinder finds the newest file per directory from a fixed map
+type mapFinder struct {
+	newest  map[string]string
+	growing map[string]bool
+	asked   []string
+}
+
+func (f *mapFinder) FindNewestFile(dir, ext string) (string, error) {
+	f.asked = append(f.asked, dir)
+	if f.growing[dir] {
+		return "", fmt.Errorf("recording in %s: %w", dir, ErrFileGrowing)
+	}
+	if file, ok := f.newest[dir]; ok {
+		return file, nil
+	}
+	return "", fmt.Errorf("no video files found in %s", dir)
+}
+
+func TestFindNewestSource_PriorityOrder(t *testing.T) {
+	finder := &mapFinder{newest: map[string]string{
+		"/obs":     "/obs/2025-12-21 10-00-00.mp4",
+		"/capture": "/capture/2025-12-28 10-00-00.mp4",
+	}}
+
+	got, err := FindNewestSource(finder, []string{"/obs", "/capture"}, ".mp4")
+	if err != nil {
+		t.Fatalf("FindNewestSource() error = %v", err)
+	}
+	if got != "/obs/2025-12-21 10-00-00.mp4" {
+		t.Errorf("FindNewestSource() = %q, want the first directory's newest file", got)
+	}
+	if len(finder.asked) != 1 {
+		t.Errorf("searched %v, want only the first directory", finder.asked)
+	}
+}
+
+func TestFindNewestSource_FallsBackWhenEmpty(t *testing.T) {
+	finder := &mapFinder{newest: map[string]string{"/capture": "/capture/2025-12-28.mp4"}}
+
+	got, err := FindNewestSource(finder, []string{"/obs", "/capture"}, ".mp4")
+	if err != nil {
+		t.Fatalf("FindNewestSource() error = %v", err)
+	}
+	if got != "/capture/2025-12-28.mp4" {
+		t.Errorf("FindNewestSource() = %q, want /capture/2025-12-28.mp4", got)
+	}
+}
+
+func TestFindNewestSource_GrowingStopsSearch(t *testing.T) {
+	finder := &mapFinder{
+		newest:  map[string]string{"/capture": "/capture/2025-12-21.mp4"},
+		growing: map[string]bool{"/obs": true},
+	}
+
+	_, err := FindNewestSource(finder, []string{"/obs", "/capture"}, ".mp4")
+	if !errors.Is(err, ErrFileGrowing) {
+		t.Errorf("FindNewestSource() error = %v, want ErrFileGrowing", err)
+	}
+}
+
+func TestFindNewestSource_NoneFound(t *testing.T) {
+	finder := &mapFinder{}
+
+	_, err := FindNewestSource(finder, []string{"/obs"}, ".mp4")
+	if err == nil || err.Error() != "no video files found in /obs" {
+		t.Errorf("single directory error = %v, want the finder's error unchanged", err)
+	}
+
+	_, err = FindNewestSource(finder, []string{"/obs", "/capture"}, ".mp4")
+	if err == nil || !strings.Contains(err.Error(), "no video files found in any source directory (/obs, /capture)") {
+		t.Errorf("several directories error = %v", err)
+	}
+
+	if _, err := FindNewestSource(finder, nil, ".mp4"); err == nil {
+		t.Error("expected an error with no source directories")
+	}
+}
+
+func TestResolveSource(t *testing.T) {
+	exists := func(path string) bool { return path == "/capture/2025-12-28.mp4" }
+	dirs := []string{"/obs", "/capture"}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "2025-12-28.mp4", want: "/capture/2025-12-28.mp4"},
+		{name: "2025-12-21.mp4", want: "/obs/2025-12-21.mp4"},
+		{name: "/elsewhere/2025-12-28.mp4", want: "/elsewhere/2025-12-28.mp4"},
+	}
+	for _, tt := range tests {
+		if got := ResolveSource(exists, dirs, tt.name); got != tt.want {
+			t.Errorf("ResolveSource(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSourceDirectoryOf(t *testing.T) {
+	dirs := []string{"/obs", "/capture/"}
+	if got := SourceDirectoryOf(dirs, "/capture/2025-12-28.mp4"); got != "/capture/" {
+		t.Errorf("SourceDirectoryOf(capture file) = %q", got)
+	}
+	if got := SourceDirectoryOf(dirs, "/elsewhere/2025-12-28.mp4"); got != "/obs" {
+		t.Errorf("SourceDirectoryOf(other file) = %q, want the first directory", got)
+	}
+}
@@ -0,0 +1,84 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BudgetWindow is the rolling period an upload budget covers
+const BudgetWindow = 7 * 24 * time.Hour
+
+// What a run does when it would go over its upload budget
+const (
+	BudgetWarn    = "warn"
+	BudgetConfirm = "confirm"
+)
+
+// UploadBudget caps how much is uploaded in a rolling week, for congregations
+// that upload over a metered connection
+type UploadBudget struct {
+	WeeklyBytes int64  // Zero means no budget
+	OnExceed    string // BudgetWarn (default) or BudgetConfirm
+}
+
+// ParseBudgetAction validates what to do when the budget would be exceeded
+func ParseBudgetAction(s string) (string, error) {
+	switch a := strings.ToLower(strings.TrimSpace(s)); a {
+	case "":
+		return BudgetWarn, nil
+	case BudgetWarn, BudgetConfirm:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown action %q (must be warn or confirm)", s)
+	}
+}
+
+// Enabled reports whether a budget is set
+func (b UploadBudget) Enabled() bool {
+	return b.WeeklyBytes > 0
+}
+
+// Confirms reports whether going over the budget needs the operator's go-ahead
+func (b UploadBudget) Confirms() bool {
+	return b.OnExceed == BudgetConfirm
+}
+
+// BudgetUsage is what has been uploaded against a budget
+type BudgetUsage struct {
+	Used  int64
+	Limit int64
+	Since time.Time
+}
+
+// Usage totals the uploads recorded in the week before now
+func (b UploadBudget) Usage(entries []Entry, now time.Time) BudgetUsage {
+	since := now.Add(-BudgetWindow)
+	return BudgetUsage{Used: UploadedSince(entries, since), Limit: b.WeeklyBytes, Since: since}
+}
+
+// Remaining returns how much can still be uploaded, never below zero
+func (u BudgetUsage) Remaining() int64 {
+	if u.Used >= u.Limit {
+		return 0
+	}
+	return u.Limit - u.Used
+}
+
+// Exceeds reports whether uploading upcoming more bytes would go over the limit
+func (u BudgetUsage) Exceeds(upcoming int64) bool {
+	return u.Used+upcoming > u.Limit
+}
+
+// UploadedSince totals the video and audio bytes of runs processed at or
+// after since
+func UploadedSince(entries []Entry, since time.Time) int64 {
+	var total int64
+	for _, e := range entries {
+		if e.ProcessedAt.Before(since) {
+			continue
+		}
+		total += e.VideoSize + e.AudioSize
+	}
+	return total
+}
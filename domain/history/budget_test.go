@@ -0,0 +1,58 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUploadBudget_UsageCountsTheLastWeek(t *testing.T) {
+	now := time.Date(2026, 1, 4, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{ProcessedAt: now.Add(-8 * 24 * time.Hour), VideoSize: 900, AudioSize: 100},
+		{ProcessedAt: now.Add(-7 * 24 * time.Hour), VideoSize: 400, AudioSize: 50},
+		{ProcessedAt: now.Add(-2 * 24 * time.Hour), AudioSize: 30},
+	}
+
+	usage := UploadBudget{WeeklyBytes: 1000}.Usage(entries, now)
+	if usage.Used != 480 {
+		t.Errorf("Used = %d, want 480", usage.Used)
+	}
+	if usage.Remaining() != 520 {
+		t.Errorf("Remaining() = %d, want 520", usage.Remaining())
+	}
+	if usage.Exceeds(520) {
+		t.Error("expected an upload filling the budget exactly to fit")
+	}
+	if !usage.Exceeds(521) {
+		t.Error("expected an upload one byte over the budget to exceed it")
+	}
+}
+
+func TestBudgetUsage_RemainingNeverNegative(t *testing.T) {
+	if got := (BudgetUsage{Used: 1500, Limit: 1000}).Remaining(); got != 0 {
+		t.Errorf("Remaining() = %d, want 0", got)
+	}
+}
+
+func TestParseBudgetAction(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "", want: BudgetWarn},
+		{input: "warn", want: BudgetWarn},
+		{input: "Confirm", want: BudgetConfirm},
+		{input: "block", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBudgetAction(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBudgetAction(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBudgetAction(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// Correction is a follow-up email sent because the recording email named the
+// wrong minister
+type Correction struct {
+	Minister         string    `json:"minister"`          // The minister who led the service
+	PreviousMinister string    `json:"previous_minister"` // The minister the first email named
+	MessageID        string    `json:"message_id,omitempty"`
+	SentAt           time.Time `json:"sent_at"`
+}
+
+// Latest returns the most recent entry for the service date, or ErrNoEntry
+func Latest(entries []Entry, serviceDate time.Time) (Entry, error) {
+	i, err := latestIndex(entries, serviceDate)
+	if err != nil {
+		return Entry{}, err
+	}
+	return entries[i], nil
+}
+
+// AttachCorrection records the correction on the most recent entry for the
+// service date and sets its minister to the corrected one. It returns
+// ErrNoEntry if none matches.
+func AttachCorrection(entries []Entry, serviceDate time.Time, c Correction) ([]Entry, error) {
+	i, err := latestIndex(entries, serviceDate)
+	if err != nil {
+		return entries, err
+	}
+	entries[i].Minister = c.Minister
+	entries[i].Corrections = append(entries[i].Corrections, c)
+	return entries, nil
+}
+
+// latestIndex finds the most recent entry for the service date
+func latestIndex(entries []Entry, serviceDate time.Time) (int, error) {
+	day := dateOnly(serviceDate)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if dateOnly(entries[i].ServiceDate).Equal(day) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w on %s", ErrNoEntry, day.Format("2006-01-02"))
+}
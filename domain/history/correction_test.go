@@ -0,0 +1,34 @@
+package history
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAttachCorrection(t *testing.T) {
+	entries := []Entry{
+		{ServiceDate: date("2025-12-28"), Minister: "Pr. Smith"},
+		{ServiceDate: date("2025-12-28"), Minister: "Pr. Smith", EmailSubject: "rerun"},
+	}
+
+	latest, err := Latest(entries, date("2025-12-28"))
+	if err != nil || latest.EmailSubject != "rerun" {
+		t.Fatalf("Latest() = %+v, %v, want the rerun", latest, err)
+	}
+
+	c := Correction{Minister: "Pr. Jones", PreviousMinister: "Pr. Smith", SentAt: date("2025-12-29")}
+	entries, err = AttachCorrection(entries, date("2025-12-28"), c)
+	if err != nil {
+		t.Fatalf("AttachCorrection() error = %v", err)
+	}
+	if entries[0].Minister != "Pr. Smith" || len(entries[0].Corrections) != 0 {
+		t.Errorf("the earlier run should be untouched, got %+v", entries[0])
+	}
+	if entries[1].Minister != "Pr. Jones" || len(entries[1].Corrections) != 1 {
+		t.Errorf("the latest run should name the corrected minister, got %+v", entries[1])
+	}
+
+	if _, err := Latest(entries, date("2025-07-06")); !errors.Is(err, ErrNoEntry) {
+		t.Errorf("Latest() on unknown date error = %v, want ErrNoEntry", err)
+	}
+}
@@ -0,0 +1,101 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultDriftWeeks is how many low scores in a row raise a drift alert
+const DefaultDriftWeeks = 3
+
+// DefaultDriftMargin puts the default alert threshold this far above the
+// detection match threshold, so the alert fires before detection fails
+const DefaultDriftMargin = 0.05
+
+// DetectionScore is the start detection confidence recorded for one service
+type DetectionScore struct {
+	ServiceDate time.Time
+	Confidence  float64
+	CameraAngle string
+	EarlyExit   bool // Detection stopped on a confident frame bracket
+}
+
+// DetectionScores returns the detection confidence of each auto-detected
+// service, oldest first. A service processed more than once keeps its latest run.
+func DetectionScores(entries []Entry) []DetectionScore {
+	latest := make(map[time.Time]DetectionScore)
+	for _, e := range entries {
+		if e.DetectionConfidence <= 0 {
+			continue
+		}
+		day := dateOnly(e.ServiceDate)
+		latest[day] = DetectionScore{ServiceDate: day, Confidence: e.DetectionConfidence, CameraAngle: e.CameraAngle, EarlyExit: e.DetectionEarlyExit}
+	}
+
+	scores := make([]DetectionScore, 0, len(latest))
+	for _, s := range latest {
+		scores = append(scores, s)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].ServiceDate.Before(scores[j].ServiceDate)
+	})
+	return scores
+}
+
+// Drift describes how detection confidence is trending against a threshold
+type Drift struct {
+	Scores    []DetectionScore // Oldest first
+	Threshold float64
+	Weeks     int
+}
+
+// NewDrift analyzes the scores in entries. A zero weeks uses DefaultDriftWeeks.
+func NewDrift(entries []Entry, threshold float64, weeks int) Drift {
+	if weeks <= 0 {
+		weeks = DefaultDriftWeeks
+	}
+	return Drift{Scores: DetectionScores(entries), Threshold: threshold, Weeks: weeks}
+}
+
+// EarlyExits counts the scores whose detection stopped early
+func (d Drift) EarlyExits() int {
+	n := 0
+	for _, s := range d.Scores {
+		if s.EarlyExit {
+			n++
+		}
+	}
+	return n
+}
+
+// LowStreak counts the most recent scores in a row below the threshold
+func (d Drift) LowStreak() int {
+	n := 0
+	for i := len(d.Scores) - 1; i >= 0 && d.Scores[i].Confidence < d.Threshold; i-- {
+		n++
+	}
+	return n
+}
+
+// Alert reports whether the last Weeks scores were all below the threshold
+func (d Drift) Alert() bool {
+	return d.LowStreak() >= d.Weeks
+}
+
+// Change compares the average of the last Weeks scores with the Weeks before
+// them. ok is false until there are enough scores for both windows.
+func (d Drift) Change() (recent, previous float64, ok bool) {
+	n := len(d.Scores)
+	if n < 2*d.Weeks {
+		return 0, 0, false
+	}
+	return average(d.Scores[n-d.Weeks:]), average(d.Scores[n-2*d.Weeks : n-d.Weeks]), true
+}
+
+func average(scores []DetectionScore) float64 {
+	var sum float64
+	for _, s := range scores {
+		sum += s.Confidence
+	}
+	return sum / float64(len(scores))
+}
@@ -0,0 +1,94 @@
+package history
+
+import "testing"
+
+func scoreEntries(scores ...float64) []Entry {
+	start := date("2025-11-02")
+	entries := make([]Entry, len(scores))
+	for i, s := range scores {
+		entries[i] = Entry{ServiceDate: start.AddDate(0, 0, 7*i), DetectionConfidence: s}
+	}
+	return entries
+}
+
+func TestDetectionScores_SkipsManualAndKeepsLatestRun(t *testing.T) {
+	entries := []Entry{
+		{ServiceDate: date("2025-12-28"), DetectionConfidence: 0.90},
+		{ServiceDate: date("2025-12-21"), DetectionConfidence: 0.93, CameraAngle: "wide"},
+		{ServiceDate: date("2025-12-14")}, // --start given by hand
+		{ServiceDate: date("2025-12-28"), DetectionConfidence: 0.88},
+	}
+
+	scores := DetectionScores(entries)
+	if len(scores) != 2 {
+		t.Fatalf("got %d scores, want 2: %+v", len(scores), scores)
+	}
+	if !scores[0].ServiceDate.Equal(date("2025-12-21")) || scores[0].CameraAngle != "wide" {
+		t.Errorf("first score = %+v, want 2025-12-21 wide", scores[0])
+	}
+	if scores[1].Confidence != 0.88 {
+		t.Errorf("2025-12-28 confidence = %v, want the later run's 0.88", scores[1].Confidence)
+	}
+}
+
+func TestDrift_Alert(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		streak int
+		alert  bool
+	}{
+		{name: "healthy", scores: []float64{0.95, 0.94, 0.95}, streak: 0, alert: false},
+		{name: "one low week", scores: []float64{0.95, 0.94, 0.88}, streak: 1, alert: false},
+		{name: "declined for three weeks", scores: []float64{0.95, 0.89, 0.88, 0.87}, streak: 3, alert: true},
+		{name: "recovered", scores: []float64{0.89, 0.88, 0.87, 0.95}, streak: 0, alert: false},
+		{name: "no scores", scores: nil, streak: 0, alert: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDrift(scoreEntries(tt.scores...), 0.90, 3)
+			if got := d.LowStreak(); got != tt.streak {
+				t.Errorf("LowStreak() = %d, want %d", got, tt.streak)
+			}
+			if got := d.Alert(); got != tt.alert {
+				t.Errorf("Alert() = %v, want %v", got, tt.alert)
+			}
+		})
+	}
+}
+
+func TestDrift_DefaultWeeks(t *testing.T) {
+	if d := NewDrift(nil, 0.9, 0); d.Weeks != DefaultDriftWeeks {
+		t.Errorf("Weeks = %d, want %d", d.Weeks, DefaultDriftWeeks)
+	}
+}
+
+func TestDrift_Change(t *testing.T) {
+	d := NewDrift(scoreEntries(0.96, 0.94, 0.90, 0.86), 0.9, 2)
+	recent, previous, ok := d.Change()
+	if !ok {
+		t.Fatal("expected enough scores for a change")
+	}
+	if recent != 0.88 || previous != 0.95 {
+		t.Errorf("Change() = %v, %v; want 0.88, 0.95", recent, previous)
+	}
+
+	if _, _, ok := NewDrift(scoreEntries(0.96, 0.94, 0.90), 0.9, 2).Change(); ok {
+		t.Error("expected no change with fewer than two windows of scores")
+	}
+}
+
+func TestDrift_EarlyExits(t *testing.T) {
+	entries := scoreEntries(0.96, 0.97, 0.91)
+	entries[0].DetectionEarlyExit = true
+	entries[1].DetectionEarlyExit = true
+
+	drift := NewDrift(entries, 0.90, 3)
+	if got := drift.EarlyExits(); got != 2 {
+		t.Errorf("EarlyExits() = %d, want 2", got)
+	}
+	if !drift.Scores[0].EarlyExit || drift.Scores[2].EarlyExit {
+		t.Errorf("scores = %+v, want early exit on the first but not the last", drift.Scores)
+	}
+}
@@ -0,0 +1,154 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/resource"
+)
+
+// OutcomeSuccess marks a run that uploaded and emailed the recording
+const OutcomeSuccess = "success"
+
+// Entry records one processed service
+type Entry struct {
+	ServiceDate time.Time `json:"service_date"`
+	ProcessedAt time.Time `json:"processed_at"`
+	Minister    string    `json:"minister,omitempty"`
+	Title       string    `json:"title,omitempty"` // Sermon title
+	Scripture   string    `json:"scripture,omitempty"`
+	SourceFile  string    `json:"source_file,omitempty"`
+	StartTime   string    `json:"start_time,omitempty"` // HH:MM:SS in the source
+	EndTime     string    `json:"end_time,omitempty"`
+
+	DurationSeconds int   `json:"duration_seconds"`
+	VideoSize       int64 `json:"video_size,omitempty"` // Bytes
+	AudioSize       int64 `json:"audio_size,omitempty"`
+
+	VideoFileID string   `json:"video_file_id,omitempty"`
+	AudioFileID string   `json:"audio_file_id,omitempty"`
+	VideoURL    string   `json:"video_url,omitempty"`
+	AudioURL    string   `json:"audio_url,omitempty"`
+	Recipients  []string `json:"recipients,omitempty"` // To and CC addresses
+
+	// EmailSubject and EmailMessageIDs identify the recording email, so a
+	// correction can reply to it; there is one ID per recipient group
+	EmailSubject    string   `json:"email_subject,omitempty"`
+	EmailMessageIDs []string `json:"email_message_ids,omitempty"`
+
+	// FolderID is the Drive folder the run uploaded to when it was not the
+	// configured services folder, e.g. for a convention
+	FolderID string `json:"folder_id,omitempty"`
+
+	// DetectionConfidence is the best template match score (0.0-1.0) when the
+	// start was auto-detected; zero when it was given by hand
+	DetectionConfidence float64 `json:"detection_confidence,omitempty"`
+	CameraAngle         string  `json:"camera_angle,omitempty"`
+	// DetectionEarlyExit records that detection stopped on a confident frame
+	// bracket, to follow how often early exit kicks in
+	DetectionEarlyExit bool `json:"detection_early_exit,omitempty"`
+
+	// FFmpegVersion is the ffmpeg the run used, since versions differ in
+	// how they trim and normalize
+	FFmpegVersion string `json:"ffmpeg_version,omitempty"`
+
+	// Usage is the CPU, memory and disk use sampled during the ffmpeg
+	// steps, to see what processing costs the computer
+	Usage []resource.StepUsage `json:"usage,omitempty"`
+
+	Outcome string `json:"outcome"`
+
+	// Notes are operator remarks such as A/V issues during the service
+	Notes []Note `json:"notes,omitempty"`
+
+	// Corrections are follow-up emails that fixed the minister's name
+	Corrections []Correction `json:"corrections,omitempty"`
+}
+
+// Store persists history entries
+// This is a port that can be implemented by different infrastructure adapters
+type Store interface {
+	// Append adds an entry to the history
+	Append(e Entry) error
+
+	// List returns all entries in the order they were recorded
+	List() ([]Entry, error)
+
+	// AddNote attaches a note to the most recent entry for the service date
+	AddNote(serviceDate time.Time, note Note) error
+
+	// AddCorrection records a correction on the most recent entry for the
+	// service date
+	AddCorrection(serviceDate time.Time, c Correction) error
+}
+
+// Filter selects entries by service date. Zero bounds are open.
+type Filter struct {
+	From time.Time // Inclusive
+	To   time.Time // Inclusive
+}
+
+// Matches reports whether the entry's service date is within the filter
+func (f Filter) Matches(e Entry) bool {
+	day := dateOnly(e.ServiceDate)
+	if !f.From.IsZero() && day.Before(dateOnly(f.From)) {
+		return false
+	}
+	if !f.To.IsZero() && day.After(dateOnly(f.To)) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the entries that match the filter, keeping their order
+func (f Filter) Apply(entries []Entry) []Entry {
+	var matched []Entry
+	for _, e := range entries {
+		if f.Matches(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// OnDate returns the runs recorded for the service date, oldest first
+func OnDate(entries []Entry, serviceDate time.Time) []Entry {
+	return Filter{From: serviceDate, To: serviceDate}.Apply(entries)
+}
+
+// ProcessedRun returns the most recent successful run for the service date
+// made from sourceFile. Runs recorded without a source file, such as ones
+// backfilled from Drive, match any source.
+func ProcessedRun(entries []Entry, serviceDate time.Time, sourceFile string) (Entry, bool) {
+	runs := OnDate(entries, serviceDate)
+	for i := len(runs) - 1; i >= 0; i-- {
+		e := runs[i]
+		if e.Outcome == OutcomeSuccess && (e.SourceFile == "" || e.SourceFile == sourceFile) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// ParseFormat validates an export format name
+func ParseFormat(s string) (string, error) {
+	switch f := strings.ToLower(s); f {
+	case FormatCSV, FormatJSON:
+		return f, nil
+	case "":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (must be csv or json)", s)
+	}
+}
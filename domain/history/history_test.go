@@ -0,0 +1,153 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestFilter_Apply(t *testing.T) {
+	entries := []Entry{
+		{ServiceDate: date("2024-12-29")},
+		{ServiceDate: date("2025-01-05")},
+		{ServiceDate: date("2025-06-15")},
+		{ServiceDate: date("2025-12-28")},
+		{ServiceDate: date("2026-01-04")},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{name: "no bounds", filter: Filter{}, want: []string{"2024-12-29", "2025-01-05", "2025-06-15", "2025-12-28", "2026-01-04"}},
+		{name: "inclusive year", filter: Filter{From: date("2025-01-05"), To: date("2025-12-28")}, want: []string{"2025-01-05", "2025-06-15", "2025-12-28"}},
+		{name: "from only", filter: Filter{From: date("2025-12-01")}, want: []string{"2025-12-28", "2026-01-04"}},
+		{name: "to only", filter: Filter{To: date("2025-01-01")}, want: []string{"2024-12-29"}},
+		{name: "empty range", filter: Filter{From: date("2025-07-01"), To: date("2025-07-31")}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Apply(entries)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() returned %d entries, want %d", len(got), len(tt.want))
+			}
+			for i, e := range got {
+				if d := e.ServiceDate.Format("2006-01-02"); d != tt.want[i] {
+					t.Errorf("entry %d = %s, want %s", i, d, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilter_MatchesIgnoresTimeOfDay(t *testing.T) {
+	f := Filter{To: date("2025-12-28")}
+	e := Entry{ServiceDate: time.Date(2025, 12, 28, 18, 30, 0, 0, time.UTC)}
+	if !f.Matches(e) {
+		t.Error("expected an evening service on the To date to match")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", FormatCSV, false},
+		{"csv", FormatCSV, false},
+		{"JSON", FormatJSON, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestAttachNote(t *testing.T) {
+	entries := []Entry{
+		{ServiceDate: date("2025-12-28"), Minister: "first run"},
+		{ServiceDate: date("2026-01-04")},
+		{ServiceDate: date("2025-12-28"), Minister: "rerun"},
+	}
+	note, err := NewNote("  organ mic buzzing ", date("2025-12-29"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = AttachNote(entries, date("2025-12-28"), note)
+	if err != nil {
+		t.Fatalf("AttachNote() error = %v", err)
+	}
+	if len(entries[0].Notes) != 0 || len(entries[2].Notes) != 1 {
+		t.Fatalf("expected the note on the latest run, got %+v", entries)
+	}
+	if entries[2].Notes[0].Text != "organ mic buzzing" {
+		t.Errorf("note text = %q", entries[2].Notes[0].Text)
+	}
+
+	if _, err := AttachNote(entries, date("2025-07-06"), note); !errors.Is(err, ErrNoEntry) {
+		t.Errorf("AttachNote() on unknown date error = %v, want ErrNoEntry", err)
+	}
+	if _, err := NewNote("   ", time.Time{}); err == nil {
+		t.Error("expected an error for an empty note")
+	}
+}
+
+func TestProcessedRun(t *testing.T) {
+	entries := []Entry{
+		{ServiceDate: date("2025-12-28"), SourceFile: "2025-12-28 10-06-16.mp4", Outcome: OutcomeSuccess, Minister: "first"},
+		{ServiceDate: date("2025-12-28"), SourceFile: "2025-12-28 10-06-16.mp4", Outcome: OutcomeSuccess, Minister: "second"},
+		{ServiceDate: date("2026-01-04"), Outcome: OutcomeSuccess},
+		{ServiceDate: date("2026-01-11"), SourceFile: "2026-01-11 10-00-00.mp4", Outcome: "failed"},
+	}
+
+	tests := []struct {
+		name     string
+		date     string
+		source   string
+		want     bool
+		minister string
+	}{
+		{"same recording", "2025-12-28", "2025-12-28 10-06-16.mp4", true, "second"},
+		{"another recording that day", "2025-12-28", "2025-12-28 18-30-00.mp4", false, ""},
+		{"run without a source", "2026-01-04", "2026-01-04 10-00-00.mp4", true, ""},
+		{"unsuccessful run", "2026-01-11", "2026-01-11 10-00-00.mp4", false, ""},
+		{"no run", "2026-01-18", "2026-01-18 10-00-00.mp4", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, ok := ProcessedRun(entries, date(tt.date), tt.source)
+			if ok != tt.want || run.Minister != tt.minister {
+				t.Errorf("ProcessedRun() = %q, %v; want %q, %v", run.Minister, ok, tt.minister, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnDate(t *testing.T) {
+	entries := []Entry{
+		{ServiceDate: date("2025-12-28"), Minister: "first"},
+		{ServiceDate: date("2026-01-04")},
+		{ServiceDate: date("2025-12-28"), Minister: "second"},
+	}
+	got := OnDate(entries, date("2025-12-28"))
+	if len(got) != 2 || got[0].Minister != "first" || got[1].Minister != "second" {
+		t.Errorf("OnDate() = %+v, want both 2025-12-28 runs in order", got)
+	}
+}
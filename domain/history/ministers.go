@@ -0,0 +1,55 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// MinisterStat summarizes the services one minister appears in
+type MinisterStat struct {
+	Minister   string
+	Services   int
+	AvgSeconds int       // Average trimmed service length
+	LastServed time.Time // Most recent service date
+}
+
+// MinisterStats tallies services per minister, most services first and then
+// by name. A service processed more than once counts its latest run; entries
+// without a minister are left out.
+func MinisterStats(entries []Entry) []MinisterStat {
+	latest := make(map[time.Time]Entry)
+	for _, e := range entries {
+		latest[dateOnly(e.ServiceDate)] = e
+	}
+
+	byName := make(map[string]*MinisterStat)
+	totals := make(map[string]int)
+	for day, e := range latest {
+		if e.Minister == "" {
+			continue
+		}
+		stat, ok := byName[e.Minister]
+		if !ok {
+			stat = &MinisterStat{Minister: e.Minister}
+			byName[e.Minister] = stat
+		}
+		stat.Services++
+		totals[e.Minister] += e.DurationSeconds
+		if day.After(stat.LastServed) {
+			stat.LastServed = day
+		}
+	}
+
+	stats := make([]MinisterStat, 0, len(byName))
+	for name, stat := range byName {
+		stat.AvgSeconds = totals[name] / stat.Services
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Services != stats[j].Services {
+			return stats[i].Services > stats[j].Services
+		}
+		return stats[i].Minister < stats[j].Minister
+	})
+	return stats
+}
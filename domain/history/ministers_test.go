@@ -0,0 +1,40 @@
+package history
+
+import "testing"
+
+func TestMinisterStats(t *testing.T) {
+	entries := []Entry{
+		{ServiceDate: date("2025-01-05"), Minister: "Pr. Jane Doe", DurationSeconds: 3600},
+		{ServiceDate: date("2025-01-12"), Minister: "Pr. John Smith", DurationSeconds: 5000},
+		{ServiceDate: date("2025-02-02"), Minister: "Pr. Jane Doe", DurationSeconds: 4000},
+		// Reprocessed with a corrected minister; only this run counts
+		{ServiceDate: date("2025-01-12"), Minister: "Pr. Jane Doe", DurationSeconds: 5000},
+		{ServiceDate: date("2025-03-02"), DurationSeconds: 4200},
+		{ServiceDate: date("2025-03-09"), Minister: "Pr. Adam Brown", DurationSeconds: 3000},
+	}
+
+	stats := MinisterStats(entries)
+
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 ministers, got %+v", stats)
+	}
+	jane := stats[0]
+	if jane.Minister != "Pr. Jane Doe" || jane.Services != 3 {
+		t.Errorf("expected Pr. Jane Doe with 3 services first, got %+v", jane)
+	}
+	if jane.AvgSeconds != 4200 {
+		t.Errorf("expected average 4200 seconds, got %d", jane.AvgSeconds)
+	}
+	if !jane.LastServed.Equal(date("2025-02-02")) {
+		t.Errorf("expected last served 2025-02-02, got %s", jane.LastServed)
+	}
+	if stats[1].Minister != "Pr. Adam Brown" || stats[1].Services != 1 {
+		t.Errorf("expected Pr. Adam Brown second, got %+v", stats[1])
+	}
+}
+
+func TestMinisterStats_Empty(t *testing.T) {
+	if stats := MinisterStats(nil); len(stats) != 0 {
+		t.Errorf("expected no stats, got %+v", stats)
+	}
+}
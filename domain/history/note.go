@@ -0,0 +1,37 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNoEntry is returned when a note targets a service that was never recorded
+var ErrNoEntry = errors.New("no processed service recorded")
+
+// Note is free text attached to a processed service, e.g. "organ mic buzzing"
+type Note struct {
+	Text    string    `json:"text"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// NewNote trims the text and rejects empty notes
+func NewNote(text string, at time.Time) (Note, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Note{}, fmt.Errorf("note text is empty")
+	}
+	return Note{Text: text, AddedAt: at}, nil
+}
+
+// AttachNote appends the note to the most recent entry for the service date
+// and returns the updated entries. It returns ErrNoEntry if none matches.
+func AttachNote(entries []Entry, serviceDate time.Time, note Note) ([]Entry, error) {
+	i, err := latestIndex(entries, serviceDate)
+	if err != nil {
+		return entries, err
+	}
+	entries[i].Notes = append(entries[i].Notes, note)
+	return entries, nil
+}
@@ -0,0 +1,50 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultStartSamples is how many recent services the detection search range
+// adapts to
+const DefaultStartSamples = 8
+
+// DetectedStarts returns the start offsets, in seconds, of the last n
+// auto-detected services, oldest first. A service processed more than once
+// keeps its latest run; runs with a start given by hand are skipped.
+func DetectedStarts(entries []Entry, n int) []int {
+	type start struct {
+		day     time.Time
+		seconds int
+	}
+	latest := make(map[time.Time]start)
+	for _, e := range entries {
+		if e.DetectionConfidence <= 0 {
+			continue
+		}
+		var h, m, s int
+		if _, err := fmt.Sscanf(e.StartTime, "%d:%d:%d", &h, &m, &s); err != nil {
+			continue
+		}
+		day := dateOnly(e.ServiceDate)
+		latest[day] = start{day: day, seconds: h*3600 + m*60 + s}
+	}
+
+	starts := make([]start, 0, len(latest))
+	for _, s := range latest {
+		starts = append(starts, s)
+	}
+	sort.Slice(starts, func(i, j int) bool {
+		return starts[i].day.Before(starts[j].day)
+	})
+	if n > 0 && len(starts) > n {
+		starts = starts[len(starts)-n:]
+	}
+
+	seconds := make([]int, len(starts))
+	for i, s := range starts {
+		seconds[i] = s.seconds
+	}
+	return seconds
+}
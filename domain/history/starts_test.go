@@ -0,0 +1,24 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectedStarts(t *testing.T) {
+	entries := []Entry{
+		{ServiceDate: date("2025-12-21"), StartTime: "00:09:00", DetectionConfidence: 0.9},
+		{ServiceDate: date("2025-12-07"), StartTime: "00:07:30", DetectionConfidence: 0.9},
+		{ServiceDate: date("2025-12-14"), StartTime: "00:12:00"}, // given by hand
+		{ServiceDate: date("2025-12-28"), StartTime: "00:08:00", DetectionConfidence: 0.9},
+		{ServiceDate: date("2025-12-28"), StartTime: "00:10:15", DetectionConfidence: 0.92},
+		{ServiceDate: date("2026-01-04"), StartTime: "bad", DetectionConfidence: 0.9},
+	}
+
+	if got, want := DetectedStarts(entries, 0), []int{450, 540, 615}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectedStarts() = %v, want %v", got, want)
+	}
+	if got, want := DetectedStarts(entries, 2), []int{540, 615}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectedStarts(2) = %v, want the latest %v", got, want)
+	}
+}
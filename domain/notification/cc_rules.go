@@ -0,0 +1,123 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CCRule adds CC recipients when a service matches its conditions. Every
+// condition that is set must hold for the rule to fire.
+type CCRule struct {
+	Name string
+
+	// ServiceTypes fires for any of these service types (case-insensitive)
+	ServiceTypes []string
+
+	// Ministers fires for any of these minister names (case-insensitive)
+	Ministers []string
+
+	// GuestMinister fires when the minister is not one of the local ministers
+	GuestMinister bool
+
+	CC []Recipient
+}
+
+// Validate checks that the rule has a name, at least one condition, and recipients
+func (r CCRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("cc rule has no name")
+	}
+	if len(r.ServiceTypes) == 0 && len(r.Ministers) == 0 && !r.GuestMinister {
+		return fmt.Errorf("cc rule %q has no conditions", r.Name)
+	}
+	if len(r.CC) == 0 {
+		return fmt.Errorf("cc rule %q has no cc recipients", r.Name)
+	}
+	return nil
+}
+
+// CCContext describes the service a CC decision is made for
+type CCContext struct {
+	Minister    string
+	ServiceType string
+}
+
+// FiredRule explains why a CC rule applied and who it added
+type FiredRule struct {
+	Rule    string
+	Reasons []string
+	Added   []Recipient
+}
+
+// CCRuleSet is the configured CC rules with the local ministers that guest
+// rules compare against
+type CCRuleSet struct {
+	Rules          []CCRule
+	LocalMinisters []string
+}
+
+// Evaluate applies the rules to a service, returning cc extended with each fired
+// rule's recipients (deduplicated by address) and the rules that fired
+func (s CCRuleSet) Evaluate(cc []Recipient, ctx CCContext) ([]Recipient, []FiredRule) {
+	result := append([]Recipient(nil), cc...)
+	seen := make(map[string]bool, len(cc))
+	for _, r := range cc {
+		seen[strings.ToLower(r.Address)] = true
+	}
+
+	var fired []FiredRule
+	for _, rule := range s.Rules {
+		reasons, ok := s.match(rule, ctx)
+		if !ok {
+			continue
+		}
+		f := FiredRule{Rule: rule.Name, Reasons: reasons}
+		for _, r := range rule.CC {
+			if seen[strings.ToLower(r.Address)] {
+				continue
+			}
+			seen[strings.ToLower(r.Address)] = true
+			result = append(result, r)
+			f.Added = append(f.Added, r)
+		}
+		fired = append(fired, f)
+	}
+	return result, fired
+}
+
+// match reports whether every condition of the rule holds, with a reason for each
+func (s CCRuleSet) match(rule CCRule, ctx CCContext) ([]string, bool) {
+	var reasons []string
+	if len(rule.ServiceTypes) > 0 {
+		if !containsFold(rule.ServiceTypes, ctx.ServiceType) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("service type is %q", ctx.ServiceType))
+	}
+	if len(rule.Ministers) > 0 {
+		if !containsFold(rule.Ministers, ctx.Minister) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("minister is %q", ctx.Minister))
+	}
+	if rule.GuestMinister {
+		if ctx.Minister == "" || containsFold(s.LocalMinisters, ctx.Minister) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("minister %q is not a local minister", ctx.Minister))
+	}
+	return reasons, true
+}
+
+func containsFold(list []string, v string) bool {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return false
+	}
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), v) {
+			return true
+		}
+	}
+	return false
+}
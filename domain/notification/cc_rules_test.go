@@ -0,0 +1,121 @@
+package notification
+
+import "testing"
+
+func TestCCRuleSet_Evaluate(t *testing.T) {
+	rector := Recipient{Name: "Rector", Address: "rector@example.com"}
+	district := Recipient{Name: "District", Address: "district@example.com"}
+	office := Recipient{Name: "Office", Address: "office@example.com"}
+
+	set := CCRuleSet{
+		Rules: []CCRule{
+			{Name: "guest-minister", GuestMinister: true, CC: []Recipient{rector}},
+			{Name: "feast-day", ServiceTypes: []string{"Feast Day"}, CC: []Recipient{district, office}},
+		},
+		LocalMinisters: []string{"Pr. Henkel", "henkel"},
+	}
+
+	tests := []struct {
+		name      string
+		ctx       CCContext
+		wantCC    []string
+		wantFired []string
+	}{
+		{
+			name:   "local minister on a regular service",
+			ctx:    CCContext{Minister: "Pr. Henkel", ServiceType: "Service"},
+			wantCC: []string{"office@example.com"},
+		},
+		{
+			name:      "guest minister",
+			ctx:       CCContext{Minister: "Rev. Visitor", ServiceType: "Service"},
+			wantCC:    []string{"office@example.com", "rector@example.com"},
+			wantFired: []string{"guest-minister"},
+		},
+		{
+			name:      "guest minister on a feast day",
+			ctx:       CCContext{Minister: "Rev. Visitor", ServiceType: "feast day"},
+			wantCC:    []string{"office@example.com", "rector@example.com", "district@example.com"},
+			wantFired: []string{"guest-minister", "feast-day"},
+		},
+		{
+			name:   "no minister is not a guest",
+			ctx:    CCContext{ServiceType: "Service"},
+			wantCC: []string{"office@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc, fired := set.Evaluate([]Recipient{office}, tt.ctx)
+
+			var gotCC []string
+			for _, r := range cc {
+				gotCC = append(gotCC, r.Address)
+			}
+			if !equalStrings(gotCC, tt.wantCC) {
+				t.Errorf("cc = %v, want %v", gotCC, tt.wantCC)
+			}
+
+			var gotFired []string
+			for _, f := range fired {
+				gotFired = append(gotFired, f.Rule)
+				if len(f.Reasons) == 0 {
+					t.Errorf("rule %q fired without a reason", f.Rule)
+				}
+			}
+			if !equalStrings(gotFired, tt.wantFired) {
+				t.Errorf("fired = %v, want %v", gotFired, tt.wantFired)
+			}
+		})
+	}
+}
+
+func TestCCRuleSet_AllConditionsMustHold(t *testing.T) {
+	set := CCRuleSet{Rules: []CCRule{{
+		Name:          "guest-feast",
+		ServiceTypes:  []string{"Feast Day"},
+		GuestMinister: true,
+		CC:            []Recipient{{Name: "Rector", Address: "rector@example.com"}},
+	}}}
+
+	if _, fired := set.Evaluate(nil, CCContext{Minister: "Rev. Visitor", ServiceType: "Service"}); len(fired) != 0 {
+		t.Errorf("expected no rules to fire, got %+v", fired)
+	}
+	if _, fired := set.Evaluate(nil, CCContext{Minister: "Rev. Visitor", ServiceType: "Feast Day"}); len(fired) != 1 || len(fired[0].Reasons) != 2 {
+		t.Errorf("expected one rule with two reasons, got %+v", fired)
+	}
+}
+
+func TestCCRule_Validate(t *testing.T) {
+	cc := []Recipient{{Name: "Rector", Address: "rector@example.com"}}
+	tests := []struct {
+		name    string
+		rule    CCRule
+		wantErr bool
+	}{
+		{"valid", CCRule{Name: "guest", GuestMinister: true, CC: cc}, false},
+		{"no name", CCRule{GuestMinister: true, CC: cc}, true},
+		{"no conditions", CCRule{Name: "always", CC: cc}, true},
+		{"no recipients", CCRule{Name: "guest", GuestMinister: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNothingToCorrect is returned when a correction names the minister the
+// original email already had
+var ErrNothingToCorrect = errors.New("nothing to correct")
+
+// CorrectionTemplate is a short follow-up to a recording email that named the
+// wrong minister. It repeats the links so the reply stands on its own.
+var CorrectionTemplate = EmailTemplate{
+	SubjectFormat: "Correction: {{.ChurchName}}: Recording of Service on {{.DateFormatted}}",
+	PlainText: `{{.Greeting}}
+
+A quick correction to my earlier email about the service on {{.DateRange}}: it was led by {{.MinisterName}}{{if .PreviousMinister}}, not {{.PreviousMinister}}{{end}}.
+
+The recording links are unchanged:
+{{if .AudioURL}}Audio: {{.AudioURL}}
+{{end}}{{if .VideoURL}}Video: {{.VideoURL}}
+{{end}}
+Sorry for the mix-up!
+{{.SenderName}}`,
+	HTML: `<div dir="ltr">{{.Greeting}}<br><br>
+A quick correction to my earlier email about the service on {{.DateRange}}: it was led by {{.MinisterName}}{{if .PreviousMinister}}, not {{.PreviousMinister}}{{end}}.<br><br>
+The recording links are unchanged:{{if .AudioURL}} <a href="{{.AudioURL}}">audio</a>{{if .VideoURL}} and{{end}}{{end}}{{if .VideoURL}} <a href="{{.VideoURL}}">video</a>{{end}}.<br><br>
+Sorry for the mix-up!<br>
+{{.SenderName}}</div>`,
+}
+
+// ReplySubject returns the subject of a reply to an email, adding "Re: "
+// unless it is already there
+func ReplySubject(subject string) string {
+	if strings.HasPrefix(strings.ToLower(subject), "re:") {
+		return subject
+	}
+	return "Re: " + subject
+}
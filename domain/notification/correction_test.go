@@ -0,0 +1,64 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCorrectionTemplate(t *testing.T) {
+	req := &EmailRequest{
+		To:               []Recipient{{Name: "John Doe", Address: "john@example.com"}},
+		ServiceDate:      time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		MinisterName:     "Pr. Jones",
+		PreviousMinister: "Pr. Smith",
+		AudioURL:         "https://drive.google.com/file/d/abc/view",
+		SenderName:       "Jonathan",
+	}
+	data := NewTemplateData(req, time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC))
+
+	body, err := CorrectionTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	for _, want := range []string{
+		"Dear John,",
+		"service on 12/28/2025: it was led by Pr. Jones, not Pr. Smith.",
+		"Audio: https://drive.google.com/file/d/abc/view",
+		"Sorry for the mix-up!\nJonathan",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("plain text missing %q in:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "Video:") {
+		t.Errorf("plain text should not link a missing video:\n%s", body)
+	}
+
+	html, err := CorrectionTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if !strings.Contains(html, `unchanged: <a href="https://drive.google.com/file/d/abc/view">audio</a>.`) {
+		t.Errorf("HTML should link the audio only:\n%s", html)
+	}
+
+	data.PreviousMinister = ""
+	body, _ = CorrectionTemplate.RenderPlainText(data)
+	if !strings.Contains(body, "it was led by Pr. Jones.") {
+		t.Errorf("without a previous minister the body should only name the new one:\n%s", body)
+	}
+}
+
+func TestReplySubject(t *testing.T) {
+	tests := map[string]string{
+		"White Plains: Recording of Service on 12/28/2025": "Re: White Plains: Recording of Service on 12/28/2025",
+		"Re: Recording": "Re: Recording",
+		"RE: Recording": "RE: Recording",
+	}
+	for in, want := range tests {
+		if got := ReplySubject(in); got != want {
+			t.Errorf("ReplySubject(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package notification
 
 import (
+	"context"
 	"time"
 )
 
@@ -10,16 +11,72 @@ type Recipient struct {
 	Address string
 }
 
+// String formats the recipient as "Name <address>", or just the address when
+// it has no name
+func (r Recipient) String() string {
+	if r.Name == "" {
+		return r.Address
+	}
+	return r.Name + " <" + r.Address + ">"
+}
+
+// AudioVersion is an extra copy of the audio, such as a small one for
+// forwarding on WhatsApp
+type AudioVersion struct {
+	Label string // Quality and size, e.g. "64k, 28.8 MB"
+	URL   string
+}
+
 // EmailRequest contains all the data needed to send a service recording notification
 type EmailRequest struct {
-	To           []Recipient // Primary recipients
-	CC           []Recipient // Carbon copy recipients
-	ServiceDate  time.Time   // Date of the service
-	MinisterName string      // Name of the minister (e.g., "Pr. Smith")
-	AudioURL     string      // Google Drive URL for audio file
-	VideoURL     string      // Google Drive URL for video file
-	ChurchName   string      // Name of the church for subject line
-	SenderName   string      // Name to sign the email (e.g., "Jonathan")
+	To             []Recipient    // Primary recipients
+	CC             []Recipient    // Carbon copy recipients
+	ServiceDate    time.Time      // Date of the service
+	ServiceEndDate time.Time      // Date the service ended, when it ran past midnight (optional)
+	MinisterName   string         // Name of the minister (e.g., "Pr. Smith")
+	Title          string         // Sermon title (optional)
+	Scripture      string         // Scripture reading (optional)
+	AudioURL       string         // Google Drive URL for audio file
+	VideoURL       string         // Google Drive URL for video file
+	ChurchName     string         // Name of the church for subject line
+	SenderName     string         // Name to sign the email (e.g., "Jonathan")
+	Subject        string         // Pre-rendered subject; the sender's template subject is used when empty
+	Context        string         // Extra paragraph for the recipients' group (optional)
+	Template       *EmailTemplate // Replaces the sender's template when set, e.g. for a recipient group
+	Greeting       GreetingRules  // How the body greets the To recipients
+
+	// AudioVersions are extra copies of the audio at other bitrates (optional)
+	AudioVersions []AudioVersion
+
+	// Mirror URLs on the alternate download server, for recipients without Drive access
+	MirrorAudioURL string
+	MirrorVideoURL string
+
+	// FolderURL links to the Drive folder of all services (optional)
+	FolderURL string
+
+	// LivestreamURL links to the service's livestream recording or the
+	// channel it streams on (optional)
+	LivestreamURL string
+
+	// PreviousMinister is the minister a correction replaces (optional)
+	PreviousMinister string
+
+	// AccessEnds is when privately shared links stop working (optional)
+	AccessEnds time.Time
+
+	// MessageID is the Message-ID header to send with; the sender fills it
+	// in when empty, so a later correction can reply to the email
+	MessageID string
+
+	// InReplyTo lists the Message-IDs this email replies to, threading it
+	// under an earlier email (optional)
+	InReplyTo []string
+}
+
+// Overnight reports whether the service ran past midnight into a later date
+func (r *EmailRequest) Overnight() bool {
+	return r.ServiceEndDate.After(r.ServiceDate)
 }
 
 // Validate checks that the email request has all required fields
@@ -43,5 +100,5 @@ func (r *EmailRequest) Validate() error {
 
 // EmailSender defines the interface for sending emails
 type EmailSender interface {
-	Send(req *EmailRequest) error
+	Send(ctx context.Context, req *EmailRequest) error
 }
@@ -87,3 +87,12 @@ func TestEmailRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestRecipient_String(t *testing.T) {
+	if got := (Recipient{Name: "Jane Doe", Address: "jane@example.com"}).String(); got != "Jane Doe <jane@example.com>" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (Recipient{Address: "jane@example.com"}).String(); got != "jane@example.com" {
+		t.Errorf("String() without a name = %q", got)
+	}
+}
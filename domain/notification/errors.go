@@ -21,6 +21,10 @@ var (
 	// ErrAmbiguousRecipient is returned when multiple recipients match a query
 	ErrAmbiguousRecipient = errors.New("multiple recipients match query")
 
+	// ErrExclusionUnmatched is returned when an excluded person is not among
+	// the resolved recipients, which is usually a typo
+	ErrExclusionUnmatched = errors.New("exclusion matches no recipient")
+
 	// ErrSendFailed is returned when the email fails to send
 	ErrSendFailed = errors.New("failed to send email")
 )
@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GreetingStyle is the register the email greeting is written in
+type GreetingStyle string
+
+const (
+	GreetingCasual GreetingStyle = "casual" // Dear John & Jane, / Hey Everyone!
+	GreetingFormal GreetingStyle = "formal" // Dear John Doe and Jane Smith, / Dear Friends,
+)
+
+// DefaultGreetingMaxNames is how many recipients are named before the group
+// greeting is used
+const DefaultGreetingMaxNames = 2
+
+// greetingPhrasing is the wording a style uses where the rules don't say
+type greetingPhrasing struct {
+	group       string // More recipients than MaxNames
+	none        string // No recipients
+	conjunction string // Before the last name
+	fullNames   bool   // Full names instead of first names
+}
+
+var greetingStyles = map[GreetingStyle]greetingPhrasing{
+	GreetingCasual: {group: "Hey Everyone!", none: "Hello,", conjunction: "&"},
+	GreetingFormal: {group: "Dear Friends,", none: "Dear Friends,", conjunction: "and", fullNames: true},
+}
+
+// GreetingRules decide how an email greets its recipients. The zero value is
+// the casual style naming up to two people.
+type GreetingRules struct {
+	Style      GreetingStyle
+	MaxNames   int    // Most recipients named before Group is used (default 2)
+	Salutation string // Word before the names (default "Dear")
+	Group      string // Greeting for more than MaxNames recipients (default from Style)
+}
+
+// ParseGreetingStyle parses a configured style; empty means casual
+func ParseGreetingStyle(s string) (GreetingStyle, error) {
+	style := GreetingStyle(strings.ToLower(strings.TrimSpace(s)))
+	if style == "" {
+		return GreetingCasual, nil
+	}
+	if _, ok := greetingStyles[style]; !ok {
+		return "", fmt.Errorf("unknown style %q (want casual or formal)", s)
+	}
+	return style, nil
+}
+
+// Validate checks the style is known and MaxNames is usable
+func (g GreetingRules) Validate() error {
+	if g.Style != "" {
+		if _, ok := greetingStyles[g.Style]; !ok {
+			return fmt.Errorf("unknown style %q (want casual or formal)", g.Style)
+		}
+	}
+	if g.MaxNames < 0 {
+		return fmt.Errorf("max_names must not be negative, got %d", g.MaxNames)
+	}
+	return nil
+}
+
+// Format greets recipients by name, or with the group greeting when there
+// are more of them than MaxNames
+func (g GreetingRules) Format(recipients []Recipient) string {
+	phrasing, ok := greetingStyles[g.Style]
+	if !ok {
+		phrasing = greetingStyles[GreetingCasual]
+	}
+	if len(recipients) == 0 {
+		return phrasing.none
+	}
+	maxNames := g.MaxNames
+	if maxNames == 0 {
+		maxNames = DefaultGreetingMaxNames
+	}
+	if len(recipients) > maxNames {
+		if g.Group != "" {
+			return g.Group
+		}
+		return phrasing.group
+	}
+
+	names := make([]string, len(recipients))
+	for i, r := range recipients {
+		if phrasing.fullNames {
+			names[i] = strings.TrimSpace(r.Name)
+		} else {
+			names[i] = getFirstName(strings.TrimSpace(r.Name))
+		}
+		if names[i] == "" {
+			names[i] = "Friend"
+		}
+	}
+	salutation := g.Salutation
+	if salutation == "" {
+		salutation = "Dear"
+	}
+	return fmt.Sprintf("%s %s,", salutation, joinNames(names, phrasing.conjunction))
+}
+
+// joinNames lists names as "A", "A & B" or "A, B & C"
+func joinNames(names []string, conjunction string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " " + conjunction + " " + names[len(names)-1]
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGreetingRules_Format(t *testing.T) {
+	one := []Recipient{{Name: "John Doe"}}
+	two := []Recipient{{Name: "John Doe"}, {Name: "Jane Smith"}}
+	three := []Recipient{{Name: "John Doe"}, {Name: "Jane Smith"}, {Name: "Alice Brown"}}
+
+	tests := []struct {
+		name       string
+		rules      GreetingRules
+		recipients []Recipient
+		want       string
+	}{
+		{"casual one", GreetingRules{}, one, "Dear John,"},
+		{"casual two", GreetingRules{Style: GreetingCasual}, two, "Dear John & Jane,"},
+		{"casual three", GreetingRules{}, three, "Hey Everyone!"},
+		{"casual none", GreetingRules{}, nil, "Hello,"},
+		{"casual three named", GreetingRules{MaxNames: 3}, three, "Dear John, Jane & Alice,"},
+		{"casual salutation", GreetingRules{Salutation: "Hi"}, two, "Hi John & Jane,"},
+		{"casual group", GreetingRules{MaxNames: 1, Group: "Hi all!"}, two, "Hi all!"},
+		{"formal one", GreetingRules{Style: GreetingFormal}, one, "Dear John Doe,"},
+		{"formal two", GreetingRules{Style: GreetingFormal}, two, "Dear John Doe and Jane Smith,"},
+		{"formal three", GreetingRules{Style: GreetingFormal}, three, "Dear Friends,"},
+		{"formal none", GreetingRules{Style: GreetingFormal}, nil, "Dear Friends,"},
+		{"formal three named", GreetingRules{Style: GreetingFormal, MaxNames: 3}, three, "Dear John Doe, Jane Smith and Alice Brown,"},
+		{"formal group", GreetingRules{Style: GreetingFormal, Group: "Dear Brothers and Sisters,"}, three, "Dear Brothers and Sisters,"},
+		{"formal no name", GreetingRules{Style: GreetingFormal}, []Recipient{{Address: "a@example.com"}}, "Dear Friend,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Format(tt.recipients); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGreetingStyle(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    GreetingStyle
+		wantErr bool
+	}{
+		{"", GreetingCasual, false},
+		{"casual", GreetingCasual, false},
+		{" Formal ", GreetingFormal, false},
+		{"chatty", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseGreetingStyle(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseGreetingStyle(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGreetingRules_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   GreetingRules
+		wantErr string
+	}{
+		{"zero value", GreetingRules{}, ""},
+		{"formal", GreetingRules{Style: GreetingFormal, MaxNames: 4}, ""},
+		{"unknown style", GreetingRules{Style: "chatty"}, "unknown style"},
+		{"negative max names", GreetingRules{MaxNames: -1}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTemplateData_UsesGreetingRules(t *testing.T) {
+	req := &EmailRequest{
+		To:       []Recipient{{Name: "John Doe"}},
+		Greeting: GreetingRules{Style: GreetingFormal},
+	}
+	if got := NewTemplateData(req, req.ServiceDate).Greeting; got != "Dear John Doe," {
+		t.Errorf("Greeting = %q, want %q", got, "Dear John Doe,")
+	}
+}
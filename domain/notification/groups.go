@@ -0,0 +1,125 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecipientGroup is a set of recipients who get their own variant of the
+// notification, e.g. the choir with a paragraph about next week's rehearsal
+type RecipientGroup struct {
+	Name    string
+	Members []Recipient
+
+	// Context is an extra paragraph shown by the template after the links
+	Context string
+
+	// Template replaces the sender's template for this group when set
+	Template *EmailTemplate
+}
+
+// Validate checks that the group has a name and members, and that its template parses
+func (g RecipientGroup) Validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("recipient group has no name")
+	}
+	if len(g.Members) == 0 {
+		return fmt.Errorf("recipient group %q has no members", g.Name)
+	}
+	if g.Template != nil {
+		if err := g.Template.Validate(); err != nil {
+			return fmt.Errorf("recipient group %q: %w", g.Name, err)
+		}
+	}
+	return nil
+}
+
+// has reports whether r is a member, matching addresses case-insensitively
+func (g RecipientGroup) has(r Recipient) bool {
+	for _, m := range g.Members {
+		if strings.EqualFold(m.Address, r.Address) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecipientGroups is the configured groups, in the order their emails are sent
+type RecipientGroups []RecipientGroup
+
+// Validate checks each group, and that names are unique and no recipient is in two groups
+func (gs RecipientGroups) Validate() error {
+	names := make(map[string]bool, len(gs))
+	members := make(map[string]string)
+	for _, g := range gs {
+		if err := g.Validate(); err != nil {
+			return err
+		}
+		if names[g.Name] {
+			return fmt.Errorf("recipient group %q is defined twice", g.Name)
+		}
+		names[g.Name] = true
+		for _, m := range g.Members {
+			address := strings.ToLower(m.Address)
+			if other, ok := members[address]; ok && other != g.Name {
+				return fmt.Errorf("%s is in both recipient groups %q and %q", m.Address, other, g.Name)
+			}
+			members[address] = g.Name
+		}
+	}
+	return nil
+}
+
+// DefaultGroup names the batch of recipients outside every group
+const DefaultGroup = "default"
+
+// Batch is the recipients that get one variant of the email. Group is nil
+// for recipients outside every group, who get the standard email.
+type Batch struct {
+	Group *RecipientGroup
+	To    []Recipient
+}
+
+// GroupName returns the batch's group name, or DefaultGroup for ungrouped recipients
+func (b Batch) GroupName() string {
+	if b.Group == nil {
+		return DefaultGroup
+	}
+	return b.Group.Name
+}
+
+// Split divides recipients into batches: ungrouped recipients first, then
+// each group with members among them, in configured order. Empty batches
+// are left out.
+func (gs RecipientGroups) Split(to []Recipient) []Batch {
+	var ungrouped []Recipient
+	grouped := make([][]Recipient, len(gs))
+	for _, r := range to {
+		i := gs.indexOf(r)
+		if i < 0 {
+			ungrouped = append(ungrouped, r)
+			continue
+		}
+		grouped[i] = append(grouped[i], r)
+	}
+
+	var batches []Batch
+	if len(ungrouped) > 0 {
+		batches = append(batches, Batch{To: ungrouped})
+	}
+	for i := range gs {
+		if len(grouped[i]) > 0 {
+			batches = append(batches, Batch{Group: &gs[i], To: grouped[i]})
+		}
+	}
+	return batches
+}
+
+func (gs RecipientGroups) indexOf(r Recipient) int {
+	for i, g := range gs {
+		if g.has(r) {
+			return i
+		}
+	}
+	return -1
+}
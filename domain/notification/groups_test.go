@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecipientGroups_Split(t *testing.T) {
+	jonathan := Recipient{Name: "Jonathan White", Address: "jonathan@example.com"}
+	mary := Recipient{Name: "Mary Singer", Address: "mary@example.com"}
+	smith := Recipient{Name: "Pr. Smith", Address: "smith@example.com"}
+
+	groups := RecipientGroups{
+		{Name: "ministers", Members: []Recipient{smith}},
+		{Name: "choir", Members: []Recipient{{Name: "Mary", Address: "MARY@example.com"}}},
+	}
+
+	batches := groups.Split([]Recipient{mary, jonathan, smith})
+	var got []string
+	for _, b := range batches {
+		names := make([]string, len(b.To))
+		for i, r := range b.To {
+			names[i] = r.Name
+		}
+		got = append(got, b.GroupName()+"="+strings.Join(names, ","))
+	}
+	want := "default=Jonathan White ministers=Pr. Smith choir=Mary Singer"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Split() = %v, want %s", got, want)
+	}
+
+	if batches := groups.Split([]Recipient{jonathan}); len(batches) != 1 || batches[0].Group != nil {
+		t.Errorf("Split() of ungrouped recipients = %+v, want one default batch", batches)
+	}
+	if batches := RecipientGroups(nil).Split(nil); len(batches) != 0 {
+		t.Errorf("Split(nil) = %+v, want no batches", batches)
+	}
+}
+
+func TestRecipientGroups_Validate(t *testing.T) {
+	mary := Recipient{Name: "Mary Singer", Address: "mary@example.com"}
+
+	tests := []struct {
+		name    string
+		groups  RecipientGroups
+		wantErr string
+	}{
+		{"valid", RecipientGroups{{Name: "choir", Members: []Recipient{mary}}}, ""},
+		{"no name", RecipientGroups{{Members: []Recipient{mary}}}, "has no name"},
+		{"no members", RecipientGroups{{Name: "choir"}}, "has no members"},
+		{
+			"duplicate name",
+			RecipientGroups{{Name: "choir", Members: []Recipient{mary}}, {Name: "choir", Members: []Recipient{{Address: "x@example.com"}}}},
+			"defined twice",
+		},
+		{
+			"member of two groups",
+			RecipientGroups{{Name: "choir", Members: []Recipient{mary}}, {Name: "board", Members: []Recipient{{Address: "Mary@example.com"}}}},
+			`in both recipient groups "choir" and "board"`,
+		},
+		{
+			"broken template",
+			RecipientGroups{{Name: "choir", Members: []Recipient{mary}, Template: &EmailTemplate{PlainText: "{{if"}}},
+			"invalid plain text template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.groups.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultSubjectTemplate produces the original "Church: Recording of Service on MM/DD/YYYY" subject
+const DefaultSubjectTemplate = "{church}: Recording of Service on {date}"
+
+// DefaultServiceType is used for {service_type} when none is given
+const DefaultServiceType = "Service"
+
+// subjectVariables lists the placeholders a subject template may use
+var subjectVariables = []string{"church", "date", "date_range", "minister", "service_type", "label", "title", "scripture"}
+
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// SubjectVars holds the values substituted into a subject template
+type SubjectVars struct {
+	Church      string // {church}
+	Date        string // {date}, formatted MM/DD/YYYY
+	DateRange   string // {date_range}, {date} or "12/31/2025 to 01/01/2026" past midnight
+	Minister    string // {minister}
+	ServiceType string // {service_type}, e.g. "Service" or "Evening Service"
+	Label       string // {label}, free text such as "Confirmation"
+	Title       string // {title}, the sermon title
+	Scripture   string // {scripture}, e.g. "John 10:11-16"
+}
+
+// SubjectTemplate is a validated subject line with {variable} placeholders
+type SubjectTemplate struct {
+	raw string
+}
+
+// ParseSubjectTemplate validates a subject template. An empty string yields
+// the default template.
+func ParseSubjectTemplate(s string) (*SubjectTemplate, error) {
+	if strings.TrimSpace(s) == "" {
+		s = DefaultSubjectTemplate
+	}
+
+	for _, m := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+		if !isSubjectVariable(m[1]) {
+			return nil, fmt.Errorf("unknown subject variable {%s} (available: {%s})", m[1], strings.Join(subjectVariables, "}, {"))
+		}
+	}
+	if rest := placeholderPattern.ReplaceAllString(s, ""); strings.ContainsAny(rest, "{}") {
+		return nil, fmt.Errorf("unmatched brace in subject template %q", s)
+	}
+
+	return &SubjectTemplate{raw: s}, nil
+}
+
+func isSubjectVariable(name string) bool {
+	for _, v := range subjectVariables {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Render substitutes vars into the template. Runs of whitespace left by empty
+// variables are collapsed.
+func (t *SubjectTemplate) Render(vars SubjectVars) string {
+	r := strings.NewReplacer(
+		"{church}", vars.Church,
+		"{date}", vars.Date,
+		"{date_range}", vars.DateRange,
+		"{minister}", vars.Minister,
+		"{service_type}", vars.ServiceType,
+		"{label}", vars.Label,
+		"{title}", vars.Title,
+		"{scripture}", vars.Scripture,
+	)
+	return strings.Join(strings.Fields(r.Replace(t.raw)), " ")
+}
+
+// String returns the template source
+func (t *SubjectTemplate) String() string {
+	return t.raw
+}
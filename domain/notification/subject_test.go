@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSubjectTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "empty uses default", input: ""},
+		{name: "all variables", input: "{church} {date} {minister} {service_type} {label}"},
+		{name: "no variables", input: "Service recording"},
+		{name: "unknown variable", input: "{church}: {pastor}", wantErr: "unknown subject variable {pastor}"},
+		{name: "unmatched brace", input: "{church: Recording", wantErr: "unmatched brace"},
+		{name: "stray closing brace", input: "{church}} Recording", wantErr: "unmatched brace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseSubjectTemplate(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseSubjectTemplate(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSubjectTemplate(%q) unexpected error: %v", tt.input, err)
+			}
+			if tmpl == nil {
+				t.Fatal("expected template")
+			}
+		})
+	}
+}
+
+func TestSubjectTemplate_Render(t *testing.T) {
+	vars := SubjectVars{
+		Church:      "Springfield Church",
+		Date:        "12/28/2025",
+		Minister:    "Pr. Smith",
+		ServiceType: "Evening Service",
+		Label:       "Confirmation",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		vars     SubjectVars
+		want     string
+	}{
+		{
+			name:     "default matches original subject",
+			template: "",
+			vars:     vars,
+			want:     "Springfield Church: Recording of Service on 12/28/2025",
+		},
+		{
+			name:     "custom template",
+			template: "{church} {service_type} ({label}) - {minister}, {date}",
+			vars:     vars,
+			want:     "Springfield Church Evening Service (Confirmation) - Pr. Smith, 12/28/2025",
+		},
+		{
+			name:     "sermon title and scripture",
+			template: "{church}: {title} ({scripture}) on {date}",
+			vars:     SubjectVars{Church: "Springfield Church", Date: "12/28/2025", Title: "The Good Shepherd", Scripture: "John 10:11-16"},
+			want:     "Springfield Church: The Good Shepherd (John 10:11-16) on 12/28/2025",
+		},
+		{
+			name:     "date range of an overnight service",
+			template: "{church}: Watch Night Service, {date_range}",
+			vars:     SubjectVars{Church: "Springfield Church", Date: "12/31/2025", DateRange: "12/31/2025 to 01/01/2026"},
+			want:     "Springfield Church: Watch Night Service, 12/31/2025 to 01/01/2026",
+		},
+		{
+			name:     "empty variable collapses whitespace",
+			template: "{church}: {label} Recording on {date}",
+			vars:     SubjectVars{Church: "Springfield Church", Date: "12/28/2025"},
+			want:     "Springfield Church: Recording on 12/28/2025",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseSubjectTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseSubjectTemplate() error = %v", err)
+			}
+			if got := tmpl.Render(tt.vars); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
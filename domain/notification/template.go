@@ -9,14 +9,32 @@ import (
 
 // TemplateData contains all the fields available for email template rendering
 type TemplateData struct {
-	Greeting      string // Dynamic greeting based on recipient count
-	ChurchName    string
-	DateFormatted string // e.g., "12/28/2025"
-	ServiceRef    string // "today's", "yesterday's", or "Sunday's" based on when email is sent
-	MinisterName  string
-	AudioURL      string
-	VideoURL      string
-	SenderName    string
+	Greeting         string // Dynamic greeting based on recipient count
+	ChurchName       string
+	DateFormatted    string // e.g., "12/28/2025"
+	DateRange        string // DateFormatted, or "12/31/2025 to 01/01/2026" for a service that ran past midnight
+	EndDateFormatted string // e.g., "01/01/2026" when the service ran past midnight (optional)
+	ServiceRef       string // "today's", "yesterday's", or "Sunday's" based on when email is sent
+	MinisterName     string
+	Title            string // Sermon title (optional)
+	Scripture        string // Scripture reading, e.g. "John 10:11-16" (optional)
+	AudioURL         string
+	VideoURL         string
+	SenderName       string
+	Context          string // Extra paragraph for the recipient's group (optional)
+
+	AudioVersions []AudioVersion // Extra copies of the audio at other bitrates (optional)
+
+	// Mirror links on the alternate download server (optional)
+	MirrorAudioURL string
+	MirrorVideoURL string
+
+	FolderURL     string // Drive folder of previous services (optional)
+	LivestreamURL string // Livestream recording or channel (optional)
+
+	PreviousMinister string // Minister named in the email being corrected (optional)
+
+	AccessEnds string // e.g., "01/27/2026" when privately shared links stop working (optional)
 }
 
 // EmailTemplate contains the templates for rendering emails
@@ -26,44 +44,93 @@ type EmailTemplate struct {
 	HTML          string
 }
 
+// plainSermonLine names the sermon above the links in the plain text body
+const plainSermonLine = `{{if .Title}}Sermon: {{.Title}}{{if .Scripture}} ({{.Scripture}}){{end}}
+{{else if .Scripture}}Scripture: {{.Scripture}}
+{{end}}`
+
 // DefaultTemplate is the standard email template for service recordings
 var DefaultTemplate = EmailTemplate{
 	SubjectFormat: "{{.ChurchName}}: Recording of Service on {{.DateFormatted}}",
 	PlainText: `{{.Greeting}}
 
-{{if .VideoURL}}Here is the audio and video from {{.ServiceRef}} service{{if .MinisterName}} with {{.MinisterName}}{{end}}.
+{{if .VideoURL}}Here is the audio and video from {{.ServiceRef}} service{{if .EndDateFormatted}} ({{.DateRange}}){{end}}{{if .MinisterName}} with {{.MinisterName}}{{end}}.
+
+` + plainSermonLine + `Audio: {{.AudioURL}}
+Video: {{.VideoURL}}{{else}}Here is the audio from {{.ServiceRef}} service{{if .EndDateFormatted}} ({{.DateRange}}){{end}}{{if .MinisterName}} with {{.MinisterName}}{{end}}.
+
+` + plainSermonLine + `Audio: {{.AudioURL}}{{end}}
+{{if .AudioVersions}}
+Other audio versions:
+{{range .AudioVersions}}{{.Label}}: {{.URL}}
+{{end}}{{end}}{{if .Context}}
+{{.Context}}
+{{end}}{{if .MirrorAudioURL}}
+Can't open Google Drive? Download from our mirror instead:
+Audio: {{.MirrorAudioURL}}{{if .MirrorVideoURL}}
+Video: {{.MirrorVideoURL}}{{end}}
+Each file has a .sha256 checksum next to it for verification.
+{{end}}{{if .LivestreamURL}}
+Watch the livestream recording: {{.LivestreamURL}}
+{{end}}{{if .FolderURL}}
+Previous services: {{.FolderURL}}
+{{end}}
+Thanks!
+{{.SenderName}}`,
+	HTML: `<div dir="ltr">{{.Greeting}}<br><br>
+{{if .VideoURL}}Here is the <a href="{{.AudioURL}}">audio</a> and <a href="{{.VideoURL}}">video</a> from {{.ServiceRef}} service{{if .EndDateFormatted}} ({{.DateRange}}){{end}}{{if .MinisterName}} with {{.MinisterName}}{{end}}.{{else}}Here is the <a href="{{.AudioURL}}">audio</a> from {{.ServiceRef}} service{{if .EndDateFormatted}} ({{.DateRange}}){{end}}{{if .MinisterName}} with {{.MinisterName}}{{end}}.{{end}}<br><br>
+{{if .Title}}Sermon: <b>{{.Title}}</b>{{if .Scripture}} ({{.Scripture}}){{end}}<br><br>
+{{else if .Scripture}}Scripture: {{.Scripture}}<br><br>
+{{end}}{{if .AudioVersions}}Other audio versions: {{range $i, $v := .AudioVersions}}{{if $i}}, {{end}}<a href="{{$v.URL}}">{{$v.Label}}</a>{{end}}<br><br>
+{{end}}{{if .Context}}{{.Context}}<br><br>
+{{end}}{{if .MirrorAudioURL}}Can't open Google Drive? Download the <a href="{{.MirrorAudioURL}}">audio</a>{{if .MirrorVideoURL}} or <a href="{{.MirrorVideoURL}}">video</a>{{end}} from our mirror instead. Each file has a .sha256 checksum next to it for verification.<br><br>
+{{end}}{{if .LivestreamURL}}<a href="{{.LivestreamURL}}">Watch the livestream recording</a><br><br>
+{{end}}{{if .FolderURL}}Browse <a href="{{.FolderURL}}">previous services</a>.<br><br>
+{{end}}Thanks!<br>
+{{.SenderName}}</div>`,
+}
+
+// privateNotice asks recipients of a privately shared recording not to
+// forward it
+const privateNotice = `These recordings are shared privately with the people this email is addressed to. Please don't forward the links; no one else can open them.{{if .AccessEnds}} Access ends on {{.AccessEnds}}, so please download anything you want to keep.{{end}}`
 
-Audio: {{.AudioURL}}
-Video: {{.VideoURL}}{{else}}Here is the audio from {{.ServiceRef}} service{{if .MinisterName}} with {{.MinisterName}}{{end}}.
+// PrivateTemplate is the email for a privately shared service, such as a
+// funeral. It leaves out the mirror, livestream and previous services links.
+var PrivateTemplate = EmailTemplate{
+	SubjectFormat: "{{.ChurchName}}: Private Recording of Service on {{.DateFormatted}}",
+	PlainText: `{{.Greeting}}
+
+Here is the {{if .VideoURL}}audio and video{{else}}audio{{end}} from {{.ServiceRef}} service{{if .EndDateFormatted}} ({{.DateRange}}){{end}}{{if .MinisterName}} with {{.MinisterName}}{{end}}.
 
-Audio: {{.AudioURL}}{{end}}
+` + plainSermonLine + `Audio: {{.AudioURL}}{{if .VideoURL}}
+Video: {{.VideoURL}}{{end}}
+{{if .AudioVersions}}
+Other audio versions:
+{{range .AudioVersions}}{{.Label}}: {{.URL}}
+{{end}}{{end}}{{if .Context}}
+{{.Context}}
+{{end}}
+` + privateNotice + `
 
 Thanks!
 {{.SenderName}}`,
 	HTML: `<div dir="ltr">{{.Greeting}}<br><br>
-{{if .VideoURL}}Here is the <a href="{{.AudioURL}}">audio</a> and <a href="{{.VideoURL}}">video</a> from {{.ServiceRef}} service{{if .MinisterName}} with {{.MinisterName}}{{end}}.{{else}}Here is the <a href="{{.AudioURL}}">audio</a> from {{.ServiceRef}} service{{if .MinisterName}} with {{.MinisterName}}{{end}}.{{end}}<br><br>
+Here is the <a href="{{.AudioURL}}">audio</a>{{if .VideoURL}} and <a href="{{.VideoURL}}">video</a>{{end}} from {{.ServiceRef}} service{{if .EndDateFormatted}} ({{.DateRange}}){{end}}{{if .MinisterName}} with {{.MinisterName}}{{end}}.<br><br>
+{{if .Title}}Sermon: <b>{{.Title}}</b>{{if .Scripture}} ({{.Scripture}}){{end}}<br><br>
+{{else if .Scripture}}Scripture: {{.Scripture}}<br><br>
+{{end}}{{if .AudioVersions}}Other audio versions: {{range $i, $v := .AudioVersions}}{{if $i}}, {{end}}<a href="{{$v.URL}}">{{$v.Label}}</a>{{end}}<br><br>
+{{end}}{{if .Context}}{{.Context}}<br><br>
+{{end}}<i>` + privateNotice + `</i><br><br>
 Thanks!<br>
 {{.SenderName}}</div>`,
 }
 
-// FormatGreeting creates an appropriate greeting based on number of recipients
+// FormatGreeting creates the default greeting based on number of recipients
 // 1 recipient: "Dear John,"
 // 2 recipients: "Dear John & Jane,"
 // 3+ recipients: "Hey Everyone!"
 func FormatGreeting(recipients []Recipient) string {
-	switch len(recipients) {
-	case 0:
-		return "Hello,"
-	case 1:
-		name := getFirstName(recipients[0].Name)
-		return fmt.Sprintf("Dear %s,", name)
-	case 2:
-		name1 := getFirstName(recipients[0].Name)
-		name2 := getFirstName(recipients[1].Name)
-		return fmt.Sprintf("Dear %s & %s,", name1, name2)
-	default:
-		return "Hey Everyone!"
-	}
+	return GreetingRules{}.Format(recipients)
 }
 
 // getFirstName extracts the first name from a full name
@@ -86,10 +153,14 @@ func getFirstName(fullName string) string {
 // - Yesterday: "yesterday's"
 // - 2-6 days ago: "Sunday's" (assuming services are on Sunday)
 // - 7+ days ago: "the 12/28" (explicit date reference)
+//
+// "Today" is now's calendar day in now's location, so pass now in the service
+// timezone. Days are counted on the calendar, so a DST change in between
+// doesn't shift the result.
 func FormatServiceRef(serviceDate, now time.Time) string {
-	// Normalize to date only (ignore time component)
-	serviceDay := time.Date(serviceDate.Year(), serviceDate.Month(), serviceDate.Day(), 0, 0, 0, 0, time.Local)
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	// Normalize to date only (ignore time component); UTC days are all 24 hours
+	serviceDay := time.Date(serviceDate.Year(), serviceDate.Month(), serviceDate.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 
 	diff := today.Sub(serviceDay).Hours() / 24
 
@@ -106,6 +177,77 @@ func FormatServiceRef(serviceDate, now time.Time) string {
 	}
 }
 
+// FormatOvernightServiceRef refers to a service that ran past midnight into
+// endDate: "last night's" on endDate, otherwise as FormatServiceRef does
+// for serviceDate. Sent the morning after a New Year's Eve service, the email
+// says "last night's" rather than "yesterday's".
+func FormatOvernightServiceRef(serviceDate, endDate, now time.Time) string {
+	if now.Year() == endDate.Year() && now.YearDay() == endDate.YearDay() {
+		return "last night's"
+	}
+	return FormatServiceRef(serviceDate, now)
+}
+
+// FormatDateRange formats the service date, followed by the end date when
+// the service ran past midnight: "12/31/2025 to 01/01/2026"
+func FormatDateRange(serviceDate, endDate time.Time) string {
+	if !endDate.After(serviceDate) {
+		return serviceDate.Format("01/02/2006")
+	}
+	return serviceDate.Format("01/02/2006") + " to " + endDate.Format("01/02/2006")
+}
+
+// NewTemplateData builds the template fields for an email request sent at now
+func NewTemplateData(req *EmailRequest, now time.Time) TemplateData {
+	serviceRef, endDate := FormatServiceRef(req.ServiceDate, now), ""
+	if req.Overnight() {
+		serviceRef = FormatOvernightServiceRef(req.ServiceDate, req.ServiceEndDate, now)
+		endDate = req.ServiceEndDate.Format("01/02/2006")
+	}
+	data := TemplateData{
+		Greeting:         req.Greeting.Format(req.To),
+		ChurchName:       req.ChurchName,
+		DateFormatted:    req.ServiceDate.Format("01/02/2006"),
+		DateRange:        FormatDateRange(req.ServiceDate, req.ServiceEndDate),
+		EndDateFormatted: endDate,
+		ServiceRef:       serviceRef,
+		MinisterName:     req.MinisterName,
+		Title:            req.Title,
+		Scripture:        req.Scripture,
+		AudioURL:         req.AudioURL,
+		VideoURL:         req.VideoURL,
+		SenderName:       req.SenderName,
+		Context:          req.Context,
+		AudioVersions:    req.AudioVersions,
+
+		MirrorAudioURL: req.MirrorAudioURL,
+		MirrorVideoURL: req.MirrorVideoURL,
+		FolderURL:      req.FolderURL,
+		LivestreamURL:  req.LivestreamURL,
+
+		PreviousMinister: req.PreviousMinister,
+	}
+	if !req.AccessEnds.IsZero() {
+		data.AccessEnds = req.AccessEnds.Format("01/02/2006")
+	}
+	return data
+}
+
+// Validate checks that the subject and both bodies parse
+func (t *EmailTemplate) Validate() error {
+	parts := []struct{ name, text string }{
+		{"subject", t.SubjectFormat},
+		{"plain text", t.PlainText},
+		{"HTML", t.HTML},
+	}
+	for _, p := range parts {
+		if _, err := template.New(p.name).Parse(p.text); err != nil {
+			return fmt.Errorf("invalid %s template: %w", p.name, err)
+		}
+	}
+	return nil
+}
+
 // RenderSubject renders the email subject using the template
 func (t *EmailTemplate) RenderSubject(data TemplateData) (string, error) {
 	return renderTemplate("subject", t.SubjectFormat, data)
@@ -224,6 +224,58 @@ func TestFormatServiceRef(t *testing.T) {
 	}
 }
 
+func TestFormatServiceRef_Timezones(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone unavailable: %v", err)
+	}
+	saturday := time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		serviceDate time.Time
+		now         time.Time
+		want        string
+	}{
+		{
+			// Clocks spring forward on 2025-03-09, so that day is 23 hours long
+			name:        "yesterday across spring forward",
+			serviceDate: saturday,
+			now:         time.Date(2025, 3, 9, 22, 0, 0, 0, newYork),
+			want:        "yesterday's",
+		},
+		{
+			// Clocks fall back on 2025-11-02, so that day is 25 hours long
+			name:        "yesterday across fall back",
+			serviceDate: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+			now:         time.Date(2025, 11, 2, 23, 30, 0, 0, newYork),
+			want:        "yesterday's",
+		},
+		{
+			name:        "a week later across spring forward",
+			serviceDate: saturday,
+			now:         time.Date(2025, 3, 15, 1, 0, 0, 0, newYork),
+			want:        "the 3/8",
+		},
+		{
+			// 02:30 UTC Sunday is still Saturday evening in New York
+			name:        "saturday evening sent after midnight UTC",
+			serviceDate: saturday,
+			now:         time.Date(2025, 3, 9, 2, 30, 0, 0, time.UTC).In(newYork),
+			want:        "today's",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatServiceRef(tt.serviceDate, tt.now)
+			if got != tt.want {
+				t.Errorf("FormatServiceRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatGreeting(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -266,3 +318,328 @@ func TestFormatGreeting(t *testing.T) {
 		})
 	}
 }
+
+func TestEmailTemplate_MirrorLinks(t *testing.T) {
+	data := TemplateData{
+		Greeting:       "Dear John,",
+		AudioURL:       "https://drive.google.com/file/d/abc/view",
+		VideoURL:       "https://drive.google.com/file/d/xyz/view",
+		MirrorAudioURL: "https://files.example.org/2025-12-28.mp3",
+		MirrorVideoURL: "https://files.example.org/2025-12-28.mp4",
+		SenderName:     "Jonathan",
+	}
+
+	plain, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	for _, check := range []string{
+		"Audio: https://files.example.org/2025-12-28.mp3",
+		"Video: https://files.example.org/2025-12-28.mp4",
+		".sha256",
+		"Thanks!\nJonathan",
+	} {
+		if !strings.Contains(plain, check) {
+			t.Errorf("RenderPlainText() missing %q in:\n%s", check, plain)
+		}
+	}
+
+	html, err := DefaultTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if !strings.Contains(html, `<a href="https://files.example.org/2025-12-28.mp3">audio</a>`) {
+		t.Errorf("RenderHTML() missing mirror audio link in:\n%s", html)
+	}
+}
+
+func TestEmailTemplate_NoMirrorSection(t *testing.T) {
+	data := TemplateData{
+		Greeting:   "Dear John,",
+		AudioURL:   "https://drive.google.com/file/d/abc/view",
+		SenderName: "Jonathan",
+	}
+
+	plain, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	if strings.Contains(plain, "mirror") {
+		t.Errorf("RenderPlainText() should omit mirror section without mirror links:\n%s", plain)
+	}
+}
+
+func TestEmailTemplate_FolderLink(t *testing.T) {
+	data := TemplateData{
+		Greeting:   "Dear John,",
+		AudioURL:   "https://drive.google.com/file/d/abc/view",
+		SenderName: "Jonathan",
+		FolderURL:  "https://drive.google.com/drive/folders/folder123",
+	}
+
+	plain, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	if !strings.Contains(plain, "Previous services: https://drive.google.com/drive/folders/folder123\n\nThanks!") {
+		t.Errorf("RenderPlainText() missing folder footer in:\n%s", plain)
+	}
+
+	html, err := DefaultTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if !strings.Contains(html, `Browse <a href="https://drive.google.com/drive/folders/folder123">previous services</a>.`) {
+		t.Errorf("RenderHTML() missing folder footer in:\n%s", html)
+	}
+
+	data.FolderURL = ""
+	if plain, _ := DefaultTemplate.RenderPlainText(data); strings.Contains(plain, "Previous services") {
+		t.Errorf("RenderPlainText() should omit the folder footer without a folder URL:\n%s", plain)
+	}
+}
+
+func TestEmailTemplate_AudioVersions(t *testing.T) {
+	data := TemplateData{
+		Greeting:   "Dear John,",
+		AudioURL:   "https://drive.google.com/file/d/abc/view",
+		SenderName: "Jonathan",
+		AudioVersions: []AudioVersion{
+			{Label: "64k, 28.8 MB", URL: "https://drive.google.com/file/d/small/view"},
+			{Label: "320k, 144.0 MB", URL: "https://drive.google.com/file/d/large/view"},
+		},
+	}
+
+	plain, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	want := "Audio: https://drive.google.com/file/d/abc/view\n\nOther audio versions:\n" +
+		"64k, 28.8 MB: https://drive.google.com/file/d/small/view\n" +
+		"320k, 144.0 MB: https://drive.google.com/file/d/large/view\n\nThanks!"
+	if !strings.Contains(plain, want) {
+		t.Errorf("RenderPlainText() missing audio versions in:\n%s", plain)
+	}
+
+	html, err := DefaultTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if !strings.Contains(html, `Other audio versions: <a href="https://drive.google.com/file/d/small/view">64k, 28.8 MB</a>, <a href="https://drive.google.com/file/d/large/view">320k, 144.0 MB</a><br><br>`) {
+		t.Errorf("RenderHTML() missing audio versions in:\n%s", html)
+	}
+
+	data.AudioVersions = nil
+	if plain, _ := DefaultTemplate.RenderPlainText(data); strings.Contains(plain, "Other audio versions") {
+		t.Errorf("RenderPlainText() should omit audio versions when there are none:\n%s", plain)
+	}
+}
+
+func TestEmailTemplate_Livestream(t *testing.T) {
+	data := TemplateData{
+		Greeting:      "Dear John,",
+		AudioURL:      "https://drive.google.com/file/d/abc/view",
+		SenderName:    "Jonathan",
+		LivestreamURL: "https://www.youtube.com/@nacchurch/streams",
+	}
+
+	plain, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	if !strings.Contains(plain, "Watch the livestream recording: https://www.youtube.com/@nacchurch/streams\n\nThanks!") {
+		t.Errorf("RenderPlainText() missing livestream line in:\n%s", plain)
+	}
+
+	html, err := DefaultTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if !strings.Contains(html, `<a href="https://www.youtube.com/@nacchurch/streams">Watch the livestream recording</a>`) {
+		t.Errorf("RenderHTML() missing livestream link in:\n%s", html)
+	}
+}
+
+func TestEmailTemplate_NoLivestream(t *testing.T) {
+	data := TemplateData{
+		Greeting:   "Dear John,",
+		AudioURL:   "https://drive.google.com/file/d/abc/view",
+		SenderName: "Jonathan",
+	}
+
+	plain, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	html, err := DefaultTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	for name, body := range map[string]string{"plain text": plain, "HTML": html} {
+		if strings.Contains(body, "livestream") {
+			t.Errorf("%s body should omit the livestream line without a URL:\n%s", name, body)
+		}
+	}
+}
+
+func TestEmailTemplate_Sermon(t *testing.T) {
+	data := TemplateData{
+		Greeting:   "Dear John,",
+		Title:      "The Good Shepherd",
+		Scripture:  "John 10:11-16",
+		AudioURL:   "https://drive.google.com/file/d/abc/view",
+		VideoURL:   "https://drive.google.com/file/d/xyz/view",
+		SenderName: "Jonathan",
+	}
+
+	plain, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	if !strings.Contains(plain, "service.\n\nSermon: The Good Shepherd (John 10:11-16)\nAudio: https://") {
+		t.Errorf("RenderPlainText() missing the sermon line in:\n%s", plain)
+	}
+
+	html, err := DefaultTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if !strings.Contains(html, "Sermon: <b>The Good Shepherd</b> (John 10:11-16)<br><br>") {
+		t.Errorf("RenderHTML() missing the sermon line in:\n%s", html)
+	}
+
+	data.Title = ""
+	data.VideoURL = ""
+	if plain, _ := DefaultTemplate.RenderPlainText(data); !strings.Contains(plain, "\n\nScripture: John 10:11-16\nAudio: https://") {
+		t.Errorf("RenderPlainText() missing the scripture line in:\n%s", plain)
+	}
+
+	data.Scripture = ""
+	if plain, _ := DefaultTemplate.RenderPlainText(data); !strings.Contains(plain, "service.\n\nAudio: https://") {
+		t.Errorf("RenderPlainText() changed without a sermon:\n%s", plain)
+	}
+}
+
+func TestEmailTemplate_Context(t *testing.T) {
+	data := TemplateData{
+		Greeting:   "Dear Mary,",
+		AudioURL:   "https://drive.google.com/file/d/abc/view",
+		SenderName: "Jonathan",
+		Context:    "Choir rehearsal moves to Thursday.",
+	}
+
+	plain, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	if !strings.Contains(plain, "view\n\nChoir rehearsal moves to Thursday.\n\nThanks!") {
+		t.Errorf("RenderPlainText() should show the context between links and sign-off:\n%s", plain)
+	}
+
+	html, err := DefaultTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if !strings.Contains(html, "Choir rehearsal moves to Thursday.<br><br>") {
+		t.Errorf("RenderHTML() missing context in:\n%s", html)
+	}
+}
+
+func TestEmailTemplate_Validate(t *testing.T) {
+	if err := DefaultTemplate.Validate(); err != nil {
+		t.Errorf("DefaultTemplate.Validate() error = %v", err)
+	}
+	broken := EmailTemplate{PlainText: "{{.AudioURL", HTML: "ok"}
+	if err := broken.Validate(); err == nil {
+		t.Error("expected an error for an unclosed action")
+	}
+}
+
+func TestFormatOvernightServiceRef(t *testing.T) {
+	eve := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	newYear := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"the night it ended", time.Date(2026, 1, 1, 1, 30, 0, 0, time.Local), "last night's"},
+		{"later that day", time.Date(2026, 1, 1, 14, 0, 0, 0, time.Local), "last night's"},
+		{"a week later", time.Date(2026, 1, 8, 9, 0, 0, 0, time.Local), "the 12/31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatOvernightServiceRef(eve, newYear, tt.now); got != tt.want {
+				t.Errorf("FormatOvernightServiceRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailTemplate_OvernightService(t *testing.T) {
+	req := &EmailRequest{
+		To:             []Recipient{{Name: "Jane Doe", Address: "jane@example.com"}},
+		ServiceDate:    time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		ServiceEndDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		AudioURL:       "https://drive.google.com/file/d/abc/view",
+		SenderName:     "Jonathan",
+	}
+	data := NewTemplateData(req, time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local))
+	if data.DateRange != "12/31/2025 to 01/01/2026" || data.EndDateFormatted != "01/01/2026" {
+		t.Errorf("DateRange = %q, EndDateFormatted = %q", data.DateRange, data.EndDateFormatted)
+	}
+
+	body, err := DefaultTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	if want := "from last night's service (12/31/2025 to 01/01/2026)."; !strings.Contains(body, want) {
+		t.Errorf("RenderPlainText() missing %q in:\n%s", want, body)
+	}
+
+	req.ServiceEndDate = time.Time{}
+	data = NewTemplateData(req, time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local))
+	if data.DateRange != "12/31/2025" || data.EndDateFormatted != "" {
+		t.Errorf("same-day service: DateRange = %q, EndDateFormatted = %q", data.DateRange, data.EndDateFormatted)
+	}
+	body, _ = DefaultTemplate.RenderPlainText(data)
+	if strings.Contains(body, " to 01/01/2026") {
+		t.Errorf("same-day service should not show a date range:\n%s", body)
+	}
+}
+
+func TestPrivateTemplate(t *testing.T) {
+	if err := PrivateTemplate.Validate(); err != nil {
+		t.Fatalf("PrivateTemplate does not parse: %v", err)
+	}
+	req := &EmailRequest{
+		To:          []Recipient{{Name: "Mary Jones", Address: "mary@example.com"}},
+		ServiceDate: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC),
+		AudioURL:    "https://drive.google.com/file/d/abc/view",
+		SenderName:  "Jonathan",
+		FolderURL:   "https://drive.google.com/drive/folders/services",
+		AccessEnds:  time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC),
+	}
+	data := NewTemplateData(req, req.ServiceDate)
+
+	body, err := PrivateTemplate.RenderPlainText(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Here is the audio from today's service.", "Audio: https://drive.google.com/file/d/abc/view", "shared privately", "Access ends on 02/02/2026"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("plain text missing %q in:\n%s", want, body)
+		}
+	}
+	html, err := PrivateTemplate.RenderHTML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range []string{body, html} {
+		if strings.Contains(body, "Video") || strings.Contains(body, req.FolderURL) {
+			t.Errorf("private email links the video or other services:\n%s", body)
+		}
+	}
+}
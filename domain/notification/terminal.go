@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TerminalWidth is the line width terminal previews wrap at
+const TerminalWidth = 78
+
+var (
+	htmlWhitespace = regexp.MustCompile(`\s+`)
+	htmlAnchor     = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	htmlBold       = regexp.MustCompile(`(?i)</?(b|strong)>`)
+	htmlLineBreak  = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+	htmlTag        = regexp.MustCompile(`<[^>]*>`)
+	blankLines     = regexp.MustCompile(`\n{3,}`)
+)
+
+// RenderHTMLBody renders the HTML body the email is sent with at now
+func RenderHTMLBody(req *EmailRequest, now time.Time) (string, error) {
+	tmpl := DefaultTemplate
+	if req.Template != nil {
+		tmpl = *req.Template
+	}
+	return tmpl.RenderHTML(NewTemplateData(req, now))
+}
+
+// RenderTerminal turns an HTML email body into text for a terminal. Line
+// breaks are kept, links are shown inline as "text <url>", bold is marked
+// *like this* and other markup is dropped. Lines are wrapped at width without
+// splitting a word or link; a width of 0 leaves them whole.
+func RenderTerminal(body string, width int) string {
+	text := htmlWhitespace.ReplaceAllString(body, " ")
+	text = htmlAnchor.ReplaceAllStringFunc(text, func(a string) string {
+		m := htmlAnchor.FindStringSubmatch(a)
+		url := html.UnescapeString(m[1])
+		label := strings.TrimSpace(htmlTag.ReplaceAllString(m[2], ""))
+		// Marked with control characters so the tag stripper keeps them
+		link := "\x00" + url + "\x01"
+		if label == "" || html.UnescapeString(label) == url {
+			return link
+		}
+		return label + " " + link
+	})
+	text = htmlBold.ReplaceAllString(text, "*")
+	text = htmlLineBreak.ReplaceAllString(text, "\n")
+	text = htmlTag.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = strings.NewReplacer("\x00", "<", "\x01", ">").Replace(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = wrapLine(strings.TrimSpace(line), width)
+	}
+	text = blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text) + "\n"
+}
+
+// wrapLine breaks line at spaces so no part is longer than width, unless a
+// single word is
+func wrapLine(line string, width int) string {
+	if width <= 0 || len(line) <= width {
+		return line
+	}
+	var b strings.Builder
+	n := 0
+	for _, word := range strings.Fields(line) {
+		switch {
+		case n == 0:
+		case n+1+len(word) > width:
+			b.WriteByte('\n')
+			n = 0
+		default:
+			b.WriteByte(' ')
+			n++
+		}
+		b.WriteString(word)
+		n += len(word)
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderTerminal_DefaultTemplate(t *testing.T) {
+	req := &EmailRequest{
+		To:           []Recipient{{Name: "Jane Doe", Address: "jane@example.com"}},
+		ServiceDate:  time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		MinisterName: "Pr. Smith",
+		Title:        "Walking in Faith",
+		AudioURL:     "https://drive.google.com/file/d/audio/view?usp=sharing&x=1",
+		VideoURL:     "https://drive.google.com/file/d/video/view",
+		SenderName:   "Jonathan",
+	}
+	body, err := RenderHTMLBody(req, time.Date(2025, 12, 28, 13, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("RenderHTMLBody() error = %v", err)
+	}
+
+	got := RenderTerminal(body, 0)
+	want := "Dear Jane,\n\n" +
+		"Here is the audio <https://drive.google.com/file/d/audio/view?usp=sharing&x=1> and video <https://drive.google.com/file/d/video/view> from today's service with Pr. Smith.\n\n" +
+		"Sermon: *Walking in Faith*\n\n" +
+		"Thanks!\nJonathan\n"
+	if got != want {
+		t.Errorf("RenderTerminal() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTerminal_Entities(t *testing.T) {
+	got := RenderTerminal(`<p>Tom &amp; Jerry&#39;s <a href="https://x.org/?a=1&amp;b=2">https://x.org/?a=1&amp;b=2</a></p>`, 0)
+	if want := "Tom & Jerry's <https://x.org/?a=1&b=2>\n"; got != want {
+		t.Errorf("RenderTerminal() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderTerminal_WrapsWithoutSplittingLinks(t *testing.T) {
+	link := "https://drive.google.com/file/d/" + strings.Repeat("x", 40) + "/view"
+	got := RenderTerminal(`Here is the <a href="`+link+`">audio</a> from today's service.`, 30)
+	for _, line := range strings.Split(strings.TrimSpace(got), "\n") {
+		if len(line) > 30 && !strings.Contains(line, link) {
+			t.Errorf("line %q is longer than 30 characters", line)
+		}
+	}
+	if !strings.Contains(got, "<"+link+">") {
+		t.Errorf("expected the link to stay whole, got:\n%s", got)
+	}
+}
@@ -0,0 +1,117 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Service types with their own policy
+const (
+	TypeRegular = "regular"
+	TypeFuneral = "funeral"
+)
+
+// Funeral defaults: the family keeps access for a month and Drive keeps the
+// files for three
+const (
+	DefaultFuneralRevokeAfterDays = 30
+	DefaultFuneralRetentionDays   = 90
+)
+
+// ErrUnknownType is returned for a service type without a policy
+var ErrUnknownType = errors.New("unknown service type")
+
+// Policy is what a service type lets distribution and notification do with
+// its recordings
+type Policy struct {
+	Type string
+
+	// PublicSharing shares files with anyone who has the link; otherwise
+	// each file is shared only with the email's recipients
+	PublicSharing bool
+
+	// VideoOptional makes the run audio-only unless the video is asked for
+	VideoOptional bool
+
+	// CCRules lets email.cc_rules add CC recipients; otherwise only the
+	// CCs given for the run are copied
+	CCRules bool
+
+	// DefaultCC copies email.default_cc; otherwise, as with CCRules, only
+	// the CCs given for the run are copied and given access
+	DefaultCC bool
+
+	// Private labels the email as private and leaves out the links to
+	// other services
+	Private bool
+
+	// RevokeAfterDays ends the recipients' access this many days after the
+	// upload; zero keeps it
+	RevokeAfterDays int
+
+	// RetentionDays deletes the files from Drive this many days after the
+	// upload; zero keeps them until space is needed
+	RetentionDays int
+}
+
+// FuneralSettings are the configurable parts of the funeral policy
+type FuneralSettings struct {
+	RevokeAfterDays int
+	RetentionDays   int
+}
+
+// Regular returns the policy of an ordinary service: public links, the
+// video included and the default CCs and every CC rule applied
+func Regular() Policy {
+	return Policy{Type: TypeRegular, PublicSharing: true, CCRules: true, DefaultCC: true}
+}
+
+// Funeral returns the private policy of a funeral
+func Funeral(settings FuneralSettings) Policy {
+	return Policy{
+		Type:            TypeFuneral,
+		VideoOptional:   true,
+		Private:         true,
+		RevokeAfterDays: settings.RevokeAfterDays,
+		RetentionDays:   settings.RetentionDays,
+	}
+}
+
+// ForType returns the policy of a service type; an empty type is regular
+func ForType(name string, funeral FuneralSettings) (Policy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", TypeRegular:
+		return Regular(), nil
+	case TypeFuneral:
+		return Funeral(funeral), nil
+	}
+	return Policy{}, fmt.Errorf("%w %q (use %s or %s)", ErrUnknownType, name, TypeRegular, TypeFuneral)
+}
+
+// IsRegular reports whether the policy is the ordinary one
+func (p Policy) IsRegular() bool {
+	return p.Type == "" || p.Type == TypeRegular
+}
+
+// RevokeAt returns when access to files uploaded at uploaded ends, or the
+// zero time when it does not
+func (p Policy) RevokeAt(uploaded time.Time) time.Time {
+	return afterDays(uploaded, p.RevokeAfterDays)
+}
+
+// DeleteAt returns when files uploaded at uploaded are deleted, or the zero
+// time when they are kept
+func (p Policy) DeleteAt(uploaded time.Time) time.Time {
+	return afterDays(uploaded, p.RetentionDays)
+}
+
+// afterDays returns the date days after t, or the zero time for zero days
+func afterDays(t time.Time, days int) time.Time {
+	if days <= 0 {
+		return time.Time{}
+	}
+	y, m, d := t.Date()
+	return time.Date(y, m, d+days, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestForType(t *testing.T) {
+	settings := FuneralSettings{RevokeAfterDays: 14, RetentionDays: 60}
+
+	for _, name := range []string{"", "regular", "Regular"} {
+		p, err := ForType(name, settings)
+		if err != nil || !p.IsRegular() || !p.PublicSharing || !p.CCRules || !p.DefaultCC || p.Private {
+			t.Errorf("ForType(%q) = %+v, %v; want the regular policy", name, p, err)
+		}
+	}
+
+	p, err := ForType(" Funeral ", settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.IsRegular() || p.PublicSharing || p.CCRules || p.DefaultCC || !p.Private || !p.VideoOptional {
+		t.Errorf("funeral policy = %+v", p)
+	}
+	if p.RevokeAfterDays != 14 || p.RetentionDays != 60 {
+		t.Errorf("funeral days = %d, %d; want the settings", p.RevokeAfterDays, p.RetentionDays)
+	}
+
+	if _, err := ForType("wedding", settings); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("ForType(wedding) error = %v, want ErrUnknownType", err)
+	}
+}
+
+func TestPolicy_RevokeAndDeleteDates(t *testing.T) {
+	uploaded := time.Date(2026, 1, 30, 15, 4, 5, 0, time.UTC)
+	p := Funeral(FuneralSettings{RevokeAfterDays: 2, RetentionDays: 30})
+
+	if got := p.RevokeAt(uploaded); !got.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("RevokeAt() = %v, want the start of 2026-02-01", got)
+	}
+	if got := p.DeleteAt(uploaded); !got.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("DeleteAt() = %v, want the start of 2026-03-01", got)
+	}
+	if !Regular().RevokeAt(uploaded).IsZero() || !Regular().DeleteAt(uploaded).IsZero() {
+		t.Error("the regular policy should neither revoke nor delete")
+	}
+}
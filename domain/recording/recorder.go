@@ -0,0 +1,22 @@
+package recording
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotRecording is returned when no recording is in progress
+var ErrNotRecording = errors.New("no recording is in progress")
+
+// Recorder controls a live recording application such as OBS
+// This is a port that can be implemented by different infrastructure adapters
+type Recorder interface {
+	// IsRecording reports whether a recording is currently active
+	IsRecording(ctx context.Context) (bool, error)
+
+	// StopRecording stops the active recording and returns the saved file path
+	StopRecording(ctx context.Context) (string, error)
+
+	// WaitForStop blocks until the active recording stops and returns the saved file path
+	WaitForStop(ctx context.Context) (string, error)
+}
@@ -0,0 +1,157 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Release channels
+const (
+	ChannelStable = "stable" // Published, non-prerelease builds only
+	ChannelBeta   = "beta"   // Includes prereleases
+)
+
+// ChecksumsAsset is the name of the release asset listing SHA-256 checksums
+const ChecksumsAsset = "checksums.txt"
+
+// SignatureAsset is the name of the detached ed25519 signature over ChecksumsAsset
+const SignatureAsset = ChecksumsAsset + ".sig"
+
+// Release describes a published build of the tool
+type Release struct {
+	Version    string // Tag name, e.g. "v1.4.0"
+	Prerelease bool
+	Assets     []Asset
+}
+
+// Asset is a downloadable file attached to a release
+type Asset struct {
+	Name string
+	URL  string
+}
+
+// FindAsset returns the asset with the given name, or nil if not present
+func (r *Release) FindAsset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// Source lists releases and downloads their assets
+// This is a port that can be implemented by different infrastructure adapters
+type Source interface {
+	// Releases returns published releases, newest first
+	Releases(ctx context.Context) ([]Release, error)
+	// Download returns the contents of the asset at url
+	Download(ctx context.Context, url string) ([]byte, error)
+}
+
+// Installer replaces the running executable with a new binary
+type Installer interface {
+	// Install atomically swaps the file at execPath for binary
+	Install(binary []byte, execPath string) error
+}
+
+// ValidateChannel returns an error if channel is not a known release channel
+func ValidateChannel(channel string) error {
+	switch channel {
+	case ChannelStable, ChannelBeta:
+		return nil
+	default:
+		return fmt.Errorf("unknown release channel %q (use %q or %q)", channel, ChannelStable, ChannelBeta)
+	}
+}
+
+// BinaryAssetName returns the release asset name for a platform,
+// e.g. "nac-service-media_linux_amd64" or "nac-service-media_windows_amd64.exe"
+func BinaryAssetName(goos, goarch string) string {
+	name := fmt.Sprintf("nac-service-media_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// CompareVersions compares two "vMAJOR.MINOR.PATCH[-pre]" versions.
+// It returns -1 if a < b, 0 if equal, and 1 if a > b. A prerelease sorts
+// before the release it precedes. Unparseable versions return an error.
+func CompareVersions(a, b string) (int, error) {
+	pa, prea, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	pb, preb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range pa {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	switch {
+	case prea == preb:
+		return 0, nil
+	case prea == "":
+		return 1, nil
+	case preb == "":
+		return -1, nil
+	case prea < preb:
+		return -1, nil
+	default:
+		return 1, nil
+	}
+}
+
+// gitDescribeSuffix matches the end of what `git describe --dirty` adds after
+// a tag: commits since it and the commit hash ("-3-gabc1234"), or "-dirty"
+var gitDescribeSuffix = regexp.MustCompile(`(-\d+-g[0-9a-f]+|-dirty)$`)
+
+// IsDevBuild reports whether v was not built from a release tag: empty, "dev",
+// not vMAJOR.MINOR.PATCH (e.g. a bare commit hash), or a tag with a
+// git describe suffix such as "v1.2.0-3-gabc1234-dirty". Development builds
+// can't be compared with releases.
+func IsDevBuild(v string) bool {
+	if v == "" || v == "dev" {
+		return true
+	}
+	if _, _, err := parseVersion(v); err != nil {
+		return true
+	}
+	return gitDescribeSuffix.MatchString(v)
+}
+
+// parseVersion splits a version into numeric parts and a prerelease suffix
+func parseVersion(v string) ([3]int, string, error) {
+	var parts [3]int
+	s := strings.TrimPrefix(v, "v")
+
+	pre := ""
+	if idx := strings.Index(s, "-"); idx >= 0 {
+		s, pre = s[:idx], s[idx+1:]
+	}
+
+	fields := strings.Split(s, ".")
+	if len(fields) != 3 {
+		return parts, "", fmt.Errorf("invalid version %q: expected vMAJOR.MINOR.PATCH", v)
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, "", fmt.Errorf("invalid version %q: expected vMAJOR.MINOR.PATCH", v)
+		}
+		parts[i] = n
+	}
+	return parts, pre, nil
+}
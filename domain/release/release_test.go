@@ -0,0 +1,88 @@
+package release
+
+import (
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		want    int
+		wantErr bool
+	}{
+		{name: "equal", a: "v1.2.3", b: "v1.2.3", want: 0},
+		{name: "missing v prefix", a: "1.2.3", b: "v1.2.3", want: 0},
+		{name: "older patch", a: "v1.2.3", b: "v1.2.4", want: -1},
+		{name: "newer minor", a: "v1.10.0", b: "v1.9.9", want: 1},
+		{name: "older major", a: "v1.9.9", b: "v2.0.0", want: -1},
+		{name: "prerelease before release", a: "v2.0.0-beta.1", b: "v2.0.0", want: -1},
+		{name: "release after prerelease", a: "v2.0.0", b: "v2.0.0-rc.1", want: 1},
+		{name: "prereleases ordered", a: "v2.0.0-beta.1", b: "v2.0.0-beta.2", want: -1},
+		{name: "dev build", a: "dev", b: "v1.0.0", wantErr: true},
+		{name: "too few parts", a: "v1.2", b: "v1.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareVersions(tt.a, tt.b)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("CompareVersions(%q, %q) expected error", tt.a, tt.b)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompareVersions(%q, %q) unexpected error: %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDevBuild(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "v1.2.0", want: false},
+		{version: "1.2.0", want: false},
+		{version: "v2.0.0-rc.1", want: false},
+		{version: "", want: true},
+		{version: "dev", want: true},
+		{version: "abc1234", want: true},
+		{version: "abc1234-dirty", want: true},
+		{version: "v1.2.0-dirty", want: true},
+		{version: "v1.2.0-3-gabc1234", want: true},
+		{version: "v1.2.0-3-gabc1234-dirty", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := IsDevBuild(tt.version); got != tt.want {
+			t.Errorf("IsDevBuild(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestBinaryAssetName(t *testing.T) {
+	if got := BinaryAssetName("linux", "amd64"); got != "nac-service-media_linux_amd64" {
+		t.Errorf("unexpected linux asset name: %s", got)
+	}
+	if got := BinaryAssetName("windows", "amd64"); got != "nac-service-media_windows_amd64.exe" {
+		t.Errorf("unexpected windows asset name: %s", got)
+	}
+}
+
+func TestValidateChannel(t *testing.T) {
+	for _, ch := range []string{ChannelStable, ChannelBeta} {
+		if err := ValidateChannel(ch); err != nil {
+			t.Errorf("ValidateChannel(%q) unexpected error: %v", ch, err)
+		}
+	}
+	if err := ValidateChannel("nightly"); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
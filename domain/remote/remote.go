@@ -0,0 +1,176 @@
+// Package remote describes runs started through the remote control API, such
+// as a companion app on the sound desk kicking off processing
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"nac-service-media/domain/history"
+)
+
+// ErrRunInProgress is returned when a run is started while another is going
+var ErrRunInProgress = errors.New("another run is in progress")
+
+// ErrRunNotFound is returned for an unknown run ID
+var ErrRunNotFound = errors.New("run not found")
+
+// ErrNothingToResend is returned when history has no sent service for a date
+var ErrNothingToResend = errors.New("no processed service to resend")
+
+// RunState is where a run is in its life
+type RunState string
+
+const (
+	RunRunning   RunState = "running"
+	RunSucceeded RunState = "succeeded"
+	RunFailed    RunState = "failed"
+)
+
+// Kinds of run
+const (
+	KindProcess = "process"
+	KindResend  = "resend"
+)
+
+// Run is one command started through the API
+type Run struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Args       []string   `json:"args"`
+	State      RunState   `json:"state"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Output     string     `json:"output,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// ProcessRequest asks for a non-interactive process run. Empty fields fall
+// back to the same defaults as the command line.
+type ProcessRequest struct {
+	Input       string   `json:"input,omitempty"` // Source video; the newest recording when empty
+	Start       string   `json:"start,omitempty"` // HH:MM:SS; detected when empty
+	End         string   `json:"end,omitempty"`
+	Date        string   `json:"date,omitempty"` // YYYY-MM-DD when the file name has none
+	Minister    string   `json:"minister,omitempty"`
+	Recipients  []string `json:"recipients,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Scripture   string   `json:"scripture,omitempty"`
+	SkipVideo   bool     `json:"skip_video,omitempty"`
+	Sandbox     bool     `json:"sandbox,omitempty"`
+	AllowDelete bool     `json:"allow_delete,omitempty"`
+}
+
+// Args returns the process command line for the request
+func (r ProcessRequest) Args() []string {
+	args := []string{"process", "--non-interactive"}
+	args = appendFlag(args, "--input", r.Input)
+	args = appendFlag(args, "--start", r.Start)
+	args = appendFlag(args, "--end", r.End)
+	args = appendFlag(args, "--date", r.Date)
+	args = appendFlag(args, "--minister", r.Minister)
+	for _, to := range r.Recipients {
+		args = appendFlag(args, "--recipient", to)
+	}
+	args = appendFlag(args, "--title", r.Title)
+	args = appendFlag(args, "--scripture", r.Scripture)
+	if r.SkipVideo {
+		args = append(args, "--skip-video")
+	}
+	if r.Sandbox {
+		args = append(args, "--sandbox")
+	}
+	if r.AllowDelete {
+		args = append(args, "--allow-delete")
+	}
+	return args
+}
+
+// Validate checks the request names recipients and a well-formed date
+func (r ProcessRequest) Validate() error {
+	if len(r.Recipients) == 0 {
+		return fmt.Errorf("recipients are required")
+	}
+	if r.Date != "" {
+		if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+			return fmt.Errorf("invalid date %q (use YYYY-MM-DD)", r.Date)
+		}
+	}
+	return nil
+}
+
+// ResendRequest asks to email a processed service again
+type ResendRequest struct {
+	Date       string   `json:"date"` // Service date, YYYY-MM-DD
+	Recipients []string `json:"recipients"`
+	Sandbox    bool     `json:"sandbox,omitempty"`
+}
+
+// Validate checks the request names a date and who to send to
+func (r ResendRequest) Validate() error {
+	if _, err := time.Parse("2006-01-02", r.Date); err != nil {
+		return fmt.Errorf("invalid date %q (use YYYY-MM-DD)", r.Date)
+	}
+	if len(r.Recipients) == 0 {
+		return fmt.Errorf("recipients are required")
+	}
+	return nil
+}
+
+// Args returns the send-email command line that resends entry
+func (r ResendRequest) Args(entry history.Entry) []string {
+	args := []string{"send-email", "--date=" + r.Date, "--minister=" + entry.Minister}
+	for _, to := range r.Recipients {
+		args = appendFlag(args, "--to", to)
+	}
+	args = appendFlag(args, "--audio-url", entry.AudioURL)
+	args = appendFlag(args, "--video-url", entry.VideoURL)
+	args = appendFlag(args, "--title", entry.Title)
+	args = appendFlag(args, "--scripture", entry.Scripture)
+	if r.Sandbox {
+		args = append(args, "--sandbox")
+	}
+	return args
+}
+
+// LatestSent returns the most recent successful run for a service date that
+// has links to send
+func LatestSent(entries []history.Entry, date string) (history.Entry, error) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.ServiceDate.Format("2006-01-02") != date || e.Outcome != history.OutcomeSuccess {
+			continue
+		}
+		if e.AudioURL != "" || e.VideoURL != "" {
+			return e, nil
+		}
+	}
+	return history.Entry{}, fmt.Errorf("%w for %s", ErrNothingToResend, date)
+}
+
+// CommandRunner runs one of this tool's own commands
+// This is a port that can be implemented by different infrastructure adapters
+type CommandRunner interface {
+	Run(ctx context.Context, args []string, output io.Writer) error
+}
+
+// Controller is what the remote control API drives
+type Controller interface {
+	StartProcess(req ProcessRequest) (Run, error)
+	Resend(req ResendRequest) (Run, error)
+	Run(id string) (Run, error)
+	Runs() []Run
+	History(filter history.Filter) ([]history.Entry, error)
+}
+
+// appendFlag adds --flag=value when value is set; the joined form keeps a
+// value starting with "-" from being read as a flag
+func appendFlag(args []string, flag, value string) []string {
+	if value == "" {
+		return args
+	}
+	return append(args, flag+"="+value)
+}
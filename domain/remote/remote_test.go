@@ -0,0 +1,92 @@
+package remote
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/history"
+)
+
+func TestProcessRequest_Args(t *testing.T) {
+	req := ProcessRequest{
+		Start:      "00:05:30",
+		End:        "01:45:00",
+		Minister:   "smith",
+		Recipients: []string{"jane", "-john"},
+		Title:      "The Good Shepherd",
+		SkipVideo:  true,
+	}
+	want := []string{
+		"process", "--non-interactive",
+		"--start=00:05:30", "--end=01:45:00", "--minister=smith",
+		"--recipient=jane", "--recipient=-john",
+		"--title=The Good Shepherd", "--skip-video",
+	}
+	if got := req.Args(); !slices.Equal(got, want) {
+		t.Errorf("Args() = %q, want %q", got, want)
+	}
+}
+
+func TestProcessRequest_Validate(t *testing.T) {
+	if err := (ProcessRequest{Recipients: []string{"jane"}, Date: "2025-12-28"}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (ProcessRequest{}).Validate(); err == nil {
+		t.Error("expected an error without recipients")
+	}
+	if err := (ProcessRequest{Recipients: []string{"jane"}, Date: "12/28/2025"}).Validate(); err == nil {
+		t.Error("expected an error for a bad date")
+	}
+}
+
+func TestResendRequest_Args(t *testing.T) {
+	entry := history.Entry{
+		Minister: "Pr. Smith",
+		AudioURL: "https://drive.google.com/file/d/a/view",
+		Title:    "Grace",
+	}
+	req := ResendRequest{Date: "2025-12-28", Recipients: []string{"jane"}, Sandbox: true}
+	want := []string{
+		"send-email", "--date=2025-12-28", "--minister=Pr. Smith", "--to=jane",
+		"--audio-url=https://drive.google.com/file/d/a/view", "--title=Grace", "--sandbox",
+	}
+	if got := req.Args(entry); !slices.Equal(got, want) {
+		t.Errorf("Args() = %q, want %q", got, want)
+	}
+}
+
+func TestResendRequest_Validate(t *testing.T) {
+	if err := (ResendRequest{Date: "2025-12-28", Recipients: []string{"jane"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (ResendRequest{Recipients: []string{"jane"}}).Validate(); err == nil {
+		t.Error("expected an error without a date")
+	}
+	if err := (ResendRequest{Date: "2025-12-28"}).Validate(); err == nil {
+		t.Error("expected an error without recipients")
+	}
+}
+
+func TestLatestSent(t *testing.T) {
+	day := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	entries := []history.Entry{
+		{ServiceDate: day, Outcome: history.OutcomeSuccess, AudioURL: "first"},
+		{ServiceDate: day, Outcome: history.OutcomeSuccess, AudioURL: "second"},
+		{ServiceDate: day, Outcome: "failed", AudioURL: "failed"},
+		{ServiceDate: day.AddDate(0, 0, 7), Outcome: history.OutcomeSuccess, AudioURL: "next week"},
+	}
+
+	got, err := LatestSent(entries, "2025-12-28")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.AudioURL != "second" {
+		t.Errorf("LatestSent() = %q, want the latest successful run", got.AudioURL)
+	}
+
+	if _, err := LatestSent(entries, "2025-12-21"); !errors.Is(err, ErrNothingToResend) {
+		t.Errorf("expected ErrNothingToResend, got %v", err)
+	}
+}
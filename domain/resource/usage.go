@@ -0,0 +1,141 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/distribution"
+)
+
+// DefaultSampleInterval is how often usage is sampled while a step runs
+const DefaultSampleInterval = time.Second
+
+// Process is one reading of a process's cumulative counters
+type Process struct {
+	PID        int
+	CPU        time.Duration // User plus system time so far
+	Memory     int64         // Resident bytes
+	ReadBytes  int64         // Read from storage so far
+	WriteBytes int64         // Written to storage so far
+}
+
+// Snapshot is every process of the run, read at one moment
+type Snapshot struct {
+	At        time.Time
+	Processes []Process
+}
+
+// Memory returns the resident bytes of every process together
+func (s Snapshot) Memory() int64 {
+	var total int64
+	for _, p := range s.Processes {
+		total += p.Memory
+	}
+	return total
+}
+
+// since returns the CPU time and IO spent since prev. Processes that started
+// in between count from zero; counters that went backwards (a reused PID)
+// count as nothing.
+func (s Snapshot) since(prev Snapshot) (cpu time.Duration, read, write int64) {
+	before := make(map[int]Process, len(prev.Processes))
+	for _, p := range prev.Processes {
+		before[p.PID] = p
+	}
+	for _, p := range s.Processes {
+		b := before[p.PID]
+		cpu += max(p.CPU-b.CPU, 0)
+		read += max(p.ReadBytes-b.ReadBytes, 0)
+		write += max(p.WriteBytes-b.WriteBytes, 0)
+	}
+	return cpu, read, write
+}
+
+// Sampler reads the counters of this program and the processes it started,
+// such as ffmpeg.
+// This is a port that can be implemented by different infrastructure adapters.
+type Sampler interface {
+	Sample() (Snapshot, error)
+}
+
+// Usage is how hard a step worked the machine. CPU is in percent of one
+// core, so a step using four cores fully shows 400%.
+type Usage struct {
+	CPUPeak      float64 `json:"cpu_peak"`
+	CPUAverage   float64 `json:"cpu_average"`
+	MemoryPeak   int64   `json:"memory_peak"`   // Resident bytes
+	ReadPeak     float64 `json:"read_peak"`     // Bytes per second from storage
+	ReadAverage  float64 `json:"read_average"`  // Bytes per second from storage
+	WritePeak    float64 `json:"write_peak"`    // Bytes per second to storage
+	WriteAverage float64 `json:"write_average"` // Bytes per second to storage
+}
+
+// IsZero reports whether nothing was measured
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
+
+// String formats the usage for output, e.g. "CPU 350% peak, 210% average;
+// memory 412.0 MB peak; disk read 80.0 MB/s peak, 45.2 MB/s average; disk
+// write 12.0 MB/s peak, 6.1 MB/s average"
+func (u Usage) String() string {
+	return strings.Join([]string{
+		fmt.Sprintf("CPU %.0f%% peak, %.0f%% average", u.CPUPeak, u.CPUAverage),
+		"memory " + distribution.FormatSize(u.MemoryPeak) + " peak",
+		fmt.Sprintf("disk read %s peak, %s average", rate(u.ReadPeak), rate(u.ReadAverage)),
+		fmt.Sprintf("disk write %s peak, %s average", rate(u.WritePeak), rate(u.WriteAverage)),
+	}, "; ")
+}
+
+func rate(bytesPerSecond float64) string {
+	return distribution.FormatSize(int64(bytesPerSecond)) + "/s"
+}
+
+// StepUsage is the usage of one named workflow step
+type StepUsage struct {
+	Step string `json:"step"`
+	Usage
+}
+
+// Meter turns snapshots taken while a step runs into its Usage. Peaks are
+// over each interval between snapshots; averages over the whole step.
+type Meter struct {
+	prev     Snapshot
+	havePrev bool
+	elapsed  time.Duration
+	cpu      time.Duration
+	read     int64
+	write    int64
+	usage    Usage
+}
+
+// Add records a snapshot
+func (m *Meter) Add(s Snapshot) {
+	m.usage.MemoryPeak = max(m.usage.MemoryPeak, s.Memory())
+	if m.havePrev {
+		if interval := s.At.Sub(m.prev.At); interval > 0 {
+			cpu, read, write := s.since(m.prev)
+			seconds := interval.Seconds()
+			m.usage.CPUPeak = max(m.usage.CPUPeak, cpu.Seconds()/seconds*100)
+			m.usage.ReadPeak = max(m.usage.ReadPeak, float64(read)/seconds)
+			m.usage.WritePeak = max(m.usage.WritePeak, float64(write)/seconds)
+			m.elapsed += interval
+			m.cpu += cpu
+			m.read += read
+			m.write += write
+		}
+	}
+	m.prev, m.havePrev = s, true
+}
+
+// Usage returns the peaks and averages so far
+func (m *Meter) Usage() Usage {
+	u := m.usage
+	if seconds := m.elapsed.Seconds(); seconds > 0 {
+		u.CPUAverage = m.cpu.Seconds() / seconds * 100
+		u.ReadAverage = float64(m.read) / seconds
+		u.WriteAverage = float64(m.write) / seconds
+	}
+	return u
+}
@@ -0,0 +1,64 @@
+package resource
+
+import (
+	"testing"
+	"time"
+)
+
+const mb = 1024 * 1024
+
+func TestMeter_PeaksAndAverages(t *testing.T) {
+	start := time.Date(2025, 12, 28, 11, 0, 0, 0, time.UTC)
+	self := func(cpu time.Duration) Process {
+		return Process{PID: 100, CPU: cpu, Memory: 40 * mb}
+	}
+
+	var m Meter
+	m.Add(Snapshot{At: start, Processes: []Process{self(5 * time.Second)}})
+	// ffmpeg starts and works three cores for a second, reading 100 MB
+	m.Add(Snapshot{At: start.Add(time.Second), Processes: []Process{
+		self(5 * time.Second),
+		{PID: 200, CPU: 3 * time.Second, Memory: 300 * mb, ReadBytes: 100 * mb, WriteBytes: 20 * mb},
+	}})
+	// ...then one core for a second, reading nothing more
+	m.Add(Snapshot{At: start.Add(2 * time.Second), Processes: []Process{
+		self(5 * time.Second),
+		{PID: 200, CPU: 4 * time.Second, Memory: 200 * mb, ReadBytes: 100 * mb, WriteBytes: 40 * mb},
+	}})
+
+	u := m.Usage()
+	if u.CPUPeak != 300 || u.CPUAverage != 200 {
+		t.Errorf("CPU = %.0f%% peak, %.0f%% average; want 300%%, 200%%", u.CPUPeak, u.CPUAverage)
+	}
+	if u.MemoryPeak != 340*mb {
+		t.Errorf("memory peak = %d, want 340 MB", u.MemoryPeak)
+	}
+	if u.ReadPeak != 100*mb || u.ReadAverage != 50*mb || u.WritePeak != 20*mb || u.WriteAverage != 20*mb {
+		t.Errorf("disk = %+v", u)
+	}
+	want := "CPU 300% peak, 200% average; memory 340.0 MB peak; disk read 100.0 MB/s peak, 50.0 MB/s average; disk write 20.0 MB/s peak, 20.0 MB/s average"
+	if u.String() != want {
+		t.Errorf("String() = %q, want %q", u.String(), want)
+	}
+}
+
+func TestMeter_ReusedPIDCountsNothing(t *testing.T) {
+	start := time.Date(2025, 12, 28, 11, 0, 0, 0, time.UTC)
+	var m Meter
+	m.Add(Snapshot{At: start, Processes: []Process{{PID: 200, CPU: 90 * time.Second, ReadBytes: 5 * mb}}})
+	m.Add(Snapshot{At: start.Add(time.Second), Processes: []Process{{PID: 200, CPU: time.Second / 2}}})
+	if u := m.Usage(); u.CPUPeak != 0 || u.ReadPeak != 0 {
+		t.Errorf("usage = %+v, want nothing counted for counters that went backwards", u)
+	}
+}
+
+func TestMeter_SingleSnapshot(t *testing.T) {
+	var m Meter
+	if !m.Usage().IsZero() {
+		t.Error("a meter with no snapshots should measure nothing")
+	}
+	m.Add(Snapshot{At: time.Now(), Processes: []Process{{PID: 1, Memory: mb}}})
+	if u := m.Usage(); u.MemoryPeak != mb || u.CPUAverage != 0 {
+		t.Errorf("usage = %+v, want only the memory", u)
+	}
+}
@@ -0,0 +1,187 @@
+package runstate
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/video"
+)
+
+// ErrNoState is returned when no unfinished run has been saved
+var ErrNoState = errors.New("no unfinished run saved")
+
+// Params are the options a run was started with, so a resumed run repeats
+// them exactly. Timestamps are the resolved ones, so detection is not rerun.
+type Params struct {
+	InputPath     string   `json:"input_path"`
+	StartTime     string   `json:"start_time"`
+	EndTime       string   `json:"end_time"`
+	DateOverride  string   `json:"date,omitempty"`
+	MinisterKey   string   `json:"minister,omitempty"`
+	RecipientKeys []string `json:"recipients,omitempty"`
+	ExcludeKeys   []string `json:"exclude,omitempty"`
+	CCKeys        []string `json:"cc,omitempty"`
+	SenderKey     string   `json:"sender,omitempty"`
+	ServiceType   string   `json:"service_type,omitempty"`
+	Type          string   `json:"type,omitempty"` // Service type whose policy applies
+	Label         string   `json:"label,omitempty"`
+	Title         string   `json:"title,omitempty"`
+	Scripture     string   `json:"scripture,omitempty"`
+	Notes         []string `json:"notes,omitempty"`
+	SkipVideo     bool     `json:"skip_video,omitempty"`
+	WithVideo     bool     `json:"with_video,omitempty"` // Video asked for when the type makes it optional
+	AudioTrack    int      `json:"audio_track,omitempty"`
+	Sandbox       bool     `json:"sandbox,omitempty"`
+	Target        string   `json:"target,omitempty"`
+
+	Chapters []video.Chapter `json:"chapters,omitempty"`
+}
+
+// Upload is a file the run has uploaded
+type Upload struct {
+	FileID         string `json:"file_id"`
+	FileName       string `json:"file_name"`
+	URL            string `json:"url"`
+	Size           int64  `json:"size,omitempty"`
+	MD5Checksum    string `json:"md5,omitempty"`
+	SharingPending bool   `json:"sharing_pending,omitempty"`
+}
+
+// NewUpload records an upload result
+func NewUpload(r *distribution.UploadResult) *Upload {
+	if r == nil {
+		return nil
+	}
+	return &Upload{
+		FileID:         r.FileID,
+		FileName:       r.FileName,
+		URL:            r.ShareableURL,
+		Size:           r.Size,
+		MD5Checksum:    r.MD5Checksum,
+		SharingPending: r.SharingPending,
+	}
+}
+
+// Result returns the upload as the result the workflow carries on with
+func (u *Upload) Result() *distribution.UploadResult {
+	if u == nil {
+		return nil
+	}
+	return &distribution.UploadResult{
+		FileID:         u.FileID,
+		FileName:       u.FileName,
+		ShareableURL:   u.URL,
+		Size:           u.Size,
+		MD5Checksum:    u.MD5Checksum,
+		SharingPending: u.SharingPending,
+	}
+}
+
+// Hosted is a video the run put on a video host such as YouTube
+type Hosted struct {
+	Host    string `json:"host"`
+	ID      string `json:"id"`
+	URL     string `json:"url"`
+	Privacy string `json:"privacy,omitempty"`
+}
+
+// NewHosted records a hosted video
+func NewHosted(v *distribution.HostedVideo) *Hosted {
+	if v == nil {
+		return nil
+	}
+	return &Hosted{Host: v.Host, ID: v.ID, URL: v.URL, Privacy: string(v.Privacy)}
+}
+
+// Result returns the hosted video the workflow carries on with
+func (h *Hosted) Result() *distribution.HostedVideo {
+	if h == nil {
+		return nil
+	}
+	return &distribution.HostedVideo{Host: h.Host, ID: h.ID, URL: h.URL, Privacy: distribution.Privacy(h.Privacy)}
+}
+
+// Variant is an extra MP3 at another bitrate
+type Variant struct {
+	Bitrate string `json:"bitrate"`
+	Path    string `json:"path"`
+}
+
+// AudioVersion is an uploaded extra MP3 as linked in the email
+type AudioVersion struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// State is the progress of a process run, saved after each completed step
+// so a failed run can resume where it stopped
+type State struct {
+	ServiceDate time.Time `json:"service_date"`
+	Params      Params    `json:"params"`
+	LastStep    string    `json:"last_step,omitempty"` // Name of the last completed step
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	TrimmedPath    string    `json:"trimmed_path,omitempty"`
+	AudioPath      string    `json:"audio_path,omitempty"`
+	Variants       []Variant `json:"variants,omitempty"`
+	StorageChecked bool      `json:"storage_checked,omitempty"`
+	Video          *Upload   `json:"video,omitempty"`
+	Hosted         *Hosted   `json:"hosted,omitempty"` // The video on a video host
+	Audio          *Upload   `json:"audio,omitempty"`
+
+	AudioVersions []AudioVersion `json:"audio_versions,omitempty"`
+}
+
+// New starts the state of a run
+func New(serviceDate time.Time, params Params) *State {
+	return &State{ServiceDate: serviceDate, Params: params}
+}
+
+// Complete marks a step as the last one done
+func (s *State) Complete(step string, at time.Time) {
+	s.LastStep = step
+	s.UpdatedAt = at
+}
+
+// Summary describes what the run had finished, e.g. "video trimmed, audio
+// extracted, video uploaded"
+func (s *State) Summary() string {
+	var done []string
+	if s.TrimmedPath != "" {
+		done = append(done, "video trimmed")
+	}
+	if s.AudioPath != "" {
+		done = append(done, "audio extracted")
+	}
+	if s.Video != nil {
+		done = append(done, "video uploaded")
+	}
+	if s.Hosted != nil {
+		done = append(done, "video on "+s.Hosted.Host)
+	}
+	if s.Audio != nil {
+		done = append(done, "audio uploaded")
+	}
+	if len(done) == 0 {
+		return "nothing finished yet"
+	}
+	return strings.Join(done, ", ")
+}
+
+// Store keeps the state of unfinished runs, one per service date
+// This is a port that can be implemented by different infrastructure adapters
+type Store interface {
+	// Load returns the saved run for the service date, or ErrNoState
+	Load(serviceDate time.Time) (*State, error)
+
+	// Latest returns the most recently updated saved run, or ErrNoState
+	Latest() (*State, error)
+
+	// Save replaces the saved run for the state's service date
+	Save(s *State) error
+
+	// Clear removes the saved run for the service date, once it finished
+	Clear(serviceDate time.Time) error
+}
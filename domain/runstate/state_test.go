@@ -0,0 +1,45 @@
+package runstate
+
+import (
+	"testing"
+	"time"
+
+	"nac-service-media/domain/distribution"
+)
+
+func TestUpload_RoundTrip(t *testing.T) {
+	r := &distribution.UploadResult{FileID: "abc", FileName: "2025-12-28.mp4", ShareableURL: "https://drive.google.com/file/d/abc/view", Size: 42, SharingPending: true}
+	if got := NewUpload(r).Result(); *got != *r {
+		t.Errorf("round trip = %+v, want %+v", got, r)
+	}
+	if NewUpload(nil) != nil || (*Upload)(nil).Result() != nil {
+		t.Error("a missing upload should stay missing")
+	}
+}
+
+func TestHosted_RoundTrip(t *testing.T) {
+	v := &distribution.HostedVideo{Host: "YouTube", ID: "abc", URL: "https://www.youtube.com/watch?v=abc", Privacy: distribution.PrivacyUnlisted}
+	if got := NewHosted(v).Result(); *got != *v {
+		t.Errorf("round trip = %+v, want %+v", got, v)
+	}
+	if NewHosted(nil) != nil || (*Hosted)(nil).Result() != nil {
+		t.Error("a missing video should stay missing")
+	}
+}
+
+func TestState_Summary(t *testing.T) {
+	s := New(time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC), Params{InputPath: "service.mp4"})
+	if got := s.Summary(); got != "nothing finished yet" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	s.TrimmedPath, s.AudioPath = "trimmed.mp4", "audio.mp3"
+	s.Video = &Upload{FileID: "abc"}
+	s.Complete("Upload video", time.Now())
+	if got := s.Summary(); got != "video trimmed, audio extracted, video uploaded" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if s.LastStep != "Upload video" || s.UpdatedAt.IsZero() {
+		t.Errorf("Complete() left %+v", s)
+	}
+}
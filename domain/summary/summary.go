@@ -0,0 +1,332 @@
+package summary
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/resource"
+)
+
+// Archive formats
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// ParseFormats validates a comma-separated list of archive formats. An empty
+// list means both Markdown and HTML.
+func ParseFormats(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return []string{FormatMarkdown, FormatHTML}, nil
+	}
+	var formats []string
+	for _, f := range strings.Split(s, ",") {
+		switch f = strings.ToLower(strings.TrimSpace(f)); f {
+		case FormatMarkdown, "md":
+			formats = append(formats, FormatMarkdown)
+		case FormatHTML:
+			formats = append(formats, FormatHTML)
+		default:
+			return nil, fmt.Errorf("unknown summary format %q (must be markdown or html)", f)
+		}
+	}
+	return formats, nil
+}
+
+// RunSummary is the record of one completed process run, for archiving
+type RunSummary struct {
+	Church      string
+	ServiceDate time.Time
+	ProcessedAt time.Time
+	Minister    string
+	SourceFile  string
+	StartTime   string // HH:MM:SS in the source
+	EndTime     string
+	AudioOnly   bool
+
+	Steps []Step
+	Total time.Duration
+
+	Files   []File
+	Links   []Link
+	Deleted []distribution.DeletedFile // Old videos removed from Drive to make room
+	Email   Email
+	Notes   []string
+
+	FFmpeg string // Version of the ffmpeg that trimmed and encoded
+}
+
+// Step is how long one workflow step took
+type Step struct {
+	Name     string
+	Duration time.Duration
+	Usage    resource.Usage // Zero when the step was not measured
+}
+
+// MeasuredSteps returns the steps whose resource use was sampled
+func (s RunSummary) MeasuredSteps() []Step {
+	var measured []Step
+	for _, step := range s.Steps {
+		if !step.Usage.IsZero() {
+			measured = append(measured, step)
+		}
+	}
+	return measured
+}
+
+// File is a local output of the run
+type File struct {
+	Kind string // "Video" or "Audio"
+	Path string
+	Size int64 // Bytes
+}
+
+// Link is a shareable URL produced by the run
+type Link struct {
+	Label string
+	URL   string
+}
+
+// Email is the notification as it was sent
+type Email struct {
+	Subject   string
+	To        []string
+	CC        []string
+	PlainText string
+	HTML      string
+}
+
+// Title is the heading used in both formats
+func (s RunSummary) Title() string {
+	title := "Service Recording " + s.ServiceDate.Format("2006-01-02")
+	if s.Church != "" {
+		title = s.Church + ": " + title
+	}
+	return title
+}
+
+// FileName returns the archive file name for a format, e.g. 2025-12-28.md
+func (s RunSummary) FileName(format string) string {
+	ext := ".md"
+	if format == FormatHTML {
+		ext = ".html"
+	}
+	return s.ServiceDate.Format("2006-01-02") + ext
+}
+
+var funcs = map[string]any{
+	"duration": formatDuration,
+	"size":     distribution.FormatSize,
+	"join":     strings.Join,
+}
+
+var markdownTemplate = template.Must(template.New("markdown").Funcs(funcs).Parse(`# {{.Title}}
+
+| | |
+|---|---|
+| Service date | {{.ServiceDate.Format "2006-01-02"}} |
+| Processed | {{.ProcessedAt.Format "2006-01-02 15:04"}} |
+{{- if .Minister}}
+| Minister | {{.Minister}} |
+{{- end}}
+| Source | {{.SourceFile}} |
+| Trim range | {{.StartTime}} to {{.EndTime}} |
+{{- if .AudioOnly}}
+| Mode | Audio only |
+{{- end}}
+{{- if .FFmpeg}}
+| FFmpeg | {{.FFmpeg}} |
+{{- end}}
+| Total time | {{duration .Total}} |
+
+## Steps
+
+| Step | Time |
+|---|---|
+{{- range .Steps}}
+| {{.Name}} | {{duration .Duration}} |
+{{- end}}
+{{if .MeasuredSteps}}
+## Resource Use
+{{range .MeasuredSteps}}
+- {{.Name}}: {{.Usage}}
+{{- end}}
+{{end}}
+{{- if .Files}}
+## Files
+{{range .Files}}
+- {{.Kind}}: ` + "`{{.Path}}`" + ` ({{size .Size}})
+{{- end}}
+{{end}}
+{{- if .Links}}
+## Links
+{{range .Links}}
+- [{{.Label}}]({{.URL}})
+{{- end}}
+{{end}}
+{{- if .Deleted}}
+## Deleted from Drive
+
+| File | Service date | Size |
+|---|---|---|
+{{- range .Deleted}}
+| {{.Name}} | {{.Date}} | {{size .Size}} |
+{{- end}}
+{{end}}
+{{- if .Notes}}
+## Notes
+{{range .Notes}}
+- {{.}}
+{{- end}}
+{{end}}
+{{- if .Email.Subject}}
+## Email
+
+**Subject:** {{.Email.Subject}}  
+**To:** {{join .Email.To ", "}}  
+{{- if .Email.CC}}
+**CC:** {{join .Email.CC ", "}}  
+{{- end}}
+
+` + "```" + `
+{{.Email.PlainText}}
+` + "```" + `
+{{end}}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+.email { border: 1px solid #ccc; padding: 1em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr><th>Service date</th><td>{{.ServiceDate.Format "2006-01-02"}}</td></tr>
+<tr><th>Processed</th><td>{{.ProcessedAt.Format "2006-01-02 15:04"}}</td></tr>
+{{- if .Minister}}
+<tr><th>Minister</th><td>{{.Minister}}</td></tr>
+{{- end}}
+<tr><th>Source</th><td>{{.SourceFile}}</td></tr>
+<tr><th>Trim range</th><td>{{.StartTime}} to {{.EndTime}}</td></tr>
+{{- if .AudioOnly}}
+<tr><th>Mode</th><td>Audio only</td></tr>
+{{- end}}
+{{- if .FFmpeg}}
+<tr><th>FFmpeg</th><td>{{.FFmpeg}}</td></tr>
+{{- end}}
+<tr><th>Total time</th><td>{{duration .Total}}</td></tr>
+</table>
+<h2>Steps</h2>
+<table>
+<tr><th>Step</th><th>Time</th></tr>
+{{- range .Steps}}
+<tr><td>{{.Name}}</td><td>{{duration .Duration}}</td></tr>
+{{- end}}
+</table>
+{{- if .MeasuredSteps}}
+<h2>Resource Use</h2>
+<ul>
+{{- range .MeasuredSteps}}
+<li>{{.Name}}: {{.Usage}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Files}}
+<h2>Files</h2>
+<ul>
+{{- range .Files}}
+<li>{{.Kind}}: <code>{{.Path}}</code> ({{size .Size}})</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Links}}
+<h2>Links</h2>
+<ul>
+{{- range .Links}}
+<li><a href="{{.URL}}">{{.Label}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Deleted}}
+<h2>Deleted from Drive</h2>
+<table>
+<tr><th>File</th><th>Service date</th><th>Size</th></tr>
+{{- range .Deleted}}
+<tr><td>{{.Name}}</td><td>{{.Date}}</td><td>{{size .Size}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Notes}}
+<h2>Notes</h2>
+<ul>
+{{- range .Notes}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Email.Subject}}
+<h2>Email</h2>
+<p><strong>Subject:</strong> {{.Email.Subject}}<br>
+<strong>To:</strong> {{join .Email.To ", "}}
+{{- if .Email.CC}}<br>
+<strong>CC:</strong> {{join .Email.CC ", "}}
+{{- end}}</p>
+<div class="email">{{.EmailHTML}}</div>
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlView adds the trusted email body to a summary for the HTML template
+type htmlView struct {
+	RunSummary
+	EmailHTML htmltemplate.HTML
+}
+
+// Render returns the summary in the given format
+func (s RunSummary) Render(format string) (string, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatMarkdown:
+		err = markdownTemplate.Execute(&buf, s)
+	case FormatHTML:
+		// The email body comes from our own template, not from user input
+		err = htmlTemplate.Execute(&buf, htmlView{RunSummary: s, EmailHTML: htmltemplate.HTML(s.Email.HTML)})
+	default:
+		return "", fmt.Errorf("unknown summary format %q", format)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s summary: %w", format, err)
+	}
+	return buf.String(), nil
+}
+
+// formatDuration formats a duration like the process output, e.g. "12m 5s"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	m := d / time.Minute
+	sec := (d % time.Minute) / time.Second
+	if m > 0 {
+		return fmt.Sprintf("%dm %ds", m, sec)
+	}
+	return fmt.Sprintf("%ds", sec)
+}
+
+// Archive stores run summaries, e.g. in the congregation's records folder
+type Archive interface {
+	// Save writes the summary and returns the paths written
+	Save(s RunSummary) ([]string, error)
+}
@@ -0,0 +1,108 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/resource"
+)
+
+func testSummary() RunSummary {
+	return RunSummary{
+		Church:      "White Plains",
+		ServiceDate: time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		ProcessedAt: time.Date(2025, 12, 28, 13, 5, 0, 0, time.UTC),
+		Minister:    "Pr. Smith",
+		SourceFile:  "2025-12-28 10-06-16.mp4",
+		StartTime:   "00:05:30",
+		EndTime:     "01:45:00",
+		Steps: []Step{
+			{Name: "Trim video", Duration: 95 * time.Second, Usage: resource.Usage{CPUPeak: 350, CPUAverage: 210, MemoryPeak: 400 * 1024 * 1024}},
+			{Name: "Upload audio", Duration: 40 * time.Second},
+		},
+		Total: 12*time.Minute + 5*time.Second,
+		Files: []File{{Kind: "Audio", Path: "/audio/2025-12-28.mp3", Size: 90 * 1024 * 1024}},
+		Links: []Link{{Label: "Audio", URL: "https://drive.google.com/file/d/a/view"}},
+		Deleted: []distribution.DeletedFile{
+			{Name: "2025-11-01.mp4", Size: 1 << 30, ServiceDate: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		Email: Email{
+			Subject:   "White Plains: Recording of Service on 12/28/2025",
+			To:        []string{"Jane Doe <jane@example.com>"},
+			PlainText: "Dear Jane,",
+			HTML:      `<div dir="ltr">Dear Jane,</div>`,
+		},
+		Notes:  []string{"organ mic <buzzing>"},
+		FFmpeg: "6.1.1",
+	}
+}
+
+func TestRunSummary_RenderMarkdown(t *testing.T) {
+	got, err := testSummary().Render(FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{
+		"# White Plains: Service Recording 2025-12-28",
+		"| Trim range | 00:05:30 to 01:45:00 |",
+		"| Total time | 12m 5s |",
+		"| FFmpeg | 6.1.1 |",
+		"| Trim video | 1m 35s |",
+		"## Resource Use\n\n- Trim video: CPU 350% peak, 210% average; memory 400.0 MB peak;",
+		"- Audio: `/audio/2025-12-28.mp3` (90.0 MB)",
+		"- [Audio](https://drive.google.com/file/d/a/view)",
+		"## Deleted from Drive\n\n| File | Service date | Size |\n|---|---|---|\n| 2025-11-01.mp4 | 2025-11-01 | 1.0 GB |",
+		"- organ mic <buzzing>",
+		"**To:** Jane Doe <jane@example.com>",
+		"Dear Jane,",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunSummary_RenderHTML(t *testing.T) {
+	got, err := testSummary().Render(FormatHTML)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{
+		"<title>White Plains: Service Recording 2025-12-28</title>",
+		`<a href="https://drive.google.com/file/d/a/view">Audio</a>`,
+		"<li>Trim video: CPU 350% peak, 210% average;",
+		"<tr><td>2025-11-01.mp4</td><td>2025-11-01</td><td>1.0 GB</td></tr>",
+		"organ mic &lt;buzzing&gt;",
+		`<div class="email"><div dir="ltr">Dear Jane,</div></div>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "", want: "markdown,html"},
+		{input: "md", want: "markdown"},
+		{input: "HTML, markdown", want: "html,markdown"},
+		{input: "pdf", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFormats(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormats(%q) error = %v", tt.input, err)
+			}
+			if !tt.wantErr && strings.Join(got, ",") != tt.want {
+				t.Errorf("ParseFormats(%q) = %v, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package video
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,6 +18,9 @@ type AudioExtractionRequest struct {
 	Bitrate         string
 	StartTime       *Timestamp // Optional: start timestamp for extraction
 	EndTime         *Timestamp // Optional: end timestamp for extraction
+	AudioTrack      int        // Optional: 1-based audio stream to extract; 0 uses the default
+	Tags            MediaTags  // Optional: title and scripture written into the MP3
+	Variant         string     // Optional: names an extra version, e.g. "64k" for YYYY-MM-DD-64k.mp3
 }
 
 // NewAudioExtractionRequest creates a new AudioExtractionRequest with validation
@@ -71,12 +76,94 @@ func (r *AudioExtractionRequest) HasTimestamps() bool {
 	return r.StartTime != nil && r.EndTime != nil
 }
 
-// OutputFilename returns the output filename in YYYY-MM-DD.mp3 format
+// OutputFilename returns the output filename in YYYY-MM-DD.mp3 format, or
+// YYYY-MM-DD-<variant>.mp3 for an extra version
 func (r *AudioExtractionRequest) OutputFilename() string {
+	if r.Variant != "" {
+		return r.ServiceDate.Format("2006-01-02") + "-" + r.Variant + ".mp3"
+	}
 	return r.ServiceDate.Format("2006-01-02") + ".mp3"
 }
 
+// AtBitrate returns a copy of the request for an extra version at bitrate,
+// named after it so it sits next to the main MP3
+func (r *AudioExtractionRequest) AtBitrate(bitrate string) *AudioExtractionRequest {
+	v := *r
+	v.Bitrate = bitrate
+	v.Variant = strings.ToLower(strings.TrimSpace(bitrate))
+	return &v
+}
+
 // OutputPath returns the full output path including the directory
 func (r *AudioExtractionRequest) OutputPath(outputDir string) string {
 	return filepath.Join(outputDir, r.OutputFilename())
 }
+
+// EstimatedSize returns the expected MP3 size in bytes from the bitrate and the
+// length between the timestamps. It returns 0 when either is unknown.
+func (r *AudioExtractionRequest) EstimatedSize() int64 {
+	if !r.HasTimestamps() {
+		return 0
+	}
+	bitsPerSecond, err := ParseBitrate(r.Bitrate)
+	if err != nil {
+		return 0
+	}
+	seconds := r.EndTime.TotalSeconds() - r.StartTime.TotalSeconds()
+	if seconds <= 0 {
+		return 0
+	}
+	return int64(seconds) * bitsPerSecond / 8
+}
+
+// ValidateBitrates checks a list of ffmpeg bitrates such as "192k" and
+// "64k", which must not repeat
+func ValidateBitrates(bitrates []string) error {
+	seen := make(map[int64]string)
+	for _, b := range bitrates {
+		n, err := ParseBitrate(b)
+		if err != nil {
+			return err
+		}
+		if prev, ok := seen[n]; ok {
+			return fmt.Errorf("bitrate %q repeats %q", b, prev)
+		}
+		seen[n] = b
+	}
+	return nil
+}
+
+// ParseBitrate parses an ffmpeg bitrate such as "192k" into bits per second.
+// A common misspelling such as "192kbps" is rejected with the form to use.
+func ParseBitrate(s string) (int64, error) {
+	if n, ok := parseBitrate(s); ok {
+		return n, nil
+	}
+	raw := strings.ToLower(strings.TrimSpace(s))
+	for _, suffix := range []string{"bps", "b/s", "bit/s", "bits"} {
+		if trimmed, ok := strings.CutSuffix(raw, suffix); ok {
+			trimmed = strings.ReplaceAll(trimmed, " ", "")
+			if _, ok := parseBitrate(trimmed); ok {
+				return 0, fmt.Errorf("'%s' is not a bitrate, use '%s'", s, trimmed)
+			}
+		}
+	}
+	return 0, fmt.Errorf("'%s' is not a bitrate, use e.g. '%s'", s, DefaultAudioBitrate)
+}
+
+// parseBitrate parses an ffmpeg bitrate such as "192k" into bits per second
+func parseBitrate(s string) (int64, bool) {
+	raw := strings.ToLower(strings.TrimSpace(s))
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(raw, "k"):
+		multiplier, raw = 1000, strings.TrimSuffix(raw, "k")
+	case strings.HasSuffix(raw, "m"):
+		multiplier, raw = 1000*1000, strings.TrimSuffix(raw, "m")
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n * multiplier, true
+}
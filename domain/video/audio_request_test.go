@@ -1,6 +1,7 @@
 package video
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -109,6 +110,62 @@ func TestAudioExtractionRequest_OutputFilename(t *testing.T) {
 	}
 }
 
+func TestAudioExtractionRequest_AtBitrate(t *testing.T) {
+	req := &AudioExtractionRequest{
+		SourceVideoPath: "/videos/2025-12-28.mp4",
+		ServiceDate:     time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		Bitrate:         "192k",
+		AudioTrack:      2,
+	}
+
+	v := req.AtBitrate("64K")
+	if v.Bitrate != "64K" || v.AudioTrack != 2 || v.SourceVideoPath != req.SourceVideoPath {
+		t.Errorf("AtBitrate() = %+v, want a copy at 64K", v)
+	}
+	if got, want := v.OutputFilename(), "2025-12-28-64k.mp3"; got != want {
+		t.Errorf("OutputFilename() = %q, want %q", got, want)
+	}
+	if got, want := req.OutputFilename(), "2025-12-28.mp3"; got != want {
+		t.Errorf("AtBitrate() changed the original: OutputFilename() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateBitrates(t *testing.T) {
+	tests := []struct {
+		name     string
+		bitrates []string
+		wantErr  bool
+	}{
+		{"empty", nil, false},
+		{"several", []string{"192k", "64k", "1m"}, false},
+		{"malformed", []string{"192k", "fast"}, true},
+		{"repeated", []string{"192k", "64k", "64K"}, true},
+		{"same rate written twice", []string{"1000k", "1m"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateBitrates(tt.bitrates); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBitrates(%v) error = %v, wantErr %v", tt.bitrates, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseBitrate(t *testing.T) {
+	if n, err := ParseBitrate("192k"); err != nil || n != 192000 {
+		t.Errorf("ParseBitrate(192k) = %d, %v", n, err)
+	}
+	_, err := ParseBitrate("192kbps")
+	if err == nil || !strings.Contains(err.Error(), "use '192k'") {
+		t.Errorf("ParseBitrate(192kbps) error = %v, want a hint to use 192k", err)
+	}
+	_, err = ParseBitrate("fast")
+	if err == nil || !strings.Contains(err.Error(), "e.g. '192k'") {
+		t.Errorf("ParseBitrate(fast) error = %v, want an example", err)
+	}
+}
+
 func TestAudioExtractionRequest_OutputPath(t *testing.T) {
 	req := &AudioExtractionRequest{
 		ServiceDate: time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
@@ -130,3 +187,36 @@ func TestAudioExtractionRequest_OutputPath(t *testing.T) {
 		})
 	}
 }
+
+func TestAudioExtractionRequest_EstimatedSize(t *testing.T) {
+	date := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		bitrate string
+		start   string
+		end     string
+		want    int64
+	}{
+		{name: "192k for an hour", bitrate: "192k", start: "00:00:00", end: "01:00:00", want: 3600 * 192000 / 8},
+		{name: "plain bits per second", bitrate: "128000", start: "00:00:00", end: "00:00:10", want: 160000},
+		{name: "invalid bitrate", bitrate: "fast", start: "00:00:00", end: "01:00:00", want: 0},
+		{name: "end before start", bitrate: "192k", start: "01:00:00", end: "00:30:00", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := NewAudioExtractionRequestWithTimestamps("src.mp4", date, tt.bitrate, tt.start, tt.end)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := req.EstimatedSize(); got != tt.want {
+				t.Errorf("EstimatedSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := (&AudioExtractionRequest{Bitrate: "192k"}).EstimatedSize(); got != 0 {
+		t.Errorf("EstimatedSize() without timestamps = %d, want 0", got)
+	}
+}
@@ -0,0 +1,23 @@
+package video
+
+import "fmt"
+
+// DefaultAudioTrack lets ffmpeg choose the audio stream (the first one)
+const DefaultAudioTrack = 0
+
+// ValidateAudioTrack checks a 1-based audio track number; 0 means the default
+func ValidateAudioTrack(track int) error {
+	if track < 0 {
+		return fmt.Errorf("invalid audio track %d: must be 1 or greater", track)
+	}
+	return nil
+}
+
+// AudioTrackMap returns the ffmpeg -map specifier for a 1-based audio track,
+// or "" for the default track
+func AudioTrackMap(track int) string {
+	if track <= DefaultAudioTrack {
+		return ""
+	}
+	return fmt.Sprintf("0:a:%d", track-1)
+}
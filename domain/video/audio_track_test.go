@@ -0,0 +1,31 @@
+package video
+
+import "testing"
+
+func TestAudioTrackMap(t *testing.T) {
+	tests := []struct {
+		track int
+		want  string
+	}{
+		{0, ""},
+		{1, "0:a:0"},
+		{2, "0:a:1"},
+	}
+
+	for _, tt := range tests {
+		if got := AudioTrackMap(tt.track); got != tt.want {
+			t.Errorf("AudioTrackMap(%d) = %q, want %q", tt.track, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAudioTrack(t *testing.T) {
+	for _, track := range []int{0, 1, 4} {
+		if err := ValidateAudioTrack(track); err != nil {
+			t.Errorf("ValidateAudioTrack(%d) unexpected error: %v", track, err)
+		}
+	}
+	if err := ValidateAudioTrack(-1); err == nil {
+		t.Error("ValidateAudioTrack(-1) expected error")
+	}
+}
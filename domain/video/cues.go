@@ -0,0 +1,209 @@
+package video
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidCues is returned when a cue file cannot be used
+var ErrInvalidCues = errors.New("invalid cue file")
+
+// Chapter marks where a part of the service begins, e.g. the sermon. At is
+// on the source recording's timeline.
+type Chapter struct {
+	Title string
+	At    Timestamp
+}
+
+// Cues are the markers the sound desk writes alongside a recording. Start and
+// End are nil when the file has no such marker.
+type Cues struct {
+	Start    *Timestamp
+	End      *Timestamp
+	Chapters []Chapter
+}
+
+// cueMarker is one row of a cue file
+type cueMarker struct {
+	Marker string `json:"marker"`
+	Time   string `json:"time"`
+	Label  string `json:"label,omitempty"`
+}
+
+// CueSidecars returns where a cue file for the source would be: next to it,
+// with the same name and a .csv or .json extension
+func CueSidecars(sourcePath string) []string {
+	base := strings.TrimSuffix(sourcePath, filepath.Ext(sourcePath))
+	return []string{base + ".csv", base + ".json"}
+}
+
+// ParseCues reads a cue file; its format comes from the name's extension.
+//
+// CSV has a header row naming the marker, time and optional label columns:
+//
+//	marker,time,label
+//	start,00:05:30,
+//	chapter,00:32:00,Sermon
+//	end,01:45:00,
+//
+// JSON has the same fields in a list of markers:
+//
+//	{"markers": [{"marker": "start", "time": "00:05:30"}, ...]}
+//
+// Times are HH:MM:SS; fractions of a second are dropped.
+func ParseCues(name string, data []byte) (*Cues, error) {
+	var markers []cueMarker
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".csv":
+		markers, err = readCSVCues(data)
+	case ".json":
+		markers, err = readJSONCues(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q, use .csv or .json", ErrInvalidCues, ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newCues(markers)
+}
+
+func readCSVCues(data []byte) ([]cueMarker, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidCues)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCues, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	markerCol, hasMarker := columns["marker"]
+	timeCol, hasTime := columns["time"]
+	if !hasMarker || !hasTime {
+		return nil, fmt.Errorf("%w: line 1: the header must name the marker and time columns, e.g. \"marker,time,label\"", ErrInvalidCues)
+	}
+	labelCol, hasLabel := columns["label"]
+
+	var markers []cueMarker
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return markers, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCues, err)
+		}
+		line, _ := r.FieldPos(0)
+		field := func(col int) string {
+			if col < len(record) {
+				return strings.TrimSpace(record[col])
+			}
+			return ""
+		}
+		m := cueMarker{Marker: field(markerCol), Time: field(timeCol)}
+		if hasLabel {
+			m.Label = field(labelCol)
+		}
+		if err := m.check(fmt.Sprintf("line %d", line)); err != nil {
+			return nil, err
+		}
+		markers = append(markers, m)
+	}
+}
+
+func readJSONCues(data []byte) ([]cueMarker, error) {
+	var file struct {
+		Markers []cueMarker `json:"markers"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %v (expected {\"markers\": [{\"marker\": \"start\", \"time\": \"00:05:30\"}, ...]})", ErrInvalidCues, err)
+	}
+	for i := range file.Markers {
+		if err := file.Markers[i].check(fmt.Sprintf("marker %d", i+1)); err != nil {
+			return nil, err
+		}
+	}
+	return file.Markers, nil
+}
+
+// check normalizes the marker name and time, naming where a bad one is
+func (m *cueMarker) check(where string) error {
+	m.Marker = strings.ToLower(strings.TrimSpace(m.Marker))
+	switch m.Marker {
+	case "start", "end", "chapter":
+	case "":
+		return fmt.Errorf("%w: %s: the marker is missing", ErrInvalidCues, where)
+	default:
+		return fmt.Errorf("%w: %s: unknown marker %q, use start, end or chapter", ErrInvalidCues, where, m.Marker)
+	}
+	if whole, _, ok := strings.Cut(strings.TrimSpace(m.Time), "."); ok {
+		m.Time = whole
+	}
+	if _, err := ParseTimestamp(strings.TrimSpace(m.Time)); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidCues, where, err)
+	}
+	m.Time = strings.TrimSpace(m.Time)
+	return nil
+}
+
+// newCues maps the markers to a start, end and chapters, and checks they fit
+// together
+func newCues(markers []cueMarker) (*Cues, error) {
+	cues := &Cues{}
+	for _, m := range markers {
+		ts, _ := ParseTimestamp(m.Time)
+		switch m.Marker {
+		case "start":
+			if cues.Start != nil {
+				return nil, fmt.Errorf("%w: more than one start marker (%s and %s)", ErrInvalidCues, cues.Start, ts)
+			}
+			cues.Start = &ts
+		case "end":
+			if cues.End != nil {
+				return nil, fmt.Errorf("%w: more than one end marker (%s and %s)", ErrInvalidCues, cues.End, ts)
+			}
+			cues.End = &ts
+		case "chapter":
+			cues.Chapters = append(cues.Chapters, Chapter{Title: m.Label, At: ts})
+		}
+	}
+	if cues.Start == nil && cues.End == nil && len(cues.Chapters) == 0 {
+		return nil, fmt.Errorf("%w: no start, end or chapter markers", ErrInvalidCues)
+	}
+	if cues.Start != nil && cues.End != nil && !cues.End.After(*cues.Start) {
+		return nil, fmt.Errorf("%w: the end marker %s must be after the start marker %s", ErrInvalidCues, cues.End, cues.Start)
+	}
+
+	sort.SliceStable(cues.Chapters, func(i, j int) bool {
+		return cues.Chapters[i].At.Before(cues.Chapters[j].At)
+	})
+	for i, c := range cues.Chapters {
+		if cues.Start != nil && c.At.Before(*cues.Start) {
+			return nil, fmt.Errorf("%w: chapter %q at %s is before the start marker %s", ErrInvalidCues, c.Title, c.At, cues.Start)
+		}
+		if cues.End != nil && !c.At.Before(*cues.End) {
+			return nil, fmt.Errorf("%w: chapter %q at %s is not before the end marker %s", ErrInvalidCues, c.Title, c.At, cues.End)
+		}
+		if c.Title == "" {
+			cues.Chapters[i].Title = fmt.Sprintf("Chapter %d", i+1)
+		}
+	}
+	return cues, nil
+}
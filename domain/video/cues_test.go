@@ -0,0 +1,73 @@
+package video
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseCues_CSV(t *testing.T) {
+	data := "marker,time,label\n" +
+		"# written by the sound desk\n" +
+		"start,00:05:30.250,\n" +
+		"chapter,00:40:00,Sermon\n" +
+		"chapter,00:10:00,\n" +
+		"end,01:45:00,\n"
+	cues, err := ParseCues("service.csv", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cues.Start == nil || cues.Start.String() != "00:05:30" {
+		t.Errorf("start = %v, want 00:05:30", cues.Start)
+	}
+	if cues.End == nil || cues.End.String() != "01:45:00" {
+		t.Errorf("end = %v, want 01:45:00", cues.End)
+	}
+	want := []Chapter{{Title: "Chapter 1", At: Timestamp{Minutes: 10}}, {Title: "Sermon", At: Timestamp{Minutes: 40}}}
+	if len(cues.Chapters) != 2 || cues.Chapters[0] != want[0] || cues.Chapters[1] != want[1] {
+		t.Errorf("chapters = %+v, want %+v in order", cues.Chapters, want)
+	}
+}
+
+func TestParseCues_JSON(t *testing.T) {
+	cues, err := ParseCues("service.JSON", []byte(`{"markers": [{"marker": "Start", "time": "00:05:30"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cues.Start == nil || cues.Start.String() != "00:05:30" || cues.End != nil {
+		t.Errorf("cues = %+v, want only a start", cues)
+	}
+}
+
+func TestParseCues_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"cues.txt", "start,00:05:30", "unsupported format"},
+		{"cues.csv", "", "the file is empty"},
+		{"cues.csv", "type,at\nstart,00:05:30\n", "line 1: the header must name the marker and time columns"},
+		{"cues.csv", "marker,time\nbegin,00:05:30\n", "line 2: unknown marker \"begin\""},
+		{"cues.csv", "marker,time\nstart,5:30\n", "line 2: invalid timestamp format"},
+		{"cues.csv", "marker,time\nstart,00:05:30\nstart,00:06:00\n", "more than one start marker"},
+		{"cues.csv", "marker,time\nstart,01:00:00\nend,00:30:00\n", "must be after the start marker"},
+		{"cues.csv", "marker,time\n", "no start, end or chapter markers"},
+		{"cues.csv", "marker,time,label\nstart,00:05:30,\nchapter,00:01:00,Welcome\n", "chapter \"Welcome\" at 00:01:00 is before the start marker"},
+		{"cues.json", `{"markers": [{"marker": "start", "at": "00:05:30"}]}`, "unknown field \"at\""},
+		{"cues.json", `{"markers": [{"time": "00:05:30"}]}`, "marker 1: the marker is missing"},
+	}
+	for _, tt := range tests {
+		_, err := ParseCues(tt.name, []byte(tt.data))
+		if !errors.Is(err, ErrInvalidCues) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseCues(%q, %q) error = %v, want %q", tt.name, tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestCueSidecars(t *testing.T) {
+	got := CueSidecars("/rec/2025-12-28 10-06-16.mp4")
+	if len(got) != 2 || got[0] != "/rec/2025-12-28 10-06-16.csv" || got[1] != "/rec/2025-12-28 10-06-16.json" {
+		t.Errorf("CueSidecars() = %q", got)
+	}
+}
@@ -1,6 +1,9 @@
 package video
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // AudioExtractor defines the interface for audio extraction operations
 // This is a port that can be implemented by different infrastructure adapters
@@ -8,3 +11,9 @@ type AudioExtractor interface {
 	// Extract extracts audio from a video according to the request and saves to outputPath
 	Extract(ctx context.Context, req *AudioExtractionRequest, outputPath string) error
 }
+
+// AudioStreamer writes extracted MP3 audio to a stream instead of a file, so it
+// can be uploaded while it is still being encoded
+type AudioStreamer interface {
+	Stream(ctx context.Context, req *AudioExtractionRequest, w io.Writer) error
+}
@@ -0,0 +1,140 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultAspect is the display aspect ratio recordings are expected to have
+const DefaultAspect = "16:9"
+
+// AspectAny turns off the aspect ratio check
+const AspectAny = "any"
+
+// aspectTolerance is how far a display aspect may be from the expected one,
+// as a fraction, before it is reported
+const aspectTolerance = 0.01
+
+// Geometry is the frame size and orientation of a video's first video stream
+type Geometry struct {
+	Width  int
+	Height int
+	// SARNum and SARDen are the sample (pixel) aspect ratio; zero means
+	// square pixels
+	SARNum int
+	SARDen int
+	// Rotation is the display rotation in degrees, e.g. 90 for a phone
+	// recording held upright
+	Rotation int
+}
+
+// DisplaySize returns the size the video is shown at, after stretching
+// non-square pixels and applying the rotation
+func (g Geometry) DisplaySize() (width, height float64) {
+	width, height = float64(g.Width), float64(g.Height)
+	if g.SARNum > 0 && g.SARDen > 0 {
+		width = width * float64(g.SARNum) / float64(g.SARDen)
+	}
+	if r := ((g.Rotation % 360) + 360) % 360; r == 90 || r == 270 {
+		width, height = height, width
+	}
+	return width, height
+}
+
+// String describes the geometry, e.g. "1920x1080 (16:9)"
+func (g Geometry) String() string {
+	w, h := g.DisplaySize()
+	return fmt.Sprintf("%dx%d (%s)", g.Width, g.Height, aspectLabel(w, h))
+}
+
+// GeometryProber reads the geometry of a video file
+type GeometryProber interface {
+	Geometry(ctx context.Context, path string) (Geometry, error)
+}
+
+// GeometryExpectation is what a correctly configured recording looks like
+type GeometryExpectation struct {
+	// Width and Height are the expected frame size; zero accepts any
+	Width  int
+	Height int
+	// Aspect is the expected display aspect as a ratio of width to height;
+	// zero accepts any
+	Aspect      float64
+	AspectLabel string
+}
+
+// NewGeometryExpectation builds an expectation from a frame size and an
+// aspect such as "16:9"; an empty aspect means DefaultAspect and "any" turns
+// the aspect check off
+func NewGeometryExpectation(width, height int, aspect string) (GeometryExpectation, error) {
+	if width < 0 || height < 0 {
+		return GeometryExpectation{}, fmt.Errorf("frame size %dx%d must not be negative", width, height)
+	}
+	e := GeometryExpectation{Width: width, Height: height}
+	if aspect == "" {
+		aspect = DefaultAspect
+	}
+	if aspect == AspectAny {
+		return e, nil
+	}
+	w, h, ok := strings.Cut(aspect, ":")
+	num, err1 := strconv.Atoi(w)
+	den, err2 := strconv.Atoi(h)
+	if !ok || err1 != nil || err2 != nil || num <= 0 || den <= 0 {
+		return GeometryExpectation{}, fmt.Errorf("aspect %q must look like 16:9, or be %q", aspect, AspectAny)
+	}
+	e.Aspect = float64(num) / float64(den)
+	e.AspectLabel = aspect
+	return e, nil
+}
+
+// Problems lists the ways g differs from the expectation, or nil when the
+// video looks right. A portrait or rotated video is always reported.
+func (e GeometryExpectation) Problems(g Geometry) []string {
+	var problems []string
+	w, h := g.DisplaySize()
+	if h > w {
+		msg := fmt.Sprintf("video is portrait, shown at %.0fx%.0f", w, h)
+		if g.Rotation%360 != 0 {
+			msg += fmt.Sprintf(" after a %d° rotation", g.Rotation)
+		}
+		problems = append(problems, msg)
+	} else if g.Rotation%360 != 0 {
+		problems = append(problems, fmt.Sprintf("video is rotated %d°", g.Rotation))
+	}
+	if (e.Width > 0 && g.Width != e.Width) || (e.Height > 0 && g.Height != e.Height) {
+		problems = append(problems, fmt.Sprintf("video is %dx%d, expected %s", g.Width, g.Height, e.sizeLabel()))
+	}
+	if e.Aspect > 0 && h > 0 && math.Abs(w/h-e.Aspect)/e.Aspect > aspectTolerance {
+		problems = append(problems, fmt.Sprintf("display aspect is %s, expected %s", aspectLabel(w, h), e.AspectLabel))
+	}
+	return problems
+}
+
+func (e GeometryExpectation) sizeLabel() string {
+	size := func(n int) string {
+		if n == 0 {
+			return "any"
+		}
+		return strconv.Itoa(n)
+	}
+	return size(e.Width) + "x" + size(e.Height)
+}
+
+// aspectLabel names a display size's aspect ratio, e.g. 16:9 for 1920x1080
+// or 3:2 for 720x480; sizes without a small whole ratio are given as a decimal
+func aspectLabel(w, h float64) string {
+	if h <= 0 {
+		return "unknown"
+	}
+	for den := 1; den <= 20; den++ {
+		num := w / h * float64(den)
+		if math.Abs(num-math.Round(num)) < 0.01 {
+			return fmt.Sprintf("%d:%d", int(math.Round(num)), den)
+		}
+	}
+	return fmt.Sprintf("%.2f:1", w/h)
+}
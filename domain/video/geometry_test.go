@@ -0,0 +1,65 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewGeometryExpectation(t *testing.T) {
+	e, err := NewGeometryExpectation(0, 0, "")
+	if err != nil || e.AspectLabel != DefaultAspect {
+		t.Errorf("default expectation = %+v, %v; want the %s aspect", e, err, DefaultAspect)
+	}
+
+	e, err = NewGeometryExpectation(1920, 1080, AspectAny)
+	if err != nil || e.Aspect != 0 {
+		t.Errorf("%q expectation = %+v, %v; want no aspect check", AspectAny, e, err)
+	}
+
+	for _, aspect := range []string{"wide", "16/9", "0:9", "16:"} {
+		if _, err := NewGeometryExpectation(0, 0, aspect); err == nil {
+			t.Errorf("NewGeometryExpectation(%q) succeeded, want an error", aspect)
+		}
+	}
+	if _, err := NewGeometryExpectation(-1, 0, ""); err == nil {
+		t.Error("negative width accepted")
+	}
+}
+
+func TestGeometryExpectation_Problems(t *testing.T) {
+	expect, _ := NewGeometryExpectation(1920, 1080, "16:9")
+	anySize, _ := NewGeometryExpectation(0, 0, "16:9")
+
+	tests := []struct {
+		name   string
+		expect GeometryExpectation
+		g      Geometry
+		want   []string
+	}{
+		{name: "correct", expect: expect, g: Geometry{Width: 1920, Height: 1080}},
+		{name: "720p is fine without a size", expect: anySize, g: Geometry{Width: 1280, Height: 720}},
+		{name: "anamorphic DVD is 16:9", expect: anySize, g: Geometry{Width: 720, Height: 480, SARNum: 32, SARDen: 27}},
+		{name: "stretched 720x480", expect: expect, g: Geometry{Width: 720, Height: 480},
+			want: []string{"video is 720x480, expected 1920x1080", "display aspect is 3:2, expected 16:9"}},
+		{name: "portrait frame", expect: anySize, g: Geometry{Width: 1080, Height: 1920},
+			want: []string{"video is portrait", "display aspect is 9:16"}},
+		{name: "rotated by metadata", expect: anySize, g: Geometry{Width: 1920, Height: 1080, Rotation: -90},
+			want: []string{"video is portrait, shown at 1080x1920 after a -90° rotation", "display aspect is 9:16"}},
+		{name: "upside down", expect: anySize, g: Geometry{Width: 1920, Height: 1080, Rotation: 180},
+			want: []string{"video is rotated 180°"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.expect.Problems(tt.g)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Problems() = %q, want %d problems", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
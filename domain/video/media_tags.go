@@ -0,0 +1,13 @@
+package video
+
+// MediaTags describe a recording beyond its date; they are written into the
+// trimmed MP4 and the MP3 so players show a meaningful title
+type MediaTags struct {
+	Title     string // Sermon title, e.g. "The Good Shepherd"
+	Scripture string // Scripture reading, e.g. "John 10:11-16"
+}
+
+// IsZero reports whether no tags are set
+func (t MediaTags) IsZero() bool {
+	return t.Title == "" && t.Scripture == ""
+}
@@ -0,0 +1,48 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// OverwritePolicy controls what happens when an output file already exists
+type OverwritePolicy string
+
+// Supported overwrite policies
+const (
+	OverwritePrompt    OverwritePolicy = "prompt"    // Ask before replacing
+	OverwriteReplace   OverwritePolicy = "overwrite" // Replace the existing file
+	OverwriteSkip      OverwritePolicy = "skip"      // Reuse the existing file if it is valid
+	OverwriteVersioned OverwritePolicy = "version"   // Write to the next free -vN path
+)
+
+// DefaultOverwritePolicy preserves the original behavior of replacing outputs
+const DefaultOverwritePolicy = OverwriteReplace
+
+// ParseOverwritePolicy parses a policy name; an empty string yields the default
+func ParseOverwritePolicy(s string) (OverwritePolicy, error) {
+	switch p := OverwritePolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return DefaultOverwritePolicy, nil
+	case OverwritePrompt, OverwriteReplace, OverwriteSkip, OverwriteVersioned:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid overwrite policy %q (must be prompt, overwrite, skip, or version)", s)
+	}
+}
+
+// VersionedPath returns path with a -vN suffix before the extension,
+// e.g. 2025-12-28.mp4 -> 2025-12-28-v2.mp4
+func VersionedPath(path string, n int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-v%d%s", strings.TrimSuffix(path, ext), n, ext)
+}
+
+// MediaValidator checks that an existing media file is complete and playable
+// This is a port that can be implemented by different infrastructure adapters
+type MediaValidator interface {
+	// Validate returns an error if the file is missing, truncated, or unreadable
+	Validate(ctx context.Context, path string) error
+}
@@ -0,0 +1,58 @@
+package video
+
+import (
+	"testing"
+)
+
+func TestParseOverwritePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    OverwritePolicy
+		wantErr bool
+	}{
+		{name: "empty uses default", input: "", want: OverwriteReplace},
+		{name: "prompt", input: "prompt", want: OverwritePrompt},
+		{name: "overwrite", input: "overwrite", want: OverwriteReplace},
+		{name: "skip", input: "skip", want: OverwriteSkip},
+		{name: "version", input: "version", want: OverwriteVersioned},
+		{name: "case insensitive", input: " Skip ", want: OverwriteSkip},
+		{name: "unknown", input: "replace", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOverwritePolicy(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseOverwritePolicy(%q) expected error, got %q", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOverwritePolicy(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOverwritePolicy(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionedPath(t *testing.T) {
+	tests := []struct {
+		path string
+		n    int
+		want string
+	}{
+		{"/tmp/trimmed/2025-12-28.mp4", 2, "/tmp/trimmed/2025-12-28-v2.mp4"},
+		{"/tmp/audio/2025-12-28.mp3", 3, "/tmp/audio/2025-12-28-v3.mp3"},
+		{"noext", 2, "noext-v2"},
+	}
+
+	for _, tt := range tests {
+		if got := VersionedPath(tt.path, tt.n); got != tt.want {
+			t.Errorf("VersionedPath(%q, %d) = %q, want %q", tt.path, tt.n, got, tt.want)
+		}
+	}
+}
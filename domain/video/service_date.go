@@ -0,0 +1,106 @@
+package video
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	// OBS format: YYYY-MM-DD HH-MM-SS.mp4
+	obsFilenamePattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}\s+\d{2}-\d{2}-\d{2})\.mp4$`)
+	// Trimmed format: YYYY-MM-DD.mp4
+	trimmedFilenamePattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\.mp4$`)
+	whitespace             = regexp.MustCompile(`\s+`)
+)
+
+// ServiceCalendar turns recording times into service dates. Service dates are
+// calendar days in the congregation's timezone, held as midnight UTC so they
+// compare and format the same on every machine.
+type ServiceCalendar struct {
+	// Recording is the clock OBS names recordings with (default: system timezone)
+	Recording *time.Location
+	// Service is the congregation's timezone (default: system timezone)
+	Service *time.Location
+}
+
+// Location returns the service timezone
+func (c ServiceCalendar) Location() *time.Location {
+	if c.Service == nil {
+		return time.Local
+	}
+	return c.Service
+}
+
+func (c ServiceCalendar) recordingLocation() *time.Location {
+	if c.Recording == nil {
+		return time.Local
+	}
+	return c.Recording
+}
+
+// DateOf returns the service date t falls on in the service timezone
+func (c ServiceCalendar) DateOf(t time.Time) time.Time {
+	local := t.In(c.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DateFromFilename extracts the service date from an OBS recording name such
+// as "2025-12-28 10-06-16.mp4" or a trimmed name such as "2025-12-28.mp4".
+// An OBS timestamp is read on the recording clock and converted to the service
+// timezone, so a Saturday-evening service recorded on a UTC machine keeps
+// Saturday's date.
+func (c ServiceCalendar) DateFromFilename(filename string) (time.Time, error) {
+	if obsFilenamePattern.MatchString(filename) {
+		recorded, err := c.recordedAt(filename)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return c.DateOf(recorded), nil
+	}
+	if m := trimmedFilenamePattern.FindStringSubmatch(filename); m != nil {
+		return time.Parse("2006-01-02", m[1])
+	}
+	return time.Time{}, fmt.Errorf("filename does not match expected format")
+}
+
+// ClockDrift returns how far a file's modification time is from the time in
+// its OBS recording name. OBS names the file when recording starts and the
+// file is last written when it stops, so a few hours is normal; a day or so
+// means the recording PC's clock was wrong. It is false for names without a
+// time of day, such as trimmed outputs.
+func (c ServiceCalendar) ClockDrift(filename string, modTime time.Time) (time.Duration, bool) {
+	recorded, err := c.recordedAt(filename)
+	if err != nil {
+		return 0, false
+	}
+	drift := modTime.Sub(recorded)
+	if drift < 0 {
+		drift = -drift
+	}
+	return drift, true
+}
+
+// DaysCrossed returns how many midnights in the service timezone pass
+// between start and end in an OBS recording, so a New Year's Eve service
+// from 23:30 to 01:15 crosses one. It is false for names without a time of
+// day, such as trimmed outputs.
+func (c ServiceCalendar) DaysCrossed(filename string, start, end Timestamp) (int, bool) {
+	recorded, err := c.recordedAt(filename)
+	if err != nil {
+		return 0, false
+	}
+	first := c.DateOf(recorded.Add(time.Duration(start.TotalSeconds()) * time.Second))
+	last := c.DateOf(recorded.Add(time.Duration(end.TotalSeconds()) * time.Second))
+	return int(last.Sub(first).Hours() / 24), true
+}
+
+// recordedAt reads when an OBS recording started from its name
+func (c ServiceCalendar) recordedAt(filename string) (time.Time, error) {
+	m := obsFilenamePattern.FindStringSubmatch(filename)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("filename does not match expected format")
+	}
+	stamp := whitespace.ReplaceAllString(m[1], " ")
+	return time.ParseInLocation("2006-01-02 15-04-05", stamp, c.recordingLocation())
+}
@@ -0,0 +1,155 @@
+package video
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestServiceCalendar_DateFromFilename(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	utcMachine := ServiceCalendar{Recording: time.UTC, Service: newYork}
+
+	tests := []struct {
+		name     string
+		calendar ServiceCalendar
+		filename string
+		want     string
+	}{
+		{"same clock", ServiceCalendar{Recording: newYork, Service: newYork}, "2025-12-28 10-06-16.mp4", "2025-12-28"},
+		{"saturday evening after midnight UTC", utcMachine, "2025-12-28 01-30-00.mp4", "2025-12-27"},
+		{"sunday morning on a UTC clock", utcMachine, "2025-12-28 15-00-00.mp4", "2025-12-28"},
+		{"extra spaces", utcMachine, "2025-12-28  15-00-00.mp4", "2025-12-28"},
+		// DST ends 2025-11-02 at 06:00 UTC: 04:30 UTC is 00:30 EDT, a day later it is 23:30 EST
+		{"before fall back", utcMachine, "2025-11-02 04-30-00.mp4", "2025-11-02"},
+		{"after fall back", utcMachine, "2025-11-03 04-30-00.mp4", "2025-11-02"},
+		// DST starts 2025-03-09 at 07:00 UTC: 04:30 UTC is 23:30 EST the day before
+		{"before spring forward", utcMachine, "2025-03-09 04-30-00.mp4", "2025-03-08"},
+		{"after spring forward", utcMachine, "2025-03-10 03-30-00.mp4", "2025-03-09"},
+		{"trimmed name is already a service date", utcMachine, "2025-12-28.mp4", "2025-12-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.calendar.DateFromFilename(tt.filename)
+			if err != nil {
+				t.Fatalf("DateFromFilename(%q) error = %v", tt.filename, err)
+			}
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("DateFromFilename(%q) = %s, want %s", tt.filename, got.Format("2006-01-02"), tt.want)
+			}
+			if got.Location() != time.UTC || got.Hour() != 0 {
+				t.Errorf("DateFromFilename(%q) = %v, want midnight UTC", tt.filename, got)
+			}
+		})
+	}
+}
+
+func TestServiceCalendar_DateFromFilename_Invalid(t *testing.T) {
+	for _, name := range []string{"service.mp4", "2025-12-28.mp3", "2025-13-01 10-00-00.mp4", "2025-12-28 10-06.mp4"} {
+		if _, err := (ServiceCalendar{}).DateFromFilename(name); err == nil {
+			t.Errorf("DateFromFilename(%q) expected error", name)
+		}
+	}
+}
+
+func TestServiceCalendar_DateOf(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	c := ServiceCalendar{Service: newYork}
+
+	// 2025-12-28 03:00 UTC is still Saturday evening in New York
+	got := c.DateOf(time.Date(2025, 12, 28, 3, 0, 0, 0, time.UTC))
+	if want := time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("DateOf() = %v, want %v", got, want)
+	}
+}
+
+func TestServiceCalendar_DefaultsToSystemTimezone(t *testing.T) {
+	var c ServiceCalendar
+	if c.Location() != time.Local {
+		t.Errorf("Location() = %v, want time.Local", c.Location())
+	}
+	recorded := time.Date(2025, 12, 28, 23, 59, 0, 0, time.Local)
+	got, err := c.DateFromFilename(recorded.Format("2006-01-02 15-04-05") + ".mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Format("2006-01-02") != "2025-12-28" {
+		t.Errorf("DateFromFilename() = %s, want 2025-12-28", got.Format("2006-01-02"))
+	}
+}
+
+func TestServiceCalendar_ClockDrift(t *testing.T) {
+	cal := ServiceCalendar{Recording: time.UTC, Service: time.UTC}
+
+	tests := []struct {
+		name     string
+		filename string
+		modTime  time.Time
+		want     time.Duration
+		ok       bool
+	}{
+		{"written when the recording stopped", "2025-12-28 10-06-16.mp4", time.Date(2025, 12, 28, 11, 50, 0, 0, time.UTC), time.Hour + 43*time.Minute + 44*time.Second, true},
+		{"clock a day fast", "2025-12-29 10-06-16.mp4", time.Date(2025, 12, 28, 11, 50, 0, 0, time.UTC), 22*time.Hour + 16*time.Minute + 16*time.Second, true},
+		{"trimmed name has no time of day", "2025-12-28.mp4", time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), 0, false},
+		{"unrecognised name", "service.mp4", time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := cal.ClockDrift(tt.filename, tt.modTime)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ClockDrift(%q) = %v, %v; want %v, %v", tt.filename, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestServiceCalendar_DaysCrossed(t *testing.T) {
+	utc := ServiceCalendar{Recording: time.UTC, Service: time.UTC}
+
+	tests := []struct {
+		name       string
+		cal        ServiceCalendar
+		filename   string
+		start, end Timestamp
+		want       int
+		ok         bool
+	}{
+		{"New Year's Eve past midnight", utc, "2025-12-31 23-25-00.mp4", Timestamp{Minutes: 5}, Timestamp{Hours: 1, Minutes: 50}, 1, true},
+		{"ends just before midnight", utc, "2025-12-31 21-55-00.mp4", Timestamp{Minutes: 5}, Timestamp{Hours: 2, Minutes: 4}, 0, true},
+		{"recording started before midnight, service after", utc, "2025-12-31 23-50-00.mp4", Timestamp{Minutes: 20}, Timestamp{Hours: 1}, 0, true},
+		{"trimmed name has no time of day", utc, "2025-12-31.mp4", Timestamp{}, Timestamp{Hours: 3}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.cal.DaysCrossed(tt.filename, tt.start, tt.end)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("DaysCrossed(%q) = %d, %v; want %d, %v", tt.filename, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestServiceCalendar_DaysCrossed_ServiceTimezone(t *testing.T) {
+	// A UTC recording PC; 04:25 UTC is 23:25 on New Year's Eve in New York
+	cal := ServiceCalendar{Recording: time.UTC, Service: mustLoadLocation(t, "America/New_York")}
+
+	got, ok := cal.DaysCrossed("2026-01-01 04-25-00.mp4", Timestamp{Minutes: 5}, Timestamp{Hours: 1, Minutes: 50})
+	if !ok || got != 1 {
+		t.Errorf("DaysCrossed() = %d, %v; want 1, true", got, ok)
+	}
+	got, ok = cal.DaysCrossed("2026-01-01 04-25-00.mp4", Timestamp{Minutes: 5}, Timestamp{Minutes: 30})
+	if !ok || got != 0 {
+		t.Errorf("DaysCrossed() before midnight = %d, %v; want 0, true", got, ok)
+	}
+}
@@ -0,0 +1,125 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeAnchor says what a TimeSpec's offset is measured from
+type TimeAnchor int
+
+const (
+	// AnchorAbsolute is a plain HH:MM:SS position in the file
+	AnchorAbsolute TimeAnchor = iota
+	// AnchorFileEnd is "-HH:MM:SS": that long before the end of the file
+	AnchorFileEnd
+	// AnchorStart is "+HH:MM:SS": that long after a reference start
+	AnchorStart
+)
+
+// TimeSpec is a trim timestamp that may be relative to the file end or to a start
+type TimeSpec struct {
+	Anchor TimeAnchor
+	Offset Timestamp
+}
+
+// ParseTimeSpec parses HH:MM:SS, -HH:MM:SS (before the end of the file) or
+// +HH:MM:SS (after a reference start)
+func ParseTimeSpec(s string) (TimeSpec, error) {
+	anchor := AnchorAbsolute
+	switch {
+	case strings.HasPrefix(s, "-"):
+		anchor = AnchorFileEnd
+	case strings.HasPrefix(s, "+"):
+		anchor = AnchorStart
+	}
+	raw := s
+	if anchor != AnchorAbsolute {
+		raw = s[1:]
+	}
+
+	offset, err := ParseTimestamp(raw)
+	if err != nil {
+		return TimeSpec{}, fmt.Errorf("invalid timestamp format %q: expected HH:MM:SS, -HH:MM:SS or +HH:MM:SS", s)
+	}
+	return TimeSpec{Anchor: anchor, Offset: offset}, nil
+}
+
+// IsRelative returns true for -HH:MM:SS and +HH:MM:SS specs
+func (t TimeSpec) IsRelative() bool {
+	return t.Anchor != AnchorAbsolute
+}
+
+// NeedsDuration returns true if resolving the spec requires the file duration
+func (t TimeSpec) NeedsDuration() bool {
+	return t.Anchor == AnchorFileEnd
+}
+
+// Resolve returns the absolute timestamp. ref is the start a +HH:MM:SS spec
+// counts from; duration is the file length and is only used by -HH:MM:SS.
+func (t TimeSpec) Resolve(ref Timestamp, duration time.Duration) (Timestamp, error) {
+	switch t.Anchor {
+	case AnchorFileEnd:
+		total := int(duration / time.Second)
+		if total <= 0 {
+			return Timestamp{}, fmt.Errorf("%s needs the file duration, which is unknown", t)
+		}
+		if t.Offset.TotalSeconds() > total {
+			return Timestamp{}, fmt.Errorf("%s is before the start of a %s file", t, TimestampFromSeconds(total))
+		}
+		return TimestampFromSeconds(total - t.Offset.TotalSeconds()), nil
+	case AnchorStart:
+		return TimestampFromSeconds(ref.TotalSeconds() + t.Offset.TotalSeconds()), nil
+	default:
+		return t.Offset, nil
+	}
+}
+
+// FitToDuration keeps a trim range inside a file of the given length. An end
+// past the end of the file, as when a long service outlasts its recording,
+// is moved to the end and clamped is true. A start at or past the end is an
+// error. An unknown (zero) duration changes nothing.
+func FitToDuration(start, end Timestamp, duration time.Duration) (fitted Timestamp, clamped bool, err error) {
+	total := int(duration / time.Second)
+	if total <= 0 {
+		return end, false, nil
+	}
+	if start.TotalSeconds() >= total {
+		return Timestamp{}, false, fmt.Errorf("start %s is past the end of the %s recording", start, TimestampFromSeconds(total))
+	}
+	if end.TotalSeconds() > total {
+		return TimestampFromSeconds(total), true, nil
+	}
+	return end, false, nil
+}
+
+// String returns the spec as it was written
+func (t TimeSpec) String() string {
+	switch t.Anchor {
+	case AnchorFileEnd:
+		return "-" + t.Offset.String()
+	case AnchorStart:
+		return "+" + t.Offset.String()
+	default:
+		return t.Offset.String()
+	}
+}
+
+// TimestampFromSeconds converts a number of seconds to a Timestamp
+func TimestampFromSeconds(seconds int) Timestamp {
+	if seconds < 0 {
+		seconds = 0
+	}
+	return Timestamp{
+		Hours:   seconds / 3600,
+		Minutes: seconds % 3600 / 60,
+		Seconds: seconds % 60,
+	}
+}
+
+// DurationProber reads the length of a media file
+type DurationProber interface {
+	Duration(ctx context.Context, path string) (time.Duration, error)
+}
@@ -0,0 +1,128 @@
+package video
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeSpec(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantAnchor TimeAnchor
+		wantOffset Timestamp
+		wantErr    bool
+	}{
+		{input: "01:30:00", wantAnchor: AnchorAbsolute, wantOffset: Timestamp{Hours: 1, Minutes: 30}},
+		{input: "-00:05:00", wantAnchor: AnchorFileEnd, wantOffset: Timestamp{Minutes: 5}},
+		{input: "+00:02:00", wantAnchor: AnchorStart, wantOffset: Timestamp{Minutes: 2}},
+		{input: "-5:00", wantErr: true},
+		{input: "--00:05:00", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTimeSpec(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTimeSpec(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeSpec(%q) error = %v", tt.input, err)
+			}
+			if got.Anchor != tt.wantAnchor || got.Offset != tt.wantOffset {
+				t.Errorf("ParseTimeSpec(%q) = %+v", tt.input, got)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %q, want %q", got.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestTimeSpec_Resolve(t *testing.T) {
+	duration := time.Hour + 50*time.Minute + 500*time.Millisecond
+	ref := Timestamp{Minutes: 10, Seconds: 30}
+
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr string
+	}{
+		{spec: "01:00:00", want: "01:00:00"},
+		{spec: "-00:05:00", want: "01:45:00"},
+		{spec: "+00:02:00", want: "00:12:30"},
+		{spec: "-02:00:00", wantErr: "before the start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := ParseTimeSpec(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := spec.Resolve(ref, duration)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Resolve() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeSpec_ResolveFromEndNeedsDuration(t *testing.T) {
+	spec, _ := ParseTimeSpec("-00:05:00")
+	if !spec.NeedsDuration() {
+		t.Fatal("expected -HH:MM:SS to need the duration")
+	}
+	if _, err := spec.Resolve(Timestamp{}, 0); err == nil {
+		t.Error("expected an error without a duration")
+	}
+}
+
+func TestFitToDuration(t *testing.T) {
+	recording := 100 * time.Minute
+	tests := []struct {
+		name        string
+		start, end  Timestamp
+		duration    time.Duration
+		want        Timestamp
+		wantClamped bool
+		wantErr     string
+	}{
+		{"inside the recording", Timestamp{Minutes: 5}, Timestamp{Hours: 1, Minutes: 30}, recording, Timestamp{Hours: 1, Minutes: 30}, false, ""},
+		{"ends at the end", Timestamp{Minutes: 5}, Timestamp{Hours: 1, Minutes: 40}, recording, Timestamp{Hours: 1, Minutes: 40}, false, ""},
+		{"ends past the end", Timestamp{Minutes: 5}, Timestamp{Hours: 1, Minutes: 50}, recording, Timestamp{Hours: 1, Minutes: 40}, true, ""},
+		{"starts at the end", Timestamp{Hours: 1, Minutes: 40}, Timestamp{Hours: 1, Minutes: 50}, recording, Timestamp{}, false, "past the end of the 01:40:00 recording"},
+		{"unknown duration", Timestamp{Minutes: 5}, Timestamp{Hours: 3}, 0, Timestamp{Hours: 3}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, clamped, err := FitToDuration(tt.start, tt.end, tt.duration)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FitToDuration() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FitToDuration() error = %v", err)
+			}
+			if got != tt.want || clamped != tt.wantClamped {
+				t.Errorf("FitToDuration() = %s, %v; want %s, %v", got, clamped, tt.want, tt.wantClamped)
+			}
+		})
+	}
+}
@@ -13,6 +13,10 @@ type TrimRequest struct {
 	Start       Timestamp
 	End         Timestamp
 	ServiceDate time.Time
+	AudioTrack  int // Optional: 1-based audio stream to keep; 0 keeps ffmpeg's default selection
+	Tags        MediaTags
+	Watermark   Watermark // Optional: text burned in, which means re-encoding the video
+	Chapters    []Chapter // Optional: chapter marks on the source's timeline
 }
 
 // sourceFilenameRegex matches OBS output format: YYYY-MM-DD HH-MM-SS.mp4
@@ -33,6 +37,12 @@ func NewTrimRequest(sourcePath string, start, end Timestamp) (*TrimRequest, erro
 		return nil, fmt.Errorf("invalid date in filename: %w", err)
 	}
 
+	return NewTrimRequestForDate(sourcePath, serviceDate, start, end)
+}
+
+// NewTrimRequestForDate creates a TrimRequest for a given service date, e.g.
+// from --date, so the source can have any filename
+func NewTrimRequestForDate(sourcePath string, serviceDate time.Time, start, end Timestamp) (*TrimRequest, error) {
 	req := &TrimRequest{
 		SourcePath:  sourcePath,
 		Start:       start,
@@ -182,3 +182,21 @@ func TestTrimRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTrimRequestForDate(t *testing.T) {
+	start, _ := ParseTimestamp("00:05:30")
+	end, _ := ParseTimestamp("01:45:00")
+	date := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+
+	req, err := NewTrimRequestForDate("/videos/capture.mp4", date, start, end)
+	if err != nil {
+		t.Fatalf("NewTrimRequestForDate() error = %v", err)
+	}
+	if req.OutputFilename() != "2025-12-28.mp4" {
+		t.Errorf("OutputFilename() = %q, want 2025-12-28.mp4", req.OutputFilename())
+	}
+
+	if _, err := NewTrimRequestForDate("/videos/capture.mp4", date, end, start); err == nil {
+		t.Error("expected an error for end before start")
+	}
+}
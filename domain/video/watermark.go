@@ -0,0 +1,130 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultWatermarkText shows the service date, e.g. "December 28, 2025"
+const DefaultWatermarkText = "{date}"
+
+// DefaultWatermarkFontSize is the text height in pixels when none is configured
+const DefaultWatermarkFontSize = 36
+
+// Where the watermark is drawn
+const (
+	WatermarkTopLeft      = "top-left"
+	WatermarkTopRight     = "top-right"
+	WatermarkBottomLeft   = "bottom-left"
+	WatermarkBottomRight  = "bottom-right" // Default
+	WatermarkBottomCenter = "bottom-center"
+)
+
+var watermarkPositions = []string{WatermarkTopLeft, WatermarkTopRight, WatermarkBottomLeft, WatermarkBottomRight, WatermarkBottomCenter}
+
+// watermarkVariables lists the placeholders a watermark template may use
+var watermarkVariables = []string{"date", "iso_date", "title", "scripture"}
+
+var watermarkPlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// Watermark is text burned into the trimmed video. The zero value draws nothing.
+type Watermark struct {
+	Text     string // Rendered text
+	Position string // One of the Watermark* positions
+	FontFile string // Optional TrueType font; ffmpeg's default font otherwise
+	FontSize int
+}
+
+// WatermarkStyle is a watermark before the service is known. The zero value
+// draws nothing.
+type WatermarkStyle struct {
+	Template *WatermarkTemplate
+	Position string
+	FontFile string
+	FontSize int
+}
+
+// For renders the watermark for a service
+func (s WatermarkStyle) For(serviceDate time.Time, tags MediaTags) Watermark {
+	if s.Template == nil {
+		return Watermark{}
+	}
+	return Watermark{
+		Text:     s.Template.Render(serviceDate, tags),
+		Position: s.Position,
+		FontFile: s.FontFile,
+		FontSize: s.FontSize,
+	}
+}
+
+// IsZero reports whether there is nothing to draw
+func (w Watermark) IsZero() bool {
+	return strings.TrimSpace(w.Text) == ""
+}
+
+// WatermarkPreviewer renders one frame of a source with a watermark drawn,
+// so its placement can be checked before a full trim
+type WatermarkPreviewer interface {
+	PreviewWatermark(ctx context.Context, sourcePath string, at Timestamp, w Watermark, outputPath string) error
+}
+
+// ParseWatermarkPosition validates a position, defaulting to bottom-right
+func ParseWatermarkPosition(s string) (string, error) {
+	if s == "" {
+		return WatermarkBottomRight, nil
+	}
+	for _, p := range watermarkPositions {
+		if s == p {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown watermark position %q (must be %s)", s, strings.Join(watermarkPositions, ", "))
+}
+
+// WatermarkTemplate is validated watermark text with {variable} placeholders
+type WatermarkTemplate struct {
+	raw string
+}
+
+// ParseWatermarkTemplate validates a watermark template. An empty string
+// yields the default, the service date.
+func ParseWatermarkTemplate(s string) (*WatermarkTemplate, error) {
+	if strings.TrimSpace(s) == "" {
+		s = DefaultWatermarkText
+	}
+	for _, m := range watermarkPlaceholder.FindAllStringSubmatch(s, -1) {
+		if !isWatermarkVariable(m[1]) {
+			return nil, fmt.Errorf("unknown watermark variable {%s} (available: {%s})", m[1], strings.Join(watermarkVariables, "}, {"))
+		}
+	}
+	if rest := watermarkPlaceholder.ReplaceAllString(s, ""); strings.ContainsAny(rest, "{}") {
+		return nil, fmt.Errorf("unmatched brace in watermark text %q", s)
+	}
+	return &WatermarkTemplate{raw: s}, nil
+}
+
+func isWatermarkVariable(name string) bool {
+	for _, v := range watermarkVariables {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Render fills in the service date and sermon tags. A separator left
+// dangling by an empty variable, such as " - " before a missing title, is
+// trimmed from the ends.
+func (t *WatermarkTemplate) Render(serviceDate time.Time, tags MediaTags) string {
+	r := strings.NewReplacer(
+		"{date}", serviceDate.Format("January 2, 2006"),
+		"{iso_date}", serviceDate.Format("2006-01-02"),
+		"{title}", tags.Title,
+		"{scripture}", tags.Scripture,
+	)
+	text := strings.Join(strings.Fields(r.Replace(t.raw)), " ")
+	return strings.Trim(text, " -|·,")
+}
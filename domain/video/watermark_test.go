@@ -0,0 +1,59 @@
+package video
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWatermarkPosition(t *testing.T) {
+	if got, err := ParseWatermarkPosition(""); err != nil || got != WatermarkBottomRight {
+		t.Errorf("expected bottom-right default, got %q, %v", got, err)
+	}
+	if got, err := ParseWatermarkPosition("top-left"); err != nil || got != WatermarkTopLeft {
+		t.Errorf("expected top-left, got %q, %v", got, err)
+	}
+	if _, err := ParseWatermarkPosition("middle"); err == nil || !strings.Contains(err.Error(), "unknown watermark position") {
+		t.Errorf("expected unknown position error, got %v", err)
+	}
+}
+
+func TestWatermarkTemplate_Render(t *testing.T) {
+	date := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		template string
+		tags     MediaTags
+		want     string
+	}{
+		{name: "default is the date", template: "", want: "December 28, 2025"},
+		{name: "iso date", template: "Service {iso_date}", want: "Service 2025-12-28"},
+		{
+			name:     "title and scripture",
+			template: "{date} - {title} ({scripture})",
+			tags:     MediaTags{Title: "The Good Shepherd", Scripture: "John 10:11-16"},
+			want:     "December 28, 2025 - The Good Shepherd (John 10:11-16)",
+		},
+		{name: "missing title drops the separator", template: "{date} - {title}", want: "December 28, 2025"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseWatermarkTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseWatermarkTemplate failed: %v", err)
+			}
+			if got := tmpl.Render(date, tt.tags); got != tt.want {
+				t.Errorf("Render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWatermarkTemplate_Invalid(t *testing.T) {
+	if _, err := ParseWatermarkTemplate("{minister}"); err == nil || !strings.Contains(err.Error(), "unknown watermark variable {minister}") {
+		t.Errorf("expected unknown variable error, got %v", err)
+	}
+	if _, err := ParseWatermarkTemplate("{date"); err == nil || !strings.Contains(err.Error(), "unmatched brace") {
+		t.Errorf("expected unmatched brace error, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+Feature: Remote control API
+  As an A/V volunteer at the sound desk
+  I want to start processing and resend emails from another device
+  So that I don't need to sit at the recording computer
+
+  Scenario: Requests without the token are refused
+    Given the remote control API is running with token "s3cret"
+    When I GET "/api/v1/runs" with token "wrong"
+    Then the API should respond with status 401
+    And the API response should include "missing or wrong API token"
+
+  Scenario: Start a process run and follow it to the end
+    Given the remote control API is running with token "s3cret"
+    When I POST "/api/v1/runs" with token "s3cret" and body:
+      """
+      {"start": "00:05:30", "end": "01:45:00", "minister": "smith", "recipients": ["jane"]}
+      """
+    Then the API should respond with status 202
+    And the API response should include "run-1"
+    And remote run "run-1" should finish as "succeeded"
+    And the remote command should be "process --non-interactive --start=00:05:30 --end=01:45:00 --minister=smith --recipient=jane"
+    When I GET "/api/v1/runs/run-1" with token "s3cret"
+    Then the API should respond with status 200
+    And the API response should include "succeeded"
+    And the API response should include "Done"
+
+  Scenario: A failed run reports its error
+    Given the remote control API is running with token "s3cret"
+    And remote commands fail with "process failed: exit status 17"
+    When I POST "/api/v1/runs" with token "s3cret" and body:
+      """
+      {"recipients": ["jane"]}
+      """
+    Then remote run "run-1" should finish as "failed"
+    When I GET "/api/v1/runs/run-1" with token "s3cret"
+    Then the API response should include "exit status 17"
+
+  Scenario: Only one run goes at a time
+    Given the remote control API is running with token "s3cret"
+    And remote commands keep running
+    When I POST "/api/v1/runs" with token "s3cret" and body:
+      """
+      {"recipients": ["jane"]}
+      """
+    And I POST "/api/v1/runs" with token "s3cret" and body:
+      """
+      {"recipients": ["john"]}
+      """
+    Then the API should respond with status 409
+    And the API response should include "another run is in progress: run-1"
+
+  Scenario: A run needs recipients
+    Given the remote control API is running with token "s3cret"
+    When I POST "/api/v1/runs" with token "s3cret" and body:
+      """
+      {"start": "00:05:30"}
+      """
+    Then the API should respond with status 400
+    And the API response should include "recipients are required"
+    And no remote command should run
+
+  Scenario: List history for a date range
+    Given the history contains services:
+      | date       | minister  |
+      | 2025-12-21 | Pr. Jones |
+      | 2025-12-28 | Pr. Smith |
+    And the remote control API is running with token "s3cret"
+    When I GET "/api/v1/history?from=2025-12-25" with token "s3cret"
+    Then the API should respond with status 200
+    And the API response should include "Pr. Smith"
+    And the API response should not include "Pr. Jones"
+
+  Scenario: Resend a processed service
+    Given the history contains services:
+      | date       | minister  | audio_url                                |
+      | 2025-12-28 | Pr. Smith | https://drive.google.com/file/d/abc/view |
+    And the remote control API is running with token "s3cret"
+    When I POST "/api/v1/resends" with token "s3cret" and body:
+      """
+      {"date": "2025-12-28", "recipients": ["jane"]}
+      """
+    Then the API should respond with status 202
+    And remote run "run-1" should finish as "succeeded"
+    And the remote command should be "send-email --date=2025-12-28 --minister=Pr. Smith --to=jane --audio-url=https://drive.google.com/file/d/abc/view"
+
+  Scenario: Resending a service that was never processed
+    Given the history contains services:
+      | date       | minister  | audio_url                                |
+      | 2025-12-28 | Pr. Smith | https://drive.google.com/file/d/abc/view |
+    And the remote control API is running with token "s3cret"
+    When I POST "/api/v1/resends" with token "s3cret" and body:
+      """
+      {"date": "2025-12-21", "recipients": ["jane"]}
+      """
+    Then the API should respond with status 404
+    And the API response should include "no processed service to resend for 2025-12-21"
+    And no remote command should run
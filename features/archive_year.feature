@@ -0,0 +1,53 @@
+Feature: Year-End Archive
+  As a media coordinator closing out the year
+  I want a year's services moved into one shared folder with an index
+  So that leadership can find any service from that year in one place
+
+  Background:
+    Given the Services folder to archive holds:
+      | name              | service_date |
+      | 2025-03-02.mp4    |              |
+      | 2025-03-02.mp3    |              |
+      | Easter.mp4        | 2025-04-20   |
+      | 2026-01-04.mp4    |              |
+    And the history contains services:
+      | date       | minister  | title             |
+      | 2025-03-02 | Pr. Smith | The Good Shepherd |
+    And the leadership group "Council" has "jones, brown"
+
+  Scenario: Archive a year into a new folder
+    When I archive the year 2025
+    Then the archive should succeed
+    And the archive output should include "Created folder Archive"
+    And the archive output should include "Created folder Archive/2025"
+    And the folder "Archive/2025" should hold "2025-03-02.mp4, 2025-03-02.mp3, Easter.mp4, 2025 Services Index.html"
+    And the Services folder should still hold "2026-01-04.mp4"
+    And the index should include "<td>Sunday, March 2</td><td>Pr. Smith</td><td>The Good Shepherd</td>"
+    And the index should include ">Easter.mp4</a>"
+    And the folder "Archive/2025" should be shared with "jones@example.com, brown@example.com"
+
+  Scenario: A dry run changes nothing
+    When I preview archiving the year 2025
+    Then the archive should succeed
+    And the archive output should include "Would create folder Archive/2025"
+    And the archive output should include "Would move: Easter.mp4"
+    And the archive output should include "Would share with jones@example.com"
+    And the archive output should include "Index lists 2 services"
+    And the Services folder should still hold "2025-03-02.mp4, 2025-03-02.mp3, Easter.mp4, 2026-01-04.mp4"
+
+  Scenario: Running again refreshes the index
+    Given I archive the year 2025
+    And the Services folder to archive holds:
+      | name           | service_date |
+      | 2025-12-28.mp4 |              |
+    When I archive the year 2025
+    Then the archive should succeed
+    And the archive output should include "Moved: 2025-12-28.mp4"
+    And the archive output should include "3 files were already archived"
+    And the archive output should include "Updated 2025 Services Index.html"
+    And the folder "Archive/2025" should hold "2025-03-02.mp4, 2025-03-02.mp3, Easter.mp4, 2025 Services Index.html, 2025-12-28.mp4"
+    And the index should include "Sunday, December 28"
+
+  Scenario: A year with no services
+    When I archive the year 2019
+    Then the archive should fail with "no 2019 services found"
@@ -45,3 +45,28 @@ Feature: Audio Extraction
     Given a trimmed video at "/test/trimmed/2025-01-15.mp4"
     When I extract audio for service date "2025-01-15"
     Then the audio output file should be "/tmp/test-audio/2025-01-15.mp3"
+
+  Scenario: A trimmed video ignores the configured audio track
+    Given audio.track is 2 in config with trimmed videos in "/test/trimmed"
+    And a trimmed video at "/test/trimmed/2025-12-28.mp4"
+    When I extract audio with the configured track for service date "2025-12-28"
+    Then the MP3 should be read from audio track 0
+
+  Scenario: A trimmed video outside trimmed_directory ignores the configured audio track
+    Given audio.track is 2 in config with trimmed videos in "/test/trimmed"
+    And a trimmed video at "/downloads/2025-12-28.mp4"
+    When I extract audio with the configured track for service date "2025-12-28"
+    Then the MP3 should be read from audio track 0
+
+  Scenario: A raw recording uses the configured audio track
+    Given audio.track is 2 in config with trimmed videos in "/test/trimmed"
+    And a raw recording at "/test/source/2025-12-28 10-00-00.mkv"
+    When I extract audio with the configured track for service date "2025-12-28"
+    Then the MP3 should be read from audio track 2
+
+  Scenario: An explicit audio track applies to a trimmed video
+    Given audio.track is 2 in config with trimmed videos in "/test/trimmed"
+    And a trimmed video at "/test/trimmed/2025-12-28.mp4"
+    And --audio-track 3 is passed
+    When I extract audio with the configured track for service date "2025-12-28"
+    Then the MP3 should be read from audio track 3
@@ -0,0 +1,96 @@
+Feature: Audio Refresh
+  As a media coordinator
+  I want to re-extract a published service's audio and replace it in Drive
+  So that listeners get the better audio from the link they already have
+
+  Background:
+    Given Drive holds "2025-12-28.mp3" with ID "audio-123" for the refresh
+    And Drive holds "2025-12-28.mp4" with ID "video-456" for the refresh
+
+  Scenario: Replace the Drive audio in place
+    Given the trimmed video "2025-12-28.mp4" exists locally for the refresh
+    When I refresh the audio for "2025-12-28" with bitrate "128k"
+    Then the refresh should succeed
+    And the audio should have been re-extracted with bitrate "128k"
+    And Drive file "audio-123" should have been given new content
+    And no Drive file should have been created by the refresh
+    And the refresh output should contain "https://drive.google.com/file/d/audio-123/view?usp=sharing"
+    And no refresh email should be sent
+
+  Scenario: Download the trimmed video from Drive when it is not local
+    When I refresh the audio for "2025-12-28" with bitrate "192k"
+    Then the refresh should succeed
+    And Drive file "video-456" should have been downloaded for the refresh
+    And the refresh output should contain "Trimmed video not found locally"
+    And Drive file "audio-123" should have been given new content
+
+  Scenario: A refresh reads the trimmed video's only audio stream
+    Given audio.track is 2 in config for the refresh
+    And Drive holds "sunday-service.mp4" with ID "video-789" for the refresh
+    When I refresh the audio for "2025-12-28" from "sunday-service.mp4"
+    Then the refresh should succeed
+    And Drive file "video-789" should have been downloaded for the refresh
+    And the refreshed audio should be read from audio track 0
+
+  Scenario: Trimmed video is neither local nor in Drive
+    Given Drive has no "2025-12-28.mp4" for the refresh
+    When I refresh the audio for "2025-12-28" with bitrate "192k"
+    Then the refresh should fail with "2025-12-28.mp4 was not found in Drive"
+
+  Scenario: Audio that was never uploaded is not replaced
+    Given the trimmed video "2025-12-28.mp4" exists locally for the refresh
+    And Drive has no "2025-12-28.mp3" for the refresh
+    When I refresh the audio for "2025-12-28" with bitrate "128k"
+    Then the refresh should fail with "2025-12-28.mp3 is not in Drive yet"
+    And no Drive file should have been created by the refresh
+
+  Scenario: Notify recipients of the refreshed audio
+    Given the trimmed video "2025-12-28.mp4" exists locally for the refresh
+    And the refresh notifies "Jane Doe" at "jane@example.com"
+    When I refresh the audio for "2025-12-28" with bitrate "128k"
+    Then the refresh should succeed
+    And the refresh email should contain "https://drive.google.com/file/d/audio-123/view?usp=sharing"
+    And the refresh email should contain "https://drive.google.com/file/d/video-456/view?usp=sharing"
+    And the refresh email should contain "The audio recording has been updated"
+
+  Scenario: Replacing the audio pins the version it replaces
+    Given the trimmed video "2025-12-28.mp4" exists locally for the refresh
+    And Drive file "audio-123" has revisions "r1, r2, r3"
+    And Drive revision "r1" of "audio-123" is pinned
+    And Drive revision "r2" of "audio-123" is pinned
+    And replaced versions are kept up to 2
+    When I refresh the audio for "2025-12-28" with bitrate "128k"
+    Then the refresh should succeed
+    And Drive revision "r3" of "audio-123" should be pinned
+    And Drive revision "r2" of "audio-123" should be pinned
+    And Drive revision "r1" of "audio-123" should not be pinned
+    And the refresh output should contain "Pinned the current revision r3"
+    And the refresh output should contain "Unpinned revision r1"
+    And Drive file "audio-123" should have been given new content
+
+  Scenario: List the versions of a service's audio
+    Given Drive file "audio-123" has revisions "r1, r2"
+    And Drive revision "r1" of "audio-123" is pinned
+    When I list the Drive revisions of the audio for "2025-12-28"
+    Then the refresh should succeed
+    And the refresh output should contain "Revisions of 2025-12-28.mp3"
+    And the refresh output should contain "(current)"
+
+  Scenario: Roll the audio back to an earlier version
+    Given Drive file "audio-123" has revisions "r1, r2"
+    When I restore the audio for "2025-12-28" to revision "r1"
+    Then the refresh should succeed
+    And Drive file "audio-123" should have been restored from revision "r1"
+    And Drive revision "r2" of "audio-123" should be pinned
+    And the refresh output should contain "Restored: 2025-12-28.mp3"
+    And the refresh output should contain "https://drive.google.com/file/d/audio-123/view?usp=sharing"
+
+  Scenario: Restoring the current version is refused
+    Given Drive file "audio-123" has revisions "r1, r2"
+    When I restore the audio for "2025-12-28" to revision "r2"
+    Then the refresh should fail with "already the current content"
+
+  Scenario: Restoring an unknown version is refused
+    Given Drive file "audio-123" has revisions "r1, r2"
+    When I restore the audio for "2025-12-28" to revision "r9"
+    Then the refresh should fail with "has no revision"
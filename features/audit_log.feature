@@ -0,0 +1,97 @@
+Feature: Audit Log of Destructive Operations
+  As the person responsible for the recordings
+  I want every deletion and sharing change written to a tamper-evident log
+  So that I can account for a recording that goes missing
+
+  Background:
+    Given the Services folder ID is "test-folder-id"
+    And valid Google Drive credentials
+    And an audit log
+
+  Scenario: Drive cleanup deletions are recorded
+    Given there is 500 MB of available storage
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-11-10.mp4   | 1073741824  |
+      | 2025-11-17.mp4   | 1073741824  |
+    When I ensure 1 GB of space is available
+    And I show the audit log
+    Then the audit show should succeed
+    And the audit output should include "drive_delete  file-1  by av@church-pc  via nac-service-media drive cleanup"
+    And the audit output should not include "file-2"
+    And the audit output should include "Audit log intact: 1 events"
+
+  Scenario: Concurrent deletions form one intact chain
+    Given there is 0 MB of available storage
+    And the cleanup concurrency is 3
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-09-07.mp4   | 268435456   |
+      | 2025-09-14.mp4   | 268435456   |
+      | 2025-09-21.mp4   | 268435456   |
+      | 2025-09-28.mp4   | 268435456   |
+    When I ensure 1 GB of space is available
+    And I show the audit log
+    Then the audit show should succeed
+    And the audit output should include "Audit log intact: 4 events"
+
+  Scenario: Failed deletions are recorded with their error
+    Given there is 0 MB of available storage
+    And deleting "2025-10-05.mp4" will fail
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-10-05.mp4   | 536870912   |
+      | 2025-10-12.mp4   | 536870912   |
+      | 2025-10-19.mp4   | 536870912   |
+    When I ensure 1 GB of space is available
+    And I show the audit log
+    Then the audit show should succeed
+    And the audit output should include "failed: unable to delete file: googleapi: Error 500: backend error"
+
+  Scenario: Sharing, trash emptying and local removals are recorded
+    When I share the Drive file "file-9"
+    And I empty the Drive trash
+    And I remove the local file "2025-12-28 10-06-16.mp4"
+    And I show the audit log
+    Then the audit show should succeed
+    And the audit output should include "share         file-9 (anyone:reader)"
+    And the audit output should include "empty_trash"
+    And the audit output should include "local_remove"
+    And the audit output should include "2025-12-28 10-06-16.mp4"
+    And the audit output should include "Audit log intact: 3 events"
+
+  Scenario: Show only one kind of event, or the most recent ones
+    When I share the Drive file "file-8"
+    And I share the Drive file "file-9"
+    And I empty the Drive trash
+    And I show the audit log for action "share"
+    Then the audit output should include "file-8"
+    And the audit output should not include "empty_trash"
+    When I show the last 1 audit event
+    Then the audit output should include "empty_trash"
+    And the audit output should not include "file-8"
+    And the audit output should include "Audit log intact: 3 events"
+
+  Scenario: An edited line is detected
+    When I share the Drive file "file-8"
+    And I share the Drive file "file-9"
+    And audit line 1 is edited to replace "file-8" with "file-7"
+    And I show the audit log
+    Then the audit show should fail with "audit log has been altered: event 1 was edited"
+
+  Scenario: A removed line is detected
+    When I share the Drive file "file-7"
+    And I share the Drive file "file-8"
+    And I share the Drive file "file-9"
+    And audit line 2 is removed
+    And I show the audit log
+    Then the audit show should fail with "event 2 does not follow event 1 (removed or reordered)"
+
+  Scenario: Nothing recorded yet
+    When I show the audit log
+    Then the audit show should succeed
+    And the audit output should include "No audit events recorded"
+
+  Scenario: Unknown action filter
+    When I show the audit log for action "upload"
+    Then the audit show should fail with "unknown action"
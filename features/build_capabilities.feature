@@ -0,0 +1,43 @@
+Feature: Build Capabilities
+  As an operator running a binary built without optional features
+  I want to see what the build can do and clear messages when it cannot
+  So that a missing detection build does not fail confusingly
+
+  Scenario: Capabilities list what this build lacks
+    When I list the build capabilities
+    Then the capabilities should include "start detection      no (build with -tags=detection and install OpenCV/GoCV)"
+    And the capabilities should include "failure simulation   no (build with -tags=devtools)"
+
+  Scenario: Detection is used when enabled and compiled in
+    Given detection is enabled in config
+    And the build includes detection
+    When I check whether "--start" can be detected
+    Then detection should be usable
+
+  Scenario: A build without detection requires --start
+    Given detection is enabled in config
+    When I check whether "--start" can be detected
+    Then the timestamp should be required with "--start flag is required: auto-detection is not available in this build (built without -tags=detection)"
+    And the error should mark detection as unavailable
+
+  Scenario: A build without detection rejects a relative --start
+    Given detection is enabled in config
+    When I check whether "--start" can be detected from "+00:02:00"
+    Then the timestamp should be required with "--start +00:02:00 is relative to the detected start, but auto-detection is not available in this build"
+
+  Scenario: Detection disabled in config still asks for --end
+    When I check whether "--end" can be detected
+    Then the timestamp should be required with "--end flag is required (auto-detection is disabled in config)"
+
+  Scenario: Silence end detection works without the detection build
+    Given detection is enabled in config
+    And the end is detected by "silence"
+    When I check whether the end can be detected
+    Then detection should be usable
+
+  Scenario: Cross end detection needs the detection build
+    Given detection is enabled in config
+    And the end is detected by "cross"
+    When I check whether the end can be detected
+    Then the timestamp should be required with "--end flag is required: auto-detection is not available in this build"
+    And the error should mark detection as unavailable
@@ -0,0 +1,61 @@
+Feature: Cleanup Prefers Archived Recordings
+  As a user
+  I want cleanup to delete recordings that are safe on the mirror first
+  So that the only copy of a service is the last thing removed from Drive
+
+  Background:
+    Given the Services folder ID is "test-folder-id"
+    And valid Google Drive credentials
+    And there is 500 MB of available storage
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-11-10.mp4   | 1073741824  |
+      | 2025-11-17.mp4   | 1073741824  |
+      | 2025-11-24.mp4   | 1073741824  |
+      | 2025-12-01.mp4   | 1073741824  |
+
+  Scenario: Files already on the mirror are deleted before older unarchived ones
+    Given the mirror holds "2025-11-24.mp4"
+    And the mirror holds "2025-12-01.mp4"
+    When I ensure 2 GB of space is available
+    Then the cleanup should succeed
+    And "2025-11-24.mp4" should be deleted as archived
+    And "2025-12-01.mp4" should be deleted as archived
+    And "2025-11-10.mp4" should not be deleted
+    And the cleanup result should show 2 files deleted
+
+  Scenario: Unarchived files are deleted oldest first once archived ones run out
+    Given the mirror holds "2025-12-01.mp4"
+    When I ensure 2 GB of space is available
+    Then "2025-12-01.mp4" should be deleted as archived
+    And "2025-11-10.mp4" should be deleted
+    And "2025-11-17.mp4" should not be deleted
+    And the cleanup result should show 2 files deleted
+
+  Scenario: A mirror copy of a different size does not count as archived
+    Given the mirror holds "2025-11-24.mp4" with a different size
+    When I ensure 1 GB of space is available
+    Then "2025-11-10.mp4" should be deleted
+    And "2025-11-24.mp4" should not be deleted
+
+  Scenario: A mirror copy without its checksum file does not count as archived
+    Given the mirror holds "2025-11-24.mp4" without its checksum file
+    When I ensure 1 GB of space is available
+    Then "2025-11-10.mp4" should be deleted
+    And "2025-11-24.mp4" should not be deleted
+
+  Scenario: An unreachable mirror falls back to oldest first
+    Given the mirror holds "2025-11-24.mp4"
+    And the mirror cannot be reached
+    When I ensure 1 GB of space is available
+    Then the cleanup should succeed
+    And "2025-11-10.mp4" should be deleted
+    And "2025-11-24.mp4" should not be deleted
+
+  Scenario: Cleanup command notes files that are still on the mirror
+    Given the mirror holds "2025-12-01.mp4"
+    When I run drive cleanup with "--ensure-space" "2GB"
+    Then the cleanup command should succeed
+    And the cleanup output should include "Removed: 2025-12-01.mp4 (1.0 GB, still on the mirror)"
+    And the cleanup output should include "Removed: 2025-11-10.mp4 (1.0 GB)"
+    And the cleanup output should include "Freed 2.0 GB from 2 files"
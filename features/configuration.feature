@@ -14,3 +14,273 @@ Feature: Configuration Loading
     Given no configuration file exists at "config/nonexistent.yaml"
     When I attempt to load the configuration
     Then I should receive an error about missing configuration
+
+  Scenario: A single source directory is still supported
+    Given a configuration file containing:
+      """
+      paths:
+        source_directory: /videos/obs
+      """
+    When I load the configuration
+    Then the source directories should be "/videos/obs"
+
+  Scenario: Load several source directories in priority order
+    Given a configuration file containing:
+      """
+      paths:
+        source_directories:
+          - /videos/obs
+          - /videos/capture-card
+      """
+    When I load the configuration
+    Then the source directories should be "/videos/obs, /videos/capture-card"
+
+  Scenario: Reject both source directory keys
+    Given a configuration file containing:
+      """
+      paths:
+        source_directory: /videos/obs
+        source_directories:
+          - /videos/capture-card
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "set source_directory or source_directories, not both"
+
+  Scenario: Reject a misspelled bitrate with the form to use
+    Given a configuration file containing:
+      """
+      audio:
+        bitrate: 192kbps
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid audio.bitrate: '192kbps' is not a bitrate, use '192k'"
+
+  Scenario: Load a custom email subject template
+    Given a configuration file with email subject "{church}: {service_type} on {date}"
+    When I load the configuration
+    Then the email subject should be "{church}: {service_type} on {date}"
+
+  Scenario: Reject an email subject with an unknown variable
+    Given a configuration file with email subject "{church}: {pastor}"
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid email.subject: unknown subject variable {pastor}"
+
+  Scenario: Load S3 storage settings
+    Given a configuration file containing:
+      """
+      google:
+        services_folder_id: "drive-folder"
+      storage:
+        provider: s3
+        s3:
+          endpoint: https://minio.example.org
+          bucket: media
+          access_key_id: minio
+          secret_access_key: minio-secret
+      """
+    When I load the configuration
+    Then the storage provider should be S3 with links valid for 168 hours
+
+  Scenario: Reject S3 storage without a bucket
+    Given a configuration file containing:
+      """
+      storage:
+        provider: s3
+        s3:
+          endpoint: https://minio.example.org
+          access_key_id: minio
+          secret_access_key: minio-secret
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid storage.s3: bucket is required"
+
+  Scenario: Reject presigned links longer than a week
+    Given a configuration file containing:
+      """
+      storage:
+        provider: s3
+        s3:
+          endpoint: https://minio.example.org
+          bucket: media
+          access_key_id: minio
+          secret_access_key: minio-secret
+          link_expiry_hours: 200
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "link_expiry_hours 200 must be between 1 and 168"
+
+  Scenario: Reject an unknown storage provider
+    Given a configuration file containing:
+      """
+      storage:
+        provider: dropbox
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid storage.provider"
+
+  Scenario: Reject an unknown Drive scope mode
+    Given a configuration file containing:
+      """
+      google:
+        scope_mode: readonly
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid google.scope_mode"
+
+  Scenario: Reject a negative pre-share scan timeout
+    Given a configuration file containing:
+      """
+      scan:
+        command: [clamscan, --no-summary]
+        timeout_seconds: -5
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid scan.timeout_seconds"
+
+  Scenario: Reject a negative number of upload chunk retries
+    Given a configuration file containing:
+      """
+      google:
+        upload_chunk_retries: -1
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid google.upload_chunk_retries"
+
+  Scenario: Reject a negative number of kept revisions
+    Given a configuration file containing:
+      """
+      google:
+        keep_revisions: -2
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid google.keep_revisions"
+
+  Scenario: Reject an unknown upload budget action
+    Given a configuration file containing:
+      """
+      upload_budget:
+        weekly_gb: 5
+        on_exceed: block
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid upload_budget.on_exceed"
+
+  Scenario: Reject a malformed video aspect ratio
+    Given a configuration file containing:
+      """
+      video:
+        width: 1920
+        height: 1080
+        aspect: widescreen
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "must look like 16:9"
+
+  Scenario: Reject an unknown watermark position
+    Given a configuration file containing:
+      """
+      video:
+        watermark:
+          enabled: true
+          position: middle
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid video.watermark: unknown watermark position"
+
+  Scenario: Reject an unknown watermark variable
+    Given a configuration file containing:
+      """
+      video:
+        watermark:
+          text: "{minister}"
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "unknown watermark variable {minister}"
+
+  Scenario: Reject a detection early exit score above 1
+    Given a configuration file containing:
+      """
+      detection:
+        thresholds:
+          early_exit_score: 1.5
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid detection.thresholds.early_exit_score"
+
+  Scenario: Emails copy the sender unless turned off
+    Given a configuration file containing:
+      """
+      email:
+        from_address: avteam@example.com
+        bcc_sender: false
+      """
+    When I load the configuration
+    Then the sender should not be copied on emails
+
+  Scenario: Archive trimmed videos and audio by default
+    Given a configuration file containing:
+      """
+      archive:
+        directory: /backup/services
+        keep_weeks: 52
+      """
+    When I load the configuration
+    Then old "trimmed" files should be archived before cleanup
+    And old "audio" files should be archived before cleanup
+    And old "source" files should not be archived before cleanup
+
+  Scenario: Reject an unknown archive kind
+    Given a configuration file containing:
+      """
+      archive:
+        directory: /backup/services
+        include: [trimmed, photos]
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid archive: unknown include"
+
+  Scenario: Reject a negative clock drift limit
+    Given a configuration file containing:
+      """
+      locale:
+        max_clock_drift_hours: -1
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid locale.max_clock_drift_hours"
+
+  Scenario: Reject a livestream URL that is not a web link
+    Given a configuration file containing:
+      """
+      email:
+        livestream_url: youtube.com/@nacchurch
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid email.livestream_url"
+
+  Scenario: The first of several audio bitrates is the main MP3
+    Given a configuration file containing:
+      """
+      audio:
+        bitrates: [192k, 64k]
+      """
+    When I load the configuration
+    Then the main audio bitrate should be "192k" with extras "64k"
+
+  Scenario: Reject a repeated audio bitrate
+    Given a configuration file containing:
+      """
+      audio:
+        bitrates: [192k, 64k, 64K]
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "invalid audio.bitrates"
+
+  Scenario: Reject a main audio bitrate that is not listed first
+    Given a configuration file containing:
+      """
+      audio:
+        bitrate: 128k
+        bitrates: [192k, 64k]
+      """
+    When I attempt to load the configuration
+    Then I should receive a configuration error containing "remove audio.bitrate"
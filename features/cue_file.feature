@@ -0,0 +1,77 @@
+Feature: Sound Desk Cue Files
+  As an A/V operator
+  I want process to read the markers the sound desk writes alongside a recording
+  So that the trim points and chapters come from the desk instead of detection
+
+  Background:
+    Given the process config has paths:
+      | source_directory  | /test/source    |
+      | trimmed_directory | /test/trimmed   |
+      | audio_directory   | /test/audio     |
+    And the process config has services folder "folder123"
+    And the process config has ministers:
+      | key   | name           |
+      | smith | Pr. John Smith |
+    And the process config has recipients:
+      | key  | name     | address          |
+      | jane | Jane Doe | jane@example.com |
+    And the process config has senders:
+      | key    | name     | default |
+      | avteam | A/V Team | yes     |
+
+  Scenario: A cue file next to the recording supplies the start, end and chapters
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a cue file exists at "/test/source/2025-12-28 10-06-16.csv" with:
+      """
+      marker,time,label
+      start,00:05:30,
+      chapter,00:05:30,Welcome
+      chapter,00:40:00,Sermon
+      end,01:45:00,
+      """
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "Using cue file:"
+    And the video should be trimmed from "00:05:30" to "01:45:00"
+    And the trimmed video should have chapters "Welcome@00:05:30, Sermon@00:40:00"
+    And the output should include "Chapters: Welcome (00:05:30), Sermon (00:40:00)"
+
+  Scenario: Timestamps given by hand win over the cue file
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a cue file exists at "/test/source/markers.json" with:
+      """
+      {"markers": [
+        {"marker": "start", "time": "00:05:30"},
+        {"marker": "end", "time": "01:45:00"}
+      ]}
+      """
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --cues      | /test/source/markers.json            |
+      | --end       | 01:50:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "End:   01:50:00 (--end; the cue file has 01:45:00)"
+    And the video should be trimmed from "00:05:30" to "01:50:00"
+    And the trimmed video should have no chapters
+
+  Scenario: A malformed cue file stops before anything is trimmed
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a cue file exists at "/test/source/2025-12-28 10-06-16.csv" with:
+      """
+      marker,time
+      start,00:05:30
+      finish,01:45:00
+      """
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --recipient | jane                                 |
+    Then the process should fail with error "invalid cue file: line 3: unknown marker"
+    And the process should fail with error "use start, end or chapter"
+    And the video should not be trimmed
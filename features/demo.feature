@@ -0,0 +1,35 @@
+Feature: Demo mode
+  As a volunteer trainer
+  I want to run the whole workflow on a sample recording
+  So that new volunteers can practice, and installs can be checked, without touching real accounts
+
+  Background:
+    Given a demo folder
+    And today is "2026-01-01" for the demo
+
+  Scenario: The demo processes the sample recording against simulated Google services
+    When I run the demo
+    Then the demo should succeed
+    And the sample recording should have been rendered
+    And the demo output should include "Simulated Drive folder demo-services now holds:"
+    And the demo output should include "2025-12-28.mp4"
+    And the demo output should include "2025-12-28.mp3"
+    And the demo output should include "To:      Demo Congregation <congregation@demo-church.example.com>"
+    And the demo output should include "Nothing was uploaded and no email was sent"
+    And the email preview should include "Pr. Smith"
+    And the email preview should include "https://drive.google.com/file/d/"
+
+  Scenario: The sample is filed as last Sunday's recording and trimmed
+    When I run the demo
+    Then the demo should trim "2025-12-28 10-00-00.mp4" from "00:00:15" to "00:01:45"
+
+  Scenario: The sample recording is reused on later runs
+    Given the demo has run before
+    When I run the demo
+    Then the demo should succeed
+    And the sample recording should not have been rendered again
+
+  Scenario: The demo reports when the sample cannot be rendered
+    Given ffmpeg cannot render the sample
+    When I run the demo
+    Then the demo should fail with "sample recording"
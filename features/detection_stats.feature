@@ -0,0 +1,76 @@
+Feature: Detection Score Drift
+  As a media coordinator
+  I want to see how start detection match scores trend week to week
+  So that I re-capture templates before detection silently fails
+
+  Scenario: Healthy scores are listed oldest first
+    Given the history contains services:
+      | date       | confidence | camera_angle |
+      | 2025-12-21 | 0.94       | wide         |
+      | 2025-12-07 | 0.95       | wide         |
+      | 2025-12-14 | 0.93       | close        |
+    When I show detection stats with threshold 0.90 over 3 weeks
+    Then the detection stats should include "alert below 90% for 3 services in a row"
+    And the detection stats should include "2025-12-07   95%  wide"
+    And the detection stats should include "2025-12-21   94%  wide"
+    And the detection stats should include "Scores are healthy"
+    And the detection stats should not include "Warning"
+
+  Scenario: Early exits are marked and counted
+    Given the history contains services:
+      | date       | confidence | early_exit |
+      | 2025-12-07 | 0.96       | yes        |
+      | 2025-12-14 | 0.93       |            |
+      | 2025-12-21 | 0.97       | yes        |
+    When I show detection stats with threshold 0.90 over 3 weeks
+    Then the detection stats should include "2025-12-07   96%  (early exit)"
+    And the detection stats should not include "2025-12-14   93%  (early exit)"
+    And the detection stats should include "Early exit: 2 of 3 detections stopped on a confident frame bracket"
+
+  Scenario: Services without a detected start are skipped
+    Given the history contains services:
+      | date       | confidence |
+      | 2025-12-14 | 0.93       |
+      | 2025-12-21 |            |
+    When I show detection stats with threshold 0.90 over 3 weeks
+    Then the detection stats should include "2025-12-14"
+    And the detection stats should not include "2025-12-21"
+
+  Scenario: Declining scores raise an alert
+    Given the history contains services:
+      | date       | confidence |
+      | 2025-11-16 | 0.96       |
+      | 2025-11-23 | 0.95       |
+      | 2025-11-30 | 0.94       |
+      | 2025-12-07 | 0.89       |
+      | 2025-12-14 | 0.88       |
+      | 2025-12-21 | 0.87       |
+    When I show detection stats with threshold 0.90 over 3 weeks
+    Then the detection stats should include "2025-12-21   87%  (low)"
+    And the detection stats should include "Trend: last 3 average 88%, previous 3 average 95% (-7 points)"
+    And the detection stats should include "Warning: scores have been below 90% for 3 services in a row"
+
+  Scenario: A recent recovery clears the alert
+    Given the history contains services:
+      | date       | confidence |
+      | 2025-12-07 | 0.89       |
+      | 2025-12-14 | 0.88       |
+      | 2025-12-21 | 0.87       |
+      | 2025-12-28 | 0.95       |
+    When I show detection stats with threshold 0.90 over 3 weeks
+    Then the detection stats should include "Scores are healthy"
+
+  Scenario: Only the most recent services are listed
+    Given the history contains services:
+      | date       | confidence |
+      | 2025-12-07 | 0.95       |
+      | 2025-12-14 | 0.94       |
+      | 2025-12-21 | 0.93       |
+    When I show the last 2 detection stats with threshold 0.90 over 3 weeks
+    Then the detection stats should include "2025-12-21"
+    And the detection stats should not include "2025-12-07"
+
+  Scenario: No scores recorded yet
+    Given a history store
+    When I show detection stats with threshold 0.90 over 3 weeks
+    Then the detection stats should include "No detection scores recorded yet"
@@ -0,0 +1,188 @@
+Feature: Doctor
+  As a user on a network that requires a proxy
+  I want a command that checks Google API connectivity
+  So that proxy problems show up before a service is processed
+
+  Scenario: Google APIs are reached through the configured proxy
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "Network"
+    And the doctor output should include "(network.proxy_url) (HTTP 404)"
+    And the doctor output should include "www.googleapis.test: reachable through proxy"
+    And the proxy should have been asked for "oauth2.googleapis.test"
+    And the proxy should have been asked for "www.googleapis.test"
+    And the doctor output should include "All checks passed."
+
+  Scenario: The proxy environment variable is honored
+    Given an HTTP proxy is running
+    And the HTTP_PROXY environment variable points at the proxy
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "(environment) (HTTP 404)"
+    And the proxy should have been asked for "www.googleapis.test"
+
+  Scenario: An unreachable proxy fails the network check
+    Given the config proxy URL points at a closed port
+    When I run doctor
+    Then doctor should fail with "2 doctor check(s) failed"
+    And the doctor output should include "FAIL  oauth2.googleapis.test: cannot connect through proxy"
+
+  Scenario: A missing CA bundle fails the network check
+    Given the config CA bundle is "missing-ca.pem"
+    When I run doctor
+    Then doctor should fail with "1 doctor check(s) failed"
+    And the doctor output should include "unable to read CA bundle"
+
+  Scenario: Every source directory is checked in priority order
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 2 recordings
+    And the doctor source directory "capture" holds 1 recording
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "Source directories"
+    And the doctor output should include "ok    1. "
+    And the doctor output should include "/obs: 2 recording(s)"
+    And the doctor output should include "ok    2. "
+    And the doctor output should include "/capture: 1 recording(s)"
+
+  Scenario: A missing lower-priority source directory is only a warning
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor source directory "capture" is missing
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "warn  2. "
+    And the doctor output should include "/capture: not found"
+
+  Scenario: Doctor fails when no source directory can be read
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" is missing
+    And the doctor source directory "capture" is missing
+    When I run doctor
+    Then doctor should fail with "1 doctor check(s) failed"
+    And the doctor output should include "FAIL  no source directory can be read"
+
+  Scenario: Detection enabled in a build without detection is a warning
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And detection is enabled in the doctor config
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "Timestamp detection"
+    And the doctor output should include "warn  detection.enabled is set, but this build has no detection"
+
+  Scenario: A Windows path fails the path check outside Windows and WSL
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config trimmed directory is "C:\Users\church\Videos\Trimmed"
+    When I run doctor
+    Then doctor should fail with "1 doctor check(s) failed"
+    And the doctor output should include "Configured paths"
+    And the doctor output should include "running on Linux or macOS"
+    And the doctor output should include "FAIL  paths.trimmed_directory: C:\Users\church\Videos\Trimmed is a Windows path"
+
+  Scenario: Under WSL a Windows path is checked where WSL mounts it
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And doctor runs on the "wsl" platform
+    And the doctor config trimmed directory is "Q:\Videos\Trimmed"
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "running on WSL"
+    And the doctor output should include "warn  paths.trimmed_directory: /mnt/q/Videos/Trimmed not found; drive Q: is not mounted in WSL"
+
+  Scenario: A missing credentials file is a warning
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config credentials file is missing
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "warn  google.credentials_file: "
+    And the doctor output should include "credentials.json not found"
+
+  Scenario: OAuth tokens are checked for their location, mode and scopes
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config keeps the drive token with mode 600
+    And the drive token was granted "https://www.googleapis.com/auth/drive"
+    And the doctor config keeps the gmail token with mode 644
+    And the gmail token was granted "https://www.googleapis.com/auth/gmail.send"
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "OAuth tokens"
+    And the doctor output should include "/drive_token.json"
+    And the doctor output should include "ok    google.drive_token_file: granted https://www.googleapis.com/auth/drive"
+    And the doctor output should include "warn  google.gmail_token_file: other users can read it (mode 0644); run chmod 600 "
+    And the doctor output should include "ok    google.gmail_token_file: granted https://www.googleapis.com/auth/gmail.send"
+
+  Scenario: A token without the scope this app needs fails
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config keeps the gmail token with mode 600
+    And the gmail token was granted "https://www.googleapis.com/auth/gmail.readonly"
+    When I run doctor
+    Then doctor should fail with "1 doctor check(s) failed"
+    And the doctor output should include "FAIL  google.gmail_token_file: granted https://www.googleapis.com/auth/gmail.readonly, not https://www.googleapis.com/auth/gmail.send; delete "
+
+  Scenario: A full Drive token covers the narrower file scope
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config asks Drive only for the files it creates
+    And the doctor config keeps the drive token with mode 600
+    And the drive token was granted "https://www.googleapis.com/auth/drive"
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "ok    google.drive_token_file: granted https://www.googleapis.com/auth/drive.file"
+
+  Scenario: A token that cannot be refreshed is a warning
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config keeps the gmail token with mode 600
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "warn  google.gmail_token_file: cannot check its scopes: cannot refresh: invalid_grant"
+    And the doctor output should include "warn  google.drive_token_file is not set"
+
+  Scenario: The services folder is named so it can be confirmed
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config services folder is "services-folder"
+    And Drive has a writable folder "services-folder" named "Sunday Services"
+    When I run doctor
+    Then doctor should pass
+    And the doctor output should include "Drive folder"
+    And the doctor output should include "ok    google.services_folder_id: Sunday Services (services-folder), files can be added"
+
+  Scenario: A mistyped services folder ID fails
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config services folder is "servces-folder"
+    And Drive has a writable folder "services-folder" named "Sunday Services"
+    When I run doctor
+    Then doctor should fail with "1 doctor check(s) failed"
+    And the doctor output should include "FAIL  google.services_folder_id: drive folder not found: no folder with ID servces-folder is visible to this account"
+
+  Scenario: A services folder shared read-only fails
+    Given an HTTP proxy is running
+    And the config proxy URL points at the proxy
+    And the doctor source directory "obs" holds 1 recording
+    And the doctor config services folder is "services-folder"
+    And Drive has a read-only folder "services-folder" named "Sunday Services"
+    When I run doctor
+    Then doctor should fail with "1 doctor check(s) failed"
+    And the doctor output should include "FAIL  google.services_folder_id: drive folder is read-only for this account: Sunday Services (services-folder)"
@@ -0,0 +1,76 @@
+Feature: Drive Metadata Backfill
+  As a media coordinator
+  I want recordings uploaded before metadata tagging to be tagged too
+  So that lookups by service date find every service, not just recent ones
+
+  Scenario: Tag untagged files from their names
+    Given the Services folder to backfill holds:
+      | name           | mime_type  | service_date | media_type |
+      | 2024-03-10.mp4 | video/mp4  |              |            |
+      | 2024-03-10.mp3 | audio/mpeg |              |            |
+      | 2025-12-28.mp4 | video/mp4  | 2025-12-28   | video      |
+    When I backfill metadata
+    Then the backfill should succeed
+    And "2024-03-10.mp4" should be tagged with service_date "2024-03-10"
+    And "2024-03-10.mp4" should be tagged with media_type "video"
+    And "2024-03-10.mp3" should be tagged with media_type "audio"
+    And the backfill output should include "Tagged 2 files, 1 already tagged, 0 unclassified"
+
+  Scenario: Existing properties are kept
+    Given the Services folder to backfill holds:
+      | name                     | mime_type | service_date | media_type |
+      | 2024-03-10 - Evening.mp4 | video/mp4 | 2024-03-09   |            |
+    When I backfill metadata
+    Then the backfill should succeed
+    And "2024-03-10 - Evening.mp4" should be tagged with service_date "2024-03-09"
+    And "2024-03-10 - Evening.mp4" should be tagged with media_type "video"
+
+  Scenario: Files that cannot be classified are reported and left alone
+    Given the Services folder to backfill holds:
+      | name              | mime_type  |
+      | 2024-03-10.mp4    | video/mp4  |
+      | Christmas Eve.mp4 | video/mp4  |
+      | notes.txt         | text/plain |
+    When I backfill metadata
+    Then the backfill should succeed
+    And the backfill output should include "Could not classify 2 files"
+    And the backfill output should include "Christmas Eve.mp4"
+    And the backfill output should include "notes.txt"
+    And "Christmas Eve.mp4" should have no media_type tag
+    And the backfill output should include "Tagged 1 files, 0 already tagged, 2 unclassified"
+
+  Scenario: Preview without writing anything
+    Given the Services folder to backfill holds:
+      | name           | mime_type |
+      | 2024-03-10.mp4 | video/mp4 |
+    When I preview the metadata backfill
+    Then the backfill should succeed
+    And the backfill output should include "Would tag: 2024-03-10.mp4 (media_type=video, service_date=2024-03-10)"
+    And no files should have been tagged
+    And "2024-03-10.mp4" should have no service_date tag
+
+  Scenario: Tag in batches
+    Given the Services folder to backfill holds:
+      | name           | mime_type  |
+      | 2024-03-03.mp4 | video/mp4  |
+      | 2024-03-03.mp3 | audio/mpeg |
+      | 2024-03-10.mp4 | video/mp4  |
+      | 2024-03-10.mp3 | audio/mpeg |
+      | 2024-03-17.mp4 | video/mp4  |
+    When I backfill metadata in batches of 2
+    Then the backfill should succeed
+    And the backfill output should include "Tagged 2 of 5 files"
+    And the backfill output should include "Tagged 4 of 5 files"
+    And the backfill output should include "Tagged 5 of 5 files"
+    And "2024-03-17.mp4" should be tagged with service_date "2024-03-17"
+
+  Scenario: A file that fails to tag does not stop the rest
+    Given the Services folder to backfill holds:
+      | name           | mime_type  |
+      | 2024-03-10.mp3 | audio/mpeg |
+      | 2024-03-10.mp4 | video/mp4  |
+    And tagging "2024-03-10.mp3" fails
+    When I backfill metadata
+    Then the backfill should fail with "1 files could not be tagged"
+    And the backfill output should include "could not tag 2024-03-10.mp3"
+    And "2024-03-10.mp4" should be tagged with service_date "2024-03-10"
@@ -51,6 +51,49 @@ Feature: Google Drive Storage Cleanup
     And "2025-10-15.mp4" should be deleted
     And the cleanup result should show 2 files deleted
 
+  Scenario: Many old files are deleted concurrently
+    Given there is 0 MB of available storage
+    And the cleanup concurrency is 3
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-09-07.mp4   | 268435456   |
+      | 2025-09-14.mp4   | 268435456   |
+      | 2025-09-21.mp4   | 268435456   |
+      | 2025-09-28.mp4   | 268435456   |
+      | 2025-10-05.mp4   | 268435456   |
+      | 2025-10-12.mp4   | 268435456   |
+      | 2025-10-19.mp4   | 268435456   |
+      | 2025-10-26.mp4   | 268435456   |
+      | 2025-11-02.mp4   | 268435456   |
+      | 2025-11-09.mp4   | 268435456   |
+      | 2025-11-16.mp4   | 268435456   |
+      | 2025-11-23.mp4   | 268435456   |
+    When I ensure 2 GB of space is available
+    Then the cleanup should succeed
+    And the cleanup result should show 8 files deleted
+    And the cleanup result should show 2147483648 bytes freed
+    And "2025-10-26.mp4" should be deleted
+    And deletions should run concurrently
+    And at most 3 deletions should run at once
+
+  Scenario: A failed deletion does not abort the cleanup
+    Given there is 0 MB of available storage
+    And the cleanup concurrency is 2
+    And deleting "2025-10-05.mp4" will fail
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-10-05.mp4   | 536870912   |
+      | 2025-10-12.mp4   | 536870912   |
+      | 2025-10-19.mp4   | 536870912   |
+      | 2025-10-26.mp4   | 536870912   |
+    When I ensure 1 GB of space is available
+    Then the cleanup should succeed
+    And "2025-10-05.mp4" should fail to delete
+    And "2025-10-12.mp4" should be deleted
+    And "2025-10-19.mp4" should be deleted
+    And the cleanup result should show 2 files deleted
+    And the cleanup result should show 1073741824 bytes freed
+
   Scenario: No mp4 files available to delete
     Given there is 100 MB of available storage
     And the Services folder contains mp4 files:
@@ -58,6 +101,15 @@ Feature: Google Drive Storage Cleanup
     When I ensure 1 GB of space is available
     Then I should receive an error about insufficient storage
 
+  Scenario: With the file scope only files this app uploaded can be deleted
+    Given Drive access is limited to files this app created
+    And there is 100 MB of available storage
+    And the Services folder contains mp4 files:
+      | name | size |
+    When I ensure 1 GB of space is available
+    Then the cleanup command should fail with "no mp4 files uploaded by this app to delete"
+    And the cleanup command should fail with "other files must be deleted by hand"
+
   Scenario: Files sorted correctly with mixed formats
     Given the Services folder contains mp4 files:
       | name                       | size        |
@@ -72,3 +124,49 @@ Feature: Google Drive Storage Cleanup
       | 2025-11-10 08-00-00.mp4    |
       | 2025-11-17.mp4             |
       | 2025-11-24.mp4             |
+
+  Scenario: Cleanup command makes room for an upload of a given size
+    Given there is 500 MB of available storage
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-11-10.mp4   | 1073741824  |
+      | 2025-11-17.mp4   | 1073741824  |
+      | 2025-11-24.mp4   | 1073741824  |
+    When I run drive cleanup with "--ensure-space" "2GB"
+    Then the cleanup command should succeed
+    And the cleanup output should include "Making room for a 2.0 GB upload"
+    And the cleanup output should include "Removed: 2025-11-10.mp4 (1.0 GB)"
+    And the cleanup output should include "Removed: 2025-11-17.mp4 (1.0 GB)"
+    And the cleanup output should include "(1.0 GB); 2.0 GB of 2.0 GB freed"
+    And the cleanup output should include "[2/2] Removed: "
+    And the cleanup output should include "2025-11-10.mp4  2025-11-10    1.0 GB"
+    And the cleanup output should include "2025-11-17.mp4  2025-11-17    1.0 GB"
+    And the cleanup output should include "Freed 2.0 GB from 2 files"
+
+  Scenario: Cleanup command frees space until an absolute target is free
+    Given there is 2 GB of available storage
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-11-10.mp4   | 1073741824  |
+      | 2025-11-17.mp4   | 1073741824  |
+      | 2025-11-24.mp4   | 1073741824  |
+      | 2025-12-01.mp4   | 1073741824  |
+    When I run drive cleanup with "--target-free" "5GB"
+    Then the cleanup command should succeed
+    And the cleanup output should include "Freeing space until 5.0 GB is available"
+    And the cleanup output should include "Removed: 2025-11-24.mp4"
+    And the cleanup output should not include "2025-12-01.mp4"
+    And the cleanup output should include "Freed 3.0 GB from 3 files"
+
+  Scenario: Cleanup command leaves files alone when the target is already free
+    Given there is 2 GB of available storage
+    And the Services folder contains mp4 files:
+      | name             | size        |
+      | 2025-11-10.mp4   | 1073741824  |
+    When I run drive cleanup with "--target-free" "1.5 GB"
+    Then the cleanup command should succeed
+    And the cleanup output should include "Storage OK, nothing removed"
+
+  Scenario: Cleanup command rejects an unreadable size
+    When I run drive cleanup with "--ensure-space" "two gigs"
+    Then the cleanup command should fail with "invalid --ensure-space"
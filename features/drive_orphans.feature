@@ -0,0 +1,70 @@
+Feature: Orphaned Drive Files
+  As a user
+  I want to find forgotten files in the Services folder
+  So that I can free storage and stop sharing files nobody meant to keep
+
+  Background:
+    Given the history contains services:
+      | date       | processed_at | video_file_id | audio_file_id |
+      | 2025-06-01 | 2025-06-01   | vid-0601      | aud-0601      |
+      | 2025-06-08 | 2025-06-08   | vid-0608      | aud-0608      |
+
+  Scenario: Files in the trash are recommended for deletion
+    Given the Services folder holds these files for the orphan report:
+      | id       | name           | size_mb | state   |
+      | vid-0601 | 2025-06-01.mp4 | 1024    | public  |
+      | old-1    | 2025-05-25.mp4 | 2048    | trashed |
+    When I report orphaned Drive files
+    Then the orphan report should list "2025-05-25.mp4" as "delete" because "still counts against storage"
+    And the orphan report should include "In the trash (1):"
+    And the orphan report should include "The trash holds 2.0 GB"
+    And the orphan report should not include "2025-06-01.mp4"
+
+  Scenario: A trashed file history still links is flagged for review
+    Given the Services folder holds these files for the orphan report:
+      | id       | name           | size_mb | state   |
+      | aud-0608 | 2025-06-08.mp3 | 80      | trashed |
+    When I report orphaned Drive files
+    Then the orphan report should list "2025-06-08.mp3" as "review" because "history links it for 2025-06-08"
+
+  Scenario: Public files no run links to get a recommendation
+    Given the Services folder holds these files for the orphan report:
+      | id       | name              | size_mb | state   | created    |
+      | vid-0608 | 2025-06-08.mp4    | 1024    | public  | 2025-06-08 |
+      | dup-0608 | 2025-06-08-v2.mp4 | 1024    | public  | 2025-06-09 |
+      | pre-1    | 2025-01-05.mp3    | 80      | public  | 2025-01-05 |
+      | hand-1   | 2025-06-15.mp4    | 1024    | public  | 2025-06-15 |
+      | priv-1   | 2025-06-22.mp4    | 1024    | private | 2025-06-22 |
+    When I report orphaned Drive files
+    Then the orphan report should list "2025-06-08-v2.mp4" as "delete" because "history for 2025-06-08 links other files"
+    And the orphan report should list "2025-01-05.mp3" as "keep" because "uploaded before history was kept"
+    And the orphan report should list "2025-06-15.mp4" as "review" because "no run recorded for 2025-06-15"
+    And the orphan report should not include "2025-06-22.mp4"
+    And the orphan report should not include "2025-06-08.mp4"
+    And the orphan report should include "3 orphaned files: 1 to delete, 1 to keep, 1 to review"
+
+  Scenario: An untrimmed copy of a processed service is recommended for deletion
+    Given the Services folder holds these files for the orphan report:
+      | id       | name                    | size_mb | state  | service_date | created    |
+      | dup-0601 | 2025-06-01 10-00-00.mp4 | 4096    | public | 2025-06-01   | 2025-06-02 |
+    When I report orphaned Drive files
+    Then the orphan report should list "2025-06-01 10-00-00.mp4" as "delete" because "history for 2025-06-01 links other files"
+
+  Scenario: Files with unknown names are listed for review
+    Given the Services folder holds these files for the orphan report:
+      | id       | name                   | size_mb | state   |
+      | misc-1   | Copy of 2025-06-01.mp4 | 1024    | public  |
+      | misc-2   | notes.txt              | 1       | private |
+      | vid-0601 | Christmas Service.mp4  | 1024    | public  |
+    When I report orphaned Drive files
+    Then the orphan report should list "Copy of 2025-06-01.mp4" as "review" because "shared publicly but in no history entry"
+    And the orphan report should list "notes.txt" as "review" because "name matches no upload pattern"
+    And the orphan report should include "Unrecognised names (2):"
+    And the orphan report should not include "Christmas Service.mp4"
+
+  Scenario: Subfolders are not reported
+    Given the Services folder holds these files for the orphan report:
+      | id       | name   | size_mb | state   | mime_type                          |
+      | folder-1 | Events | 0       | private | application/vnd.google-apps.folder |
+    When I report orphaned Drive files
+    Then the orphan report should include "No orphaned files found"
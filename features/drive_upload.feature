@@ -37,6 +37,15 @@ Feature: Google Drive Upload and Sharing
     Then both uploads should succeed
     And I should receive shareable URLs for both files
 
+  Scenario: Large uploads report their progress
+    Given I have a video file at "/tmp/test-video.mp4"
+    And Drive confirms the upload 6 bytes at a time
+    When I upload the video to the Services folder
+    Then the upload should succeed
+    And the upload output should contain " 33% of 18 B"
+    And the upload output should contain " 66% of 18 B"
+    And the upload output should contain "100% of 18 B"
+
   Scenario: Handle upload failure for non-existent file
     Given I have a video file at "/tmp/nonexistent.mp4"
     When I attempt to upload the video
@@ -76,3 +85,61 @@ Feature: Google Drive Upload and Sharing
     Then no files should be deleted before upload
     And the upload should succeed
     And the upload output should not contain "Replacing existing"
+
+  Scenario: Transient sharing failure is retried
+    Given I have a video file at "/tmp/2025-12-28.mp4"
+    And the permission API will fail 1 time
+    When I upload the video to the Services folder
+    Then the upload should succeed
+    And the uploaded file should be shared publicly
+
+  Scenario: Upload is kept when sharing keeps failing
+    Given I have a video file at "/tmp/2025-12-28.mp4"
+    And the permission API will fail
+    When I upload the video to the Services folder
+    Then the upload should succeed
+    And the upload should be marked as sharing pending
+    And the upload output should contain "could not share it"
+
+  Scenario: Re-share a service's files by date
+    Given the Drive folder already contains:
+      | name             | mimeType   | size       |
+      | 2025-12-21.mp4   | video/mp4  | 1073741824 |
+      | 2025-12-28.mp4   | video/mp4  | 1073741824 |
+      | 2025-12-28.mp3   | audio/mpeg | 89128960   |
+    When I share the files for "2025-12-28"
+    Then the share should succeed
+    And the file "2025-12-28.mp4" should be shared publicly
+    And the file "2025-12-28.mp3" should be shared publicly
+    And the upload output should contain "Shared: 2025-12-28.mp3"
+
+  Scenario: Re-share finds a renamed upload by its service date tag
+    Given the Drive folder already contains:
+      | name       | mimeType   | size       | service_date |
+      | Sunday.mp4 | video/mp4  | 1073741824 | 2025-12-28   |
+      | Sunday.mp3 | audio/mpeg | 89128960   | 2025-12-28   |
+    When I share the files for "2025-12-28" with the "metadata" processed check
+    Then the share should succeed
+    And the file "Sunday.mp4" should be shared publicly
+    And the file "Sunday.mp3" should be shared publicly
+
+  Scenario: Re-share with the name processed check only matches file names
+    Given the Drive folder already contains:
+      | name           | mimeType   | size       | service_date |
+      | Sunday.mp4     | video/mp4  | 1073741824 | 2025-12-28   |
+      | 2025-12-28.mp3 | audio/mpeg | 89128960   |              |
+    When I share the files for "2025-12-28" with the "name" processed check
+    Then the share should succeed
+    And the file "2025-12-28.mp3" should be shared publicly
+    And the file "Sunday.mp4" should not be shared
+
+  Scenario: Re-share fails when nothing was uploaded for the date
+    When I share the files for "2025-12-28"
+    Then the share should fail with error "no uploaded files found"
+
+  Scenario: Publish audio to a mirror with a checksum
+    Given I have an audio file at "/tmp/2025-12-28.mp3"
+    And a WebDAV mirror server
+    When I publish the audio to the mirror
+    Then the mirror should have "2025-12-28.mp3" with a matching checksum
+    And the upload output should contain "Published: 2025-12-28.mp3"
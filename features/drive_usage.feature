@@ -0,0 +1,75 @@
+Feature: Google Drive Storage Usage
+  As a user
+  I want to see what is using Drive storage
+  So that I can decide which old services to archive
+
+  Background:
+    Given the Services folder ID is "test-folder-id"
+
+  Scenario: Usage is broken down by service year
+    Given the Services folder holds files:
+      | name                    | size_mb | service_date |
+      | 2024-03-03.mp4          | 1024    |              |
+      | 2024-03-03.mp3          | 100     |              |
+      | 2025-01-05.mp4          | 2048    |              |
+      | renamed-service.mp4     | 512     | 2025-02-02   |
+      | notes.txt               | 1       |              |
+    When I check Drive usage
+    Then the usage report should include "Services folder: 3.6 GB in 5 files"
+    And the usage report should include "2024: 1.1 GB (2 files)"
+    And the usage report should include "2025: 2.5 GB (2 files)"
+    And the usage report should include "Undated: 1.0 MB (1 files)"
+
+  Scenario: Largest files are listed first
+    Given the Services folder holds files:
+      | name           | size_mb |
+      | 2025-01-05.mp4 | 1024    |
+      | 2025-01-12.mp4 | 3072    |
+      | 2025-01-19.mp4 | 2048    |
+    When I check Drive usage listing the top 2 files
+    Then the usage report should include " 1. 2025-01-12.mp4"
+    And the usage report should include " 2. 2025-01-19.mp4"
+    And the usage report should not include " 3. "
+
+  Scenario: Recommend the oldest months to archive
+    Given the Services folder holds files:
+      | name           | size_mb |
+      | 2024-11-03.mp4 | 1024    |
+      | 2024-11-10.mp4 | 1024    |
+      | 2024-12-01.mp4 | 1024    |
+      | 2025-01-05.mp4 | 1024    |
+    When I check Drive usage to reclaim "2.5GB"
+    Then the usage report should include "To reclaim 2.5 GB, archive:"
+    And the usage report should include "2024-11: 2.0 GB (2 files)"
+    And the usage report should include "2024-12: 1.0 GB (1 files)"
+    And the usage report should include "Total: 3.0 GB from 2 months"
+    And the usage report should not include "2025-01:"
+
+  Scenario: Warn when archiving cannot free enough
+    Given the Services folder holds files:
+      | name           | size_mb |
+      | 2025-01-05.mp4 | 1024    |
+    When I check Drive usage to reclaim "5GB"
+    Then the usage report should include "Warning: archiving every dated month frees only 1.0 GB"
+
+  Scenario: Invalid reclaim target
+    Given the Services folder holds files:
+      | name           | size_mb |
+      | 2025-01-05.mp4 | 1024    |
+    When I check Drive usage to reclaim "lots"
+    Then the usage check should fail with "invalid --reclaim"
+
+  Scenario: The file scope counts only files this app uploaded
+    Given Drive access is limited to files this app created
+    And the Services folder holds files:
+      | name           | size_mb |
+      | 2025-01-05.mp4 | 1024    |
+    When I check Drive usage
+    Then the usage report should include "Only files uploaded by this app are counted (google.scope_mode: file)"
+
+  Scenario: The full scope counts every file
+    Given the Services folder holds files:
+      | name           | size_mb |
+      | 2025-01-05.mp4 | 1024    |
+    When I check Drive usage
+    Then the usage report should not include "Only files uploaded by this app"
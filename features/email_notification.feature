@@ -50,6 +50,45 @@ Feature: Email Notification
     Then an email should be sent
     And the body should contain "Hey Everyone!"
 
+  Scenario: Formal greeting uses full names
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the email greeting style is "formal"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "jane" with name "Jane Doe" and email "jane@example.com"
+    When I send notification to "jonathan,jane"
+    Then an email should be sent
+    And the body should contain "Dear Jonathan White and Jane Doe,"
+
+  Scenario: Formal greeting for a larger group
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the email greeting style is "formal"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "jane" with name "Jane Doe" and email "jane@example.com"
+    And I have a recipient "alice" with name "Alice Smith" and email "alice@example.com"
+    When I send notification to "jonathan,jane,alice"
+    Then an email should be sent
+    And the body should contain "Dear Friends,"
+
+  Scenario: Casual greeting can name more recipients and use its own group greeting
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the email greeting names up to 3 recipients
+    And the email greeting for larger groups is "Hi all!"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "jane" with name "Jane Doe" and email "jane@example.com"
+    And I have a recipient "alice" with name "Alice Smith" and email "alice@example.com"
+    When I send notification to "jonathan,jane,alice"
+    Then an email should be sent
+    And the body should contain "Dear Jonathan, Jane & Alice,"
+
   Scenario: Lookup recipient by first name
     Given I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
     When I lookup recipient "Jonathan"
@@ -88,3 +127,311 @@ Feature: Email Notification
     Then an email should be sent
     And the HTML body should contain clickable audio link
     And the HTML body should contain clickable video link
+
+  Scenario: Custom subject template with service type and label
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+      | video | https://drive.google.com/file/d/xyz/view      |
+    And the service date is "2025-12-28"
+    And the minister was "Pr. Smith"
+    And the email subject template is "{church} {service_type} ({label}) - {minister}, {date}"
+    And the service type is "Evening Service"
+    And the label is "Confirmation"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    When I send notification to "jonathan"
+    Then an email should be sent
+    And the subject should be "White Plains Evening Service (Confirmation) - Pr. Smith, 12/28/2025"
+
+  Scenario: Subject service type defaults to Service
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the email subject template is "{church}: {service_type} Recording, {date}"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    When I send notification to "jonathan"
+    Then an email should be sent
+    And the subject should be "White Plains: Service Recording, 12/28/2025"
+
+  Scenario: CC the rector when a guest minister preaches
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the minister was "Rev. Visitor"
+    And a local minister "henkel" named "Pr. Henkel"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "rector" with name "Rector Brown" and email "rector@example.com"
+    And a CC rule "guest-minister" for guest ministers that CCs "rector"
+    When I send notification to "jonathan"
+    Then an email should be sent
+    And the email should CC "Rector Brown <rector@example.com>"
+
+  Scenario: Local minister does not trigger the guest rule
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the minister was "Pr. Henkel"
+    And a local minister "henkel" named "Pr. Henkel"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "rector" with name "Rector Brown" and email "rector@example.com"
+    And a CC rule "guest-minister" for guest ministers that CCs "rector"
+    When I send notification to "jonathan"
+    Then an email should be sent
+    And the email should not CC "rector@example.com"
+
+  Scenario: Dry run explains which CC rules fired
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the minister was "Rev. Visitor"
+    And the service type is "Feast Day"
+    And a local minister "henkel" named "Pr. Henkel"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "rector" with name "Rector Brown" and email "rector@example.com"
+    And I have a recipient "district" with name "District Office" and email "district@example.com"
+    And a CC rule "guest-minister" for guest ministers that CCs "rector"
+    And a CC rule "feast-day" for service type "Feast Day" that CCs "district"
+    When I preview the notification to "jonathan"
+    Then the preview should include "CC: Rector Brown <rector@example.com>, District Office <district@example.com>"
+    And the preview should show rule "guest-minister" adding "Rector Brown" because "is not a local minister"
+    And the preview should show rule "feast-day" adding "District Office" because "service type"
+    And the preview should include "Dry run: email not sent"
+    And no email should be sent
+
+  Scenario: Sandbox mode reroutes all mail to the operator
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the minister was "Pr. Smith"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a default CC "Admin <admin@example.com>"
+    And the operator address is "av@example.com"
+    And email sandbox mode is on
+    When I send notification to "jonathan"
+    Then an email should be sent
+    And the email should be sent to "White Plains <av@example.com>"
+    And the subject should be "[TEST] White Plains: Recording of Service on 12/28/2025"
+    And the email should not CC "admin@example.com"
+    And the email should not CC "jonathan@example.com"
+    And the email should not BCC anyone
+
+  Scenario: Sandbox mode falls back to the from address
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And email sandbox mode is on
+    When I send notification to "jonathan"
+    Then the email should be sent to "White Plains <whiteplainsnac@gmail.com>"
+
+  Scenario: Preview shows the sandbox rerouting
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And the minister was "Pr. Smith"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And the operator address is "av@example.com"
+    And email sandbox mode is on
+    When I preview the notification to "jonathan"
+    Then the preview should include "Sandbox: rerouting to White Plains <av@example.com> only"
+    And the preview should include "Subject: [TEST] White Plains: Recording of Service on 12/28/2025"
+    And no email should be sent
+
+  Scenario: A recipient group gets its own context paragraph
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+      | video | https://drive.google.com/file/d/xyz/view      |
+    And the service date is "2025-12-28"
+    And the minister was "Pr. Smith"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "mary" with name "Mary Singer" and email "mary@example.com"
+    And a recipient group "choir" with members "mary" and context "Choir rehearsal moves to Thursday at 7pm."
+    When I send notification to "jonathan,mary"
+    Then 2 emails should be sent
+    And the email to "mary@example.com" should contain "Dear Mary,"
+    And the email to "mary@example.com" should contain "Choir rehearsal moves to Thursday at 7pm."
+    And the email to "jonathan@example.com" should contain "Dear Jonathan,"
+    And the email to "jonathan@example.com" should not contain "Choir rehearsal"
+
+  Scenario: A recipient group can use its own template
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "pr-smith" with name "Pr. Smith" and email "smith@example.com"
+    And a recipient group "ministers" with members "pr-smith" and context ""
+    And the recipient group "ministers" uses a template saying "Dear brother in ministry,"
+    When I send notification to "jonathan,pr-smith"
+    Then 2 emails should be sent
+    And the email to "smith@example.com" should contain "Dear brother in ministry,"
+    And the email to "smith@example.com" should contain "https://drive.google.com/file/d/abc/view"
+    And the email to "jonathan@example.com" should not contain "brother in ministry"
+
+  Scenario: Default CCs are copied only once when sending per group
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "mary" with name "Mary Singer" and email "mary@example.com"
+    And I have a default CC "Admin <admin@example.com>"
+    And a recipient group "choir" with members "mary" and context "See you at rehearsal."
+    When I send notification to "jonathan,mary"
+    Then 2 emails should be sent
+    And the email to "jonathan@example.com" should contain "admin@example.com"
+    And the email to "mary@example.com" should not contain "admin@example.com"
+
+  Scenario: A failed group send is reported while other groups still get their email
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "mary" with name "Mary Singer" and email "mary@example.com"
+    And a recipient group "choir" with members "mary" and context "See you at rehearsal."
+    And Gmail rejects mail to "mary@example.com"
+    When I send notification to "jonathan,mary"
+    Then sending should fail with "failed to send to 1 of 2 groups"
+    And sending should fail with "choir: failed to send email: gmail rejected mary@example.com"
+    And 1 email should be sent
+    And the email to "jonathan@example.com" should contain "Dear Jonathan,"
+
+  Scenario: An unresponsive Gmail API times out instead of hanging
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And Gmail does not respond
+    And the email send timeout is 50 milliseconds
+    When I send notification to "jonathan"
+    Then sending should fail with "timed out after 50ms"
+    And sending should fail with "context deadline exceeded"
+    And no email should be sent
+
+  Scenario: A cancelled run sends no email
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "mary" with name "Mary Singer" and email "mary@example.com"
+    And a recipient group "choir" with members "mary" and context "See you at rehearsal."
+    And the run is cancelled before the email is sent
+    When I send notification to "jonathan,mary"
+    Then sending should fail with "failed to send to 2 of 2 groups"
+    And sending should fail with "not sent: context canceled"
+    And no email should be sent
+
+  Scenario: Preview lists the recipient groups
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "mary" with name "Mary Singer" and email "mary@example.com"
+    And a recipient group "choir" with members "mary" and context "See you at rehearsal."
+    When I preview the notification to "jonathan,mary"
+    Then the preview should include "Groups:"
+    And the preview should include "  default: Jonathan White"
+    And the preview should include "  choir: Mary Singer"
+    And no email should be sent
+
+  Scenario: Preview the email body in the terminal
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+      | video | https://drive.google.com/file/d/xyz/view      |
+    And the service date is "2025-12-28"
+    And the minister was "Pr. Henkel"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    When I preview the notification to "jonathan" in the terminal
+    Then the preview should include "Dear Jonathan,"
+    And the preview should include "audio <https://drive.google.com/file/d/abc/view>"
+    And the preview should include "<https://drive.google.com/file/d/xyz/view>"
+    And the preview should include "Thanks!"
+    And the preview should include "Dry run: email not sent"
+    And no email should be sent
+
+  Scenario: Each recipient group's email is previewed
+    Given I have uploaded files with URLs:
+      | type  | url                                           |
+      | audio | https://drive.google.com/file/d/abc/view      |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    And I have a recipient "mary" with name "Mary Singer" and email "mary@example.com"
+    And a recipient group "choir" with members "mary" and context "See you at rehearsal."
+    When I preview the notification to "jonathan,mary" in the terminal
+    Then the preview should include "Email for choir:"
+    And the preview should include "See you at rehearsal."
+    And the preview should include "Dear Mary,"
+
+  Scenario: The sender gets a blind copy of each email
+    Given I have uploaded files with URLs:
+      | type  | url                                      |
+      | audio | https://drive.google.com/file/d/abc/view |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    When I send notification to "jonathan"
+    Then an email should be sent
+    And the email should be sent to "Jonathan White <jonathan@example.com>"
+    And the email should BCC "whiteplainsnac@gmail.com"
+
+  Scenario: The sender copy can be turned off
+    Given copying the sender on emails is turned off
+    And I have uploaded files with URLs:
+      | type  | url                                      |
+      | audio | https://drive.google.com/file/d/abc/view |
+    And the service date is "2025-12-28"
+    And I have a recipient "jonathan" with name "Jonathan White" and email "jonathan@example.com"
+    When I send notification to "jonathan"
+    Then an email should be sent
+    And the email should not BCC anyone
+
+  Scenario: Correct the minister in an email already sent
+    Given I have a recipient "jane" with name "Jane Doe" and email "jane@example.com"
+    And the "2025-12-28" recording email named "Pr. Smith" and went to "jane@example.com, guest@example.com" as message "<first@gmail.com>"
+    When I send a correction for "2025-12-28" naming minister "Pr. Jones"
+    Then 1 email should be sent
+    And the email should be sent to "Jane Doe <jane@example.com>, guest@example.com"
+    And the subject should be "Re: White Plains: Recording of Service on 12/28/2025"
+    And the email should reply to message "<first@gmail.com>"
+    And the body should contain "it was led by Pr. Jones, not Pr. Smith."
+    And the body should contain "https://drive.google.com/file/d/abc/view"
+    And the history for "2025-12-28" should name minister "Pr. Jones"
+
+  Scenario: Correcting with the minister already named sends nothing
+    Given the "2025-12-28" recording email named "Pr. Smith" and went to "jane@example.com" as message "<first@gmail.com>"
+    When I send a correction for "2025-12-28" naming minister "pr. smith"
+    Then no email should be sent
+    And sending should fail with "nothing to correct"
+
+  Scenario: A correction for an email with no recorded message ID is sent unthreaded
+    Given the "2025-12-28" recording email named "Pr. Smith" and went to "jane@example.com" with no message ID
+    When I send a correction for "2025-12-28" naming minister "Pr. Jones"
+    Then 1 email should be sent
+    And the correction output should include "will not thread under it"
+
+  Scenario: A correction needs a recorded run for the date
+    Given the "2025-12-28" recording email named "Pr. Smith" and went to "jane@example.com" as message "<first@gmail.com>"
+    When I send a correction for "2026-01-04" naming minister "Pr. Jones"
+    Then no email should be sent
+    And sending should fail with "no processed service recorded on 2026-01-04"
+
+  Scenario: A sandboxed correction goes only to the operator and leaves history alone
+    Given email sandbox mode is on
+    And the operator address is "operator@example.com"
+    And the "2025-12-28" recording email named "Pr. Smith" and went to "jane@example.com" as message "<first@gmail.com>"
+    When I send a correction for "2025-12-28" naming minister "Pr. Jones"
+    Then the email should be sent to "White Plains <operator@example.com>"
+    And the subject should be "[TEST] Re: White Plains: Recording of Service on 12/28/2025"
+    And the history for "2025-12-28" should name minister "Pr. Smith"
@@ -38,7 +38,33 @@ func initializeScenarios(ctx *godog.ScenarioContext) {
 	steps.InitializeDriveScenario(ctx)
 	steps.InitializeCleanupScenario(ctx)
 	steps.InitializeUploadScenario(ctx)
+	steps.InitializeShareScanScenario(ctx)
 	steps.InitializeEmailScenario(ctx)
+	steps.InitializeCorrectionScenario(ctx)
+	steps.InitializeResumeScenario(ctx)
+	steps.InitializeCuesScenario(ctx)
+	steps.InitializeYouTubeScenario(ctx)
 	steps.InitializeConfigCrudScenario(ctx)
 	steps.InitializeProcessScenario(ctx)
+	steps.InitializeUpdateScenario(ctx)
+	steps.InitializeHistoryScenario(ctx)
+	steps.InitializeUsageScenario(ctx)
+	steps.InitializeOrphanScenario(ctx)
+	steps.InitializeCapabilitiesScenario(ctx)
+	steps.InitializeFinderScenario(ctx)
+	steps.InitializeSourcesScenario(ctx)
+	steps.InitializeDoctorScenario(ctx)
+	steps.InitializeWorkspaceScenario(ctx)
+	steps.InitializeRefreshScenario(ctx)
+	steps.InitializeS3StorageScenario(ctx)
+	steps.InitializeBackfillScenario(ctx)
+	steps.InitializeAuditScenario(ctx)
+	steps.InitializeWatermarkScenario(ctx)
+	steps.InitializeBundleScenario(ctx)
+	steps.InitializeMigrateScenario(ctx)
+	steps.InitializeAPIScenario(ctx)
+	steps.InitializeWhoamiScenario(ctx)
+	steps.InitializeDemoScenario(ctx)
+	steps.InitializeArchiveYearScenario(ctx)
+	steps.InitializeFuneralScenario(ctx)
 }
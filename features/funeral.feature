@@ -0,0 +1,115 @@
+Feature: Funeral Services
+  As an A/V team member
+  I want a funeral recording shared only with the family, and only for a while
+  So that a private service is not published like a Sunday service
+
+  Background:
+    Given the process config has paths:
+      | source_directory  | /test/source    |
+      | trimmed_directory | /test/trimmed   |
+      | audio_directory   | /test/audio     |
+    And the process config has services folder "folder123"
+    And the process config has ministers:
+      | key   | name           |
+      | smith | Pr. John Smith |
+    And the process config has recipients:
+      | key  | name        | address          |
+      | jane | Jane Doe    | jane@example.com |
+      | bob  | Bob Smith   | bob@example.com  |
+      | ann  | Ann Elder   | ann@example.com  |
+    And the process config has senders:
+      | key    | name     | default |
+      | avteam | A/V Team | yes     |
+    And a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+
+  Scenario: A funeral is audio only and shared with the family alone
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+      | --cc        | bob                                  |
+      | --type      | funeral                              |
+    Then the process should succeed
+    And the video should not be uploaded to Drive
+    And the audio should be uploaded to Drive
+    And the uploaded audio should be shared only with "bob@example.com, jane@example.com"
+    And the uploaded audio should stop being shared after 30 days and be deleted after 90 days
+    And email should include "[Private]"
+    And email should include "shared privately"
+    And email should include "Access ends on"
+    And the output should include "Type: funeral"
+    And the output should include "Sharing: only with 2 people"
+
+  Scenario: CC rules for Sunday services do not copy in others
+    Given the process config has a CC rule "elders" for service type "Funeral" copying "ann"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+      | --type      | funeral                              |
+    Then the process should succeed
+    And email should be sent to "jane@example.com"
+    And email should not be sent to "ann@example.com"
+    And the uploaded audio should be shared only with "jane@example.com"
+
+  Scenario: Default CCs are not copied in or given access
+    Given the process config has default CCs:
+      | name       | address           |
+      | Admin User | admin@example.com |
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+      | --type      | funeral                              |
+    Then the process should succeed
+    And email should be sent to "jane@example.com"
+    And email should not be sent to "admin@example.com"
+    And the uploaded audio should be shared only with "jane@example.com"
+
+  Scenario: The family can ask for the video too
+    When I run process with flags:
+      | flag         | value                                |
+      | --input      | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start      | 00:05:30                             |
+      | --end        | 01:45:00                             |
+      | --minister   | smith                                |
+      | --recipient  | jane                                 |
+      | --type       | funeral                              |
+      | --with-video | true                                 |
+    Then the process should succeed
+    And the video should be uploaded to Drive
+    And the uploaded video should be shared only with "jane@example.com"
+    And the uploaded audio should be shared only with "jane@example.com"
+
+  Scenario: An unknown service type is rejected
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+      | --type      | wedding                              |
+    Then the process should fail with error "unknown service type"
+
+  Scenario: drive expire ends access and then deletes the files
+    Given Drive has "2025-11-02 - Funeral.mp3" shared with "jane@example.com" until "2025-12-02" and kept until "2026-01-31"
+    And Drive has "2025-08-01 - Funeral.mp3" shared with "bob@example.com" until "2025-08-31" and kept until "2025-10-30"
+    And Drive has "2025-12-10 - Funeral.mp3" shared with "ann@example.com" until "2026-01-09" and kept until "2026-03-10"
+    And Drive has "2025-12-28.mp3" shared with anyone with the link
+    When I run drive expire on "2025-12-29"
+    Then "2025-11-02 - Funeral.mp3" should not be shared with anyone
+    And "2025-08-01 - Funeral.mp3" should be deleted from Drive
+    And "2025-12-10 - Funeral.mp3" should be shared with "ann@example.com"
+    And "2025-12-28.mp3" should still be shared with anyone with the link
+    And the expire output should include "Ended access: 2025-11-02 - Funeral.mp3 (jane@example.com)"
+    And the expire output should include "Deleted: 2025-08-01 - Funeral.mp3"
@@ -0,0 +1,112 @@
+Feature: History Export
+  As a user
+  I want to export the services I have processed
+  So that I can report yearly on recordings and distribution
+
+  Background:
+    Given the history contains services:
+      | date       | minister       | duration_seconds | video_size | audio_size | recipients | video_url                       | audio_url                       |
+      | 2024-12-29 | Pr. John Smith | 5400             | 900000000  | 80000000   | 3          | https://drive.example/v/1229    | https://drive.example/a/1229    |
+      | 2025-01-05 | Pr. Jane Doe   | 5970             | 950000000  | 85000000   | 4          | https://drive.example/v/0105    | https://drive.example/a/0105    |
+      | 2025-12-28 | Pr. John Smith | 6000             | 1000000000 | 90000000   | 5          | https://drive.example/v/1228    | https://drive.example/a/1228    |
+      | 2026-01-04 | Pr. Jane Doe   | 3600             |            | 60000000   | 2          |                                 | https://drive.example/a/0104    |
+
+  Scenario: Export all services as CSV
+    When I export history as "csv"
+    Then the export should contain 4 services
+    And the export should include "date,minister,duration,video_size_bytes,audio_size_bytes,recipients,video_url,audio_url"
+    And the export should include "2025-01-05,Pr. Jane Doe,01:39:30,950000000,85000000,4,https://drive.example/v/0105,https://drive.example/a/0105"
+    And the export should include "2026-01-04,Pr. Jane Doe,01:00:00,0,60000000,2,,https://drive.example/a/0104"
+
+  Scenario: Export a calendar year
+    When I export history as "csv" for year "2025"
+    Then the export should contain 2 services
+    And the export should include "2025-01-05"
+    And the export should include "2025-12-28"
+    And the export should not include "2024-12-29"
+    And the export should not include "2026-01-04"
+
+  Scenario: Export a date range as JSON
+    When I export history as "json" from "2025-12-01" to "2026-01-31"
+    Then the export should be valid JSON with 2 services
+    And the exported JSON should include a service with date "2025-12-28"
+    And the exported JSON should include a service with recipients "5"
+    And the export should not include "2025-01-05"
+
+  Scenario: Empty range exports only the header
+    When I export history as "csv" from "2025-07-01" to "2025-07-31"
+    Then the export should contain 0 services
+    And the export should include "date,minister,duration"
+
+  Scenario: Reject an unknown format
+    When I export history as "xml"
+    Then the export should fail with "unknown export format"
+
+  Scenario: Reject a reversed date range
+    When I export history as "csv" from "2025-12-31" to "2025-01-01"
+    Then the export should fail with "before --from date"
+
+  Scenario: Add a note to a processed service
+    When I add the history note "organ mic buzzing" to "2025-12-28"
+    Then the note output should include "Added note to 2025-12-28: organ mic buzzing"
+    And the history for "2025-12-28" should have notes "organ mic buzzing"
+    When I add the history note "camera 2 dropped frames" to "2025-12-28"
+    Then the history for "2025-12-28" should have notes "organ mic buzzing; camera 2 dropped frames"
+    When I export history as "csv"
+    Then the export should contain 4 services
+
+  Scenario: List services with notes
+    Given I add the history note "organ mic buzzing" to "2025-12-28"
+    And I add the history note "late start" to "2024-12-29"
+    When I list history notes for year "2025"
+    Then the note output should include "2025-12-28 (Pr. John Smith)"
+    And the note output should include "  - organ mic buzzing"
+    And the note output should not include "late start"
+
+  Scenario: Reject a note for a service that was not processed
+    When I add the history note "organ mic buzzing" to "2025-07-06"
+    Then adding the note should fail with "no processed service recorded on 2025-07-06"
+
+  Scenario: Reject an empty note
+    When I add the history note "  " to "2025-12-28"
+    Then adding the note should fail with "note text is empty"
+
+  Scenario: Report minister statistics for a year
+    When I show minister stats for year "2025"
+    Then the minister stats should list "Pr. Jane Doe" with 1 service averaging "01:39:30" last served "2025-01-05"
+    And the minister stats should list "Pr. John Smith" with 1 service averaging "01:40:00" last served "2025-12-28"
+    And the minister stats should include "2 services by 2 ministers"
+
+  Scenario: Report minister statistics over all history
+    When I show minister stats from "" to ""
+    Then the minister stats should list "Pr. Jane Doe" with 2 services averaging "01:19:45" last served "2026-01-04"
+    And the minister stats should list "Pr. John Smith" with 2 services averaging "01:35:00" last served "2025-12-28"
+
+  Scenario: Minister statistics for a range with no services
+    When I show minister stats from "2025-07-01" to "2025-07-31"
+    Then the minister stats should include "No services with a minister recorded"
+
+  Scenario: List processed services for a year
+    When I list history for year "2025"
+    Then the history output should include "DATE        MINISTER"
+    And the history output should include "2025-01-05  Pr. Jane Doe"
+    And the history output should include "success"
+    And the history output should include "2 runs"
+    And the history output should not include "2024-12-29"
+
+  Scenario: Show everything recorded for a service
+    Given the history contains services:
+      | date       | minister       | source_file             | start_time | end_time | duration_seconds | recipients | video_file_id | video_url                    | audio_file_id | audio_url                    |
+      | 2026-01-11 | Pr. John Smith | 2026-01-11 10-02-00.mp4 | 00:05:30   | 01:45:00 | 5970             | 2          | vid-0111      | https://drive.example/v/0111 | aud-0111      | https://drive.example/a/0111 |
+    When I show history for "2026-01-11"
+    Then the history output should include "2026-01-11 (Pr. John Smith)"
+    And the history output should include "Source:      2026-01-11 10-02-00.mp4"
+    And the history output should include "Trim:        00:05:30-01:45:00 (01:39:30 long)"
+    And the history output should include "Video:       vid-0111 https://drive.example/v/0111"
+    And the history output should include "Audio:       aud-0111 https://drive.example/a/0111"
+    And the history output should include "Recipients:  r0@example.com, r1@example.com"
+    And the history output should include "Outcome:     success"
+
+  Scenario: Show a service that was not processed
+    When I show history for "2025-07-06"
+    Then showing history should fail with "no processed service recorded on 2025-07-06"
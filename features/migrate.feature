@@ -0,0 +1,70 @@
+Feature: Migrate From Manual Uploads
+  As a media coordinator switching from uploading by hand
+  I want existing Drive files renamed and recorded in history
+  So that the tool treats services uploaded before the switch like its own
+
+  Scenario: Rename hand-named files and seed history
+    Given the Services folder to migrate holds:
+      | id | name                        | size       | uploaded   |
+      | v1 | Service 12-21-2025.mp4      | 1200000000 | 2025-12-21 |
+      | a1 | Dec 21 2025 sermon.mp3      | 85000000   | 2025-12-21 |
+      | v2 | 2025-12-28.mp4              | 1100000000 | 2025-12-28 |
+      | n1 | Bulletin.pdf                | 200000     | 2025-12-28 |
+    When I run the migration scan
+    Then the migration should succeed
+    And the Services folder should hold "2025-12-21.mp4, 2025-12-21.mp3, 2025-12-28.mp4"
+    And the migration output should include "Renamed: Service 12-21-2025.mp4 -> 2025-12-21.mp4"
+    And the migration output should include "1 already named YYYY-MM-DD, 2 to rename, 0 skipped, 1 not recognised"
+    And the migration output should include "Bulletin.pdf"
+    And the migration output should include "Added 2 services to history (0 already recorded)"
+    And the history should have 2 services
+    And the history for "2025-12-21" should link video "v1" and audio "a1"
+
+  Scenario: A dry run changes nothing
+    Given the Services folder to migrate holds:
+      | id | name                   | size       | uploaded   |
+      | v1 | Service 12-21-2025.mp4 | 1200000000 | 2025-12-21 |
+    When I preview the migration scan
+    Then the migration should succeed
+    And no files should have been renamed
+    And the migration output should include "Would rename: Service 12-21-2025.mp4 -> 2025-12-21.mp4"
+    And the migration output should include "Would add 1 services to history"
+    And the history should have 0 services
+
+  Scenario: Names that would clash are left alone
+    Given the Services folder to migrate holds:
+      | id | name                | size | uploaded   |
+      | v1 | 2025-12-21.mp4      | 100  | 2025-12-21 |
+      | v2 | 2025_12_21 copy.mp4 | 100  | 2025-12-22 |
+      | v3 | 01-04-2026 AM.mp4   | 100  | 2026-01-04 |
+      | v4 | 2026-01-04 PM.mp4   | 100  | 2026-01-04 |
+    When I run the migration scan
+    Then the migration should succeed
+    And no files should have been renamed
+    And the migration output should include "2025_12_21 copy.mp4: 2025-12-21.mp4 already exists"
+    And the migration output should include "01-04-2026 AM.mp4: 2 files would be named 2026-01-04.mp4"
+
+  Scenario: Services already in history are not added again
+    Given the history contains services:
+      | date       | minister |
+      | 2025-12-21 | Smith    |
+    And the Services folder to migrate holds:
+      | id | name                   | size | uploaded   |
+      | v1 | Service 12-21-2025.mp4 | 100  | 2025-12-21 |
+      | v2 | Service 12-28-2025.mp4 | 100  | 2025-12-28 |
+    When I run the migration scan
+    Then the migration should succeed
+    And the migration output should include "Added 1 services to history (1 already recorded)"
+    And the history should have 2 services
+
+  Scenario: A failed rename is reported and kept out of history
+    Given the Services folder to migrate holds:
+      | id | name                   | size | uploaded   |
+      | v1 | Service 12-21-2025.mp4 | 100  | 2025-12-21 |
+      | v2 | Service 12-28-2025.mp4 | 100  | 2025-12-28 |
+    And renaming "Service 12-21-2025.mp4" fails
+    When I run the migration scan
+    Then the migration should fail with "1 files could not be renamed"
+    And the migration output should include "Warning: could not rename Service 12-21-2025.mp4"
+    And the Services folder should hold "2025-12-28.mp4"
+    And the history should have 1 service
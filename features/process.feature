@@ -43,6 +43,56 @@ Feature: End-to-End Process Command
     And email should include minister "Pr. John Smith"
     And email should include video and audio links
 
+  Scenario: Services folder link is shown and optionally emailed
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config includes the folder link in emails
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "Folder link: https://drive.google.com/drive/folders/folder123"
+    And email should include "Previous services: https://drive.google.com/drive/folders/folder123"
+    And email should include ">previous services</a>"
+
+  Scenario: Services folder link is left out of the email by default
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "Folder link: https://drive.google.com/drive/folders/folder123"
+    And email should not include "drive/folders"
+
+  Scenario: Livestream link is emailed when configured
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config links the livestream channel "https://www.youtube.com/@nacchurch/streams"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And email should include "Watch the livestream recording: https://www.youtube.com/@nacchurch/streams"
+    And email should include ">Watch the livestream recording</a>"
+
+  Scenario: Livestream link is left out of the email when not configured
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And email should not include "livestream"
+
   Scenario: Process with multiple recipients
     Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
     When I run process with flags:
@@ -57,6 +107,118 @@ Feature: End-to-End Process Command
     And email should be sent to "jane@example.com"
     And email should be sent to "john@example.com"
 
+  Scenario: Process to a recipient group leaving one member out
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config has recipients:
+      | key  | name        | address          |
+      | mary | Mary Singer | mary@example.com |
+    And the process config has a recipient group "choir" with members "jane, john, mary"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | choir                                |
+      | --exclude   | john                                 |
+    Then the process should succeed
+    And the output should include "Recipients: Jane Doe <jane@example.com>, Mary Singer <mary@example.com>"
+    And email should be sent to "jane@example.com"
+    And email should be sent to "mary@example.com"
+    And email should not be sent to "john@example.com"
+
+  Scenario: An exclusion that matches no recipient stops processing
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --exclude   | john                                 |
+    Then the process should fail with error "exclusion matches no recipient"
+    And the process error code should be "EXCLUSION_UNMATCHED" with exit code 16
+    And email should not be sent to "jane@example.com"
+
+  Scenario: Process with an end time relative to the end of the file
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process source video is 110 minutes long
+    When I run process with flags:
+      | flag       | value                              |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                           |
+      | --end      | -00:05:00                          |
+      | --minister | smith                              |
+      | --recipient| jane                               |
+    Then the process should succeed
+    And the video should be trimmed from "00:05:30" to "01:45:00"
+    And the output should include "Trim range: 00:05:30 to 01:45:00"
+
+  Scenario: A New Year's Eve service runs past midnight
+    Given a source video exists at "/test/source/2025-12-31 23-25-00.mp4"
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-31 23-25-00.mp4 |
+      | --start    | 00:05:00                             |
+      | --end      | 01:50:00                             |
+      | --minister | smith                                |
+      | --recipient| jane                                 |
+    Then the process should succeed
+    And the video should be trimmed from "00:05:00" to "01:50:00"
+    And the output should include "Service date: 2025-12-31"
+    And the output should include "Service runs past midnight, ending 2026-01-01"
+    And email should include "12/31/2025 to 01/01/2026"
+
+  Scenario: An overnight service keeps the date given with --date
+    Given a source video exists at "/test/source/2025-12-31 23-25-00.mp4"
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-31 23-25-00.mp4 |
+      | --start    | 00:05:00                             |
+      | --end      | 01:50:00                             |
+      | --date     | 2025-12-24                           |
+      | --recipient| jane                                 |
+    Then the process should succeed
+    And the output should include "Service date: 2025-12-24"
+    And the output should include "Service runs past midnight, ending 2025-12-25"
+    And email should include "12/24/2025 to 12/25/2025"
+
+  Scenario: A service that ends before midnight is not overnight
+    Given a source video exists at "/test/source/2025-12-31 21-55-00.mp4"
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-31 21-55-00.mp4 |
+      | --start    | 00:05:00                             |
+      | --end      | 02:04:00                             |
+      | --recipient| jane                                 |
+    Then the process should succeed
+    And the output should not include "past midnight"
+    And email should not include "to 01/01/2026"
+
+  Scenario: An end past the end of the recording trims to the end
+    Given a source video exists at "/test/source/2025-12-31 23-25-00.mp4"
+    And the process source video is 100 minutes long
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-31 23-25-00.mp4 |
+      | --start    | 00:05:00                             |
+      | --end      | 01:50:00                             |
+      | --recipient| jane                                 |
+    Then the process should succeed
+    And the video should be trimmed from "00:05:00" to "01:40:00"
+    And the output should include "End 01:50:00 is past the end of the recording; trimming to the end at 01:40:00"
+
+  Scenario: A start past the end of the recording is rejected
+    Given a source video exists at "/test/source/2025-12-31 23-25-00.mp4"
+    And the process source video is 100 minutes long
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-31 23-25-00.mp4 |
+      | --start    | 01:45:00                             |
+      | --end      | 01:50:00                             |
+      | --recipient| jane                                 |
+    Then the process should fail with error "start 01:45:00 is past the end of the 01:40:00 recording"
+    And the video should not be trimmed
+
   Scenario: Process using newest file when input omitted
     Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
     And a source video exists at "/test/source/2025-12-29 09-15-00.mp4"
@@ -109,6 +271,9 @@ Feature: End-to-End Process Command
       | --recipient| jane                               |
     Then the process should fail with error "minister 'unknown' not found"
     And the error should suggest command "config add minister --key unknown"
+    And the process error code should be "MINISTER_NOT_FOUND" with exit code 10
+    And the JSON error report should include "\"code\":\"MINISTER_NOT_FOUND\""
+    And the JSON error report should include "\"exit_code\":10"
 
   Scenario: Error when recipient not found
     Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
@@ -121,6 +286,34 @@ Feature: End-to-End Process Command
       | --recipient| unknown                            |
     Then the process should fail with error "recipient 'unknown' not found"
     And the error should suggest command "config add recipient --key unknown"
+    And the process error code should be "RECIPIENT_NOT_FOUND" with exit code 11
+
+  Scenario: The Drive folder is named before processing starts
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag       | value                              |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                           |
+      | --end      | 01:45:00                           |
+      | --minister | smith                              |
+      | --recipient| jane                               |
+    Then the process should succeed
+    And the output should include "Drive folder: Sunday Services"
+
+  Scenario: Error when the Drive folder ID is mistyped
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the services folder ID is mistyped
+    When I run process with flags:
+      | flag       | value                              |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                           |
+      | --end      | 01:45:00                           |
+      | --minister | smith                              |
+      | --recipient| jane                               |
+    Then the process should fail with error "drive folder not found: no folder with ID folder123 is visible to this account"
+    And the process error code should be "DRIVE_FOLDER_INVALID" with exit code 23
+    And the video should not be trimmed
+    And the video should not be uploaded to Drive
 
   Scenario: Error when source file not found
     Given no source video exists at "/test/source/2025-12-28 10-06-16.mp4"
@@ -158,6 +351,53 @@ Feature: End-to-End Process Command
     And the recovery should suggest "upload" command
     And the recovery should suggest "send-email" command
 
+  Scenario: Email failure recovery includes the uploaded file URLs
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And sending the email will fail with "quota exceeded"
+    When I run process with flags:
+      | flag       | value                              |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                           |
+      | --end      | 01:45:00                           |
+      | --minister | smith                              |
+      | --recipient| jane                               |
+    Then the process should fail with error "quota exceeded"
+    And the output should include "Already uploaded:"
+    And the output should include "(file ID uploaded-file-1)"
+    And the recovery email command should include minister "Pr. John Smith"
+    And the recovery email command should include audio URL "https://drive.google.com/file/d/uploaded-file-2/view?usp=sharing"
+    And the recovery email command should include video URL "https://drive.google.com/file/d/uploaded-file-1/view?usp=sharing"
+    And the output should not include "<URL>"
+
+  Scenario: Audio upload failure recovery reuses the uploaded video
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the drive upload of "mp3" files will fail with "connection reset"
+    When I run process with flags:
+      | flag       | value                              |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                           |
+      | --end      | 01:45:00                           |
+      | --minister | smith                              |
+      | --recipient| jane                               |
+    Then the process should fail with error "connection reset"
+    And the output should include "upload --audio-only --audio"
+    And the recovery email command should include video URL "https://drive.google.com/file/d/uploaded-file-1/view?usp=sharing"
+    And the output should include "--audio-url <URL>"
+
+  Scenario: Sharing failure after upload still sends the email
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And drive sharing will fail with "backend error"
+    When I run process with flags:
+      | flag       | value                              |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                           |
+      | --end      | 01:45:00                           |
+      | --minister | smith                              |
+      | --recipient| jane                               |
+    Then the process should succeed
+    And email should be sent to "jane@example.com"
+    And the output should include "nac-service-media drive share --date 2025-12-28"
+
   Scenario: Progress output shows step completion
     Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
     When I run process with flags:
@@ -184,6 +424,30 @@ Feature: End-to-End Process Command
       | name           | size      |
       | 2025-11-01.mp4 | 1073741824|
       | 2025-11-08.mp4 | 1073741824|
+    And the operator answers the checkpoints with "yes"
+    When I run process with flags:
+      | flag       | value                              |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                           |
+      | --end      | 01:45:00                           |
+      | --minister | smith                              |
+      | --recipient| jane                               |
+    Then the process should succeed
+    And the output should include "these old videos would be deleted:"
+    And the output should include "Next: delete the oldest videos from Drive to free"
+    And the output should include "Removed: 2025-11-01.mp4 (1.0 GB);"
+    And the output should include "(1.0 GB); 2.0 GB of 2.0 GB freed"
+    And the output should include "Deleted from Drive (2.0 GB freed):"
+    And the output should include "2025-11-01.mp4  2025-11-01    1.0 GB"
+
+  Scenario: Declining the Drive cleanup stops before anything is deleted
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And drive has insufficient space
+    And drive has old files:
+      | name           | size      |
+      | 2025-11-01.mp4 | 1073741824|
+      | 2025-11-08.mp4 | 1073741824|
+    And the operator answers the checkpoints with "no"
     When I run process with flags:
       | flag       | value                              |
       | --input    | /test/source/2025-12-28 10-06-16.mp4 |
@@ -191,8 +455,370 @@ Feature: End-to-End Process Command
       | --end      | 01:45:00                           |
       | --minister | smith                              |
       | --recipient| jane                               |
+    Then the process should fail with error "Drive cleanup: stopped at a checkpoint"
+    And the output should include "        2025-11-01.mp4 (1024.0 MB)"
+    And the output should not include "Removed:"
+    And cleanup should not be called
+
+  Scenario: A non-interactive run needs --allow-delete to make room on Drive
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And drive has insufficient space
+    And drive has old files:
+      | name           | size      |
+      | 2025-11-01.mp4 | 1073741824|
+    When I run process with flags:
+      | flag              | value                              |
+      | --input           | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start           | 00:05:30                           |
+      | --end             | 01:45:00                           |
+      | --minister        | smith                              |
+      | --recipient       | jane                               |
+      | --non-interactive |                                    |
+    Then the process should fail with error "making room means deleting 1 old video(s), which needs approval"
+    And the process error code should be "DELETE_NOT_ALLOWED" with exit code 21
+    And cleanup should not be called
+
+  Scenario: --allow-delete makes room on Drive without asking
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And drive has insufficient space
+    And drive has old files:
+      | name           | size      |
+      | 2025-11-01.mp4 | 1073741824|
+      | 2025-11-08.mp4 | 1073741824|
+    When I run process with flags:
+      | flag              | value                              |
+      | --input           | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start           | 00:05:30                           |
+      | --end             | 01:45:00                           |
+      | --minister        | smith                              |
+      | --recipient       | jane                               |
+      | --non-interactive |                                    |
+      | --allow-delete    |                                    |
     Then the process should succeed
     And the output should include "Removed: 2025-11-01.mp4"
+    And the output should not include "Next: delete"
+    And the process audit log should record the cleanup "allowed by --allow-delete"
+
+  Scenario: A recording written on a different day than its name needs --date
+    Given a source video exists at "/test/source/2025-12-29 10-06-16.mp4"
+    And "/test/source/2025-12-29 10-06-16.mp4" was last written at "2025-12-28 11:50"
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-29 10-06-16.mp4 |
+      | --start    | 00:05:30                             |
+      | --end      | 01:45:00                             |
+      | --minister | smith                                |
+      | --recipient| jane                                 |
+    Then the process should fail with error "the recording PC's clock may be wrong"
+    And the process error code should be "CLOCK_DRIFT" with exit code 18
+    And the output should include "Warning: 2025-12-29 10-06-16.mp4 was last written 2025-12-28 11:50"
+    And the video should not be trimmed
+
+  Scenario: --date confirms the service date when the clock drifted
+    Given a source video exists at "/test/source/2025-12-29 10-06-16.mp4"
+    And "/test/source/2025-12-29 10-06-16.mp4" was last written at "2025-12-28 11:50"
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-29 10-06-16.mp4 |
+      | --start    | 00:05:30                             |
+      | --end      | 01:45:00                             |
+      | --minister | smith                                |
+      | --recipient| jane                                 |
+      | --date     | 2025-12-28                           |
+    Then the process should succeed
+    And the output should include "Service date: 2025-12-28"
+    And the output should not include "clock may be wrong"
+
+  Scenario: A recording written when it stopped passes the clock check
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And "/test/source/2025-12-28 10-06-16.mp4" was last written at "2025-12-28 11:50"
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                             |
+      | --end      | 01:45:00                             |
+      | --minister | smith                                |
+      | --recipient| jane                                 |
+    Then the process should succeed
+    And the output should not include "clock may be wrong"
+
+  Scenario: Confirm each step pauses before Drive cleanup and the email
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And drive has insufficient space
+    And drive has old files:
+      | name           | size      |
+      | 2025-11-01.mp4 | 1073741824|
+      | 2025-11-08.mp4 | 1073741824|
+    And the operator answers the checkpoints with "yes, yes"
+    When I run process with flags:
+      | flag                | value                              |
+      | --input             | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start             | 00:05:30                           |
+      | --end               | 01:45:00                           |
+      | --minister          | smith                              |
+      | --recipient         | jane                               |
+      | --confirm-each-step |                                    |
+    Then the process should succeed
+    And the output should include "Next: delete the oldest videos from Drive to free"
+    And the output should include "Removed: 2025-11-01.mp4"
+    And the output should include "Next: send the email to Jane Doe <jane@example.com>"
+    And email should be sent to "jane@example.com"
+
+  Scenario: Recipients can be changed at the email checkpoint
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the operator adds "john" to the recipients at the email step
+    And the operator removes "Admin User <admin@example.com>" at the email step
+    And the operator answers the checkpoints with "yes"
+    When I run process with flags:
+      | flag                | value                              |
+      | --input             | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start             | 00:05:30                           |
+      | --end               | 01:45:00                           |
+      | --minister          | smith                              |
+      | --recipient         | jane                               |
+      | --confirm-each-step |                                    |
+    Then the process should succeed
+    And the output should include "To: Jane Doe <jane@example.com>"
+    And the output should include "CC: Admin User <admin@example.com>"
+    And the output should include "Added: John Doe <john@example.com>"
+    And the output should include "Removed: admin@example.com"
+    And the output should include "Next: send the email to Jane Doe <jane@example.com>, John Doe <john@example.com>"
+    And email should be sent to "john@example.com"
+    And email should not be sent to "admin@example.com"
+
+  Scenario: An unknown recipient added at the email checkpoint is reported
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the operator adds "deacon" to the CCs at the email step
+    And the operator answers the checkpoints with "yes"
+    When I run process with flags:
+      | flag                | value                              |
+      | --input             | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start             | 00:05:30                           |
+      | --end               | 01:45:00                           |
+      | --minister          | smith                              |
+      | --recipient         | jane                               |
+      | --confirm-each-step |                                    |
+    Then the process should succeed
+    And the output should include "Not added:"
+    And email should be sent to "jane@example.com"
+
+  Scenario: Declining a checkpoint stops before the email is sent
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the operator answers the checkpoints with "no"
+    When I run process with flags:
+      | flag                | value                              |
+      | --input             | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start             | 00:05:30                           |
+      | --end               | 01:45:00                           |
+      | --minister          | smith                              |
+      | --recipient         | jane                               |
+      | --confirm-each-step |                                    |
+    Then the process should fail with error "stopped at a checkpoint"
+    And email should not be sent to "jane@example.com"
+    And the output should include recovery commands
+
+  Scenario: Non-interactive runs skip the checkpoints
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag                | value                              |
+      | --input             | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start             | 00:05:30                           |
+      | --end               | 01:45:00                           |
+      | --minister          | smith                              |
+      | --recipient         | jane                               |
+      | --confirm-each-step |                                    |
+      | --non-interactive   |                                    |
+    Then the process should succeed
+    And the output should include "--confirm-each-step is ignored with --non-interactive"
+    And the output should not include "Next: send the email"
+
+  Scenario: A run under the weekly upload budget reports what is left
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    And the history contains services:
+      | date       | video_size | audio_size | processed_days_ago |
+      | 2025-12-14 | 2000000000 | 85000000   | 10                 |
+      | 2025-12-21 | 1000000000 | 85000000   | 2                  |
+    And the process config has a weekly upload budget of 5 GB
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                             |
+      | --end      | 01:45:00                             |
+      | --minister | smith                                |
+      | --recipient| jane                                 |
+    Then the process should succeed
+    And the output should include "Upload budget: 1.0 GB of 5.0 GB used this week"
+    And the output should not include "weekly upload budget"
+
+  Scenario: Going over the weekly upload budget warns by default
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    And the history contains services:
+      | date       | video_size | audio_size | processed_days_ago |
+      | 2025-12-21 | 4000000000 | 85000000   | 2                  |
+    And the process config has a weekly upload budget of 5 GB
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                             |
+      | --end      | 01:45:00                             |
+      | --minister | smith                                |
+      | --recipient| jane                                 |
+    Then the process should succeed
+    And the output should include "Warning: uploading 1.2 GB would go over the weekly upload budget"
+    And email should be sent to "jane@example.com"
+
+  Scenario: Going over a confirm-only budget asks first
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    And the history contains services:
+      | date       | video_size | audio_size | processed_days_ago |
+      | 2025-12-21 | 4000000000 | 85000000   | 2                  |
+    And the process config asks before going over a weekly upload budget of 5 GB
+    And the operator answers the checkpoints with "no"
+    When I run process with flags:
+      | flag       | value                                |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                             |
+      | --end      | 01:45:00                             |
+      | --minister | smith                                |
+      | --recipient| jane                                 |
+    Then the process should fail with error "upload budget: stopped at a checkpoint"
+    And email should not be sent to "jane@example.com"
+
+  Scenario: A non-interactive run stops at a confirm-only budget
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    And the history contains services:
+      | date       | video_size | audio_size | processed_days_ago |
+      | 2025-12-21 | 4000000000 | 85000000   | 2                  |
+    And the process config asks before going over a weekly upload budget of 5 GB
+    When I run process with flags:
+      | flag              | value                                |
+      | --input           | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start           | 00:05:30                             |
+      | --end             | 01:45:00                             |
+      | --minister        | smith                                |
+      | --recipient       | jane                                 |
+      | --non-interactive |                                      |
+    Then the process should fail with error "would go over the weekly upload budget"
+    And the process error code should be "OVER_UPLOAD_BUDGET" with exit code 19
+
+  Scenario: Recovery suggests a drive cleanup when there is no room left
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And drive has insufficient space
+    When I run process with flags:
+      | flag       | value                              |
+      | --input    | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start    | 00:05:30                           |
+      | --end      | 01:45:00                           |
+      | --minister | smith                              |
+      | --recipient| jane                               |
+    Then the process should fail with error "storage check failed"
+    And the output should include "1. Storage:    nac-service-media drive cleanup --ensure-space 1226MB"
+    And the output should include "2. Auth:"
+
+  Scenario: Streaming skip video mode uploads the audio while it is encoded
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag           | value                                |
+      | --input        | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start        | 00:05:30                             |
+      | --end          | 01:45:00                             |
+      | --recipient    | jane                                 |
+      | --skip-video   |                                      |
+      | --stream-audio |                                      |
+    Then the process should succeed
+    And the audio should be streamed to Drive
+    And the streamed audio should not be saved locally
+    And the output should not include "Created: "
+    And the output should include "Estimated audio size: 136.6 MB"
+    And the output should include "[1/3] Checking Drive storage"
+    And the output should include "[2/3] Extracting and uploading audio"
+    And the output should include "size and checksum verified"
+    And the output should include "[3/3] Sending email"
+    And email should include audio link only
+
+  Scenario: A streamed run records the uploaded audio size in history
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    When I run process with flags:
+      | flag           | value                                |
+      | --input        | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start        | 00:05:30                             |
+      | --end          | 01:45:00                             |
+      | --recipient    | jane                                 |
+      | --skip-video   |                                      |
+      | --stream-audio |                                      |
+    Then the process should succeed
+    And the streamed audio should not be saved locally
+    And the history for "2025-12-28" should record an audio size of 19 bytes
+
+  Scenario: Streamed audio is also saved locally when asked
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config keeps streamed audio locally
+    When I run process with flags:
+      | flag           | value                                |
+      | --input        | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start        | 00:05:30                             |
+      | --end          | 01:45:00                             |
+      | --recipient    | jane                                 |
+      | --skip-video   |                                      |
+      | --stream-audio |                                      |
+    Then the process should succeed
+    And the audio should be streamed to Drive
+    And the streamed audio should be saved locally
+    And the output should include "Created: "
+
+  Scenario: Streaming falls back to a file when ffmpeg fails
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And streaming the audio will fail with "broken pipe"
+    When I run process with flags:
+      | flag           | value                                |
+      | --input        | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start        | 00:05:30                             |
+      | --end          | 01:45:00                             |
+      | --recipient    | jane                                 |
+      | --skip-video   |                                      |
+      | --stream-audio |                                      |
+    Then the process should succeed
+    And the output should include "Streaming failed:"
+    And the output should include "broken pipe"
+    And the output should include "Falling back to extracting to a file first"
+    And the audio should not be streamed to Drive
+    And the audio should be uploaded to Drive
+
+  Scenario: Streamed upload that fails verification is replaced by a file upload
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And drive will store streamed uploads truncated
+    When I run process with flags:
+      | flag           | value                                |
+      | --input        | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start        | 00:05:30                             |
+      | --end          | 01:45:00                             |
+      | --recipient    | jane                                 |
+      | --skip-video   |                                      |
+      | --stream-audio |                                      |
+    Then the process should succeed
+    And the output should include "but 19 were sent"
+    And the audio should not be streamed to Drive
+    And the audio should be uploaded to Drive
+
+  Scenario: Streaming needs the overwrite policy
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag           | value                                |
+      | --input        | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start        | 00:05:30                             |
+      | --end          | 01:45:00                             |
+      | --recipient    | jane                                 |
+      | --skip-video   |                                      |
+      | --stream-audio |                                      |
+      | --on-existing  | skip                                 |
+    Then the process should succeed
+    And the output should include "Streaming upload needs --on-existing overwrite"
+    And the audio should not be streamed to Drive
 
   # Skip video mode scenarios
   Scenario: Skip video mode extracts audio only
@@ -237,6 +863,7 @@ Feature: End-to-End Process Command
     And drive has old files:
       | name           | size      |
       | 2025-11-01.mp4 | 1073741824|
+    And the operator answers the checkpoints with "yes"
     When I run process with flags:
       | flag         | value                              |
       | --input      | /test/source/2025-12-28 10-06-16.mp4 |
@@ -263,6 +890,35 @@ Feature: End-to-End Process Command
       | --recipient| jane     |
     Then the process should fail with error "has already been processed"
     And the error should suggest command "--input"
+    And the process error code should be "ALREADY_PROCESSED" with exit code 20
+
+  Scenario: Skip a recording history says was processed in auto-detect mode
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the history contains services:
+      | date       | minister       | source_file             | processed_at |
+      | 2025-12-28 | Pr. John Smith | 2025-12-28 10-06-16.mp4 | 2025-12-28   |
+    When I run process with flags:
+      | flag       | value    |
+      | --start    | 00:05:30 |
+      | --end      | 01:45:00 |
+      | --minister | smith    |
+      | --recipient| jane     |
+    Then the process should fail with error "(2025-12-28) was already processed on"
+    And the process should fail with error "according to history"
+    And the process error code should be "ALREADY_PROCESSED" with exit code 20
+
+  Scenario: A recording history has not seen is processed in auto-detect mode
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the history contains services:
+      | date       | minister       | source_file             |
+      | 2025-12-28 | Pr. John Smith | 2025-12-28 08-00-00.mp4 |
+    When I run process with flags:
+      | flag       | value    |
+      | --start    | 00:05:30 |
+      | --end      | 01:45:00 |
+      | --minister | smith    |
+      | --recipient| jane     |
+    Then the process should succeed
 
   Scenario: Process partial upload when only mp3 exists in Drive
     Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
@@ -321,3 +977,677 @@ Feature: End-to-End Process Command
       | --minister | smith    |
       | --recipient| jane     |
     Then the process should fail with error "failed to check Drive"
+
+  Scenario: Skip already-processed service whose Drive files were renamed
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And drive has files tagged with service date "2025-12-28":
+      | name                  | mimeType   |
+      | Christmas Service.mp4 | video/mp4  |
+      | Christmas Service.mp3 | audio/mpeg |
+    When I run process with flags:
+      | flag       | value    |
+      | --start    | 00:05:30 |
+      | --end      | 01:45:00 |
+      | --minister | smith    |
+      | --recipient| jane     |
+    Then the process should fail with error "has already been processed"
+
+  Scenario: Name-based check ignores renamed Drive files
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the processed check strategy is "name"
+    And drive has files tagged with service date "2025-12-28":
+      | name                  | mimeType   |
+      | Christmas Service.mp4 | video/mp4  |
+      | Christmas Service.mp3 | audio/mpeg |
+    When I run process with flags:
+      | flag       | value    |
+      | --start    | 00:05:30 |
+      | --end      | 01:45:00 |
+      | --minister | smith    |
+      | --recipient| jane     |
+    Then the process should succeed
+
+  Scenario: Uploaded files are tagged with the service date
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag       | value    |
+      | --start    | 00:05:30 |
+      | --end      | 01:45:00 |
+      | --minister | smith    |
+      | --recipient| jane     |
+    Then the process should succeed
+    And uploaded files should be tagged with service date "2025-12-28"
+
+  Scenario: Process the recording handed off by OBS
+    Given a source video exists at "/test/source/2025-12-21 10-00-00.mp4"
+    And OBS is recording to "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag        | value    |
+      | --from-obs  |          |
+      | --start     | 00:05:30 |
+      | --end       | 01:45:00 |
+      | --minister  | smith    |
+      | --recipient | jane     |
+    Then the process should succeed
+    And the OBS recording should have been stopped
+    And the source path should be "/test/source/2025-12-28 10-06-16.mp4"
+    And the service date should be "2025-12-28"
+    And the output should include "Recording saved:"
+
+  Scenario: Wait for the operator to stop the OBS recording
+    Given OBS is recording to "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag        | value    |
+      | --from-obs  |          |
+      | --obs-wait  |          |
+      | --start     | 00:05:30 |
+      | --end       | 01:45:00 |
+      | --minister  | smith    |
+      | --recipient | jane     |
+    Then the process should succeed
+    And the OBS recording should have been waited for
+    And the output should include "Waiting for the recording to stop"
+
+  Scenario: OBS hand-off fails when nothing is recording
+    Given OBS is not recording
+    When I run process with flags:
+      | flag        | value    |
+      | --from-obs  |          |
+      | --start     | 00:05:30 |
+      | --end       | 01:45:00 |
+      | --recipient | jane     |
+    Then the process should fail with error "no recording is in progress"
+
+  Scenario: Mirror outputs for recipients without Drive access
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a mirror download server at "https://files.example.org/services"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And "2025-12-28.mp4" should be published to the mirror with its checksum
+    And "2025-12-28.mp3" should be published to the mirror with its checksum
+    And the output should include "Mirror link: https://files.example.org/services/2025-12-28.mp3"
+    And email should include video and audio links
+    And email should include "https://files.example.org/services/2025-12-28.mp3"
+    And email should include "https://files.example.org/services/2025-12-28.mp4"
+
+  Scenario: Mirror failure does not block the email
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a mirror download server at "https://files.example.org/services"
+    And the mirror download server will fail with "connection refused"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "could not publish to mirror"
+    And the output should include "nac-service-media publish --date 2025-12-28"
+    And email should include video and audio links
+    And email should not include "files.example.org"
+
+  Scenario: Completed run is recorded in history
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the history should record "2025-12-28" with 2 recipients and duration 5970 seconds
+    When I export history as "csv"
+    Then the export should contain 1 service
+    And the export should include "2025-12-28,Pr. John Smith,01:39:30"
+
+  Scenario: Upload a special event to another folder
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    And the process config includes the folder link in emails
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --folder-id | convention2025                       |
+    Then the process should succeed
+    And uploaded files should be in folder "convention2025"
+    And the output should include "Folder link: https://drive.google.com/drive/folders/convention2025"
+    And email should include "Previous services: https://drive.google.com/drive/folders/convention2025"
+    And the history for "2025-12-28" should record folder "convention2025"
+
+  Scenario: The configured folder is not recorded in history
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And uploaded files should be in folder "folder123"
+    And the history for "2025-12-28" should record folder ""
+
+  Scenario: Recovery commands keep the overridden folder
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the drive upload will fail with "authentication expired"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --folder-id | convention2025                       |
+    Then the process should fail with error "authentication expired"
+    And the output should include "--folder-id convention2025"
+
+  Scenario: Detection confidence is recorded and drift is flagged
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the history contains services:
+      | date       | confidence |
+      | 2025-12-14 | 0.89       |
+      | 2025-12-21 | 0.88       |
+    And the start was detected with confidence 0.87
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the history for "2025-12-28" should have detection confidence 0.87
+    And the output should include "Warning: start detection scores have been below 90% for 3 services in a row."
+    And the output should include "nac-service-media detect stats"
+
+  Scenario: An early detection exit is recorded in history
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    And the start was detected early with confidence 0.97
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the history for "2025-12-28" should have detection confidence 0.97
+    And the history for "2025-12-28" should record an early detection exit
+
+  Scenario: Notes given to process are recorded in history
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --note      | organ mic buzzing                    |
+      | --note      | camera 2 dropped frames              |
+    Then the process should succeed
+    And the history for "2025-12-28" should have notes "organ mic buzzing; camera 2 dropped frames"
+    And the output should include "Notes:"
+    And the output should include "  - organ mic buzzing"
+
+  Scenario: Sermon title and scripture are tagged, emailed and recorded
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --title     | The Good Shepherd                    |
+      | --scripture | John 10:11-16                        |
+    Then the process should succeed
+    And the trimmed video and audio should be tagged "The Good Shepherd" with scripture "John 10:11-16"
+    And email should include "Sermon: The Good Shepherd (John 10:11-16)"
+    And the history for "2025-12-28" should have title "The Good Shepherd" and scripture "John 10:11-16"
+
+  Scenario: Sandbox sends the email only to the operator
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --sandbox   |                                      |
+    Then the process should succeed
+    And email should be sent to "church@example.com"
+    And email should not include "jane@example.com"
+    And email should not include "admin@example.com"
+    And email should include "[TEST]"
+    And the output should include "Sandbox: sending only to Test Church <church@example.com>"
+    And the output should not include "Sent to: Jane Doe"
+
+  Scenario: Run summary is archived in Markdown and HTML
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And run summaries are archived in a temporary directory
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+      | --note      | organ mic buzzing                    |
+    Then the process should succeed
+    And the output should include "Summary: "
+    And the run summary "2025-12-28.md" should include "# Test Church: Service Recording 2025-12-28"
+    And the run summary "2025-12-28.md" should include "| Trim range | 00:05:30 to 01:45:00 |"
+    And the run summary "2025-12-28.md" should include "| Upload video |"
+    And the run summary "2025-12-28.md" should include "- [Video](https://drive.google.com/"
+    And the run summary "2025-12-28.md" should include "**To:** Jane Doe <jane@example.com>"
+    And the run summary "2025-12-28.md" should include "- organ mic buzzing"
+    And the run summary "2025-12-28.html" should include "<h1>Test Church: Service Recording 2025-12-28</h1>"
+
+  Scenario: Run summary in Markdown only for an audio-only run
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And run summaries are archived in a temporary directory
+    And the summary formats are "markdown"
+    When I run process with flags:
+      | flag         | value                                |
+      | --input      | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start      | 00:05:30                             |
+      | --end        | 01:45:00                             |
+      | --recipient  | jane                                 |
+      | --skip-video |                                      |
+    Then the process should succeed
+    And the run summary "2025-12-28.md" should include "| Mode | Audio only |"
+    And the run summary "2025-12-28.md" should include "- [Audio](https://drive.google.com/"
+    And the run summary "2025-12-28.md" should not include "- [Video]"
+    And the run summary "2025-12-28.html" should not exist
+
+  Scenario: No run summary is written when a step fails
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And run summaries are archived in a temporary directory
+    And sending the email will fail with "quota exceeded"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should fail with error "quota exceeded"
+    And the run summary "2025-12-28.md" should not exist
+
+  Scenario: Saturday-evening recording named on a UTC clock keeps Saturday's date
+    Given a source video exists at "/test/source/2025-12-28 01-30-00.mp4"
+    And the service timezone is "America/New_York" and recordings are named in "UTC"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 01-30-00.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "Service date: 2025-12-27"
+    And the output should include "2025-12-27.mp4"
+
+  Scenario: Without a timezone the recording's own date is used
+    Given a source video exists at "/test/source/2025-12-28 01-30-00.mp4"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 01-30-00.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "Service date: 2025-12-28"
+
+  Scenario: An unknown service timezone is rejected
+    Given a source video exists at "/test/source/2025-12-28 01-30-00.mp4"
+    And the service timezone is "Mars/Olympus_Mons" and recordings are named in "UTC"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 01-30-00.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should fail with error "unknown timezone"
+
+  Scenario: Failed run is not recorded in history
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And a history store
+    And sending the email will fail with "quota exceeded"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+    Then the process should fail with error "quota exceeded"
+    And the history should be empty
+
+  Scenario: Audio track selection keeps only that track in the trimmed video
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag          | value                                |
+      | --input       | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start       | 00:05:30                             |
+      | --end         | 01:45:00                             |
+      | --minister    | smith                                |
+      | --recipient   | jane                                 |
+      | --audio-track | 2                                    |
+    Then the process should succeed
+    And the trimmed video should keep audio track 2
+    And the audio should be extracted from audio track 0
+
+  Scenario: Audio track defaults to config in skip video mode
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config has audio track 2
+    When I run process with flags:
+      | flag         | value                                |
+      | --input      | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start      | 00:05:30                             |
+      | --end        | 01:45:00                             |
+      | --minister   | smith                                |
+      | --recipient  | jane                                 |
+      | --skip-video |                                      |
+    Then the process should succeed
+    And the audio should be extracted from audio track 2
+
+  Scenario: Extra audio bitrates are uploaded and listed in the email
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config makes audio at bitrates "192k, 64k, 32k"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the audio should be extracted at bitrates "192k, 64k, 32k"
+    And the output should include "2025-12-28-64k.mp3 (64k)"
+    And the output should include "Uploaded: 2025-12-28-64k.mp3"
+    And the output should include "Uploaded: 2025-12-28-32k.mp3"
+    And email should include "Other audio versions:"
+    And email should include ">64k, 81.1 MB</a>"
+    And email should include "32k, 81.1 MB: https://drive.google.com/"
+
+  Scenario: Extra audio bitrates in skip video mode
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config makes audio at bitrates "128k, 64k"
+    When I run process with flags:
+      | flag           | value                                |
+      | --input        | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start        | 00:05:30                             |
+      | --end          | 01:45:00                             |
+      | --recipient    | jane                                 |
+      | --skip-video   |                                      |
+      | --stream-audio |                                      |
+    Then the process should succeed
+    And the output should include "Extra audio bitrates are made from one extraction; extracting to files first"
+    And the audio should not be streamed to Drive
+    And the audio should be extracted at bitrates "128k, 64k"
+    And the output should include "Uploaded: 2025-12-28-64k.mp3"
+    And email should include "64k, 81.1 MB: https://drive.google.com/"
+
+  Scenario: A failed extra bitrate upload leaves it out of the email
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config makes audio at bitrates "192k, 64k"
+    And the drive upload of "2025-12-28-64k.mp3" will fail with "quota exceeded"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "Warning: 2025-12-28-64k.mp3 was not uploaded and is left out of the email"
+    And email should not include "Other audio versions"
+
+  Scenario: Configured watermark is drawn on the trimmed video
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config draws the watermark "{date} - {title}"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --title     | The Good Shepherd                    |
+    Then the process should succeed
+    And the trimmed video should carry the watermark "December 28, 2025 - The Good Shepherd"
+    And the output should include "Watermark: December 28, 2025 - The Good Shepherd (re-encoding"
+
+  Scenario: Recovery commands repeat the audio track
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And trimming will fail with "unknown stream 0:a:1"
+    When I run process with flags:
+      | flag          | value                                |
+      | --input       | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start       | 00:05:30                             |
+      | --end         | 01:45:00                             |
+      | --minister    | smith                                |
+      | --recipient   | jane                                 |
+      | --audio-track | 2                                    |
+    Then the process should fail with error "unknown stream 0:a:1"
+    And the output should include "--start 00:05:30 --end 01:45:00 --audio-track 2"
+
+  # Non-interactive mode (cron/watch)
+  Scenario: Non-interactive run stops instead of asking to overwrite
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And an earlier output exists at "/test/trimmed/2025-12-28.mp4"
+    When I run process with flags:
+      | flag              | value                                |
+      | --input           | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start           | 00:05:30                             |
+      | --end             | 01:45:00                             |
+      | --recipient       | jane                                 |
+      | --on-existing     | prompt                               |
+      | --non-interactive |                                      |
+    Then the process should stop for input with reason "overwrite_confirmation"
+    And the process should fail with error "choose --on-existing overwrite, skip or version"
+    And the video should not be uploaded to Drive
+
+  Scenario: Non-interactive run uses the configured action for existing outputs
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And an earlier output exists at "/test/trimmed/2025-12-28.mp4"
+    When I run process with flags:
+      | flag              | value                                |
+      | --input           | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start           | 00:05:30                             |
+      | --end             | 01:45:00                             |
+      | --recipient       | jane                                 |
+      | --on-existing     | skip                                 |
+      | --non-interactive |                                      |
+    Then the process should succeed
+    And the output should include "Reused existing: "
+    And email should be sent to "jane@example.com"
+
+  Scenario: CC a group and a one-off address without copying the recipient twice
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config has a recipient group "does" with members "jane, john"
+    When I run process with flags:
+      | flag        | value                                                     |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4                      |
+      | --start     | 00:05:30                                                  |
+      | --end       | 01:45:00                                                  |
+      | --recipient | jane                                                      |
+      | --cc        | does, Guest Pastor <guest@example.org>, admin@example.com |
+    Then the process should succeed
+    And email should include "Cc: Admin User <admin@example.com>, John Doe <john@example.com>, Guest Pastor <guest@example.org>"
+
+  Scenario: Non-interactive run refuses an ambiguous CC
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag              | value                                |
+      | --input           | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start           | 00:05:30                             |
+      | --end             | 01:45:00                             |
+      | --recipient       | jane                                 |
+      | --cc              | doe                                  |
+      | --non-interactive |                                      |
+    Then the process should stop for input with reason "ambiguous_recipient"
+    And the process should fail with error "it matches 2 recipients"
+    And the video should not be trimmed
+
+  Scenario: Ambiguous recipient is reported as ambiguous
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | doe                                  |
+    Then the process should fail with error "multiple recipients match"
+    And the process should fail with error "matches Jane Doe, John Doe"
+
+  Scenario: A stretched recording is flagged before upload
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process source video is 720x480
+    And recordings are expected to be 1920x1080
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should succeed
+    And the output should include "Warning: video is 720x480, expected 1920x1080"
+    And the output should include "Warning: display aspect is 3:2, expected 16:9"
+    And the video should be uploaded to Drive
+
+  Scenario: A stretched recording stops the run with --strict
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process source video is 720x480
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --strict    |                                      |
+    Then the process should fail with error "display aspect is 3:2, expected 16:9"
+    And the process should fail with error "Check the OBS output resolution"
+    And the video should not be trimmed
+    And the video should not be uploaded to Drive
+
+  Scenario: A rotated recording stops the run when the config is strict
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process source video is 1920x1080 rotated 90 degrees
+    And mismatched recordings stop processing
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+    Then the process should fail with error "video is portrait, shown at 1080x1920"
+    And the video should not be trimmed
+
+  Scenario: A correctly sized recording is not flagged
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process source video is 1920x1080
+    And recordings are expected to be 1920x1080
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --strict    |                                      |
+    Then the process should succeed
+    And the output should not include "Warning: video"
+
+  Scenario: A quick run takes everything else from the defaults
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config has senders:
+      | key  | name      |
+      | mary | Mary Jones |
+    And the process config has defaults:
+      | minister       | smith    |
+      | recipients     | jane     |
+      | service_length | 01:40:00 |
+      | sender         | mary     |
+    When I run process with flags:
+      | flag    | value                                |
+      | --input | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start | 00:05:30                             |
+      | --quick |                                      |
+    Then the process should succeed
+    And the output should include "Date:       Sunday, 12/28/2025"
+    And the output should include "End:        +01:40:00 (defaults.service_length after the start)"
+    And the output should include "Minister:   Pr. John Smith (smith)"
+    And the output should include "To:         Jane Doe"
+    And the output should include "Sender:     Mary Jones (mary)"
+    And the video should be trimmed from "00:05:30" to "01:45:30"
+    And email should be sent to "jane@example.com"
+    And email should include minister "Pr. John Smith"
+    And email should include "Mary Jones"
+
+  Scenario: A quick run uses the minister scheduled for the service date
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config has ministers:
+      | key   | name          |
+      | jones | Pr. Tom Jones |
+    And the process config has defaults:
+      | minister   | smith |
+      | recipients | jane  |
+    And the process config schedules minister "jones" on "2025-12-28"
+    When I run process with flags:
+      | flag    | value                                |
+      | --input | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start | 00:05:30                             |
+      | --end   | 01:45:00                             |
+      | --quick |                                      |
+    Then the process should succeed
+    And email should include minister "Pr. Tom Jones"
+
+  Scenario: Flags given with --quick beat the defaults
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config has defaults:
+      | recipients     | jane     |
+      | service_length | 01:40:00 |
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:30:00                             |
+      | --recipient | john                                 |
+      | --quick     |                                      |
+    Then the process should succeed
+    And the output should include "Minister:   none"
+    And the video should be trimmed from "00:05:30" to "01:30:00"
+    And email should be sent to "john@example.com"
+    And email should not be sent to "jane@example.com"
+
+  Scenario: Saying no to the quick summary stops before anything is processed
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    And the process config has defaults:
+      | recipients     | jane     |
+      | service_length | 01:40:00 |
+    And the operator answers the checkpoints with "no"
+    When I run process with flags:
+      | flag    | value                                |
+      | --input | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start | 00:05:30                             |
+      | --quick |                                      |
+    Then the process should fail with error "quick run: stopped at a checkpoint"
+    And the video should not be trimmed
+    And the process should not send an email
+
+  Scenario: A quick run needs recipients from somewhere
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag    | value                                |
+      | --input | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start | 00:05:30                             |
+      | --end   | 01:45:00                             |
+      | --quick |                                      |
+    Then the process should fail with error "--quick needs defaults.recipients in config, or --recipient"
@@ -0,0 +1,76 @@
+Feature: Finding people across the config
+  As an operator sending service emails
+  I want names to resolve wherever the person is defined
+  So that --to mary works even when Mary is only a default CC
+
+  Background:
+    Given a config file exists with initial data
+
+  Scenario: Recipients are only looked up among recipients by default
+    Given cc exists with name "Mary Jones" and email "mary@example.com"
+    When I look up the recipient "mary"
+    Then the command should fail with "recipient not found"
+
+  Scenario: A default CC is found when lookup across all entries is enabled
+    Given cc exists with name "Mary Jones" and email "mary@example.com"
+    And lookup across all entries is enabled
+    When I look up the recipient "mary"
+    Then the command should succeed
+    And the output should contain "Mary Jones <mary@example.com>"
+
+  Scenario: A recipient takes precedence over a CC with the same name
+    Given recipient "mary" exists with name "Mary Smith" and email "mary.smith@example.com"
+    And cc exists with name "Mary Jones" and email "mary@example.com"
+    And lookup across all entries is enabled
+    When I look up the recipient "mary"
+    Then the command should succeed
+    And the output should contain "Mary Smith <mary.smith@example.com>"
+    And the output should not contain "Mary Jones"
+
+  Scenario: A CC listed twice resolves to one person
+    Given cc exists with name "Mary Jones" and email "mary@example.com"
+    And cc exists with name "Mary Jones" and email "mary@example.com"
+    And lookup across all entries is enabled
+    When I look up the recipients "mary, jones"
+    Then the command should succeed
+    And the output should contain "Mary Jones <mary@example.com>"
+
+  Scenario: Two CCs with the same first name are ambiguous
+    Given cc exists with name "Mary Jones" and email "mary@example.com"
+    And cc exists with name "Mary Brown" and email "mbrown@example.com"
+    And lookup across all entries is enabled
+    When I look up the recipient "mary"
+    Then the command should fail with "matches Mary Brown, Mary Jones"
+
+  Scenario: A sender is found by name when lookup across all entries is enabled
+    Given sender "avteam" exists with name "White Plains A/V Team"
+    And lookup across all entries is enabled
+    When I look up the sender "White Plains A/V Team"
+    Then the command should succeed
+    And the output should contain "White Plains A/V Team (avteam)"
+
+  Scenario: A sender is only found by key by default
+    Given sender "avteam" exists with name "White Plains A/V Team"
+    When I look up the sender "White Plains A/V Team"
+    Then the command should fail with "sender not found"
+
+  Scenario: Show everywhere a person is defined
+    Given recipient "mary" exists with name "Mary Jones" and email "mary@example.com"
+    And cc exists with name "Mary Jones" and email "mary@example.com"
+    And sender "avteam" exists with name "A/V Team"
+    When I run config find "mary"
+    Then the command should succeed
+    And the output should contain "recipient"
+    And the output should contain "cc"
+    And the output should not contain "sender"
+
+  Scenario: Find by email address
+    Given cc exists with name "Mary Jones" and email "mary@example.com"
+    When I run config find "MARY@example.com"
+    Then the command should succeed
+    And the output should contain "Mary Jones"
+
+  Scenario: Find reports when nothing matches
+    When I run config find "nobody"
+    Then the command should succeed
+    And the output should contain "Nothing in the config matches"
@@ -0,0 +1,61 @@
+Feature: S3-Compatible Storage
+  As a media coordinator
+  I want to upload services to the district's MinIO server instead of Google Drive
+  So that recordings stay on storage the district manages
+
+  Background:
+    Given an S3 bucket "media" storing under "services/"
+
+  Scenario: Upload outputs to the bucket with presigned links
+    Given the local outputs "2025-12-28.mp4" and "2025-12-28.mp3" for S3
+    When I upload the outputs to S3
+    Then the S3 upload should succeed
+    And the bucket should hold "services/2025-12-28.mp4"
+    And the bucket should hold "services/2025-12-28.mp3"
+    And the S3 upload output should contain "/media/services/2025-12-28.mp3?"
+    And the S3 upload output should contain "X-Amz-Expires=604800"
+    And the S3 upload output should not contain "drive.google.com"
+
+  Scenario: Presigned links expire when configured
+    Given the S3 links expire after 48 hours
+    And the local outputs "2025-12-28.mp4" and "2025-12-28.mp3" for S3
+    When I upload the outputs to S3
+    Then the S3 upload should succeed
+    And the S3 upload output should contain "X-Amz-Expires=172800"
+
+  Scenario: Links from a public bucket
+    Given the S3 bucket is served publicly at "https://media.example.org"
+    And the local outputs "2025-12-28.mp4" and "2025-12-28.mp3" for S3
+    When I upload the outputs to S3
+    Then the S3 upload should succeed
+    And the S3 upload output should contain "https://media.example.org/services/2025-12-28.mp3"
+    And the S3 upload output should not contain "X-Amz-"
+
+  Scenario: A re-upload replaces the object
+    Given the bucket holds "services/2025-12-28.mp3" of 10 bytes
+    And the local outputs "2025-12-28.mp4" and "2025-12-28.mp3" for S3
+    When I upload the outputs to S3
+    Then the S3 upload should succeed
+    And the S3 upload output should contain "Replacing existing 2025-12-28.mp3"
+    And the bucket should hold "services/2025-12-28.mp3"
+
+  Scenario: A bucket without a quota always has room
+    Given the bucket holds "services/2024-01-07.mp4" of 100 bytes
+    When I make room in S3 for "2GB"
+    Then the S3 cleanup should succeed
+    And the bucket should hold "services/2024-01-07.mp4"
+
+  Scenario: A bucket quota frees space by deleting the oldest videos
+    Given the S3 bucket quota is "250B"
+    And the bucket holds "services/2024-01-07.mp4" of 100 bytes
+    And the bucket holds "services/2024-01-14.mp4" of 100 bytes
+    When I make room in S3 for "120B"
+    Then the S3 cleanup should succeed
+    And the bucket should not hold "services/2024-01-07.mp4"
+    And the bucket should hold "services/2024-01-14.mp4"
+
+  Scenario: Access denied by the server
+    Given the S3 server denies access
+    And the local outputs "2025-12-28.mp4" and "2025-12-28.mp3" for S3
+    When I upload the outputs to S3
+    Then the S3 upload should fail with "403 Forbidden"
@@ -0,0 +1,82 @@
+Feature: Version and Self-Update
+  As a volunteer running the tool on a church PC
+  I want to update to the latest release from the command line
+  So that I'm not stuck on an old build
+
+  Background:
+    Given the running version is "v1.0.0"
+    And the following releases are published:
+      | version       | prerelease |
+      | v1.2.0-beta.1 | true       |
+      | v1.1.0        | false      |
+      | v1.0.0        | false      |
+
+  Scenario: Check reports an available update without installing
+    When I run self-update with "--check"
+    Then self-update should succeed
+    And the self-update output should include "Latest:  v1.1.0"
+    And the self-update output should include "Update available"
+    And the installed binary should be unchanged
+
+  Scenario: Install the latest stable release
+    When I run self-update
+    Then self-update should succeed
+    And the installed binary should contain "binary v1.1.0"
+    And the self-update output should include "Updated to v1.1.0"
+
+  Scenario: Beta channel includes prereleases
+    When I run self-update on the "beta" channel
+    Then self-update should succeed
+    And the installed binary should contain "binary v1.2.0-beta.1"
+
+  Scenario: Already on the latest release
+    Given the running version is "v1.1.0"
+    When I run self-update
+    Then self-update should succeed
+    And the self-update output should include "Already up to date"
+    And the installed binary should be unchanged
+
+  Scenario: Check with --force on the latest release reports no update
+    Given the running version is "v1.1.0"
+    When I run self-update with "--check --force"
+    Then self-update should succeed
+    And the self-update output should include "Already up to date"
+    And the self-update output should not include "Update available"
+    And the installed binary should be unchanged
+
+  Scenario: Checksum mismatch aborts the update
+    Given release "v1.1.0" has a corrupted checksum
+    When I run self-update
+    Then self-update should fail with error "checksum mismatch"
+    And the installed binary should be unchanged
+
+  Scenario: Signed release is verified with the configured public key
+    Given release signing is configured
+    When I run self-update
+    Then self-update should succeed
+    And the installed binary should contain "binary v1.1.0"
+
+  Scenario: Invalid signature aborts the update
+    Given release signing is configured
+    And release "v1.1.0" has an invalid signature
+    When I run self-update
+    Then self-update should fail with error "signature verification failed"
+    And the installed binary should be unchanged
+
+  Scenario: Development builds require --force
+    Given the running version is "dev"
+    When I run self-update
+    Then self-update should fail with error "development build"
+    And the installed binary should be unchanged
+
+  Scenario: Builds from an untagged commit are development builds
+    Given the running version is "v1.0.0-3-gabc1234-dirty"
+    When I run self-update
+    Then self-update should fail with error "development build"
+    And the installed binary should be unchanged
+
+  Scenario: Self-update disabled in managed environments
+    Given self-update is disabled in config
+    When I run self-update
+    Then self-update should fail with error "self-update is disabled"
+    And the installed binary should be unchanged
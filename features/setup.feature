@@ -70,3 +70,87 @@ Feature: Configuration Setup
       | Add a quick-lookup recipient  | n                        |
     Then a config file should exist
     And the config should have source_directory "/new/recordings"
+
+  Scenario: Optional sections are skipped by default
+    Given no config file exists for setup
+    When I run the setup command with inputs:
+      | prompt                        | value                    |
+      | OBS recordings                | /tmp/recordings          |
+      | trimmed videos                | /tmp/trimmed             |
+      | audio files                   | /tmp/audio               |
+      | audio bitrate                 | 192k                     |
+      | credentials file              | credentials.json         |
+      | folder ID                     | test-folder-id           |
+      | from name                     | Test Church              |
+      | from address                  | test@example.com         |
+    Then a config file should exist
+    And the config should have email service_type ""
+    And the config should have default sender ""
+    And the config should have detection disabled
+
+  Scenario: Add ministers, senders, service type and detection
+    Given no config file exists for setup
+    When I run the setup command with inputs:
+      | prompt                        | value                      |
+      | OBS recordings                | /tmp/recordings            |
+      | trimmed videos                | /tmp/trimmed               |
+      | audio files                   | /tmp/audio                 |
+      | audio bitrate                 | 192k                       |
+      | credentials file              | credentials.json           |
+      | folder ID                     | test-folder-id             |
+      | from name                     | Test Church                |
+      | from address                  | test@example.com           |
+      | Add a CC recipient            | n                          |
+      | Add a quick-lookup recipient  | n                          |
+      | Add a minister                | y                          |
+      | Key                           | Smith                      |
+      | Full name                     | Pr. John Smith             |
+      | Add a minister                | n                          |
+      | Add a sender                  | y                          |
+      | Key                           | jane                       |
+      | Full name                     | Jane Doe                   |
+      | Add a sender                  | y                          |
+      | Key                           | bob                        |
+      | Full name                     | Bob Jones                  |
+      | Add a sender                  | n                          |
+      | Select default sender         | jane                       |
+      | Select service type           | Divine Service             |
+      | Enable detection              | y                          |
+      | Cross template directory      | /opt/nac/detection         |
+      | Amen template directory       | /opt/nac/audio             |
+      | Search from minute            | 2                          |
+      | Search up to minute           | 25                         |
+    Then a config file should exist
+    And the config should have minister "smith" named "Pr. John Smith"
+    And the config should have sender "jane" named "Jane Doe"
+    And the config should have sender "bob" named "Bob Jones"
+    And the config should have default sender "jane"
+    And the config should have email service_type "Divine Service"
+    And the config should have detection enabled with templates in "/opt/nac/detection"
+    And the config should have a detection search range of 2 to 25 minutes
+
+  Scenario: A single sender becomes the default and a custom service type is typed in
+    Given no config file exists for setup
+    When I run the setup command with inputs:
+      | prompt                        | value                    |
+      | OBS recordings                | /tmp/recordings          |
+      | trimmed videos                | /tmp/trimmed             |
+      | audio files                   | /tmp/audio               |
+      | audio bitrate                 | 192k                     |
+      | credentials file              | credentials.json         |
+      | folder ID                     | test-folder-id           |
+      | from name                     | Test Church              |
+      | from address                  | test@example.com         |
+      | Add a CC recipient            | n                        |
+      | Add a quick-lookup recipient  | n                        |
+      | Add a minister                | n                        |
+      | Add a sender                  | y                        |
+      | Key                           | jane                     |
+      | Full name                     | Jane Doe                 |
+      | Add a sender                  | n                        |
+      | Select service type           | Other...                 |
+      | Service type                  | Harvest Festival         |
+    Then a config file should exist
+    And the config should have default sender "jane"
+    And the config should have email service_type "Harvest Festival"
+    And the config should have detection disabled
@@ -0,0 +1,61 @@
+Feature: Scanning outputs before they are shared publicly
+  As a church following the district's data-protection guidance
+  I want a configured scanner to check each file before it is made public
+  So that nothing that fails the scan is ever shared
+
+  Background:
+    Given the Services folder ID is "test-folder-id"
+    And valid Google Drive upload credentials
+
+  Scenario: A file that passes the scan is shared
+    Given a pre-share scanner that passes clean files
+    And I have a video file at "/tmp/2025-12-28.mp4"
+    When I upload the video to the Services folder
+    Then the upload should succeed
+    And the uploaded file should be shared publicly
+
+  Scenario: A file that fails the scan is uploaded but not shared
+    Given a pre-share scanner that reports "Eicar-Signature FOUND"
+    And I have a video file at "/tmp/2025-12-28.mp4"
+    When I upload the video to the Services folder
+    Then the upload should succeed
+    And the uploaded file should not be shared publicly
+    And the upload should be marked as sharing pending
+    And the upload output should contain "did not pass the pre-share scan"
+    And the upload output should contain "Eicar-Signature FOUND"
+
+  Scenario: A scan that runs past its timeout blocks sharing
+    Given a pre-share scanner that takes longer than its timeout
+    And I have an audio file at "/tmp/2025-12-28.mp3"
+    When I upload the audio to the Services folder
+    Then the upload should succeed
+    And the uploaded file should not be shared publicly
+    And the upload output should contain "timed out after 100ms"
+
+  Scenario: A scanner's own clean exit code is honored
+    Given a pre-share scanner that treats exit code 3 as clean
+    And I have an audio file at "/tmp/2025-12-28.mp3"
+    When I upload the audio to the Services folder
+    Then the upload should succeed
+    And the uploaded file should be shared publicly
+
+  Scenario: Re-sharing scans the local copies first
+    Given the Drive folder already contains:
+      | name             | mimeType   | size       |
+      | 2025-12-28.mp4   | video/mp4  | 1073741824 |
+      | 2025-12-28.mp3   | audio/mpeg | 89128960   |
+    And a pre-share scanner that passes clean files
+    And a local copy of "2025-12-28.mp4" is kept
+    And a local copy of "2025-12-28.mp3" is kept
+    When I share the files for "2025-12-28" after scanning them
+    Then the share should succeed
+    And the file "2025-12-28.mp4" should be shared publicly
+    And the file "2025-12-28.mp3" should be shared publicly
+
+  Scenario: Re-sharing refuses files with no local copy to scan
+    Given the Drive folder already contains:
+      | name             | mimeType   | size       |
+      | 2025-12-28.mp4   | video/mp4  | 1073741824 |
+    And a pre-share scanner that passes clean files
+    When I share the files for "2025-12-28" after scanning them
+    Then the share should fail with error "no local copy to scan"
@@ -0,0 +1,165 @@
+Feature: Simulated Process Failures
+  As a developer
+  I want to make a chosen process step fail on purpose
+  So that recovery output can be checked without breaking a real service
+
+  Background:
+    Given the process config has paths:
+      | source_directory  | /test/source    |
+      | trimmed_directory | /test/trimmed   |
+      | audio_directory   | /test/audio     |
+    And the process config has services folder "folder123"
+    And the process config has ministers:
+      | key   | name           |
+      | smith | Pr. John Smith |
+    And the process config has recipients:
+      | key  | name     | address          |
+      | jane | Jane Doe | jane@example.com |
+      | john | John Doe | john@example.com |
+    And the process config has default CCs:
+      | name       | address           |
+      | Admin User | admin@example.com |
+    And the process config has senders:
+      | key    | name    | default |
+      | avteam | A/V Team | yes    |
+
+  Scenario: A simulated upload failure stops before the upload and shows recovery
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag               | value                                |
+      | --input            | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start            | 00:05:30                             |
+      | --end              | 01:45:00                             |
+      | --minister         | smith                                |
+      | --recipient        | jane                                 |
+      | --simulate-failure | 4                                    |
+    Then the process should fail with error "simulated failure at step 4 (Upload video)"
+    And the output should include "Simulating a failure at step 4 (developer build)"
+    And the video should be trimmed from "00:05:30" to "01:45:00"
+    And the video should not be uploaded to Drive
+    And the output should include recovery commands
+    And the recovery should suggest "upload" command
+    And the recovery should suggest "send-email" command
+    And the process should not send an email
+
+  Scenario: A simulated email failure keeps the uploaded links for recovery
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag               | value                                |
+      | --input            | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start            | 00:05:30                             |
+      | --end              | 01:45:00                             |
+      | --minister         | smith                                |
+      | --recipient        | jane                                 |
+      | --simulate-failure | 7                                    |
+    Then the process should fail with error "simulated failure at step 7 (Send email)"
+    And the video should be uploaded to Drive
+    And the audio should be uploaded to Drive
+    And the output should include "Already uploaded:"
+    And the recovery email command should include minister "Pr. John Smith"
+    And the process should not send an email
+
+  Scenario: A simulated failure at a step that cannot fail is ignored
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag               | value                                |
+      | --input            | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start            | 00:05:30                             |
+      | --end              | 01:45:00                             |
+      | --minister         | smith                                |
+      | --recipient        | jane                                 |
+      | --simulate-failure | 6                                    |
+    Then the process should succeed
+    And email should be sent to "jane@example.com"
+
+  Scenario: Audio-only steps are numbered as shown in the output
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag               | value                                |
+      | --input            | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start            | 00:05:30                             |
+      | --end              | 01:45:00                             |
+      | --recipient        | jane                                 |
+      | --skip-video       |                                      |
+      | --simulate-failure | 3                                    |
+    Then the process should fail with error "simulated failure at step 3 (Upload audio)"
+    And the audio should not be uploaded to Drive
+    And the recovery should suggest "upload" command
+
+  Scenario: A simulated streaming failure falls back to a file upload
+    Given a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag               | value                                |
+      | --input            | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start            | 00:05:30                             |
+      | --end              | 01:45:00                             |
+      | --recipient        | jane                                 |
+      | --skip-video       |                                      |
+      | --stream-audio     |                                      |
+      | --simulate-failure | 2                                    |
+    Then the process should succeed
+    And the output should include "Streaming failed: simulated failure at step 2 (Extract and upload audio)"
+    And the output should include "Falling back to extracting to a file first"
+    And the audio should be uploaded to Drive
+
+  Scenario: A failed run picks up from its last completed step with --resume
+    Given process saves its progress for --resume
+    And a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag               | value                                |
+      | --input            | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start            | 00:05:30                             |
+      | --end              | 01:45:00                             |
+      | --minister         | smith                                |
+      | --recipient        | jane                                 |
+      | --simulate-failure | 5                                    |
+    Then the process should fail with error "simulated failure at step 5 (Upload audio)"
+    And the output should include "nac-service-media process --resume --date 2025-12-28"
+    When I resume the process run for "2025-12-28"
+    Then the process should succeed
+    And the output should include "Resuming the run saved at"
+    And the output should include "video trimmed, audio extracted, video uploaded"
+    And no process step should have run twice
+    And the audio should be uploaded to Drive
+    And email should be sent to "jane@example.com"
+    And no process run should be saved for "2025-12-28"
+
+  Scenario: Resuming an audio-only run skips the finished upload
+    Given process saves its progress for --resume
+    And a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag               | value                                |
+      | --input            | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start            | 00:05:30                             |
+      | --end              | 01:45:00                             |
+      | --recipient        | jane                                 |
+      | --skip-video       |                                      |
+      | --simulate-failure | 4                                    |
+    Then the process should fail with error "simulated failure at step 4 (Send email)"
+    When I resume the process run for "2025-12-28"
+    Then the process should succeed
+    And the output should include "Done in the earlier run"
+    And no process step should have run twice
+    And email should be sent to "jane@example.com"
+    And no process run should be saved for "2025-12-28"
+
+  Scenario: Resuming a streamed run does not upload the audio again
+    Given process saves its progress for --resume
+    And a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+    When I run process with flags:
+      | flag               | value                                |
+      | --input            | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start            | 00:05:30                             |
+      | --end              | 01:45:00                             |
+      | --recipient        | jane                                 |
+      | --skip-video       |                                      |
+      | --stream-audio     |                                      |
+      | --simulate-failure | 3                                    |
+    Then the process should fail with error "simulated failure at step 3 (Send email)"
+    And the streamed audio should not be saved locally
+    When I resume the process run for "2025-12-28"
+    Then the process should succeed
+    And the output should include "Done in the earlier run: 2025-12-28.mp3 (in Drive)"
+    And no process step should have run twice
+    And email should be sent to "jane@example.com"
+    And no process run should be saved for "2025-12-28"
@@ -0,0 +1,75 @@
+Feature: Source Recording Selection
+  As a user
+  I want process to notice a recording OBS is still writing
+  So that I don't trim a half-finished file
+
+  Background:
+    Given the source directory holds recordings "2025-12-21 10-05-00.mp4, 2025-12-28 10-06-16.mp4"
+
+  Scenario: Newest finished recording is selected
+    When I look for the newest recording
+    Then the recording "2025-12-28 10-06-16.mp4" should be selected
+
+  Scenario: A recording still in progress is an error by default
+    Given "2025-12-28 10-06-16.mp4" is still being recorded
+    When I look for the newest recording
+    Then finding the recording should fail with "2025-12-28 10-06-16.mp4 is still being recorded; stop the recording in OBS"
+
+  Scenario: Skip a recording still in progress
+    Given "2025-12-28 10-06-16.mp4" is still being recorded
+    And the in-progress policy is "skip"
+    When I look for the newest recording
+    Then the recording "2025-12-21 10-05-00.mp4" should be selected
+    And the finder output should include "Skipping 2025-12-28 10-06-16.mp4: it is still being recorded"
+
+  Scenario: Skip fails when every recording is in progress
+    Given "2025-12-28 10-06-16.mp4" is still being recorded
+    And "2025-12-21 10-05-00.mp4" is still being recorded
+    And the in-progress policy is "skip"
+    When I look for the newest recording
+    Then finding the recording should fail with "every video file is still being recorded"
+
+  Scenario: Wait for a recording to finish
+    Given "2025-12-28 10-06-16.mp4" finishes recording after 3 checks
+    And the in-progress policy is "wait"
+    When I look for the newest recording
+    Then the recording "2025-12-28 10-06-16.mp4" should be selected
+    And the finder output should include "Waiting for 2025-12-28 10-06-16.mp4 to finish recording"
+    And the finder output should include "Recording finished: 2025-12-28 10-06-16.mp4"
+
+  Scenario: Waiting gives up after the timeout
+    Given "2025-12-28 10-06-16.mp4" is still being recorded
+    And the in-progress policy is "wait"
+    When I look for the newest recording
+    Then finding the recording should fail with "was still being recorded after 1s"
+
+  Scenario: Reject an unknown policy
+    Given "2025-12-28 10-06-16.mp4" is still being recorded
+    And the in-progress policy is "ignore"
+    When I look for the newest recording
+    Then finding the recording should fail with "unknown in-progress policy"
+
+  Scenario: The first source directory with recordings wins
+    Given a lower-priority source directory holds recordings "2026-01-04 10-00-00.mp4"
+    When I look for the newest recording across the source directories
+    Then the recording "2025-12-28 10-06-16.mp4" should be selected
+    And the recording should come from the first source directory
+
+  Scenario: A lower-priority source directory is used when the first is empty
+    Given a lower-priority source directory holds recordings "2025-12-28 09-58-00.mp4"
+    And the source directory is emptied
+    When I look for the newest recording across the source directories
+    Then the recording "2025-12-28 09-58-00.mp4" should be selected
+    And the recording should come from the second source directory
+
+  Scenario: A recording in progress is not replaced by one from a lower-priority directory
+    Given a lower-priority source directory holds recordings "2025-12-28 09-58-00.mp4"
+    And "2025-12-28 10-06-16.mp4" is still being recorded
+    When I look for the newest recording across the source directories
+    Then finding the recording should fail with "2025-12-28 10-06-16.mp4 is still being recorded"
+
+  Scenario: Every source directory is empty
+    Given a lower-priority source directory holds recordings "notes.txt"
+    And the source directory is emptied
+    When I look for the newest recording across the source directories
+    Then finding the recording should fail with "no video files found in any source directory"
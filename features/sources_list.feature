@@ -0,0 +1,73 @@
+Feature: Source Listing
+  As a user
+  I want to list the recordings in my source directories with whether each was processed
+  So that I can spot Sundays that were missed
+
+  Background:
+    Given recordings "2025-09-28 10-01-00.mp4, 2025-10-05 10-02-00.mp4, 2025-10-12 10-00-30.mp4, 2025-10-19 09-59-00.mp4" are waiting in the source directory
+    And each recording is 5400 seconds long
+
+  Scenario: List recordings since a date with where each was processed
+    Given the trimmed output "2025-10-05.mp4" exists
+    And the history contains services:
+      | date       | minister       |
+      | 2025-10-05 | Pr. John Smith |
+      | 2025-10-12 | Pr. Jane Doe   |
+    When I list sources since "2025-10-01"
+    Then the sources listing should show "2025-10-05" as "local, history"
+    And the sources listing should show "2025-10-12" as "history"
+    And the sources listing should show "2025-10-19" as "no"
+    And the sources listing should include "01:30:00"
+    And the sources listing should include "1 of 3 recordings have not been processed"
+    And the sources listing should not include "2025-09-28"
+
+  Scenario: Audio alone counts as processed
+    Given the trimmed output "2025-10-19.mp3" exists
+    When I list sources from "2025-10-19" until "2025-10-19"
+    Then the sources listing should show "2025-10-19" as "local"
+    And the sources listing should include "0 of 1 recordings have not been processed"
+
+  Scenario: Only list the recordings still to process
+    Given the history contains services:
+      | date       | minister       |
+      | 2025-10-05 | Pr. John Smith |
+    When I list unprocessed sources since "2025-10-01"
+    Then the sources listing should show "2025-10-12" as "no"
+    And the sources listing should show "2025-10-19" as "no"
+    And the sources listing should not include "2025-10-05"
+    And the sources listing should include "2 of 3 recordings have not been processed"
+
+  Scenario: Check Drive for uploaded services
+    Given Drive already has the video and audio for "2025-10-12"
+    When I list sources since "2025-10-01" checking Drive
+    Then the sources listing should show "2025-10-12" as "drive"
+    And the sources listing should show "2025-10-05" as "no"
+
+  Scenario: Recordings across several source directories are listed by date
+    Given recordings "2025-10-26 10-00-00.mp4" are waiting in a second source directory
+    When I list sources since "2025-10-15"
+    Then the sources listing should show "2025-10-19" as "no"
+    And the sources listing should show "2025-10-26" as "no"
+    And "2025-10-19" should be listed before "2025-10-26"
+
+  Scenario: Recordings without a date are only listed without a range
+    Given recordings "rehearsal.mp4" are waiting in the source directory
+    When I list all sources
+    Then the sources listing should include "rehearsal.mp4"
+    And the sources listing should include "5 of 5 recordings have not been processed"
+    When I list sources since "2025-01-01"
+    Then the sources listing should not include "rehearsal.mp4"
+
+  Scenario: An unreadable source directory is skipped with a warning
+    Given a source directory that cannot be read
+    When I list sources since "2025-10-01"
+    Then the sources listing should include "Warning: skipping source directory"
+    And the sources listing should include "3 of 3 recordings have not been processed"
+
+  Scenario: Reject an invalid date
+    When I list sources since "October 1st"
+    Then listing sources should fail with "invalid --since date"
+
+  Scenario: Reject a range that ends before it starts
+    When I list sources from "2025-10-19" until "2025-10-01"
+    Then listing sources should fail with "--until date 2025-10-01 is before --since date 2025-10-19"
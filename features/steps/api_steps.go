@@ -0,0 +1,233 @@
+//go:build integration
+
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	appremote "nac-service-media/application/remote"
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/remote"
+	"nac-service-media/infrastructure/api"
+
+	"github.com/cucumber/godog"
+)
+
+// apiContext holds test state for remote control API scenarios
+type apiContext struct {
+	runner  *apiMockRunner
+	service *appremote.Service
+	server  *httptest.Server
+	status  int
+	body    string
+}
+
+var sharedAPIContext *apiContext
+
+func getAPIContext() *apiContext {
+	return sharedAPIContext
+}
+
+// apiMockRunner stands in for running this tool's commands
+type apiMockRunner struct {
+	mu       sync.Mutex
+	commands [][]string
+	failWith string
+	hold     chan struct{} // Commands wait for it to close when set
+}
+
+func (m *apiMockRunner) Run(ctx context.Context, args []string, output io.Writer) error {
+	m.mu.Lock()
+	m.commands = append(m.commands, args)
+	hold, failWith := m.hold, m.failWith
+	m.mu.Unlock()
+
+	fmt.Fprintf(output, "Running %s\n", args[0])
+	if hold != nil {
+		select {
+		case <-hold:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if failWith != "" {
+		return fmt.Errorf("%s", failWith)
+	}
+	fmt.Fprintln(output, "Done")
+	return nil
+}
+
+var _ remote.CommandRunner = (*apiMockRunner)(nil)
+
+func InitializeAPIScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedAPIContext = &apiContext{runner: &apiMockRunner{}}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if a := getAPIContext(); a != nil {
+			a.releaseCommands()
+			if a.server != nil {
+				a.server.Close()
+				a.service.Wait()
+			}
+		}
+		sharedAPIContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the remote control API is running with token "([^"]*)"$`, theRemoteControlAPIIsRunningWithToken)
+	ctx.Step(`^remote commands fail with "([^"]*)"$`, remoteCommandsFailWith)
+	ctx.Step(`^remote commands keep running$`, remoteCommandsKeepRunning)
+	ctx.Step(`^I (GET|POST) "([^"]*)" with token "([^"]*)"$`, iRequestWithToken)
+	ctx.Step(`^I (GET|POST) "([^"]*)" with token "([^"]*)" and body:$`, iRequestWithTokenAndBody)
+	ctx.Step(`^the API should respond with status (\d+)$`, theAPIShouldRespondWithStatus)
+	ctx.Step(`^the API response should include "([^"]*)"$`, theAPIResponseShouldInclude)
+	ctx.Step(`^the API response should not include "([^"]*)"$`, theAPIResponseShouldNotInclude)
+	ctx.Step(`^remote run "([^"]*)" should finish as "([^"]*)"$`, remoteRunShouldFinishAs)
+	ctx.Step(`^the remote command should be "([^"]*)"$`, theRemoteCommandShouldBe)
+	ctx.Step(`^no remote command should run$`, noRemoteCommandShouldRun)
+}
+
+func theRemoteControlAPIIsRunningWithToken(token string) error {
+	a := getAPIContext()
+	var store history.Store
+	if h := getHistoryContext(); h != nil && h.store != nil {
+		store = h.store
+	}
+	a.service = appremote.NewService(context.Background(), a.runner, store)
+	handler, err := api.NewHandler(a.service, token)
+	if err != nil {
+		return err
+	}
+	a.server = httptest.NewServer(handler)
+	return nil
+}
+
+func remoteCommandsFailWith(message string) error {
+	getAPIContext().runner.failWith = message
+	return nil
+}
+
+func remoteCommandsKeepRunning() error {
+	getAPIContext().runner.hold = make(chan struct{})
+	return nil
+}
+
+// releaseCommands lets held commands finish
+func (a *apiContext) releaseCommands() {
+	a.runner.mu.Lock()
+	defer a.runner.mu.Unlock()
+	if a.runner.hold != nil {
+		close(a.runner.hold)
+		a.runner.hold = nil
+	}
+}
+
+func iRequestWithToken(method, path, token string) error {
+	return getAPIContext().request(method, path, token, "")
+}
+
+func iRequestWithTokenAndBody(method, path, token string, body *godog.DocString) error {
+	return getAPIContext().request(method, path, token, body.Content)
+}
+
+func (a *apiContext) request(method, path, token, body string) error {
+	req, err := http.NewRequest(method, a.server.URL+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := a.server.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	a.status = resp.StatusCode
+	a.body = string(data)
+	return nil
+}
+
+func theAPIShouldRespondWithStatus(status int) error {
+	a := getAPIContext()
+	if a.status != status {
+		return fmt.Errorf("expected status %d, got %d: %s", status, a.status, a.body)
+	}
+	return nil
+}
+
+func theAPIResponseShouldInclude(text string) error {
+	a := getAPIContext()
+	if !strings.Contains(a.body, text) {
+		return fmt.Errorf("expected response to include %q, got: %s", text, a.body)
+	}
+	return nil
+}
+
+func theAPIResponseShouldNotInclude(text string) error {
+	a := getAPIContext()
+	if strings.Contains(a.body, text) {
+		return fmt.Errorf("expected response not to include %q, got: %s", text, a.body)
+	}
+	return nil
+}
+
+func remoteRunShouldFinishAs(id, state string) error {
+	a := getAPIContext()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		run, err := a.service.Run(id)
+		if err != nil {
+			return err
+		}
+		if run.State != remote.RunRunning {
+			if string(run.State) != state {
+				data, _ := json.Marshal(run)
+				return fmt.Errorf("expected run %s to finish as %s, got %s", id, state, data)
+			}
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("run %s is still running", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func theRemoteCommandShouldBe(command string) error {
+	r := getAPIContext().runner
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.commands) == 0 {
+		return fmt.Errorf("no command was run")
+	}
+	if got := strings.Join(r.commands[len(r.commands)-1], " "); got != command {
+		return fmt.Errorf("expected command %q, got %q", command, got)
+	}
+	return nil
+}
+
+func noRemoteCommandShouldRun() error {
+	r := getAPIContext().runner
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.commands) > 0 {
+		return fmt.Errorf("expected no command, got %q", r.commands)
+	}
+	return nil
+}
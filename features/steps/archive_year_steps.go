@@ -0,0 +1,236 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/drive"
+
+	googledrive "google.golang.org/api/drive/v3"
+
+	"github.com/cucumber/godog"
+)
+
+const archiveServicesFolderID = "test-services"
+
+// archiveYearContext holds test state for year archive scenarios
+type archiveYearContext struct {
+	service *drive.MemoryService
+	cfg     *config.Config
+	output  *bytes.Buffer
+	err     error
+}
+
+var sharedArchiveYearContext *archiveYearContext
+
+func getArchiveYearContext() *archiveYearContext {
+	return sharedArchiveYearContext
+}
+
+func InitializeArchiveYearScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedArchiveYearContext = &archiveYearContext{
+			service: drive.NewMemoryService(),
+			cfg: &config.Config{
+				Google: config.GoogleConfig{ServicesFolderID: archiveServicesFolderID},
+				Email:  config.EmailConfig{FromName: "Test Church"},
+			},
+			output: &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		sharedArchiveYearContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the Services folder to archive holds:$`, theServicesFolderToArchiveHolds)
+	ctx.Step(`^the leadership group "([^"]*)" has "([^"]*)"$`, theLeadershipGroupHas)
+	ctx.Step(`^I archive the year (\d+)$`, iArchiveTheYear)
+	ctx.Step(`^I preview archiving the year (\d+)$`, iPreviewArchivingTheYear)
+	ctx.Step(`^the archive should succeed$`, theArchiveShouldSucceed)
+	ctx.Step(`^the archive should fail with "([^"]*)"$`, theArchiveShouldFailWith)
+	ctx.Step(`^the archive output should include "([^"]*)"$`, theArchiveOutputShouldInclude)
+	ctx.Step(`^the folder "([^"]*)" should hold "([^"]*)"$`, theFolderShouldHold)
+	ctx.Step(`^the Services folder should still hold "([^"]*)"$`, theServicesFolderShouldStillHold)
+	ctx.Step(`^the index should include "([^"]*)"$`, theIndexShouldInclude)
+	ctx.Step(`^the folder "([^"]*)" should be shared with "([^"]*)"$`, theFolderShouldBeSharedWith)
+}
+
+func theServicesFolderToArchiveHolds(table *godog.Table) error {
+	a := getArchiveYearContext()
+	header := table.Rows[0].Cells
+	for _, row := range table.Rows[1:] {
+		var name string
+		props := map[string]string{}
+		for j, cell := range row.Cells {
+			switch header[j].Value {
+			case "name":
+				name = cell.Value
+			case "service_date":
+				if cell.Value != "" {
+					props[distribution.PropertyServiceDate] = cell.Value
+				}
+			}
+		}
+		if _, err := a.service.UploadReader(context.Background(), name, "video/mp4", archiveServicesFolderID, strings.NewReader("media"), props); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func theLeadershipGroupHas(name, members string) error {
+	a := getArchiveYearContext()
+	if a.cfg.Email.Recipients == nil {
+		a.cfg.Email.Recipients = make(map[string]config.RecipientConfig)
+	}
+	var keys []string
+	for _, key := range strings.Split(members, ",") {
+		key = strings.TrimSpace(key)
+		keys = append(keys, key)
+		a.cfg.Email.Recipients[key] = config.RecipientConfig{Name: strings.ToUpper(key[:1]) + key[1:], Address: key + "@example.com"}
+	}
+	a.cfg.Email.Groups = append(a.cfg.Email.Groups, config.RecipientGroupConfig{Name: name, Members: keys})
+	a.cfg.YearArchive.ShareWith = []string{name}
+	return nil
+}
+
+func runArchiveYear(year int, dryRun bool) error {
+	a := getArchiveYearContext()
+	client, err := drive.NewClient(context.Background(), "", drive.WithDriveService(a.service))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	h := getHistoryContext()
+	if h.store == nil {
+		if err := aHistoryStore(); err != nil {
+			return err
+		}
+	}
+
+	a.output.Reset()
+	a.err = cmd.RunArchiveYearWithDependencies(context.Background(), a.cfg, client, h.store, year, dryRun, a.output)
+	return nil
+}
+
+func iArchiveTheYear(year int) error {
+	return runArchiveYear(year, false)
+}
+
+func iPreviewArchivingTheYear(year int) error {
+	return runArchiveYear(year, true)
+}
+
+func theArchiveShouldSucceed() error {
+	a := getArchiveYearContext()
+	if a.err != nil {
+		return fmt.Errorf("expected success, got %v\noutput:\n%s", a.err, a.output.String())
+	}
+	return nil
+}
+
+func theArchiveShouldFailWith(msg string) error {
+	a := getArchiveYearContext()
+	if a.err == nil {
+		return fmt.Errorf("expected an error containing %q, got success\noutput:\n%s", msg, a.output.String())
+	}
+	if !strings.Contains(a.err.Error(), msg) {
+		return fmt.Errorf("expected an error containing %q, got %v", msg, a.err)
+	}
+	return nil
+}
+
+func theArchiveOutputShouldInclude(text string) error {
+	a := getArchiveYearContext()
+	if !strings.Contains(a.output.String(), text) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", text, a.output.String())
+	}
+	return nil
+}
+
+// archiveFolderID follows a slash-separated path of folder names down from
+// the Services folder
+func archiveFolderID(path string) (string, error) {
+	a := getArchiveYearContext()
+	id := archiveServicesFolderID
+	for _, name := range strings.Split(path, "/") {
+		var found *googledrive.File
+		for _, f := range a.service.Files() {
+			if f.Name == name && f.MimeType == distribution.MimeTypeFolder && slices.Contains(f.Parents, id) {
+				found = f
+			}
+		}
+		if found == nil {
+			return "", fmt.Errorf("no folder %s on Drive", path)
+		}
+		id = found.Id
+	}
+	return id, nil
+}
+
+func archiveFolderNames(folderID string) string {
+	var names []string
+	for _, f := range getArchiveYearContext().service.Files() {
+		if slices.Contains(f.Parents, folderID) && f.MimeType != distribution.MimeTypeFolder {
+			names = append(names, f.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func theFolderShouldHold(path, want string) error {
+	id, err := archiveFolderID(path)
+	if err != nil {
+		return err
+	}
+	if got := archiveFolderNames(id); got != want {
+		return fmt.Errorf("%s holds %q, want %q", path, got, want)
+	}
+	return nil
+}
+
+func theServicesFolderShouldStillHold(want string) error {
+	if got := archiveFolderNames(archiveServicesFolderID); got != want {
+		return fmt.Errorf("the Services folder holds %q, want %q", got, want)
+	}
+	return nil
+}
+
+func theIndexShouldInclude(text string) error {
+	a := getArchiveYearContext()
+	for _, f := range a.service.Files() {
+		if f.Name != distribution.YearIndexFileName(2025) {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := a.service.DownloadFile(context.Background(), f.Id, &buf); err != nil {
+			return err
+		}
+		if !strings.Contains(buf.String(), text) {
+			return fmt.Errorf("expected the index to include %q, got:\n%s", text, buf.String())
+		}
+		return nil
+	}
+	return fmt.Errorf("no index was uploaded")
+}
+
+func theFolderShouldBeSharedWith(path, want string) error {
+	id, err := archiveFolderID(path)
+	if err != nil {
+		return err
+	}
+	if got := strings.Join(getArchiveYearContext().service.SharedWith(id), ", "); got != want {
+		return fmt.Errorf("%s is shared with %q, want %q", path, got, want)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nac-service-media/cmd"
+	infraaudit "nac-service-media/infrastructure/audit"
+	"nac-service-media/infrastructure/drive"
+	"nac-service-media/infrastructure/filesystem"
+
+	"github.com/cucumber/godog"
+)
+
+// auditContext holds test state for audit log scenarios
+type auditContext struct {
+	dir    string
+	log    *infraaudit.JSONLog
+	output bytes.Buffer
+	err    error
+}
+
+var sharedAuditContext *auditContext
+
+func getAuditContext() *auditContext {
+	return sharedAuditContext
+}
+
+func InitializeAuditScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedAuditContext = &auditContext{}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if a := getAuditContext(); a != nil && a.dir != "" {
+			os.RemoveAll(a.dir)
+		}
+		sharedAuditContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^an audit log$`, anAuditLog)
+	ctx.Step(`^I share the Drive file "([^"]*)"$`, iShareTheDriveFile)
+	ctx.Step(`^I empty the Drive trash$`, iEmptyTheDriveTrash)
+	ctx.Step(`^I remove the local file "([^"]*)"$`, iRemoveTheLocalFile)
+	ctx.Step(`^I show the audit log$`, iShowTheAuditLog)
+	ctx.Step(`^I show the audit log for action "([^"]*)"$`, iShowTheAuditLogForAction)
+	ctx.Step(`^I show the last (\d+) audit events?$`, iShowTheLastAuditEvents)
+	ctx.Step(`^audit line (\d+) is edited to replace "([^"]*)" with "([^"]*)"$`, auditLineIsEdited)
+	ctx.Step(`^audit line (\d+) is removed$`, auditLineIsRemoved)
+	ctx.Step(`^the audit show should succeed$`, theAuditShowShouldSucceed)
+	ctx.Step(`^the audit show should fail with "([^"]*)"$`, theAuditShowShouldFailWith)
+	ctx.Step(`^the audit output should include "([^"]*)"$`, theAuditOutputShouldInclude)
+	ctx.Step(`^the audit output should not include "([^"]*)"$`, theAuditOutputShouldNotInclude)
+}
+
+// anAuditLog creates an empty log and records Drive cleanup through it
+func anAuditLog() error {
+	a := getAuditContext()
+	dir, err := os.MkdirTemp("", "audit-test-*")
+	if err != nil {
+		return err
+	}
+	a.dir = dir
+	a.log = infraaudit.NewJSONLog(filepath.Join(dir, "audit.jsonl"),
+		infraaudit.WithActor("av@church-pc"),
+		infraaudit.WithCommand("nac-service-media drive cleanup"),
+	)
+	getCleanupContext().auditLog = a.log
+	return nil
+}
+
+func auditedDriveClient() (*drive.Client, error) {
+	return drive.NewClient(context.Background(), "",
+		drive.WithDriveService(getCleanupContext().mockService),
+		drive.WithAuditLog(getAuditContext().log),
+	)
+}
+
+func iShareTheDriveFile(fileID string) error {
+	client, err := auditedDriveClient()
+	if err != nil {
+		return err
+	}
+	return client.SetPublicSharing(context.Background(), fileID)
+}
+
+func iEmptyTheDriveTrash() error {
+	client, err := auditedDriveClient()
+	if err != nil {
+		return err
+	}
+	return client.EmptyTrash(context.Background())
+}
+
+func iRemoveTheLocalFile(name string) error {
+	a := getAuditContext()
+	path := filepath.Join(a.dir, name)
+	if err := os.WriteFile(path, []byte("video"), 0644); err != nil {
+		return err
+	}
+	return filesystem.NewRemover(filesystem.WithAuditLog(a.log)).Remove(path)
+}
+
+func iShowTheAuditLog() error {
+	return runAuditShow(0, "")
+}
+
+func iShowTheAuditLogForAction(action string) error {
+	return runAuditShow(0, action)
+}
+
+func iShowTheLastAuditEvents(limit int) error {
+	return runAuditShow(limit, "")
+}
+
+func runAuditShow(limit int, action string) error {
+	a := getAuditContext()
+	a.output.Reset()
+	a.err = cmd.RunAuditShowWithDependencies(a.log, limit, action, &a.output)
+	return nil
+}
+
+// rewriteAuditLines applies edit to the log's lines (1-based line numbers)
+func rewriteAuditLines(edit func(lines []string) ([]string, error)) error {
+	a := getAuditContext()
+	data, err := os.ReadFile(a.log.Path())
+	if err != nil {
+		return err
+	}
+	lines, err := edit(strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(a.log.Path(), []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+func auditLineIsEdited(line int, old, replacement string) error {
+	return rewriteAuditLines(func(lines []string) ([]string, error) {
+		if line < 1 || line > len(lines) || !strings.Contains(lines[line-1], old) {
+			return nil, fmt.Errorf("audit line %d does not contain %q", line, old)
+		}
+		lines[line-1] = strings.Replace(lines[line-1], old, replacement, 1)
+		return lines, nil
+	})
+}
+
+func auditLineIsRemoved(line int) error {
+	return rewriteAuditLines(func(lines []string) ([]string, error) {
+		if line < 1 || line > len(lines) {
+			return nil, fmt.Errorf("audit log has no line %d", line)
+		}
+		return append(lines[:line-1], lines[line:]...), nil
+	})
+}
+
+func theAuditShowShouldSucceed() error {
+	a := getAuditContext()
+	if a.err != nil {
+		return fmt.Errorf("expected audit show to succeed, got: %v\nOutput:\n%s", a.err, a.output.String())
+	}
+	return nil
+}
+
+func theAuditShowShouldFailWith(expected string) error {
+	a := getAuditContext()
+	if a.err == nil {
+		return fmt.Errorf("expected audit show to fail with %q, but it succeeded\nOutput:\n%s", expected, a.output.String())
+	}
+	if !strings.Contains(a.err.Error(), expected) {
+		return fmt.Errorf("expected error to contain %q, got: %v", expected, a.err)
+	}
+	return nil
+}
+
+func theAuditOutputShouldInclude(expected string) error {
+	a := getAuditContext()
+	if !strings.Contains(a.output.String(), expected) {
+		return fmt.Errorf("expected audit output to include %q, got:\n%s", expected, a.output.String())
+	}
+	return nil
+}
+
+func theAuditOutputShouldNotInclude(unexpected string) error {
+	a := getAuditContext()
+	if strings.Contains(a.output.String(), unexpected) {
+		return fmt.Errorf("expected audit output not to include %q, got:\n%s", unexpected, a.output.String())
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/infrastructure/drive"
+
+	googledrive "google.golang.org/api/drive/v3"
+
+	"github.com/cucumber/godog"
+)
+
+// taggingMockDriveService is a cleanup mock that can also update appProperties
+type taggingMockDriveService struct {
+	cleanupMockDriveService
+	failTags map[string]bool // file names whose tagging fails
+	writes   int
+}
+
+func (m *taggingMockDriveService) UpdateAppProperties(ctx context.Context, fileID string, appProperties map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.files {
+		if f.Id != fileID {
+			continue
+		}
+		if m.failTags[f.Name] {
+			return fmt.Errorf("googleapi: Error 403: User rate limit exceeded")
+		}
+		if f.AppProperties == nil {
+			f.AppProperties = make(map[string]string)
+		}
+		for k, v := range appProperties {
+			f.AppProperties[k] = v
+		}
+		m.writes++
+		return nil
+	}
+	return fmt.Errorf("googleapi: Error 404: File not found: %s", fileID)
+}
+
+// backfillContext holds test state for metadata backfill scenarios
+type backfillContext struct {
+	mockService *taggingMockDriveService
+	batchSize   int
+	output      *bytes.Buffer
+	err         error
+}
+
+// SharedBackfillContext is reset before each scenario
+var SharedBackfillContext *backfillContext
+
+func getBackfillContext() *backfillContext {
+	return SharedBackfillContext
+}
+
+func InitializeBackfillScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		SharedBackfillContext = &backfillContext{
+			mockService: &taggingMockDriveService{failTags: make(map[string]bool)},
+			batchSize:   20,
+			output:      &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		SharedBackfillContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the Services folder to backfill holds:$`, theServicesFolderToBackfillHolds)
+	ctx.Step(`^tagging "([^"]*)" fails$`, taggingFails)
+	ctx.Step(`^I backfill metadata$`, iBackfillMetadata)
+	ctx.Step(`^I backfill metadata in batches of (\d+)$`, iBackfillMetadataInBatchesOf)
+	ctx.Step(`^I preview the metadata backfill$`, iPreviewTheMetadataBackfill)
+	ctx.Step(`^the backfill should succeed$`, theBackfillShouldSucceed)
+	ctx.Step(`^the backfill should fail with "([^"]*)"$`, theBackfillShouldFailWith)
+	ctx.Step(`^the backfill output should include "([^"]*)"$`, theBackfillOutputShouldInclude)
+	ctx.Step(`^"([^"]*)" should be tagged with ([a-z_]+) "([^"]*)"$`, shouldBeTaggedWith)
+	ctx.Step(`^"([^"]*)" should have no ([a-z_]+) tag$`, shouldHaveNoTag)
+	ctx.Step(`^no files should have been tagged$`, noFilesShouldHaveBeenTagged)
+}
+
+func theServicesFolderToBackfillHolds(table *godog.Table) error {
+	b := getBackfillContext()
+	header := table.Rows[0].Cells
+	for i, row := range table.Rows[1:] {
+		f := &googledrive.File{Id: fmt.Sprintf("file-%d", i+1)}
+		for j, cell := range row.Cells {
+			switch header[j].Value {
+			case "name":
+				f.Name = cell.Value
+			case "mime_type":
+				f.MimeType = cell.Value
+			case "service_date", "media_type":
+				if cell.Value != "" {
+					if f.AppProperties == nil {
+						f.AppProperties = make(map[string]string)
+					}
+					f.AppProperties[header[j].Value] = cell.Value
+				}
+			}
+		}
+		b.mockService.files = append(b.mockService.files, f)
+	}
+	return nil
+}
+
+func taggingFails(name string) error {
+	getBackfillContext().mockService.failTags[name] = true
+	return nil
+}
+
+func runBackfill(dryRun bool) error {
+	b := getBackfillContext()
+	client, err := drive.NewClient(context.Background(), "", drive.WithDriveService(b.mockService))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+
+	b.output.Reset()
+	b.err = cmd.RunDriveBackfillWithDependencies(context.Background(), client, "test-folder-id",
+		dryRun, b.batchSize, time.Millisecond, b.output)
+	return nil
+}
+
+func iBackfillMetadata() error {
+	return runBackfill(false)
+}
+
+func iBackfillMetadataInBatchesOf(size int) error {
+	getBackfillContext().batchSize = size
+	return runBackfill(false)
+}
+
+func iPreviewTheMetadataBackfill() error {
+	return runBackfill(true)
+}
+
+func theBackfillShouldSucceed() error {
+	b := getBackfillContext()
+	if b.err != nil {
+		return fmt.Errorf("expected success, got %v\noutput:\n%s", b.err, b.output.String())
+	}
+	return nil
+}
+
+func theBackfillShouldFailWith(expected string) error {
+	b := getBackfillContext()
+	if b.err == nil {
+		return fmt.Errorf("expected an error containing %q, got success\noutput:\n%s", expected, b.output.String())
+	}
+	if !strings.Contains(b.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %v", expected, b.err)
+	}
+	return nil
+}
+
+func theBackfillOutputShouldInclude(expected string) error {
+	b := getBackfillContext()
+	if !strings.Contains(b.output.String(), expected) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", expected, b.output.String())
+	}
+	return nil
+}
+
+func backfillFile(name string) (*googledrive.File, error) {
+	for _, f := range getBackfillContext().mockService.files {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("no file named %s", name)
+}
+
+func shouldBeTaggedWith(name, key, value string) error {
+	f, err := backfillFile(name)
+	if err != nil {
+		return err
+	}
+	if got := f.AppProperties[key]; got != value {
+		return fmt.Errorf("%s has %s %q, want %q", name, key, got, value)
+	}
+	return nil
+}
+
+func shouldHaveNoTag(name, key string) error {
+	f, err := backfillFile(name)
+	if err != nil {
+		return err
+	}
+	if got, ok := f.AppProperties[key]; ok {
+		return fmt.Errorf("%s has %s %q, want none", name, key, got)
+	}
+	return nil
+}
+
+func noFilesShouldHaveBeenTagged() error {
+	if n := getBackfillContext().mockService.writes; n != 0 {
+		return fmt.Errorf("%d files were tagged", n)
+	}
+	return nil
+}
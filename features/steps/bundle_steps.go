@@ -0,0 +1,249 @@
+//go:build integration
+
+package steps
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/history"
+	"nac-service-media/infrastructure/config"
+	infrahistory "nac-service-media/infrastructure/history"
+	"nac-service-media/infrastructure/workspace"
+
+	"github.com/cucumber/godog"
+)
+
+// bundleContext holds test state for support bundle scenarios
+type bundleContext struct {
+	dir    string
+	cfg    *config.Config
+	output *bytes.Buffer
+	err    error
+	files  map[string]string // Bundle contents by name
+}
+
+// SharedBundleContext is reset before each scenario
+var SharedBundleContext *bundleContext
+
+func getBundleContext() *bundleContext {
+	return SharedBundleContext
+}
+
+func InitializeBundleScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		dir, err := os.MkdirTemp("", "bundle-feature-*")
+		if err != nil {
+			return c, err
+		}
+		SharedBundleContext = &bundleContext{
+			dir: dir,
+			cfg: &config.Config{
+				Paths:   config.PathsConfig{WorkspaceDirectory: filepath.Join(dir, "workspaces")},
+				History: config.HistoryConfig{File: filepath.Join(dir, "history.jsonl")},
+				Audit:   config.AuditConfig{File: filepath.Join(dir, "audit.jsonl")},
+				Summary: config.SummaryConfig{Dir: filepath.Join(dir, "summaries")},
+			},
+			output: &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if b := SharedBundleContext; b != nil {
+			os.RemoveAll(b.dir)
+		}
+		SharedBundleContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the config file to bundle contains:$`, theConfigFileToBundleContains)
+	ctx.Step(`^the last run processed the "([^"]*)" service$`, theLastRunProcessedTheService)
+	ctx.Step(`^the last run processed the "([^"]*)" service with ffmpeg "([^"]*)"$`, theLastRunProcessedTheServiceWithFFmpeg)
+	ctx.Step(`^a run summary was archived for "([^"]*)"$`, aRunSummaryWasArchivedFor)
+	ctx.Step(`^a failed run kept a workspace with (\d+) frames? of (\d+) bytes$`, aFailedRunKeptAWorkspaceWithFrames)
+	ctx.Step(`^I bundle the last run$`, iBundleTheLastRun)
+	ctx.Step(`^I bundle the last run with --max-size (\d+)$`, iBundleTheLastRunWithMaxSize)
+	ctx.Step(`^I run bundle without --last-run$`, iRunBundleWithoutLastRun)
+	ctx.Step(`^the bundle should fail with "([^"]*)"$`, theBundleShouldFailWith)
+	ctx.Step(`^the bundle should contain "([^"]*)"$`, theBundleShouldContain)
+	ctx.Step(`^the bundle should not contain "([^"]*)"$`, theBundleShouldNotContain)
+	ctx.Step(`^the bundled "([^"]*)" should include "([^"]*)"$`, theBundledFileShouldInclude)
+	ctx.Step(`^the bundled "([^"]*)" should not include "([^"]*)"$`, theBundledFileShouldNotInclude)
+	ctx.Step(`^the bundle output should include "([^"]*)"$`, theBundleOutputShouldInclude)
+}
+
+func (b *bundleContext) configPath() string {
+	return filepath.Join(b.dir, "config.yaml")
+}
+
+func theConfigFileToBundleContains(content *godog.DocString) error {
+	return os.WriteFile(getBundleContext().configPath(), []byte(content.Content), 0644)
+}
+
+func theLastRunProcessedTheService(date string) error {
+	return theLastRunProcessedTheServiceWithFFmpeg(date, "")
+}
+
+func theLastRunProcessedTheServiceWithFFmpeg(date, version string) error {
+	b := getBundleContext()
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	return infrahistory.NewJSONStore(b.cfg.History.File).Append(history.Entry{
+		ServiceDate:   serviceDate,
+		ProcessedAt:   serviceDate.Add(13 * time.Hour),
+		Minister:      "Pr. John Smith",
+		FFmpegVersion: version,
+	})
+}
+
+func aRunSummaryWasArchivedFor(date string) error {
+	b := getBundleContext()
+	if err := os.MkdirAll(b.cfg.Summary.Dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.cfg.Summary.Dir, date+".md"), []byte("# Service "+date+"\n"), 0644)
+}
+
+func aFailedRunKeptAWorkspaceWithFrames(frames, size int) error {
+	b := getBundleContext()
+	ws, err := workspace.New(b.cfg.Paths.WorkspaceDirectory, time.Now())
+	if err != nil {
+		return err
+	}
+	for i := 1; i <= frames; i++ {
+		name := filepath.Join(ws.Dir(workspace.Frames), fmt.Sprintf("frame_%04d.jpg", i))
+		if err := os.WriteFile(name, bytes.Repeat([]byte{0xff}, size), 0644); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filepath.Join(ws.Dir(workspace.Partial), "2025-12-28.mp4"), []byte("partial"), 0644)
+}
+
+func runBundle(lastRun bool, maxSizeMB int) error {
+	b := getBundleContext()
+	path := filepath.Join(b.dir, "bundle.zip")
+	b.err = cmd.RunBundleWithDependencies(cmd.BundleInput{
+		LastRun:    lastRun,
+		OutputPath: path,
+		MaxSizeMB:  maxSizeMB,
+		ConfigFile: b.configPath(),
+		Config:     b.cfg,
+	}, time.Date(2025, 12, 29, 9, 0, 0, 0, time.UTC), b.output)
+	if b.err != nil {
+		return nil
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	b.files = make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		b.files[f.Name] = string(data)
+	}
+	return nil
+}
+
+func iBundleTheLastRun() error {
+	return runBundle(true, 20)
+}
+
+func iBundleTheLastRunWithMaxSize(mb int) error {
+	return runBundle(true, mb)
+}
+
+func iRunBundleWithoutLastRun() error {
+	return runBundle(false, 20)
+}
+
+func theBundleShouldFailWith(expected string) error {
+	b := getBundleContext()
+	if b.err == nil {
+		return fmt.Errorf("expected bundle to fail with %q, but it succeeded", expected)
+	}
+	if !strings.Contains(b.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, b.err.Error())
+	}
+	return nil
+}
+
+// bundledFile finds a file by its name, or by the end of its name for
+// workspace files whose run directory is random
+func bundledFile(name string) (string, bool) {
+	b := getBundleContext()
+	if b.err != nil {
+		return "", false
+	}
+	for path, data := range b.files {
+		if path == name || strings.HasSuffix(path, "/"+name) {
+			return data, true
+		}
+	}
+	return "", false
+}
+
+func theBundleShouldContain(name string) error {
+	if _, ok := bundledFile(name); !ok {
+		b := getBundleContext()
+		return fmt.Errorf("expected bundle to contain %q (error: %v), got %v", name, b.err, b.files)
+	}
+	return nil
+}
+
+func theBundleShouldNotContain(name string) error {
+	if _, ok := bundledFile(name); ok {
+		return fmt.Errorf("expected bundle not to contain %q", name)
+	}
+	return nil
+}
+
+func theBundledFileShouldInclude(name, expected string) error {
+	data, ok := bundledFile(name)
+	if !ok {
+		return fmt.Errorf("bundle has no %q", name)
+	}
+	if !strings.Contains(data, expected) {
+		return fmt.Errorf("expected %s to include %q, got:\n%s", name, expected, data)
+	}
+	return nil
+}
+
+func theBundledFileShouldNotInclude(name, unexpected string) error {
+	data, ok := bundledFile(name)
+	if !ok {
+		return fmt.Errorf("bundle has no %q", name)
+	}
+	if strings.Contains(data, unexpected) {
+		return fmt.Errorf("expected %s not to include %q, got:\n%s", name, unexpected, data)
+	}
+	return nil
+}
+
+func theBundleOutputShouldInclude(expected string) error {
+	b := getBundleContext()
+	if !strings.Contains(b.output.String(), expected) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", expected, b.output.String())
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/detection"
+	"nac-service-media/infrastructure/config"
+
+	"github.com/cucumber/godog"
+)
+
+// capabilitiesContext holds state for build capability scenarios
+type capabilitiesContext struct {
+	detection config.DetectionConfig
+	available bool
+	output    *bytes.Buffer
+	err       error
+}
+
+var sharedCapabilitiesContext *capabilitiesContext
+
+func getCapabilitiesContext() *capabilitiesContext {
+	return sharedCapabilitiesContext
+}
+
+func InitializeCapabilitiesScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedCapabilitiesContext = &capabilitiesContext{output: &bytes.Buffer{}}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		sharedCapabilitiesContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^I list the build capabilities$`, iListTheBuildCapabilities)
+	ctx.Step(`^the capabilities should include "([^"]*)"$`, theCapabilitiesShouldInclude)
+	ctx.Step(`^detection is enabled in config$`, detectionIsEnabledInConfig)
+	ctx.Step(`^the build includes detection$`, theBuildIncludesDetection)
+	ctx.Step(`^I check whether "([^"]*)" can be detected(?: from "([^"]*)")?$`, iCheckWhetherCanBeDetected)
+	ctx.Step(`^the end is detected by "([^"]*)"$`, theEndIsDetectedBy)
+	ctx.Step(`^I check whether the end can be detected$`, iCheckWhetherTheEndCanBeDetected)
+	ctx.Step(`^detection should be usable$`, detectionShouldBeUsable)
+	ctx.Step(`^the timestamp should be required with "([^"]*)"$`, theTimestampShouldBeRequiredWith)
+	ctx.Step(`^the error should mark detection as unavailable$`, theErrorShouldMarkDetectionAsUnavailable)
+}
+
+func iListTheBuildCapabilities() error {
+	c := getCapabilitiesContext()
+	cmd.PrintCapabilities(c.output, cmd.BuildCapabilities())
+	return nil
+}
+
+func theCapabilitiesShouldInclude(expected string) error {
+	if out := getCapabilitiesContext().output.String(); !strings.Contains(out, expected) {
+		return fmt.Errorf("expected capabilities to include %q, got:\n%s", expected, out)
+	}
+	return nil
+}
+
+func detectionIsEnabledInConfig() error {
+	getCapabilitiesContext().detection.Enabled = true
+	return nil
+}
+
+func theBuildIncludesDetection() error {
+	getCapabilitiesContext().available = true
+	return nil
+}
+
+func iCheckWhetherCanBeDetected(flag, value string) error {
+	c := getCapabilitiesContext()
+	c.err = cmd.RequireDetection(c.detection, c.available, flag, value)
+	return nil
+}
+
+func theEndIsDetectedBy(method string) error {
+	getCapabilitiesContext().detection.End.Method = method
+	return nil
+}
+
+func iCheckWhetherTheEndCanBeDetected() error {
+	c := getCapabilitiesContext()
+	c.err = cmd.RequireEndDetection(c.detection, c.available)
+	return nil
+}
+
+func detectionShouldBeUsable() error {
+	if err := getCapabilitiesContext().err; err != nil {
+		return fmt.Errorf("expected detection to be usable, got: %v", err)
+	}
+	return nil
+}
+
+func theTimestampShouldBeRequiredWith(expected string) error {
+	err := getCapabilitiesContext().err
+	if err == nil {
+		return fmt.Errorf("expected an error containing %q, got none", expected)
+	}
+	if !strings.Contains(err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, err.Error())
+	}
+	return nil
+}
+
+func theErrorShouldMarkDetectionAsUnavailable() error {
+	if err := getCapabilitiesContext().err; !errors.Is(err, detection.ErrDetectionUnavailable) {
+		return fmt.Errorf("expected ErrDetectionUnavailable, got: %v", err)
+	}
+	return nil
+}
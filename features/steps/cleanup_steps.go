@@ -3,13 +3,18 @@
 package steps
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	appdist "nac-service-media/application/distribution"
+	"nac-service-media/cmd"
+	"nac-service-media/domain/audit"
 	"nac-service-media/domain/distribution"
 	"nac-service-media/infrastructure/drive"
 
@@ -29,6 +34,11 @@ type cleanupMockDriveService struct {
 	shouldFail      bool
 	failError       error
 	permissionError bool
+	failDeletes     map[string]bool // file names whose deletion fails
+	deleteDelay     time.Duration
+	inFlight        int
+	maxInFlight     int
+	mu              sync.Mutex
 }
 
 func (m *cleanupMockDriveService) ListFiles(ctx context.Context, query string, fields string, orderBy string) ([]*googledrive.File, error) {
@@ -90,6 +100,22 @@ func (m *cleanupMockDriveService) DeleteFile(ctx context.Context, fileID string)
 	if m.shouldFail {
 		return m.failError
 	}
+
+	m.mu.Lock()
+	m.inFlight++
+	m.maxInFlight = max(m.maxInFlight, m.inFlight)
+	m.mu.Unlock()
+
+	time.Sleep(m.deleteDelay)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+	for _, f := range m.files {
+		if f.Id == fileID && m.failDeletes[f.Name] {
+			return fmt.Errorf("googleapi: Error 500: backend error")
+		}
+	}
 	m.deletedFileIDs = append(m.deletedFileIDs, fileID)
 	return nil
 }
@@ -102,7 +128,7 @@ func (m *cleanupMockDriveService) EmptyTrash(ctx context.Context) error {
 	return nil
 }
 
-func (m *cleanupMockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string) (*googledrive.File, error) {
+func (m *cleanupMockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*googledrive.File, error) {
 	if m.shouldFail {
 		return nil, m.failError
 	}
@@ -125,12 +151,41 @@ func (m *cleanupMockDriveService) CreatePermission(ctx context.Context, fileID s
 // cleanupContext holds test state for cleanup scenarios
 type cleanupContext struct {
 	folderID      string
+	concurrency   int
 	client        *drive.Client
 	mockService   *cleanupMockDriveService
 	cleanupResult *distribution.CleanupResult
 	files         []distribution.FileInfo
 	err           error
 	service       *appdist.CleanupService
+	output        bytes.Buffer
+	scopeMode     string
+	auditLog      audit.Recorder // Set by "an audit log"
+	mirror        *cleanupMockMirror
+	archive       distribution.Archive
+}
+
+// cleanupMockMirror is an alternate download server that reports what it holds
+type cleanupMockMirror struct {
+	files       map[string]int64
+	unreachable bool
+}
+
+func (m *cleanupMockMirror) Put(ctx context.Context, remoteName string, r io.Reader, size int64) error {
+	m.files[remoteName] = size
+	return nil
+}
+
+func (m *cleanupMockMirror) URL(remoteName string) string {
+	return "https://media.example.org/" + remoteName
+}
+
+func (m *cleanupMockMirror) Stat(ctx context.Context, remoteName string) (int64, bool, error) {
+	if m.unreachable {
+		return 0, false, fmt.Errorf("dial tcp: connection refused")
+	}
+	size, ok := m.files[remoteName]
+	return size, ok, nil
 }
 
 // SharedCleanupContext is reset before each scenario via Before hook
@@ -165,8 +220,26 @@ func InitializeCleanupScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^"([^"]*)" should be deleted$`, fileShouldBeDeleted)
 	ctx.Step(`^the cleanup result should show (\d+) files? deleted$`, theCleanupResultShouldShowFilesDeleted)
 	ctx.Step(`^I should receive an error about insufficient storage$`, iShouldReceiveAnErrorAboutInsufficientStorage)
+	ctx.Step(`^the cleanup concurrency is (\d+)$`, theCleanupConcurrencyIs)
+	ctx.Step(`^deleting "([^"]*)" will fail$`, deletingWillFail)
+	ctx.Step(`^"([^"]*)" should fail to delete$`, fileShouldFailToDelete)
+	ctx.Step(`^the cleanup should succeed$`, theCleanupShouldSucceed)
+	ctx.Step(`^at most (\d+) deletions should run at once$`, atMostDeletionsShouldRunAtOnce)
+	ctx.Step(`^deletions should run concurrently$`, deletionsShouldRunConcurrently)
 	ctx.Step(`^I list mp4 files sorted by date$`, iListMP4FilesSortedByDate)
 	ctx.Step(`^the files should be in order:$`, theFilesShouldBeInOrder)
+	ctx.Step(`^I run drive cleanup with "([^"]*)" "([^"]*)"$`, iRunDriveCleanupWith)
+	ctx.Step(`^the cleanup command should succeed$`, theCleanupShouldSucceed)
+	ctx.Step(`^the cleanup command should fail with "([^"]*)"$`, theCleanupCommandShouldFailWith)
+	ctx.Step(`^the cleanup output should include "([^"]*)"$`, theCleanupOutputShouldInclude)
+	ctx.Step(`^the cleanup output should not include "([^"]*)"$`, theCleanupOutputShouldNotInclude)
+	ctx.Step(`^Drive access is limited to files this app created$`, driveAccessIsLimitedToFilesThisAppCreated)
+	ctx.Step(`^the mirror holds "([^"]*)"$`, theMirrorHolds)
+	ctx.Step(`^the mirror holds "([^"]*)" with a different size$`, theMirrorHoldsWithADifferentSize)
+	ctx.Step(`^the mirror holds "([^"]*)" without its checksum file$`, theMirrorHoldsWithoutItsChecksumFile)
+	ctx.Step(`^the mirror cannot be reached$`, theMirrorCannotBeReached)
+	ctx.Step(`^"([^"]*)" should be deleted as archived$`, fileShouldBeDeletedAsArchived)
+	ctx.Step(`^"([^"]*)" should not be deleted$`, fileShouldNotBeDeleted)
 }
 
 func thereIsAvailableStorage(amount int, unit string) error {
@@ -225,6 +298,8 @@ func iEnsureSpaceIsAvailable(amount int, unit string) error {
 		context.Background(),
 		"",
 		drive.WithDriveService(c.mockService),
+		drive.WithScopeMode(c.scopeMode),
+		drive.WithAuditLog(c.auditLog),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %v", err)
@@ -232,7 +307,10 @@ func iEnsureSpaceIsAvailable(amount int, unit string) error {
 	c.client = client
 
 	// Create cleanup service
-	c.service = appdist.NewCleanupService(client, c.folderID)
+	c.service = appdist.NewCleanupService(client, c.folderID,
+		appdist.WithCleanupConcurrency(c.concurrency),
+		appdist.WithArchive(c.archive),
+	)
 
 	// Run the cleanup
 	result, err := c.service.EnsureSpaceAvailable(context.Background(), neededBytes)
@@ -305,6 +383,59 @@ func iShouldReceiveAnErrorAboutInsufficientStorage() error {
 	return nil
 }
 
+func theCleanupConcurrencyIs(n int) error {
+	c := getCleanupContext()
+	c.concurrency = n
+	c.mockService.deleteDelay = 20 * time.Millisecond
+	return nil
+}
+
+func deletingWillFail(name string) error {
+	c := getCleanupContext()
+	if c.mockService.failDeletes == nil {
+		c.mockService.failDeletes = make(map[string]bool)
+	}
+	c.mockService.failDeletes[name] = true
+	return nil
+}
+
+func fileShouldFailToDelete(name string) error {
+	c := getCleanupContext()
+	if c.cleanupResult == nil {
+		return fmt.Errorf("cleanup result is nil")
+	}
+	for _, fd := range c.cleanupResult.Failed {
+		if fd.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected %q to fail to delete, failures: %+v", name, c.cleanupResult.Failed)
+}
+
+func theCleanupShouldSucceed() error {
+	c := getCleanupContext()
+	if c.err != nil {
+		return fmt.Errorf("expected cleanup to succeed, got: %v", c.err)
+	}
+	return nil
+}
+
+func atMostDeletionsShouldRunAtOnce(n int) error {
+	c := getCleanupContext()
+	if c.mockService.maxInFlight > n {
+		return fmt.Errorf("expected at most %d concurrent deletions, saw %d", n, c.mockService.maxInFlight)
+	}
+	return nil
+}
+
+func deletionsShouldRunConcurrently() error {
+	c := getCleanupContext()
+	if c.mockService.maxInFlight < 2 {
+		return fmt.Errorf("expected concurrent deletions, saw at most %d at once", c.mockService.maxInFlight)
+	}
+	return nil
+}
+
 func iListMP4FilesSortedByDate() error {
 	c := getCleanupContext()
 
@@ -313,6 +444,8 @@ func iListMP4FilesSortedByDate() error {
 		context.Background(),
 		"",
 		drive.WithDriveService(c.mockService),
+		drive.WithScopeMode(c.scopeMode),
+		drive.WithAuditLog(c.auditLog),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %v", err)
@@ -359,3 +492,153 @@ func theFilesShouldBeInOrder(table *godog.Table) error {
 
 	return nil
 }
+
+func iRunDriveCleanupWith(flag, size string) error {
+	c := getCleanupContext()
+
+	client, err := drive.NewClient(
+		context.Background(),
+		"",
+		drive.WithDriveService(c.mockService),
+		drive.WithAuditLog(c.auditLog),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+
+	var ensure, target string
+	switch flag {
+	case "--ensure-space":
+		ensure = size
+	case "--target-free":
+		target = size
+	default:
+		return fmt.Errorf("unknown cleanup flag %q", flag)
+	}
+	c.err = cmd.RunDriveCleanupWithDependencies(context.Background(), client, c.folderID, ensure, target, c.concurrency, c.archive, &c.output)
+	return nil
+}
+
+// driveAccessIsLimitedToFilesThisAppCreated sets google.scope_mode: file for
+// both cleanup and usage scenarios
+func driveAccessIsLimitedToFilesThisAppCreated() error {
+	if c := getCleanupContext(); c != nil {
+		c.scopeMode = drive.ScopeModeFile
+	}
+	if u := getUsageContext(); u != nil {
+		u.scopeMode = drive.ScopeModeFile
+	}
+	return nil
+}
+
+func theCleanupCommandShouldFailWith(msg string) error {
+	c := getCleanupContext()
+	if c.err == nil {
+		return fmt.Errorf("expected cleanup to fail with %q, output:\n%s", msg, c.output.String())
+	}
+	if !strings.Contains(c.err.Error(), msg) {
+		return fmt.Errorf("expected error containing %q, got: %v", msg, c.err)
+	}
+	return nil
+}
+
+func theCleanupOutputShouldInclude(text string) error {
+	c := getCleanupContext()
+	if !strings.Contains(c.output.String(), text) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", text, c.output.String())
+	}
+	return nil
+}
+
+func theCleanupOutputShouldNotInclude(text string) error {
+	c := getCleanupContext()
+	if strings.Contains(c.output.String(), text) {
+		return fmt.Errorf("expected output not to include %q, got:\n%s", text, c.output.String())
+	}
+	return nil
+}
+
+// putOnMirror publishes the Drive file name to the mirror with the given size
+func putOnMirror(name string, size int64, withChecksum bool) error {
+	c := getCleanupContext()
+	if c.mirror == nil {
+		c.mirror = &cleanupMockMirror{files: make(map[string]int64)}
+		c.archive = distribution.MirrorArchive(c.mirror)
+	}
+	c.mirror.files[name] = size
+	if withChecksum {
+		c.mirror.files[name+distribution.ChecksumSuffix] = 82
+	}
+	return nil
+}
+
+func driveFileSize(name string) (int64, error) {
+	for _, f := range getCleanupContext().mockService.files {
+		if f.Name == name {
+			return f.Size, nil
+		}
+	}
+	return 0, fmt.Errorf("no Drive file named %q", name)
+}
+
+func theMirrorHolds(name string) error {
+	size, err := driveFileSize(name)
+	if err != nil {
+		return err
+	}
+	return putOnMirror(name, size, true)
+}
+
+func theMirrorHoldsWithADifferentSize(name string) error {
+	size, err := driveFileSize(name)
+	if err != nil {
+		return err
+	}
+	return putOnMirror(name, size/2, true)
+}
+
+func theMirrorHoldsWithoutItsChecksumFile(name string) error {
+	size, err := driveFileSize(name)
+	if err != nil {
+		return err
+	}
+	return putOnMirror(name, size, false)
+}
+
+func theMirrorCannotBeReached() error {
+	c := getCleanupContext()
+	if c.mirror == nil {
+		return fmt.Errorf("no mirror configured")
+	}
+	c.mirror.unreachable = true
+	return nil
+}
+
+func fileShouldBeDeletedAsArchived(name string) error {
+	c := getCleanupContext()
+	if c.cleanupResult == nil {
+		return fmt.Errorf("cleanup result is nil")
+	}
+	for _, df := range c.cleanupResult.DeletedFiles {
+		if df.Name == name {
+			if !df.Archived {
+				return fmt.Errorf("expected %q to be deleted as archived, but it was not marked archived", name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("expected %q to be deleted, deleted: %+v", name, c.cleanupResult.DeletedFiles)
+}
+
+func fileShouldNotBeDeleted(name string) error {
+	c := getCleanupContext()
+	if c.cleanupResult == nil {
+		return fmt.Errorf("cleanup result is nil")
+	}
+	for _, df := range c.cleanupResult.DeletedFiles {
+		if df.Name == name {
+			return fmt.Errorf("expected %q to be kept, but it was deleted", name)
+		}
+	}
+	return nil
+}
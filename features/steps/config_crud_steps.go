@@ -93,9 +93,16 @@ func InitializeConfigCrudScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the config should not contain sender "([^"]*)"$`, testCtx.theConfigShouldNotContainSender)
 
 	// Common assertions
+	// Lookup
+	ctx.Step(`^I run config find "([^"]*)"$`, testCtx.iRunConfigFind)
+	ctx.Step(`^lookup across all entries is enabled$`, testCtx.lookupAcrossAllEntriesIsEnabled)
+	ctx.Step(`^I look up the recipients? "([^"]*)"$`, testCtx.iLookUpTheRecipients)
+	ctx.Step(`^I look up the sender "([^"]*)"$`, testCtx.iLookUpTheSender)
+
 	ctx.Step(`^the command should succeed$`, testCtx.theCommandShouldSucceed)
 	ctx.Step(`^the command should fail with "([^"]*)"$`, testCtx.theCommandShouldFailWith)
 	ctx.Step(`^the output should contain "([^"]*)"$`, testCtx.theOutputShouldContain)
+	ctx.Step(`^the output should not contain "([^"]*)"$`, testCtx.theOutputShouldNotContain)
 }
 
 func (c *configCrudContext) loadConfig() error {
@@ -402,6 +409,14 @@ func (c *configCrudContext) theOutputShouldContain(expected string) error {
 	return nil
 }
 
+func (c *configCrudContext) theOutputShouldNotContain(unexpected string) error {
+	output := c.output.String()
+	if strings.Contains(output, unexpected) {
+		return fmt.Errorf("expected output not to contain %q but got:\n%s", unexpected, output)
+	}
+	return nil
+}
+
 // --- Sender steps ---
 
 func (c *configCrudContext) iRunConfigAddSender(key, name string) error {
@@ -476,3 +491,49 @@ func (c *configCrudContext) theConfigShouldNotContainSender(key string) error {
 	}
 	return nil
 }
+
+// --- Lookup ---
+
+func (c *configCrudContext) iRunConfigFind(query string) error {
+	if err := c.loadConfig(); err != nil {
+		return err
+	}
+	c.output.Reset()
+	c.err = cmd.RunConfigFindWithDependencies(c.config, c.configPath, query, c.output)
+	return nil
+}
+
+func (c *configCrudContext) lookupAcrossAllEntriesIsEnabled() error {
+	if err := c.loadConfig(); err != nil {
+		return err
+	}
+	c.config.Email.LookupAll = true
+	return c.saveConfig()
+}
+
+// iLookUpTheRecipients resolves --to style queries, writing each match to the output
+func (c *configCrudContext) iLookUpTheRecipients(queries string) error {
+	if err := c.loadConfig(); err != nil {
+		return err
+	}
+	c.output.Reset()
+	recipients, err := config.NewRecipientLookup(c.config, c.configPath).LookupRecipients([]string{queries})
+	c.err = err
+	for _, r := range recipients {
+		fmt.Fprintf(c.output, "%s <%s>\n", r.Name, r.Address)
+	}
+	return nil
+}
+
+func (c *configCrudContext) iLookUpTheSender(query string) error {
+	if err := c.loadConfig(); err != nil {
+		return err
+	}
+	c.output.Reset()
+	sender, err := config.NewRecipientLookup(c.config, c.configPath).LookupSender(query)
+	c.err = err
+	if err == nil {
+		fmt.Fprintf(c.output, "%s (%s)\n", sender.Name, sender.Key)
+	}
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"nac-service-media/infrastructure/config"
 
@@ -37,8 +39,18 @@ func InitializeConfigScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I attempt to load the configuration$`, testCtx.iAttemptToLoadTheConfiguration)
 	ctx.Step(`^the trimmed directory should be "([^"]*)"$`, testCtx.theTrimmedDirectoryShouldBe)
 	ctx.Step(`^the audio directory should be "([^"]*)"$`, testCtx.theAudioDirectoryShouldBe)
+	ctx.Step(`^the main audio bitrate should be "([^"]*)" with extras "([^"]*)"$`, testCtx.theMainAudioBitrateShouldBeWithExtras)
 	ctx.Step(`^the Google services folder ID should be "([^"]*)"$`, testCtx.theGoogleServicesFolderIDShouldBe)
 	ctx.Step(`^I should receive an error about missing configuration$`, testCtx.iShouldReceiveAnErrorAboutMissingConfiguration)
+	ctx.Step(`^a configuration file with email subject "([^"]*)"$`, testCtx.aConfigurationFileWithEmailSubject)
+	ctx.Step(`^the email subject should be "([^"]*)"$`, testCtx.theEmailSubjectShouldBe)
+	ctx.Step(`^I should receive a configuration error containing "([^"]*)"$`, testCtx.iShouldReceiveAConfigurationErrorContaining)
+	ctx.Step(`^a configuration file containing:$`, testCtx.aConfigurationFileContaining)
+	ctx.Step(`^the storage provider should be S3 with links valid for (\d+) hours$`, testCtx.theStorageProviderShouldBeS3WithLinksValidFor)
+	ctx.Step(`^the source directories should be "([^"]*)"$`, testCtx.theSourceDirectoriesShouldBe)
+	ctx.Step(`^the sender should not be copied on emails$`, testCtx.theSenderShouldNotBeCopiedOnEmails)
+	ctx.Step(`^old "([^"]*)" files should be archived before cleanup$`, testCtx.oldFilesShouldBeArchived)
+	ctx.Step(`^old "([^"]*)" files should not be archived before cleanup$`, testCtx.oldFilesShouldNotBeArchived)
 }
 
 func findProjectRoot() (string, error) {
@@ -109,6 +121,48 @@ func (c *configContext) theTrimmedDirectoryShouldBe(expected string) error {
 	return nil
 }
 
+func (c *configContext) theSourceDirectoriesShouldBe(expected string) error {
+	if c.cfg == nil {
+		return fmt.Errorf("config was not loaded")
+	}
+	if got := strings.Join(c.cfg.Paths.Sources(), ", "); got != expected {
+		return fmt.Errorf("expected source directories %q, got %q", expected, got)
+	}
+	return nil
+}
+
+func (c *configContext) theSenderShouldNotBeCopiedOnEmails() error {
+	if c.cfg == nil {
+		return fmt.Errorf("config was not loaded")
+	}
+	if c.cfg.Email.CopySender(false) {
+		return fmt.Errorf("expected email.bcc_sender to be off")
+	}
+	return nil
+}
+
+func (c *configContext) oldFilesShouldBeArchived(kind string) error {
+	return c.checkArchived(kind, true)
+}
+
+func (c *configContext) oldFilesShouldNotBeArchived(kind string) error {
+	return c.checkArchived(kind, false)
+}
+
+func (c *configContext) checkArchived(kind string, expected bool) error {
+	if c.cfg == nil {
+		return fmt.Errorf("config was not loaded")
+	}
+	policy, err := c.cfg.Archive.Policy()
+	if err != nil {
+		return err
+	}
+	if policy.Includes(kind) != expected {
+		return fmt.Errorf("expected archiving %s to be %v, got include %v", kind, expected, policy.Kinds)
+	}
+	return nil
+}
+
 func (c *configContext) theAudioDirectoryShouldBe(expected string) error {
 	if c.cfg == nil {
 		return fmt.Errorf("config was not loaded")
@@ -119,6 +173,19 @@ func (c *configContext) theAudioDirectoryShouldBe(expected string) error {
 	return nil
 }
 
+func (c *configContext) theMainAudioBitrateShouldBeWithExtras(main, extras string) error {
+	if c.cfg == nil {
+		return fmt.Errorf("config was not loaded")
+	}
+	if c.cfg.Audio.Bitrate.String() != main {
+		return fmt.Errorf("expected main audio bitrate %q, got %q", main, c.cfg.Audio.Bitrate)
+	}
+	if got := strings.Join(c.cfg.Audio.ExtraBitrates(), ", "); got != extras {
+		return fmt.Errorf("expected extra bitrates %q, got %q", extras, got)
+	}
+	return nil
+}
+
 func (c *configContext) theGoogleServicesFolderIDShouldBe(expected string) error {
 	if c.cfg == nil {
 		return fmt.Errorf("config was not loaded")
@@ -135,3 +202,58 @@ func (c *configContext) iShouldReceiveAnErrorAboutMissingConfiguration() error {
 	}
 	return nil
 }
+
+func (c *configContext) aConfigurationFileWithEmailSubject(subject string) error {
+	dir, err := os.MkdirTemp("", "config-test-*")
+	if err != nil {
+		return err
+	}
+	c.configPath = filepath.Join(dir, "config.yaml")
+	data := fmt.Sprintf("email:\n  from_name: \"White Plains\"\n  subject: %q\n", subject)
+	return os.WriteFile(c.configPath, []byte(data), 0644)
+}
+
+func (c *configContext) aConfigurationFileContaining(doc *godog.DocString) error {
+	dir, err := os.MkdirTemp("", "config-test-*")
+	if err != nil {
+		return err
+	}
+	c.configPath = filepath.Join(dir, "config.yaml")
+	return os.WriteFile(c.configPath, []byte(doc.Content), 0644)
+}
+
+func (c *configContext) theStorageProviderShouldBeS3WithLinksValidFor(hours int) error {
+	if c.cfg == nil {
+		return fmt.Errorf("config was not loaded")
+	}
+	if !c.cfg.UsesS3() {
+		return fmt.Errorf("expected S3 storage, got provider %q", c.cfg.Storage.Provider)
+	}
+	if got := c.cfg.Storage.S3.LinkExpiry(); got != time.Duration(hours)*time.Hour {
+		return fmt.Errorf("expected links valid for %d hours, got %s", hours, got)
+	}
+	if url := c.cfg.ServicesFolderURL(); url != "" {
+		return fmt.Errorf("expected no services folder link with S3 storage, got %s", url)
+	}
+	return nil
+}
+
+func (c *configContext) theEmailSubjectShouldBe(expected string) error {
+	if c.cfg == nil {
+		return fmt.Errorf("config was not loaded")
+	}
+	if c.cfg.Email.Subject != expected {
+		return fmt.Errorf("expected email subject %q, got %q", expected, c.cfg.Email.Subject)
+	}
+	return nil
+}
+
+func (c *configContext) iShouldReceiveAConfigurationErrorContaining(expected string) error {
+	if c.loadErr == nil {
+		return fmt.Errorf("expected an error but got none")
+	}
+	if !strings.Contains(c.loadErr.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, c.loadErr.Error())
+	}
+	return nil
+}
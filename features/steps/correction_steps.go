@@ -0,0 +1,183 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	appnotif "nac-service-media/application/notification"
+	"nac-service-media/cmd"
+	"nac-service-media/domain/history"
+	"nac-service-media/infrastructure/config"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/cucumber/godog"
+)
+
+// correctionContext holds the run history a correction reads and updates
+type correctionContext struct {
+	dir    string
+	store  *infrahistory.JSONStore
+	output bytes.Buffer
+}
+
+var sharedCorrectionContext *correctionContext
+
+func InitializeCorrectionScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedCorrectionContext = &correctionContext{}
+		return c, nil
+	})
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if sharedCorrectionContext != nil && sharedCorrectionContext.dir != "" {
+			os.RemoveAll(sharedCorrectionContext.dir)
+		}
+		sharedCorrectionContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the "([^"]*)" recording email named "([^"]*)" and went to "([^"]*)" as message "([^"]*)"$`, theRecordingEmailNamedAndWentTo)
+	ctx.Step(`^the "([^"]*)" recording email named "([^"]*)" and went to "([^"]*)" with no message ID$`, theRecordingEmailNamedAndWentToWithNoMessageID)
+	ctx.Step(`^I send a correction for "([^"]*)" naming minister "([^"]*)"$`, iSendACorrectionForNamingMinister)
+	ctx.Step(`^the email should reply to message "([^"]*)"$`, theEmailShouldReplyToMessage)
+	ctx.Step(`^the history for "([^"]*)" should name minister "([^"]*)"$`, theHistoryForShouldNameMinister)
+	ctx.Step(`^the correction output should include "([^"]*)"$`, theCorrectionOutputShouldInclude)
+}
+
+func (c *correctionContext) historyStore() (*infrahistory.JSONStore, error) {
+	if c.store != nil {
+		return c.store, nil
+	}
+	dir, err := os.MkdirTemp("", "correction-*")
+	if err != nil {
+		return nil, err
+	}
+	c.dir = dir
+	c.store = infrahistory.NewJSONStore(filepath.Join(dir, "history.jsonl"))
+	return c.store, nil
+}
+
+func theRecordingEmailNamedAndWentTo(date, minister, recipients, messageID string) error {
+	return recordEmail(date, minister, recipients, []string{messageID})
+}
+
+func theRecordingEmailNamedAndWentToWithNoMessageID(date, minister, recipients string) error {
+	return recordEmail(date, minister, recipients, nil)
+}
+
+func recordEmail(date, minister, recipients string, messageIDs []string) error {
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	store, err := sharedCorrectionContext.historyStore()
+	if err != nil {
+		return err
+	}
+	var addresses []string
+	for _, address := range strings.Split(recipients, ",") {
+		addresses = append(addresses, strings.TrimSpace(address))
+	}
+	return store.Append(history.Entry{
+		ServiceDate:     serviceDate,
+		ProcessedAt:     serviceDate.Add(2 * time.Hour),
+		Minister:        minister,
+		AudioURL:        "https://drive.google.com/file/d/abc/view",
+		VideoURL:        "https://drive.google.com/file/d/xyz/view",
+		Recipients:      addresses,
+		EmailSubject:    "White Plains: Recording of Service on " + serviceDate.Format("01/02/2006"),
+		EmailMessageIDs: messageIDs,
+		Outcome:         history.OutcomeSuccess,
+	})
+}
+
+func iSendACorrectionForNamingMinister(date, minister string) error {
+	e := getEmailContext()
+	c := sharedCorrectionContext
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	store, err := c.historyStore()
+	if err != nil {
+		return err
+	}
+
+	opts, err := e.sandboxOptions()
+	if err != nil {
+		return err
+	}
+	greeting, err := e.cfg.Email.Greeting.Rules()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, appnotif.WithGreeting(greeting))
+
+	e.err = cmd.RunSendCorrectionWithDependencies(
+		context.Background(),
+		e.gmailClient,
+		store,
+		config.NewRecipientLookup(e.cfg, ""),
+		e.cfg.Email.FromName,
+		"Jonathan",
+		serviceDate,
+		minister,
+		false,
+		&c.output,
+		opts...,
+	)
+	return nil
+}
+
+func theEmailShouldReplyToMessage(messageID string) error {
+	e := getEmailContext()
+	if len(e.mockService.sentMessages) == 0 {
+		return fmt.Errorf("no email was sent")
+	}
+	raw, err := decodeMessage(e.mockService.sentMessages[0])
+	if err != nil {
+		return err
+	}
+	for _, header := range []string{"In-Reply-To: " + messageID, "References: " + messageID} {
+		if !strings.Contains(raw, header) {
+			return fmt.Errorf("email missing %q in:\n%s", header, raw)
+		}
+	}
+	return nil
+}
+
+func theHistoryForShouldNameMinister(date, minister string) error {
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	store, err := sharedCorrectionContext.historyStore()
+	if err != nil {
+		return err
+	}
+	entries, err := store.List()
+	if err != nil {
+		return err
+	}
+	entry, err := history.Latest(entries, serviceDate)
+	if err != nil {
+		return err
+	}
+	if entry.Minister != minister {
+		return fmt.Errorf("history names %q, want %q", entry.Minister, minister)
+	}
+	return nil
+}
+
+func theCorrectionOutputShouldInclude(expected string) error {
+	if out := sharedCorrectionContext.output.String(); !strings.Contains(out, expected) {
+		return fmt.Errorf("output missing %q:\n%s", expected, out)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+//go:build integration
+
+package steps
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cucumber/godog"
+)
+
+func InitializeCuesScenario(ctx *godog.ScenarioContext) {
+	ctx.Step(`^a cue file exists at "([^"]*)" with:$`, aCueFileExistsAtWith)
+	ctx.Step(`^the trimmed video should have chapters "([^"]*)"$`, theTrimmedVideoShouldHaveChapters)
+	ctx.Step(`^the trimmed video should have no chapters$`, theTrimmedVideoShouldHaveNoChapters)
+}
+
+func aCueFileExistsAtWith(path string, content *godog.DocString) error {
+	p := getProcessContext()
+	actualPath := translatePath(p, path)
+	p.fileChecker.existingFiles[actualPath] = true
+	return p.fileChecker.fs.WriteFile(actualPath, []byte(content.Content))
+}
+
+// theTrimmedVideoShouldHaveChapters compares "Title@HH:MM:SS" entries separated by commas
+func theTrimmedVideoShouldHaveChapters(want string) error {
+	p := getProcessContext()
+	if len(p.trimmer.calls) == 0 {
+		return fmt.Errorf("the video was not trimmed")
+	}
+	var got []string
+	for _, c := range p.trimmer.calls[0].req.Chapters {
+		got = append(got, c.Title+"@"+c.At.String())
+	}
+	if strings.Join(got, ", ") != want {
+		return fmt.Errorf("expected chapters %q, got %q", want, strings.Join(got, ", "))
+	}
+	return nil
+}
+
+func theTrimmedVideoShouldHaveNoChapters() error {
+	p := getProcessContext()
+	if len(p.trimmer.calls) == 0 {
+		return fmt.Errorf("the video was not trimmed")
+	}
+	if chapters := p.trimmer.calls[0].req.Chapters; len(chapters) > 0 {
+		return fmt.Errorf("expected no chapters, got %+v", chapters)
+	}
+	return nil
+}
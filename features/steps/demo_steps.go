@@ -0,0 +1,218 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/video"
+
+	"github.com/cucumber/godog"
+)
+
+// demoContext holds state for demo mode scenarios
+type demoContext struct {
+	dir       string
+	now       time.Time
+	sample    *demoMockSample
+	trimmer   *demoMockTrimmer
+	extractor *demoMockExtractor
+	output    *bytes.Buffer
+	err       error
+}
+
+var sharedDemoContext *demoContext
+
+func getDemoContext() *demoContext {
+	return sharedDemoContext
+}
+
+// demoMockSample writes a stand-in recording instead of running ffmpeg
+type demoMockSample struct {
+	renders int
+	err     error
+}
+
+func (m *demoMockSample) Generate(ctx context.Context, outputPath string) error {
+	if m.err != nil {
+		return fmt.Errorf("ffmpeg could not render the sample recording: %w", m.err)
+	}
+	m.renders++
+	return os.WriteFile(outputPath, []byte("sample video"), 0644)
+}
+
+// demoMockTrimmer writes a stand-in trimmed video
+type demoMockTrimmer struct {
+	requests []*video.TrimRequest
+}
+
+func (m *demoMockTrimmer) Trim(ctx context.Context, req *video.TrimRequest, outputPath string) error {
+	m.requests = append(m.requests, req)
+	return os.WriteFile(outputPath, []byte("trimmed video"), 0644)
+}
+
+// demoMockExtractor writes a stand-in MP3
+type demoMockExtractor struct{}
+
+func (m *demoMockExtractor) Extract(ctx context.Context, req *video.AudioExtractionRequest, outputPath string) error {
+	return os.WriteFile(outputPath, []byte("audio"), 0644)
+}
+
+// demoFileChecker checks the demo folder on disk
+type demoFileChecker struct{}
+
+func (demoFileChecker) Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func InitializeDemoScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedDemoContext = &demoContext{
+			now:       time.Now(),
+			sample:    &demoMockSample{},
+			trimmer:   &demoMockTrimmer{},
+			extractor: &demoMockExtractor{},
+			output:    &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if d := getDemoContext(); d != nil && d.dir != "" {
+			os.RemoveAll(d.dir)
+		}
+		sharedDemoContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^a demo folder$`, aDemoFolder)
+	ctx.Step(`^today is "([^"]*)" for the demo$`, todayIsForTheDemo)
+	ctx.Step(`^the demo has run before$`, theDemoHasRunBefore)
+	ctx.Step(`^ffmpeg cannot render the sample$`, ffmpegCannotRenderTheSample)
+	ctx.Step(`^I run the demo$`, iRunTheDemo)
+	ctx.Step(`^the demo should succeed$`, theDemoShouldSucceed)
+	ctx.Step(`^the demo should fail with "([^"]*)"$`, theDemoShouldFailWith)
+	ctx.Step(`^the demo output should include "([^"]*)"$`, theDemoOutputShouldInclude)
+	ctx.Step(`^the email preview should include "([^"]*)"$`, theEmailPreviewShouldInclude)
+	ctx.Step(`^the sample recording should have been rendered$`, theSampleRecordingShouldHaveBeenRendered)
+	ctx.Step(`^the sample recording should not have been rendered again$`, theSampleRecordingShouldNotHaveBeenRenderedAgain)
+	ctx.Step(`^the demo should trim "([^"]*)" from "([^"]*)" to "([^"]*)"$`, theDemoShouldTrimFromTo)
+}
+
+func aDemoFolder() error {
+	dir, err := os.MkdirTemp("", "demo-test-*")
+	if err != nil {
+		return err
+	}
+	getDemoContext().dir = dir
+	return nil
+}
+
+func todayIsForTheDemo(date string) error {
+	now, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	getDemoContext().now = now
+	return nil
+}
+
+func theDemoHasRunBefore() error {
+	if err := iRunTheDemo(); err != nil {
+		return err
+	}
+	if err := theDemoShouldSucceed(); err != nil {
+		return err
+	}
+	d := getDemoContext()
+	d.output.Reset()
+	d.sample.renders = 0
+	return nil
+}
+
+func ffmpegCannotRenderTheSample() error {
+	getDemoContext().sample.err = errors.New("executable file not found in $PATH")
+	return nil
+}
+
+func iRunTheDemo() error {
+	d := getDemoContext()
+	d.err = cmd.RunDemoWithDependencies(context.Background(), d.dir, d.sample, d.trimmer, d.extractor, demoFileChecker{}, d.now, d.output)
+	return nil
+}
+
+func theDemoShouldSucceed() error {
+	d := getDemoContext()
+	if d.err != nil {
+		return fmt.Errorf("demo failed: %v\nOutput:\n%s", d.err, d.output.String())
+	}
+	return nil
+}
+
+func theDemoShouldFailWith(expected string) error {
+	d := getDemoContext()
+	if d.err == nil {
+		return fmt.Errorf("expected the demo to fail with %q, but it succeeded", expected)
+	}
+	if !strings.Contains(d.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got: %v", expected, d.err)
+	}
+	return nil
+}
+
+func theDemoOutputShouldInclude(expected string) error {
+	d := getDemoContext()
+	if !strings.Contains(d.output.String(), expected) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", expected, d.output.String())
+	}
+	return nil
+}
+
+func theEmailPreviewShouldInclude(expected string) error {
+	data, err := os.ReadFile(filepath.Join(getDemoContext().dir, cmd.DemoPreviewFile))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(data), expected) {
+		return fmt.Errorf("expected the email preview to include %q, got:\n%s", expected, data)
+	}
+	return nil
+}
+
+func theSampleRecordingShouldHaveBeenRendered() error {
+	if n := getDemoContext().sample.renders; n != 1 {
+		return fmt.Errorf("expected the sample to be rendered once, got %d", n)
+	}
+	return nil
+}
+
+func theSampleRecordingShouldNotHaveBeenRenderedAgain() error {
+	if n := getDemoContext().sample.renders; n != 0 {
+		return fmt.Errorf("expected the sample to be reused, but it was rendered %d times", n)
+	}
+	return nil
+}
+
+func theDemoShouldTrimFromTo(recording, start, end string) error {
+	d := getDemoContext()
+	if len(d.trimmer.requests) != 1 {
+		return fmt.Errorf("expected one trim, got %d", len(d.trimmer.requests))
+	}
+	req := d.trimmer.requests[0]
+	if req.Start.String() != start || req.End.String() != end {
+		return fmt.Errorf("trimmed from %s to %s, want %s to %s", req.Start, req.End, start, end)
+	}
+	if filepath.Base(req.SourcePath) != recording {
+		return fmt.Errorf("trimmed %s, want %s", req.SourcePath, recording)
+	}
+	return nil
+}
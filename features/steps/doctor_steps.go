@@ -0,0 +1,351 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/drive"
+
+	"github.com/cucumber/godog"
+	googledrive "google.golang.org/api/drive/v3"
+)
+
+// doctorEndpoints stand in for the Google API hosts; plain HTTP so the test
+// proxy can answer them without CONNECT
+var doctorEndpoints = []string{
+	"http://oauth2.googleapis.test/",
+	"http://www.googleapis.test/",
+}
+
+// doctorContext holds test state for doctor scenarios
+type doctorContext struct {
+	cfg      *config.Config
+	platform domainfs.Platform
+	proxy    *httptest.Server
+	mu       sync.Mutex
+	proxied  []string // Hosts the proxy was asked for
+	envVars  map[string]*string
+	output   *bytes.Buffer
+	err      error
+	srcRoot  string // Parent of the scenario's source directories
+	tokenDir string
+	granted  map[string][]string // Scopes each token file was granted
+	drive    *readOnlyFolders    // Drive the folder check looks in; nil skips it
+}
+
+// readOnlyFolders is an in-memory Drive where some folders are shared with
+// the account read-only
+type readOnlyFolders struct {
+	*drive.MemoryService
+	readOnly map[string]bool
+}
+
+func (s *readOnlyFolders) GetFile(ctx context.Context, fileID, fields string) (*googledrive.File, error) {
+	f, err := s.MemoryService.GetFile(ctx, fileID, fields)
+	if err == nil && s.readOnly[fileID] {
+		f.Capabilities.CanAddChildren = false
+	}
+	return f, err
+}
+
+// SharedDoctorContext is reset before each scenario
+var SharedDoctorContext *doctorContext
+
+func getDoctorContext() *doctorContext {
+	return SharedDoctorContext
+}
+
+func InitializeDoctorScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		SharedDoctorContext = &doctorContext{
+			cfg:      &config.Config{},
+			platform: domainfs.PlatformUnix,
+			envVars:  make(map[string]*string),
+			output:   &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if d := SharedDoctorContext; d != nil {
+			if d.srcRoot != "" {
+				os.RemoveAll(d.srcRoot)
+			}
+			if d.tokenDir != "" {
+				os.RemoveAll(d.tokenDir)
+			}
+			if d.proxy != nil {
+				d.proxy.Close()
+			}
+			for name, value := range d.envVars {
+				if value == nil {
+					os.Unsetenv(name)
+				} else {
+					os.Setenv(name, *value)
+				}
+			}
+		}
+		SharedDoctorContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^an HTTP proxy is running$`, anHTTPProxyIsRunning)
+	ctx.Step(`^the config proxy URL points at the proxy$`, theConfigProxyURLPointsAtTheProxy)
+	ctx.Step(`^the HTTP_PROXY environment variable points at the proxy$`, theHTTPProxyEnvironmentVariablePointsAtTheProxy)
+	ctx.Step(`^the config proxy URL points at a closed port$`, theConfigProxyURLPointsAtAClosedPort)
+	ctx.Step(`^the config CA bundle is "([^"]*)"$`, theConfigCABundleIs)
+	ctx.Step(`^the doctor source directory "([^"]*)" holds (\d+) recordings?$`, theDoctorSourceDirectoryHoldsRecordings)
+	ctx.Step(`^the doctor source directory "([^"]*)" is missing$`, theDoctorSourceDirectoryIsMissing)
+	ctx.Step(`^detection is enabled in the doctor config$`, detectionIsEnabledInTheDoctorConfig)
+	ctx.Step(`^doctor runs on the "([^"]*)" platform$`, doctorRunsOnThePlatform)
+	ctx.Step(`^the doctor config trimmed directory is "([^"]*)"$`, theDoctorConfigTrimmedDirectoryIs)
+	ctx.Step(`^the doctor config credentials file is missing$`, theDoctorConfigCredentialsFileIsMissing)
+	ctx.Step(`^the doctor config keeps the (drive|gmail) token with mode (\d+)$`, theDoctorConfigKeepsTheTokenWithMode)
+	ctx.Step(`^the (drive|gmail) token was granted "([^"]*)"$`, theTokenWasGranted)
+	ctx.Step(`^the doctor config asks Drive only for the files it creates$`, theDoctorConfigAsksDriveOnlyForTheFilesItCreates)
+	ctx.Step(`^the doctor config services folder is "([^"]*)"$`, theDoctorConfigServicesFolderIs)
+	ctx.Step(`^Drive has a (writable|read-only) folder "([^"]*)" named "([^"]*)"$`, driveHasAFolderNamed)
+	ctx.Step(`^I run doctor$`, iRunDoctor)
+	ctx.Step(`^doctor should pass$`, doctorShouldPass)
+	ctx.Step(`^doctor should fail with "([^"]*)"$`, doctorShouldFailWith)
+	ctx.Step(`^the doctor output should include "([^"]*)"$`, theDoctorOutputShouldInclude)
+	ctx.Step(`^the proxy should have been asked for "([^"]*)"$`, theProxyShouldHaveBeenAskedFor)
+}
+
+func anHTTPProxyIsRunning() error {
+	d := getDoctorContext()
+	d.proxy = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.mu.Lock()
+		d.proxied = append(d.proxied, r.URL.Host)
+		d.mu.Unlock()
+		// Google answers unauthenticated requests with 404 at the root
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	return nil
+}
+
+func theConfigProxyURLPointsAtTheProxy() error {
+	d := getDoctorContext()
+	d.cfg.Network.ProxyURL = d.proxy.URL
+	return nil
+}
+
+func theHTTPProxyEnvironmentVariablePointsAtTheProxy() error {
+	d := getDoctorContext()
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy"} {
+		if _, saved := d.envVars[name]; !saved {
+			if value, ok := os.LookupEnv(name); ok {
+				d.envVars[name] = &value
+			} else {
+				d.envVars[name] = nil
+			}
+		}
+		os.Unsetenv(name)
+	}
+	return os.Setenv("HTTP_PROXY", d.proxy.URL)
+}
+
+func theConfigProxyURLPointsAtAClosedPort() error {
+	// Reserve a port, then close it so connections are refused
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	addr := l.Addr().String()
+	l.Close()
+	getDoctorContext().cfg.Network.ProxyURL = "http://" + addr
+	return nil
+}
+
+func theConfigCABundleIs(name string) error {
+	getDoctorContext().cfg.Network.CABundle = filepath.Join(os.TempDir(), "doctor-test", name)
+	return nil
+}
+
+// doctorSourceDirectory adds a source directory named name, in priority order
+func doctorSourceDirectory(name string) (string, error) {
+	d := getDoctorContext()
+	if d.srcRoot == "" {
+		root, err := os.MkdirTemp("", "doctor-sources-*")
+		if err != nil {
+			return "", err
+		}
+		d.srcRoot = root
+	}
+	dir := filepath.Join(d.srcRoot, name)
+	d.cfg.Paths.SourceDirectories = append(d.cfg.Paths.SourceDirectories, dir)
+	return dir, nil
+}
+
+func theDoctorSourceDirectoryHoldsRecordings(name string, count int) error {
+	dir, err := doctorSourceDirectory(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for i := range count {
+		file := filepath.Join(dir, fmt.Sprintf("2025-12-%02d 10-00-00.mp4", i+1))
+		if err := os.WriteFile(file, []byte("video"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func theDoctorSourceDirectoryIsMissing(name string) error {
+	_, err := doctorSourceDirectory(name)
+	return err
+}
+
+func detectionIsEnabledInTheDoctorConfig() error {
+	getDoctorContext().cfg.Detection.Enabled = true
+	return nil
+}
+
+func doctorRunsOnThePlatform(platform string) error {
+	getDoctorContext().platform = domainfs.Platform(platform)
+	return nil
+}
+
+func theDoctorConfigTrimmedDirectoryIs(dir string) error {
+	getDoctorContext().cfg.Paths.TrimmedDirectory = dir
+	return nil
+}
+
+func theDoctorConfigCredentialsFileIsMissing() error {
+	getDoctorContext().cfg.Google.CredentialsFile = filepath.Join(os.TempDir(), "doctor-test", "credentials.json")
+	return nil
+}
+
+func theDoctorConfigKeepsTheTokenWithMode(service, mode string) error {
+	d := getDoctorContext()
+	if d.tokenDir == "" {
+		dir, err := os.MkdirTemp("", "doctor-tokens-*")
+		if err != nil {
+			return err
+		}
+		d.tokenDir = dir
+	}
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(d.tokenDir, service+"_token.json")
+	if err := os.WriteFile(path, []byte(`{"refresh_token":"r"}`), 0600); err != nil {
+		return err
+	}
+	if err := os.Chmod(path, os.FileMode(perm)); err != nil {
+		return err
+	}
+	if service == "drive" {
+		d.cfg.Google.DriveTokenFile = path
+	} else {
+		d.cfg.Google.GmailTokenFile = path
+	}
+	return nil
+}
+
+func theTokenWasGranted(service, scopes string) error {
+	d := getDoctorContext()
+	if d.granted == nil {
+		d.granted = make(map[string][]string)
+	}
+	d.granted[filepath.Join(d.tokenDir, service+"_token.json")] = strings.Fields(scopes)
+	return nil
+}
+
+func theDoctorConfigAsksDriveOnlyForTheFilesItCreates() error {
+	getDoctorContext().cfg.Google.ScopeMode = "file"
+	return nil
+}
+
+func theDoctorConfigServicesFolderIs(id string) error {
+	getDoctorContext().cfg.Google.ServicesFolderID = id
+	return nil
+}
+
+func driveHasAFolderNamed(access, id, name string) error {
+	getDoctorContext().drive = &readOnlyFolders{
+		MemoryService: drive.NewMemoryService(drive.WithMemoryFolder(id, name)),
+		readOnly:      map[string]bool{id: access == "read-only"},
+	}
+	return nil
+}
+
+func iRunDoctor() error {
+	d := getDoctorContext()
+	d.output.Reset()
+	scopes := func(ctx context.Context, tokenFile string) ([]string, error) {
+		granted, ok := d.granted[tokenFile]
+		if !ok {
+			return nil, fmt.Errorf("cannot refresh: invalid_grant")
+		}
+		return granted, nil
+	}
+	var client distribution.DriveClient
+	if d.drive != nil {
+		c, err := drive.NewClient(context.Background(), "", drive.WithDriveService(d.drive))
+		if err != nil {
+			return err
+		}
+		client = c
+	}
+	d.err = cmd.RunDoctorWithDependencies(context.Background(), d.cfg, doctorEndpoints, d.platform, scopes, client, d.output)
+	return nil
+}
+
+func doctorShouldPass() error {
+	d := getDoctorContext()
+	if d.err != nil {
+		return fmt.Errorf("doctor failed: %v\nOutput:\n%s", d.err, d.output.String())
+	}
+	return nil
+}
+
+func doctorShouldFailWith(expected string) error {
+	d := getDoctorContext()
+	if d.err == nil {
+		return fmt.Errorf("expected doctor to fail with %q, but it passed:\n%s", expected, d.output.String())
+	}
+	if !strings.Contains(d.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, d.err.Error())
+	}
+	return nil
+}
+
+func theDoctorOutputShouldInclude(expected string) error {
+	d := getDoctorContext()
+	if !strings.Contains(d.output.String(), expected) {
+		return fmt.Errorf("expected doctor output to include %q, got:\n%s", expected, d.output.String())
+	}
+	return nil
+}
+
+func theProxyShouldHaveBeenAskedFor(host string) error {
+	d := getDoctorContext()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, h := range d.proxied {
+		if h == host {
+			return nil
+		}
+	}
+	return fmt.Errorf("proxy was not asked for %s (got %v)", host, d.proxied)
+}
@@ -66,7 +66,7 @@ func (m *mockDriveService) EmptyTrash(ctx context.Context) error {
 	return nil
 }
 
-func (m *mockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string) (*googledrive.File, error) {
+func (m *mockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*googledrive.File, error) {
 	if m.shouldFail {
 		return nil, m.failError
 	}
@@ -3,6 +3,7 @@
 package steps
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	appnotif "nac-service-media/application/notification"
+	"nac-service-media/cmd"
 	"nac-service-media/domain/notification"
 	"nac-service-media/infrastructure/config"
 	"nac-service-media/infrastructure/gmail"
@@ -24,12 +26,23 @@ type mockGmailService struct {
 	sentMessages []*googlegmail.Message
 	shouldFail   bool
 	failError    error
+	failTo       string // Fail only messages addressed to this address
+	hang         bool   // Never respond; return only when ctx is done
 }
 
 func (m *mockGmailService) SendMessage(ctx context.Context, userID string, message *googlegmail.Message) (*googlegmail.Message, error) {
 	if m.shouldFail {
 		return nil, m.failError
 	}
+	if m.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if m.failTo != "" {
+		if raw, err := decodeMessage(message); err == nil && strings.Contains(raw, "<"+m.failTo+">") {
+			return nil, fmt.Errorf("gmail rejected %s", m.failTo)
+		}
+	}
 	m.sentMessages = append(m.sentMessages, message)
 	return &googlegmail.Message{Id: "test-message-id"}, nil
 }
@@ -45,9 +58,15 @@ type emailContext struct {
 	videoURL      string
 	serviceDate   time.Time
 	ministerName  string
+	serviceType   string
+	label         string
 	recipients    []notification.Recipient
 	lookupResult  []notification.Recipient
 	lookupErr     error
+	preview       *bytes.Buffer
+	sendTimeout   time.Duration
+	cancelled     bool // Send with an already cancelled context
+	terminal      bool // Preview the body as terminal text
 }
 
 // SharedEmailContext is reset before each scenario
@@ -87,10 +106,27 @@ func InitializeEmailScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I have uploaded files with URLs:$`, iHaveUploadedFilesWithURLs)
 	ctx.Step(`^the service date is "([^"]*)"$`, theServiceDateIs)
 	ctx.Step(`^the minister was "([^"]*)"$`, theMinisterWas)
+	ctx.Step(`^the email subject template is "([^"]*)"$`, theEmailSubjectTemplateIs)
+	ctx.Step(`^the service type is "([^"]*)"$`, theServiceTypeIs)
+	ctx.Step(`^the label is "([^"]*)"$`, theLabelIs)
+	ctx.Step(`^a local minister "([^"]*)" named "([^"]*)"$`, aLocalMinisterNamed)
+	ctx.Step(`^a CC rule "([^"]*)" for guest ministers that CCs "([^"]*)"$`, aCCRuleForGuestMinisters)
+	ctx.Step(`^a CC rule "([^"]*)" for service type "([^"]*)" that CCs "([^"]*)"$`, aCCRuleForServiceType)
+	ctx.Step(`^a recipient group "([^"]*)" with members "([^"]*)" and context "([^"]*)"$`, aRecipientGroupWithContext)
+	ctx.Step(`^the recipient group "([^"]*)" uses a template saying "([^"]*)"$`, theRecipientGroupUsesATemplateSaying)
+	ctx.Step(`^Gmail rejects mail to "([^"]*)"$`, gmailRejectsMailTo)
 
 	// Action steps
+	ctx.Step(`^email sandbox mode is on$`, emailSandboxModeIsOn)
+	ctx.Step(`^copying the sender on emails is turned off$`, copyingTheSenderOnEmailsIsTurnedOff)
+	ctx.Step(`^the operator address is "([^"]*)"$`, theOperatorAddressIs)
+	ctx.Step(`^the email greeting style is "([^"]*)"$`, theEmailGreetingStyleIs)
+	ctx.Step(`^the email greeting names up to (\d+) recipients$`, theEmailGreetingNamesUpToRecipients)
+	ctx.Step(`^the email greeting for larger groups is "([^"]*)"$`, theEmailGreetingForLargerGroupsIs)
 	ctx.Step(`^I send notification to "([^"]*)"$`, iSendNotificationTo)
 	ctx.Step(`^I lookup recipient "([^"]*)"$`, iLookupRecipient)
+	ctx.Step(`^I preview the notification to "([^"]*)"$`, iPreviewTheNotificationTo)
+	ctx.Step(`^I preview the notification to "([^"]*)" in the terminal$`, iPreviewTheNotificationToInTheTerminal)
 
 	// Assertion steps
 	ctx.Step(`^an email should be sent$`, anEmailShouldBeSent)
@@ -102,6 +138,19 @@ func InitializeEmailScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I should find "([^"]*)"$`, iShouldFind)
 	ctx.Step(`^I should receive an error about unknown recipient$`, iShouldReceiveAnErrorAboutUnknownRecipient)
 	ctx.Step(`^the email should CC "([^"]*)"$`, theEmailShouldCC)
+	ctx.Step(`^the email should not CC "([^"]*)"$`, theEmailShouldNotCC)
+	ctx.Step(`^the email should BCC "([^"]*)"$`, theEmailShouldBCC)
+	ctx.Step(`^the email should not BCC anyone$`, theEmailShouldNotBCCAnyone)
+	ctx.Step(`^the preview should include "([^"]*)"$`, thePreviewShouldInclude)
+	ctx.Step(`^the preview should show rule "([^"]*)" adding "([^"]*)" because "([^"]*)"$`, thePreviewShouldShowRule)
+	ctx.Step(`^no email should be sent$`, noEmailShouldBeSent)
+	ctx.Step(`^(\d+) emails? should be sent$`, emailsShouldBeSent)
+	ctx.Step(`^the email to "([^"]*)" should contain "([^"]*)"$`, theEmailToShouldContain)
+	ctx.Step(`^the email to "([^"]*)" should not contain "([^"]*)"$`, theEmailToShouldNotContain)
+	ctx.Step(`^sending should fail with "([^"]*)"$`, sendingShouldFailWith)
+	ctx.Step(`^Gmail does not respond$`, gmailDoesNotRespond)
+	ctx.Step(`^the email send timeout is (\d+) milliseconds$`, theEmailSendTimeoutIsMilliseconds)
+	ctx.Step(`^the run is cancelled before the email is sent$`, theRunIsCancelledBeforeTheEmailIsSent)
 	ctx.Step(`^the HTML body should contain clickable audio link$`, theHTMLBodyShouldContainClickableAudioLink)
 	ctx.Step(`^the HTML body should contain clickable video link$`, theHTMLBodyShouldContainClickableVideoLink)
 }
@@ -121,7 +170,7 @@ func validGmailCredentials() error {
 		Address: e.cfg.Email.FromAddress,
 	}
 
-	e.gmailClient = gmail.NewClient(from, gmail.WithGmailService(e.mockService))
+	e.gmailClient = gmail.NewClient(from, gmail.WithGmailService(e.mockService), gmail.WithBCCSender(e.cfg.Email.CopySender(false)))
 	e.service = appnotif.NewService(e.gmailClient, e.cfg.Email.FromName, "Jonathan")
 	return nil
 }
@@ -183,6 +232,125 @@ func theMinisterWas(minister string) error {
 	return nil
 }
 
+func theEmailSubjectTemplateIs(tmpl string) error {
+	e := getEmailContext()
+	subject, err := notification.ParseSubjectTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+	e.service = appnotif.NewService(e.gmailClient, e.cfg.Email.FromName, "Jonathan", appnotif.WithSubjectTemplate(subject))
+	return nil
+}
+
+func theServiceTypeIs(serviceType string) error {
+	getEmailContext().serviceType = serviceType
+	return nil
+}
+
+func theLabelIs(label string) error {
+	getEmailContext().label = label
+	return nil
+}
+
+func aLocalMinisterNamed(key, name string) error {
+	e := getEmailContext()
+	if e.cfg.Ministers == nil {
+		e.cfg.Ministers = make(map[string]config.MinisterConfig)
+	}
+	e.cfg.Ministers[key] = config.MinisterConfig{Name: name}
+	return nil
+}
+
+func aCCRuleForGuestMinisters(name, cc string) error {
+	e := getEmailContext()
+	e.cfg.Email.CCRules = append(e.cfg.Email.CCRules, config.CCRuleConfig{
+		Name: name,
+		When: config.CCRuleWhen{GuestMinister: true},
+		CC:   strings.Split(cc, ","),
+	})
+	return nil
+}
+
+func aCCRuleForServiceType(name, serviceType, cc string) error {
+	e := getEmailContext()
+	e.cfg.Email.CCRules = append(e.cfg.Email.CCRules, config.CCRuleConfig{
+		Name: name,
+		When: config.CCRuleWhen{ServiceType: []string{serviceType}},
+		CC:   strings.Split(cc, ","),
+	})
+	return nil
+}
+
+func aRecipientGroupWithContext(name, members, context string) error {
+	e := getEmailContext()
+	e.cfg.Email.Groups = append(e.cfg.Email.Groups, config.RecipientGroupConfig{
+		Name:    name,
+		Members: strings.Split(members, ","),
+		Context: context,
+	})
+	return nil
+}
+
+func theRecipientGroupUsesATemplateSaying(name, text string) error {
+	e := getEmailContext()
+	for i := range e.cfg.Email.Groups {
+		if e.cfg.Email.Groups[i].Name == name {
+			e.cfg.Email.Groups[i].PlainText = text + "\n\nAudio: {{.AudioURL}}"
+			e.cfg.Email.Groups[i].HTML = text + `<br><a href="{{.AudioURL}}">audio</a>`
+			return nil
+		}
+	}
+	return fmt.Errorf("no recipient group %q", name)
+}
+
+func gmailRejectsMailTo(address string) error {
+	getEmailContext().mockService.failTo = address
+	return nil
+}
+
+func emailSandboxModeIsOn() error {
+	getEmailContext().cfg.Email.Sandbox = true
+	return validGmailCredentials()
+}
+
+func copyingTheSenderOnEmailsIsTurnedOff() error {
+	off := false
+	getEmailContext().cfg.Email.BCCSender = &off
+	return validGmailCredentials()
+}
+
+func theEmailGreetingStyleIs(style string) error {
+	getEmailContext().cfg.Email.Greeting.Style = style
+	return nil
+}
+
+func theEmailGreetingNamesUpToRecipients(n int) error {
+	getEmailContext().cfg.Email.Greeting.MaxNames = n
+	return nil
+}
+
+func theEmailGreetingForLargerGroupsIs(group string) error {
+	getEmailContext().cfg.Email.Greeting.Group = group
+	return nil
+}
+
+func theOperatorAddressIs(address string) error {
+	getEmailContext().cfg.Email.OperatorAddress = address
+	return nil
+}
+
+// sandboxOptions returns the sandbox option when email.sandbox is set
+func (e *emailContext) sandboxOptions() ([]appnotif.Option, error) {
+	if !e.cfg.Email.Sandbox {
+		return nil, nil
+	}
+	operator, err := config.NewRecipientLookup(e.cfg, "").Operator()
+	if err != nil {
+		return nil, err
+	}
+	return []appnotif.Option{appnotif.WithSandbox(operator)}, nil
+}
+
 func iSendNotificationTo(recipientQuery string) error {
 	e := getEmailContext()
 
@@ -195,19 +363,112 @@ func iSendNotificationTo(recipientQuery string) error {
 	e.recipients = recipients
 
 	ccRecipients := lookup.GetDefaultCC()
+	ccRules, err := lookup.CCRules()
+	if err != nil {
+		e.err = err
+		return nil
+	}
+	appnotif.WithCCRules(ccRules)(e.service)
+	groups, err := lookup.Groups()
+	if err != nil {
+		e.err = err
+		return nil
+	}
+	appnotif.WithRecipientGroups(groups)(e.service)
+	greeting, err := e.cfg.Email.Greeting.Rules()
+	if err != nil {
+		e.err = err
+		return nil
+	}
+	appnotif.WithGreeting(greeting)(e.service)
+	sandbox, err := e.sandboxOptions()
+	if err != nil {
+		e.err = err
+		return nil
+	}
+	for _, opt := range sandbox {
+		opt(e.service)
+	}
+	if e.sendTimeout > 0 {
+		appnotif.WithSendTimeout(e.sendTimeout)(e.service)
+	}
 
-	err = e.service.Send(appnotif.SendRequest{
+	sendCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if e.cancelled {
+		cancel()
+	}
+	err = e.service.Send(sendCtx, appnotif.SendRequest{
 		To:           recipients,
 		CC:           ccRecipients,
 		ServiceDate:  e.serviceDate,
 		MinisterName: e.ministerName,
 		AudioURL:     e.audioURL,
 		VideoURL:     e.videoURL,
+		ServiceType:  e.serviceType,
+		Label:        e.label,
 	})
 	e.err = err
 	return nil
 }
 
+func iPreviewTheNotificationTo(recipientQuery string) error {
+	e := getEmailContext()
+
+	lookup := config.NewRecipientLookup(e.cfg, "")
+	recipients, err := lookup.LookupRecipients([]string{recipientQuery})
+	if err != nil {
+		e.err = err
+		return nil
+	}
+	ccRules, err := lookup.CCRules()
+	if err != nil {
+		e.err = err
+		return nil
+	}
+	groups, err := lookup.Groups()
+	if err != nil {
+		e.err = err
+		return nil
+	}
+	opts, err := e.sandboxOptions()
+	if err != nil {
+		e.err = err
+		return nil
+	}
+
+	if e.terminal {
+		opts = append(opts, appnotif.WithTerminalPreview(time.UTC))
+	}
+
+	e.preview = &bytes.Buffer{}
+	e.err = cmd.RunSendEmailWithDependencies(
+		context.Background(),
+		e.gmailClient,
+		e.cfg.Email.FromName,
+		"Jonathan",
+		recipients,
+		lookup.GetDefaultCC(),
+		e.serviceDate,
+		e.ministerName,
+		e.serviceType,
+		e.label,
+		"",
+		"",
+		e.audioURL,
+		e.videoURL,
+		true,
+		e.preview,
+		append(opts, appnotif.WithCCRules(ccRules), appnotif.WithRecipientGroups(groups))...,
+	)
+	return nil
+}
+
+func iPreviewTheNotificationToInTheTerminal(recipientQuery string) error {
+	getEmailContext().terminal = true
+	return iPreviewTheNotificationTo(recipientQuery)
+}
+
 func iLookupRecipient(query string) error {
 	e := getEmailContext()
 	lookup := config.NewRecipientLookup(e.cfg, "")
@@ -338,6 +599,95 @@ func theEmailShouldCC(expectedCC string) error {
 	return nil
 }
 
+func theEmailShouldNotCC(unexpected string) error {
+	e := getEmailContext()
+	if len(e.mockService.sentMessages) == 0 {
+		return fmt.Errorf("no email was sent")
+	}
+
+	raw, err := decodeMessage(e.mockService.sentMessages[0])
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(raw, unexpected) {
+		return fmt.Errorf("expected email not to include %q in:\n%s", unexpected, raw)
+	}
+	return nil
+}
+
+// sentHeaders returns the header block of the first sent email
+func sentHeaders() (string, error) {
+	e := getEmailContext()
+	if len(e.mockService.sentMessages) == 0 {
+		return "", fmt.Errorf("no email was sent")
+	}
+	raw, err := decodeMessage(e.mockService.sentMessages[0])
+	if err != nil {
+		return "", err
+	}
+	headers, _, _ := strings.Cut(raw, "\r\n\r\n")
+	return headers, nil
+}
+
+func theEmailShouldBCC(address string) error {
+	headers, err := sentHeaders()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(headers, "Bcc: "+address+"\r\n") {
+		return fmt.Errorf("expected a Bcc to %q in:\n%s", address, headers)
+	}
+	if strings.Contains(headers, "To: "+address) || strings.Contains(headers, "Cc: "+address) {
+		return fmt.Errorf("expected %q to be hidden from the visible recipients in:\n%s", address, headers)
+	}
+	return nil
+}
+
+func theEmailShouldNotBCCAnyone() error {
+	headers, err := sentHeaders()
+	if err != nil {
+		return err
+	}
+	if strings.Contains(headers, "Bcc:") {
+		return fmt.Errorf("expected no Bcc header in:\n%s", headers)
+	}
+	return nil
+}
+
+func thePreviewShouldInclude(expected string) error {
+	e := getEmailContext()
+	if e.err != nil {
+		return fmt.Errorf("preview failed: %v", e.err)
+	}
+	if e.preview == nil || !strings.Contains(e.preview.String(), expected) {
+		return fmt.Errorf("expected preview to include %q, got:\n%v", expected, e.preview)
+	}
+	return nil
+}
+
+func thePreviewShouldShowRule(rule, added, reason string) error {
+	e := getEmailContext()
+	if e.preview == nil {
+		return fmt.Errorf("no preview was shown")
+	}
+	for _, line := range strings.Split(e.preview.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, rule+":") && strings.Contains(line, reason) && strings.HasSuffix(line, "-> cc "+added) {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected rule %q adding %q because %q in preview:\n%s", rule, added, reason, e.preview.String())
+}
+
+func noEmailShouldBeSent() error {
+	e := getEmailContext()
+	if n := len(e.mockService.sentMessages); n != 0 {
+		return fmt.Errorf("expected no email to be sent, but %d were sent", n)
+	}
+	return nil
+}
+
 func theHTMLBodyShouldContainClickableAudioLink() error {
 	e := getEmailContext()
 	if len(e.mockService.sentMessages) == 0 {
@@ -381,3 +731,75 @@ func decodeMessage(msg *googlegmail.Message) (string, error) {
 	}
 	return string(decoded), nil
 }
+
+func emailsShouldBeSent(count int) error {
+	e := getEmailContext()
+	if n := len(e.mockService.sentMessages); n != count {
+		return fmt.Errorf("expected %d emails to be sent, but %d were sent", count, n)
+	}
+	return nil
+}
+
+// emailTo returns the sent message addressed to the given address
+func (e *emailContext) emailTo(address string) (string, error) {
+	for _, msg := range e.mockService.sentMessages {
+		raw, err := decodeMessage(msg)
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(raw, "\r\n") {
+			if strings.HasPrefix(line, "To: ") && strings.Contains(line, "<"+address+">") {
+				return raw, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no email was sent to %s", address)
+}
+
+func theEmailToShouldContain(address, expected string) error {
+	raw, err := getEmailContext().emailTo(address)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(raw, expected) {
+		return fmt.Errorf("email to %s doesn't contain %q in:\n%s", address, expected, raw)
+	}
+	return nil
+}
+
+func theEmailToShouldNotContain(address, unexpected string) error {
+	raw, err := getEmailContext().emailTo(address)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(raw, unexpected) {
+		return fmt.Errorf("expected email to %s not to contain %q in:\n%s", address, unexpected, raw)
+	}
+	return nil
+}
+
+func gmailDoesNotRespond() error {
+	getEmailContext().mockService.hang = true
+	return nil
+}
+
+func theEmailSendTimeoutIsMilliseconds(ms int) error {
+	getEmailContext().sendTimeout = time.Duration(ms) * time.Millisecond
+	return nil
+}
+
+func theRunIsCancelledBeforeTheEmailIsSent() error {
+	getEmailContext().cancelled = true
+	return nil
+}
+
+func sendingShouldFailWith(expected string) error {
+	e := getEmailContext()
+	if e.err == nil {
+		return fmt.Errorf("expected sending to fail with %q, but it succeeded", expected)
+	}
+	if !strings.Contains(e.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got: %v", expected, e.err)
+	}
+	return nil
+}
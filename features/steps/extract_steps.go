@@ -9,8 +9,10 @@ import (
 	"strings"
 	"time"
 
+	appvideo "nac-service-media/application/video"
 	"nac-service-media/cmd"
 	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/config"
 
 	"github.com/cucumber/godog"
 )
@@ -57,6 +59,8 @@ type extractContext struct {
 	output         *bytes.Buffer
 	err            error
 	resultPath     string
+	cfg            *config.Config
+	trackFlag      int // --audio-track, 0 when not passed
 }
 
 // SharedExtractContext is reset before each scenario via Before hook
@@ -94,6 +98,11 @@ func InitializeExtractScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the audio output file should be "([^"]*)"$`, theAudioOutputFileShouldBe)
 	ctx.Step(`^ffmpeg should have been called with audio arguments:$`, ffmpegShouldHaveBeenCalledWithAudioArguments)
 	ctx.Step(`^I should receive an error about missing source video$`, iShouldReceiveAnErrorAboutMissingSourceVideo)
+	ctx.Step(`^audio\.track is (\d+) in config with trimmed videos in "([^"]*)"$`, audioTrackIsInConfig)
+	ctx.Step(`^a raw recording at "([^"]*)"$`, aTrimmedVideoAt)
+	ctx.Step(`^--audio-track (\d+) is passed$`, audioTrackIsPassed)
+	ctx.Step(`^I extract audio with the configured track for service date "([^"]*)"$`, iExtractAudioWithTheConfiguredTrackForServiceDate)
+	ctx.Step(`^the MP3 should be read from audio track (\d+)$`, theMP3ShouldBeReadFromAudioTrack)
 }
 
 func theAudioOutputDirectoryIs(dir string) error {
@@ -252,3 +261,56 @@ func iShouldReceiveAnErrorAboutMissingSourceVideo() error {
 	}
 	return nil
 }
+
+func audioTrackIsInConfig(track int, trimmedDir string) error {
+	e := getExtractContext()
+	e.cfg = &config.Config{
+		Audio: config.AudioConfig{Track: track},
+		Paths: config.PathsConfig{TrimmedDirectory: trimmedDir},
+	}
+	return nil
+}
+
+func audioTrackIsPassed(track int) error {
+	e := getExtractContext()
+	e.trackFlag = track
+	return nil
+}
+
+func iExtractAudioWithTheConfiguredTrackForServiceDate(dateStr string) error {
+	e := getExtractContext()
+
+	serviceDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid service date format: %w", err)
+	}
+	e.serviceDate = serviceDate
+
+	track := cmd.ExtractAudioTrack(e.cfg, e.trackFlag, e.sourcePath, false)
+	e.err = cmd.RunExtractAudioWithDependencies(
+		context.Background(),
+		e.extractor,
+		e.fileChecker,
+		e.outputDir,
+		e.bitrate,
+		e.sourcePath,
+		e.serviceDate,
+		e.output,
+		appvideo.WithAudioTrack(track),
+	)
+	if e.err != nil {
+		return fmt.Errorf("unexpected error: %v", e.err)
+	}
+	return nil
+}
+
+func theMP3ShouldBeReadFromAudioTrack(track int) error {
+	e := getExtractContext()
+	if len(e.extractor.calls) == 0 {
+		return fmt.Errorf("ffmpeg was not called")
+	}
+	if got := e.extractor.calls[0].req.AudioTrack; got != track {
+		return fmt.Errorf("expected audio track %d, got %d", track, got)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"nac-service-media/cmd"
+	domainfs "nac-service-media/domain/filesystem"
+
+	"github.com/cucumber/godog"
+)
+
+// mockGrowthDetector reports files as growing for a number of checks
+type mockGrowthDetector struct {
+	mu      sync.Mutex
+	growing map[string]int // Base name -> checks that still report growing (-1 = forever)
+}
+
+func (m *mockGrowthDetector) IsGrowing(path string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	remaining := m.growing[filepath.Base(path)]
+	if remaining == 0 {
+		return false, nil
+	}
+	if remaining > 0 {
+		m.growing[filepath.Base(path)] = remaining - 1
+	}
+	return true, nil
+}
+
+// finderContext holds test state for source file selection scenarios
+type finderContext struct {
+	dir      string
+	extra    []string // Lower-priority source directories, after dir
+	growth   *mockGrowthDetector
+	policy   string
+	output   *bytes.Buffer
+	selected string
+	err      error
+}
+
+// SharedFinderContext is reset before each scenario
+var SharedFinderContext *finderContext
+
+func getFinderContext() *finderContext {
+	return SharedFinderContext
+}
+
+func InitializeFinderScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		SharedFinderContext = &finderContext{
+			growth: &mockGrowthDetector{growing: make(map[string]int)},
+			output: &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if f := SharedFinderContext; f != nil {
+			for _, dir := range append([]string{f.dir}, f.extra...) {
+				if dir != "" {
+					os.RemoveAll(dir)
+				}
+			}
+		}
+		SharedFinderContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the source directory holds recordings "([^"]*)"$`, theSourceDirectoryHoldsRecordings)
+	ctx.Step(`^"([^"]*)" is still being recorded$`, isStillBeingRecorded)
+	ctx.Step(`^"([^"]*)" finishes recording after (\d+) checks?$`, finishesRecordingAfterChecks)
+	ctx.Step(`^the in-progress policy is "([^"]*)"$`, theInProgressPolicyIs)
+	ctx.Step(`^a lower-priority source directory holds recordings "([^"]*)"$`, aLowerPrioritySourceDirectoryHoldsRecordings)
+	ctx.Step(`^the source directory is emptied$`, theSourceDirectoryIsEmptied)
+	ctx.Step(`^I look for the newest recording$`, iLookForTheNewestRecording)
+	ctx.Step(`^I look for the newest recording across the source directories$`, iLookForTheNewestRecordingAcrossTheSourceDirectories)
+	ctx.Step(`^the recording should come from the (first|second) source directory$`, theRecordingShouldComeFromTheSourceDirectory)
+	ctx.Step(`^the recording "([^"]*)" should be selected$`, theRecordingShouldBeSelected)
+	ctx.Step(`^finding the recording should fail with "([^"]*)"$`, findingTheRecordingShouldFailWith)
+	ctx.Step(`^the finder output should include "([^"]*)"$`, theFinderOutputShouldInclude)
+}
+
+func theSourceDirectoryHoldsRecordings(names string) error {
+	dir, err := recordingsDirectory(names)
+	if err != nil {
+		return err
+	}
+	getFinderContext().dir = dir
+	return nil
+}
+
+func aLowerPrioritySourceDirectoryHoldsRecordings(names string) error {
+	dir, err := recordingsDirectory(names)
+	if err != nil {
+		return err
+	}
+	f := getFinderContext()
+	f.extra = append(f.extra, dir)
+	return nil
+}
+
+// recordingsDirectory creates a temporary directory holding the named recordings
+func recordingsDirectory(names string) (string, error) {
+	dir, err := os.MkdirTemp("", "finder-*")
+	if err != nil {
+		return "", err
+	}
+	for _, name := range strings.Split(names, ",") {
+		if err := os.WriteFile(filepath.Join(dir, strings.TrimSpace(name)), []byte("video"), 0644); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+func theSourceDirectoryIsEmptied() error {
+	f := getFinderContext()
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.Remove(filepath.Join(f.dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isStillBeingRecorded(name string) error {
+	getFinderContext().growth.growing[name] = -1
+	return nil
+}
+
+func finishesRecordingAfterChecks(name string, checks int) error {
+	getFinderContext().growth.growing[name] = checks
+	return nil
+}
+
+func theInProgressPolicyIs(policy string) error {
+	getFinderContext().policy = policy
+	return nil
+}
+
+func (f *finderContext) finder() *cmd.ProductionFileFinder {
+	return &cmd.ProductionFileFinder{
+		Growth:       f.growth,
+		InProgress:   f.policy,
+		Output:       f.output,
+		WaitTimeout:  time.Second,
+		PollInterval: time.Millisecond,
+	}
+}
+
+func iLookForTheNewestRecording() error {
+	f := getFinderContext()
+	f.selected, f.err = f.finder().FindNewestFile(f.dir, ".mp4")
+	return nil
+}
+
+func iLookForTheNewestRecordingAcrossTheSourceDirectories() error {
+	f := getFinderContext()
+	f.selected, f.err = domainfs.FindNewestSource(f.finder(), append([]string{f.dir}, f.extra...), ".mp4")
+	return nil
+}
+
+func theRecordingShouldComeFromTheSourceDirectory(which string) error {
+	f := getFinderContext()
+	want := f.dir
+	if which == "second" {
+		want = f.extra[0]
+	}
+	if filepath.Dir(f.selected) != want {
+		return fmt.Errorf("expected the recording to come from %s, got %s", want, f.selected)
+	}
+	return nil
+}
+
+func theRecordingShouldBeSelected(name string) error {
+	f := getFinderContext()
+	if f.err != nil {
+		return fmt.Errorf("expected %s to be selected, got error: %v", name, f.err)
+	}
+	if filepath.Base(f.selected) != name {
+		return fmt.Errorf("expected %s to be selected, got %s", name, filepath.Base(f.selected))
+	}
+	return nil
+}
+
+func findingTheRecordingShouldFailWith(expected string) error {
+	f := getFinderContext()
+	if f.err == nil {
+		return fmt.Errorf("expected an error containing %q, but %s was selected", expected, f.selected)
+	}
+	if !strings.Contains(f.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, f.err.Error())
+	}
+	return nil
+}
+
+func theFinderOutputShouldInclude(expected string) error {
+	f := getFinderContext()
+	if !strings.Contains(f.output.String(), expected) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", expected, f.output.String())
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/drive"
+
+	"github.com/cucumber/godog"
+)
+
+// expiryContext holds a Drive of privately shared files for drive expire
+type expiryContext struct {
+	service *drive.MemoryService
+	client  *drive.Client
+	output  *bytes.Buffer
+	err     error
+}
+
+var expiryCtx *expiryContext
+
+// InitializeFuneralScenario registers the steps for private service types
+func InitializeFuneralScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		service := drive.NewMemoryService()
+		client, err := drive.NewClient(c, "", drive.WithDriveService(service))
+		if err != nil {
+			return c, err
+		}
+		expiryCtx = &expiryContext{service: service, client: client, output: &bytes.Buffer{}}
+		return c, nil
+	})
+
+	ctx.Step(`^the process config has a CC rule "([^"]*)" for service type "([^"]*)" copying "([^"]*)"$`, theProcessConfigHasACCRuleForServiceType)
+	ctx.Step(`^the uploaded (audio|video) should be shared only with "([^"]*)"$`, theUploadedFileShouldBeSharedOnlyWith)
+	ctx.Step(`^the uploaded audio should stop being shared after (\d+) days and be deleted after (\d+) days$`, theUploadedAudioShouldExpireAfter)
+
+	ctx.Step(`^Drive has "([^"]*)" shared with "([^"]*)" until "([^"]*)" and kept until "([^"]*)"$`, driveHasPrivatelySharedFile)
+	ctx.Step(`^Drive has "([^"]*)" shared with anyone with the link$`, driveHasPubliclySharedFile)
+	ctx.Step(`^I run drive expire on "([^"]*)"$`, iRunDriveExpireOn)
+	ctx.Step(`^"([^"]*)" should be shared with "([^"]*)"$`, driveFileShouldBeSharedWith)
+	ctx.Step(`^"([^"]*)" should not be shared with anyone$`, driveFileShouldNotBeSharedWithAnyone)
+	ctx.Step(`^"([^"]*)" should still be shared with anyone with the link$`, driveFileShouldStillBePublic)
+	ctx.Step(`^"([^"]*)" should be deleted from Drive$`, driveFileShouldBeDeleted)
+	ctx.Step(`^the expire output should include "([^"]*)"$`, theExpireOutputShouldInclude)
+}
+
+func theProcessConfigHasACCRuleForServiceType(name, serviceType, cc string) error {
+	p := getProcessContext()
+	p.cfg.Email.CCRules = append(p.cfg.Email.CCRules, config.CCRuleConfig{
+		Name: name,
+		When: config.CCRuleWhen{ServiceType: []string{serviceType}},
+		CC:   []string{cc},
+	})
+	return nil
+}
+
+// uploadedFile returns the file uploaded to the process Drive with ext
+func uploadedFile(ext string) (string, map[string]string, error) {
+	p := getProcessContext()
+	for _, f := range p.driveService.uploadedFiles {
+		if strings.HasSuffix(f.Name, ext) {
+			return f.Id, f.AppProperties, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no %s file was uploaded", ext)
+}
+
+func theUploadedFileShouldBeSharedOnlyWith(kind, want string) error {
+	ext := ".mp3"
+	if kind == "video" {
+		ext = ".mp4"
+	}
+	id, _, err := uploadedFile(ext)
+	if err != nil {
+		return err
+	}
+	var readers []string
+	for _, perm := range getProcessContext().driveService.grants[id] {
+		if perm.Type != "user" || perm.Role != "reader" {
+			return fmt.Errorf("the %s was shared with type=%s, role=%s", kind, perm.Type, perm.Role)
+		}
+		readers = append(readers, perm.EmailAddress)
+	}
+	sort.Strings(readers)
+	if got := strings.Join(readers, ", "); got != want {
+		return fmt.Errorf("the %s is shared with %q, want %q", kind, got, want)
+	}
+	return nil
+}
+
+func theUploadedAudioShouldExpireAfter(revokeDays, deleteDays int) error {
+	_, props, err := uploadedFile(".mp3")
+	if err != nil {
+		return err
+	}
+	today := time.Now()
+	for key, days := range map[string]int{distribution.PropertyRevokeAfter: revokeDays, distribution.PropertyDeleteAfter: deleteDays} {
+		want := today.AddDate(0, 0, days).Format("2006-01-02")
+		if props[key] != want {
+			return fmt.Errorf("%s = %q, want %q (properties %v)", key, props[key], want, props)
+		}
+	}
+	return nil
+}
+
+// storeFile puts a file in the expiry Drive's Services folder
+func storeFile(name string, props map[string]string) (string, error) {
+	f, err := expiryCtx.service.UploadReader(context.Background(), name, distribution.MimeTypeMP3, "services", strings.NewReader("audio"), props)
+	if err != nil {
+		return "", err
+	}
+	return f.Id, nil
+}
+
+func driveHasPrivatelySharedFile(name, readers, revokeAfter, deleteAfter string) error {
+	id, err := storeFile(name, map[string]string{
+		distribution.PropertyRevokeAfter: revokeAfter,
+		distribution.PropertyDeleteAfter: deleteAfter,
+	})
+	if err != nil {
+		return err
+	}
+	for _, address := range strings.Split(readers, ",") {
+		if err := expiryCtx.client.ShareWithUser(context.Background(), id, strings.TrimSpace(address)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func driveHasPubliclySharedFile(name string) error {
+	id, err := storeFile(name, nil)
+	if err != nil {
+		return err
+	}
+	return expiryCtx.client.SetPublicSharing(context.Background(), id)
+}
+
+func iRunDriveExpireOn(date string) error {
+	now, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	expiryCtx.err = cmd.RunDriveExpireWithDependencies(context.Background(), expiryCtx.client, "services", now, expiryCtx.output)
+	return nil
+}
+
+// storedFileID returns the ID of a file in the expiry Drive, or "" once it is gone
+func storedFileID(name string) string {
+	for _, f := range expiryCtx.service.Files() {
+		if f.Name == name {
+			return f.Id
+		}
+	}
+	return ""
+}
+
+func driveFileShouldBeSharedWith(name, want string) error {
+	id := storedFileID(name)
+	if id == "" {
+		return fmt.Errorf("%s is not in Drive", name)
+	}
+	if got := strings.Join(expiryCtx.service.SharedWith(id), ", "); got != want {
+		return fmt.Errorf("%s is shared with %q, want %q", name, got, want)
+	}
+	return nil
+}
+
+func driveFileShouldNotBeSharedWithAnyone(name string) error {
+	id := storedFileID(name)
+	if id == "" {
+		return fmt.Errorf("%s is not in Drive", name)
+	}
+	if got := expiryCtx.service.SharedWith(id); len(got) > 0 || expiryCtx.service.IsPublic(id) {
+		return fmt.Errorf("%s is still shared with %v (public: %v)", name, got, expiryCtx.service.IsPublic(id))
+	}
+	return nil
+}
+
+func driveFileShouldStillBePublic(name string) error {
+	if id := storedFileID(name); id == "" || !expiryCtx.service.IsPublic(id) {
+		return fmt.Errorf("%s is no longer shared with anyone with the link", name)
+	}
+	return nil
+}
+
+func driveFileShouldBeDeleted(name string) error {
+	if storedFileID(name) != "" {
+		return fmt.Errorf("%s is still in Drive", name)
+	}
+	return nil
+}
+
+func theExpireOutputShouldInclude(text string) error {
+	if expiryCtx.err != nil {
+		return fmt.Errorf("drive expire failed: %w", expiryCtx.err)
+	}
+	if !strings.Contains(expiryCtx.output.String(), text) {
+		return fmt.Errorf("output does not include %q:\n%s", text, expiryCtx.output.String())
+	}
+	return nil
+}
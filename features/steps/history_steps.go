@@ -0,0 +1,520 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/history"
+	infrahistory "nac-service-media/infrastructure/history"
+
+	"github.com/cucumber/godog"
+)
+
+// historyContext holds test state for history scenarios
+type historyContext struct {
+	dir    string
+	store  *infrahistory.JSONStore
+	output *bytes.Buffer
+	count  int
+	err    error
+}
+
+// SharedHistoryContext is reset before each scenario
+var SharedHistoryContext *historyContext
+
+func getHistoryContext() *historyContext {
+	return SharedHistoryContext
+}
+
+func InitializeHistoryScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		SharedHistoryContext = &historyContext{output: &bytes.Buffer{}}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if h := SharedHistoryContext; h != nil && h.dir != "" {
+			os.RemoveAll(h.dir)
+		}
+		SharedHistoryContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^a history store$`, aHistoryStore)
+	ctx.Step(`^the history contains services:$`, theHistoryContainsServices)
+	ctx.Step(`^I export history as "([^"]*)"$`, iExportHistoryAs)
+	ctx.Step(`^I export history as "([^"]*)" from "([^"]*)" to "([^"]*)"$`, iExportHistoryFromTo)
+	ctx.Step(`^I export history as "([^"]*)" for year "([^"]*)"$`, iExportHistoryForYear)
+	ctx.Step(`^the export should contain (\d+) services?$`, theExportShouldContainServices)
+	ctx.Step(`^the export should include "([^"]*)"$`, theExportShouldInclude)
+	ctx.Step(`^the export should not include "([^"]*)"$`, theExportShouldNotInclude)
+	ctx.Step(`^the export should be valid JSON with (\d+) services?$`, theExportShouldBeValidJSON)
+	ctx.Step(`^the exported JSON should include a service with (\w+) "([^"]*)"$`, theExportedJSONShouldIncludeAServiceWith)
+	ctx.Step(`^the export should fail with "([^"]*)"$`, theExportShouldFailWith)
+	ctx.Step(`^the history should record "([^"]*)" with (\d+) recipients? and duration (\d+) seconds$`, theHistoryShouldRecord)
+	ctx.Step(`^the history should be empty$`, theHistoryShouldBeEmpty)
+	ctx.Step(`^I add the history note "([^"]*)" to "([^"]*)"$`, iAddTheHistoryNoteTo)
+	ctx.Step(`^I list history notes$`, iListHistoryNotes)
+	ctx.Step(`^I list history notes for year "([^"]*)"$`, iListHistoryNotesForYear)
+	ctx.Step(`^the history for "([^"]*)" should have notes "([^"]*)"$`, theHistoryForShouldHaveNotes)
+	ctx.Step(`^the history for "([^"]*)" should have title "([^"]*)" and scripture "([^"]*)"$`, theHistoryForShouldHaveTitle)
+	ctx.Step(`^the note output should include "([^"]*)"$`, theExportShouldInclude)
+	ctx.Step(`^the note output should not include "([^"]*)"$`, theExportShouldNotInclude)
+	ctx.Step(`^adding the note should fail with "([^"]*)"$`, theExportShouldFailWith)
+	ctx.Step(`^I show detection stats with threshold ([\d.]+) over (\d+) weeks$`, iShowDetectionStatsWithThresholdOverWeeks)
+	ctx.Step(`^I show the last (\d+) detection stats with threshold ([\d.]+) over (\d+) weeks$`, iShowTheLastDetectionStats)
+	ctx.Step(`^the detection stats should include "([^"]*)"$`, theExportShouldInclude)
+	ctx.Step(`^the detection stats should not include "([^"]*)"$`, theExportShouldNotInclude)
+	ctx.Step(`^the history for "([^"]*)" should have detection confidence ([\d.]+)$`, theHistoryForShouldHaveDetectionConfidence)
+	ctx.Step(`^the history for "([^"]*)" should record an early detection exit$`, theHistoryForShouldRecordAnEarlyDetectionExit)
+	ctx.Step(`^the history for "([^"]*)" should record folder "([^"]*)"$`, theHistoryForShouldRecordFolder)
+	ctx.Step(`^the history for "([^"]*)" should record an audio size of (\d+) bytes$`, theHistoryForShouldRecordAnAudioSizeOf)
+	ctx.Step(`^I list history$`, iListHistory)
+	ctx.Step(`^I list history for year "([^"]*)"$`, iListHistoryForYear)
+	ctx.Step(`^I show history for "([^"]*)"$`, iShowHistoryFor)
+	ctx.Step(`^the history output should include "([^"]*)"$`, theExportShouldInclude)
+	ctx.Step(`^the history output should not include "([^"]*)"$`, theExportShouldNotInclude)
+	ctx.Step(`^showing history should fail with "([^"]*)"$`, theExportShouldFailWith)
+	ctx.Step(`^I show minister stats for year "([^"]*)"$`, iShowMinisterStatsForYear)
+	ctx.Step(`^I show minister stats from "([^"]*)" to "([^"]*)"$`, iShowMinisterStatsFromTo)
+	ctx.Step(`^the minister stats should list "([^"]*)" with (\d+) services? averaging "([^"]*)" last served "([^"]*)"$`, theMinisterStatsShouldList)
+	ctx.Step(`^the minister stats should include "([^"]*)"$`, theExportShouldInclude)
+}
+
+func aHistoryStore() error {
+	h := getHistoryContext()
+	dir, err := os.MkdirTemp("", "history-test-*")
+	if err != nil {
+		return err
+	}
+	h.dir = dir
+	h.store = infrahistory.NewJSONStore(filepath.Join(dir, "history.jsonl"))
+	return nil
+}
+
+func theHistoryContainsServices(table *godog.Table) error {
+	h := getHistoryContext()
+	if h.store == nil {
+		if err := aHistoryStore(); err != nil {
+			return err
+		}
+	}
+
+	header := table.Rows[0].Cells
+	for _, row := range table.Rows[1:] {
+		e := history.Entry{Outcome: history.OutcomeSuccess}
+		for i, cell := range row.Cells {
+			v := cell.Value
+			switch header[i].Value {
+			case "date":
+				d, err := time.Parse("2006-01-02", v)
+				if err != nil {
+					return fmt.Errorf("invalid date %q: %w", v, err)
+				}
+				e.ServiceDate = d
+			case "minister":
+				e.Minister = v
+			case "title":
+				e.Title = v
+			case "duration_seconds":
+				e.DurationSeconds, _ = strconv.Atoi(v)
+			case "video_size":
+				e.VideoSize, _ = strconv.ParseInt(v, 10, 64)
+			case "audio_size":
+				e.AudioSize, _ = strconv.ParseInt(v, 10, 64)
+			case "recipients":
+				n, _ := strconv.Atoi(v)
+				for j := 0; j < n; j++ {
+					e.Recipients = append(e.Recipients, fmt.Sprintf("r%d@example.com", j))
+				}
+			case "video_url":
+				e.VideoURL = v
+			case "audio_url":
+				e.AudioURL = v
+			case "confidence":
+				e.DetectionConfidence, _ = strconv.ParseFloat(v, 64)
+			case "camera_angle":
+				e.CameraAngle = v
+			case "early_exit":
+				e.DetectionEarlyExit = v == "yes"
+			case "video_file_id":
+				e.VideoFileID = v
+			case "audio_file_id":
+				e.AudioFileID = v
+			case "source_file":
+				e.SourceFile = v
+			case "start_time":
+				e.StartTime = v
+			case "end_time":
+				e.EndTime = v
+			case "processed_at":
+				d, err := time.Parse("2006-01-02", v)
+				if err != nil {
+					return fmt.Errorf("invalid processed_at %q: %w", v, err)
+				}
+				e.ProcessedAt = d
+			case "processed_days_ago":
+				days, err := strconv.Atoi(v)
+				if err != nil {
+					return fmt.Errorf("invalid processed_days_ago %q: %w", v, err)
+				}
+				e.ProcessedAt = time.Now().AddDate(0, 0, -days)
+			}
+		}
+		if err := h.store.Append(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runHistoryExport(format, from, to, year string) error {
+	h := getHistoryContext()
+	if h.store == nil {
+		return fmt.Errorf("no history store configured")
+	}
+	h.output.Reset()
+	h.count, h.err = cmd.RunHistoryExportWithDependencies(h.store, format, from, to, year, h.output)
+	return nil
+}
+
+func iExportHistoryAs(format string) error {
+	return runHistoryExport(format, "", "", "")
+}
+
+func iExportHistoryFromTo(format, from, to string) error {
+	return runHistoryExport(format, from, to, "")
+}
+
+func iExportHistoryForYear(format, year string) error {
+	return runHistoryExport(format, "", "", year)
+}
+
+func theExportShouldContainServices(n int) error {
+	h := getHistoryContext()
+	if h.err != nil {
+		return fmt.Errorf("export failed: %v", h.err)
+	}
+	if h.count != n {
+		return fmt.Errorf("expected %d services, got %d:\n%s", n, h.count, h.output.String())
+	}
+	return nil
+}
+
+func theExportShouldInclude(expected string) error {
+	h := getHistoryContext()
+	if !strings.Contains(h.output.String(), expected) {
+		return fmt.Errorf("expected export to include %q, got:\n%s", expected, h.output.String())
+	}
+	return nil
+}
+
+func theExportShouldNotInclude(unexpected string) error {
+	h := getHistoryContext()
+	if strings.Contains(h.output.String(), unexpected) {
+		return fmt.Errorf("expected export not to include %q, got:\n%s", unexpected, h.output.String())
+	}
+	return nil
+}
+
+func theExportShouldBeValidJSON(n int) error {
+	h := getHistoryContext()
+	if h.err != nil {
+		return fmt.Errorf("export failed: %v", h.err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(h.output.Bytes(), &rows); err != nil {
+		return fmt.Errorf("export is not valid JSON: %w\n%s", err, h.output.String())
+	}
+	if len(rows) != n {
+		return fmt.Errorf("expected %d JSON rows, got %d", n, len(rows))
+	}
+	return nil
+}
+
+func theExportedJSONShouldIncludeAServiceWith(field, value string) error {
+	h := getHistoryContext()
+	var rows []map[string]any
+	if err := json.Unmarshal(h.output.Bytes(), &rows); err != nil {
+		return fmt.Errorf("export is not valid JSON: %w\n%s", err, h.output.String())
+	}
+	for _, row := range rows {
+		if v, ok := row[field]; ok && fmt.Sprint(v) == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("no exported service with %s %q in:\n%s", field, value, h.output.String())
+}
+
+func theExportShouldFailWith(expected string) error {
+	h := getHistoryContext()
+	if h.err == nil {
+		return fmt.Errorf("expected export to fail with %q, but it succeeded", expected)
+	}
+	if !strings.Contains(h.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, h.err.Error())
+	}
+	return nil
+}
+
+func theHistoryShouldRecord(date string, recipients, duration int) error {
+	h := getHistoryContext()
+	entries, err := h.store.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ServiceDate.Format("2006-01-02") != date {
+			continue
+		}
+		if len(e.Recipients) != recipients {
+			return fmt.Errorf("expected %d recipients, got %v", recipients, e.Recipients)
+		}
+		if e.DurationSeconds != duration {
+			return fmt.Errorf("expected duration %d seconds, got %d", duration, e.DurationSeconds)
+		}
+		if e.Outcome != history.OutcomeSuccess {
+			return fmt.Errorf("expected outcome %q, got %q", history.OutcomeSuccess, e.Outcome)
+		}
+		return nil
+	}
+	return fmt.Errorf("no history entry for %s in %d entries", date, len(entries))
+}
+
+func theHistoryShouldBeEmpty() error {
+	h := getHistoryContext()
+	entries, err := h.store.List()
+	if err != nil {
+		return err
+	}
+	if len(entries) != 0 {
+		return fmt.Errorf("expected empty history, got %d entries", len(entries))
+	}
+	return nil
+}
+
+func iAddTheHistoryNoteTo(text, date string) error {
+	h := getHistoryContext()
+	if h.store == nil {
+		return fmt.Errorf("no history store configured")
+	}
+	h.output.Reset()
+	h.err = cmd.RunHistoryNoteAddWithDependencies(h.store, date, text, h.output)
+	return nil
+}
+
+func iListHistoryNotes() error {
+	return iListHistoryNotesForYear("")
+}
+
+func iListHistoryNotesForYear(year string) error {
+	h := getHistoryContext()
+	if h.store == nil {
+		return fmt.Errorf("no history store configured")
+	}
+	h.output.Reset()
+	h.err = cmd.RunHistoryNoteListWithDependencies(h.store, "", "", year, h.output)
+	return nil
+}
+
+func iListHistory() error {
+	return iListHistoryForYear("")
+}
+
+func iListHistoryForYear(year string) error {
+	h := getHistoryContext()
+	if h.store == nil {
+		return fmt.Errorf("no history store configured")
+	}
+	h.output.Reset()
+	h.err = cmd.RunHistoryListWithDependencies(h.store, "", "", year, h.output)
+	return nil
+}
+
+func iShowHistoryFor(date string) error {
+	h := getHistoryContext()
+	if h.store == nil {
+		return fmt.Errorf("no history store configured")
+	}
+	h.output.Reset()
+	h.err = cmd.RunHistoryShowWithDependencies(h.store, date, h.output)
+	return nil
+}
+
+// theHistoryForShouldHaveNotes checks the notes on the latest entry for date,
+// given as a "; "-separated list
+func theHistoryForShouldHaveNotes(date, want string) error {
+	h := getHistoryContext()
+	entries, err := h.store.List()
+	if err != nil {
+		return err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].ServiceDate.Format("2006-01-02") != date {
+			continue
+		}
+		var got []string
+		for _, n := range entries[i].Notes {
+			got = append(got, n.Text)
+		}
+		if strings.Join(got, "; ") != want {
+			return fmt.Errorf("expected notes %q, got %q", want, strings.Join(got, "; "))
+		}
+		return nil
+	}
+	return fmt.Errorf("no history entry for %s", date)
+}
+
+// theHistoryForShouldHaveTitle checks the sermon on the latest entry for date
+func theHistoryForShouldHaveTitle(date, title, scripture string) error {
+	h := getHistoryContext()
+	entries, err := h.store.List()
+	if err != nil {
+		return err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].ServiceDate.Format("2006-01-02") != date {
+			continue
+		}
+		if entries[i].Title != title || entries[i].Scripture != scripture {
+			return fmt.Errorf("expected title %q and scripture %q, got %q and %q", title, scripture, entries[i].Title, entries[i].Scripture)
+		}
+		return nil
+	}
+	return fmt.Errorf("no history entry for %s", date)
+}
+
+func iShowDetectionStatsWithThresholdOverWeeks(threshold float64, weeks int) error {
+	return iShowTheLastDetectionStats(0, threshold, weeks)
+}
+
+func iShowTheLastDetectionStats(last int, threshold float64, weeks int) error {
+	h := getHistoryContext()
+	if h.store == nil {
+		if err := aHistoryStore(); err != nil {
+			return err
+		}
+	}
+	h.output.Reset()
+	h.err = cmd.RunDetectStatsWithDependencies(h.store, threshold, weeks, last, h.output)
+	if h.err != nil {
+		return fmt.Errorf("detect stats failed: %w", h.err)
+	}
+	return nil
+}
+
+func theHistoryForShouldRecordAnEarlyDetectionExit(date string) error {
+	entries, err := getHistoryContext().store.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ServiceDate.Format("2006-01-02") == date {
+			if !e.DetectionEarlyExit {
+				return fmt.Errorf("expected an early detection exit for %s", date)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no history entry for %s", date)
+}
+
+func theHistoryForShouldHaveDetectionConfidence(date string, confidence float64) error {
+	h := getHistoryContext()
+	entries, err := h.store.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ServiceDate.Format("2006-01-02") == date {
+			if e.DetectionConfidence != confidence {
+				return fmt.Errorf("expected confidence %v for %s, got %v", confidence, date, e.DetectionConfidence)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no history entry for %s", date)
+}
+
+func theHistoryForShouldRecordFolder(date, folderID string) error {
+	h := getHistoryContext()
+	entries, err := h.store.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ServiceDate.Format("2006-01-02") == date {
+			if e.FolderID != folderID {
+				return fmt.Errorf("expected folder %q for %s, got %q", folderID, date, e.FolderID)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no history entry for %s", date)
+}
+
+func theHistoryForShouldRecordAnAudioSizeOf(date string, size int64) error {
+	h := getHistoryContext()
+	entries, err := h.store.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ServiceDate.Format("2006-01-02") == date {
+			if e.AudioSize != size {
+				return fmt.Errorf("expected audio size %d for %s, got %d", size, date, e.AudioSize)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no history entry for %s", date)
+}
+
+func runMinisterStats(from, to, year string) error {
+	h := getHistoryContext()
+	if h.store == nil {
+		return fmt.Errorf("no history store configured")
+	}
+	h.output.Reset()
+	h.err = cmd.RunHistoryMinisterStatsWithDependencies(h.store, from, to, year, h.output)
+	return nil
+}
+
+func iShowMinisterStatsForYear(year string) error {
+	return runMinisterStats("", "", year)
+}
+
+func iShowMinisterStatsFromTo(from, to string) error {
+	return runMinisterStats(from, to, "")
+}
+
+// theMinisterStatsShouldList finds the minister's row in the stats table and
+// checks its columns
+func theMinisterStatsShouldList(minister string, services int, avg, last string) error {
+	h := getHistoryContext()
+	if h.err != nil {
+		return fmt.Errorf("minister stats failed: %v", h.err)
+	}
+	want := []string{strconv.Itoa(services), avg, last}
+	for _, line := range strings.Split(h.output.String(), "\n") {
+		if !strings.HasPrefix(line, minister+"  ") {
+			continue
+		}
+		got := strings.Fields(strings.TrimPrefix(line, minister))
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			return fmt.Errorf("expected %s row %v, got %v", minister, want, got)
+		}
+		return nil
+	}
+	return fmt.Errorf("no row for %s in:\n%s", minister, h.output.String())
+}
@@ -0,0 +1,216 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/infrastructure/drive"
+
+	googledrive "google.golang.org/api/drive/v3"
+
+	"github.com/cucumber/godog"
+)
+
+// renamingMockDriveService is a cleanup mock that can also rename files
+type renamingMockDriveService struct {
+	cleanupMockDriveService
+	failRenames map[string]bool // file names whose rename fails
+	renames     int
+}
+
+func (m *renamingMockDriveService) UpdateName(ctx context.Context, fileID, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.files {
+		if f.Id != fileID {
+			continue
+		}
+		if m.failRenames[f.Name] {
+			return fmt.Errorf("googleapi: Error 403: User rate limit exceeded")
+		}
+		f.Name = name
+		m.renames++
+		return nil
+	}
+	return fmt.Errorf("googleapi: Error 404: File not found: %s", fileID)
+}
+
+// migrateContext holds test state for migration scenarios
+type migrateContext struct {
+	mockService *renamingMockDriveService
+	output      *bytes.Buffer
+	err         error
+}
+
+// SharedMigrateContext is reset before each scenario
+var SharedMigrateContext *migrateContext
+
+func getMigrateContext() *migrateContext {
+	return SharedMigrateContext
+}
+
+func InitializeMigrateScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		SharedMigrateContext = &migrateContext{
+			mockService: &renamingMockDriveService{failRenames: make(map[string]bool)},
+			output:      &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		SharedMigrateContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the Services folder to migrate holds:$`, theServicesFolderToMigrateHolds)
+	ctx.Step(`^renaming "([^"]*)" fails$`, renamingFails)
+	ctx.Step(`^I run the migration scan$`, iRunTheMigrationScan)
+	ctx.Step(`^I preview the migration scan$`, iPreviewTheMigrationScan)
+	ctx.Step(`^the migration should succeed$`, theMigrationShouldSucceed)
+	ctx.Step(`^the migration should fail with "([^"]*)"$`, theMigrationShouldFailWith)
+	ctx.Step(`^the migration output should include "([^"]*)"$`, theMigrationOutputShouldInclude)
+	ctx.Step(`^the Services folder should hold "([^"]*)"$`, theServicesFolderShouldHold)
+	ctx.Step(`^no files should have been renamed$`, noFilesShouldHaveBeenRenamed)
+	ctx.Step(`^the history should have (\d+) services?$`, theHistoryShouldHaveServices)
+	ctx.Step(`^the history for "([^"]*)" should link video "([^"]*)" and audio "([^"]*)"$`, theHistoryForShouldLinkVideoAndAudio)
+}
+
+func theServicesFolderToMigrateHolds(table *godog.Table) error {
+	m := getMigrateContext()
+	header := table.Rows[0].Cells
+	for i, row := range table.Rows[1:] {
+		f := &googledrive.File{Id: fmt.Sprintf("file-%d", i+1)}
+		for j, cell := range row.Cells {
+			switch header[j].Value {
+			case "id":
+				f.Id = cell.Value
+			case "name":
+				f.Name = cell.Value
+			case "size":
+				fmt.Sscan(cell.Value, &f.Size)
+			case "uploaded":
+				d, err := time.Parse("2006-01-02", cell.Value)
+				if err != nil {
+					return fmt.Errorf("invalid uploaded date %q: %w", cell.Value, err)
+				}
+				f.CreatedTime = d.Format(time.RFC3339)
+			}
+		}
+		m.mockService.files = append(m.mockService.files, f)
+	}
+	return nil
+}
+
+func renamingFails(name string) error {
+	getMigrateContext().mockService.failRenames[name] = true
+	return nil
+}
+
+func runMigration(dryRun bool) error {
+	m := getMigrateContext()
+	client, err := drive.NewClient(context.Background(), "", drive.WithDriveService(m.mockService))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	h := getHistoryContext()
+	if h.store == nil {
+		if err := aHistoryStore(); err != nil {
+			return err
+		}
+	}
+
+	m.output.Reset()
+	m.err = cmd.RunMigrateScanWithDependencies(context.Background(), client, "test-folder-id", h.store, dryRun, m.output)
+	return nil
+}
+
+func iRunTheMigrationScan() error {
+	return runMigration(false)
+}
+
+func iPreviewTheMigrationScan() error {
+	return runMigration(true)
+}
+
+func theMigrationShouldSucceed() error {
+	m := getMigrateContext()
+	if m.err != nil {
+		return fmt.Errorf("expected success, got %v\noutput:\n%s", m.err, m.output.String())
+	}
+	return nil
+}
+
+func theMigrationShouldFailWith(expected string) error {
+	m := getMigrateContext()
+	if m.err == nil {
+		return fmt.Errorf("expected an error containing %q, got success", expected)
+	}
+	if !strings.Contains(m.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %v", expected, m.err)
+	}
+	return nil
+}
+
+func theMigrationOutputShouldInclude(expected string) error {
+	m := getMigrateContext()
+	if !strings.Contains(m.output.String(), expected) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", expected, m.output.String())
+	}
+	return nil
+}
+
+func theServicesFolderShouldHold(names string) error {
+	m := getMigrateContext()
+	held := make(map[string]bool)
+	for _, f := range m.mockService.files {
+		held[f.Name] = true
+	}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); !held[name] {
+			return fmt.Errorf("expected the folder to hold %q", name)
+		}
+	}
+	return nil
+}
+
+func noFilesShouldHaveBeenRenamed() error {
+	if n := getMigrateContext().mockService.renames; n != 0 {
+		return fmt.Errorf("expected no renames, got %d", n)
+	}
+	return nil
+}
+
+func theHistoryShouldHaveServices(count int) error {
+	entries, err := getHistoryContext().store.List()
+	if err != nil {
+		return err
+	}
+	if len(entries) != count {
+		return fmt.Errorf("expected %d history entries, got %d", count, len(entries))
+	}
+	return nil
+}
+
+func theHistoryForShouldLinkVideoAndAudio(date, videoID, audioID string) error {
+	entries, err := getHistoryContext().store.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ServiceDate.Format("2006-01-02") != date {
+			continue
+		}
+		if e.VideoFileID != videoID || e.AudioFileID != audioID {
+			return fmt.Errorf("history for %s links video %q and audio %q", date, e.VideoFileID, e.AudioFileID)
+		}
+		return nil
+	}
+	return fmt.Errorf("no history entry for %s", date)
+}
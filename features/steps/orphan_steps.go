@@ -0,0 +1,207 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/infrastructure/drive"
+
+	googledrive "google.golang.org/api/drive/v3"
+
+	"github.com/cucumber/godog"
+)
+
+// orphanMockDriveService answers the trash, sharing and listing queries of
+// the orphan report
+type orphanMockDriveService struct {
+	files   []*googledrive.File
+	trashed map[string]bool
+	public  map[string]bool
+}
+
+func (m *orphanMockDriveService) ListFiles(ctx context.Context, query string, fields string, orderBy string) ([]*googledrive.File, error) {
+	wantTrashed := strings.Contains(query, "trashed = true")
+	wantPublic := strings.Contains(query, "visibility = 'anyoneWithLink'")
+	var result []*googledrive.File
+	for _, f := range m.files {
+		if m.trashed[f.Id] != wantTrashed || (wantPublic && !m.public[f.Id]) {
+			continue
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+func (m *orphanMockDriveService) GetAbout(ctx context.Context, fields string) (*googledrive.About, error) {
+	return &googledrive.About{StorageQuota: &googledrive.AboutStorageQuota{}}, nil
+}
+
+func (m *orphanMockDriveService) DeleteFile(ctx context.Context, fileID string) error {
+	return fmt.Errorf("the orphan report must not delete files")
+}
+
+func (m *orphanMockDriveService) EmptyTrash(ctx context.Context) error {
+	return fmt.Errorf("the orphan report must not empty the trash")
+}
+
+func (m *orphanMockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*googledrive.File, error) {
+	return nil, fmt.Errorf("the orphan report must not upload files")
+}
+
+func (m *orphanMockDriveService) CreatePermission(ctx context.Context, fileID string, permission *googledrive.Permission) error {
+	return fmt.Errorf("the orphan report must not change sharing")
+}
+
+// orphanContext holds test state for orphan report scenarios
+type orphanContext struct {
+	service *orphanMockDriveService
+	output  *bytes.Buffer
+	err     error
+}
+
+var sharedOrphanContext *orphanContext
+
+func getOrphanContext() *orphanContext {
+	return sharedOrphanContext
+}
+
+func InitializeOrphanScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedOrphanContext = &orphanContext{
+			service: &orphanMockDriveService{trashed: make(map[string]bool), public: make(map[string]bool)},
+			output:  &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		sharedOrphanContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the Services folder holds these files for the orphan report:$`, theServicesFolderHoldsTheseFilesForTheOrphanReport)
+	ctx.Step(`^I report orphaned Drive files$`, iReportOrphanedDriveFiles)
+	ctx.Step(`^the orphan report should include "([^"]*)"$`, theOrphanReportShouldInclude)
+	ctx.Step(`^the orphan report should not include "([^"]*)"$`, theOrphanReportShouldNotInclude)
+	ctx.Step(`^the orphan report should list "([^"]*)" as "([^"]*)" because "([^"]*)"$`, theOrphanReportShouldListAsBecause)
+}
+
+func theServicesFolderHoldsTheseFilesForTheOrphanReport(table *godog.Table) error {
+	m := getOrphanContext().service
+	header := table.Rows[0].Cells
+	for _, row := range table.Rows[1:] {
+		f := &googledrive.File{}
+		for i, cell := range row.Cells {
+			v := cell.Value
+			switch header[i].Value {
+			case "id":
+				f.Id = v
+			case "name":
+				f.Name = v
+			case "size_mb":
+				mb, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid size %q: %w", v, err)
+				}
+				f.Size = mb * 1024 * 1024
+			case "state":
+				switch v {
+				case "trashed":
+					m.trashed[f.Id] = true
+				case "public":
+					m.public[f.Id] = true
+				case "private":
+				default:
+					return fmt.Errorf("unknown state %q", v)
+				}
+			case "service_date":
+				if v != "" {
+					f.AppProperties = map[string]string{"service_date": v}
+				}
+			case "created":
+				d, err := time.Parse("2006-01-02", v)
+				if err != nil {
+					return fmt.Errorf("invalid created date %q: %w", v, err)
+				}
+				f.CreatedTime = d.Format(time.RFC3339)
+			case "mime_type":
+				f.MimeType = v
+			}
+		}
+		m.files = append(m.files, f)
+	}
+	return nil
+}
+
+func iReportOrphanedDriveFiles() error {
+	o := getOrphanContext()
+	client, err := drive.NewClient(context.Background(), "", drive.WithDriveService(o.service))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	h := getHistoryContext()
+	if h.store == nil {
+		if err := aHistoryStore(); err != nil {
+			return err
+		}
+	}
+
+	o.output.Reset()
+	o.err = cmd.RunDriveOrphansWithDependencies(context.Background(), client, "test-folder-id", h.store, o.output)
+	return nil
+}
+
+func (o *orphanContext) report() (string, error) {
+	if o.err != nil {
+		return "", fmt.Errorf("orphan report failed: %v", o.err)
+	}
+	return o.output.String(), nil
+}
+
+func theOrphanReportShouldInclude(text string) error {
+	out, err := getOrphanContext().report()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(out, text) {
+		return fmt.Errorf("expected report to include %q, got:\n%s", text, out)
+	}
+	return nil
+}
+
+func theOrphanReportShouldNotInclude(text string) error {
+	out, err := getOrphanContext().report()
+	if err != nil {
+		return err
+	}
+	if strings.Contains(out, text) {
+		return fmt.Errorf("expected report not to include %q, got:\n%s", text, out)
+	}
+	return nil
+}
+
+// theOrphanReportShouldListAsBecause checks a file's recommendation and reason
+func theOrphanReportShouldListAsBecause(name, recommendation, reason string) error {
+	out, err := getOrphanContext().report()
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), "  ", 2)
+		if fields[0] != name {
+			continue
+		}
+		if !strings.Contains(line, "  "+recommendation+"  ") || !strings.Contains(line, reason) {
+			return fmt.Errorf("expected %s as %q because %q, got %q", name, recommendation, reason, line)
+		}
+		return nil
+	}
+	return fmt.Errorf("expected %s in the report, got:\n%s", name, out)
+}
@@ -5,20 +5,37 @@ package steps
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	appprocess "nac-service-media/application/process"
 	"nac-service-media/cmd"
+	"nac-service-media/domain/audit"
+	"nac-service-media/domain/distribution"
 	domainfs "nac-service-media/domain/filesystem"
+	"nac-service-media/domain/history"
 	"nac-service-media/domain/video"
 	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/ui"
 
 	googledrive "google.golang.org/api/drive/v3"
 	googlegmail "google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
 
 	"github.com/cucumber/godog"
 )
@@ -37,6 +54,9 @@ type processContext struct {
 	fileFinder    *processMockFileFinder
 	diskChecker   *processMockDiskChecker
 	fileRemover   *processMockFileRemover
+	recorder      *processMockRecorder
+	publisher     *processMockPublisher
+	auditLog      *processMockAudit
 
 	// State
 	flags          map[string][]string
@@ -52,6 +72,15 @@ type processContext struct {
 	usedSourcePath string
 	serviceDate    string
 	trimmedFile    string
+	duration       time.Duration
+	geometry       *video.Geometry
+	summaryDir     string
+	detected       float64 // Start detection confidence
+	detectedEarly  bool    // Start detection stopped on a confident bracket
+	checkpoints    []bool  // Answers to --confirm-each-step checkpoints
+	reviewChoices  []string // Recipient review menu choices at the email step
+	reviewInputs   []string // Recipients typed in at the email step
+	modTimes       processMockModTimes
 }
 
 // SharedProcessContext is reset before each scenario via Before hook
@@ -63,6 +92,75 @@ func getProcessContext() *processContext {
 
 // --- Mock implementations ---
 
+// processMockModTimes reports when source videos were last written
+type processMockModTimes map[string]time.Time
+
+func (m processMockModTimes) ModTime(path string) (time.Time, error) {
+	if t, ok := m[path]; ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("no modification time for %s", path)
+}
+
+// processMockAudit keeps the audit events a process run records
+type processMockAudit struct {
+	events []audit.Event
+}
+
+func (m *processMockAudit) Record(e audit.Event) error {
+	m.events = append(m.events, e)
+	return nil
+}
+
+var _ audit.Recorder = (*processMockAudit)(nil)
+
+// processMockPublisher simulates the alternate download server
+type processMockPublisher struct {
+	baseURL string
+	files   map[string]string
+	failErr error
+}
+
+func (m *processMockPublisher) Put(ctx context.Context, remoteName string, r io.Reader, size int64) error {
+	if m.failErr != nil {
+		return m.failErr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.files[remoteName] = string(data)
+	return nil
+}
+
+func (m *processMockPublisher) URL(remoteName string) string {
+	return m.baseURL + "/" + remoteName
+}
+
+// processMockRecorder simulates OBS for --from-obs
+type processMockRecorder struct {
+	recording  bool
+	outputPath string
+	stopped    bool
+	waited     bool
+}
+
+func (m *processMockRecorder) IsRecording(ctx context.Context) (bool, error) {
+	return m.recording, nil
+}
+
+func (m *processMockRecorder) StopRecording(ctx context.Context) (string, error) {
+	m.stopped = true
+	m.recording = false
+	return m.outputPath, nil
+}
+
+func (m *processMockRecorder) WaitForStop(ctx context.Context) (string, error) {
+	m.waited = true
+	m.recording = false
+	return m.outputPath, nil
+}
+
 type processMockTrimmer struct {
 	calls       []processTrimCall
 	shouldFail  bool
@@ -84,24 +182,21 @@ func (m *processMockTrimmer) Trim(ctx context.Context, req *video.TrimRequest, o
 	if m.fileChecker != nil {
 		m.fileChecker.existingFiles[outputPath] = true
 		m.fileChecker.fileSizes[outputPath] = 1200000000 // ~1.2GB
-	}
-	// Create actual temp file for upload service's os.Stat check
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err == nil {
-		if f, err := os.Create(outputPath); err == nil {
-			f.WriteString("mock video content")
-			f.Close()
-			m.fileChecker.createdFiles = append(m.fileChecker.createdFiles, outputPath)
-		}
+		// Uploads and publishing read the output back
+		return m.fileChecker.fs.WriteFile(outputPath, []byte("mock video content"))
 	}
 	return nil
 }
 
 type processMockExtractor struct {
+	mu         sync.Mutex // Extra bitrates are extracted concurrently
 	calls      []processExtractCall
 	shouldFail bool
 	failError  error
 	fileChecker *processMockFileChecker
+
+	streamCalls []*video.AudioExtractionRequest
+	streamError error
 }
 
 type processExtractCall struct {
@@ -110,6 +205,8 @@ type processExtractCall struct {
 }
 
 func (m *processMockExtractor) Extract(ctx context.Context, req *video.AudioExtractionRequest, outputPath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.shouldFail {
 		return m.failError
 	}
@@ -118,23 +215,25 @@ func (m *processMockExtractor) Extract(ctx context.Context, req *video.AudioExtr
 	if m.fileChecker != nil {
 		m.fileChecker.existingFiles[outputPath] = true
 		m.fileChecker.fileSizes[outputPath] = 85000000 // ~85MB
-	}
-	// Create actual temp file for upload service's os.Stat check
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err == nil {
-		if f, err := os.Create(outputPath); err == nil {
-			f.WriteString("mock audio content")
-			f.Close()
-			m.fileChecker.createdFiles = append(m.fileChecker.createdFiles, outputPath)
-		}
+		// Uploads and publishing read the output back
+		return m.fileChecker.fs.WriteFile(outputPath, []byte("mock audio content"))
 	}
 	return nil
 }
 
+// Stream writes fake MP3 data, as ffmpeg would to stdout
+func (m *processMockExtractor) Stream(ctx context.Context, req *video.AudioExtractionRequest, w io.Writer) error {
+	m.streamCalls = append(m.streamCalls, req)
+	if _, err := w.Write([]byte("mock streamed audio")); err != nil {
+		return err
+	}
+	return m.streamError
+}
+
 type processMockFileChecker struct {
 	existingFiles map[string]bool
 	fileSizes     map[string]int64
-	createdFiles  []string // Track created files for cleanup
+	fs            *filesystem.MemFS // Local outputs, kept in memory
 }
 
 func (m *processMockFileChecker) Exists(path string) bool {
@@ -197,6 +296,7 @@ type processMockDriveService struct {
 	files           []*googledrive.File
 	uploadedFiles   []*googledrive.File
 	permissions     map[string]*googledrive.Permission
+	grants          map[string][]*googledrive.Permission // Every permission created, by file ID
 	shouldFail      bool
 	failError       error
 	uploadFails     bool
@@ -206,13 +306,25 @@ type processMockDriveService struct {
 	deletedFileIDs  []string
 	trashEmptied    bool
 	nextFileID      int
+	uploadFailsExt  string // Only fail uploads with this extension (empty = all)
+	uploadFailsName string // Fail only the upload of this file name
+	permissionFails bool   // For CreatePermission failures
+	permissionError error  // Error to return from CreatePermission
 	fileLookupFails bool   // For FindFileByName failures
 	fileLookupError error  // Error to return from FindFileByName
+	truncateStreams bool   // Report one byte fewer than was streamed
+	streamedFiles   []*googledrive.File
+	folderMissing   bool // GetFile finds no services folder
+	mu              sync.Mutex
 }
 
+// appPropertyQueryRegex extracts key/value from an "appProperties has" query clause
+var appPropertyQueryRegex = regexp.MustCompile(`appProperties has \{ key='([^']*)' and value='([^']*)' \}`)
+
 func newProcessMockDriveService() *processMockDriveService {
 	return &processMockDriveService{
 		permissions:  make(map[string]*googledrive.Permission),
+		grants:       make(map[string][]*googledrive.Permission),
 		storageLimit: 15 * 1024 * 1024 * 1024, // 15 GB
 		storageUsage: 0,
 		nextFileID:   1,
@@ -224,7 +336,7 @@ func (m *processMockDriveService) ListFiles(ctx context.Context, query string, f
 		return nil, m.failError
 	}
 	// Check for file lookup failure (used for FindFileByName via ListFiles query)
-	if m.fileLookupFails && strings.Contains(query, "name = ") {
+	if m.fileLookupFails && (strings.Contains(query, "name = ") || strings.Contains(query, "appProperties has")) {
 		return nil, m.fileLookupError
 	}
 	// Filter out deleted files
@@ -238,8 +350,13 @@ func (m *processMockDriveService) ListFiles(ctx context.Context, query string, f
 			}
 		}
 		if !deleted {
-			// If query contains a name filter, only return matching files
-			if strings.Contains(query, "name = '") {
+			// If query contains an appProperties filter, only return tagged files
+			if matches := appPropertyQueryRegex.FindStringSubmatch(query); matches != nil {
+				if f.AppProperties[matches[1]] == matches[2] {
+					result = append(result, f)
+				}
+			} else if strings.Contains(query, "name = '") {
+				// If query contains a name filter, only return matching files
 				// Extract filename from query like "name = '2025-12-28.mp4'"
 				start := strings.Index(query, "name = '") + 8
 				end := strings.Index(query[start:], "'")
@@ -287,6 +404,8 @@ func (m *processMockDriveService) DeleteFile(ctx context.Context, fileID string)
 	if m.shouldFail {
 		return m.failError
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.deletedFileIDs = append(m.deletedFileIDs, fileID)
 	return nil
 }
@@ -299,8 +418,8 @@ func (m *processMockDriveService) EmptyTrash(ctx context.Context) error {
 	return nil
 }
 
-func (m *processMockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string) (*googledrive.File, error) {
-	if m.uploadFails {
+func (m *processMockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*googledrive.File, error) {
+	if m.uploadFailsName != "" && m.uploadFailsName == fileName || m.uploadFails && (m.uploadFailsExt == "" || strings.HasSuffix(fileName, "."+m.uploadFailsExt)) {
 		return nil, m.uploadError
 	}
 	if m.shouldFail {
@@ -311,24 +430,77 @@ func (m *processMockDriveService) UploadFile(ctx context.Context, fileName, mime
 	m.nextFileID++
 
 	file := &googledrive.File{
-		Id:          fileID,
-		Name:        fileName,
-		MimeType:    mimeType,
-		Size:        1024,
-		WebViewLink: fmt.Sprintf("https://drive.google.com/file/d/%s/view", fileID),
+		Id:            fileID,
+		Name:          fileName,
+		MimeType:      mimeType,
+		Size:          1024,
+		WebViewLink:   fmt.Sprintf("https://drive.google.com/file/d/%s/view", fileID),
+		AppProperties: appProperties,
+		Parents:       []string{folderID},
 	}
 	m.uploadedFiles = append(m.uploadedFiles, file)
 	return file, nil
 }
 
+// UploadReader reads the whole stream and reports its size and checksum
+func (m *processMockDriveService) UploadReader(ctx context.Context, fileName, mimeType, folderID string, r io.Reader, appProperties map[string]string) (*googledrive.File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if m.uploadFailsName != "" && m.uploadFailsName == fileName || m.uploadFails && (m.uploadFailsExt == "" || strings.HasSuffix(fileName, "."+m.uploadFailsExt)) {
+		return nil, m.uploadError
+	}
+
+	fileID := fmt.Sprintf("uploaded-file-%d", m.nextFileID)
+	m.nextFileID++
+	sum := md5.Sum(data)
+	size := int64(len(data))
+	if m.truncateStreams {
+		size--
+	}
+
+	file := &googledrive.File{
+		Id:            fileID,
+		Name:          fileName,
+		MimeType:      mimeType,
+		Size:          size,
+		Md5Checksum:   hex.EncodeToString(sum[:]),
+		WebViewLink:   fmt.Sprintf("https://drive.google.com/file/d/%s/view", fileID),
+		AppProperties: appProperties,
+		Parents:       []string{folderID},
+	}
+	m.uploadedFiles = append(m.uploadedFiles, file)
+	m.streamedFiles = append(m.streamedFiles, file)
+	return file, nil
+}
+
 func (m *processMockDriveService) CreatePermission(ctx context.Context, fileID string, permission *googledrive.Permission) error {
+	if m.permissionFails {
+		return m.permissionError
+	}
 	if m.shouldFail {
 		return m.failError
 	}
 	m.permissions[fileID] = permission
+	m.grants[fileID] = append(m.grants[fileID], permission)
 	return nil
 }
 
+// GetFile finds every folder, named Sunday Services and open for uploads,
+// unless the folder ID was mistyped
+func (m *processMockDriveService) GetFile(ctx context.Context, fileID, fields string) (*googledrive.File, error) {
+	if m.folderMissing {
+		return nil, &googleapi.Error{Code: http.StatusNotFound, Message: "File not found: " + fileID}
+	}
+	return &googledrive.File{
+		Id:           fileID,
+		Name:         "Sunday Services",
+		MimeType:     distribution.MimeTypeFolder,
+		Capabilities: &googledrive.FileCapabilities{CanAddChildren: true},
+	}, nil
+}
+
 type processMockGmailService struct {
 	sentMessages []*googlegmail.Message
 	shouldFail   bool
@@ -350,6 +522,7 @@ func InitializeProcessScenario(ctx *godog.ScenarioContext) {
 		fileChecker := &processMockFileChecker{
 			existingFiles: make(map[string]bool),
 			fileSizes:     make(map[string]int64),
+			fs:            filesystem.NewMemFS(),
 		}
 		// Use temp directories that are actually writable
 		tempDir := os.TempDir()
@@ -381,6 +554,7 @@ func InitializeProcessScenario(ctx *godog.ScenarioContext) {
 			fileFinder:   &processMockFileFinder{},
 			diskChecker:  &processMockDiskChecker{usage: 50.0},
 			fileRemover:  &processMockFileRemover{},
+			recorder:     &processMockRecorder{},
 			flags:        make(map[string][]string),
 			output:       &bytes.Buffer{},
 		}
@@ -388,11 +562,8 @@ func InitializeProcessScenario(ctx *godog.ScenarioContext) {
 	})
 
 	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
-		// Clean up any created temp files
-		if SharedProcessContext != nil && SharedProcessContext.fileChecker != nil {
-			for _, f := range SharedProcessContext.fileChecker.createdFiles {
-				os.Remove(f)
-			}
+		if SharedProcessContext != nil && SharedProcessContext.summaryDir != "" {
+			os.RemoveAll(SharedProcessContext.summaryDir)
 		}
 		SharedProcessContext = nil
 		return c, nil
@@ -404,20 +575,66 @@ func InitializeProcessScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the process config has ministers:$`, theProcessConfigHasMinisters)
 	ctx.Step(`^the process config has recipients:$`, theProcessConfigHasRecipients)
 	ctx.Step(`^the process config has default CCs:$`, theProcessConfigHasDefaultCCs)
+	ctx.Step(`^the process config has a recipient group "([^"]*)" with members "([^"]*)"$`, theProcessConfigHasARecipientGroupWithMembers)
 	ctx.Step(`^the process config has senders:$`, theProcessConfigHasSenders)
+	ctx.Step(`^the process config has defaults:$`, theProcessConfigHasDefaults)
+	ctx.Step(`^the process config schedules minister "([^"]*)" on "([^"]*)"$`, theProcessConfigSchedulesMinisterOn)
 
 	// Source file steps
 	ctx.Step(`^a source video exists at "([^"]*)"$`, aSourceVideoExistsAtProcess)
+	ctx.Step(`^an earlier output exists at "([^"]*)"$`, anEarlierOutputExistsAt)
 	ctx.Step(`^no source video exists at "([^"]*)"$`, noSourceVideoExistsAtProcess)
 	ctx.Step(`^the source directory is empty$`, theSourceDirectoryIsEmpty)
+	ctx.Step(`^the process source video is (\d+) minutes long$`, theProcessSourceVideoIsMinutesLong)
+	ctx.Step(`^the process source video is (\d+)x(\d+)$`, theProcessSourceVideoIs)
+	ctx.Step(`^the process source video is (\d+)x(\d+) rotated (-?\d+) degrees$`, theProcessSourceVideoIsRotated)
+	ctx.Step(`^recordings are expected to be (\d+)x(\d+)$`, recordingsAreExpectedToBe)
+	ctx.Step(`^mismatched recordings stop processing$`, mismatchedRecordingsStopProcessing)
+	ctx.Step(`^the start was detected with confidence ([\d.]+)$`, theStartWasDetectedWithConfidence)
+	ctx.Step(`^the start was detected early with confidence ([\d.]+)$`, theStartWasDetectedEarlyWithConfidence)
+	ctx.Step(`^run summaries are archived in a temporary directory$`, runSummariesAreArchivedInATemporaryDirectory)
+	ctx.Step(`^the summary formats are "([^"]*)"$`, theSummaryFormatsAre)
+	ctx.Step(`^the service timezone is "([^"]*)" and recordings are named in "([^"]*)"$`, theServiceTimezoneIsAndRecordingsAreNamedIn)
+	ctx.Step(`^the run summary "([^"]*)" should include "([^"]*)"$`, theRunSummaryShouldInclude)
+	ctx.Step(`^the run summary "([^"]*)" should not include "([^"]*)"$`, theRunSummaryShouldNotInclude)
+	ctx.Step(`^the run summary "([^"]*)" should not exist$`, theRunSummaryShouldNotExist)
 
 	// Drive state steps
 	ctx.Step(`^drive has insufficient space$`, driveHasInsufficientSpace)
 	ctx.Step(`^drive has very insufficient space for audio$`, driveHasVeryInsufficientSpaceForAudio)
 	ctx.Step(`^drive has old files:$`, driveHasOldFiles)
 	ctx.Step(`^the drive upload will fail with "([^"]*)"$`, theDriveUploadWillFailWith)
+	ctx.Step(`^the drive upload of "([^"]*)" files will fail with "([^"]*)"$`, theDriveUploadOfFilesWillFailWith)
+	ctx.Step(`^the drive upload of "([^"]*)" will fail with "([^"]*)"$`, theDriveUploadOfWillFailWith)
+	ctx.Step(`^sending the email will fail with "([^"]*)"$`, sendingTheEmailWillFailWith)
+	ctx.Step(`^trimming will fail with "([^"]*)"$`, trimmingWillFailWith)
+	ctx.Step(`^streaming the audio will fail with "([^"]*)"$`, streamingTheAudioWillFailWith)
+	ctx.Step(`^drive will store streamed uploads truncated$`, driveWillStoreStreamedUploadsTruncated)
+	ctx.Step(`^the audio should be streamed to Drive$`, theAudioShouldBeStreamedToDrive)
+	ctx.Step(`^the audio should not be streamed to Drive$`, theAudioShouldNotBeStreamedToDrive)
+	ctx.Step(`^the streamed audio should be saved locally$`, theStreamedAudioShouldBeSavedLocally)
+	ctx.Step(`^the streamed audio should not be saved locally$`, theStreamedAudioShouldNotBeSavedLocally)
+	ctx.Step(`^the process config keeps streamed audio locally$`, theProcessConfigKeepsStreamedAudioLocally)
+	ctx.Step(`^drive sharing will fail with "([^"]*)"$`, driveSharingWillFailWith)
 	ctx.Step(`^drive has processed files:$`, driveHasProcessedFiles)
+	ctx.Step(`^drive has files tagged with service date "([^"]*)":$`, driveHasFilesTaggedWithServiceDate)
+	ctx.Step(`^the processed check strategy is "([^"]*)"$`, theProcessedCheckStrategyIs)
+	ctx.Step(`^uploaded files should be tagged with service date "([^"]*)"$`, uploadedFilesShouldBeTaggedWithServiceDate)
+	ctx.Step(`^uploaded files should be in folder "([^"]*)"$`, uploadedFilesShouldBeInFolder)
 	ctx.Step(`^drive will fail file lookup with "([^"]*)"$`, driveWillFailFileLookupWith)
+	ctx.Step(`^the services folder ID is mistyped$`, theServicesFolderIDIsMistyped)
+	ctx.Step(`^a mirror download server at "([^"]*)"$`, aMirrorDownloadServerAt)
+	ctx.Step(`^the mirror download server will fail with "([^"]*)"$`, theMirrorDownloadServerWillFailWith)
+	ctx.Step(`^"([^"]*)" should be published to the mirror with its checksum$`, shouldBePublishedToTheMirrorWithItsChecksum)
+	ctx.Step(`^email should include "([^"]*)"$`, emailShouldInclude)
+	ctx.Step(`^email should not include "([^"]*)"$`, emailShouldNotInclude)
+	ctx.Step(`^OBS is recording to "([^"]*)"$`, obsIsRecordingTo)
+	ctx.Step(`^OBS is not recording$`, obsIsNotRecording)
+	ctx.Step(`^the OBS recording should have been (stopped|waited for)$`, theOBSRecordingShouldHaveBeen)
+	ctx.Step(`^the operator answers the checkpoints with "([^"]*)"$`, theOperatorAnswersTheCheckpointsWith)
+	ctx.Step(`^"([^"]*)" was last written at "([^"]*)"$`, wasLastWrittenAt)
+	ctx.Step(`^the operator adds "([^"]*)" to the (recipients|CCs) at the email step$`, theOperatorAddsAtTheEmailStep)
+	ctx.Step(`^the operator removes "([^"]*)" at the email step$`, theOperatorRemovesAtTheEmailStep)
 
 	// Action steps
 	ctx.Step(`^I run process with flags:$`, iRunProcessWithFlags)
@@ -425,14 +642,20 @@ func InitializeProcessScenario(ctx *godog.ScenarioContext) {
 	// Assertion steps
 	ctx.Step(`^the process should succeed$`, theProcessShouldSucceed)
 	ctx.Step(`^the process should fail with error "([^"]*)"$`, theProcessShouldFailWithError)
+	ctx.Step(`^the process error code should be "([^"]*)" with exit code (\d+)$`, theProcessErrorCodeShouldBeWithExitCode)
+	ctx.Step(`^the JSON error report should include "(.*)"$`, theJSONErrorReportShouldInclude)
+	ctx.Step(`^the process should stop for input with reason "([^"]*)"$`, theProcessShouldStopForInputWithReason)
 	ctx.Step(`^the error should suggest command "([^"]*)"$`, theErrorShouldSuggestCommand)
 	ctx.Step(`^the video should be trimmed from "([^"]*)" to "([^"]*)"$`, theVideoShouldBeTrimmedFromTo)
 	ctx.Step(`^the audio should be extracted with bitrate "([^"]*)"$`, theAudioShouldBeExtractedWithBitrate)
 	ctx.Step(`^drive cleanup should be called with space for (\d+) files$`, driveCleanupShouldBeCalledWithSpaceForFiles)
+	ctx.Step(`^cleanup should not be called$`, cleanupShouldNotBeCalled)
+	ctx.Step(`^the process audit log should record the cleanup "([^"]*)"$`, theProcessAuditLogShouldRecordTheCleanup)
 	ctx.Step(`^the video should be uploaded to Drive$`, theVideoShouldBeUploadedToDrive)
 	ctx.Step(`^the audio should be uploaded to Drive$`, theAudioShouldBeUploadedToDrive)
 	ctx.Step(`^both files should be shared publicly$`, bothFilesShouldBeSharedPublicly)
 	ctx.Step(`^email should be sent to "([^"]*)"$`, emailShouldBeSentTo)
+	ctx.Step(`^email should not be sent to "([^"]*)"$`, emailShouldNotBeSentTo)
 	ctx.Step(`^email should include minister "([^"]*)"$`, emailShouldIncludeMinister)
 	ctx.Step(`^email should include video and audio links$`, emailShouldIncludeVideoAndAudioLinks)
 	ctx.Step(`^the source video should be "([^"]*)"$`, theSourceVideoShouldBe)
@@ -442,12 +665,29 @@ func InitializeProcessScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the output should include "([^"]*)"$`, theOutputShouldInclude)
 	ctx.Step(`^the output should include recovery commands$`, theOutputShouldIncludeRecoveryCommands)
 	ctx.Step(`^the recovery should suggest "([^"]*)" command$`, theRecoveryShouldSuggestCommand)
+	ctx.Step(`^the output should not include "([^"]*)"$`, theOutputShouldNotInclude)
+	ctx.Step(`^the recovery email command should include minister "([^"]*)"$`, theRecoveryEmailCommandShouldIncludeMinister)
+	ctx.Step(`^the recovery email command should include (audio|video) URL "([^"]*)"$`, theRecoveryEmailCommandShouldIncludeURL)
 
 	// Skip video mode steps
 	ctx.Step(`^the video should not be trimmed$`, theVideoShouldNotBeTrimmed)
 	ctx.Step(`^the audio should be extracted with timestamps "([^"]*)" to "([^"]*)"$`, theAudioShouldBeExtractedWithTimestamps)
 	ctx.Step(`^the video should not be uploaded to Drive$`, theVideoShouldNotBeUploadedToDrive)
+	ctx.Step(`^the audio should not be uploaded to Drive$`, theAudioShouldNotBeUploadedToDrive)
+	ctx.Step(`^the process should not send an email$`, theProcessShouldNotSendAnEmail)
 	ctx.Step(`^email should include audio link only$`, emailShouldIncludeAudioLinkOnly)
+	ctx.Step(`^the process config has audio track (\d+)$`, theProcessConfigHasAudioTrack)
+	ctx.Step(`^the process config makes audio at bitrates "([^"]*)"$`, theProcessConfigMakesAudioAtBitrates)
+	ctx.Step(`^the audio should be extracted at bitrates "([^"]*)"$`, theAudioShouldBeExtractedAtBitrates)
+	ctx.Step(`^the process config has a weekly upload budget of ([\d.]+) GB$`, theProcessConfigHasAWeeklyUploadBudgetOf)
+	ctx.Step(`^the process config asks before going over a weekly upload budget of ([\d.]+) GB$`, theProcessConfigAsksBeforeGoingOverAWeeklyUploadBudgetOf)
+	ctx.Step(`^the process config draws the watermark "([^"]*)"$`, theProcessConfigDrawsTheWatermark)
+	ctx.Step(`^the trimmed video should carry the watermark "([^"]*)"$`, theTrimmedVideoShouldCarryTheWatermark)
+	ctx.Step(`^the process config includes the folder link in emails$`, theProcessConfigIncludesTheFolderLinkInEmails)
+	ctx.Step(`^the process config links the livestream channel "([^"]*)"$`, theProcessConfigLinksTheLivestreamChannel)
+	ctx.Step(`^the trimmed video should keep audio track (\d+)$`, theTrimmedVideoShouldKeepAudioTrack)
+	ctx.Step(`^the audio should be extracted from audio track (\d+)$`, theAudioShouldBeExtractedFromAudioTrack)
+	ctx.Step(`^the trimmed video and audio should be tagged "([^"]*)" with scripture "([^"]*)"$`, theTrimmedVideoAndAudioShouldBeTagged)
 }
 
 func theProcessConfigHasPaths(table *godog.Table) error {
@@ -492,6 +732,16 @@ func theProcessConfigHasRecipients(table *godog.Table) error {
 	return nil
 }
 
+func theProcessConfigHasARecipientGroupWithMembers(name, members string) error {
+	p := getProcessContext()
+	group := config.RecipientGroupConfig{Name: name}
+	for _, m := range strings.Split(members, ",") {
+		group.Members = append(group.Members, strings.TrimSpace(m))
+	}
+	p.cfg.Email.Groups = append(p.cfg.Email.Groups, group)
+	return nil
+}
+
 func theProcessConfigHasDefaultCCs(table *godog.Table) error {
 	p := getProcessContext()
 	for i, row := range table.Rows {
@@ -525,6 +775,35 @@ func theProcessConfigHasSenders(table *godog.Table) error {
 	return nil
 }
 
+func theProcessConfigHasDefaults(table *godog.Table) error {
+	defaults := &getProcessContext().cfg.Defaults
+	for _, row := range table.Rows {
+		value := row.Cells[1].Value
+		switch key := row.Cells[0].Value; key {
+		case "minister":
+			defaults.Minister = value
+		case "recipients":
+			defaults.Recipients = strings.Split(value, ",")
+		case "service_length":
+			defaults.ServiceLength = value
+		case "sender":
+			defaults.Sender = value
+		default:
+			return fmt.Errorf("unknown defaults setting %q", key)
+		}
+	}
+	return nil
+}
+
+func theProcessConfigSchedulesMinisterOn(key, date string) error {
+	defaults := &getProcessContext().cfg.Defaults
+	if defaults.MinisterSchedule == nil {
+		defaults.MinisterSchedule = make(map[string]string)
+	}
+	defaults.MinisterSchedule[date] = key
+	return nil
+}
+
 // translatePath converts feature file paths to actual temp paths
 func translatePath(p *processContext, featurePath string) string {
 	// Replace /test/source with actual source directory
@@ -550,6 +829,112 @@ func aSourceVideoExistsAtProcess(path string) error {
 	return nil
 }
 
+func aMirrorDownloadServerAt(baseURL string) error {
+	p := getProcessContext()
+	p.publisher = &processMockPublisher{baseURL: baseURL, files: make(map[string]string)}
+	return nil
+}
+
+func theMirrorDownloadServerWillFailWith(errMsg string) error {
+	p := getProcessContext()
+	if p.publisher == nil {
+		return fmt.Errorf("no mirror download server configured")
+	}
+	p.publisher.failErr = fmt.Errorf("%s", errMsg)
+	return nil
+}
+
+func shouldBePublishedToTheMirrorWithItsChecksum(name string) error {
+	p := getProcessContext()
+	if _, ok := p.publisher.files[name]; !ok {
+		return fmt.Errorf("%s was not published; published: %v", name, mapKeys(p.publisher.files))
+	}
+	checksum, ok := p.publisher.files[name+distribution.ChecksumSuffix]
+	if !ok {
+		return fmt.Errorf("checksum for %s was not published", name)
+	}
+	if !strings.HasSuffix(checksum, "  "+name+"\n") {
+		return fmt.Errorf("checksum file is not in sha256sum format: %q", checksum)
+	}
+	return nil
+}
+
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func sentEmailContents(p *processContext) []string {
+	var contents []string
+	for _, msg := range p.gmailService.sentMessages {
+		if decoded, err := base64.URLEncoding.DecodeString(msg.Raw); err == nil {
+			contents = append(contents, string(decoded))
+		}
+	}
+	return contents
+}
+
+func emailShouldInclude(text string) error {
+	p := getProcessContext()
+	for _, content := range sentEmailContents(p) {
+		if strings.Contains(content, text) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no sent email contains %q", text)
+}
+
+func emailShouldNotInclude(text string) error {
+	p := getProcessContext()
+	for _, content := range sentEmailContents(p) {
+		if strings.Contains(content, text) {
+			return fmt.Errorf("sent email unexpectedly contains %q", text)
+		}
+	}
+	return nil
+}
+
+func anEarlierOutputExistsAt(path string) error {
+	p := getProcessContext()
+	actualPath := translatePath(p, path)
+	if err := p.fileChecker.fs.WriteFile(actualPath, []byte("earlier output")); err != nil {
+		return err
+	}
+	p.fileChecker.existingFiles[actualPath] = true
+	p.fileChecker.fileSizes[actualPath] = 100000000 // ~100MB
+	return nil
+}
+
+func obsIsRecordingTo(path string) error {
+	p := getProcessContext()
+	actualPath := translatePath(p, path)
+	p.fileChecker.existingFiles[actualPath] = true
+	p.fileChecker.fileSizes[actualPath] = 2000000000 // ~2GB
+	p.recorder.recording = true
+	p.recorder.outputPath = actualPath
+	return nil
+}
+
+func obsIsNotRecording() error {
+	p := getProcessContext()
+	p.recorder.recording = false
+	return nil
+}
+
+func theOBSRecordingShouldHaveBeen(how string) error {
+	p := getProcessContext()
+	if how == "stopped" && (!p.recorder.stopped || p.recorder.waited) {
+		return fmt.Errorf("expected the recording to be stopped by the command (stopped=%v, waited=%v)", p.recorder.stopped, p.recorder.waited)
+	}
+	if how == "waited for" && (!p.recorder.waited || p.recorder.stopped) {
+		return fmt.Errorf("expected the command to wait for the recording to stop (stopped=%v, waited=%v)", p.recorder.stopped, p.recorder.waited)
+	}
+	return nil
+}
+
 func noSourceVideoExistsAtProcess(path string) error {
 	p := getProcessContext()
 	p.fileChecker.existingFiles[path] = false
@@ -603,6 +988,35 @@ func theDriveUploadWillFailWith(errorMsg string) error {
 	return nil
 }
 
+func theDriveUploadOfFilesWillFailWith(ext, errorMsg string) error {
+	p := getProcessContext()
+	p.driveService.uploadFails = true
+	p.driveService.uploadFailsExt = ext
+	p.driveService.uploadError = fmt.Errorf("%s", errorMsg)
+	return nil
+}
+
+func theDriveUploadOfWillFailWith(name, errorMsg string) error {
+	p := getProcessContext()
+	p.driveService.uploadFailsName = name
+	p.driveService.uploadError = fmt.Errorf("%s", errorMsg)
+	return nil
+}
+
+func driveSharingWillFailWith(errorMsg string) error {
+	p := getProcessContext()
+	p.driveService.permissionFails = true
+	p.driveService.permissionError = fmt.Errorf("%s", errorMsg)
+	return nil
+}
+
+func sendingTheEmailWillFailWith(errorMsg string) error {
+	p := getProcessContext()
+	p.gmailService.shouldFail = true
+	p.gmailService.failError = fmt.Errorf("%s", errorMsg)
+	return nil
+}
+
 func driveHasProcessedFiles(table *godog.Table) error {
 	p := getProcessContext()
 	for i, row := range table.Rows {
@@ -627,6 +1041,55 @@ func driveHasProcessedFiles(table *godog.Table) error {
 	return nil
 }
 
+func driveHasFilesTaggedWithServiceDate(serviceDate string, table *godog.Table) error {
+	p := getProcessContext()
+	for i, row := range table.Rows {
+		if i == 0 {
+			continue // Skip header
+		}
+		p.driveService.files = append(p.driveService.files, &googledrive.File{
+			Id:            fmt.Sprintf("tagged-file-%d", i),
+			Name:          row.Cells[0].Value,
+			MimeType:      row.Cells[1].Value,
+			Size:          1000000, // 1MB placeholder
+			AppProperties: map[string]string{distribution.PropertyServiceDate: serviceDate},
+		})
+	}
+	return nil
+}
+
+func theProcessedCheckStrategyIs(strategy string) error {
+	p := getProcessContext()
+	p.cfg.Google.ProcessedCheck = strategy
+	return nil
+}
+
+func uploadedFilesShouldBeInFolder(folderID string) error {
+	p := getProcessContext()
+	if len(p.driveService.uploadedFiles) == 0 {
+		return fmt.Errorf("no files were uploaded")
+	}
+	for _, f := range p.driveService.uploadedFiles {
+		if len(f.Parents) != 1 || f.Parents[0] != folderID {
+			return fmt.Errorf("expected %s in folder %s, got %v", f.Name, folderID, f.Parents)
+		}
+	}
+	return nil
+}
+
+func uploadedFilesShouldBeTaggedWithServiceDate(serviceDate string) error {
+	p := getProcessContext()
+	if len(p.driveService.uploadedFiles) == 0 {
+		return fmt.Errorf("no files were uploaded")
+	}
+	for _, f := range p.driveService.uploadedFiles {
+		if got := f.AppProperties[distribution.PropertyServiceDate]; got != serviceDate {
+			return fmt.Errorf("expected %s to be tagged with service date %q, got %q", f.Name, serviceDate, got)
+		}
+	}
+	return nil
+}
+
 func driveWillFailFileLookupWith(errorMsg string) error {
 	p := getProcessContext()
 	p.driveService.fileLookupFails = true
@@ -634,6 +1097,59 @@ func driveWillFailFileLookupWith(errorMsg string) error {
 	return nil
 }
 
+func theServicesFolderIDIsMistyped() error {
+	getProcessContext().driveService.folderMissing = true
+	return nil
+}
+
+func theProcessSourceVideoIsMinutesLong(minutes int) error {
+	getProcessContext().duration = time.Duration(minutes) * time.Minute
+	return nil
+}
+
+// mockGeometryProber reports a fixed video geometry
+type mockGeometryProber struct {
+	geometry video.Geometry
+}
+
+func (m *mockGeometryProber) Geometry(ctx context.Context, path string) (video.Geometry, error) {
+	return m.geometry, nil
+}
+
+func theProcessSourceVideoIs(width, height int) error {
+	getProcessContext().geometry = &video.Geometry{Width: width, Height: height}
+	return nil
+}
+
+func theProcessSourceVideoIsRotated(width, height, degrees int) error {
+	getProcessContext().geometry = &video.Geometry{Width: width, Height: height, Rotation: degrees}
+	return nil
+}
+
+func recordingsAreExpectedToBe(width, height int) error {
+	p := getProcessContext()
+	p.cfg.Video.Width = width
+	p.cfg.Video.Height = height
+	return nil
+}
+
+func mismatchedRecordingsStopProcessing() error {
+	getProcessContext().cfg.Video.Strict = true
+	return nil
+}
+
+func theStartWasDetectedWithConfidence(confidence float64) error {
+	getProcessContext().detected = confidence
+	return nil
+}
+
+func theStartWasDetectedEarlyWithConfidence(confidence float64) error {
+	p := getProcessContext()
+	p.detected = confidence
+	p.detectedEarly = true
+	return nil
+}
+
 func iRunProcessWithFlags(table *godog.Table) error {
 	p := getProcessContext()
 
@@ -653,15 +1169,85 @@ func iRunProcessWithFlags(table *godog.Table) error {
 
 	// Build process input from flags
 	_, skipVideo := p.flags["--skip-video"]
+	_, sandbox := p.flags["--sandbox"]
+	_, streamAudio := p.flags["--stream-audio"]
+	_, nonInteractive := p.flags["--non-interactive"]
+	_, strict := p.flags["--strict"]
+	_, confirmSteps := p.flags["--confirm-each-step"]
+	_, allowDelete := p.flags["--allow-delete"]
+	_, quick := p.flags["--quick"]
+	_, withVideo := p.flags["--with-video"]
+	p.auditLog = &processMockAudit{}
 	input := cmd.ProcessInput{
 		InputPath:    getFirstFlag(p.flags, "--input"),
 		StartTime:    getFirstFlag(p.flags, "--start"),
 		EndTime:      getFirstFlag(p.flags, "--end"),
 		MinisterKey:  getFirstFlag(p.flags, "--minister"),
 		RecipientKeys: p.flags["--recipient"],
+		ExcludeKeys:  p.flags["--exclude"],
 		CCKeys:       p.flags["--cc"],
 		DateOverride: getFirstFlag(p.flags, "--date"),
 		SkipVideo:    skipVideo,
+		Notes:        p.flags["--note"],
+		Sandbox:      sandbox,
+		SummaryDir:   p.summaryDir,
+		StreamAudio:  streamAudio,
+		OnExisting:   getFirstFlag(p.flags, "--on-existing"),
+		NonInteractive: nonInteractive,
+		Strict:       strict,
+		ConfirmSteps: confirmSteps,
+		AllowDelete:  allowDelete,
+		AuditLog:     p.auditLog,
+		Prompter:     NewMockPrompter(p.reviewInputs, p.checkpoints).WithSelections(p.reviewChoices...),
+		Title:        getFirstFlag(p.flags, "--title"),
+		Scripture:    getFirstFlag(p.flags, "--scripture"),
+		FolderID:     getFirstFlag(p.flags, "--folder-id"),
+		Cues:         translatePath(p, getFirstFlag(p.flags, "--cues")),
+		Target:       getFirstFlag(p.flags, "--target"),
+		Type:         getFirstFlag(p.flags, "--type"),
+		WithVideo:    withVideo,
+		VideoHost:    testVideoHost(),
+		Quick:        quick,
+		FS:           p.fileChecker.fs,
+	}
+
+	if step := getFirstFlag(p.flags, "--simulate-failure"); step != "" {
+		n, err := strconv.Atoi(step)
+		if err != nil {
+			return fmt.Errorf("invalid --simulate-failure %q: %w", step, err)
+		}
+		input.SimulateFailureAt = n
+	}
+	if track := getFirstFlag(p.flags, "--audio-track"); track != "" {
+		n, err := strconv.Atoi(track)
+		if err != nil {
+			return fmt.Errorf("invalid --audio-track %q: %w", track, err)
+		}
+		input.AudioTrack = n
+	}
+	if p.publisher != nil {
+		input.Publisher = p.publisher
+	}
+	if h := getHistoryContext(); h != nil && h.store != nil {
+		input.History = h.store
+	}
+	if p.duration > 0 {
+		input.Prober = &mockDurationProber{duration: p.duration}
+	}
+	if p.modTimes != nil {
+		input.ModTimes = p.modTimes
+	}
+	if p.geometry != nil {
+		input.GeometryProber = &mockGeometryProber{geometry: *p.geometry}
+	}
+	if resumeStore != nil {
+		input.RunState = resumeStore
+	}
+	input.DetectionConfidence = p.detected
+	input.DetectionEarlyExit = p.detectedEarly
+	if _, fromOBS := p.flags["--from-obs"]; fromOBS {
+		input.Recorder = p.recorder
+		_, input.WaitForRecording = p.flags["--obs-wait"]
 	}
 
 	// Run the process command with dependencies
@@ -732,6 +1318,49 @@ func theProcessShouldFailWithError(expectedError string) error {
 	return nil
 }
 
+func theProcessErrorCodeShouldBeWithExitCode(code string, exitCode int) error {
+	p := getProcessContext()
+	var validationErr *appprocess.ValidationError
+	if !errors.As(p.err, &validationErr) {
+		return fmt.Errorf("expected a validation error, got: %v", p.err)
+	}
+	if validationErr.Code != code {
+		return fmt.Errorf("expected code %s, got %q", code, validationErr.Code)
+	}
+	if got := cmd.ExitCode(p.err); got != exitCode {
+		return fmt.Errorf("expected exit code %d, got %d", exitCode, got)
+	}
+	return nil
+}
+
+func theJSONErrorReportShouldInclude(expected string) error {
+	p := getProcessContext()
+	if p.err == nil {
+		return fmt.Errorf("expected the process to fail")
+	}
+	var buf bytes.Buffer
+	if err := cmd.WriteError(&buf, p.err, cmd.ErrorFormatJSON); err != nil {
+		return err
+	}
+	expected = strings.ReplaceAll(expected, `\"`, `"`)
+	if !strings.Contains(buf.String(), expected) {
+		return fmt.Errorf("expected JSON error report to include %s, got: %s", expected, buf.String())
+	}
+	return nil
+}
+
+func theProcessShouldStopForInputWithReason(reason string) error {
+	p := getProcessContext()
+	var inputErr *ui.InputRequiredError
+	if !errors.As(p.err, &inputErr) {
+		return fmt.Errorf("expected the process to stop for input, got: %v\nOutput: %s", p.err, p.output.String())
+	}
+	if inputErr.Reason != reason || !strings.HasPrefix(p.err.Error(), "non-interactive: "+reason+": ") {
+		return fmt.Errorf("expected reason %q, got: %v", reason, p.err)
+	}
+	return nil
+}
+
 func theErrorShouldSuggestCommand(expectedCmd string) error {
 	p := getProcessContext()
 	if p.err == nil {
@@ -777,6 +1406,24 @@ func driveCleanupShouldBeCalledWithSpaceForFiles(fileCount int) error {
 	return nil
 }
 
+func cleanupShouldNotBeCalled() error {
+	p := getProcessContext()
+	if ids := p.driveService.deletedFileIDs; len(ids) > 0 {
+		return fmt.Errorf("expected no Drive deletions, got %v", ids)
+	}
+	return nil
+}
+
+func theProcessAuditLogShouldRecordTheCleanup(decision string) error {
+	p := getProcessContext()
+	for _, e := range p.auditLog.events {
+		if e.Action == audit.ActionCleanupDecision && e.Detail == decision {
+			return nil
+		}
+	}
+	return fmt.Errorf("no %s event with detail %q in %+v", audit.ActionCleanupDecision, decision, p.auditLog.events)
+}
+
 func theVideoShouldBeUploadedToDrive() error {
 	p := getProcessContext()
 	if !p.uploadCalled {
@@ -835,6 +1482,58 @@ func emailShouldBeSentTo(email string) error {
 	return fmt.Errorf("email to %q was not found", email)
 }
 
+func wasLastWrittenAt(path, stamp string) error {
+	p := getProcessContext()
+	written, err := time.ParseInLocation("2006-01-02 15:04", stamp, time.Local)
+	if err != nil {
+		return err
+	}
+	if p.modTimes == nil {
+		p.modTimes = processMockModTimes{}
+	}
+	p.modTimes[translatePath(p, path)] = written
+	return nil
+}
+
+func theOperatorAnswersTheCheckpointsWith(answers string) error {
+	p := getProcessContext()
+	for _, answer := range strings.Split(answers, ",") {
+		p.checkpoints = append(p.checkpoints, strings.TrimSpace(answer) == "yes")
+	}
+	return nil
+}
+
+func theOperatorAddsAtTheEmailStep(query, list string) error {
+	p := getProcessContext()
+	choice := "Add a recipient"
+	if list == "CCs" {
+		choice = "Add a CC"
+	}
+	p.reviewChoices = append(p.reviewChoices, choice)
+	p.reviewInputs = append(p.reviewInputs, query)
+	return nil
+}
+
+func theOperatorRemovesAtTheEmailStep(recipient string) error {
+	p := getProcessContext()
+	p.reviewChoices = append(p.reviewChoices, "Remove someone", recipient)
+	return nil
+}
+
+func emailShouldNotBeSentTo(email string) error {
+	p := getProcessContext()
+	for _, msg := range p.gmailService.sentMessages {
+		decoded, err := base64.URLEncoding.DecodeString(msg.Raw)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(string(decoded), email) {
+			return fmt.Errorf("expected no email to %q, but one was sent", email)
+		}
+	}
+	return nil
+}
+
 func emailShouldIncludeMinister(minister string) error {
 	p := getProcessContext()
 	if !p.emailSent {
@@ -934,6 +1633,48 @@ func theRecoveryShouldSuggestCommand(command string) error {
 	return nil
 }
 
+func theOutputShouldNotInclude(unexpected string) error {
+	p := getProcessContext()
+	output := p.output.String()
+	if strings.Contains(output, unexpected) {
+		return fmt.Errorf("expected output not to include %q:\n%s", unexpected, output)
+	}
+	return nil
+}
+
+// recoveryEmailCommand returns the send-email line from the recovery output
+func recoveryEmailCommand() (string, error) {
+	p := getProcessContext()
+	for _, line := range strings.Split(p.output.String(), "\n") {
+		if strings.Contains(line, "nac-service-media send-email") {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("no send-email recovery command in output:\n%s", p.output.String())
+}
+
+func theRecoveryEmailCommandShouldIncludeMinister(name string) error {
+	line, err := recoveryEmailCommand()
+	if err != nil {
+		return err
+	}
+	if expected := fmt.Sprintf("--minister %q", name); !strings.Contains(line, expected) {
+		return fmt.Errorf("expected %s in recovery command: %s", expected, line)
+	}
+	return nil
+}
+
+func theRecoveryEmailCommandShouldIncludeURL(kind, url string) error {
+	line, err := recoveryEmailCommand()
+	if err != nil {
+		return err
+	}
+	if expected := fmt.Sprintf("--%s-url %q", kind, url); !strings.Contains(line, expected) {
+		return fmt.Errorf("expected %s in recovery command: %s", expected, line)
+	}
+	return nil
+}
+
 // --- Skip video mode step implementations ---
 
 func theVideoShouldNotBeTrimmed() error {
@@ -962,6 +1703,202 @@ func theAudioShouldBeExtractedWithTimestamps(start, end string) error {
 	return nil
 }
 
+func trimmingWillFailWith(errMsg string) error {
+	p := getProcessContext()
+	p.trimmer.shouldFail = true
+	p.trimmer.failError = fmt.Errorf("%s", errMsg)
+	return nil
+}
+
+func streamingTheAudioWillFailWith(errMsg string) error {
+	getProcessContext().extractor.streamError = fmt.Errorf("%s", errMsg)
+	return nil
+}
+
+func driveWillStoreStreamedUploadsTruncated() error {
+	getProcessContext().driveService.truncateStreams = true
+	return nil
+}
+
+func theAudioShouldBeStreamedToDrive() error {
+	p := getProcessContext()
+	if len(p.extractor.streamCalls) == 0 {
+		return fmt.Errorf("audio was not streamed")
+	}
+	if len(p.extractor.calls) > 0 {
+		return fmt.Errorf("audio was also extracted to a file")
+	}
+	for _, f := range p.driveService.streamedFiles {
+		if strings.HasSuffix(f.Name, ".mp3") {
+			return nil
+		}
+	}
+	return fmt.Errorf("no audio was uploaded from a stream")
+}
+
+func theAudioShouldNotBeStreamedToDrive() error {
+	p := getProcessContext()
+	if len(p.extractor.calls) == 0 {
+		return fmt.Errorf("audio was not extracted to a file")
+	}
+	for _, f := range p.driveService.streamedFiles {
+		if !slices.Contains(p.driveService.deletedFileIDs, f.Id) {
+			return fmt.Errorf("streamed upload %s (%s) was kept", f.Name, f.Id)
+		}
+	}
+	return nil
+}
+
+func theStreamedAudioShouldBeSavedLocally() error {
+	p := getProcessContext()
+	if len(p.extractor.streamCalls) == 0 {
+		return fmt.Errorf("audio was not streamed")
+	}
+	path := p.extractor.streamCalls[0].OutputPath(p.cfg.Paths.AudioDirectory)
+	data, err := p.fileChecker.fs.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("streamed audio was not saved: %v", err)
+	}
+	if string(data) != "mock streamed audio" {
+		return fmt.Errorf("saved audio %q does not match what was streamed", data)
+	}
+	return nil
+}
+
+func theStreamedAudioShouldNotBeSavedLocally() error {
+	p := getProcessContext()
+	if len(p.extractor.streamCalls) == 0 {
+		return fmt.Errorf("audio was not streamed")
+	}
+	path := p.extractor.streamCalls[0].OutputPath(p.cfg.Paths.AudioDirectory)
+	if _, err := p.fileChecker.fs.ReadFile(path); err == nil {
+		return fmt.Errorf("streamed audio was saved to %s", path)
+	}
+	return nil
+}
+
+func theProcessConfigKeepsStreamedAudioLocally() error {
+	getProcessContext().cfg.Audio.StreamKeepLocal = true
+	return nil
+}
+
+func theProcessConfigHasAudioTrack(track int) error {
+	getProcessContext().cfg.Audio.Track = track
+	return nil
+}
+
+func theProcessConfigMakesAudioAtBitrates(list string) error {
+	p := getProcessContext()
+	p.cfg.Audio.Bitrates = nil
+	for _, b := range strings.Split(list, ",") {
+		p.cfg.Audio.Bitrates = append(p.cfg.Audio.Bitrates, config.Bitrate(strings.TrimSpace(b)))
+	}
+	p.cfg.Audio.Bitrate = p.cfg.Audio.Bitrates[0]
+	return nil
+}
+
+func theAudioShouldBeExtractedAtBitrates(list string) error {
+	p := getProcessContext()
+	got := make(map[string]string)
+	for _, call := range p.extractor.calls {
+		got[call.req.Bitrate] = filepath.Base(call.outputPath)
+	}
+	want := strings.Split(list, ",")
+	if len(got) != len(want) {
+		return fmt.Errorf("expected %d extractions, got %v", len(want), got)
+	}
+	for i, b := range want {
+		b = strings.TrimSpace(b)
+		name, ok := got[b]
+		if !ok {
+			return fmt.Errorf("no extraction at %s, got %v", b, got)
+		}
+		if i == 0 && strings.Contains(name, "-"+b) {
+			return fmt.Errorf("main MP3 at %s should not be named after its bitrate: %s", b, name)
+		}
+		if i > 0 && !strings.HasSuffix(name, "-"+b+".mp3") {
+			return fmt.Errorf("extra MP3 at %s should be named after its bitrate, got %s", b, name)
+		}
+	}
+	return nil
+}
+
+func theProcessConfigHasAWeeklyUploadBudgetOf(gb float64) error {
+	getProcessContext().cfg.Budget = config.UploadBudgetConfig{WeeklyGB: gb, OnExceed: history.BudgetWarn}
+	return nil
+}
+
+func theProcessConfigAsksBeforeGoingOverAWeeklyUploadBudgetOf(gb float64) error {
+	getProcessContext().cfg.Budget = config.UploadBudgetConfig{WeeklyGB: gb, OnExceed: history.BudgetConfirm}
+	return nil
+}
+
+func theProcessConfigDrawsTheWatermark(text string) error {
+	getProcessContext().cfg.Video.Watermark = config.WatermarkConfig{Enabled: true, Text: text}
+	return nil
+}
+
+func theTrimmedVideoShouldCarryTheWatermark(text string) error {
+	p := getProcessContext()
+	if !p.trimCalled {
+		return fmt.Errorf("trim was not called")
+	}
+	if got := p.trimmer.calls[0].req.Watermark.Text; got != text {
+		return fmt.Errorf("expected watermark %q, got %q", text, got)
+	}
+	return nil
+}
+
+func theProcessConfigIncludesTheFolderLinkInEmails() error {
+	getProcessContext().cfg.Email.IncludeFolderLink = true
+	return nil
+}
+
+func theProcessConfigLinksTheLivestreamChannel(url string) error {
+	getProcessContext().cfg.Email.LivestreamURL = url
+	return nil
+}
+
+func theTrimmedVideoShouldKeepAudioTrack(track int) error {
+	p := getProcessContext()
+	if !p.trimCalled {
+		return fmt.Errorf("trim was not called")
+	}
+	if got := p.trimmer.calls[0].req.AudioTrack; got != track {
+		return fmt.Errorf("expected trimmed video to keep audio track %d, got %d", track, got)
+	}
+	return nil
+}
+
+// theAudioShouldBeExtractedFromAudioTrack checks the track read by extraction;
+// 0 is the input's default stream
+func theAudioShouldBeExtractedFromAudioTrack(track int) error {
+	p := getProcessContext()
+	if !p.extractCalled {
+		return fmt.Errorf("audio extraction was not called")
+	}
+	if got := p.extractor.calls[0].req.AudioTrack; got != track {
+		return fmt.Errorf("expected audio track %d to be extracted, got %d", track, got)
+	}
+	return nil
+}
+
+// theTrimmedVideoAndAudioShouldBeTagged checks the metadata requested for both outputs
+func theTrimmedVideoAndAudioShouldBeTagged(title, scripture string) error {
+	p := getProcessContext()
+	if !p.trimCalled || !p.extractCalled {
+		return fmt.Errorf("trim and audio extraction were not both called")
+	}
+	want := video.MediaTags{Title: title, Scripture: scripture}
+	if got := p.trimmer.calls[0].req.Tags; got != want {
+		return fmt.Errorf("expected trimmed video tags %+v, got %+v", want, got)
+	}
+	if got := p.extractor.calls[0].req.Tags; got != want {
+		return fmt.Errorf("expected audio tags %+v, got %+v", want, got)
+	}
+	return nil
+}
+
 func theVideoShouldNotBeUploadedToDrive() error {
 	p := getProcessContext()
 	// Check that no mp4 files were uploaded
@@ -973,6 +1910,24 @@ func theVideoShouldNotBeUploadedToDrive() error {
 	return nil
 }
 
+func theAudioShouldNotBeUploadedToDrive() error {
+	p := getProcessContext()
+	for _, f := range p.driveService.uploadedFiles {
+		if strings.HasSuffix(f.Name, ".mp3") {
+			return fmt.Errorf("expected no audio upload, but found: %s", f.Name)
+		}
+	}
+	return nil
+}
+
+func theProcessShouldNotSendAnEmail() error {
+	p := getProcessContext()
+	if len(p.gmailService.sentMessages) > 0 {
+		return fmt.Errorf("expected no email, but %d were sent", len(p.gmailService.sentMessages))
+	}
+	return nil
+}
+
 func emailShouldIncludeAudioLinkOnly() error {
 	p := getProcessContext()
 	if !p.emailSent {
@@ -996,3 +1951,57 @@ func emailShouldIncludeAudioLinkOnly() error {
 	}
 	return fmt.Errorf("no matching email found")
 }
+
+func runSummariesAreArchivedInATemporaryDirectory() error {
+	p := getProcessContext()
+	dir, err := os.MkdirTemp("", "process-test-summaries")
+	if err != nil {
+		return err
+	}
+	p.summaryDir = dir
+	return nil
+}
+
+func theSummaryFormatsAre(formats string) error {
+	getProcessContext().cfg.Summary.Formats = formats
+	return nil
+}
+
+func theServiceTimezoneIsAndRecordingsAreNamedIn(timezone, recording string) error {
+	p := getProcessContext()
+	p.cfg.Locale.Timezone = timezone
+	p.cfg.Locale.RecordingTimezone = recording
+	return nil
+}
+
+func theRunSummaryShouldInclude(name, expected string) error {
+	p := getProcessContext()
+	data, err := os.ReadFile(filepath.Join(p.summaryDir, name))
+	if err != nil {
+		return fmt.Errorf("run summary %s was not written: %v\nOutput:\n%s", name, err, p.output.String())
+	}
+	if !strings.Contains(string(data), expected) {
+		return fmt.Errorf("expected run summary %s to include %q, got:\n%s", name, expected, data)
+	}
+	return nil
+}
+
+func theRunSummaryShouldNotInclude(name, unexpected string) error {
+	p := getProcessContext()
+	data, err := os.ReadFile(filepath.Join(p.summaryDir, name))
+	if err != nil {
+		return fmt.Errorf("run summary %s was not written: %v", name, err)
+	}
+	if strings.Contains(string(data), unexpected) {
+		return fmt.Errorf("expected run summary %s not to include %q, got:\n%s", name, unexpected, data)
+	}
+	return nil
+}
+
+func theRunSummaryShouldNotExist(name string) error {
+	p := getProcessContext()
+	if _, err := os.Stat(filepath.Join(p.summaryDir, name)); !os.IsNotExist(err) {
+		return fmt.Errorf("expected run summary %s not to exist (stat error: %v)", name, err)
+	}
+	return nil
+}
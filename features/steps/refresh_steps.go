@@ -0,0 +1,461 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	appnotif "nac-service-media/application/notification"
+	appvideo "nac-service-media/application/video"
+	"nac-service-media/cmd"
+	"nac-service-media/domain/notification"
+	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/drive"
+	"nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/gmail"
+
+	googledrive "google.golang.org/api/drive/v3"
+
+	"github.com/cucumber/godog"
+)
+
+// nameQuery extracts the exact-name filter from a Drive query
+var nameQuery = regexp.MustCompile(`name = '([^']*)'`)
+
+// refreshDriveService is a Drive mock that honors name queries, can replace
+// and download file content, and keeps revisions of the files given some
+type refreshDriveService struct {
+	mockDriveService
+	byName     map[string]*googledrive.File
+	updatedIDs []string
+	created    []string
+	downloads  []string
+
+	revisions        map[string][]*googledrive.Revision
+	revisionDownload []string
+}
+
+func (m *refreshDriveService) ListFiles(ctx context.Context, query string, fields string, orderBy string) ([]*googledrive.File, error) {
+	match := nameQuery.FindStringSubmatch(query)
+	if match == nil {
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	if f, ok := m.byName[match[1]]; ok {
+		return []*googledrive.File{f}, nil
+	}
+	return nil, nil
+}
+
+func (m *refreshDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*googledrive.File, error) {
+	m.created = append(m.created, fileName)
+	return m.mockDriveService.UploadFile(ctx, fileName, mimeType, folderID, localPath, appProperties)
+}
+
+func (m *refreshDriveService) UpdateFileContent(ctx context.Context, fileID, mimeType, localPath string) (*googledrive.File, error) {
+	m.updatedIDs = append(m.updatedIDs, fileID)
+	if revs, ok := m.revisions[fileID]; ok {
+		modified := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(len(revs)) * time.Hour)
+		m.revisions[fileID] = append(revs, &googledrive.Revision{
+			Id:           fmt.Sprintf("new-%d", len(m.updatedIDs)),
+			ModifiedTime: modified.Format(time.RFC3339),
+			Size:         2048,
+		})
+	}
+	return &googledrive.File{Id: fileID, Size: 2048}, nil
+}
+
+func (m *refreshDriveService) ListRevisions(ctx context.Context, fileID string) ([]*googledrive.Revision, error) {
+	return m.revisions[fileID], nil
+}
+
+func (m *refreshDriveService) UpdateRevision(ctx context.Context, fileID, revisionID string, keepForever bool) error {
+	rev, err := m.revision(fileID, revisionID)
+	if err != nil {
+		return err
+	}
+	rev.KeepForever = keepForever
+	return nil
+}
+
+func (m *refreshDriveService) DownloadRevision(ctx context.Context, fileID, revisionID string, w io.Writer) error {
+	if _, err := m.revision(fileID, revisionID); err != nil {
+		return err
+	}
+	m.revisionDownload = append(m.revisionDownload, fileID+"@"+revisionID)
+	_, err := io.WriteString(w, "audio data")
+	return err
+}
+
+func (m *refreshDriveService) revision(fileID, revisionID string) (*googledrive.Revision, error) {
+	for _, r := range m.revisions[fileID] {
+		if r.Id == revisionID {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("file %s has no revision %s", fileID, revisionID)
+}
+
+func (m *refreshDriveService) DownloadFile(ctx context.Context, fileID string, w io.Writer) error {
+	m.downloads = append(m.downloads, fileID)
+	_, err := io.WriteString(w, "video data")
+	return err
+}
+
+// refreshContext holds test state for audio refresh scenarios
+type refreshContext struct {
+	dir        string
+	drive      *refreshDriveService
+	extractor  *mockExtractor
+	gmail      *mockGmailService
+	notifier   *appnotif.Service
+	recipients []notification.Recipient
+	keep       int
+	track      int // audio.track in config
+	output     *bytes.Buffer
+	err        error
+}
+
+// SharedRefreshContext is reset before each scenario via Before hook
+var SharedRefreshContext *refreshContext
+
+func getRefreshContext() *refreshContext {
+	return SharedRefreshContext
+}
+
+func InitializeRefreshScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		dir, err := os.MkdirTemp("", "refresh-test-*")
+		if err != nil {
+			return c, err
+		}
+		SharedRefreshContext = &refreshContext{
+			dir: dir,
+			drive: &refreshDriveService{
+				byName:    make(map[string]*googledrive.File),
+				revisions: make(map[string][]*googledrive.Revision),
+			},
+			extractor: &mockExtractor{},
+			gmail:     &mockGmailService{},
+			output:    &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if r := getRefreshContext(); r != nil {
+			os.RemoveAll(r.dir)
+		}
+		SharedRefreshContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^Drive holds "([^"]*)" with ID "([^"]*)" for the refresh$`, driveHoldsForTheRefresh)
+	ctx.Step(`^Drive has no "([^"]*)" for the refresh$`, driveHasNoForTheRefresh)
+	ctx.Step(`^the trimmed video "([^"]*)" exists locally for the refresh$`, theTrimmedVideoExistsLocallyForTheRefresh)
+	ctx.Step(`^the refresh notifies "([^"]*)" at "([^"]*)"$`, theRefreshNotifies)
+	ctx.Step(`^I refresh the audio for "([^"]*)" with bitrate "([^"]*)"$`, iRefreshTheAudioForWithBitrate)
+	ctx.Step(`^I refresh the audio for "([^"]*)" from "([^"]*)"$`, iRefreshTheAudioForFrom)
+	ctx.Step(`^audio\.track is (\d+) in config for the refresh$`, audioTrackIsInConfigForTheRefresh)
+	ctx.Step(`^the refreshed audio should be read from audio track (\d+)$`, theRefreshedAudioShouldBeReadFromAudioTrack)
+	ctx.Step(`^the refresh should succeed$`, theRefreshShouldSucceed)
+	ctx.Step(`^the refresh should fail with "([^"]*)"$`, theRefreshShouldFailWith)
+	ctx.Step(`^the audio should have been re-extracted with bitrate "([^"]*)"$`, theAudioShouldHaveBeenReExtractedWithBitrate)
+	ctx.Step(`^Drive file "([^"]*)" should have been given new content$`, driveFileShouldHaveBeenGivenNewContent)
+	ctx.Step(`^no Drive file should have been created by the refresh$`, noDriveFileShouldHaveBeenCreatedByTheRefresh)
+	ctx.Step(`^Drive file "([^"]*)" should have been downloaded for the refresh$`, driveFileShouldHaveBeenDownloadedForTheRefresh)
+	ctx.Step(`^the refresh output should contain "([^"]*)"$`, theRefreshOutputShouldContain)
+	ctx.Step(`^the refresh email should contain "([^"]*)"$`, theRefreshEmailShouldContain)
+	ctx.Step(`^no refresh email should be sent$`, noRefreshEmailShouldBeSent)
+
+	ctx.Step(`^Drive file "([^"]*)" has revisions "([^"]*)"$`, driveFileHasRevisions)
+	ctx.Step(`^Drive revision "([^"]*)" of "([^"]*)" is pinned$`, driveRevisionOfIsPinned)
+	ctx.Step(`^replaced versions are kept up to (\d+)$`, replacedVersionsAreKeptUpTo)
+	ctx.Step(`^Drive revision "([^"]*)" of "([^"]*)" should be pinned$`, driveRevisionOfShouldBePinned)
+	ctx.Step(`^Drive revision "([^"]*)" of "([^"]*)" should not be pinned$`, driveRevisionOfShouldNotBePinned)
+	ctx.Step(`^I list the Drive revisions of the (audio|video) for "([^"]*)"$`, iListTheDriveRevisionsOf)
+	ctx.Step(`^I restore the (audio|video) for "([^"]*)" to revision "([^"]*)"$`, iRestoreToRevision)
+	ctx.Step(`^Drive file "([^"]*)" should have been restored from revision "([^"]*)"$`, driveFileShouldHaveBeenRestoredFromRevision)
+}
+
+func (r *refreshContext) trimmedDir() string {
+	return filepath.Join(r.dir, "trimmed")
+}
+
+func driveHoldsForTheRefresh(name, id string) error {
+	r := getRefreshContext()
+	r.drive.byName[name] = &googledrive.File{Id: id, Name: name, Size: 1024}
+	return nil
+}
+
+func driveHasNoForTheRefresh(name string) error {
+	r := getRefreshContext()
+	delete(r.drive.byName, name)
+	return nil
+}
+
+func theTrimmedVideoExistsLocallyForTheRefresh(name string) error {
+	r := getRefreshContext()
+	if err := os.MkdirAll(r.trimmedDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.trimmedDir(), name), []byte("video data"), 0644)
+}
+
+func theRefreshNotifies(name, address string) error {
+	r := getRefreshContext()
+	from := notification.Recipient{Name: "White Plains", Address: "whiteplainsnac@gmail.com"}
+	client := gmail.NewClient(from, gmail.WithGmailService(r.gmail))
+	r.notifier = appnotif.NewService(client, "White Plains", "Jonathan")
+	r.recipients = append(r.recipients, notification.Recipient{Name: name, Address: address})
+	return nil
+}
+
+func audioTrackIsInConfigForTheRefresh(track int) error {
+	r := getRefreshContext()
+	r.track = track
+	return nil
+}
+
+func iRefreshTheAudioForWithBitrate(date, bitrate string) error {
+	r := getRefreshContext()
+	return r.refresh(date, bitrate, filepath.Join(r.trimmedDir(), date+".mp4"))
+}
+
+// iRefreshTheAudioForFrom refreshes from a video named outside the trimmed
+// folder, as with extract-audio --source
+func iRefreshTheAudioForFrom(date, name string) error {
+	r := getRefreshContext()
+	return r.refresh(date, "192k", filepath.Join(r.dir, name))
+}
+
+// refresh runs the refresh as extract-audio --replace-drive would, with the
+// audio track it picks
+func (r *refreshContext) refresh(date, bitrate, sourcePath string) error {
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+
+	client, err := drive.NewClient(context.Background(), "", drive.WithDriveService(r.drive))
+	if err != nil {
+		return err
+	}
+	cfg := &config.Config{
+		Audio: config.AudioConfig{Track: r.track},
+		Paths: config.PathsConfig{TrimmedDirectory: r.trimmedDir()},
+	}
+
+	r.err = cmd.RunRefreshAudioWithDependencies(
+		context.Background(),
+		r.extractor,
+		filesystem.NewChecker(),
+		filepath.Join(r.dir, "audio"),
+		bitrate,
+		sourcePath,
+		serviceDate,
+		cmd.AudioRefresh{
+			Drive:    client,
+			FolderID: "services-folder",
+			Notifier: r.notifier,
+			Notify:   r.recipients,
+
+			KeepRevisions: r.keep,
+		},
+		r.output,
+		appvideo.WithAudioTrack(cmd.ExtractAudioTrack(cfg, video.DefaultAudioTrack, sourcePath, true)),
+	)
+	return nil
+}
+
+func theRefreshShouldSucceed() error {
+	r := getRefreshContext()
+	if r.err != nil {
+		return fmt.Errorf("expected success, got %v\noutput:\n%s", r.err, r.output.String())
+	}
+	return nil
+}
+
+func theRefreshShouldFailWith(expected string) error {
+	r := getRefreshContext()
+	if r.err == nil {
+		return fmt.Errorf("expected an error containing %q, got success", expected)
+	}
+	if !strings.Contains(r.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %v", expected, r.err)
+	}
+	return nil
+}
+
+func theAudioShouldHaveBeenReExtractedWithBitrate(bitrate string) error {
+	r := getRefreshContext()
+	if len(r.extractor.calls) != 1 {
+		return fmt.Errorf("expected 1 extraction, got %d", len(r.extractor.calls))
+	}
+	if got := r.extractor.calls[0].req.Bitrate; got != bitrate {
+		return fmt.Errorf("expected bitrate %s, got %s", bitrate, got)
+	}
+	return nil
+}
+
+func theRefreshedAudioShouldBeReadFromAudioTrack(track int) error {
+	r := getRefreshContext()
+	if len(r.extractor.calls) != 1 {
+		return fmt.Errorf("expected 1 extraction, got %d", len(r.extractor.calls))
+	}
+	if got := r.extractor.calls[0].req.AudioTrack; got != track {
+		return fmt.Errorf("expected audio track %d, got %d", track, got)
+	}
+	return nil
+}
+
+func driveFileShouldHaveBeenGivenNewContent(id string) error {
+	r := getRefreshContext()
+	for _, updated := range r.drive.updatedIDs {
+		if updated == id {
+			return nil
+		}
+	}
+	return fmt.Errorf("drive file %s was not updated; updated: %v", id, r.drive.updatedIDs)
+}
+
+func noDriveFileShouldHaveBeenCreatedByTheRefresh() error {
+	r := getRefreshContext()
+	if len(r.drive.created) > 0 {
+		return fmt.Errorf("expected no new Drive files, got %v", r.drive.created)
+	}
+	if len(r.drive.deletedFileIDs) > 0 {
+		return fmt.Errorf("expected no Drive files deleted, got %v", r.drive.deletedFileIDs)
+	}
+	return nil
+}
+
+func driveFileShouldHaveBeenDownloadedForTheRefresh(id string) error {
+	r := getRefreshContext()
+	if len(r.drive.downloads) != 1 || r.drive.downloads[0] != id {
+		return fmt.Errorf("expected %s to be downloaded, got %v", id, r.drive.downloads)
+	}
+	return nil
+}
+
+func theRefreshOutputShouldContain(expected string) error {
+	r := getRefreshContext()
+	if !strings.Contains(r.output.String(), expected) {
+		return fmt.Errorf("expected output to contain %q, got:\n%s", expected, r.output.String())
+	}
+	return nil
+}
+
+func theRefreshEmailShouldContain(expected string) error {
+	r := getRefreshContext()
+	if len(r.gmail.sentMessages) != 1 {
+		return fmt.Errorf("expected 1 email, got %d", len(r.gmail.sentMessages))
+	}
+	raw, err := decodeMessage(r.gmail.sentMessages[0])
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(raw, expected) {
+		return fmt.Errorf("email doesn't contain %q in:\n%s", expected, raw)
+	}
+	return nil
+}
+
+func noRefreshEmailShouldBeSent() error {
+	r := getRefreshContext()
+	if n := len(r.gmail.sentMessages); n != 0 {
+		return fmt.Errorf("expected no email, got %d", n)
+	}
+	return nil
+}
+
+func driveFileHasRevisions(fileID, ids string) error {
+	r := getRefreshContext()
+	start := time.Date(2025, 12, 28, 14, 0, 0, 0, time.UTC)
+	var revs []*googledrive.Revision
+	for i, id := range strings.Split(ids, ",") {
+		revs = append(revs, &googledrive.Revision{
+			Id:           strings.TrimSpace(id),
+			ModifiedTime: start.Add(time.Duration(i) * 24 * time.Hour).Format(time.RFC3339),
+			Size:         1024,
+		})
+	}
+	r.drive.revisions[fileID] = revs
+	return nil
+}
+
+func driveRevisionOfIsPinned(revisionID, fileID string) error {
+	return getRefreshContext().drive.UpdateRevision(context.Background(), fileID, revisionID, true)
+}
+
+func replacedVersionsAreKeptUpTo(keep int) error {
+	getRefreshContext().keep = keep
+	return nil
+}
+
+func driveRevisionOfShouldBePinned(revisionID, fileID string) error {
+	rev, err := getRefreshContext().drive.revision(fileID, revisionID)
+	if err != nil {
+		return err
+	}
+	if !rev.KeepForever {
+		return fmt.Errorf("expected revision %s of %s to be pinned", revisionID, fileID)
+	}
+	return nil
+}
+
+func driveRevisionOfShouldNotBePinned(revisionID, fileID string) error {
+	rev, err := getRefreshContext().drive.revision(fileID, revisionID)
+	if err != nil {
+		return err
+	}
+	if rev.KeepForever {
+		return fmt.Errorf("expected revision %s of %s not to be pinned", revisionID, fileID)
+	}
+	return nil
+}
+
+func (r *refreshContext) revisionsInput(mediaType, date string) (*drive.Client, cmd.DriveRevisionsInput, error) {
+	client, err := drive.NewClient(context.Background(), "", drive.WithDriveService(r.drive))
+	return client, cmd.DriveRevisionsInput{Date: date, Type: mediaType, Keep: r.keep}, err
+}
+
+func iListTheDriveRevisionsOf(mediaType, date string) error {
+	r := getRefreshContext()
+	client, input, err := r.revisionsInput(mediaType, date)
+	if err != nil {
+		return err
+	}
+	r.err = cmd.RunDriveRevisionsListWithDependencies(context.Background(), client, "services-folder", input, r.output)
+	return nil
+}
+
+func iRestoreToRevision(mediaType, date, revisionID string) error {
+	r := getRefreshContext()
+	client, input, err := r.revisionsInput(mediaType, date)
+	if err != nil {
+		return err
+	}
+	input.Revision = revisionID
+	r.err = cmd.RunDriveRevisionsRestoreWithDependencies(context.Background(), client, "services-folder", input, r.output)
+	return nil
+}
+
+func driveFileShouldHaveBeenRestoredFromRevision(fileID, revisionID string) error {
+	r := getRefreshContext()
+	want := fileID + "@" + revisionID
+	for _, got := range r.drive.revisionDownload {
+		if got == want {
+			return driveFileShouldHaveBeenGivenNewContent(fileID)
+		}
+	}
+	return fmt.Errorf("revision %s of %s was not downloaded; downloaded: %v", revisionID, fileID, r.drive.revisionDownload)
+}
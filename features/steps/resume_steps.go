@@ -0,0 +1,117 @@
+//go:build integration
+
+package steps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/runstate"
+	infrarunstate "nac-service-media/infrastructure/runstate"
+
+	"github.com/cucumber/godog"
+)
+
+// resumeStore keeps the process runs saved for --resume, when a scenario asks for it
+var resumeStore *infrarunstate.DirStore
+
+func InitializeResumeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		resumeStore = nil
+		return c, nil
+	})
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if resumeStore != nil {
+			os.RemoveAll(resumeStore.Dir())
+		}
+		resumeStore = nil
+		return c, nil
+	})
+
+	ctx.Step(`^process saves its progress for --resume$`, processSavesItsProgress)
+	ctx.Step(`^I resume the process run for "([^"]*)"$`, iResumeTheProcessRunFor)
+	ctx.Step(`^no process step should have run twice$`, noProcessStepShouldHaveRunTwice)
+	ctx.Step(`^no process run should be saved for "([^"]*)"$`, noProcessRunShouldBeSavedFor)
+}
+
+func processSavesItsProgress() error {
+	dir, err := os.MkdirTemp("", "nac-state-*")
+	if err != nil {
+		return err
+	}
+	resumeStore = infrarunstate.NewDirStore(dir)
+	return nil
+}
+
+func iResumeTheProcessRunFor(date string) error {
+	p := getProcessContext()
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	state, err := resumeStore.Load(serviceDate)
+	if err != nil {
+		return fmt.Errorf("no saved run to resume: %w", err)
+	}
+
+	input := cmd.ResumeProcessInput(state)
+	input.RunState = resumeStore
+	input.AuditLog = p.auditLog
+	input.Prompter = NewMockPrompter(p.reviewInputs, p.checkpoints)
+	input.FS = p.fileChecker.fs
+	p.output.Reset()
+	p.err = cmd.RunProcessWithDependencies(
+		context.Background(),
+		p.cfg,
+		p.trimmer,
+		p.extractor,
+		p.fileChecker,
+		p.driveService,
+		p.gmailService,
+		p.fileFinder,
+		input,
+		p.output,
+		p.diskChecker,
+		p.fileRemover,
+	)
+
+	p.uploadCalled = len(p.driveService.uploadedFiles) > 0
+	p.shareCalled = len(p.driveService.permissions) > 0
+	p.emailSent = len(p.gmailService.sentMessages) > 0
+	return nil
+}
+
+func noProcessStepShouldHaveRunTwice() error {
+	p := getProcessContext()
+	if n := len(p.trimmer.calls); n > 1 {
+		return fmt.Errorf("expected the video to be trimmed at most once, got %d times", n)
+	}
+	if n := len(p.extractor.calls) + len(p.extractor.streamCalls); n > 1 {
+		return fmt.Errorf("expected the audio to be extracted at most once, got %d times", n)
+	}
+	uploads := map[string]int{}
+	for _, f := range p.driveService.uploadedFiles {
+		uploads[f.Name]++
+	}
+	for name, n := range uploads {
+		if n > 1 {
+			return fmt.Errorf("expected %s to be uploaded once, got %d times", name, n)
+		}
+	}
+	return nil
+}
+
+func noProcessRunShouldBeSavedFor(date string) error {
+	serviceDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	if _, err := resumeStore.Load(serviceDate); !errors.Is(err, runstate.ErrNoState) {
+		return fmt.Errorf("expected the saved run for %s to be cleared, got %v", date, err)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/s3"
+
+	"github.com/cucumber/godog"
+)
+
+// fakeBucket is an in-memory, path-style S3 server for a single bucket
+type fakeBucket struct {
+	name   string
+	denied bool
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (b *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.denied {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>")
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != b.name {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if key == "" {
+		b.list(w, r.URL.Query().Get("prefix"), r.URL.Query().Get("delimiter"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		b.objects[key] = data
+	case http.MethodHead, http.MethodGet:
+		data, ok := b.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (b *fakeBucket) list(w http.ResponseWriter, prefix, delimiter string) {
+	var keys []string
+	for key := range b.objects {
+		rest, ok := strings.CutPrefix(key, prefix)
+		if ok && (delimiter == "" || !strings.Contains(rest, delimiter)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	fmt.Fprint(w, "<ListBucketResult>")
+	for _, key := range keys {
+		fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>2025-12-28T15:00:00.000Z</LastModified></Contents>",
+			key, len(b.objects[key]))
+	}
+	fmt.Fprint(w, "</ListBucketResult>")
+}
+
+// s3StorageContext holds test state for S3 storage scenarios
+type s3StorageContext struct {
+	dir       string
+	bucket    *fakeBucket
+	server    *httptest.Server
+	prefix    string
+	opts      []s3.Option
+	videoPath string
+	audioPath string
+	output    *bytes.Buffer
+	err       error
+}
+
+// SharedS3StorageContext is reset before each scenario via Before hook
+var SharedS3StorageContext *s3StorageContext
+
+func getS3StorageContext() *s3StorageContext {
+	return SharedS3StorageContext
+}
+
+func InitializeS3StorageScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		dir, err := os.MkdirTemp("", "s3-storage-test-*")
+		if err != nil {
+			return c, err
+		}
+		SharedS3StorageContext = &s3StorageContext{dir: dir, output: &bytes.Buffer{}}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if s := getS3StorageContext(); s != nil {
+			if s.server != nil {
+				s.server.Close()
+			}
+			os.RemoveAll(s.dir)
+		}
+		SharedS3StorageContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^an S3 bucket "([^"]*)" storing under "([^"]*)"$`, anS3BucketStoringUnder)
+	ctx.Step(`^the S3 links expire after (\d+) hours$`, theS3LinksExpireAfterHours)
+	ctx.Step(`^the S3 bucket is served publicly at "([^"]*)"$`, theS3BucketIsServedPubliclyAt)
+	ctx.Step(`^the S3 bucket quota is "([^"]*)"$`, theS3BucketQuotaIs)
+	ctx.Step(`^the S3 server denies access$`, theS3ServerDeniesAccess)
+	ctx.Step(`^the bucket holds "([^"]*)" of (\d+) bytes$`, theBucketHoldsOfBytes)
+	ctx.Step(`^the local outputs "([^"]*)" and "([^"]*)" for S3$`, theLocalOutputsForS3)
+	ctx.Step(`^I upload the outputs to S3$`, iUploadTheOutputsToS3)
+	ctx.Step(`^I make room in S3 for "([^"]*)"$`, iMakeRoomInS3For)
+	ctx.Step(`^the S3 upload should succeed$`, theS3OperationShouldSucceed)
+	ctx.Step(`^the S3 cleanup should succeed$`, theS3OperationShouldSucceed)
+	ctx.Step(`^the S3 upload should fail with "([^"]*)"$`, theS3UploadShouldFailWith)
+	ctx.Step(`^the bucket should hold "([^"]*)"$`, theBucketShouldHold)
+	ctx.Step(`^the bucket should not hold "([^"]*)"$`, theBucketShouldNotHold)
+	ctx.Step(`^the S3 upload output should contain "([^"]*)"$`, theS3UploadOutputShouldContain)
+	ctx.Step(`^the S3 upload output should not contain "([^"]*)"$`, theS3UploadOutputShouldNotContain)
+}
+
+func anS3BucketStoringUnder(bucket, prefix string) error {
+	s := getS3StorageContext()
+	s.bucket = &fakeBucket{name: bucket, objects: make(map[string][]byte)}
+	s.server = httptest.NewServer(s.bucket)
+	s.prefix = prefix
+	return nil
+}
+
+func theS3LinksExpireAfterHours(hours int) error {
+	s := getS3StorageContext()
+	s.opts = append(s.opts, s3.WithLinkExpiry(time.Duration(hours)*time.Hour))
+	return nil
+}
+
+func theS3BucketIsServedPubliclyAt(url string) error {
+	s := getS3StorageContext()
+	s.opts = append(s.opts, s3.WithPublicURL(url))
+	return nil
+}
+
+func theS3BucketQuotaIs(size string) error {
+	s := getS3StorageContext()
+	quota, err := distribution.ParseSize(size)
+	if err != nil {
+		return err
+	}
+	s.opts = append(s.opts, s3.WithQuota(quota))
+	return nil
+}
+
+func theS3ServerDeniesAccess() error {
+	getS3StorageContext().bucket.denied = true
+	return nil
+}
+
+func theBucketHoldsOfBytes(key string, size int) error {
+	s := getS3StorageContext()
+	s.bucket.objects[key] = bytes.Repeat([]byte("x"), size)
+	return nil
+}
+
+func theLocalOutputsForS3(video, audio string) error {
+	s := getS3StorageContext()
+	s.videoPath = filepath.Join(s.dir, video)
+	s.audioPath = filepath.Join(s.dir, audio)
+	if err := os.WriteFile(s.videoPath, []byte("video data"), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(s.audioPath, []byte("audio data"), 0644)
+}
+
+func (s *s3StorageContext) client() (*s3.Client, error) {
+	opts := append([]s3.Option{
+		s3.WithCredentials("minio", "minio-secret"),
+		s3.WithPrefix(s.prefix),
+	}, s.opts...)
+	return s3.NewClient(s.server.URL, s.bucket.name, opts...)
+}
+
+func iUploadTheOutputsToS3() error {
+	s := getS3StorageContext()
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+	s.err = cmd.RunUploadWithDependencies(context.Background(), client, "", s.videoPath, s.audioPath, false, false, s.output)
+	return nil
+}
+
+func iMakeRoomInS3For(size string) error {
+	s := getS3StorageContext()
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+	s.err = cmd.RunDriveCleanupWithDependencies(context.Background(), client, "", size, "", 1, nil, s.output)
+	return nil
+}
+
+func theS3OperationShouldSucceed() error {
+	s := getS3StorageContext()
+	if s.err != nil {
+		return fmt.Errorf("expected success, got %v\noutput:\n%s", s.err, s.output.String())
+	}
+	return nil
+}
+
+func theS3UploadShouldFailWith(expected string) error {
+	s := getS3StorageContext()
+	if s.err == nil {
+		return fmt.Errorf("expected an error containing %q, got success", expected)
+	}
+	if !strings.Contains(s.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %v", expected, s.err)
+	}
+	return nil
+}
+
+func theBucketShouldHold(key string) error {
+	s := getS3StorageContext()
+	data, ok := s.bucket.objects[key]
+	if !ok {
+		return fmt.Errorf("bucket does not hold %s", key)
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("%s is empty", key)
+	}
+	return nil
+}
+
+func theBucketShouldNotHold(key string) error {
+	s := getS3StorageContext()
+	if _, ok := s.bucket.objects[key]; ok {
+		return fmt.Errorf("bucket still holds %s", key)
+	}
+	return nil
+}
+
+func theS3UploadOutputShouldContain(expected string) error {
+	s := getS3StorageContext()
+	if !strings.Contains(s.output.String(), expected) {
+		return fmt.Errorf("expected output to contain %q, got:\n%s", expected, s.output.String())
+	}
+	return nil
+}
+
+func theS3UploadOutputShouldNotContain(unexpected string) error {
+	s := getS3StorageContext()
+	if strings.Contains(s.output.String(), unexpected) {
+		return fmt.Errorf("expected output not to contain %q, got:\n%s", unexpected, s.output.String())
+	}
+	return nil
+}
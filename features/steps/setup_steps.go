@@ -11,6 +11,7 @@ import (
 
 	"nac-service-media/cmd"
 	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/ui"
 
 	"github.com/cucumber/godog"
 )
@@ -27,14 +28,19 @@ type setupContext struct {
 
 var SharedSetupContext = &setupContext{}
 
-// MockPrompter implements cmd.Prompter for testing
+// MockPrompter implements ui.Prompter for testing. Once a list of responses
+// runs out, prompts answer with their default.
 type MockPrompter struct {
 	inputResponses   []string
 	confirmResponses []bool
+	selectResponses  []string
 	inputIndex       int
 	confirmIndex     int
+	selectIndex      int
 }
 
+var _ ui.Prompter = (*MockPrompter)(nil)
+
 func NewMockPrompter(inputs []string, confirms []bool) *MockPrompter {
 	return &MockPrompter{
 		inputResponses:   inputs,
@@ -42,6 +48,13 @@ func NewMockPrompter(inputs []string, confirms []bool) *MockPrompter {
 	}
 }
 
+// WithSelections queues answers for Select and MultiSelect prompts; a
+// MultiSelect answer lists its choices separated by commas
+func (m *MockPrompter) WithSelections(answers ...string) *MockPrompter {
+	m.selectResponses = append(m.selectResponses, answers...)
+	return m
+}
+
 func (m *MockPrompter) Input(message string, defaultValue string) (string, error) {
 	if m.inputIndex >= len(m.inputResponses) {
 		if defaultValue != "" {
@@ -63,6 +76,36 @@ func (m *MockPrompter) Confirm(message string, defaultValue bool) (bool, error)
 	return response, nil
 }
 
+func (m *MockPrompter) Select(message string, options []string, defaultValue string) (string, error) {
+	if m.selectIndex >= len(m.selectResponses) {
+		return defaultValue, nil
+	}
+	response := m.selectResponses[m.selectIndex]
+	m.selectIndex++
+	if len(ui.KnownOptions(options, []string{response})) == 0 {
+		return "", fmt.Errorf("%q is not an option for %q (options: %v)", response, message, options)
+	}
+	return response, nil
+}
+
+func (m *MockPrompter) MultiSelect(message string, options []string, defaults []string) ([]string, error) {
+	if m.selectIndex >= len(m.selectResponses) {
+		return defaults, nil
+	}
+	response := m.selectResponses[m.selectIndex]
+	m.selectIndex++
+	var choices []string
+	for _, c := range strings.Split(response, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			choices = append(choices, c)
+		}
+	}
+	if known := ui.KnownOptions(options, choices); len(known) != len(choices) {
+		return nil, fmt.Errorf("%q has choices that are not options for %q (options: %v)", response, message, options)
+	}
+	return choices, nil
+}
+
 func InitializeSetupScenario(ctx *godog.ScenarioContext) {
 	testCtx := SharedSetupContext
 
@@ -104,6 +147,13 @@ func InitializeSetupScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the config should have a CC recipient "([^"]*)"$`, testCtx.theConfigShouldHaveACCRecipient)
 	ctx.Step(`^the config should have a quick-lookup recipient "([^"]*)"$`, testCtx.theConfigShouldHaveAQuickLookupRecipient)
 	ctx.Step(`^the setup should be cancelled$`, testCtx.theSetupShouldBeCancelled)
+	ctx.Step(`^the config should have minister "([^"]*)" named "([^"]*)"$`, testCtx.theConfigShouldHaveMinisterNamed)
+	ctx.Step(`^the config should have sender "([^"]*)" named "([^"]*)"$`, testCtx.theConfigShouldHaveSenderNamed)
+	ctx.Step(`^the config should have default sender "([^"]*)"$`, testCtx.theConfigShouldHaveDefaultSender)
+	ctx.Step(`^the config should have email service_type "([^"]*)"$`, testCtx.theConfigShouldHaveEmailServiceType)
+	ctx.Step(`^the config should have detection enabled with templates in "([^"]*)"$`, testCtx.theConfigShouldHaveDetectionEnabledWithTemplatesIn)
+	ctx.Step(`^the config should have a detection search range of (\d+) to (\d+) minutes$`, testCtx.theConfigShouldHaveADetectionSearchRange)
+	ctx.Step(`^the config should have detection disabled$`, testCtx.theConfigShouldHaveDetectionDisabled)
 	ctx.Step(`^the existing config should be unchanged$`, testCtx.theExistingConfigShouldBeUnchanged)
 }
 
@@ -138,8 +188,8 @@ email:
 }
 
 func (s *setupContext) iRunTheSetupCommandWithInputs(table *godog.Table) error {
-	inputs, confirms := parseInputTable(table)
-	prompter := NewMockPrompter(inputs, confirms)
+	inputs, confirms, selections := parseSetupTable(table)
+	prompter := NewMockPrompter(inputs, confirms).WithSelections(selections...)
 
 	s.err = cmd.RunSetupWithPrompter(prompter, s.configPath)
 	if s.err != nil {
@@ -175,8 +225,16 @@ func (s *setupContext) iRunTheSetupCommandWithConfirmationAndInputs(confirmation
 }
 
 func parseInputTable(table *godog.Table) ([]string, []bool) {
+	inputs, confirms, _ := parseSetupTable(table)
+	return inputs, confirms
+}
+
+// parseSetupTable splits the rows by prompt kind: "Add ..." and "Enable ..."
+// rows answer confirms, "Select ..." rows answer selections, and the rest are inputs
+func parseSetupTable(table *godog.Table) ([]string, []bool, []string) {
 	var inputs []string
 	var confirms []bool
+	var selections []string
 
 	for i, row := range table.Rows {
 		if i == 0 {
@@ -185,15 +243,17 @@ func parseInputTable(table *godog.Table) ([]string, []bool) {
 		prompt := strings.ToLower(row.Cells[0].Value)
 		value := row.Cells[1].Value
 
-		// Check if this is a confirm prompt (starts with "Add")
-		if strings.HasPrefix(prompt, "add") {
+		switch {
+		case strings.HasPrefix(prompt, "add"), strings.HasPrefix(prompt, "enable"):
 			confirms = append(confirms, strings.ToLower(value) == "y")
-		} else {
+		case strings.HasPrefix(prompt, "select"):
+			selections = append(selections, value)
+		default:
 			inputs = append(inputs, value)
 		}
 	}
 
-	return inputs, confirms
+	return inputs, confirms, selections
 }
 
 func (s *setupContext) aConfigFileShouldExist() error {
@@ -288,3 +348,84 @@ func (s *setupContext) theExistingConfigShouldBeUnchanged() error {
 	}
 	return nil
 }
+
+func (s *setupContext) theConfigShouldHaveMinisterNamed(key, name string) error {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if got := cfg.Ministers[key].Name; got != name {
+		return fmt.Errorf("expected minister %q named %q, got %q (ministers: %v)", key, name, got, cfg.Ministers)
+	}
+	return nil
+}
+
+func (s *setupContext) theConfigShouldHaveSenderNamed(key, name string) error {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if got := cfg.Senders.Senders[key].Name; got != name {
+		return fmt.Errorf("expected sender %q named %q, got %q (senders: %v)", key, name, got, cfg.Senders.Senders)
+	}
+	return nil
+}
+
+func (s *setupContext) theConfigShouldHaveDefaultSender(key string) error {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Senders.DefaultSender != key {
+		return fmt.Errorf("expected default sender %q, got %q", key, cfg.Senders.DefaultSender)
+	}
+	return nil
+}
+
+func (s *setupContext) theConfigShouldHaveEmailServiceType(expected string) error {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Email.ServiceType != expected {
+		return fmt.Errorf("expected email service_type %q, got %q", expected, cfg.Email.ServiceType)
+	}
+	return nil
+}
+
+func (s *setupContext) theConfigShouldHaveDetectionEnabledWithTemplatesIn(dir string) error {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.Detection.Enabled {
+		return fmt.Errorf("expected detection to be enabled")
+	}
+	if cfg.Detection.TemplatesDir != dir {
+		return fmt.Errorf("expected templates_dir %q, got %q", dir, cfg.Detection.TemplatesDir)
+	}
+	return nil
+}
+
+func (s *setupContext) theConfigShouldHaveADetectionSearchRange(start, end int) error {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	r := cfg.Detection.SearchRange
+	if r.StartMinutes != start || r.EndMinutes != end {
+		return fmt.Errorf("expected search range %d-%d minutes, got %d-%d", start, end, r.StartMinutes, r.EndMinutes)
+	}
+	return nil
+}
+
+func (s *setupContext) theConfigShouldHaveDetectionDisabled() error {
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Detection.Enabled {
+		return fmt.Errorf("expected detection to be disabled")
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+//go:build integration
+
+package steps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"nac-service-media/application/distribution"
+	"nac-service-media/cmd"
+	"nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/scanner"
+
+	"github.com/cucumber/godog"
+)
+
+// shareScanContext holds the pre-share scanner for upload scenarios
+type shareScanContext struct {
+	scanner  *scanner.Command
+	localDir string
+}
+
+var sharedShareScanContext *shareScanContext
+
+func getShareScanContext() *shareScanContext {
+	return sharedShareScanContext
+}
+
+func InitializeShareScanScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedShareScanContext = &shareScanContext{}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if s := getShareScanContext(); s != nil && s.localDir != "" {
+			os.RemoveAll(s.localDir)
+		}
+		sharedShareScanContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^a pre-share scanner that passes clean files$`, aPreShareScannerThatPassesCleanFiles)
+	ctx.Step(`^a pre-share scanner that reports "([^"]*)"$`, aPreShareScannerThatReports)
+	ctx.Step(`^a pre-share scanner that takes longer than its timeout$`, aPreShareScannerThatTakesLongerThanItsTimeout)
+	ctx.Step(`^a pre-share scanner that treats exit code (\d+) as clean$`, aPreShareScannerThatTreatsExitCodeAsClean)
+	ctx.Step(`^a local copy of "([^"]*)" is kept$`, aLocalCopyOfIsKept)
+	ctx.Step(`^I share the files for "([^"]*)" after scanning them$`, iShareTheFilesForAfterScanningThem)
+	ctx.Step(`^the uploaded file should not be shared publicly$`, theUploadedFileShouldNotBeSharedPublicly)
+}
+
+// useScanner routes the upload service's sharing through the scanner
+func useScanner(args []string, opts ...scanner.Option) error {
+	s, err := scanner.NewCommand(args, opts...)
+	if err != nil {
+		return err
+	}
+	getShareScanContext().scanner = s
+	u := getUploadContext()
+	// The scanner runs as its own process, so it needs files on disk
+	u.useFiles(filesystem.OSFS{})
+	u.service = distribution.NewUploadService(u.client, u.folderID, u.outputBuffer, distribution.WithScanner(s))
+	return nil
+}
+
+func aPreShareScannerThatPassesCleanFiles() error {
+	return useScanner([]string{"sh", "-c", `test -f "$0"`})
+}
+
+// aPreShareScannerThatReports fails every file with a clamscan-style verdict
+func aPreShareScannerThatReports(verdict string) error {
+	return useScanner([]string{"sh", "-c", `echo "$1: ` + verdict + `"; exit 1`, "scan", "{file}"})
+}
+
+func aPreShareScannerThatTakesLongerThanItsTimeout() error {
+	return useScanner([]string{"sh", "-c", "exec sleep 5"}, scanner.WithTimeout(100*time.Millisecond))
+}
+
+func aPreShareScannerThatTreatsExitCodeAsClean(code int) error {
+	return useScanner([]string{"sh", "-c", fmt.Sprintf("exit %d", code)}, scanner.WithExitCode(code))
+}
+
+func aLocalCopyOfIsKept(name string) error {
+	s := getShareScanContext()
+	if s.localDir == "" {
+		dir, err := os.MkdirTemp("", "share-scan-*")
+		if err != nil {
+			return err
+		}
+		s.localDir = dir
+	}
+	return os.WriteFile(filepath.Join(s.localDir, name), []byte("output"), 0644)
+}
+
+func iShareTheFilesForAfterScanningThem(date string) error {
+	u := getUploadContext()
+	s := getShareScanContext()
+	u.err = cmd.RunDriveShareWithDependencies(context.Background(), u.client, u.folderID, date, u.outputBuffer,
+		distribution.WithScanner(s.scanner), distribution.WithLocalCopies(s.localDir))
+	return nil
+}
+
+func theUploadedFileShouldNotBeSharedPublicly() error {
+	u := getUploadContext()
+	if u.uploadResult == nil {
+		return fmt.Errorf("no upload result")
+	}
+	if _, ok := u.mockService.permissions[u.uploadResult.FileID]; ok {
+		return fmt.Errorf("expected %s not to be shared", u.uploadResult.FileName)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	appdist "nac-service-media/application/distribution"
+	"nac-service-media/application/sources"
+	"nac-service-media/cmd"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/filesystem"
+
+	"github.com/cucumber/godog"
+)
+
+// sourcesContext holds test state for source listing scenarios
+type sourcesContext struct {
+	root       string
+	dirs       []string
+	trimmedDir string
+	audioDir   string
+	duration   time.Duration
+	inDrive    map[string]bool
+	output     *bytes.Buffer
+	err        error
+}
+
+var sharedSourcesContext *sourcesContext
+
+func getSourcesContext() *sourcesContext {
+	return sharedSourcesContext
+}
+
+func InitializeSourcesScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedSourcesContext = &sourcesContext{output: &bytes.Buffer{}, inDrive: make(map[string]bool)}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if s := getSourcesContext(); s != nil && s.root != "" {
+			os.RemoveAll(s.root)
+		}
+		sharedSourcesContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^recordings "([^"]*)" are waiting in the source directory$`, recordingsAreWaitingInTheSourceDirectory)
+	ctx.Step(`^recordings "([^"]*)" are waiting in a second source directory$`, recordingsAreWaitingInASecondSourceDirectory)
+	ctx.Step(`^a source directory that cannot be read$`, aSourceDirectoryThatCannotBeRead)
+	ctx.Step(`^each recording is (\d+) seconds long$`, eachRecordingIsSecondsLong)
+	ctx.Step(`^the trimmed output "([^"]*)" exists$`, theTrimmedOutputExists)
+	ctx.Step(`^Drive already has the video and audio for "([^"]*)"$`, driveAlreadyHasTheVideoAndAudioFor)
+	ctx.Step(`^I list all sources$`, iListAllSources)
+	ctx.Step(`^I list sources since "([^"]*)"$`, iListSourcesSince)
+	ctx.Step(`^I list sources from "([^"]*)" until "([^"]*)"$`, iListSourcesFromUntil)
+	ctx.Step(`^I list unprocessed sources since "([^"]*)"$`, iListUnprocessedSourcesSince)
+	ctx.Step(`^I list sources since "([^"]*)" checking Drive$`, iListSourcesSinceCheckingDrive)
+	ctx.Step(`^the sources listing should include "([^"]*)"$`, theSourcesListingShouldInclude)
+	ctx.Step(`^the sources listing should not include "([^"]*)"$`, theSourcesListingShouldNotInclude)
+	ctx.Step(`^the sources listing should show "([^"]*)" as "([^"]*)"$`, theSourcesListingShouldShowAs)
+	ctx.Step(`^"([^"]*)" should be listed before "([^"]*)"$`, shouldBeListedBefore)
+	ctx.Step(`^listing sources should fail with "([^"]*)"$`, listingSourcesShouldFailWith)
+}
+
+// fakeDriveCheck reports services as uploaded by date
+type fakeDriveCheck struct {
+	uploaded map[string]bool
+}
+
+func (f *fakeDriveCheck) Check(ctx context.Context, serviceDate string) (*appdist.ProcessedStatus, error) {
+	if !f.uploaded[serviceDate] {
+		return &appdist.ProcessedStatus{}, nil
+	}
+	return &appdist.ProcessedStatus{
+		Video: &distribution.FileInfo{Name: serviceDate + ".mp4"},
+		Audio: &distribution.FileInfo{Name: serviceDate + ".mp3"},
+	}, nil
+}
+
+func (s *sourcesContext) ensureRoot() error {
+	if s.root != "" {
+		return nil
+	}
+	root, err := os.MkdirTemp("", "sources-list-*")
+	if err != nil {
+		return err
+	}
+	s.root = root
+	s.trimmedDir = filepath.Join(root, "trimmed")
+	s.audioDir = filepath.Join(root, "audio")
+	for _, dir := range []string{s.trimmedDir, s.audioDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSourceDir creates the nth source directory holding the named recordings
+func (s *sourcesContext) addSourceDir(n int, names string) error {
+	if err := s.ensureRoot(); err != nil {
+		return err
+	}
+	for len(s.dirs) < n {
+		dir := filepath.Join(s.root, fmt.Sprintf("source-%d", len(s.dirs)+1))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		s.dirs = append(s.dirs, dir)
+	}
+	for _, name := range strings.Split(names, ",") {
+		path := filepath.Join(s.dirs[n-1], strings.TrimSpace(name))
+		if err := os.WriteFile(path, []byte("recording"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func recordingsAreWaitingInTheSourceDirectory(names string) error {
+	return getSourcesContext().addSourceDir(1, names)
+}
+
+func recordingsAreWaitingInASecondSourceDirectory(names string) error {
+	return getSourcesContext().addSourceDir(2, names)
+}
+
+func aSourceDirectoryThatCannotBeRead() error {
+	s := getSourcesContext()
+	if err := s.ensureRoot(); err != nil {
+		return err
+	}
+	s.dirs = append(s.dirs, filepath.Join(s.root, "unplugged"))
+	return nil
+}
+
+func eachRecordingIsSecondsLong(seconds int) error {
+	getSourcesContext().duration = time.Duration(seconds) * time.Second
+	return nil
+}
+
+// theTrimmedOutputExists puts an MP4 in the trimmed directory or an MP3 in the
+// audio directory
+func theTrimmedOutputExists(name string) error {
+	s := getSourcesContext()
+	if err := s.ensureRoot(); err != nil {
+		return err
+	}
+	dir := s.trimmedDir
+	if filepath.Ext(name) == ".mp3" {
+		dir = s.audioDir
+	}
+	return os.WriteFile(filepath.Join(dir, name), []byte("output"), 0644)
+}
+
+func driveAlreadyHasTheVideoAndAudioFor(date string) error {
+	getSourcesContext().inDrive[date] = true
+	return nil
+}
+
+func (s *sourcesContext) list(since, until string, unprocessedOnly, checkDrive bool) error {
+	opts := []sources.ListOption{
+		sources.WithOutputs(filesystem.NewChecker(), s.trimmedDir, s.audioDir),
+		sources.WithDurationProber(&mockDurationProber{duration: s.duration}),
+	}
+	if h := getHistoryContext(); h != nil && h.store != nil {
+		opts = append(opts, sources.WithHistory(h.store))
+	}
+	if checkDrive {
+		opts = append(opts, sources.WithDriveCheck(&fakeDriveCheck{uploaded: s.inDrive}))
+	}
+	calendar := video.ServiceCalendar{Recording: time.UTC, Service: time.UTC}
+	svc := sources.NewListService(&cmd.ProductionFileFinder{}, calendar, opts...)
+
+	s.output.Reset()
+	s.err = cmd.RunSourcesListWithDependencies(context.Background(), svc, s.dirs, since, until, unprocessedOnly, s.output)
+	return nil
+}
+
+func iListAllSources() error {
+	return getSourcesContext().list("", "", false, false)
+}
+
+func iListSourcesSince(since string) error {
+	return getSourcesContext().list(since, "", false, false)
+}
+
+func iListSourcesFromUntil(since, until string) error {
+	return getSourcesContext().list(since, until, false, false)
+}
+
+func iListUnprocessedSourcesSince(since string) error {
+	return getSourcesContext().list(since, "", true, false)
+}
+
+func iListSourcesSinceCheckingDrive(since string) error {
+	return getSourcesContext().list(since, "", false, true)
+}
+
+func (s *sourcesContext) listed() (string, error) {
+	if s.err != nil {
+		return "", fmt.Errorf("listing sources failed: %v", s.err)
+	}
+	return s.output.String(), nil
+}
+
+func theSourcesListingShouldInclude(text string) error {
+	out, err := getSourcesContext().listed()
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(out, text) {
+		return fmt.Errorf("expected listing to include %q, got:\n%s", text, out)
+	}
+	return nil
+}
+
+func theSourcesListingShouldNotInclude(text string) error {
+	out, err := getSourcesContext().listed()
+	if err != nil {
+		return err
+	}
+	if strings.Contains(out, text) {
+		return fmt.Errorf("expected listing not to include %q, got:\n%s", text, out)
+	}
+	return nil
+}
+
+// theSourcesListingShouldShowAs checks the PROCESSED column of a date's row
+func theSourcesListingShouldShowAs(date, processed string) error {
+	out, err := getSourcesContext().listed()
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, date) {
+			continue
+		}
+		if !strings.HasSuffix(strings.TrimSpace(line), "  "+processed) {
+			return fmt.Errorf("expected %s to show as %q, got %q", date, processed, line)
+		}
+		return nil
+	}
+	return fmt.Errorf("expected a row for %s, got:\n%s", date, out)
+}
+
+func shouldBeListedBefore(first, second string) error {
+	out, err := getSourcesContext().listed()
+	if err != nil {
+		return err
+	}
+	i, j := strings.Index(out, first), strings.Index(out, second)
+	if i < 0 || j < 0 || i > j {
+		return fmt.Errorf("expected %s before %s, got:\n%s", first, second, out)
+	}
+	return nil
+}
+
+func listingSourcesShouldFailWith(text string) error {
+	s := getSourcesContext()
+	if s.err == nil {
+		return fmt.Errorf("expected listing sources to fail, got:\n%s", s.output.String())
+	}
+	if !strings.Contains(s.err.Error(), text) {
+		return fmt.Errorf("expected error containing %q, got %v", text, s.err)
+	}
+	return nil
+}
@@ -6,12 +6,18 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	appvideo "nac-service-media/application/video"
 	"nac-service-media/cmd"
 	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/drive"
 
 	"github.com/cucumber/godog"
+	googledrive "google.golang.org/api/drive/v3"
 )
 
 // mockTrimmer records calls to Trim for verification
@@ -20,6 +26,7 @@ type mockTrimmer struct {
 	shouldFail  bool
 	failError   error
 	fileChecker *mockFileChecker // Reference to mark output files as existing
+	writeFiles  bool             // Write empty outputs so they can be uploaded
 }
 
 type trimCall struct {
@@ -47,9 +54,20 @@ func (m *mockTrimmer) Trim(ctx context.Context, req *video.TrimRequest, outputPa
 	if m.fileChecker != nil {
 		m.fileChecker.existingFiles[outputPath] = true
 	}
+	if m.writeFiles {
+		return writeEmptyFile(outputPath)
+	}
 	return nil
 }
 
+// writeEmptyFile creates path and its directory
+func writeEmptyFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, nil, 0644)
+}
+
 // mockFileChecker simulates file existence
 type mockFileChecker struct {
 	existingFiles map[string]bool
@@ -64,6 +82,7 @@ type mockTrimExtractor struct {
 	calls      []trimExtractCall
 	shouldFail bool
 	failError  error
+	writeFiles bool // Write empty outputs so they can be uploaded
 }
 
 type trimExtractCall struct {
@@ -87,9 +106,23 @@ func (m *mockTrimExtractor) Extract(ctx context.Context, req *video.AudioExtract
 			"-y", outputPath,
 		},
 	})
+	if m.writeFiles {
+		return writeEmptyFile(outputPath)
+	}
 	return nil
 }
 
+// trimUploadDriveService records the uploads chained after a trim
+type trimUploadDriveService struct {
+	mockDriveService
+	uploads []string // "name in folder"
+}
+
+func (m *trimUploadDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*googledrive.File, error) {
+	m.uploads = append(m.uploads, fileName+" in "+folderID)
+	return m.mockDriveService.UploadFile(ctx, fileName, mimeType, folderID, localPath, appProperties)
+}
+
 // trimContext holds test state for trim scenarios
 type trimContext struct {
 	sourcePath      string
@@ -101,10 +134,53 @@ type trimContext struct {
 	extractor       *mockTrimExtractor
 	audioOutputDir  string
 	audioBitrate    string
+	audioTrack      int
 	output          *bytes.Buffer
 	err             error
 	resultPath      string
 	audioResultPath string
+	corruptFiles    map[string]bool
+	promptAnswer    string
+	duration        time.Duration
+	watermark       video.WatermarkStyle
+	serviceDate     time.Time
+	drive           *trimUploadDriveService
+}
+
+// mockDurationProber reports a fixed source length
+type mockDurationProber struct {
+	duration time.Duration
+}
+
+func (m *mockDurationProber) Duration(ctx context.Context, path string) (time.Duration, error) {
+	return m.duration, nil
+}
+
+// trimOptions returns the options every trim scenario shares
+func (t *trimContext) trimOptions() []appvideo.Option {
+	var opts []appvideo.Option
+	if t.duration != 0 {
+		opts = append(opts, appvideo.WithDurationProber(&mockDurationProber{duration: t.duration}))
+	}
+	if t.watermark.Template != nil {
+		opts = append(opts, appvideo.WithWatermark(t.watermark))
+	}
+	if !t.serviceDate.IsZero() {
+		opts = append(opts, appvideo.WithServiceDate(t.serviceDate))
+	}
+	return opts
+}
+
+// mockMediaValidator rejects files marked as corrupt in the trim context
+type mockMediaValidator struct {
+	corrupt map[string]bool
+}
+
+func (m *mockMediaValidator) Validate(ctx context.Context, path string) error {
+	if m.corrupt[path] {
+		return fmt.Errorf("%s has no readable duration", path)
+	}
+	return nil
 }
 
 // SharedTrimContext is reset before each scenario via Before hook
@@ -120,10 +196,11 @@ func InitializeTrimScenario(ctx *godog.ScenarioContext) {
 			existingFiles: make(map[string]bool),
 		}
 		SharedTrimContext = &trimContext{
-			trimmer:     &mockTrimmer{fileChecker: fileChecker},
-			fileChecker: fileChecker,
-			extractor:   &mockTrimExtractor{},
-			output:      &bytes.Buffer{},
+			trimmer:      &mockTrimmer{fileChecker: fileChecker},
+			fileChecker:  fileChecker,
+			extractor:    &mockTrimExtractor{},
+			output:       &bytes.Buffer{},
+			corruptFiles: make(map[string]bool),
 		}
 		return c, nil
 	})
@@ -152,6 +229,27 @@ func InitializeTrimScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I trim the video from "([^"]*)" to "([^"]*)" with audio extraction$`, iTrimTheVideoFromToWithAudioExtraction)
 	ctx.Step(`^the trim audio output file should be "([^"]*)"$`, theTrimAudioOutputFileShouldBe)
 	ctx.Step(`^the trim audio extraction should have used arguments:$`, ffmpegShouldHaveBeenCalledWithAudioArgumentsTrim)
+	ctx.Step(`^the trim audio track is (\d+)$`, theTrimAudioTrackIs)
+	ctx.Step(`^the trimmed video should keep only audio track (\d+)$`, theTrimmedVideoShouldKeepOnlyAudioTrack)
+	ctx.Step(`^the trim audio extraction should read audio track (\d+)$`, theTrimAudioExtractionShouldReadAudioTrack)
+
+	// Steps for the --on-existing overwrite policy
+	ctx.Step(`^an existing output file at "([^"]*)"$`, anExistingOutputFileAt)
+	ctx.Step(`^the existing output file at "([^"]*)" is corrupt$`, theExistingOutputFileAtIsCorrupt)
+	ctx.Step(`^I will answer "(yes|no)" when asked to overwrite$`, iWillAnswerWhenAskedToOverwrite)
+	ctx.Step(`^I trim the video from "([^"]*)" to "([^"]*)" with on-existing "([^"]*)"$`, iTrimTheVideoFromToWithOnExisting)
+	ctx.Step(`^the video should not have been trimmed again$`, theVideoShouldNotHaveBeenTrimmedAgain)
+	ctx.Step(`^the trim output should contain "([^"]*)"$`, theTrimOutputShouldContain)
+
+	// Relative timestamp steps
+	ctx.Step(`^the source video is (\d+) minutes long$`, theSourceVideoIsMinutesLong)
+	ctx.Step(`^the trim should fail with "([^"]*)"$`, theTrimShouldFailWith)
+
+	// Date override and chained upload
+	ctx.Step(`^the trim service date is "([^"]*)"$`, theTrimServiceDateIs)
+	ctx.Step(`^trim outputs are written under a temporary directory$`, trimOutputsAreWrittenUnderATemporaryDirectory)
+	ctx.Step(`^I trim the video from "([^"]*)" to "([^"]*)" with audio extraction and upload to folder "([^"]*)"$`, iTrimTheVideoWithAudioExtractionAndUploadToFolder)
+	ctx.Step(`^the trim should have uploaded:$`, theTrimShouldHaveUploaded)
 }
 
 func theTrimmedOutputDirectoryIs(dir string) error {
@@ -191,6 +289,7 @@ func iTrimTheVideoFromTo(start, end string) error {
 		"",  // no audio output dir
 		"",  // no audio bitrate
 		t.output,
+		t.trimOptions()...,
 	)
 
 	if t.err != nil {
@@ -242,6 +341,7 @@ func iAttemptToTrimFromTo(start, end string) error {
 		"",  // no audio output dir
 		"",  // no audio bitrate
 		t.output,
+		t.trimOptions()...,
 	)
 	return nil
 }
@@ -356,6 +456,7 @@ func iTrimTheVideoFromToWithAudioExtraction(start, end string) error {
 		t.audioOutputDir,
 		t.audioBitrate,
 		t.output,
+		appvideo.WithAudioTrack(t.audioTrack),
 	)
 
 	if t.err != nil {
@@ -374,6 +475,35 @@ func iTrimTheVideoFromToWithAudioExtraction(start, end string) error {
 	return nil
 }
 
+func theTrimAudioTrackIs(track int) error {
+	getTrimContext().audioTrack = track
+	return nil
+}
+
+func theTrimmedVideoShouldKeepOnlyAudioTrack(track int) error {
+	t := getTrimContext()
+	if len(t.trimmer.calls) == 0 {
+		return fmt.Errorf("trim was not called")
+	}
+	if got := t.trimmer.calls[0].req.AudioTrack; got != track {
+		return fmt.Errorf("expected trimmed video to keep audio track %d, got %d", track, got)
+	}
+	return nil
+}
+
+// theTrimAudioExtractionShouldReadAudioTrack checks the track read from the
+// trimmed video; 0 is its default stream
+func theTrimAudioExtractionShouldReadAudioTrack(track int) error {
+	t := getTrimContext()
+	if len(t.extractor.calls) == 0 {
+		return fmt.Errorf("ffmpeg audio extraction was not called")
+	}
+	if got := t.extractor.calls[0].req.AudioTrack; got != track {
+		return fmt.Errorf("expected audio extraction from track %d, got %d", track, got)
+	}
+	return nil
+}
+
 func theTrimAudioOutputFileShouldBe(expected string) error {
 	t := getTrimContext()
 	if t.audioResultPath != expected {
@@ -408,3 +538,165 @@ func ffmpegShouldHaveBeenCalledWithAudioArgumentsTrim(table *godog.Table) error
 	}
 	return nil
 }
+
+// Steps for the --on-existing overwrite policy
+
+func anExistingOutputFileAt(path string) error {
+	t := getTrimContext()
+	t.fileChecker.existingFiles[path] = true
+	return nil
+}
+
+func theExistingOutputFileAtIsCorrupt(path string) error {
+	t := getTrimContext()
+	t.corruptFiles[path] = true
+	return nil
+}
+
+func iWillAnswerWhenAskedToOverwrite(answer string) error {
+	t := getTrimContext()
+	// The prompt offers "Overwrite" or "Reuse existing file"
+	t.promptAnswer = "Reuse existing file"
+	if answer == "yes" {
+		t.promptAnswer = "Overwrite"
+	}
+	return nil
+}
+
+func iTrimTheVideoFromToWithOnExisting(start, end, policy string) error {
+	t := getTrimContext()
+	t.startTime = start
+	t.endTime = end
+
+	p, err := video.ParseOverwritePolicy(policy)
+	if err != nil {
+		return err
+	}
+	overwrite := appvideo.OverwriteOptions{
+		Policy:    p,
+		Validator: &mockMediaValidator{corrupt: t.corruptFiles},
+		Confirm:   cmd.ConfirmOverwrite(NewMockPrompter(nil, nil).WithSelections(t.promptAnswer)),
+	}
+
+	t.err = cmd.RunTrimWithDependencies(
+		context.Background(),
+		t.trimmer,
+		t.fileChecker,
+		t.outputDir,
+		t.sourcePath,
+		t.startTime,
+		t.endTime,
+		nil, // no audio extractor
+		"",  // no audio output dir
+		"",  // no audio bitrate
+		t.output,
+		appvideo.WithOverwrite(overwrite),
+	)
+
+	if t.err != nil {
+		return fmt.Errorf("unexpected error: %v", t.err)
+	}
+
+	if len(t.trimmer.calls) > 0 {
+		t.resultPath = t.trimmer.calls[0].outputPath
+	}
+	return nil
+}
+
+func theVideoShouldNotHaveBeenTrimmedAgain() error {
+	t := getTrimContext()
+	if len(t.trimmer.calls) != 0 {
+		return fmt.Errorf("expected no trim, but ffmpeg was called for %s", t.trimmer.calls[0].outputPath)
+	}
+	return nil
+}
+
+func theTrimOutputShouldContain(expected string) error {
+	t := getTrimContext()
+	if !strings.Contains(t.output.String(), expected) {
+		return fmt.Errorf("expected output to contain %q, got:\n%s", expected, t.output.String())
+	}
+	return nil
+}
+
+func theSourceVideoIsMinutesLong(minutes int) error {
+	getTrimContext().duration = time.Duration(minutes) * time.Minute
+	return nil
+}
+
+func theTrimShouldFailWith(expected string) error {
+	t := getTrimContext()
+	if t.err == nil {
+		return fmt.Errorf("expected trim to fail with %q, but it succeeded", expected)
+	}
+	if !strings.Contains(t.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, t.err.Error())
+	}
+	return nil
+}
+
+func theTrimServiceDateIs(date string) error {
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	getTrimContext().serviceDate = d
+	return nil
+}
+
+func trimOutputsAreWrittenUnderATemporaryDirectory() error {
+	t := getTrimContext()
+	dir, err := os.MkdirTemp("", "trim-upload-*")
+	if err != nil {
+		return err
+	}
+	t.outputDir = filepath.Join(dir, "trimmed")
+	t.audioOutputDir = filepath.Join(dir, "audio")
+	t.trimmer.writeFiles = true
+	t.extractor.writeFiles = true
+	return nil
+}
+
+func iTrimTheVideoWithAudioExtractionAndUploadToFolder(start, end, folderID string) error {
+	t := getTrimContext()
+	t.drive = &trimUploadDriveService{}
+	client, err := drive.NewClient(context.Background(), "", drive.WithDriveService(t.drive))
+	if err != nil {
+		return err
+	}
+	chain := cmd.TrimChain{
+		Extractor:      t.extractor,
+		AudioOutputDir: t.audioOutputDir,
+		AudioBitrate:   "192k",
+		Uploader:       client,
+		FolderID:       folderID,
+	}
+	t.err = cmd.RunTrimChainWithDependencies(
+		context.Background(),
+		t.trimmer,
+		t.fileChecker,
+		t.outputDir,
+		t.sourcePath,
+		start,
+		end,
+		chain,
+		t.output,
+		t.trimOptions()...,
+	)
+	if t.err != nil {
+		return fmt.Errorf("unexpected error: %v", t.err)
+	}
+	return nil
+}
+
+func theTrimShouldHaveUploaded(table *godog.Table) error {
+	t := getTrimContext()
+	var want []string
+	for _, row := range table.Rows[1:] {
+		want = append(want, row.Cells[0].Value+" in "+row.Cells[1].Value)
+	}
+	if got := strings.Join(t.drive.uploads, ", "); got != strings.Join(want, ", ") {
+		return fmt.Errorf("expected uploads %q, got %q", strings.Join(want, ", "), got)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/release"
+	"nac-service-media/infrastructure/config"
+	"nac-service-media/infrastructure/filesystem"
+
+	"github.com/cucumber/godog"
+)
+
+const (
+	updateTestGOOS       = "linux"
+	updateTestGOARCH     = "amd64"
+	updateOriginalBinary = "original binary"
+)
+
+// mockReleaseSource implements release.Source with in-memory releases
+type mockReleaseSource struct {
+	releases []release.Release
+	assets   map[string][]byte // keyed by URL
+}
+
+func (m *mockReleaseSource) Releases(ctx context.Context) ([]release.Release, error) {
+	return m.releases, nil
+}
+
+func (m *mockReleaseSource) Download(ctx context.Context, url string) ([]byte, error) {
+	data, ok := m.assets[url]
+	if !ok {
+		return nil, fmt.Errorf("asset not found: %s", url)
+	}
+	return data, nil
+}
+
+// updateContext holds test state for self-update scenarios
+type updateContext struct {
+	tempDir        string
+	execPath       string
+	currentVersion string
+	cfg            *config.Config
+	source         *mockReleaseSource
+	privateKey     ed25519.PrivateKey
+	output         *bytes.Buffer
+	err            error
+}
+
+// SharedUpdateContext is reset before each scenario via Before hook
+var SharedUpdateContext *updateContext
+
+func getUpdateContext() *updateContext {
+	return SharedUpdateContext
+}
+
+func InitializeUpdateScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		tempDir, err := os.MkdirTemp("", "update-test-*")
+		if err != nil {
+			return c, err
+		}
+		execPath := filepath.Join(tempDir, "nac-service-media")
+		if err := os.WriteFile(execPath, []byte(updateOriginalBinary), 0755); err != nil {
+			return c, err
+		}
+		SharedUpdateContext = &updateContext{
+			tempDir:  tempDir,
+			execPath: execPath,
+			cfg:      &config.Config{},
+			source:   &mockReleaseSource{assets: make(map[string][]byte)},
+			output:   &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if SharedUpdateContext != nil && SharedUpdateContext.tempDir != "" {
+			os.RemoveAll(SharedUpdateContext.tempDir)
+		}
+		SharedUpdateContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the running version is "([^"]*)"$`, theRunningVersionIs)
+	ctx.Step(`^the following releases are published:$`, theFollowingReleasesArePublished)
+	ctx.Step(`^release "([^"]*)" has a corrupted checksum$`, releaseHasACorruptedChecksum)
+	ctx.Step(`^release signing is configured$`, releaseSigningIsConfigured)
+	ctx.Step(`^release "([^"]*)" has an invalid signature$`, releaseHasAnInvalidSignature)
+	ctx.Step(`^self-update is disabled in config$`, selfUpdateIsDisabledInConfig)
+	ctx.Step(`^I run self-update$`, iRunSelfUpdate)
+	ctx.Step(`^I run self-update with "--check"$`, iRunSelfUpdateCheckOnly)
+	ctx.Step(`^I run self-update with "--check --force"$`, iRunSelfUpdateCheckForce)
+	ctx.Step(`^I run self-update on the "([^"]*)" channel$`, iRunSelfUpdateOnChannel)
+	ctx.Step(`^self-update should succeed$`, selfUpdateShouldSucceed)
+	ctx.Step(`^self-update should fail with error "([^"]*)"$`, selfUpdateShouldFailWithError)
+	ctx.Step(`^the self-update output should include "([^"]*)"$`, theSelfUpdateOutputShouldInclude)
+	ctx.Step(`^the self-update output should not include "([^"]*)"$`, theSelfUpdateOutputShouldNotInclude)
+	ctx.Step(`^the installed binary should contain "([^"]*)"$`, theInstalledBinaryShouldContain)
+	ctx.Step(`^the installed binary should be unchanged$`, theInstalledBinaryShouldBeUnchanged)
+}
+
+func theRunningVersionIs(v string) error {
+	getUpdateContext().currentVersion = v
+	return nil
+}
+
+func theFollowingReleasesArePublished(table *godog.Table) error {
+	u := getUpdateContext()
+	assetName := release.BinaryAssetName(updateTestGOOS, updateTestGOARCH)
+
+	for i, row := range table.Rows {
+		if i == 0 {
+			continue // Skip header row
+		}
+		version := row.Cells[0].Value
+		binary := []byte("binary " + version)
+		sum := sha256.Sum256(binary)
+		checksums := fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), assetName)
+
+		binaryURL := fmt.Sprintf("mem://%s/%s", version, assetName)
+		checksumsURL := fmt.Sprintf("mem://%s/%s", version, release.ChecksumsAsset)
+		u.source.assets[binaryURL] = binary
+		u.source.assets[checksumsURL] = []byte(checksums)
+
+		u.source.releases = append(u.source.releases, release.Release{
+			Version:    version,
+			Prerelease: row.Cells[1].Value == "true",
+			Assets: []release.Asset{
+				{Name: assetName, URL: binaryURL},
+				{Name: release.ChecksumsAsset, URL: checksumsURL},
+			},
+		})
+	}
+	return nil
+}
+
+func releaseHasACorruptedChecksum(version string) error {
+	u := getUpdateContext()
+	assetName := release.BinaryAssetName(updateTestGOOS, updateTestGOARCH)
+	url := fmt.Sprintf("mem://%s/%s", version, release.ChecksumsAsset)
+	u.source.assets[url] = []byte(fmt.Sprintf("%s  %s\n", strings.Repeat("0", 64), assetName))
+	return nil
+}
+
+func releaseSigningIsConfigured() error {
+	u := getUpdateContext()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return err
+	}
+	u.privateKey = priv
+	u.cfg.Update.PublicKey = base64.StdEncoding.EncodeToString(pub)
+
+	// Sign every published release's checksums
+	for i := range u.source.releases {
+		r := &u.source.releases[i]
+		checksums := u.source.assets[r.FindAsset(release.ChecksumsAsset).URL]
+		sigURL := fmt.Sprintf("mem://%s/%s", r.Version, release.SignatureAsset)
+		u.source.assets[sigURL] = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, checksums)))
+		r.Assets = append(r.Assets, release.Asset{Name: release.SignatureAsset, URL: sigURL})
+	}
+	return nil
+}
+
+func releaseHasAnInvalidSignature(version string) error {
+	u := getUpdateContext()
+	sigURL := fmt.Sprintf("mem://%s/%s", version, release.SignatureAsset)
+	u.source.assets[sigURL] = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(u.privateKey, []byte("something else"))))
+	return nil
+}
+
+func selfUpdateIsDisabledInConfig() error {
+	getUpdateContext().cfg.Update.Disabled = true
+	return nil
+}
+
+func runSelfUpdate(channel string, checkOnly, force bool) error {
+	u := getUpdateContext()
+	input := cmd.SelfUpdateInput{
+		CurrentVersion: u.currentVersion,
+		Channel:        channel,
+		CheckOnly:      checkOnly,
+		Force:          force,
+		GOOS:           updateTestGOOS,
+		GOARCH:         updateTestGOARCH,
+		ExecPath:       u.execPath,
+	}
+	u.err = cmd.RunSelfUpdateWithDependencies(context.Background(), u.cfg, u.source, filesystem.NewBinaryInstaller(), input, u.output)
+	return nil
+}
+
+func iRunSelfUpdate() error {
+	return runSelfUpdate("", false, false)
+}
+
+func iRunSelfUpdateCheckOnly() error {
+	return runSelfUpdate("", true, false)
+}
+
+func iRunSelfUpdateCheckForce() error {
+	return runSelfUpdate("", true, true)
+}
+
+func iRunSelfUpdateOnChannel(channel string) error {
+	return runSelfUpdate(channel, false, false)
+}
+
+func selfUpdateShouldSucceed() error {
+	u := getUpdateContext()
+	if u.err != nil {
+		return fmt.Errorf("expected success, got error: %v\nOutput:\n%s", u.err, u.output.String())
+	}
+	return nil
+}
+
+func selfUpdateShouldFailWithError(expected string) error {
+	u := getUpdateContext()
+	if u.err == nil {
+		return fmt.Errorf("expected error containing %q, but self-update succeeded", expected)
+	}
+	if !strings.Contains(u.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got: %v", expected, u.err)
+	}
+	return nil
+}
+
+func theSelfUpdateOutputShouldInclude(expected string) error {
+	u := getUpdateContext()
+	if !strings.Contains(u.output.String(), expected) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", expected, u.output.String())
+	}
+	return nil
+}
+
+func theSelfUpdateOutputShouldNotInclude(unexpected string) error {
+	u := getUpdateContext()
+	if strings.Contains(u.output.String(), unexpected) {
+		return fmt.Errorf("expected output not to include %q, got:\n%s", unexpected, u.output.String())
+	}
+	return nil
+}
+
+func theInstalledBinaryShouldContain(expected string) error {
+	u := getUpdateContext()
+	data, err := os.ReadFile(u.execPath)
+	if err != nil {
+		return fmt.Errorf("failed to read installed binary: %v", err)
+	}
+	if string(data) != expected {
+		return fmt.Errorf("expected installed binary %q, got %q", expected, string(data))
+	}
+	info, err := os.Stat(u.execPath)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("installed binary is not executable: %v", info.Mode())
+	}
+	return nil
+}
+
+func theInstalledBinaryShouldBeUnchanged() error {
+	return theInstalledBinaryShouldContain(updateOriginalBinary)
+}
@@ -5,13 +5,22 @@ package steps
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"os"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"strings"
 
 	appdist "nac-service-media/application/distribution"
+	"nac-service-media/cmd"
 	"nac-service-media/domain/distribution"
+	domainfs "nac-service-media/domain/filesystem"
 	"nac-service-media/infrastructure/drive"
+	"nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/webdav"
 
 	googledrive "google.golang.org/api/drive/v3"
 
@@ -26,12 +35,15 @@ type uploadMockDriveService struct {
 	shouldFail       bool
 	failError        error
 	permissionFail   bool
+	permissionFails  int // Fail this many CreatePermission calls, then succeed
 	storageLimit     int64
 	storageUsage     int64
 	deletedFileIDs   []string
 	trashEmptied     bool
 	permissionError  bool
 	nextFileID       int
+	localFiles       domainfs.FS // Where uploads are read from
+	progressChunk    int64       // Bytes Drive confirms at a time; 0 reports no progress
 }
 
 func newUploadMockDriveService() *uploadMockDriveService {
@@ -48,6 +60,16 @@ func (m *uploadMockDriveService) ListFiles(ctx context.Context, query string, fi
 	if m.permissionError {
 		return nil, fmt.Errorf("googleapi: Error 403: The user does not have permission")
 	}
+	// Filter files by appProperty (for FindFilesByProperty support)
+	if matches := appPropertyQueryRegex.FindStringSubmatch(query); matches != nil {
+		result := []*googledrive.File{}
+		for _, f := range m.files {
+			if f.AppProperties[matches[1]] == matches[2] {
+				result = append(result, f)
+			}
+		}
+		return result, nil
+	}
 	// Filter files by name if query contains "name = " (for FindFileByName support)
 	if strings.Contains(query, "name = ") {
 		// Extract the filename from the query
@@ -95,13 +117,13 @@ func (m *uploadMockDriveService) EmptyTrash(ctx context.Context) error {
 	return nil
 }
 
-func (m *uploadMockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string) (*googledrive.File, error) {
+func (m *uploadMockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*googledrive.File, error) {
 	if m.shouldFail {
 		return nil, m.failError
 	}
 
 	// Check if file exists (for realistic testing)
-	info, err := os.Stat(localPath)
+	info, err := m.localFiles.Stat(localPath)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open file: %w", err)
 	}
@@ -121,10 +143,27 @@ func (m *uploadMockDriveService) UploadFile(ctx context.Context, fileName, mimeT
 	return file, nil
 }
 
+func (m *uploadMockDriveService) UploadFileWithProgress(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string, progress func(sent int64)) (*googledrive.File, error) {
+	file, err := m.UploadFile(ctx, fileName, mimeType, folderID, localPath, appProperties)
+	if err != nil || m.progressChunk == 0 {
+		return file, err
+	}
+	for sent := m.progressChunk; ; sent += m.progressChunk {
+		progress(min(sent, file.Size))
+		if sent >= file.Size {
+			return file, nil
+		}
+	}
+}
+
 func (m *uploadMockDriveService) CreatePermission(ctx context.Context, fileID string, permission *googledrive.Permission) error {
 	if m.permissionFail {
 		return fmt.Errorf("permission API error: unable to set sharing permission")
 	}
+	if m.permissionFails > 0 {
+		m.permissionFails--
+		return fmt.Errorf("permission API error: backend error")
+	}
 	if m.shouldFail {
 		return m.failError
 	}
@@ -145,6 +184,9 @@ type uploadContext struct {
 	uploadedFileID     string
 	service            *appdist.UploadService
 	outputBuffer       *bytes.Buffer
+	mirror             *httptest.Server
+	mirrorFiles        map[string]string
+	files              domainfs.FS // Local files; in memory unless a scanner needs them on disk
 }
 
 // SharedUploadContext is reset before each scenario via Before hook
@@ -159,17 +201,21 @@ func InitializeUploadScenario(ctx *godog.ScenarioContext) {
 		SharedUploadContext = &uploadContext{
 			mockService: newUploadMockDriveService(),
 		}
+		SharedUploadContext.useFiles(filesystem.NewMemFS())
 		return c, nil
 	})
 
 	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
 		// Clean up test files if created
 		if SharedUploadContext != nil {
+			if SharedUploadContext.mirror != nil {
+				SharedUploadContext.mirror.Close()
+			}
 			if SharedUploadContext.videoPath != "" {
-				os.Remove(SharedUploadContext.videoPath)
+				SharedUploadContext.files.Remove(SharedUploadContext.videoPath)
 			}
 			if SharedUploadContext.audioPath != "" {
-				os.Remove(SharedUploadContext.audioPath)
+				SharedUploadContext.files.Remove(SharedUploadContext.audioPath)
 			}
 		}
 		SharedUploadContext = nil
@@ -180,6 +226,9 @@ func InitializeUploadScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the Services folder ID is "([^"]*)"$`, uploadTheServicesFolderIDIs)
 	ctx.Step(`^valid Google Drive upload credentials$`, validGoogleDriveUploadCredentials)
 	ctx.Step(`^I have a video file at "([^"]*)"$`, iHaveAVideoFileAt)
+	ctx.Step(`^a WebDAV mirror server$`, aWebDAVMirrorServer)
+	ctx.Step(`^I publish the audio to the mirror$`, iPublishTheAudioToTheMirror)
+	ctx.Step(`^the mirror should have "([^"]*)" with a matching checksum$`, theMirrorShouldHaveWithAMatchingChecksum)
 	ctx.Step(`^I have an audio file at "([^"]*)"$`, iHaveAnAudioFileAt)
 	ctx.Step(`^I upload the video to the Services folder$`, iUploadTheVideoToTheServicesFolder)
 	ctx.Step(`^I upload the audio to the Services folder$`, iUploadTheAudioToTheServicesFolder)
@@ -195,7 +244,17 @@ func InitializeUploadScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I should receive shareable URLs for both files$`, iShouldReceiveShareableURLsForBothFiles)
 	ctx.Step(`^I attempt to upload the video$`, iAttemptToUploadTheVideo)
 	ctx.Step(`^I should receive an error about missing file$`, iShouldReceiveAnErrorAboutMissingFile)
+	ctx.Step(`^Drive confirms the upload (\d+) bytes at a time$`, driveConfirmsTheUploadBytesAtATime)
 	ctx.Step(`^the permission API will fail$`, thePermissionAPIWillFail)
+	ctx.Step(`^the permission API will fail (\d+) times?$`, thePermissionAPIWillFailTimes)
+	ctx.Step(`^the uploaded file should be shared publicly$`, theUploadedFileShouldBeSharedPublicly)
+	ctx.Step(`^the upload should be marked as sharing pending$`, theUploadShouldBeMarkedAsSharingPending)
+	ctx.Step(`^I share the files for "([^"]*)"$`, iShareTheFilesFor)
+	ctx.Step(`^I share the files for "([^"]*)" with the "([^"]*)" processed check$`, iShareTheFilesForWithTheProcessedCheck)
+	ctx.Step(`^the share should succeed$`, theShareShouldSucceed)
+	ctx.Step(`^the share should fail with error "([^"]*)"$`, theShareShouldFailWithError)
+	ctx.Step(`^the file "([^"]*)" should be shared publicly$`, theFileShouldBeSharedPublicly)
+	ctx.Step(`^the file "([^"]*)" should not be shared$`, theFileShouldNotBeShared)
 	ctx.Step(`^I attempt to set public sharing permission$`, iAttemptToSetPublicSharingPermission)
 	ctx.Step(`^I should receive an error about permission failure$`, iShouldReceiveAnErrorAboutPermissionFailure)
 
@@ -207,6 +266,12 @@ func InitializeUploadScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the upload output should not contain "([^"]*)"$`, uploadTheOutputShouldNotContain)
 }
 
+// useFiles sets where test files are written and uploads read them from
+func (u *uploadContext) useFiles(fsys domainfs.FS) {
+	u.files = fsys
+	u.mockService.localFiles = fsys
+}
+
 func uploadTheServicesFolderIDIs(folderID string) error {
 	u := getUploadContext()
 	u.folderID = folderID
@@ -227,7 +292,7 @@ func validGoogleDriveUploadCredentials() error {
 	}
 	u.client = client
 	u.outputBuffer = &bytes.Buffer{}
-	u.service = appdist.NewUploadService(client, u.folderID, u.outputBuffer)
+	u.service = appdist.NewUploadService(client, u.folderID, u.outputBuffer, appdist.WithFS(u.files))
 	return nil
 }
 
@@ -235,12 +300,15 @@ func iHaveAVideoFileAt(path string) error {
 	u := getUploadContext()
 	// Create a test file for the mock to find
 	if !strings.Contains(path, "nonexistent") {
-		f, err := os.Create(path)
+		if err := u.files.MkdirAll(filepath.Dir(path)); err != nil {
+			return err
+		}
+		f, err := u.files.Create(path)
 		if err != nil {
 			return fmt.Errorf("failed to create test file: %v", err)
 		}
 		// Write some content to make it non-empty
-		f.WriteString("test video content")
+		io.WriteString(f, "test video content")
 		f.Close()
 	}
 	u.videoPath = path
@@ -251,12 +319,15 @@ func iHaveAnAudioFileAt(path string) error {
 	u := getUploadContext()
 	// Create a test file for the mock to find
 	if !strings.Contains(path, "nonexistent") {
-		f, err := os.Create(path)
+		if err := u.files.MkdirAll(filepath.Dir(path)); err != nil {
+			return err
+		}
+		f, err := u.files.Create(path)
 		if err != nil {
 			return fmt.Errorf("failed to create test file: %v", err)
 		}
 		// Write some content to make it non-empty
-		f.WriteString("test audio content")
+		io.WriteString(f, "test audio content")
 		f.Close()
 	}
 	u.audioPath = path
@@ -416,12 +487,106 @@ func iShouldReceiveAnErrorAboutMissingFile() error {
 	return nil
 }
 
+func driveConfirmsTheUploadBytesAtATime(n int) error {
+	getUploadContext().mockService.progressChunk = int64(n)
+	return nil
+}
+
 func thePermissionAPIWillFail() error {
 	u := getUploadContext()
 	u.mockService.permissionFail = true
 	return nil
 }
 
+func thePermissionAPIWillFailTimes(count int) error {
+	u := getUploadContext()
+	u.mockService.permissionFails = count
+	return nil
+}
+
+func theUploadedFileShouldBeSharedPublicly() error {
+	u := getUploadContext()
+	if u.uploadResult == nil {
+		return fmt.Errorf("no upload result")
+	}
+	if u.uploadResult.SharingPending {
+		return fmt.Errorf("expected file to be shared, but sharing is pending")
+	}
+	if _, ok := u.mockService.permissions[u.uploadResult.FileID]; !ok {
+		return fmt.Errorf("permission not set for uploaded file %s", u.uploadResult.FileID)
+	}
+	return nil
+}
+
+func theUploadShouldBeMarkedAsSharingPending() error {
+	u := getUploadContext()
+	if u.uploadResult == nil {
+		return fmt.Errorf("no upload result")
+	}
+	if !u.uploadResult.SharingPending {
+		return fmt.Errorf("expected sharing to be pending")
+	}
+	return nil
+}
+
+func iShareTheFilesFor(date string) error {
+	u := getUploadContext()
+	u.err = cmd.RunDriveShareWithDependencies(context.Background(), u.client, u.folderID, date, u.outputBuffer)
+	return nil
+}
+
+func iShareTheFilesForWithTheProcessedCheck(date, strategy string) error {
+	u := getUploadContext()
+	u.err = cmd.RunDriveShareWithDependencies(context.Background(), u.client, u.folderID, date, u.outputBuffer,
+		appdist.WithProcessedCheck(strategy))
+	return nil
+}
+
+func theShareShouldSucceed() error {
+	u := getUploadContext()
+	if u.err != nil {
+		return fmt.Errorf("expected share to succeed, but got error: %v", u.err)
+	}
+	return nil
+}
+
+func theShareShouldFailWithError(expected string) error {
+	u := getUploadContext()
+	if u.err == nil {
+		return fmt.Errorf("expected error containing %q, but share succeeded", expected)
+	}
+	if !strings.Contains(u.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got: %v", expected, u.err)
+	}
+	return nil
+}
+
+func theFileShouldBeSharedPublicly(name string) error {
+	u := getUploadContext()
+	for _, f := range u.mockService.files {
+		if f.Name == name {
+			if _, ok := u.mockService.permissions[f.Id]; !ok {
+				return fmt.Errorf("permission not set on %s", name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("file %s not found in Drive folder", name)
+}
+
+func theFileShouldNotBeShared(name string) error {
+	u := getUploadContext()
+	for _, f := range u.mockService.files {
+		if f.Name == name {
+			if _, ok := u.mockService.permissions[f.Id]; ok {
+				return fmt.Errorf("expected %s not to be shared", name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("file %s not found in Drive folder", name)
+}
+
 func iAttemptToSetPublicSharingPermission() error {
 	u := getUploadContext()
 	u.err = u.client.SetPublicSharing(context.Background(), u.uploadedFileID)
@@ -450,18 +615,22 @@ func uploadTheDriveFolderAlreadyContains(table *godog.Table) error {
 			// Skip header row
 			continue
 		}
-		// Columns: name, mimeType, size
+		// Columns: name, mimeType, size and an optional service_date tag
 		name := row.Cells[0].Value
 		mimeType := row.Cells[1].Value
 		size := int64(0)
 		fmt.Sscanf(row.Cells[2].Value, "%d", &size)
 
-		u.mockService.files = append(u.mockService.files, &googledrive.File{
+		file := &googledrive.File{
 			Id:       fmt.Sprintf("existing-file-%d", i),
 			Name:     name,
 			MimeType: mimeType,
 			Size:     size,
-		})
+		}
+		if len(row.Cells) > 3 && row.Cells[3].Value != "" {
+			file.AppProperties = map[string]string{distribution.PropertyServiceDate: row.Cells[3].Value}
+		}
+		u.mockService.files = append(u.mockService.files, file)
 	}
 	return nil
 }
@@ -507,3 +676,58 @@ func uploadTheOutputShouldNotContain(unexpected string) error {
 	}
 	return nil
 }
+
+func aWebDAVMirrorServer() error {
+	u := getUploadContext()
+	u.mirrorFiles = make(map[string]string)
+	u.mirror = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		u.mirrorFiles[strings.TrimPrefix(r.URL.Path, "/services/")] = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	return nil
+}
+
+func iPublishTheAudioToTheMirror() error {
+	u := getUploadContext()
+	if u.outputBuffer == nil {
+		u.outputBuffer = &bytes.Buffer{}
+	}
+	publisher := webdav.NewClient(u.mirror.URL + "/services")
+	u.err = cmd.RunPublishWithDependencies(context.Background(), publisher, []string{u.audioPath}, u.outputBuffer, appdist.WithPublishFS(u.files))
+	if u.err != nil {
+		return fmt.Errorf("publish failed: %v", u.err)
+	}
+	return nil
+}
+
+func theMirrorShouldHaveWithAMatchingChecksum(name string) error {
+	u := getUploadContext()
+	content, ok := u.mirrorFiles[name]
+	if !ok {
+		return fmt.Errorf("%s was not published to the mirror", name)
+	}
+	f, err := u.files.Open(u.audioPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	local, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	if content != string(local) {
+		return fmt.Errorf("published content does not match %s", filepath.Base(u.audioPath))
+	}
+
+	sum := sha256.Sum256(local)
+	want := hex.EncodeToString(sum[:]) + "  " + name + "\n"
+	if got := u.mirrorFiles[name+distribution.ChecksumSuffix]; got != want {
+		return fmt.Errorf("checksum file = %q, want %q", got, want)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"nac-service-media/cmd"
+	"nac-service-media/infrastructure/drive"
+
+	googledrive "google.golang.org/api/drive/v3"
+
+	"github.com/cucumber/godog"
+)
+
+// usageContext holds test state for drive usage scenarios
+type usageContext struct {
+	mockService *cleanupMockDriveService
+	output      *bytes.Buffer
+	err         error
+	scopeMode   string
+}
+
+// SharedUsageContext is reset before each scenario
+var SharedUsageContext *usageContext
+
+func getUsageContext() *usageContext {
+	return SharedUsageContext
+}
+
+func InitializeUsageScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		SharedUsageContext = &usageContext{
+			mockService: &cleanupMockDriveService{
+				storageLimit: 100 * 1024 * 1024 * 1024, // 100 GB
+			},
+			output: &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		SharedUsageContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the Services folder holds files:$`, theServicesFolderHoldsFiles)
+	ctx.Step(`^I check Drive usage$`, iCheckDriveUsage)
+	ctx.Step(`^I check Drive usage listing the top (\d+) files?$`, iCheckDriveUsageListingTop)
+	ctx.Step(`^I check Drive usage to reclaim "([^"]*)"$`, iCheckDriveUsageToReclaim)
+	ctx.Step(`^the usage report should include "([^"]*)"$`, theUsageReportShouldInclude)
+	ctx.Step(`^the usage report should not include "([^"]*)"$`, theUsageReportShouldNotInclude)
+	ctx.Step(`^the usage check should fail with "([^"]*)"$`, theUsageCheckShouldFailWith)
+}
+
+func theServicesFolderHoldsFiles(table *godog.Table) error {
+	u := getUsageContext()
+	header := table.Rows[0].Cells
+	var used int64
+	for i, row := range table.Rows[1:] {
+		f := &googledrive.File{Id: fmt.Sprintf("file-%d", i+1)}
+		for j, cell := range row.Cells {
+			switch header[j].Value {
+			case "name":
+				f.Name = cell.Value
+			case "size_mb":
+				mb, err := strconv.ParseInt(cell.Value, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid size %q: %w", cell.Value, err)
+				}
+				f.Size = mb * 1024 * 1024
+			case "service_date":
+				if cell.Value != "" {
+					f.AppProperties = map[string]string{"service_date": cell.Value}
+				}
+			}
+		}
+		used += f.Size
+		u.mockService.files = append(u.mockService.files, f)
+	}
+	u.mockService.storageUsage = used
+	return nil
+}
+
+func runDriveUsage(top int, reclaim string) error {
+	u := getUsageContext()
+	client, err := drive.NewClient(context.Background(), "", drive.WithDriveService(u.mockService), drive.WithScopeMode(u.scopeMode))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+
+	folderID := "test-folder-id"
+	if d := getDriveContext(); d != nil && d.folderID != "" {
+		folderID = d.folderID
+	}
+
+	u.output.Reset()
+	u.err = cmd.RunDriveUsageWithDependencies(context.Background(), client, folderID, top, reclaim, u.output)
+	return nil
+}
+
+func iCheckDriveUsage() error {
+	return runDriveUsage(10, "")
+}
+
+func iCheckDriveUsageListingTop(top int) error {
+	return runDriveUsage(top, "")
+}
+
+func iCheckDriveUsageToReclaim(target string) error {
+	return runDriveUsage(10, target)
+}
+
+func theUsageReportShouldInclude(expected string) error {
+	u := getUsageContext()
+	if u.err != nil {
+		return fmt.Errorf("usage check failed: %v", u.err)
+	}
+	if !strings.Contains(u.output.String(), expected) {
+		return fmt.Errorf("expected usage report to include %q, got:\n%s", expected, u.output.String())
+	}
+	return nil
+}
+
+func theUsageReportShouldNotInclude(unexpected string) error {
+	u := getUsageContext()
+	if strings.Contains(u.output.String(), unexpected) {
+		return fmt.Errorf("expected usage report not to include %q, got:\n%s", unexpected, u.output.String())
+	}
+	return nil
+}
+
+func theUsageCheckShouldFailWith(expected string) error {
+	u := getUsageContext()
+	if u.err == nil {
+		return fmt.Errorf("expected usage check to fail with %q, but it succeeded", expected)
+	}
+	if !strings.Contains(u.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, u.err.Error())
+	}
+	return nil
+}
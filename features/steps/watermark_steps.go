@@ -0,0 +1,165 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"nac-service-media/cmd"
+	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/config"
+
+	"github.com/cucumber/godog"
+)
+
+// mockWatermarkPreviewer records the frame it was asked to render
+type mockWatermarkPreviewer struct {
+	called     bool
+	sourcePath string
+	at         video.Timestamp
+	watermark  video.Watermark
+	outputPath string
+}
+
+func (m *mockWatermarkPreviewer) PreviewWatermark(ctx context.Context, sourcePath string, at video.Timestamp, w video.Watermark, outputPath string) error {
+	m.called = true
+	m.sourcePath = sourcePath
+	m.at = at
+	m.watermark = w
+	m.outputPath = outputPath
+	return nil
+}
+
+// watermarkContext holds test state for watermark preview scenarios
+type watermarkContext struct {
+	settings  config.WatermarkConfig
+	previewer *mockWatermarkPreviewer
+	output    bytes.Buffer
+	err       error
+}
+
+var sharedWatermarkContext *watermarkContext
+
+func getWatermarkContext() *watermarkContext {
+	return sharedWatermarkContext
+}
+
+func InitializeWatermarkScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		sharedWatermarkContext = &watermarkContext{previewer: &mockWatermarkPreviewer{}}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		sharedWatermarkContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the watermark text is "([^"]*)" at the "([^"]*)"$`, theWatermarkTextIsAtThe)
+	ctx.Step(`^the trimmed video should be watermarked "([^"]*)" at the "([^"]*)"$`, theTrimmedVideoShouldBeWatermarked)
+	ctx.Step(`^the trimmed video should not be watermarked$`, theTrimmedVideoShouldNotBeWatermarked)
+	ctx.Step(`^I preview the watermark on "([^"]*)" at "([^"]*)"$`, iPreviewTheWatermarkOnAt)
+	ctx.Step(`^I preview the watermark on "([^"]*)" for date "([^"]*)" and title "([^"]*)"$`, iPreviewTheWatermarkOnForDateAndTitle)
+	ctx.Step(`^the preview frame should be taken at "([^"]*)" with text "([^"]*)"$`, thePreviewFrameShouldBeTakenAtWithText)
+	ctx.Step(`^the watermark preview should fail with "([^"]*)"$`, theWatermarkPreviewShouldFailWith)
+	ctx.Step(`^the watermark preview output should include "([^"]*)"$`, theWatermarkPreviewOutputShouldInclude)
+}
+
+// theWatermarkTextIsAtThe turns on video.watermark for both trim and preview scenarios
+func theWatermarkTextIsAtThe(text, position string) error {
+	w := getWatermarkContext()
+	w.settings = config.WatermarkConfig{Enabled: true, Text: text, Position: position}
+	style, err := w.settings.Style()
+	if err != nil {
+		return err
+	}
+	getTrimContext().watermark = style
+	return nil
+}
+
+func theTrimmedVideoShouldBeWatermarked(text, position string) error {
+	t := getTrimContext()
+	if len(t.trimmer.calls) == 0 {
+		return fmt.Errorf("trim was not called")
+	}
+	got := t.trimmer.calls[0].req.Watermark
+	if got.Text != text || got.Position != position {
+		return fmt.Errorf("expected watermark %q at %s, got %q at %s", text, position, got.Text, got.Position)
+	}
+	return nil
+}
+
+func theTrimmedVideoShouldNotBeWatermarked() error {
+	t := getTrimContext()
+	if len(t.trimmer.calls) == 0 {
+		return fmt.Errorf("trim was not called")
+	}
+	if got := t.trimmer.calls[0].req.Watermark; !got.IsZero() {
+		return fmt.Errorf("expected no watermark, got %q", got.Text)
+	}
+	return nil
+}
+
+func iPreviewTheWatermarkOnAt(source, at string) error {
+	return previewWatermark(cmd.WatermarkPreviewInput{SourcePath: source, At: at, OutputPath: "preview.png"})
+}
+
+func iPreviewTheWatermarkOnForDateAndTitle(source, date, title string) error {
+	return previewWatermark(cmd.WatermarkPreviewInput{
+		SourcePath: source,
+		At:         cmd.DefaultWatermarkPreviewAt,
+		OutputPath: "preview.png",
+		Date:       date,
+		Tags:       video.MediaTags{Title: title},
+	})
+}
+
+// previewWatermark previews the configured look, which is drawn even while
+// watermarking is disabled
+func previewWatermark(input cmd.WatermarkPreviewInput) error {
+	w := getWatermarkContext()
+	settings := w.settings
+	settings.Enabled = true
+	style, err := settings.Style()
+	if err != nil {
+		return err
+	}
+	w.err = cmd.RunWatermarkPreviewWithDependencies(context.Background(), w.previewer, style, video.ServiceCalendar{}, input, &w.output)
+	return nil
+}
+
+func thePreviewFrameShouldBeTakenAtWithText(at, text string) error {
+	w := getWatermarkContext()
+	if w.err != nil {
+		return fmt.Errorf("preview failed: %v", w.err)
+	}
+	if !w.previewer.called {
+		return fmt.Errorf("no preview frame was rendered")
+	}
+	if w.previewer.at.String() != at || w.previewer.watermark.Text != text {
+		return fmt.Errorf("expected %q at %s, got %q at %s", text, at, w.previewer.watermark.Text, w.previewer.at)
+	}
+	return nil
+}
+
+func theWatermarkPreviewShouldFailWith(expected string) error {
+	w := getWatermarkContext()
+	if w.err == nil {
+		return fmt.Errorf("expected preview to fail with %q, but it succeeded", expected)
+	}
+	if !strings.Contains(w.err.Error(), expected) {
+		return fmt.Errorf("expected error to contain %q, got: %v", expected, w.err)
+	}
+	return nil
+}
+
+func theWatermarkPreviewOutputShouldInclude(expected string) error {
+	w := getWatermarkContext()
+	if !strings.Contains(w.output.String(), expected) {
+		return fmt.Errorf("expected preview output to include %q, got:\n%s", expected, w.output.String())
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nac-service-media/cmd"
+	"nac-service-media/infrastructure/config"
+
+	"github.com/cucumber/godog"
+)
+
+// whoamiContext holds state for operator identity scenarios
+type whoamiContext struct {
+	cfg      *config.Config
+	userPath string
+	output   *bytes.Buffer
+	err      error
+}
+
+var sharedWhoamiContext *whoamiContext
+
+func getWhoamiContext() *whoamiContext {
+	return sharedWhoamiContext
+}
+
+func InitializeWhoamiScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		dir, err := os.MkdirTemp("", "whoami-test-*")
+		if err != nil {
+			return c, err
+		}
+		sharedWhoamiContext = &whoamiContext{
+			cfg:      &config.Config{},
+			userPath: filepath.Join(dir, "nac-service-media", config.UserConfigFile),
+			output:   &bytes.Buffer{},
+		}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if w := getWhoamiContext(); w != nil {
+			os.RemoveAll(filepath.Dir(filepath.Dir(w.userPath)))
+		}
+		sharedWhoamiContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^the config has senders:$`, theConfigHasSenders)
+	ctx.Step(`^the config has no default sender$`, theConfigHasNoDefaultSender)
+	ctx.Step(`^the operator is logged in as "([^"]*)"$`, theOperatorIsLoggedInAs)
+	ctx.Step(`^I run whoami$`, iRunWhoami)
+	ctx.Step(`^I run whoami with sender "([^"]*)" and operator email "([^"]*)"$`, iRunWhoamiWithSenderAndOperatorEmail)
+	ctx.Step(`^whoami should succeed$`, whoamiShouldSucceed)
+	ctx.Step(`^whoami should fail with "([^"]*)"$`, whoamiShouldFailWith)
+	ctx.Step(`^the whoami output should include "([^"]*)"$`, theWhoamiOutputShouldInclude)
+	ctx.Step(`^the user file should contain "([^"]*)"$`, theUserFileShouldContain)
+	ctx.Step(`^the user file should not exist$`, theUserFileShouldNotExist)
+}
+
+func theConfigHasSenders(table *godog.Table) error {
+	w := getWhoamiContext()
+	w.cfg.Senders.Senders = make(map[string]config.SenderConfig)
+	for i, row := range table.Rows {
+		if i == 0 {
+			continue // Skip header
+		}
+		key := row.Cells[0].Value
+		w.cfg.Senders.Senders[key] = config.SenderConfig{Name: row.Cells[1].Value}
+		if len(row.Cells) > 2 && row.Cells[2].Value == "yes" {
+			w.cfg.Senders.DefaultSender = key
+		}
+	}
+	return nil
+}
+
+func theConfigHasNoDefaultSender() error {
+	getWhoamiContext().cfg.Senders.DefaultSender = ""
+	return nil
+}
+
+func theOperatorIsLoggedInAs(username string) error {
+	getWhoamiContext().cfg.User.Login = config.LoginName(username)
+	return nil
+}
+
+func iRunWhoami() error {
+	return iRunWhoamiWithSenderAndOperatorEmail("", "")
+}
+
+func iRunWhoamiWithSenderAndOperatorEmail(sender, operatorEmail string) error {
+	w := getWhoamiContext()
+	w.err = cmd.RunWhoamiWithDependencies(w.cfg, w.userPath, sender, operatorEmail, w.output)
+	return nil
+}
+
+func whoamiShouldSucceed() error {
+	if err := getWhoamiContext().err; err != nil {
+		return fmt.Errorf("expected whoami to succeed, got: %v", err)
+	}
+	return nil
+}
+
+func whoamiShouldFailWith(expected string) error {
+	err := getWhoamiContext().err
+	if err == nil {
+		return fmt.Errorf("expected an error containing %q, got none", expected)
+	}
+	if !strings.Contains(err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got %q", expected, err.Error())
+	}
+	return nil
+}
+
+func theWhoamiOutputShouldInclude(expected string) error {
+	if out := getWhoamiContext().output.String(); !strings.Contains(out, expected) {
+		return fmt.Errorf("expected whoami output to include %q, got:\n%s", expected, out)
+	}
+	return nil
+}
+
+func theUserFileShouldContain(expected string) error {
+	data, err := os.ReadFile(getWhoamiContext().userPath)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(data), expected) {
+		return fmt.Errorf("expected the user file to contain %q, got:\n%s", expected, data)
+	}
+	return nil
+}
+
+func theUserFileShouldNotExist() error {
+	if _, err := os.Stat(getWhoamiContext().userPath); !os.IsNotExist(err) {
+		return fmt.Errorf("expected no user file at %s, got: %v", getWhoamiContext().userPath, err)
+	}
+	return nil
+}
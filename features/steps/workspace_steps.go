@@ -0,0 +1,176 @@
+//go:build integration
+
+package steps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nac-service-media/cmd"
+	"nac-service-media/infrastructure/workspace"
+
+	"github.com/cucumber/godog"
+)
+
+// workspaceContext holds test state for run workspace scenarios
+type workspaceContext struct {
+	base   string
+	run    *workspace.Workspace
+	kept   bool
+	output *bytes.Buffer
+	err    error
+}
+
+// SharedWorkspaceContext is reset before each scenario
+var SharedWorkspaceContext *workspaceContext
+
+func getWorkspaceContext() *workspaceContext {
+	return SharedWorkspaceContext
+}
+
+func InitializeWorkspaceScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		base, err := os.MkdirTemp("", "workspace-feature-*")
+		if err != nil {
+			return c, err
+		}
+		SharedWorkspaceContext = &workspaceContext{base: base, output: &bytes.Buffer{}}
+		return c, nil
+	})
+
+	ctx.After(func(c context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		if w := SharedWorkspaceContext; w != nil {
+			os.RemoveAll(w.base)
+		}
+		SharedWorkspaceContext = nil
+		return c, nil
+	})
+
+	ctx.Step(`^a workspace left behind (\d+) days? ago holding (\d+) bytes$`, aWorkspaceLeftBehindDaysAgo)
+	ctx.Step(`^I run workspace clean with --older-than "([^"]*)"$`, iRunWorkspaceCleanWithOlderThan)
+	ctx.Step(`^the workspace clean should succeed$`, theWorkspaceCleanShouldSucceed)
+	ctx.Step(`^the workspace clean should fail with "([^"]*)"$`, theWorkspaceCleanShouldFailWith)
+	ctx.Step(`^the workspace output should include "([^"]*)"$`, theWorkspaceOutputShouldInclude)
+	ctx.Step(`^(\d+) workspaces? should remain$`, workspacesShouldRemain)
+	ctx.Step(`^a run workspace is created$`, aRunWorkspaceIsCreated)
+	ctx.Step(`^the run workspace should have a "([^"]*)" folder$`, theRunWorkspaceShouldHaveAFolder)
+	ctx.Step(`^the run (succeeds|fails)$`, theRunFinishes)
+	ctx.Step(`^the run workspace should be (removed|kept)$`, theRunWorkspaceShouldBe)
+}
+
+func aWorkspaceLeftBehindDaysAgo(days int, size int) error {
+	w := getWorkspaceContext()
+	started := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	ws, err := workspace.New(w.base, started)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(ws.Dir(workspace.Frames), "frame_0001.jpg"), make([]byte, size), 0644); err != nil {
+		return err
+	}
+	return os.Chtimes(ws.Root(), started, started)
+}
+
+func iRunWorkspaceCleanWithOlderThan(olderThan string) error {
+	w := getWorkspaceContext()
+	w.err = cmd.RunWorkspaceCleanWithDependencies(w.base, olderThan, time.Now(), w.output)
+	return nil
+}
+
+func theWorkspaceCleanShouldSucceed() error {
+	w := getWorkspaceContext()
+	if w.err != nil {
+		return fmt.Errorf("expected success, got: %v\nOutput:\n%s", w.err, w.output.String())
+	}
+	return nil
+}
+
+func theWorkspaceCleanShouldFailWith(expected string) error {
+	w := getWorkspaceContext()
+	if w.err == nil {
+		return fmt.Errorf("expected an error containing %q, got success", expected)
+	}
+	if !strings.Contains(w.err.Error(), expected) {
+		return fmt.Errorf("expected error containing %q, got: %v", expected, w.err)
+	}
+	return nil
+}
+
+func theWorkspaceOutputShouldInclude(expected string) error {
+	w := getWorkspaceContext()
+	if !strings.Contains(w.output.String(), expected) {
+		return fmt.Errorf("expected output to include %q, got:\n%s", expected, w.output.String())
+	}
+	return nil
+}
+
+func workspacesShouldRemain(count int) error {
+	w := getWorkspaceContext()
+	runs, err := workspace.List(w.base)
+	if err != nil {
+		return err
+	}
+	if len(runs) != count {
+		return fmt.Errorf("expected %d workspaces to remain, found %d", count, len(runs))
+	}
+	return nil
+}
+
+func aRunWorkspaceIsCreated() error {
+	w := getWorkspaceContext()
+	ws, err := workspace.New(w.base, time.Now())
+	if err != nil {
+		return err
+	}
+	w.run = ws
+	return nil
+}
+
+func theRunWorkspaceShouldHaveAFolder(name string) error {
+	w := getWorkspaceContext()
+	info, err := os.Stat(w.run.Dir(name))
+	if err != nil {
+		return fmt.Errorf("expected folder %q in the run workspace: %w", name, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("expected %q to be a folder", name)
+	}
+	return nil
+}
+
+func theRunFinishes(outcome string) error {
+	w := getWorkspaceContext()
+	var runErr error
+	if outcome == "fails" {
+		runErr = errors.New("trim failed")
+	}
+	kept, err := w.run.Finish(runErr)
+	if err != nil {
+		return err
+	}
+	w.kept = kept
+	return nil
+}
+
+func theRunWorkspaceShouldBe(state string) error {
+	w := getWorkspaceContext()
+	_, err := os.Stat(w.run.Root())
+	exists := err == nil
+	switch state {
+	case "removed":
+		if exists || w.kept {
+			return fmt.Errorf("expected the run workspace to be removed, but it is still at %s", w.run.Root())
+		}
+	case "kept":
+		if !exists || !w.kept {
+			return fmt.Errorf("expected the run workspace to be kept for debugging")
+		}
+	}
+	return nil
+}
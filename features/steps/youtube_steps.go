@@ -0,0 +1,82 @@
+//go:build integration
+
+package steps
+
+import (
+	"context"
+	"fmt"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/youtube"
+
+	"github.com/cucumber/godog"
+)
+
+// youtubeChannel keeps the videos process uploads to YouTube, when a scenario sets one up
+var youtubeChannel *youtube.Channel
+
+func InitializeYouTubeScenario(ctx *godog.ScenarioContext) {
+	ctx.Before(func(c context.Context, sc *godog.Scenario) (context.Context, error) {
+		youtubeChannel = nil
+		return c, nil
+	})
+
+	ctx.Step(`^YouTube uploads go to a test channel$`, youtubeUploadsGoToATestChannel)
+	ctx.Step(`^the YouTube channel should have (\d+) videos?$`, theYouTubeChannelShouldHaveVideos)
+	ctx.Step(`^the YouTube video should be titled "([^"]*)"$`, theYouTubeVideoShouldBeTitled)
+	ctx.Step(`^the YouTube video should be (private|unlisted|public)$`, theYouTubeVideoShouldBe)
+}
+
+// testVideoHost returns a client for the test channel, or nil when the
+// scenario has none
+func testVideoHost() distribution.VideoHost {
+	if youtubeChannel == nil {
+		return nil
+	}
+	return youtube.NewClient(youtube.WithYouTubeService(youtubeChannel))
+}
+
+func youtubeUploadsGoToATestChannel() error {
+	youtubeChannel = youtube.NewChannel()
+	return nil
+}
+
+func theYouTubeChannelShouldHaveVideos(count int) error {
+	if youtubeChannel == nil {
+		return fmt.Errorf("no test channel was set up")
+	}
+	if n := len(youtubeChannel.Videos()); n != count {
+		return fmt.Errorf("expected %d videos on the channel, got %d", count, n)
+	}
+	return nil
+}
+
+func lastYouTubeVideo() (youtube.UploadedVideo, error) {
+	if youtubeChannel == nil || len(youtubeChannel.Videos()) == 0 {
+		return youtube.UploadedVideo{}, fmt.Errorf("no video was uploaded to YouTube")
+	}
+	videos := youtubeChannel.Videos()
+	return videos[len(videos)-1], nil
+}
+
+func theYouTubeVideoShouldBeTitled(title string) error {
+	v, err := lastYouTubeVideo()
+	if err != nil {
+		return err
+	}
+	if v.Title != title {
+		return fmt.Errorf("expected the video to be titled %q, got %q", title, v.Title)
+	}
+	return nil
+}
+
+func theYouTubeVideoShouldBe(privacy string) error {
+	v, err := lastYouTubeVideo()
+	if err != nil {
+		return err
+	}
+	if v.Privacy != privacy {
+		return fmt.Errorf("expected a %s video, got %q", privacy, v.Privacy)
+	}
+	return nil
+}
@@ -50,6 +50,18 @@ Feature: Video Trimming
     When I attempt to trim from "00:05:30" to "01:45:00"
     Then I should receive an error about invalid source filename
 
+  Scenario: --date names the output for a recording without a dated filename
+    Given a source video at "/test/videos/recording.mp4"
+    And the trim service date is "2025-12-28"
+    When I trim the video from "00:05:30" to "01:45:00"
+    Then the output file should be "/tmp/test-trimmed/2025-12-28.mp4"
+
+  Scenario: --date overrides the date in the filename
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And the trim service date is "2025-12-25"
+    When I trim the video from "00:05:30" to "01:45:00"
+    Then the output file should be "/tmp/test-trimmed/2025-12-25.mp4"
+
   Scenario: Trim video with audio extraction
     Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
     And the trim audio output directory is "/tmp/test-audio"
@@ -64,3 +76,99 @@ Feature: Video Trimming
       | libmp3lame   |
       | -ab          |
       | 192k         |
+
+  Scenario: Trim keeps the selected audio track for extraction
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And the trim audio output directory is "/tmp/test-audio"
+    And the trim audio bitrate is "192k"
+    And the trim audio track is 2
+    When I trim the video from "00:05:30" to "01:45:00" with audio extraction
+    Then the trimmed video should keep only audio track 2
+    And the trim audio extraction should read audio track 0
+
+  Scenario: Existing output is overwritten by default
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And an existing output file at "/tmp/test-trimmed/2025-12-28.mp4"
+    When I trim the video from "00:05:30" to "01:45:00" with on-existing "overwrite"
+    Then the output file should be "/tmp/test-trimmed/2025-12-28.mp4"
+    And the trim output should contain "Successfully created"
+
+  Scenario: Skip reuses a valid existing output
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And an existing output file at "/tmp/test-trimmed/2025-12-28.mp4"
+    When I trim the video from "00:05:30" to "01:45:00" with on-existing "skip"
+    Then the video should not have been trimmed again
+    And the trim output should contain "Reusing existing file: /tmp/test-trimmed/2025-12-28.mp4"
+
+  Scenario: Skip regenerates a corrupt existing output
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And an existing output file at "/tmp/test-trimmed/2025-12-28.mp4"
+    And the existing output file at "/tmp/test-trimmed/2025-12-28.mp4" is corrupt
+    When I trim the video from "00:05:30" to "01:45:00" with on-existing "skip"
+    Then the output file should be "/tmp/test-trimmed/2025-12-28.mp4"
+    And the trim output should contain "Successfully created"
+
+  Scenario: Version writes to the next free filename
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And an existing output file at "/tmp/test-trimmed/2025-12-28.mp4"
+    And an existing output file at "/tmp/test-trimmed/2025-12-28-v2.mp4"
+    When I trim the video from "00:05:30" to "01:45:00" with on-existing "version"
+    Then the output file should be "/tmp/test-trimmed/2025-12-28-v3.mp4"
+
+  Scenario: Declining the overwrite prompt keeps the existing output
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And an existing output file at "/tmp/test-trimmed/2025-12-28.mp4"
+    And I will answer "no" when asked to overwrite
+    When I trim the video from "00:05:30" to "01:45:00" with on-existing "prompt"
+    Then the video should not have been trimmed again
+    And the trim output should contain "Reusing existing file"
+
+  Scenario: Accepting the overwrite prompt replaces the existing output
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And an existing output file at "/tmp/test-trimmed/2025-12-28.mp4"
+    And I will answer "yes" when asked to overwrite
+    When I trim the video from "00:05:30" to "01:45:00" with on-existing "prompt"
+    Then the output file should be "/tmp/test-trimmed/2025-12-28.mp4"
+
+  Scenario: End relative to the end of the file
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And the source video is 110 minutes long
+    When I trim the video from "00:05:30" to "-00:05:00"
+    Then the trim output should contain "Resolved range: 00:05:30 to 01:45:00"
+    And ffmpeg should have been called with arguments:
+      | argument |
+      | -ss      |
+      | 00:05:30 |
+      | -to      |
+      | 01:45:00 |
+
+  Scenario: End relative to the start time
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    When I trim the video from "00:05:30" to "+01:00:00"
+    Then ffmpeg should have been called with arguments:
+      | argument |
+      | -ss      |
+      | 00:05:30 |
+      | -to      |
+      | 01:05:30 |
+
+  Scenario: Relative end longer than the file
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And the source video is 30 minutes long
+    When I attempt to trim from "00:05:30" to "-01:00:00"
+    Then the trim should fail with "before the start of a 00:30:00 file"
+
+  Scenario: Relative end without a readable duration
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    When I attempt to trim from "00:05:30" to "-00:05:00"
+    Then the trim should fail with "need the source duration"
+
+  Scenario: Trim chains audio extraction and upload
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And trim outputs are written under a temporary directory
+    When I trim the video from "00:05:30" to "01:45:00" with audio extraction and upload to folder "convention-folder"
+    Then the trim should have uploaded:
+      | file           | folder            |
+      | 2025-12-28.mp4 | convention-folder |
+      | 2025-12-28.mp3 | convention-folder |
+    And the trim output should contain "Upload complete!"
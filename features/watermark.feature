@@ -0,0 +1,34 @@
+Feature: Video Watermark
+  As a media volunteer in a district that requires it
+  I want the service date burned into the video
+  So that every recording shows when it was held
+
+  Background:
+    Given the trimmed output directory is "/tmp/test-trimmed"
+
+  Scenario: The service date is drawn while trimming
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    And the watermark text is "{date}" at the "bottom-right"
+    When I trim the video from "00:05:30" to "01:45:00"
+    Then the trimmed video should be watermarked "December 28, 2025" at the "bottom-right"
+
+  Scenario: No watermark unless it is enabled
+    Given a source video at "/test/videos/2025-12-28 10-06-16.mp4"
+    When I trim the video from "00:05:30" to "01:45:00"
+    Then the trimmed video should not be watermarked
+
+  Scenario: Preview one frame to check placement
+    Given the watermark text is "Service of {date}" at the "top-left"
+    When I preview the watermark on "/test/videos/2025-12-28 10-06-16.mp4" at "00:20:00"
+    Then the preview frame should be taken at "00:20:00" with text "Service of December 28, 2025"
+    And the watermark preview output should include "at the top-left of the frame at 00:20:00"
+    And the watermark preview output should include "Preview saved: preview.png"
+
+  Scenario: Preview with a given date and title
+    Given the watermark text is "{iso_date} - {title}" at the "bottom-center"
+    When I preview the watermark on "/test/videos/capture.mp4" for date "2025-12-21" and title "The Good Shepherd"
+    Then the preview frame should be taken at "00:01:00" with text "2025-12-21 - The Good Shepherd"
+
+  Scenario: Preview needs a date when the filename has none
+    When I preview the watermark on "/test/videos/capture.mp4" at "00:01:00"
+    Then the watermark preview should fail with "use --date to give the service date"
@@ -0,0 +1,41 @@
+Feature: Operator Identity
+  As a volunteer who runs the recordings on my own PC
+  I want emails signed as me without passing --sender every time
+  So that recipients know who sent the links
+
+  Background:
+    Given the config has senders:
+      | key      | name     | default |
+      | avteam   | A/V Team | yes     |
+      | jonathan | Jonathan |         |
+      | mary     | Mary     |         |
+
+  Scenario: The config default signs emails when nothing else is set
+    When I run whoami
+    Then whoami should succeed
+    And the whoami output should include "User file:      none"
+    And the whoami output should include "Sender:         A/V Team (avteam), from senders.default_sender"
+
+  Scenario: A sender named after the login signs emails
+    Given the operator is logged in as "CHURCH-PC\Mary"
+    When I run whoami
+    Then the whoami output should include "Login:          mary"
+    And the whoami output should include "Sender:         Mary (mary), from login name"
+
+  Scenario: The user file beats the login name
+    Given the operator is logged in as "mary"
+    When I run whoami with sender "Jonathan" and operator email "jonathan@example.com"
+    Then whoami should succeed
+    And the user file should contain "sender: jonathan"
+    And the whoami output should include "Sender:         Jonathan (jonathan), from user file"
+    And the whoami output should include "Operator email: jonathan@example.com, from user file"
+
+  Scenario: Setting an unknown sender is rejected
+    When I run whoami with sender "nobody" and operator email ""
+    Then whoami should fail with "sender not found"
+    And the user file should not exist
+
+  Scenario: No sender anywhere
+    Given the config has no default sender
+    When I run whoami
+    Then the whoami output should include "Sender:         not set; use --sender, whoami --set-sender, or senders.default_sender"
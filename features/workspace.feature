@@ -0,0 +1,45 @@
+Feature: Run Workspaces
+  As a user
+  I want each run's scratch files kept in one place
+  So that failed runs can be debugged and old leftovers cleaned up
+
+  Scenario: A run workspace has a folder for each kind of scratch file
+    Given a run workspace is created
+    Then the run workspace should have a "frames" folder
+    And the run workspace should have a "previews" folder
+    And the run workspace should have a "partial" folder
+
+  Scenario: A successful run removes its workspace
+    Given a run workspace is created
+    When the run succeeds
+    Then the run workspace should be removed
+
+  Scenario: A failed run keeps its workspace for debugging
+    Given a run workspace is created
+    When the run fails
+    Then the run workspace should be kept
+
+  Scenario: Clean removes workspaces older than the cutoff
+    Given a workspace left behind 10 days ago holding 2048 bytes
+    And a workspace left behind 1 day ago holding 1024 bytes
+    When I run workspace clean with --older-than "7d"
+    Then the workspace clean should succeed
+    And the workspace output should include "Freed 2.0 KB from 1 workspaces"
+    And 1 workspace should remain
+
+  Scenario: Clean accepts Go durations
+    Given a workspace left behind 1 day ago holding 1024 bytes
+    When I run workspace clean with --older-than "12h"
+    Then the workspace clean should succeed
+    And 0 workspaces should remain
+
+  Scenario: Nothing to clean
+    Given a workspace left behind 1 day ago holding 1024 bytes
+    When I run workspace clean with --older-than "7d"
+    Then the workspace clean should succeed
+    And the workspace output should include "No old workspaces to remove"
+    And 1 workspace should remain
+
+  Scenario: Invalid age is rejected
+    When I run workspace clean with --older-than "soon"
+    Then the workspace clean should fail with "invalid --older-than"
@@ -0,0 +1,78 @@
+Feature: YouTube Upload Target
+  As an A/V team member
+  I want to put service videos on our YouTube channel instead of, or as well as, Google Drive
+  So that the congregation can watch them where they already watch the livestream
+
+  Background:
+    Given the process config has paths:
+      | source_directory  | /test/source    |
+      | trimmed_directory | /test/trimmed   |
+      | audio_directory   | /test/audio     |
+    And the process config has services folder "folder123"
+    And the process config has ministers:
+      | key   | name           |
+      | smith | Pr. John Smith |
+    And the process config has recipients:
+      | key  | name     | address          |
+      | jane | Jane Doe | jane@example.com |
+    And the process config has senders:
+      | key    | name     | default |
+      | avteam | A/V Team | yes     |
+    And a source video exists at "/test/source/2025-12-28 10-06-16.mp4"
+
+  Scenario: The video goes to YouTube and the audio to Drive
+    Given YouTube uploads go to a test channel
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --minister  | smith                                |
+      | --recipient | jane                                 |
+      | --title     | The Good Shepherd                    |
+      | --target    | youtube                              |
+    Then the process should succeed
+    And the YouTube channel should have 1 video
+    And the YouTube video should be titled "Test Church: Service on 12/28/2025 - The Good Shepherd"
+    And the YouTube video should be unlisted
+    And the video should not be uploaded to Drive
+    And the audio should be uploaded to Drive
+    And the output should include "On YouTube: https://www.youtube.com/watch?v=video-1 (unlisted)"
+    And email should include "https://www.youtube.com/watch?v=video-1"
+
+  Scenario: With both targets the video goes to Drive too and the email links YouTube
+    Given YouTube uploads go to a test channel
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --target    | both                                 |
+    Then the process should succeed
+    And the output should include "Video target: YouTube and Drive"
+    And the YouTube channel should have 1 video
+    And the video should be uploaded to Drive
+    And the audio should be uploaded to Drive
+    And email should include "https://www.youtube.com/watch?v=video-1"
+
+  Scenario: A YouTube target without a YouTube sign-in stops before trimming
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --target    | youtube                              |
+    Then the process should fail with error "cannot upload to youtube: no video host is set up"
+    And the video should not be trimmed
+
+  Scenario: An unknown target is rejected
+    When I run process with flags:
+      | flag        | value                                |
+      | --input     | /test/source/2025-12-28 10-06-16.mp4 |
+      | --start     | 00:05:30                             |
+      | --end       | 01:45:00                             |
+      | --recipient | jane                                 |
+      | --target    | vimeo                                |
+    Then the process should fail with error "use drive, youtube or both"
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"nac-service-media/domain/remote"
+)
+
+// SelfRunner runs this tool's own commands as child processes, so each run
+// gets fresh flags and a crash doesn't take the server down
+type SelfRunner struct {
+	Executable string   // Path to this binary
+	BaseArgs   []string // Put before each command, e.g. --config path
+}
+
+var _ remote.CommandRunner = (*SelfRunner)(nil)
+
+// Run executes the command, writing its stdout and stderr to output
+func (r *SelfRunner) Run(ctx context.Context, args []string, output io.Writer) error {
+	cmd := exec.CommandContext(ctx, r.Executable, append(append([]string(nil), r.BaseArgs...), args...)...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", args[0], err)
+	}
+	return nil
+}
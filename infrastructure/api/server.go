@@ -0,0 +1,166 @@
+// Package api serves the experimental remote control API over HTTP
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/remote"
+)
+
+// maxBodyBytes bounds a request body; requests are a few short fields
+const maxBodyBytes = 64 * 1024
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler returns the API routes, each requiring the bearer token:
+//
+//	GET  /api/v1/runs          runs started since the server began
+//	POST /api/v1/runs          start a process run
+//	GET  /api/v1/runs/{id}     a run's state and output
+//	GET  /api/v1/history       processed services, ?from=&to= as YYYY-MM-DD
+//	POST /api/v1/resends       email a processed service again
+func NewHandler(ctrl remote.Controller, token string) (http.Handler, error) {
+	if strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("an API token is required")
+	}
+	h := &handler{ctrl: ctrl}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/runs", h.listRuns)
+	mux.HandleFunc("POST /api/v1/runs", h.startRun)
+	mux.HandleFunc("GET /api/v1/runs/{id}", h.getRun)
+	mux.HandleFunc("GET /api/v1/history", h.listHistory)
+	mux.HandleFunc("POST /api/v1/resends", h.resend)
+	return requireToken(token, mux), nil
+}
+
+type handler struct {
+	ctrl remote.Controller
+}
+
+func (h *handler) listRuns(w http.ResponseWriter, r *http.Request) {
+	runs := h.ctrl.Runs()
+	if runs == nil {
+		runs = []remote.Run{}
+	}
+	writeJSON(w, http.StatusOK, runs)
+}
+
+func (h *handler) startRun(w http.ResponseWriter, r *http.Request) {
+	var req remote.ProcessRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	run, err := h.ctrl.StartProcess(req)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+func (h *handler) getRun(w http.ResponseWriter, r *http.Request) {
+	run, err := h.ctrl.Run(r.PathValue("id"))
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+func (h *handler) listHistory(w http.ResponseWriter, r *http.Request) {
+	var filter history.Filter
+	for _, p := range []struct {
+		name string
+		into *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := r.URL.Query().Get(p.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s %q (use YYYY-MM-DD)", p.name, value))
+			return
+		}
+		*p.into = t
+	}
+	entries, err := h.ctrl.History(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if entries == nil {
+		entries = []history.Entry{}
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (h *handler) resend(w http.ResponseWriter, r *http.Request) {
+	var req remote.ResendRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	run, err := h.ctrl.Resend(req)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+// requireToken rejects requests without "Authorization: Bearer <token>"
+func requireToken(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="nac-service-media"`)
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or wrong API token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusFor maps controller errors to HTTP statuses; anything else is a bad request
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, remote.ErrRunInProgress):
+		return http.StatusConflict
+	case errors.Is(err, remote.ErrRunNotFound), errors.Is(err, remote.ErrNothingToResend):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// readJSON decodes the request body into v, writing a 400 when it can't
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
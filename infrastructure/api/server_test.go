@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/remote"
+)
+
+const testToken = "secret"
+
+// fakeController records what the API asked of it
+type fakeController struct {
+	started []remote.ProcessRequest
+	resent  []remote.ResendRequest
+	runs    map[string]remote.Run
+	entries []history.Entry
+	filter  history.Filter
+	err     error
+}
+
+func (f *fakeController) StartProcess(req remote.ProcessRequest) (remote.Run, error) {
+	if f.err != nil {
+		return remote.Run{}, f.err
+	}
+	f.started = append(f.started, req)
+	return remote.Run{ID: "run-1", Kind: remote.KindProcess, State: remote.RunRunning}, nil
+}
+
+func (f *fakeController) Resend(req remote.ResendRequest) (remote.Run, error) {
+	if f.err != nil {
+		return remote.Run{}, f.err
+	}
+	f.resent = append(f.resent, req)
+	return remote.Run{ID: "run-2", Kind: remote.KindResend, State: remote.RunRunning}, nil
+}
+
+func (f *fakeController) Run(id string) (remote.Run, error) {
+	run, ok := f.runs[id]
+	if !ok {
+		return remote.Run{}, fmt.Errorf("%w: %s", remote.ErrRunNotFound, id)
+	}
+	return run, nil
+}
+
+func (f *fakeController) Runs() []remote.Run {
+	var runs []remote.Run
+	for _, r := range f.runs {
+		runs = append(runs, r)
+	}
+	return runs
+}
+
+func (f *fakeController) History(filter history.Filter) ([]history.Entry, error) {
+	f.filter = filter
+	return f.entries, nil
+}
+
+var _ remote.Controller = (*fakeController)(nil)
+
+func serve(t *testing.T, ctrl remote.Controller, method, path, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler, err := NewHandler(ctrl, testToken)
+	if err != nil {
+		t.Fatalf("NewHandler() error: %v", err)
+	}
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNewHandler_RequiresToken(t *testing.T) {
+	if _, err := NewHandler(&fakeController{}, " "); err == nil {
+		t.Error("expected an error for an empty token")
+	}
+}
+
+func TestHandler_RejectsMissingOrWrongToken(t *testing.T) {
+	for _, token := range []string{"", "wrong"} {
+		rec := serve(t, &fakeController{}, http.MethodGet, "/api/v1/runs", "", token)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("token %q: status = %d, want 401", token, rec.Code)
+		}
+	}
+}
+
+func TestHandler_StartRun(t *testing.T) {
+	ctrl := &fakeController{}
+	rec := serve(t, ctrl, http.MethodPost, "/api/v1/runs",
+		`{"start":"00:05:30","end":"01:45:00","minister":"smith","recipients":["jane"]}`, testToken)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", rec.Code, rec.Body)
+	}
+	if len(ctrl.started) != 1 || ctrl.started[0].Minister != "smith" || ctrl.started[0].Recipients[0] != "jane" {
+		t.Errorf("started = %+v", ctrl.started)
+	}
+	var run remote.Run
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil || run.ID != "run-1" {
+		t.Errorf("body = %s (%v)", rec.Body, err)
+	}
+}
+
+func TestHandler_StartRun_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"bad JSON", `{"start":`, nil, http.StatusBadRequest},
+		{"unknown field", `{"recipient":"jane"}`, nil, http.StatusBadRequest},
+		{"invalid request", `{}`, fmt.Errorf("recipients are required"), http.StatusBadRequest},
+		{"busy", `{"recipients":["jane"]}`, fmt.Errorf("%w: run-1", remote.ErrRunInProgress), http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, &fakeController{err: tt.err}, http.MethodPost, "/api/v1/runs", tt.body, testToken)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), `"error"`) {
+				t.Errorf("body = %s, want an error field", rec.Body)
+			}
+		})
+	}
+}
+
+func TestHandler_GetRun(t *testing.T) {
+	ctrl := &fakeController{runs: map[string]remote.Run{
+		"run-1": {ID: "run-1", State: remote.RunSucceeded, Output: "Done"},
+	}}
+	rec := serve(t, ctrl, http.MethodGet, "/api/v1/runs/run-1", "", testToken)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"state":"succeeded"`) {
+		t.Errorf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(t, ctrl, http.MethodGet, "/api/v1/runs/run-9", "", testToken)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown run: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestHandler_ListRuns_EmptyIsArray(t *testing.T) {
+	rec := serve(t, &fakeController{}, http.MethodGet, "/api/v1/runs", "", testToken)
+	if strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("body = %s, want []", rec.Body)
+	}
+}
+
+func TestHandler_History(t *testing.T) {
+	ctrl := &fakeController{entries: []history.Entry{{Minister: "Pr. Smith", Outcome: history.OutcomeSuccess}}}
+	rec := serve(t, ctrl, http.MethodGet, "/api/v1/history?from=2025-12-01&to=2025-12-31", "", testToken)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Pr. Smith") {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	want := history.Filter{
+		From: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+	if !ctrl.filter.From.Equal(want.From) || !ctrl.filter.To.Equal(want.To) {
+		t.Errorf("filter = %+v, want %+v", ctrl.filter, want)
+	}
+
+	rec = serve(t, ctrl, http.MethodGet, "/api/v1/history?from=last-week", "", testToken)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad from: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestHandler_Resend(t *testing.T) {
+	ctrl := &fakeController{}
+	rec := serve(t, ctrl, http.MethodPost, "/api/v1/resends", `{"date":"2025-12-28","recipients":["jane"]}`, testToken)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", rec.Code, rec.Body)
+	}
+	if len(ctrl.resent) != 1 || ctrl.resent[0].Date != "2025-12-28" {
+		t.Errorf("resent = %+v", ctrl.resent)
+	}
+
+	ctrl.err = fmt.Errorf("%w for 2025-12-21", remote.ErrNothingToResend)
+	rec = serve(t, ctrl, http.MethodPost, "/api/v1/resends", `{"date":"2025-12-21","recipients":["jane"]}`, testToken)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("nothing to resend: status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestLoadOrCreateToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets", "api_token")
+
+	token, created, err := LoadOrCreateToken(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created || len(token) != 64 {
+		t.Errorf("got token %q, created %v; want a new 64-character token", token, created)
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("token file mode = %v, want 0600", info.Mode().Perm())
+		}
+	}
+
+	again, created, err := LoadOrCreateToken(path)
+	if err != nil || created || again != token {
+		t.Errorf("second load = %q, created %v, err %v; want the same token", again, created, err)
+	}
+}
+
+func TestLoadOrCreateToken_EmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_token")
+	if err := os.WriteFile(path, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadOrCreateToken(path); err == nil {
+		t.Error("expected an error for an empty token file")
+	}
+}
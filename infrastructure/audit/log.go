@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"nac-service-media/domain/audit"
+)
+
+// JSONLog appends audit events to a JSON-lines file. Lines are only ever
+// added; each is sealed with the hash of the line before it. Records are
+// serialized so concurrent deletions still form one chain.
+type JSONLog struct {
+	mu      sync.Mutex
+	path    string
+	actor   string
+	command string
+	now     func() time.Time
+}
+
+var _ audit.Log = (*JSONLog)(nil)
+
+// Option configures a JSONLog
+type Option func(*JSONLog)
+
+// WithActor stamps events with who ran the command, e.g. user@host
+func WithActor(actor string) Option {
+	return func(l *JSONLog) {
+		l.actor = actor
+	}
+}
+
+// WithCommand stamps events with the command that performed them
+func WithCommand(command string) Option {
+	return func(l *JSONLog) {
+		l.command = command
+	}
+}
+
+// WithClock sets the time source (for testing)
+func WithClock(now func() time.Time) Option {
+	return func(l *JSONLog) {
+		l.now = now
+	}
+}
+
+// NewJSONLog creates a log backed by the file at path
+func NewJSONLog(path string, opts ...Option) *JSONLog {
+	l := &JSONLog{path: path, now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Path returns the backing file
+func (l *JSONLog) Path() string {
+	return l.path
+}
+
+// Record seals the event after the last one in the file and appends it,
+// creating the file if needed
+func (l *JSONLog) Record(e audit.Event) error {
+	if e.Time.IsZero() {
+		e.Time = l.now()
+	}
+	if e.Actor == "" {
+		e.Actor = l.actor
+	}
+	if e.Command == "" {
+		e.Command = l.command
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events, err := l.List()
+	if err != nil {
+		return err
+	}
+	prev := ""
+	if len(events) > 0 {
+		prev = events[len(events)-1].Hash
+	}
+
+	line, err := json.Marshal(audit.Seal(e, prev))
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	if dir := filepath.Dir(l.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create audit log directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+// List reads all events. A missing file is an empty log.
+func (l *JSONLog) List() ([]audit.Event, error) {
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var events []audit.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e audit.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("invalid audit event on line %d of %s: %w", lineNum, l.path, err)
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return events, nil
+}
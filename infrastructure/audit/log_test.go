@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/audit"
+)
+
+func TestJSONLog_RecordChainsEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	now := time.Date(2025, 12, 28, 12, 0, 0, 0, time.UTC)
+	log := NewJSONLog(path, WithActor("av@church-pc"), WithCommand("nac-service-media drive cleanup"), WithClock(func() time.Time { return now }))
+
+	if err := log.Record(audit.NewEvent(audit.ActionDriveDelete, "file1", nil)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := log.Record(audit.NewEvent(audit.ActionEmptyTrash, "", nil)); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	events, err := log.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[1].PrevHash != events[0].Hash {
+		t.Error("expected the second event to follow the first")
+	}
+	if events[0].Actor != "av@church-pc" || events[0].Command != "nac-service-media drive cleanup" || !events[0].Time.Equal(now) {
+		t.Errorf("expected stamped event, got %+v", events[0])
+	}
+	if err := audit.Verify(events); err != nil {
+		t.Errorf("expected intact chain, got %v", err)
+	}
+}
+
+func TestJSONLog_EditedLineFailsVerification(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewJSONLog(path)
+	for _, target := range []string{"file1", "file2"} {
+		if err := log.Record(audit.NewEvent(audit.ActionDriveDelete, target, nil)); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "file1", "fileX", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := log.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if err := audit.Verify(events); err == nil || !strings.Contains(err.Error(), "event 1 was edited") {
+		t.Errorf("expected event 1 to fail verification, got %v", err)
+	}
+}
+
+func TestJSONLog_MissingFileIsEmpty(t *testing.T) {
+	events, err := NewJSONLog(filepath.Join(t.TempDir(), "none.jsonl")).List()
+	if err != nil || len(events) != 0 {
+		t.Errorf("expected empty log, got %v, %v", events, err)
+	}
+}
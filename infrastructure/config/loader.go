@@ -2,21 +2,332 @@ package config
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/detection"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/filesystem"
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/notification"
+	"nac-service-media/domain/policy"
+	"nac-service-media/domain/summary"
+	"nac-service-media/domain/video"
+	"nac-service-media/infrastructure/drive"
+	"nac-service-media/infrastructure/ffmpeg"
+	infrafs "nac-service-media/infrastructure/filesystem"
+	"nac-service-media/infrastructure/network"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the complete application configuration
 type Config struct {
-	Paths     PathsConfig               `yaml:"paths"`
-	Audio     AudioConfig               `yaml:"audio"`
-	Google    GoogleConfig              `yaml:"google"`
-	Email     EmailConfig               `yaml:"email"`
-	Ministers map[string]MinisterConfig `yaml:"ministers,omitempty"`
-	Senders   SendersConfig             `yaml:"senders,omitempty"`
-	Detection DetectionConfig           `yaml:"detection,omitempty"`
+	Paths       PathsConfig               `yaml:"paths"`
+	Audio       AudioConfig               `yaml:"audio"`
+	Video       VideoConfig               `yaml:"video,omitempty"`
+	Google      GoogleConfig              `yaml:"google"`
+	Storage     StorageConfig             `yaml:"storage,omitempty"`
+	YouTube     YouTubeConfig             `yaml:"youtube,omitempty"`
+	Email       EmailConfig               `yaml:"email"`
+	Ministers   map[string]MinisterConfig `yaml:"ministers,omitempty"`
+	Senders     SendersConfig             `yaml:"senders,omitempty"`
+	Detection   DetectionConfig           `yaml:"detection,omitempty"`
+	Update      UpdateConfig              `yaml:"update,omitempty"`
+	OBS         OBSConfig                 `yaml:"obs,omitempty"`
+	Publish     PublishConfig             `yaml:"publish,omitempty"`
+	History     HistoryConfig             `yaml:"history,omitempty"`
+	Audit       AuditConfig               `yaml:"audit,omitempty"`
+	Scan        ScanConfig                `yaml:"scan,omitempty"`
+	Summary     SummaryConfig             `yaml:"summary,omitempty"`
+	Network     NetworkConfig             `yaml:"network,omitempty"`
+	Locale      LocaleConfig              `yaml:"locale,omitempty"`
+	Archive     ArchiveConfig             `yaml:"archive,omitempty"`
+	YearArchive YearArchiveConfig         `yaml:"year_archive,omitempty"`
+	Budget      UploadBudgetConfig        `yaml:"upload_budget,omitempty"`
+	API         APIConfig                 `yaml:"api,omitempty"`
+	Defaults    DefaultsConfig            `yaml:"defaults,omitempty"`
+	Funeral     FuneralConfig             `yaml:"funeral,omitempty"`
+
+	// User is this machine's operator identity, read from its own file
+	User UserConfig `yaml:"-"`
+}
+
+// Defaults for the remote control API
+const (
+	DefaultAPIListen    = "127.0.0.1:8765"
+	DefaultAPITokenFile = "api_token"
+)
+
+// APIConfig contains settings for the experimental remote control API
+// started by "serve --api"
+type APIConfig struct {
+	// Listen is the address to serve on (default 127.0.0.1:8765, this machine only)
+	Listen string `yaml:"listen,omitempty"`
+	// TokenFile holds the bearer token clients must send (default api_token);
+	// a random token is written there the first time the server starts
+	TokenFile string `yaml:"token_file,omitempty"`
+}
+
+// DefaultsConfig fills in what "process --quick" is not told, so a regular
+// Sunday needs no other flags
+type DefaultsConfig struct {
+	// Minister is the minister key used when MinisterSchedule has no entry
+	Minister string `yaml:"minister,omitempty"`
+	// MinisterSchedule maps service dates (YYYY-MM-DD) to minister keys
+	MinisterSchedule map[string]string `yaml:"minister_schedule,omitempty"`
+	// Recipients are the recipient keys, names or groups emailed
+	Recipients []string `yaml:"recipients,omitempty"`
+	// ServiceLength is a typical service's length (HH:MM:SS), used as
+	// --end +ServiceLength when the end cannot be auto-detected
+	ServiceLength string `yaml:"service_length,omitempty"`
+	// Sender is the sender key, unless this machine's user file names one
+	Sender string `yaml:"sender,omitempty"`
+}
+
+// MinisterFor returns the scheduled minister key for a service date, or the
+// fallback minister when the date is not scheduled
+func (c DefaultsConfig) MinisterFor(date time.Time) string {
+	if key, ok := c.MinisterSchedule[date.Format("2006-01-02")]; ok {
+		return key
+	}
+	return c.Minister
+}
+
+// validate checks the schedule's dates and the service length
+func (c DefaultsConfig) validate() error {
+	for date := range c.MinisterSchedule {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("minister_schedule: %q is not a YYYY-MM-DD date", date)
+		}
+	}
+	if c.ServiceLength != "" {
+		if _, err := video.ParseTimestamp(c.ServiceLength); err != nil {
+			return fmt.Errorf("service_length: %w", err)
+		}
+	}
+	return nil
+}
+
+// UploadBudgetConfig limits how much is uploaded each week, for congregations
+// on a metered connection such as an LTE backup
+type UploadBudgetConfig struct {
+	// WeeklyGB is how much may be uploaded in any 7 days; 0 (default) is no limit
+	WeeklyGB float64 `yaml:"weekly_gb,omitempty"`
+	// OnExceed is "warn" (default) or "confirm", which asks before a run goes
+	// over the budget and stops a non-interactive one
+	OnExceed string `yaml:"on_exceed,omitempty"`
+}
+
+// UploadBudget returns the weekly budget in bytes
+func (b UploadBudgetConfig) UploadBudget() history.UploadBudget {
+	return history.UploadBudget{
+		WeeklyBytes: int64(b.WeeklyGB * 1024 * 1024 * 1024),
+		OnExceed:    b.OnExceed,
+	}
+}
+
+// ArchiveConfig keeps compressed copies of old outputs that local cleanup
+// deletes, so a backup survives once Drive has been cleaned up too
+type ArchiveConfig struct {
+	// Directory holds the archived copies; nothing is archived when empty
+	Directory string `yaml:"directory,omitempty"`
+	// Include lists what to archive: trimmed, audio, source (default trimmed and audio)
+	Include []string `yaml:"include,omitempty"`
+	// VideoBitrate is the bitrate videos are re-encoded at (default 500k)
+	VideoBitrate Bitrate `yaml:"video_bitrate,omitempty"`
+	// KeepWeeks deletes archived copies older than this; 0 keeps them forever
+	KeepWeeks int `yaml:"keep_weeks,omitempty"`
+}
+
+// Policy returns the archive's retention rules
+func (a ArchiveConfig) Policy() (filesystem.ArchivePolicy, error) {
+	return filesystem.ParseArchivePolicy(a.Include, a.KeepWeeks)
+}
+
+// FuneralConfig contains settings for "process --type funeral", which shares
+// the recordings privately with the recipients
+type FuneralConfig struct {
+	// RevokeAfterDays ends the recipients' access this many days after the
+	// upload (default 30)
+	RevokeAfterDays int `yaml:"revoke_after_days,omitempty"`
+	// RetentionDays deletes the files from Drive this many days after the
+	// upload (default 90)
+	RetentionDays int `yaml:"retention_days,omitempty"`
+}
+
+// Settings returns the funeral policy's settings with defaults applied
+func (f FuneralConfig) Settings() policy.FuneralSettings {
+	settings := policy.FuneralSettings{
+		RevokeAfterDays: f.RevokeAfterDays,
+		RetentionDays:   f.RetentionDays,
+	}
+	if settings.RevokeAfterDays == 0 {
+		settings.RevokeAfterDays = policy.DefaultFuneralRevokeAfterDays
+	}
+	if settings.RetentionDays == 0 {
+		settings.RetentionDays = policy.DefaultFuneralRetentionDays
+	}
+	return settings
+}
+
+// ServicePolicy returns the policy for a "process --type" value
+func (c *Config) ServicePolicy(serviceType string) (policy.Policy, error) {
+	return policy.ForType(serviceType, c.Funeral.Settings())
+}
+
+// YearArchiveConfig contains settings for "archive year", which gathers a
+// year's services into their own Drive folder at the end of the year
+type YearArchiveConfig struct {
+	// FolderName is the Services subfolder holding a folder per year (default Archive)
+	FolderName string `yaml:"folder_name,omitempty"`
+	// ShareWith are the recipient keys, names or groups, such as a leadership
+	// group, given read access to each archived year
+	ShareWith []string `yaml:"share_with,omitempty"`
+}
+
+// DefaultHistoryFile is the history file used when history.file is not set
+const DefaultHistoryFile = "history.jsonl"
+
+// HistoryConfig contains settings for the local record of processed services
+type HistoryConfig struct {
+	// File is the JSON-lines history file (default history.jsonl)
+	File string `yaml:"file,omitempty"`
+}
+
+// DefaultAuditFile is the audit log used when audit.file is not set
+const DefaultAuditFile = "audit.jsonl"
+
+// AuditConfig contains settings for the log of destructive operations
+type AuditConfig struct {
+	// File is the append-only JSON-lines audit log (default audit.jsonl)
+	File string `yaml:"file,omitempty"`
+}
+
+// ScanConfig runs an external scanner, such as a virus scanner, on local
+// files before their uploads are shared publicly
+type ScanConfig struct {
+	// Command is the program and its arguments; {file} is replaced with the
+	// file's path, which is appended when no argument has it. Empty disables
+	// scanning.
+	Command []string `yaml:"command,omitempty"`
+	// TimeoutSeconds bounds one scan (default 300)
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+	// ExitCode is the exit code that means the file is clean (default 0)
+	ExitCode int `yaml:"exit_code,omitempty"`
+}
+
+// Timeout returns how long one scan may take; zero means the scanner's default
+func (c ScanConfig) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// NetworkConfig contains outbound HTTP settings for the Google APIs
+type NetworkConfig struct {
+	// ProxyURL is the HTTP(S) or SOCKS5 proxy; when empty, HTTPS_PROXY is used
+	ProxyURL string `yaml:"proxy_url,omitempty"`
+	// CABundle is a PEM file of extra trusted CAs, e.g. for a TLS-inspecting proxy
+	CABundle string `yaml:"ca_bundle,omitempty"`
+	// RateLimit is the most requests a second sent to each Google host (default: no limit)
+	RateLimit float64 `yaml:"rate_limit,omitempty"`
+	// Debug logs each request to stderr, and a count per host when the command ends
+	Debug bool `yaml:"debug,omitempty"`
+}
+
+// LocaleConfig contains the timezones used to work out service dates
+type LocaleConfig struct {
+	// Timezone is the congregation's IANA timezone, e.g. "America/New_York"
+	// (default: the system timezone)
+	Timezone string `yaml:"timezone,omitempty"`
+	// RecordingTimezone is the clock OBS names recordings with, when it differs
+	// from this machine's (default: the system timezone)
+	RecordingTimezone string `yaml:"recording_timezone,omitempty"`
+	// MaxClockDriftHours is how far a recording's modification time may be
+	// from the time in its name before --date is required (default 12)
+	MaxClockDriftHours int `yaml:"max_clock_drift_hours,omitempty"`
+}
+
+// DefaultMaxClockDrift is used when locale.max_clock_drift_hours is unset
+const DefaultMaxClockDrift = 12 * time.Hour
+
+// MaxClockDrift returns how far a recording's modification time may be from
+// the time in its name
+func (l LocaleConfig) MaxClockDrift() time.Duration {
+	if l.MaxClockDriftHours > 0 {
+		return time.Duration(l.MaxClockDriftHours) * time.Hour
+	}
+	return DefaultMaxClockDrift
+}
+
+// Calendar returns the service calendar for these timezones
+func (l LocaleConfig) Calendar() (video.ServiceCalendar, error) {
+	var cal video.ServiceCalendar
+	if l.Timezone != "" {
+		loc, err := time.LoadLocation(l.Timezone)
+		if err != nil {
+			return cal, fmt.Errorf("unknown timezone %q: %w", l.Timezone, err)
+		}
+		cal.Service = loc
+	}
+	if l.RecordingTimezone != "" {
+		loc, err := time.LoadLocation(l.RecordingTimezone)
+		if err != nil {
+			return cal, fmt.Errorf("unknown recording timezone %q: %w", l.RecordingTimezone, err)
+		}
+		cal.Recording = loc
+	}
+	return cal, nil
+}
+
+// SummaryConfig contains settings for the per-run summary written by `process`
+type SummaryConfig struct {
+	// Dir is where summaries are archived; no summary is written when empty
+	Dir string `yaml:"dir,omitempty"`
+	// Formats is "markdown", "html" or "markdown,html" (default both)
+	Formats string `yaml:"formats,omitempty"`
+}
+
+// PublishConfig contains settings for mirroring outputs to an SFTP or WebDAV
+// server, for recipients who cannot reach Google Drive
+type PublishConfig struct {
+	// Provider is "sftp" or "webdav"
+	Provider string `yaml:"provider,omitempty"`
+	// URL is sftp://host[:port]/dir or the WebDAV collection URL
+	URL      string `yaml:"url,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// PrivateKeyFile and KnownHostsFile are used by SFTP
+	PrivateKeyFile string `yaml:"private_key_file,omitempty"`
+	KnownHostsFile string `yaml:"known_hosts_file,omitempty"`
+	// PublicURL is the base download URL recipients use (required for SFTP)
+	PublicURL string `yaml:"public_url,omitempty"`
+	// Auto publishes during `process` and adds the mirror links to the email
+	Auto bool `yaml:"auto,omitempty"`
+}
+
+// OBSConfig contains obs-websocket connection settings for process --from-obs
+type OBSConfig struct {
+	// URL is the obs-websocket address (default ws://localhost:4455)
+	URL string `yaml:"url,omitempty"`
+	// Password is the obs-websocket server password, if authentication is enabled
+	Password string `yaml:"password,omitempty"`
+}
+
+// UpdateConfig contains self-update settings
+type UpdateConfig struct {
+	// Disabled turns off self-update (for managed installs)
+	Disabled bool `yaml:"disabled,omitempty"`
+	// Channel is "stable" (default) or "beta"
+	Channel string `yaml:"channel,omitempty"`
+	// Repository overrides the GitHub "owner/name" that publishes releases
+	Repository string `yaml:"repository,omitempty"`
+	// PublicKey is a base64 ed25519 key; when set, release checksums must be signed
+	PublicKey string `yaml:"public_key,omitempty"`
 }
 
 // DetectionConfig contains settings for automatic timestamp detection
@@ -26,13 +337,73 @@ type DetectionConfig struct {
 	AudioTemplatesDir string                    `yaml:"audio_templates_dir"`
 	Thresholds        DetectionThresholdsConfig `yaml:"thresholds"`
 	SearchRange       SearchRangeConfig         `yaml:"search_range"`
+	// Drift alerts when start match scores stay low for several weeks, so
+	// templates can be re-captured before detection fails
+	Drift DetectionDriftConfig `yaml:"drift,omitempty"`
+	// End chooses how the service end is found when --end is omitted
+	End EndDetectionConfig `yaml:"end,omitempty"`
+}
+
+// EndDetectionConfig contains the service end detection settings. Every
+// method searches from amen_start_offset_minutes after the start, for
+// amen_search_duration_minutes.
+type EndDetectionConfig struct {
+	// Method is amen (default), cross or silence; silence needs only ffmpeg
+	Method string `yaml:"method,omitempty"`
+	// CrossStepSeconds is how far apart frames are checked for the cross
+	// going dark (default 30)
+	CrossStepSeconds int `yaml:"cross_step_seconds,omitempty"`
+	// SilenceSeconds is how long the audio must stay quiet to end the service (default 30)
+	SilenceSeconds int `yaml:"silence_seconds,omitempty"`
+	// SilenceNoiseDB is the level below which audio counts as quiet (default -40)
+	SilenceNoiseDB int `yaml:"silence_noise_db,omitempty"`
+}
+
+// EndMethod returns detection.end.method, normalized by Load
+func (c DetectionConfig) EndMethod() detection.EndMethod {
+	if c.End.Method == "" {
+		return detection.EndByAmen
+	}
+	return detection.EndMethod(c.End.Method)
+}
+
+// DetectionDriftConfig contains the match score drift alert settings
+type DetectionDriftConfig struct {
+	// Threshold is the score below which a service counts as low
+	// (default match_score + 0.05)
+	Threshold float64 `yaml:"threshold,omitempty"`
+	// Weeks is how many low services in a row raise the alert (default 3)
+	Weeks int `yaml:"weeks,omitempty"`
+}
+
+// defaultMatchScore is the template detector's match_score when unset
+const defaultMatchScore = 0.85
+
+// DriftThreshold returns detection.drift.threshold, defaulting to just above
+// the match threshold
+func (c DetectionConfig) DriftThreshold() float64 {
+	if c.Drift.Threshold > 0 {
+		return c.Drift.Threshold
+	}
+	match := c.Thresholds.MatchScore
+	if match == 0 {
+		match = defaultMatchScore
+	}
+	return math.Min(match+history.DefaultDriftMargin, 1)
 }
 
 // DetectionThresholdsConfig contains detection threshold settings
 type DetectionThresholdsConfig struct {
-	MatchScore        float64 `yaml:"match_score"`
-	CoarseStepSeconds int     `yaml:"coarse_step_seconds"`
-	AmenMatchScore    float64 `yaml:"amen_match_score"`
+	MatchScore float64 `yaml:"match_score"`
+	// CoarseStepSeconds is the largest coarse scan step (default 120)
+	CoarseStepSeconds int `yaml:"coarse_step_seconds"`
+	// CoarseMinStepSeconds is the smallest step the scan shrinks to as the
+	// cross nears lit (default 10); set it equal to coarse_step_seconds for a fixed step
+	CoarseMinStepSeconds int     `yaml:"coarse_min_step_seconds,omitempty"`
+	AmenMatchScore       float64 `yaml:"amen_match_score"`
+	// EarlyExitScore is the match score an adjacent unlit/lit frame pair
+	// needs to end detection without refinement (default 0.95); negative turns it off
+	EarlyExitScore float64 `yaml:"early_exit_score,omitempty"`
 }
 
 // SearchRangeConfig contains the video time range to search for cross lighting
@@ -41,6 +412,59 @@ type SearchRangeConfig struct {
 	EndMinutes                int `yaml:"end_minutes"`
 	AmenStartOffsetMinutes    int `yaml:"amen_start_offset_minutes"`
 	AmenSearchDurationMinutes int `yaml:"amen_search_duration_minutes"`
+	// Adaptive narrows the start search to where recent services started
+	Adaptive AdaptiveSearchConfig `yaml:"adaptive,omitempty"`
+}
+
+// AdaptiveSearchConfig contains the settings for narrowing the start search
+// range to the starts detected in recent services
+type AdaptiveSearchConfig struct {
+	// Disabled always searches the whole start_minutes to end_minutes range
+	Disabled bool `yaml:"disabled,omitempty"`
+	// MarginMinutes is searched either side of the recent starts (default 2)
+	MarginMinutes int `yaml:"margin_minutes,omitempty"`
+	// Services is how many recent auto-detected services to adapt to (default 8)
+	Services int `yaml:"services,omitempty"`
+}
+
+// SearchRange returns the configured start search range in seconds
+func (c SearchRangeConfig) SearchRange() detection.SearchRange {
+	return detection.SearchRange{StartSeconds: c.StartMinutes * 60, EndSeconds: c.EndMinutes * 60}
+}
+
+// End search defaults, in minutes
+const (
+	defaultEndOffsetMinutes = 20
+	defaultEndSearchMinutes = 90
+)
+
+// EndSearchRange is where the end of a service starting at startSeconds is
+// looked for: from amen_start_offset_minutes after the start (default 20),
+// for amen_search_duration_minutes (default 90)
+func (c SearchRangeConfig) EndSearchRange(startSeconds int) detection.SearchRange {
+	offset, length := c.AmenStartOffsetMinutes, c.AmenSearchDurationMinutes
+	if offset == 0 {
+		offset = defaultEndOffsetMinutes
+	}
+	if length == 0 {
+		length = defaultEndSearchMinutes
+	}
+	from := startSeconds + offset*60
+	return detection.SearchRange{StartSeconds: from, EndSeconds: from + length*60}
+}
+
+// AdaptedSearchRange narrows the configured range to the starts of recent
+// auto-detected services in entries. It returns false when adaptation is
+// disabled or there is too little history.
+func (c SearchRangeConfig) AdaptedSearchRange(entries []history.Entry) (detection.SearchRange, bool) {
+	if c.Adaptive.Disabled {
+		return c.SearchRange(), false
+	}
+	services := c.Adaptive.Services
+	if services == 0 {
+		services = history.DefaultStartSamples
+	}
+	return detection.AdaptSearchRange(c.SearchRange(), history.DetectedStarts(entries, services), c.Adaptive.MarginMinutes*60)
 }
 
 // SendersConfig contains sender configuration with default sender
@@ -59,24 +483,270 @@ type MinisterConfig struct {
 	Name string `yaml:"name"`
 }
 
+// DefaultStateDirectory is where unfinished runs are kept when
+// paths.state_directory is not set
+const DefaultStateDirectory = ".nac-state"
+
 // PathsConfig contains directory paths for media processing
 type PathsConfig struct {
-	SourceDirectory  string `yaml:"source_directory"`
-	TrimmedDirectory string `yaml:"trimmed_directory"`
-	AudioDirectory   string `yaml:"audio_directory"`
+	SourceDirectory string `yaml:"source_directory,omitempty"`
+	// SourceDirectories lists recording folders in priority order, such as
+	// the OBS folder then a capture card's; use instead of SourceDirectory
+	SourceDirectories []string `yaml:"source_directories,omitempty"`
+	TrimmedDirectory  string   `yaml:"trimmed_directory"`
+	AudioDirectory    string   `yaml:"audio_directory"`
+	// InProgress is what to do when the newest source is still being recorded:
+	// "error" (default), "skip" to use the newest finished file, or "wait"
+	InProgress string `yaml:"in_progress,omitempty"`
+	// WorkspaceDirectory holds per-run scratch folders (default: system temp dir)
+	WorkspaceDirectory string `yaml:"workspace_directory,omitempty"`
+	// StateDirectory keeps the progress of unfinished process runs, so
+	// process --resume can pick up after a failure (default .nac-state)
+	StateDirectory string `yaml:"state_directory,omitempty"`
+}
+
+// Sources returns the source directories in priority order
+func (p PathsConfig) Sources() []string {
+	if len(p.SourceDirectories) > 0 {
+		return p.SourceDirectories
+	}
+	if p.SourceDirectory != "" {
+		return []string{p.SourceDirectory}
+	}
+	return nil
 }
 
 // AudioConfig contains audio extraction settings
 type AudioConfig struct {
-	Bitrate string `yaml:"bitrate"`
+	Bitrate Bitrate `yaml:"bitrate"`
+	// Bitrates makes an MP3 at each bitrate, e.g. [192k, 64k] for a small copy
+	// to forward on WhatsApp. The first is the main MP3 and replaces bitrate;
+	// the others are named YYYY-MM-DD-<bitrate>.mp3.
+	Bitrates []Bitrate `yaml:"bitrates,omitempty"`
+	// Track is the 1-based audio stream to use when sources have several (default first)
+	Track int `yaml:"track,omitempty"`
+	// StreamUpload pipes audio-only extraction straight into the Drive upload
+	StreamUpload bool `yaml:"stream_upload,omitempty"`
+	// StreamKeepLocal also saves a streamed MP3 to audio_directory
+	StreamKeepLocal bool `yaml:"stream_keep_local,omitempty"`
+}
+
+// ExtraBitrates returns the bitrates of the extra MP3s made besides the main one
+func (c AudioConfig) ExtraBitrates() []string {
+	if len(c.Bitrates) < 2 {
+		return nil
+	}
+	return Bitrates(c.Bitrates[1:])
+}
+
+// VideoConfig describes what a correctly recorded service looks like, checked
+// with ffprobe before trimming so a misconfigured OBS output is caught early
+type VideoConfig struct {
+	// Width and Height are the expected frame size, e.g. 1920 and 1080
+	// (default: any size)
+	Width  int `yaml:"width,omitempty"`
+	Height int `yaml:"height,omitempty"`
+	// Aspect is the expected display aspect ratio (default 16:9); "any"
+	// skips the check
+	Aspect string `yaml:"aspect,omitempty"`
+	// Strict stops processing on a mismatch instead of warning
+	Strict bool `yaml:"strict,omitempty"`
+	// Watermark burns text such as the service date into the trimmed video
+	Watermark WatermarkConfig `yaml:"watermark,omitempty"`
+	// FFmpegPath is the ffmpeg to run (default: the project's ffmpeg folder,
+	// then PATH); ffprobe is taken from the same folder
+	FFmpegPath string `yaml:"ffmpeg_path,omitempty"`
+	// FFmpegMinVersion is the oldest ffmpeg accepted, e.g. "6.0" (default 4.4)
+	FFmpegMinVersion string `yaml:"ffmpeg_min_version,omitempty"`
+	// FFmpegPriority is normal (default) or low, which lowers ffmpeg's CPU
+	// and disk priority so the computer stays usable during processing
+	FFmpegPriority string `yaml:"ffmpeg_priority,omitempty"`
+}
+
+// WatermarkConfig describes the optional text drawn on the trimmed video.
+// Drawing means re-encoding, so trimming takes much longer than a copy.
+type WatermarkConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Text uses {date}, {iso_date}, {title} and {scripture} (default {date})
+	Text string `yaml:"text,omitempty"`
+	// Position is top-left, top-right, bottom-left, bottom-right (default)
+	// or bottom-center
+	Position string `yaml:"position,omitempty"`
+	FontFile string `yaml:"font_file,omitempty"` // Optional TrueType font
+	FontSize int    `yaml:"font_size,omitempty"` // Pixels (default 36)
+}
+
+// Style returns what to draw; it is the zero style, which draws nothing,
+// when watermarking is disabled
+func (w WatermarkConfig) Style() (video.WatermarkStyle, error) {
+	tmpl, err := video.ParseWatermarkTemplate(w.Text)
+	if err != nil {
+		return video.WatermarkStyle{}, err
+	}
+	position, err := video.ParseWatermarkPosition(w.Position)
+	if err != nil {
+		return video.WatermarkStyle{}, err
+	}
+	if w.FontSize < 0 {
+		return video.WatermarkStyle{}, fmt.Errorf("font_size must not be negative, got %d", w.FontSize)
+	}
+	if !w.Enabled {
+		return video.WatermarkStyle{}, nil
+	}
+	return video.WatermarkStyle{Template: tmpl, Position: position, FontFile: w.FontFile, FontSize: w.FontSize}, nil
+}
+
+// Default OAuth token file names, kept in google.token_dir
+const (
+	DefaultDriveTokenFile   = "drive_token.json"
+	DefaultGmailTokenFile   = "gmail_token.json"
+	DefaultYouTubeTokenFile = "youtube_token.json"
+)
+
+// TokenDirUser is the google.token_dir value for the per-user config directory
+const TokenDirUser = "user"
+
+// tokenDirectory returns the directory token file names are relative to,
+// empty for the working directory
+func (c GoogleConfig) tokenDirectory() (string, error) {
+	if c.TokenDir == TokenDirUser {
+		return UserConfigDir()
+	}
+	return c.TokenDir, nil
+}
+
+// tokenPath places a token file name without a directory in dir, or the
+// working directory when dir is empty, and makes it absolute
+func tokenPath(dir, name, defaultName string) string {
+	if name == "" {
+		name = defaultName
+	}
+	if dir != "" && !filepath.IsAbs(name) && filesystem.DetectPathStyle(name) != filesystem.PathStyleWindows {
+		name = filepath.Join(dir, name)
+	}
+	return toAbsPath(name)
+}
+
+// Expectation returns the geometry recordings are checked against
+func (v VideoConfig) Expectation() (video.GeometryExpectation, error) {
+	return video.NewGeometryExpectation(v.Width, v.Height, v.Aspect)
 }
 
 // GoogleConfig contains Google API settings
 type GoogleConfig struct {
-	CredentialsFile  string `yaml:"credentials_file"`
-	TokenFile        string `yaml:"token_file"`
+	CredentialsFile string `yaml:"credentials_file"`
+	// DriveTokenFile, GmailTokenFile and YouTubeTokenFile hold the saved OAuth
+	// tokens (default drive_token.json, gmail_token.json and youtube_token.json
+	// in TokenDir)
+	DriveTokenFile   string `yaml:"drive_token_file,omitempty"`
 	GmailTokenFile   string `yaml:"gmail_token_file"`
+	YouTubeTokenFile string `yaml:"youtube_token_file,omitempty"`
+	// TokenFile is the older name for DriveTokenFile; Load moves it there
+	TokenFile string `yaml:"token_file,omitempty"`
+	// TokenDir is where token files without a directory are kept: the
+	// working directory (default), a path, or "user" for the per-user config
+	// directory, so each operator's tokens stay in their own profile
+	TokenDir         string `yaml:"token_dir,omitempty"`
 	ServicesFolderID string `yaml:"services_folder_id"`
+	// ProcessedCheck selects how already-processed services are detected:
+	// "metadata" (default) or "name"
+	ProcessedCheck string `yaml:"processed_check,omitempty"`
+	// CleanupConcurrency is how many old files are deleted at once when
+	// freeing Drive space (default 4)
+	CleanupConcurrency int `yaml:"cleanup_concurrency,omitempty"`
+	// ScopeMode is "full" (default), asking for access to all of Drive, or
+	// "file", asking only for the files this app creates
+	ScopeMode string `yaml:"scope_mode,omitempty"`
+	// UploadChunkRetries is how many times a failed upload chunk is re-sent
+	// before the upload gives up (default 5)
+	UploadChunkRetries int `yaml:"upload_chunk_retries,omitempty"`
+	// KeepRevisions is how many replaced versions of a file stay pinned in
+	// Drive for rollback (default 3)
+	KeepRevisions int `yaml:"keep_revisions,omitempty"`
+}
+
+// YouTubeConfig sets how service videos are put on YouTube. Uploading uses
+// the Google OAuth client, signed in to the channel's account.
+type YouTubeConfig struct {
+	// Target is where process and upload send videos without --target:
+	// drive (default), youtube or both
+	Target string `yaml:"target,omitempty"`
+	// Privacy of uploaded videos: private, unlisted (default) or public
+	Privacy string `yaml:"privacy,omitempty"`
+}
+
+// StorageConfig selects where outputs are uploaded and shared from
+type StorageConfig struct {
+	// Provider is "drive" (default) or "s3"
+	Provider string   `yaml:"provider,omitempty"`
+	S3       S3Config `yaml:"s3,omitempty"`
+}
+
+// S3Config contains settings for S3-compatible object storage such as MinIO
+type S3Config struct {
+	// Endpoint is the server URL, e.g. https://minio.example.org:9000
+	Endpoint string `yaml:"endpoint"`
+	// Region is the signing region (default us-east-1, which MinIO accepts)
+	Region string `yaml:"region,omitempty"`
+	Bucket string `yaml:"bucket"`
+	// Prefix is the key prefix outputs are stored under, e.g. "services/"
+	Prefix          string `yaml:"prefix,omitempty"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	// PublicURL links outputs at PublicURL/<key>, for a bucket that allows
+	// anonymous reads, instead of presigning each link
+	PublicURL string `yaml:"public_url,omitempty"`
+	// LinkExpiryHours is how long presigned links work (default and maximum 168)
+	LinkExpiryHours int `yaml:"link_expiry_hours,omitempty"`
+	// Quota caps the bucket, e.g. 500GB, so old videos are deleted to make
+	// room; without it the bucket is treated as unlimited
+	Quota ByteSize `yaml:"quota,omitempty"`
+}
+
+// maxLinkExpiryHours is the longest S3 allows a presigned link to work
+const maxLinkExpiryHours = 7 * 24
+
+// LinkExpiry returns how long presigned links work
+func (c S3Config) LinkExpiry() time.Duration {
+	if c.LinkExpiryHours > 0 {
+		return time.Duration(c.LinkExpiryHours) * time.Hour
+	}
+	return maxLinkExpiryHours * time.Hour
+}
+
+// QuotaBytes returns the bucket quota, or 0 when there is none
+func (c S3Config) QuotaBytes() (int64, error) {
+	return c.Quota.Bytes()
+}
+
+// validate checks the settings needed to reach the bucket
+func (c S3Config) validate() error {
+	switch {
+	case c.Endpoint == "":
+		return fmt.Errorf("endpoint is required")
+	case c.Bucket == "":
+		return fmt.Errorf("bucket is required")
+	case c.AccessKeyID == "" || c.SecretAccessKey == "":
+		return fmt.Errorf("access_key_id and secret_access_key are required")
+	case c.LinkExpiryHours < 0 || c.LinkExpiryHours > maxLinkExpiryHours:
+		return fmt.Errorf("link_expiry_hours %d must be between 1 and %d", c.LinkExpiryHours, maxLinkExpiryHours)
+	}
+	return nil
+}
+
+// UsesS3 reports whether outputs are stored in S3-compatible object storage
+// instead of Google Drive
+func (c *Config) UsesS3() bool {
+	return c.Storage.Provider == distribution.StorageProviderS3
+}
+
+// ServicesFolderURL returns the browser link to the Drive services folder,
+// or "" when there is none, as when outputs are stored in S3
+func (c *Config) ServicesFolderURL() string {
+	if c.UsesS3() || c.Google.ServicesFolderID == "" {
+		return ""
+	}
+	return distribution.FolderURL(c.Google.ServicesFolderID)
 }
 
 // EmailConfig contains email notification settings
@@ -85,6 +755,117 @@ type EmailConfig struct {
 	FromAddress string                     `yaml:"from_address"`
 	DefaultCC   []RecipientConfig          `yaml:"default_cc"`
 	Recipients  map[string]RecipientConfig `yaml:"recipients"`
+	// Subject is a template such as "{church}: Recording of {service_type} on {date}";
+	// variables are {church}, {date}, {minister}, {service_type} and {label}
+	Subject string `yaml:"subject,omitempty"`
+	// ServiceType fills {service_type} when no --service-type is given (default "Service")
+	ServiceType string `yaml:"service_type,omitempty"`
+	// CCRules add CC recipients for matching services, e.g. the rector for guest ministers
+	CCRules []CCRuleConfig `yaml:"cc_rules,omitempty"`
+	// Sandbox sends every email only to the operator with a [TEST] subject
+	Sandbox bool `yaml:"sandbox,omitempty"`
+	// OperatorAddress receives sandbox emails (defaults to from_address)
+	OperatorAddress string `yaml:"operator_address,omitempty"`
+	// Groups send their members a variant of the email, e.g. for the choir
+	Groups []RecipientGroupConfig `yaml:"groups,omitempty"`
+	// IncludeFolderLink adds a footer linking to the Drive services folder
+	IncludeFolderLink bool `yaml:"include_folder_link,omitempty"`
+	// LivestreamURL adds a "Watch the livestream recording" line linking to
+	// the church's livestream channel, e.g. https://www.youtube.com/@church/streams
+	LivestreamURL string `yaml:"livestream_url,omitempty"`
+	// SendTimeoutSeconds bounds each Gmail send (default 60)
+	SendTimeoutSeconds int `yaml:"send_timeout_seconds,omitempty"`
+	// LookupAll lets --to match default_cc entries when no recipient matches,
+	// and --sender match sender names as well as keys
+	LookupAll bool `yaml:"lookup_all,omitempty"`
+	// BCCSender blind-copies each email to from_address (default true)
+	BCCSender *bool `yaml:"bcc_sender,omitempty"`
+	// Greeting sets how the email greets its recipients
+	Greeting GreetingConfig `yaml:"greeting,omitempty"`
+}
+
+// GreetingConfig sets the style and wording of the email greeting
+type GreetingConfig struct {
+	// Style is casual ("Dear John & Jane," / "Hey Everyone!", the default)
+	// or formal ("Dear John Doe and Jane Smith," / "Dear Friends,")
+	Style string `yaml:"style,omitempty"`
+	// MaxNames is the most recipients named before the group greeting (default 2)
+	MaxNames int `yaml:"max_names,omitempty"`
+	// Salutation replaces "Dear" before the names
+	Salutation string `yaml:"salutation,omitempty"`
+	// Group replaces the style's greeting for more than max_names recipients
+	Group string `yaml:"group,omitempty"`
+}
+
+// Rules returns the greeting rules the email is rendered with
+func (g GreetingConfig) Rules() (notification.GreetingRules, error) {
+	style, err := notification.ParseGreetingStyle(g.Style)
+	if err != nil {
+		return notification.GreetingRules{}, err
+	}
+	rules := notification.GreetingRules{
+		Style:      style,
+		MaxNames:   g.MaxNames,
+		Salutation: strings.TrimSpace(g.Salutation),
+		Group:      strings.TrimSpace(g.Group),
+	}
+	return rules, rules.Validate()
+}
+
+// CopySender reports whether emails are blind-copied to the sender; never in
+// sandbox mode, from email.sandbox or a --sandbox flag, which mails only the operator
+func (c EmailConfig) CopySender(sandbox bool) bool {
+	return !sandbox && !c.Sandbox && (c.BCCSender == nil || *c.BCCSender)
+}
+
+// DefaultEmailSendTimeout is used when email.send_timeout_seconds is unset
+const DefaultEmailSendTimeout = 60 * time.Second
+
+// SendTimeout returns how long one email may take to send
+func (c EmailConfig) SendTimeout() time.Duration {
+	if c.SendTimeoutSeconds > 0 {
+		return time.Duration(c.SendTimeoutSeconds) * time.Second
+	}
+	return DefaultEmailSendTimeout
+}
+
+// EmailFolderURL returns the Drive services folder link for the email footer,
+// or "" when email.include_folder_link is off or there is no folder to link
+func (c *Config) EmailFolderURL() string {
+	if !c.Email.IncludeFolderLink {
+		return ""
+	}
+	return c.ServicesFolderURL()
+}
+
+// RecipientGroupConfig gives some recipients their own variant of the email
+type RecipientGroupConfig struct {
+	Name string `yaml:"name"`
+	// Members lists recipient keys or names from email.recipients
+	Members []string `yaml:"members"`
+	// Context is an extra paragraph after the links in the standard email
+	Context string `yaml:"context,omitempty"`
+	// PlainText and HTML replace the standard email bodies; set both or neither
+	PlainText string `yaml:"plain_text,omitempty"`
+	HTML      string `yaml:"html,omitempty"`
+}
+
+// CCRuleConfig adds the cc recipients when every condition in When holds
+type CCRuleConfig struct {
+	Name string     `yaml:"name"`
+	When CCRuleWhen `yaml:"when"`
+	// CC lists recipient keys or names from email.recipients
+	CC []string `yaml:"cc"`
+}
+
+// CCRuleWhen holds the conditions for a CC rule
+type CCRuleWhen struct {
+	// ServiceType matches any of these service types
+	ServiceType []string `yaml:"service_type,omitempty"`
+	// Minister matches any of these minister keys or names
+	Minister []string `yaml:"minister,omitempty"`
+	// GuestMinister matches ministers not listed under ministers
+	GuestMinister bool `yaml:"guest_minister,omitempty"`
 }
 
 // RecipientConfig represents an email recipient
@@ -108,22 +889,266 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if _, err := notification.ParseSubjectTemplate(cfg.Email.Subject); err != nil {
+		return nil, fmt.Errorf("invalid email.subject: %w", err)
+	}
+	if err := video.ValidateAudioTrack(cfg.Audio.Track); err != nil {
+		return nil, fmt.Errorf("invalid audio.track: %w", err)
+	}
+	if err := validateValues(&cfg); err != nil {
+		return nil, err
+	}
+	if len(cfg.Audio.Bitrates) > 0 {
+		if err := video.ValidateBitrates(Bitrates(cfg.Audio.Bitrates)); err != nil {
+			return nil, fmt.Errorf("invalid audio.bitrates: %w", err)
+		}
+		if cfg.Audio.Bitrate != "" && cfg.Audio.Bitrate != cfg.Audio.Bitrates[0] {
+			return nil, fmt.Errorf("invalid audio.bitrates: the first bitrate is the main one; remove audio.bitrate %q or list it first", cfg.Audio.Bitrate)
+		}
+		cfg.Audio.Bitrate = cfg.Audio.Bitrates[0]
+	}
+	if _, err := cfg.Video.Expectation(); err != nil {
+		return nil, fmt.Errorf("invalid video: %w", err)
+	}
+	if _, err := cfg.Video.Watermark.Style(); err != nil {
+		return nil, fmt.Errorf("invalid video.watermark: %w", err)
+	}
+	if cfg.Video.FFmpegMinVersion == "" {
+		cfg.Video.FFmpegMinVersion = ffmpeg.DefaultMinVersion
+	}
+	if _, err := ffmpeg.ParseVersion(cfg.Video.FFmpegMinVersion); err != nil {
+		return nil, fmt.Errorf("invalid video.ffmpeg_min_version: %w", err)
+	}
+	priority, err := ffmpeg.ParsePriority(cfg.Video.FFmpegPriority)
+	if err != nil {
+		return nil, fmt.Errorf("invalid video.ffmpeg_priority: %w", err)
+	}
+	cfg.Video.FFmpegPriority = string(priority)
+	if _, err := cfg.Email.Greeting.Rules(); err != nil {
+		return nil, fmt.Errorf("invalid email.greeting: %w", err)
+	}
+	if _, err := NewRecipientLookup(&cfg, path).CCRules(); err != nil {
+		return nil, fmt.Errorf("invalid email.cc_rules: %w", err)
+	}
+	if _, err := NewRecipientLookup(&cfg, path).Groups(); err != nil {
+		return nil, fmt.Errorf("invalid email.groups: %w", err)
+	}
+	if cfg.Paths.SourceDirectory != "" && len(cfg.Paths.SourceDirectories) > 0 {
+		return nil, fmt.Errorf("invalid paths: set source_directory or source_directories, not both")
+	}
+	for i, dir := range cfg.Paths.SourceDirectories {
+		if strings.TrimSpace(dir) == "" {
+			return nil, fmt.Errorf("invalid paths.source_directories: entry %d is empty", i+1)
+		}
+	}
+	if _, err := filesystem.ParseInProgressPolicy(cfg.Paths.InProgress); err != nil {
+		return nil, fmt.Errorf("invalid paths.in_progress: %w", err)
+	}
+	if _, err := cfg.Archive.Policy(); err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	if _, err := summary.ParseFormats(cfg.Summary.Formats); err != nil {
+		return nil, fmt.Errorf("invalid summary.formats: %w", err)
+	}
+	if cfg.Network.ProxyURL != "" {
+		if _, err := network.ParseProxyURL(cfg.Network.ProxyURL); err != nil {
+			return nil, fmt.Errorf("invalid network.proxy_url: %w", err)
+		}
+	}
+	target, err := distribution.ParseTarget(cfg.YouTube.Target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid youtube.target: %w", err)
+	}
+	privacy, err := distribution.ParsePrivacy(cfg.YouTube.Privacy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid youtube.privacy: %w", err)
+	}
+	cfg.YouTube.Target, cfg.YouTube.Privacy = string(target), string(privacy)
+	if cfg.Network.RateLimit < 0 {
+		return nil, fmt.Errorf("invalid network.rate_limit: %g must not be negative", cfg.Network.RateLimit)
+	}
+	if _, err := cfg.Locale.Calendar(); err != nil {
+		return nil, fmt.Errorf("invalid locale: %w", err)
+	}
+	if cfg.Budget.WeeklyGB < 0 {
+		return nil, fmt.Errorf("invalid upload_budget.weekly_gb: %g must not be negative", cfg.Budget.WeeklyGB)
+	}
+	if cfg.Budget.OnExceed, err = history.ParseBudgetAction(cfg.Budget.OnExceed); err != nil {
+		return nil, fmt.Errorf("invalid upload_budget.on_exceed: %w", err)
+	}
+	if cfg.Locale.MaxClockDriftHours < 0 {
+		return nil, fmt.Errorf("invalid locale.max_clock_drift_hours: %d must not be negative", cfg.Locale.MaxClockDriftHours)
+	}
+	if cfg.Email.SendTimeoutSeconds < 0 {
+		return nil, fmt.Errorf("invalid email.send_timeout_seconds: %d must not be negative", cfg.Email.SendTimeoutSeconds)
+	}
+	if cfg.API.Listen != "" {
+		if _, _, err := net.SplitHostPort(cfg.API.Listen); err != nil {
+			return nil, fmt.Errorf("invalid api.listen: %w", err)
+		}
+	}
+	if err := cfg.Defaults.validate(); err != nil {
+		return nil, fmt.Errorf("invalid defaults: %w", err)
+	}
+	if f := cfg.Funeral; f.RevokeAfterDays < 0 || f.RetentionDays < 0 {
+		return nil, fmt.Errorf("invalid funeral: revoke_after_days and retention_days must not be negative")
+	}
+	if name := cfg.YearArchive.FolderName; strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid year_archive.folder_name: %q must be a folder name, not a path", name)
+	}
+	if u := cfg.Email.LivestreamURL; u != "" && !isWebURL(u) {
+		return nil, fmt.Errorf("invalid email.livestream_url: %q must be an http or https link", u)
+	}
+	if s := cfg.Detection.Thresholds.EarlyExitScore; s > 1 {
+		return nil, fmt.Errorf("invalid detection.thresholds.early_exit_score: %v must not be above 1", s)
+	}
+	if t := cfg.Detection.Drift.Threshold; t < 0 || t > 1 {
+		return nil, fmt.Errorf("invalid detection.drift.threshold: %v must be between 0 and 1", t)
+	}
+	if cfg.Detection.Drift.Weeks < 0 {
+		return nil, fmt.Errorf("invalid detection.drift.weeks: %d must not be negative", cfg.Detection.Drift.Weeks)
+	}
+	if a := cfg.Detection.SearchRange.Adaptive; a.MarginMinutes < 0 || a.Services < 0 {
+		return nil, fmt.Errorf("invalid detection.search_range.adaptive: margin_minutes and services must not be negative")
+	}
+	endMethod, err := detection.ParseEndMethod(cfg.Detection.End.Method)
+	if err != nil {
+		return nil, fmt.Errorf("invalid detection.end.method: %w", err)
+	}
+	cfg.Detection.End.Method = string(endMethod)
+	if e := cfg.Detection.End; e.CrossStepSeconds < 0 || e.SilenceSeconds < 0 {
+		return nil, fmt.Errorf("invalid detection.end: cross_step_seconds and silence_seconds must not be negative")
+	}
+	if db := cfg.Detection.End.SilenceNoiseDB; db > 0 {
+		return nil, fmt.Errorf("invalid detection.end.silence_noise_db: %d must be below 0 (decibels below full scale)", db)
+	}
+	switch cfg.Storage.Provider {
+	case "", distribution.StorageProviderDrive:
+	case distribution.StorageProviderS3:
+		if err := cfg.Storage.S3.validate(); err != nil {
+			return nil, fmt.Errorf("invalid storage.s3: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid storage.provider: %q must be drive or s3", cfg.Storage.Provider)
+	}
+	if cfg.Google.ProcessedCheck, err = distribution.ParseProcessedCheck(cfg.Google.ProcessedCheck); err != nil {
+		return nil, fmt.Errorf("invalid google.processed_check: %w", err)
+	}
+	if err := drive.ValidateScopeMode(cfg.Google.ScopeMode); err != nil {
+		return nil, fmt.Errorf("invalid google.scope_mode: %w", err)
+	}
+	if cfg.Google.CleanupConcurrency < 0 {
+		return nil, fmt.Errorf("invalid google.cleanup_concurrency: %d must not be negative", cfg.Google.CleanupConcurrency)
+	}
+	if cfg.Scan.TimeoutSeconds < 0 {
+		return nil, fmt.Errorf("invalid scan.timeout_seconds: %d must not be negative", cfg.Scan.TimeoutSeconds)
+	}
+	if len(cfg.Scan.Command) > 0 && strings.TrimSpace(cfg.Scan.Command[0]) == "" {
+		return nil, fmt.Errorf("invalid scan.command: the first entry must be the program to run")
+	}
+	if cfg.Google.UploadChunkRetries < 0 {
+		return nil, fmt.Errorf("invalid google.upload_chunk_retries: %d must not be negative", cfg.Google.UploadChunkRetries)
+	}
+	if cfg.Google.KeepRevisions < 0 {
+		return nil, fmt.Errorf("invalid google.keep_revisions: %d must not be negative", cfg.Google.KeepRevisions)
+	}
+
+	if cfg.Google.TokenFile != "" {
+		if cfg.Google.DriveTokenFile != "" && cfg.Google.DriveTokenFile != cfg.Google.TokenFile {
+			return nil, fmt.Errorf("invalid google.token_file: set drive_token_file or its older name token_file, not both")
+		}
+		cfg.Google.DriveTokenFile, cfg.Google.TokenFile = cfg.Google.TokenFile, ""
+	}
+
+	// Windows and WSL paths are converted before relative ones are resolved
+	normalizePaths(&cfg, infrafs.DetectPlatform())
+
 	// Convert relative paths to absolute so tokens are always found
 	cfg.Google.CredentialsFile = toAbsPath(cfg.Google.CredentialsFile)
-	cfg.Google.TokenFile = toAbsPath(cfg.Google.TokenFile)
-	if cfg.Google.GmailTokenFile == "" {
-		cfg.Google.GmailTokenFile = toAbsPath("gmail_token.json")
-	} else {
-		cfg.Google.GmailTokenFile = toAbsPath(cfg.Google.GmailTokenFile)
+	tokenDir, err := cfg.Google.tokenDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("invalid google.token_dir: %w", err)
+	}
+	cfg.Google.DriveTokenFile = tokenPath(tokenDir, cfg.Google.DriveTokenFile, DefaultDriveTokenFile)
+	cfg.Google.GmailTokenFile = tokenPath(tokenDir, cfg.Google.GmailTokenFile, DefaultGmailTokenFile)
+	cfg.Google.YouTubeTokenFile = tokenPath(tokenDir, cfg.Google.YouTubeTokenFile, DefaultYouTubeTokenFile)
+	if cfg.History.File == "" {
+		cfg.History.File = DefaultHistoryFile
 	}
+	cfg.History.File = toAbsPath(cfg.History.File)
+	if cfg.Audit.File == "" {
+		cfg.Audit.File = DefaultAuditFile
+	}
+	cfg.Audit.File = toAbsPath(cfg.Audit.File)
+	if cfg.API.Listen == "" {
+		cfg.API.Listen = DefaultAPIListen
+	}
+	if cfg.API.TokenFile == "" {
+		cfg.API.TokenFile = DefaultAPITokenFile
+	}
+	cfg.API.TokenFile = toAbsPath(cfg.API.TokenFile)
+	cfg.Summary.Dir = toAbsPath(cfg.Summary.Dir)
+	cfg.Video.Watermark.FontFile = toAbsPath(cfg.Video.Watermark.FontFile)
+	cfg.Network.CABundle = toAbsPath(cfg.Network.CABundle)
+	cfg.Paths.WorkspaceDirectory = toAbsPath(cfg.Paths.WorkspaceDirectory)
+	if cfg.Paths.StateDirectory == "" {
+		cfg.Paths.StateDirectory = DefaultStateDirectory
+	}
+	cfg.Paths.StateDirectory = toAbsPath(cfg.Paths.StateDirectory)
 
 	return &cfg, nil
 }
 
+// PathSetting is a configured local path and its YAML key
+type PathSetting struct {
+	Key   string
+	Value *string
+	// Created is set when the tool makes the file or folder itself, so it
+	// need not exist yet
+	Created bool
+}
+
+// PathSettings returns every configured local path, including empty ones
+func (c *Config) PathSettings() []PathSetting {
+	settings := []PathSetting{{Key: "paths.source_directory", Value: &c.Paths.SourceDirectory}}
+	for i := range c.Paths.SourceDirectories {
+		key := fmt.Sprintf("paths.source_directories[%d]", i+1)
+		settings = append(settings, PathSetting{Key: key, Value: &c.Paths.SourceDirectories[i]})
+	}
+	return append(settings,
+		PathSetting{Key: "paths.trimmed_directory", Value: &c.Paths.TrimmedDirectory},
+		PathSetting{Key: "paths.audio_directory", Value: &c.Paths.AudioDirectory, Created: true},
+		PathSetting{Key: "paths.workspace_directory", Value: &c.Paths.WorkspaceDirectory, Created: true},
+		PathSetting{Key: "paths.state_directory", Value: &c.Paths.StateDirectory, Created: true},
+		PathSetting{Key: "archive.directory", Value: &c.Archive.Directory, Created: true},
+		PathSetting{Key: "summary.dir", Value: &c.Summary.Dir, Created: true},
+		PathSetting{Key: "history.file", Value: &c.History.File, Created: true},
+		PathSetting{Key: "audit.file", Value: &c.Audit.File, Created: true},
+		PathSetting{Key: "api.token_file", Value: &c.API.TokenFile, Created: true},
+		PathSetting{Key: "google.credentials_file", Value: &c.Google.CredentialsFile},
+		PathSetting{Key: "google.token_dir", Value: &c.Google.TokenDir, Created: true},
+		PathSetting{Key: "google.drive_token_file", Value: &c.Google.DriveTokenFile, Created: true},
+		PathSetting{Key: "google.gmail_token_file", Value: &c.Google.GmailTokenFile, Created: true},
+		PathSetting{Key: "detection.templates_dir", Value: &c.Detection.TemplatesDir},
+		PathSetting{Key: "detection.audio_templates_dir", Value: &c.Detection.AudioTemplatesDir},
+		PathSetting{Key: "video.watermark.font_file", Value: &c.Video.Watermark.FontFile},
+		PathSetting{Key: "network.ca_bundle", Value: &c.Network.CABundle},
+	)
+}
+
+// normalizePaths rewrites configured paths in the style platform opens, so
+// one config can name C:\Users\... or /mnt/c/Users/... on either side
+func normalizePaths(cfg *Config, platform filesystem.Platform) {
+	for _, setting := range cfg.PathSettings() {
+		*setting.Value = filesystem.NormalizePath(*setting.Value, platform)
+	}
+}
+
 // toAbsPath converts a relative path to absolute using the current working directory.
-// Already-absolute paths are returned unchanged. Empty paths are returned as-is.
+// Already-absolute paths are returned unchanged. Empty paths are returned as-is, as
+// are Windows paths this system cannot open, so doctor can name them.
 func toAbsPath(path string) string {
-	if path == "" || filepath.IsAbs(path) {
+	if path == "" || filepath.IsAbs(path) || filesystem.DetectPathStyle(path) == filesystem.PathStyleWindows {
 		return path
 	}
 	abs, err := filepath.Abs(path)
@@ -146,3 +1171,9 @@ func Save(cfg *Config, path string) error {
 
 	return nil
 }
+
+// isWebURL reports whether s is an absolute http or https URL
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,323 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/detection"
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/filesystem"
+	"nac-service-media/domain/history"
+	"nac-service-media/domain/notification"
+	"nac-service-media/domain/policy"
+	"nac-service-media/infrastructure/ffmpeg"
+)
+
+func TestNormalizePaths_WSL(t *testing.T) {
+	cfg := &Config{
+		Paths: PathsConfig{
+			SourceDirectories: []string{`C:\Users\church\Videos`, "/mnt/d/Capture"},
+			TrimmedDirectory:  `C:\Users\church\Videos\Trimmed`,
+			AudioDirectory:    "/home/church/audio",
+		},
+		Google: GoogleConfig{CredentialsFile: `D:\keys\credentials.json`},
+	}
+
+	normalizePaths(cfg, filesystem.PlatformWSL)
+
+	tests := []struct{ got, want string }{
+		{cfg.Paths.SourceDirectories[0], "/mnt/c/Users/church/Videos"},
+		{cfg.Paths.SourceDirectories[1], "/mnt/d/Capture"},
+		{cfg.Paths.TrimmedDirectory, "/mnt/c/Users/church/Videos/Trimmed"},
+		{cfg.Paths.AudioDirectory, "/home/church/audio"},
+		{cfg.Google.CredentialsFile, "/mnt/d/keys/credentials.json"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePaths_Windows(t *testing.T) {
+	cfg := &Config{Paths: PathsConfig{SourceDirectory: "/mnt/c/Users/church/Videos"}}
+
+	normalizePaths(cfg, filesystem.PlatformWindows)
+
+	if cfg.Paths.SourceDirectory != `C:\Users\church\Videos` {
+		t.Errorf("got %q", cfg.Paths.SourceDirectory)
+	}
+}
+
+func TestToAbsPath_LeavesWindowsPathsAlone(t *testing.T) {
+	if got := toAbsPath(`C:\Users\church\Videos`); got != `C:\Users\church\Videos` {
+		t.Errorf("toAbsPath() = %q, want the Windows path unchanged", got)
+	}
+}
+
+func TestGreetingConfig_Rules(t *testing.T) {
+	rules, err := GreetingConfig{Style: "Formal", MaxNames: 3, Group: " Dear Friends in Christ, "}.Rules()
+	if err != nil {
+		t.Fatalf("Rules() unexpected error: %v", err)
+	}
+	if rules.Style != notification.GreetingFormal || rules.MaxNames != 3 || rules.Group != "Dear Friends in Christ," {
+		t.Errorf("Rules() = %+v", rules)
+	}
+
+	if _, err := (GreetingConfig{Style: "chatty"}).Rules(); err == nil {
+		t.Error("expected an error for an unknown style")
+	}
+	if _, err := (GreetingConfig{MaxNames: -2}).Rules(); err == nil {
+		t.Error("expected an error for negative max_names")
+	}
+}
+
+func TestDefaultsConfig_MinisterFor(t *testing.T) {
+	defaults := DefaultsConfig{
+		Minister:         "smith",
+		MinisterSchedule: map[string]string{"2026-10-25": "jones"},
+	}
+	if got := defaults.MinisterFor(time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)); got != "jones" {
+		t.Errorf("scheduled date: got %q, want jones", got)
+	}
+	if got := defaults.MinisterFor(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)); got != "smith" {
+		t.Errorf("unscheduled date: got %q, want the fallback smith", got)
+	}
+}
+
+func TestDefaultsConfig_Validate(t *testing.T) {
+	if err := (DefaultsConfig{ServiceLength: "01:40:00", MinisterSchedule: map[string]string{"2026-10-25": "jones"}}).validate(); err != nil {
+		t.Errorf("validate() unexpected error: %v", err)
+	}
+	if err := (DefaultsConfig{ServiceLength: "100 minutes"}).validate(); err == nil {
+		t.Error("expected an error for a service_length that is not HH:MM:SS")
+	}
+	if err := (DefaultsConfig{MinisterSchedule: map[string]string{"10/25/2026": "jones"}}).validate(); err == nil {
+		t.Error("expected an error for a schedule date that is not YYYY-MM-DD")
+	}
+}
+
+func TestLoad_TokenFiles(t *testing.T) {
+	dir := t.TempDir()
+	load := func(google string) (*Config, error) {
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte("google:\n"+google), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return Load(path)
+	}
+
+	tokens := filepath.Join(dir, "tokens")
+	cfg, err := load("  token_file: drive.json\n  token_dir: " + tokens + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Google.DriveTokenFile != filepath.Join(tokens, "drive.json") || cfg.Google.TokenFile != "" {
+		t.Errorf("token_file should move to drive_token_file in token_dir, got %q and %q", cfg.Google.DriveTokenFile, cfg.Google.TokenFile)
+	}
+	if cfg.Google.GmailTokenFile != filepath.Join(tokens, DefaultGmailTokenFile) {
+		t.Errorf("gmail token = %q, want the default name in token_dir", cfg.Google.GmailTokenFile)
+	}
+
+	absolute := filepath.Join(dir, "elsewhere", "gmail.json")
+	cfg, err = load("  gmail_token_file: " + absolute + "\n  token_dir: " + tokens + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Google.GmailTokenFile != absolute {
+		t.Errorf("an absolute token file should stay put, got %q", cfg.Google.GmailTokenFile)
+	}
+	if cfg.Google.DriveTokenFile != filepath.Join(tokens, DefaultDriveTokenFile) {
+		t.Errorf("drive token = %q, want the default name in token_dir", cfg.Google.DriveTokenFile)
+	}
+
+	if _, err := load("  token_file: a.json\n  drive_token_file: b.json\n"); err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("expected an error for both token_file and drive_token_file, got %v", err)
+	}
+}
+
+func TestLoad_YearArchiveFolderName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("year_archive:\n  folder_name: Archive/Old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "year_archive.folder_name") {
+		t.Errorf("expected an error for a folder_name with a slash, got %v", err)
+	}
+}
+
+func TestLoad_FFmpegMinVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("video:\n  width: 1920\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Video.FFmpegMinVersion != ffmpeg.DefaultMinVersion {
+		t.Errorf("ffmpeg_min_version = %q, want the default %s", cfg.Video.FFmpegMinVersion, ffmpeg.DefaultMinVersion)
+	}
+
+	if err := os.WriteFile(path, []byte("video:\n  ffmpeg_min_version: latest\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "video.ffmpeg_min_version") {
+		t.Errorf("expected an error for a version with no number, got %v", err)
+	}
+}
+
+func TestLoad_YouTube(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("youtube:\n  target: Both\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.YouTube.Target != "both" || cfg.YouTube.Privacy != "unlisted" {
+		t.Errorf("youtube = %+v, want target both and the default unlisted privacy", cfg.YouTube)
+	}
+	if filepath.Base(cfg.Google.YouTubeTokenFile) != DefaultYouTubeTokenFile {
+		t.Errorf("youtube token = %q, want the default name", cfg.Google.YouTubeTokenFile)
+	}
+
+	for yaml, field := range map[string]string{
+		"youtube:\n  target: vimeo\n":    "youtube.target",
+		"youtube:\n  privacy: friends\n": "youtube.privacy",
+	} {
+		if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), field) {
+			t.Errorf("expected an error naming %s, got %v", field, err)
+		}
+	}
+}
+
+func TestLoad_ProcessedCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("google:\n  processed_check: Name\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Google.ProcessedCheck != distribution.ProcessedCheckName {
+		t.Errorf("processed_check = %q, want name", cfg.Google.ProcessedCheck)
+	}
+
+	if err := os.WriteFile(path, []byte("google:\n  processed_check: metdata\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "google.processed_check") {
+		t.Errorf("expected an error naming google.processed_check, got %v", err)
+	}
+}
+
+func TestLoad_FFmpegPriority(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("video:\n  ffmpeg_priority: Low\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Video.FFmpegPriority != "low" {
+		t.Errorf("ffmpeg priority = %q, want low", cfg.Video.FFmpegPriority)
+	}
+
+	if err := os.WriteFile(path, []byte("video:\n  ffmpeg_priority: realtime\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "video.ffmpeg_priority") {
+		t.Errorf("expected an error naming video.ffmpeg_priority, got %v", err)
+	}
+}
+
+func TestLoad_EndDetection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("detection:\n  end:\n    method: Silence\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Detection.EndMethod() != detection.EndBySilence {
+		t.Errorf("end method = %q, want silence", cfg.Detection.EndMethod())
+	}
+	if got := cfg.Detection.SearchRange.EndSearchRange(300); got.StartSeconds != 1500 || got.EndSeconds != 6900 {
+		t.Errorf("EndSearchRange(300) = %+v, want 20 minutes after the start for 90 minutes", got)
+	}
+
+	for yaml, field := range map[string]string{
+		"detection:\n  end:\n    method: applause\n":     "detection.end.method",
+		"detection:\n  end:\n    silence_seconds: -5\n":  "detection.end",
+		"detection:\n  end:\n    silence_noise_db: 40\n": "detection.end.silence_noise_db",
+	} {
+		if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), field) {
+			t.Errorf("expected an error naming %s, got %v", field, err)
+		}
+	}
+}
+
+func TestLoad_Funeral(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("funeral:\n  revoke_after_days: 14\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := cfg.ServicePolicy("funeral")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.RevokeAfterDays != 14 || p.RetentionDays != policy.DefaultFuneralRetentionDays {
+		t.Errorf("funeral policy = %+v, want access for 14 days and the default retention", p)
+	}
+	if _, err := cfg.ServicePolicy("wedding"); !errors.Is(err, policy.ErrUnknownType) {
+		t.Errorf("ServicePolicy(wedding) error = %v, want ErrUnknownType", err)
+	}
+
+	if err := os.WriteFile(path, []byte("funeral:\n  retention_days: -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "funeral") {
+		t.Errorf("expected an error naming funeral, got %v", err)
+	}
+}
+
+func TestSearchRangeConfig_AdaptedSearchRange(t *testing.T) {
+	var entries []history.Entry
+	for i, start := range []string{"00:07:00", "00:09:30", "00:12:00", "00:10:00"} {
+		entries = append(entries, history.Entry{
+			ServiceDate:         time.Date(2025, 12, 7+7*i, 0, 0, 0, 0, time.UTC),
+			StartTime:           start,
+			DetectionConfidence: 0.9,
+		})
+	}
+	cfg := SearchRangeConfig{StartMinutes: 0, EndMinutes: 30, Adaptive: AdaptiveSearchConfig{MarginMinutes: 1}}
+
+	got, ok := cfg.AdaptedSearchRange(entries)
+	if !ok || got.String() != "06:00-13:00" {
+		t.Errorf("AdaptedSearchRange() = %v, %v; want 06:00-13:00", got, ok)
+	}
+
+	cfg.Adaptive.Disabled = true
+	if got, ok := cfg.AdaptedSearchRange(entries); ok || got.String() != "00:00-30:00" {
+		t.Errorf("disabled: AdaptedSearchRange() = %v, %v; want the configured range", got, ok)
+	}
+}
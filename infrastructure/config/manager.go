@@ -12,6 +12,7 @@ var (
 	ErrRecipientNotFound = errors.New("recipient not found")
 	ErrCCNotFound        = errors.New("cc not found")
 	ErrSenderNotFound    = errors.New("sender not found")
+	ErrNoDefaultSender   = errors.New("no default sender configured")
 	ErrDuplicateKey      = errors.New("key already exists")
 	ErrInvalidEmail      = errors.New("invalid email format")
 )
@@ -400,12 +401,41 @@ func (m *ConfigManager) GetSender(key string) (Sender, error) {
 	return Sender{}, fmt.Errorf("%w: %q", ErrSenderNotFound, key)
 }
 
-// GetDefaultSender gets the default sender
+// Where the sender used without --sender comes from
+const (
+	SenderFromUserFile = "user file"
+	SenderFromLogin    = "login name"
+	SenderFromConfig   = "senders.default_sender"
+)
+
+// GetDefaultSender gets the sender used without --sender; see DefaultSender
 func (m *ConfigManager) GetDefaultSender() (Sender, error) {
+	sender, _, err := m.DefaultSender()
+	return sender, err
+}
+
+// DefaultSender returns the sender used without --sender and where it came
+// from: the sender in this machine's user file, then a sender whose key is
+// the operator's login name, then senders.default_sender
+func (m *ConfigManager) DefaultSender() (Sender, string, error) {
+	user := m.config.User
+	if user.Sender != "" {
+		sender, err := m.GetSender(user.Sender)
+		if err != nil {
+			return Sender{}, "", fmt.Errorf("%s names a sender that is not in config: %w", user.Path, err)
+		}
+		return sender, SenderFromUserFile, nil
+	}
+	if user.Login != "" {
+		if sender, err := m.GetSender(user.Login); err == nil {
+			return sender, SenderFromLogin, nil
+		}
+	}
 	if m.config.Senders.DefaultSender == "" {
-		return Sender{}, fmt.Errorf("no default sender configured")
+		return Sender{}, "", ErrNoDefaultSender
 	}
-	return m.GetSender(m.config.Senders.DefaultSender)
+	sender, err := m.GetSender(m.config.Senders.DefaultSender)
+	return sender, SenderFromConfig, err
 }
 
 // RemoveSender removes a sender by key
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/mail"
+	"sort"
 	"strings"
 
 	"nac-service-media/domain/notification"
@@ -22,7 +25,8 @@ func NewRecipientLookup(cfg *Config, configPath string) *RecipientLookup {
 }
 
 // LookupRecipient finds recipients matching the query (first name, last name, full name, or key)
-// Returns all matches - caller should handle ambiguity
+// Returns all matches - caller should handle ambiguity. With email.lookup_all,
+// default CCs are searched when no recipient matches.
 func (r *RecipientLookup) LookupRecipient(query string) ([]notification.Recipient, error) {
 	query = strings.ToLower(strings.TrimSpace(query))
 	if query == "" {
@@ -32,20 +36,7 @@ func (r *RecipientLookup) LookupRecipient(query string) ([]notification.Recipien
 	var matches []notification.Recipient
 
 	for key, rc := range r.config.Email.Recipients {
-		keyLower := strings.ToLower(key)
-		nameLower := strings.ToLower(rc.Name)
-		nameParts := strings.Fields(nameLower)
-
-		var firstName, lastName string
-		if len(nameParts) > 0 {
-			firstName = nameParts[0]
-		}
-		if len(nameParts) > 1 {
-			lastName = nameParts[len(nameParts)-1]
-		}
-
-		// Match on: key, first name, last name, or full name
-		if keyLower == query || firstName == query || lastName == query || nameLower == query {
+		if personMatches(query, key, rc.Name) {
 			matches = append(matches, notification.Recipient{
 				Name:    rc.Name,
 				Address: rc.Address,
@@ -53,46 +44,169 @@ func (r *RecipientLookup) LookupRecipient(query string) ([]notification.Recipien
 		}
 	}
 
+	// Recipients take precedence; a CC listed twice counts once
+	if len(matches) == 0 && r.config.Email.LookupAll {
+		seen := make(map[string]bool)
+		for _, cc := range NewConfigManager(r.config, r.configPath).ListCCs() {
+			if personMatches(query, cc.Key, cc.Name) && !seen[strings.ToLower(cc.Address)] {
+				seen[strings.ToLower(cc.Address)] = true
+				matches = append(matches, notification.Recipient{Name: cc.Name, Address: cc.Address})
+			}
+		}
+	}
+
 	if len(matches) == 0 {
 		return nil, notification.ErrRecipientNotFound
 	}
 
+	// Recipients are a map; sort so ambiguous matches are reported consistently
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Name < matches[j].Name
+	})
 	return matches, nil
 }
 
+// personMatches reports whether a lowercase query is an entry's key, first
+// name, last name, or full name
+func personMatches(query, key, name string) bool {
+	nameLower := strings.ToLower(name)
+	nameParts := strings.Fields(nameLower)
+
+	var firstName, lastName string
+	if len(nameParts) > 0 {
+		firstName = nameParts[0]
+	}
+	if len(nameParts) > 1 {
+		lastName = nameParts[len(nameParts)-1]
+	}
+
+	return strings.ToLower(key) == query || firstName == query || lastName == query || nameLower == query
+}
+
+// LookupSender finds a sender by key. With email.lookup_all, a sender whose
+// first, last or full name matches is found too; a key match always wins.
+func (r *RecipientLookup) LookupSender(query string) (Sender, error) {
+	mgr := NewConfigManager(r.config, r.configPath)
+	sender, err := mgr.GetSender(query)
+	if err == nil || !r.config.Email.LookupAll {
+		return sender, err
+	}
+
+	q := strings.ToLower(strings.TrimSpace(query))
+	var matches []Sender
+	for _, s := range mgr.ListSenders() {
+		if personMatches(q, "", s.Name) {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Sender{}, err
+	case 1:
+		return matches[0], nil
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Key < matches[j].Key
+	})
+	keys := make([]string, len(matches))
+	for i, m := range matches {
+		keys[i] = m.Key
+	}
+	return Sender{}, fmt.Errorf("%w: sender %q matches %s - use the sender key", notification.ErrAmbiguousRecipient, query, strings.Join(keys, ", "))
+}
+
+// Entry is somewhere a person is defined in the config
+type Entry struct {
+	Section string // minister, recipient, cc, or sender
+	Key     string
+	Name    string
+	Address string // Empty for ministers and senders
+}
+
+// Find returns every minister, recipient, CC and sender whose key, name or
+// email address matches the query, in that section order
+func (r *RecipientLookup) Find(query string) []Entry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	mgr := NewConfigManager(r.config, r.configPath)
+
+	var found []Entry
+	add := func(section, key, name, address string) {
+		if personMatches(query, key, name) || (address != "" && strings.ToLower(address) == query) {
+			found = append(found, Entry{Section: section, Key: key, Name: name, Address: address})
+		}
+	}
+	ministers := mgr.ListMinisters()
+	sort.Slice(ministers, func(i, j int) bool { return ministers[i].Key < ministers[j].Key })
+	for _, m := range ministers {
+		add("minister", m.Key, m.Name, "")
+	}
+	recipients := mgr.ListRecipients()
+	sort.Slice(recipients, func(i, j int) bool { return recipients[i].Key < recipients[j].Key })
+	for _, rc := range recipients {
+		add("recipient", rc.Key, rc.Name, rc.Address)
+	}
+	for _, cc := range mgr.ListCCs() {
+		add("cc", cc.Key, cc.Name, cc.Address)
+	}
+	senders := mgr.ListSenders()
+	sort.Slice(senders, func(i, j int) bool { return senders[i].Key < senders[j].Key })
+	for _, s := range senders {
+		add("sender", s.Key, s.Name, "")
+	}
+	return found
+}
+
 // LookupRecipients looks up multiple recipients by query strings
-// Supports comma-separated or multiple queries
+// Supports comma-separated or multiple queries. A query that matches no
+// recipient but names one of email.groups adds all of the group's members,
+// and a raw "Name <address>" or bare address is used as given.
 func (r *RecipientLookup) LookupRecipients(queries []string) ([]notification.Recipient, error) {
 	var allRecipients []notification.Recipient
 	seen := make(map[string]bool) // Deduplicate by email
 
-	for _, q := range queries {
-		// Handle comma-separated values
-		for _, query := range strings.Split(q, ",") {
-			query = strings.TrimSpace(query)
-			if query == "" {
-				continue
+	for _, query := range SplitQueries(queries) {
+		if rc, ok := ParseAddress(query); ok {
+			if !seen[rc.Address] {
+				seen[rc.Address] = true
+				allRecipients = append(allRecipients, rc)
 			}
-
-			matches, err := r.LookupRecipient(query)
-			if err != nil {
-				return nil, fmt.Errorf("recipient %q: %w", query, err)
-			}
-
-			if len(matches) > 1 {
-				names := make([]string, len(matches))
-				for i, m := range matches {
-					names[i] = m.Name
+			continue
+		}
+		matches, err := r.LookupRecipient(query)
+		if errors.Is(err, notification.ErrRecipientNotFound) {
+			if members, ok, groupErr := r.groupMembers(query); ok {
+				if groupErr != nil {
+					return nil, groupErr
+				}
+				for _, m := range members {
+					if !seen[m.Address] {
+						seen[m.Address] = true
+						allRecipients = append(allRecipients, m)
+					}
 				}
-				return nil, fmt.Errorf("%w: %q matches %s - use last name to disambiguate",
-					notification.ErrAmbiguousRecipient, query, strings.Join(names, ", "))
+				continue
 			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("recipient %q: %w", query, err)
+		}
 
-			// Add if not already seen
-			if !seen[matches[0].Address] {
-				seen[matches[0].Address] = true
-				allRecipients = append(allRecipients, matches[0])
+		if len(matches) > 1 {
+			names := make([]string, len(matches))
+			for i, m := range matches {
+				names[i] = m.Name
 			}
+			return nil, fmt.Errorf("%w: %q matches %s - use last name to disambiguate",
+				notification.ErrAmbiguousRecipient, query, strings.Join(names, ", "))
+		}
+
+		// Add if not already seen
+		if !seen[matches[0].Address] {
+			seen[matches[0].Address] = true
+			allRecipients = append(allRecipients, matches[0])
 		}
 	}
 
@@ -103,6 +217,134 @@ func (r *RecipientLookup) LookupRecipients(queries []string) ([]notification.Rec
 	return allRecipients, nil
 }
 
+// ResolveRecipients looks up recipients as LookupRecipients does, then drops
+// everyone matched by excludes, e.g. a choir group without one member. An
+// exclusion that removes nobody is an error rather than silently ignored.
+func (r *RecipientLookup) ResolveRecipients(queries, excludes []string) ([]notification.Recipient, error) {
+	recipients, err := r.LookupRecipients(queries)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, query := range SplitQueries(excludes) {
+		var kept []notification.Recipient
+		for _, rc := range recipients {
+			if !r.excludes(query, rc) {
+				kept = append(kept, rc)
+			}
+		}
+		if len(kept) == len(recipients) {
+			return nil, fmt.Errorf("exclude %q: %w", query, notification.ErrExclusionUnmatched)
+		}
+		recipients = kept
+	}
+
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("every recipient was excluded: %w", notification.ErrNoRecipients)
+	}
+	return recipients, nil
+}
+
+// excludes reports whether an exclusion query names rc by name, email
+// address, or recipient key
+func (r *RecipientLookup) excludes(query string, rc notification.Recipient) bool {
+	q := strings.ToLower(query)
+	if personMatches(q, "", rc.Name) || strings.ToLower(rc.Address) == q {
+		return true
+	}
+	for key, cfg := range r.config.Email.Recipients {
+		if strings.ToLower(key) == q && strings.EqualFold(cfg.Address, rc.Address) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupMembers returns the members of the email.groups entry named query.
+// ok is false when no group has that name.
+func (r *RecipientLookup) groupMembers(query string) (members []notification.Recipient, ok bool, err error) {
+	for _, gc := range r.config.Email.Groups {
+		if gc.Name == "" || !strings.EqualFold(gc.Name, query) {
+			continue
+		}
+		for _, member := range gc.Members {
+			matches, err := r.LookupRecipient(member)
+			if err != nil {
+				return nil, true, fmt.Errorf("group %q: recipient %q: %w", gc.Name, member, err)
+			}
+			if len(matches) > 1 {
+				return nil, true, fmt.Errorf("group %q: %w: %q", gc.Name, notification.ErrAmbiguousRecipient, member)
+			}
+			members = append(members, matches[0])
+		}
+		return members, true, nil
+	}
+	return nil, false, nil
+}
+
+// LookupCC resolves one CC query the way LookupRecipients resolves a
+// recipient: a raw address, a recipient key or name, or a group name. A name
+// matching several recipients returns them all with ambiguous set, so the
+// caller can ask or refuse.
+func (r *RecipientLookup) LookupCC(query string) (cc []notification.Recipient, ambiguous bool, err error) {
+	if rc, ok := ParseAddress(query); ok {
+		return []notification.Recipient{rc}, false, nil
+	}
+	matches, err := r.LookupRecipient(query)
+	if errors.Is(err, notification.ErrRecipientNotFound) {
+		if members, ok, groupErr := r.groupMembers(query); ok {
+			return members, false, groupErr
+		}
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return matches, len(matches) > 1, nil
+}
+
+// ParseAddress reads a raw "Name <address>" or bare address, for someone not
+// in the config. ok is false when query is not an email address.
+func ParseAddress(query string) (rc notification.Recipient, ok bool) {
+	if !strings.Contains(query, "@") {
+		return rc, false
+	}
+	addr, err := mail.ParseAddress(query)
+	if err != nil {
+		return rc, false
+	}
+	return notification.Recipient{Name: addr.Name, Address: addr.Address}, true
+}
+
+// DedupeCC drops CC recipients already in to or listed earlier in cc,
+// comparing addresses case-insensitively
+func DedupeCC(cc, to []notification.Recipient) []notification.Recipient {
+	seen := make(map[string]bool, len(to)+len(cc))
+	for _, rc := range to {
+		seen[strings.ToLower(rc.Address)] = true
+	}
+	var kept []notification.Recipient
+	for _, rc := range cc {
+		if !seen[strings.ToLower(rc.Address)] {
+			seen[strings.ToLower(rc.Address)] = true
+			kept = append(kept, rc)
+		}
+	}
+	return kept
+}
+
+// SplitQueries flattens repeated and comma-separated flag values
+func SplitQueries(values []string) []string {
+	var queries []string
+	for _, v := range values {
+		for _, q := range strings.Split(v, ",") {
+			if q = strings.TrimSpace(q); q != "" {
+				queries = append(queries, q)
+			}
+		}
+	}
+	return queries
+}
+
 // GetDefaultCC returns the configured default CC recipients
 func (r *RecipientLookup) GetDefaultCC() []notification.Recipient {
 	cc := make([]notification.Recipient, len(r.config.Email.DefaultCC))
@@ -115,6 +357,116 @@ func (r *RecipientLookup) GetDefaultCC() []notification.Recipient {
 	return cc
 }
 
+// CCRules resolves email.cc_rules into a rule set, looking up cc recipients
+// and minister keys. Every configured minister counts as local.
+func (r *RecipientLookup) CCRules() (notification.CCRuleSet, error) {
+	var set notification.CCRuleSet
+	for key, m := range r.config.Ministers {
+		set.LocalMinisters = append(set.LocalMinisters, key, m.Name)
+	}
+
+	for i, rc := range r.config.Email.CCRules {
+		rule := notification.CCRule{
+			Name:          rc.Name,
+			ServiceTypes:  rc.When.ServiceType,
+			GuestMinister: rc.When.GuestMinister,
+		}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		for _, m := range rc.When.Minister {
+			rule.Ministers = append(rule.Ministers, m)
+			if mc, ok := r.config.Ministers[m]; ok {
+				rule.Ministers = append(rule.Ministers, mc.Name)
+			}
+		}
+		for _, query := range rc.CC {
+			matches, err := r.LookupRecipient(query)
+			if err != nil {
+				return set, fmt.Errorf("cc rule %q: recipient %q: %w", rule.Name, query, err)
+			}
+			if len(matches) > 1 {
+				return set, fmt.Errorf("cc rule %q: %w: %q", rule.Name, notification.ErrAmbiguousRecipient, query)
+			}
+			rule.CC = append(rule.CC, matches[0])
+		}
+		if err := rule.Validate(); err != nil {
+			return set, err
+		}
+		set.Rules = append(set.Rules, rule)
+	}
+	return set, nil
+}
+
+// Groups resolves email.groups, looking up members by recipient key or name
+func (r *RecipientLookup) Groups() (notification.RecipientGroups, error) {
+	var groups notification.RecipientGroups
+	for i, gc := range r.config.Email.Groups {
+		group := notification.RecipientGroup{Name: gc.Name, Context: gc.Context}
+		if group.Name == "" {
+			group.Name = fmt.Sprintf("group %d", i+1)
+		}
+		for _, query := range gc.Members {
+			matches, err := r.LookupRecipient(query)
+			if err != nil {
+				return nil, fmt.Errorf("group %q: recipient %q: %w", group.Name, query, err)
+			}
+			if len(matches) > 1 {
+				return nil, fmt.Errorf("group %q: %w: %q", group.Name, notification.ErrAmbiguousRecipient, query)
+			}
+			group.Members = append(group.Members, matches[0])
+		}
+		if gc.PlainText != "" || gc.HTML != "" {
+			if gc.PlainText == "" || gc.HTML == "" {
+				return nil, fmt.Errorf("group %q needs both plain_text and html", group.Name)
+			}
+			group.Template = &notification.EmailTemplate{
+				SubjectFormat: notification.DefaultTemplate.SubjectFormat,
+				PlainText:     gc.PlainText,
+				HTML:          gc.HTML,
+			}
+		}
+		groups = append(groups, group)
+	}
+	if err := groups.Validate(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// Operator returns who receives emails in sandbox mode: the operator_email
+// in this machine's user file, then email.operator_address, then
+// email.from_address
+func (r *RecipientLookup) Operator() (notification.Recipient, error) {
+	address := r.config.User.OperatorEmail
+	if address == "" {
+		address = r.config.Email.OperatorAddress
+	}
+	if address == "" {
+		address = r.config.Email.FromAddress
+	}
+	if address == "" {
+		return notification.Recipient{}, fmt.Errorf("sandbox mode needs email.operator_address or email.from_address")
+	}
+	return notification.Recipient{Name: r.config.Email.FromName, Address: address}, nil
+}
+
+// NameAddresses turns recorded addresses back into recipients, named from
+// email.recipients where the address is configured
+func (r *RecipientLookup) NameAddresses(addresses []string) []notification.Recipient {
+	result := make([]notification.Recipient, len(addresses))
+	for i, address := range addresses {
+		result[i] = notification.Recipient{Address: address}
+		for _, rc := range r.config.Email.Recipients {
+			if strings.EqualFold(rc.Address, address) {
+				result[i].Name = rc.Name
+				break
+			}
+		}
+	}
+	return result
+}
+
 // AddRecipient adds a new recipient to the config and saves it
 func (r *RecipientLookup) AddRecipient(key, name, address string) error {
 	if r.config.Email.Recipients == nil {
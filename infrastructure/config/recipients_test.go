@@ -2,6 +2,8 @@ package config
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	"nac-service-media/domain/notification"
@@ -161,7 +163,10 @@ func TestRecipientLookup_AmbiguousRecipient(t *testing.T) {
 		t.Fatalf("LookupRecipient() error = %v", err)
 	}
 	if len(matches) != 2 {
-		t.Errorf("LookupRecipient() should return 2 matches for ambiguous query, got %d", len(matches))
+		t.Fatalf("LookupRecipient() should return 2 matches for ambiguous query, got %d", len(matches))
+	}
+	if matches[0].Name != "Jane Doe" || matches[1].Name != "Jane Smith" {
+		t.Errorf("LookupRecipient() matches should be sorted by name, got %v", matches)
 	}
 
 	// LookupRecipients returns error for ambiguous
@@ -201,3 +206,361 @@ func TestRecipientLookup_GetDefaultCC(t *testing.T) {
 		t.Errorf("GetDefaultCC() = %+v, unexpected", cc[0])
 	}
 }
+
+func TestRecipientLookup_CCRules(t *testing.T) {
+	cfg := &Config{
+		Ministers: map[string]MinisterConfig{
+			"henkel": {Name: "Pr. Henkel"},
+		},
+		Email: EmailConfig{
+			Recipients: map[string]RecipientConfig{
+				"rector": {Name: "Rector Brown", Address: "rector@example.com"},
+			},
+			CCRules: []CCRuleConfig{
+				{Name: "guest-minister", When: CCRuleWhen{GuestMinister: true}, CC: []string{"rector"}},
+				{Name: "henkel", When: CCRuleWhen{Minister: []string{"henkel"}}, CC: []string{"Brown"}},
+			},
+		},
+	}
+
+	set, err := NewRecipientLookup(cfg, "").CCRules()
+	if err != nil {
+		t.Fatalf("CCRules() error = %v", err)
+	}
+	if len(set.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(set.Rules))
+	}
+	if set.Rules[0].CC[0].Address != "rector@example.com" {
+		t.Errorf("expected rector to be resolved, got %+v", set.Rules[0].CC)
+	}
+
+	// Minister keys match by configured name too
+	_, fired := set.Evaluate(nil, notification.CCContext{Minister: "Pr. Henkel"})
+	if len(fired) != 1 || fired[0].Rule != "henkel" {
+		t.Errorf("expected only the henkel rule to fire, got %+v", fired)
+	}
+}
+
+func TestRecipientLookup_CCRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule CCRuleConfig
+	}{
+		{"unknown recipient", CCRuleConfig{Name: "guest", When: CCRuleWhen{GuestMinister: true}, CC: []string{"nobody"}}},
+		{"no conditions", CCRuleConfig{Name: "always", CC: []string{"rector"}}},
+		{"no recipients", CCRuleConfig{Name: "guest", When: CCRuleWhen{GuestMinister: true}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Email: EmailConfig{
+				Recipients: map[string]RecipientConfig{
+					"rector": {Name: "Rector Brown", Address: "rector@example.com"},
+				},
+				CCRules: []CCRuleConfig{tt.rule},
+			}}
+			if _, err := NewRecipientLookup(cfg, "").CCRules(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRecipientLookup_Operator(t *testing.T) {
+	cfg := &Config{Email: EmailConfig{FromName: "Church", FromAddress: "church@example.com"}}
+	lookup := NewRecipientLookup(cfg, "")
+
+	op, err := lookup.Operator()
+	if err != nil || op.Address != "church@example.com" {
+		t.Fatalf("Operator() = %+v, %v; want the from address", op, err)
+	}
+
+	cfg.Email.OperatorAddress = "av@example.com"
+	if op, _ := lookup.Operator(); op.Address != "av@example.com" {
+		t.Errorf("Operator() = %+v, want operator_address", op)
+	}
+
+	cfg.User.OperatorEmail = "jonathan@example.com"
+	if op, _ := lookup.Operator(); op.Address != "jonathan@example.com" {
+		t.Errorf("Operator() = %+v, want the user file's operator_email", op)
+	}
+
+	if _, err := NewRecipientLookup(&Config{}, "").Operator(); err == nil {
+		t.Error("expected an error with no operator or from address")
+	}
+}
+
+func TestRecipientLookup_Groups(t *testing.T) {
+	cfg := &Config{Email: EmailConfig{
+		Recipients: map[string]RecipientConfig{
+			"mary":  {Name: "Mary Singer", Address: "mary@example.com"},
+			"smith": {Name: "Pr. Smith", Address: "smith@example.com"},
+		},
+		Groups: []RecipientGroupConfig{
+			{Name: "choir", Members: []string{"mary"}, Context: "Rehearsal is Thursday."},
+			{Name: "ministers", Members: []string{"Smith"}, PlainText: "Hi {{.AudioURL}}", HTML: "<p>Hi</p>"},
+		},
+	}}
+
+	groups, err := NewRecipientLookup(cfg, "").Groups()
+	if err != nil {
+		t.Fatalf("Groups() error = %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("Groups() returned %d groups, want 2", len(groups))
+	}
+	if groups[0].Members[0].Address != "mary@example.com" || groups[0].Context != "Rehearsal is Thursday." || groups[0].Template != nil {
+		t.Errorf("choir group = %+v", groups[0])
+	}
+	if groups[1].Template == nil || groups[1].Template.PlainText != "Hi {{.AudioURL}}" {
+		t.Errorf("ministers group should use its own template, got %+v", groups[1])
+	}
+}
+
+func TestRecipientLookup_Groups_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		group RecipientGroupConfig
+	}{
+		{"unknown member", RecipientGroupConfig{Name: "choir", Members: []string{"nobody"}}},
+		{"no members", RecipientGroupConfig{Name: "choir"}},
+		{"half a template", RecipientGroupConfig{Name: "choir", Members: []string{"mary"}, PlainText: "Hi"}},
+		{"broken template", RecipientGroupConfig{Name: "choir", Members: []string{"mary"}, PlainText: "{{", HTML: "ok"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Email: EmailConfig{
+				Recipients: map[string]RecipientConfig{
+					"mary": {Name: "Mary Singer", Address: "mary@example.com"},
+				},
+				Groups: []RecipientGroupConfig{tt.group},
+			}}
+			if _, err := NewRecipientLookup(cfg, "").Groups(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func choirConfig() *Config {
+	return &Config{Email: EmailConfig{
+		Recipients: map[string]RecipientConfig{
+			"mary": {Name: "Mary Singer", Address: "mary@example.com"},
+			"jane": {Name: "Jane Doe", Address: "jane@example.com"},
+			"tom":  {Name: "Tom Tenor", Address: "tom@example.com"},
+		},
+		Groups: []RecipientGroupConfig{
+			{Name: "Choir", Members: []string{"mary", "jane", "tom"}},
+		},
+	}}
+}
+
+func TestRecipientLookup_ResolveRecipients_GroupWithExclusions(t *testing.T) {
+	lookup := NewRecipientLookup(choirConfig(), "")
+
+	got, err := lookup.ResolveRecipients([]string{"choir"}, []string{"jane,tom@example.com"})
+	if err != nil {
+		t.Fatalf("ResolveRecipients() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Address != "mary@example.com" {
+		t.Errorf("expected only Mary, got %+v", got)
+	}
+}
+
+func TestRecipientLookup_ResolveRecipients_GroupAndIndividual(t *testing.T) {
+	lookup := NewRecipientLookup(choirConfig(), "")
+
+	got, err := lookup.ResolveRecipients([]string{"mary", "choir"}, nil)
+	if err != nil {
+		t.Fatalf("ResolveRecipients() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("expected Mary once plus the rest of the choir, got %+v", got)
+	}
+}
+
+func TestRecipientLookup_ResolveRecipients_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		queries  []string
+		excludes []string
+		want     error
+	}{
+		{"exclusion matches nobody", []string{"choir"}, []string{"bob"}, notification.ErrExclusionUnmatched},
+		{"exclusion outside the list", []string{"mary"}, []string{"jane"}, notification.ErrExclusionUnmatched},
+		{"everyone excluded", []string{"mary"}, []string{"mary"}, notification.ErrNoRecipients},
+		{"unknown group", []string{"orchestra"}, nil, notification.ErrRecipientNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRecipientLookup(choirConfig(), "").ResolveRecipients(tt.queries, tt.excludes)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRecipientLookup_ResolveRecipients_RawAddresses(t *testing.T) {
+	lookup := NewRecipientLookup(choirConfig(), "")
+
+	got, err := lookup.ResolveRecipients([]string{"Guest Pastor <guest@example.org>, visitor@example.org", "mary"}, nil)
+	if err != nil {
+		t.Fatalf("ResolveRecipients() error = %v", err)
+	}
+	want := []notification.Recipient{
+		{Name: "Guest Pastor", Address: "guest@example.org"},
+		{Address: "visitor@example.org"},
+		{Name: "Mary Singer", Address: "mary@example.com"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveRecipients() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRecipientLookup_LookupCC(t *testing.T) {
+	cfg := choirConfig()
+	cfg.Email.Recipients["john"] = RecipientConfig{Name: "John Doe", Address: "john@example.com"}
+	lookup := NewRecipientLookup(cfg, "")
+
+	tests := []struct {
+		query     string
+		want      int
+		ambiguous bool
+	}{
+		{"Elder Brown <brown@example.org>", 1, false},
+		{"tom", 1, false},
+		{"choir", 3, false},
+		{"doe", 2, true},
+	}
+	for _, tt := range tests {
+		cc, ambiguous, err := lookup.LookupCC(tt.query)
+		if err != nil {
+			t.Errorf("LookupCC(%q) error = %v", tt.query, err)
+			continue
+		}
+		if len(cc) != tt.want || ambiguous != tt.ambiguous {
+			t.Errorf("LookupCC(%q) = %+v, ambiguous %v; want %d, ambiguous %v", tt.query, cc, ambiguous, tt.want, tt.ambiguous)
+		}
+	}
+
+	if _, _, err := lookup.LookupCC("nobody"); !errors.Is(err, notification.ErrRecipientNotFound) {
+		t.Errorf("expected ErrRecipientNotFound, got %v", err)
+	}
+}
+
+func TestDedupeCC(t *testing.T) {
+	to := []notification.Recipient{{Name: "Mary", Address: "mary@example.com"}}
+	cc := []notification.Recipient{
+		{Name: "Mary", Address: "MARY@example.com"},
+		{Name: "Tom", Address: "tom@example.com"},
+		{Name: "Tom Tenor", Address: "tom@example.com"},
+	}
+	got := DedupeCC(cc, to)
+	if len(got) != 1 || got[0].Name != "Tom" {
+		t.Errorf("DedupeCC() = %+v, want only the first Tom", got)
+	}
+}
+
+func TestRecipientLookup_NameAddresses(t *testing.T) {
+	lookup := NewRecipientLookup(choirConfig(), "")
+	got := lookup.NameAddresses([]string{"MARY@example.com", "guest@example.com"})
+	if len(got) != 2 || got[0].Name == "" || got[0].Address != "MARY@example.com" {
+		t.Fatalf("NameAddresses() = %+v, want Mary named", got)
+	}
+	if got[1].Name != "" {
+		t.Errorf("an unknown address should stay unnamed, got %+v", got[1])
+	}
+}
+
+func TestRecipientLookup_LookupAll(t *testing.T) {
+	cfg := &Config{
+		Email: EmailConfig{
+			Recipients: map[string]RecipientConfig{
+				"jane": {Name: "Jane Doe", Address: "jane@example.com"},
+			},
+			DefaultCC: []RecipientConfig{
+				{Name: "Mary Jones", Address: "mary@example.com"},
+				{Name: "Jane Roe", Address: "jane.roe@example.com"},
+			},
+		},
+	}
+	lookup := NewRecipientLookup(cfg, "")
+
+	if _, err := lookup.LookupRecipient("mary"); !errors.Is(err, notification.ErrRecipientNotFound) {
+		t.Fatalf("expected CCs to be ignored by default, got %v", err)
+	}
+
+	cfg.Email.LookupAll = true
+	matches, err := lookup.LookupRecipient("mary")
+	if err != nil || len(matches) != 1 || matches[0].Address != "mary@example.com" {
+		t.Fatalf("LookupRecipient(mary) = %v, %v", matches, err)
+	}
+
+	// A recipient wins over a CC with the same first name
+	matches, err = lookup.LookupRecipient("jane")
+	if err != nil || len(matches) != 1 || matches[0].Address != "jane@example.com" {
+		t.Errorf("LookupRecipient(jane) = %v, %v", matches, err)
+	}
+}
+
+func TestRecipientLookup_LookupSender(t *testing.T) {
+	cfg := &Config{
+		Senders: SendersConfig{Senders: map[string]SenderConfig{
+			"avteam": {Name: "White Plains A/V Team"},
+			"pastor": {Name: "Pastor Team"},
+			"team":   {Name: "Team Leads"},
+		}},
+	}
+	lookup := NewRecipientLookup(cfg, "")
+
+	if _, err := lookup.LookupSender("White Plains A/V Team"); !errors.Is(err, ErrSenderNotFound) {
+		t.Fatalf("expected names to be ignored by default, got %v", err)
+	}
+
+	cfg.Email.LookupAll = true
+	sender, err := lookup.LookupSender("White Plains A/V Team")
+	if err != nil || sender.Key != "avteam" {
+		t.Errorf("LookupSender(name) = %+v, %v", sender, err)
+	}
+
+	// The key "team" wins over the names ending in Team
+	sender, err = lookup.LookupSender("team")
+	if err != nil || sender.Key != "team" {
+		t.Errorf("LookupSender(team) = %+v, %v", sender, err)
+	}
+
+	delete(cfg.Senders.Senders, "team")
+	if _, err := lookup.LookupSender("team"); !errors.Is(err, notification.ErrAmbiguousRecipient) {
+		t.Errorf("expected an ambiguous sender, got %v", err)
+	}
+}
+
+func TestRecipientLookup_Find(t *testing.T) {
+	cfg := &Config{
+		Ministers: map[string]MinisterConfig{"jones": {Name: "Rev. Mary Jones"}},
+		Email: EmailConfig{
+			Recipients: map[string]RecipientConfig{"mary": {Name: "Mary Jones", Address: "mary@example.com"}},
+			DefaultCC:  []RecipientConfig{{Name: "Mary Jones", Address: "mary@example.com"}},
+		},
+		Senders: SendersConfig{Senders: map[string]SenderConfig{"avteam": {Name: "A/V Team"}}},
+	}
+	lookup := NewRecipientLookup(cfg, "")
+
+	var sections []string
+	for _, e := range lookup.Find("Jones") {
+		sections = append(sections, e.Section)
+	}
+	if got := strings.Join(sections, ","); got != "minister,recipient,cc" {
+		t.Errorf("Find(Jones) sections = %s", got)
+	}
+
+	if found := lookup.Find("MARY@example.com"); len(found) != 2 {
+		t.Errorf("Find(address) = %+v, want the recipient and the cc", found)
+	}
+	if found := lookup.Find(""); found != nil {
+		t.Errorf("Find(\"\") = %+v, want nothing", found)
+	}
+}
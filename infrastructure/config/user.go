@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UserConfigFile is the name of the operator identity file
+const UserConfigFile = "user.yaml"
+
+// UserConfig says who operates this machine. It is kept per machine, outside
+// the shared config, so each volunteer's PC signs emails as them without
+// --sender.
+type UserConfig struct {
+	// Sender is the senders.senders key emails are signed with
+	Sender string `yaml:"sender,omitempty"`
+	// OperatorEmail receives sandbox emails instead of email.operator_address
+	OperatorEmail string `yaml:"operator_email,omitempty"`
+
+	// Login is the operating system user, used as a sender key when Sender is empty
+	Login string `yaml:"-"`
+	// Path is where the file was read from, empty when there was none
+	Path string `yaml:"-"`
+}
+
+// UserConfigDir returns this application's per-user config directory, such
+// as ~/.config/nac-service-media or %AppData%\nac-service-media
+func UserConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+	return filepath.Join(dir, "nac-service-media"), nil
+}
+
+// DefaultUserConfigPath returns user.yaml in the per-user config directory
+func DefaultUserConfigPath() (string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, UserConfigFile), nil
+}
+
+// LoadUser reads the operator identity file. A missing file is not an error;
+// it leaves every setting empty.
+func LoadUser(path string) (UserConfig, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return UserConfig{}, nil
+	}
+	if err != nil {
+		return UserConfig{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var user UserConfig
+	if err := yaml.Unmarshal(data, &user); err != nil {
+		return UserConfig{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	user.Sender = strings.ToLower(strings.TrimSpace(user.Sender))
+	user.OperatorEmail = strings.TrimSpace(user.OperatorEmail)
+	if user.OperatorEmail != "" && !isValidEmail(user.OperatorEmail) {
+		return UserConfig{}, fmt.Errorf("invalid operator_email in %s: %w: %q", path, ErrInvalidEmail, user.OperatorEmail)
+	}
+	user.Path = path
+	return user, nil
+}
+
+// SaveUser writes the operator identity file, creating its directory
+func SaveUser(user UserConfig, path string) error {
+	if user.OperatorEmail != "" && !isValidEmail(user.OperatorEmail) {
+		return fmt.Errorf("%w: %q", ErrInvalidEmail, user.OperatorEmail)
+	}
+	data, err := yaml.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s: %w", UserConfigFile, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoginName strips a Windows DOMAIN\ prefix from an operating system user
+// name and lowercases it, so it can match a sender key
+func LoginName(username string) string {
+	if i := strings.LastIndex(username, `\`); i >= 0 {
+		username = username[i+1:]
+	}
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadUser(t *testing.T) {
+	dir := t.TempDir()
+
+	user, err := LoadUser(filepath.Join(dir, "missing.yaml"))
+	if err != nil || user != (UserConfig{}) {
+		t.Fatalf("LoadUser(missing) = %+v, %v; want an empty identity", user, err)
+	}
+
+	path := filepath.Join(dir, "user.yaml")
+	if err := os.WriteFile(path, []byte("sender: Jonathan\noperator_email: jonathan@example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	user, err = LoadUser(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Sender != "jonathan" || user.OperatorEmail != "jonathan@example.com" || user.Path != path {
+		t.Errorf("LoadUser() = %+v", user)
+	}
+
+	if err := os.WriteFile(path, []byte("operator_email: not-an-address\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadUser(path); !errors.Is(err, ErrInvalidEmail) {
+		t.Errorf("LoadUser() error = %v, want ErrInvalidEmail", err)
+	}
+}
+
+func TestSaveUser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nac-service-media", "user.yaml")
+	if err := SaveUser(UserConfig{Sender: "jonathan", Login: "ignored"}, path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "sender: jonathan\n" {
+		t.Errorf("saved %q, want only the sender", got)
+	}
+}
+
+func TestLoginName(t *testing.T) {
+	tests := map[string]string{
+		"jonathan":         "jonathan",
+		`CHURCH-PC\AVTeam`: "avteam",
+		" Jonathan.White ": "jonathan.white",
+	}
+	for in, want := range tests {
+		if got := LoginName(in); got != want {
+			t.Errorf("LoginName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestConfigManager_DefaultSender(t *testing.T) {
+	newConfig := func() *Config {
+		return &Config{Senders: SendersConfig{
+			DefaultSender: "avteam",
+			Senders: map[string]SenderConfig{
+				"avteam":   {Name: "A/V Team"},
+				"jonathan": {Name: "Jonathan"},
+				"mary":     {Name: "Mary"},
+			},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		user     UserConfig
+		noConfig bool
+		wantKey  string
+		wantFrom string
+		wantErr  error
+	}{
+		{name: "config default", wantKey: "avteam", wantFrom: SenderFromConfig},
+		{name: "login name matches a sender", user: UserConfig{Login: "mary"}, wantKey: "mary", wantFrom: SenderFromLogin},
+		{name: "login name matches nobody", user: UserConfig{Login: "guest"}, wantKey: "avteam", wantFrom: SenderFromConfig},
+		{name: "user file beats login name", user: UserConfig{Sender: "jonathan", Login: "mary", Path: "user.yaml"}, wantKey: "jonathan", wantFrom: SenderFromUserFile},
+		{name: "user file names an unknown sender", user: UserConfig{Sender: "nobody", Path: "user.yaml"}, wantErr: ErrSenderNotFound},
+		{name: "nothing set", noConfig: true, wantErr: ErrNoDefaultSender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			if tt.noConfig {
+				cfg.Senders.DefaultSender = ""
+			}
+			cfg.User = tt.user
+
+			sender, from, err := NewConfigManager(cfg, "").DefaultSender()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DefaultSender() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.user.Path != "" && !strings.Contains(err.Error(), tt.user.Path) {
+					t.Errorf("error %q should name the user file", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DefaultSender() error = %v", err)
+			}
+			if sender.Key != tt.wantKey || from != tt.wantFrom {
+				t.Errorf("DefaultSender() = %s from %s, want %s from %s", sender.Key, from, tt.wantKey, tt.wantFrom)
+			}
+		})
+	}
+}
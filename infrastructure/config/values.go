@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/video"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Bitrate is an ffmpeg bitrate such as "192k"
+type Bitrate string
+
+// BitsPerSecond parses the bitrate
+func (b Bitrate) BitsPerSecond() (int64, error) {
+	return video.ParseBitrate(string(b))
+}
+
+func (b Bitrate) String() string {
+	return string(b)
+}
+
+// Validate checks the bitrate; empty means the default
+func (b Bitrate) Validate() error {
+	if b == "" {
+		return nil
+	}
+	_, err := b.BitsPerSecond()
+	return err
+}
+
+// UnmarshalYAML reads a bitrate written as a string or a number of bits
+func (b *Bitrate) UnmarshalYAML(node *yaml.Node) error {
+	s, err := scalar(node, "a bitrate such as 192k")
+	*b = Bitrate(s)
+	return err
+}
+
+// Bitrates converts a list of bitrates to the strings ffmpeg is given
+func Bitrates(bitrates []Bitrate) []string {
+	out := make([]string, len(bitrates))
+	for i, b := range bitrates {
+		out[i] = string(b)
+	}
+	return out
+}
+
+// ByteSize is a size such as "500GB"; units are binary
+type ByteSize string
+
+// Bytes parses the size; empty is 0
+func (s ByteSize) Bytes() (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return distribution.ParseSize(string(s))
+}
+
+func (s ByteSize) String() string {
+	return string(s)
+}
+
+// Validate checks the size
+func (s ByteSize) Validate() error {
+	_, err := s.Bytes()
+	return err
+}
+
+// UnmarshalYAML reads a size written as a string or a number of bytes
+func (s *ByteSize) UnmarshalYAML(node *yaml.Node) error {
+	v, err := scalar(node, "a size such as 500GB")
+	*s = ByteSize(v)
+	return err
+}
+
+// Duration is a Go duration such as "90s" or "12h", or whole days such as "7d"
+type Duration string
+
+// Value parses the duration; empty is 0
+func (d Duration) Value() (time.Duration, error) {
+	s := strings.TrimSpace(string(d))
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("'%s' is not a number of days, use e.g. '7d'", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a duration, use e.g. '90s', '12h' or '7d'", s)
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("'%s' is negative", s)
+	}
+	return v, nil
+}
+
+func (d Duration) String() string {
+	return string(d)
+}
+
+// Validate checks the duration
+func (d Duration) Validate() error {
+	_, err := d.Value()
+	return err
+}
+
+// UnmarshalYAML reads a duration written as a string
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	s, err := scalar(node, "a duration such as 12h or 7d")
+	*d = Duration(s)
+	return err
+}
+
+// scalar returns a YAML value that must be a single string or number
+func scalar(node *yaml.Node, want string) (string, error) {
+	if node.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("line %d: expected %s", node.Line, want)
+	}
+	return strings.TrimSpace(node.Value), nil
+}
+
+// valueSetting is a typed config value and its YAML key
+type valueSetting struct {
+	Key   string
+	Value interface{ Validate() error }
+}
+
+// valueSettings returns every bitrate and size setting, so Load can name
+// the key of one that does not parse
+func (c *Config) valueSettings() []valueSetting {
+	settings := []valueSetting{{Key: "audio.bitrate", Value: c.Audio.Bitrate}}
+	for i, b := range c.Audio.Bitrates {
+		settings = append(settings, valueSetting{Key: fmt.Sprintf("audio.bitrates[%d]", i+1), Value: b})
+	}
+	return append(settings,
+		valueSetting{Key: "archive.video_bitrate", Value: c.Archive.VideoBitrate},
+		valueSetting{Key: "storage.s3.quota", Value: c.Storage.S3.Quota},
+	)
+}
+
+// validateValues checks each typed setting
+func validateValues(c *Config) error {
+	for _, setting := range c.valueSettings() {
+		if err := setting.Value.Validate(); err != nil {
+			return fmt.Errorf("invalid %s: %w", setting.Key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDuration_Value(t *testing.T) {
+	tests := []struct {
+		in      Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"90s", 90 * time.Second, false},
+		{"12h", 12 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"soon", 0, true},
+		{"-1h", 0, true},
+		{"xd", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := tt.in.Value()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Duration(%q).Value() = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestByteSize_Bytes(t *testing.T) {
+	if n, err := ByteSize("").Bytes(); err != nil || n != 0 {
+		t.Errorf("empty size = %d, %v", n, err)
+	}
+	if n, err := ByteSize("2GB").Bytes(); err != nil || n != 2<<30 {
+		t.Errorf("2GB = %d, %v", n, err)
+	}
+	if err := ByteSize("lots").Validate(); err == nil {
+		t.Error("expected an error for a size with no number")
+	}
+}
+
+func TestLoad_TypedValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	load := func(yaml string) (*Config, error) {
+		if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return Load(path)
+	}
+
+	cfg, err := load("audio:\n  bitrate: 192000\nstorage:\n  s3:\n    quota: 500GB\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Audio.Bitrate != "192000" {
+		t.Errorf("a numeric bitrate should load as written, got %q", cfg.Audio.Bitrate)
+	}
+	if n, _ := cfg.Storage.S3.QuotaBytes(); n != 500<<30 {
+		t.Errorf("quota = %d bytes", n)
+	}
+
+	tests := []struct {
+		yaml string
+		want string
+	}{
+		{"audio:\n  bitrate: 192kbps\n", "invalid audio.bitrate: '192kbps' is not a bitrate, use '192k'"},
+		{"audio:\n  bitrates: [192k, fast]\n", "invalid audio.bitrates[2]"},
+		{"archive:\n  video_bitrate: 500kb/s\n", "invalid archive.video_bitrate: '500kb/s' is not a bitrate, use '500k'"},
+		{"storage:\n  s3:\n    quota: lots\n", "invalid storage.s3.quota"},
+		{"audio:\n  bitrate: [192k]\n", "expected a bitrate such as 192k"},
+	}
+	for _, tt := range tests {
+		if _, err := load(tt.yaml); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Load(%q) error = %v, want %q", tt.yaml, err, tt.want)
+		}
+	}
+}
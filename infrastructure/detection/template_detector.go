@@ -4,6 +4,7 @@ package detection
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -17,12 +18,19 @@ import (
 	"gocv.io/x/gocv"
 )
 
-// TemplateDetector implements detection.StartDetector using GoCV template matching
+// Available reports whether this build can detect timestamps
+const Available = true
+
+// TemplateDetector implements detection.BoundaryDetector using GoCV template matching
 type TemplateDetector struct {
 	templates  map[string]gocv.Mat
 	ffmpegPath string
 	config     config.DetectionConfig
 	tempDir    string
+	// ownsTempDir is set when tempDir was created here rather than given by WithFramesDir
+	ownsTempDir bool
+	// searchRange, when set by WithSearchRange, replaces the configured range
+	searchRange *detection.SearchRange
 }
 
 // TemplateDetectorOption is a functional option for configuring TemplateDetector
@@ -35,6 +43,22 @@ func WithFFmpegPath(path string) TemplateDetectorOption {
 	}
 }
 
+// WithFramesDir extracts frames into dir, e.g. the run workspace, which then
+// owns it; by default a temporary directory is created and removed on Close
+func WithFramesDir(dir string) TemplateDetectorOption {
+	return func(d *TemplateDetector) {
+		d.tempDir = dir
+	}
+}
+
+// WithSearchRange searches only r for the start, e.g. a range narrowed to
+// where recent services started, instead of the configured range
+func WithSearchRange(r detection.SearchRange) TemplateDetectorOption {
+	return func(d *TemplateDetector) {
+		d.searchRange = &r
+	}
+}
+
 // NewTemplateDetector creates a new template-based detector
 func NewTemplateDetector(cfg config.DetectionConfig, opts ...TemplateDetectorOption) *TemplateDetector {
 	d := &TemplateDetector{
@@ -86,7 +110,7 @@ func (d *TemplateDetector) Close() {
 	}
 
 	// Clean up temp directory if created
-	if d.tempDir != "" {
+	if d.ownsTempDir {
 		os.RemoveAll(d.tempDir)
 	}
 }
@@ -94,19 +118,28 @@ func (d *TemplateDetector) Close() {
 // DetectStart implements detection.StartDetector using a 3-phase algorithm
 func (d *TemplateDetector) DetectStart(ctx context.Context, videoPath string) (detection.DetectionResult, error) {
 	// Create temp directory for extracted frames
-	var err error
-	d.tempDir, err = os.MkdirTemp("", "nac-detection-*")
-	if err != nil {
-		return detection.DetectionResult{}, fmt.Errorf("failed to create temp directory: %w", err)
+	if d.tempDir == "" {
+		var err error
+		d.tempDir, err = os.MkdirTemp("", "nac-detection-*")
+		if err != nil {
+			return detection.DetectionResult{}, fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		d.ownsTempDir = true
 	}
 
 	// Get search range
 	startSeconds := d.config.SearchRange.StartMinutes * 60
 	endSeconds := d.config.SearchRange.EndMinutes * 60
-	coarseStep := d.config.Thresholds.CoarseStepSeconds
-	if coarseStep == 0 {
-		coarseStep = 30 // Default to 30 seconds
+	scanFrom := 0
+	if d.searchRange != nil {
+		startSeconds, endSeconds = d.searchRange.StartSeconds, d.searchRange.EndSeconds
+		scanFrom = startSeconds
 	}
+	stepper := detection.NewCoarseStepper(
+		d.config.Thresholds.CoarseStepSeconds,
+		d.config.Thresholds.CoarseMinStepSeconds,
+		d.matchThreshold(),
+	)
 
 	var framesAnalyzed int
 
@@ -123,19 +156,25 @@ func (d *TemplateDetector) DetectStart(ctx context.Context, videoPath string) (d
 		}, nil
 	}
 
-	// Phase 1: Coarse scan to find bounds
+	// Phase 1: Adaptive coarse scan to find bounds. Steps start large and
+	// shrink as the lit score trends up, so the last unlit frame stays close
+	// to the first lit one.
 	var firstUnlitTime, firstLitTime int
+	var lastUnlit, lastLit detection.FrameAnalysis
 	foundUnlit, foundLit := false, false
 
 	// Start from 0 if the early check showed unlit or not visible
 	scanStart := 0
 	if earlyCheck.State == detection.StateUnlit {
 		firstUnlitTime = 5
+		lastUnlit = earlyCheck
 		foundUnlit = true
-		scanStart = coarseStep // Skip ahead since we already checked the beginning
+		scanStart = stepper.Next(earlyCheck) // Skip ahead since we already checked the beginning
 	}
+	// A narrowed range skips straight to where recent services started
+	scanStart = max(scanStart, scanFrom)
 
-	for t := scanStart; t <= endSeconds; t += coarseStep {
+	for t := scanStart; t <= endSeconds; {
 		select {
 		case <-ctx.Done():
 			return detection.DetectionResult{}, ctx.Err()
@@ -145,26 +184,55 @@ func (d *TemplateDetector) DetectStart(ctx context.Context, videoPath string) (d
 		analysis, err := d.analyzeFrame(ctx, videoPath, t)
 		framesAnalyzed++
 		if err != nil {
-			continue // Skip frames that fail to extract
+			t += stepper.Skip(t) // Skip frames that fail to extract
+			continue
 		}
 
-		if analysis.State == detection.StateUnlit && !foundUnlit {
-			firstUnlitTime = t
-			foundUnlit = true
-		}
-		if analysis.State == detection.StateLit && !foundLit {
+		if analysis.State == detection.StateLit {
+			stepper.Next(analysis)
 			firstLitTime = t
+			lastLit = analysis
 			foundLit = true
 			break // Found lit, we have our bounds
 		}
+		if analysis.State == detection.StateUnlit {
+			// The cross stays lit once lit, so the latest unlit frame is the tightest lower bound
+			firstUnlitTime = t
+			lastUnlit = analysis
+			foundUnlit = true
+		}
+		t += stepper.Next(analysis)
 	}
 
 	if !foundLit {
-		return detection.DetectionResult{FramesAnalyzed: framesAnalyzed}, fmt.Errorf("could not detect cross lighting up in search range")
+		return detection.DetectionResult{FramesAnalyzed: framesAnalyzed, CoarseTrace: stepper.Trace()}, fmt.Errorf("could not detect cross lighting up in search range")
+	}
+
+	// A very confident lit frame is worth one probe a second earlier: a
+	// confident unlit frame there pins the transition, skipping phases 2 and 3
+	earlyExitScore := d.earlyExitScore()
+	if earlyExitScore > 0 && lastLit.Confidence >= earlyExitScore && firstLitTime > startSeconds {
+		prev, err := lastUnlit, error(nil)
+		if !foundUnlit || firstUnlitTime != firstLitTime-1 {
+			prev, err = d.analyzeFrame(ctx, videoPath, firstLitTime-1)
+			framesAnalyzed++
+		}
+		if err == nil {
+			if detection.ConfidentBracket(prev, lastLit, earlyExitScore) {
+				return d.result(firstLitTime, lastLit, framesAnalyzed, stepper, true), nil
+			}
+			switch prev.State {
+			case detection.StateLit:
+				firstLitTime, lastLit = prev.TimestampSeconds, prev
+			case detection.StateUnlit:
+				firstUnlitTime, lastUnlit, foundUnlit = prev.TimestampSeconds, prev, true
+			}
+		}
 	}
 
 	// If we found lit but no unlit, search backwards
 	if !foundUnlit {
+		coarseStep := stepper.Step()
 		for t := firstLitTime - coarseStep; t >= startSeconds; t -= coarseStep {
 			analysis, err := d.analyzeFrame(ctx, videoPath, t)
 			framesAnalyzed++
@@ -184,7 +252,7 @@ func (d *TemplateDetector) DetectStart(ctx context.Context, videoPath string) (d
 
 	// Phase 2: Binary search to narrow down
 	low, high := firstUnlitTime, firstLitTime
-	var lastLitAnalysis detection.FrameAnalysis
+	lastLitAnalysis := lastLit
 
 	for high-low > 1 {
 		select {
@@ -207,9 +275,17 @@ func (d *TemplateDetector) DetectStart(ctx context.Context, videoPath string) (d
 			lastLitAnalysis = analysis
 		} else {
 			low = mid
+			if analysis.State == detection.StateUnlit {
+				lastUnlit = analysis
+			}
 		}
 	}
 
+	// A confident bracket already pins the transition to high
+	if detection.ConfidentBracket(lastUnlit, lastLitAnalysis, earlyExitScore) {
+		return d.result(high, lastLitAnalysis, framesAnalyzed, stepper, true), nil
+	}
+
 	// Phase 3: Refinement - scan second by second around the transition
 	transitionTime := high
 	for t := high - 2; t <= high+2; t++ {
@@ -228,6 +304,46 @@ func (d *TemplateDetector) DetectStart(ctx context.Context, videoPath string) (d
 		}
 	}
 
+	return d.result(transitionTime, lastLitAnalysis, framesAnalyzed, stepper, false), nil
+}
+
+// DetectEnd implements detection.EndDetector by finding the cross going dark,
+// searching from the configured offset after serviceStartSeconds
+func (d *TemplateDetector) DetectEnd(ctx context.Context, videoPath string, serviceStartSeconds int) (detection.EndDetectionResult, error) {
+	if d.tempDir == "" {
+		var err error
+		d.tempDir, err = os.MkdirTemp("", "nac-detection-*")
+		if err != nil {
+			return detection.EndDetectionResult{}, fmt.Errorf("failed to create temp directory: %w", err)
+		}
+		d.ownsTempDir = true
+	}
+
+	var confidence float64
+	probe := func(ctx context.Context, seconds int) (detection.FrameAnalysis, error) {
+		a, err := d.analyzeFrame(ctx, videoPath, seconds)
+		if err == nil && a.State == detection.StateUnlit {
+			confidence = a.Confidence
+		}
+		return a, err
+	}
+	r := d.config.SearchRange.EndSearchRange(serviceStartSeconds)
+	end, _, err := detection.FindCrossOff(ctx, probe, r, d.config.End.CrossStepSeconds)
+	if errors.Is(err, detection.ErrEndNotFound) {
+		return detection.EndDetectionResult{Error: err.Error()}, nil
+	}
+	if err != nil {
+		return detection.EndDetectionResult{}, err
+	}
+	return detection.EndDetectionResult{
+		Timestamp:  video.TimestampFromSeconds(end),
+		Confidence: confidence,
+		Detected:   true,
+	}, nil
+}
+
+// result builds the detection result for a transition at transitionTime
+func (d *TemplateDetector) result(transitionTime int, lit detection.FrameAnalysis, framesAnalyzed int, stepper *detection.CoarseStepper, earlyExit bool) detection.DetectionResult {
 	// Convert to timestamp
 	hours := transitionTime / 3600
 	minutes := (transitionTime % 3600) / 60
@@ -241,10 +357,12 @@ func (d *TemplateDetector) DetectStart(ctx context.Context, videoPath string) (d
 
 	return detection.DetectionResult{
 		Timestamp:      timestamp,
-		Confidence:     lastLitAnalysis.Confidence,
-		CameraAngle:    lastLitAnalysis.CameraAngle,
+		Confidence:     lit.Confidence,
+		CameraAngle:    lit.CameraAngle,
 		FramesAnalyzed: framesAnalyzed,
-	}, nil
+		CoarseTrace:    stepper.Trace(),
+		EarlyExit:      earlyExit,
+	}
 }
 
 // analyzeFrame extracts and analyzes a single frame from the video
@@ -281,10 +399,8 @@ func (d *TemplateDetector) analyzeFrame(ctx context.Context, videoPath string, t
 
 // analyzeFrameMat analyzes a frame image against all templates
 func (d *TemplateDetector) analyzeFrameMat(frame gocv.Mat, timestampSeconds int) detection.FrameAnalysis {
-	threshold := d.config.Thresholds.MatchScore
-	if threshold == 0 {
-		threshold = 0.85 // Default threshold
-	}
+	threshold := d.matchThreshold()
+	var litScore float64
 
 	var bestMatch struct {
 		name       string
@@ -300,6 +416,9 @@ func (d *TemplateDetector) analyzeFrameMat(frame gocv.Mat, timestampSeconds int)
 		result.Close()
 
 		score := float64(maxVal)
+		if strings.HasSuffix(name, "_lit") && score > litScore {
+			litScore = score
+		}
 		if score > bestMatch.score {
 			bestMatch.name = name
 			bestMatch.score = score
@@ -318,6 +437,7 @@ func (d *TemplateDetector) analyzeFrameMat(frame gocv.Mat, timestampSeconds int)
 			CameraAngle:      bestMatch.cameraType,
 			Confidence:       bestMatch.score,
 			TimestampSeconds: timestampSeconds,
+			LitScore:         litScore,
 		}
 	}
 
@@ -326,8 +446,25 @@ func (d *TemplateDetector) analyzeFrameMat(frame gocv.Mat, timestampSeconds int)
 		CameraAngle:      "",
 		Confidence:       bestMatch.score,
 		TimestampSeconds: timestampSeconds,
+		LitScore:         litScore,
+	}
+}
+
+// matchThreshold returns the template score at which a frame counts as matched
+func (d *TemplateDetector) matchThreshold() float64 {
+	if d.config.Thresholds.MatchScore == 0 {
+		return 0.85 // Default threshold
+	}
+	return d.config.Thresholds.MatchScore
+}
+
+// earlyExitScore returns the bracket score that ends detection early
+func (d *TemplateDetector) earlyExitScore() float64 {
+	if d.config.Thresholds.EarlyExitScore == 0 {
+		return detection.DefaultEarlyExitScore
 	}
+	return d.config.Thresholds.EarlyExitScore
 }
 
-// Ensure TemplateDetector implements detection.StartDetector
-var _ detection.StartDetector = (*TemplateDetector)(nil)
+// Ensure TemplateDetector implements detection.BoundaryDetector
+var _ detection.BoundaryDetector = (*TemplateDetector)(nil)
@@ -10,6 +10,9 @@ import (
 	"nac-service-media/infrastructure/config"
 )
 
+// Available reports whether this build can detect timestamps
+const Available = false
+
 // TemplateDetector is a stub when GoCV/OpenCV is not available
 type TemplateDetector struct {
 	config config.DetectionConfig
@@ -28,9 +31,19 @@ func WithFFmpegPath(path string) TemplateDetectorOption {
 	return func(d *TemplateDetector) {}
 }
 
+// WithFramesDir is a no-op in stub mode
+func WithFramesDir(dir string) TemplateDetectorOption {
+	return func(d *TemplateDetector) {}
+}
+
+// WithSearchRange is a no-op in stub mode
+func WithSearchRange(r detection.SearchRange) TemplateDetectorOption {
+	return func(d *TemplateDetector) {}
+}
+
 // LoadTemplates returns an error indicating detection is not available
 func (d *TemplateDetector) LoadTemplates(templatesDir string) error {
-	return fmt.Errorf("detection not available: build with '-tags=detection' and install OpenCV/GoCV")
+	return fmt.Errorf("%w: build with '-tags=detection' and install OpenCV/GoCV", detection.ErrDetectionUnavailable)
 }
 
 // Close is a no-op in stub mode
@@ -38,8 +51,13 @@ func (d *TemplateDetector) Close() {}
 
 // DetectStart returns an error indicating detection is not available
 func (d *TemplateDetector) DetectStart(ctx context.Context, videoPath string) (detection.DetectionResult, error) {
-	return detection.DetectionResult{}, fmt.Errorf("detection not available: build with '-tags=detection' and install OpenCV/GoCV")
+	return detection.DetectionResult{}, fmt.Errorf("%w: build with '-tags=detection' and install OpenCV/GoCV", detection.ErrDetectionUnavailable)
+}
+
+// DetectEnd returns an error indicating detection is not available
+func (d *TemplateDetector) DetectEnd(ctx context.Context, videoPath string, serviceStartSeconds int) (detection.EndDetectionResult, error) {
+	return detection.EndDetectionResult{}, fmt.Errorf("%w: build with '-tags=detection' and install OpenCV/GoCV", detection.ErrDetectionUnavailable)
 }
 
-// Ensure TemplateDetector implements detection.StartDetector
-var _ detection.StartDetector = (*TemplateDetector)(nil)
+// Ensure TemplateDetector implements detection.BoundaryDetector
+var _ detection.BoundaryDetector = (*TemplateDetector)(nil)
@@ -2,11 +2,18 @@ package drive
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
 	"os"
 	"time"
 
+	"nac-service-media/domain/audit"
+	"nac-service-media/domain/clock"
 	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/filesystem"
 
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/drive/v3"
@@ -21,27 +28,103 @@ type DriveService interface {
 	GetAbout(ctx context.Context, fields string) (*drive.About, error)
 	DeleteFile(ctx context.Context, fileID string) error
 	EmptyTrash(ctx context.Context) error
-	UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string) (*drive.File, error)
+	UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*drive.File, error)
 	CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) error
 }
 
+// ReaderUploader is a DriveService that can upload content of unknown length
+// from a reader using a resumable upload
+type ReaderUploader interface {
+	UploadReader(ctx context.Context, fileName, mimeType, folderID string, r io.Reader, appProperties map[string]string) (*drive.File, error)
+}
+
+// ProgressUploader is a DriveService that reports how much of a local file
+// has been uploaded as it goes
+type ProgressUploader interface {
+	UploadFileWithProgress(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string, progress func(sent int64)) (*drive.File, error)
+}
+
+// ContentUpdater is a DriveService that can replace an existing file's content
+type ContentUpdater interface {
+	UpdateFileContent(ctx context.Context, fileID, mimeType, localPath string) (*drive.File, error)
+}
+
+// FileDownloader is a DriveService that can stream a file's content
+type FileDownloader interface {
+	DownloadFile(ctx context.Context, fileID string, w io.Writer) error
+}
+
+// RevisionEditor is a DriveService that can list, pin and download file revisions
+type RevisionEditor interface {
+	ListRevisions(ctx context.Context, fileID string) ([]*drive.Revision, error)
+	UpdateRevision(ctx context.Context, fileID, revisionID string, keepForever bool) error
+	DownloadRevision(ctx context.Context, fileID, revisionID string, w io.Writer) error
+}
+
+// PropertyUpdater is a DriveService that can change an existing file's appProperties
+type PropertyUpdater interface {
+	UpdateAppProperties(ctx context.Context, fileID string, appProperties map[string]string) error
+}
+
+// NameUpdater is a DriveService that can rename an existing file
+type NameUpdater interface {
+	UpdateName(ctx context.Context, fileID, name string) error
+}
+
+// FolderMover is a DriveService that can create folders and move files
+// between them
+type FolderMover interface {
+	CreateFolder(ctx context.Context, name, parentID string) (*drive.File, error)
+	MoveFile(ctx context.Context, fileID, addParentID, removeParentID string) error
+}
+
+// PermissionEditor is a DriveService that can list and remove a file's permissions
+type PermissionEditor interface {
+	ListPermissions(ctx context.Context, fileID string) ([]*drive.Permission, error)
+	DeletePermission(ctx context.Context, fileID, permissionID string) error
+}
+
+// FileGetter is a DriveService that can look up one file or folder by ID
+type FileGetter interface {
+	GetFile(ctx context.Context, fileID, fields string) (*drive.File, error)
+}
+
+// uploadFields are the file fields returned after an upload
+const uploadFields = "id, name, size, webViewLink, md5Checksum"
+
+// folderFields are the fields needed to check a folder can take uploads
+const folderFields = "id, name, mimeType, capabilities/canAddChildren"
+
 // GoogleDriveService is the production implementation using the Google Drive API
 type GoogleDriveService struct {
-	service *drive.Service
+	service    *drive.Service
+	httpClient *http.Client
+
+	chunkRetries int           // Zero means DefaultChunkRetries
+	uploadLog    io.Writer     // Chunk progress and retries; nil for none
+	sessions     *SessionStore // Saves upload sessions for the next run; nil for none
+
+	files filesystem.Opener // Reads uploads; nil uses the os package
+	sleep clock.Sleeper     // Waits between chunk retries; nil really sleeps
 }
 
-// ListFiles lists files matching the query
+// ListFiles lists files matching the query, following page tokens so large
+// folders are returned in full
 func (s *GoogleDriveService) ListFiles(ctx context.Context, query string, fields string, orderBy string) ([]*drive.File, error) {
-	r, err := s.service.Files.List().
+	var files []*drive.File
+	err := s.service.Files.List().
 		Q(query).
-		Fields(googleapi.Field("files(" + fields + ")")).
+		Fields(googleapi.Field("nextPageToken, files("+fields+")")).
 		OrderBy(orderBy).
-		Context(ctx).
-		Do()
+		PageSize(1000).
+		Pages(ctx, func(r *drive.FileList) error {
+			files = append(files, r.Files...)
+			return nil
+		})
 	if err != nil {
 		return nil, err
 	}
-	return r.Files, nil
+	return files, nil
 }
 
 // GetAbout gets information about the user's Drive
@@ -60,24 +143,95 @@ func (s *GoogleDriveService) EmptyTrash(ctx context.Context) error {
 }
 
 // UploadFile uploads a file to Google Drive
-func (s *GoogleDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string) (*drive.File, error) {
-	f, err := os.Open(localPath)
+func (s *GoogleDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*drive.File, error) {
+	return s.UploadFileWithProgress(ctx, fileName, mimeType, folderID, localPath, appProperties, nil)
+}
+
+// UploadFileWithProgress uploads a file to Google Drive, calling progress
+// after each chunk Drive confirms
+func (s *GoogleDriveService) UploadFileWithProgress(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string, progress func(sent int64)) (*drive.File, error) {
+	f, err := opener(s.files).Open(localPath)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open file: %w", err)
 	}
 	defer f.Close()
 
+	stat, ok := f.(interface{ Stat() (fs.FileInfo, error) })
+	if s.sessions == nil || !ok {
+		return s.uploadReader(ctx, fileName, mimeType, folderID, f, appProperties, progress)
+	}
+	info, err := stat.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file: %w", err)
+	}
+
 	fileMetadata := &drive.File{
-		Name:     fileName,
-		Parents:  []string{folderID},
-		MimeType: mimeType,
+		Name:          fileName,
+		Parents:       []string{folderID},
+		MimeType:      mimeType,
+		AppProperties: appProperties,
 	}
+	upload := s.resumable()
+	upload.progress = progress
+	key := sessionKey(folderID, fileName, localPath)
 
-	file, err := s.service.Files.Create(fileMetadata).
-		Media(f).
-		Fields("id, name, size, webViewLink").
-		Context(ctx).
-		Do()
+	if session := s.sessions.load(key, info.Size(), info.ModTime()); session != "" {
+		file, err := upload.resume(ctx, session, f, info.Size())
+		if !errors.Is(err, errSessionGone) {
+			return s.finishSession(key, file, err)
+		}
+		s.sessions.remove(key)
+		upload.logf("  The earlier upload of %s has expired; starting over\n", fileName)
+	}
+
+	upload.started = func(session string) {
+		err := s.sessions.save(key, savedSession{
+			URL:       session,
+			LocalPath: localPath,
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			Started:   s.sessions.now(),
+		})
+		if err != nil {
+			upload.logf("  Warning: %v; an interrupted upload will start over\n", err)
+		}
+	}
+	file, err := upload.upload(ctx, http.MethodPost, "", fileMetadata, f)
+	return s.finishSession(key, file, err)
+}
+
+// finishSession forgets a session once its upload is done. An upload cut off
+// mid-file keeps its session, and the error says the next run resumes it.
+func (s *GoogleDriveService) finishSession(key string, file *drive.File, err error) (*drive.File, error) {
+	var chunkErr *ChunkError
+	switch {
+	case err == nil:
+		s.sessions.remove(key)
+		return file, nil
+	case errors.As(err, &chunkErr):
+		return nil, fmt.Errorf("unable to upload file: %w; run again to resume from %s", err, distribution.FormatSize(chunkErr.Start))
+	default:
+		return nil, fmt.Errorf("unable to upload file: %w", err)
+	}
+}
+
+// UploadReader uploads from a reader in resumable chunks, so the length does
+// not need to be known up front
+func (s *GoogleDriveService) UploadReader(ctx context.Context, fileName, mimeType, folderID string, r io.Reader, appProperties map[string]string) (*drive.File, error) {
+	return s.uploadReader(ctx, fileName, mimeType, folderID, r, appProperties, nil)
+}
+
+func (s *GoogleDriveService) uploadReader(ctx context.Context, fileName, mimeType, folderID string, r io.Reader, appProperties map[string]string, progress func(sent int64)) (*drive.File, error) {
+	fileMetadata := &drive.File{
+		Name:          fileName,
+		Parents:       []string{folderID},
+		MimeType:      mimeType,
+		AppProperties: appProperties,
+	}
+
+	upload := s.resumable()
+	upload.progress = progress
+	file, err := upload.upload(ctx, http.MethodPost, "", fileMetadata, r)
 	if err != nil {
 		return nil, fmt.Errorf("unable to upload file: %w", err)
 	}
@@ -85,20 +239,245 @@ func (s *GoogleDriveService) UploadFile(ctx context.Context, fileName, mimeType,
 	return file, nil
 }
 
+// UpdateFileContent uploads new content for an existing file. The file keeps
+// its ID, name, parents and permissions.
+func (s *GoogleDriveService) UpdateFileContent(ctx context.Context, fileID, mimeType, localPath string) (*drive.File, error) {
+	f, err := opener(s.files).Open(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %w", err)
+	}
+	defer f.Close()
+
+	file, err := s.resumable().upload(ctx, http.MethodPatch, fileID, &drive.File{MimeType: mimeType}, f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update file: %w", err)
+	}
+	return file, nil
+}
+
+// resumable returns the chunked uploader for this service's settings
+func (s *GoogleDriveService) resumable() *resumableUpload {
+	retries := s.chunkRetries
+	if retries == 0 {
+		retries = DefaultChunkRetries
+	}
+	return &resumableUpload{
+		client:     s.httpClient,
+		baseURL:    uploadURL,
+		chunkSize:  DefaultChunkSize,
+		maxRetries: retries,
+		backoff:    chunkBackoff,
+		sleep:      s.sleep,
+		log:        s.uploadLog,
+	}
+}
+
+// opener returns files, or the os package when it is nil
+func opener(files filesystem.Opener) filesystem.Opener {
+	if files == nil {
+		return filesystem.OSFS{}
+	}
+	return files
+}
+
+// UpdateAppProperties sets appProperties on a file. Drive merges them with
+// the properties the file already has.
+func (s *GoogleDriveService) UpdateAppProperties(ctx context.Context, fileID string, appProperties map[string]string) error {
+	_, err := s.service.Files.Update(fileID, &drive.File{AppProperties: appProperties}).
+		Fields("id").
+		Context(ctx).
+		Do()
+	return err
+}
+
+// UpdateName renames a file, keeping its ID and sharing link
+func (s *GoogleDriveService) UpdateName(ctx context.Context, fileID, name string) error {
+	_, err := s.service.Files.Update(fileID, &drive.File{Name: name}).
+		Fields("id").
+		Context(ctx).
+		Do()
+	return err
+}
+
+// CreateFolder creates a folder inside parentID
+func (s *GoogleDriveService) CreateFolder(ctx context.Context, name, parentID string) (*drive.File, error) {
+	return s.service.Files.Create(&drive.File{
+		Name:     name,
+		MimeType: distribution.MimeTypeFolder,
+		Parents:  []string{parentID},
+	}).Fields("id, name, mimeType, createdTime").Context(ctx).Do()
+}
+
+// GetFile looks up one file or folder by ID
+func (s *GoogleDriveService) GetFile(ctx context.Context, fileID, fields string) (*drive.File, error) {
+	return s.service.Files.Get(fileID).Fields(googleapi.Field(fields)).Context(ctx).Do()
+}
+
+// MoveFile moves a file from one folder to another, keeping its ID and sharing
+func (s *GoogleDriveService) MoveFile(ctx context.Context, fileID, addParentID, removeParentID string) error {
+	_, err := s.service.Files.Update(fileID, &drive.File{}).
+		AddParents(addParentID).
+		RemoveParents(removeParentID).
+		Fields("id").
+		Context(ctx).
+		Do()
+	return err
+}
+
+// DownloadFile writes a file's content to w
+func (s *GoogleDriveService) DownloadFile(ctx context.Context, fileID string, w io.Writer) error {
+	resp, err := s.service.Files.Get(fileID).Context(ctx).Download()
+	if err != nil {
+		return fmt.Errorf("unable to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("unable to download file: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions returns all of a file's revisions
+func (s *GoogleDriveService) ListRevisions(ctx context.Context, fileID string) ([]*drive.Revision, error) {
+	var revisions []*drive.Revision
+	err := s.service.Revisions.List(fileID).
+		Fields("nextPageToken, revisions(id, modifiedTime, size, md5Checksum, keepForever)").
+		Context(ctx).
+		Pages(ctx, func(page *drive.RevisionList) error {
+			revisions = append(revisions, page.Revisions...)
+			return nil
+		})
+	return revisions, err
+}
+
+// UpdateRevision pins or unpins a revision
+func (s *GoogleDriveService) UpdateRevision(ctx context.Context, fileID, revisionID string, keepForever bool) error {
+	_, err := s.service.Revisions.Update(fileID, revisionID, &drive.Revision{KeepForever: keepForever, ForceSendFields: []string{"KeepForever"}}).
+		Fields("id").
+		Context(ctx).
+		Do()
+	return err
+}
+
+// DownloadRevision writes a revision's content to w
+func (s *GoogleDriveService) DownloadRevision(ctx context.Context, fileID, revisionID string, w io.Writer) error {
+	resp, err := s.service.Revisions.Get(fileID, revisionID).Context(ctx).Download()
+	if err != nil {
+		return fmt.Errorf("unable to download revision: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("unable to download revision: %w", err)
+	}
+	return nil
+}
+
 // CreatePermission creates a permission on a file
 func (s *GoogleDriveService) CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) error {
 	_, err := s.service.Permissions.Create(fileID, permission).Context(ctx).Do()
 	return err
 }
 
+// ListPermissions lists the permissions on a file
+func (s *GoogleDriveService) ListPermissions(ctx context.Context, fileID string) ([]*drive.Permission, error) {
+	var permissions []*drive.Permission
+	err := s.service.Permissions.List(fileID).
+		Fields("nextPageToken, permissions(id, type, role, emailAddress)").
+		Context(ctx).
+		Pages(ctx, func(list *drive.PermissionList) error {
+			permissions = append(permissions, list.Permissions...)
+			return nil
+		})
+	return permissions, err
+}
+
+// DeletePermission removes a permission from a file
+func (s *GoogleDriveService) DeletePermission(ctx context.Context, fileID, permissionID string) error {
+	return s.service.Permissions.Delete(fileID, permissionID).Context(ctx).Do()
+}
+
 // Client implements distribution.DriveClient using Google Drive API
 type Client struct {
-	driveService DriveService
+	driveService   DriveService
+	nonInteractive bool
+	scopeMode      string
+	auditLog       audit.Recorder
+	chunkRetries   int
+	uploadLog      io.Writer
+	sessionDir     string
+
+	files filesystem.Opener
+	now   func() time.Time
+	sleep clock.Sleeper
 }
 
 // ClientOption is a functional option for configuring Client
 type ClientOption func(*Client)
 
+// WithNonInteractiveAuth makes NewClientWithOAuth fail with ErrAuthRequired
+// instead of opening a browser when the saved token is missing or invalid
+func WithNonInteractiveAuth() ClientOption {
+	return func(c *Client) {
+		c.nonInteractive = true
+	}
+}
+
+// WithAuditLog records deletions, trash emptying and sharing changes
+func WithAuditLog(recorder audit.Recorder) ClientOption {
+	return func(c *Client) {
+		c.auditLog = recorder
+	}
+}
+
+// WithChunkRetries sets how many times a failed upload chunk is re-sent
+// before the upload gives up (default DefaultChunkRetries)
+func WithChunkRetries(n int) ClientOption {
+	return func(c *Client) {
+		c.chunkRetries = n
+	}
+}
+
+// WithUploadLog writes upload progress and chunk retries to w
+func WithUploadLog(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.uploadLog = w
+	}
+}
+
+// WithUploadSessions saves the sessions of uploads from local files in dir,
+// so an upload cut off by a dropped connection resumes on the next run
+func WithUploadSessions(dir string) ClientOption {
+	return func(c *Client) {
+		c.sessionDir = dir
+	}
+}
+
+// WithFileOpener reads uploads and writes downloads and the OAuth token
+// through files instead of the os package (for testing)
+func WithFileOpener(files filesystem.Opener) ClientOption {
+	return func(c *Client) {
+		c.files = files
+	}
+}
+
+// WithClock sets the time source that decides whether the saved OAuth token
+// has expired (for testing)
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// WithSleeper sets how the client waits between upload chunk retries, so
+// backoff can be tested without waiting
+func WithSleeper(sleep clock.Sleeper) ClientOption {
+	return func(c *Client) {
+		c.sleep = sleep
+	}
+}
+
 // WithDriveService sets a custom drive service (for testing)
 func WithDriveService(svc DriveService) ClientOption {
 	return func(c *Client) {
@@ -121,12 +500,22 @@ func NewClient(ctx context.Context, credentialsPath string, opts ...ClientOption
 		if err != nil {
 			return nil, err
 		}
+		c.configure(svc)
 		c.driveService = svc
 	}
 
 	return c, nil
 }
 
+// configure passes the client's upload settings on to the service it created
+func (c *Client) configure(svc *GoogleDriveService) {
+	svc.chunkRetries, svc.uploadLog = c.chunkRetries, c.uploadLog
+	if c.sessionDir != "" {
+		svc.sessions = NewSessionStore(c.sessionDir)
+	}
+	svc.files, svc.sleep = c.files, c.sleep
+}
+
 // newGoogleDriveService creates a production Google Drive service
 func newGoogleDriveService(ctx context.Context, credentialsPath string) (*GoogleDriveService, error) {
 	b, err := os.ReadFile(credentialsPath)
@@ -145,27 +534,53 @@ func newGoogleDriveService(ctx context.Context, credentialsPath string) (*Google
 		return nil, fmt.Errorf("unable to create drive service: %w", err)
 	}
 
-	return &GoogleDriveService{service: srv}, nil
+	return &GoogleDriveService{service: srv, httpClient: client}, nil
 }
 
+// fileFields are the Drive file fields requested for FileInfo conversion
+const fileFields = "id, name, mimeType, size, createdTime, appProperties"
+
 // ListFiles implements distribution.DriveClient
 func (c *Client) ListFiles(ctx context.Context, folderID string) ([]distribution.FileInfo, error) {
 	query := fmt.Sprintf("'%s' in parents and trashed = false", folderID)
-	files, err := c.driveService.ListFiles(ctx, query, "id, name, mimeType, size, createdTime", "name")
+	files, err := c.driveService.ListFiles(ctx, query, fileFields, "name")
 	if err != nil {
-		return nil, fmt.Errorf("failed to list files: %w", err)
+		return nil, fmt.Errorf("failed to list files: %w", c.scopeError(err, "listing the services folder"))
 	}
 
 	var result []distribution.FileInfo
 	for _, f := range files {
-		createdTime := parseTime(f.CreatedTime)
-		result = append(result, distribution.FileInfo{
-			ID:          f.Id,
-			Name:        f.Name,
-			MimeType:    f.MimeType,
-			Size:        f.Size,
-			CreatedTime: createdTime,
-		})
+		result = append(result, toFileInfo(f))
+	}
+	return result, nil
+}
+
+// ListTrashed implements distribution.TrashLister
+func (c *Client) ListTrashed(ctx context.Context, folderID string) ([]distribution.FileInfo, error) {
+	query := fmt.Sprintf("'%s' in parents and trashed = true", folderID)
+	files, err := c.driveService.ListFiles(ctx, query, fileFields, "name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trashed files: %w", c.scopeError(err, "listing the trash"))
+	}
+
+	var result []distribution.FileInfo
+	for _, f := range files {
+		result = append(result, toFileInfo(f))
+	}
+	return result, nil
+}
+
+// ListPublic implements distribution.PublicLister
+func (c *Client) ListPublic(ctx context.Context, folderID string) ([]distribution.FileInfo, error) {
+	query := fmt.Sprintf("'%s' in parents and visibility = 'anyoneWithLink' and trashed = false", folderID)
+	files, err := c.driveService.ListFiles(ctx, query, fileFields, "name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared files: %w", c.scopeError(err, "listing shared files"))
+	}
+
+	var result []distribution.FileInfo
+	for _, f := range files {
+		result = append(result, toFileInfo(f))
 	}
 	return result, nil
 }
@@ -175,9 +590,9 @@ func (c *Client) ListFiles(ctx context.Context, folderID string) ([]distribution
 func (c *Client) FindFileByName(ctx context.Context, folderID, fileName string) (*distribution.FileInfo, error) {
 	// Use Drive API query to filter by exact name
 	query := fmt.Sprintf("'%s' in parents and name = '%s' and trashed = false", folderID, fileName)
-	files, err := c.driveService.ListFiles(ctx, query, "id, name, mimeType, size, createdTime", "name")
+	files, err := c.driveService.ListFiles(ctx, query, fileFields, "name")
 	if err != nil {
-		return nil, fmt.Errorf("failed to find file by name: %w", err)
+		return nil, fmt.Errorf("failed to find file by name: %w", c.scopeError(err, "looking up "+fileName))
 	}
 
 	if len(files) == 0 {
@@ -185,14 +600,36 @@ func (c *Client) FindFileByName(ctx context.Context, folderID, fileName string)
 	}
 
 	// Return first match (should only be one with exact name match)
-	f := files[0]
-	return &distribution.FileInfo{
-		ID:          f.Id,
-		Name:        f.Name,
-		MimeType:    f.MimeType,
-		Size:        f.Size,
-		CreatedTime: parseTime(f.CreatedTime),
-	}, nil
+	info := toFileInfo(files[0])
+	return &info, nil
+}
+
+// FindFilesByProperty implements distribution.DriveClient
+// Matches files whose appProperties contain key=value, regardless of file name
+func (c *Client) FindFilesByProperty(ctx context.Context, folderID, key, value string) ([]distribution.FileInfo, error) {
+	query := fmt.Sprintf("'%s' in parents and appProperties has { key='%s' and value='%s' } and trashed = false", folderID, key, value)
+	files, err := c.driveService.ListFiles(ctx, query, fileFields, "name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find files by property: %w", c.scopeError(err, "searching the services folder"))
+	}
+
+	result := make([]distribution.FileInfo, 0, len(files))
+	for _, f := range files {
+		result = append(result, toFileInfo(f))
+	}
+	return result, nil
+}
+
+// toFileInfo converts a Drive API file to the domain FileInfo
+func toFileInfo(f *drive.File) distribution.FileInfo {
+	return distribution.FileInfo{
+		ID:            f.Id,
+		Name:          f.Name,
+		MimeType:      f.MimeType,
+		Size:          f.Size,
+		CreatedTime:   parseTime(f.CreatedTime),
+		AppProperties: f.AppProperties,
+	}
 }
 
 // parseTime parses a Google Drive timestamp string
@@ -226,21 +663,14 @@ func (c *Client) GetStorageQuota(ctx context.Context) (*distribution.StorageInfo
 // Handles both "YYYY-MM-DD.mp4" and "YYYY-MM-DD HH-MM-SS.mp4" formats
 func (c *Client) ListMP4Files(ctx context.Context, folderID string) ([]distribution.FileInfo, error) {
 	query := fmt.Sprintf("'%s' in parents and mimeType='video/mp4' and trashed=false", folderID)
-	files, err := c.driveService.ListFiles(ctx, query, "id, name, mimeType, size, createdTime", "name")
+	files, err := c.driveService.ListFiles(ctx, query, fileFields, "name")
 	if err != nil {
-		return nil, fmt.Errorf("failed to list mp4 files: %w", err)
+		return nil, fmt.Errorf("failed to list mp4 files: %w", c.scopeError(err, "listing videos"))
 	}
 
 	var result []distribution.FileInfo
 	for _, f := range files {
-		createdTime := parseTime(f.CreatedTime)
-		result = append(result, distribution.FileInfo{
-			ID:          f.Id,
-			Name:        f.Name,
-			MimeType:    f.MimeType,
-			Size:        f.Size,
-			CreatedTime: createdTime,
-		})
+		result = append(result, toFileInfo(f))
 	}
 
 	// Files are already sorted by name from Google Drive API
@@ -250,33 +680,291 @@ func (c *Client) ListMP4Files(ctx context.Context, folderID string) ([]distribut
 
 // DeletePermanently implements distribution.DriveClient
 func (c *Client) DeletePermanently(ctx context.Context, fileID string) error {
-	if err := c.driveService.DeleteFile(ctx, fileID); err != nil {
-		return fmt.Errorf("unable to delete file: %w", err)
+	err := c.driveService.DeleteFile(ctx, fileID)
+	if err != nil {
+		err = fmt.Errorf("unable to delete file: %w", c.scopeError(err, "deleting a file"))
 	}
-	return nil
+	return audit.Record(c.auditLog, audit.ActionDriveDelete, fileID, "", err)
 }
 
-// EmptyTrash implements distribution.DriveClient
+// EmptyTrash implements distribution.DriveClient. The drive.file scope
+// cannot empty the trash.
 func (c *Client) EmptyTrash(ctx context.Context) error {
-	if err := c.driveService.EmptyTrash(ctx); err != nil {
-		return fmt.Errorf("unable to empty trash: %w", err)
+	if c.AppFilesOnly() {
+		return fmt.Errorf("%w: emptying the trash needs google.scope_mode: full", distribution.ErrInsufficientScope)
 	}
-	return nil
+	err := c.driveService.EmptyTrash(ctx)
+	if err != nil {
+		err = fmt.Errorf("unable to empty trash: %w", err)
+	}
+	return audit.Record(c.auditLog, audit.ActionEmptyTrash, "", "", err)
 }
 
-// Upload implements distribution.DriveClient
+// Upload implements distribution.DriveClient. req.Progress is called only
+// when the service can report progress.
 func (c *Client) Upload(ctx context.Context, req distribution.UploadRequest) (*distribution.UploadResult, error) {
-	file, err := c.driveService.UploadFile(ctx, req.FileName, req.MimeType, req.FolderID, req.LocalPath)
+	var file *drive.File
+	var err error
+	if uploader, ok := c.driveService.(ProgressUploader); ok && req.Progress != nil {
+		file, err = uploader.UploadFileWithProgress(ctx, req.FileName, req.MimeType, req.FolderID, req.LocalPath, req.AppProperties, req.Progress)
+	} else {
+		file, err = c.driveService.UploadFile(ctx, req.FileName, req.MimeType, req.FolderID, req.LocalPath, req.AppProperties)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload file: %w", err)
+		return nil, fmt.Errorf("failed to upload file: %w", c.scopeError(err, "uploading "+req.FileName))
 	}
 
+	return toUploadResult(file), nil
+}
+
+// UploadStream implements distribution.StreamUploader
+func (c *Client) UploadStream(ctx context.Context, req distribution.UploadRequest, r io.Reader) (*distribution.UploadResult, error) {
+	uploader, ok := c.driveService.(ReaderUploader)
+	if !ok {
+		return nil, distribution.ErrStreamingUnsupported
+	}
+	file, err := uploader.UploadReader(ctx, req.FileName, req.MimeType, req.FolderID, r, req.AppProperties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload stream: %w", c.scopeError(err, "uploading "+req.FileName))
+	}
+	return toUploadResult(file), nil
+}
+
+// ReplaceContent implements distribution.ContentReplacer
+func (c *Client) ReplaceContent(ctx context.Context, fileID string, req distribution.UploadRequest) (*distribution.UploadResult, error) {
+	updater, ok := c.driveService.(ContentUpdater)
+	if !ok {
+		return nil, distribution.ErrReplaceUnsupported
+	}
+	file, err := updater.UpdateFileContent(ctx, fileID, req.MimeType, req.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace file content: %w", c.scopeError(err, "replacing "+req.FileName))
+	}
+	return toUploadResult(file), nil
+}
+
+// SetAppProperties implements distribution.PropertyTagger
+func (c *Client) SetAppProperties(ctx context.Context, fileID string, props map[string]string) error {
+	updater, ok := c.driveService.(PropertyUpdater)
+	if !ok {
+		return distribution.ErrTaggingUnsupported
+	}
+	if err := updater.UpdateAppProperties(ctx, fileID, props); err != nil {
+		return fmt.Errorf("failed to tag file: %w", c.scopeError(err, "tagging "+fileID))
+	}
+	return nil
+}
+
+// RenameFile implements distribution.FileRenamer
+func (c *Client) RenameFile(ctx context.Context, fileID, newName string) error {
+	updater, ok := c.driveService.(NameUpdater)
+	if !ok {
+		return distribution.ErrRenameUnsupported
+	}
+	if err := updater.UpdateName(ctx, fileID, newName); err != nil {
+		return fmt.Errorf("failed to rename file: %w", c.scopeError(err, "renaming "+fileID))
+	}
+	return nil
+}
+
+// CreateFolder implements distribution.FolderOrganizer
+func (c *Client) CreateFolder(ctx context.Context, parentID, name string) (*distribution.FileInfo, error) {
+	mover, ok := c.driveService.(FolderMover)
+	if !ok {
+		return nil, distribution.ErrFoldersUnsupported
+	}
+	folder, err := mover.CreateFolder(ctx, name, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create folder %s: %w", name, c.scopeError(err, "creating a folder"))
+	}
+	info := toFileInfo(folder)
+	return &info, nil
+}
+
+// FolderDetails implements distribution.FolderInspector
+func (c *Client) FolderDetails(ctx context.Context, folderID string) (*distribution.FolderDetails, error) {
+	getter, ok := c.driveService.(FileGetter)
+	if !ok {
+		return nil, distribution.ErrFolderLookupUnsupported
+	}
+	folder, err := getter.GetFile(ctx, folderID, folderFields)
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound && !c.AppFilesOnly() {
+			return nil, fmt.Errorf("%w: no folder with ID %s is visible to this account", distribution.ErrFolderNotFound, folderID)
+		}
+		return nil, fmt.Errorf("failed to look up folder %s: %w", folderID, c.scopeError(err, "looking up the services folder"))
+	}
+
+	details := &distribution.FolderDetails{ID: folder.Id, Name: folder.Name, MimeType: folder.MimeType}
+	if folder.Capabilities != nil {
+		details.CanAddChildren = folder.Capabilities.CanAddChildren
+	}
+	return details, nil
+}
+
+// MoveFile implements distribution.FolderOrganizer
+func (c *Client) MoveFile(ctx context.Context, fileID, fromFolderID, toFolderID string) error {
+	mover, ok := c.driveService.(FolderMover)
+	if !ok {
+		return distribution.ErrFoldersUnsupported
+	}
+	if err := mover.MoveFile(ctx, fileID, toFolderID, fromFolderID); err != nil {
+		return fmt.Errorf("failed to move file: %w", c.scopeError(err, "moving "+fileID))
+	}
+	return nil
+}
+
+// ShareWithUser implements distribution.UserSharer
+func (c *Client) ShareWithUser(ctx context.Context, fileID, address string) error {
+	permission := &drive.Permission{
+		Type:         "user",
+		Role:         "reader",
+		EmailAddress: address,
+	}
+
+	err := c.driveService.CreatePermission(ctx, fileID, permission)
+	if err != nil {
+		err = fmt.Errorf("unable to share with %s: %w", address, c.scopeError(err, "sharing a file"))
+	}
+	return audit.Record(c.auditLog, audit.ActionShare, fileID, permission.Type+":"+permission.Role+":"+address, err)
+}
+
+// RevokeReaders implements distribution.ReaderRevoker. Only people given
+// read access lose it; the owner, editors and link sharing are left alone.
+func (c *Client) RevokeReaders(ctx context.Context, fileID string) ([]string, error) {
+	editor, ok := c.driveService.(PermissionEditor)
+	if !ok {
+		return nil, distribution.ErrRevokeUnsupported
+	}
+	permissions, err := editor.ListPermissions(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list who can open %s: %w", fileID, c.scopeError(err, "listing a file's sharing"))
+	}
+
+	var revoked []string
+	for _, p := range permissions {
+		if p.Type != "user" || p.Role != "reader" {
+			continue
+		}
+		err := editor.DeletePermission(ctx, fileID, p.Id)
+		if err == nil {
+			revoked = append(revoked, p.EmailAddress)
+		} else {
+			err = fmt.Errorf("unable to stop sharing with %s: %w", p.EmailAddress, c.scopeError(err, "changing a file's sharing"))
+		}
+		if err := audit.Record(c.auditLog, audit.ActionUnshare, fileID, p.Type+":"+p.Role+":"+p.EmailAddress, err); err != nil {
+			return revoked, err
+		}
+	}
+	return revoked, nil
+}
+
+// Download implements distribution.Downloader. A failed download leaves no
+// partial file behind.
+func (c *Client) Download(ctx context.Context, fileID, localPath string) (err error) {
+	downloader, ok := c.driveService.(FileDownloader)
+	if !ok {
+		return distribution.ErrDownloadUnsupported
+	}
+
+	files := opener(c.files)
+	f, err := files.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", localPath, err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("failed to write %s: %w", localPath, cerr)
+		}
+		if err != nil {
+			files.Remove(localPath)
+		}
+	}()
+
+	if err := downloader.DownloadFile(ctx, fileID, f); err != nil {
+		return fmt.Errorf("failed to download file: %w", c.scopeError(err, "downloading a file"))
+	}
+	return nil
+}
+
+// ListRevisions implements distribution.RevisionManager
+func (c *Client) ListRevisions(ctx context.Context, fileID string) ([]distribution.Revision, error) {
+	editor, ok := c.driveService.(RevisionEditor)
+	if !ok {
+		return nil, distribution.ErrRevisionsUnsupported
+	}
+	found, err := editor.ListRevisions(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", c.scopeError(err, "listing revisions"))
+	}
+	revisions := make([]distribution.Revision, 0, len(found))
+	for _, r := range found {
+		modified, _ := time.Parse(time.RFC3339, r.ModifiedTime)
+		revisions = append(revisions, distribution.Revision{
+			ID:           r.Id,
+			ModifiedTime: modified,
+			Size:         r.Size,
+			MD5Checksum:  r.Md5Checksum,
+			KeepForever:  r.KeepForever,
+		})
+	}
+	distribution.SortRevisions(revisions)
+	return revisions, nil
+}
+
+// KeepRevision implements distribution.RevisionManager
+func (c *Client) KeepRevision(ctx context.Context, fileID, revisionID string, keep bool) error {
+	editor, ok := c.driveService.(RevisionEditor)
+	if !ok {
+		return distribution.ErrRevisionsUnsupported
+	}
+	if err := editor.UpdateRevision(ctx, fileID, revisionID, keep); err != nil {
+		return fmt.Errorf("failed to update revision %s: %w", revisionID, c.scopeError(err, "pinning a revision"))
+	}
+	return nil
+}
+
+// RestoreRevision implements distribution.RevisionManager. Drive can't roll a
+// file back itself, so the revision is downloaded and uploaded as new content.
+func (c *Client) RestoreRevision(ctx context.Context, fileID, revisionID, mimeType string) (*distribution.UploadResult, error) {
+	editor, ok := c.driveService.(RevisionEditor)
+	if !ok {
+		return nil, distribution.ErrRevisionsUnsupported
+	}
+	updater, ok := c.driveService.(ContentUpdater)
+	if !ok {
+		return nil, distribution.ErrReplaceUnsupported
+	}
+
+	tmp, err := os.CreateTemp("", "revision-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	err = editor.DownloadRevision(ctx, fileID, revisionID, tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download revision %s: %w", revisionID, c.scopeError(err, "downloading a revision"))
+	}
+
+	file, err := updater.UpdateFileContent(ctx, fileID, mimeType, tmp.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore revision %s: %w", revisionID, c.scopeError(err, "restoring a revision"))
+	}
+	return toUploadResult(file), nil
+}
+
+func toUploadResult(file *drive.File) *distribution.UploadResult {
 	return &distribution.UploadResult{
 		FileID:       file.Id,
 		FileName:     file.Name,
 		ShareableURL: file.WebViewLink,
 		Size:         file.Size,
-	}, nil
+		MD5Checksum:  file.Md5Checksum,
+	}
 }
 
 // SetPublicSharing implements distribution.DriveClient
@@ -286,10 +974,11 @@ func (c *Client) SetPublicSharing(ctx context.Context, fileID string) error {
 		Role: "reader",
 	}
 
-	if err := c.driveService.CreatePermission(ctx, fileID, permission); err != nil {
-		return fmt.Errorf("unable to set sharing permission: %w", err)
+	err := c.driveService.CreatePermission(ctx, fileID, permission)
+	if err != nil {
+		err = fmt.Errorf("unable to set sharing permission: %w", c.scopeError(err, "sharing a file"))
 	}
-	return nil
+	return audit.Record(c.auditLog, audit.ActionShare, fileID, permission.Type+":"+permission.Role, err)
 }
 
 // UploadAndShare implements distribution.DriveClient
@@ -304,9 +993,38 @@ func (c *Client) UploadAndShare(ctx context.Context, req distribution.UploadRequ
 	}
 
 	// Generate proper sharing URL
-	result.ShareableURL = fmt.Sprintf("https://drive.google.com/file/d/%s/view?usp=sharing", result.FileID)
+	result.ShareableURL = distribution.ShareableURL(result.FileID)
 	return result, nil
 }
 
-// Ensure Client implements distribution.DriveClient
-var _ distribution.DriveClient = (*Client)(nil)
+// Ensure Client implements distribution.DriveClient and its optional capabilities
+var (
+	_ distribution.DriveClient     = (*Client)(nil)
+	_ distribution.StreamUploader  = (*Client)(nil)
+	_ distribution.ContentReplacer = (*Client)(nil)
+	_ distribution.RevisionManager = (*Client)(nil)
+	_ distribution.Downloader      = (*Client)(nil)
+	_ distribution.AppFileScoped   = (*Client)(nil)
+	_ distribution.PropertyTagger  = (*Client)(nil)
+	_ distribution.FileRenamer     = (*Client)(nil)
+	_ distribution.TrashLister     = (*Client)(nil)
+	_ distribution.PublicLister    = (*Client)(nil)
+	_ distribution.FolderOrganizer = (*Client)(nil)
+	_ distribution.UserSharer      = (*Client)(nil)
+	_ distribution.ReaderRevoker   = (*Client)(nil)
+	_ distribution.FolderInspector = (*Client)(nil)
+)
+
+// Ensure GoogleDriveService implements the optional service capabilities
+var (
+	_ ReaderUploader   = (*GoogleDriveService)(nil)
+	_ ProgressUploader = (*GoogleDriveService)(nil)
+	_ ContentUpdater   = (*GoogleDriveService)(nil)
+	_ FileDownloader   = (*GoogleDriveService)(nil)
+	_ PropertyUpdater  = (*GoogleDriveService)(nil)
+	_ RevisionEditor   = (*GoogleDriveService)(nil)
+	_ NameUpdater      = (*GoogleDriveService)(nil)
+	_ FolderMover      = (*GoogleDriveService)(nil)
+	_ PermissionEditor = (*GoogleDriveService)(nil)
+	_ FileGetter       = (*GoogleDriveService)(nil)
+)
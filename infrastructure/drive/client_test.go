@@ -2,11 +2,20 @@ package drive
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"nac-service-media/domain/audit"
+	"nac-service-media/domain/distribution"
+
+	"golang.org/x/oauth2"
 	"google.golang.org/api/drive/v3"
 )
 
@@ -19,12 +28,27 @@ type mockDriveService struct {
 	storageUsage   int64
 	deletedFileIDs []string
 	trashEmptied   bool
+	lastQuery      string
 }
 
+// appPropertyQueryRegex extracts key/value from an "appProperties has" query clause
+var appPropertyQueryRegex = regexp.MustCompile(`appProperties has \{ key='([^']*)' and value='([^']*)' \}`)
+
 func (m *mockDriveService) ListFiles(ctx context.Context, query string, fields string, orderBy string) ([]*drive.File, error) {
+	m.lastQuery = query
 	if m.shouldFail {
 		return nil, m.failError
 	}
+	// Filter files by appProperty (for FindFilesByProperty support)
+	if matches := appPropertyQueryRegex.FindStringSubmatch(query); matches != nil {
+		var result []*drive.File
+		for _, f := range m.files {
+			if f.AppProperties[matches[1]] == matches[2] {
+				result = append(result, f)
+			}
+		}
+		return result, nil
+	}
 	// Filter files by name if query contains "name = " (for FindFileByName support)
 	if strings.Contains(query, "name = ") {
 		// Extract the filename from the query
@@ -72,7 +96,7 @@ func (m *mockDriveService) EmptyTrash(ctx context.Context) error {
 	return nil
 }
 
-func (m *mockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string) (*drive.File, error) {
+func (m *mockDriveService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*drive.File, error) {
 	if m.shouldFail {
 		return nil, m.failError
 	}
@@ -512,6 +536,51 @@ func TestClient_EmptyTrash(t *testing.T) {
 	}
 }
 
+type recordingAuditLog struct {
+	events []audit.Event
+}
+
+func (r *recordingAuditLog) Record(e audit.Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+func TestClient_AuditLog(t *testing.T) {
+	log := &recordingAuditLog{}
+	mock := &mockDriveService{}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock), WithAuditLog(log))
+
+	if err := client.DeletePermanently(context.Background(), "file1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.EmptyTrash(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.SetPublicSharing(context.Background(), "file2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mock.shouldFail = true
+	mock.failError = fmt.Errorf("API error")
+	if err := client.DeletePermanently(context.Background(), "file3"); err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	want := []audit.Event{
+		{Action: audit.ActionDriveDelete, Target: "file1", Outcome: audit.OutcomeSuccess},
+		{Action: audit.ActionEmptyTrash, Outcome: audit.OutcomeSuccess},
+		{Action: audit.ActionShare, Target: "file2", Detail: "anyone:reader", Outcome: audit.OutcomeSuccess},
+		{Action: audit.ActionDriveDelete, Target: "file3", Outcome: audit.OutcomeFailed, Error: "unable to delete file: API error"},
+	}
+	if len(log.events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), log.events)
+	}
+	for i, w := range want {
+		if log.events[i] != w {
+			t.Errorf("event %d: expected %+v, got %+v", i+1, w, log.events[i])
+		}
+	}
+}
+
 func TestClient_FindFileByName(t *testing.T) {
 	testTime := time.Date(2025, 12, 28, 10, 0, 0, 0, time.UTC)
 
@@ -609,3 +678,468 @@ func TestClient_FindFileByName(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_FindFilesByProperty(t *testing.T) {
+	mock := &mockDriveService{
+		files: []*drive.File{
+			{
+				Id:            "file-1",
+				Name:          "Christmas Service.mp4",
+				MimeType:      "video/mp4",
+				AppProperties: map[string]string{"service_date": "2025-12-28"},
+			},
+			{
+				Id:            "file-2",
+				Name:          "2025-12-28.mp3",
+				MimeType:      "audio/mpeg",
+				AppProperties: map[string]string{"service_date": "2025-12-28"},
+			},
+			{
+				Id:            "file-3",
+				Name:          "2025-12-21.mp4",
+				MimeType:      "video/mp4",
+				AppProperties: map[string]string{"service_date": "2025-12-21"},
+			},
+			{
+				Id:   "file-4",
+				Name: "2025-12-28 untagged.mp4",
+			},
+		},
+	}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	files, err := client.FindFilesByProperty(context.Background(), "test-folder-id", "service_date", "2025-12-28")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if files[0].Name != "Christmas Service.mp4" {
+		t.Errorf("expected renamed file to match by property, got %q", files[0].Name)
+	}
+	if files[0].AppProperties["service_date"] != "2025-12-28" {
+		t.Errorf("expected AppProperties to be populated, got %v", files[0].AppProperties)
+	}
+	if !strings.Contains(mock.lastQuery, "'test-folder-id' in parents") || !strings.Contains(mock.lastQuery, "trashed = false") {
+		t.Errorf("query should be scoped to folder and exclude trash, got %q", mock.lastQuery)
+	}
+}
+
+func TestClient_FindFilesByProperty_NoMatches(t *testing.T) {
+	mock := &mockDriveService{files: []*drive.File{}}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	files, err := client.FindFilesByProperty(context.Background(), "test-folder-id", "service_date", "2025-12-28")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if files == nil || len(files) != 0 {
+		t.Errorf("expected empty non-nil slice, got %v", files)
+	}
+}
+
+func TestClient_FindFilesByProperty_APIError(t *testing.T) {
+	mock := &mockDriveService{shouldFail: true, failError: fmt.Errorf("API error")}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	_, err := client.FindFilesByProperty(context.Background(), "test-folder-id", "service_date", "2025-12-28")
+	if err == nil || !containsString(err.Error(), "failed to find files by property") {
+		t.Errorf("expected wrapped property lookup error, got %v", err)
+	}
+}
+
+func TestClient_ListTrashedAndPublic(t *testing.T) {
+	mock := &mockDriveService{files: []*drive.File{{Id: "file-1", Name: "2025-12-28.mp4", Size: 10}}}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	files, err := client.ListTrashed(context.Background(), "test-folder-id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 1 || files[0].ID != "file-1" {
+		t.Errorf("unexpected files: %+v", files)
+	}
+	if mock.lastQuery != "'test-folder-id' in parents and trashed = true" {
+		t.Errorf("unexpected trash query %q", mock.lastQuery)
+	}
+
+	if _, err := client.ListPublic(context.Background(), "test-folder-id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(mock.lastQuery, "visibility = 'anyoneWithLink'") || !strings.Contains(mock.lastQuery, "trashed = false") {
+		t.Errorf("unexpected public query %q", mock.lastQuery)
+	}
+}
+
+// streamingMockDriveService also implements ReaderUploader
+type streamingMockDriveService struct {
+	mockDriveService
+	received []byte
+}
+
+func (m *streamingMockDriveService) UploadReader(ctx context.Context, fileName, mimeType, folderID string, r io.Reader, appProperties map[string]string) (*drive.File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	m.received = data
+	return &drive.File{Id: "streamed-file-id", Name: fileName, Size: int64(len(data)), Md5Checksum: "d41d8cd9"}, nil
+}
+
+func TestClient_UploadStream(t *testing.T) {
+	mock := &streamingMockDriveService{}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	result, err := client.UploadStream(context.Background(), distribution.UploadRequest{FileName: "2025-12-28.mp3"}, strings.NewReader("mp3 data"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(mock.received) != "mp3 data" {
+		t.Errorf("uploaded %q, want the stream contents", mock.received)
+	}
+	if result.FileID != "streamed-file-id" || result.Size != 8 || result.MD5Checksum != "d41d8cd9" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestClient_UploadStream_Unsupported(t *testing.T) {
+	client, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+
+	_, err := client.UploadStream(context.Background(), distribution.UploadRequest{FileName: "2025-12-28.mp3"}, strings.NewReader("mp3 data"))
+	if !errors.Is(err, distribution.ErrStreamingUnsupported) {
+		t.Errorf("expected ErrStreamingUnsupported, got %v", err)
+	}
+}
+
+// replacingMockDriveService also implements ContentUpdater, FileDownloader
+// and PropertyUpdater
+type replacingMockDriveService struct {
+	mockDriveService
+	updatedID   string
+	updatedPath string
+	content     string
+	downloadErr error
+	taggedID    string
+	taggedProps map[string]string
+	renamedID   string
+	renamedTo   string
+}
+
+func (m *replacingMockDriveService) UpdateFileContent(ctx context.Context, fileID, mimeType, localPath string) (*drive.File, error) {
+	m.updatedID = fileID
+	m.updatedPath = localPath
+	return &drive.File{Id: fileID, Name: "2025-12-28.mp3", Size: 42}, nil
+}
+
+func (m *replacingMockDriveService) DownloadFile(ctx context.Context, fileID string, w io.Writer) error {
+	if _, err := io.WriteString(w, m.content); err != nil {
+		return err
+	}
+	return m.downloadErr
+}
+
+func (m *replacingMockDriveService) UpdateAppProperties(ctx context.Context, fileID string, appProperties map[string]string) error {
+	if m.shouldFail {
+		return m.failError
+	}
+	m.taggedID = fileID
+	m.taggedProps = appProperties
+	return nil
+}
+
+func (m *replacingMockDriveService) UpdateName(ctx context.Context, fileID, name string) error {
+	if m.shouldFail {
+		return m.failError
+	}
+	m.renamedID = fileID
+	m.renamedTo = name
+	return nil
+}
+
+func TestClient_ReplaceContent(t *testing.T) {
+	mock := &replacingMockDriveService{}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	result, err := client.ReplaceContent(context.Background(), "audio-id", distribution.UploadRequest{LocalPath: "/audio/2025-12-28.mp3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.updatedID != "audio-id" || mock.updatedPath != "/audio/2025-12-28.mp3" {
+		t.Errorf("updated %q from %q", mock.updatedID, mock.updatedPath)
+	}
+	if result.FileID != "audio-id" || result.Size != 42 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestClient_ReplaceContent_Unsupported(t *testing.T) {
+	client, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+
+	_, err := client.ReplaceContent(context.Background(), "audio-id", distribution.UploadRequest{})
+	if !errors.Is(err, distribution.ErrReplaceUnsupported) {
+		t.Errorf("expected ErrReplaceUnsupported, got %v", err)
+	}
+}
+
+func TestClient_Download(t *testing.T) {
+	mock := &replacingMockDriveService{content: "video data"}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+	path := filepath.Join(t.TempDir(), "2025-12-28.mp4")
+
+	if err := client.Download(context.Background(), "video-id", path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading download: %v", err)
+	}
+	if string(data) != "video data" {
+		t.Errorf("downloaded %q, want the file contents", data)
+	}
+}
+
+func TestClient_Download_FailureRemovesPartialFile(t *testing.T) {
+	mock := &replacingMockDriveService{content: "partial", downloadErr: errors.New("connection reset")}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+	path := filepath.Join(t.TempDir(), "2025-12-28.mp4")
+
+	if err := client.Download(context.Background(), "video-id", path); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("partial download was left at %s", path)
+	}
+}
+
+func TestClient_Download_ThroughFileOpener(t *testing.T) {
+	files := newMemOpener()
+	client, _ := NewClient(context.Background(), "",
+		WithDriveService(&replacingMockDriveService{content: "video data"}), WithFileOpener(files))
+
+	if err := client.Download(context.Background(), "video-id", "2025-12-28.mp4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(files.files["2025-12-28.mp4"]); got != "video data" {
+		t.Errorf("downloaded %q, want the file contents", got)
+	}
+}
+
+func TestClient_Download_Unsupported(t *testing.T) {
+	client, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+
+	err := client.Download(context.Background(), "video-id", filepath.Join(t.TempDir(), "x.mp4"))
+	if !errors.Is(err, distribution.ErrDownloadUnsupported) {
+		t.Errorf("expected ErrDownloadUnsupported, got %v", err)
+	}
+}
+
+func TestClient_SetAppProperties(t *testing.T) {
+	mock := &replacingMockDriveService{}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	props := map[string]string{distribution.PropertyServiceDate: "2024-03-10", distribution.PropertyMediaType: distribution.MediaTypeVideo}
+	if err := client.SetAppProperties(context.Background(), "video-id", props); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.taggedID != "video-id" || mock.taggedProps[distribution.PropertyServiceDate] != "2024-03-10" {
+		t.Errorf("tagged %q with %v", mock.taggedID, mock.taggedProps)
+	}
+}
+
+func TestClient_SetAppProperties_Error(t *testing.T) {
+	apiErr := errors.New("rate limit exceeded")
+	mock := &replacingMockDriveService{mockDriveService: mockDriveService{shouldFail: true, failError: apiErr}}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	err := client.SetAppProperties(context.Background(), "video-id", map[string]string{"k": "v"})
+	if !errors.Is(err, apiErr) {
+		t.Errorf("expected the API error to be wrapped, got %v", err)
+	}
+}
+
+func TestClient_SetAppProperties_Unsupported(t *testing.T) {
+	client, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+
+	err := client.SetAppProperties(context.Background(), "video-id", map[string]string{"k": "v"})
+	if !errors.Is(err, distribution.ErrTaggingUnsupported) {
+		t.Errorf("expected ErrTaggingUnsupported, got %v", err)
+	}
+}
+
+func TestClient_RenameFile(t *testing.T) {
+	mock := &replacingMockDriveService{}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	if err := client.RenameFile(context.Background(), "video-id", "2025-12-28.mp4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.renamedID != "video-id" || mock.renamedTo != "2025-12-28.mp4" {
+		t.Errorf("renamed %q to %q", mock.renamedID, mock.renamedTo)
+	}
+}
+
+func TestClient_RenameFile_Unsupported(t *testing.T) {
+	client, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+
+	err := client.RenameFile(context.Background(), "video-id", "2025-12-28.mp4")
+	if !errors.Is(err, distribution.ErrRenameUnsupported) {
+		t.Errorf("expected ErrRenameUnsupported, got %v", err)
+	}
+}
+
+func TestGetToken_NonInteractiveWithoutToken(t *testing.T) {
+	cfg := &oauth2.Config{ClientID: "id", Endpoint: oauth2.Endpoint{TokenURL: "http://127.0.0.1:0/token"}}
+	tokenFile := filepath.Join(t.TempDir(), "token.json")
+
+	_, err := getToken(context.Background(), cfg, tokenStore{file: tokenFile}, true)
+	if !errors.Is(err, ErrAuthRequired) {
+		t.Fatalf("getToken() error = %v, want ErrAuthRequired", err)
+	}
+}
+
+// revisionMockDriveService also implements RevisionEditor
+type revisionMockDriveService struct {
+	replacingMockDriveService
+	revisions       []*drive.Revision
+	revisionContent map[string]string
+	pinned          map[string]bool
+	restoredContent string
+}
+
+func (m *revisionMockDriveService) ListRevisions(ctx context.Context, fileID string) ([]*drive.Revision, error) {
+	return m.revisions, nil
+}
+
+func (m *revisionMockDriveService) UpdateRevision(ctx context.Context, fileID, revisionID string, keepForever bool) error {
+	if m.pinned == nil {
+		m.pinned = make(map[string]bool)
+	}
+	m.pinned[revisionID] = keepForever
+	return nil
+}
+
+func (m *revisionMockDriveService) DownloadRevision(ctx context.Context, fileID, revisionID string, w io.Writer) error {
+	_, err := io.WriteString(w, m.revisionContent[revisionID])
+	return err
+}
+
+func (m *revisionMockDriveService) UpdateFileContent(ctx context.Context, fileID, mimeType, localPath string) (*drive.File, error) {
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, err
+	}
+	m.restoredContent = string(data)
+	return m.replacingMockDriveService.UpdateFileContent(ctx, fileID, mimeType, localPath)
+}
+
+func TestClient_ListRevisions(t *testing.T) {
+	mock := &revisionMockDriveService{revisions: []*drive.Revision{
+		{Id: "r2", ModifiedTime: "2025-12-29T10:00:00Z", Size: 20, KeepForever: true},
+		{Id: "r1", ModifiedTime: "2025-12-28T13:00:00Z", Size: 10},
+	}}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	revisions, err := client.ListRevisions(context.Background(), "audio-id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].ID != "r1" || revisions[1].ID != "r2" {
+		t.Fatalf("expected revisions oldest first, got %+v", revisions)
+	}
+	if !revisions[1].KeepForever || revisions[1].Size != 20 || revisions[1].ModifiedTime.Day() != 29 {
+		t.Errorf("unexpected revision %+v", revisions[1])
+	}
+}
+
+func TestClient_KeepRevision(t *testing.T) {
+	mock := &revisionMockDriveService{}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	if err := client.KeepRevision(context.Background(), "audio-id", "r1", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mock.pinned["r1"] {
+		t.Errorf("expected r1 to be pinned, got %v", mock.pinned)
+	}
+}
+
+func TestClient_RestoreRevision(t *testing.T) {
+	mock := &revisionMockDriveService{revisionContent: map[string]string{"r1": "original audio"}}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	result, err := client.RestoreRevision(context.Background(), "audio-id", "r1", distribution.MimeTypeMP3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.restoredContent != "original audio" || mock.updatedID != "audio-id" {
+		t.Errorf("expected audio-id to get r1's content, got %q on %q", mock.restoredContent, mock.updatedID)
+	}
+	if result.FileID != "audio-id" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if _, err := os.Stat(mock.updatedPath); !os.IsNotExist(err) {
+		t.Errorf("expected the downloaded revision to be removed, got %v", err)
+	}
+}
+
+func TestClient_Revisions_Unsupported(t *testing.T) {
+	client, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+
+	if _, err := client.ListRevisions(context.Background(), "audio-id"); !errors.Is(err, distribution.ErrRevisionsUnsupported) {
+		t.Errorf("expected ErrRevisionsUnsupported, got %v", err)
+	}
+	if err := client.KeepRevision(context.Background(), "audio-id", "r1", true); !errors.Is(err, distribution.ErrRevisionsUnsupported) {
+		t.Errorf("expected ErrRevisionsUnsupported, got %v", err)
+	}
+}
+
+func TestClient_RevokeReaders_Unsupported(t *testing.T) {
+	client, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+
+	if _, err := client.RevokeReaders(context.Background(), "audio-id"); !errors.Is(err, distribution.ErrRevokeUnsupported) {
+		t.Errorf("expected ErrRevokeUnsupported, got %v", err)
+	}
+}
+
+// folderMockDriveService also implements FileGetter
+type folderMockDriveService struct {
+	mockDriveService
+	folder *drive.File
+	fields string
+}
+
+func (m *folderMockDriveService) GetFile(ctx context.Context, fileID, fields string) (*drive.File, error) {
+	m.fields = fields
+	return m.folder, nil
+}
+
+func TestClient_FolderDetails_ReadOnly(t *testing.T) {
+	mock := &folderMockDriveService{folder: &drive.File{
+		Id:           "services",
+		Name:         "Shared With Me",
+		MimeType:     distribution.MimeTypeFolder,
+		Capabilities: &drive.FileCapabilities{CanAddChildren: false},
+	}}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock))
+
+	folder, err := client.FolderDetails(context.Background(), "services")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(mock.fields, "capabilities/canAddChildren") {
+		t.Errorf("requested fields %q, want the folder's capabilities", mock.fields)
+	}
+	if !errors.Is(folder.Check(), distribution.ErrFolderNotWritable) {
+		t.Errorf("Check() = %v, want ErrFolderNotWritable", folder.Check())
+	}
+}
+
+func TestClient_FolderDetails_Unsupported(t *testing.T) {
+	client, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+
+	if _, err := client.FolderDetails(context.Background(), "services"); !errors.Is(err, distribution.ErrFolderLookupUnsupported) {
+		t.Errorf("expected ErrFolderLookupUnsupported, got %v", err)
+	}
+}
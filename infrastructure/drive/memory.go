@@ -0,0 +1,477 @@
+package drive
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/filesystem"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultMemoryQuota is the storage limit of a MemoryService, matching a free
+// Google account
+const DefaultMemoryQuota = 15 * 1024 * 1024 * 1024
+
+// Query clauses the Client sends, matched by MemoryService
+var (
+	parentClause      = regexp.MustCompile(`'([^']*)' in parents`)
+	trashedClause     = regexp.MustCompile(`trashed ?= ?(true|false)`)
+	nameClause        = regexp.MustCompile(`name = '([^']*)'`)
+	mimeTypeClause    = regexp.MustCompile(`mimeType ?= ?'([^']*)'`)
+	appPropertyClause = regexp.MustCompile(`appProperties has \{ key='([^']*)' and value='([^']*)' \}`)
+	publicClause      = regexp.MustCompile(`visibility = 'anyoneWithLink'`)
+)
+
+// MemoryService is a DriveService that keeps files in memory instead of
+// talking to Google. It understands the queries the Client sends, so the
+// whole workflow can run against it without an account.
+type MemoryService struct {
+	mu     sync.Mutex
+	stored []*memoryFile
+	nextID int
+	limit  int64
+
+	files filesystem.Opener // Reads uploads; nil uses the os package
+	now   func() time.Time
+}
+
+type memoryFile struct {
+	meta    *drive.File
+	content []byte
+	public  bool
+	readers []string // Addresses given read access
+}
+
+// MemoryOption is a functional option for configuring MemoryService
+type MemoryOption func(*MemoryService)
+
+// WithMemoryQuota sets the storage limit reported by GetAbout
+func WithMemoryQuota(limit int64) MemoryOption {
+	return func(s *MemoryService) {
+		s.limit = limit
+	}
+}
+
+// WithMemoryFileOpener sets where uploaded local files are read from
+func WithMemoryFileOpener(files filesystem.Opener) MemoryOption {
+	return func(s *MemoryService) {
+		s.files = files
+	}
+}
+
+// WithMemoryClock sets the clock used for created times
+func WithMemoryClock(now func() time.Time) MemoryOption {
+	return func(s *MemoryService) {
+		s.now = now
+	}
+}
+
+// WithMemoryFolder stores an empty folder with a fixed ID, such as the
+// services folder a config points at
+func WithMemoryFolder(id, name string) MemoryOption {
+	return func(s *MemoryService) {
+		s.stored = append(s.stored, &memoryFile{meta: &drive.File{
+			Id:       id,
+			Name:     name,
+			MimeType: distribution.MimeTypeFolder,
+		}})
+	}
+}
+
+// NewMemoryService creates an empty in-memory Drive
+func NewMemoryService(opts ...MemoryOption) *MemoryService {
+	s := &MemoryService{
+		nextID: 1,
+		limit:  DefaultMemoryQuota,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ListFiles returns the files matching the query's parent, trashed, name,
+// mimeType and appProperties clauses, sorted by name
+func (s *MemoryService) ListFiles(ctx context.Context, query string, fields string, orderBy string) ([]*drive.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*drive.File
+	for _, f := range s.stored {
+		if matchesQuery(f, query) {
+			result = append(result, f.meta)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func matchesQuery(f *memoryFile, query string) bool {
+	if m := parentClause.FindStringSubmatch(query); m != nil && !hasValue(f.meta.Parents, m[1]) {
+		return false
+	}
+	if m := trashedClause.FindStringSubmatch(query); m != nil && m[1] == "true" {
+		// Deletes are permanent, so nothing is ever in the trash
+		return false
+	}
+	if m := nameClause.FindStringSubmatch(query); m != nil && f.meta.Name != m[1] {
+		return false
+	}
+	if m := mimeTypeClause.FindStringSubmatch(query); m != nil && f.meta.MimeType != m[1] {
+		return false
+	}
+	if m := appPropertyClause.FindStringSubmatch(query); m != nil && f.meta.AppProperties[m[1]] != m[2] {
+		return false
+	}
+	if publicClause.MatchString(query) && !f.public {
+		return false
+	}
+	return true
+}
+
+func hasValue(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAbout reports the quota and the total size of the stored files
+func (s *MemoryService) GetAbout(ctx context.Context, fields string) (*drive.About, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var usage int64
+	for _, f := range s.stored {
+		usage += f.meta.Size
+	}
+	return &drive.About{
+		StorageQuota: &drive.AboutStorageQuota{Limit: s.limit, Usage: usage},
+	}, nil
+}
+
+// DeleteFile removes a file permanently
+func (s *MemoryService) DeleteFile(ctx context.Context, fileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.stored {
+		if f.meta.Id == fileID {
+			s.stored = append(s.stored[:i], s.stored[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("file not found: %s", fileID)
+}
+
+// EmptyTrash does nothing, since deleted files skip the trash
+func (s *MemoryService) EmptyTrash(ctx context.Context) error {
+	return nil
+}
+
+// UploadFile stores a copy of a local file
+func (s *MemoryService) UploadFile(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string) (*drive.File, error) {
+	f, err := opener(s.files).Open(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %w", err)
+	}
+	defer f.Close()
+
+	return s.UploadReader(ctx, fileName, mimeType, folderID, f, appProperties)
+}
+
+// UploadFileWithProgress stores a copy of a local file, reporting its whole
+// size once it is stored
+func (s *MemoryService) UploadFileWithProgress(ctx context.Context, fileName, mimeType, folderID, localPath string, appProperties map[string]string, progress func(sent int64)) (*drive.File, error) {
+	file, err := s.UploadFile(ctx, fileName, mimeType, folderID, localPath, appProperties)
+	if err == nil && progress != nil {
+		progress(file.Size)
+	}
+	return file, err
+}
+
+// UploadReader stores everything read from r
+func (s *MemoryService) UploadReader(ctx context.Context, fileName, mimeType, folderID string, r io.Reader, appProperties map[string]string) (*drive.File, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to upload file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("memory-file-%d", s.nextID)
+	s.nextID++
+	meta := &drive.File{
+		Id:            id,
+		Name:          fileName,
+		MimeType:      mimeType,
+		Parents:       []string{folderID},
+		AppProperties: copyProperties(appProperties),
+		WebViewLink:   fmt.Sprintf("https://drive.google.com/file/d/%s/view", id),
+		CreatedTime:   s.now().UTC().Format(time.RFC3339),
+	}
+	file := &memoryFile{meta: meta}
+	file.setContent(content)
+	s.stored = append(s.stored, file)
+	return meta, nil
+}
+
+// UpdateFileContent replaces a stored file's content from a local file
+func (s *MemoryService) UpdateFileContent(ctx context.Context, fileID, mimeType, localPath string) (*drive.File, error) {
+	f, err := opener(s.files).Open(localPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file: %w", err)
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return nil, err
+	}
+	file.setContent(content)
+	return file.meta, nil
+}
+
+// UpdateAppProperties merges appProperties into a stored file's
+func (s *MemoryService) UpdateAppProperties(ctx context.Context, fileID string, appProperties map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return err
+	}
+	if file.meta.AppProperties == nil {
+		file.meta.AppProperties = make(map[string]string)
+	}
+	for k, v := range appProperties {
+		file.meta.AppProperties[k] = v
+	}
+	return nil
+}
+
+// UpdateName renames a stored file
+func (s *MemoryService) UpdateName(ctx context.Context, fileID, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return err
+	}
+	file.meta.Name = name
+	return nil
+}
+
+// DownloadFile writes a stored file's content to w
+func (s *MemoryService) DownloadFile(ctx context.Context, fileID string, w io.Writer) error {
+	s.mu.Lock()
+	file, err := s.find(fileID)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, bytes.NewReader(file.content))
+	return err
+}
+
+// CreatePermission records an "anyone" permission as a public file
+func (s *MemoryService) CreatePermission(ctx context.Context, fileID string, permission *drive.Permission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return err
+	}
+	switch permission.Type {
+	case "anyone":
+		file.public = true
+	case "user":
+		file.readers = append(file.readers, permission.EmailAddress)
+	}
+	return nil
+}
+
+// ListPermissions returns a file's "anyone" and reader permissions, with
+// each reader's address as its ID
+func (s *MemoryService) ListPermissions(ctx context.Context, fileID string) ([]*drive.Permission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return nil, err
+	}
+	var permissions []*drive.Permission
+	if file.public {
+		permissions = append(permissions, &drive.Permission{Id: "anyoneWithLink", Type: "anyone", Role: "reader"})
+	}
+	for _, address := range file.readers {
+		permissions = append(permissions, &drive.Permission{Id: address, Type: "user", Role: "reader", EmailAddress: address})
+	}
+	return permissions, nil
+}
+
+// DeletePermission removes a permission listed by ListPermissions
+func (s *MemoryService) DeletePermission(ctx context.Context, fileID, permissionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return err
+	}
+	if permissionID == "anyoneWithLink" && file.public {
+		file.public = false
+		return nil
+	}
+	for i, address := range file.readers {
+		if address == permissionID {
+			file.readers = append(file.readers[:i], file.readers[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("permission not found: %s", permissionID)
+}
+
+// CreateFolder stores an empty folder inside parentID
+func (s *MemoryService) CreateFolder(ctx context.Context, name, parentID string) (*drive.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := fmt.Sprintf("memory-folder-%d", s.nextID)
+	s.nextID++
+	meta := &drive.File{
+		Id:          id,
+		Name:        name,
+		MimeType:    distribution.MimeTypeFolder,
+		Parents:     []string{parentID},
+		CreatedTime: s.now().UTC().Format(time.RFC3339),
+	}
+	s.stored = append(s.stored, &memoryFile{meta: meta})
+	return meta, nil
+}
+
+// GetFile returns a stored file or folder, or a 404 like Drive's. Every
+// folder can take uploads.
+func (s *MemoryService) GetFile(ctx context.Context, fileID, fields string) (*drive.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return nil, &googleapi.Error{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	meta := *file.meta
+	meta.Capabilities = &drive.FileCapabilities{CanAddChildren: meta.MimeType == distribution.MimeTypeFolder}
+	return &meta, nil
+}
+
+// MoveFile swaps one of a stored file's parents for another
+func (s *MemoryService) MoveFile(ctx context.Context, fileID, addParentID, removeParentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return err
+	}
+	parents := []string{addParentID}
+	for _, p := range file.meta.Parents {
+		if p != removeParentID && p != addParentID {
+			parents = append(parents, p)
+		}
+	}
+	file.meta.Parents = parents
+	return nil
+}
+
+// Files returns the stored files, oldest first
+func (s *MemoryService) Files() []*drive.File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := make([]*drive.File, len(s.stored))
+	for i, f := range s.stored {
+		files[i] = f.meta
+	}
+	return files
+}
+
+// IsPublic reports whether anyone with the link can open the file
+func (s *MemoryService) IsPublic(fileID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	return err == nil && file.public
+}
+
+// SharedWith returns the addresses given read access to a file or folder
+func (s *MemoryService) SharedWith(fileID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := s.find(fileID)
+	if err != nil {
+		return nil
+	}
+	return append([]string(nil), file.readers...)
+}
+
+func (s *MemoryService) find(fileID string) (*memoryFile, error) {
+	for _, f := range s.stored {
+		if f.meta.Id == fileID {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("file not found: %s", fileID)
+}
+
+func (f *memoryFile) setContent(content []byte) {
+	sum := md5.Sum(content)
+	f.content = content
+	f.meta.Size = int64(len(content))
+	f.meta.Md5Checksum = hex.EncodeToString(sum[:])
+}
+
+func copyProperties(props map[string]string) map[string]string {
+	if props == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(props))
+	for k, v := range props {
+		copied[k] = v
+	}
+	return copied
+}
+
+// Ensure MemoryService implements DriveService and its optional capabilities
+var (
+	_ DriveService     = (*MemoryService)(nil)
+	_ ReaderUploader   = (*MemoryService)(nil)
+	_ ProgressUploader = (*MemoryService)(nil)
+	_ ContentUpdater   = (*MemoryService)(nil)
+	_ FileDownloader   = (*MemoryService)(nil)
+	_ PropertyUpdater  = (*MemoryService)(nil)
+	_ NameUpdater      = (*MemoryService)(nil)
+	_ FolderMover      = (*MemoryService)(nil)
+	_ PermissionEditor = (*MemoryService)(nil)
+	_ FileGetter       = (*MemoryService)(nil)
+)
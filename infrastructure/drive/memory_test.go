@@ -0,0 +1,243 @@
+package drive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/infrastructure/filesystem"
+)
+
+func newMemoryClient(t *testing.T, files *filesystem.MemFS) (*Client, *MemoryService) {
+	t.Helper()
+	now := time.Date(2025, 12, 28, 12, 0, 0, 0, time.UTC)
+	svc := NewMemoryService(WithMemoryFileOpener(files), WithMemoryClock(func() time.Time { return now }), WithMemoryQuota(1000))
+	client, err := NewClient(context.Background(), "", WithDriveService(svc))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client, svc
+}
+
+func TestMemoryService_UploadAndShare(t *testing.T) {
+	ctx := context.Background()
+	files := filesystem.NewMemFS()
+	if err := files.WriteFile("/out/2025-12-28.mp4", []byte("video")); err != nil {
+		t.Fatal(err)
+	}
+	client, svc := newMemoryClient(t, files)
+
+	result, err := client.UploadAndShare(ctx, distribution.UploadRequest{
+		LocalPath:     "/out/2025-12-28.mp4",
+		FileName:      "2025-12-28.mp4",
+		FolderID:      "folder",
+		MimeType:      "video/mp4",
+		AppProperties: map[string]string{"service_date": "2025-12-28"},
+	})
+	if err != nil {
+		t.Fatalf("UploadAndShare: %v", err)
+	}
+	if result.Size != 5 || result.MD5Checksum == "" {
+		t.Errorf("result = %+v, want size 5 and a checksum", result)
+	}
+	if !svc.IsPublic(result.FileID) {
+		t.Error("uploaded file is not public")
+	}
+
+	found, err := client.FindFileByName(ctx, "folder", "2025-12-28.mp4")
+	if err != nil || found == nil || found.ID != result.FileID {
+		t.Fatalf("FindFileByName = %+v, %v", found, err)
+	}
+	if missing, _ := client.FindFileByName(ctx, "other", "2025-12-28.mp4"); missing != nil {
+		t.Errorf("found %+v in the wrong folder", missing)
+	}
+	tagged, err := client.FindFilesByProperty(ctx, "folder", "service_date", "2025-12-28")
+	if err != nil || len(tagged) != 1 {
+		t.Errorf("FindFilesByProperty = %v, %v", tagged, err)
+	}
+	public, err := client.ListPublic(ctx, "folder")
+	if err != nil || len(public) != 1 {
+		t.Errorf("ListPublic = %v, %v", public, err)
+	}
+	mp4s, err := client.ListMP4Files(ctx, "folder")
+	if err != nil || len(mp4s) != 1 || !mp4s[0].CreatedTime.Equal(time.Date(2025, 12, 28, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("ListMP4Files = %+v, %v", mp4s, err)
+	}
+
+	quota, err := client.GetStorageQuota(ctx)
+	if err != nil || quota.UsedBytes != 5 || quota.TotalBytes != 1000 {
+		t.Errorf("GetStorageQuota = %+v, %v", quota, err)
+	}
+}
+
+func TestClient_UploadReportsProgress(t *testing.T) {
+	files := filesystem.NewMemFS()
+	if err := files.WriteFile("/out/2025-12-28.mp3", []byte("audio")); err != nil {
+		t.Fatal(err)
+	}
+	client, _ := newMemoryClient(t, files)
+
+	var sent int64
+	_, err := client.Upload(context.Background(), distribution.UploadRequest{
+		LocalPath: "/out/2025-12-28.mp3",
+		FileName:  "2025-12-28.mp3",
+		FolderID:  "folder",
+		MimeType:  distribution.MimeTypeMP3,
+		Progress:  func(n int64) { sent = n },
+	})
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if sent != 5 {
+		t.Errorf("progress reported %d bytes, want 5", sent)
+	}
+}
+
+func TestMemoryService_DeleteAndDownload(t *testing.T) {
+	ctx := context.Background()
+	svc := NewMemoryService()
+	first, err := svc.UploadReader(ctx, "a.mp3", "audio/mpeg", "folder", bytes.NewReader([]byte("first")), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UploadReader(ctx, "b.mp3", "audio/mpeg", "folder", bytes.NewReader([]byte("second")), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := svc.DownloadFile(ctx, first.Id, &buf); err != nil || buf.String() != "first" {
+		t.Errorf("DownloadFile = %q, %v", buf.String(), err)
+	}
+
+	if err := svc.DeleteFile(ctx, first.Id); err != nil {
+		t.Fatalf("DeleteFile: %v", err)
+	}
+	if err := svc.DeleteFile(ctx, first.Id); err == nil {
+		t.Error("deleting a missing file should fail")
+	}
+	if files := svc.Files(); len(files) != 1 || files[0].Name != "b.mp3" {
+		t.Errorf("Files = %v, want only b.mp3", files)
+	}
+	trashed, err := svc.ListFiles(ctx, "'folder' in parents and trashed = true", "", "")
+	if err != nil || len(trashed) != 0 {
+		t.Errorf("trashed = %v, %v; deletes skip the trash", trashed, err)
+	}
+}
+
+func TestMemoryService_FoldersAndUserSharing(t *testing.T) {
+	ctx := context.Background()
+	client, svc := newMemoryClient(t, filesystem.NewMemFS())
+	file, err := svc.UploadReader(ctx, "2025-12-28.mp4", "video/mp4", "services", bytes.NewReader([]byte("video")), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	folder, err := client.CreateFolder(ctx, "services", "2025")
+	if err != nil {
+		t.Fatalf("CreateFolder: %v", err)
+	}
+	if folder.MimeType != distribution.FolderMimeType || folder.Name != "2025" {
+		t.Errorf("folder = %+v", folder)
+	}
+	if found, err := client.FindFileByName(ctx, "services", "2025"); err != nil || found == nil || found.ID != folder.ID {
+		t.Errorf("FindFileByName(2025) = %+v, %v", found, err)
+	}
+
+	if err := client.MoveFile(ctx, file.Id, "services", folder.ID); err != nil {
+		t.Fatalf("MoveFile: %v", err)
+	}
+	if moved, _ := client.FindFileByName(ctx, folder.ID, "2025-12-28.mp4"); moved == nil || moved.ID != file.Id {
+		t.Errorf("file not in the new folder: %+v", moved)
+	}
+	if left, _ := client.FindFileByName(ctx, "services", "2025-12-28.mp4"); left != nil {
+		t.Errorf("file still in the old folder: %+v", left)
+	}
+
+	if err := client.ShareWithUser(ctx, folder.ID, "elder@example.com"); err != nil {
+		t.Fatalf("ShareWithUser: %v", err)
+	}
+	if got := svc.SharedWith(folder.ID); len(got) != 1 || got[0] != "elder@example.com" {
+		t.Errorf("SharedWith = %v", got)
+	}
+	if svc.IsPublic(folder.ID) {
+		t.Error("sharing with a person made the folder public")
+	}
+}
+
+func TestMemoryService_RevokeReaders(t *testing.T) {
+	ctx := context.Background()
+	files := filesystem.NewMemFS()
+	if err := files.WriteFile("/out/2025-12-28.mp3", []byte("audio")); err != nil {
+		t.Fatal(err)
+	}
+	client, svc := newMemoryClient(t, files)
+	result, err := client.UploadAndShare(ctx, distribution.UploadRequest{
+		LocalPath: "/out/2025-12-28.mp3",
+		FileName:  "2025-12-28.mp3",
+		FolderID:  "folder",
+		MimeType:  "audio/mpeg",
+	})
+	if err != nil {
+		t.Fatalf("UploadAndShare: %v", err)
+	}
+	for _, address := range []string{"family@example.com", "friend@example.com"} {
+		if err := client.ShareWithUser(ctx, result.FileID, address); err != nil {
+			t.Fatalf("ShareWithUser: %v", err)
+		}
+	}
+
+	revoked, err := client.RevokeReaders(ctx, result.FileID)
+	if err != nil {
+		t.Fatalf("RevokeReaders: %v", err)
+	}
+	if len(revoked) != 2 || revoked[0] != "family@example.com" || revoked[1] != "friend@example.com" {
+		t.Errorf("revoked = %v", revoked)
+	}
+	if got := svc.SharedWith(result.FileID); len(got) != 0 {
+		t.Errorf("still shared with %v", got)
+	}
+	if !svc.IsPublic(result.FileID) {
+		t.Error("revoking readers should leave link sharing alone")
+	}
+
+	if revoked, err := client.RevokeReaders(ctx, result.FileID); err != nil || len(revoked) != 0 {
+		t.Errorf("second RevokeReaders = %v, %v; want nothing to revoke", revoked, err)
+	}
+}
+
+func TestClient_FolderDetails(t *testing.T) {
+	ctx := context.Background()
+	svc := NewMemoryService(WithMemoryFolder("services", "Sunday Services"))
+	client, err := NewClient(ctx, "", WithDriveService(svc))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	file, err := svc.UploadReader(ctx, "notes.txt", "text/plain", "services", strings.NewReader("notes"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	folder, err := client.FolderDetails(ctx, "services")
+	if err != nil {
+		t.Fatalf("FolderDetails: %v", err)
+	}
+	if folder.Name != "Sunday Services" || folder.Check() != nil {
+		t.Errorf("folder = %+v, check %v; want a writable folder named Sunday Services", folder, folder.Check())
+	}
+
+	notFolder, err := client.FolderDetails(ctx, file.Id)
+	if err != nil {
+		t.Fatalf("FolderDetails(file): %v", err)
+	}
+	if !errors.Is(notFolder.Check(), distribution.ErrNotAFolder) {
+		t.Errorf("Check() on a file = %v, want ErrNotAFolder", notFolder.Check())
+	}
+
+	if _, err := client.FolderDetails(ctx, "typo"); !errors.Is(err, distribution.ErrFolderNotFound) {
+		t.Errorf("FolderDetails(typo) = %v, want ErrFolderNotFound", err)
+	}
+}
@@ -3,11 +3,15 @@ package drive
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"time"
+
+	"nac-service-media/infrastructure/filesystem"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -19,23 +23,32 @@ import (
 type OAuthConfig struct {
 	CredentialsFile string // Path to OAuth client credentials JSON
 	TokenFile       string // Path to store/load token
+	// NonInteractive fails with ErrAuthRequired instead of opening a browser
+	// to sign in when there is no valid token
+	NonInteractive bool
+	// ScopeMode is "full" (default) or "file"
+	ScopeMode string
 }
 
+// ErrAuthRequired is returned in non-interactive mode when signing in is needed
+var ErrAuthRequired = errors.New("no valid OAuth token; run 'nac-service-media auth status --fix' to sign in")
+
 // newOAuthDriveService creates a Drive service using OAuth 2.0 user authentication
-func newOAuthDriveService(ctx context.Context, cfg OAuthConfig) (*GoogleDriveService, error) {
+func newOAuthDriveService(ctx context.Context, cfg OAuthConfig, store tokenStore) (*GoogleDriveService, error) {
 	b, err := os.ReadFile(cfg.CredentialsFile)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read OAuth credentials file: %w", err)
 	}
 
 	// Parse the OAuth client credentials
-	config, err := google.ConfigFromJSON(b, drive.DriveScope)
+	config, err := google.ConfigFromJSON(b, Scope(cfg.ScopeMode))
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse OAuth credentials: %w", err)
 	}
 
 	// Get or create token
-	token, err := getToken(ctx, config, cfg.TokenFile)
+	store.file = cfg.TokenFile
+	token, err := getToken(ctx, config, store, cfg.NonInteractive)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get OAuth token: %w", err)
 	}
@@ -47,34 +60,50 @@ func newOAuthDriveService(ctx context.Context, cfg OAuthConfig) (*GoogleDriveSer
 		return nil, fmt.Errorf("unable to create drive service: %w", err)
 	}
 
-	return &GoogleDriveService{service: srv}, nil
+	return &GoogleDriveService{service: srv, httpClient: client}, nil
 }
 
-// getToken retrieves a token from file or initiates the OAuth flow
-func getToken(ctx context.Context, config *oauth2.Config, tokenFile string) (*oauth2.Token, error) {
+// getToken returns the saved token while it is unexpired, refreshes it when
+// it has expired, and otherwise (unless nonInteractive) initiates the OAuth flow
+func getToken(ctx context.Context, config *oauth2.Config, store tokenStore, nonInteractive bool) (*oauth2.Token, error) {
 	// Try to load existing token
-	token, err := loadToken(tokenFile)
+	token, err := store.load()
 	if err == nil {
-		// Check if token is still valid or can be refreshed
-		tokenSource := config.TokenSource(ctx, token)
-		newToken, err := tokenSource.Token()
-		if err == nil {
-			// Save refreshed token if it changed
-			if newToken.AccessToken != token.AccessToken {
-				saveToken(tokenFile, newToken)
+		if !store.expired(token) {
+			return token, nil
+		}
+		if token.RefreshToken != "" {
+			// Only the refresh token is passed so the refresh does not depend
+			// on the oauth2 package's own clock
+			newToken, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+			if err == nil {
+				store.save(newToken)
+				return newToken, nil
 			}
-			return newToken, nil
 		}
 		// Token refresh failed, need to re-authenticate
 	}
 
 	// No valid token, initiate OAuth flow
-	return getTokenFromWeb(ctx, config, tokenFile)
+	if nonInteractive {
+		return nil, ErrAuthRequired
+	}
+	return getTokenFromWeb(ctx, config, store)
+}
+
+// tokenExpiryDelta refreshes a token this long before it actually expires
+const tokenExpiryDelta = time.Minute
+
+// tokenStore loads and saves the OAuth token file
+type tokenStore struct {
+	file  string
+	files filesystem.Opener // nil uses the os package
+	now   func() time.Time  // nil uses time.Now
 }
 
-// loadToken loads a token from a file
-func loadToken(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
+// load reads the token from the file
+func (s tokenStore) load() (*oauth2.Token, error) {
+	f, err := opener(s.files).Open(s.file)
 	if err != nil {
 		return nil, err
 	}
@@ -85,9 +114,9 @@ func loadToken(file string) (*oauth2.Token, error) {
 	return token, err
 }
 
-// saveToken saves a token to a file
-func saveToken(file string, token *oauth2.Token) error {
-	f, err := os.Create(file)
+// save writes the token to the file
+func (s tokenStore) save(token *oauth2.Token) error {
+	f, err := filesystem.CreatePrivate(opener(s.files), s.file)
 	if err != nil {
 		return err
 	}
@@ -96,8 +125,24 @@ func saveToken(file string, token *oauth2.Token) error {
 	return json.NewEncoder(f).Encode(token)
 }
 
+// expired reports whether the token has no access token or expires within
+// tokenExpiryDelta; a token without an expiry never expires
+func (s tokenStore) expired(token *oauth2.Token) bool {
+	if token.AccessToken == "" {
+		return true
+	}
+	if token.Expiry.IsZero() {
+		return false
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	return !now().Add(tokenExpiryDelta).Before(token.Expiry)
+}
+
 // getTokenFromWeb initiates the OAuth flow via browser
-func getTokenFromWeb(ctx context.Context, config *oauth2.Config, tokenFile string) (*oauth2.Token, error) {
+func getTokenFromWeb(ctx context.Context, config *oauth2.Config, store tokenStore) (*oauth2.Token, error) {
 	// Use localhost redirect for installed apps
 	config.RedirectURL = "http://localhost:8085/callback"
 
@@ -162,7 +207,7 @@ func getTokenFromWeb(ctx context.Context, config *oauth2.Config, tokenFile strin
 	}
 
 	// Save token for future use
-	if err := saveToken(tokenFile, token); err != nil {
+	if err := store.save(token); err != nil {
 		fmt.Printf("Warning: couldn't save token: %v\n", err)
 	}
 
@@ -209,10 +254,13 @@ func NewClientWithOAuth(ctx context.Context, credentialsPath, tokenPath string,
 		svc, err := newOAuthDriveService(ctx, OAuthConfig{
 			CredentialsFile: credentialsPath,
 			TokenFile:       tokenPath,
-		})
+			NonInteractive:  c.nonInteractive,
+			ScopeMode:       c.scopeMode,
+		}, tokenStore{files: c.files, now: c.now})
 		if err != nil {
 			return nil, err
 		}
+		c.configure(svc)
 		c.driveService = svc
 	}
 
@@ -0,0 +1,168 @@
+package drive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// memOpener keeps files in memory
+type memOpener struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemOpener() *memOpener {
+	return &memOpener{files: map[string][]byte{}}
+}
+
+func (m *memOpener) Open(name string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memOpener) Create(name string) (io.WriteCloser, error) {
+	return &memFile{opener: m, name: name}, nil
+}
+
+func (m *memOpener) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+// memFile stores its contents in the opener when closed
+type memFile struct {
+	bytes.Buffer
+	opener *memOpener
+	name   string
+}
+
+func (f *memFile) Close() error {
+	f.opener.mu.Lock()
+	defer f.opener.mu.Unlock()
+	f.opener.files[f.name] = f.Bytes()
+	return nil
+}
+
+var testNow = time.Date(2025, 12, 28, 10, 0, 0, 0, time.UTC)
+
+func saveTestToken(t *testing.T, files *memOpener, token *oauth2.Token) tokenStore {
+	t.Helper()
+	store := tokenStore{file: "token.json", files: files, now: func() time.Time { return testNow }}
+	if err := store.save(token); err != nil {
+		t.Fatalf("saving token: %v", err)
+	}
+	return store
+}
+
+// newTokenServer answers refresh requests with a new access token
+func newTokenServer(t *testing.T, refreshes *int) *oauth2.Config {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*refreshes++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"fresh","token_type":"Bearer","expires_in":3600}`)
+	}))
+	t.Cleanup(server.Close)
+	return &oauth2.Config{ClientID: "id", Endpoint: oauth2.Endpoint{TokenURL: server.URL}}
+}
+
+func TestGetToken_UnexpiredTokenIsNotRefreshed(t *testing.T) {
+	var refreshes int
+	cfg := newTokenServer(t, &refreshes)
+	store := saveTestToken(t, newMemOpener(), &oauth2.Token{
+		AccessToken: "saved", RefreshToken: "refresh", Expiry: testNow.Add(time.Hour),
+	})
+
+	token, err := getToken(context.Background(), cfg, store, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "saved" || refreshes != 0 {
+		t.Errorf("got %q after %d refreshes, want the saved token", token.AccessToken, refreshes)
+	}
+}
+
+func TestGetToken_ExpiredTokenIsRefreshedAndSaved(t *testing.T) {
+	var refreshes int
+	cfg := newTokenServer(t, &refreshes)
+	files := newMemOpener()
+	// Within a minute of expiring counts as expired
+	store := saveTestToken(t, files, &oauth2.Token{
+		AccessToken: "stale", RefreshToken: "refresh", Expiry: testNow.Add(30 * time.Second),
+	})
+
+	token, err := getToken(context.Background(), cfg, store, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "fresh" || refreshes != 1 {
+		t.Errorf("got %q after %d refreshes, want one refresh", token.AccessToken, refreshes)
+	}
+
+	var saved oauth2.Token
+	if err := json.Unmarshal(files.files["token.json"], &saved); err != nil {
+		t.Fatalf("reading saved token: %v", err)
+	}
+	if saved.AccessToken != "fresh" || saved.RefreshToken != "refresh" {
+		t.Errorf("saved %+v, want the refreshed token keeping its refresh token", saved)
+	}
+}
+
+func TestGetToken_ExpiredWithoutRefreshTokenNeedsSignIn(t *testing.T) {
+	var refreshes int
+	cfg := newTokenServer(t, &refreshes)
+	store := saveTestToken(t, newMemOpener(), &oauth2.Token{
+		AccessToken: "stale", Expiry: testNow.Add(-time.Hour),
+	})
+
+	if _, err := getToken(context.Background(), cfg, store, true); !errors.Is(err, ErrAuthRequired) {
+		t.Fatalf("getToken() error = %v, want ErrAuthRequired", err)
+	}
+	if refreshes != 0 {
+		t.Errorf("expected no refresh, got %d", refreshes)
+	}
+}
+
+func TestTokenStore_Expired(t *testing.T) {
+	store := tokenStore{now: func() time.Time { return testNow }}
+	tests := []struct {
+		name  string
+		token *oauth2.Token
+		want  bool
+	}{
+		{"no access token", &oauth2.Token{}, true},
+		{"no expiry", &oauth2.Token{AccessToken: "a"}, false},
+		{"expires later", &oauth2.Token{AccessToken: "a", Expiry: testNow.Add(2 * time.Minute)}, false},
+		{"expires within a minute", &oauth2.Token{AccessToken: "a", Expiry: testNow.Add(time.Minute)}, true},
+		{"already expired", &oauth2.Token{AccessToken: "a", Expiry: testNow.Add(-time.Second)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := store.expired(tt.token); got != tt.want {
+				t.Errorf("expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package drive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/clock"
+	"nac-service-media/domain/distribution"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultChunkSize is how much of an upload is sent per request. Drive needs
+// a multiple of 256 KiB.
+const DefaultChunkSize = 16 << 20
+
+// DefaultChunkRetries is how many times a failed chunk is re-sent before the
+// upload gives up
+const DefaultChunkRetries = 5
+
+// uploadURL is the Drive endpoint resumable upload sessions are started on
+const uploadURL = "https://www.googleapis.com/upload/drive/v3/files"
+
+const (
+	initialChunkBackoff = 2 * time.Second
+	maxChunkBackoff     = 60 * time.Second
+)
+
+// ChunkError is returned when a range of an upload still fails after every
+// retry. Bytes before Start are safely on Drive.
+type ChunkError struct {
+	Start, End int64 // Inclusive byte range that could not be sent
+	Attempts   int
+	Err        error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("bytes %d-%d failed after %d attempts: %v", e.Start, e.End, e.Attempts, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// resumableUpload sends content in chunks over a Drive resumable upload
+// session. A chunk that fails is re-sent from the last byte Drive confirmed,
+// so a flaky connection never restarts the whole file.
+type resumableUpload struct {
+	client     *http.Client
+	baseURL    string
+	chunkSize  int
+	maxRetries int
+	backoff    func(attempt int) time.Duration
+	sleep      clock.Sleeper // Default clock.Sleep
+	log        io.Writer
+	progress   func(sent int64)     // Replaces the log's per-chunk line when set
+	started    func(session string) // Called with a new session's URL, so it can be saved
+}
+
+// errSessionGone is returned when Drive no longer knows an upload session,
+// which it forgets after a week
+var errSessionGone = errors.New("upload session has expired")
+
+// chunkBackoff doubles from 2s up to a minute
+func chunkBackoff(attempt int) time.Duration {
+	d := initialChunkBackoff << (attempt - 1)
+	if d <= 0 || d > maxChunkBackoff {
+		return maxChunkBackoff
+	}
+	return d
+}
+
+// upload creates (POST) or replaces (PATCH) a file with r's content
+func (u *resumableUpload) upload(ctx context.Context, method, fileID string, meta *drive.File, r io.Reader) (*drive.File, error) {
+	session, err := u.start(ctx, method, fileID, meta)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start upload: %w", err)
+	}
+	if u.started != nil {
+		u.started(session)
+	}
+	return u.send(ctx, session, r, 0)
+}
+
+// resume continues an earlier session for content of size bytes, asking
+// Drive how much it has before reading r. It returns errSessionGone, with
+// nothing read, when the session cannot be continued.
+func (u *resumableUpload) resume(ctx context.Context, session string, r io.Reader, size int64) (*drive.File, error) {
+	file, next, err := u.put(ctx, session, nil, 0, strconv.FormatInt(size, 10))
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && (apiErr.Code == http.StatusNotFound || apiErr.Code == http.StatusGone) {
+		return nil, errSessionGone
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to check upload session: %w", err)
+	}
+	if file != nil {
+		return file, nil
+	}
+	if next > size {
+		return nil, errSessionGone
+	}
+
+	u.logf("  Resuming after %s already on Drive\n", distribution.FormatSize(next))
+	if _, err := io.CopyN(io.Discard, r, next); err != nil {
+		return nil, fmt.Errorf("unable to read upload content: %w", err)
+	}
+	return u.send(ctx, session, r, next)
+}
+
+// send uploads r, which starts at offset in the content, chunk by chunk
+func (u *resumableUpload) send(ctx context.Context, session string, r io.Reader, offset int64) (*drive.File, error) {
+	br := bufio.NewReader(r)
+	buf := make([]byte, u.chunkSize)
+	for {
+		n, err := io.ReadFull(br, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("unable to read upload content: %w", err)
+		}
+		final := n < len(buf)
+		if !final {
+			if _, err := br.Peek(1); err == io.EOF {
+				final = true
+			} else if err != nil {
+				return nil, fmt.Errorf("unable to read upload content: %w", err)
+			}
+		}
+
+		file, err := u.sendChunk(ctx, session, buf[:n], offset, final)
+		if err != nil {
+			return nil, err
+		}
+		offset += int64(n)
+		if u.progress != nil {
+			u.progress(offset)
+		}
+		if final {
+			return file, nil
+		}
+		if u.progress == nil {
+			u.logf("  Uploaded %s\n", distribution.FormatSize(offset))
+		}
+	}
+}
+
+// start opens an upload session and returns its URL
+func (u *resumableUpload) start(ctx context.Context, method, fileID string, meta *drive.File) (string, error) {
+	target := u.baseURL
+	if fileID != "" {
+		target += "/" + url.PathEscape(fileID)
+	}
+	target += "?uploadType=resumable&fields=" + url.QueryEscape(uploadFields)
+
+	body, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	if meta.MimeType != "" {
+		req.Header.Set("X-Upload-Content-Type", meta.MimeType)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return "", err
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return "", errors.New("drive did not return an upload session")
+	}
+	return session, nil
+}
+
+// sendChunk sends chunk, which starts at offset, retrying with backoff. Only
+// the bytes Drive has not confirmed are re-sent. The file is returned once
+// the final chunk is accepted.
+func (u *resumableUpload) sendChunk(ctx context.Context, session string, chunk []byte, offset int64, final bool) (*drive.File, error) {
+	total := "*"
+	if final {
+		total = strconv.FormatInt(offset+int64(len(chunk)), 10)
+	}
+	end := offset + int64(len(chunk)) - 1
+
+	sent := 0
+	failures := 0
+	for {
+		file, next, err := u.put(ctx, session, chunk[sent:], offset+int64(sent), total)
+		if err == nil {
+			if file != nil {
+				return file, nil
+			}
+			confirmed := confirmedIn(next, offset, len(chunk))
+			if confirmed >= len(chunk) && !final {
+				return nil, nil
+			}
+			if confirmed > sent {
+				// Drive kept only part of the chunk; send the rest
+				sent = confirmed
+				continue
+			}
+			err = fmt.Errorf("drive accepted none of bytes %d-%d", offset+int64(sent), end)
+		} else if !retryableChunkError(ctx, err) {
+			return nil, fmt.Errorf("unable to upload bytes %d-%d: %w", offset+int64(sent), end, err)
+		}
+
+		failures++
+		if failures > u.maxRetries {
+			return nil, &ChunkError{Start: offset + int64(sent), End: end, Attempts: failures, Err: err}
+		}
+		wait := u.backoff(failures)
+		u.logf("  Bytes %d-%d failed (%v); retrying in %s (%d of %d)\n", offset+int64(sent), end, err, wait, failures, u.maxRetries)
+		if err := u.wait(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		// Ask Drive how much arrived before re-sending; if it cannot say,
+		// the next attempt finds out
+		file, next, err = u.put(ctx, session, nil, offset, total)
+		if err == nil {
+			if file != nil {
+				return file, nil
+			}
+			sent = confirmedIn(next, offset, len(chunk))
+		}
+	}
+}
+
+// put sends data starting at start, or only asks for the session's status
+// when data is empty. It returns the file when the upload is complete, or
+// the offset of the first byte Drive still needs.
+func (u *resumableUpload) put(ctx context.Context, session string, data []byte, start int64, total string) (*drive.File, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(data) == 0 {
+		req.Header.Set("Content-Range", "bytes */"+total)
+	} else {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%s", start, start+int64(len(data))-1, total))
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		var file drive.File
+		if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+			return nil, 0, fmt.Errorf("unable to read uploaded file: %w", err)
+		}
+		return &file, 0, nil
+	case http.StatusPermanentRedirect:
+		return nil, parseRangeEnd(resp.Header.Get("Range")), nil
+	default:
+		return nil, 0, googleapi.CheckResponse(resp)
+	}
+}
+
+// parseRangeEnd reads "bytes=0-N" and returns N+1, the next byte wanted
+func parseRangeEnd(header string) int64 {
+	_, last, ok := strings.Cut(strings.TrimPrefix(header, "bytes="), "-")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n + 1
+}
+
+// confirmedIn returns how many bytes of a chunk at offset Drive has, given
+// the next byte it wants
+func confirmedIn(next, offset int64, size int) int {
+	switch {
+	case next <= offset:
+		return 0
+	case next-offset > int64(size):
+		return size
+	default:
+		return int(next - offset)
+	}
+}
+
+// retryableChunkError reports whether re-sending could help: rate limits,
+// server errors and dropped connections, unless the caller gave up
+func retryableChunkError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500
+	}
+	return true
+}
+
+// wait pauses before a retry, using the injected sleep when there is one
+func (u *resumableUpload) wait(ctx context.Context, d time.Duration) error {
+	if u.sleep != nil {
+		return u.sleep(ctx, d)
+	}
+	return clock.Sleep(ctx, d)
+}
+
+func (u *resumableUpload) logf(format string, args ...any) {
+	if u.log != nil {
+		fmt.Fprintf(u.log, format, args...)
+	}
+}
@@ -0,0 +1,270 @@
+package drive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+)
+
+// fakeUploadServer implements enough of Drive's resumable upload protocol to
+// test chunk retries. failPuts lists which content PUTs (1-based) fail.
+type fakeUploadServer struct {
+	mu        sync.Mutex
+	received  bytes.Buffer
+	puts      int
+	failPuts  map[int]int // PUT number -> status to fail with
+	keepBytes int         // On a failing PUT, how many of its bytes still arrive
+	ranges    []string
+	method    string
+	gone      bool // The session has expired
+}
+
+func (f *fakeUploadServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/upload/", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.method = r.Method
+		f.mu.Unlock()
+		w.Header().Set("Location", "http://"+r.Host+"/session")
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/session", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		body, _ := io.ReadAll(r.Body)
+		contentRange := r.Header.Get("Content-Range")
+		f.ranges = append(f.ranges, contentRange)
+		if f.gone {
+			http.Error(w, "no such session", http.StatusNotFound)
+			return
+		}
+
+		if len(body) > 0 {
+			f.puts++
+			start := parseRangeStart(contentRange)
+			if start != int64(f.received.Len()) {
+				http.Error(w, "out of order", http.StatusBadRequest)
+				return
+			}
+			if status, ok := f.failPuts[f.puts]; ok {
+				f.received.Write(body[:min(f.keepBytes, len(body))])
+				w.WriteHeader(status)
+				return
+			}
+			f.received.Write(body)
+		}
+
+		total := contentRange[strings.LastIndex(contentRange, "/")+1:]
+		if total != "*" && strconv.Itoa(f.received.Len()) == total {
+			fmt.Fprintf(w, `{"id":"file-1","size":"%d"}`, f.received.Len())
+			return
+		}
+		if f.received.Len() > 0 {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", f.received.Len()-1))
+		}
+		w.WriteHeader(http.StatusPermanentRedirect)
+	})
+	return mux
+}
+
+func parseRangeStart(contentRange string) int64 {
+	spec := strings.TrimPrefix(contentRange, "bytes ")
+	first, _, _ := strings.Cut(spec, "-")
+	n, _ := strconv.ParseInt(first, 10, 64)
+	return n
+}
+
+func newTestUpload(t *testing.T, f *fakeUploadServer, retries int, log io.Writer) *resumableUpload {
+	t.Helper()
+	server := httptest.NewServer(f.handler())
+	t.Cleanup(server.Close)
+	return &resumableUpload{
+		client:     server.Client(),
+		baseURL:    server.URL + "/upload/drive/v3/files",
+		chunkSize:  4,
+		maxRetries: retries,
+		backoff:    func(int) time.Duration { return 0 },
+		log:        log,
+	}
+}
+
+func TestResumableUpload_SendsAllChunks(t *testing.T) {
+	f := &fakeUploadServer{}
+	var log bytes.Buffer
+	u := newTestUpload(t, f, 3, &log)
+
+	file, err := u.upload(context.Background(), http.MethodPost, "", &drive.File{Name: "a.mp3"}, strings.NewReader("0123456789"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if file.Id != "file-1" || file.Size != 10 {
+		t.Errorf("unexpected file: %+v", file)
+	}
+	if f.received.String() != "0123456789" {
+		t.Errorf("received %q", f.received.String())
+	}
+	want := []string{"bytes 0-3/*", "bytes 4-7/*", "bytes 8-9/10"}
+	if strings.Join(f.ranges, ",") != strings.Join(want, ",") {
+		t.Errorf("expected ranges %v, got %v", want, f.ranges)
+	}
+	if !strings.Contains(log.String(), "Uploaded 8 B") {
+		t.Errorf("expected chunk progress in log, got:\n%s", log.String())
+	}
+}
+
+func TestResumableUpload_ReportsProgress(t *testing.T) {
+	f := &fakeUploadServer{}
+	var log bytes.Buffer
+	u := newTestUpload(t, f, 3, &log)
+	var sent []int64
+	u.progress = func(n int64) { sent = append(sent, n) }
+
+	if _, err := u.upload(context.Background(), http.MethodPost, "", &drive.File{}, strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(sent) != "[4 8 10]" {
+		t.Errorf("expected progress after each chunk, got %v", sent)
+	}
+	if strings.Contains(log.String(), "Uploaded") {
+		t.Errorf("expected progress to replace the log line, got:\n%s", log.String())
+	}
+}
+
+func TestResumableUpload_ExactMultipleOfChunkSize(t *testing.T) {
+	f := &fakeUploadServer{}
+	u := newTestUpload(t, f, 3, nil)
+
+	if _, err := u.upload(context.Background(), http.MethodPost, "", &drive.File{}, strings.NewReader("01234567")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last := f.ranges[len(f.ranges)-1]; last != "bytes 4-7/8" {
+		t.Errorf("expected the last chunk to carry the total, got %q", last)
+	}
+}
+
+func TestResumableUpload_RetriesOnlyTheFailedRange(t *testing.T) {
+	// The second chunk fails after one of its bytes arrived
+	f := &fakeUploadServer{failPuts: map[int]int{2: http.StatusServiceUnavailable}, keepBytes: 1}
+	var log bytes.Buffer
+	u := newTestUpload(t, f, 3, &log)
+
+	if _, err := u.upload(context.Background(), http.MethodPost, "", &drive.File{}, strings.NewReader("0123456789")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.received.String() != "0123456789" {
+		t.Errorf("received %q", f.received.String())
+	}
+	want := []string{"bytes 0-3/*", "bytes 4-7/*", "bytes */*", "bytes 5-7/*", "bytes 8-9/10"}
+	if strings.Join(f.ranges, ",") != strings.Join(want, ",") {
+		t.Errorf("expected ranges %v, got %v", want, f.ranges)
+	}
+	if !strings.Contains(log.String(), "Bytes 4-7 failed") || !strings.Contains(log.String(), "(1 of 3)") {
+		t.Errorf("expected the retry in the log, got:\n%s", log.String())
+	}
+}
+
+func TestResumableUpload_GivesUpNamingTheRange(t *testing.T) {
+	f := &fakeUploadServer{failPuts: map[int]int{2: 503, 3: 503, 4: 503}}
+	u := newTestUpload(t, f, 2, nil)
+
+	_, err := u.upload(context.Background(), http.MethodPost, "", &drive.File{}, strings.NewReader("0123456789"))
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) {
+		t.Fatalf("expected a ChunkError, got %v", err)
+	}
+	if chunkErr.Start != 4 || chunkErr.End != 7 || chunkErr.Attempts != 3 {
+		t.Errorf("unexpected chunk error: %+v", chunkErr)
+	}
+	if !strings.Contains(err.Error(), "bytes 4-7 failed after 3 attempts") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestResumableUpload_ClientErrorIsNotRetried(t *testing.T) {
+	f := &fakeUploadServer{failPuts: map[int]int{1: http.StatusForbidden}}
+	u := newTestUpload(t, f, 3, nil)
+
+	_, err := u.upload(context.Background(), http.MethodPost, "", &drive.File{}, strings.NewReader("0123"))
+	if err == nil || !strings.Contains(err.Error(), "unable to upload bytes 0-3") {
+		t.Fatalf("expected a non-retried failure, got %v", err)
+	}
+	if f.puts != 1 {
+		t.Errorf("expected 1 attempt, got %d", f.puts)
+	}
+}
+
+func TestResumableUpload_ReplacePatchesTheFile(t *testing.T) {
+	f := &fakeUploadServer{}
+	u := newTestUpload(t, f, 3, nil)
+
+	if _, err := u.upload(context.Background(), http.MethodPatch, "file-1", &drive.File{}, strings.NewReader("abc")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.method != http.MethodPatch {
+		t.Errorf("expected PATCH to start the session, got %s", f.method)
+	}
+}
+
+func TestResumableUpload_SleepsBetweenRetries(t *testing.T) {
+	f := &fakeUploadServer{failPuts: map[int]int{1: 503, 2: 503}}
+	u := newTestUpload(t, f, 3, nil)
+	u.backoff = chunkBackoff
+	var waits []time.Duration
+	u.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	if _, err := u.upload(context.Background(), http.MethodPost, "", &drive.File{}, strings.NewReader("0123")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []time.Duration{chunkBackoff(1), chunkBackoff(2)}
+	if fmt.Sprint(waits) != fmt.Sprint(want) {
+		t.Errorf("expected waits %v, got %v", want, waits)
+	}
+}
+
+func TestResumableUpload_CancelledSleepStopsRetrying(t *testing.T) {
+	f := &fakeUploadServer{failPuts: map[int]int{1: 503}}
+	u := newTestUpload(t, f, 3, nil)
+	u.sleep = func(ctx context.Context, d time.Duration) error {
+		return context.Canceled
+	}
+
+	_, err := u.upload(context.Background(), http.MethodPost, "", &drive.File{}, strings.NewReader("0123"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancellation, got %v", err)
+	}
+	if f.puts != 1 {
+		t.Errorf("expected 1 attempt, got %d", f.puts)
+	}
+}
+
+func TestChunkBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{5, 32 * time.Second},
+		{6, time.Minute},
+		{40, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := chunkBackoff(tt.attempt); got != tt.want {
+			t.Errorf("chunkBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package drive
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"nac-service-media/domain/distribution"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// Scope modes select how much of the user's Drive the app asks for
+const (
+	ScopeModeFull = "full" // every file (the drive scope)
+	ScopeModeFile = "file" // only files the app created (the drive.file scope)
+)
+
+// Scope returns the OAuth scope requested for a scope mode; empty means full
+func Scope(mode string) string {
+	if mode == ScopeModeFile {
+		return drive.DriveFileScope
+	}
+	return drive.DriveScope
+}
+
+// ValidateScopeMode checks a google.scope_mode value
+func ValidateScopeMode(mode string) error {
+	switch mode {
+	case "", ScopeModeFull, ScopeModeFile:
+		return nil
+	}
+	return fmt.Errorf("%q must be full or file", mode)
+}
+
+// WithScopeMode requests the scope for mode when signing in. In file mode
+// the client only sees files it uploaded.
+func WithScopeMode(mode string) ClientOption {
+	return func(c *Client) {
+		c.scopeMode = mode
+	}
+}
+
+// AppFilesOnly implements distribution.AppFileScoped
+func (c *Client) AppFilesOnly() bool {
+	return c.scopeMode == ScopeModeFile
+}
+
+// scopeError explains a Drive error caused by the granted scope, naming the
+// operation that needed more access; other errors are returned unchanged
+func (c *Client) scopeError(err error, operation string) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch {
+	case apiErr.Code == http.StatusForbidden && insufficientScope(apiErr):
+		if c.AppFilesOnly() {
+			return fmt.Errorf("%w: %s needs google.scope_mode: full, then 'nac-service-media auth status --fix' to sign in again: %w",
+				distribution.ErrInsufficientScope, operation, err)
+		}
+		return fmt.Errorf("%w: the saved Drive token does not allow %s; delete the Drive token file and run 'nac-service-media auth status --fix' to sign in again: %w",
+			distribution.ErrInsufficientScope, operation, err)
+	case apiErr.Code == http.StatusNotFound && c.AppFilesOnly():
+		return fmt.Errorf("%w: %s failed because the file or folder was not created by nac-service-media, which google.scope_mode: file cannot see; use a services folder the app created or set google.scope_mode: full: %w",
+			distribution.ErrInsufficientScope, operation, err)
+	}
+	return err
+}
+
+// insufficientScope reports whether a 403 was caused by the token's scopes
+// rather than by the user's permissions on a file
+func insufficientScope(e *googleapi.Error) bool {
+	if strings.Contains(strings.ToLower(e.Message), "insufficient authentication scopes") {
+		return true
+	}
+	for _, item := range e.Errors {
+		if item.Reason == "insufficientScopes" ||
+			item.Reason == "insufficientPermissions" && strings.Contains(strings.ToLower(item.Message), "scope") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package drive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"nac-service-media/domain/distribution"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{mode: "", want: drive.DriveScope},
+		{mode: ScopeModeFull, want: drive.DriveScope},
+		{mode: ScopeModeFile, want: drive.DriveFileScope},
+	}
+
+	for _, tt := range tests {
+		if got := Scope(tt.mode); got != tt.want {
+			t.Errorf("Scope(%q) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestValidateScopeMode(t *testing.T) {
+	for _, mode := range []string{"", "full", "file"} {
+		if err := ValidateScopeMode(mode); err != nil {
+			t.Errorf("ValidateScopeMode(%q) = %v, want nil", mode, err)
+		}
+	}
+	if err := ValidateScopeMode("readonly"); err == nil {
+		t.Error("ValidateScopeMode(readonly) succeeded, want an error")
+	}
+}
+
+func TestClient_AppFilesOnly(t *testing.T) {
+	full, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}))
+	file, _ := NewClient(context.Background(), "", WithDriveService(&mockDriveService{}), WithScopeMode(ScopeModeFile))
+
+	if distribution.SeesAppFilesOnly(full) {
+		t.Error("full scope client reports app files only")
+	}
+	if !distribution.SeesAppFilesOnly(file) {
+		t.Error("file scope client does not report app files only")
+	}
+}
+
+func TestClient_ScopeErrors(t *testing.T) {
+	scopeDenied := &googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: "Request had insufficient authentication scopes.",
+		Errors:  []googleapi.ErrorItem{{Reason: "insufficientPermissions", Message: "Insufficient Permission: Request had insufficient authentication scopes."}},
+	}
+	fileDenied := &googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: "The user does not have sufficient permissions for this file.",
+		Errors:  []googleapi.ErrorItem{{Reason: "insufficientFilePermissions"}},
+	}
+	notFound := &googleapi.Error{
+		Code:    http.StatusNotFound,
+		Message: "File not found: folder-123.",
+		Errors:  []googleapi.ErrorItem{{Reason: "notFound"}},
+	}
+
+	tests := []struct {
+		name      string
+		scopeMode string
+		apiErr    error
+		wantScope bool
+		wantText  string
+	}{
+		{name: "file scope missing access", scopeMode: ScopeModeFile, apiErr: scopeDenied, wantScope: true, wantText: "needs google.scope_mode: full"},
+		{name: "full scope with a narrow token", scopeMode: ScopeModeFull, apiErr: scopeDenied, wantScope: true, wantText: "the saved Drive token does not allow"},
+		{name: "file scope cannot see the folder", scopeMode: ScopeModeFile, apiErr: notFound, wantScope: true, wantText: "not created by nac-service-media"},
+		{name: "full scope folder really missing", scopeMode: ScopeModeFull, apiErr: notFound},
+		{name: "file permission is not a scope problem", scopeMode: ScopeModeFile, apiErr: fileDenied},
+		{name: "other errors pass through", scopeMode: ScopeModeFile, apiErr: fmt.Errorf("connection reset")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockDriveService{shouldFail: true, failError: tt.apiErr}
+			client, _ := NewClient(context.Background(), "", WithDriveService(mock), WithScopeMode(tt.scopeMode))
+
+			_, err := client.ListFiles(context.Background(), "folder-123")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := errors.Is(err, distribution.ErrInsufficientScope); got != tt.wantScope {
+				t.Errorf("errors.Is(ErrInsufficientScope) = %v, want %v (err: %v)", got, tt.wantScope, err)
+			}
+			if !errors.Is(err, tt.apiErr) {
+				t.Errorf("error does not wrap the API error: %v", err)
+			}
+			if tt.wantText != "" && !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestClient_EmptyTrash_FileScope(t *testing.T) {
+	mock := &mockDriveService{}
+	client, _ := NewClient(context.Background(), "", WithDriveService(mock), WithScopeMode(ScopeModeFile))
+
+	err := client.EmptyTrash(context.Background())
+	if !errors.Is(err, distribution.ErrInsufficientScope) {
+		t.Errorf("EmptyTrash() = %v, want ErrInsufficientScope", err)
+	}
+	if mock.trashEmptied {
+		t.Error("trash was emptied despite the file scope")
+	}
+}
@@ -0,0 +1,91 @@
+package drive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// sessionLifetime is how long Drive keeps an unfinished upload session
+const sessionLifetime = 7 * 24 * time.Hour
+
+// SessionStore keeps the URLs of unfinished Drive uploads on disk, one JSON
+// file per upload, so a run cut off by a dropped connection can resume the
+// upload next time instead of sending the file again
+type SessionStore struct {
+	dir string
+	now func() time.Time
+}
+
+// NewSessionStore creates a store backed by dir, which is created on first save
+func NewSessionStore(dir string) *SessionStore {
+	return &SessionStore{dir: dir, now: time.Now}
+}
+
+// savedSession is an upload session and the local file it was sending
+type savedSession struct {
+	URL       string    `json:"url"`
+	LocalPath string    `json:"local_path"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"mod_time"`
+	Started   time.Time `json:"started"`
+}
+
+// sessionKey names an upload by where it is going and what it is sending
+func sessionKey(folderID, fileName, localPath string) string {
+	sum := sha256.Sum256([]byte(folderID + "\x00" + fileName + "\x00" + localPath))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s *SessionStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// load returns the session saved for key if it was sending the same file,
+// unchanged, and Drive may still have it; otherwise "" and the saved session
+// is forgotten
+func (s *SessionStore) load(key string, size int64, modTime time.Time) string {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return ""
+	}
+	var saved savedSession
+	if json.Unmarshal(data, &saved) != nil || saved.Size != size || !saved.ModTime.Equal(modTime) ||
+		s.now().Sub(saved.Started) >= sessionLifetime {
+		s.remove(key)
+		return ""
+	}
+	return saved.URL
+}
+
+// save writes the session through a temporary file, so a crash mid-write
+// never leaves half a session behind
+func (s *SessionStore) save(key string, session savedSession) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create upload session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode upload session: %w", err)
+	}
+	path := s.path(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write upload session: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace upload session: %w", err)
+	}
+	return nil
+}
+
+// remove forgets a session. It is best effort: a session left behind is
+// checked with Drive and dropped the next time.
+func (s *SessionStore) remove(key string) {
+	os.Remove(s.path(key))
+}
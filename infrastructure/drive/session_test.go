@@ -0,0 +1,114 @@
+package drive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionStore_ForgetsChangedOrExpiredFiles(t *testing.T) {
+	store := NewSessionStore(filepath.Join(t.TempDir(), "uploads"))
+	started := time.Date(2025, 12, 28, 12, 0, 0, 0, time.UTC)
+	modTime := started.Add(-time.Hour)
+	store.now = func() time.Time { return started }
+
+	if err := store.save("k", savedSession{URL: "https://upload/1", Size: 10, ModTime: modTime, Started: started}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if got := store.load("k", 10, modTime); got != "https://upload/1" {
+		t.Errorf("load() = %q, want the saved session", got)
+	}
+	if got := store.load("k", 11, modTime); got != "" {
+		t.Errorf("load() for a changed file = %q, want none", got)
+	}
+	if got := store.load("k", 10, modTime); got != "" {
+		t.Errorf("load() after a mismatch = %q, want the session forgotten", got)
+	}
+
+	store.save("k", savedSession{URL: "https://upload/2", Size: 10, ModTime: modTime, Started: started})
+	store.now = func() time.Time { return started.Add(sessionLifetime) }
+	if got := store.load("k", 10, modTime); got != "" {
+		t.Errorf("load() a week later = %q, want none", got)
+	}
+}
+
+// redirectTransport sends every request to a test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newSessionTestService(t *testing.T, f *fakeUploadServer, dir string) *GoogleDriveService {
+	t.Helper()
+	server := httptest.NewServer(f.handler())
+	t.Cleanup(server.Close)
+	target, _ := url.Parse(server.URL)
+	return &GoogleDriveService{
+		httpClient:   &http.Client{Transport: redirectTransport{target: target}},
+		chunkRetries: 1,
+		sessions:     NewSessionStore(dir),
+		sleep:        func(context.Context, time.Duration) error { return nil },
+	}
+}
+
+func TestGoogleDriveService_ResumesAnInterruptedUpload(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, "2025-12-28.mp4")
+	if err := os.WriteFile(local, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sessions := filepath.Join(dir, "uploads")
+
+	// The connection drops twice, each time after two bytes got through
+	f := &fakeUploadServer{failPuts: map[int]int{1: 503, 2: 503}, keepBytes: 2}
+	svc := newSessionTestService(t, f, sessions)
+	_, err := svc.UploadFile(context.Background(), "2025-12-28.mp4", "video/mp4", "folder", local, nil)
+	if err == nil || !strings.Contains(err.Error(), "run again to resume from 2 B") {
+		t.Fatalf("expected an interrupted upload, got %v", err)
+	}
+
+	// The next run picks up where Drive left off
+	f.failPuts, f.ranges = nil, nil
+	svc = newSessionTestService(t, f, sessions)
+	file, err := svc.UploadFile(context.Background(), "2025-12-28.mp4", "video/mp4", "folder", local, nil)
+	if err != nil {
+		t.Fatalf("resumed upload: %v", err)
+	}
+	if file.Id != "file-1" || f.received.String() != "0123456789" {
+		t.Errorf("file = %+v, received %q", file, f.received.String())
+	}
+	want := []string{"bytes */10", "bytes 4-9/10"}
+	if strings.Join(f.ranges, ",") != strings.Join(want, ",") {
+		t.Errorf("expected ranges %v, got %v", want, f.ranges)
+	}
+	if entries, _ := os.ReadDir(sessions); len(entries) != 0 {
+		t.Errorf("expected the finished session to be forgotten, found %d", len(entries))
+	}
+}
+
+func TestResumableUpload_ResumeReportsAnExpiredSession(t *testing.T) {
+	f := &fakeUploadServer{gone: true}
+	u := newTestUpload(t, f, 3, nil)
+	r := strings.NewReader("0123456789")
+
+	session := strings.TrimSuffix(u.baseURL, "/upload/drive/v3/files") + "/session"
+	_, err := u.resume(context.Background(), session, r, 10)
+	if !errors.Is(err, errSessionGone) {
+		t.Fatalf("expected errSessionGone, got %v", err)
+	}
+	if r.Len() != 10 {
+		t.Errorf("expected nothing read, %d bytes left", r.Len())
+	}
+}
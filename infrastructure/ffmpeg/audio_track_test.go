@@ -0,0 +1,86 @@
+package ffmpeg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/video"
+)
+
+// recordingRunner captures ffmpeg arguments
+type recordingRunner struct {
+	args []string
+}
+
+func (r *recordingRunner) Run(ctx context.Context, name string, args ...string) error {
+	r.args = args
+	return nil
+}
+
+func (r *recordingRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	r.args = args
+	return nil, nil
+}
+
+func TestTrimmer_AudioTrackMap(t *testing.T) {
+	start, _ := video.ParseTimestamp("00:05:30")
+	end, _ := video.ParseTimestamp("01:45:00")
+
+	tests := []struct {
+		name  string
+		track int
+		want  string
+	}{
+		{name: "default track", track: 0, want: "-i src.mp4 -ss 00:05:30 -to 01:45:00 -c copy"},
+		{name: "second track", track: 2, want: "-i src.mp4 -ss 00:05:30 -to 01:45:00 -map 0:v:0 -map 0:a:1 -c copy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &recordingRunner{}
+			trimmer := NewTrimmer(WithCommandRunner(runner))
+			req := &video.TrimRequest{SourcePath: "src.mp4", Start: start, End: end, AudioTrack: tt.track}
+
+			if err := trimmer.Trim(context.Background(), req, "out.mp4"); err != nil {
+				t.Fatalf("Trim() error = %v", err)
+			}
+			if got := strings.Join(runner.args, " "); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("args = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractor_AudioTrackMap(t *testing.T) {
+	date := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		track int
+		want  string
+	}{
+		{name: "default track", track: 0, want: "-i src.mp4 -vn"},
+		{name: "second track", track: 2, want: "-i src.mp4 -map 0:a:1 -vn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &recordingRunner{}
+			extractor := NewExtractor(WithExtractorCommandRunner(runner))
+			req, err := video.NewAudioExtractionRequest("src.mp4", date, "192k")
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.AudioTrack = tt.track
+
+			if err := extractor.Extract(context.Background(), req, "out.mp3"); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if got := strings.Join(runner.args, " "); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("args = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// LocalDir is the project folder checked for ffmpeg before PATH, so a
+// known-good build can be kept next to the config
+const LocalDir = "ffmpeg"
+
+// DefaultMinVersion is the oldest ffmpeg known to trim, normalize and
+// concatenate the way the workflow expects
+const DefaultMinVersion = "4.4"
+
+// ErrFFmpegTooOld is returned when the installed ffmpeg is older than the
+// configured minimum
+var ErrFFmpegTooOld = errors.New("ffmpeg is too old")
+
+// Tools are the ffmpeg and ffprobe executables to run
+type Tools struct {
+	FFmpeg  string
+	FFprobe string
+	Source  string // "config", "local" or "PATH"
+}
+
+// Locate finds ffmpeg and ffprobe. A configured path must exist, and a bare
+// name is looked up on PATH; otherwise the project-local ffmpeg folder wins
+// over PATH. ffprobe is taken from beside ffmpeg when it is there.
+func Locate(configured string) (Tools, error) {
+	if configured != "" && !strings.ContainsAny(configured, `/\`) {
+		path, err := exec.LookPath(configured)
+		if err != nil {
+			return Tools{}, fmt.Errorf("video.ffmpeg_path %s not found on PATH: %w", configured, err)
+		}
+		configured = path
+	}
+	if configured != "" {
+		if _, err := os.Stat(configured); err != nil {
+			return Tools{}, fmt.Errorf("video.ffmpeg_path %s not found: %w", configured, err)
+		}
+		return Tools{FFmpeg: configured, FFprobe: sibling(configured, "ffprobe"), Source: "config"}, nil
+	}
+	for _, dir := range []string{LocalDir, filepath.Join(LocalDir, "bin")} {
+		path := filepath.Join(dir, executable("ffmpeg"))
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return Tools{FFmpeg: path, FFprobe: sibling(path, "ffprobe"), Source: "local"}, nil
+		}
+	}
+	path, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return Tools{}, fmt.Errorf("ffmpeg not found in %s or on PATH; install it or set video.ffmpeg_path", LocalDir)
+	}
+	return Tools{FFmpeg: path, FFprobe: sibling(path, "ffprobe"), Source: "PATH"}, nil
+}
+
+// sibling returns the named tool from ffmpeg's folder, falling back to PATH
+func sibling(ffmpegPath, name string) string {
+	path := filepath.Join(filepath.Dir(ffmpegPath), executable(name))
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	if found, err := exec.LookPath(name); err == nil {
+		return found
+	}
+	return name
+}
+
+func executable(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
+
+// Version is an ffmpeg release number. Raw is the version as ffmpeg
+// reported it, e.g. "6.1.1-3ubuntu5" or "N-113478-g7ca2ad0" for a git build.
+type Version struct {
+	Major, Minor, Patch int
+	Raw                 string
+}
+
+// String returns the version as ffmpeg reported it
+func (v Version) String() string {
+	return v.Raw
+}
+
+// Known reports whether the release number could be read. Git builds carry
+// no release number.
+func (v Version) Known() bool {
+	return v.Major > 0
+}
+
+// Less reports whether v is an older release than other
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+var releasePattern = regexp.MustCompile(`^n?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// ParseVersion reads a version such as "4.4", "6.1.1-3ubuntu5" or "n7.0"
+func ParseVersion(s string) (Version, error) {
+	m := releasePattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid ffmpeg version %q", s)
+	}
+	v := Version{Raw: s}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, nil
+}
+
+// parseVersionOutput reads the version from `ffmpeg -version` output, whose
+// first line reads "ffmpeg version 6.1.1 Copyright ..."
+func parseVersionOutput(out []byte) (Version, error) {
+	line, _, _ := strings.Cut(string(out), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 3 || fields[1] != "version" {
+		return Version{}, fmt.Errorf("unexpected ffmpeg -version output %q", line)
+	}
+	v, err := ParseVersion(fields[2])
+	if err != nil {
+		// A git build such as N-113478-g7ca2ad0 has no release number
+		return Version{Raw: fields[2]}, nil
+	}
+	return v, nil
+}
+
+// DetectVersion runs `ffmpeg -version` and reads the installed version
+func DetectVersion(ctx context.Context, runner CommandRunner, ffmpegPath string) (Version, error) {
+	out, err := runner.Output(ctx, ffmpegPath, "-version")
+	if err != nil {
+		return Version{}, fmt.Errorf("ffmpeg not found or not executable: %w", err)
+	}
+	return parseVersionOutput(out)
+}
+
+// RequireVersion returns ErrFFmpegTooOld when v is older than min. A
+// version with no release number, such as a git build, is accepted.
+func RequireVersion(v Version, min string) error {
+	if min == "" || !v.Known() {
+		return nil
+	}
+	want, err := ParseVersion(min)
+	if err != nil {
+		return err
+	}
+	if v.Less(want) {
+		return fmt.Errorf("%w: found %s, need %s or newer; upgrade ffmpeg or point video.ffmpeg_path at a newer build", ErrFFmpegTooOld, v, min)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in    string
+		major int
+		minor int
+		patch int
+	}{
+		{"4.4", 4, 4, 0},
+		{"6.1.1-3ubuntu5", 6, 1, 1},
+		{"n7.0", 7, 0, 0},
+		{"7", 7, 0, 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q) error = %v", tt.in, err)
+		}
+		if got.Major != tt.major || got.Minor != tt.minor || got.Patch != tt.patch || got.String() != tt.in {
+			t.Errorf("ParseVersion(%q) = %+v", tt.in, got)
+		}
+	}
+
+	if _, err := ParseVersion("latest"); err == nil {
+		t.Error("expected an error for a version with no number")
+	}
+}
+
+func TestDetectVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+		known  bool
+	}{
+		{"release", "ffmpeg version 6.1.1-3ubuntu5 Copyright (c) 2000-2023 the FFmpeg developers\nbuilt with gcc 13\n", "6.1.1-3ubuntu5", true},
+		{"git build", "ffmpeg version N-113478-g7ca2ad0 Copyright (c) 2000-2024\n", "N-113478-g7ca2ad0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &probeRunner{output: tt.output}
+			got, err := DetectVersion(context.Background(), runner, "ffmpeg")
+			if err != nil {
+				t.Fatalf("DetectVersion() error = %v", err)
+			}
+			if got.String() != tt.want || got.Known() != tt.known {
+				t.Errorf("DetectVersion() = %+v, want %s (known %v)", got, tt.want, tt.known)
+			}
+		})
+	}
+
+	if _, err := DetectVersion(context.Background(), &probeRunner{output: "not ffmpeg"}, "ffmpeg"); err == nil {
+		t.Error("expected an error for output that is not from ffmpeg")
+	}
+}
+
+func TestRequireVersion(t *testing.T) {
+	v := func(s string) Version {
+		parsed, err := ParseVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	if err := RequireVersion(v("4.3.2"), "4.4"); !errors.Is(err, ErrFFmpegTooOld) {
+		t.Errorf("RequireVersion(4.3.2, 4.4) = %v, want ErrFFmpegTooOld", err)
+	}
+	for _, ok := range []string{"4.4", "4.4.1", "5.0", "10.0"} {
+		if err := RequireVersion(v(ok), "4.4"); err != nil {
+			t.Errorf("RequireVersion(%s, 4.4) = %v", ok, err)
+		}
+	}
+	if err := RequireVersion(Version{Raw: "N-113478-g7ca2ad0"}, "4.4"); err != nil {
+		t.Errorf("a git build should be accepted, got %v", err)
+	}
+	if err := RequireVersion(v("2.8"), ""); err != nil {
+		t.Errorf("no minimum should accept anything, got %v", err)
+	}
+}
+
+func TestTrimmer_VerifyInstalled_MinVersion(t *testing.T) {
+	runner := &probeRunner{output: "ffmpeg version 4.2.7 Copyright\n"}
+	trimmer := NewTrimmer(WithCommandRunner(runner), WithMinVersion("4.4"))
+
+	err := trimmer.VerifyInstalled(context.Background())
+	if !errors.Is(err, ErrFFmpegTooOld) {
+		t.Fatalf("VerifyInstalled() = %v, want ErrFFmpegTooOld", err)
+	}
+	if runner.args[0] != "-version" {
+		t.Errorf("ran ffmpeg %v, want -version", runner.args)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("PATH", "")
+
+	if _, err := Locate(""); err == nil {
+		t.Fatal("expected an error with no ffmpeg anywhere")
+	}
+	if _, err := Locate(filepath.Join(dir, "missing", "ffmpeg")); err == nil {
+		t.Fatal("expected an error for a configured path that does not exist")
+	}
+
+	local := filepath.Join(LocalDir, "bin", executable("ffmpeg"))
+	writeExecutable(t, local)
+	writeExecutable(t, filepath.Join(LocalDir, "bin", executable("ffprobe")))
+	tools, err := Locate("")
+	if err != nil {
+		t.Fatalf("Locate() error = %v", err)
+	}
+	if tools.FFmpeg != local || tools.Source != "local" {
+		t.Errorf("Locate() = %+v, want the local build", tools)
+	}
+	if tools.FFprobe != filepath.Join(LocalDir, "bin", executable("ffprobe")) {
+		t.Errorf("FFprobe = %s, want the one beside ffmpeg", tools.FFprobe)
+	}
+
+	configured := filepath.Join(dir, "opt", executable("ffmpeg"))
+	writeExecutable(t, configured)
+	tools, err = Locate(configured)
+	if err != nil {
+		t.Fatalf("Locate() error = %v", err)
+	}
+	if tools.FFmpeg != configured || tools.Source != "config" || tools.FFprobe != "ffprobe" {
+		t.Errorf("Locate(%s) = %+v", configured, tools)
+	}
+}
+
+func writeExecutable(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
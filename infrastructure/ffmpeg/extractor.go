@@ -3,6 +3,7 @@ package ffmpeg
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"nac-service-media/domain/video"
 )
@@ -10,6 +11,7 @@ import (
 // Extractor implements video.AudioExtractor using ffmpeg
 type Extractor struct {
 	ffmpegPath string
+	minVersion string
 	runner     CommandRunner
 }
 
@@ -23,6 +25,13 @@ func WithExtractorFFmpegPath(path string) ExtractorOption {
 	}
 }
 
+// WithExtractorMinVersion makes VerifyInstalled reject an ffmpeg older than min
+func WithExtractorMinVersion(min string) ExtractorOption {
+	return func(e *Extractor) {
+		e.minVersion = min
+	}
+}
+
 // WithExtractorCommandRunner sets a custom command runner (for testing)
 func WithExtractorCommandRunner(runner CommandRunner) ExtractorOption {
 	return func(e *Extractor) {
@@ -46,6 +55,36 @@ func NewExtractor(opts ...ExtractorOption) *Extractor {
 
 // Extract implements video.AudioExtractor
 func (e *Extractor) Extract(ctx context.Context, req *video.AudioExtractionRequest, outputPath string) error {
+	args := append(encodeArgs(req),
+		"-y", // Overwrite output file if it exists
+		outputPath,
+	)
+
+	if err := e.runner.Run(ctx, e.ffmpegPath, args...); err != nil {
+		return fmt.Errorf("ffmpeg audio extraction failed: %w", err)
+	}
+
+	return nil
+}
+
+// Stream implements video.AudioStreamer, writing the MP3 to w as ffmpeg encodes it
+func (e *Extractor) Stream(ctx context.Context, req *video.AudioExtractionRequest, w io.Writer) error {
+	runner, ok := e.runner.(StreamRunner)
+	if !ok {
+		return fmt.Errorf("ffmpeg command runner cannot stream output")
+	}
+
+	args := append(encodeArgs(req), "-f", "mp3", "pipe:1")
+	if err := runner.RunWithStdout(ctx, w, e.ffmpegPath, args...); err != nil {
+		return fmt.Errorf("ffmpeg audio streaming failed: %w", err)
+	}
+
+	return nil
+}
+
+// encodeArgs returns the ffmpeg arguments that select and encode the audio,
+// without the output
+func encodeArgs(req *video.AudioExtractionRequest) []string {
 	var args []string
 
 	// If timestamps are provided, add seek and duration options
@@ -56,30 +95,37 @@ func (e *Extractor) Extract(ctx context.Context, req *video.AudioExtractionReque
 		)
 	}
 
+	args = append(args, "-i", req.SourceVideoPath)
+	if m := video.AudioTrackMap(req.AudioTrack); m != "" {
+		args = append(args, "-map", m)
+	}
+
 	args = append(args,
-		"-i", req.SourceVideoPath,
 		"-vn",                   // No video
 		"-acodec", "libmp3lame", // MP3 codec
 		"-ab", req.Bitrate,      // Audio bitrate
-		"-y",                    // Overwrite output file if it exists
-		outputPath,
 	)
-
-	if err := e.runner.Run(ctx, e.ffmpegPath, args...); err != nil {
-		return fmt.Errorf("ffmpeg audio extraction failed: %w", err)
-	}
-
-	return nil
+	return append(args, metadataArgs(req.Tags)...)
 }
 
-// VerifyInstalled checks that ffmpeg is available
+// VerifyInstalled checks that ffmpeg is available and new enough
 func (e *Extractor) VerifyInstalled(ctx context.Context) error {
-	_, err := e.runner.Output(ctx, e.ffmpegPath, "-version")
+	_, err := e.Version(ctx)
+	return err
+}
+
+// Version returns the installed ffmpeg version, or ErrFFmpegTooOld when it is
+// older than the minimum
+func (e *Extractor) Version(ctx context.Context) (Version, error) {
+	v, err := DetectVersion(ctx, e.runner, e.ffmpegPath)
 	if err != nil {
-		return fmt.Errorf("ffmpeg not found or not executable: %w", err)
+		return Version{}, err
 	}
-	return nil
+	return v, RequireVersion(v, e.minVersion)
 }
 
-// Ensure Extractor implements video.AudioExtractor
-var _ video.AudioExtractor = (*Extractor)(nil)
+// Ensure Extractor implements video.AudioExtractor and video.AudioStreamer
+var (
+	_ video.AudioExtractor = (*Extractor)(nil)
+	_ video.AudioStreamer  = (*Extractor)(nil)
+)
@@ -0,0 +1,54 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/video"
+)
+
+// streamingRunner captures ffmpeg arguments and writes fake MP3 data to stdout
+type streamingRunner struct {
+	recordingRunner
+}
+
+func (r *streamingRunner) RunWithStdout(ctx context.Context, stdout io.Writer, name string, args ...string) error {
+	r.args = args
+	_, err := stdout.Write([]byte("mp3 data"))
+	return err
+}
+
+func TestExtractor_Stream(t *testing.T) {
+	date := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	req, err := video.NewAudioExtractionRequestWithTimestamps("src.mp4", date, "192k", "00:05:30", "01:45:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &streamingRunner{}
+	extractor := NewExtractor(WithExtractorCommandRunner(runner))
+	var out bytes.Buffer
+	if err := extractor.Stream(context.Background(), req, &out); err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+
+	want := "-ss 00:05:30 -to 01:45:00 -i src.mp4 -vn -acodec libmp3lame -ab 192k -f mp3 pipe:1"
+	if got := strings.Join(runner.args, " "); got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+	if out.String() != "mp3 data" {
+		t.Errorf("streamed %q, want the runner's stdout", out.String())
+	}
+}
+
+func TestExtractor_StreamNeedsStreamRunner(t *testing.T) {
+	extractor := NewExtractor(WithExtractorCommandRunner(&recordingRunner{}))
+	req, _ := video.NewAudioExtractionRequest("src.mp4", time.Now(), "192k")
+	if err := extractor.Stream(context.Background(), req, io.Discard); err == nil {
+		t.Error("expected an error from a runner that cannot stream")
+	}
+}
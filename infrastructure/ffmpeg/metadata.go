@@ -0,0 +1,57 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"nac-service-media/domain/video"
+)
+
+// metadataArgs returns the ffmpeg options that write tags into the output.
+// The scripture goes in the comment tag, which both MP4 and ID3 players show.
+func metadataArgs(tags video.MediaTags) []string {
+	var args []string
+	if tags.Title != "" {
+		args = append(args, "-metadata", "title="+tags.Title)
+	}
+	if tags.Scripture != "" {
+		args = append(args, "-metadata", "comment="+tags.Scripture)
+	}
+	return args
+}
+
+// chapterMetadata returns an FFMETADATA file marking the chapters, each
+// running until the next one or end. Times stay on the source's timeline;
+// ffmpeg shifts them by the trim start and drops any outside the trim.
+func chapterMetadata(chapters []video.Chapter, end video.Timestamp) string {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for i, c := range chapters {
+		until := end
+		if i+1 < len(chapters) {
+			until = chapters[i+1].At
+		}
+		fmt.Fprintf(&b, "[CHAPTER]\nTIMEBASE=1/1\nSTART=%d\nEND=%d\ntitle=%s\n",
+			c.At.TotalSeconds(), until.TotalSeconds(), metadataEscaper.Replace(c.Title))
+	}
+	return b.String()
+}
+
+// metadataEscaper escapes the characters FFMETADATA files treat as special
+var metadataEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", `\`+"\n")
+
+// writeChapterMetadata saves the chapters for ffmpeg to read; the caller
+// removes the file
+func writeChapterMetadata(chapters []video.Chapter, end video.Timestamp) (string, error) {
+	f, err := os.CreateTemp("", "nac-chapters-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to write chapters: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(chapterMetadata(chapters, end)); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write chapters: %w", err)
+	}
+	return f.Name(), nil
+}
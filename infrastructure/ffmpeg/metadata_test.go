@@ -0,0 +1,83 @@
+package ffmpeg
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/video"
+)
+
+func TestTrimmer_WritesTags(t *testing.T) {
+	start, _ := video.ParseTimestamp("00:05:30")
+	end, _ := video.ParseTimestamp("01:45:00")
+	runner := &recordingRunner{}
+	trimmer := NewTrimmer(WithCommandRunner(runner))
+	req := &video.TrimRequest{SourcePath: "src.mp4", Start: start, End: end,
+		Tags: video.MediaTags{Title: "The Good Shepherd", Scripture: "John 10:11-16"}}
+
+	if err := trimmer.Trim(context.Background(), req, "out.mp4"); err != nil {
+		t.Fatalf("Trim() error = %v", err)
+	}
+	for _, want := range []string{"title=The Good Shepherd", "comment=John 10:11-16"} {
+		if i := slices.Index(runner.args, want); i < 1 || runner.args[i-1] != "-metadata" {
+			t.Errorf("args %q do not set -metadata %q", runner.args, want)
+		}
+	}
+}
+
+func TestExtractor_WritesTags(t *testing.T) {
+	runner := &recordingRunner{}
+	extractor := NewExtractor(WithExtractorCommandRunner(runner))
+	req, _ := video.NewAudioExtractionRequest("src.mp4", time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC), "192k")
+	req.Tags = video.MediaTags{Title: "The Good Shepherd"}
+
+	if err := extractor.Extract(context.Background(), req, "out.mp3"); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if i := slices.Index(runner.args, "title=The Good Shepherd"); i < 1 || runner.args[i-1] != "-metadata" {
+		t.Errorf("args %q do not set the title", runner.args)
+	}
+	if slices.ContainsFunc(runner.args, func(a string) bool { return strings.HasPrefix(a, "comment=") }) {
+		t.Errorf("args %q set a comment without a scripture", runner.args)
+	}
+}
+
+func TestChapterMetadata(t *testing.T) {
+	chapters := []video.Chapter{
+		{Title: "Welcome", At: video.Timestamp{Minutes: 5, Seconds: 30}},
+		{Title: "Sermon; part=1", At: video.Timestamp{Minutes: 40}},
+	}
+	got := chapterMetadata(chapters, video.Timestamp{Hours: 1, Minutes: 45})
+	want := ";FFMETADATA1\n" +
+		"[CHAPTER]\nTIMEBASE=1/1\nSTART=330\nEND=2400\ntitle=Welcome\n" +
+		"[CHAPTER]\nTIMEBASE=1/1\nSTART=2400\nEND=6300\ntitle=Sermon\\; part\\=1\n"
+	if got != want {
+		t.Errorf("chapterMetadata() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTrimmer_MapsChapters(t *testing.T) {
+	start, _ := video.ParseTimestamp("00:05:30")
+	end, _ := video.ParseTimestamp("01:45:00")
+	runner := &recordingRunner{}
+	trimmer := NewTrimmer(WithCommandRunner(runner))
+	req := &video.TrimRequest{SourcePath: "src.mp4", Start: start, End: end,
+		Chapters: []video.Chapter{{Title: "Sermon", At: video.Timestamp{Minutes: 40}}}}
+
+	if err := trimmer.Trim(context.Background(), req, "out.mp4"); err != nil {
+		t.Fatalf("Trim() error = %v", err)
+	}
+	if len(runner.args) < 4 || runner.args[2] != "-i" || !strings.Contains(runner.args[3], "nac-chapters-") {
+		t.Fatalf("args %q do not read the chapters as a second input", runner.args)
+	}
+	if i := slices.Index(runner.args, "-map_chapters"); i < 0 || runner.args[i+1] != "1" {
+		t.Errorf("args %q do not map the chapters", runner.args)
+	}
+	if _, err := os.Stat(runner.args[3]); !os.IsNotExist(err) {
+		t.Errorf("the chapters file %s should be removed after the trim", runner.args[3])
+	}
+}
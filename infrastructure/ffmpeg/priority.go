@@ -0,0 +1,47 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Priority is how ffmpeg competes with the rest of the machine
+type Priority string
+
+// Priorities
+const (
+	PriorityNormal Priority = "normal"
+	// PriorityLow runs ffmpeg at nice 10 with idle disk priority on Linux, or
+	// in the below-normal priority class on Windows, so the computer stays
+	// usable while a service is processed
+	PriorityLow Priority = "low"
+)
+
+// ParsePriority reads video.ffmpeg_priority; empty is normal
+func ParsePriority(s string) (Priority, error) {
+	switch p := Priority(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return PriorityNormal, nil
+	case PriorityNormal, PriorityLow:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown priority %q: use normal or low", s)
+	}
+}
+
+// start starts cmd at the runner's priority
+func (r *ExecCommandRunner) start(cmd *exec.Cmd) error {
+	if r.Priority == PriorityLow {
+		return startLowPriority(cmd)
+	}
+	return cmd.Start()
+}
+
+// run runs cmd at the runner's priority and waits for it
+func (r *ExecCommandRunner) run(cmd *exec.Cmd) error {
+	if err := r.start(cmd); err != nil {
+		return err
+	}
+	return cmd.Wait()
+}
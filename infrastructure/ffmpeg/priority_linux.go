@@ -0,0 +1,33 @@
+package ffmpeg
+
+import (
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// Lowered priority settings; see setpriority(2) and ioprio_set(2)
+const (
+	lowNice          = 10
+	ioprioWhoProcess = 1
+	ioprioClassIdle  = 3
+	ioprioClassShift = 13
+)
+
+// startLowPriority starts cmd from a thread lowered to nice 10 and the idle
+// IO class, so ffmpeg and every thread it starts inherit the lower priority.
+// Lowering cannot be undone without privileges, so the thread is thrown away
+// afterwards rather than returned to the Go scheduler.
+func startLowPriority(cmd *exec.Cmd) error {
+	started := make(chan error, 1)
+	go func() {
+		// Never unlocked: the thread exits with this goroutine
+		runtime.LockOSThread()
+		// Best effort: ffmpeg still runs when the priority cannot be lowered,
+		// e.g. when this program already runs at a higher nice value
+		_ = syscall.Setpriority(syscall.PRIO_PROCESS, 0, lowNice)
+		_, _, _ = syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, 0, ioprioClassIdle<<ioprioClassShift)
+		started <- cmd.Start()
+	}()
+	return <-started
+}
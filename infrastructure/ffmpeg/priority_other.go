@@ -0,0 +1,11 @@
+//go:build !linux && !windows
+
+package ffmpeg
+
+import "os/exec"
+
+// startLowPriority starts cmd at normal priority: lowering it is only
+// supported on Linux and Windows
+func startLowPriority(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
@@ -0,0 +1,51 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParsePriority(t *testing.T) {
+	if p, err := ParsePriority(""); err != nil || p != PriorityNormal {
+		t.Errorf("ParsePriority(\"\") = %q, %v; want normal", p, err)
+	}
+	if p, err := ParsePriority("Low"); err != nil || p != PriorityLow {
+		t.Errorf("ParsePriority(Low) = %q, %v", p, err)
+	}
+	if _, err := ParsePriority("realtime"); err == nil {
+		t.Error("ParsePriority(realtime) should fail")
+	}
+}
+
+func TestExecCommandRunner_LowPriority(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("reads the nice value from /proc")
+	}
+	niceOf := func(r *ExecCommandRunner) int {
+		t.Helper()
+		out, err := r.Output(context.Background(), "cat", "/proc/self/stat")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// nice is field 19; fields after the command name start at field 3
+		fields := strings.Fields(string(out[bytes.LastIndexByte(out, ')')+1:]))
+		nice, err := strconv.Atoi(fields[19-3])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nice
+	}
+
+	normal := niceOf(&ExecCommandRunner{})
+	low := niceOf(&ExecCommandRunner{Priority: PriorityLow})
+	if low < max(normal, lowNice) {
+		t.Errorf("low priority nice = %d, want at least %d (normal is %d)", low, max(normal, lowNice), normal)
+	}
+	if again := niceOf(&ExecCommandRunner{}); again != normal {
+		t.Errorf("a normal command after a low one has nice %d, want %d", again, normal)
+	}
+}
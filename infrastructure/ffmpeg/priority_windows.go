@@ -0,0 +1,18 @@
+package ffmpeg
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// belowNormalPriorityClass is BELOW_NORMAL_PRIORITY_CLASS from the Windows API
+const belowNormalPriorityClass = 0x00004000
+
+// startLowPriority starts cmd in the below-normal priority class
+func startLowPriority(cmd *exec.Cmd) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= belowNormalPriorityClass
+	return cmd.Start()
+}
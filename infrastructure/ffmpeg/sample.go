@@ -0,0 +1,65 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SampleDuration is the length of the sample service recording
+const SampleDuration = 2 * time.Minute
+
+// SampleGenerator renders a sample service recording from ffmpeg's built-in
+// test pattern and tone, so the demo needs no recording of its own
+type SampleGenerator struct {
+	ffmpegPath string
+	runner     CommandRunner
+}
+
+// SampleOption is a functional option for configuring SampleGenerator
+type SampleOption func(*SampleGenerator)
+
+// WithSampleFFmpegPath sets the path to the ffmpeg binary
+func WithSampleFFmpegPath(path string) SampleOption {
+	return func(g *SampleGenerator) {
+		g.ffmpegPath = path
+	}
+}
+
+// WithSampleCommandRunner sets the command runner (for testing)
+func WithSampleCommandRunner(runner CommandRunner) SampleOption {
+	return func(g *SampleGenerator) {
+		g.runner = runner
+	}
+}
+
+// NewSampleGenerator creates a new SampleGenerator
+func NewSampleGenerator(opts ...SampleOption) *SampleGenerator {
+	g := &SampleGenerator{
+		ffmpegPath: "ffmpeg",
+		runner:     &ExecCommandRunner{},
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Generate writes a SampleDuration-long 720p MP4 with a stereo tone to outputPath
+func (g *SampleGenerator) Generate(ctx context.Context, outputPath string) error {
+	seconds := strconv.Itoa(int(SampleDuration.Seconds()))
+	args := []string{
+		"-f", "lavfi", "-i", "testsrc2=size=1280x720:rate=30:duration=" + seconds,
+		"-f", "lavfi", "-i", "sine=frequency=440:sample_rate=48000:duration=" + seconds,
+		"-c:v", "libx264", "-preset", "ultrafast", "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-ac", "2",
+		"-shortest",
+		"-y",
+		outputPath,
+	}
+	if err := g.runner.Run(ctx, g.ffmpegPath, args...); err != nil {
+		return fmt.Errorf("ffmpeg could not render the sample recording: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type failingRunner struct {
+	recordingRunner
+}
+
+func (r *failingRunner) Run(ctx context.Context, name string, args ...string) error {
+	return errors.New("exit status 1")
+}
+
+func TestSampleGenerator_Generate(t *testing.T) {
+	runner := &recordingRunner{}
+	g := NewSampleGenerator(WithSampleCommandRunner(runner))
+	if err := g.Generate(context.Background(), "/demo/sample.mp4"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	got := strings.Join(runner.args, " ")
+	for _, want := range []string{"testsrc2=size=1280x720:rate=30:duration=120", "sine=frequency=440:sample_rate=48000:duration=120", "-y /demo/sample.mp4"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("args = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSampleGenerator_GenerateFails(t *testing.T) {
+	g := NewSampleGenerator(WithSampleCommandRunner(&failingRunner{}))
+	err := g.Generate(context.Background(), "/demo/sample.mp4")
+	if err == nil || !strings.Contains(err.Error(), "sample recording") {
+		t.Errorf("Generate() error = %v, want the render failure", err)
+	}
+}
@@ -0,0 +1,152 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/detection"
+)
+
+// Silence detection defaults
+const (
+	DefaultSilenceNoiseDB  = -40
+	DefaultSilenceMinimum  = 30 * time.Second
+	DefaultEndSearchOffset = 20 * time.Minute
+	DefaultEndSearchLength = 90 * time.Minute
+)
+
+// SilenceDetector finds the end of a service as the first long silence after
+// it, using ffmpeg's silencedetect filter. It needs no detection build.
+type SilenceDetector struct {
+	ffmpegPath   string
+	runner       CommandRunner
+	noiseDB      int
+	minimum      time.Duration
+	searchOffset time.Duration
+	searchLength time.Duration
+}
+
+// SilenceOption is a functional option for configuring SilenceDetector
+type SilenceOption func(*SilenceDetector)
+
+// WithSilenceFFmpegPath sets the path to the ffmpeg binary
+func WithSilenceFFmpegPath(path string) SilenceOption {
+	return func(d *SilenceDetector) {
+		d.ffmpegPath = path
+	}
+}
+
+// WithSilenceCommandRunner sets the command runner (for testing)
+func WithSilenceCommandRunner(runner CommandRunner) SilenceOption {
+	return func(d *SilenceDetector) {
+		d.runner = runner
+	}
+}
+
+// WithSilenceThreshold sets the level below which audio counts as silent and
+// how long it must stay there; zero values keep the defaults
+func WithSilenceThreshold(noiseDB int, minimum time.Duration) SilenceOption {
+	return func(d *SilenceDetector) {
+		if noiseDB != 0 {
+			d.noiseDB = noiseDB
+		}
+		if minimum > 0 {
+			d.minimum = minimum
+		}
+	}
+}
+
+// WithEndSearch searches from offset after the service start for length;
+// zero values keep the defaults
+func WithEndSearch(offset, length time.Duration) SilenceOption {
+	return func(d *SilenceDetector) {
+		if offset > 0 {
+			d.searchOffset = offset
+		}
+		if length > 0 {
+			d.searchLength = length
+		}
+	}
+}
+
+// NewSilenceDetector creates a new SilenceDetector
+func NewSilenceDetector(opts ...SilenceOption) *SilenceDetector {
+	d := &SilenceDetector{
+		ffmpegPath:   "ffmpeg",
+		runner:       &ExecCommandRunner{},
+		noiseDB:      DefaultSilenceNoiseDB,
+		minimum:      DefaultSilenceMinimum,
+		searchOffset: DefaultEndSearchOffset,
+		searchLength: DefaultEndSearchLength,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// DetectEnd implements detection.EndDetector
+func (d *SilenceDetector) DetectEnd(ctx context.Context, videoPath string, serviceStartSeconds int) (detection.EndDetectionResult, error) {
+	from := serviceStartSeconds + int(d.searchOffset.Seconds())
+	r := detection.SearchRange{StartSeconds: from, EndSeconds: from + int(d.searchLength.Seconds())}
+
+	// silencedetect logs to stderr; ametadata prints the same markers to stdout
+	filter := fmt.Sprintf("silencedetect=noise=%ddB:d=%s,ametadata=mode=print:file=-",
+		d.noiseDB, strconv.FormatFloat(d.minimum.Seconds(), 'f', -1, 64))
+	out, err := d.runner.Output(ctx, d.ffmpegPath,
+		"-hide_banner", "-nostats",
+		"-ss", strconv.Itoa(r.StartSeconds),
+		"-t", strconv.Itoa(r.EndSeconds-r.StartSeconds),
+		"-i", videoPath,
+		"-vn", "-af", filter,
+		"-f", "null", "-",
+	)
+	if err != nil {
+		return detection.EndDetectionResult{}, fmt.Errorf("ffmpeg silence detection failed: %w", err)
+	}
+
+	end, err := detection.EndFromSilences(parseSilences(out, r.StartSeconds), r, d.minimum.Seconds())
+	if errors.Is(err, detection.ErrEndNotFound) {
+		return detection.EndDetectionResult{Error: err.Error()}, nil
+	}
+	if err != nil {
+		return detection.EndDetectionResult{}, err
+	}
+	return detection.EndDetectionResult{Timestamp: end, Detected: true}, nil
+}
+
+// parseSilences reads the silence markers ametadata printed. Times are
+// relative to the seek point, so offset is added back.
+func parseSilences(out []byte, offset int) []detection.Silence {
+	var silences []detection.Silence
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		seconds += float64(offset)
+		switch key {
+		case "lavfi.silence_start":
+			silences = append(silences, detection.Silence{Start: seconds})
+		case "lavfi.silence_end":
+			if n := len(silences); n > 0 && silences[n-1].End == 0 {
+				silences[n-1].End = seconds
+			}
+		}
+	}
+	return silences
+}
+
+// Ensure SilenceDetector implements detection.EndDetector
+var _ detection.EndDetector = (*SilenceDetector)(nil)
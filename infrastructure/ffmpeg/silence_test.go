@@ -0,0 +1,80 @@
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// outputRunner returns canned stdout from Output
+type outputRunner struct {
+	recordingRunner
+	out []byte
+	err error
+}
+
+func (r *outputRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	r.args = args
+	return r.out, r.err
+}
+
+const silenceOutput = `frame:512    pts:245760  pts_time:5.12
+lavfi.silence_start=12.3
+frame:1100   pts:528000  pts_time:11
+lavfi.silence_end=19.5
+lavfi.silence_duration=7.2
+frame:2310   pts:1108800 pts_time:23.1
+lavfi.silence_start=4517.25
+frame:9000   pts:4320000 pts_time:4560
+lavfi.silence_end=4560
+lavfi.silence_duration=42.75
+`
+
+func TestSilenceDetector_DetectEnd(t *testing.T) {
+	runner := &outputRunner{out: []byte(silenceOutput)}
+	d := NewSilenceDetector(WithSilenceCommandRunner(runner), WithSilenceThreshold(-35, 30*time.Second))
+
+	result, err := d.DetectEnd(context.Background(), "/videos/service.mp4", 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The search starts 20 minutes after the service start at 5:00
+	if !result.Detected || result.Timestamp.String() != "01:40:18" {
+		t.Errorf("result = %+v, want the end at 01:40:18", result)
+	}
+
+	got := strings.Join(runner.args, " ")
+	for _, want := range []string{"-ss 1500 -t 5400 -i /videos/service.mp4", "silencedetect=noise=-35dB:d=30,ametadata=mode=print:file=-", "-f null -"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("args = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSilenceDetector_NoLongSilence(t *testing.T) {
+	runner := &outputRunner{out: []byte("lavfi.silence_start=12.3\nlavfi.silence_end=19.5\n")}
+	result, err := NewSilenceDetector(WithSilenceCommandRunner(runner)).DetectEnd(context.Background(), "service.mp4", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Detected || !strings.Contains(result.Error, "no silence of 30s") {
+		t.Errorf("result = %+v, want not detected", result)
+	}
+}
+
+func TestSilenceDetector_FFmpegFails(t *testing.T) {
+	runner := &outputRunner{err: errors.New("exit status 1")}
+	_, err := NewSilenceDetector(WithSilenceCommandRunner(runner)).DetectEnd(context.Background(), "service.mp4", 0)
+	if err == nil || !strings.Contains(err.Error(), "silence detection failed") {
+		t.Errorf("DetectEnd() error = %v", err)
+	}
+}
+
+func TestParseSilences_OpenSilence(t *testing.T) {
+	silences := parseSilences([]byte("lavfi.silence_start=30\n"), 600)
+	if len(silences) != 1 || silences[0].Start != 630 || silences[0].End != 0 {
+		t.Errorf("silences = %+v, want one open silence from 630s", silences)
+	}
+}
@@ -1,8 +1,10 @@
 package ffmpeg
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 
@@ -16,25 +18,49 @@ type CommandRunner interface {
 	Output(ctx context.Context, name string, args ...string) ([]byte, error)
 }
 
+// StreamRunner is a CommandRunner that can also send a command's stdout to a
+// writer, for output that is consumed while the command runs
+type StreamRunner interface {
+	RunWithStdout(ctx context.Context, stdout io.Writer, name string, args ...string) error
+}
+
 // ExecCommandRunner is the production implementation using os/exec
-type ExecCommandRunner struct{}
+type ExecCommandRunner struct {
+	// Priority is what commands are started at (default normal)
+	Priority Priority
+}
 
 // Run executes a command and returns any error
 func (r *ExecCommandRunner) Run(ctx context.Context, name string, args ...string) error {
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return r.run(cmd)
 }
 
 // Output executes a command and returns its output
 func (r *ExecCommandRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
 	cmd := exec.CommandContext(ctx, name, args...)
-	return cmd.Output()
+	if r.Priority != PriorityLow {
+		return cmd.Output()
+	}
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := r.run(cmd)
+	return stdout.Bytes(), err
+}
+
+// RunWithStdout executes a command, writing its stdout to the given writer
+func (r *ExecCommandRunner) RunWithStdout(ctx context.Context, stdout io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	return r.run(cmd)
 }
 
 // Trimmer implements video.Trimmer using ffmpeg
 type Trimmer struct {
 	ffmpegPath string
+	minVersion string
 	runner     CommandRunner
 }
 
@@ -48,6 +74,13 @@ func WithFFmpegPath(path string) TrimmerOption {
 	}
 }
 
+// WithMinVersion makes VerifyInstalled reject an ffmpeg older than min
+func WithMinVersion(min string) TrimmerOption {
+	return func(t *Trimmer) {
+		t.minVersion = min
+	}
+}
+
 // WithCommandRunner sets a custom command runner (for testing)
 func WithCommandRunner(runner CommandRunner) TrimmerOption {
 	return func(t *Trimmer) {
@@ -71,14 +104,34 @@ func NewTrimmer(opts ...TrimmerOption) *Trimmer {
 
 // Trim implements video.Trimmer
 func (t *Trimmer) Trim(ctx context.Context, req *video.TrimRequest, outputPath string) error {
-	args := []string{
-		"-i", req.SourcePath,
+	args := []string{"-i", req.SourcePath}
+	if len(req.Chapters) > 0 {
+		chapters, err := writeChapterMetadata(req.Chapters, req.End)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(chapters)
+		args = append(args, "-i", chapters, "-map_chapters", "1")
+	}
+	args = append(args,
 		"-ss", req.Start.String(),
 		"-to", req.End.String(),
-		"-c", "copy",
+	)
+	// Keep the video and only the selected audio track
+	if m := video.AudioTrackMap(req.AudioTrack); m != "" {
+		args = append(args, "-map", "0:v:0", "-map", m)
+	}
+	args = append(args, metadataArgs(req.Tags)...)
+	if req.Watermark.IsZero() {
+		args = append(args, "-c", "copy")
+	} else {
+		args = append(args, "-vf", drawtextFilter(req.Watermark))
+		args = append(args, watermarkEncodeArgs...)
+	}
+	args = append(args,
 		"-y", // Overwrite output file if it exists
 		outputPath,
-	}
+	)
 
 	if err := t.runner.Run(ctx, t.ffmpegPath, args...); err != nil {
 		return fmt.Errorf("ffmpeg trim failed: %w", err)
@@ -87,13 +140,20 @@ func (t *Trimmer) Trim(ctx context.Context, req *video.TrimRequest, outputPath s
 	return nil
 }
 
-// VerifyInstalled checks that ffmpeg is available
+// VerifyInstalled checks that ffmpeg is available and new enough
 func (t *Trimmer) VerifyInstalled(ctx context.Context) error {
-	_, err := t.runner.Output(ctx, t.ffmpegPath, "-version")
+	_, err := t.Version(ctx)
+	return err
+}
+
+// Version returns the installed ffmpeg version, or ErrFFmpegTooOld when it is
+// older than the minimum
+func (t *Trimmer) Version(ctx context.Context) (Version, error) {
+	v, err := DetectVersion(ctx, t.runner, t.ffmpegPath)
 	if err != nil {
-		return fmt.Errorf("ffmpeg not found or not executable: %w", err)
+		return Version{}, err
 	}
-	return nil
+	return v, RequireVersion(v, t.minVersion)
 }
 
 // Ensure Trimmer implements video.Trimmer
@@ -0,0 +1,139 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/video"
+)
+
+// Validator implements video.MediaValidator using ffprobe
+type Validator struct {
+	ffprobePath string
+	runner      CommandRunner
+}
+
+// ValidatorOption is a functional option for configuring Validator
+type ValidatorOption func(*Validator)
+
+// WithFFprobePath sets a custom ffprobe executable path
+func WithFFprobePath(path string) ValidatorOption {
+	return func(v *Validator) {
+		v.ffprobePath = path
+	}
+}
+
+// WithValidatorCommandRunner sets a custom command runner (for testing)
+func WithValidatorCommandRunner(runner CommandRunner) ValidatorOption {
+	return func(v *Validator) {
+		v.runner = runner
+	}
+}
+
+// NewValidator creates a new ffprobe-based media validator
+func NewValidator(opts ...ValidatorOption) *Validator {
+	v := &Validator{
+		ffprobePath: "ffprobe",
+		runner:      &ExecCommandRunner{},
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Validate implements video.MediaValidator. A file is valid when ffprobe can
+// read its container and reports a positive duration.
+func (v *Validator) Validate(ctx context.Context, path string) error {
+	_, err := v.Duration(ctx, path)
+	return err
+}
+
+// Duration implements video.DurationProber using the container's duration
+func (v *Validator) Duration(ctx context.Context, path string) (time.Duration, error) {
+	out, err := v.runner.Output(ctx, v.ffprobePath,
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		path,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe could not read %s: %w", path, err)
+	}
+
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+	if err != nil || seconds <= 0 {
+		return 0, fmt.Errorf("%s has no readable duration", path)
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// probeStreams is the part of ffprobe's JSON output read by Geometry
+type probeStreams struct {
+	Streams []struct {
+		Width             int    `json:"width"`
+		Height            int    `json:"height"`
+		SampleAspectRatio string `json:"sample_aspect_ratio"`
+		Tags              struct {
+			Rotate string `json:"rotate"`
+		} `json:"tags"`
+		SideDataList []struct {
+			Rotation float64 `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+}
+
+// Geometry implements video.GeometryProber using the first video stream.
+// Rotation is read from the display matrix, or the older rotate tag.
+func (v *Validator) Geometry(ctx context.Context, path string) (video.Geometry, error) {
+	out, err := v.runner.Output(ctx, v.ffprobePath,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height,sample_aspect_ratio:stream_tags=rotate:stream_side_data=rotation",
+		"-of", "json",
+		path,
+	)
+	if err != nil {
+		return video.Geometry{}, fmt.Errorf("ffprobe could not read %s: %w", path, err)
+	}
+
+	var probe probeStreams
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return video.Geometry{}, fmt.Errorf("unexpected ffprobe output for %s: %w", path, err)
+	}
+	if len(probe.Streams) == 0 || probe.Streams[0].Width <= 0 || probe.Streams[0].Height <= 0 {
+		return video.Geometry{}, fmt.Errorf("%s has no video stream", path)
+	}
+
+	stream := probe.Streams[0]
+	g := video.Geometry{Width: stream.Width, Height: stream.Height}
+	if num, den, ok := strings.Cut(stream.SampleAspectRatio, ":"); ok {
+		g.SARNum, _ = strconv.Atoi(num)
+		g.SARDen, _ = strconv.Atoi(den)
+	}
+	for _, sd := range stream.SideDataList {
+		if sd.Rotation != 0 {
+			// The display matrix turns counterclockwise; the rotate tag clockwise
+			g.Rotation = int(-sd.Rotation)
+		}
+	}
+	if g.Rotation == 0 && stream.Tags.Rotate != "" {
+		g.Rotation, _ = strconv.Atoi(stream.Tags.Rotate)
+	}
+	return g, nil
+}
+
+// Ensure Validator implements video.MediaValidator, video.DurationProber and
+// video.GeometryProber
+var (
+	_ video.MediaValidator = (*Validator)(nil)
+	_ video.DurationProber = (*Validator)(nil)
+	_ video.GeometryProber = (*Validator)(nil)
+)
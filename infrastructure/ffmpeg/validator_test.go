@@ -0,0 +1,75 @@
+package ffmpeg
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"nac-service-media/domain/video"
+)
+
+// probeRunner returns canned ffprobe output
+type probeRunner struct {
+	recordingRunner
+	output string
+}
+
+func (r *probeRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	r.args = args
+	return []byte(r.output), nil
+}
+
+func TestValidator_Geometry(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   video.Geometry
+	}{
+		{
+			name:   "square pixels",
+			output: `{"streams": [{"width": 1920, "height": 1080, "sample_aspect_ratio": "1:1"}]}`,
+			want:   video.Geometry{Width: 1920, Height: 1080, SARNum: 1, SARDen: 1},
+		},
+		{
+			name:   "unknown pixel aspect",
+			output: `{"streams": [{"width": 720, "height": 480, "sample_aspect_ratio": "0:1"}]}`,
+			want:   video.Geometry{Width: 720, Height: 480, SARNum: 0, SARDen: 1},
+		},
+		{
+			name:   "display matrix rotation",
+			output: `{"streams": [{"width": 1920, "height": 1080, "side_data_list": [{"rotation": -90}]}]}`,
+			want:   video.Geometry{Width: 1920, Height: 1080, Rotation: 90},
+		},
+		{
+			name:   "rotate tag",
+			output: `{"streams": [{"width": 1920, "height": 1080, "tags": {"rotate": "270"}}]}`,
+			want:   video.Geometry{Width: 1920, Height: 1080, Rotation: 270},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &probeRunner{output: tt.output}
+			v := NewValidator(WithValidatorCommandRunner(runner))
+
+			got, err := v.Geometry(context.Background(), "/videos/service.mp4")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Geometry() = %+v, want %+v", got, tt.want)
+			}
+			if !strings.Contains(strings.Join(runner.args, " "), "-select_streams v:0") {
+				t.Errorf("ffprobe args %q do not select the first video stream", runner.args)
+			}
+		})
+	}
+}
+
+func TestValidator_Geometry_NoVideoStream(t *testing.T) {
+	v := NewValidator(WithValidatorCommandRunner(&probeRunner{output: `{"streams": []}`}))
+
+	if _, err := v.Geometry(context.Background(), "/audio/service.mp3"); err == nil {
+		t.Error("expected an error for a file without a video stream")
+	}
+}
@@ -0,0 +1,93 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"nac-service-media/domain/video"
+)
+
+// watermarkMargin is the gap in pixels between the text and the frame edge
+const watermarkMargin = "20"
+
+// watermarkEncodeArgs re-encode the video, which drawing on it requires. The
+// audio is still copied.
+var watermarkEncodeArgs = []string{"-c:v", "libx264", "-preset", "veryfast", "-crf", "20", "-c:a", "copy"}
+
+// drawtextFilter returns the -vf filter that draws w. Text and font paths are
+// escaped for both the option value and the filtergraph, so colons in
+// "John 10:11" or "C:\Windows\Fonts" survive.
+func drawtextFilter(w video.Watermark) string {
+	size := w.FontSize
+	if size <= 0 {
+		size = video.DefaultWatermarkFontSize
+	}
+	x, y := watermarkXY(w.Position)
+
+	opts := []string{"text=" + escapeFilterValue(w.Text), "expansion=none"}
+	if w.FontFile != "" {
+		opts = append(opts, "fontfile="+escapeFilterValue(w.FontFile))
+	}
+	opts = append(opts,
+		"fontsize="+strconv.Itoa(size),
+		"fontcolor=white",
+		"box=1",
+		"boxcolor=black@0.5",
+		"boxborderw=10",
+		"x="+x,
+		"y="+y,
+	)
+	return "drawtext=" + strings.Join(opts, ":")
+}
+
+// watermarkXY returns drawtext position expressions; w/h are the frame and
+// tw/th the text size
+func watermarkXY(position string) (string, string) {
+	left, right, center := watermarkMargin, "w-tw-"+watermarkMargin, "(w-tw)/2"
+	top, bottom := watermarkMargin, "h-th-"+watermarkMargin
+	switch position {
+	case video.WatermarkTopLeft:
+		return left, top
+	case video.WatermarkTopRight:
+		return right, top
+	case video.WatermarkBottomLeft:
+		return left, bottom
+	case video.WatermarkBottomCenter:
+		return center, bottom
+	default:
+		return right, bottom
+	}
+}
+
+// ffmpeg parses a filter option value, then the filtergraph around it, so
+// special characters are escaped once for each
+var (
+	optionEscaper      = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`)
+	filtergraphEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`)
+)
+
+// escapeFilterValue escapes s for use as an option value inside -vf
+func escapeFilterValue(s string) string {
+	return filtergraphEscaper.Replace(optionEscaper.Replace(s))
+}
+
+// PreviewWatermark implements video.WatermarkPreviewer, saving the frame at
+// at with w drawn on it as an image such as a PNG
+func (t *Trimmer) PreviewWatermark(ctx context.Context, sourcePath string, at video.Timestamp, w video.Watermark, outputPath string) error {
+	args := []string{
+		"-ss", at.String(),
+		"-i", sourcePath,
+		"-frames:v", "1",
+		"-vf", drawtextFilter(w),
+		"-y",
+		outputPath,
+	}
+	if err := t.runner.Run(ctx, t.ffmpegPath, args...); err != nil {
+		return fmt.Errorf("ffmpeg watermark preview failed: %w", err)
+	}
+	return nil
+}
+
+var _ video.WatermarkPreviewer = (*Trimmer)(nil)
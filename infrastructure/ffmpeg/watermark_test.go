@@ -0,0 +1,121 @@
+package ffmpeg
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"nac-service-media/domain/video"
+)
+
+func TestEscapeFilterValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "December 28, 2025", want: `December 28\, 2025`},
+		{in: "John 10:11-16", want: `John 10\\:11-16`},
+		{in: "Pastor's [Guest]", want: `Pastor\\\'s \[Guest\]`},
+		{in: `C:\Windows\Fonts\arial.ttf`, want: `C\\:\\\\Windows\\\\Fonts\\\\arial.ttf`},
+	}
+	for _, tt := range tests {
+		if got := escapeFilterValue(tt.in); got != tt.want {
+			t.Errorf("escapeFilterValue(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDrawtextFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		w    video.Watermark
+		want []string
+	}{
+		{
+			name: "defaults to bottom-right at 36px",
+			w:    video.Watermark{Text: "December 28, 2025"},
+			want: []string{`text=December 28\, 2025`, "fontsize=36", "x=w-tw-20", "y=h-th-20"},
+		},
+		{
+			name: "top-left with a font",
+			w:    video.Watermark{Text: "Service", Position: video.WatermarkTopLeft, FontFile: "/fonts/DejaVuSans.ttf", FontSize: 48},
+			want: []string{"fontfile=/fonts/DejaVuSans.ttf", "fontsize=48", "x=20", "y=20"},
+		},
+		{
+			name: "bottom-center",
+			w:    video.Watermark{Text: "Service", Position: video.WatermarkBottomCenter},
+			want: []string{"x=(w-tw)/2", "y=h-th-20"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := drawtextFilter(tt.w)
+			if !strings.HasPrefix(got, "drawtext=") {
+				t.Fatalf("filter %q is not a drawtext filter", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("filter %q does not contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTrimmer_Watermark(t *testing.T) {
+	start, _ := video.ParseTimestamp("00:05:30")
+	end, _ := video.ParseTimestamp("01:45:00")
+	runner := &recordingRunner{}
+	trimmer := NewTrimmer(WithCommandRunner(runner))
+	req := &video.TrimRequest{SourcePath: "src.mp4", Start: start, End: end,
+		Watermark: video.Watermark{Text: "December 28, 2025"}}
+
+	if err := trimmer.Trim(context.Background(), req, "out.mp4"); err != nil {
+		t.Fatalf("Trim() error = %v", err)
+	}
+	if i := slices.Index(runner.args, "-vf"); i < 0 || !strings.HasPrefix(runner.args[i+1], "drawtext=") {
+		t.Errorf("args %q do not draw the watermark", runner.args)
+	}
+	if i := slices.Index(runner.args, "-c:v"); i < 0 || runner.args[i+1] != "libx264" {
+		t.Errorf("args %q do not re-encode the video", runner.args)
+	}
+	if slices.Contains(runner.args, "-c") {
+		t.Errorf("args %q still stream-copy the video", runner.args)
+	}
+}
+
+func TestTrimmer_NoWatermarkCopiesStreams(t *testing.T) {
+	start, _ := video.ParseTimestamp("00:05:30")
+	end, _ := video.ParseTimestamp("01:45:00")
+	runner := &recordingRunner{}
+	trimmer := NewTrimmer(WithCommandRunner(runner))
+
+	if err := trimmer.Trim(context.Background(), &video.TrimRequest{SourcePath: "src.mp4", Start: start, End: end}, "out.mp4"); err != nil {
+		t.Fatalf("Trim() error = %v", err)
+	}
+	if slices.Contains(runner.args, "-vf") {
+		t.Errorf("args %q draw a watermark that was not asked for", runner.args)
+	}
+	if i := slices.Index(runner.args, "-c"); i < 0 || runner.args[i+1] != "copy" {
+		t.Errorf("args %q do not stream-copy", runner.args)
+	}
+}
+
+func TestTrimmer_PreviewWatermark(t *testing.T) {
+	at, _ := video.ParseTimestamp("00:10:00")
+	runner := &recordingRunner{}
+	trimmer := NewTrimmer(WithCommandRunner(runner))
+
+	err := trimmer.PreviewWatermark(context.Background(), "src.mp4", at, video.Watermark{Text: "Service"}, "preview.png")
+	if err != nil {
+		t.Fatalf("PreviewWatermark() error = %v", err)
+	}
+	want := []string{"-ss", "00:10:00", "-i", "src.mp4", "-frames:v", "1"}
+	if !slices.Equal(runner.args[:len(want)], want) {
+		t.Errorf("args %q should start with %q", runner.args, want)
+	}
+	if runner.args[len(runner.args)-1] != "preview.png" {
+		t.Errorf("args %q do not write preview.png", runner.args)
+	}
+}
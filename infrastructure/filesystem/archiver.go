@@ -0,0 +1,216 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// DefaultArchiveVideoBitrate is the video bitrate archived copies are
+// re-encoded at, enough to follow a service but far smaller than the original
+const DefaultArchiveVideoBitrate = "500k"
+
+// archiveAudioBitrate is the AAC bitrate of re-encoded videos
+const archiveAudioBitrate = "64k"
+
+// CommandRunner runs an external command such as ffmpeg
+type CommandRunner interface {
+	Run(ctx context.Context, name string, args ...string) error
+}
+
+type execRunner struct{}
+
+func (execRunner) Run(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// LocalArchiver implements filesystem.Archiver in a local directory. Videos
+// are re-encoded at a low bitrate with ffmpeg; anything else, such as an MP3
+// that would not shrink much more, is packed into a gzipped tarball.
+type LocalArchiver struct {
+	dir          string
+	videoBitrate string
+	policy       domainfs.ArchivePolicy
+	ffmpegPath   string
+	runner       CommandRunner
+	now          func() time.Time
+}
+
+var _ domainfs.Archiver = (*LocalArchiver)(nil)
+
+// ArchiverOption configures a LocalArchiver
+type ArchiverOption func(*LocalArchiver)
+
+// WithVideoBitrate sets the bitrate videos are re-encoded at
+func WithVideoBitrate(bitrate string) ArchiverOption {
+	return func(a *LocalArchiver) {
+		if bitrate != "" {
+			a.videoBitrate = bitrate
+		}
+	}
+}
+
+// WithArchiveFFmpegPath sets the ffmpeg executable videos are compressed with
+func WithArchiveFFmpegPath(path string) ArchiverOption {
+	return func(a *LocalArchiver) {
+		a.ffmpegPath = path
+	}
+}
+
+// WithArchiveRunner sets the command runner used for ffmpeg (for testing)
+func WithArchiveRunner(runner CommandRunner) ArchiverOption {
+	return func(a *LocalArchiver) {
+		a.runner = runner
+	}
+}
+
+// WithArchiveClock sets the time Prune measures retention against (for testing)
+func WithArchiveClock(now func() time.Time) ArchiverOption {
+	return func(a *LocalArchiver) {
+		a.now = now
+	}
+}
+
+// NewLocalArchiver creates an archiver that keeps copies in dir and prunes
+// them by policy
+func NewLocalArchiver(dir string, policy domainfs.ArchivePolicy, opts ...ArchiverOption) *LocalArchiver {
+	a := &LocalArchiver{
+		dir:          dir,
+		videoBitrate: DefaultArchiveVideoBitrate,
+		policy:       policy,
+		ffmpegPath:   "ffmpeg",
+		runner:       execRunner{},
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Archive writes a compressed copy of path into the archive directory. A
+// failed copy is removed so it is never mistaken for a good one.
+func (a *LocalArchiver) Archive(path string) (string, error) {
+	if err := os.MkdirAll(a.dir, 0755); err != nil {
+		return "", fmt.Errorf("create archive directory: %w", err)
+	}
+
+	var dst string
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".mp4") {
+		dst = filepath.Join(a.dir, filepath.Base(path))
+		err = a.reencode(path, dst)
+	} else {
+		dst = filepath.Join(a.dir, filepath.Base(path)+".tar.gz")
+		err = writeTarball(path, dst)
+	}
+	if err != nil {
+		os.Remove(dst)
+		return "", err
+	}
+	return dst, nil
+}
+
+func (a *LocalArchiver) reencode(src, dst string) error {
+	args := []string{
+		"-i", src,
+		"-c:v", "libx264", "-preset", "veryfast", "-b:v", a.videoBitrate,
+		"-c:a", "aac", "-b:a", archiveAudioBitrate,
+		"-y", dst,
+	}
+	if err := a.runner.Run(context.Background(), a.ffmpegPath, args...); err != nil {
+		return fmt.Errorf("ffmpeg re-encode failed: %w", err)
+	}
+	return nil
+}
+
+// writeTarball packs src alone into a gzipped tarball at dst
+func writeTarball(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", filepath.Base(src), err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", filepath.Base(src), err)
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return fmt.Errorf("tar header for %s: %w", filepath.Base(src), err)
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Base(dst), err)
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("write %s: %w", filepath.Base(dst), cerr)
+		}
+	}()
+
+	gz, err := gzip.NewWriterLevel(out, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(dst), err)
+	}
+	if _, err := io.Copy(tw, in); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(dst), err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(dst), err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(dst), err)
+	}
+	return nil
+}
+
+// Prune deletes archived copies older than the retention window, judged by
+// when each was archived. A missing archive directory has nothing to prune.
+func (a *LocalArchiver) Prune() ([]string, error) {
+	entries, err := os.ReadDir(a.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read archive directory: %w", err)
+	}
+
+	now := a.now()
+	var pruned []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return pruned, fmt.Errorf("stat %s: %w", entry.Name(), err)
+		}
+		if !a.policy.Expired(info.ModTime(), now) {
+			continue
+		}
+		path := filepath.Join(a.dir, entry.Name())
+		if err := os.Remove(path); err != nil {
+			return pruned, fmt.Errorf("delete %s: %w", entry.Name(), err)
+		}
+		pruned = append(pruned, path)
+	}
+	return pruned, nil
+}
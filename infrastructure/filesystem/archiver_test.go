@@ -0,0 +1,169 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// recordingRunner records commands and writes the output file ffmpeg would
+type recordingRunner struct {
+	args []string
+	err  error
+}
+
+func (r *recordingRunner) Run(ctx context.Context, name string, args ...string) error {
+	r.args = args
+	if r.err != nil {
+		return r.err
+	}
+	return os.WriteFile(args[len(args)-1], []byte("small video"), 0644)
+}
+
+func TestLocalArchiver_ReencodesVideo(t *testing.T) {
+	dir := t.TempDir()
+	archiveDir := filepath.Join(dir, "archive")
+	src := filepath.Join(dir, "2025-12-21.mp4")
+	if err := os.WriteFile(src, []byte("full video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := &recordingRunner{}
+	archiver := NewLocalArchiver(archiveDir, domainfs.ArchivePolicy{}, WithArchiveRunner(runner), WithVideoBitrate("300k"))
+
+	got, err := archiver.Archive(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(archiveDir, "2025-12-21.mp4"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if !containsPair(runner.args, "-b:v", "300k") {
+		t.Errorf("expected the configured video bitrate, got %v", runner.args)
+	}
+	if !containsPair(runner.args, "-i", src) {
+		t.Errorf("expected the source as input, got %v", runner.args)
+	}
+}
+
+func TestLocalArchiver_FailedReencodeLeavesNoCopy(t *testing.T) {
+	dir := t.TempDir()
+	archiveDir := filepath.Join(dir, "archive")
+	src := filepath.Join(dir, "2025-12-21.mp4")
+	if err := os.WriteFile(src, []byte("full video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A partial copy from ffmpeg before it failed
+	if err := os.WriteFile(filepath.Join(archiveDir, "2025-12-21.mp4"), []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archiver := NewLocalArchiver(archiveDir, domainfs.ArchivePolicy{}, WithArchiveRunner(&recordingRunner{err: errors.New("exit status 1")}))
+
+	if _, err := archiver.Archive(src); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := os.Stat(filepath.Join(archiveDir, "2025-12-21.mp4")); !os.IsNotExist(err) {
+		t.Error("expected the partial copy to be removed")
+	}
+}
+
+func TestLocalArchiver_PacksAudioIntoTarball(t *testing.T) {
+	dir := t.TempDir()
+	archiveDir := filepath.Join(dir, "archive")
+	src := filepath.Join(dir, "2025-12-21.mp3")
+	if err := os.WriteFile(src, []byte("sermon audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archiver := NewLocalArchiver(archiveDir, domainfs.ArchivePolicy{})
+
+	got, err := archiver.Archive(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(archiveDir, "2025-12-21.mp3.tar.gz"); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	f, err := os.Open(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	header, err := tr.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Name != "2025-12-21.mp3" {
+		t.Errorf("expected entry 2025-12-21.mp3, got %s", header.Name)
+	}
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "sermon audio" {
+		t.Errorf("expected the original audio, got %q", data)
+	}
+}
+
+func TestLocalArchiver_PrunesExpiredCopies(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := filepath.Join(dir, "2025-06-01.mp4")
+	recent := filepath.Join(dir, "2026-02-22.mp3.tar.gz")
+	for path, archivedAt := range map[string]time.Time{
+		old:    now.AddDate(0, 0, -60),
+		recent: now.AddDate(0, 0, -7),
+	} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, archivedAt, archivedAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archiver := NewLocalArchiver(dir, domainfs.ArchivePolicy{KeepWeeks: 4}, WithArchiveClock(func() time.Time { return now }))
+
+	pruned, err := archiver.Prune()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0] != old {
+		t.Errorf("expected only %s pruned, got %v", old, pruned)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("expected the recent copy to be kept: %v", err)
+	}
+}
+
+func TestLocalArchiver_PruneMissingDirectory(t *testing.T) {
+	archiver := NewLocalArchiver(filepath.Join(t.TempDir(), "missing"), domainfs.ArchivePolicy{KeepWeeks: 1})
+
+	pruned, err := archiver.Prune()
+	if err != nil || len(pruned) != 0 {
+		t.Errorf("expected nothing pruned and no error, got %v, %v", pruned, err)
+	}
+}
+
+func containsPair(args []string, flag, value string) bool {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag && args[i+1] == value {
+			return true
+		}
+	}
+	return false
+}
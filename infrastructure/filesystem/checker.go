@@ -1,24 +1,54 @@
 package filesystem
 
 import (
-	"os"
+	"time"
 
+	domainfs "nac-service-media/domain/filesystem"
 	"nac-service-media/domain/video"
 )
 
-// Checker implements video.FileChecker using the os package
-type Checker struct{}
+// Checker implements video.FileChecker, using the os package unless
+// WithCheckerFS sets another file system
+type Checker struct {
+	fs domainfs.FS
+}
+
+// CheckerOption configures a Checker
+type CheckerOption func(*Checker)
+
+// WithCheckerFS sets the file system files are looked up in
+func WithCheckerFS(fsys domainfs.FS) CheckerOption {
+	return func(c *Checker) {
+		c.fs = fsys
+	}
+}
 
 // NewChecker creates a new filesystem checker
-func NewChecker() *Checker {
-	return &Checker{}
+func NewChecker(opts ...CheckerOption) *Checker {
+	c := &Checker{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Exists returns true if the file exists
 func (c *Checker) Exists(path string) bool {
-	_, err := os.Stat(path)
+	_, err := orOS(c.fs).Stat(path)
 	return err == nil
 }
 
-// Ensure Checker implements video.FileChecker
-var _ video.FileChecker = (*Checker)(nil)
+// ModTime returns when the file was last written
+func (c *Checker) ModTime(path string) (time.Time, error) {
+	info, err := orOS(c.fs).Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// Ensure Checker implements video.FileChecker and filesystem.ModTimer
+var (
+	_ video.FileChecker = (*Checker)(nil)
+	_ domainfs.ModTimer = (*Checker)(nil)
+)
@@ -0,0 +1,71 @@
+package filesystem
+
+import (
+	"fmt"
+	"time"
+
+	"nac-service-media/domain/filesystem"
+)
+
+// Defaults for GrowthDetector
+const (
+	DefaultGrowthSampleInterval = 2 * time.Second
+	DefaultGrowthQuietPeriod    = 10 * time.Second
+)
+
+// GrowthDetector decides whether a file is still being written by sampling its
+// size twice and checking how recently it was modified
+type GrowthDetector struct {
+	interval time.Duration // Between the two samples
+	quiet    time.Duration // Files modified more recently than this are still being written
+	now      func() time.Time
+	sleep    func(time.Duration)
+	fs       filesystem.FS
+}
+
+// GrowthOption configures a GrowthDetector
+type GrowthOption func(*GrowthDetector)
+
+// WithGrowthFS sets the file system sampled files are read from
+func WithGrowthFS(fsys filesystem.FS) GrowthOption {
+	return func(d *GrowthDetector) {
+		d.fs = fsys
+	}
+}
+
+var _ filesystem.GrowthDetector = (*GrowthDetector)(nil)
+
+// NewGrowthDetector creates a detector with the default sample interval and quiet period
+func NewGrowthDetector(opts ...GrowthOption) *GrowthDetector {
+	d := &GrowthDetector{
+		interval: DefaultGrowthSampleInterval,
+		quiet:    DefaultGrowthQuietPeriod,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// IsGrowing returns true if the file was modified within the quiet period or
+// its size changed between two samples
+func (d *GrowthDetector) IsGrowing(path string) (bool, error) {
+	fsys := orOS(d.fs)
+	before, err := fsys.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if d.now().Sub(before.ModTime()) < d.quiet {
+		return true, nil
+	}
+
+	d.sleep(d.interval)
+
+	after, err := fsys.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return after.Size() != before.Size() || !after.ModTime().Equal(before.ModTime()), nil
+}
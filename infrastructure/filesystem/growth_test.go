@@ -0,0 +1,68 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGrowthDetector_IsGrowing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "2025-12-28 10-06-16.mp4")
+	if err := os.WriteFile(path, []byte("frames"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		during func() // Runs between the two samples
+		now    time.Time
+		want   bool
+	}{
+		{name: "finished recording", now: time.Now(), want: false},
+		{name: "recently modified", now: old.Add(time.Second), want: true},
+		{
+			name: "size changes between samples",
+			now:  time.Now(),
+			during: func() {
+				f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+				f.Write([]byte("more frames"))
+				f.Close()
+				os.Chtimes(path, old, old)
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &GrowthDetector{
+				interval: time.Second,
+				quiet:    10 * time.Second,
+				now:      func() time.Time { return tt.now },
+				sleep: func(time.Duration) {
+					if tt.during != nil {
+						tt.during()
+					}
+				},
+			}
+			got, err := d.IsGrowing(path)
+			if err != nil {
+				t.Fatalf("IsGrowing() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsGrowing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrowthDetector_MissingFile(t *testing.T) {
+	if _, err := NewGrowthDetector().IsGrowing(filepath.Join(t.TempDir(), "missing.mp4")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
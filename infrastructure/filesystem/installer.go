@@ -0,0 +1,60 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"nac-service-media/domain/release"
+)
+
+// BinaryInstaller implements release.Installer by writing the new binary next
+// to the executable and renaming it into place
+type BinaryInstaller struct{}
+
+// NewBinaryInstaller creates a new BinaryInstaller
+func NewBinaryInstaller() *BinaryInstaller {
+	return &BinaryInstaller{}
+}
+
+// Install writes binary beside execPath and swaps it in with a rename, so the
+// executable is never left partially written. The previous binary is moved
+// aside first because Windows cannot overwrite a running executable.
+func (i *BinaryInstaller) Install(binary []byte, execPath string) error {
+	dir := filepath.Dir(execPath)
+
+	tmp, err := os.CreateTemp(dir, ".nac-service-media-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		return fmt.Errorf("failed to make update executable: %w", err)
+	}
+
+	oldPath := execPath + ".old"
+	os.Remove(oldPath)
+	if err := os.Rename(execPath, oldPath); err != nil {
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(tmpPath, execPath); err != nil {
+		// Restore the original so the tool keeps working
+		os.Rename(oldPath, execPath)
+		return fmt.Errorf("failed to swap in update: %w", err)
+	}
+	os.Remove(oldPath) // Best effort; may fail on Windows while running
+
+	return nil
+}
+
+// Ensure BinaryInstaller implements the domain interface
+var _ release.Installer = (*BinaryInstaller)(nil)
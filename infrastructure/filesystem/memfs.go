@@ -0,0 +1,314 @@
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// MemFS is an in-memory FS for tests. Directories exist when made with
+// MkdirAll or when a file inside them does; the root always exists.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+	dirs  map[string]bool
+	now   func() time.Time
+}
+
+type memFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// MemFSOption configures a MemFS
+type MemFSOption func(*MemFS)
+
+// WithMemClock sets the clock that stamps file modification times
+func WithMemClock(now func() time.Time) MemFSOption {
+	return func(m *MemFS) {
+		m.now = now
+	}
+}
+
+// NewMemFS creates an empty in-memory file system
+func NewMemFS(opts ...MemFSOption) *MemFS {
+	m := &MemFS{
+		files: make(map[string]*memFile),
+		dirs:  make(map[string]bool),
+		now:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// WriteFile stores data at name, creating its directories
+func (m *MemFS) WriteFile(name string, data []byte) error {
+	name = filepath.Clean(name)
+	if err := m.MkdirAll(filepath.Dir(name)); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.isDir(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: errIsDir}
+	}
+	m.files[name] = &memFile{data: bytes.Clone(data), modTime: m.now()}
+	return nil
+}
+
+// ReadFile returns the contents of the named file
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return bytes.Clone(f.data), nil
+}
+
+// SetModTime changes when the named file was last written
+func (m *MemFS) SetModTime(name string, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrNotExist}
+	}
+	f.modTime = t
+	return nil
+}
+
+// Open opens the named file for reading
+func (m *MemFS) Open(name string) (io.ReadCloser, error) {
+	data, err := m.ReadFile(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Create creates or truncates the named file; its directory must exist
+func (m *MemFS) Create(name string) (io.WriteCloser, error) {
+	name = filepath.Clean(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isDir(filepath.Dir(name)) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if m.isDir(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errIsDir}
+	}
+	m.files[name] = &memFile{modTime: m.now()}
+	return &memWriter{fs: m, name: name}, nil
+}
+
+// Stat describes the named file or directory
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	name = filepath.Clean(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stat(name)
+}
+
+// MkdirAll creates a directory and any missing parents
+func (m *MemFS) MkdirAll(path string) error {
+	path = filepath.Clean(path)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for p := path; !isRoot(p); p = filepath.Dir(p) {
+		if _, ok := m.files[p]; ok {
+			return &fs.PathError{Op: "mkdir", Path: path, Err: errNotDir}
+		}
+	}
+	for p := path; !isRoot(p); p = filepath.Dir(p) {
+		m.dirs[p] = true
+	}
+	return nil
+}
+
+// Remove deletes the named file or empty directory
+func (m *MemFS) Remove(name string) error {
+	name = filepath.Clean(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[name]; ok {
+		delete(m.files, name)
+		return nil
+	}
+	if !m.isDir(name) {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if len(m.children(name)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(m.dirs, name)
+	return nil
+}
+
+// WalkDir calls fn for root and everything below it, in lexical order
+func (m *MemFS) WalkDir(root string, fn fs.WalkDirFunc) error {
+	root = filepath.Clean(root)
+	info, err := m.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = m.walk(root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+func (m *MemFS) walk(path string, entry fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, entry, nil); err != nil || !entry.IsDir() {
+		if errors.Is(err, fs.SkipDir) && entry.IsDir() {
+			return nil
+		}
+		return err
+	}
+	m.mu.Lock()
+	names := m.children(path)
+	m.mu.Unlock()
+	for _, name := range names {
+		child := filepath.Join(path, name)
+		info, err := m.Stat(child)
+		if err != nil {
+			continue // Removed while walking
+		}
+		if err := m.walk(child, fs.FileInfoToDirEntry(info), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stat describes name; the caller holds the lock
+func (m *MemFS) stat(name string) (fs.FileInfo, error) {
+	if f, ok := m.files[name]; ok {
+		return memFileInfo{name: filepath.Base(name), size: int64(len(f.data)), modTime: f.modTime}, nil
+	}
+	if m.isDir(name) {
+		return memFileInfo{name: filepath.Base(name), dir: true}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// isDir reports whether name is a directory; the caller holds the lock
+func (m *MemFS) isDir(name string) bool {
+	if isRoot(name) || m.dirs[name] {
+		return true
+	}
+	return len(m.children(name)) > 0
+}
+
+// children returns the sorted names directly inside dir; the caller holds the lock
+func (m *MemFS) children(dir string) []string {
+	prefix := dir + string(filepath.Separator)
+	if isRoot(dir) && strings.HasSuffix(dir, string(filepath.Separator)) {
+		prefix = dir
+	}
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if dir == "." && !filepath.IsAbs(path) {
+			seen[strings.SplitN(path, string(filepath.Separator), 2)[0]] = true
+			return
+		}
+		if rest, ok := strings.CutPrefix(path, prefix); ok && rest != "" {
+			seen[strings.SplitN(rest, string(filepath.Separator), 2)[0]] = true
+		}
+	}
+	for path := range m.files {
+		add(path)
+	}
+	for path := range m.dirs {
+		add(path)
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isRoot reports whether path is the top of the tree, such as / or .
+func isRoot(path string) bool {
+	return path == "." || filepath.Dir(path) == path
+}
+
+var (
+	errIsDir    = errors.New("is a directory")
+	errNotDir   = errors.New("not a directory")
+	errNotEmpty = errors.New("directory not empty")
+)
+
+// memWriter appends to a MemFS file until it is closed
+type memWriter struct {
+	fs     *MemFS
+	name   string
+	closed bool
+}
+
+func (w *memWriter) Write(p []byte) (int, error) {
+	w.fs.mu.Lock()
+	defer w.fs.mu.Unlock()
+	if w.closed {
+		return 0, fs.ErrClosed
+	}
+	f, ok := w.fs.files[w.name]
+	if !ok {
+		return 0, &fs.PathError{Op: "write", Path: w.name, Err: fs.ErrNotExist}
+	}
+	f.data = append(f.data, p...)
+	f.modTime = w.fs.now()
+	return len(p), nil
+}
+
+func (w *memWriter) Close() error {
+	w.fs.mu.Lock()
+	defer w.fs.mu.Unlock()
+	if w.closed {
+		return fs.ErrClosed
+	}
+	w.closed = true
+	return nil
+}
+
+// memFileInfo describes a MemFS file or directory
+type memFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) ModTime() time.Time { return i.modTime }
+func (i memFileInfo) IsDir() bool        { return i.dir }
+func (i memFileInfo) Sys() any           { return nil }
+
+func (i memFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+// Ensure MemFS implements Opener and the domain FS
+var (
+	_ Opener      = (*MemFS)(nil)
+	_ domainfs.FS = (*MemFS)(nil)
+)
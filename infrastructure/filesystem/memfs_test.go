@@ -0,0 +1,152 @@
+package filesystem
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMemFS_CreateAndOpen(t *testing.T) {
+	written := time.Date(2025, 12, 28, 12, 0, 0, 0, time.UTC)
+	m := NewMemFS(WithMemClock(func() time.Time { return written }))
+	path := filepath.Join("/media", "audio", "2025-12-28.mp3")
+
+	if _, err := m.Create(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Create without a directory: got %v, want ErrNotExist", err)
+	}
+	if err := m.MkdirAll(filepath.Dir(path)); err != nil {
+		t.Fatal(err)
+	}
+	w, err := m.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, "mp3 frames"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, "more"); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("write after close: got %v, want ErrClosed", err)
+	}
+
+	r, err := m.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != "mp3 frames" {
+		t.Errorf("read %q, %v; want the written content", data, err)
+	}
+
+	info, err := m.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 10 || info.IsDir() || !info.ModTime().Equal(written) {
+		t.Errorf("Stat = size %d, dir %v, mod time %v", info.Size(), info.IsDir(), info.ModTime())
+	}
+	if info, err := m.Stat("/media"); err != nil || !info.IsDir() {
+		t.Errorf("Stat of a parent = %v, %v; want a directory", info, err)
+	}
+}
+
+func TestMemFS_MissingFiles(t *testing.T) {
+	m := NewMemFS()
+	if _, err := m.Open("/missing.mp4"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open: got %v, want ErrNotExist", err)
+	}
+	if _, err := m.Stat("/missing.mp4"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat: got %v, want ErrNotExist", err)
+	}
+	if err := m.Remove("/missing.mp4"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove: got %v, want ErrNotExist", err)
+	}
+}
+
+func TestMemFS_Remove(t *testing.T) {
+	m := NewMemFS()
+	if err := m.WriteFile("/trimmed/2025-12-28.mp4", []byte("video")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Remove("/trimmed"); err == nil {
+		t.Error("expected an error removing a directory that is not empty")
+	}
+	if err := m.Remove("/trimmed/2025-12-28.mp4"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Stat("/trimmed/2025-12-28.mp4"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("removed file still exists: %v", err)
+	}
+	if err := m.Remove("/trimmed"); err != nil {
+		t.Errorf("removing the empty directory: %v", err)
+	}
+}
+
+func TestMemFS_MkdirAllOverFile(t *testing.T) {
+	m := NewMemFS()
+	if err := m.WriteFile("/media/audio", []byte("not a directory")); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.MkdirAll("/media/audio/extra"); err == nil {
+		t.Error("expected an error making a directory below a file")
+	}
+}
+
+func TestMemFS_WalkDir(t *testing.T) {
+	m := NewMemFS()
+	for _, name := range []string{"/media/b.mp3", "/media/a/2.mp4", "/media/a/1.mp4", "/media/a-b.mp4", "/other/c.mp3"} {
+		if err := m.WriteFile(name, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.MkdirAll("/media/empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	err := m.WalkDir("/media", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		if d.IsDir() && d.Name() == "a" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/media", "/media/a", "/media/a-b.mp4", "/media/b.mp3", "/media/empty"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("visited %v, want %v", visited, want)
+	}
+
+	err = m.WalkDir("/missing", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("walking a missing root: got %v, want ErrNotExist", err)
+	}
+}
+
+func TestChecker_WithCheckerFS(t *testing.T) {
+	m := NewMemFS()
+	if err := m.WriteFile("/trimmed/2025-12-28.mp4", []byte("video")); err != nil {
+		t.Fatal(err)
+	}
+	c := NewChecker(WithCheckerFS(m))
+	if !c.Exists("/trimmed/2025-12-28.mp4") {
+		t.Error("expected the in-memory file to exist")
+	}
+	if c.Exists("/trimmed/2026-01-04.mp4") {
+		t.Error("expected a missing file not to exist")
+	}
+}
@@ -0,0 +1,97 @@
+package filesystem
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// Opener opens, creates and removes files. Clients that read uploads or write
+// downloads and tokens take one, so tests can keep those files in memory.
+type Opener interface {
+	Open(name string) (io.ReadCloser, error)
+	Create(name string) (io.WriteCloser, error)
+	Remove(name string) error
+}
+
+// OSFS implements Opener and the domain FS using the os package
+type OSFS struct{}
+
+// Open opens the named file for reading
+func (OSFS) Open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+// Create creates or truncates the named file
+func (OSFS) Create(name string) (io.WriteCloser, error) {
+	return os.Create(name)
+}
+
+// Stat describes the named file or directory
+func (OSFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// MkdirAll creates a directory and any missing parents
+func (OSFS) MkdirAll(path string) error {
+	return os.MkdirAll(path, 0755)
+}
+
+// Remove deletes the named file
+func (OSFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// CreatePrivate creates or truncates the named file so only its owner can
+// read it, creating its directory the same way
+func (OSFS) CreatePrivate(name string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, PrivateFileMode)
+	if err != nil {
+		return nil, err
+	}
+	// An existing file keeps its mode through OpenFile
+	if err := f.Chmod(PrivateFileMode); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// WalkDir walks the tree at root
+func (OSFS) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, fn)
+}
+
+// PrivateFileMode is the mode of files holding secrets such as OAuth tokens
+const PrivateFileMode fs.FileMode = 0600
+
+// CreatePrivate creates a file holding a secret, readable only by its owner
+// when the opener supports it and with Create otherwise
+func CreatePrivate(o Opener, name string) (io.WriteCloser, error) {
+	if p, ok := o.(interface {
+		CreatePrivate(name string) (io.WriteCloser, error)
+	}); ok {
+		return p.CreatePrivate(name)
+	}
+	return o.Create(name)
+}
+
+// orOS returns fsys, or the real file system when it is nil
+func orOS(fsys domainfs.FS) domainfs.FS {
+	if fsys == nil {
+		return OSFS{}
+	}
+	return fsys
+}
+
+// Ensure OSFS implements Opener and the domain FS
+var (
+	_ Opener      = OSFS{}
+	_ domainfs.FS = OSFS{}
+)
@@ -0,0 +1,58 @@
+package filesystem
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCreatePrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows does not use Unix file modes")
+	}
+	path := filepath.Join(t.TempDir(), "tokens", "gmail_token.json")
+
+	w, err := CreatePrivate(OSFS{}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "{}")
+	w.Close()
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != PrivateFileMode {
+		t.Fatalf("new token: %v, %v; want mode 0600", info, err)
+	}
+	if info, _ := os.Stat(filepath.Dir(path)); info.Mode().Perm() != 0700 {
+		t.Errorf("token directory mode = %04o, want 0700", info.Mode().Perm())
+	}
+
+	// A token written before tokens were private is tightened on save
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err = CreatePrivate(OSFS{}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if info, _ := os.Stat(path); info.Mode().Perm() != PrivateFileMode {
+		t.Errorf("rewritten token mode = %04o, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestCreatePrivate_FallsBackToCreate(t *testing.T) {
+	m := NewMemFS()
+	if err := m.MkdirAll("/tokens"); err != nil {
+		t.Fatal(err)
+	}
+	w, err := CreatePrivate(m, "/tokens/drive_token.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "{}")
+	w.Close()
+	if data, err := m.ReadFile("/tokens/drive_token.json"); err != nil || string(data) != "{}" {
+		t.Errorf("read %q, %v; want the token", data, err)
+	}
+}
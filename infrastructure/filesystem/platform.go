@@ -0,0 +1,32 @@
+package filesystem
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+// DetectPlatform reports whether the tool runs on Windows, under WSL, or on
+// another Unix, which decides how configured paths are converted
+func DetectPlatform() domainfs.Platform {
+	return detectPlatform(runtime.GOOS, os.Getenv, os.ReadFile)
+}
+
+func detectPlatform(goos string, getenv func(string) string, readFile func(string) ([]byte, error)) domainfs.Platform {
+	switch goos {
+	case "windows":
+		return domainfs.PlatformWindows
+	case "linux":
+		if getenv("WSL_DISTRO_NAME") != "" || getenv("WSL_INTEROP") != "" {
+			return domainfs.PlatformWSL
+		}
+		// WSL kernels name Microsoft in their release string
+		if release, err := readFile("/proc/sys/kernel/osrelease"); err == nil &&
+			strings.Contains(strings.ToLower(string(release)), "microsoft") {
+			return domainfs.PlatformWSL
+		}
+	}
+	return domainfs.PlatformUnix
+}
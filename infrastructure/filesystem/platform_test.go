@@ -0,0 +1,42 @@
+package filesystem
+
+import (
+	"errors"
+	"testing"
+
+	domainfs "nac-service-media/domain/filesystem"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	release := func(s string) func(string) ([]byte, error) {
+		return func(string) ([]byte, error) { return []byte(s), nil }
+	}
+	noRelease := func(string) ([]byte, error) { return nil, errors.New("not found") }
+
+	tests := []struct {
+		name     string
+		goos     string
+		getenv   func(string) string
+		readFile func(string) ([]byte, error)
+		want     domainfs.Platform
+	}{
+		{"windows", "windows", noEnv, noRelease, domainfs.PlatformWindows},
+		{"wsl by environment", "linux", func(k string) string {
+			if k == "WSL_DISTRO_NAME" {
+				return "Ubuntu"
+			}
+			return ""
+		}, noRelease, domainfs.PlatformWSL},
+		{"wsl by kernel", "linux", noEnv, release("5.15.167.4-microsoft-standard-WSL2\n"), domainfs.PlatformWSL},
+		{"linux", "linux", noEnv, release("6.8.0-45-generic\n"), domainfs.PlatformUnix},
+		{"macos", "darwin", noEnv, noRelease, domainfs.PlatformUnix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectPlatform(tt.goos, tt.getenv, tt.readFile); got != tt.want {
+				t.Errorf("detectPlatform() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
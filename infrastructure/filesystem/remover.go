@@ -1,22 +1,46 @@
 package filesystem
 
 import (
-	"os"
-
+	"nac-service-media/domain/audit"
 	domainfs "nac-service-media/domain/filesystem"
 )
 
-// Remover implements filesystem.FileRemover using os.Remove
-type Remover struct{}
+// Remover implements filesystem.FileRemover, using os.Remove unless
+// WithRemoverFS sets another file system
+type Remover struct {
+	auditLog audit.Recorder
+	fs       domainfs.FS
+}
+
+// RemoverOption configures a Remover
+type RemoverOption func(*Remover)
+
+// WithAuditLog records every removal
+func WithAuditLog(recorder audit.Recorder) RemoverOption {
+	return func(r *Remover) {
+		r.auditLog = recorder
+	}
+}
+
+// WithRemoverFS sets the file system files are removed from
+func WithRemoverFS(fsys domainfs.FS) RemoverOption {
+	return func(r *Remover) {
+		r.fs = fsys
+	}
+}
 
 // NewRemover creates a new Remover
-func NewRemover() *Remover {
-	return &Remover{}
+func NewRemover(opts ...RemoverOption) *Remover {
+	r := &Remover{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Remove deletes the file at the given path
 func (r *Remover) Remove(path string) error {
-	return os.Remove(path)
+	return audit.Record(r.auditLog, audit.ActionLocalRemove, path, "", orOS(r.fs).Remove(path))
 }
 
 // Ensure Remover implements the domain interface
@@ -0,0 +1,131 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"nac-service-media/domain/release"
+)
+
+// DefaultRepository is the GitHub repository that publishes releases
+const DefaultRepository = "Jonathan-A-White/nac-service-media"
+
+// HTTPDoer abstracts the HTTP client for testing
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client implements release.Source using the GitHub Releases API
+type Client struct {
+	httpClient HTTPDoer
+	baseURL    string
+	repository string
+}
+
+// Option configures the Client
+type Option func(*Client)
+
+// WithHTTPClient sets a custom HTTP client (useful for testing)
+func WithHTTPClient(c HTTPDoer) Option {
+	return func(client *Client) {
+		client.httpClient = c
+	}
+}
+
+// WithBaseURL overrides the GitHub API base URL
+func WithBaseURL(url string) Option {
+	return func(client *Client) {
+		client.baseURL = url
+	}
+}
+
+// NewClient creates a new GitHub releases client for repository ("owner/name")
+func NewClient(repository string, opts ...Option) *Client {
+	if repository == "" {
+		repository = DefaultRepository
+	}
+	c := &Client{
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+		baseURL:    "https://api.github.com",
+		repository: repository,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// githubRelease is the subset of the GitHub release payload we use
+type githubRelease struct {
+	TagName    string `json:"tag_name"`
+	Prerelease bool   `json:"prerelease"`
+	Draft      bool   `json:"draft"`
+	Assets     []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Releases implements release.Source
+func (c *Client) Releases(ctx context.Context) ([]release.Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases", c.baseURL, c.repository)
+	body, err := c.get(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []githubRelease
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	releases := make([]release.Release, 0, len(payload))
+	for _, r := range payload {
+		if r.Draft {
+			continue
+		}
+		rel := release.Release{Version: r.TagName, Prerelease: r.Prerelease}
+		for _, a := range r.Assets {
+			rel.Assets = append(rel.Assets, release.Asset{Name: a.Name, URL: a.BrowserDownloadURL})
+		}
+		releases = append(releases, rel)
+	}
+	return releases, nil
+}
+
+// Download implements release.Source
+func (c *Client) Download(ctx context.Context, url string) ([]byte, error) {
+	return c.get(ctx, url, "application/octet-stream")
+}
+
+// get performs a GET request and returns the response body
+func (c *Client) get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %s failed: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+	}
+	return body, nil
+}
+
+// Ensure Client implements the domain interface
+var _ release.Source = (*Client)(nil)
@@ -2,12 +2,15 @@ package gmail
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
 
 	"nac-service-media/domain/notification"
+	"nac-service-media/infrastructure/filesystem"
 
 	"google.golang.org/api/gmail/v1"
 )
@@ -33,6 +36,15 @@ type Client struct {
 	gmailService GmailService
 	from         notification.Recipient
 	template     notification.EmailTemplate
+	scheduler    *SendScheduler
+	location     *time.Location
+	bccSender    bool
+
+	now       func() time.Time
+	boundary  func() string // Separates the MIME parts
+	messageID func() string // Identifies each email so replies can thread under it
+
+	files filesystem.Opener // Reads and writes the OAuth token; nil uses the os package
 }
 
 // ClientOption is a functional option for configuring Client
@@ -52,12 +64,68 @@ func WithTemplate(tmpl notification.EmailTemplate) ClientOption {
 	}
 }
 
+// WithLocation sets the timezone that decides whether the service was
+// "today's" or "yesterday's" (default: the system timezone)
+func WithLocation(loc *time.Location) ClientOption {
+	return func(c *Client) {
+		c.location = loc
+	}
+}
+
+// WithScheduler throttles sends through the given scheduler
+func WithScheduler(s *SendScheduler) ClientOption {
+	return func(c *Client) {
+		c.scheduler = s
+	}
+}
+
+// WithBCCSender blind-copies each email to the from address, so the operator
+// keeps the exact message sent in their own inbox
+func WithBCCSender(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.bccSender = enabled
+	}
+}
+
+// WithClock sets the time source that decides whether the service was
+// "today's" or "yesterday's" (for testing)
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// WithBoundary sets how MIME boundaries are generated (for testing)
+func WithBoundary(boundary func() string) ClientOption {
+	return func(c *Client) {
+		c.boundary = boundary
+	}
+}
+
+// WithMessageID sets how Message-IDs are generated (for testing)
+func WithMessageID(messageID func() string) ClientOption {
+	return func(c *Client) {
+		c.messageID = messageID
+	}
+}
+
+// WithFileOpener reads and writes the OAuth token through files instead of
+// the os package (for testing)
+func WithFileOpener(files filesystem.Opener) ClientOption {
+	return func(c *Client) {
+		c.files = files
+	}
+}
+
 // NewClient creates a new Gmail client
 func NewClient(from notification.Recipient, opts ...ClientOption) *Client {
 	c := &Client{
 		from:     from,
 		template: notification.DefaultTemplate,
+		now:      time.Now,
+		boundary: randomBoundary,
 	}
+	c.messageID = func() string { return randomMessageID(c.from.Address) }
 
 	for _, opt := range opts {
 		opt(c)
@@ -66,36 +134,43 @@ func NewClient(from notification.Recipient, opts ...ClientOption) *Client {
 	return c
 }
 
-// Send sends an email using the Gmail API
-func (c *Client) Send(req *notification.EmailRequest) error {
+// Send sends an email using the Gmail API; ctx bounds the API call. An empty
+// req.MessageID is set to the Message-ID the email was sent with.
+func (c *Client) Send(ctx context.Context, req *notification.EmailRequest) error {
 	if err := req.Validate(); err != nil {
 		return fmt.Errorf("invalid email request: %w", err)
 	}
+	if req.MessageID == "" {
+		req.MessageID = c.messageID()
+	}
 
 	// Build template data with dynamic greeting and service reference
-	data := notification.TemplateData{
-		Greeting:      notification.FormatGreeting(req.To),
-		ChurchName:    req.ChurchName,
-		DateFormatted: req.ServiceDate.Format("01/02/2006"),
-		ServiceRef:    notification.FormatServiceRef(req.ServiceDate, time.Now()),
-		MinisterName:  req.MinisterName,
-		AudioURL:      req.AudioURL,
-		VideoURL:      req.VideoURL,
-		SenderName:    req.SenderName,
-	}
-
-	// Render templates
-	subject, err := c.template.RenderSubject(data)
-	if err != nil {
-		return fmt.Errorf("failed to render subject: %w", err)
+	now := c.now()
+	if c.location != nil {
+		now = now.In(c.location)
+	}
+	data := notification.NewTemplateData(req, now)
+	tmpl := c.template
+	if req.Template != nil {
+		tmpl = *req.Template
+	}
+
+	// Render templates, preferring a subject rendered from config
+	subject := req.Subject
+	if subject == "" {
+		var err error
+		subject, err = tmpl.RenderSubject(data)
+		if err != nil {
+			return fmt.Errorf("failed to render subject: %w", err)
+		}
 	}
 
-	plainText, err := c.template.RenderPlainText(data)
+	plainText, err := tmpl.RenderPlainText(data)
 	if err != nil {
 		return fmt.Errorf("failed to render plain text: %w", err)
 	}
 
-	htmlBody, err := c.template.RenderHTML(data)
+	htmlBody, err := tmpl.RenderHTML(data)
 	if err != nil {
 		return fmt.Errorf("failed to render HTML: %w", err)
 	}
@@ -108,10 +183,16 @@ func (c *Client) Send(req *notification.EmailRequest) error {
 		Raw: base64.URLEncoding.EncodeToString([]byte(rawMessage)),
 	}
 
-	// Send via Gmail API
-	_, err = c.gmailService.SendMessage(context.Background(), "me", message)
+	// Send via Gmail API, throttled when a scheduler is configured
+	if c.scheduler != nil {
+		_, err = c.scheduler.Do(ctx, func() (*gmail.Message, error) {
+			return c.gmailService.SendMessage(ctx, "me", message)
+		})
+	} else {
+		_, err = c.gmailService.SendMessage(ctx, "me", message)
+	}
 	if err != nil {
-		return fmt.Errorf("%w: %v", notification.ErrSendFailed, err)
+		return fmt.Errorf("%w: %w", notification.ErrSendFailed, err)
 	}
 
 	return nil
@@ -148,26 +229,66 @@ func (c *Client) buildMIMEMessage(req *notification.EmailRequest, subject, plain
 		msg.WriteString(fmt.Sprintf("Cc: %s\r\n", strings.Join(ccAddrs, ", ")))
 	}
 
+	// Gmail drops the Bcc header from delivered copies, so recipients never see it
+	if c.bccSender && !c.addressedToSender(req) {
+		msg.WriteString(fmt.Sprintf("Bcc: %s\r\n", c.from.Address))
+	}
+
 	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	msg.WriteString(fmt.Sprintf("Message-ID: %s\r\n", req.MessageID))
+	if len(req.InReplyTo) > 0 {
+		msg.WriteString(fmt.Sprintf("In-Reply-To: %s\r\n", req.InReplyTo[0]))
+		msg.WriteString(fmt.Sprintf("References: %s\r\n", strings.Join(req.InReplyTo, " ")))
+	}
 	msg.WriteString("MIME-Version: 1.0\r\n")
-	msg.WriteString("Content-Type: multipart/alternative; boundary=\"boundary42\"\r\n\r\n")
+	boundary := c.boundary()
+	msg.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=\"%s\"\r\n\r\n", boundary))
 
 	// Plain text part
-	msg.WriteString("--boundary42\r\n")
+	msg.WriteString("--" + boundary + "\r\n")
 	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
 	msg.WriteString(plainText)
 	msg.WriteString("\r\n\r\n")
 
 	// HTML part
-	msg.WriteString("--boundary42\r\n")
+	msg.WriteString("--" + boundary + "\r\n")
 	msg.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n\r\n")
 	msg.WriteString(htmlBody)
 	msg.WriteString("\r\n\r\n")
 
-	msg.WriteString("--boundary42--\r\n")
+	msg.WriteString("--" + boundary + "--\r\n")
 
 	return msg.String()
 }
 
+// randomBoundary returns a MIME boundary that cannot appear in the body
+func randomBoundary() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "nac-" + hex.EncodeToString(b)
+}
+
+// randomMessageID returns a unique Message-ID in the from address's domain
+func randomMessageID(from string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	domain := "nac-service-media"
+	if _, host, ok := strings.Cut(from, "@"); ok && host != "" {
+		domain = host
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// addressedToSender reports whether the from address already gets a copy as a
+// To or CC recipient, e.g. in sandbox mode
+func (c *Client) addressedToSender(req *notification.EmailRequest) bool {
+	for _, r := range append(append([]notification.Recipient{}, req.To...), req.CC...) {
+		if strings.EqualFold(r.Address, c.from.Address) {
+			return true
+		}
+	}
+	return false
+}
+
 // Ensure Client implements notification.EmailSender
 var _ notification.EmailSender = (*Client)(nil)
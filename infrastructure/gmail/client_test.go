@@ -3,12 +3,16 @@ package gmail
 import (
 	"context"
 	"encoding/base64"
+	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"nac-service-media/domain/notification"
+	"nac-service-media/infrastructure/filesystem"
 
+	"golang.org/x/oauth2"
 	"google.golang.org/api/gmail/v1"
 )
 
@@ -27,6 +31,30 @@ func (m *mockGmailService) SendMessage(ctx context.Context, userID string, messa
 	return &gmail.Message{Id: "test-message-id"}, nil
 }
 
+// blockingGmailService waits for the request context like an unresponsive API
+type blockingGmailService struct{}
+
+func (blockingGmailService) SendMessage(ctx context.Context, userID string, message *gmail.Message) (*gmail.Message, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestClient_Send_HonorsContext(t *testing.T) {
+	from := notification.Recipient{Name: "Jonathan White", Address: "whiteplainsnac@gmail.com"}
+	client := NewClient(from, WithGmailService(blockingGmailService{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := client.Send(ctx, &notification.EmailRequest{
+		To:          []notification.Recipient{{Name: "John Doe", Address: "john@example.com"}},
+		ServiceDate: time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		AudioURL:    "https://drive.google.com/file/d/abc/view",
+	})
+	if !errors.Is(err, notification.ErrSendFailed) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Send() error = %v, want ErrSendFailed wrapping context.DeadlineExceeded", err)
+	}
+}
+
 func TestClient_Send(t *testing.T) {
 	mock := &mockGmailService{}
 	from := notification.Recipient{Name: "Jonathan White", Address: "whiteplainsnac@gmail.com"}
@@ -44,7 +72,7 @@ func TestClient_Send(t *testing.T) {
 		SenderName:   "Jonathan",
 	}
 
-	err := client.Send(req)
+	err := client.Send(context.Background(), req)
 	if err != nil {
 		t.Fatalf("Send() error = %v", err)
 	}
@@ -100,7 +128,7 @@ func TestClient_Send_MultipleRecipients(t *testing.T) {
 		SenderName:   "Jonathan",
 	}
 
-	err := client.Send(req)
+	err := client.Send(context.Background(), req)
 	if err != nil {
 		t.Fatalf("Send() error = %v", err)
 	}
@@ -132,7 +160,7 @@ func TestClient_Send_ValidationError(t *testing.T) {
 		AudioURL:     "https://drive.google.com/file/d/abc/view",
 	}
 
-	err := client.Send(req)
+	err := client.Send(context.Background(), req)
 	if err == nil {
 		t.Fatal("Send() expected error for invalid request, got nil")
 	}
@@ -142,6 +170,195 @@ func TestClient_Send_ValidationError(t *testing.T) {
 }
 
 // decodeBase64URL decodes a base64 URL encoded string
+func TestClient_Send_RequestTemplate(t *testing.T) {
+	mock := &mockGmailService{}
+	from := notification.Recipient{Name: "Jonathan White", Address: "whiteplainsnac@gmail.com"}
+	client := NewClient(from, WithGmailService(mock))
+
+	err := client.Send(context.Background(), &notification.EmailRequest{
+		To:          []notification.Recipient{{Name: "Pr. Smith", Address: "smith@example.com"}},
+		ServiceDate: time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		AudioURL:    "https://drive.google.com/file/d/abc/view",
+		ChurchName:  "White Plains",
+		Template: &notification.EmailTemplate{
+			SubjectFormat: "Ministers: {{.DateFormatted}}",
+			PlainText:     "Dear brother, {{.AudioURL}}",
+			HTML:          "<p>Dear brother</p>",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	rawBytes, err := decodeBase64URL(mock.sentMessages[0].Raw)
+	if err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	raw := string(rawBytes)
+	for _, check := range []string{"Subject: Ministers: 12/28/2025", "Dear brother, https://drive.google.com/file/d/abc/view"} {
+		if !strings.Contains(raw, check) {
+			t.Errorf("message missing %q in:\n%s", check, raw)
+		}
+	}
+	if strings.Contains(raw, "Thanks!") {
+		t.Errorf("message should use the request's template, not the default:\n%s", raw)
+	}
+}
+
 func decodeBase64URL(s string) ([]byte, error) {
 	return base64.URLEncoding.DecodeString(s)
 }
+
+func TestClient_Send_BCCSender(t *testing.T) {
+	from := notification.Recipient{Name: "Jonathan White", Address: "whiteplainsnac@gmail.com"}
+	tests := []struct {
+		name    string
+		enabled bool
+		to      notification.Recipient
+		cc      []notification.Recipient
+		wantBcc bool
+	}{
+		{"enabled", true, notification.Recipient{Name: "John Doe", Address: "john@example.com"}, nil, true},
+		{"disabled", false, notification.Recipient{Name: "John Doe", Address: "john@example.com"}, nil, false},
+		{"sender already a recipient", true, notification.Recipient{Name: "Jonathan", Address: "WhitePlainsNAC@gmail.com"}, nil, false},
+		{"sender already CC'd", true, notification.Recipient{Name: "John Doe", Address: "john@example.com"}, []notification.Recipient{from}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockGmailService{}
+			client := NewClient(from, WithGmailService(mock), WithBCCSender(tt.enabled))
+			err := client.Send(context.Background(), &notification.EmailRequest{
+				To:          []notification.Recipient{tt.to},
+				CC:          tt.cc,
+				ServiceDate: time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+				AudioURL:    "https://drive.google.com/file/d/abc/view",
+			})
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			rawBytes, err := decodeBase64URL(mock.sentMessages[0].Raw)
+			if err != nil {
+				t.Fatalf("failed to decode message: %v", err)
+			}
+			raw := string(rawBytes)
+			headers, _, _ := strings.Cut(raw, "\r\n\r\n")
+
+			if got := strings.Contains(headers, "Bcc: whiteplainsnac@gmail.com\r\n"); got != tt.wantBcc {
+				t.Errorf("Bcc header present = %v, want %v in:\n%s", got, tt.wantBcc, headers)
+			}
+			if strings.Contains(headers, "To: "+from.Address) && tt.wantBcc {
+				t.Errorf("sender should not be a visible recipient:\n%s", headers)
+			}
+			if strings.Count(raw, "Bcc:") > 1 {
+				t.Errorf("expected at most one Bcc header:\n%s", headers)
+			}
+		})
+	}
+}
+
+func TestClient_Send_ClockAndBoundary(t *testing.T) {
+	mock := &mockGmailService{}
+	from := notification.Recipient{Name: "Jonathan White", Address: "whiteplainsnac@gmail.com"}
+	client := NewClient(from, WithGmailService(mock),
+		WithClock(func() time.Time { return time.Date(2025, 12, 29, 9, 0, 0, 0, time.UTC) }),
+		WithBoundary(func() string { return "test-boundary" }))
+
+	err := client.Send(context.Background(), &notification.EmailRequest{
+		To:          []notification.Recipient{{Name: "John Doe", Address: "john@example.com"}},
+		ServiceDate: time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		AudioURL:    "https://drive.google.com/file/d/abc/view",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	rawBytes, err := decodeBase64URL(mock.sentMessages[0].Raw)
+	if err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	raw := string(rawBytes)
+
+	checks := []string{
+		"from yesterday's service",
+		"boundary=\"test-boundary\"",
+		"--test-boundary\r\nContent-Type: text/plain",
+		"--test-boundary\r\nContent-Type: text/html",
+		"--test-boundary--\r\n",
+	}
+	for _, check := range checks {
+		if !strings.Contains(raw, check) {
+			t.Errorf("message missing %q in:\n%s", check, raw)
+		}
+	}
+}
+
+func TestClient_Send_RandomBoundary(t *testing.T) {
+	if a, b := randomBoundary(), randomBoundary(); a == b || len(a) != len("nac-")+32 {
+		t.Errorf("expected distinct 16-byte boundaries, got %q and %q", a, b)
+	}
+}
+
+func TestClient_Send_Threading(t *testing.T) {
+	mock := &mockGmailService{}
+	from := notification.Recipient{Name: "Jonathan White", Address: "whiteplainsnac@gmail.com"}
+	client := NewClient(from, WithGmailService(mock), WithMessageID(func() string { return "<new@gmail.com>" }))
+
+	req := &notification.EmailRequest{
+		To:          []notification.Recipient{{Name: "John Doe", Address: "john@example.com"}},
+		ServiceDate: time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		AudioURL:    "https://drive.google.com/file/d/abc/view",
+		InReplyTo:   []string{"<first@gmail.com>", "<second@gmail.com>"},
+	}
+	if err := client.Send(context.Background(), req); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if req.MessageID != "<new@gmail.com>" {
+		t.Errorf("MessageID = %q, want the one sent", req.MessageID)
+	}
+	rawBytes, err := decodeBase64URL(mock.sentMessages[0].Raw)
+	if err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	raw := string(rawBytes)
+	for _, check := range []string{
+		"Message-ID: <new@gmail.com>\r\n",
+		"In-Reply-To: <first@gmail.com>\r\n",
+		"References: <first@gmail.com> <second@gmail.com>\r\n",
+	} {
+		if !strings.Contains(raw, check) {
+			t.Errorf("message missing %q in:\n%s", check, raw)
+		}
+	}
+
+	if id := randomMessageID("whiteplainsnac@gmail.com"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@gmail.com>") {
+		t.Errorf("randomMessageID() = %q", id)
+	}
+}
+
+func TestGetToken_UsesInjectedClock(t *testing.T) {
+	// The token expired long ago by the wall clock but not by the fake one,
+	// so it is returned without a refresh
+	now := time.Date(2025, 12, 28, 10, 0, 0, 0, time.UTC)
+	store := tokenStore{
+		file:  filepath.Join(t.TempDir(), "token.json"),
+		files: filesystem.OSFS{},
+		now:   func() time.Time { return now },
+	}
+	saved := &oauth2.Token{AccessToken: "saved", RefreshToken: "refresh", Expiry: now.Add(time.Hour)}
+	if err := store.save(saved); err != nil {
+		t.Fatalf("saving token: %v", err)
+	}
+	cfg := &oauth2.Config{ClientID: "id", Endpoint: oauth2.Endpoint{TokenURL: "http://127.0.0.1:0/token"}}
+
+	token, err := getToken(context.Background(), cfg, store, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.AccessToken != "saved" {
+		t.Errorf("got %q, want the saved token", token.AccessToken)
+	}
+
+	store.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if _, err := getToken(context.Background(), cfg, store, true); !errors.Is(err, ErrAuthRequired) {
+		t.Errorf("expected an expired token whose refresh fails to need sign-in, got %v", err)
+	}
+}
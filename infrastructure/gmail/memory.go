@@ -0,0 +1,123 @@
+package gmail
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// SentMessage is an email kept by an Outbox, decoded from its MIME form
+type SentMessage struct {
+	From    string
+	To      string
+	Cc      string
+	Subject string
+
+	MessageID  string
+	InReplyTo  string
+	References string
+
+	Text string // The text/plain part
+	HTML string // The text/html part
+}
+
+// Outbox is a GmailService that keeps messages instead of sending them, so
+// the workflow can run without a Google account
+type Outbox struct {
+	mu       sync.Mutex
+	messages []*gmail.Message
+}
+
+// NewOutbox creates an empty outbox
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+// SendMessage keeps the message
+func (o *Outbox) SendMessage(ctx context.Context, userID string, message *gmail.Message) (*gmail.Message, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, message)
+	return &gmail.Message{Id: fmt.Sprintf("outbox-%d", len(o.messages))}, nil
+}
+
+// Sent decodes the kept messages, oldest first
+func (o *Outbox) Sent() ([]SentMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	sent := make([]SentMessage, 0, len(o.messages))
+	for i, m := range o.messages {
+		msg, err := decodeMessage(m.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode message %d: %w", i+1, err)
+		}
+		sent = append(sent, msg)
+	}
+	return sent, nil
+}
+
+// decodeMessage parses the base64url MIME message built by Client.Send
+func decodeMessage(raw string) (SentMessage, error) {
+	data, err := base64.URLEncoding.DecodeString(raw)
+	if err != nil {
+		return SentMessage{}, err
+	}
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		return SentMessage{}, err
+	}
+	msg := SentMessage{
+		From:    parsed.Header.Get("From"),
+		To:      parsed.Header.Get("To"),
+		Cc:      parsed.Header.Get("Cc"),
+		Subject: parsed.Header.Get("Subject"),
+
+		MessageID:  parsed.Header.Get("Message-ID"),
+		InReplyTo:  parsed.Header.Get("In-Reply-To"),
+		References: parsed.Header.Get("References"),
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		return SentMessage{}, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		body, err := io.ReadAll(parsed.Body)
+		msg.Text = string(body)
+		return msg, err
+	}
+
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			return msg, nil
+		}
+		if err != nil {
+			return SentMessage{}, err
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return SentMessage{}, err
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "text/plain":
+			msg.Text = strings.TrimRight(string(body), "\r\n")
+		case "text/html":
+			msg.HTML = strings.TrimRight(string(body), "\r\n")
+		}
+	}
+}
+
+// Ensure Outbox implements GmailService
+var _ GmailService = (*Outbox)(nil)
@@ -0,0 +1,56 @@
+package gmail
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/notification"
+)
+
+func TestOutbox_Sent(t *testing.T) {
+	outbox := NewOutbox()
+	from := notification.Recipient{Name: "Jonathan White", Address: "whiteplainsnac@gmail.com"}
+	client := NewClient(from, WithGmailService(outbox))
+
+	err := client.Send(context.Background(), &notification.EmailRequest{
+		To:           []notification.Recipient{{Name: "John Doe", Address: "john@example.com"}},
+		CC:           []notification.Recipient{{Name: "Jane Doe", Address: "jane@example.com"}},
+		ServiceDate:  time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		MinisterName: "Pr. Smith",
+		AudioURL:     "https://drive.google.com/file/d/abc/view",
+		ChurchName:   "White Plains",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	sent, err := outbox.Sent()
+	if err != nil {
+		t.Fatalf("Sent() error = %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("Sent() = %d messages, want 1", len(sent))
+	}
+	msg := sent[0]
+	if msg.To != "John Doe <john@example.com>" || msg.Cc != "Jane Doe <jane@example.com>" {
+		t.Errorf("To = %q, Cc = %q", msg.To, msg.Cc)
+	}
+	if !strings.Contains(msg.Subject, "12/28/2025") {
+		t.Errorf("Subject = %q, want the service date", msg.Subject)
+	}
+	if !strings.Contains(msg.HTML, "https://drive.google.com/file/d/abc/view") || !strings.HasPrefix(strings.TrimSpace(msg.HTML), "<") {
+		t.Errorf("HTML part = %q, want the audio link", msg.HTML)
+	}
+	if msg.Text == "" || strings.Contains(msg.Text, "<html") {
+		t.Errorf("Text part = %q, want plain text", msg.Text)
+	}
+}
+
+func TestOutbox_SentEmpty(t *testing.T) {
+	sent, err := NewOutbox().Sent()
+	if err != nil || len(sent) != 0 {
+		t.Errorf("Sent() = %v, %v; want none", sent, err)
+	}
+}
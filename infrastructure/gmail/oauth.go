@@ -3,13 +3,16 @@ package gmail
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"time"
 
 	"nac-service-media/domain/notification"
+	"nac-service-media/infrastructure/filesystem"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -21,13 +24,22 @@ import (
 type OAuthConfig struct {
 	CredentialsFile string // Path to OAuth client credentials JSON
 	TokenFile       string // Path to store/load token
+	// NonInteractive fails with ErrAuthRequired instead of opening a browser
+	// to sign in when there is no valid token
+	NonInteractive bool
 }
 
+// ErrAuthRequired is returned in non-interactive mode when signing in is needed
+var ErrAuthRequired = errors.New("no valid OAuth token; run 'nac-service-media auth status --fix' to sign in")
+
 // NewClientWithOAuth creates a new Gmail client using OAuth 2.0
 func NewClientWithOAuth(ctx context.Context, cfg OAuthConfig, from notification.Recipient, opts ...ClientOption) (*Client, error) {
 	c := &Client{
-		from:     from,
-		template: notification.DefaultTemplate,
+		from:      from,
+		template:  notification.DefaultTemplate,
+		scheduler: DefaultScheduler,
+		now:       time.Now,
+		boundary:  randomBoundary,
 	}
 
 	for _, opt := range opts {
@@ -36,7 +48,7 @@ func NewClientWithOAuth(ctx context.Context, cfg OAuthConfig, from notification.
 
 	// If no custom Gmail service was provided, create one with OAuth
 	if c.gmailService == nil {
-		svc, err := newOAuthGmailService(ctx, cfg)
+		svc, err := newOAuthGmailService(ctx, cfg, tokenStore{files: c.files, now: c.now})
 		if err != nil {
 			return nil, err
 		}
@@ -47,7 +59,7 @@ func NewClientWithOAuth(ctx context.Context, cfg OAuthConfig, from notification.
 }
 
 // newOAuthGmailService creates a Gmail service using OAuth 2.0 user authentication
-func newOAuthGmailService(ctx context.Context, cfg OAuthConfig) (*GoogleGmailService, error) {
+func newOAuthGmailService(ctx context.Context, cfg OAuthConfig, store tokenStore) (*GoogleGmailService, error) {
 	b, err := os.ReadFile(cfg.CredentialsFile)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read OAuth credentials file: %w", err)
@@ -60,7 +72,8 @@ func newOAuthGmailService(ctx context.Context, cfg OAuthConfig) (*GoogleGmailSer
 	}
 
 	// Get or create token
-	token, err := getToken(ctx, config, cfg.TokenFile)
+	store.file = cfg.TokenFile
+	token, err := getToken(ctx, config, store, cfg.NonInteractive)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get OAuth token: %w", err)
 	}
@@ -75,31 +88,47 @@ func newOAuthGmailService(ctx context.Context, cfg OAuthConfig) (*GoogleGmailSer
 	return &GoogleGmailService{service: srv}, nil
 }
 
-// getToken retrieves a token from file or initiates the OAuth flow
-func getToken(ctx context.Context, config *oauth2.Config, tokenFile string) (*oauth2.Token, error) {
+// getToken returns the saved token while it is unexpired, refreshes it when
+// it has expired, and otherwise (unless nonInteractive) initiates the OAuth flow
+func getToken(ctx context.Context, config *oauth2.Config, store tokenStore, nonInteractive bool) (*oauth2.Token, error) {
 	// Try to load existing token
-	token, err := loadToken(tokenFile)
+	token, err := store.load()
 	if err == nil {
-		// Check if token is still valid or can be refreshed
-		tokenSource := config.TokenSource(ctx, token)
-		newToken, err := tokenSource.Token()
-		if err == nil {
-			// Save refreshed token if it changed
-			if newToken.AccessToken != token.AccessToken {
-				saveToken(tokenFile, newToken)
+		if !store.expired(token) {
+			return token, nil
+		}
+		if token.RefreshToken != "" {
+			// Only the refresh token is passed so the refresh does not depend
+			// on the oauth2 package's own clock
+			newToken, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+			if err == nil {
+				store.save(newToken)
+				return newToken, nil
 			}
-			return newToken, nil
 		}
 		// Token refresh failed, need to re-authenticate
 	}
 
 	// No valid token, initiate OAuth flow
-	return getTokenFromWeb(ctx, config, tokenFile)
+	if nonInteractive {
+		return nil, ErrAuthRequired
+	}
+	return getTokenFromWeb(ctx, config, store)
 }
 
-// loadToken loads a token from a file
-func loadToken(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
+// tokenExpiryDelta refreshes a token this long before it actually expires
+const tokenExpiryDelta = time.Minute
+
+// tokenStore loads and saves the OAuth token file
+type tokenStore struct {
+	file  string
+	files filesystem.Opener // nil uses the os package
+	now   func() time.Time  // nil uses time.Now
+}
+
+// load reads the token from the file
+func (s tokenStore) load() (*oauth2.Token, error) {
+	f, err := s.opener().Open(s.file)
 	if err != nil {
 		return nil, err
 	}
@@ -110,9 +139,9 @@ func loadToken(file string) (*oauth2.Token, error) {
 	return token, err
 }
 
-// saveToken saves a token to a file
-func saveToken(file string, token *oauth2.Token) error {
-	f, err := os.Create(file)
+// save writes the token to the file
+func (s tokenStore) save(token *oauth2.Token) error {
+	f, err := filesystem.CreatePrivate(s.opener(), s.file)
 	if err != nil {
 		return err
 	}
@@ -121,8 +150,32 @@ func saveToken(file string, token *oauth2.Token) error {
 	return json.NewEncoder(f).Encode(token)
 }
 
+// expired reports whether the token has no access token or expires within
+// tokenExpiryDelta; a token without an expiry never expires
+func (s tokenStore) expired(token *oauth2.Token) bool {
+	if token.AccessToken == "" {
+		return true
+	}
+	if token.Expiry.IsZero() {
+		return false
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	return !now().Add(tokenExpiryDelta).Before(token.Expiry)
+}
+
+// opener returns files, or the os package when it is nil
+func (s tokenStore) opener() filesystem.Opener {
+	if s.files == nil {
+		return filesystem.OSFS{}
+	}
+	return s.files
+}
+
 // getTokenFromWeb initiates the OAuth flow via browser
-func getTokenFromWeb(ctx context.Context, config *oauth2.Config, tokenFile string) (*oauth2.Token, error) {
+func getTokenFromWeb(ctx context.Context, config *oauth2.Config, store tokenStore) (*oauth2.Token, error) {
 	// Use localhost redirect for installed apps
 	// Use a different port than Drive to avoid conflicts
 	config.RedirectURL = "http://localhost:8086/callback"
@@ -189,7 +242,7 @@ func getTokenFromWeb(ctx context.Context, config *oauth2.Config, tokenFile strin
 	}
 
 	// Save token for future use
-	if err := saveToken(tokenFile, token); err != nil {
+	if err := store.save(token); err != nil {
 		fmt.Printf("Warning: couldn't save token: %v\n", err)
 	}
 
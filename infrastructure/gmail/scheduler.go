@@ -0,0 +1,154 @@
+package gmail
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+
+	"nac-service-media/domain/clock"
+)
+
+// Default send scheduling limits, chosen to stay well under Gmail's per-user quota
+const (
+	DefaultMinSendInterval = 1 * time.Second
+	DefaultMaxSendRetries  = 5
+	initialRetryBackoff    = 2 * time.Second
+	maxRetryBackoff        = 60 * time.Second
+)
+
+// DefaultScheduler is shared by every OAuth-backed client in the process so
+// that all email-producing features draw from the same send budget
+var DefaultScheduler = NewSendScheduler(DefaultMinSendInterval, DefaultMaxSendRetries)
+
+// SendScheduler spaces out Gmail sends by a minimum interval and retries
+// rate-limited sends, honoring the Retry-After header when present
+type SendScheduler struct {
+	mu          sync.Mutex
+	minInterval time.Duration
+	maxRetries  int
+	lastSend    time.Time
+
+	now   func() time.Time
+	sleep clock.Sleeper
+}
+
+// SchedulerOption is a functional option for configuring SendScheduler
+type SchedulerOption func(*SendScheduler)
+
+// WithSchedulerClock sets the scheduler's time source (for testing)
+func WithSchedulerClock(now func() time.Time) SchedulerOption {
+	return func(s *SendScheduler) {
+		s.now = now
+	}
+}
+
+// WithSchedulerSleep sets how the scheduler waits, so spacing and retries
+// can be tested without waiting
+func WithSchedulerSleep(sleep clock.Sleeper) SchedulerOption {
+	return func(s *SendScheduler) {
+		s.sleep = sleep
+	}
+}
+
+// NewSendScheduler creates a scheduler with the given spacing and retry limit
+func NewSendScheduler(minInterval time.Duration, maxRetries int, opts ...SchedulerOption) *SendScheduler {
+	s := &SendScheduler{
+		minInterval: minInterval,
+		maxRetries:  maxRetries,
+		now:         time.Now,
+		sleep:       clock.Sleep,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Do runs send once the minimum interval has elapsed since the previous send,
+// retrying while Gmail reports rate limiting. Sends are serialized.
+func (s *SendScheduler) Do(ctx context.Context, send func() (*gmail.Message, error)) (*gmail.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if !s.lastSend.IsZero() {
+			if wait := s.minInterval - s.now().Sub(s.lastSend); wait > 0 {
+				if err := s.sleep(ctx, wait); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		msg, err := send()
+		s.lastSend = s.now()
+		if err == nil {
+			return msg, nil
+		}
+
+		delay, limited := retryDelay(err, attempt, s.now())
+		if !limited || attempt >= s.maxRetries {
+			return nil, err
+		}
+		if err := s.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay reports whether err is a rate-limit response and how long to
+// wait before retrying. Retry-After wins; otherwise backoff doubles per attempt.
+func retryDelay(err error, attempt int, now time.Time) (time.Duration, bool) {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || !isRateLimited(apiErr) {
+		return 0, false
+	}
+
+	if d, ok := parseRetryAfter(apiErr.Header.Get("Retry-After"), now); ok {
+		return d, true
+	}
+
+	backoff := initialRetryBackoff << attempt
+	if backoff > maxRetryBackoff || backoff <= 0 {
+		backoff = maxRetryBackoff
+	}
+	return backoff, true
+}
+
+// isRateLimited returns true for 429s and 403 rate-limit reasons
+func isRateLimited(err *googleapi.Error) bool {
+	if err.Code == http.StatusTooManyRequests {
+		return true
+	}
+	if err.Code == http.StatusForbidden {
+		for _, item := range err.Errors {
+			if item.Reason == "rateLimitExceeded" || item.Reason == "userRateLimitExceeded" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After value in seconds or HTTP-date form,
+// measuring a date from now
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,143 @@
+package gmail
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// fakeClock records sleeps and advances time instead of blocking
+type fakeClock struct {
+	current time.Time
+	slept   []time.Duration
+}
+
+func (c *fakeClock) now() time.Time { return c.current }
+
+func (c *fakeClock) sleep(ctx context.Context, d time.Duration) error {
+	c.slept = append(c.slept, d)
+	c.current = c.current.Add(d)
+	return nil
+}
+
+func newTestScheduler(minInterval time.Duration, maxRetries int) (*SendScheduler, *fakeClock) {
+	clock := &fakeClock{current: time.Date(2025, 12, 28, 12, 0, 0, 0, time.UTC)}
+	s := NewSendScheduler(minInterval, maxRetries,
+		WithSchedulerClock(clock.now), WithSchedulerSleep(clock.sleep))
+	return s, clock
+}
+
+func rateLimitError(retryAfter string) error {
+	header := http.Header{}
+	if retryAfter != "" {
+		header.Set("Retry-After", retryAfter)
+	}
+	return &googleapi.Error{Code: http.StatusTooManyRequests, Header: header}
+}
+
+func TestSendScheduler_EnforcesMinimumInterval(t *testing.T) {
+	s, clock := newTestScheduler(2*time.Second, 0)
+	send := func() (*gmail.Message, error) { return &gmail.Message{}, nil }
+
+	if _, err := s.Do(context.Background(), send); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	if len(clock.slept) != 0 {
+		t.Errorf("first send should not wait, slept %v", clock.slept)
+	}
+
+	clock.current = clock.current.Add(500 * time.Millisecond)
+	if _, err := s.Do(context.Background(), send); err != nil {
+		t.Fatalf("second send: %v", err)
+	}
+	if len(clock.slept) != 1 || clock.slept[0] != 1500*time.Millisecond {
+		t.Errorf("expected a 1.5s wait before second send, slept %v", clock.slept)
+	}
+}
+
+func TestSendScheduler_RetriesWithRetryAfter(t *testing.T) {
+	s, clock := newTestScheduler(0, 3)
+	calls := 0
+	send := func() (*gmail.Message, error) {
+		calls++
+		if calls == 1 {
+			return nil, rateLimitError("7")
+		}
+		return &gmail.Message{Id: "sent"}, nil
+	}
+
+	msg, err := s.Do(context.Background(), send)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Id != "sent" || calls != 2 {
+		t.Errorf("expected success on second attempt, calls=%d", calls)
+	}
+	if len(clock.slept) != 1 || clock.slept[0] != 7*time.Second {
+		t.Errorf("expected to honor Retry-After of 7s, slept %v", clock.slept)
+	}
+}
+
+func TestSendScheduler_RetryAfterDateUsesSchedulerClock(t *testing.T) {
+	s, clock := newTestScheduler(0, 3)
+	retryAt := clock.current.Add(30 * time.Second).Format(http.TimeFormat)
+	calls := 0
+	send := func() (*gmail.Message, error) {
+		calls++
+		if calls == 1 {
+			return nil, rateLimitError(retryAt)
+		}
+		return &gmail.Message{Id: "sent"}, nil
+	}
+
+	if _, err := s.Do(context.Background(), send); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clock.slept) != 1 || clock.slept[0] != 30*time.Second {
+		t.Errorf("expected to wait until the Retry-After date, slept %v", clock.slept)
+	}
+}
+
+func TestSendScheduler_BacksOffWithoutRetryAfter(t *testing.T) {
+	s, clock := newTestScheduler(0, 2)
+	rateLimited := &googleapi.Error{
+		Code:   http.StatusForbidden,
+		Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}},
+	}
+	send := func() (*gmail.Message, error) { return nil, rateLimited }
+
+	_, err := s.Do(context.Background(), send)
+	if !errors.Is(err, rateLimited) {
+		t.Fatalf("expected rate limit error after retries, got %v", err)
+	}
+	want := []time.Duration{2 * time.Second, 4 * time.Second}
+	if len(clock.slept) != len(want) {
+		t.Fatalf("expected %d backoff sleeps, got %v", len(want), clock.slept)
+	}
+	for i := range want {
+		if clock.slept[i] != want[i] {
+			t.Errorf("backoff %d = %v, want %v", i, clock.slept[i], want[i])
+		}
+	}
+}
+
+func TestSendScheduler_DoesNotRetryOtherErrors(t *testing.T) {
+	s, clock := newTestScheduler(0, 5)
+	calls := 0
+	send := func() (*gmail.Message, error) {
+		calls++
+		return nil, &googleapi.Error{Code: http.StatusBadRequest}
+	}
+
+	if _, err := s.Do(context.Background(), send); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 || len(clock.slept) != 0 {
+		t.Errorf("non-rate-limit errors should not retry, calls=%d slept=%v", calls, clock.slept)
+	}
+}
@@ -0,0 +1,140 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"nac-service-media/domain/history"
+)
+
+// JSONStore keeps history as one JSON object per line, so recording a run is
+// a single append
+type JSONStore struct {
+	path string
+}
+
+var _ history.Store = (*JSONStore)(nil)
+
+// NewJSONStore creates a store backed by the file at path
+func NewJSONStore(path string) *JSONStore {
+	return &JSONStore{path: path}
+}
+
+// Path returns the backing file
+func (s *JSONStore) Path() string {
+	return s.path
+}
+
+// Append adds an entry to the end of the file, creating it if needed
+func (s *JSONStore) Append(e history.Entry) error {
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create history directory: %w", err)
+		}
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+	return nil
+}
+
+// AddNote attaches a note to the latest entry for the service date. The file
+// is rewritten through a temporary file so a failure leaves it intact.
+func (s *JSONStore) AddNote(serviceDate time.Time, note history.Note) error {
+	entries, err := s.List()
+	if err != nil {
+		return err
+	}
+	entries, err = history.AttachNote(entries, serviceDate, note)
+	if err != nil {
+		return err
+	}
+	return s.rewrite(entries)
+}
+
+// AddCorrection records a correction on the latest entry for the service
+// date, rewriting the file as AddNote does
+func (s *JSONStore) AddCorrection(serviceDate time.Time, c history.Correction) error {
+	entries, err := s.List()
+	if err != nil {
+		return err
+	}
+	entries, err = history.AttachCorrection(entries, serviceDate, c)
+	if err != nil {
+		return err
+	}
+	return s.rewrite(entries)
+}
+
+// rewrite replaces the file with entries through a temporary file, so a
+// failure leaves it intact
+func (s *JSONStore) rewrite(entries []history.Entry) error {
+	var buf bytes.Buffer
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace history file: %w", err)
+	}
+	return nil
+}
+
+// List reads all entries. A missing file is an empty history.
+func (s *JSONStore) List() ([]history.Entry, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []history.Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e history.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("invalid history entry on line %d of %s: %w", lineNum, s.path, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	return entries, nil
+}
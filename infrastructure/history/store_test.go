@@ -0,0 +1,124 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/history"
+)
+
+func TestJSONStore_AppendAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "history.jsonl")
+	store := NewJSONStore(path)
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("List() on missing file error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected empty history, got %d entries", len(entries))
+	}
+
+	first := history.Entry{
+		ServiceDate:     time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		Minister:        "Pr. Smith",
+		DurationSeconds: 3600,
+		VideoURL:        "https://drive.google.com/file/d/v/view",
+		Recipients:      []string{"jane@example.com"},
+		Outcome:         history.OutcomeSuccess,
+	}
+	second := history.Entry{
+		ServiceDate: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC),
+		Outcome:     history.OutcomeSuccess,
+	}
+	for _, e := range []history.Entry{first, second} {
+		if err := store.Append(e); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	entries, err = store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if !entries[0].ServiceDate.Equal(first.ServiceDate) || entries[0].Minister != "Pr. Smith" {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if len(entries[0].Recipients) != 1 || entries[0].Recipients[0] != "jane@example.com" {
+		t.Errorf("recipients = %v", entries[0].Recipients)
+	}
+	if !entries[1].ServiceDate.Equal(second.ServiceDate) {
+		t.Errorf("second entry date = %v", entries[1].ServiceDate)
+	}
+}
+
+func TestJSONStore_ListInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if err := os.WriteFile(path, []byte("{\"outcome\":\"success\"}\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewJSONStore(path).List()
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error naming line 2, got %v", err)
+	}
+}
+
+func TestJSONStore_AddNote(t *testing.T) {
+	store := NewJSONStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	serviceDate := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	if err := store.Append(history.Entry{ServiceDate: serviceDate, Outcome: history.OutcomeSuccess}); err != nil {
+		t.Fatal(err)
+	}
+
+	note := history.Note{Text: "organ mic buzzing", AddedAt: serviceDate.Add(time.Hour)}
+	if err := store.AddNote(serviceDate, note); err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || len(entries[0].Notes) != 1 || entries[0].Notes[0].Text != "organ mic buzzing" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if _, err := os.Stat(store.Path() + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+
+	if err := store.AddNote(serviceDate.AddDate(0, 0, 7), note); err == nil {
+		t.Error("expected an error for a date with no entry")
+	}
+}
+
+func TestJSONStore_AddCorrection(t *testing.T) {
+	store := NewJSONStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	serviceDate := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	entry := history.Entry{ServiceDate: serviceDate, Minister: "Pr. Smith", EmailMessageIDs: []string{"<a@example.com>"}, Outcome: history.OutcomeSuccess}
+	if err := store.Append(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	c := history.Correction{Minister: "Pr. Jones", PreviousMinister: "Pr. Smith", MessageID: "<b@example.com>", SentAt: serviceDate.Add(24 * time.Hour)}
+	if err := store.AddCorrection(serviceDate, c); err != nil {
+		t.Fatalf("AddCorrection() error = %v", err)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Minister != "Pr. Jones" || len(entries[0].Corrections) != 1 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if entries[0].EmailMessageIDs[0] != "<a@example.com>" || entries[0].Corrections[0].MessageID != "<b@example.com>" {
+		t.Errorf("message IDs not kept: %+v", entries[0])
+	}
+}
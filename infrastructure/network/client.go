@@ -0,0 +1,184 @@
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/oauth2"
+)
+
+// Settings configures outbound HTTP for the Google APIs
+type Settings struct {
+	// ProxyURL is an explicit proxy; empty uses HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	ProxyURL string
+	// CABundle is a PEM file of extra trusted CAs, e.g. for a TLS-inspecting proxy
+	CABundle string
+	// RateLimit is the most requests a second sent to each host; 0 is no limit
+	RateLimit float64
+	// RequestLog, when set, gets a line for each request
+	RequestLog io.Writer
+}
+
+// ParseProxyURL validates an http, https or socks5 proxy URL
+func ParseProxyURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", s, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", s)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", s)
+	}
+	return u, nil
+}
+
+// NewHTTPClient returns an HTTP client that uses the configured proxy (or the
+// proxy environment variables, read when the client is created) and trusts
+// the CA bundle in addition to the system roots. Its transport is a
+// *Transport, rate limited and logged as the settings say.
+func NewHTTPClient(s Settings) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	envProxy := httpproxy.FromEnvironment().ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return envProxy(req.URL)
+	}
+	if s.ProxyURL != "" {
+		u, err := ParseProxyURL(s.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	if s.CABundle != "" {
+		pool, err := loadCABundle(s.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Transport: NewTransport(transport, WithRequestLog(s.RequestLog), WithHostRateLimit(s.RateLimit))}, nil
+}
+
+// shared holds the clients handed out by SharedHTTPClient
+var shared = struct {
+	sync.Mutex
+	clients map[sharedKey]*http.Client
+}{clients: make(map[sharedKey]*http.Client)}
+
+type sharedKey struct {
+	proxyURL, caBundle string
+	rateLimit          float64
+	requestLog         bool
+}
+
+// SharedHTTPClient returns the client for the settings, creating it on first
+// use, so the Google API clients and token refreshes of a run share one
+// connection pool and rate limit. The request log of the first call is kept.
+func SharedHTTPClient(s Settings) (*http.Client, error) {
+	key := sharedKey{proxyURL: s.ProxyURL, caBundle: s.CABundle, rateLimit: s.RateLimit, requestLog: s.RequestLog != nil}
+	shared.Lock()
+	defer shared.Unlock()
+	if client, ok := shared.clients[key]; ok {
+		return client, nil
+	}
+	client, err := NewHTTPClient(s)
+	if err != nil {
+		return nil, err
+	}
+	shared.clients[key] = client
+	return client, nil
+}
+
+// SharedStats returns the request counts of the shared clients, by host
+func SharedStats() []HostStats {
+	shared.Lock()
+	byHost := map[string]HostStats{}
+	for _, client := range shared.clients {
+		for _, s := range client.Transport.(*Transport).Stats() {
+			total := byHost[s.Host]
+			total.Host = s.Host
+			total.Requests += s.Requests
+			total.Errors += s.Errors
+			total.Elapsed += s.Elapsed
+			total.Waited += s.Waited
+			byHost[s.Host] = total
+		}
+	}
+	shared.Unlock()
+
+	stats := make([]HostStats, 0, len(byHost))
+	for _, s := range byHost {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Host < stats[j].Host })
+	return stats
+}
+
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// Context returns ctx carrying the client. oauth2 token refreshes, and the
+// Google API clients built from oauth2 configs, use it for every request.
+func Context(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
+
+// ProxyFor reports the proxy used to reach target and where it is configured:
+// "network.proxy_url", "environment", or "" when requests go direct
+func ProxyFor(s Settings, target string) (*url.URL, string, error) {
+	if s.ProxyURL != "" {
+		u, err := ParseProxyURL(s.ProxyURL)
+		return u, "network.proxy_url", err
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, "", err
+	}
+	proxy, err := httpproxy.FromEnvironment().ProxyFunc()(u)
+	if err != nil || proxy == nil {
+		return nil, "", err
+	}
+	return proxy, "environment", nil
+}
+
+// Probe sends a GET to target and returns the HTTP status. Any response
+// means the network path works, since the Google endpoints need auth; only
+// proxy, DNS and TLS failures are errors.
+func Probe(ctx context.Context, client *http.Client, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
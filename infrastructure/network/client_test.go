@@ -0,0 +1,131 @@
+package network
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProxyURL(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{input: "http://proxy.church.local:3128"},
+		{input: "socks5://10.0.0.1:1080"},
+		{input: "ftp://proxy:21", wantErr: true},
+		{input: "proxy:3128", wantErr: true},
+		{input: "http://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if _, err := ParseProxyURL(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("ParseProxyURL(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_UsesProxyURL(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	client, err := NewHTTPClient(Settings{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := Probe(context.Background(), client, "http://www.googleapis.test/")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if status != http.StatusNoContent || proxiedHost != "www.googleapis.test" {
+		t.Errorf("status = %d, proxied host = %q; want the request sent through the proxy", status, proxiedHost)
+	}
+}
+
+func TestNewHTTPClient_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	plain, _ := NewHTTPClient(Settings{})
+	if _, err := Probe(context.Background(), plain, server.URL); err == nil {
+		t.Fatal("expected the test server's certificate to be untrusted without the bundle")
+	}
+
+	client, err := NewHTTPClient(Settings{CABundle: bundle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Probe(context.Background(), client, server.URL); err != nil {
+		t.Errorf("Probe() with CA bundle error = %v", err)
+	}
+}
+
+func TestNewHTTPClient_InvalidCABundle(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundle, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewHTTPClient(Settings{CABundle: bundle}); err == nil {
+		t.Error("expected an error for a bundle without certificates")
+	}
+	if _, err := NewHTTPClient(Settings{CABundle: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("expected an error for a missing bundle")
+	}
+}
+
+func TestProxyFor(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	t.Setenv("NO_PROXY", "")
+
+	proxy, source, err := ProxyFor(Settings{ProxyURL: "http://config-proxy:8080"}, "https://www.googleapis.com")
+	if err != nil || proxy.Host != "config-proxy:8080" || source != "network.proxy_url" {
+		t.Errorf("explicit proxy = %v, %q, %v", proxy, source, err)
+	}
+
+	proxy, source, err = ProxyFor(Settings{}, "https://www.googleapis.com")
+	if err != nil || proxy == nil || proxy.Host != "env-proxy:3128" || source != "environment" {
+		t.Errorf("environment proxy = %v, %q, %v", proxy, source, err)
+	}
+
+	t.Setenv("NO_PROXY", "googleapis.com")
+	proxy, source, err = ProxyFor(Settings{}, "https://www.googleapis.com")
+	if err != nil || proxy != nil || source != "" {
+		t.Errorf("NO_PROXY host = %v, %q, %v; want direct", proxy, source, err)
+	}
+}
+
+func TestNewHTTPClient_UsesEnvironmentProxy(t *testing.T) {
+	var proxied bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+	}))
+	defer proxy.Close()
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("NO_PROXY", "")
+
+	client, err := NewHTTPClient(Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Probe(context.Background(), client, "http://www.googleapis.test/"); err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if !proxied {
+		t.Error("expected the request to go through HTTP_PROXY")
+	}
+}
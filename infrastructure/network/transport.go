@@ -0,0 +1,167 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxIdleConnsPerHost keeps enough connections open for the many small Drive
+// metadata calls a run makes (net/http keeps 2)
+const maxIdleConnsPerHost = 16
+
+// Transport is the http.RoundTripper shared by the Google API clients. It
+// spaces out requests to each host when a rate limit is set, logs each
+// request when given a writer, and counts requests per host. It is safe for
+// concurrent use.
+type Transport struct {
+	base     http.RoundTripper
+	log      io.Writer
+	interval time.Duration // Minimum time between requests to a host; 0 is no limit
+	now      func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// HostStats counts the requests sent to one host
+type HostStats struct {
+	Host     string
+	Requests int
+	Errors   int           // Requests that failed without a response
+	Elapsed  time.Duration // Total time waiting for responses
+	Waited   time.Duration // Total time held back by the rate limit
+}
+
+type hostState struct {
+	next  time.Time // Earliest time the next request may start
+	stats HostStats
+}
+
+// TransportOption configures a Transport
+type TransportOption func(*Transport)
+
+// WithRequestLog writes a line for each request: method, host, path, status
+// and time taken. Query strings are left out.
+func WithRequestLog(w io.Writer) TransportOption {
+	return func(t *Transport) {
+		t.log = w
+	}
+}
+
+// WithHostRateLimit allows at most perSecond requests a second to each host;
+// 0 or less is no limit
+func WithHostRateLimit(perSecond float64) TransportOption {
+	return func(t *Transport) {
+		t.interval = 0
+		if perSecond > 0 {
+			t.interval = time.Duration(float64(time.Second) / perSecond)
+		}
+	}
+}
+
+// NewTransport wraps base, or http.DefaultTransport when it is nil
+func NewTransport(base http.RoundTripper, opts ...TransportOption) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{base: base, now: time.Now, hosts: make(map[string]*hostState)}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if wait := t.reserve(host); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	start := t.now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := t.now().Sub(start)
+
+	t.mu.Lock()
+	h := t.host(host)
+	h.stats.Requests++
+	h.stats.Elapsed += elapsed
+	if err != nil {
+		h.stats.Errors++
+	}
+	t.mu.Unlock()
+
+	if t.log != nil {
+		result := "error: " + fmt.Sprint(err)
+		if err == nil {
+			result = resp.Status
+		}
+		fmt.Fprintf(t.log, "http: %s %s%s -> %s (%s)\n", req.Method, host, req.URL.Path, result, elapsed.Round(time.Millisecond))
+	}
+	return resp, err
+}
+
+// reserve takes the next free slot for host and returns how long to wait
+// for it
+func (t *Transport) reserve(host string) time.Duration {
+	if t.interval <= 0 {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h := t.host(host)
+	now := t.now()
+	slot := h.next
+	if slot.Before(now) {
+		slot = now
+	}
+	h.next = slot.Add(t.interval)
+	wait := slot.Sub(now)
+	h.stats.Waited += wait
+	return wait
+}
+
+// host returns the state for host; t.mu must be held
+func (t *Transport) host(host string) *hostState {
+	h, ok := t.hosts[host]
+	if !ok {
+		h = &hostState{stats: HostStats{Host: host}}
+		t.hosts[host] = h
+	}
+	return h
+}
+
+// Stats returns the counts for each host the transport has sent to, by host
+func (t *Transport) Stats() []HostStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := make([]HostStats, 0, len(t.hosts))
+	for _, h := range t.hosts {
+		stats = append(stats, h.stats)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Host < stats[j].Host })
+	return stats
+}
+
+// WriteStats writes a line per host: requests, failures and time taken
+func WriteStats(w io.Writer, stats []HostStats) {
+	for _, s := range stats {
+		fmt.Fprintf(w, "http: %s: %d requests, %d failed, %s waiting for responses", s.Host, s.Requests, s.Errors, s.Elapsed.Round(time.Millisecond))
+		if s.Waited > 0 {
+			fmt.Fprintf(w, ", %s held back by network.rate_limit", s.Waited.Round(time.Millisecond))
+		}
+		fmt.Fprintln(w)
+	}
+}
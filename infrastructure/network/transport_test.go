@@ -0,0 +1,147 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// roundTripFunc lets a function stand in for the base transport
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func okResponse(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: http.NoBody, Request: req}, nil
+}
+
+func TestTransport_LogsAndCountsPerHost(t *testing.T) {
+	var log bytes.Buffer
+	failing := errors.New("connection reset")
+	transport := NewTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == "gmail.googleapis.com" {
+			return nil, failing
+		}
+		return okResponse(req)
+	}), WithRequestLog(&log))
+	client := &http.Client{Transport: transport}
+
+	for _, url := range []string{
+		"https://www.googleapis.com/drive/v3/files?q=secret",
+		"https://www.googleapis.com/drive/v3/about",
+		"https://gmail.googleapis.com/gmail/v1/users/me/messages/send",
+	} {
+		if resp, err := client.Get(url); err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	stats := transport.Stats()
+	if len(stats) != 2 || stats[0].Host != "gmail.googleapis.com" || stats[1].Host != "www.googleapis.com" {
+		t.Fatalf("stats = %+v, want one entry per host in order", stats)
+	}
+	if stats[0].Requests != 1 || stats[0].Errors != 1 || stats[1].Requests != 2 || stats[1].Errors != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if !strings.Contains(log.String(), "http: GET www.googleapis.com/drive/v3/files -> 200 OK") {
+		t.Errorf("log does not show the request:\n%s", log.String())
+	}
+	if strings.Contains(log.String(), "secret") {
+		t.Errorf("log should leave out query strings:\n%s", log.String())
+	}
+	if !strings.Contains(log.String(), "gmail.googleapis.com/gmail/v1/users/me/messages/send -> error: connection reset") {
+		t.Errorf("log does not show the failure:\n%s", log.String())
+	}
+}
+
+func TestTransport_RateLimitsEachHost(t *testing.T) {
+	transport := NewTransport(roundTripFunc(okResponse), WithHostRateLimit(1))
+	now := time.Date(2025, 12, 28, 10, 0, 0, 0, time.UTC)
+	transport.now = func() time.Time { return now }
+
+	if wait := transport.reserve("a.example"); wait != 0 {
+		t.Errorf("first request waited %s", wait)
+	}
+	if wait := transport.reserve("a.example"); wait != time.Second {
+		t.Errorf("second request to the same host waited %s, want 1s", wait)
+	}
+	if wait := transport.reserve("b.example"); wait != 0 {
+		t.Errorf("another host waited %s", wait)
+	}
+	now = now.Add(5 * time.Second)
+	if wait := transport.reserve("a.example"); wait != 0 {
+		t.Errorf("a request after a pause waited %s", wait)
+	}
+}
+
+func TestTransport_RateLimitStopsWithTheContext(t *testing.T) {
+	transport := NewTransport(roundTripFunc(okResponse), WithHostRateLimit(0.001))
+	transport.reserve("www.googleapis.com")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.googleapis.com/drive/v3/about", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestTransport_ConcurrentRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := NewHTTPClient(Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := client.Get(server.URL); err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := client.Transport.(*Transport).Stats()
+	if len(stats) != 1 || stats[0].Requests != 20 {
+		t.Errorf("stats = %+v, want 20 requests to one host", stats)
+	}
+}
+
+func TestSharedHTTPClient_ReusesTheClient(t *testing.T) {
+	first, err := SharedHTTPClient(Settings{RateLimit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := SharedHTTPClient(Settings{RateLimit: 50})
+	other, _ := SharedHTTPClient(Settings{RateLimit: 25})
+	if first != second {
+		t.Error("the same settings should share one client")
+	}
+	if first == other {
+		t.Error("different settings should get their own client")
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	var out bytes.Buffer
+	WriteStats(&out, []HostStats{{Host: "www.googleapis.com", Requests: 12, Errors: 1, Elapsed: 1500 * time.Millisecond, Waited: 200 * time.Millisecond}})
+	want := "http: www.googleapis.com: 12 requests, 1 failed, 1.5s waiting for responses, 200ms held back by network.rate_limit\n"
+	if out.String() != want {
+		t.Errorf("WriteStats() = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,336 @@
+package obs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"nac-service-media/domain/recording"
+
+	"golang.org/x/net/websocket"
+)
+
+// DefaultURL is the obs-websocket server address used by OBS 28+
+const DefaultURL = "ws://localhost:4455"
+
+// obs-websocket v5 protocol constants
+const (
+	subprotocol = "obswebsocket.json"
+	rpcVersion  = 1
+
+	opHello           = 0
+	opIdentify        = 1
+	opIdentified      = 2
+	opEvent           = 5
+	opRequest         = 6
+	opRequestResponse = 7
+
+	eventSubscriptionOutputs = 1 << 6
+	outputStateStopped       = "OBS_WEBSOCKET_OUTPUT_STOPPED"
+)
+
+type message struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d"`
+}
+
+type hello struct {
+	Authentication *struct {
+		Challenge string `json:"challenge"`
+		Salt      string `json:"salt"`
+	} `json:"authentication"`
+}
+
+type identify struct {
+	RPCVersion         int    `json:"rpcVersion"`
+	Authentication     string `json:"authentication,omitempty"`
+	EventSubscriptions int    `json:"eventSubscriptions"`
+}
+
+type request struct {
+	RequestType string `json:"requestType"`
+	RequestID   string `json:"requestId"`
+}
+
+type requestResponse struct {
+	RequestType   string `json:"requestType"`
+	RequestID     string `json:"requestId"`
+	RequestStatus struct {
+		Result  bool   `json:"result"`
+		Code    int    `json:"code"`
+		Comment string `json:"comment"`
+	} `json:"requestStatus"`
+	ResponseData json.RawMessage `json:"responseData"`
+}
+
+type event struct {
+	EventType string          `json:"eventType"`
+	EventData json.RawMessage `json:"eventData"`
+}
+
+type recordStateChanged struct {
+	OutputActive bool   `json:"outputActive"`
+	OutputState  string `json:"outputState"`
+	OutputPath   string `json:"outputPath"`
+}
+
+// Client implements recording.Recorder over the obs-websocket v5 protocol
+type Client struct {
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan requestResponse
+	nextID  int
+	err     error
+
+	recordStops chan string
+	done        chan struct{}
+}
+
+// Option is a functional option for configuring the connection
+type Option func(*dialOptions)
+
+type dialOptions struct {
+	password string
+}
+
+// WithPassword sets the obs-websocket server password
+func WithPassword(password string) Option {
+	return func(o *dialOptions) {
+		o.password = password
+	}
+}
+
+// Dial connects and identifies with the obs-websocket server at url
+func Dial(ctx context.Context, url string, opts ...Option) (*Client, error) {
+	o := &dialOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg, err := websocket.NewConfig(url, "http://localhost/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid OBS websocket URL %q: %w", url, err)
+	}
+	cfg.Protocol = []string{subprotocol}
+
+	conn, err := cfg.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to OBS at %s (is obs-websocket enabled?): %w", url, err)
+	}
+
+	c := &Client{
+		conn:        conn,
+		pending:     make(map[string]chan requestResponse),
+		recordStops: make(chan string, 1),
+		done:        make(chan struct{}),
+	}
+
+	if err := c.identify(ctx, o.password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go c.readLoop()
+	return c, nil
+}
+
+// identify performs the Hello/Identify/Identified handshake
+func (c *Client) identify(ctx context.Context, password string) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetDeadline(deadline)
+		defer c.conn.SetDeadline(time.Time{})
+	}
+
+	var msg message
+	if err := websocket.JSON.Receive(c.conn, &msg); err != nil {
+		return fmt.Errorf("failed to read OBS hello: %w", err)
+	}
+	if msg.Op != opHello {
+		return fmt.Errorf("unexpected OBS message op %d (expected hello)", msg.Op)
+	}
+
+	var h hello
+	if err := json.Unmarshal(msg.D, &h); err != nil {
+		return fmt.Errorf("failed to parse OBS hello: %w", err)
+	}
+
+	id := identify{RPCVersion: rpcVersion, EventSubscriptions: eventSubscriptionOutputs}
+	if h.Authentication != nil {
+		if password == "" {
+			return fmt.Errorf("OBS requires a websocket password; set obs.password in config")
+		}
+		id.Authentication = authResponse(password, h.Authentication.Salt, h.Authentication.Challenge)
+	}
+
+	if err := c.send(opIdentify, id); err != nil {
+		return fmt.Errorf("failed to identify with OBS: %w", err)
+	}
+
+	if err := websocket.JSON.Receive(c.conn, &msg); err != nil {
+		return fmt.Errorf("OBS rejected the connection (check obs.password): %w", err)
+	}
+	if msg.Op != opIdentified {
+		return fmt.Errorf("unexpected OBS message op %d (expected identified)", msg.Op)
+	}
+	return nil
+}
+
+// authResponse computes base64(sha256(base64(sha256(password + salt)) + challenge))
+func authResponse(password, salt, challenge string) string {
+	secret := sha256.Sum256([]byte(password + salt))
+	secretB64 := base64.StdEncoding.EncodeToString(secret[:])
+	auth := sha256.Sum256([]byte(secretB64 + challenge))
+	return base64.StdEncoding.EncodeToString(auth[:])
+}
+
+func (c *Client) send(op int, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return websocket.JSON.Send(c.conn, message{Op: op, D: data})
+}
+
+// readLoop dispatches request responses and recording events until the connection closes
+func (c *Client) readLoop() {
+	for {
+		var msg message
+		if err := websocket.JSON.Receive(c.conn, &msg); err != nil {
+			c.mu.Lock()
+			c.err = fmt.Errorf("OBS connection closed: %w", err)
+			c.mu.Unlock()
+			close(c.done)
+			return
+		}
+
+		switch msg.Op {
+		case opRequestResponse:
+			var resp requestResponse
+			if json.Unmarshal(msg.D, &resp) != nil {
+				continue
+			}
+			c.mu.Lock()
+			ch, ok := c.pending[resp.RequestID]
+			c.mu.Unlock()
+			if ok {
+				ch <- resp
+			}
+
+		case opEvent:
+			var ev event
+			if json.Unmarshal(msg.D, &ev) != nil || ev.EventType != "RecordStateChanged" {
+				continue
+			}
+			var state recordStateChanged
+			if json.Unmarshal(ev.EventData, &state) != nil || state.OutputState != outputStateStopped {
+				continue
+			}
+			select {
+			case c.recordStops <- state.OutputPath:
+			default:
+			}
+		}
+	}
+}
+
+// call sends a request and waits for its response data
+func (c *Client) call(ctx context.Context, requestType string) (json.RawMessage, error) {
+	c.mu.Lock()
+	c.nextID++
+	id := strconv.Itoa(c.nextID)
+	ch := make(chan requestResponse, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.send(opRequest, request{RequestType: requestType, RequestID: id}); err != nil {
+		return nil, fmt.Errorf("failed to send OBS %s request: %w", requestType, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, c.closedErr()
+	case resp := <-ch:
+		if !resp.RequestStatus.Result {
+			return nil, fmt.Errorf("OBS %s failed (code %d): %s", requestType, resp.RequestStatus.Code, resp.RequestStatus.Comment)
+		}
+		return resp.ResponseData, nil
+	}
+}
+
+func (c *Client) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err == nil {
+		return errors.New("OBS connection closed")
+	}
+	return c.err
+}
+
+// IsRecording implements recording.Recorder
+func (c *Client) IsRecording(ctx context.Context) (bool, error) {
+	data, err := c.call(ctx, "GetRecordStatus")
+	if err != nil {
+		return false, err
+	}
+	var status struct {
+		OutputActive bool `json:"outputActive"`
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return false, fmt.Errorf("failed to parse OBS record status: %w", err)
+	}
+	return status.OutputActive, nil
+}
+
+// StopRecording implements recording.Recorder
+func (c *Client) StopRecording(ctx context.Context) (string, error) {
+	data, err := c.call(ctx, "StopRecord")
+	if err != nil {
+		return "", err
+	}
+	var stopped struct {
+		OutputPath string `json:"outputPath"`
+	}
+	if err := json.Unmarshal(data, &stopped); err != nil {
+		return "", fmt.Errorf("failed to parse OBS stop response: %w", err)
+	}
+	return stopped.OutputPath, nil
+}
+
+// WaitForStop implements recording.Recorder
+func (c *Client) WaitForStop(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-c.done:
+		return "", c.closedErr()
+	case path := <-c.recordStops:
+		return path, nil
+	}
+}
+
+// Close closes the connection to OBS
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Ensure Client implements recording.Recorder
+var _ recording.Recorder = (*Client)(nil)
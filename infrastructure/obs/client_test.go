@@ -0,0 +1,189 @@
+package obs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// fakeOBS is a minimal obs-websocket v5 server
+type fakeOBS struct {
+	password   string
+	recording  bool
+	outputPath string
+	// stopEvent emits RecordStateChanged after this many requests (0 = never)
+	stopEventAfter int
+	requests       []string
+}
+
+func (f *fakeOBS) serve(t *testing.T) string {
+	t.Helper()
+	server := websocket.Server{
+		Handshake: func(cfg *websocket.Config, r *http.Request) error {
+			cfg.Protocol = []string{subprotocol}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			f.handle(t, ws)
+		},
+	}
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func (f *fakeOBS) send(ws *websocket.Conn, op int, payload any) {
+	data, _ := json.Marshal(payload)
+	websocket.JSON.Send(ws, message{Op: op, D: data})
+}
+
+func (f *fakeOBS) handle(t *testing.T, ws *websocket.Conn) {
+	const salt, challenge = "c2FsdA==", "Y2hhbGxlbmdl"
+	h := map[string]any{"obsWebSocketVersion": "5.1.0", "rpcVersion": 1}
+	if f.password != "" {
+		h["authentication"] = map[string]string{"salt": salt, "challenge": challenge}
+	}
+	f.send(ws, opHello, h)
+
+	var msg message
+	if err := websocket.JSON.Receive(ws, &msg); err != nil || msg.Op != opIdentify {
+		return
+	}
+	var id identify
+	json.Unmarshal(msg.D, &id)
+	if f.password != "" && id.Authentication != authResponse(f.password, salt, challenge) {
+		return // OBS closes the connection on failed authentication
+	}
+	f.send(ws, opIdentified, map[string]int{"negotiatedRpcVersion": 1})
+
+	for {
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			return
+		}
+		var req request
+		json.Unmarshal(msg.D, &req)
+		f.requests = append(f.requests, req.RequestType)
+
+		resp := map[string]any{
+			"requestType":   req.RequestType,
+			"requestId":     req.RequestID,
+			"requestStatus": map[string]any{"result": true, "code": 100},
+		}
+		switch req.RequestType {
+		case "GetRecordStatus":
+			resp["responseData"] = map[string]any{"outputActive": f.recording}
+		case "StopRecord":
+			if !f.recording {
+				resp["requestStatus"] = map[string]any{"result": false, "code": 501, "comment": "Output not running"}
+			} else {
+				f.recording = false
+				resp["responseData"] = map[string]any{"outputPath": f.outputPath}
+			}
+		}
+		f.send(ws, opRequestResponse, resp)
+
+		if f.stopEventAfter > 0 && len(f.requests) == f.stopEventAfter {
+			f.send(ws, opEvent, map[string]any{
+				"eventType": "RecordStateChanged",
+				"eventData": map[string]any{
+					"outputActive": false,
+					"outputState":  outputStateStopped,
+					"outputPath":   f.outputPath,
+				},
+			})
+		}
+	}
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestClient_StopRecording(t *testing.T) {
+	fake := &fakeOBS{password: "secret", recording: true, outputPath: "/videos/2025-12-28 10-06-16.mp4"}
+	ctx := testContext(t)
+
+	client, err := Dial(ctx, fake.serve(t), WithPassword("secret"))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer client.Close()
+
+	active, err := client.IsRecording(ctx)
+	if err != nil || !active {
+		t.Fatalf("IsRecording = %v, %v; want true", active, err)
+	}
+
+	path, err := client.StopRecording(ctx)
+	if err != nil {
+		t.Fatalf("StopRecording failed: %v", err)
+	}
+	if path != fake.outputPath {
+		t.Errorf("StopRecording path = %q, want %q", path, fake.outputPath)
+	}
+}
+
+func TestClient_StopRecording_NotRunning(t *testing.T) {
+	fake := &fakeOBS{}
+	ctx := testContext(t)
+
+	client, err := Dial(ctx, fake.serve(t))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.StopRecording(ctx)
+	if err == nil || !strings.Contains(err.Error(), "Output not running") {
+		t.Errorf("expected OBS request failure, got %v", err)
+	}
+}
+
+func TestClient_WaitForStop(t *testing.T) {
+	fake := &fakeOBS{recording: true, outputPath: "/videos/2025-12-28 10-06-16.mp4", stopEventAfter: 1}
+	ctx := testContext(t)
+
+	client, err := Dial(ctx, fake.serve(t))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.IsRecording(ctx); err != nil {
+		t.Fatalf("IsRecording failed: %v", err)
+	}
+	path, err := client.WaitForStop(ctx)
+	if err != nil {
+		t.Fatalf("WaitForStop failed: %v", err)
+	}
+	if path != fake.outputPath {
+		t.Errorf("WaitForStop path = %q, want %q", path, fake.outputPath)
+	}
+}
+
+func TestDial_PasswordRequired(t *testing.T) {
+	fake := &fakeOBS{password: "secret"}
+
+	_, err := Dial(testContext(t), fake.serve(t))
+	if err == nil || !strings.Contains(err.Error(), "obs.password") {
+		t.Errorf("expected missing password error, got %v", err)
+	}
+}
+
+func TestDial_WrongPassword(t *testing.T) {
+	fake := &fakeOBS{password: "secret"}
+
+	_, err := Dial(testContext(t), fake.serve(t), WithPassword("wrong"))
+	if err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("expected rejected connection error, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/resource"
+)
+
+// Linux reports CPU time in clock ticks of USER_HZ, which is 100 on every
+// mainstream architecture, and resident memory in pages
+const (
+	clockTicksPerSecond = 100
+	defaultPageSize     = 4096
+)
+
+// ProcSampler implements resource.Sampler by reading /proc, so it sees this
+// program and every process below it, such as ffmpeg. A Windows ffmpeg.exe
+// started from WSL runs outside Linux and is not seen.
+type ProcSampler struct {
+	root     string
+	pid      int
+	pageSize int64
+	now      func() time.Time
+}
+
+// ProcOption is a functional option for configuring ProcSampler
+type ProcOption func(*ProcSampler)
+
+// WithProcRoot reads the process table from dir instead of /proc (for testing)
+func WithProcRoot(dir string) ProcOption {
+	return func(s *ProcSampler) {
+		s.root = dir
+	}
+}
+
+// WithPID samples pid and its descendants instead of this program
+func WithPID(pid int) ProcOption {
+	return func(s *ProcSampler) {
+		s.pid = pid
+	}
+}
+
+// NewProcSampler creates a new ProcSampler
+func NewProcSampler(opts ...ProcOption) *ProcSampler {
+	s := &ProcSampler{
+		root:     "/proc",
+		pid:      os.Getpid(),
+		pageSize: int64(os.Getpagesize()),
+		now:      time.Now,
+	}
+	if s.pageSize <= 0 {
+		s.pageSize = defaultPageSize
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Sample implements resource.Sampler
+func (s *ProcSampler) Sample() (resource.Snapshot, error) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return resource.Snapshot{}, fmt.Errorf("cannot read the process table: %w", err)
+	}
+
+	stats := make(map[int]procStat)
+	children := make(map[int][]int)
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		st, err := s.readStat(pid)
+		if err != nil {
+			continue // The process exited while the table was read
+		}
+		stats[pid] = st
+		children[st.ppid] = append(children[st.ppid], pid)
+	}
+	if _, ok := stats[s.pid]; !ok {
+		return resource.Snapshot{}, fmt.Errorf("process %d is not in %s", s.pid, s.root)
+	}
+
+	snap := resource.Snapshot{At: s.now()}
+	for queue := []int{s.pid}; len(queue) > 0; queue = queue[1:] {
+		pid := queue[0]
+		st := stats[pid]
+		read, write := s.readIO(pid)
+		snap.Processes = append(snap.Processes, resource.Process{
+			PID:        pid,
+			CPU:        time.Duration(st.ticks) * time.Second / clockTicksPerSecond,
+			Memory:     st.rssPages * s.pageSize,
+			ReadBytes:  read,
+			WriteBytes: write,
+		})
+		queue = append(queue, children[pid]...)
+	}
+	return snap, nil
+}
+
+// procStat holds the fields of /proc/<pid>/stat that are sampled
+type procStat struct {
+	ppid     int
+	ticks    int64 // utime + stime
+	rssPages int64
+}
+
+// readStat parses /proc/<pid>/stat. The command name can hold spaces and
+// parentheses, so fields are counted from its closing parenthesis.
+func (s *ProcSampler) readStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join(s.root, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return procStat{}, fmt.Errorf("malformed stat for process %d", pid)
+	}
+	// Fields after the name start at state (field 3)
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("malformed stat for process %d", pid)
+	}
+	field := func(n int) int64 {
+		v, _ := strconv.ParseInt(fields[n-3], 10, 64)
+		return v
+	}
+	return procStat{
+		ppid:     int(field(4)),
+		ticks:    field(14) + field(15),
+		rssPages: field(24),
+	}, nil
+}
+
+// readIO returns the bytes a process read from and wrote to storage; zero
+// when /proc/<pid>/io cannot be read
+func (s *ProcSampler) readIO(pid int) (read, write int64) {
+	f, err := os.Open(filepath.Join(s.root, strconv.Itoa(pid), "io"))
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		switch key {
+		case "read_bytes":
+			read = n
+		case "write_bytes":
+			write = n
+		}
+	}
+	return read, write
+}
+
+// Ensure ProcSampler implements resource.Sampler
+var _ resource.Sampler = (*ProcSampler)(nil)
@@ -0,0 +1,80 @@
+package resource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// writeProc adds a process to a fake /proc
+func writeProc(t *testing.T, root string, pid, ppid int, name string, utime, stime, rssPages int, io string) {
+	t.Helper()
+	dir := filepath.Join(root, strconv.Itoa(pid))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	stat := fmt.Sprintf("%d (%s) S %d 1 1 0 -1 4194304 100 0 0 0 %d %d 0 0 20 0 4 0 1000 100000 %d 18446744073709551615\n",
+		pid, name, ppid, utime, stime, rssPages)
+	if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if io != "" {
+		if err := os.WriteFile(filepath.Join(dir, "io"), []byte(io), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestProcSampler_Sample(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, 100, 1, "nac-service-media", 250, 50, 1000, "rchar: 999\nread_bytes: 4096\nwrite_bytes: 0\n")
+	writeProc(t, root, 200, 100, "ffmpeg", 1200, 300, 50000, "read_bytes: 104857600\nwrite_bytes: 20971520\ncancelled_write_bytes: 0\n")
+	writeProc(t, root, 300, 200, "my (odd) name", 10, 0, 10, "")
+	writeProc(t, root, 400, 1, "browser", 99999, 0, 99999, "read_bytes: 1\n")
+
+	at := time.Date(2025, 12, 28, 11, 0, 0, 0, time.UTC)
+	s := NewProcSampler(WithProcRoot(root), WithPID(100))
+	s.pageSize = 4096
+	s.now = func() time.Time { return at }
+
+	snap, err := s.Sample()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !snap.At.Equal(at) || len(snap.Processes) != 3 {
+		t.Fatalf("snapshot = %+v, want this program and its two descendants", snap)
+	}
+	ffmpeg := snap.Processes[1]
+	if ffmpeg.PID != 200 || ffmpeg.CPU != 15*time.Second || ffmpeg.Memory != 50000*4096 ||
+		ffmpeg.ReadBytes != 104857600 || ffmpeg.WriteBytes != 20971520 {
+		t.Errorf("ffmpeg = %+v", ffmpeg)
+	}
+	if odd := snap.Processes[2]; odd.PID != 300 || odd.CPU != 100*time.Millisecond || odd.ReadBytes != 0 {
+		t.Errorf("process with parentheses in its name = %+v", odd)
+	}
+}
+
+func TestProcSampler_MissingProcess(t *testing.T) {
+	if _, err := NewProcSampler(WithProcRoot(t.TempDir()), WithPID(100)).Sample(); err == nil {
+		t.Error("Sample() should fail when the program is not in the process table")
+	}
+	if _, err := NewProcSampler(WithProcRoot(filepath.Join(t.TempDir(), "none"))).Sample(); err == nil {
+		t.Error("Sample() should fail without a process table")
+	}
+}
+
+func TestProcSampler_ThisProcess(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("no /proc on this system")
+	}
+	snap, err := NewProcSampler().Sample()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Processes) == 0 || snap.Processes[0].PID != os.Getpid() || snap.Processes[0].Memory == 0 {
+		t.Errorf("snapshot = %+v, want this test process first", snap)
+	}
+}
@@ -0,0 +1,107 @@
+package runstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/runstate"
+)
+
+// DirStore keeps each unfinished run as <date>.json in a directory
+type DirStore struct {
+	dir string
+}
+
+var _ runstate.Store = (*DirStore)(nil)
+
+// NewDirStore creates a store backed by dir, which is created on first save
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+// Dir returns the backing directory
+func (s *DirStore) Dir() string {
+	return s.dir
+}
+
+func (s *DirStore) path(serviceDate time.Time) string {
+	return filepath.Join(s.dir, serviceDate.Format("2006-01-02")+".json")
+}
+
+// Load reads the saved run for the service date
+func (s *DirStore) Load(serviceDate time.Time) (*runstate.State, error) {
+	return s.read(s.path(serviceDate), serviceDate.Format("2006-01-02"))
+}
+
+// Latest reads the saved run updated most recently
+func (s *DirStore) Latest() (*runstate.State, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run state: %w", err)
+	}
+	var latest *runstate.State
+	for _, path := range paths {
+		state, err := s.read(path, strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil || state.UpdatedAt.After(latest.UpdatedAt) {
+			latest = state
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w in %s", runstate.ErrNoState, s.dir)
+	}
+	return latest, nil
+}
+
+func (s *DirStore) read(path, date string) (*runstate.State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s", runstate.ErrNoState, date)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run state: %w", err)
+	}
+	var state runstate.State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid run state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// Save writes the run through a temporary file, so a crash mid-write leaves
+// the previous state intact
+func (s *DirStore) Save(state *runstate.State) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create run state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run state: %w", err)
+	}
+
+	path := s.path(state.ServiceDate)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write run state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace run state: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the saved run; it is not an error if there is none
+func (s *DirStore) Clear(serviceDate time.Time) error {
+	if err := os.Remove(s.path(serviceDate)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove run state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package runstate
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/runstate"
+)
+
+func TestDirStore(t *testing.T) {
+	store := NewDirStore(filepath.Join(t.TempDir(), ".nac-state"))
+	first := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 7)
+
+	if _, err := store.Latest(); !errors.Is(err, runstate.ErrNoState) {
+		t.Fatalf("Latest() on an empty store error = %v, want ErrNoState", err)
+	}
+	if _, err := store.Load(first); !errors.Is(err, runstate.ErrNoState) {
+		t.Fatalf("Load() error = %v, want ErrNoState", err)
+	}
+
+	a := runstate.New(first, runstate.Params{InputPath: "a.mp4", StartTime: "00:10:00", EndTime: "01:40:00"})
+	a.TrimmedPath = "trimmed/2025-12-28.mp4"
+	a.Video = &runstate.Upload{FileID: "vid", URL: "https://drive.google.com/file/d/vid/view"}
+	a.Complete("Upload video", time.Date(2025, 12, 28, 12, 0, 0, 0, time.UTC))
+	b := runstate.New(second, runstate.Params{InputPath: "b.mp4"})
+	b.Complete("Trim video", time.Date(2025, 12, 28, 11, 0, 0, 0, time.UTC))
+	for _, s := range []*runstate.State{a, b} {
+		if err := store.Save(s); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	got, err := store.Load(first)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.Params.StartTime != "00:10:00" || got.Video.FileID != "vid" || got.LastStep != "Upload video" {
+		t.Errorf("Load() = %+v", got)
+	}
+	if latest, err := store.Latest(); err != nil || !latest.ServiceDate.Equal(first) {
+		t.Errorf("Latest() = %+v, %v, want the run updated last", latest, err)
+	}
+
+	if err := store.Clear(first); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, err := store.Load(first); !errors.Is(err, runstate.ErrNoState) {
+		t.Errorf("Load() after Clear() error = %v", err)
+	}
+	if err := store.Clear(first); err != nil {
+		t.Errorf("clearing twice should not fail: %v", err)
+	}
+}
@@ -0,0 +1,562 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/audit"
+	"nac-service-media/domain/distribution"
+)
+
+// DefaultRegion is the signing region when none is configured; MinIO accepts it
+const DefaultRegion = "us-east-1"
+
+// metaPrefix starts the headers S3 stores as user metadata. App property
+// names are sent with "-" for "_", which proxies may drop from header names.
+const metaPrefix = "X-Amz-Meta-"
+
+// HTTPDoer abstracts the HTTP client for testing
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client implements distribution.DriveClient on an S3-compatible bucket such
+// as MinIO. Files are objects under a key prefix; a file's ID is its key.
+// There is no trash or sharing: deletes are immediate, and links are either
+// presigned or served from a public URL.
+type Client struct {
+	httpClient HTTPDoer
+	endpoint   *url.URL
+	bucket     string
+	prefix     string
+	signer     signer
+	publicURL  string
+	linkExpiry time.Duration
+	quota      int64
+	now        func() time.Time
+	auditLog   audit.Recorder
+}
+
+// Option configures the Client
+type Option func(*Client)
+
+// WithHTTPClient sets a custom HTTP client (useful for testing)
+func WithHTTPClient(c HTTPDoer) Option {
+	return func(client *Client) {
+		client.httpClient = c
+	}
+}
+
+// WithAuditLog records deleted objects
+func WithAuditLog(recorder audit.Recorder) Option {
+	return func(client *Client) {
+		client.auditLog = recorder
+	}
+}
+
+// WithCredentials sets the access key pair requests are signed with
+func WithCredentials(accessKey, secretKey string) Option {
+	return func(client *Client) {
+		client.signer.accessKey = accessKey
+		client.signer.secretKey = secretKey
+	}
+}
+
+// WithRegion sets the signing region (default us-east-1)
+func WithRegion(region string) Option {
+	return func(client *Client) {
+		if region != "" {
+			client.signer.region = region
+		}
+	}
+}
+
+// WithPrefix stores files under a key prefix such as "services/"
+func WithPrefix(prefix string) Option {
+	return func(client *Client) {
+		client.prefix = prefix
+	}
+}
+
+// WithPublicURL links files at publicURL/<key>, for buckets that allow
+// anonymous reads, instead of presigning each link
+func WithPublicURL(u string) Option {
+	return func(client *Client) {
+		client.publicURL = strings.TrimSuffix(u, "/")
+	}
+}
+
+// WithLinkExpiry sets how long presigned links work (default and maximum one week)
+func WithLinkExpiry(d time.Duration) Option {
+	return func(client *Client) {
+		if d > 0 {
+			client.linkExpiry = min(d, MaxLinkExpiry)
+		}
+	}
+}
+
+// WithQuota caps the bucket at quota bytes. Without it the bucket is treated
+// as unlimited and nothing is ever deleted to make room.
+func WithQuota(quota int64) Option {
+	return func(client *Client) {
+		client.quota = quota
+	}
+}
+
+// WithClock sets the time source used to sign requests (useful for testing)
+func WithClock(now func() time.Time) Option {
+	return func(client *Client) {
+		client.now = now
+	}
+}
+
+// NewClient creates a client for bucket on the S3-compatible server at
+// endpoint, using path-style URLs (endpoint/bucket/key)
+func NewClient(endpoint, bucket string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q: want http(s)://host[:port]", endpoint)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("no S3 bucket given")
+	}
+
+	c := &Client{
+		httpClient: http.DefaultClient,
+		endpoint:   u,
+		bucket:     bucket,
+		signer:     signer{region: DefaultRegion},
+		linkExpiry: MaxLinkExpiry,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// key returns the object key for a file name
+func (c *Client) key(fileName string) string {
+	return c.prefix + fileName
+}
+
+// objectURL returns the URL of key and its path as escaped on the wire
+func (c *Client) objectURL(key string) (*url.URL, string) {
+	escaped := c.endpoint.EscapedPath() + "/" + uriEncode(c.bucket, true)
+	if key != "" {
+		escaped += "/" + uriEncode(key, false)
+	}
+	u := *c.endpoint
+	u.Path, _ = url.PathUnescape(escaped)
+	u.RawPath = escaped
+	return &u, escaped
+}
+
+// do sends a signed request for key, or for the bucket itself when key is
+// empty, and returns the response if the status is 2xx. A 404 is returned as
+// errNotFound so callers can treat it as "no such file".
+func (c *Client) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	u, canonicalURI := c.objectURL(key)
+	// Send the query exactly as it was signed
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	c.signer.authorize(req, canonicalURI, unsignedPayload, c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 %s %s failed: %w", method, c.describe(key), err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	return nil, fmt.Errorf("S3 %s %s failed: %s%s", method, c.describe(key), resp.Status, errorCode(resp.Body))
+}
+
+// describe names key, or the bucket, for error messages
+func (c *Client) describe(key string) string {
+	if key == "" {
+		return "bucket " + c.bucket
+	}
+	return key
+}
+
+// errNotFound is returned by do for a 404
+var errNotFound = errors.New("not found")
+
+// errorCode returns the code from an S3 XML error body, formatted for
+// appending to a status, or "" if there is none
+func errorCode(body io.Reader) string {
+	var e struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(body, 64*1024)).Decode(&e); err != nil || e.Code == "" {
+		return ""
+	}
+	if e.Message == "" {
+		return " (" + e.Code + ")"
+	}
+	return fmt.Sprintf(" (%s: %s)", e.Code, e.Message)
+}
+
+// listBucketResult is the ListObjectsV2 response body
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// list returns every object under prefix. With shallow set, objects in
+// "subfolders" of the prefix are left out.
+func (c *Client) list(ctx context.Context, prefix string, shallow bool) ([]distribution.FileInfo, error) {
+	var files []distribution.FileInfo
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if shallow {
+			query.Set("delimiter", "/")
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil, 0, nil)
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("S3 bucket %s does not exist", c.bucket)
+		}
+		if err != nil {
+			return nil, err
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read S3 listing: %w", err)
+		}
+
+		for _, obj := range page.Contents {
+			name := strings.TrimPrefix(obj.Key, prefix)
+			if name == "" || strings.HasSuffix(name, "/") {
+				continue
+			}
+			files = append(files, distribution.FileInfo{
+				ID:          obj.Key,
+				Name:        name,
+				MimeType:    mimeType(name),
+				Size:        obj.Size,
+				CreatedTime: obj.LastModified,
+			})
+		}
+
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return files, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// head returns the object at key, or nil if there is none
+func (c *Client) head(ctx context.Context, key string) (*distribution.FileInfo, error) {
+	resp, err := c.do(ctx, http.MethodHead, key, nil, nil, 0, nil)
+	if errors.Is(err, errNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	info := &distribution.FileInfo{
+		ID:       key,
+		Name:     strings.TrimPrefix(key, c.prefix),
+		MimeType: resp.Header.Get("Content-Type"),
+		Size:     resp.ContentLength,
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.CreatedTime = t
+	}
+	for name, values := range resp.Header {
+		if prop, ok := strings.CutPrefix(http.CanonicalHeaderKey(name), metaPrefix); ok && len(values) > 0 {
+			if info.AppProperties == nil {
+				info.AppProperties = make(map[string]string)
+			}
+			info.AppProperties[strings.ReplaceAll(strings.ToLower(prop), "-", "_")] = values[0]
+		}
+	}
+	return info, nil
+}
+
+// put uploads req.LocalPath to key with req's type and app properties
+func (c *Client) put(ctx context.Context, key string, req distribution.UploadRequest) (*distribution.UploadResult, error) {
+	f, err := os.Open(req.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	header := http.Header{}
+	if req.MimeType != "" {
+		header.Set("Content-Type", req.MimeType)
+	}
+	for name, value := range req.AppProperties {
+		header.Set(metaPrefix+strings.ReplaceAll(name, "_", "-"), value)
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, key, nil, f, stat.Size(), header)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	result := &distribution.UploadResult{
+		FileID:   key,
+		FileName: req.FileName,
+		Size:     stat.Size(),
+	}
+	// A single-part upload's ETag is the hex MD5 of the content
+	if etag := strings.Trim(resp.Header.Get("ETag"), `"`); len(etag) == 32 {
+		result.MD5Checksum = etag
+	}
+	return result, nil
+}
+
+// ListFiles implements distribution.DriveClient. folderID is ignored; files
+// are the objects directly under the client's prefix.
+func (c *Client) ListFiles(ctx context.Context, folderID string) ([]distribution.FileInfo, error) {
+	return c.list(ctx, c.prefix, true)
+}
+
+// FindFileByName implements distribution.DriveClient
+func (c *Client) FindFileByName(ctx context.Context, folderID, fileName string) (*distribution.FileInfo, error) {
+	return c.head(ctx, c.key(fileName))
+}
+
+// FindFilesByProperty implements distribution.DriveClient. Listings don't
+// include metadata, so each file is looked up on its own.
+func (c *Client) FindFilesByProperty(ctx context.Context, folderID, key, value string) ([]distribution.FileInfo, error) {
+	files, err := c.ListFiles(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	matches := []distribution.FileInfo{}
+	for _, f := range files {
+		info, err := c.head(ctx, f.ID)
+		if err != nil {
+			return nil, err
+		}
+		if info != nil && info.AppProperties[key] == value {
+			matches = append(matches, *info)
+		}
+	}
+	return matches, nil
+}
+
+// GetStorageQuota implements distribution.DriveClient. Without a quota the
+// bucket is reported as unlimited and not listed.
+func (c *Client) GetStorageQuota(ctx context.Context) (*distribution.StorageInfo, error) {
+	if c.quota <= 0 {
+		return &distribution.StorageInfo{Unlimited: true}, nil
+	}
+
+	files, err := c.list(ctx, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure bucket usage: %w", err)
+	}
+	var used int64
+	for _, f := range files {
+		used += f.Size
+	}
+	return &distribution.StorageInfo{
+		TotalBytes:     c.quota,
+		UsedBytes:      used,
+		AvailableBytes: max(c.quota-used, 0),
+	}, nil
+}
+
+// ListMP4Files implements distribution.DriveClient
+func (c *Client) ListMP4Files(ctx context.Context, folderID string) ([]distribution.FileInfo, error) {
+	files, err := c.ListFiles(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	var mp4s []distribution.FileInfo
+	for _, f := range files {
+		if strings.HasSuffix(strings.ToLower(f.Name), ".mp4") {
+			mp4s = append(mp4s, f)
+		}
+	}
+	sort.Slice(mp4s, func(i, j int) bool { return mp4s[i].Name < mp4s[j].Name })
+	return mp4s, nil
+}
+
+// DeletePermanently implements distribution.DriveClient. Deleting a missing
+// object is not an error.
+func (c *Client) DeletePermanently(ctx context.Context, fileID string) error {
+	resp, err := c.do(ctx, http.MethodDelete, fileID, nil, nil, 0, nil)
+	if errors.Is(err, errNotFound) {
+		err = nil
+	} else if err == nil {
+		resp.Body.Close()
+	}
+	return audit.Record(c.auditLog, audit.ActionDriveDelete, fileID, "", err)
+}
+
+// EmptyTrash implements distribution.DriveClient. Buckets have no trash.
+func (c *Client) EmptyTrash(ctx context.Context) error {
+	return nil
+}
+
+// Upload implements distribution.DriveClient, storing the file at the
+// client's prefix plus req.FileName
+func (c *Client) Upload(ctx context.Context, req distribution.UploadRequest) (*distribution.UploadResult, error) {
+	result, err := c.put(ctx, c.key(req.FileName), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+	return result, nil
+}
+
+// SetPublicSharing implements distribution.DriveClient. Objects need no
+// sharing: their links are presigned or served from the public URL.
+func (c *Client) SetPublicSharing(ctx context.Context, fileID string) error {
+	return nil
+}
+
+// UploadAndShare implements distribution.DriveClient
+func (c *Client) UploadAndShare(ctx context.Context, req distribution.UploadRequest) (*distribution.UploadResult, error) {
+	result, err := c.Upload(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result.ShareableURL = c.Link(result.FileID)
+	return result, nil
+}
+
+// ReplaceContent implements distribution.ContentReplacer. The object keeps
+// its key, so public links are unchanged; presigned links are issued afresh.
+func (c *Client) ReplaceContent(ctx context.Context, fileID string, req distribution.UploadRequest) (*distribution.UploadResult, error) {
+	existing, err := c.head(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace file content: %w", err)
+	}
+	// A PUT replaces metadata too, so carry the old app properties over
+	if existing != nil && len(existing.AppProperties) > 0 {
+		props := make(map[string]string, len(existing.AppProperties)+len(req.AppProperties))
+		for k, v := range existing.AppProperties {
+			props[k] = v
+		}
+		for k, v := range req.AppProperties {
+			props[k] = v
+		}
+		req.AppProperties = props
+	}
+
+	result, err := c.put(ctx, fileID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replace file content: %w", err)
+	}
+	result.ShareableURL = c.Link(fileID)
+	return result, nil
+}
+
+// Download implements distribution.Downloader. A failed download leaves no
+// partial file behind.
+func (c *Client) Download(ctx context.Context, fileID, localPath string) (err error) {
+	resp, err := c.do(ctx, http.MethodGet, fileID, nil, nil, 0, nil)
+	if errors.Is(err, errNotFound) {
+		return fmt.Errorf("failed to download file: %s does not exist", fileID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	f, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", localPath, err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("failed to write %s: %w", localPath, cerr)
+		}
+		if err != nil {
+			os.Remove(localPath)
+		}
+	}()
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return fmt.Errorf("failed to download file: %w", err)
+	}
+	return nil
+}
+
+// Link implements distribution.Linker: the public URL of the object if one
+// is configured, otherwise a presigned GET link valid for the link expiry
+func (c *Client) Link(fileID string) string {
+	if c.publicURL != "" {
+		return c.publicURL + "/" + uriEncode(fileID, false)
+	}
+	u, canonicalURI := c.objectURL(fileID)
+	return c.signer.presign(http.MethodGet, u, canonicalURI, c.linkExpiry, c.now())
+}
+
+// mimeType guesses a file's type from its extension
+func mimeType(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".mp4":
+		return distribution.MimeTypeMP4
+	case ".mp3":
+		return distribution.MimeTypeMP3
+	}
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// Ensure Client implements the distribution ports it supports
+var (
+	_ distribution.DriveClient     = (*Client)(nil)
+	_ distribution.ContentReplacer = (*Client)(nil)
+	_ distribution.Downloader      = (*Client)(nil)
+	_ distribution.Linker          = (*Client)(nil)
+)
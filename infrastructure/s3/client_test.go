@@ -0,0 +1,442 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/audit"
+	"nac-service-media/domain/distribution"
+)
+
+// fakeObject is an object held by fakeS3
+type fakeObject struct {
+	data        []byte
+	contentType string
+	meta        http.Header
+}
+
+// fakeS3 is a minimal path-style S3 server for one bucket
+type fakeS3 struct {
+	t        *testing.T
+	bucket   string
+	pageSize int
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{t: t, bucket: "media", pageSize: 1000, objects: make(map[string]fakeObject)}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+		f.t.Errorf("unsigned request: %s %s", r.Method, r.URL)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "<Error><Code>NoSuchBucket</Code></Error>")
+		return
+	}
+
+	if key == "" {
+		f.list(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		meta := http.Header{}
+		for name, values := range r.Header {
+			if strings.HasPrefix(name, "X-Amz-Meta-") {
+				meta[name] = values
+			}
+		}
+		f.objects[key] = fakeObject{data: data, contentType: r.Header.Get("Content-Type"), meta: meta}
+		sum := md5.Sum(data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	case http.MethodHead, http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for name, values := range obj.meta {
+			w.Header()[name] = values
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", fmt.Sprint(len(obj.data)))
+		w.Header().Set("Last-Modified", "Sun, 28 Dec 2025 15:00:00 GMT")
+		if r.Method == http.MethodGet {
+			w.Write(obj.data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+
+	var keys []string
+	for key := range f.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" && strings.Contains(strings.TrimPrefix(key, prefix), delimiter) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	start := 0
+	if token := q.Get("continuation-token"); token != "" {
+		fmt.Sscan(token, &start)
+	}
+	end := min(start+f.pageSize, len(keys))
+
+	fmt.Fprint(w, "<ListBucketResult>")
+	for _, key := range keys[start:end] {
+		fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>2025-12-28T15:00:00.000Z</LastModified></Contents>",
+			key, len(f.objects[key].data))
+	}
+	if end < len(keys) {
+		fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", end)
+	}
+	fmt.Fprint(w, "</ListBucketResult>")
+}
+
+func (f *fakeS3) put(key, data string) {
+	f.objects[key] = fakeObject{data: []byte(data), meta: http.Header{}}
+}
+
+func newTestClient(t *testing.T, server *httptest.Server, opts ...Option) *Client {
+	t.Helper()
+	opts = append([]Option{WithCredentials("minio", "minio-secret"), WithPrefix("services/")}, opts...)
+	client, err := NewClient(server.URL, "media", opts...)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	return client
+}
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewClient_InvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "minio.example.org", "ftp://minio.example.org"} {
+		if _, err := NewClient(endpoint, "media"); err == nil {
+			t.Errorf("NewClient(%q) succeeded, want an error", endpoint)
+		}
+	}
+	if _, err := NewClient("https://minio.example.org", ""); err == nil {
+		t.Error("NewClient without a bucket succeeded, want an error")
+	}
+}
+
+func TestClient_UploadAndFind(t *testing.T) {
+	fake, server := newFakeS3(t)
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	result, err := client.UploadAndShare(ctx, distribution.UploadRequest{
+		LocalPath:     writeTempFile(t, "2025-12-28.mp3", "audio"),
+		FileName:      "2025-12-28.mp3",
+		MimeType:      distribution.MimeTypeMP3,
+		AppProperties: map[string]string{distribution.PropertyServiceDate: "2025-12-28"},
+	})
+	if err != nil {
+		t.Fatalf("UploadAndShare failed: %v", err)
+	}
+
+	if result.FileID != "services/2025-12-28.mp3" {
+		t.Errorf("FileID = %q, want services/2025-12-28.mp3", result.FileID)
+	}
+	sum := md5.Sum([]byte("audio"))
+	if err := distribution.VerifyUpload(result, 5, hex.EncodeToString(sum[:])); err != nil {
+		t.Errorf("VerifyUpload: %v", err)
+	}
+	if !strings.Contains(result.ShareableURL, "/media/services/2025-12-28.mp3?") {
+		t.Errorf("ShareableURL = %q, want a presigned object link", result.ShareableURL)
+	}
+	if got := fake.objects["services/2025-12-28.mp3"].contentType; got != distribution.MimeTypeMP3 {
+		t.Errorf("Content-Type = %q, want %s", got, distribution.MimeTypeMP3)
+	}
+
+	found, err := client.FindFileByName(ctx, "ignored", "2025-12-28.mp3")
+	if err != nil {
+		t.Fatalf("FindFileByName failed: %v", err)
+	}
+	if found == nil {
+		t.Fatal("FindFileByName found nothing")
+	}
+	if found.Name != "2025-12-28.mp3" || found.Size != 5 {
+		t.Errorf("found %+v, want 2025-12-28.mp3 of 5 bytes", found)
+	}
+	if got := found.AppProperties[distribution.PropertyServiceDate]; got != "2025-12-28" {
+		t.Errorf("service_date = %q, want 2025-12-28", got)
+	}
+
+	missing, err := client.FindFileByName(ctx, "ignored", "2026-01-04.mp3")
+	if err != nil || missing != nil {
+		t.Errorf("FindFileByName(missing) = %v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestClient_FindFilesByProperty(t *testing.T) {
+	fake, server := newFakeS3(t)
+	client := newTestClient(t, server)
+	fake.objects["services/renamed.mp4"] = fakeObject{data: []byte("v"), meta: http.Header{"X-Amz-Meta-Service-Date": {"2025-12-28"}}}
+	fake.objects["services/2026-01-04.mp4"] = fakeObject{data: []byte("v"), meta: http.Header{"X-Amz-Meta-Service-Date": {"2026-01-04"}}}
+
+	files, err := client.FindFilesByProperty(context.Background(), "ignored", distribution.PropertyServiceDate, "2025-12-28")
+	if err != nil {
+		t.Fatalf("FindFilesByProperty failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "renamed.mp4" {
+		t.Errorf("FindFilesByProperty = %+v, want renamed.mp4", files)
+	}
+}
+
+func TestClient_ListFiles(t *testing.T) {
+	fake, server := newFakeS3(t)
+	fake.pageSize = 2
+	client := newTestClient(t, server)
+	fake.put("services/2026-01-04.mp4", "video2")
+	fake.put("services/2025-12-28.mp4", "video")
+	fake.put("services/2025-12-28.mp3", "audio")
+	fake.put("services/archive/2024-01-07.mp4", "old")
+	fake.put("other/readme.txt", "x")
+
+	files, err := client.ListFiles(context.Background(), "ignored")
+	if err != nil {
+		t.Fatalf("ListFiles failed: %v", err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	if got, want := strings.Join(names, ","), "2025-12-28.mp3,2025-12-28.mp4,2026-01-04.mp4"; got != want {
+		t.Errorf("ListFiles = %s, want %s", got, want)
+	}
+
+	mp4s, err := client.ListMP4Files(context.Background(), "ignored")
+	if err != nil {
+		t.Fatalf("ListMP4Files failed: %v", err)
+	}
+	if len(mp4s) != 2 || mp4s[0].Name != "2025-12-28.mp4" || mp4s[0].MimeType != distribution.MimeTypeMP4 {
+		t.Errorf("ListMP4Files = %+v, want the two videos oldest first", mp4s)
+	}
+}
+
+func TestClient_GetStorageQuota(t *testing.T) {
+	fake, server := newFakeS3(t)
+	fake.put("services/2025-12-28.mp4", "0123456789")
+	fake.put("other/backup.zip", "01234")
+
+	unlimited, err := newTestClient(t, server).GetStorageQuota(context.Background())
+	if err != nil {
+		t.Fatalf("GetStorageQuota failed: %v", err)
+	}
+	if !unlimited.Unlimited || !unlimited.HasSpaceFor(1<<40) {
+		t.Errorf("without a quota got %+v, want unlimited", unlimited)
+	}
+
+	capped, err := newTestClient(t, server, WithQuota(20)).GetStorageQuota(context.Background())
+	if err != nil {
+		t.Fatalf("GetStorageQuota failed: %v", err)
+	}
+	want := distribution.StorageInfo{TotalBytes: 20, UsedBytes: 15, AvailableBytes: 5}
+	if *capped != want {
+		t.Errorf("with a quota got %+v, want %+v", *capped, want)
+	}
+}
+
+func TestClient_DeletePermanently(t *testing.T) {
+	fake, server := newFakeS3(t)
+	client := newTestClient(t, server)
+	fake.put("services/2025-12-28.mp4", "video")
+
+	if err := client.DeletePermanently(context.Background(), "services/2025-12-28.mp4"); err != nil {
+		t.Fatalf("DeletePermanently failed: %v", err)
+	}
+	if _, ok := fake.objects["services/2025-12-28.mp4"]; ok {
+		t.Error("object still exists after delete")
+	}
+}
+
+type recordingAuditLog struct {
+	events []audit.Event
+}
+
+func (r *recordingAuditLog) Record(e audit.Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+func TestClient_DeletePermanently_Audited(t *testing.T) {
+	fake, server := newFakeS3(t)
+	log := &recordingAuditLog{}
+	client := newTestClient(t, server, WithAuditLog(log))
+	fake.put("services/2025-12-28.mp4", "video")
+
+	if err := client.DeletePermanently(context.Background(), "services/2025-12-28.mp4"); err != nil {
+		t.Fatalf("DeletePermanently failed: %v", err)
+	}
+	if len(log.events) != 1 {
+		t.Fatalf("expected 1 audit event, got %+v", log.events)
+	}
+	if e := log.events[0]; e.Action != audit.ActionDriveDelete || e.Target != "services/2025-12-28.mp4" || e.Outcome != audit.OutcomeSuccess {
+		t.Errorf("unexpected audit event %+v", e)
+	}
+}
+
+func TestClient_ReplaceContent_KeepsProperties(t *testing.T) {
+	fake, server := newFakeS3(t)
+	client := newTestClient(t, server, WithPublicURL("https://media.example.org/"))
+	fake.objects["services/2025-12-28.mp3"] = fakeObject{data: []byte("old"), meta: http.Header{"X-Amz-Meta-Service-Date": {"2025-12-28"}}}
+
+	result, err := client.ReplaceContent(context.Background(), "services/2025-12-28.mp3", distribution.UploadRequest{
+		LocalPath: writeTempFile(t, "2025-12-28.mp3", "new audio"),
+		FileName:  "2025-12-28.mp3",
+		MimeType:  distribution.MimeTypeMP3,
+	})
+	if err != nil {
+		t.Fatalf("ReplaceContent failed: %v", err)
+	}
+
+	obj := fake.objects["services/2025-12-28.mp3"]
+	if string(obj.data) != "new audio" {
+		t.Errorf("content = %q, want new audio", obj.data)
+	}
+	if got := obj.meta.Get("X-Amz-Meta-Service-Date"); got != "2025-12-28" {
+		t.Errorf("service date metadata = %q, want it kept", got)
+	}
+	if result.ShareableURL != "https://media.example.org/services/2025-12-28.mp3" {
+		t.Errorf("ShareableURL = %q, want the public link", result.ShareableURL)
+	}
+}
+
+func TestClient_Download(t *testing.T) {
+	fake, server := newFakeS3(t)
+	client := newTestClient(t, server)
+	fake.put("services/2025-12-28.mp4", "video data")
+	dest := filepath.Join(t.TempDir(), "2025-12-28.mp4")
+
+	if err := client.Download(context.Background(), "services/2025-12-28.mp4", dest); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "video data" {
+		t.Errorf("downloaded %q, want video data", data)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.mp4")
+	if err := client.Download(context.Background(), "services/missing.mp4", missing); err == nil {
+		t.Error("Download of a missing object succeeded")
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("a failed download left a file behind")
+	}
+}
+
+func TestClient_ErrorIncludesS3Code(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>")
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := client.ListFiles(context.Background(), "ignored")
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("expected a 403 AccessDenied error, got %v", err)
+	}
+}
+
+func TestClient_Link(t *testing.T) {
+	now := func() time.Time { return time.Date(2025, 12, 28, 15, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		want   []string
+		absent []string
+	}{
+		{
+			name: "presigned for a week by default",
+			want: []string{
+				"https://minio.example.org/media/services/2025-12-28%20final.mp4?",
+				"X-Amz-Expires=604800",
+				"X-Amz-Date=20251228T150000Z",
+				"X-Amz-Signature=",
+			},
+		},
+		{
+			name: "shorter expiry",
+			opts: []Option{WithLinkExpiry(48 * time.Hour)},
+			want: []string{"X-Amz-Expires=172800"},
+		},
+		{
+			name: "expiry capped at a week",
+			opts: []Option{WithLinkExpiry(30 * 24 * time.Hour)},
+			want: []string{"X-Amz-Expires=604800"},
+		},
+		{
+			name:   "public URL",
+			opts:   []Option{WithPublicURL("https://media.example.org/")},
+			want:   []string{"https://media.example.org/services/2025-12-28%20final.mp4"},
+			absent: []string{"X-Amz-"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithCredentials("minio", "minio-secret"), WithClock(now)}, tt.opts...)
+			client, err := NewClient("https://minio.example.org", "media", opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			link := client.Link("services/2025-12-28 final.mp4")
+			for _, want := range tt.want {
+				if !strings.Contains(link, want) {
+					t.Errorf("Link() = %s, want it to contain %s", link, want)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(link, absent) {
+					t.Errorf("Link() = %s, want no %s", link, absent)
+				}
+			}
+		})
+	}
+}
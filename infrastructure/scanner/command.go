@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"nac-service-media/domain/distribution"
+)
+
+// DefaultTimeout bounds one scan when no timeout is configured
+const DefaultTimeout = 5 * time.Minute
+
+// FilePlaceholder in a command argument is replaced with the scanned file's
+// path. When no argument has it, the path is appended.
+const FilePlaceholder = "{file}"
+
+// Command runs an external program, such as clamscan, on each file
+type Command struct {
+	args     []string
+	timeout  time.Duration
+	exitCode int
+}
+
+var _ distribution.Scanner = (*Command)(nil)
+
+// Option is a functional option for configuring Command
+type Option func(*Command)
+
+// WithTimeout sets how long one scan may run (default DefaultTimeout)
+func WithTimeout(d time.Duration) Option {
+	return func(c *Command) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithExitCode sets the exit code that means the file is clean (default 0)
+func WithExitCode(code int) Option {
+	return func(c *Command) {
+		c.exitCode = code
+	}
+}
+
+// NewCommand creates a scanner running args, the program and its arguments
+func NewCommand(args []string, opts ...Option) (*Command, error) {
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		return nil, errors.New("scan command is empty")
+	}
+	c := &Command{args: args, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Scan runs the command on path. A timeout or an unexpected exit code wraps
+// distribution.ErrScanFailed; a command that cannot be started is an error
+// too, so a missing scanner never lets a file through.
+func (c *Command) Scan(ctx context.Context, path string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	args := c.argsFor(path)
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = time.Second // Don't wait on children left holding the output open
+	err := cmd.Run()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w: %s timed out after %s", distribution.ErrScanFailed, args[0], c.timeout)
+	}
+	code := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		return fmt.Errorf("unable to run scan command %s: %w", args[0], err)
+	}
+	if code != c.exitCode {
+		return fmt.Errorf("%w: %s exited with %d, want %d%s", distribution.ErrScanFailed, args[0], code, c.exitCode, lastLine(output.String()))
+	}
+	return nil
+}
+
+// argsFor fills in the file placeholder
+func (c *Command) argsFor(path string) []string {
+	args := make([]string, len(c.args))
+	placed := false
+	for i, a := range c.args {
+		if strings.Contains(a, FilePlaceholder) {
+			a = strings.ReplaceAll(a, FilePlaceholder, path)
+			placed = true
+		}
+		args[i] = a
+	}
+	if !placed {
+		args = append(args, path)
+	}
+	return args
+}
+
+// lastLine returns the scanner's last line of output as ": line", usually
+// its verdict, or "" when it printed nothing
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if line := strings.TrimSpace(lines[len(lines)-1]); line != "" {
+		return ": " + line
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/distribution"
+)
+
+func writeFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "2025-12-28.mp3")
+	if err := os.WriteFile(path, []byte("audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewCommand_RejectsEmpty(t *testing.T) {
+	if _, err := NewCommand(nil); err == nil {
+		t.Error("expected an error for an empty command")
+	}
+	if _, err := NewCommand([]string{" "}); err == nil {
+		t.Error("expected an error for a blank program")
+	}
+}
+
+func TestCommand_Scan(t *testing.T) {
+	path := writeFile(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		opts     []Option
+		wantErr  string
+		wantScan bool // Error should wrap ErrScanFailed
+	}{
+		{name: "clean file", args: []string{"sh", "-c", `test -f "$0"`}},
+		{name: "placeholder", args: []string{"sh", "-c", `test "$0" = "--file=` + path + `"`, "--file={file}"}},
+		{
+			name:     "infected file",
+			args:     []string{"sh", "-c", "echo \"$0: Eicar FOUND\"; exit 1"},
+			wantErr:  "exited with 1, want 0: " + path + ": Eicar FOUND",
+			wantScan: true,
+		},
+		{name: "custom clean exit code", args: []string{"sh", "-c", "exit 3"}, opts: []Option{WithExitCode(3)}},
+		{
+			name:     "timeout",
+			args:     []string{"sh", "-c", "exec sleep 5"},
+			opts:     []Option{WithTimeout(50 * time.Millisecond)},
+			wantErr:  "timed out after 50ms",
+			wantScan: true,
+		},
+		{name: "missing program", args: []string{"no-such-scanner-xyz"}, wantErr: "unable to run scan command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCommand(tt.args, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.Scan(context.Background(), path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, distribution.ErrScanFailed) != tt.wantScan {
+				t.Errorf("errors.Is(err, ErrScanFailed) = %v, want %v", !tt.wantScan, tt.wantScan)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package sftp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"nac-service-media/domain/distribution"
+
+	pkgsftp "github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Config contains SFTP connection settings
+type Config struct {
+	URL            string // sftp://host[:port]/remote/dir
+	Username       string
+	Password       string // Optional if PrivateKeyFile is set
+	PrivateKeyFile string // Optional path to an unencrypted private key
+	KnownHostsFile string // Defaults to ~/.ssh/known_hosts
+	PublicURL      string // Base HTTP(S) URL the remote dir is served from
+}
+
+// Client implements distribution.Publisher over SFTP. It connects on first use.
+type Client struct {
+	cfg       Config
+	addr      string
+	dir       string
+	publicURL string
+
+	sshClient  *ssh.Client
+	sftpClient *pkgsftp.Client
+}
+
+// Option configures the Client
+type Option func(*Client)
+
+// WithSFTPClient uses an established SFTP session instead of dialing (useful for testing)
+func WithSFTPClient(c *pkgsftp.Client) Option {
+	return func(client *Client) {
+		client.sftpClient = c
+	}
+}
+
+// NewClient creates an SFTP publisher from cfg
+func NewClient(cfg Config, opts ...Option) (*Client, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Scheme != "sftp" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid SFTP URL %q (expected sftp://host[:port]/path)", cfg.URL)
+	}
+	if cfg.PublicURL == "" {
+		return nil, fmt.Errorf("publish.public_url is required for SFTP so recipients have a download link")
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+	dir := u.Path
+	if dir == "" {
+		dir = "."
+	}
+
+	c := &Client{
+		cfg:       cfg,
+		addr:      net.JoinHostPort(u.Hostname(), port),
+		dir:       dir,
+		publicURL: strings.TrimSuffix(cfg.PublicURL, "/"),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// connect dials the SSH server and opens an SFTP session if not already connected
+func (c *Client) connect(ctx context.Context) error {
+	if c.sftpClient != nil {
+		return nil
+	}
+
+	auth, err := c.authMethods()
+	if err != nil {
+		return err
+	}
+	hostKeys, err := c.hostKeyCallback()
+	if err != nil {
+		return err
+	}
+	sshConfig := &ssh.ClientConfig{
+		User:            c.cfg.Username,
+		Auth:            auth,
+		HostKeyCallback: hostKeys,
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", c.addr, err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, c.addr, sshConfig)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SSH handshake with %s failed: %w", c.addr, err)
+	}
+	c.sshClient = ssh.NewClient(sshConn, chans, reqs)
+
+	c.sftpClient, err = pkgsftp.NewClient(c.sshClient)
+	if err != nil {
+		c.sshClient.Close()
+		c.sshClient = nil
+		return fmt.Errorf("failed to start SFTP session: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) authMethods() ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+	if c.cfg.PrivateKeyFile != "" {
+		key, err := os.ReadFile(c.cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	if c.cfg.Password != "" {
+		methods = append(methods, ssh.Password(c.cfg.Password))
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("SFTP requires publish.password or publish.private_key_file")
+	}
+	return methods, nil
+}
+
+func (c *Client) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	file := c.cfg.KnownHostsFile
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate known_hosts: %w", err)
+		}
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	callback, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known hosts from %s: %w", file, err)
+	}
+	return callback, nil
+}
+
+// Put implements distribution.Publisher
+func (c *Client) Put(ctx context.Context, remoteName string, r io.Reader, size int64) error {
+	if err := c.connect(ctx); err != nil {
+		return err
+	}
+
+	if err := c.sftpClient.MkdirAll(c.dir); err != nil {
+		return fmt.Errorf("failed to create remote directory %s: %w", c.dir, err)
+	}
+
+	target := path.Join(c.dir, remoteName)
+	f, err := c.sftpClient.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to finish writing %s: %w", target, err)
+	}
+	return nil
+}
+
+// Stat implements distribution.PublishedStater
+func (c *Client) Stat(ctx context.Context, remoteName string) (int64, bool, error) {
+	if err := c.connect(ctx); err != nil {
+		return 0, false, err
+	}
+
+	target := path.Join(c.dir, remoteName)
+	info, err := c.sftpClient.Stat(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to look up %s: %w", target, err)
+	}
+	return info.Size(), true, nil
+}
+
+// URL implements distribution.Publisher
+func (c *Client) URL(remoteName string) string {
+	return c.publicURL + "/" + url.PathEscape(remoteName)
+}
+
+// Close ends the SFTP session and SSH connection
+func (c *Client) Close() error {
+	if c.sftpClient != nil {
+		c.sftpClient.Close()
+	}
+	if c.sshClient != nil {
+		return c.sshClient.Close()
+	}
+	return nil
+}
+
+// Ensure Client implements distribution.Publisher and distribution.PublishedStater
+var (
+	_ distribution.Publisher       = (*Client)(nil)
+	_ distribution.PublishedStater = (*Client)(nil)
+)
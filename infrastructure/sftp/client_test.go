@@ -0,0 +1,118 @@
+package sftp
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	pkgsftp "github.com/pkg/sftp"
+)
+
+// newInMemorySFTP returns a client connected to an in-memory SFTP server
+func newInMemorySFTP(t *testing.T) *pkgsftp.Client {
+	t.Helper()
+	clientConn, serverConn := net.Pipe()
+
+	server := pkgsftp.NewRequestServer(serverConn, pkgsftp.InMemHandler())
+	go server.Serve()
+	t.Cleanup(func() { server.Close() })
+
+	client, err := pkgsftp.NewClientPipe(clientConn, clientConn)
+	if err != nil {
+		t.Fatalf("failed to start in-memory SFTP client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClient_Put(t *testing.T) {
+	session := newInMemorySFTP(t)
+	client, err := NewClient(Config{
+		URL:       "sftp://files.example.org/srv/services",
+		PublicURL: "https://files.example.org/services/",
+	}, WithSFTPClient(session))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if err := client.Put(context.Background(), "2025-12-28.mp3", strings.NewReader("audio"), 5); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	f, err := session.Open("/srv/services/2025-12-28.mp3")
+	if err != nil {
+		t.Fatalf("uploaded file not found: %v", err)
+	}
+	defer f.Close()
+	got, _ := io.ReadAll(f)
+	if string(got) != "audio" {
+		t.Errorf("uploaded content = %q, want audio", got)
+	}
+
+	if url := client.URL("2025-12-28.mp3"); url != "https://files.example.org/services/2025-12-28.mp3" {
+		t.Errorf("URL() = %q", url)
+	}
+}
+
+func TestClient_Stat(t *testing.T) {
+	session := newInMemorySFTP(t)
+	client, err := NewClient(Config{
+		URL:       "sftp://files.example.org/srv/services",
+		PublicURL: "https://files.example.org/services/",
+	}, WithSFTPClient(session))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if err := client.Put(context.Background(), "2025-12-28.mp3", strings.NewReader("audio"), 5); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	size, found, err := client.Stat(context.Background(), "2025-12-28.mp3")
+	if err != nil || !found || size != 5 {
+		t.Errorf("Stat(published) = %d, %v, %v; want 5, true, nil", size, found, err)
+	}
+
+	if _, found, err := client.Stat(context.Background(), "2025-12-21.mp3"); err != nil || found {
+		t.Errorf("Stat(missing) found = %v, err = %v; want false, nil", found, err)
+	}
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "wrong scheme",
+			cfg:     Config{URL: "https://files.example.org/srv", PublicURL: "https://files.example.org"},
+			wantErr: "invalid SFTP URL",
+		},
+		{
+			name:    "missing public URL",
+			cfg:     Config{URL: "sftp://files.example.org/srv"},
+			wantErr: "public_url is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestClient_DefaultPort(t *testing.T) {
+	client, err := NewClient(Config{URL: "sftp://files.example.org/srv", PublicURL: "https://files.example.org"})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.addr != "files.example.org:22" {
+		t.Errorf("addr = %q, want files.example.org:22", client.addr)
+	}
+}
@@ -0,0 +1,44 @@
+package summary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"nac-service-media/domain/summary"
+)
+
+// DirArchive writes run summaries into a directory, one file per format
+// named after the service date. A rerun for the same date replaces them.
+type DirArchive struct {
+	dir     string
+	formats []string
+}
+
+var _ summary.Archive = (*DirArchive)(nil)
+
+// NewDirArchive creates an archive in dir writing the given formats
+func NewDirArchive(dir string, formats []string) *DirArchive {
+	return &DirArchive{dir: dir, formats: formats}
+}
+
+// Save renders the summary in each format and writes it to the directory
+func (a *DirArchive) Save(s summary.RunSummary) ([]string, error) {
+	if err := os.MkdirAll(a.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create summary directory: %w", err)
+	}
+
+	var written []string
+	for _, format := range a.formats {
+		content, err := s.Render(format)
+		if err != nil {
+			return written, err
+		}
+		path := filepath.Join(a.dir, s.FileName(format))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return written, fmt.Errorf("failed to write summary %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
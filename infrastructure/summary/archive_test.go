@@ -0,0 +1,38 @@
+package summary
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/summary"
+)
+
+func TestDirArchive_Save(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "records")
+	archive := NewDirArchive(dir, []string{summary.FormatMarkdown, summary.FormatHTML})
+
+	written, err := archive.Save(summary.RunSummary{
+		ServiceDate: time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC),
+		SourceFile:  "2025-12-28 10-06-16.mp4",
+	})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	want := []string{filepath.Join(dir, "2025-12-28.md"), filepath.Join(dir, "2025-12-28.html")}
+	if strings.Join(written, ",") != strings.Join(want, ",") {
+		t.Fatalf("Save() wrote %v, want %v", written, want)
+	}
+	for _, path := range written {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "2025-12-28 10-06-16.mp4") {
+			t.Errorf("%s does not mention the source:\n%s", path, data)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package support
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nac-service-media/domain/distribution"
+	"nac-service-media/domain/support"
+	"nac-service-media/infrastructure/workspace"
+)
+
+// ConfigFile collects the config file
+type ConfigFile struct {
+	Path string
+}
+
+var _ support.Source = ConfigFile{}
+
+// Name describes the source
+func (c ConfigFile) Name() string { return "config file" }
+
+// Collect reads the config file; secrets are scrubbed by the bundle service
+func (c ConfigFile) Collect(support.Run) ([]support.File, error) {
+	data, err := readOptional(c.Path)
+	if data == nil || err != nil {
+		return nil, err
+	}
+	return []support.File{{Name: "config.yaml", Data: data}}, nil
+}
+
+// SummaryFiles collects the run summaries archived for the run's service date
+type SummaryFiles struct {
+	Dir string
+}
+
+var _ support.Source = SummaryFiles{}
+
+// Name describes the source
+func (s SummaryFiles) Name() string { return "run summary" }
+
+// Collect reads the Markdown and HTML summaries for the run
+func (s SummaryFiles) Collect(run support.Run) ([]support.File, error) {
+	if s.Dir == "" || run.IsZero() {
+		return nil, nil
+	}
+	var files []support.File
+	for _, ext := range []string{".md", ".html"} {
+		name := run.ServiceDate.Format("2006-01-02") + ext
+		data, err := readOptional(filepath.Join(s.Dir, name))
+		if err != nil {
+			return files, err
+		}
+		if data != nil {
+			files = append(files, support.File{Name: "summary/" + name, Data: data})
+		}
+	}
+	return files, nil
+}
+
+// LatestWorkspace collects the newest workspace a failed run left behind:
+// its detection frames, and a listing of everything else, since previews and
+// partial outputs are too big to send
+type LatestWorkspace struct {
+	Base string // Empty means workspace.DefaultBase()
+}
+
+var _ support.Source = LatestWorkspace{}
+
+// Name describes the source
+func (w LatestWorkspace) Name() string { return "failed run workspace" }
+
+// Collect reads the frames of the newest leftover workspace
+func (w LatestWorkspace) Collect(support.Run) ([]support.File, error) {
+	runs, err := workspace.List(w.Base)
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	latest := runs[len(runs)-1]
+	prefix := "workspace/" + filepath.Base(latest.Path) + "/"
+
+	var listing strings.Builder
+	var frames []support.File
+	err = filepath.WalkDir(latest.Path, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(latest.Path, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		fmt.Fprintf(&listing, "%s\t%s\n", rel, distribution.FormatSize(info.Size()))
+		if strings.HasPrefix(rel, workspace.Frames+"/") {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			frames = append(frames, support.File{Name: prefix + rel, Data: data})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].Name < frames[j].Name })
+	return append([]support.File{{Name: prefix + "files.txt", Data: []byte(listing.String())}}, frames...), nil
+}
+
+// readOptional reads a file, returning nil data when it doesn't exist
+func readOptional(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,60 @@
+package support
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"nac-service-media/domain/support"
+)
+
+// ZipWriter writes support bundles as zip files
+type ZipWriter struct {
+	now func() time.Time
+}
+
+var _ support.Writer = (*ZipWriter)(nil)
+
+// NewZipWriter creates a zip bundle writer
+func NewZipWriter() *ZipWriter {
+	return &ZipWriter{now: time.Now}
+}
+
+// Write creates the zip at path. A partly written zip is removed.
+func (w *ZipWriter) Write(path string, files []support.File) (err error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create bundle directory: %w", err)
+		}
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	zw := zip.NewWriter(out)
+	modified := w.now()
+	for _, f := range files {
+		entry, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", f.Name, err)
+		}
+		if _, err := entry.Write(f.Data); err != nil {
+			return fmt.Errorf("failed to add %s to bundle: %w", f.Name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package support
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"nac-service-media/domain/support"
+)
+
+func TestZipWriter_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundles", "bundle.zip")
+	files := []support.File{
+		{Name: "MANIFEST.txt", Data: []byte("manifest")},
+		{Name: "workspace/frames/0001.jpg", Data: []byte{0xff, 0xd8}},
+	}
+
+	if err := NewZipWriter().Write(path, files); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	defer r.Close()
+	if len(r.File) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(r.File))
+	}
+	for i, f := range r.File {
+		if f.Name != files[i].Name {
+			t.Errorf("file %d = %s, want %s", i, f.Name, files[i].Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		if string(data) != string(files[i].Data) {
+			t.Errorf("%s = %q, want %q", f.Name, data, files[i].Data)
+		}
+	}
+}
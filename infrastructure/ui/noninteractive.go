@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInputRequired matches every InputRequiredError
+var ErrInputRequired = errors.New("input required")
+
+// Reasons a non-interactive run stopped instead of asking. They are stable so
+// scripts can match on them.
+const (
+	ReasonPrompt                = "prompt"
+	ReasonOverwriteConfirmation = "overwrite_confirmation"
+	ReasonAmbiguousRecipient    = "ambiguous_recipient"
+	ReasonAuthRequired          = "auth_required"
+)
+
+// InputRequiredError reports that a run needed an answer from the user but
+// was told not to ask. Its message starts with "non-interactive: <reason>:".
+type InputRequiredError struct {
+	Reason string
+	Err    error
+}
+
+func (e *InputRequiredError) Error() string {
+	return fmt.Sprintf("non-interactive: %s: %v", e.Reason, e.Err)
+}
+
+func (e *InputRequiredError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrInputRequired) hold for every InputRequiredError
+func (e *InputRequiredError) Is(target error) bool {
+	return target == ErrInputRequired
+}
+
+// NonInteractivePrompter fails every question with an InputRequiredError, so
+// unattended runs (cron, watch) stop instead of waiting for input
+type NonInteractivePrompter struct{}
+
+var _ Prompter = NonInteractivePrompter{}
+
+func (NonInteractivePrompter) Input(message string, defaultValue string) (string, error) {
+	return "", refuse(message)
+}
+
+func (NonInteractivePrompter) Confirm(message string, defaultValue bool) (bool, error) {
+	return false, refuse(message)
+}
+
+func (NonInteractivePrompter) Select(message string, options []string, defaultValue string) (string, error) {
+	return "", refuse(message)
+}
+
+func (NonInteractivePrompter) MultiSelect(message string, options []string, defaults []string) ([]string, error) {
+	return nil, refuse(message)
+}
+
+func refuse(message string) error {
+	return &InputRequiredError{Reason: ReasonPrompt, Err: fmt.Errorf("would have asked %q", message)}
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNonInteractivePrompter(t *testing.T) {
+	var p Prompter = NonInteractivePrompter{}
+
+	_, err := p.Confirm("Overwrite?", true)
+	if !errors.Is(err, ErrInputRequired) {
+		t.Fatalf("Confirm() error = %v, want ErrInputRequired", err)
+	}
+	want := `non-interactive: prompt: would have asked "Overwrite?"`
+	if err.Error() != want {
+		t.Errorf("Confirm() error = %q, want %q", err, want)
+	}
+
+	if _, err := p.Input("Name", "x"); !errors.Is(err, ErrInputRequired) {
+		t.Errorf("Input() error = %v, want ErrInputRequired", err)
+	}
+	if _, err := p.Select("Pick", []string{"a"}, "a"); !errors.Is(err, ErrInputRequired) {
+		t.Errorf("Select() error = %v, want ErrInputRequired", err)
+	}
+	if _, err := p.MultiSelect("Pick", []string{"a"}, nil); !errors.Is(err, ErrInputRequired) {
+		t.Errorf("MultiSelect() error = %v, want ErrInputRequired", err)
+	}
+}
+
+func TestInputRequiredError_Wrapped(t *testing.T) {
+	cause := errors.New("token expired")
+	err := fmt.Errorf("failed to create client: %w", &InputRequiredError{Reason: ReasonAuthRequired, Err: cause})
+
+	var ire *InputRequiredError
+	if !errors.As(err, &ire) || ire.Reason != ReasonAuthRequired {
+		t.Fatalf("errors.As() = %v, want reason %s", ire, ReasonAuthRequired)
+	}
+	if !errors.Is(err, cause) || !errors.Is(err, ErrInputRequired) {
+		t.Error("wrapped error should match both its cause and ErrInputRequired")
+	}
+}
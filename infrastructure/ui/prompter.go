@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// Prompter asks the user questions on the terminal. Commands take a Prompter
+// rather than reading stdin directly so their prompts can be mocked in tests.
+type Prompter interface {
+	// Input asks for free text, returning defaultValue when the answer is empty
+	Input(message string, defaultValue string) (string, error)
+	// Confirm asks a yes/no question
+	Confirm(message string, defaultValue bool) (bool, error)
+	// Select asks for one of options; defaultValue is preselected if listed
+	Select(message string, options []string, defaultValue string) (string, error)
+	// MultiSelect asks for any number of options; listed defaults are preselected
+	MultiSelect(message string, options []string, defaults []string) ([]string, error)
+}
+
+// SurveyPrompter implements Prompter using the survey library
+type SurveyPrompter struct{}
+
+var _ Prompter = (*SurveyPrompter)(nil)
+
+// NewSurveyPrompter creates a terminal prompter
+func NewSurveyPrompter() *SurveyPrompter {
+	return &SurveyPrompter{}
+}
+
+func (p *SurveyPrompter) Input(message string, defaultValue string) (string, error) {
+	result := ""
+	prompt := &survey.Input{
+		Message: message,
+		Default: defaultValue,
+	}
+	if err := survey.AskOne(prompt, &result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func (p *SurveyPrompter) Confirm(message string, defaultValue bool) (bool, error) {
+	result := defaultValue
+	prompt := &survey.Confirm{
+		Message: message,
+		Default: defaultValue,
+	}
+	if err := survey.AskOne(prompt, &result); err != nil {
+		return false, err
+	}
+	return result, nil
+}
+
+func (p *SurveyPrompter) Select(message string, options []string, defaultValue string) (string, error) {
+	result := ""
+	prompt := &survey.Select{
+		Message: message,
+		Options: options,
+	}
+	// survey rejects a default that isn't one of the options
+	if d := KnownOptions(options, []string{defaultValue}); len(d) > 0 {
+		prompt.Default = d[0]
+	}
+	if err := survey.AskOne(prompt, &result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func (p *SurveyPrompter) MultiSelect(message string, options []string, defaults []string) ([]string, error) {
+	var result []string
+	prompt := &survey.MultiSelect{
+		Message: message,
+		Options: options,
+	}
+	if d := KnownOptions(options, defaults); len(d) > 0 {
+		prompt.Default = d
+	}
+	if err := survey.AskOne(prompt, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// KnownOptions returns the values that appear in options, in the order given
+// and without duplicates. Prompters use it to drop stale defaults, e.g. a
+// configured value that is no longer offered.
+func KnownOptions(options []string, values []string) []string {
+	offered := make(map[string]bool, len(options))
+	for _, o := range options {
+		offered[o] = true
+	}
+	var known []string
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if offered[v] && !seen[v] {
+			known = append(known, v)
+			seen[v] = true
+		}
+	}
+	return known
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKnownOptions(t *testing.T) {
+	options := []string{"markdown", "html", "json"}
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{"all known", []string{"html", "markdown"}, []string{"html", "markdown"}},
+		{"drops unknown", []string{"pdf", "html"}, []string{"html"}},
+		{"drops duplicates", []string{"json", "json"}, []string{"json"}},
+		{"empty default", []string{""}, nil},
+		{"none", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KnownOptions(options, tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KnownOptions(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
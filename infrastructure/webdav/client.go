@@ -0,0 +1,132 @@
+package webdav
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"nac-service-media/domain/distribution"
+)
+
+// HTTPDoer abstracts the HTTP client for testing
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client implements distribution.Publisher by PUTting files to a WebDAV collection
+type Client struct {
+	httpClient HTTPDoer
+	baseURL    string
+	publicURL  string
+	username   string
+	password   string
+}
+
+// Option configures the Client
+type Option func(*Client)
+
+// WithHTTPClient sets a custom HTTP client (useful for testing)
+func WithHTTPClient(c HTTPDoer) Option {
+	return func(client *Client) {
+		client.httpClient = c
+	}
+}
+
+// WithBasicAuth sets the WebDAV credentials
+func WithBasicAuth(username, password string) Option {
+	return func(client *Client) {
+		client.username = username
+		client.password = password
+	}
+}
+
+// WithPublicURL sets the base URL recipients download from, if it differs
+// from the WebDAV upload URL
+func WithPublicURL(u string) Option {
+	return func(client *Client) {
+		client.publicURL = u
+	}
+}
+
+// NewClient creates a WebDAV publisher for the collection at baseURL
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.publicURL == "" {
+		c.publicURL = c.baseURL
+	}
+	c.publicURL = strings.TrimSuffix(c.publicURL, "/")
+	return c
+}
+
+// Put implements distribution.Publisher
+func (c *Client) Put(ctx context.Context, remoteName string, r io.Reader, size int64) error {
+	target := c.baseURL + "/" + url.PathEscape(remoteName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, r)
+	if err != nil {
+		return fmt.Errorf("failed to create WebDAV request: %w", err)
+	}
+	req.ContentLength = size
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("WebDAV upload of %s failed: %w", remoteName, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	default:
+		return fmt.Errorf("WebDAV upload of %s failed: %s", remoteName, resp.Status)
+	}
+}
+
+// Stat implements distribution.PublishedStater with a HEAD request
+func (c *Client) Stat(ctx context.Context, remoteName string) (int64, bool, error) {
+	target := c.baseURL + "/" + url.PathEscape(remoteName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create WebDAV request: %w", err)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("WebDAV lookup of %s failed: %w", remoteName, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.ContentLength, true, nil
+	case http.StatusNotFound:
+		return 0, false, nil
+	default:
+		return 0, false, fmt.Errorf("WebDAV lookup of %s failed: %s", remoteName, resp.Status)
+	}
+}
+
+// URL implements distribution.Publisher
+func (c *Client) URL(remoteName string) string {
+	return c.publicURL + "/" + url.PathEscape(remoteName)
+}
+
+// Ensure Client implements distribution.Publisher and distribution.PublishedStater
+var (
+	_ distribution.Publisher       = (*Client)(nil)
+	_ distribution.PublishedStater = (*Client)(nil)
+)
@@ -0,0 +1,113 @@
+package webdav
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Put(t *testing.T) {
+	var gotPath, gotBody, gotUser string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		gotPath = r.URL.Path
+		gotUser, _, _ = r.BasicAuth()
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/services/", WithBasicAuth("av", "secret"))
+	if err := client.Put(context.Background(), "2025-12-28.mp3", strings.NewReader("audio"), 5); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if gotPath != "/services/2025-12-28.mp3" {
+		t.Errorf("path = %q, want /services/2025-12-28.mp3", gotPath)
+	}
+	if gotBody != "audio" {
+		t.Errorf("body = %q, want audio", gotBody)
+	}
+	if gotUser != "av" {
+		t.Errorf("basic auth user = %q, want av", gotUser)
+	}
+}
+
+func TestClient_Put_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	err := client.Put(context.Background(), "2025-12-28.mp3", strings.NewReader("audio"), 5)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected 403 error, got %v", err)
+	}
+}
+
+func TestClient_URL(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *Client
+		want   string
+	}{
+		{
+			name:   "defaults to upload URL",
+			client: NewClient("https://dav.example.org/services/"),
+			want:   "https://dav.example.org/services/2025-12-28%20final.mp4",
+		},
+		{
+			name:   "public URL override",
+			client: NewClient("https://dav.example.org/remote.php/services", WithPublicURL("https://media.example.org/")),
+			want:   "https://media.example.org/2025-12-28%20final.mp4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.client.URL("2025-12-28 final.mp4"); got != tt.want {
+				t.Errorf("URL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Stat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/services/2025-12-28.mp3":
+			w.Header().Set("Content-Length", "5")
+			w.WriteHeader(http.StatusOK)
+		case "/services/broken.mp3":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL + "/services")
+
+	size, found, err := client.Stat(context.Background(), "2025-12-28.mp3")
+	if err != nil || !found || size != 5 {
+		t.Errorf("Stat(published) = %d, %v, %v; want 5, true, nil", size, found, err)
+	}
+
+	if _, found, err := client.Stat(context.Background(), "2025-12-21.mp3"); err != nil || found {
+		t.Errorf("Stat(missing) found = %v, err = %v; want false, nil", found, err)
+	}
+
+	if _, _, err := client.Stat(context.Background(), "broken.mp3"); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 error, got %v", err)
+	}
+}
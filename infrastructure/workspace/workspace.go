@@ -0,0 +1,145 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Subfolders created in every run directory
+const (
+	Frames   = "frames"   // Video frames extracted for detection
+	Previews = "previews" // Preview clips
+	Partial  = "partial"  // Outputs still being written
+)
+
+// runPrefix marks directories created by New, so Clean never touches anything else
+const runPrefix = "run-"
+
+// DefaultBase returns the directory runs are created in when none is configured
+func DefaultBase() string {
+	return filepath.Join(os.TempDir(), "nac-service-media")
+}
+
+// Workspace is a per-run scratch directory with a subfolder per kind of file
+type Workspace struct {
+	root string
+}
+
+// New creates a run directory under base (DefaultBase when empty), named after
+// the run's start time so leftovers sort oldest first
+func New(base string, now time.Time) (*Workspace, error) {
+	if base == "" {
+		base = DefaultBase()
+	}
+	if err := os.MkdirAll(base, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+	root, err := os.MkdirTemp(base, runPrefix+now.Format("20060102-150405")+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	for _, sub := range []string{Frames, Previews, Partial} {
+		if err := os.Mkdir(filepath.Join(root, sub), 0755); err != nil {
+			os.RemoveAll(root)
+			return nil, fmt.Errorf("failed to create workspace: %w", err)
+		}
+	}
+	return &Workspace{root: root}, nil
+}
+
+// Root returns the run directory
+func (w *Workspace) Root() string {
+	return w.root
+}
+
+// Dir returns the path of a subfolder such as Frames
+func (w *Workspace) Dir(sub string) string {
+	return filepath.Join(w.root, sub)
+}
+
+// Finish removes the workspace after a successful run. After a failed run
+// (runErr != nil) it is kept for debugging and Finish reports kept as true.
+func (w *Workspace) Finish(runErr error) (kept bool, err error) {
+	if runErr != nil {
+		return true, nil
+	}
+	if err := os.RemoveAll(w.root); err != nil {
+		return false, fmt.Errorf("failed to remove workspace: %w", err)
+	}
+	return false, nil
+}
+
+// Leftover is a run directory left behind by a failed or interrupted run
+type Leftover struct {
+	Path    string
+	ModTime time.Time
+	Bytes   int64
+}
+
+// List returns the run directories under base, oldest first
+func List(base string) ([]Leftover, error) {
+	if base == "" {
+		base = DefaultBase()
+	}
+	entries, err := os.ReadDir(base)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace directory: %w", err)
+	}
+
+	var runs []Leftover
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), runPrefix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(base, e.Name())
+		runs = append(runs, Leftover{Path: path, ModTime: info.ModTime(), Bytes: dirSize(path)})
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].ModTime.Before(runs[j].ModTime) })
+	return runs, nil
+}
+
+// Clean removes run directories under base last modified before cutoff and
+// returns what was removed
+func Clean(base string, cutoff time.Time) ([]Leftover, error) {
+	runs, err := List(base)
+	if err != nil {
+		return nil, err
+	}
+	var removed []Leftover
+	for _, r := range runs {
+		if !r.ModTime.Before(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(r.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", r.Path, err)
+		}
+		removed = append(removed, r)
+	}
+	return removed, nil
+}
+
+// dirSize totals the files under dir, ignoring anything unreadable
+func dirSize(dir string) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
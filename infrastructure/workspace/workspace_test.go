@@ -0,0 +1,91 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew_CreatesSubfolders(t *testing.T) {
+	base := t.TempDir()
+	w, err := New(base, time.Date(2025, 12, 28, 10, 6, 16, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(filepath.Base(w.Root()), "run-20251228-100616-") {
+		t.Errorf("Root() = %s, want a run-20251228-100616- directory", w.Root())
+	}
+	for _, sub := range []string{Frames, Previews, Partial} {
+		if info, err := os.Stat(w.Dir(sub)); err != nil || !info.IsDir() {
+			t.Errorf("missing subfolder %s: %v", sub, err)
+		}
+	}
+}
+
+func TestFinish_RemovesOnSuccess(t *testing.T) {
+	w, err := New(t.TempDir(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(w.Dir(Frames), "frame_1.png"), []byte("png"), 0644)
+
+	kept, err := w.Finish(nil)
+	if err != nil || kept {
+		t.Fatalf("Finish(nil) = %v, %v; want removed", kept, err)
+	}
+	if _, err := os.Stat(w.Root()); !os.IsNotExist(err) {
+		t.Errorf("workspace still exists after success")
+	}
+}
+
+func TestFinish_KeepsOnFailure(t *testing.T) {
+	w, err := New(t.TempDir(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err := w.Finish(errors.New("trim failed"))
+	if err != nil || !kept {
+		t.Fatalf("Finish(err) = %v, %v; want kept", kept, err)
+	}
+	if _, err := os.Stat(w.Root()); err != nil {
+		t.Errorf("workspace removed after failure: %v", err)
+	}
+}
+
+func TestClean_RemovesOnlyOldRuns(t *testing.T) {
+	base := t.TempDir()
+	now := time.Now()
+
+	old, _ := New(base, now)
+	recent, _ := New(base, now)
+	os.WriteFile(filepath.Join(old.Dir(Partial), "2025-12-28.mp4"), make([]byte, 10), 0644)
+	os.Chtimes(old.Root(), now.Add(-10*24*time.Hour), now.Add(-10*24*time.Hour))
+
+	// Not created by New, so never cleaned
+	other := filepath.Join(base, "keep-me")
+	os.Mkdir(other, 0755)
+	os.Chtimes(other, now.Add(-30*24*time.Hour), now.Add(-30*24*time.Hour))
+
+	removed, err := Clean(base, now.Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0].Path != old.Root() || removed[0].Bytes != 10 {
+		t.Fatalf("Clean() removed %+v, want only %s (10 bytes)", removed, old.Root())
+	}
+	for _, keep := range []string{recent.Root(), other} {
+		if _, err := os.Stat(keep); err != nil {
+			t.Errorf("%s was removed: %v", keep, err)
+		}
+	}
+}
+
+func TestList_MissingBase(t *testing.T) {
+	runs, err := List(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(runs) != 0 {
+		t.Errorf("List(missing) = %v, %v; want empty", runs, err)
+	}
+}
@@ -0,0 +1,130 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"nac-service-media/domain/distribution"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// Name is the host name shown in output and history
+const Name = "YouTube"
+
+// categoryNonprofits is YouTube's "Nonprofits & Activism" category, which
+// covers religious organizations
+const categoryNonprofits = "29"
+
+// ErrQuotaExceeded is returned when the project's daily YouTube API quota is
+// used up; each upload costs a large share of it
+var ErrQuotaExceeded = errors.New("YouTube API quota exceeded; try again tomorrow or upload to Drive with --target drive")
+
+// YouTubeService defines the YouTube API operations the client uses
+// This allows mocking the YouTube API in tests
+type YouTubeService interface {
+	InsertVideo(ctx context.Context, video *youtube.Video, media io.Reader) (*youtube.Video, error)
+}
+
+// GoogleYouTubeService is the production implementation using the YouTube Data API
+type GoogleYouTubeService struct {
+	service *youtube.Service
+}
+
+// InsertVideo uploads a video with its snippet, status and recording details
+func (s *GoogleYouTubeService) InsertVideo(ctx context.Context, video *youtube.Video, media io.Reader) (*youtube.Video, error) {
+	return s.service.Videos.Insert([]string{"snippet", "status", "recordingDetails"}, video).Media(media).Context(ctx).Do()
+}
+
+// Client implements distribution.VideoHost using the YouTube Data API
+type Client struct {
+	service YouTubeService
+}
+
+var _ distribution.VideoHost = (*Client)(nil)
+
+// ClientOption is a functional option for configuring Client
+type ClientOption func(*Client)
+
+// WithYouTubeService sets a custom YouTube service (for testing)
+func WithYouTubeService(svc YouTubeService) ClientOption {
+	return func(c *Client) {
+		c.service = svc
+	}
+}
+
+// NewClient creates a new YouTube client with the given options
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Name implements distribution.VideoHost
+func (c *Client) Name() string {
+	return Name
+}
+
+// UploadVideo uploads the video to the signed-in account's channel. Videos
+// are declared as not made for children, as services are for the whole
+// congregation.
+func (c *Client) UploadVideo(ctx context.Context, v distribution.VideoUpload, r io.Reader) (*distribution.HostedVideo, error) {
+	video := &youtube.Video{
+		Snippet: &youtube.VideoSnippet{
+			Title:       v.Title,
+			Description: v.Description,
+			CategoryId:  categoryNonprofits,
+		},
+		Status: &youtube.VideoStatus{
+			PrivacyStatus:           string(v.Privacy),
+			SelfDeclaredMadeForKids: false,
+			ForceSendFields:         []string{"SelfDeclaredMadeForKids"},
+		},
+	}
+	if !v.RecordedAt.IsZero() {
+		video.RecordingDetails = &youtube.VideoRecordingDetails{RecordingDate: v.RecordedAt.Format("2006-01-02T15:04:05Z07:00")}
+	}
+
+	uploaded, err := c.service.InsertVideo(ctx, video, r)
+	if err != nil {
+		if quotaExceeded(err) {
+			return nil, fmt.Errorf("failed to upload %s: %w", v.FileName, ErrQuotaExceeded)
+		}
+		return nil, fmt.Errorf("failed to upload %s to YouTube: %w", v.FileName, err)
+	}
+
+	privacy := v.Privacy
+	if uploaded.Status != nil && uploaded.Status.PrivacyStatus != "" {
+		privacy = distribution.Privacy(uploaded.Status.PrivacyStatus)
+	}
+	return &distribution.HostedVideo{
+		Host:    Name,
+		ID:      uploaded.Id,
+		URL:     WatchURL(uploaded.Id),
+		Privacy: privacy,
+	}, nil
+}
+
+// WatchURL returns the link to watch a video
+func WatchURL(videoID string) string {
+	return "https://www.youtube.com/watch?v=" + videoID
+}
+
+// quotaExceeded reports whether YouTube refused the request for quota
+func quotaExceeded(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, e := range apiErr.Errors {
+		if e.Reason == "quotaExceeded" || e.Reason == "uploadLimitExceeded" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package youtube
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"nac-service-media/domain/distribution"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// failingService is a YouTubeService whose uploads fail
+type failingService struct{ err error }
+
+func (f failingService) InsertVideo(ctx context.Context, video *youtube.Video, media io.Reader) (*youtube.Video, error) {
+	return nil, f.err
+}
+
+// recordingService keeps the video it was sent
+type recordingService struct{ video *youtube.Video }
+
+func (r *recordingService) InsertVideo(ctx context.Context, video *youtube.Video, media io.Reader) (*youtube.Video, error) {
+	r.video = video
+	return &youtube.Video{Id: "abc123"}, nil
+}
+
+func TestClient_UploadVideo(t *testing.T) {
+	channel := NewChannel()
+	client := NewClient(WithYouTubeService(channel))
+
+	v := distribution.VideoUpload{
+		FileName:    "2025-12-28.mp4",
+		Title:       "Springfield Church: Service on 12/28/2025",
+		Description: "Led by Pr. Smith",
+		Privacy:     distribution.PrivacyPrivate,
+	}
+	hosted, err := client.UploadVideo(context.Background(), v, strings.NewReader("video data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hosted.Host != "YouTube" || hosted.URL != "https://www.youtube.com/watch?v="+hosted.ID || hosted.Privacy != distribution.PrivacyPrivate {
+		t.Errorf("hosted = %+v", hosted)
+	}
+
+	videos := channel.Videos()
+	if len(videos) != 1 {
+		t.Fatalf("channel has %d videos, want 1", len(videos))
+	}
+	if videos[0].Title != v.Title || videos[0].Description != v.Description || videos[0].Privacy != "private" || videos[0].Size != 10 {
+		t.Errorf("uploaded = %+v", videos[0])
+	}
+}
+
+func TestClient_UploadVideo_Request(t *testing.T) {
+	svc := &recordingService{}
+	recorded := time.Date(2025, 12, 28, 0, 0, 0, 0, time.UTC)
+	_, err := NewClient(WithYouTubeService(svc)).UploadVideo(context.Background(), distribution.VideoUpload{
+		Title:      "Service",
+		Privacy:    distribution.PrivacyUnlisted,
+		RecordedAt: recorded,
+	}, strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svc.video.Snippet.CategoryId != "29" || svc.video.Status.PrivacyStatus != "unlisted" {
+		t.Errorf("video = %+v / %+v", svc.video.Snippet, svc.video.Status)
+	}
+	if svc.video.RecordingDetails == nil || svc.video.RecordingDetails.RecordingDate != "2025-12-28T00:00:00Z" {
+		t.Errorf("recording details = %+v", svc.video.RecordingDetails)
+	}
+}
+
+func TestClient_UploadVideo_QuotaExceeded(t *testing.T) {
+	quota := &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}
+	client := NewClient(WithYouTubeService(failingService{err: quota}))
+
+	_, err := client.UploadVideo(context.Background(), distribution.VideoUpload{FileName: "2025-12-28.mp4"}, strings.NewReader(""))
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("UploadVideo() error = %v, want ErrQuotaExceeded", err)
+	}
+
+	other := NewClient(WithYouTubeService(failingService{err: errors.New("connection reset")}))
+	if _, err := other.UploadVideo(context.Background(), distribution.VideoUpload{FileName: "2025-12-28.mp4"}, strings.NewReader("")); err == nil || errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("UploadVideo() error = %v, want a plain upload error", err)
+	}
+}
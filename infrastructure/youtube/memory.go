@@ -0,0 +1,60 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+// UploadedVideo is a video kept by a Channel
+type UploadedVideo struct {
+	ID          string
+	Title       string
+	Description string
+	Privacy     string
+	Size        int64
+}
+
+// Channel is a YouTubeService that keeps uploads in memory, so the workflow
+// can run without a YouTube account
+type Channel struct {
+	mu     sync.Mutex
+	videos []UploadedVideo
+}
+
+var _ YouTubeService = (*Channel)(nil)
+
+// NewChannel creates an empty channel
+func NewChannel() *Channel {
+	return &Channel{}
+}
+
+// InsertVideo reads the media and keeps the video
+func (c *Channel) InsertVideo(ctx context.Context, video *youtube.Video, media io.Reader) (*youtube.Video, error) {
+	size, err := io.Copy(io.Discard, media)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	uploaded := UploadedVideo{ID: fmt.Sprintf("video-%d", len(c.videos)+1), Size: size}
+	if video.Snippet != nil {
+		uploaded.Title, uploaded.Description = video.Snippet.Title, video.Snippet.Description
+	}
+	if video.Status != nil {
+		uploaded.Privacy = video.Status.PrivacyStatus
+	}
+	c.videos = append(c.videos, uploaded)
+	return &youtube.Video{Id: uploaded.ID, Snippet: video.Snippet, Status: video.Status}, nil
+}
+
+// Videos returns the kept videos, oldest first
+func (c *Channel) Videos() []UploadedVideo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]UploadedVideo(nil), c.videos...)
+}
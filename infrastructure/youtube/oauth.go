@@ -0,0 +1,267 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"nac-service-media/infrastructure/filesystem"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+)
+
+// OAuthConfig holds the configuration for OAuth 2.0 authentication
+type OAuthConfig struct {
+	CredentialsFile string // Path to OAuth client credentials JSON
+	TokenFile       string // Path to store/load token
+	// NonInteractive fails with ErrAuthRequired instead of opening a browser
+	// to sign in when there is no valid token
+	NonInteractive bool
+}
+
+// ErrAuthRequired is returned in non-interactive mode when signing in is needed
+var ErrAuthRequired = errors.New("no valid YouTube OAuth token; run 'nac-service-media upload --target youtube' from a terminal to sign in")
+
+// NewClientWithOAuth creates a new YouTube client using OAuth 2.0. It only
+// asks to upload videos, not to manage the channel.
+func NewClientWithOAuth(ctx context.Context, cfg OAuthConfig, opts ...ClientOption) (*Client, error) {
+	c := NewClient(opts...)
+
+	// If no custom YouTube service was provided, create one with OAuth
+	if c.service == nil {
+		svc, err := newOAuthYouTubeService(ctx, cfg, tokenStore{})
+		if err != nil {
+			return nil, err
+		}
+		c.service = svc
+	}
+
+	return c, nil
+}
+
+// newOAuthYouTubeService creates a YouTube service using OAuth 2.0 user authentication
+func newOAuthYouTubeService(ctx context.Context, cfg OAuthConfig, store tokenStore) (*GoogleYouTubeService, error) {
+	b, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read OAuth credentials file: %w", err)
+	}
+
+	// Parse the OAuth client credentials - need the YouTube upload scope
+	config, err := google.ConfigFromJSON(b, youtube.YoutubeUploadScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse OAuth credentials: %w", err)
+	}
+
+	// Get or create token
+	store.file = cfg.TokenFile
+	token, err := getToken(ctx, config, store, cfg.NonInteractive)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get OAuth token: %w", err)
+	}
+
+	// Create the YouTube service
+	client := config.Client(ctx, token)
+	srv, err := youtube.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create YouTube service: %w", err)
+	}
+
+	return &GoogleYouTubeService{service: srv}, nil
+}
+
+// getToken returns the saved token while it is unexpired, refreshes it when
+// it has expired, and otherwise (unless nonInteractive) initiates the OAuth flow
+func getToken(ctx context.Context, config *oauth2.Config, store tokenStore, nonInteractive bool) (*oauth2.Token, error) {
+	// Try to load existing token
+	token, err := store.load()
+	if err == nil {
+		if !store.expired(token) {
+			return token, nil
+		}
+		if token.RefreshToken != "" {
+			// Only the refresh token is passed so the refresh does not depend
+			// on the oauth2 package's own clock
+			newToken, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+			if err == nil {
+				store.save(newToken)
+				return newToken, nil
+			}
+		}
+		// Token refresh failed, need to re-authenticate
+	}
+
+	// No valid token, initiate OAuth flow
+	if nonInteractive {
+		return nil, ErrAuthRequired
+	}
+	return getTokenFromWeb(ctx, config, store)
+}
+
+// tokenExpiryDelta refreshes a token this long before it actually expires
+const tokenExpiryDelta = time.Minute
+
+// tokenStore loads and saves the OAuth token file
+type tokenStore struct {
+	file  string
+	files filesystem.Opener // nil uses the os package
+	now   func() time.Time  // nil uses time.Now
+}
+
+// load reads the token from the file
+func (s tokenStore) load() (*oauth2.Token, error) {
+	f, err := s.opener().Open(s.file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	token := &oauth2.Token{}
+	err = json.NewDecoder(f).Decode(token)
+	return token, err
+}
+
+// save writes the token to the file
+func (s tokenStore) save(token *oauth2.Token) error {
+	f, err := filesystem.CreatePrivate(s.opener(), s.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(token)
+}
+
+// expired reports whether the token has no access token or expires within
+// tokenExpiryDelta; a token without an expiry never expires
+func (s tokenStore) expired(token *oauth2.Token) bool {
+	if token.AccessToken == "" {
+		return true
+	}
+	if token.Expiry.IsZero() {
+		return false
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	return !now().Add(tokenExpiryDelta).Before(token.Expiry)
+}
+
+// opener returns files, or the os package when it is nil
+func (s tokenStore) opener() filesystem.Opener {
+	if s.files == nil {
+		return filesystem.OSFS{}
+	}
+	return s.files
+}
+
+// getTokenFromWeb initiates the OAuth flow via browser
+func getTokenFromWeb(ctx context.Context, config *oauth2.Config, store tokenStore) (*oauth2.Token, error) {
+	// Use localhost redirect for installed apps
+	// Use a different port than Drive and Gmail to avoid conflicts
+	config.RedirectURL = "http://localhost:8087/callback"
+
+	// Channel to receive the auth code
+	codeChan := make(chan string, 1)
+	errChan := make(chan error, 1)
+
+	// Start local server to receive callback
+	mux := http.NewServeMux()
+	server := &http.Server{Addr: ":8087", Handler: mux}
+
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			errChan <- fmt.Errorf("no code in callback")
+			fmt.Fprintf(w, "Error: No authorization code received")
+			return
+		}
+		codeChan <- code
+		fmt.Fprintf(w, "<html><body><h1>YouTube Authorization successful!</h1><p>You can close this window and return to the terminal.</p></body></html>")
+	})
+
+	// Start server in background
+	go func() {
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			errChan <- err
+		}
+	}()
+
+	// Generate auth URL
+	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+
+	fmt.Println()
+	fmt.Println("Opening browser for YouTube authentication...")
+	fmt.Println("If the browser doesn't open, please visit this URL:")
+	fmt.Println()
+	fmt.Println(authURL)
+	fmt.Println()
+
+	// Try to open browser
+	openBrowser(authURL)
+
+	// Wait for callback
+	var authCode string
+	select {
+	case authCode = <-codeChan:
+		// Got the code
+	case err := <-errChan:
+		server.Shutdown(ctx)
+		return nil, err
+	case <-ctx.Done():
+		server.Shutdown(ctx)
+		return nil, ctx.Err()
+	}
+
+	// Shutdown server
+	server.Shutdown(ctx)
+
+	// Exchange code for token
+	token, err := config.Exchange(ctx, authCode)
+	if err != nil {
+		return nil, fmt.Errorf("unable to exchange auth code: %w", err)
+	}
+
+	// Save token for future use
+	if err := store.save(token); err != nil {
+		fmt.Printf("Warning: couldn't save token: %v\n", err)
+	}
+
+	fmt.Println("YouTube authentication successful!")
+	return token, nil
+}
+
+// openBrowser opens a URL in the default browser
+func openBrowser(url string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		// Try various Linux browser openers
+		if _, err := exec.LookPath("xdg-open"); err == nil {
+			cmd = exec.Command("xdg-open", url)
+		} else if _, err := exec.LookPath("wslview"); err == nil {
+			// WSL
+			cmd = exec.Command("wslview", url)
+		} else {
+			// Try Windows browser from WSL
+			cmd = exec.Command("cmd.exe", "/c", "start", url)
+		}
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", url)
+	}
+
+	if cmd != nil {
+		cmd.Start()
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	// Embed the timezone database so locale.timezone works on Windows
+	// machines without Go's zoneinfo installed
+	_ "time/tzdata"
+
 	"nac-service-media/cmd"
 )
 
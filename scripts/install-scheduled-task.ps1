@@ -130,6 +130,8 @@ Write-Host ""
 Write-Host "The task '$TaskName' is now scheduled to run:"
 Write-Host "  - Every $TriggerDay at $TriggerTime"
 Write-Host "  - Logs are written to: $ScriptDir\..\logs\"
+Write-Host "  - With --non-interactive --allow-delete: nothing is asked, and old"
+Write-Host "    videos are deleted from Drive when it is too full"
 Write-Host ""
 Write-Host "To test the task manually, run:"
 Write-Host "  schtasks /run /tn `"$TaskName`""
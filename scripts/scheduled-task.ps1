@@ -46,7 +46,9 @@ Write-Log ""
 # Build the WSL command
 # Uses -l (login shell) to ensure PATH is loaded from .profile
 # Changes to project directory first so config/config.yaml is found
-$WslCommand = "cd $WslProjectDir && nac-service-media process --recipient $Recipient"
+# Nobody is at the terminal, so never prompt; --allow-delete lets the run free
+# Drive space by deleting old videos instead of stopping with DELETE_NOT_ALLOWED
+$WslCommand = "cd $WslProjectDir && nac-service-media process --recipient $Recipient --non-interactive --allow-delete"
 
 if ($DryRun) {
     Write-Log "[DRY RUN] Would execute: wsl.exe -d Ubuntu -- bash -lc `"$WslCommand`""